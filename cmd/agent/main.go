@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"goonhub/internal/agent"
+	"goonhub/internal/infrastructure/logging"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+func main() {
+	hostname, _ := os.Hostname()
+
+	// Every flag can also be set via environment variable for container deployments
+	server := flag.String("server", os.Getenv("GOONHUB_AGENT_SERVER"), "GoonHub server URL (e.g. http://goonhub:8080)")
+	token := flag.String("token", os.Getenv("GOONHUB_AGENT_TOKEN"), "shared agent token (agents.token in the server config)")
+	name := flag.String("name", envOr("GOONHUB_AGENT_NAME", hostname), "agent name shown in the admin UI")
+	phases := flag.String("phases", envOr("GOONHUB_AGENT_PHASES", agent.PhaseSprites), "comma-separated phases to accept")
+	concurrency := flag.Int("concurrency", envInt("GOONHUB_AGENT_CONCURRENCY", 1), "tasks to run in parallel")
+	workDir := flag.String("work-dir", envOr("GOONHUB_AGENT_WORK_DIR", filepath.Join(os.TempDir(), "goonhub-agent")), "scratch directory for sources and outputs")
+	pathMap := flag.String("path-map", os.Getenv("GOONHUB_AGENT_PATH_MAP"), "server=local path prefixes for shared storage, comma-separated")
+	flag.Parse()
+
+	if *server == "" || *token == "" {
+		fmt.Fprintln(os.Stderr, "both -server and -token are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	mappings, err := parsePathMap(*pathMap)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -path-map: %v\n", err)
+		os.Exit(2)
+	}
+
+	logger := logging.Default()
+	defer logger.Sync()
+
+	a := agent.New(agent.Config{
+		ServerURL:    *server,
+		Token:        *token,
+		Name:         *name,
+		Version:      version,
+		Phases:       splitList(*phases),
+		Concurrency:  *concurrency,
+		WorkDir:      *workDir,
+		PathMappings: mappings,
+	}, logger.Logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("Starting agent",
+		zap.String("name", *name),
+		zap.String("version", version),
+		zap.String("server", *server),
+	)
+	if err := a.Run(ctx); err != nil && ctx.Err() == nil {
+		logger.Fatal("Agent failed", zap.Error(err))
+	}
+	logger.Info("Agent stopped")
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func parsePathMap(s string) (map[string]string, error) {
+	mappings := make(map[string]string)
+	for _, entry := range splitList(s) {
+		serverPrefix, localPrefix, ok := strings.Cut(entry, "=")
+		if !ok || serverPrefix == "" || localPrefix == "" {
+			return nil, fmt.Errorf("entry %q must be server=local", entry)
+		}
+		mappings[serverPrefix] = localPrefix
+	}
+	return mappings, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"goonhub/internal/cli"
+	"goonhub/internal/wire"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	// Config path can be set via flag or the same environment variable as the server
+	configPath := flag.String("config", os.Getenv("GOONHUB_CONFIG"), "path to the config file")
+	flag.Usage = func() { cli.PrintUsage(os.Stderr) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 || args[0] == "help" {
+		cli.PrintUsage(os.Stdout)
+		return
+	}
+	if !cli.IsCommand(args[0]) {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		cli.PrintUsage(os.Stderr)
+		os.Exit(2)
+	}
+
+	app, err := wire.InitializeCLI(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.Run(ctx, args); err != nil {
+		stop()
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
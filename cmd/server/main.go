@@ -1,18 +1,32 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"goonhub/internal/wire"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	reindex := flag.Bool("reindex", false, "rebuild the search index from the database and exit without starting the server")
+	resume := flag.Bool("resume", false, "with -reindex, continue an interrupted reindex from its checkpoint")
+	flag.Parse()
+
 	// Initialize Server using Wire
 	// Config path can be set via environment variable or use default
 	configPath := ""
 	if path := os.Getenv("GOONHUB_CONFIG"); path != "" {
 		configPath = path
 	}
+
+	if *reindex {
+		runReindex(configPath, *resume)
+		return
+	}
+
 	srv, err := wire.InitializeServer(configPath)
 	if err != nil {
 		log.Fatalf("Failed to initialize server: %v", err)
@@ -23,3 +37,24 @@ func main() {
 		log.Fatalf("Server failed: %v", err)
 	}
 }
+
+// runReindex performs an offline search rebuild, running the same command as
+// "goonhubctl reindex".
+func runReindex(configPath string, resume bool) {
+	app, err := wire.InitializeCLI(configPath)
+	if err != nil {
+		log.Fatalf("Failed to initialize: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	args := []string{"reindex"}
+	if resume {
+		args = append(args, "-resume")
+	}
+	if err := app.Run(ctx, args); err != nil {
+		stop()
+		log.Fatalf("Reindex failed: %v", err)
+	}
+}
@@ -0,0 +1,373 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goonhub/pkg/ffmpeg"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// claimWait is how long each claim request long-polls the server
+	claimWait = 30 * time.Second
+	// retryDelay is the pause after a failed server call before trying again
+	retryDelay = 5 * time.Second
+	// progressInterval throttles progress reports to the server
+	progressInterval = 2 * time.Second
+)
+
+// Config configures a remote agent
+type Config struct {
+	ServerURL   string
+	Token       string
+	Name        string
+	Version     string
+	Phases      []string
+	Concurrency int    // tasks run in parallel
+	WorkDir     string // scratch space for downloaded sources and outputs
+	// PathMappings maps server path prefixes to local prefixes for shared storage.
+	// When a mapped source exists locally it is read in place instead of downloaded.
+	PathMappings map[string]string
+}
+
+// Agent pulls tasks from the server, runs them with local ffmpeg and uploads the results
+type Agent struct {
+	cfg    Config
+	client *Client
+	logger *zap.Logger
+
+	registerMu sync.Mutex
+
+	runningMu sync.Mutex
+	running   map[string]context.CancelFunc
+}
+
+// New creates a new Agent
+func New(cfg Config, logger *zap.Logger) *Agent {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	return &Agent{
+		cfg:     cfg,
+		client:  NewClient(cfg.ServerURL, cfg.Token),
+		logger:  logger,
+		running: make(map[string]context.CancelFunc),
+	}
+}
+
+// Run registers with the server and processes tasks until ctx is cancelled
+func (a *Agent) Run(ctx context.Context) error {
+	if err := os.MkdirAll(a.cfg.WorkDir, 0755); err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+
+	interval, err := a.register(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.heartbeatLoop(ctx, interval)
+	}()
+
+	for i := 0; i < a.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.workLoop(ctx)
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// register (re-)registers the agent. staleID is the ID that was rejected; when
+// another goroutine already replaced it, registration is skipped.
+func (a *Agent) register(ctx context.Context, staleID string) (time.Duration, error) {
+	a.registerMu.Lock()
+	defer a.registerMu.Unlock()
+
+	if staleID != "" && a.client.AgentID() != staleID {
+		return 0, nil
+	}
+
+	for {
+		resp, err := a.client.Register(ctx, RegisterRequest{
+			Name:        a.cfg.Name,
+			Version:     a.cfg.Version,
+			Phases:      a.cfg.Phases,
+			Concurrency: a.cfg.Concurrency,
+		})
+		if err == nil {
+			a.logger.Info("Registered with server",
+				zap.String("agent_id", resp.AgentID),
+				zap.String("server", a.cfg.ServerURL),
+			)
+			return time.Duration(resp.HeartbeatInterval) * time.Second, nil
+		}
+
+		a.logger.Warn("Registration failed, retrying", zap.Error(err), zap.Duration("retry_in", retryDelay))
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func (a *Agent) heartbeatLoop(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		id := a.client.AgentID()
+		resp, err := a.client.Heartbeat(ctx)
+		if errors.Is(err, ErrNotFound) {
+			a.logger.Warn("Server no longer knows this agent, registering again")
+			if _, err := a.register(ctx, id); err != nil {
+				return
+			}
+			continue
+		}
+		if err != nil {
+			a.logger.Warn("Heartbeat failed", zap.Error(err))
+			continue
+		}
+
+		for _, taskID := range resp.CancelTasks {
+			a.cancelTask(taskID)
+		}
+	}
+}
+
+func (a *Agent) workLoop(ctx context.Context) {
+	for ctx.Err() == nil {
+		id := a.client.AgentID()
+		task, err := a.client.Claim(ctx, claimWait)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, ErrNotFound) {
+				if _, err := a.register(ctx, id); err != nil {
+					return
+				}
+				continue
+			}
+			a.logger.Warn("Claim failed", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+		if task == nil {
+			continue
+		}
+
+		a.runTask(ctx, task)
+	}
+}
+
+// runTask executes a task and always reports a result, even on failure
+func (a *Agent) runTask(ctx context.Context, task *Task) {
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	a.runningMu.Lock()
+	a.running[task.ID] = cancel
+	a.runningMu.Unlock()
+	defer func() {
+		a.runningMu.Lock()
+		delete(a.running, task.ID)
+		a.runningMu.Unlock()
+	}()
+
+	logger := a.logger.With(
+		zap.String("task_id", task.ID),
+		zap.String("phase", task.Phase),
+		zap.Uint("scene_id", task.SceneID),
+	)
+	logger.Info("Task started")
+	start := time.Now()
+
+	taskDir := filepath.Join(a.cfg.WorkDir, task.ID)
+	defer os.RemoveAll(taskDir)
+
+	var result TaskResult
+	var err error
+	switch task.Phase {
+	case PhaseSprites:
+		result, err = a.runSprites(taskCtx, task, taskDir)
+	default:
+		err = fmt.Errorf("unsupported phase %q", task.Phase)
+	}
+
+	if errors.Is(err, ErrTaskGone) || errors.Is(err, ErrNotFound) {
+		logger.Info("Task abandoned by server")
+		return
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("agent shutting down")
+		}
+		result = TaskResult{Error: err.Error()}
+		logger.Error("Task failed", zap.Error(err))
+	}
+
+	// Report with a fresh context so shutdown still tells the server what happened
+	reportCtx, reportCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer reportCancel()
+	if err := a.client.Complete(reportCtx, task.ID, result); err != nil {
+		logger.Warn("Failed to report task result", zap.Error(err))
+		return
+	}
+
+	if result.Error == "" {
+		logger.Info("Task completed", zap.Duration("elapsed", time.Since(start)))
+	}
+}
+
+func (a *Agent) runSprites(ctx context.Context, task *Task, taskDir string) (TaskResult, error) {
+	if task.Sprites == nil {
+		return TaskResult{}, fmt.Errorf("missing sprites parameters")
+	}
+	p := task.Sprites
+
+	source, err := a.resolveSource(ctx, task, taskDir)
+	if err != nil {
+		return TaskResult{}, err
+	}
+
+	outDir := filepath.Join(taskDir, "out")
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return TaskResult{}, err
+	}
+
+	// A cancellation signalled through progress reports stops ffmpeg via ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var reportErr error
+	lastReport := time.Time{}
+	progress := func(pct int) {
+		if time.Since(lastReport) < progressInterval && pct < 100 {
+			return
+		}
+		lastReport = time.Now()
+		cancelled, err := a.client.ReportProgress(ctx, task.ID, pct)
+		if errors.Is(err, ErrTaskGone) || errors.Is(err, ErrNotFound) || cancelled {
+			reportErr = ErrTaskGone
+			cancel()
+		}
+	}
+
+	sheets, err := ffmpeg.ExtractSpriteSheetsWithProgress(
+		ctx,
+		source,
+		outDir,
+		int(task.SceneID),
+		p.TileWidth,
+		p.TileHeight,
+		p.GridCols,
+		p.GridRows,
+		p.FrameInterval,
+		p.FrameQuality,
+		p.Concurrency,
+		progress,
+	)
+	if reportErr != nil {
+		return TaskResult{}, reportErr
+	}
+	if err != nil {
+		return TaskResult{}, fmt.Errorf("sprite sheet generation failed: %w", err)
+	}
+
+	sort.Strings(sheets)
+	for _, name := range sheets {
+		if err := a.client.UploadArtifact(ctx, task.ID, name, filepath.Join(outDir, name)); err != nil {
+			return TaskResult{}, fmt.Errorf("failed to upload %s: %w", name, err)
+		}
+	}
+
+	return TaskResult{SpriteSheets: sheets}, nil
+}
+
+// resolveSource returns a local path for the task's source, downloading it when
+// it is not reachable through a path mapping.
+func (a *Agent) resolveSource(ctx context.Context, task *Task, taskDir string) (string, error) {
+	if local, ok := mapPath(task.SourcePath, a.cfg.PathMappings); ok {
+		if info, err := os.Stat(local); err == nil && (task.SourceSize == 0 || info.Size() == task.SourceSize) {
+			return local, nil
+		}
+	}
+
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		return "", err
+	}
+	dst := filepath.Join(taskDir, "source"+filepath.Ext(task.SourcePath))
+	f, err := os.Create(dst)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	a.logger.Info("Downloading source",
+		zap.String("task_id", task.ID),
+		zap.Int64("size", task.SourceSize),
+	)
+	if err := a.client.DownloadSource(ctx, task.ID, f); err != nil {
+		return "", err
+	}
+	return dst, f.Close()
+}
+
+func (a *Agent) cancelTask(taskID string) {
+	a.runningMu.Lock()
+	cancel, ok := a.running[taskID]
+	a.runningMu.Unlock()
+	if ok {
+		a.logger.Info("Server cancelled task", zap.String("task_id", taskID))
+		cancel()
+	}
+}
+
+// mapPath rewrites a server path using the longest matching prefix mapping.
+// Without mappings the server path is tried as-is.
+func mapPath(serverPath string, mappings map[string]string) (string, bool) {
+	if len(mappings) == 0 {
+		return serverPath, true
+	}
+
+	best := ""
+	for prefix := range mappings {
+		if strings.HasPrefix(serverPath, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return mappings[best] + strings.TrimPrefix(serverPath, best), true
+}
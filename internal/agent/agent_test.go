@@ -0,0 +1,32 @@
+package agent
+
+import "testing"
+
+func TestMapPath(t *testing.T) {
+	mappings := map[string]string{
+		"/data":        "/mnt/nas",
+		"/data/videos": "/mnt/videos",
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		mappings map[string]string
+		want     string
+		wantOK   bool
+	}{
+		{"no mappings uses path as-is", "/data/a.mp4", nil, "/data/a.mp4", true},
+		{"longest prefix wins", "/data/videos/a.mp4", mappings, "/mnt/videos/a.mp4", true},
+		{"shorter prefix", "/data/other/a.mp4", mappings, "/mnt/nas/other/a.mp4", true},
+		{"unmapped path", "/srv/a.mp4", mappings, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mapPath(tt.path, tt.mappings)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("mapPath(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when the server does not know the agent or task.
+	// For agent-level calls it means the agent must register again.
+	ErrNotFound = errors.New("not found")
+	// ErrTaskGone is returned when the server has abandoned the task
+	ErrTaskGone = errors.New("task cancelled by server")
+)
+
+// Client talks to the server's agent API
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+
+	mu      sync.RWMutex
+	agentID string
+}
+
+// NewClient creates a new Client for the server at baseURL
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/") + "/api/v1/agents",
+		token:   token,
+		// No global timeout: claims long-poll and downloads can be large
+		http: &http.Client{},
+	}
+}
+
+// AgentID returns the ID assigned by the last successful registration
+func (c *Client) AgentID() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.agentID
+}
+
+// Register registers the agent and remembers the assigned ID
+func (c *Client) Register(ctx context.Context, req RegisterRequest) (*RegisterResponse, error) {
+	var resp RegisterResponse
+	if err := c.doJSON(ctx, http.MethodPost, "/register", req, &resp); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.agentID = resp.AgentID
+	c.mu.Unlock()
+	return &resp, nil
+}
+
+// Heartbeat keeps the agent registered and returns tasks to abandon
+func (c *Client) Heartbeat(ctx context.Context) (*HeartbeatResponse, error) {
+	var resp HeartbeatResponse
+	if err := c.doJSON(ctx, http.MethodPost, c.agentPath("/heartbeat"), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Claim long-polls for a task. It returns nil when none arrived within wait.
+func (c *Client) Claim(ctx context.Context, wait time.Duration) (*Task, error) {
+	path := c.agentPath(fmt.Sprintf("/claim?wait=%d", int(wait.Seconds())))
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	var task Task
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to decode task: %w", err)
+	}
+	return &task, nil
+}
+
+// DownloadSource streams the task's source file into dst
+func (c *Client) DownloadSource(ctx context.Context, taskID string, dst io.Writer) error {
+	req, err := c.newRequest(ctx, http.MethodGet, c.taskPath(taskID, "/source"), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, resp.Body); err != nil {
+		return fmt.Errorf("failed to download source: %w", err)
+	}
+	return nil
+}
+
+// ReportProgress sends task progress and reports whether the server cancelled the task
+func (c *Client) ReportProgress(ctx context.Context, taskID string, progress int) (bool, error) {
+	var resp ProgressResponse
+	if err := c.doJSON(ctx, http.MethodPost, c.taskPath(taskID, "/progress"), ProgressRequest{Progress: progress}, &resp); err != nil {
+		return false, err
+	}
+	return resp.Cancelled, nil
+}
+
+// UploadArtifact uploads a local file as a task artifact
+func (c *Client) UploadArtifact(ctx context.Context, taskID, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPut, c.taskPath(taskID, "/artifacts/"+url.PathEscape(name)), f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp)
+}
+
+// Complete reports the task result
+func (c *Client) Complete(ctx context.Context, taskID string, result TaskResult) error {
+	return c.doJSON(ctx, http.MethodPost, c.taskPath(taskID, "/complete"), result, nil)
+}
+
+func (c *Client) agentPath(suffix string) string {
+	return "/" + url.PathEscape(c.AgentID()) + suffix
+}
+
+func (c *Client) taskPath(taskID, suffix string) string {
+	return c.agentPath("/tasks/" + url.PathEscape(taskID) + suffix)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	return req, nil
+}
+
+func (c *Client) doJSON(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		buf, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// checkStatus maps non-2xx responses to errors
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusGone:
+		return ErrTaskGone
+	}
+
+	var body struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&body)
+	if body.Error != "" {
+		return fmt.Errorf("server returned %d: %s", resp.StatusCode, body.Error)
+	}
+	return fmt.Errorf("server returned %d", resp.StatusCode)
+}
@@ -0,0 +1,66 @@
+package agent
+
+// Wire types shared by the server's agent API and the remote agent binary.
+// Everything here is JSON-serialized over HTTP, so field names are part of the protocol.
+
+// Phases that can currently be dispatched to remote agents.
+const (
+	PhaseSprites = "sprites"
+)
+
+// RegisterRequest is sent by an agent when it starts (or after the server forgot it).
+type RegisterRequest struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Phases      []string `json:"phases"`
+	Concurrency int      `json:"concurrency"`
+}
+
+// RegisterResponse assigns the agent its ID for subsequent calls.
+type RegisterResponse struct {
+	AgentID           string `json:"agent_id"`
+	HeartbeatInterval int    `json:"heartbeat_interval"` // seconds
+}
+
+// HeartbeatResponse lists tasks assigned to the agent that the server no longer wants.
+type HeartbeatResponse struct {
+	CancelTasks []string `json:"cancel_tasks"`
+}
+
+// Task is a serialized job handed to an agent. Exactly one of the phase-specific
+// parameter blocks is set, matching Phase.
+type Task struct {
+	ID         string         `json:"id"`
+	SceneID    uint           `json:"scene_id"`
+	Phase      string         `json:"phase"`
+	SourcePath string         `json:"source_path"` // path on the server, usable when storage is shared
+	SourceSize int64          `json:"source_size"`
+	Sprites    *SpritesParams `json:"sprites,omitempty"`
+}
+
+// SpritesParams mirrors the arguments of ffmpeg.ExtractSpriteSheetsWithProgress.
+type SpritesParams struct {
+	TileWidth     int `json:"tile_width"`
+	TileHeight    int `json:"tile_height"`
+	GridCols      int `json:"grid_cols"`
+	GridRows      int `json:"grid_rows"`
+	FrameInterval int `json:"frame_interval"`
+	FrameQuality  int `json:"frame_quality"`
+	Concurrency   int `json:"concurrency"`
+}
+
+// ProgressRequest reports task progress (0-100).
+type ProgressRequest struct {
+	Progress int `json:"progress"`
+}
+
+// ProgressResponse tells the agent whether it should abandon the task.
+type ProgressResponse struct {
+	Cancelled bool `json:"cancelled"`
+}
+
+// TaskResult is sent when a task finishes. Artifacts must be uploaded before completing.
+type TaskResult struct {
+	Error        string   `json:"error,omitempty"`
+	SpriteSheets []string `json:"sprite_sheets,omitempty"`
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+
+	"github.com/gin-gonic/gin"
+)
+
+type auditRoute struct {
+	action       string
+	resourceType string
+}
+
+// auditedRoutes names the action recorded for a successful request to a route,
+// keyed by "METHOD /full/path". Other successful changes under /api/v1/admin/
+// are recorded as admin.change.
+var auditedRoutes = map[string]auditRoute{
+	"DELETE /api/v1/scenes/:id":                        {"scene.trash", "scene"},
+	"PUT /api/v1/scenes/:id/details":                   {"scene.update", "scene"},
+	"PUT /api/v1/admin/scenes/:id/scene-metadata":      {"scene.apply_metadata", "scene"},
+	"DELETE /api/v1/tags/:id":                          {"tag.delete", "tag"},
+	"PUT /api/v1/admin/tags/:id":                       {"tag.update", "tag"},
+	"POST /api/v1/admin/tags/:id/merge":                {"tag.merge", "tag"},
+	"DELETE /api/v1/admin/tags/unused":                 {"tag.delete_unused", "tag"},
+	"PATCH /api/v1/scenes/bulk":                        {"scene.bulk_update", "scene"},
+	"POST /api/v1/explorer/bulk/tags":                  {"scene.bulk_tags", "scene"},
+	"POST /api/v1/explorer/bulk/actors":                {"scene.bulk_actors", "scene"},
+	"POST /api/v1/explorer/bulk/studio":                {"scene.bulk_studio", "scene"},
+	"DELETE /api/v1/explorer/bulk/scenes":              {"scene.bulk_trash", "scene"},
+	"POST /api/v1/review-workflow/bulk":                {"scene.bulk_review_state", "scene"},
+	"PUT /api/v1/settings/password":                    {"user.password_change", "user"},
+	"PUT /api/v1/settings/username":                    {"user.username_change", "user"},
+	"POST /api/v1/auth/2fa/enable":                     {"user.two_factor_enable", "user"},
+	"POST /api/v1/auth/2fa/disable":                    {"user.two_factor_disable", "user"},
+	"DELETE /api/v1/auth/sessions":                     {"session.revoke_others", "session"},
+	"DELETE /api/v1/auth/sessions/:id":                 {"session.revoke", "session"},
+	"POST /api/v1/api-keys":                            {"api_key.create", "api_key"},
+	"DELETE /api/v1/api-keys/:id":                      {"api_key.revoke", "api_key"},
+	"POST /api/v1/admin/users":                         {"user.create", "user"},
+	"PUT /api/v1/admin/users/:id/role":                 {"user.role_change", "user"},
+	"PUT /api/v1/admin/users/:id/password":             {"user.password_reset", "user"},
+	"DELETE /api/v1/admin/users/:id":                   {"user.delete", "user"},
+	"POST /api/v1/admin/invites":                       {"invite.create", "invite"},
+	"DELETE /api/v1/admin/invites/:id":                 {"invite.revoke", "invite"},
+	"PUT /api/v1/admin/roles/:id/permissions":          {"role.permissions_change", "role"},
+	"PUT /api/v1/admin/roles/:id/content-restrictions": {"role.content_restriction_change", "role"},
+	"DELETE /api/v1/admin/api-keys/:id":                {"api_key.revoke", "api_key"},
+	"PUT /api/v1/admin/pool-config":                    {"config.update", "pool_config"},
+	"PUT /api/v1/admin/processing-config":              {"config.update", "processing_config"},
+	"PUT /api/v1/admin/trigger-config":                 {"config.update", "trigger_config"},
+	"PUT /api/v1/admin/retry-config":                   {"config.update", "retry_config"},
+	"PUT /api/v1/admin/search/config":                  {"config.update", "search_config"},
+	"PUT /api/v1/admin/app-settings":                   {"config.update", "app_settings"},
+	"PUT /api/v1/admin/scan/exclusions":                {"config.update", "scan_exclusions"},
+	"POST /api/v1/admin/scan/dropbox":                  {"scan.dropbox_import", "scan"},
+	"POST /api/v1/admin/import/urls":                   {"import.url", "remote_import"},
+	"POST /api/v1/admin/import/torrents/run":           {"import.torrents", "torrent_import"},
+	"POST /api/v1/admin/import/torrents/:id/retry":     {"import.torrent_retry", "torrent_import"},
+	"POST /api/v1/admin/storage-paths":                 {"storage_path.create", "storage_path"},
+	"PUT /api/v1/admin/storage-paths/:id":              {"storage_path.update", "storage_path"},
+	"DELETE /api/v1/admin/storage-paths/:id":           {"storage_path.delete", "storage_path"},
+	"POST /api/v1/admin/explorer/move":                 {"scene.move_files", "scene"},
+	"POST /api/v1/admin/explorer/migrate":              {"scene.migrate_files", "scene"},
+	"POST /api/v1/admin/explorer/scenes/:id/rename":    {"scene.rename_file", "scene"},
+	"POST /api/v1/admin/trash/:id/restore":             {"scene.restore", "scene"},
+	"DELETE /api/v1/admin/trash/:id":                   {"scene.delete", "scene"},
+	"DELETE /api/v1/admin/trash":                       {"trash.empty", "scene"},
+	"POST /api/v1/admin/duplicates/:id/resolve":        {"duplicate.resolve", "duplicate_group"},
+	"DELETE /api/v1/admin/actors/:id":                  {"actor.delete", "actor"},
+	"POST /api/v1/admin/actors/:id/merge":              {"actor.merge", "actor"},
+	"POST /api/v1/admin/actors/:id/image/generate":     {"actor.image_generate", "actor"},
+	"POST /api/v1/admin/actor-images/generate":         {"actor.image_generate_missing", "actor"},
+	"DELETE /api/v1/admin/studios/:id":                 {"studio.delete", "studio"},
+
+	// PornDB auto-match and its review queue write scene metadata
+	"POST /api/v1/admin/porndb/auto-match":                    {"scene.porndb_auto_match", "scene"},
+	"POST /api/v1/admin/porndb/match-reviews/:sceneId/accept": {"scene.apply_metadata", "scene"},
+	"POST /api/v1/admin/scenes/:id/apply-match":               {"scene.apply_metadata", "scene"},
+
+	// PornDB performer syncs overwrite actor details
+	"POST /api/v1/admin/porndb/performer-sync":      {"actor.porndb_sync", "actor"},
+	"PUT /api/v1/admin/actors/:id/porndb-sync":      {"actor.porndb_sync_settings", "actor"},
+	"POST /api/v1/admin/actors/:id/porndb-sync/run": {"actor.porndb_sync", "actor"},
+
+	// Scraping a page into a scene writes its metadata
+	"POST /api/v1/scenes/:id/scrape": {"scene.scrape", "scene"},
+}
+
+// auditSkippedRoutes are admin POSTs that only read or test and change nothing
+var auditSkippedRoutes = map[string]bool{
+	"POST /api/v1/admin/storage-paths/validate":     true,
+	"POST /api/v1/admin/storage-paths/health/check": true,
+	"POST /api/v1/admin/scan/exclusions/test":       true,
+}
+
+// auditResourceParams are the route parameters tried, in order, as the resource ID
+var auditResourceParams = []string{"id", "uuid", "job_id", "jobID", "sceneId"}
+
+// AuditMiddleware records successful destructive and administrative requests in
+// the audit log. Reads and failed requests are not recorded.
+func AuditMiddleware(audit *core.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		status := c.Writer.Status()
+		if status >= 400 {
+			return
+		}
+
+		route := c.FullPath()
+		key := c.Request.Method + " " + route
+		target, ok := auditedRoutes[key]
+		if !ok {
+			if !strings.HasPrefix(route, "/api/v1/admin/") || auditSkippedRoutes[key] {
+				return
+			}
+			target = auditRoute{action: "admin.change"}
+		}
+
+		entry := data.AuditEntry{
+			Action:       target.action,
+			ResourceType: target.resourceType,
+			Method:       c.Request.Method,
+			Route:        route,
+			Status:       status,
+			IPAddress:    c.ClientIP(),
+		}
+		for _, param := range auditResourceParams {
+			if v := c.Param(param); v != "" {
+				entry.ResourceID = v
+				break
+			}
+		}
+		if user, err := GetUserFromContext(c); err == nil {
+			entry.UserID = &user.UserID
+			entry.Username = user.Username
+			if user.APIKeyID != 0 {
+				entry.APIKeyID = &user.APIKeyID
+			}
+		}
+
+		audit.Record(entry)
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newAuditTestRouter(t *testing.T, status int) (*gin.Engine, *mocks.MockAuditRepository) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAuditRepository(ctrl)
+	audit := core.NewAuditService(repo, 365, zap.NewNop())
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &core.UserPayload{UserID: 7, Username: "alice", Role: "admin"})
+	})
+	router.Use(AuditMiddleware(audit))
+	respond := func(c *gin.Context) { c.Status(status) }
+	router.DELETE("/api/v1/scenes/:id", respond)
+	router.GET("/api/v1/scenes/:id", respond)
+	router.POST("/api/v1/admin/jobs/:job_id/cancel", respond)
+	router.POST("/api/v1/admin/storage-paths/validate", respond)
+	router.POST("/api/v1/playlists", respond)
+	return router, repo
+}
+
+func serveAudit(router *gin.Engine, method, path string) {
+	req, _ := http.NewRequest(method, path, nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestAuditMiddleware_RecordsMappedRoute(t *testing.T) {
+	router, repo := newAuditTestRouter(t, http.StatusNoContent)
+
+	repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(entry *data.AuditEntry) error {
+		if entry.Action != "scene.trash" || entry.ResourceType != "scene" || entry.ResourceID != "42" {
+			t.Fatalf("unexpected entry: %+v", entry)
+		}
+		if entry.UserID == nil || *entry.UserID != 7 || entry.Username != "alice" {
+			t.Fatalf("expected acting user to be recorded, got %+v", entry)
+		}
+		if entry.Route != "/api/v1/scenes/:id" || entry.Status != http.StatusNoContent {
+			t.Fatalf("unexpected route or status: %+v", entry)
+		}
+		return nil
+	})
+	serveAudit(router, "DELETE", "/api/v1/scenes/42")
+}
+
+func TestAuditMiddleware_RecordsOtherAdminChanges(t *testing.T) {
+	router, repo := newAuditTestRouter(t, http.StatusOK)
+
+	repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(entry *data.AuditEntry) error {
+		if entry.Action != "admin.change" || entry.ResourceID != "abc" {
+			t.Fatalf("unexpected entry: %+v", entry)
+		}
+		return nil
+	})
+	serveAudit(router, "POST", "/api/v1/admin/jobs/abc/cancel")
+}
+
+func TestAuditMiddleware_SkipsReadsFailuresAndUnlistedRoutes(t *testing.T) {
+	router, _ := newAuditTestRouter(t, http.StatusOK)
+	serveAudit(router, "GET", "/api/v1/scenes/42")
+	serveAudit(router, "POST", "/api/v1/admin/storage-paths/validate")
+	serveAudit(router, "POST", "/api/v1/playlists")
+
+	failing, _ := newAuditTestRouter(t, http.StatusConflict)
+	serveAudit(failing, "DELETE", "/api/v1/scenes/42")
+}
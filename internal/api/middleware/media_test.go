@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"goonhub/internal/config"
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+func newMediaTestRouter(t *testing.T, signer *core.MediaSigner) *gin.Engine {
+	authService, _, _ := newTestAuthService(t)
+	return newMediaTestRouterWithAuth(signer, authService)
+}
+
+func newMediaTestRouterWithAuth(signer *core.MediaSigner, authService *core.AuthService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/vtt/:videoId", MediaAccess(signer, authService, MediaSceneIDParam("videoId")), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/sprites/:filename", MediaAccess(signer, authService, MediaSceneIDPrefix("filename")), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func serveMedia(r *gin.Engine, path string) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestMediaAccess_DisabledAllowsAll(t *testing.T) {
+	r := newMediaTestRouter(t, core.NewMediaSigner("secret", config.MediaSigningConfig{}))
+
+	if code := serveMedia(r, "/vtt/1"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+}
+
+func TestMediaAccess_RequiresSignature(t *testing.T) {
+	signer := core.NewMediaSigner("secret", config.MediaSigningConfig{Enabled: true, TTL: time.Hour})
+	r := newMediaTestRouter(t, signer)
+
+	if code := serveMedia(r, "/vtt/1"); code != http.StatusForbidden {
+		t.Fatalf("expected 403 without signature, got %d", code)
+	}
+	if code := serveMedia(r, signer.SignPath("/vtt/1", 1)); code != http.StatusOK {
+		t.Fatalf("expected 200 with signature, got %d", code)
+	}
+	if code := serveMedia(r, signer.SignPath("/vtt/2", 1)); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for another scene's signature, got %d", code)
+	}
+	if code := serveMedia(r, signer.SignPath("/sprites/1_sprite_001.webp", 1)); code != http.StatusOK {
+		t.Fatalf("expected 200 for signed sprite sheet, got %d", code)
+	}
+	if code := serveMedia(r, signer.SignPath("/sprites/sprite.webp", 1)); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for unparseable sprite name, got %d", code)
+	}
+}
+
+func TestMediaAccess_SessionToken(t *testing.T) {
+	authService, userRepo, revokedRepo := newTestAuthService(t)
+	signer := core.NewMediaSigner("secret", config.MediaSigningConfig{Enabled: true, TTL: time.Hour})
+	r := newMediaTestRouterWithAuth(signer, authService)
+
+	user := &data.User{ID: 42, Username: "alice", Password: hashForTest(t, "testpass"), Role: "admin"}
+	userRepo.EXPECT().GetByUsername("alice").Return(user, nil)
+	userRepo.EXPECT().UpdateLastLogin(uint(42)).Return(nil)
+	token, _, err := authService.Login("alice", "testpass")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	revokedRepo.EXPECT().IsRevoked(gomock.Any()).Return(false, nil).AnyTimes()
+
+	for _, tc := range []struct {
+		token string
+		want  int
+	}{
+		{token, http.StatusOK},
+		{"not-a-token", http.StatusForbidden},
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/vtt/1", nil)
+		req.Header.Set("Authorization", "Bearer "+tc.token)
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Fatalf("expected %d, got %d", tc.want, w.Code)
+		}
+	}
+}
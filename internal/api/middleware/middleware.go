@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"fmt"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/apperrors"
 	"goonhub/internal/core"
 	"goonhub/internal/infrastructure/logging"
 	"net/http"
@@ -15,7 +18,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func Setup(r *gin.Engine, logger *logging.Logger, allowedOrigins []string, environment string) {
+func Setup(r *gin.Engine, logger *logging.Logger, requestStats *core.RequestStatsService, apiUsage *core.APIUsageService, allowedOrigins []string, environment string) {
 	// Panic Recovery
 	r.Use(gin.Recovery())
 
@@ -24,6 +27,11 @@ func Setup(r *gin.Engine, logger *logging.Logger, allowedOrigins []string, envir
 	// breaking seeking in Firefox)
 	r.Use(gzip.Gzip(gzip.DefaultCompression, gzip.WithExcludedPathsRegexs([]string{
 		`/api/v1/scenes/\d+/stream`,
+		`/api/v1/scenes/\d+/download`,
+		`/api/v1/downloads/bundle`,
+		`/api/v1/agents/[^/]+/tasks/[^/]+/source`,
+		`/cast/[^/]+/stream`,
+		`/api/v1/watch-parties/[^/]+/ws`,
 	})))
 
 	// Security Headers
@@ -33,7 +41,7 @@ func Setup(r *gin.Engine, logger *logging.Logger, allowedOrigins []string, envir
 	r.Use(RequestID())
 
 	// Structured Logger
-	r.Use(Logger(logger))
+	r.Use(Logger(logger, requestStats, apiUsage))
 
 	// CORS - validate origins at startup in production
 	if environment == "production" {
@@ -43,14 +51,36 @@ func Setup(r *gin.Engine, logger *logging.Logger, allowedOrigins []string, envir
 			}
 		}
 	}
-	r.Use(cors.New(cors.Config{
+	appCORS := cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
-	}))
+	})
+	r.Use(func(c *gin.Context) {
+		// Cast routes are fetched by cast receivers on other origins; CastCORS handles them
+		if strings.HasPrefix(c.Request.URL.Path, "/cast/") {
+			c.Next()
+			return
+		}
+		appCORS(c)
+	})
+}
+
+// CastCORS allows any origin to fetch cast streams. Cast URLs carry their own
+// signed token and never use cookies, so credentials are not allowed.
+func CastCORS() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("Access-Control-Allow-Origin", "*")
+		h.Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+		h.Set("Access-Control-Allow-Headers", "Range, Content-Type")
+		h.Set("Access-Control-Expose-Headers", "Content-Length, Content-Range, Accept-Ranges")
+		h.Set("Access-Control-Max-Age", "43200")
+		c.Next()
+	}
 }
 
 // SecurityHeaders adds essential security headers to all responses.
@@ -104,33 +134,72 @@ func RequestID() gin.HandlerFunc {
 	}
 }
 
-func Logger(logger *logging.Logger) gin.HandlerFunc {
+// Logger writes a structured access log line per request, feeds per-route
+// latency into requestStats and counts authenticated requests in apiUsage (may be
+// nil). Requests over the slow threshold are logged at warn level together with
+// the DB and Meilisearch time captured on the request context.
+func Logger(logger *logging.Logger, requestStats *core.RequestStatsService, apiUsage *core.APIUsageService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 
+		ctx, timings := core.WithRequestTimings(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
 		c.Next()
 
 		end := time.Now()
 		latency := end.Sub(start)
+		status := c.Writer.Status()
+		route := c.FullPath()
+
+		bytes := max(c.Writer.Size(), 0)
+		user, userErr := GetUserFromContext(c)
+
+		requestStats.Record(c.Request.Method, route, status, latency)
+		if userErr == nil {
+			apiUsage.Record(user.UserID, c.Request.Method, route, status, bytes)
+		}
 
 		if len(c.Errors) > 0 {
 			for _, e := range c.Errors.Errors() {
 				logger.Error(e)
 			}
-		} else {
-			logger.Info("Request",
-				zap.Int("status", c.Writer.Status()),
-				zap.String("method", c.Request.Method),
-				zap.String("path", path),
-				zap.String("query", query),
-				zap.String("ip", c.ClientIP()),
-				zap.String("user-agent", c.Request.UserAgent()),
-				zap.Duration("latency", latency),
-				zap.String("request_id", c.GetString("RequestID")),
-			)
+			return
+		}
+
+		fields := []zap.Field{
+			zap.Int("status", status),
+			zap.String("method", c.Request.Method),
+			zap.String("route", route),
+			zap.String("path", path),
+			zap.String("query", query),
+			zap.String("ip", c.ClientIP()),
+			zap.String("user-agent", c.Request.UserAgent()),
+			zap.Duration("latency", latency),
+			zap.Int("bytes", bytes),
+			zap.String("request_id", c.GetString("RequestID")),
+		}
+		if userErr == nil {
+			fields = append(fields, zap.String("user", user.Username))
 		}
+
+		if !requestStats.IsSlow(latency) {
+			logger.Info("Request", fields...)
+			return
+		}
+
+		dbTime, dbOps := timings.Total(core.TimingDB)
+		searchTime, searchOps := timings.Total(core.TimingSearch)
+		fields = append(fields,
+			zap.Duration("slow_threshold", requestStats.SlowThreshold()),
+			zap.Duration("db_time", dbTime),
+			zap.Int("db_ops", dbOps),
+			zap.Duration("search_time", searchTime),
+			zap.Int("search_ops", searchOps),
+		)
+		logger.Warn("Slow request", fields...)
 	}
 }
 
@@ -139,23 +208,7 @@ const AuthCookieName = "goonhub_auth"
 
 func AuthMiddleware(authService *core.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := ""
-
-		// Try to get token from HTTP-only cookie first (preferred, more secure)
-		if cookie, err := c.Cookie(AuthCookieName); err == nil && cookie != "" {
-			token = cookie
-		}
-
-		// Fall back to Authorization header for backward compatibility
-		if token == "" {
-			authHeader := c.GetHeader("Authorization")
-			if authHeader != "" {
-				token = strings.TrimPrefix(authHeader, "Bearer ")
-				if token == authHeader {
-					token = "" // Not a Bearer token
-				}
-			}
-		}
+		token := TokenFromRequest(c)
 
 		if token == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
@@ -175,6 +228,245 @@ func AuthMiddleware(authService *core.AuthService) gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthMiddleware sets the user on the context when the request carries a
+// valid token, and otherwise lets it through anonymously. Used on public routes
+// that only need to know who is asking, such as streaming.
+func OptionalAuthMiddleware(authService *core.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := TokenFromRequest(c); token != "" {
+			if payload, err := authService.ValidateToken(token); err == nil {
+				c.Set("user", payload)
+			}
+		}
+		c.Next()
+	}
+}
+
+// APIKeyHeader carries an API key for clients that can't send it as a Bearer token
+const APIKeyHeader = "X-API-Key"
+
+// TokenFromRequest returns the auth token from the HTTP-only cookie (preferred),
+// the X-API-Key header or the Authorization header (backward compatibility), or ""
+// if there is none.
+func TokenFromRequest(c *gin.Context) string {
+	if cookie, err := c.Cookie(AuthCookieName); err == nil && cookie != "" {
+		return cookie
+	}
+
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return key
+	}
+
+	authHeader := c.GetHeader("Authorization")
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if token == authHeader {
+		return "" // Not a Bearer token
+	}
+	return token
+}
+
+// privacyLockExemptRoutes stay reachable while a session is privacy-locked
+var privacyLockExemptRoutes = map[string]bool{
+	"GET /api/v1/auth/me":                   true,
+	"POST /api/v1/auth/logout":              true,
+	"GET /api/v1/auth/privacy-lock":         true,
+	"POST /api/v1/auth/privacy-lock/unlock": true,
+	"POST /api/v1/auth/privacy-lock/lock":   true,
+}
+
+// PrivacyLockMiddleware rejects requests from idle-locked sessions with 423 until
+// they are unlocked with the user's PIN. Must run after AuthMiddleware.
+func PrivacyLockMiddleware(privacyLockService *core.PrivacyLockService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if privacyLockExemptRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		userPayload, err := GetUserFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		// The lock guards browser sessions left unattended, not scripts
+		if userPayload.APIKeyID != 0 {
+			c.Next()
+			return
+		}
+
+		if err := privacyLockService.Check(userPayload.UserID, TokenFromRequest(c)); err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// twoFactorSetupExemptRoutes stay reachable for sessions that must set up two-factor
+// authentication before anything else
+var twoFactorSetupExemptRoutes = map[string]bool{
+	"GET /api/v1/auth/me":                   true,
+	"POST /api/v1/auth/logout":              true,
+	"GET /api/v1/auth/privacy-lock":         true,
+	"POST /api/v1/auth/privacy-lock/unlock": true,
+	"POST /api/v1/auth/privacy-lock/lock":   true,
+	"GET /api/v1/auth/2fa":                  true,
+	"POST /api/v1/auth/2fa/setup":           true,
+	"POST /api/v1/auth/2fa/enable":          true,
+}
+
+// TwoFactorSetupMiddleware rejects requests from sessions that logged in while policy
+// required two-factor authentication they had not set up, until it is enabled.
+// Must run after AuthMiddleware.
+func TwoFactorSetupMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if twoFactorSetupExemptRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		userPayload, err := GetUserFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		if userPayload.TwoFactorSetup {
+			response.Error(c, apperrors.ErrTwoFactorSetupRequired)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// apiKeyBlockedRoutePrefixes are off-limits to API keys: account management, key
+// management and administration need a browser session
+var apiKeyBlockedRoutePrefixes = []string{
+	"/api/v1/auth/",
+	"/api/v1/settings",
+	"/api/v1/api-keys",
+	"/api/v1/admin/",
+}
+
+// permissionCheckedKey is set by RequirePermission on requests that passed its check
+const permissionCheckedKey = "permission_checked"
+
+// APIKeyRouteGuardMiddleware rejects API key requests to account and admin routes.
+// Routes of an APIKeyScopedGroup also refuse keys when no permission was checked.
+// Must run after AuthMiddleware.
+func APIKeyRouteGuardMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userPayload, err := GetUserFromContext(c)
+		if err != nil || userPayload.APIKeyID == 0 {
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		for _, prefix := range apiKeyBlockedRoutePrefixes {
+			if strings.HasPrefix(route, prefix) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot be used for this endpoint"})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// APIKeyScopedGroup is a router group whose routes only let API key requests
+// reach their handler after a permission check passed (RequirePermission, on the
+// route or a parent group). A key is scoped to permissions, so a route outside
+// all of them is outside its scope too.
+type APIKeyScopedGroup struct {
+	*gin.RouterGroup
+}
+
+// NewAPIKeyScopedGroup wraps group; routes registered through the wrapper, or
+// through the groups it creates, get the API key check.
+func NewAPIKeyScopedGroup(group *gin.RouterGroup) *APIKeyScopedGroup {
+	return &APIKeyScopedGroup{RouterGroup: group}
+}
+
+func (g *APIKeyScopedGroup) Group(relativePath string, handlers ...gin.HandlerFunc) *APIKeyScopedGroup {
+	return &APIKeyScopedGroup{RouterGroup: g.RouterGroup.Group(relativePath, handlers...)}
+}
+
+// Handle registers a route with requireCheckedPermission right before its handler,
+// so it runs after every middleware of the route and its groups.
+func (g *APIKeyScopedGroup) Handle(httpMethod, relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	if len(handlers) > 0 {
+		last := len(handlers) - 1
+		scoped := make([]gin.HandlerFunc, 0, len(handlers)+1)
+		scoped = append(scoped, handlers[:last]...)
+		handlers = append(scoped, requireCheckedPermission, handlers[last])
+	}
+	return g.RouterGroup.Handle(httpMethod, relativePath, handlers...)
+}
+
+func (g *APIKeyScopedGroup) GET(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+func (g *APIKeyScopedGroup) POST(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+func (g *APIKeyScopedGroup) PUT(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodPut, relativePath, handlers...)
+}
+
+func (g *APIKeyScopedGroup) PATCH(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodPatch, relativePath, handlers...)
+}
+
+func (g *APIKeyScopedGroup) DELETE(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodDelete, relativePath, handlers...)
+}
+
+func (g *APIKeyScopedGroup) HEAD(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodHead, relativePath, handlers...)
+}
+
+func (g *APIKeyScopedGroup) OPTIONS(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return g.Handle(http.MethodOptions, relativePath, handlers...)
+}
+
+func (g *APIKeyScopedGroup) Any(relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodHead, http.MethodOptions, http.MethodDelete, http.MethodConnect, http.MethodTrace} {
+		g.Handle(method, relativePath, handlers...)
+	}
+	return g
+}
+
+func (g *APIKeyScopedGroup) Match(methods []string, relativePath string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	for _, method := range methods {
+		g.Handle(method, relativePath, handlers...)
+	}
+	return g
+}
+
+// requireCheckedPermission refuses API key requests that no permission check passed.
+// Session requests are left to the route's own authorization.
+func requireCheckedPermission(c *gin.Context) {
+	if user, err := GetUserFromContext(c); err == nil && user.APIKeyID != 0 {
+		if _, checked := c.Get(permissionCheckedKey); !checked {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot be used for this endpoint"})
+			c.Abort()
+			return
+		}
+	}
+	c.Next()
+}
+
 func RequireRole(role string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		user, exists := c.Get("user")
@@ -217,12 +509,34 @@ func RequirePermission(rbac *core.RBACService, permission string) gin.HandlerFun
 			return
 		}
 
-		if !rbac.HasPermission(userPayload.Role, permission) {
+		if !rbac.HasPermission(userPayload.Role, permission) || !userPayload.AllowsPermission(permission) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
 			c.Abort()
 			return
 		}
 
+		c.Set(permissionCheckedKey, permission)
+		c.Next()
+	}
+}
+
+// AgentAuthMiddleware authenticates remote job agents with the shared agent token.
+// All agent routes answer 404 when remote agents are disabled.
+func AgentAuthMiddleware(enabled bool, token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Remote agents are disabled"})
+			c.Abort()
+			return
+		}
+
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid agent token"})
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
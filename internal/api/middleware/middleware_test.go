@@ -3,6 +3,7 @@ package middleware
 import (
 	"goonhub/internal/core"
 	"goonhub/internal/data"
+	"goonhub/internal/infrastructure/logging"
 	"goonhub/internal/mocks"
 	"net/http"
 	"net/http/httptest"
@@ -237,3 +238,261 @@ func TestRequirePermission_Lacks(t *testing.T) {
 		t.Fatalf("expected 403 when permission lacking, got %d", w.Code)
 	}
 }
+
+func TestRequirePermission_APIKeyLimitedToScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roleRepo := mocks.NewMockRoleRepository(ctrl)
+	permRepo := mocks.NewMockPermissionRepository(ctrl)
+
+	roleRepo.EXPECT().GetAllRolePermissions().Return(map[string][]string{
+		"user": {"scenes:view", "scenes:download"},
+	}, nil)
+	rbac, _ := core.NewRBACService(roleRepo, permRepo, zap.NewNop())
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &core.UserPayload{UserID: 1, Role: "user", APIKeyID: 7, Scopes: []string{"scenes:view"}})
+		c.Next()
+	})
+	router.GET("/view", RequirePermission(rbac, "scenes:view"), func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+	router.GET("/download", RequirePermission(rbac, "scenes:download"), func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	req, _ := http.NewRequest("GET", "/view", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 for scoped permission, got %d", w.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/download", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 403 {
+		t.Fatalf("expected 403 for permission outside the key's scopes, got %d", w.Code)
+	}
+}
+
+func TestAPIKeyRouteGuardMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roleRepo := mocks.NewMockRoleRepository(ctrl)
+	permRepo := mocks.NewMockPermissionRepository(ctrl)
+
+	roleRepo.EXPECT().GetAllRolePermissions().Return(map[string][]string{
+		"admin": {"scenes:view", "scenes:delete"},
+	}, nil)
+	rbac, _ := core.NewRBACService(roleRepo, permRepo, zap.NewNop())
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		payload := &core.UserPayload{UserID: 1, Role: "admin"}
+		if c.GetHeader(APIKeyHeader) != "" {
+			payload.APIKeyID = 7
+			payload.Scopes = []string{"scenes:view"}
+		}
+		c.Set("user", payload)
+		c.Next()
+	})
+	router.Use(APIKeyRouteGuardMiddleware())
+	ok := func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	}
+	// Permission middleware wrapped in another handler, and a renamed copy
+	wrapped := func(c *gin.Context) {
+		RequirePermission(rbac, "scenes:view")(c)
+	}
+	renamed := RequirePermission(rbac, "scenes:view")
+
+	v1 := NewAPIKeyScopedGroup(router.Group("/api/v1"))
+	v1.GET("/scenes", RequirePermission(rbac, "scenes:view"), ok)
+	v1.DELETE("/scenes/:id", RequirePermission(rbac, "scenes:delete"), ok)
+	v1.GET("/wrapped", wrapped, ok)
+	v1.GET("/renamed", renamed, ok)
+	v1.POST("/explorer/bulk/tags", ok)
+	v1.GET("/admin/users", ok)
+	parties := v1.Group("/watch-parties")
+	parties.Use(RequirePermission(rbac, "scenes:view"))
+	parties.GET("", ok)
+	// Middleware that does not check a permission must not count as one
+	v1.GET("/passthrough", func(c *gin.Context) { c.Next() }, ok)
+
+	tests := []struct {
+		method string
+		path   string
+		apiKey bool
+		want   int
+	}{
+		{"GET", "/api/v1/scenes", true, 200},
+		{"GET", "/api/v1/wrapped", true, 200},
+		{"GET", "/api/v1/renamed", true, 200},
+		{"GET", "/api/v1/watch-parties", true, 200},
+		{"GET", "/api/v1/passthrough", true, 403},
+		{"GET", "/api/v1/passthrough", false, 200},
+		// Outside the key's scopes
+		{"DELETE", "/api/v1/scenes/1", true, 403},
+		{"DELETE", "/api/v1/scenes/1", false, 200},
+		// No declared permission, so outside every key's scopes
+		{"POST", "/api/v1/explorer/bulk/tags", true, 403},
+		{"POST", "/api/v1/explorer/bulk/tags", false, 200},
+		{"GET", "/api/v1/admin/users", true, 403},
+		{"GET", "/api/v1/admin/users", false, 200},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, tt.path, nil)
+		if tt.apiKey {
+			req.Header.Set(APIKeyHeader, "ghk_key")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Fatalf("%s %s (api key %v): expected %d, got %d", tt.method, tt.path, tt.apiKey, tt.want, w.Code)
+		}
+	}
+}
+
+func TestPrivacyLockMiddleware_LockedSession(t *testing.T) {
+	authSvc, userRepo, _ := newTestAuthService(t)
+	lockSvc := core.NewPrivacyLockService(userRepo, authSvc, zap.NewNop())
+
+	user := &data.User{ID: 42, Username: "alice", PrivacyPinHash: hashForTest(t, "1234"), PrivacyLockMinutes: 15}
+	userRepo.EXPECT().GetByID(uint(42)).Return(user, nil)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &core.UserPayload{UserID: 42, Username: "alice", Role: "user"})
+		c.Next()
+	})
+	router.Use(PrivacyLockMiddleware(lockSvc))
+	router.GET("/api/v1/scenes", func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+	router.GET("/api/v1/auth/privacy-lock", func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	// A session without recorded activity is locked
+	req, _ := http.NewRequest("GET", "/api/v1/scenes", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusLocked {
+		t.Fatalf("expected 423 for locked session, got %d", w.Code)
+	}
+
+	// Lock status stays reachable
+	req, _ = http.NewRequest("GET", "/api/v1/auth/privacy-lock", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 for exempt route, got %d", w.Code)
+	}
+
+	lockSvc.StartSession("token")
+	req, _ = http.NewRequest("GET", "/api/v1/scenes", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 for unlocked session, got %d", w.Code)
+	}
+}
+
+func TestTwoFactorSetupMiddleware(t *testing.T) {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &core.UserPayload{UserID: 1, Username: "root", Role: "admin", TwoFactorSetup: c.GetHeader("X-Setup") != ""})
+		c.Next()
+	})
+	router.Use(TwoFactorSetupMiddleware())
+	router.GET("/api/v1/scenes", func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+	router.POST("/api/v1/auth/2fa/setup", func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/scenes", nil)
+	req.Header.Set("X-Setup", "1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for setup-only session, got %d", w.Code)
+	}
+
+	req, _ = http.NewRequest("POST", "/api/v1/auth/2fa/setup", nil)
+	req.Header.Set("X-Setup", "1")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 for exempt route, got %d", w.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/v1/scenes", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 for full session, got %d", w.Code)
+	}
+}
+
+func TestLogger_RecordsRouteStatsAndTimings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	stats := core.NewRequestStatsService(time.Nanosecond)
+
+	router := gin.New()
+	router.Use(Logger(&logging.Logger{Logger: zap.NewNop()}, stats, nil))
+	router.GET("/api/v1/scenes/:id", func(c *gin.Context) {
+		core.TrackTiming(c.Request.Context(), core.TimingDB)()
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/scenes/7", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	// Unmatched paths are not aggregated
+	req, _ = http.NewRequest("GET", "/nope", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	slowest := stats.Slowest(10)
+	if len(slowest) != 1 {
+		t.Fatalf("expected 1 endpoint, got %d", len(slowest))
+	}
+	if slowest[0].Route != "/api/v1/scenes/:id" || slowest[0].Count != 1 || slowest[0].SlowCount != 1 {
+		t.Fatalf("unexpected endpoint stats: %+v", slowest[0])
+	}
+}
+
+func TestSetup_CastRoutesBypassAppCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	Setup(router, &logging.Logger{Logger: zap.NewNop()}, core.NewRequestStatsService(time.Second), nil, []string{"http://app.local"}, "development")
+	router.GET("/cast/:token/stream", CastCORS(), func(c *gin.Context) { c.Status(200) })
+	router.GET("/api/v1/scenes", func(c *gin.Context) { c.Status(200) })
+
+	// A cast receiver on another origin may fetch cast streams
+	req, _ := http.NewRequest("GET", "/cast/abc/stream", nil)
+	req.Header.Set("Origin", "https://receiver.example")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != 200 || w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Fatalf("expected cast stream open to any origin, got %d %q", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	// Other routes still only allow the configured origins
+	req, _ = http.NewRequest("GET", "/api/v1/scenes", nil)
+	req.Header.Set("Origin", "https://receiver.example")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected foreign origin rejected on API routes, got %d", w.Code)
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"goonhub/internal/core"
 	"goonhub/internal/data"
 	"goonhub/internal/infrastructure/logging"
 	"html"
@@ -27,6 +28,7 @@ type OGMiddleware struct {
 	playlistRepo    data.PlaylistRepository
 	shareLinkRepo   data.ShareLinkRepository
 	appSettingsRepo data.AppSettingsRepository
+	mediaSigner     *core.MediaSigner
 	logger          *logging.Logger
 }
 
@@ -37,6 +39,7 @@ func NewOGMiddleware(
 	playlistRepo data.PlaylistRepository,
 	shareLinkRepo data.ShareLinkRepository,
 	appSettingsRepo data.AppSettingsRepository,
+	mediaSigner *core.MediaSigner,
 	logger *logging.Logger,
 ) *OGMiddleware {
 	return &OGMiddleware{
@@ -46,6 +49,7 @@ func NewOGMiddleware(
 		playlistRepo:    playlistRepo,
 		shareLinkRepo:   shareLinkRepo,
 		appSettingsRepo: appSettingsRepo,
+		mediaSigner:     mediaSigner,
 		logger:          logger,
 	}
 }
@@ -130,7 +134,7 @@ func (m *OGMiddleware) serveScene(c *gin.Context, idStr string, baseURL string)
 	}
 
 	desc := truncateDescription(scene.Description)
-	image := fmt.Sprintf("%s/thumbnails/%d?size=lg", baseURL, scene.ID)
+	image := baseURL + m.mediaSigner.SignPath(fmt.Sprintf("/thumbnails/%d?size=lg", scene.ID), scene.ID)
 	url := fmt.Sprintf("%s/watch/%d", baseURL, scene.ID)
 
 	renderOGPage(c, title, desc, image, url, "video.other")
@@ -199,8 +203,8 @@ func (m *OGMiddleware) serveShare(c *gin.Context, token string, baseURL string)
 		return false
 	}
 
-	// Only serve OG for public share links
-	if link.ShareType != "public" {
+	// Only serve OG for public share links; a password keeps the title private too
+	if link.ShareType != "public" || link.PasswordHash != "" {
 		return false
 	}
 
@@ -220,7 +224,7 @@ func (m *OGMiddleware) serveShare(c *gin.Context, token string, baseURL string)
 	}
 
 	desc := truncateDescription(scene.Description)
-	image := fmt.Sprintf("%s/thumbnails/%d?size=lg", baseURL, scene.ID)
+	image := baseURL + m.mediaSigner.SignPath(fmt.Sprintf("/thumbnails/%d?size=lg", scene.ID), scene.ID)
 	url := fmt.Sprintf("%s/share/%s", baseURL, token)
 
 	renderOGPage(c, title, desc, image, url, "video.other")
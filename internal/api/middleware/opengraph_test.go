@@ -31,7 +31,7 @@ func setupOGTest(t *testing.T) (*OGMiddleware, *mocks.MockSceneRepository, *mock
 	appSettingsRepo := mocks.NewMockAppSettingsRepository(ctrl)
 	logger := &logging.Logger{Logger: zap.NewNop()}
 
-	mw := NewOGMiddleware(sceneRepo, actorRepo, studioRepo, playlistRepo, shareLinkRepo, appSettingsRepo, logger)
+	mw := NewOGMiddleware(sceneRepo, actorRepo, studioRepo, playlistRepo, shareLinkRepo, appSettingsRepo, nil, logger)
 	return mw, sceneRepo, actorRepo, studioRepo, playlistRepo, appSettingsRepo
 }
 
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SceneAccess guards the /scenes/:id routes: a scene the user's role is
+// restricted from answers not found on every sub-route (markers, tags,
+// interactions, shares, ...), as if it did not exist. Routes without a numeric
+// :id pass through to their own handlers.
+func SceneAccess(sceneService *core.SceneService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		role := ""
+		if user, err := GetUserFromContext(c); err == nil {
+			role = user.Role
+		}
+		if err := sceneService.CheckSceneAccess(uint(id), role); err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestSceneAccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roleRepo := mocks.NewMockRoleRepository(ctrl)
+	permRepo := mocks.NewMockPermissionRepository(ctrl)
+	restrictionRepo := mocks.NewMockContentRestrictionRepository(ctrl)
+
+	roleRepo.EXPECT().GetAllRolePermissions().Return(map[string][]string{
+		"guest": {"scenes:view"},
+	}, nil)
+	rbac, _ := core.NewRBACService(roleRepo, permRepo, zap.NewNop())
+	restrictionRepo.EXPECT().ListAll().Return([]data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", StoragePathIDs: []int64{1}},
+	}, nil)
+	if err := rbac.SetContentRestrictionRepository(restrictionRepo); err != nil {
+		t.Fatalf("failed to load content restrictions: %v", err)
+	}
+	restrictionRepo.EXPECT().IsSceneVisible(gomock.Any(), uint(7)).Return(false, nil)
+	restrictionRepo.EXPECT().IsSceneVisible(gomock.Any(), uint(8)).Return(true, nil)
+
+	sceneService := &core.SceneService{}
+	sceneService.SetRBACService(rbac)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", &core.UserPayload{UserID: 1, Role: "guest"})
+		c.Next()
+	})
+	scenes := router.Group("/scenes", SceneAccess(sceneService))
+	scenes.GET("/segments", func(c *gin.Context) { c.Status(http.StatusOK) })
+	scenes.GET("/:id/markers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for path, want := range map[string]int{
+		"/scenes/7/markers": http.StatusNotFound,
+		"/scenes/8/markers": http.StatusOK,
+		"/scenes/segments":  http.StatusOK,
+	} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Fatalf("GET %s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShareAccessCookieName holds the access token of an unlocked password-protected
+// share link. The cookie's path is the link's own API path, so it is only sent
+// for that link's data, stream and thumbnail.
+const ShareAccessCookieName = "goonhub_share"
+
+const shareLinkContextKey = "share_link"
+
+// ShareAccess guards the public /shares/:token routes. It acts as a constrained
+// session for the link: the request passes only if the link exists, has not
+// expired, the viewer is logged in for auth_required links, and the link was
+// unlocked for password-protected links. The access token is read from the
+// share cookie or, for clients without cookies, the "access" query parameter.
+func ShareAccess(shareService *core.ShareService, authService *core.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		isAuthenticated := false
+		if token := TokenFromRequest(c); token != "" {
+			_, err := authService.ValidateToken(token)
+			isAuthenticated = err == nil
+		}
+
+		accessToken := c.Query("access")
+		if cookie, err := c.Cookie(ShareAccessCookieName); err == nil && cookie != "" {
+			accessToken = cookie
+		}
+
+		link, err := shareService.AuthorizeShareLink(c.Param("token"), isAuthenticated, accessToken)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set(shareLinkContextKey, link)
+		c.Next()
+	}
+}
+
+// GetShareLinkFromContext returns the share link authorized by ShareAccess.
+func GetShareLinkFromContext(c *gin.Context) (*data.ShareLink, bool) {
+	value, exists := c.Get(shareLinkContextKey)
+	if !exists {
+		return nil, false
+	}
+	link, ok := value.(*data.ShareLink)
+	return link, ok
+}
+
+// ShareAccessCookie returns the cookie carrying a share link's access token.
+func ShareAccessCookie(shareToken string, access *core.ShareAccess, secure bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     ShareAccessCookieName,
+		Value:    access.Token,
+		Path:     "/api/v1/shares/" + shareToken,
+		Expires:  access.ExpiresAt,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newShareTestRouter(t *testing.T, link *data.ShareLink) (*gin.Engine, *core.ShareService) {
+	authSvc, _, _ := newTestAuthService(t)
+	repo := mocks.NewMockShareLinkRepository(gomock.NewController(t))
+	repo.EXPECT().GetByToken(link.Token).Return(link, nil).AnyTimes()
+	shareSvc := core.NewShareService(repo, mocks.NewMockSceneRepository(gomock.NewController(t)), "test-secret", zap.NewNop())
+
+	router := gin.New()
+	router.GET("/api/v1/shares/:token/stream", ShareAccess(shareSvc, authSvc), func(c *gin.Context) {
+		shared, ok := GetShareLinkFromContext(c)
+		if !ok || shared.ID != link.ID {
+			t.Fatalf("expected the share link on the context, got %+v", shared)
+		}
+		c.Status(http.StatusOK)
+	})
+	return router, shareSvc
+}
+
+func TestShareAccess_PasswordLinkNeedsUnlock(t *testing.T) {
+	link := &data.ShareLink{ID: 1, Token: "locked", SceneID: 3, ShareType: data.ShareTypePublic, PasswordHash: hashForTest(t, "hunter22")}
+	router, shareSvc := newShareTestRouter(t, link)
+
+	req, _ := http.NewRequest("GET", "/api/v1/shares/locked/stream", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 before unlocking, got %d", w.Code)
+	}
+
+	access, err := shareSvc.UnlockShareLink("locked", false, "hunter22")
+	if err != nil {
+		t.Fatalf("failed to unlock: %v", err)
+	}
+	cookie := ShareAccessCookie("locked", access, false)
+	if cookie.Path != "/api/v1/shares/locked" {
+		t.Fatalf("expected the cookie to be scoped to the link, got path %q", cookie.Path)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/v1/shares/locked/stream", nil)
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 with the share cookie, got %d", w.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/v1/shares/locked/stream?access="+access.Token, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 with the access query parameter, got %d", w.Code)
+	}
+}
+
+func TestShareAccess_AuthRequiredLinkRejectsAnonymous(t *testing.T) {
+	link := &data.ShareLink{ID: 2, Token: "members", SceneID: 3, ShareType: data.ShareTypeAuthRequired}
+	router, _ := newShareTestRouter(t, link)
+
+	req, _ := http.NewRequest("GET", "/api/v1/shares/members/stream", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for anonymous viewer, got %d", w.Code)
+	}
+}
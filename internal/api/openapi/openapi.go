@@ -0,0 +1,340 @@
+// Package openapi builds an OpenAPI 3 document for the API from the router's
+// registered routes and per-route annotations.
+//
+// Every /api route becomes an operation, so the document never misses an
+// endpoint. Annotations add what the router can't know: a summary, query
+// parameters, and request and response bodies, given as Go values whose types
+// are turned into JSON schemas by reflection.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.0.3"
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem maps lowercase HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// SecurityRequirement maps security scheme names to scopes.
+type SecurityRequirement map[string][]string
+
+// Object describes an ad-hoc JSON object, such as a gin.H response, as property
+// names mapped to example values of the property types.
+type Object map[string]any
+
+// Binary marks a request or response body as a file rather than JSON.
+type Binary struct {
+	// ContentType defaults to application/octet-stream
+	ContentType string
+}
+
+// Multipart describes a multipart/form-data request: form field names mapped to
+// example values, with Binary values for file fields.
+type Multipart map[string]any
+
+// Op annotates the operation registered for one route.
+type Op struct {
+	Summary     string
+	Description string
+	// Tags replaces the tag derived from the path
+	Tags []string
+	// Query is a struct whose `form` tagged fields are query parameters, or an Object
+	Query any
+	// Path overrides the schema type ("integer" or "string") of path parameters
+	Path map[string]string
+	// Body is the request body: a value, an Object, a Multipart or a Binary
+	Body any
+	// Response is the success response body; nil leaves it undocumented
+	Response any
+	// Status is the success status, default 200
+	Status int
+	// Public operations need no credentials
+	Public bool
+}
+
+// Route identifies an annotated route as "METHOD /path", using the router's
+// ":param" syntax.
+type Route = string
+
+// Annotations maps routes to their annotations.
+type Annotations map[Route]Op
+
+// Config holds what Build needs besides the routes.
+type Config struct {
+	Info Info
+	// PathPrefix limits the document to routes under it, such as "/api"
+	PathPrefix string
+	// ErrorResponse is the body of error responses
+	ErrorResponse any
+	// Security lists the schemes protected operations accept
+	SecuritySchemes map[string]SecurityScheme
+}
+
+var pathParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// Build returns the document for routes. Annotated routes that aren't
+// registered are ignored, so stale annotations can't describe missing endpoints.
+func Build(cfg Config, routes gin.RoutesInfo, annotations Annotations) *Document {
+	schemas := NewSchemas()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    cfg.Info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         schemas.Components,
+			SecuritySchemes: cfg.SecuritySchemes,
+		},
+	}
+	for name := range cfg.SecuritySchemes {
+		doc.Security = append(doc.Security, SecurityRequirement{name: {}})
+	}
+	sort.Slice(doc.Security, func(i, j int) bool { return firstKey(doc.Security[i]) < firstKey(doc.Security[j]) })
+
+	var errorSchema *Schema
+	if cfg.ErrorResponse != nil {
+		errorSchema = schemas.For(cfg.ErrorResponse)
+	}
+
+	sorted := append(gin.RoutesInfo(nil), routes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	tags := map[string]bool{}
+	operationIDs := map[string]int{}
+	for _, route := range sorted {
+		if !strings.HasPrefix(route.Path, cfg.PathPrefix) || route.Method == http.MethodHead || route.Method == http.MethodOptions {
+			continue
+		}
+		op := annotations[route.Method+" "+route.Path]
+		operation := buildOperation(route, op, cfg.PathPrefix, schemas, errorSchema)
+
+		id := operation.OperationID
+		operationIDs[id]++
+		if n := operationIDs[id]; n > 1 {
+			operation.OperationID = fmt.Sprintf("%s%d", id, n)
+		}
+		for _, tag := range operation.Tags {
+			tags[tag] = true
+		}
+
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		item := doc.Paths[path]
+		if item == nil {
+			item = PathItem{}
+			doc.Paths[path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc
+}
+
+func buildOperation(route gin.RouteInfo, op Op, prefix string, schemas *Schemas, errorSchema *Schema) *Operation {
+	name := handlerName(route.Handler)
+	operation := &Operation{
+		OperationID: lowerFirst(name),
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Responses:   map[string]Response{},
+	}
+	if operation.Summary == "" {
+		operation.Summary = humanize(name)
+	}
+	if len(operation.Tags) == 0 {
+		operation.Tags = []string{pathTag(route.Path, prefix)}
+	}
+	if op.Public {
+		// An empty requirement overrides the document-wide security
+		operation.Security = []SecurityRequirement{}
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		paramType := op.Path[match[1]]
+		if paramType == "" {
+			paramType = pathParamType(match[1])
+		}
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: paramType},
+		})
+	}
+	if op.Query != nil {
+		operation.Parameters = append(operation.Parameters, schemas.QueryParameters(op.Query)...)
+	}
+
+	if op.Body != nil {
+		operation.RequestBody = &RequestBody{Required: true, Content: schemas.content(op.Body)}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Response{Description: http.StatusText(status)}
+	if op.Response != nil {
+		success.Content = schemas.content(op.Response)
+	}
+	operation.Responses[fmt.Sprint(status)] = success
+	if errorSchema != nil {
+		operation.Responses["default"] = Response{
+			Description: "Error",
+			Content:     map[string]MediaType{"application/json": {Schema: errorSchema}},
+		}
+	}
+	return operation
+}
+
+// handlerName returns the method or function name of a gin handler, such as
+// ListScenes for "goonhub/internal/api/v1/handler.(*SceneHandler).ListScenes-fm".
+func handlerName(handler string) string {
+	name := handler[strings.LastIndex(handler, ".")+1:]
+	name = strings.TrimSuffix(name, "-fm")
+	if name == "" || strings.HasPrefix(name, "func") {
+		return "handler"
+	}
+	return name
+}
+
+// humanize turns ListScenes into "List scenes".
+func humanize(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := rune(name[i-1])
+			if !unicode.IsUpper(prev) {
+				b.WriteRune(' ')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// pathTag is the first path segment after the prefix and version, so
+// /api/v1/scenes/:id gets "scenes" and /api/v1/admin/jobs gets "admin".
+func pathTag(path, prefix string) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, prefix), "/"), "/")
+	for _, segment := range segments {
+		if segment == "" || segment == "v1" || strings.HasPrefix(segment, ":") {
+			continue
+		}
+		return segment
+	}
+	return "default"
+}
+
+// pathParamType guesses numeric IDs from the parameter name: "id" and names
+// ending in "ID" are database IDs. Routes keyed by job or string IDs override it.
+func pathParamType(name string) string {
+	if name == "id" || strings.HasSuffix(name, "ID") {
+		return "integer"
+	}
+	return "string"
+}
+
+func firstKey(req SecurityRequirement) string {
+	for k := range req {
+		return k
+	}
+	return ""
+}
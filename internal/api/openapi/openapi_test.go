@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testTag struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name" binding:"required"`
+}
+
+type testBase struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testScene struct {
+	testBase
+	Title    string     `json:"title"`
+	Tags     []testTag  `json:"tags"`
+	Parent   *testScene `json:"parent,omitempty"`
+	Secret   string     `json:"-"`
+	Duration *int       `json:"duration"`
+}
+
+type testQuery struct {
+	Page  int    `form:"page"`
+	Query string `form:"q" binding:"required"`
+}
+
+func testRoutes() gin.RoutesInfo {
+	return gin.RoutesInfo{
+		{Method: http.MethodGet, Path: "/api/v1/scenes", Handler: "goonhub/internal/api/v1/handler.(*SceneHandler).ListScenes-fm"},
+		{Method: http.MethodGet, Path: "/api/v1/scenes/:id", Handler: "goonhub/internal/api/v1/handler.(*SceneHandler).GetScene-fm"},
+		{Method: http.MethodHead, Path: "/api/v1/scenes/:id", Handler: "goonhub/internal/api/v1/handler.(*SceneHandler).GetScene-fm"},
+		{Method: http.MethodPost, Path: "/api/v1/jobs/:id/retry", Handler: "goonhub/internal/api/v1/handler.(*JobHandler).RetryJob-fm"},
+		{Method: http.MethodPost, Path: "/api/v1/auth/login", Handler: "goonhub/internal/api/v1/handler.(*AuthHandler).Login-fm"},
+		{Method: http.MethodGet, Path: "/thumbnails/:id", Handler: "goonhub/internal/api.NewRouter.func1"},
+	}
+}
+
+func testDocument() *Document {
+	return Build(Config{
+		Info:            Info{Title: "Test", Version: "1"},
+		PathPrefix:      "/api",
+		ErrorResponse:   Object{"error": ""},
+		SecuritySchemes: map[string]SecurityScheme{"bearer": {Type: "http", Scheme: "bearer"}},
+	}, testRoutes(), Annotations{
+		"GET /api/v1/scenes":          {Query: testQuery{}, Response: Object{"data": []testScene{}, "total": int64(0)}},
+		"GET /api/v1/scenes/:id":      {Response: testScene{}},
+		"POST /api/v1/jobs/:id/retry": {Path: map[string]string{"id": "string"}, Status: http.StatusAccepted},
+		"POST /api/v1/auth/login":     {Public: true, Body: testTag{}},
+		"DELETE /api/v1/missing":      {Summary: "Not registered"},
+	})
+}
+
+func TestBuild_CoversRoutesUnderPrefix(t *testing.T) {
+	doc := testDocument()
+
+	if _, ok := doc.Paths["/thumbnails/{id}"]; ok {
+		t.Fatal("expected routes outside the prefix to be left out")
+	}
+	if _, ok := doc.Paths["/api/v1/missing"]; ok {
+		t.Fatal("expected annotations without a route to be ignored")
+	}
+	scene, ok := doc.Paths["/api/v1/scenes/{id}"]
+	if !ok {
+		t.Fatalf("expected the :id path to be converted, got %v", doc.Paths)
+	}
+	if _, ok := scene["head"]; ok {
+		t.Fatal("expected HEAD routes to be skipped")
+	}
+
+	op := scene["get"]
+	if op.OperationID != "getScene" || op.Summary != "Get scene" || op.Tags[0] != "scenes" {
+		t.Fatalf("unexpected operation defaults: %+v", op)
+	}
+	if len(op.Parameters) != 1 || op.Parameters[0].In != "path" || op.Parameters[0].Schema.Type != "integer" {
+		t.Fatalf("expected an integer id path parameter, got %+v", op.Parameters)
+	}
+	if op.Responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/testScene" {
+		t.Fatalf("expected the response to reference the scene schema, got %+v", op.Responses["200"])
+	}
+	if _, ok := op.Responses["default"]; !ok {
+		t.Fatal("expected a default error response")
+	}
+}
+
+func TestBuild_AppliesAnnotations(t *testing.T) {
+	doc := testDocument()
+
+	retry := doc.Paths["/api/v1/jobs/{id}/retry"]["post"]
+	if retry.Parameters[0].Schema.Type != "string" {
+		t.Fatalf("expected the path type override, got %+v", retry.Parameters[0].Schema)
+	}
+	if _, ok := retry.Responses["202"]; !ok {
+		t.Fatalf("expected the annotated status, got %v", retry.Responses)
+	}
+
+	login := doc.Paths["/api/v1/auth/login"]["post"]
+	if login.Security == nil || len(login.Security) != 0 {
+		t.Fatalf("expected public operations to clear security, got %v", login.Security)
+	}
+	if login.RequestBody == nil || login.RequestBody.Content["application/json"].Schema.Ref == "" {
+		t.Fatalf("expected a JSON request body, got %+v", login.RequestBody)
+	}
+
+	list := doc.Paths["/api/v1/scenes"]["get"]
+	if len(list.Parameters) != 2 || list.Parameters[1].Name != "q" || !list.Parameters[1].Required {
+		t.Fatalf("expected query parameters from form tags, got %+v", list.Parameters)
+	}
+	if list.Security != nil {
+		t.Fatal("expected protected operations to inherit the document security")
+	}
+	if len(doc.Security) != 1 {
+		t.Fatalf("expected document-wide security, got %v", doc.Security)
+	}
+}
+
+func TestSchemas_ReflectsStructs(t *testing.T) {
+	schemas := NewSchemas()
+	ref := schemas.For(testScene{})
+	if ref.Ref != "#/components/schemas/testScene" {
+		t.Fatalf("expected a component reference, got %+v", ref)
+	}
+
+	scene := schemas.Components["testScene"]
+	if _, ok := scene.Properties["Secret"]; ok {
+		t.Fatal("expected json:\"-\" fields to be skipped")
+	}
+	if scene.Properties["id"] == nil || scene.Properties["created_at"].Format != "date-time" {
+		t.Fatalf("expected embedded fields to be inlined, got %v", scene.Properties)
+	}
+	if scene.Properties["tags"].Items.Ref != "#/components/schemas/testTag" {
+		t.Fatalf("expected array items to reference the tag schema, got %+v", scene.Properties["tags"])
+	}
+	if scene.Properties["parent"].Ref != "#/components/schemas/testScene" {
+		t.Fatalf("expected the recursive field to reference its own schema, got %+v", scene.Properties["parent"])
+	}
+	if !scene.Properties["duration"].Nullable {
+		t.Fatal("expected pointer fields to be nullable")
+	}
+
+	tag := schemas.Components["testTag"]
+	if len(tag.Required) != 1 || tag.Required[0] != "name" {
+		t.Fatalf("expected binding:\"required\" fields to be required, got %v", tag.Required)
+	}
+}
+
+func TestComponentName_Generics(t *testing.T) {
+	schemas := NewSchemas()
+	schemas.For(page[testTag]{})
+	if _, ok := schemas.Components["pagetestTag"]; !ok {
+		t.Fatalf("expected generic arguments to be reduced to type names, got %v", schemas.Components)
+	}
+}
+
+type page[T any] struct {
+	Data []T `json:"data"`
+}
@@ -0,0 +1,267 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Schema is an OpenAPI 3.0 schema object.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Schemas turns Go values into schemas, collecting named struct types as
+// reusable components.
+type Schemas struct {
+	Components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func NewSchemas() *Schemas {
+	return &Schemas{
+		Components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	objectType        = reflect.TypeOf(Object(nil))
+)
+
+// For returns the schema of v's type, or of the described object for an Object.
+func (s *Schemas) For(v any) *Schema {
+	if obj, ok := v.(Object); ok {
+		return s.object(obj)
+	}
+	return s.forType(reflect.TypeOf(v))
+}
+
+func (s *Schemas) object(obj Object) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema, len(obj))}
+	for name, value := range obj {
+		if value == nil {
+			schema.Properties[name] = &Schema{}
+			continue
+		}
+		schema.Properties[name] = s.For(value)
+	}
+	return schema
+}
+
+func (s *Schemas) forType(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	case t == durationType:
+		return &Schema{Type: "integer", Format: "int64", Nullable: nullable}
+	case t == rawMessageType:
+		return &Schema{}
+	case t == objectType:
+		return &Schema{Type: "object"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean", Nullable: nullable}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Nullable: nullable}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Nullable: nullable}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float", Nullable: nullable}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double", Nullable: nullable}
+	case reflect.String:
+		return &Schema{Type: "string", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		return &Schema{Type: "array", Items: s.forType(t.Elem()), Nullable: nullable}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.forType(t.Elem()), Nullable: nullable}
+	case reflect.Struct:
+		// Types with their own JSON encoding (gorm.DeletedAt, sql.Null*) can't be reflected
+		if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+			return &Schema{Nullable: nullable}
+		}
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.component(t)}
+	default:
+		return &Schema{}
+	}
+}
+
+// component registers a named struct type and returns its component name.
+func (s *Schemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := componentName(t)
+	if _, taken := s.Components[name]; taken {
+		// Same type name in two packages
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	s.names[t] = name
+	// Register before filling properties so recursive types terminate
+	s.Components[name] = &Schema{}
+	*s.Components[name] = *s.structSchema(t)
+	return name
+}
+
+var genericArgs = regexp.MustCompile(`[\w./]*\.`)
+
+// componentName returns the type name, with generic type arguments reduced to
+// their own names: PaginatedResponse[goonhub/internal/data.Marker] becomes
+// PaginatedResponseMarker.
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	open := strings.Index(name, "[")
+	if open < 0 {
+		return name
+	}
+	args := genericArgs.ReplaceAllString(name[open+1:len(name)-1], "")
+	return name[:open] + strings.NewReplacer(",", "", "[", "", "]", "", "*", "").Replace(args)
+}
+
+func (s *Schemas) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// addFields adds t's JSON fields to schema, inlining embedded structs the way
+// encoding/json does.
+func (s *Schemas) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.addFields(schema, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := s.forType(field.Type)
+		if strings.Contains(opts, "string") && prop.Type != "" {
+			prop = &Schema{Type: "string", Nullable: prop.Nullable}
+		}
+		schema.Properties[name] = prop
+		if strings.Contains(field.Tag.Get("binding"), "required") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// QueryParameters returns the query parameters of a struct's `form` tagged
+// fields (embedded structs included), or of an Object's properties.
+func (s *Schemas) QueryParameters(v any) []Parameter {
+	if obj, ok := v.(Object); ok {
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		params := make([]Parameter, len(names))
+		for i, name := range names {
+			params[i] = Parameter{Name: name, In: "query", Schema: s.For(obj[name])}
+		}
+		return params
+	}
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var params []Parameter
+	s.addQueryFields(&params, t)
+	return params
+}
+
+func (s *Schemas) addQueryFields(params *[]Parameter, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			s.addQueryFields(params, field.Type)
+			continue
+		}
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		*params = append(*params, Parameter{
+			Name:     name,
+			In:       "query",
+			Required: strings.Contains(field.Tag.Get("binding"), "required"),
+			Schema:   s.forType(field.Type),
+		})
+	}
+}
+
+// content returns the media types of a request or response body.
+func (s *Schemas) content(v any) map[string]MediaType {
+	switch body := v.(type) {
+	case Binary:
+		contentType := body.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		return map[string]MediaType{contentType: {Schema: &Schema{Type: "string", Format: "binary"}}}
+	case Multipart:
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for name, value := range body {
+			if _, ok := value.(Binary); ok {
+				schema.Properties[name] = &Schema{Type: "string", Format: "binary"}
+				continue
+			}
+			schema.Properties[name] = s.For(value)
+		}
+		return map[string]MediaType{"multipart/form-data": {Schema: schema}}
+	default:
+		return map[string]MediaType{"application/json": {Schema: s.For(v)}}
+	}
+}
@@ -0,0 +1,680 @@
+package api
+
+import (
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/openapi"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"goonhub/internal/core/scraper"
+	"goonhub/internal/data"
+	"goonhub/internal/graphql"
+	"goonhub/internal/streaming"
+	"goonhub/internal/version"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Example values for the property types of gin.H responses
+var (
+	aString  = ""
+	anInt    = 0
+	anInt64  = int64(0)
+	aBool    = false
+	aTime    = time.Time{}
+	aMessage = openapi.Object{"message": aString}
+)
+
+// pageQuery is the page/limit query of endpoints that parse them by hand.
+type pageQuery struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type sortedPageQuery struct {
+	pageQuery
+	Sort string `form:"sort"`
+}
+
+// usageQuery is the query of the tag and actor usage endpoints.
+type usageQuery struct {
+	IDs    string `form:"ids"`
+	Months int    `form:"months"`
+	Limit  int    `form:"limit"`
+}
+
+type labelQuery struct {
+	Label string `form:"label" binding:"required"`
+}
+
+var bulkResult = openapi.Object{
+	"message":   aString,
+	"submitted": anInt,
+	"skipped":   anInt,
+	"errors":    anInt,
+}
+
+var jobIDResult = openapi.Object{"message": aString, "job_id": aString}
+
+var thumbnailResult = openapi.Object{
+	"thumbnail_path":   aString,
+	"thumbnail_width":  anInt,
+	"thumbnail_height": anInt,
+}
+
+// routeDocs annotates routes for the OpenAPI document. Every /api route is in
+// the document; routes missing here are listed with their path parameters only.
+var routeDocs = openapi.Annotations{
+	// Public
+	"GET /api/v1/events":                      {Summary: "Stream server events (auth via the token query parameter)", Public: true, Response: openapi.Binary{ContentType: "text/event-stream"}},
+	"POST /api/v1/auth/login":                 {Public: true, Body: request.LoginRequest{}},
+	"POST /api/v1/auth/login/2fa":             {Public: true, Body: request.LoginTwoFactorRequest{}},
+	"GET /api/v1/auth/registration":           {Public: true},
+	"GET /api/v1/auth/invites/:token":         {Public: true},
+	"POST /api/v1/auth/register":              {Public: true, Body: request.RegisterRequest{}, Status: 201},
+	"GET /api/v1/shares/:token":               {Public: true},
+	"GET /api/v1/shares/:token/stream":        {Public: true, Response: openapi.Binary{ContentType: "video/mp4"}},
+	"GET /api/v1/shares/:token/thumbnail":     {Public: true, Response: openapi.Binary{ContentType: "image/webp"}},
+	"POST /api/v1/shares/:token/unlock":       {Public: true, Body: request.UnlockShareLinkRequest{}, Response: core.ShareAccess{}},
+	"GET /api/v1/scenes/:id/stream":           {Summary: "Stream a scene's original file", Public: true, Response: openapi.Binary{ContentType: "video/mp4"}},
+	"GET /api/v1/scenes/:id/stream/transcode": {Summary: "Stream a scene transcoded on the fly", Public: true, Response: openapi.Binary{ContentType: "video/mp4"}},
+
+	// Scenes
+	"GET /api/v1/scenes": {
+		Query: request.SearchScenesRequest{},
+		Response: openapi.Object{
+			"data":        []response.SceneListItem{},
+			"total":       anInt64,
+			"page":        anInt,
+			"limit":       anInt,
+			"next_cursor": aString,
+			"seed":        anInt64,
+			"ratings":     map[uint]float64{},
+			"likes":       map[uint]bool{},
+			"jizz_counts": map[uint]int{},
+		},
+	},
+	"POST /api/v1/scenes": {
+		Summary:  "Upload a scene",
+		Body:     openapi.Multipart{"scene": openapi.Binary{}, "title": aString},
+		Response: data.Scene{},
+		Status:   201,
+	},
+	"POST /api/v1/scenes/batch": {
+		Summary:     "Upload several scenes",
+		Description: "Each file is uploaded on its own; the results report the scene or the error of each file in order.",
+		Body:        openapi.Multipart{"scenes": openapi.Binary{}},
+		Response: openapi.Object{
+			"uploaded": anInt,
+			"failed":   anInt,
+			"results":  []core.UploadResult{},
+		},
+	},
+	"POST /api/v1/scenes/uploads": {
+		Summary:     "Start a chunked upload",
+		Description: "Chunks are then sent with PATCH at the offset the server reports, so an interrupted upload can resume.",
+		Body:        request.CreateUploadRequest{},
+		Response:    data.UploadSession{},
+		Status:      201,
+	},
+	"GET /api/v1/scenes/uploads/:uploadId": {
+		Summary:     "Get a chunked upload",
+		Description: "Returns the offset to resume from, also sent in the Upload-Offset header.",
+		Response:    data.UploadSession{},
+	},
+	"PATCH /api/v1/scenes/uploads/:uploadId": {
+		Summary:     "Append a chunk to an upload",
+		Description: "The Upload-Offset header must match the bytes received so far, otherwise a 409 returns the current offset. The last chunk creates the scene and returns 201.",
+		Body:        openapi.Binary{},
+		Response:    openapi.Object{"upload": data.UploadSession{}, "scene": data.Scene{}},
+	},
+	"DELETE /api/v1/scenes/uploads/:uploadId": {
+		Summary: "Cancel a chunked upload",
+		Status:  204,
+	},
+	"GET /api/v1/scenes/filters": {
+		Response: openapi.Object{
+			"studios":       []string{},
+			"actors":        []string{},
+			"tags":          []data.TagWithCount{},
+			"marker_labels": []openapi.Object{{"label": aString, "count": anInt64}},
+		},
+	},
+	"GET /api/v1/scenes/segments": {
+		Summary:  "Search scenes by marker segments",
+		Query:    openapi.Object{"q": aString, "limit": anInt},
+		Response: openapi.Object{"results": []response.SceneSegmentResult{}},
+	},
+	"GET /api/v1/scenes/random": {
+		Query:    request.SearchScenesRequest{},
+		Response: openapi.Object{"scene": response.SceneListItem{}, "seed": anInt64},
+	},
+	"GET /api/v1/scenes/shuffle": {
+		Query: request.ShuffleQueueRequest{},
+		Response: openapi.Object{
+			"data":        []response.SceneListItem{},
+			"total":       anInt64,
+			"seed":        anInt64,
+			"next_offset": anInt,
+			"done":        aBool,
+		},
+	},
+	"GET /api/v1/scenes/:id": {Response: data.Scene{}},
+	"PATCH /api/v1/scenes/bulk": {
+		Summary:     "Update metadata of many scenes",
+		Body:        request.BulkUpdateMetadataRequest{},
+		Response:    core.BulkUpdateMetadataResponse{},
+		Description: "title_template can use {title}, {filename}, {date}, {id} and {index}. The update is all or nothing: when any scene fails, nothing changes and the per-scene results come back with status 422.",
+	},
+	"PUT /api/v1/scenes/:id/details":              {Body: request.UpdateSceneDetailsRequest{}, Response: data.Scene{}},
+	"DELETE /api/v1/scenes/:id":                   {Summary: "Move a scene to the trash, or delete it permanently", Body: request.DeleteSceneRequest{}, Response: openapi.Object{"message": aString, "expires_at": aTime}},
+	"POST /api/v1/scenes/:id/playback":            {Summary: "Negotiate direct play or transcoding", Body: request.PlaybackRequest{}, Response: streaming.PlaybackDecision{}},
+	"GET /api/v1/scenes/:id/cast":                 {Summary: "Get signed URLs for casting", Response: core.CastInfo{}},
+	"GET /api/v1/scenes/:id/frame":                {Summary: "Extract a frame", Query: openapi.Object{"t": 0.0, "format": aString}, Response: openapi.Binary{ContentType: "image/jpeg"}},
+	"GET /api/v1/scenes/:id/integrity":            {Response: data.SceneIntegrityReport{}},
+	"GET /api/v1/scenes/:id/reprocess":            {Response: aMessage, Status: 202},
+	"PUT /api/v1/scenes/:id/thumbnail":            {Body: request.ExtractThumbnailRequest{}, Response: thumbnailResult},
+	"GET /api/v1/scenes/:id/similar":              {Query: openapi.Object{"limit": anInt}, Response: openapi.Object{"results": []response.SimilarSceneResult{}}},
+	"GET /api/v1/scenes/:id/related":              {Query: openapi.Object{"limit": anInt}, Response: openapi.Object{"data": []response.SceneListItem{}, "total": anInt}},
+	"PUT /api/v1/admin/scenes/:id/scene-metadata": {Body: request.ApplySceneMetadataRequest{}, Response: data.Scene{}},
+	"POST /api/v1/scenes/:id/thumbnail/upload": {
+		Body:     openapi.Multipart{"thumbnail": openapi.Binary{}},
+		Response: thumbnailResult,
+	},
+	"GET /api/v1/scenes/:id/media": {
+		Summary: "Get the scene's media URLs, signed when media signing is enabled",
+		Response: openapi.Object{
+			"thumbnail_url":    aString,
+			"thumbnail_lg_url": aString,
+			"vtt_url":          aString,
+			"trickplay_url":    aString,
+			"preview_url":      aString,
+			"expires_at":       &aTime,
+		},
+	},
+	"POST /api/v1/admin/explorer/move": {
+		Summary:     "Move scene files to a folder of a storage path",
+		Description: "Moves the files on disk, with their .nfo, .json and .xml sidecars, into folder_path (created if missing) and updates the scenes' stored paths; copies and removes across filesystems. Existing files are never overwritten. Generated thumbnails, sprites and previews are kept. Each moved scene emits a scene:file_moved event; scenes that fail are listed and the rest still move.",
+		Body:        request.MoveScenesRequest{},
+		Response:    core.MoveScenesResult{},
+	},
+	"POST /api/v1/admin/explorer/migrate": {
+		Summary:     "Move scenes to another storage path",
+		Description: "Starts a background job moving the scenes (scene_ids, or every scene under folder_path of source_storage_path_id, subfolders included) to the storage path, keeping their folders relative to the storage path root. Across filesystems each file is copied, checked against the source's checksum and only then is the source removed; sidecars follow. Scenes already in the storage path are left alone. 400 when the storage path lacks the free space, 409 while another migration runs or the storage path is offline. Progress is reported on the job (phase storage_migration) and as storage_migration:progress events, the outcome as storage_migration:completed, storage_migration:cancelled or storage_migration:failed; cancel it like any job.",
+		Body:        request.MigrateScenesRequest{},
+		Response:    core.StorageMigrationJob{},
+		Status:      202,
+	},
+	"POST /api/v1/admin/explorer/scenes/:id/rename": {
+		Summary:     "Rename a scene's file on disk",
+		Description: "Renames the file and its sidecars in the same folder. A name without an extension keeps the current one. 409 when a file with the name already exists.",
+		Body:        request.RenameSceneFileRequest{},
+		Response:    data.Scene{},
+	},
+
+	// Storage paths
+	"GET /api/v1/admin/storage-paths/:id/health": {
+		Summary:     "Get a storage path's health history",
+		Description: "Returns the latest check (null until the path was checked) and the checks of the last days (default 7, at most 90), oldest first. A path is offline when it is missing, unreadable, or empty while it has scenes, which usually means it is not mounted; free_bytes and total_bytes chart free space over time.",
+		Query:       openapi.Object{"days": anInt},
+		Response:    openapi.Object{"status": &data.StoragePathHealthSample{}, "samples": []data.StoragePathHealthSample{}},
+	},
+	"POST /api/v1/admin/storage-paths/health/check": {
+		Summary:     "Check storage path health now",
+		Description: "Checks every storage path instead of waiting for the next periodic check and records the results. Paths going offline or back online emit storage:offline and storage:online events.",
+		Response:    openapi.Object{"storage_paths": []data.StoragePathHealthSample{}},
+	},
+
+	// Scan
+	"POST /api/v1/admin/scan/dropbox": {
+		Summary:     "Import the dropbox now",
+		Description: "Runs a dropbox import pass without waiting for the next poll: video files in scan.dropbox.path that have not been modified for settle_time are moved, with their sidecars, into the configured storage path where the template places them, then imported like scanned files. Files whose destination exists are renamed or left in place per on_collision and are listed as failed; they are retried once they change. 400 when the dropbox is disabled, 409 while a scan or another pass runs or the storage path is offline.",
+		Response:    core.DropboxImportResult{},
+	},
+
+	// Tags
+	"GET /api/v1/tags/usage": {
+		Summary:     "Get tag usage per month",
+		Description: "For each tag in ids (comma-separated, at most 50), or else the limit (default 10, at most 50) tags with the most scenes added and caller watches over the window, returns a point per calendar month (UTC) of the last months (default 12, at most 60, including the current one) with the live scenes added to the library that have the tag and the caller's watch sessions of scenes with it.",
+		Query:       usageQuery{},
+		Response:    openapi.Object{"data": []data.UsageSeries{}},
+	},
+	"PUT /api/v1/admin/tags/:id": {
+		Summary:     "Rename, recolor or set the aliases of a tag",
+		Description: "A renamed tag keeps its old name as an alias, so sidecars, scrapes, searches and tag filters using it still find the tag. Names and aliases already used by another tag are rejected. The tag's scenes are re-indexed when its names change.",
+		Body:        request.UpdateTagRequest{},
+		Response:    data.Tag{},
+	},
+	"POST /api/v1/admin/tags/:id/merge": {
+		Summary:     "Merge duplicate tags into a tag",
+		Description: "Moves the scene, marker, marker label default and playlist associations of the source tags to the tag, replaces them in folder rules and role restrictions, and deletes them, in one transaction. Their names and aliases become the tag's aliases. The affected scenes are re-indexed.",
+		Body:        request.MergeTagsRequest{},
+		Response:    data.Tag{},
+	},
+	"DELETE /api/v1/admin/tags/unused": {
+		Summary:     "Delete unused tags",
+		Description: "Deletes the tags no live scene, marker, marker label default or playlist has and no folder rule or role restriction lists.",
+		Response:    openapi.Object{"data": []data.Tag{}, "deleted": 0},
+	},
+
+	// Actors
+	"GET /api/v1/actors/usage": {
+		Summary:     "Get actor usage per month",
+		Description: "Same as the tag usage, for actors: scenes added and the caller's watch sessions per month for the actors in ids, or the most used ones.",
+		Query:       usageQuery{},
+		Response:    openapi.Object{"data": []data.UsageSeries{}},
+	},
+	"GET /api/v1/actors/:uuid/stats": {
+		Summary:     "Get aggregate statistics of an actor's scenes",
+		Description: "Scene count, total duration in seconds, the caller's average rating of the scenes they rated, the first and last release dates, and the five most common tags and studios.",
+		Response:    data.ActorStats{},
+	},
+	"POST /api/v1/admin/actors/:id/merge": {
+		Summary:     "Merge duplicate actors into an actor",
+		Description: "Moves the scenes, likes, ratings, folder rules and PornDB syncs of the source actors to the actor and deletes them, in one transaction. Their names and aliases become the actor's aliases, so searches, actor filters and performer names from sidecars, scrapes and matches still find it; fields the actor lacks, its image included, are taken from the sources.",
+		Body:        request.MergeActorsRequest{},
+		Response:    data.Actor{},
+	},
+	"POST /api/v1/admin/actors/:id/image/generate": {
+		Summary:     "Generate an actor's image from a scene frame",
+		Description: "Replaces the actor's image with a portrait crop of a frame. Without a body the frame comes from one of the actor's latest scenes, preferring scenes they are alone in and then scenes with markers, at the middle marker or 40% in. scene_id picks one of the actor's scenes and timestamp (seconds) the frame.",
+		Body:        request.GenerateActorImageRequest{},
+		Response:    data.Actor{},
+	},
+	"POST /api/v1/admin/actor-images/generate": {
+		Summary:     "Generate images for actors without one",
+		Description: "Starts a background job giving every actor without an image and with a playable scene an image picked as for a single actor. Progress is tracked in the job history under the actor_image phase; 409 while a run is going.",
+		Response:    core.ActorImageJob{},
+	},
+
+	// Studios
+	"GET /api/v1/studios/:uuid/scenes": {
+		Summary:     "List a studio's scenes",
+		Description: "With include_children=true, also lists the scenes of every studio whose parent or network is the studio, directly or further down, so a network lists its whole catalog.",
+		Query: struct {
+			pageQuery
+			IncludeChildren bool `form:"include_children"`
+		}{},
+		Response: openapi.Object{"data": []data.Scene{}, "total": 0, "page": 0, "limit": 0},
+	},
+	"GET /api/v1/studios/:uuid/children": {
+		Summary:  "List the studios directly under a studio",
+		Response: openapi.Object{"data": []data.StudioWithCount{}},
+	},
+
+	// Markers
+	"GET /api/v1/scenes/:id/markers":                        {Response: openapi.Object{"markers": []data.MarkerWithTags{}}},
+	"POST /api/v1/scenes/:id/markers":                       {Body: request.CreateMarkerRequest{}, Response: data.UserSceneMarker{}, Status: 201},
+	"PUT /api/v1/scenes/:id/markers/:markerID":              {Body: request.UpdateMarkerRequest{}, Response: data.UserSceneMarker{}},
+	"DELETE /api/v1/scenes/:id/markers/:markerID":           {Status: 204},
+	"GET /api/v1/scenes/:id/markers/:markerID/clip":         {Response: openapi.Binary{ContentType: "video/mp4"}},
+	"POST /api/v1/scenes/:id/markers/:markerID/copy":        {Summary: "Copy a shared marker", Response: data.UserSceneMarker{}, Status: 201},
+	"GET /api/v1/scenes/:id/markers/shared":                 {Response: openapi.Object{"markers": []data.SharedMarker{}}},
+	"POST /api/v1/scenes/:id/markers/import":                {Body: request.ImportMarkersRequest{}, Response: core.MarkerImportResult{}},
+	"GET /api/v1/scenes/:id/markers/export":                 {Summary: "Export markers as chapters", Query: openapi.Object{"format": aString}, Response: openapi.Binary{}},
+	"POST /api/v1/scenes/:id/markers/thumbnails/regenerate": {Response: core.BulkPhaseResult{}},
+	"GET /api/v1/markers":                                   {Summary: "List marker label groups", Query: sortedPageQuery{}, Response: response.PaginatedResponse[data.MarkerLabelGroup]{}},
+	"GET /api/v1/markers/all": {
+		Query: struct {
+			sortedPageQuery
+			Cursor string `form:"cursor"`
+		}{},
+		Response:    response.PaginatedResponse[data.MarkerWithScene]{},
+		Description: "With a cursor query, even an empty one, pages by cursor and returns a CursorResponse instead.",
+	},
+	"GET /api/v1/markers/by-label": {Query: struct {
+		labelQuery
+		pageQuery
+	}{}, Response: response.PaginatedResponse[data.MarkerWithScene]{}},
+	"GET /api/v1/markers/labels":                       {Response: openapi.Object{"labels": []data.MarkerLabelSuggestion{}}},
+	"GET /api/v1/markers/label-tags":                   {Query: labelQuery{}, Response: openapi.Object{"tags": []data.Tag{}}},
+	"PUT /api/v1/markers/label-tags":                   {Query: labelQuery{}, Body: request.SetLabelTagsRequest{}, Response: openapi.Object{"tags": []data.Tag{}}},
+	"GET /api/v1/markers/label-settings":               {Response: openapi.Object{"settings": []data.MarkerLabelSetting{}}},
+	"PUT /api/v1/markers/label-settings":               {Body: request.SetLabelSettingRequest{}, Response: data.MarkerLabelSetting{}},
+	"DELETE /api/v1/markers/label-settings":            {Query: labelQuery{}, Status: 204},
+	"GET /api/v1/markers/collections":                  {Query: sortedPageQuery{}, Response: response.PaginatedResponse[data.MarkerTagCollection]{}},
+	"GET /api/v1/markers/collections/:tagID":           {Query: sortedPageQuery{}, Response: response.PaginatedResponse[data.CollectionMarker]{}},
+	"GET /api/v1/markers/collections/:tagID/queue":     {Query: openapi.Object{"seed": anInt64}, Response: core.CollectionQueue{}},
+	"GET /api/v1/markers/:markerID/tags":               {Response: openapi.Object{"tags": []data.MarkerTagInfo{}}},
+	"PUT /api/v1/markers/:markerID/tags":               {Body: request.SetMarkerTagsRequest{}, Response: openapi.Object{"tags": []data.MarkerTagInfo{}}},
+	"POST /api/v1/markers/:markerID/tags":              {Body: request.SetMarkerTagsRequest{}, Response: openapi.Object{"tags": []data.MarkerTagInfo{}}},
+	"POST /api/v1/markers/thumbnails/regenerate":       {Response: core.BulkPhaseResult{}},
+	"POST /api/v1/admin/markers/thumbnails/regenerate": {Response: core.BulkPhaseResult{}},
+	"POST /api/v1/markers/compilations":                {Body: request.CreateMarkerCompilationRequest{}, Response: data.MarkerCompilation{}, Status: 201},
+	"GET /api/v1/markers/compilations":                 {Response: openapi.Object{"compilations": []data.MarkerCompilation{}}},
+	"GET /api/v1/markers/compilations/:jobID":          {Path: map[string]string{"jobID": "string"}, Response: data.MarkerCompilation{}},
+	"GET /api/v1/markers/compilations/:jobID/download": {Path: map[string]string{"jobID": "string"}, Response: openapi.Binary{ContentType: "video/mp4"}},
+	"DELETE /api/v1/markers/compilations/:jobID":       {Path: map[string]string{"jobID": "string"}, Status: 204},
+
+	// Jobs
+	"GET /api/v1/admin/jobs": {
+		Query: struct {
+			pageQuery
+			Status string `form:"status"`
+			Cursor string `form:"cursor"`
+		}{},
+		Response: openapi.Object{
+			"data":         []data.JobHistory{},
+			"next_cursor":  aString,
+			"total":        anInt64,
+			"page":         anInt,
+			"limit":        anInt,
+			"active_count": anInt,
+			"active_jobs":  []data.JobHistory{},
+			"retention":    aString,
+			"pool_config":  core.PoolConfig{},
+			"queue_status": map[string]int{},
+		},
+	},
+	"POST /api/v1/admin/scenes/:id/process/:phase": {Query: openapi.Object{"force_target": aString}, Response: aMessage},
+	"POST /api/v1/admin/jobs/bulk": {
+		Summary:     "Trigger a phase for many scenes",
+		Description: "With dry_run the response previews the scenes that would be queued instead.",
+		Body: struct {
+			Phase       string `json:"phase" binding:"required"`
+			Mode        string `json:"mode"`
+			ForceTarget string `json:"force_target"`
+			SceneIDs    []uint `json:"scene_ids"`
+			DryRun      bool   `json:"dry_run"`
+		}{},
+		Response: bulkResult,
+	},
+	"POST /api/v1/admin/jobs/verify-all":       {Body: openapi.Object{"mode": aString}, Response: bulkResult},
+	"POST /api/v1/admin/jobs/verify-checksums": {Body: openapi.Object{"mode": aString}, Response: bulkResult},
+	"POST /api/v1/admin/jobs/retry-all-failed": {Response: openapi.Object{"message": aString, "retried": anInt}},
+	"POST /api/v1/admin/jobs/retry-batch":      {Body: openapi.Object{"job_ids": []string{}}, Response: openapi.Object{"message": aString, "retried": anInt, "errors": anInt}},
+	"DELETE /api/v1/admin/jobs/failed":         {Response: openapi.Object{"message": aString, "deleted": anInt64}},
+	"POST /api/v1/admin/jobs/:id/cancel":       {Path: map[string]string{"id": "string"}, Response: jobIDResult},
+	"POST /api/v1/admin/jobs/:id/retry":        {Path: map[string]string{"id": "string"}, Response: jobIDResult},
+	"GET /api/v1/admin/jobs/recent-failed":     {Query: openapi.Object{"limit": anInt}, Response: openapi.Object{"data": []data.JobHistory{}}},
+	"GET /api/v1/admin/jobs/:id/logs": {
+		Summary:     "Job run log",
+		Description: "Log of the job's last run: worker pool lines, then every ffmpeg and ffprobe command with its output and exit status. Kept under 64 KiB by dropping the oldest lines (truncated is then true). 404 for jobs that ran before logs were captured or outside the worker pools.",
+		Path:        map[string]string{"id": "string"},
+		Response:    data.JobLog{},
+	},
+	"POST /api/v1/admin/jobs/cancel": {
+		Summary:     "Cancel jobs in bulk",
+		Description: "Cancels the processing jobs matching every given criterion: phase, scene_ids, older_than (created longer ago, like 7d or 12h). Pending jobs are cancelled in one update and jobs waiting in a worker pool are stopped before they start; include_running also stops the executing ones. all: true is required when no criterion is set. The feeder claims no jobs meanwhile, so none slips from pending into a pool unseen.",
+		Body:        request.CancelJobsRequest{},
+		Response:    core.JobCancelResult{},
+	},
+	"GET /api/v1/admin/jobs/analytics": {
+		Summary:     "Job performance per phase",
+		Description: "Aggregates the jobs finished within window (a duration like 7d or 12h, default 7d) per phase: outcome counts, failure_rate (failed and timed out over finished, 0-1), jobs_per_hour (completed), and the average, p50, p95, p99 and max run time in seconds of completed jobs (null without any).",
+		Query:       openapi.Object{"window": aString},
+		Response:    core.JobAnalytics{},
+	},
+	"GET /api/v1/admin/jobs/analytics/timeline": {
+		Summary:     "Job outcomes over time",
+		Description: "Counts completed, failed (including timed out) and cancelled jobs per phase and bucket over window (default 7d). bucket is hour or day; by default hour for windows up to 2 days, day otherwise. Hourly buckets cover at most 14 days.",
+		Query:       openapi.Object{"window": aString, "bucket": aString},
+		Response:    openapi.Object{"data": []data.JobTimelineBucket{}},
+	},
+	"GET /api/v1/admin/jobs/analytics/slowest": {
+		Summary:     "Slowest scene jobs",
+		Description: "Lists the completed scene jobs that ran longest within window (default 7d), optionally of one phase; limit defaults to 10, at most 100.",
+		Query:       openapi.Object{"window": aString, "phase": aString, "limit": anInt},
+		Response:    openapi.Object{"data": []data.JobDuration{}},
+	},
+	"GET /api/v1/admin/jobs/retention": {
+		Summary:     "Job history retention",
+		Description: "Returns the retention policy (processing.job_history_retention and job_history_archive) and the job_history rows it reclaimed on the last run and since startup. Finished jobs past the retention are pruned hourly; failed and timed out ones are kept as failure summaries.",
+		Response:    core.JobHistoryRetentionStats{},
+	},
+	"POST /api/v1/admin/jobs/retention/run": {
+		Summary:     "Prune job history now",
+		Description: "Prunes the finished jobs past the retention window right away, archiving them when job_history_archive is on, and returns the rows reclaimed.",
+		Response:    data.JobPruneResult{},
+	},
+	"GET /api/v1/admin/jobs/failure-summaries": {
+		Summary:     "Failure summaries of pruned jobs",
+		Description: "Lists the failed and timed out jobs removed by retention, counted per phase and error, most recent first; limit defaults to 50, at most 200.",
+		Query:       openapi.Object{"phase": aString, "limit": anInt},
+		Response:    openapi.Object{"data": []data.JobFailureSummary{}},
+	},
+	"GET /api/v1/admin/pool-config": {
+		Summary:     "Worker pool sizes",
+		Description: "Returns the current size of every worker pool and, under autoscale, the autoscaler's bounds, the load average per CPU at its last evaluation and its recent resizes, newest first. Autoscaled sizes are not persisted; the sizes set with PUT are the ones used on startup.",
+		Response:    response.PoolConfig{},
+	},
+	"POST /api/v1/admin/import/urls": {
+		Summary:     "Import a URL",
+		Description: "Queues a download of a direct video file URL, or of a page of a site supported by yt-dlp when configured, into a storage path. The file is then imported like scanned files. Progress is reported as import:progress events and in job history.",
+		Body:        request.RemoteImportRequest{},
+		Response:    data.RemoteImport{},
+		Status:      202,
+	},
+	"GET /api/v1/admin/import/urls": {
+		Summary:  "List URL imports",
+		Query:    pageQuery{},
+		Response: openapi.Object{"data": []data.RemoteImport{}, "total": anInt64, "page": anInt, "limit": anInt},
+	},
+	"GET /api/v1/admin/import/urls/:jobID": {
+		Summary:  "Get a URL import",
+		Path:     map[string]string{"jobID": "string"},
+		Response: data.RemoteImport{},
+	},
+	"POST /api/v1/admin/import/urls/:jobID/cancel": {
+		Summary:  "Cancel a URL import",
+		Path:     map[string]string{"jobID": "string"},
+		Response: jobIDResult,
+	},
+	"GET /api/v1/admin/import/torrents": {
+		Summary:     "List torrent imports",
+		Description: "Files imported from the completed torrents of the torrent client, each linked to its torrent and to the scene created from it. scene_id lists the files of one scene.",
+		Query:       openapi.Object{"page": anInt, "limit": anInt, "scene_id": anInt},
+		Response:    openapi.Object{"data": []data.TorrentImport{}, "total": anInt64, "page": anInt, "limit": anInt},
+	},
+	"POST /api/v1/admin/import/torrents/run": {
+		Summary:     "Import completed torrents now",
+		Description: "Imports the video files of the completed torrents of the configured category that were not handled yet, without waiting for the next poll.",
+		Response:    core.TorrentImportResult{},
+	},
+	"POST /api/v1/admin/import/torrents/:id/retry": {
+		Summary:  "Retry a failed torrent import",
+		Response: aMessage,
+	},
+	"GET /api/v1/admin/retry-config": {Response: []data.RetryConfigRecord{}},
+	"PUT /api/v1/admin/retry-config": {
+		Summary:     "Update the retry policy of a phase",
+		Description: "Sets the phase's max retries and backoff: exponential (initial_delay_seconds * backoff_factor^retry), linear (initial_delay_seconds * (retry + 1)) or fixed, capped at max_delay_seconds, plus a random extra delay of up to jitter_percent. Failed jobs' errors are classified as not_found, timeout, permission, corrupt or resources: classes in never_retry_errors go straight to the DLQ, classes in always_retry_errors are retried, other errors are retried when retry_unclassified is set. The policy fields are optional and keep their value when omitted. Returns every phase's policy.",
+		Body:        data.RetryConfigRecord{},
+		Response:    []data.RetryConfigRecord{},
+	},
+	"GET /api/v1/admin/dlq": {
+		Query: struct {
+			pageQuery
+			Status string `form:"status"`
+		}{},
+		Response: openapi.Object{"data": []data.DLQEntry{}, "total": anInt64, "page": anInt, "limit": anInt, "stats": map[string]int64{}},
+	},
+	"POST /api/v1/admin/dlq/:job_id/retry":   {Response: jobIDResult},
+	"POST /api/v1/admin/dlq/:job_id/abandon": {Response: jobIDResult},
+	"POST /api/v1/admin/dlq/requeue": {
+		Summary:     "Requeue the DLQ entries matching a filter",
+		Description: "Resubmits the entries matching every given criterion (all entries when none is given), oldest first, up to 1000 per request; entries already retrying are skipped. older_than takes a duration like 7d or 12h. Entries that fail to resubmit are listed with their error.",
+		Body:        request.DLQBulkRequest{},
+		Response:    core.DLQBulkResult{},
+	},
+	"POST /api/v1/admin/dlq/purge": {
+		Summary:     "Delete the DLQ entries matching a filter",
+		Description: "Deletes the entries matching every given criterion. At least one criterion is required. Abandoned entries are also deleted automatically after processing.dlq_retention.",
+		Body:        request.DLQBulkRequest{},
+		Response:    openapi.Object{"message": aString, "purged": anInt64},
+	},
+
+	// Webhooks
+	"GET /api/v1/admin/webhooks": {Response: openapi.Object{"data": []data.Webhook{}, "event_types": []string{}}},
+	"POST /api/v1/admin/webhooks": {
+		Description: "The signing secret is only returned here and by rotate-secret.",
+		Body:        request.CreateWebhookRequest{},
+		Response:    core.CreatedWebhook{},
+		Status:      201,
+	},
+	"GET /api/v1/admin/webhooks/:id":                {Response: data.Webhook{}},
+	"PUT /api/v1/admin/webhooks/:id":                {Body: request.UpdateWebhookRequest{}, Response: data.Webhook{}},
+	"POST /api/v1/admin/webhooks/:id/rotate-secret": {Response: core.CreatedWebhook{}},
+	"POST /api/v1/admin/webhooks/:id/ping": {
+		Description: "Sends a ping event right away, even to a disabled webhook, and returns the recorded delivery.",
+		Response:    data.WebhookDelivery{},
+	},
+	"GET /api/v1/admin/webhooks/:id/deliveries": {
+		Query: struct {
+			pageQuery
+			Status string `form:"status"`
+		}{},
+		Response: openapi.Object{"data": []data.WebhookDelivery{}, "total": anInt64, "page": anInt, "limit": anInt},
+	},
+	"GET /api/v1/admin/webhooks/:id/deliveries/:deliveryID":            {Response: data.WebhookDelivery{}},
+	"POST /api/v1/admin/webhooks/:id/deliveries/:deliveryID/redeliver": {Response: data.WebhookDelivery{}, Status: 202},
+
+	// PornDB
+	"GET /api/v1/admin/porndb/status": {
+		Description: "rate_limit reports the client's request, 429 and retry counters since startup and the quota PornDB reported on its latest response. providers lists the metadata providers the search and match endpoints accept.",
+		Response:    openapi.Object{"configured": aBool, "rate_limit": core.PornDBRateLimitStats{}, "providers": []core.MetadataProviderStatus{}},
+	},
+	"GET /api/v1/admin/porndb/scenes": {
+		Summary:     "Search PornDB or StashDB scenes",
+		Description: "Searches by title, or by a file fingerprint (md5, oshash or phash; default oshash) when one is given. provider picks porndb (default) or stashdb; StashDB results use the same shape.",
+		Query:       openapi.Object{"title": aString, "fingerprint": aString, "algorithm": aString, "provider": aString},
+		Response:    openapi.Object{"data": []core.PornDBScene{}},
+	},
+	"GET /api/v1/admin/porndb/scenes/:id": {
+		Query:    openapi.Object{"provider": aString},
+		Response: openapi.Object{"data": core.PornDBScene{}},
+	},
+	"GET /api/v1/admin/porndb/performers": {
+		Query:    openapi.Object{"q": aString, "provider": aString},
+		Response: openapi.Object{"data": []core.PornDBPerformer{}},
+	},
+	"GET /api/v1/admin/porndb/cache": {Summary: "PornDB response cache stats", Response: core.PornDBCacheStats{}},
+	"DELETE /api/v1/admin/porndb/cache": {
+		Summary:     "Purge the PornDB response cache",
+		Description: "Deletes cached lookups of one kind (performer, scene or site), or of every kind, so they are fetched from PornDB again. With expired_only, fresh entries are kept.",
+		Query:       openapi.Object{"kind": aString, "expired_only": aBool},
+		Response:    openapi.Object{"deleted": anInt64},
+	},
+	"POST /api/v1/admin/porndb/auto-match": {
+		Summary:     "Auto-match unmatched scenes against PornDB or StashDB",
+		Description: "Searches the chosen provider (default porndb) for every scene without a PornDB or StashDB scene ID in the background. A best candidate scoring at least threshold (default 0.8) and 0.1 ahead of the runner-up is applied; the others are queued for review. Progress shows on the jobs page.",
+		Body:        request.StartPornDBAutoMatchRequest{},
+		Response:    core.PornDBAutoMatchJob{},
+		Status:      202,
+	},
+	"GET /api/v1/admin/porndb/match-reviews": {
+		Query: struct {
+			pageQuery
+			Status string `form:"status"`
+		}{},
+		Response: openapi.Object{"data": []data.PornDBMatchReview{}, "total": anInt64, "page": anInt, "limit": anInt, "running": aBool},
+	},
+	"POST /api/v1/admin/porndb/match-reviews/:sceneId/accept": {
+		Description: "Applies a scene from the review's provider, or the one given as provider, to the scene. It is usually one of the review's candidates. A StashDB match is recorded as the scene's stashdb_scene_id. fields selects what is applied, as for apply-match.",
+		Body:        request.AcceptPornDBMatchRequest{},
+		Response:    data.PornDBMatchReview{},
+	},
+	"POST /api/v1/admin/porndb/match-reviews/:sceneId/dismiss": {Response: data.PornDBMatchReview{}},
+	"POST /api/v1/admin/scenes/:id/apply-match": {
+		Summary:     "Apply a PornDB or StashDB scene to a scene",
+		Description: "Fetches the provider scene (provider defaults to porndb) and applies the selected fields: title, date, description, studio, performers, tags, markers, cover; the first four when none are given. Missing actors and tags are created, markers are added as the caller's and the cover is downloaded. Everything is written in one transaction: if any part fails, nothing changes. A pending match review of the scene is marked applied.",
+		Body:        request.ApplyPornDBMatchRequest{},
+		Response:    core.SceneMetadataResult{},
+	},
+	"GET /api/v1/admin/porndb/performer-sync": {
+		Summary:  "PornDB performer sync status",
+		Response: core.ActorSyncStatus{},
+	},
+	"POST /api/v1/admin/porndb/performer-sync": {
+		Summary:     "Sync linked actors from PornDB",
+		Description: "Refreshes, in the background, every actor with a porndb_id and its sync enabled that was not synced within porndb.performer_sync_interval. The same run happens on that interval. A field is overwritten only when empty or unchanged since the last sync; locally edited values are kept and the difference is recorded.",
+		Response:    core.ActorSyncStatus{},
+		Status:      202,
+	},
+	"PUT /api/v1/admin/actors/:id/porndb-sync": {
+		Summary:     "Link an actor to a PornDB performer",
+		Description: "Sets the actor's porndb_id and whether scheduled syncs refresh it. Omitted settings are left alone; linking another performer forgets what the last one synced.",
+		Body:        request.UpdateActorPornDBSyncRequest{},
+		Response:    data.Actor{},
+	},
+	"POST /api/v1/admin/actors/:id/porndb-sync/run": {
+		Summary:     "Sync an actor from PornDB now",
+		Description: "Refreshes the actor from its PornDB performer, bypassing the PornDB cache, whether or not its scheduled sync is enabled.",
+		Response:    data.ActorPornDBSync{},
+	},
+	"GET /api/v1/admin/actors/:id/porndb-syncs": {
+		Summary:  "List an actor's PornDB syncs",
+		Query:    pageQuery{},
+		Response: openapi.Object{"data": []data.ActorPornDBSync{}, "total": anInt64, "page": anInt, "limit": anInt},
+	},
+
+	// Scrapers
+	"POST /api/v1/scenes/:id/scrape": {
+		Summary:     "Populate a scene from a scene page",
+		Description: "Scrapes the page with the site scraper matching its domain, falling back to the page's JSON-LD and OpenGraph metadata, and writes the selected fields (title, description, date, studio, performers, tags, cover; default all) to the scene. Performers and tags are added to the scene's own and created when missing. A cover, tag or performer failure is reported in warnings.",
+		Body:        request.ScrapeSceneRequest{},
+		Response:    core.SceneScrapeResult{},
+	},
+	"GET /api/v1/scrapers": {
+		Summary:  "List the scrapers",
+		Response: openapi.Object{"data": []scraper.Info{}},
+	},
+	"POST /api/v1/scrapers/preview": {
+		Summary:  "Scrape a scene page without changing any scene",
+		Body:     request.PreviewScrapeRequest{},
+		Response: scraper.ScrapedScene{},
+	},
+
+	// Search
+	"GET /api/v1/search/validate": {
+		Summary: "Validate an advanced search query",
+		Query:   openapi.Object{"q": aString},
+		Response: openapi.Object{
+			"valid":    aBool,
+			"query":    core.SearchQuery{},
+			"warnings": []string{},
+			"error":    aString,
+		},
+	},
+	"GET /api/v1/admin/search/status":                 {Response: openapi.Object{"available": aBool, "backend": aString}},
+	"GET /api/v1/admin/search/reindex":                {Response: openapi.Object{"status": core.ReindexStatus{}}},
+	"POST /api/v1/admin/search/reindex":               {Query: openapi.Object{"resume": aBool}, Response: openapi.Object{"message": aString, "status": core.ReindexStatus{}}, Status: 202},
+	"GET /api/v1/admin/search/diagnostics":            {Response: core.SearchDiagnostics{}},
+	"GET /api/v1/admin/search/diagnostics/scenes/:id": {Response: core.SceneIndexStatus{}},
+	"GET /api/v1/admin/search/consistency":            {Response: openapi.Object{"report": &core.SearchConsistencyReport{}}},
+	"POST /api/v1/admin/search/consistency":           {Query: openapi.Object{"heal": aBool}, Response: openapi.Object{"report": core.SearchConsistencyReport{}}},
+	"GET /api/v1/admin/search/config":                 {Response: openapi.Object{"max_total_hits": anInt64}},
+	"PUT /api/v1/admin/search/config":                 {Body: openapi.Object{"max_total_hits": anInt64}, Response: openapi.Object{"max_total_hits": anInt64}},
+	"GET /api/v1/saved-searches":                      {Response: openapi.Object{"data": []response.SavedSearchResponse{}}},
+	"POST /api/v1/saved-searches":                     {Body: request.CreateSavedSearchRequest{}, Response: response.SavedSearchResponse{}, Status: 201},
+	"GET /api/v1/saved-searches/:uuid":                {Response: response.SavedSearchResponse{}},
+	"PUT /api/v1/saved-searches/:uuid":                {Body: request.UpdateSavedSearchRequest{}, Response: response.SavedSearchResponse{}},
+	"DELETE /api/v1/saved-searches/:uuid":             {Status: 204},
+	"GET /api/v1/saved-searches/:uuid/new-matches":    {Response: openapi.Object{"data": []response.SceneListItem{}}},
+	"POST /api/v1/saved-searches/:uuid/seen":          {Status: 204},
+	"POST /api/v1/graphql": {
+		Summary:     "Run a GraphQL query",
+		Description: "Read-only GraphQL API over scenes, tags, actors, studios, markers and search. Query and field errors are returned in errors with a 200 status.",
+		Body:        graphql.Request{},
+		Response:    graphql.Response{},
+	},
+	"GET /api/v1/graphql/schema": {Summary: "Get the GraphQL schema", Description: "The schema in the GraphQL schema definition language, as text/plain."},
+}
+
+// newAPIDocument builds the OpenAPI document for the /api routes registered on r.
+func newAPIDocument(r *gin.Engine) *openapi.Document {
+	return openapi.Build(openapi.Config{
+		Info: openapi.Info{
+			Title:   "GoonHub API",
+			Version: version.Version,
+		},
+		PathPrefix:    "/api",
+		ErrorResponse: response.ErrorResponse{},
+		SecuritySchemes: map[string]openapi.SecurityScheme{
+			"bearer": {Type: "http", Scheme: "bearer", Description: "Session token or API key"},
+			"cookie": {Type: "apiKey", In: "cookie", Name: middleware.AuthCookieName, Description: "Session cookie set by login"},
+			"apiKey": {Type: "apiKey", In: "header", Name: middleware.APIKeyHeader, Description: "API key"},
+		},
+	}, r.Routes(), routeDocs)
+}
@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"goonhub"
 	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/openapi"
 	"goonhub/internal/api/v1/handler"
+	"goonhub/internal/api/v1/response"
 	"goonhub/internal/config"
 	"goonhub/internal/core"
 	"goonhub/internal/infrastructure/logging"
 	"io"
 	"io/fs"
 	"net/http"
+	"net/url"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -18,7 +22,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter(logger *logging.Logger, cfg *config.Config, sceneHandler *handler.SceneHandler, authHandler *handler.AuthHandler, settingsHandler *handler.SettingsHandler, adminHandler *handler.AdminHandler, jobHandler *handler.JobHandler, poolConfigHandler *handler.PoolConfigHandler, processingConfigHandler *handler.ProcessingConfigHandler, triggerConfigHandler *handler.TriggerConfigHandler, dlqHandler *handler.DLQHandler, retryConfigHandler *handler.RetryConfigHandler, sseHandler *handler.SSEHandler, tagHandler *handler.TagHandler, actorHandler *handler.ActorHandler, studioHandler *handler.StudioHandler, interactionHandler *handler.InteractionHandler, actorInteractionHandler *handler.ActorInteractionHandler, studioInteractionHandler *handler.StudioInteractionHandler, searchHandler *handler.SearchHandler, watchHistoryHandler *handler.WatchHistoryHandler, storagePathHandler *handler.StoragePathHandler, scanHandler *handler.ScanHandler, explorerHandler *handler.ExplorerHandler, pornDBHandler *handler.PornDBHandler, savedSearchHandler *handler.SavedSearchHandler, homepageHandler *handler.HomepageHandler, markerHandler *handler.MarkerHandler, importHandler *handler.ImportHandler, streamStatsHandler *handler.StreamStatsHandler, playlistHandler *handler.PlaylistHandler, shareHandler *handler.ShareHandler, authService *core.AuthService, rbacService *core.RBACService, rateLimiter *middleware.IPRateLimiter, ogMiddleware *middleware.OGMiddleware) *gin.Engine {
+func NewRouter(logger *logging.Logger, cfg *config.Config, sceneHandler *handler.SceneHandler, authHandler *handler.AuthHandler, settingsHandler *handler.SettingsHandler, adminHandler *handler.AdminHandler, jobHandler *handler.JobHandler, poolConfigHandler *handler.PoolConfigHandler, processingConfigHandler *handler.ProcessingConfigHandler, triggerConfigHandler *handler.TriggerConfigHandler, dlqHandler *handler.DLQHandler, retryConfigHandler *handler.RetryConfigHandler, sseHandler *handler.SSEHandler, tagHandler *handler.TagHandler, actorHandler *handler.ActorHandler, studioHandler *handler.StudioHandler, interactionHandler *handler.InteractionHandler, actorInteractionHandler *handler.ActorInteractionHandler, studioInteractionHandler *handler.StudioInteractionHandler, searchHandler *handler.SearchHandler, watchHistoryHandler *handler.WatchHistoryHandler, storagePathHandler *handler.StoragePathHandler, scanHandler *handler.ScanHandler, explorerHandler *handler.ExplorerHandler, pornDBHandler *handler.PornDBHandler, savedSearchHandler *handler.SavedSearchHandler, homepageHandler *handler.HomepageHandler, markerHandler *handler.MarkerHandler, importHandler *handler.ImportHandler, streamStatsHandler *handler.StreamStatsHandler, requestStatsHandler *handler.RequestStatsHandler, duplicateHandler *handler.DuplicateHandler, artifactHandler *handler.ArtifactHandler, releaseHandler *handler.ReleaseHandler, apiUsageHandler *handler.APIUsageHandler, downloadHandler *handler.DownloadHandler, reviewWorkflowHandler *handler.ReviewWorkflowHandler, playlistHandler *handler.PlaylistHandler, shareHandler *handler.ShareHandler, agentHandler *handler.AgentHandler, watchPartyHandler *handler.WatchPartyHandler, offlineSyncHandler *handler.OfflineSyncHandler, apiKeyHandler *handler.APIKeyHandler, auditHandler *handler.AuditHandler, registrationHandler *handler.RegistrationHandler, graphQLHandler *handler.GraphQLHandler, webhookHandler *handler.WebhookHandler, scraperHandler *handler.ScraperHandler, uploadHandler *handler.UploadHandler, remoteImportHandler *handler.RemoteImportHandler, torrentImportHandler *handler.TorrentImportHandler, authService *core.AuthService, rbacService *core.RBACService, privacyLockService *core.PrivacyLockService, requestStatsService *core.RequestStatsService, apiUsageService *core.APIUsageService, auditService *core.AuditService, shareService *core.ShareService, rateLimiter *middleware.IPRateLimiter, ogMiddleware *middleware.OGMiddleware, mediaSigner *core.MediaSigner) *gin.Engine {
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -36,15 +40,23 @@ func NewRouter(logger *logging.Logger, cfg *config.Config, sceneHandler *handler
 		r.SetTrustedProxies(nil)
 	}
 
-	middleware.Setup(r, logger, cfg.Server.AllowedOrigins, cfg.Environment)
+	middleware.Setup(r, logger, requestStatsService, apiUsageService, cfg.Server.AllowedOrigins, cfg.Environment)
 
 	// Health Check (Unversioned)
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok", "env": cfg.Environment})
 	})
 
+	// Scene media needs a session or a signed URL when media signing is enabled.
+	// Private caching keeps shared proxies from handing it to anyone.
+	mediaByID := middleware.MediaAccess(mediaSigner, authService, middleware.MediaSceneIDParam("id"))
+	mediaCacheControl := "public, max-age=31536000" // 1 year cache
+	if mediaSigner.Enabled() {
+		mediaCacheControl = "private, max-age=31536000"
+	}
+
 	// Serve Thumbnails (using configured thumbnail directory)
-	r.GET("/thumbnails/:id", func(c *gin.Context) {
+	r.GET("/thumbnails/:id", mediaByID, func(c *gin.Context) {
 		id := c.Param("id")
 		size := c.DefaultQuery("size", "sm")
 		if size != "sm" && size != "lg" {
@@ -52,26 +64,60 @@ func NewRouter(logger *logging.Logger, cfg *config.Config, sceneHandler *handler
 		}
 		path := filepath.Join(cfg.Processing.ThumbnailDir, fmt.Sprintf("%s_thumb_%s.webp", id, size))
 		c.Header("Content-Type", "image/webp")
-		c.Header("Cache-Control", "public, max-age=31536000") // 1 year cache
-		c.File(path)
+		c.Header("Cache-Control", mediaCacheControl)
+		response.File(c, path)
 	})
 
 	// Serve Sprite Sheets (using configured sprite directory)
-	r.GET("/sprites/:filename", func(c *gin.Context) {
+	r.GET("/sprites/:filename", middleware.MediaAccess(mediaSigner, authService, middleware.MediaSceneIDPrefix("filename")), func(c *gin.Context) {
 		filename := c.Param("filename")
 		path := filepath.Join(cfg.Processing.SpriteDir, filename)
 		c.Header("Content-Type", "image/webp")
-		c.Header("Cache-Control", "public, max-age=31536000") // 1 year cache
-		c.File(path)
+		c.Header("Cache-Control", mediaCacheControl)
+		response.File(c, path)
 	})
 
 	// Serve VTT Files (using configured VTT directory)
-	r.GET("/vtt/:videoId", func(c *gin.Context) {
+	r.GET("/vtt/:videoId", middleware.MediaAccess(mediaSigner, authService, middleware.MediaSceneIDParam("videoId")), func(c *gin.Context) {
 		videoId := c.Param("videoId")
 		path := filepath.Join(cfg.Processing.VttDir, fmt.Sprintf("%s_thumbnails.vtt", videoId))
 		c.Header("Content-Type", "text/vtt")
-		c.Header("Cache-Control", "public, max-age=31536000") // 1 year cache
-		c.File(path)
+		c.Header("Cache-Control", mediaCacheControl)
+
+		// A signed VTT passes its signature on to the sprite URLs inside it,
+		// which cover the same scene
+		if mediaSigner.Enabled() && c.Query("sig") != "" {
+			info, err := os.Stat(path)
+			if err != nil {
+				c.Status(http.StatusNotFound)
+				return
+			}
+			if response.NotModified(c, response.FileETag(info), info.ModTime()) {
+				return
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				c.Status(http.StatusNotFound)
+				return
+			}
+			query := url.Values{"exp": {c.Query("exp")}, "sig": {c.Query("sig")}}.Encode()
+			c.Data(http.StatusOK, "text/vtt", []byte(strings.ReplaceAll(string(content), "#xywh=", "?"+query+"#xywh=")))
+			return
+		}
+		response.File(c, path)
+	})
+
+	// Serve BIF trickplay files for Roku/Plex-style players (using configured sprite directory)
+	r.GET("/trickplay/:videoId", middleware.MediaAccess(mediaSigner, authService, middleware.MediaSceneIDParam("videoId")), func(c *gin.Context) {
+		videoId := c.Param("videoId")
+		path := filepath.Join(cfg.Processing.SpriteDir, fmt.Sprintf("%s_trickplay.bif", filepath.Base(videoId)))
+		c.Header("Content-Type", "application/octet-stream")
+		if mediaSigner.Enabled() {
+			c.Header("Cache-Control", "private, max-age=86400")
+		} else {
+			c.Header("Cache-Control", "public, max-age=86400")
+		}
+		response.File(c, path)
 	})
 
 	// Serve Actor Images (using configured actor image directory)
@@ -92,7 +138,7 @@ func NewRouter(logger *logging.Logger, cfg *config.Config, sceneHandler *handler
 			c.Header("Content-Type", "application/octet-stream")
 		}
 		c.Header("Cache-Control", "public, max-age=31536000") // 1 year cache
-		c.File(path)
+		response.File(c, path)
 	})
 
 	// Serve Studio Logos (using configured studio logo directory)
@@ -113,7 +159,7 @@ func NewRouter(logger *logging.Logger, cfg *config.Config, sceneHandler *handler
 			c.Header("Content-Type", "application/octet-stream")
 		}
 		c.Header("Cache-Control", "public, max-age=31536000") // 1 year cache
-		c.File(path)
+		response.File(c, path)
 	})
 
 	// Serve Marker Thumbnails (using configured marker thumbnail directory)
@@ -127,7 +173,7 @@ func NewRouter(logger *logging.Logger, cfg *config.Config, sceneHandler *handler
 		path := filepath.Join(cfg.Processing.MarkerThumbnailDir, fmt.Sprintf("marker_%s.webp", id))
 		c.Header("Content-Type", "image/webp")
 		c.Header("Cache-Control", "public, max-age=31536000") // 1 year cache
-		c.File(path)
+		response.File(c, path)
 	})
 
 	// Serve Animated Marker Thumbnails (MP4 clips)
@@ -140,11 +186,11 @@ func NewRouter(logger *logging.Logger, cfg *config.Config, sceneHandler *handler
 		path := filepath.Join(cfg.Processing.MarkerThumbnailDir, fmt.Sprintf("marker_%s.mp4", id))
 		c.Header("Content-Type", "video/mp4")
 		c.Header("Cache-Control", "public, max-age=31536000") // 1 year cache
-		c.File(path)
+		response.File(c, path)
 	})
 
 	// Serve Scene Preview Videos (MP4 clips for hover preview)
-	r.GET("/scene-previews/:id", func(c *gin.Context) {
+	r.GET("/scene-previews/:id", mediaByID, func(c *gin.Context) {
 		id := c.Param("id")
 		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scene ID"})
@@ -152,12 +198,19 @@ func NewRouter(logger *logging.Logger, cfg *config.Config, sceneHandler *handler
 		}
 		path := filepath.Join(cfg.Processing.ScenePreviewDir, fmt.Sprintf("%s_preview.mp4", id))
 		c.Header("Content-Type", "video/mp4")
-		c.Header("Cache-Control", "public, max-age=31536000") // 1 year cache
-		c.File(path)
+		c.Header("Cache-Control", mediaCacheControl)
+		response.File(c, path)
 	})
 
 	// Register Routes
-	RegisterRoutes(r, sceneHandler, authHandler, settingsHandler, adminHandler, jobHandler, poolConfigHandler, processingConfigHandler, triggerConfigHandler, dlqHandler, retryConfigHandler, sseHandler, tagHandler, actorHandler, studioHandler, interactionHandler, actorInteractionHandler, studioInteractionHandler, searchHandler, watchHistoryHandler, storagePathHandler, scanHandler, explorerHandler, pornDBHandler, savedSearchHandler, homepageHandler, markerHandler, importHandler, streamStatsHandler, playlistHandler, shareHandler, authService, rbacService, logger, rateLimiter)
+	RegisterRoutes(r, sceneHandler, authHandler, settingsHandler, adminHandler, jobHandler, poolConfigHandler, processingConfigHandler, triggerConfigHandler, dlqHandler, retryConfigHandler, sseHandler, tagHandler, actorHandler, studioHandler, interactionHandler, actorInteractionHandler, studioInteractionHandler, searchHandler, watchHistoryHandler, storagePathHandler, scanHandler, explorerHandler, pornDBHandler, savedSearchHandler, homepageHandler, markerHandler, importHandler, streamStatsHandler, requestStatsHandler, duplicateHandler, artifactHandler, releaseHandler, apiUsageHandler, downloadHandler, reviewWorkflowHandler, playlistHandler, shareHandler, agentHandler, watchPartyHandler, offlineSyncHandler, apiKeyHandler, auditHandler, registrationHandler, graphQLHandler, webhookHandler, scraperHandler, uploadHandler, remoteImportHandler, torrentImportHandler, authService, rbacService, privacyLockService, auditService, shareService, logger, rateLimiter, cfg.Agents)
+
+	// API documentation, generated from the routes registered above
+	apiDoc := newAPIDocument(r)
+	r.GET("/api/v1/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, apiDoc)
+	})
+	r.GET("/api/docs", openapi.UIHandler("/api/v1/openapi.json"))
 
 	// Serve Frontend (SPA Fallback)
 	fsys, _ := fs.Sub(goonhub.WebDist, "web/dist")
@@ -3,13 +3,15 @@ package api
 import (
 	"goonhub/internal/api/middleware"
 	"goonhub/internal/api/v1/handler"
+	"goonhub/internal/config"
 	"goonhub/internal/core"
 	"goonhub/internal/infrastructure/logging"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandler *handler.AuthHandler, settingsHandler *handler.SettingsHandler, adminHandler *handler.AdminHandler, jobHandler *handler.JobHandler, poolConfigHandler *handler.PoolConfigHandler, processingConfigHandler *handler.ProcessingConfigHandler, triggerConfigHandler *handler.TriggerConfigHandler, dlqHandler *handler.DLQHandler, retryConfigHandler *handler.RetryConfigHandler, sseHandler *handler.SSEHandler, tagHandler *handler.TagHandler, actorHandler *handler.ActorHandler, studioHandler *handler.StudioHandler, interactionHandler *handler.InteractionHandler, actorInteractionHandler *handler.ActorInteractionHandler, studioInteractionHandler *handler.StudioInteractionHandler, searchHandler *handler.SearchHandler, watchHistoryHandler *handler.WatchHistoryHandler, storagePathHandler *handler.StoragePathHandler, scanHandler *handler.ScanHandler, explorerHandler *handler.ExplorerHandler, pornDBHandler *handler.PornDBHandler, savedSearchHandler *handler.SavedSearchHandler, homepageHandler *handler.HomepageHandler, markerHandler *handler.MarkerHandler, importHandler *handler.ImportHandler, streamStatsHandler *handler.StreamStatsHandler, playlistHandler *handler.PlaylistHandler, shareHandler *handler.ShareHandler, authService *core.AuthService, rbacService *core.RBACService, logger *logging.Logger, rateLimiter *middleware.IPRateLimiter) {
+func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandler *handler.AuthHandler, settingsHandler *handler.SettingsHandler, adminHandler *handler.AdminHandler, jobHandler *handler.JobHandler, poolConfigHandler *handler.PoolConfigHandler, processingConfigHandler *handler.ProcessingConfigHandler, triggerConfigHandler *handler.TriggerConfigHandler, dlqHandler *handler.DLQHandler, retryConfigHandler *handler.RetryConfigHandler, sseHandler *handler.SSEHandler, tagHandler *handler.TagHandler, actorHandler *handler.ActorHandler, studioHandler *handler.StudioHandler, interactionHandler *handler.InteractionHandler, actorInteractionHandler *handler.ActorInteractionHandler, studioInteractionHandler *handler.StudioInteractionHandler, searchHandler *handler.SearchHandler, watchHistoryHandler *handler.WatchHistoryHandler, storagePathHandler *handler.StoragePathHandler, scanHandler *handler.ScanHandler, explorerHandler *handler.ExplorerHandler, pornDBHandler *handler.PornDBHandler, savedSearchHandler *handler.SavedSearchHandler, homepageHandler *handler.HomepageHandler, markerHandler *handler.MarkerHandler, importHandler *handler.ImportHandler, streamStatsHandler *handler.StreamStatsHandler, requestStatsHandler *handler.RequestStatsHandler, duplicateHandler *handler.DuplicateHandler, artifactHandler *handler.ArtifactHandler, releaseHandler *handler.ReleaseHandler, apiUsageHandler *handler.APIUsageHandler, downloadHandler *handler.DownloadHandler, reviewWorkflowHandler *handler.ReviewWorkflowHandler, playlistHandler *handler.PlaylistHandler, shareHandler *handler.ShareHandler, agentHandler *handler.AgentHandler, watchPartyHandler *handler.WatchPartyHandler, offlineSyncHandler *handler.OfflineSyncHandler, apiKeyHandler *handler.APIKeyHandler, auditHandler *handler.AuditHandler, registrationHandler *handler.RegistrationHandler, graphQLHandler *handler.GraphQLHandler, webhookHandler *handler.WebhookHandler, scraperHandler *handler.ScraperHandler, uploadHandler *handler.UploadHandler, remoteImportHandler *handler.RemoteImportHandler, torrentImportHandler *handler.TorrentImportHandler, authService *core.AuthService, rbacService *core.RBACService, privacyLockService *core.PrivacyLockService, auditService *core.AuditService, shareService *core.ShareService, logger *logging.Logger, rateLimiter *middleware.IPRateLimiter, agentsCfg config.AgentsConfig) {
 	api := r.Group("/api")
 	{
 		v1 := api.Group("/v1")
@@ -20,34 +22,85 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 			// Public share endpoints (no auth required)
 			shares := v1.Group("/shares")
 			{
-				shares.GET("/:token", shareHandler.ResolveShareLink)
-				shares.GET("/:token/stream", shareHandler.StreamShareLink)
+				shareAccess := middleware.ShareAccess(shareService, authService)
+				shares.GET("/:token", shareAccess, shareHandler.ResolveShareLink)
+				shares.GET("/:token/stream", shareAccess, shareHandler.StreamShareLink)
+				shares.GET("/:token/thumbnail", shareAccess, shareHandler.ShareThumbnail)
+				shares.POST("/:token/unlock", middleware.RateLimitMiddleware(rateLimiter, logger.Logger), shareHandler.UnlockShareLink)
 			}
 
 			auth := v1.Group("/auth")
 			{
 				auth.POST("/login", middleware.RateLimitMiddleware(rateLimiter, logger.Logger), authHandler.Login)
+				auth.POST("/login/2fa", middleware.RateLimitMiddleware(rateLimiter, logger.Logger), authHandler.LoginTwoFactor)
+				auth.GET("/registration", registrationHandler.GetRegistrationStatus)
+				auth.GET("/invites/:token", middleware.RateLimitMiddleware(rateLimiter, logger.Logger), registrationHandler.PreviewInvite)
+				auth.POST("/register", middleware.RateLimitMiddleware(rateLimiter, logger.Logger), registrationHandler.Register)
 			}
 
-			protected := v1.Group("")
+			// Remote job agents (auth via shared agent token)
+			agents := v1.Group("/agents")
+			agents.Use(middleware.AgentAuthMiddleware(agentsCfg.Enabled, agentsCfg.Token))
+			{
+				agents.POST("/register", agentHandler.Register)
+				agents.POST("/:agentID/heartbeat", agentHandler.Heartbeat)
+				agents.POST("/:agentID/claim", agentHandler.Claim)
+				agents.GET("/:agentID/tasks/:taskID/source", agentHandler.DownloadSource)
+				agents.POST("/:agentID/tasks/:taskID/progress", agentHandler.ReportProgress)
+				agents.PUT("/:agentID/tasks/:taskID/artifacts/:name", agentHandler.UploadArtifact)
+				agents.POST("/:agentID/tasks/:taskID/complete", agentHandler.CompleteTask)
+			}
+
+			protected := middleware.NewAPIKeyScopedGroup(v1.Group(""))
 			protected.Use(middleware.AuthMiddleware(authService))
+			protected.Use(middleware.PrivacyLockMiddleware(privacyLockService))
+			protected.Use(middleware.TwoFactorSetupMiddleware())
+			protected.Use(middleware.APIKeyRouteGuardMiddleware())
+			protected.Use(middleware.AuditMiddleware(auditService))
 			{
 				auth := protected.Group("/auth")
 				{
 					auth.GET("/me", authHandler.Me)
 					auth.POST("/logout", authHandler.Logout)
+					auth.GET("/privacy-lock", authHandler.GetPrivacyLock)
+					auth.POST("/privacy-lock/unlock", authHandler.UnlockPrivacyLock)
+					auth.POST("/privacy-lock/lock", authHandler.LockPrivacyLock)
+					auth.GET("/2fa", authHandler.GetTwoFactor)
+					auth.POST("/2fa/setup", authHandler.SetupTwoFactor)
+					auth.POST("/2fa/enable", authHandler.EnableTwoFactor)
+					auth.POST("/2fa/disable", authHandler.DisableTwoFactor)
+					auth.POST("/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
+					auth.GET("/sessions", authHandler.ListSessions)
+					auth.DELETE("/sessions", authHandler.RevokeOtherSessions)
+					auth.DELETE("/sessions/:id", authHandler.RevokeSession)
 				}
 
-				scenes := protected.Group("/scenes")
+				scenes := protected.Group("/scenes", middleware.SceneAccess(sceneHandler.Service))
 				{
 					scenes.POST("", middleware.RequirePermission(rbacService, "scenes:upload"), sceneHandler.UploadScene)
+					scenes.POST("/batch", middleware.RequirePermission(rbacService, "scenes:upload"), uploadHandler.UploadBatch)
+					scenes.POST("/uploads", middleware.RequirePermission(rbacService, "scenes:upload"), uploadHandler.CreateUpload)
+					scenes.GET("/uploads/:uploadId", middleware.RequirePermission(rbacService, "scenes:upload"), uploadHandler.GetUpload)
+					scenes.PATCH("/uploads/:uploadId", middleware.RequirePermission(rbacService, "scenes:upload"), uploadHandler.AppendChunk)
+					scenes.DELETE("/uploads/:uploadId", middleware.RequirePermission(rbacService, "scenes:upload"), uploadHandler.CancelUpload)
 					scenes.GET("", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.ListScenes)
 					scenes.GET("/filters", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.GetFilterOptions)
+					scenes.GET("/segments", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.SearchSegments)
+					scenes.GET("/random", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.RandomScene)
+					scenes.GET("/shuffle", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.ShuffleQueue)
+					scenes.PATCH("/bulk", middleware.RequirePermission(rbacService, "scenes:upload"), explorerHandler.BulkUpdateMetadata)
 					scenes.GET("/:id", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.GetScene)
+					scenes.POST("/:id/playback", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.NegotiatePlayback)
+					scenes.GET("/:id/cast", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.GetCastInfo)
+					scenes.GET("/:id/frame", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.GetFrame)
+					scenes.GET("/:id/media", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.GetMediaURLs)
+					scenes.GET("/:id/download", middleware.RequirePermission(rbacService, "scenes:download"), downloadHandler.DownloadScene)
 					scenes.GET("/:id/reprocess", middleware.RequirePermission(rbacService, "scenes:reprocess"), sceneHandler.ReprocessScene)
 					scenes.PUT("/:id/thumbnail", middleware.RequirePermission(rbacService, "scenes:upload"), sceneHandler.ExtractThumbnail)
 					scenes.POST("/:id/thumbnail/upload", middleware.RequirePermission(rbacService, "scenes:upload"), sceneHandler.UploadThumbnail)
 					scenes.PUT("/:id/details", middleware.RequirePermission(rbacService, "scenes:upload"), sceneHandler.UpdateSceneDetails)
+					scenes.POST("/:id/scrape", middleware.RequirePermission(rbacService, "scenes:upload"), scraperHandler.ScrapeScene)
+					scenes.PUT("/:id/review-state", middleware.RequirePermission(rbacService, "scenes:upload"), reviewWorkflowHandler.SetSceneState)
 					scenes.DELETE("/:id", middleware.RequirePermission(rbacService, "scenes:trash"), sceneHandler.DeleteScene)
 					scenes.GET("/:id/tags", middleware.RequirePermission(rbacService, "scenes:view"), tagHandler.GetSceneTags)
 					scenes.PUT("/:id/tags", middleware.RequirePermission(rbacService, "scenes:upload"), tagHandler.SetSceneTags)
@@ -67,14 +120,28 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 					scenes.GET("/:id/studio", middleware.RequirePermission(rbacService, "scenes:view"), studioHandler.GetSceneStudio)
 					scenes.PUT("/:id/studio", middleware.RequirePermission(rbacService, "scenes:upload"), studioHandler.SetSceneStudio)
 					scenes.GET("/:id/related", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.GetRelatedScenes)
+					scenes.GET("/:id/similar", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.GetSimilarScenes)
+					scenes.GET("/:id/integrity", middleware.RequirePermission(rbacService, "scenes:view"), sceneHandler.GetIntegrityReport)
 					scenes.GET("/:id/markers", middleware.RequirePermission(rbacService, "scenes:view"), markerHandler.ListMarkers)
+					scenes.GET("/:id/markers/export", middleware.RequirePermission(rbacService, "scenes:view"), markerHandler.ExportMarkers)
+					scenes.GET("/:id/markers/shared", middleware.RequirePermission(rbacService, "markers:view_shared"), markerHandler.ListSharedMarkers)
+					scenes.POST("/:id/markers/import", middleware.RequirePermission(rbacService, "scenes:view"), markerHandler.ImportMarkers)
 					scenes.POST("/:id/markers", middleware.RequirePermission(rbacService, "scenes:view"), markerHandler.CreateMarker)
 					scenes.PUT("/:id/markers/:markerID", middleware.RequirePermission(rbacService, "scenes:view"), markerHandler.UpdateMarker)
 					scenes.DELETE("/:id/markers/:markerID", middleware.RequirePermission(rbacService, "scenes:view"), markerHandler.DeleteMarker)
+					scenes.GET("/:id/markers/:markerID/clip", middleware.RequirePermission(rbacService, "scenes:view"), markerHandler.StreamMarkerClip)
+					scenes.POST("/:id/markers/:markerID/copy", middleware.RequirePermission(rbacService, "markers:copy_shared"), markerHandler.CopySharedMarker)
+					scenes.POST("/:id/markers/thumbnails/regenerate", middleware.RequirePermission(rbacService, "markers:process"), markerHandler.RegenerateSceneThumbnails)
 					scenes.POST("/:id/shares", middleware.RequirePermission(rbacService, "scenes:view"), shareHandler.CreateShareLink)
 					scenes.GET("/:id/shares", middleware.RequirePermission(rbacService, "scenes:view"), shareHandler.ListShareLinks)
 				}
 
+				scrapers := protected.Group("/scrapers")
+				{
+					scrapers.GET("", middleware.RequirePermission(rbacService, "scenes:upload"), scraperHandler.ListScrapers)
+					scrapers.POST("/preview", middleware.RequirePermission(rbacService, "scenes:upload"), scraperHandler.PreviewScrape)
+				}
+
 				// Share link deletion (protected, not under /scenes/:id)
 				protected.DELETE("/shares/:id", shareHandler.DeleteShareLink)
 
@@ -85,19 +152,62 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 					history.GET("/activity", watchHistoryHandler.GetDailyActivity)
 				}
 
+				protected.GET("/usage", apiUsageHandler.GetMyUsage)
+
+				downloads := protected.Group("/downloads")
+				{
+					downloads.GET("", downloadHandler.ListDownloads)
+					downloads.GET("/bundle", middleware.RequirePermission(rbacService, "scenes:download"), downloadHandler.DownloadBundle)
+				}
+
+				reviewWorkflow := protected.Group("/review-workflow")
+				{
+					reviewWorkflow.GET("", reviewWorkflowHandler.GetWorkflow)
+					reviewWorkflow.POST("/bulk", middleware.RequirePermission(rbacService, "scenes:upload"), reviewWorkflowHandler.BulkSetState)
+				}
+
+				watchParties := protected.Group("/watch-parties")
+				watchParties.Use(middleware.RequirePermission(rbacService, "scenes:view"))
+				{
+					watchParties.GET("", watchPartyHandler.List)
+					watchParties.POST("", watchPartyHandler.Create)
+					watchParties.GET("/:id", watchPartyHandler.Get)
+					watchParties.DELETE("/:id", watchPartyHandler.End)
+					watchParties.GET("/:id/ws", watchPartyHandler.Connect)
+				}
+
+				apiKeys := protected.Group("/api-keys")
+				{
+					apiKeys.GET("", apiKeyHandler.ListAPIKeys)
+					apiKeys.GET("/permissions", apiKeyHandler.ListAPIKeyPermissions)
+					apiKeys.POST("", apiKeyHandler.CreateAPIKey)
+					apiKeys.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+				}
+
+				offlineSync := protected.Group("/sync")
+				offlineSync.Use(middleware.RequirePermission(rbacService, "scenes:download"))
+				{
+					offlineSync.GET("/manifest", offlineSyncHandler.GetManifest)
+					offlineSync.POST("/scenes", offlineSyncHandler.AddScenes)
+					offlineSync.DELETE("/scenes/:id", offlineSyncHandler.RemoveScene)
+				}
+
 				tags := protected.Group("/tags")
 				{
-					tags.GET("", tagHandler.ListTags)
+					tags.GET("", middleware.RequirePermission(rbacService, "scenes:view"), tagHandler.ListTags)
+					tags.GET("/usage", middleware.RequirePermission(rbacService, "scenes:view"), tagHandler.GetTagUsage)
 					tags.POST("", tagHandler.CreateTag)
 					tags.DELETE("/:id", tagHandler.DeleteTag)
 				}
 
 				actors := protected.Group("/actors")
 				{
-					actors.GET("", actorHandler.ListActors)
-					actors.GET("/:uuid", actorHandler.GetActorByUUID)
-					actors.GET("/:uuid/scenes", actorHandler.GetActorScenes)
-					actors.GET("/:uuid/interactions", actorInteractionHandler.GetInteractions)
+					actors.GET("", middleware.RequirePermission(rbacService, "scenes:view"), actorHandler.ListActors)
+					actors.GET("/usage", middleware.RequirePermission(rbacService, "scenes:view"), actorHandler.GetActorUsage)
+					actors.GET("/:uuid", middleware.RequirePermission(rbacService, "scenes:view"), actorHandler.GetActorByUUID)
+					actors.GET("/:uuid/scenes", middleware.RequirePermission(rbacService, "scenes:view"), actorHandler.GetActorScenes)
+					actors.GET("/:uuid/stats", middleware.RequirePermission(rbacService, "scenes:view"), actorHandler.GetActorStats)
+					actors.GET("/:uuid/interactions", middleware.RequirePermission(rbacService, "scenes:view"), actorInteractionHandler.GetInteractions)
 					actors.PUT("/:uuid/rating", actorInteractionHandler.SetRating)
 					actors.DELETE("/:uuid/rating", actorInteractionHandler.DeleteRating)
 					actors.POST("/:uuid/like", actorInteractionHandler.ToggleLike)
@@ -105,10 +215,11 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 
 				studios := protected.Group("/studios")
 				{
-					studios.GET("", studioHandler.ListStudios)
-					studios.GET("/:uuid", studioHandler.GetStudioByUUID)
-					studios.GET("/:uuid/scenes", studioHandler.GetStudioScenes)
-					studios.GET("/:uuid/interactions", studioInteractionHandler.GetInteractions)
+					studios.GET("", middleware.RequirePermission(rbacService, "scenes:view"), studioHandler.ListStudios)
+					studios.GET("/:uuid", middleware.RequirePermission(rbacService, "scenes:view"), studioHandler.GetStudioByUUID)
+					studios.GET("/:uuid/scenes", middleware.RequirePermission(rbacService, "scenes:view"), studioHandler.GetStudioScenes)
+					studios.GET("/:uuid/children", middleware.RequirePermission(rbacService, "scenes:view"), studioHandler.GetStudioChildren)
+					studios.GET("/:uuid/interactions", middleware.RequirePermission(rbacService, "scenes:view"), studioInteractionHandler.GetInteractions)
 					studios.PUT("/:uuid/rating", studioInteractionHandler.SetRating)
 					studios.DELETE("/:uuid/rating", studioInteractionHandler.DeleteRating)
 					studios.POST("/:uuid/like", studioInteractionHandler.ToggleLike)
@@ -133,6 +244,7 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 					settings.PUT("", settingsHandler.UpdateAllSettings)
 					settings.PUT("/password", settingsHandler.ChangePassword)
 					settings.PUT("/username", settingsHandler.ChangeUsername)
+					settings.PUT("/privacy-lock", authHandler.UpdatePrivacyLock)
 					settings.GET("/parsing-rules", settingsHandler.GetParsingRules)
 					settings.PUT("/parsing-rules", settingsHandler.UpdateParsingRules)
 				}
@@ -143,6 +255,8 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 					homepage.GET("/sections/:id", homepageHandler.GetSectionData)
 				}
 
+				protected.GET("/search/validate", searchHandler.ValidateQuery)
+
 				savedSearches := protected.Group("/saved-searches")
 				{
 					savedSearches.GET("", savedSearchHandler.List)
@@ -150,6 +264,14 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 					savedSearches.POST("", savedSearchHandler.Create)
 					savedSearches.PUT("/:uuid", savedSearchHandler.Update)
 					savedSearches.DELETE("/:uuid", savedSearchHandler.Delete)
+					savedSearches.GET("/:uuid/new-matches", savedSearchHandler.NewMatches)
+					savedSearches.POST("/:uuid/seen", savedSearchHandler.MarkSeen)
+				}
+
+				graphQL := protected.Group("/graphql")
+				{
+					graphQL.POST("", middleware.RequirePermission(rbacService, "scenes:view"), graphQLHandler.Execute)
+					graphQL.GET("/schema", middleware.RequirePermission(rbacService, "scenes:view"), graphQLHandler.Schema)
 				}
 
 				playlists := protected.Group("/playlists")
@@ -178,7 +300,19 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 					markers.GET("/labels", markerHandler.ListLabelSuggestions)
 					markers.GET("/by-label", markerHandler.ListMarkersByLabel)
 					markers.GET("/label-tags", markerHandler.GetLabelTags)
+					markers.GET("/collections", markerHandler.ListTagCollections)
+					markers.GET("/collections/:tagID", markerHandler.ListCollectionMarkers)
+					markers.GET("/collections/:tagID/queue", markerHandler.GetCollectionQueue)
+					markers.POST("/compilations", middleware.RequirePermission(rbacService, "markers:process"), markerHandler.CreateCompilation)
+					markers.GET("/compilations", markerHandler.ListCompilations)
+					markers.GET("/compilations/:jobID", markerHandler.GetCompilation)
+					markers.GET("/compilations/:jobID/download", markerHandler.DownloadCompilation)
+					markers.DELETE("/compilations/:jobID", markerHandler.DeleteCompilation)
 					markers.PUT("/label-tags", markerHandler.SetLabelTags)
+					markers.GET("/label-settings", markerHandler.GetLabelSettings)
+					markers.PUT("/label-settings", markerHandler.SetLabelSetting)
+					markers.DELETE("/label-settings", markerHandler.DeleteLabelSetting)
+					markers.POST("/thumbnails/regenerate", middleware.RequirePermission(rbacService, "markers:process"), markerHandler.RegenerateThumbnails)
 					markers.GET("/:markerID/tags", markerHandler.GetMarkerTags)
 					markers.PUT("/:markerID/tags", markerHandler.SetMarkerTags)
 					markers.POST("/:markerID/tags", markerHandler.AddMarkerTags)
@@ -192,9 +326,29 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 					admin.PUT("/users/:id/role", adminHandler.UpdateUserRole)
 					admin.PUT("/users/:id/password", adminHandler.ResetUserPassword)
 					admin.DELETE("/users/:id", adminHandler.DeleteUser)
+
+					// Registration invites
+					admin.GET("/invites", registrationHandler.ListInvites)
+					admin.POST("/invites", registrationHandler.CreateInvite)
+					admin.DELETE("/invites/:id", registrationHandler.RevokeInvite)
+
+					// Outbound webhooks and their delivery history
+					admin.GET("/webhooks", webhookHandler.ListWebhooks)
+					admin.POST("/webhooks", webhookHandler.CreateWebhook)
+					admin.GET("/webhooks/:id", webhookHandler.GetWebhook)
+					admin.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+					admin.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+					admin.POST("/webhooks/:id/rotate-secret", webhookHandler.RotateWebhookSecret)
+					admin.POST("/webhooks/:id/ping", webhookHandler.PingWebhook)
+					admin.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+					admin.GET("/webhooks/:id/deliveries/:deliveryID", webhookHandler.GetDelivery)
+					admin.POST("/webhooks/:id/deliveries/:deliveryID/redeliver", webhookHandler.RedeliverDelivery)
+
 					admin.GET("/roles", adminHandler.ListRoles)
 					admin.GET("/permissions", adminHandler.ListPermissions)
 					admin.PUT("/roles/:id/permissions", adminHandler.SyncRolePermissions)
+					admin.GET("/roles/:id/content-restrictions", adminHandler.GetContentRestriction)
+					admin.PUT("/roles/:id/content-restrictions", adminHandler.UpdateContentRestriction)
 					admin.GET("/jobs", jobHandler.ListJobs)
 					admin.GET("/pool-config", poolConfigHandler.GetPoolConfig)
 					admin.PUT("/pool-config", poolConfigHandler.UpdatePoolConfig)
@@ -204,35 +358,82 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 					admin.PUT("/trigger-config", triggerConfigHandler.UpdateTriggerConfig)
 					admin.POST("/scenes/:id/process/:phase", jobHandler.TriggerPhase)
 					admin.PUT("/scenes/:id/scene-metadata", sceneHandler.ApplySceneMetadata)
+					admin.POST("/scenes/:id/apply-match", pornDBHandler.ApplyMatch)
 					admin.POST("/jobs/bulk", jobHandler.TriggerBulkPhase)
+					admin.POST("/jobs/verify-all", jobHandler.VerifyAll)
+					admin.POST("/jobs/verify-checksums", jobHandler.VerifyChecksums)
 					admin.POST("/jobs/retry-all-failed", jobHandler.RetryAllFailed)
 					admin.POST("/jobs/retry-batch", jobHandler.RetryBatch)
+					admin.POST("/markers/thumbnails/regenerate", markerHandler.RegenerateAllThumbnails)
 					admin.DELETE("/jobs/failed", jobHandler.ClearFailed)
+					admin.POST("/jobs/cancel", jobHandler.CancelJobs)
 					admin.POST("/jobs/:id/cancel", jobHandler.CancelJob)
 					admin.POST("/jobs/:id/retry", jobHandler.RetryJob)
+					admin.GET("/jobs/:id/logs", jobHandler.GetJobLog)
 					admin.GET("/jobs/recent-failed", jobHandler.ListRecentFailed)
+					admin.GET("/jobs/analytics", jobHandler.GetAnalytics)
+					admin.GET("/jobs/analytics/timeline", jobHandler.GetOutcomeTimeline)
+					admin.GET("/jobs/analytics/slowest", jobHandler.GetSlowestJobs)
+					admin.GET("/jobs/retention", jobHandler.GetRetention)
+					admin.POST("/jobs/retention/run", jobHandler.RunRetention)
+					admin.GET("/jobs/failure-summaries", jobHandler.ListFailureSummaries)
+					admin.GET("/agents", agentHandler.ListAgents)
 					admin.GET("/dlq", dlqHandler.ListDLQ)
 					admin.POST("/dlq/:job_id/retry", dlqHandler.RetryFromDLQ)
 					admin.POST("/dlq/:job_id/abandon", dlqHandler.AbandonDLQ)
+					admin.POST("/dlq/requeue", dlqHandler.RequeueDLQ)
+					admin.POST("/dlq/purge", dlqHandler.PurgeDLQ)
 					admin.GET("/retry-config", retryConfigHandler.GetRetryConfig)
 					admin.PUT("/retry-config", retryConfigHandler.UpdateRetryConfig)
 					admin.GET("/search/status", searchHandler.GetStatus)
+					admin.GET("/search/reindex", searchHandler.GetReindexStatus)
 					admin.POST("/search/reindex", searchHandler.ReindexAll)
+					admin.GET("/search/diagnostics", searchHandler.GetDiagnostics)
+					admin.GET("/search/diagnostics/scenes/:id", searchHandler.GetSceneIndexStatus)
+					admin.GET("/search/consistency", searchHandler.GetConsistency)
+					admin.POST("/search/consistency", searchHandler.CheckConsistency)
 					admin.GET("/search/config", searchHandler.GetSearchConfig)
 					admin.PUT("/search/config", searchHandler.UpdateSearchConfig)
 					admin.GET("/storage-paths", storagePathHandler.List)
 					admin.POST("/storage-paths", storagePathHandler.Create)
 					admin.PUT("/storage-paths/:id", storagePathHandler.Update)
 					admin.DELETE("/storage-paths/:id", storagePathHandler.Delete)
+					admin.PUT("/storage-paths/:id/scan-schedule", storagePathHandler.UpdateScanSchedule)
+					admin.PUT("/storage-paths/:id/exclude-patterns", storagePathHandler.UpdateExcludePatterns)
 					admin.POST("/storage-paths/validate", storagePathHandler.ValidatePath)
+					admin.GET("/storage-paths/:id/health", storagePathHandler.GetHealthHistory)
+					admin.POST("/storage-paths/health/check", storagePathHandler.CheckHealth)
+					admin.POST("/explorer/move", explorerHandler.MoveScenes)
+					admin.POST("/explorer/migrate", explorerHandler.MigrateScenes)
+					admin.POST("/explorer/scenes/:id/rename", explorerHandler.RenameSceneFile)
 					admin.POST("/scan", scanHandler.StartScan)
 					admin.POST("/scan/cancel", scanHandler.CancelScan)
 					admin.GET("/scan/status", scanHandler.GetStatus)
 					admin.GET("/scan/history", scanHandler.GetHistory)
+					admin.GET("/scan/history/:id/report", scanHandler.GetReport)
+					admin.GET("/scan/history/:id/report/download", scanHandler.DownloadReport)
+					admin.GET("/scan/exclusions", scanHandler.GetExclusions)
+					admin.PUT("/scan/exclusions", scanHandler.UpdateExclusions)
+					admin.POST("/scan/exclusions/test", scanHandler.TestExclusions)
+					admin.GET("/scan/folder-rules", scanHandler.ListFolderRules)
+					admin.POST("/scan/folder-rules", scanHandler.CreateFolderRule)
+					admin.GET("/scan/folder-rules/apply", scanHandler.GetFolderRulesApplyStatus)
+					admin.POST("/scan/folder-rules/apply", scanHandler.ApplyFolderRules)
+					admin.PUT("/scan/folder-rules/:id", scanHandler.UpdateFolderRule)
+					admin.DELETE("/scan/folder-rules/:id", scanHandler.DeleteFolderRule)
+					admin.POST("/scan/dropbox", scanHandler.RunDropboxImport)
 					admin.POST("/actors", actorHandler.CreateActor)
 					admin.PUT("/actors/:id", actorHandler.UpdateActor)
 					admin.DELETE("/actors/:id", actorHandler.DeleteActor)
 					admin.POST("/actors/:id/image", actorHandler.UploadActorImage)
+					admin.POST("/actors/:id/image/generate", actorHandler.GenerateActorImage)
+					admin.POST("/actor-images/generate", actorHandler.GenerateMissingActorImages)
+					admin.POST("/actors/:id/merge", actorHandler.MergeActors)
+
+					// Tags management
+					admin.PUT("/tags/:id", tagHandler.UpdateTag)
+					admin.POST("/tags/:id/merge", tagHandler.MergeTags)
+					admin.DELETE("/tags/unused", tagHandler.DeleteUnusedTags)
 
 					// Studios management
 					admin.POST("/studios", studioHandler.CreateStudio)
@@ -249,13 +450,57 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 					admin.GET("/porndb/scenes/:id", pornDBHandler.GetScene)
 					admin.GET("/porndb/sites", pornDBHandler.SearchSites)
 					admin.GET("/porndb/sites/:id", pornDBHandler.GetSite)
+					admin.GET("/porndb/cache", pornDBHandler.GetCacheStats)
+					admin.DELETE("/porndb/cache", pornDBHandler.PurgeCache)
+					admin.POST("/porndb/auto-match", pornDBHandler.StartAutoMatch)
+					admin.GET("/porndb/match-reviews", pornDBHandler.ListMatchReviews)
+					admin.POST("/porndb/match-reviews/:sceneId/accept", pornDBHandler.AcceptMatchReview)
+					admin.POST("/porndb/match-reviews/:sceneId/dismiss", pornDBHandler.DismissMatchReview)
+					admin.GET("/porndb/performer-sync", pornDBHandler.GetPerformerSyncStatus)
+					admin.POST("/porndb/performer-sync", pornDBHandler.StartPerformerSync)
+					admin.PUT("/actors/:id/porndb-sync", pornDBHandler.UpdateActorSync)
+					admin.POST("/actors/:id/porndb-sync/run", pornDBHandler.SyncActor)
+					admin.GET("/actors/:id/porndb-syncs", pornDBHandler.ListActorSyncs)
 
 					// Import endpoints
 					admin.POST("/import/scenes", importHandler.ImportScene)
 					admin.POST("/import/markers", importHandler.ImportMarker)
+					admin.GET("/import/urls", remoteImportHandler.List)
+					admin.POST("/import/urls", remoteImportHandler.Submit)
+					admin.GET("/import/urls/:jobID", remoteImportHandler.Get)
+					admin.POST("/import/urls/:jobID/cancel", remoteImportHandler.Cancel)
+					admin.GET("/import/torrents", torrentImportHandler.List)
+					admin.POST("/import/torrents/run", torrentImportHandler.Run)
+					admin.POST("/import/torrents/:id/retry", torrentImportHandler.Retry)
 
 					// Stream statistics
 					admin.GET("/stream-stats", streamStatsHandler.GetStreamStats)
+					admin.GET("/request-stats/slowest", requestStatsHandler.GetSlowestEndpoints)
+
+					// Per-user API usage
+					admin.GET("/usage", apiUsageHandler.GetOverview)
+					admin.GET("/usage/users/:id", apiUsageHandler.GetUserUsage)
+
+					// Duplicate review
+					admin.GET("/duplicates", duplicateHandler.ListDuplicateGroups)
+					admin.GET("/duplicates/:id/compare", duplicateHandler.CompareDuplicateGroup)
+					admin.PUT("/duplicates/:id/status", duplicateHandler.UpdateDuplicateGroupStatus)
+					admin.POST("/duplicates/:id/resolve", duplicateHandler.ResolveDuplicateGroup)
+					admin.POST("/duplicates/checksums", duplicateHandler.FlagChecksumDuplicates)
+					admin.GET("/duplicates/suppressions", duplicateHandler.ListDuplicateSuppressions)
+					admin.DELETE("/duplicates/suppressions/:sceneA/:sceneB", duplicateHandler.DeleteDuplicateSuppression)
+
+					// API keys of all users
+					admin.GET("/api-keys", apiKeyHandler.ListAllAPIKeys)
+					admin.DELETE("/api-keys/:id", apiKeyHandler.AdminRevokeAPIKey)
+
+					// Audit log of destructive and admin actions
+					admin.GET("/audit-log", auditHandler.ListAuditLog)
+
+					// Generated artifact disk usage
+					admin.GET("/artifacts/stats", artifactHandler.GetStats)
+					admin.POST("/artifacts/recalculate", artifactHandler.Recalculate)
+					admin.GET("/release-info", releaseHandler.GetReleaseInfo)
 
 					// Trash management
 					admin.GET("/trash", adminHandler.ListTrash)
@@ -272,5 +517,15 @@ func RegisterRoutes(r *gin.Engine, sceneHandler *handler.SceneHandler, authHandl
 	}
 
 	// Public scene streaming endpoint (outside /api for better access)
-	r.GET("/api/v1/scenes/:id/stream", sceneHandler.StreamScene)
+	// Optional auth attributes bandwidth to the user instead of the client IP
+	r.GET("/api/v1/scenes/:id/stream", middleware.OptionalAuthMiddleware(authService), sceneHandler.StreamScene)
+	r.GET("/api/v1/scenes/:id/stream/transcode", middleware.OptionalAuthMiddleware(authService), sceneHandler.StreamSceneTranscode)
+
+	// Cast profile: signed URLs for Chromecast/DLNA renderers, open to any origin
+	cast := r.Group("/cast", middleware.CastCORS())
+	{
+		cast.GET("/:token/stream", sceneHandler.StreamCast)
+		cast.HEAD("/:token/stream", sceneHandler.StreamCast)
+		cast.OPTIONS("/:token/stream", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	}
 }
@@ -5,7 +5,9 @@ import (
 	"goonhub"
 	"goonhub/internal/api/middleware"
 	"goonhub/internal/api/v1/handler"
+	"goonhub/internal/api/v1/response"
 	"goonhub/internal/config"
+	"goonhub/internal/core"
 	"goonhub/internal/infrastructure/logging"
 	"io"
 	"io/fs"
@@ -21,7 +23,7 @@ import (
 
 // NewShareRouter creates a minimal Gin engine that serves only share-related routes.
 // This is used for the dedicated share server that can be exposed on a separate public domain.
-func NewShareRouter(cfg *config.Config, shareHandler *handler.ShareHandler, ogMiddleware *middleware.OGMiddleware, logger *logging.Logger) *gin.Engine {
+func NewShareRouter(cfg *config.Config, shareHandler *handler.ShareHandler, ogMiddleware *middleware.OGMiddleware, requestStatsService *core.RequestStatsService, shareService *core.ShareService, authService *core.AuthService, mediaSigner *core.MediaSigner, rateLimiter *middleware.IPRateLimiter, logger *logging.Logger) *gin.Engine {
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -46,9 +48,9 @@ func NewShareRouter(cfg *config.Config, shareHandler *handler.ShareHandler, ogMi
 
 	r.Use(middleware.SecurityHeaders(cfg.Environment))
 	r.Use(middleware.RequestID())
-	r.Use(middleware.Logger(logger))
+	r.Use(middleware.Logger(logger, requestStatsService, nil))
 
-	// CORS: allow the share BaseURL origin with read-only methods
+	// CORS: allow the share BaseURL origin with read-only methods, plus POST for unlocking
 	shareOrigins := []string{}
 	if cfg.Sharing.BaseURL != "" {
 		shareOrigins = append(shareOrigins, cfg.Sharing.BaseURL)
@@ -58,7 +60,7 @@ func NewShareRouter(cfg *config.Config, shareHandler *handler.ShareHandler, ogMi
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     shareOrigins,
-		AllowMethods:     []string{"GET", "HEAD", "OPTIONS"},
+		AllowMethods:     []string{"GET", "HEAD", "POST", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
@@ -70,8 +72,9 @@ func NewShareRouter(cfg *config.Config, shareHandler *handler.ShareHandler, ogMi
 		c.JSON(http.StatusOK, gin.H{"status": "ok", "server": "share"})
 	})
 
-	// Thumbnails (needed for poster images and OG tags)
-	r.GET("/thumbnails/:id", func(c *gin.Context) {
+	// Thumbnails (needed for poster images and OG tags). Share pages get signed
+	// thumbnail URLs when media signing is enabled.
+	r.GET("/thumbnails/:id", middleware.MediaAccess(mediaSigner, authService, middleware.MediaSceneIDParam("id")), func(c *gin.Context) {
 		id := c.Param("id")
 		size := c.DefaultQuery("size", "sm")
 		if size != "sm" && size != "lg" {
@@ -79,15 +82,22 @@ func NewShareRouter(cfg *config.Config, shareHandler *handler.ShareHandler, ogMi
 		}
 		path := filepath.Join(cfg.Processing.ThumbnailDir, fmt.Sprintf("%s_thumb_%s.webp", id, size))
 		c.Header("Content-Type", "image/webp")
-		c.Header("Cache-Control", "public, max-age=31536000")
-		c.File(path)
+		if mediaSigner.Enabled() {
+			c.Header("Cache-Control", "private, max-age=31536000")
+		} else {
+			c.Header("Cache-Control", "public, max-age=31536000")
+		}
+		response.File(c, path)
 	})
 
 	// Share API routes
 	shares := r.Group("/api/v1/shares")
 	{
-		shares.GET("/:token", shareHandler.ResolveShareLink)
-		shares.GET("/:token/stream", shareHandler.StreamShareLink)
+		shareAccess := middleware.ShareAccess(shareService, authService)
+		shares.GET("/:token", shareAccess, shareHandler.ResolveShareLink)
+		shares.GET("/:token/stream", shareAccess, shareHandler.StreamShareLink)
+		shares.GET("/:token/thumbnail", shareAccess, shareHandler.ShareThumbnail)
+		shares.POST("/:token/unlock", middleware.RateLimitMiddleware(rateLimiter, logger.Logger), shareHandler.UnlockShareLink)
 	}
 
 	// SPA fallback for /share/* paths only
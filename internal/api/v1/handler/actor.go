@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"goonhub/internal/api/middleware"
 	"goonhub/internal/api/v1/request"
 	"goonhub/internal/api/v1/response"
 	"goonhub/internal/apperrors"
@@ -21,13 +22,15 @@ import (
 
 type ActorHandler struct {
 	Service         *core.ActorService
+	ImageService    *core.ActorImageService
 	ActorImageDir   string
 	MaxItemsPerPage int
 }
 
-func NewActorHandler(service *core.ActorService, actorImageDir string, maxItemsPerPage int) *ActorHandler {
+func NewActorHandler(service *core.ActorService, imageService *core.ActorImageService, actorImageDir string, maxItemsPerPage int) *ActorHandler {
 	return &ActorHandler{
 		Service:         service,
+		ImageService:    imageService,
 		ActorImageDir:   actorImageDir,
 		MaxItemsPerPage: maxItemsPerPage,
 	}
@@ -119,20 +122,58 @@ func (h *ActorHandler) GetActorScenes(c *gin.Context) {
 		return
 	}
 
-	scenes, total, err := h.Service.GetActorScenes(actor.ID, page, limit)
+	scenes, total, err := h.Service.GetActorScenes(actor.ID, requestRole(c), page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get actor scenes"})
 		return
 	}
 
+	listData, ok := sceneListData(c, scenes)
+	if !ok {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":  response.ToSceneListItems(scenes),
+		"data":  listData,
 		"total": total,
 		"page":  page,
 		"limit": limit,
 	})
 }
 
+// GetActorStats returns aggregate statistics of the actor's scenes
+func (h *ActorHandler) GetActorStats(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uuidStr := c.Param("uuid")
+	if _, err := uuid.Parse(uuidStr); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor UUID"})
+		return
+	}
+
+	actor, err := h.Service.GetByUUID(uuidStr)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Actor not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get actor"})
+		return
+	}
+
+	stats, err := h.Service.GetStats(actor.ID, payload.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get actor stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
 func (h *ActorHandler) CreateActor(c *gin.Context) {
 	var req request.CreateActorRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -276,6 +317,30 @@ func (h *ActorHandler) DeleteActor(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// MergeActors merges duplicate actors into the actor, keeping their names as aliases
+func (h *ActorHandler) MergeActors(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor ID"})
+		return
+	}
+
+	var req request.MergeActorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	actor, err := h.Service.Merge(uint(id), req.SourceIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, actor)
+}
+
 var allowedImageExtensions = map[string]bool{
 	".jpg":  true,
 	".jpeg": true,
@@ -351,6 +416,62 @@ func (h *ActorHandler) UploadActorImage(c *gin.Context) {
 	c.JSON(http.StatusOK, actor)
 }
 
+// GetActorUsage returns scenes added and the caller's watches per month for
+// the given actors, or the most used ones
+func (h *ActorHandler) GetActorUsage(c *gin.Context) {
+	q, ok := bindUsageQuery(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.Service.GetUsage(q)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": series})
+}
+
+// GenerateActorImage replaces the actor's image with a frame of one of their
+// scenes, picked automatically unless the body names a scene or timestamp
+func (h *ActorHandler) GenerateActorImage(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor ID"})
+		return
+	}
+
+	var req request.GenerateActorImageRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+
+	actor, err := h.ImageService.Generate(uint(id), req.SceneID, req.Timestamp)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, actor)
+}
+
+// GenerateMissingActorImages starts a job giving every actor without an image
+// one from their scenes
+func (h *ActorHandler) GenerateMissingActorImages(c *gin.Context) {
+	job, err := h.ImageService.Start()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
 func (h *ActorHandler) GetSceneActors(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -3,9 +3,12 @@ package handler
 import (
 	"goonhub/internal/api/middleware"
 	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
 	"goonhub/internal/core"
 	"goonhub/internal/data"
+	"goonhub/internal/streaming"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -16,14 +19,16 @@ type AdminHandler struct {
 	RBACService     *core.RBACService
 	SceneService    *core.SceneService
 	AppSettingsRepo data.AppSettingsRepository
+	StreamManager   *streaming.Manager
 }
 
-func NewAdminHandler(adminService *core.AdminService, rbacService *core.RBACService, sceneService *core.SceneService, appSettingsRepo data.AppSettingsRepository) *AdminHandler {
+func NewAdminHandler(adminService *core.AdminService, rbacService *core.RBACService, sceneService *core.SceneService, appSettingsRepo data.AppSettingsRepository, streamManager *streaming.Manager) *AdminHandler {
 	return &AdminHandler{
 		AdminService:    adminService,
 		RBACService:     rbacService,
 		SceneService:    sceneService,
 		AppSettingsRepo: appSettingsRepo,
+		StreamManager:   streamManager,
 	}
 }
 
@@ -193,6 +198,46 @@ func (h *AdminHandler) SyncRolePermissions(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Role permissions updated successfully"})
 }
 
+// GetContentRestriction returns the part of the library a role is limited to.
+func (h *AdminHandler) GetContentRestriction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid role ID")
+		return
+	}
+
+	restriction, err := h.RBACService.GetContentRestriction(uint(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, restriction)
+}
+
+// UpdateContentRestriction limits a role to storage paths, tags or scene types.
+func (h *AdminHandler) UpdateContentRestriction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid role ID")
+		return
+	}
+
+	var req request.UpdateContentRestrictionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	restriction, err := h.RBACService.SetContentRestriction(uint(id), req.StoragePathIDs, req.TagIDs, req.SceneTypes)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, restriction)
+}
+
 // Trash management endpoints
 
 func (h *AdminHandler) ListTrash(c *gin.Context) {
@@ -309,6 +354,24 @@ func (h *AdminHandler) UpdateAppSettings(c *gin.Context) {
 		return
 	}
 
+	if req.StreamRateLimitKbps < 0 || req.UserStreamRateLimitKbps < 0 || req.GlobalStreamRateLimitKbps < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stream rate limits must not be negative"})
+		return
+	}
+
+	if req.OpenRegistrationRole == "" {
+		req.OpenRegistrationRole = "user"
+	}
+	roles, err := h.RBACService.GetRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load roles"})
+		return
+	}
+	if !slices.ContainsFunc(roles, func(role data.Role) bool { return role.Name == req.OpenRegistrationRole }) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown open registration role: " + req.OpenRegistrationRole})
+		return
+	}
+
 	if err := h.AppSettingsRepo.Upsert(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update app settings"})
 		return
@@ -320,5 +383,7 @@ func (h *AdminHandler) UpdateAppSettings(c *gin.Context) {
 		return
 	}
 
+	h.StreamManager.SetBandwidthLimits(streaming.BandwidthLimitsFromSettings(updated))
+
 	c.JSON(http.StatusOK, updated)
 }
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"goonhub/internal/agent"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxClaimWait caps how long a claim request may long-poll for work
+	maxClaimWait = 60 * time.Second
+	// maxArtifactSize caps a single uploaded artifact
+	maxArtifactSize = 256 << 20
+)
+
+// AgentHandler serves the API used by remote job agents and the admin agent list
+type AgentHandler struct {
+	agentService *core.AgentService
+}
+
+// NewAgentHandler creates a new AgentHandler
+func NewAgentHandler(agentService *core.AgentService) *AgentHandler {
+	return &AgentHandler{
+		agentService: agentService,
+	}
+}
+
+// Register registers a new agent
+// POST /api/v1/agents/register
+func (h *AgentHandler) Register(c *gin.Context) {
+	var req agent.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.agentService.Register(req, c.ClientIP()))
+}
+
+// Heartbeat keeps an agent registered
+// POST /api/v1/agents/:agentID/heartbeat
+func (h *AgentHandler) Heartbeat(c *gin.Context) {
+	resp, err := h.agentService.Heartbeat(c.Param("agentID"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Claim long-polls for the next task the agent can run
+// POST /api/v1/agents/:agentID/claim?wait=30
+func (h *AgentHandler) Claim(c *gin.Context) {
+	waitSeconds, _ := strconv.Atoi(c.DefaultQuery("wait", "30"))
+	wait := min(max(time.Duration(waitSeconds)*time.Second, 0), maxClaimWait)
+
+	task, err := h.agentService.Claim(c.Request.Context(), c.Param("agentID"), wait)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if task == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// DownloadSource streams the task's source video to the agent (supports range requests)
+// GET /api/v1/agents/:agentID/tasks/:taskID/source
+func (h *AgentHandler) DownloadSource(c *gin.Context) {
+	path, err := h.agentService.SourcePath(c.Param("agentID"), c.Param("taskID"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.File(path)
+}
+
+// ReportProgress records task progress
+// POST /api/v1/agents/:agentID/tasks/:taskID/progress
+func (h *AgentHandler) ReportProgress(c *gin.Context) {
+	var req agent.ProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	cancelled, err := h.agentService.ReportProgress(c.Param("agentID"), c.Param("taskID"), req.Progress)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, agent.ProgressResponse{Cancelled: cancelled})
+}
+
+// UploadArtifact stores one output file of a task
+// PUT /api/v1/agents/:agentID/tasks/:taskID/artifacts/:name
+func (h *AgentHandler) UploadArtifact(c *gin.Context) {
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxArtifactSize)
+	if err := h.agentService.StoreArtifact(c.Param("agentID"), c.Param("taskID"), c.Param("name"), body); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CompleteTask finishes a task with its result or error
+// POST /api/v1/agents/:agentID/tasks/:taskID/complete
+func (h *AgentHandler) CompleteTask(c *gin.Context) {
+	var req agent.TaskResult
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.agentService.Complete(c.Param("agentID"), c.Param("taskID"), req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListAgents returns registered agents and their health
+// GET /api/v1/admin/agents
+func (h *AgentHandler) ListAgents(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": h.agentService.Enabled(),
+		"data":    h.agentService.ListAgents(),
+	})
+}
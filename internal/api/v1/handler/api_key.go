@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *core.APIKeyService
+	rbacService   *core.RBACService
+}
+
+func NewAPIKeyHandler(apiKeyService *core.APIKeyService, rbacService *core.RBACService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		rbacService:   rbacService,
+	}
+}
+
+// ListAPIKeys returns the current user's API keys
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	keys, err := h.apiKeyService.List(userPayload.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": keys})
+}
+
+// ListAPIKeyPermissions returns the permissions the current user can grant a key
+func (h *APIKeyHandler) ListAPIKeyPermissions(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": h.rbacService.RolePermissions(userPayload.Role)})
+}
+
+// CreateAPIKey creates an API key; the key itself is only returned by this call
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req request.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	created, err := h.apiKeyService.Create(userPayload.UserID, userPayload.Role, req.Name, req.Permissions, req.ExpiresInDays)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// RevokeAPIKey revokes one of the current user's API keys
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(userPayload.UserID, uint(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+// ListAllAPIKeys returns every user's API keys
+func (h *APIKeyHandler) ListAllAPIKeys(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, 100)
+
+	keys, total, err := h.apiKeyService.ListAll(page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  keys,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// AdminRevokeAPIKey revokes any user's API key
+func (h *APIKeyHandler) AdminRevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(0, uint(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
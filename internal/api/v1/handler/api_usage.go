@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIUsageHandler struct {
+	apiUsageService *core.APIUsageService
+}
+
+func NewAPIUsageHandler(apiUsageService *core.APIUsageService) *APIUsageHandler {
+	return &APIUsageHandler{
+		apiUsageService: apiUsageService,
+	}
+}
+
+// GetMyUsage returns the calling user's API usage over the last ?days= days
+func (h *APIUsageHandler) GetMyUsage(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	report, err := h.apiUsageService.GetUserUsage(payload.UserID, days)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetUserUsage returns any user's API usage over the last ?days= days
+func (h *APIUsageHandler) GetUserUsage(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	report, err := h.apiUsageService.GetUserUsage(uint(userID), days)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetOverview lists the busiest users over the last ?days= days
+func (h *APIUsageHandler) GetOverview(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	overview, err := h.apiUsageService.GetOverview(days, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArtifactHandler struct {
+	artifactService *core.ArtifactService
+}
+
+func NewArtifactHandler(artifactService *core.ArtifactService) *ArtifactHandler {
+	return &ArtifactHandler{
+		artifactService: artifactService,
+	}
+}
+
+// GetStats returns generated artifact disk usage per storage path and the metadata quota state
+func (h *ArtifactHandler) GetStats(c *gin.Context) {
+	stats, err := h.artifactService.GetStats()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// Recalculate re-measures artifact sizes for all scenes in the background
+func (h *ArtifactHandler) Recalculate(c *gin.Context) {
+	go func() {
+		// Errors are logged in RecalculateAll
+		_ = h.artifactService.RecalculateAll()
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Artifact size recalculation started"})
+}
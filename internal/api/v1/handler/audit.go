@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *core.AuditService
+}
+
+func NewAuditHandler(auditService *core.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListAuditLog returns audit log entries, newest first. Filters: user_id, action,
+// resource_type, resource_id, and since/until as RFC 3339 times or YYYY-MM-DD dates
+// (until is exclusive).
+func (h *AuditHandler) ListAuditLog(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	page, limit = clampPagination(page, limit, 50, 200)
+
+	filter := data.AuditFilter{
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+	}
+	if raw := c.Query("user_id"); raw != "" {
+		userID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = uint(userID)
+	}
+	for param, dst := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		t, err := parseAuditTime(raw)
+		if err != nil {
+			response.BadRequest(c, "Invalid "+param+": use an RFC 3339 time or YYYY-MM-DD")
+			return
+		}
+		*dst = &t
+	}
+
+	entries, total, err := h.auditService.List(filter, page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  entries,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+func parseAuditTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, raw)
+}
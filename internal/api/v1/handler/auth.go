@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"goonhub/internal/api/middleware"
 	"goonhub/internal/api/v1/request"
 	"goonhub/internal/api/v1/response"
+	"goonhub/internal/apperrors"
 	"goonhub/internal/core"
+	"goonhub/internal/data"
 	"net/http"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,28 +22,33 @@ const (
 )
 
 type AuthHandler struct {
-	AuthService   *core.AuthService
-	UserService   *core.UserService
-	TokenDuration time.Duration
-	SecureCookies bool // Set to true in production (HTTPS only)
+	AuthService        *core.AuthService
+	UserService        *core.UserService
+	PrivacyLockService *core.PrivacyLockService
+	TokenDuration      time.Duration
+	SecureCookies      bool // Set to true in production (HTTPS only)
+	AuditService       *core.AuditService
+	SessionService     *core.SessionService
 }
 
-func NewAuthHandler(authService *core.AuthService, userService *core.UserService) *AuthHandler {
+func NewAuthHandler(authService *core.AuthService, userService *core.UserService, privacyLockService *core.PrivacyLockService) *AuthHandler {
 	return &AuthHandler{
-		AuthService:   authService,
-		UserService:   userService,
-		TokenDuration: 24 * time.Hour, // Default, should match config
-		SecureCookies: false,          // Will be set based on environment
+		AuthService:        authService,
+		UserService:        userService,
+		PrivacyLockService: privacyLockService,
+		TokenDuration:      24 * time.Hour, // Default, should match config
+		SecureCookies:      false,          // Will be set based on environment
 	}
 }
 
 // NewAuthHandlerWithConfig creates an auth handler with explicit configuration
-func NewAuthHandlerWithConfig(authService *core.AuthService, userService *core.UserService, tokenDuration time.Duration, secureCookies bool) *AuthHandler {
+func NewAuthHandlerWithConfig(authService *core.AuthService, userService *core.UserService, privacyLockService *core.PrivacyLockService, tokenDuration time.Duration, secureCookies bool) *AuthHandler {
 	return &AuthHandler{
-		AuthService:   authService,
-		UserService:   userService,
-		TokenDuration: tokenDuration,
-		SecureCookies: secureCookies,
+		AuthService:        authService,
+		UserService:        userService,
+		PrivacyLockService: privacyLockService,
+		TokenDuration:      tokenDuration,
+		SecureCookies:      secureCookies,
 	}
 }
 
@@ -51,36 +60,81 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	token, user, err := h.AuthService.Login(req.Username, req.Password)
+	var twoFactorErr *core.TwoFactorRequiredError
+	if errors.As(err, &twoFactorErr) {
+		c.JSON(http.StatusOK, response.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    twoFactorErr.ChallengeToken,
+		})
+		return
+	}
 	if err != nil {
+		h.recordLogin(c, core.AuditActionLoginFailed, req.Username, nil)
 		// SECURITY: Return generic error to prevent user enumeration and timing attacks
 		// Do not expose internal error details (lockout status, user existence, etc.)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	// Set HTTP-only secure cookie
-	// SECURITY: Token is ONLY transmitted via cookie, never in response body
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     AuthCookieName,
-		Value:    token,
-		Path:     AuthCookiePath,
-		MaxAge:   int(h.TokenDuration.Seconds()),
-		HttpOnly: true,                    // Prevent JavaScript access (XSS protection)
-		Secure:   h.SecureCookies,         // Only send over HTTPS in production
-		SameSite: http.SameSiteStrictMode, // CSRF protection
-	})
+	h.startSession(c, token, user)
+}
+
+// LoginTwoFactor finishes a login that needed a two-factor code
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req request.LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	token, user, err := h.AuthService.CompleteTwoFactorLogin(req.ChallengeToken, req.Code)
+	if err != nil {
+		if errors.Is(err, core.ErrInvalidCredentials) {
+			h.recordLogin(c, core.AuditActionLoginFailed, "", nil)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+
+	h.startSession(c, token, user)
+}
+
+// startSession sets the auth cookie for a completed login and returns the user
+func (h *AuthHandler) startSession(c *gin.Context, token string, user *data.User) {
+	// The password was just entered, so the new session starts unlocked
+	h.PrivacyLockService.StartSession(token)
+	h.setAuthCookie(c, token)
+	h.SessionService.Track(token, c.Request.UserAgent(), c.ClientIP())
+	h.recordLogin(c, core.AuditActionLogin, user.Username, &user.ID)
 
 	resp := response.AuthResponse{
 		User: response.UserSummary{
-			ID:       user.ID,
-			Username: user.Username,
-			Role:     user.Role,
+			ID:                     user.ID,
+			Username:               user.Username,
+			Role:                   user.Role,
+			TwoFactorSetupRequired: h.AuthService.RequiresTwoFactorSetup(user),
 		},
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
+// recordLogin adds a login attempt to the audit log. Logins happen before the
+// audit middleware knows who the user is, so they are recorded here instead.
+func (h *AuthHandler) recordLogin(c *gin.Context, action, username string, userID *uint) {
+	h.AuditService.Record(data.AuditEntry{
+		Action:       action,
+		ResourceType: "user",
+		UserID:       userID,
+		Username:     username,
+		Method:       c.Request.Method,
+		Route:        c.FullPath(),
+		IPAddress:    c.ClientIP(),
+	})
+}
+
 func (h *AuthHandler) Me(c *gin.Context) {
 	user, exists := c.Get("user")
 	if !exists {
@@ -95,29 +149,17 @@ func (h *AuthHandler) Me(c *gin.Context) {
 	}
 
 	resp := response.UserSummary{
-		ID:       userPayload.UserID,
-		Username: userPayload.Username,
-		Role:     userPayload.Role,
+		ID:                     userPayload.UserID,
+		Username:               userPayload.Username,
+		Role:                   userPayload.Role,
+		TwoFactorSetupRequired: userPayload.TwoFactorSetup,
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// Try to get token from cookie first, then from Authorization header
-	token := ""
-	if cookie, err := c.Cookie(AuthCookieName); err == nil && cookie != "" {
-		token = cookie
-	} else {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			token = strings.TrimPrefix(authHeader, "Bearer ")
-			if token == authHeader {
-				token = "" // Not a Bearer token
-			}
-		}
-	}
-
+	token := middleware.TokenFromRequest(c)
 	if token == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No token provided"})
 		return
@@ -127,6 +169,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
 		return
 	}
+	h.PrivacyLockService.EndSession(token)
 
 	// Clear the auth cookie
 	http.SetCookie(c.Writer, &http.Cookie{
@@ -141,3 +184,290 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
+
+// ListSessions returns the current user's active sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	sessions, err := h.SessionService.List(userPayload.UserID, middleware.TokenFromRequest(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": sessions})
+}
+
+// RevokeSession signs out one of the current user's sessions
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	if err := h.SessionService.Revoke(userPayload.UserID, uint(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// RevokeOtherSessions signs out every session of the current user but this one
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	revoked, err := h.SessionService.RevokeOthers(userPayload.UserID, middleware.TokenFromRequest(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
+
+// GetPrivacyLock returns the privacy lock state of the current session
+func (h *AuthHandler) GetPrivacyLock(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	status, err := h.PrivacyLockService.Status(userPayload.UserID, middleware.TokenFromRequest(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// UnlockPrivacyLock unlocks the current session with the user's PIN
+func (h *AuthHandler) UnlockPrivacyLock(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req request.UnlockPrivacyLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.PrivacyLockService.Unlock(userPayload.UserID, middleware.TokenFromRequest(c), req.PIN); err != nil {
+		if errors.Is(err, apperrors.ErrPINAttemptsExceeded) {
+			h.clearAuthCookie(c)
+		}
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Unlocked"})
+}
+
+// LockPrivacyLock locks the current session immediately
+func (h *AuthHandler) LockPrivacyLock(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if err := h.PrivacyLockService.Lock(userPayload.UserID, middleware.TokenFromRequest(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Locked"})
+}
+
+// UpdatePrivacyLock enables, updates or disables the user's privacy lock
+func (h *AuthHandler) UpdatePrivacyLock(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req request.UpdatePrivacyLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if req.Enabled {
+		err = h.PrivacyLockService.Configure(userPayload.UserID, req.CurrentPassword, req.PIN, req.IdleTimeoutMinutes)
+	} else {
+		err = h.PrivacyLockService.Disable(userPayload.UserID, req.CurrentPassword)
+	}
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Enabling the lock from an active session must not lock that session right away
+	h.PrivacyLockService.StartSession(middleware.TokenFromRequest(c))
+
+	status, err := h.PrivacyLockService.Status(userPayload.UserID, middleware.TokenFromRequest(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// GetTwoFactor returns the current user's two-factor authentication status
+func (h *AuthHandler) GetTwoFactor(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	status, err := h.AuthService.TwoFactorStatus(userPayload.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// SetupTwoFactor generates a new secret to add to an authenticator app
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req request.TwoFactorPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	setup, err := h.AuthService.BeginTwoFactorSetup(userPayload.UserID, req.CurrentPassword)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, setup)
+}
+
+// EnableTwoFactor confirms the setup with a code and returns the recovery codes
+func (h *AuthHandler) EnableTwoFactor(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req request.EnableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	codes, err := h.AuthService.EnableTwoFactor(userPayload.UserID, req.Code)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// A session limited to two-factor setup gets a full session in its place
+	if userPayload.TwoFactorSetup {
+		oldToken := middleware.TokenFromRequest(c)
+		token, err := h.AuthService.ReissueToken(oldToken, userPayload.UserID)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		h.PrivacyLockService.EndSession(oldToken)
+		h.PrivacyLockService.StartSession(token)
+		h.SessionService.Track(token, c.Request.UserAgent(), c.ClientIP())
+		h.setAuthCookie(c, token)
+	}
+
+	c.JSON(http.StatusOK, response.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor turns off two-factor authentication for the current user
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req request.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.AuthService.DisableTwoFactor(userPayload.UserID, req.CurrentPassword, req.Code); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req request.TwoFactorPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	codes, err := h.AuthService.RegenerateRecoveryCodes(userPayload.UserID, req.CurrentPassword)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *AuthHandler) setAuthCookie(c *gin.Context, token string) {
+	// Set HTTP-only secure cookie
+	// SECURITY: Token is ONLY transmitted via cookie, never in response body
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     AuthCookieName,
+		Value:    token,
+		Path:     AuthCookiePath,
+		MaxAge:   int(h.TokenDuration.Seconds()),
+		HttpOnly: true,                    // Prevent JavaScript access (XSS protection)
+		Secure:   h.SecureCookies,         // Only send over HTTPS in production
+		SameSite: http.SameSiteStrictMode, // CSRF protection
+	})
+}
+
+func (h *AuthHandler) clearAuthCookie(c *gin.Context) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     AuthCookieName,
+		Value:    "",
+		Path:     AuthCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.SecureCookies,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
 	"goonhub/internal/core"
 	"goonhub/internal/data"
 	"net/http"
@@ -109,3 +111,56 @@ func (h *DLQHandler) AbandonDLQ(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "DLQ entry abandoned", "job_id": jobID})
 }
+
+// RequeueDLQ resubmits every DLQ entry matching the filter
+func (h *DLQHandler) RequeueDLQ(c *gin.Context) {
+	if h.dlqService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "DLQ service not available"})
+		return
+	}
+
+	var req request.DLQBulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	result, err := h.dlqService.RequeueMatching(dlqBulkFilter(req))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// PurgeDLQ deletes every DLQ entry matching the filter
+func (h *DLQHandler) PurgeDLQ(c *gin.Context) {
+	if h.dlqService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "DLQ service not available"})
+		return
+	}
+
+	var req request.DLQBulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	purged, err := h.dlqService.PurgeMatching(dlqBulkFilter(req))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "DLQ entries purged", "purged": purged})
+}
+
+func dlqBulkFilter(req request.DLQBulkRequest) core.DLQBulkFilter {
+	return core.DLQBulkFilter{
+		Status:        req.Status,
+		Phase:         req.Phase,
+		ErrorContains: req.ErrorContains,
+		OlderThan:     req.OlderThan,
+	}
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"archive/zip"
+	"fmt"
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"goonhub/internal/streaming"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DownloadHandler struct {
+	downloadService *core.DownloadService
+	sceneService    *core.SceneService
+	streamManager   *streaming.Manager
+}
+
+func NewDownloadHandler(downloadService *core.DownloadService, sceneService *core.SceneService, streamManager *streaming.Manager) *DownloadHandler {
+	return &DownloadHandler{
+		downloadService: downloadService,
+		sceneService:    sceneService,
+		streamManager:   streamManager,
+	}
+}
+
+// DownloadScene sends a scene's original file as an attachment. Range requests
+// are supported so interrupted downloads can be resumed.
+func (h *DownloadHandler) DownloadScene(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+		return
+	}
+
+	if err := h.sceneService.CheckSceneAccess(uint(id), payload.Role); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	scene, err := h.downloadService.GetDownloadableScene(uint(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	file, err := os.Open(scene.StoredPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open scene file"})
+		return
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to access scene file"})
+		return
+	}
+
+	filename := core.DownloadFilename(scene)
+	mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(scene.StoredPath)))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	c.Header("Content-Type", mimeType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Header("Cache-Control", "private, no-transform")
+
+	// Resumed downloads request a later range; only count the request that starts the file
+	if c.Request.Method == http.MethodGet && isDownloadStart(c.GetHeader("Range")) {
+		h.downloadService.RecordDownload(payload.UserID, scene.ID)
+	}
+
+	buf := h.streamManager.BufferPool().Get()
+	defer h.streamManager.BufferPool().Put(buf)
+
+	stream := h.streamManager.OpenStream(c.Request.Context(), payload.UserID, c.ClientIP())
+	defer stream.Close()
+
+	streaming.ServeVideo(stream.Wrap(c.Writer), c.Request, filename, fileInfo.ModTime(), file, buf)
+}
+
+// DownloadBundle streams several scenes' original files as one uncompressed zip.
+// Bundles are generated on the fly and cannot be resumed.
+func (h *DownloadHandler) DownloadBundle(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var sceneIDs []uint
+	for _, part := range strings.Split(c.Query("ids"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID: " + part})
+			return
+		}
+		if err := h.sceneService.CheckSceneAccess(uint(id), payload.Role); err != nil {
+			response.Error(c, err)
+			return
+		}
+		sceneIDs = append(sceneIDs, uint(id))
+	}
+
+	scenes, err := h.downloadService.GetBundleScenes(sceneIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	ids := make([]uint, len(scenes))
+	for i, scene := range scenes {
+		ids[i] = scene.ID
+	}
+	h.downloadService.RecordDownload(payload.UserID, ids...)
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("goonhub-%d-scenes.zip", len(scenes)),
+	}))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	buf := h.streamManager.BufferPool().Get()
+	defer h.streamManager.BufferPool().Put(buf)
+
+	stream := h.streamManager.OpenStream(c.Request.Context(), payload.UserID, c.ClientIP())
+	defer stream.Close()
+
+	// Videos are already compressed, so entries are stored as-is
+	zw := zip.NewWriter(stream.Wrap(c.Writer))
+	names := make(map[string]int, len(scenes))
+	for i := range scenes {
+		if err := writeZipEntry(zw, core.DownloadFilename(&scenes[i]), scenes[i].StoredPath, names, buf); err != nil {
+			// Headers are already sent; the client is left with a truncated zip
+			c.Error(fmt.Errorf("zip bundle scene %d: %w", scenes[i].ID, err)) //nolint:errcheck
+			return
+		}
+	}
+	zw.Close() //nolint:errcheck
+}
+
+// ListDownloads returns the current user's downloaded scenes with download counts
+func (h *DownloadHandler) ListDownloads(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	entries, total, err := h.downloadService.ListUserDownloads(payload.UserID, page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.NewPaginatedResponse(response.ToDownloadEntriesResponse(entries), page, limit, total))
+}
+
+// writeZipEntry copies one file into the zip, suffixing names already used in the bundle.
+func writeZipEntry(zw *zip.Writer, name, path string, names map[string]int, buf []byte) error {
+	names[name]++
+	if n := names[name]; n > 1 {
+		ext := filepath.Ext(name)
+		name = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), n, ext)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: info.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.CopyBuffer(w, file, buf)
+	return err
+}
+
+// isDownloadStart reports whether a request fetches the file from its first byte.
+func isDownloadStart(rangeHeader string) bool {
+	if rangeHeader == "" {
+		return true
+	}
+	spec := strings.TrimSpace(strings.TrimPrefix(rangeHeader, "bytes="))
+	return strings.HasPrefix(spec, "0-")
+}
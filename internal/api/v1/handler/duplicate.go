@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DuplicateHandler struct {
+	duplicateService *core.DuplicateService
+}
+
+func NewDuplicateHandler(duplicateService *core.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{
+		duplicateService: duplicateService,
+	}
+}
+
+// ListDuplicateGroups returns flagged duplicate groups with their scenes, optionally filtered by status
+func (h *DuplicateHandler) ListDuplicateGroups(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, 100)
+	status := c.DefaultQuery("status", "")
+
+	groups, total, err := h.duplicateService.ListGroups(status, page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  groups,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// UpdateDuplicateGroupStatus resolves, dismisses or reopens a duplicate group
+func (h *DuplicateHandler) UpdateDuplicateGroupStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duplicate group ID"})
+		return
+	}
+
+	var req request.UpdateDuplicateGroupStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.duplicateService.UpdateGroupStatus(uint(id), req.Status); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Duplicate group updated", "status": req.Status})
+}
+
+// ResolveDuplicateGroup resolves a duplicate group keeping the winner scene, optionally
+// merging the other scenes into it and moving them to trash
+func (h *DuplicateHandler) ResolveDuplicateGroup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duplicate group ID"})
+		return
+	}
+
+	var req request.ResolveDuplicateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	result, err := h.duplicateService.ResolveGroup(uint(id), req.WinnerSceneID, req.Action)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// FlagChecksumDuplicates groups every set of scenes whose files have the same checksum
+func (h *DuplicateHandler) FlagChecksumDuplicates(c *gin.Context) {
+	flagged, err := h.duplicateService.FlagFileHashDuplicates()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flagged_groups": flagged})
+}
+
+// CompareDuplicateGroup returns side-by-side comparison data for a duplicate group
+func (h *DuplicateHandler) CompareDuplicateGroup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duplicate group ID"})
+		return
+	}
+
+	comparison, err := h.duplicateService.CompareGroup(uint(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// ListDuplicateSuppressions returns scene pairs confirmed as not duplicates, optionally for one scene
+func (h *DuplicateHandler) ListDuplicateSuppressions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, 100)
+	sceneID, _ := strconv.ParseUint(c.DefaultQuery("scene_id", "0"), 10, 32)
+
+	suppressions, total, err := h.duplicateService.ListSuppressions(uint(sceneID), page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  suppressions,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// DeleteDuplicateSuppression lets a pair of scenes be flagged as duplicates again
+func (h *DuplicateHandler) DeleteDuplicateSuppression(c *gin.Context) {
+	sceneIDA, errA := strconv.ParseUint(c.Param("sceneA"), 10, 32)
+	sceneIDB, errB := strconv.ParseUint(c.Param("sceneB"), 10, 32)
+	if errA != nil || errB != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+		return
+	}
+
+	if err := h.duplicateService.DeleteSuppression(uint(sceneIDA), uint(sceneIDB)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Duplicate suppression deleted"})
+}
@@ -4,19 +4,23 @@ import (
 	"goonhub/internal/api/v1/request"
 	"goonhub/internal/api/v1/response"
 	"goonhub/internal/core"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ExplorerHandler struct {
-	Service *core.ExplorerService
+	Service          *core.ExplorerService
+	MigrationService *core.StorageMigrationService
 }
 
-func NewExplorerHandler(service *core.ExplorerService) *ExplorerHandler {
+func NewExplorerHandler(service *core.ExplorerService, migrationService *core.StorageMigrationService) *ExplorerHandler {
 	return &ExplorerHandler{
-		Service: service,
+		Service:          service,
+		MigrationService: migrationService,
 	}
 }
 
@@ -59,7 +63,7 @@ func (h *ExplorerHandler) GetFolderContents(c *gin.Context) {
 		}
 	}
 
-	contents, err := h.Service.GetFolderContents(uint(storagePathID), folderPath, page, limit)
+	contents, err := h.Service.GetFolderContents(uint(storagePathID), folderPath, requestRole(c), page, limit)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -139,6 +143,50 @@ func (h *ExplorerHandler) BulkUpdateStudio(c *gin.Context) {
 	})
 }
 
+// BulkUpdateMetadata applies a partial metadata update to multiple scenes at
+// once. Nothing is changed when any scene fails, and the per-scene results
+// come back with 422 Unprocessable Entity.
+func (h *ExplorerHandler) BulkUpdateMetadata(c *gin.Context) {
+	var req request.BulkUpdateMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	var releaseDate *time.Time
+	if req.ReleaseDate != nil {
+		parsed := time.Time{} // an empty string clears the date
+		if *req.ReleaseDate != "" {
+			var err error
+			if parsed, err = time.Parse("2006-01-02", *req.ReleaseDate); err != nil {
+				response.BadRequest(c, "Invalid release_date format, expected YYYY-MM-DD")
+				return
+			}
+		}
+		releaseDate = &parsed
+	}
+
+	result, err := h.Service.BulkUpdateMetadata(core.BulkUpdateMetadataRequest{
+		SceneIDs:      req.SceneIDs,
+		TitleTemplate: req.TitleTemplate,
+		Description:   req.Description,
+		ReleaseDate:   releaseDate,
+		Origin:        req.Origin,
+		Type:          req.Type,
+		StudioID:      req.StudioID,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if !result.Applied {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	response.OK(c, result)
+}
+
 // GetFolderSceneIDs returns all scene IDs in a folder, with optional filters
 func (h *ExplorerHandler) GetFolderSceneIDs(c *gin.Context) {
 	var req request.FolderSceneIDsRequest
@@ -155,6 +203,7 @@ func (h *ExplorerHandler) GetFolderSceneIDs(c *gin.Context) {
 		TagIDs:        req.TagIDs,
 		Actors:        req.Actors,
 		HasPornDBID:   req.HasPornDBID,
+		Role:          requestRole(c),
 	})
 	if err != nil {
 		response.Error(c, err)
@@ -175,7 +224,7 @@ func (h *ExplorerHandler) BulkDeleteScenes(c *gin.Context) {
 		return
 	}
 
-	deleted, err := h.Service.BulkDeleteScenes(req.SceneIDs, req.Permanent)
+	deleted, err := h.Service.BulkDeleteScenes(req.SceneIDs, req.Permanent, req.Force)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -187,6 +236,73 @@ func (h *ExplorerHandler) BulkDeleteScenes(c *gin.Context) {
 	})
 }
 
+// MoveScenes moves scene files on disk to a folder of a storage path
+func (h *ExplorerHandler) MoveScenes(c *gin.Context) {
+	var req request.MoveScenesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	result, err := h.Service.MoveScenes(core.MoveScenesRequest{
+		SceneIDs:      req.SceneIDs,
+		StoragePathID: req.StoragePathID,
+		FolderPath:    req.FolderPath,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// MigrateScenes moves scenes to another storage path in the background,
+// verifying each copy before removing its source
+func (h *ExplorerHandler) MigrateScenes(c *gin.Context) {
+	var req request.MigrateScenesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	job, err := h.MigrationService.Start(core.StorageMigrationRequest{
+		SceneIDs:            req.SceneIDs,
+		SourceStoragePathID: req.SourceStoragePathID,
+		FolderPath:          req.FolderPath,
+		StoragePathID:       req.StoragePathID,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// RenameSceneFile renames a scene's file on disk
+func (h *ExplorerHandler) RenameSceneFile(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	var req request.RenameSceneFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	scene, err := h.Service.RenameSceneFile(uint(id), req.Filename)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, scene)
+}
+
 // GetScenesMatchInfo returns minimal scene data for bulk PornDB matching
 func (h *ExplorerHandler) GetScenesMatchInfo(c *gin.Context) {
 	var req request.ScenesMatchInfoRequest
@@ -195,7 +311,7 @@ func (h *ExplorerHandler) GetScenesMatchInfo(c *gin.Context) {
 		return
 	}
 
-	scenes, err := h.Service.GetScenesMatchInfo(req.SceneIDs)
+	scenes, err := h.Service.GetScenesMatchInfo(req.SceneIDs, requestRole(c))
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -226,6 +342,7 @@ func (h *ExplorerHandler) SearchInFolder(c *gin.Context) {
 		HasPornDBID:   req.HasPornDBID,
 		Page:          req.Page,
 		Limit:         req.Limit,
+		Role:          requestRole(c),
 	})
 	if err != nil {
 		response.Error(c, err)
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/apperrors"
+	"goonhub/internal/core"
+	"goonhub/internal/graphql"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLHandler struct {
+	graphQLService *core.GraphQLService
+}
+
+func NewGraphQLHandler(graphQLService *core.GraphQLService) *GraphQLHandler {
+	return &GraphQLHandler{
+		graphQLService: graphQLService,
+	}
+}
+
+// Execute runs a GraphQL query. Like other GraphQL servers it answers 200 with
+// an errors list for query and field errors, so clients read partial data.
+func (h *GraphQLHandler) Execute(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, apperrors.NewUnauthorizedError("authentication required"))
+		return
+	}
+
+	var req graphql.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+	if req.Query == "" {
+		response.BadRequest(c, "query is required")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.graphQLService.Execute(c.Request.Context(), payload.UserID, payload.Role, req))
+}
+
+// Schema returns the schema in the GraphQL schema definition language.
+func (h *GraphQLHandler) Schema(c *gin.Context) {
+	c.String(http.StatusOK, h.graphQLService.SDL())
+}
@@ -28,7 +28,7 @@ func (h *HomepageHandler) GetHomepageData(c *gin.Context) {
 		return
 	}
 
-	data, err := h.homepageService.GetHomepageData(userPayload.UserID)
+	data, err := h.homepageService.GetHomepageData(userPayload.UserID, userPayload.Role)
 	if err != nil {
 		response.Error(c, apperrors.NewInternalError("failed to fetch homepage data", err))
 		return
@@ -50,7 +50,7 @@ func (h *HomepageHandler) GetSectionData(c *gin.Context) {
 		return
 	}
 
-	data, err := h.homepageService.GetSectionData(userPayload.UserID, sectionID)
+	data, err := h.homepageService.GetSectionData(userPayload.UserID, userPayload.Role, sectionID)
 	if err != nil {
 		// Check if section not found
 		if strings.Contains(err.Error(), "section not found") {
@@ -163,11 +163,12 @@ func (h *ImportHandler) ImportMarker(c *gin.Context) {
 	}
 
 	marker := &data.UserSceneMarker{
-		UserID:    req.UserID,
-		SceneID:   req.SceneID,
-		Timestamp: req.Timestamp,
-		Label:     req.Label,
-		Color:     color,
+		UserID:       req.UserID,
+		SceneID:      req.SceneID,
+		Timestamp:    req.Timestamp,
+		EndTimestamp: req.EndTimestamp,
+		Label:        req.Label,
+		Color:        color,
 	}
 
 	if err := h.markerRepo.Create(marker); err != nil {
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"goonhub/internal/api/v1/request"
 	"goonhub/internal/api/v1/response"
 	"goonhub/internal/api/v1/validators"
 	"goonhub/internal/core"
@@ -14,18 +15,30 @@ import (
 
 // JobHandler handles job-related requests
 type JobHandler struct {
-	jobHistoryService *core.JobHistoryService
-	processingService *core.SceneProcessingService
+	jobHistoryService       *core.JobHistoryService
+	processingService       *core.SceneProcessingService
+	remoteImportService     *core.RemoteImportService
+	storageMigrationService *core.StorageMigrationService
+	jobQueueFeeder          *core.JobQueueFeeder
+	retentionWorker         *core.JobHistoryRetentionWorker
 }
 
 // NewJobHandler creates a new JobHandler
 func NewJobHandler(
 	jobHistoryService *core.JobHistoryService,
 	processingService *core.SceneProcessingService,
+	remoteImportService *core.RemoteImportService,
+	storageMigrationService *core.StorageMigrationService,
+	jobQueueFeeder *core.JobQueueFeeder,
+	retentionWorker *core.JobHistoryRetentionWorker,
 ) *JobHandler {
 	return &JobHandler{
-		jobHistoryService: jobHistoryService,
-		processingService: processingService,
+		jobHistoryService:       jobHistoryService,
+		processingService:       processingService,
+		remoteImportService:     remoteImportService,
+		storageMigrationService: storageMigrationService,
+		jobQueueFeeder:          jobQueueFeeder,
+		retentionWorker:         retentionWorker,
 	}
 }
 
@@ -45,10 +58,24 @@ func (h *JobHandler) ListJobs(c *gin.Context) {
 		limit = 100
 	}
 
-	jobs, total, err := h.jobHistoryService.ListJobs(page, limit, status)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
-		return
+	// Any cursor parameter, even an empty one for the first page, pages by cursor
+	cursor, useCursor := c.GetQuery("cursor")
+	var jobs []data.JobHistory
+	var total int64
+	var nextCursor string
+	var err error
+	if useCursor {
+		jobs, nextCursor, err = h.jobHistoryService.ListJobsAfter(cursor, limit, status)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+	} else {
+		jobs, total, err = h.jobHistoryService.ListJobs(page, limit, status)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list jobs"})
+			return
+		}
 	}
 
 	activeJobs, err := h.jobHistoryService.ListActiveJobs()
@@ -75,10 +102,8 @@ func (h *JobHandler) ListJobs(c *gin.Context) {
 	// avoiding the race-prone (DB count - channel size) calculation.
 	pendingByPhase, _ := h.jobHistoryService.CountPendingByPhase()
 
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"data":         jobs,
-		"total":        total,
-		"page":         page,
 		"limit":        limit,
 		"active_count": len(verifiedActive),
 		"active_jobs":  verifiedActive,
@@ -94,8 +119,18 @@ func (h *JobHandler) ListJobs(c *gin.Context) {
 			"metadata_pending":  pendingByPhase["metadata"],
 			"thumbnail_pending": pendingByPhase["thumbnail"],
 			"sprites_pending":   pendingByPhase["sprites"],
+			"ffmpeg_running":    queueStatus.FFmpegRunning,
+			"ffmpeg_waiting":    queueStatus.FFmpegWaiting,
+			"ffmpeg_limit":      queueStatus.FFmpegLimit,
 		},
-	})
+	}
+	if useCursor {
+		resp["next_cursor"] = nextCursor
+	} else {
+		resp["total"] = total
+		resp["page"] = page
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 // TriggerPhase manually triggers a processing phase for a scene
@@ -140,6 +175,7 @@ func (h *JobHandler) TriggerBulkPhase(c *gin.Context) {
 		Mode        string `json:"mode"`
 		ForceTarget string `json:"force_target"`
 		SceneIDs    []uint `json:"scene_ids"`
+		DryRun      bool   `json:"dry_run"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
@@ -172,6 +208,24 @@ func (h *JobHandler) TriggerBulkPhase(c *gin.Context) {
 		return
 	}
 
+	if req.DryRun {
+		preview, err := h.processingService.PreviewBulkPhase(req.Phase, req.Mode, req.SceneIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":         fmt.Sprintf("Dry run: bulk %s phase would queue %d scenes (%s mode)", req.Phase, preview.SceneCount, req.Mode),
+			"dry_run":         true,
+			"scene_ids":       preview.SceneIDs,
+			"scene_count":     preview.SceneCount,
+			"skipped":         preview.Skipped,
+			"total_duration":  preview.TotalDuration,
+			"estimated_bytes": preview.EstimatedBytes,
+		})
+		return
+	}
+
 	result, err := h.processingService.SubmitBulkPhase(req.Phase, req.Mode, req.ForceTarget, req.SceneIDs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -186,6 +240,77 @@ func (h *JobHandler) TriggerBulkPhase(c *gin.Context) {
 	})
 }
 
+// VerifyAll queues a full decode verification for every scene.
+// mode "missing" (default) only queues scenes that have never been verified.
+func (h *JobHandler) VerifyAll(c *gin.Context) {
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	if req.Mode == "" {
+		req.Mode = "missing"
+	}
+
+	if err := validators.ValidateJobMode(req.Mode); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.processingService.SubmitBulkPhase("verify", req.Mode, "", nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   fmt.Sprintf("Verification queued (%s mode)", req.Mode),
+		"submitted": result.Submitted,
+		"skipped":   result.Skipped,
+		"errors":    result.Errors,
+	})
+}
+
+// VerifyChecksums queues a checksum job for every scene. Scenes that already have
+// a hash are re-hashed and flagged on mismatch; the rest get their first hash.
+// mode "all" (default) checks every scene, "missing" only hashes scenes without one.
+func (h *JobHandler) VerifyChecksums(c *gin.Context) {
+	var req struct {
+		Mode string `json:"mode"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	if req.Mode == "" {
+		req.Mode = "all"
+	}
+
+	if err := validators.ValidateJobMode(req.Mode); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.processingService.SubmitBulkPhase("checksum", req.Mode, "", nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   fmt.Sprintf("Checksum verification queued (%s mode)", req.Mode),
+		"submitted": result.Submitted,
+		"skipped":   result.Skipped,
+		"errors":    result.Errors,
+	})
+}
+
 // CancelJob cancels a running job
 func (h *JobHandler) CancelJob(c *gin.Context) {
 	jobID := c.Param("id")
@@ -194,6 +319,24 @@ func (h *JobHandler) CancelJob(c *gin.Context) {
 		return
 	}
 
+	// URL imports run outside the processing pools
+	if h.remoteImportService != nil && h.remoteImportService.Owns(jobID) {
+		if err := h.remoteImportService.Cancel(jobID); err != nil {
+			response.Error(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Job cancelled", "job_id": jobID})
+		return
+	}
+	if h.storageMigrationService != nil && h.storageMigrationService.Owns(jobID) {
+		if err := h.storageMigrationService.Cancel(jobID); err != nil {
+			response.Error(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Job cancelled", "job_id": jobID})
+		return
+	}
+
 	if err := h.processingService.CancelJob(jobID); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -202,6 +345,41 @@ func (h *JobHandler) CancelJob(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Job cancelled", "job_id": jobID})
 }
 
+// CancelJobs cancels the processing jobs of a phase, of scenes, or matching
+// a filter in one call
+func (h *JobHandler) CancelJobs(c *gin.Context) {
+	if h.jobQueueFeeder == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Job queue not available"})
+		return
+	}
+
+	var req request.CancelJobsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	if req.Phase != "" {
+		if err := validators.ValidateProcessingPhase(req.Phase); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := h.jobQueueFeeder.CancelMatching(core.JobCancelFilter{
+		Phase:          req.Phase,
+		SceneIDs:       req.SceneIDs,
+		OlderThan:      req.OlderThan,
+		IncludeRunning: req.IncludeRunning,
+		All:            req.All,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // RetryJob manually retries a failed job
 func (h *JobHandler) RetryJob(c *gin.Context) {
 	jobID := c.Param("id")
@@ -218,6 +396,23 @@ func (h *JobHandler) RetryJob(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Job retried", "job_id": jobID})
 }
 
+// GetJobLog returns the captured log of a job's last run
+func (h *JobHandler) GetJobLog(c *gin.Context) {
+	jobID := c.Param("id")
+	if jobID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "job ID is required"})
+		return
+	}
+
+	log, err := h.jobHistoryService.GetJobLog(jobID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, log)
+}
+
 // ListRecentFailed returns recently failed jobs
 func (h *JobHandler) ListRecentFailed(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
@@ -292,3 +487,117 @@ func (h *JobHandler) ClearFailed(c *gin.Context) {
 		"deleted": deleted,
 	})
 }
+
+// GetAnalytics returns each phase's throughput, failure rate and duration
+// percentiles over a window (default 7d)
+func (h *JobHandler) GetAnalytics(c *gin.Context) {
+	window, err := core.ParseAnalyticsWindow(c.Query("window"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	analytics, err := h.jobHistoryService.GetAnalytics(window)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// GetOutcomeTimeline returns completed, failed and cancelled job counts per
+// phase by hour or day over a window
+func (h *JobHandler) GetOutcomeTimeline(c *gin.Context) {
+	window, err := core.ParseAnalyticsWindow(c.Query("window"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	buckets, err := h.jobHistoryService.GetOutcomeTimeline(window, c.Query("bucket"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": buckets})
+}
+
+// GetSlowestJobs returns the longest completed scene jobs over a window
+func (h *JobHandler) GetSlowestJobs(c *gin.Context) {
+	window, err := core.ParseAnalyticsWindow(c.Query("window"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	phase := c.Query("phase")
+	if phase != "" {
+		if err := validators.ValidatePhase(phase); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	jobs, err := h.jobHistoryService.GetSlowestJobs(window, phase, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": jobs})
+}
+
+// GetRetention returns the job history retention policy and the rows it
+// reclaimed
+func (h *JobHandler) GetRetention(c *gin.Context) {
+	c.JSON(http.StatusOK, h.retentionWorker.Stats())
+}
+
+// RunRetention prunes the job history past the retention window now
+func (h *JobHandler) RunRetention(c *gin.Context) {
+	result, err := h.retentionWorker.Run()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListFailureSummaries returns the failures of the jobs retention pruned,
+// grouped by phase and error
+func (h *JobHandler) ListFailureSummaries(c *gin.Context) {
+	phase := c.Query("phase")
+	if phase != "" {
+		if err := validators.ValidatePhase(phase); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	summaries, err := h.jobHistoryService.ListFailureSummaries(phase, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": summaries})
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
 	"strconv"
 
 	"goonhub/internal/api/middleware"
@@ -13,12 +15,13 @@ import (
 )
 
 type MarkerHandler struct {
-	service         *core.MarkerService
-	maxItemsPerPage int
+	service            *core.MarkerService
+	compilationService *core.MarkerCompilationService
+	maxItemsPerPage    int
 }
 
-func NewMarkerHandler(service *core.MarkerService, maxItemsPerPage int) *MarkerHandler {
-	return &MarkerHandler{service: service, maxItemsPerPage: maxItemsPerPage}
+func NewMarkerHandler(service *core.MarkerService, compilationService *core.MarkerCompilationService, maxItemsPerPage int) *MarkerHandler {
+	return &MarkerHandler{service: service, compilationService: compilationService, maxItemsPerPage: maxItemsPerPage}
 }
 
 // requireAuth extracts the authenticated user from context.
@@ -71,7 +74,7 @@ func (h *MarkerHandler) CreateMarker(c *gin.Context) {
 		return
 	}
 
-	marker, err := h.service.CreateMarker(userID, uint(sceneID), req.Timestamp, req.Label, req.Color)
+	marker, err := h.service.CreateMarker(userID, uint(sceneID), req.Timestamp, req.EndTimestamp, req.Label, req.Color, req.Visibility)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -98,7 +101,7 @@ func (h *MarkerHandler) UpdateMarker(c *gin.Context) {
 		return
 	}
 
-	marker, err := h.service.UpdateMarker(userID, uint(markerID), req.Label, req.Color, req.Timestamp)
+	marker, err := h.service.UpdateMarker(userID, uint(markerID), req.Label, req.Color, req.Timestamp, req.EndTimestamp, req.Visibility)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -178,7 +181,7 @@ func (h *MarkerHandler) ListMarkersByLabel(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	page, limit = clampPagination(page, limit, 20, h.maxItemsPerPage)
 
-	markers, total, err := h.service.GetMarkersByLabel(userID, label, page, limit)
+	markers, total, err := h.service.GetMarkersByLabel(userID, requestRole(c), label, page, limit)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -199,7 +202,63 @@ func (h *MarkerHandler) ListAllMarkers(c *gin.Context) {
 	page, limit = clampPagination(page, limit, 20, h.maxItemsPerPage)
 	sortBy := c.DefaultQuery("sort", "label_asc")
 
-	markers, total, err := h.service.GetAllMarkers(userID, page, limit, sortBy)
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		markers, next, err := h.service.GetAllMarkersAfter(userID, requestRole(c), cursor, limit, sortBy)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		response.OK(c, response.NewCursorResponse(markers, limit, next))
+		return
+	}
+
+	markers, total, err := h.service.GetAllMarkers(userID, requestRole(c), page, limit, sortBy)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, response.NewPaginatedResponse(markers, page, limit, total))
+}
+
+// ListTagCollections returns the virtual collections built from the tags on the user's markers
+func (h *MarkerHandler) ListTagCollections(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, h.maxItemsPerPage)
+
+	collections, total, err := h.service.ListTagCollections(userID, page, limit, c.DefaultQuery("sort", "density"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, response.NewPaginatedResponse(collections, page, limit, total))
+}
+
+// ListCollectionMarkers returns the user's markers carrying the collection's tag
+func (h *MarkerHandler) ListCollectionMarkers(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	tagID, err := strconv.ParseUint(c.Param("tagID"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, h.maxItemsPerPage)
+
+	markers, total, err := h.service.ListCollectionMarkers(userID, requestRole(c), uint(tagID), page, limit, c.DefaultQuery("sort", "density"))
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -208,6 +267,34 @@ func (h *MarkerHandler) ListAllMarkers(c *gin.Context) {
 	response.OK(c, response.NewPaginatedResponse(markers, page, limit, total))
 }
 
+// GetCollectionQueue returns the collection's clips in shuffled playback order
+func (h *MarkerHandler) GetCollectionQueue(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	tagID, err := strconv.ParseUint(c.Param("tagID"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid tag ID")
+		return
+	}
+
+	seed, err := strconv.ParseInt(c.DefaultQuery("seed", "0"), 10, 64)
+	if err != nil || seed < 0 {
+		response.BadRequest(c, "Invalid seed")
+		return
+	}
+
+	queue, err := h.service.CollectionQueue(userID, requestRole(c), uint(tagID), seed)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, queue)
+}
+
 // GetLabelTags returns the default tags for a label
 func (h *MarkerHandler) GetLabelTags(c *gin.Context) {
 	userID, ok := h.requireAuth(c)
@@ -264,6 +351,65 @@ func (h *MarkerHandler) SetLabelTags(c *gin.Context) {
 	response.OK(c, gin.H{"tags": tags})
 }
 
+// GetLabelSettings returns the user's label defaults
+func (h *MarkerHandler) GetLabelSettings(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.service.GetLabelSettings(userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"settings": settings})
+}
+
+// SetLabelSetting creates or replaces the defaults of a label
+func (h *MarkerHandler) SetLabelSetting(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	var req request.SetLabelSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	setting, err := h.service.SetLabelSetting(userID, req.Label, req.Color, req.Icon, req.ClipDuration)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, setting)
+}
+
+// DeleteLabelSetting removes the defaults of a label
+func (h *MarkerHandler) DeleteLabelSetting(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	label := c.Query("label")
+	if label == "" {
+		response.BadRequest(c, "label query parameter is required")
+		return
+	}
+
+	if err := h.service.DeleteLabelSetting(userID, label); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
 // GetMarkerTags returns tags for a specific marker
 func (h *MarkerHandler) GetMarkerTags(c *gin.Context) {
 	userID, ok := h.requireAuth(c)
@@ -353,3 +499,267 @@ func (h *MarkerHandler) AddMarkerTags(c *gin.Context) {
 
 	response.OK(c, gin.H{"tags": tags})
 }
+
+// StreamMarkerClip serves the segment of a range marker as an MP4, cutting it on first request
+func (h *MarkerHandler) StreamMarkerClip(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	markerID, err := strconv.ParseUint(c.Param("markerID"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid marker ID")
+		return
+	}
+
+	path, err := h.service.MarkerClip(userID, requestRole(c), uint(markerID))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "video/mp4")
+	c.Header("Cache-Control", "private, max-age=3600")
+	response.File(c, path)
+}
+
+// ImportMarkers creates markers in bulk from a file or a list, or previews the import on a dry run
+func (h *MarkerHandler) ImportMarkers(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	sceneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	var req request.ImportMarkersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	opts := core.MarkerImportOptions{
+		Format:        req.Format,
+		Content:       req.Content,
+		Color:         req.Color,
+		DedupeSeconds: core.DefaultMarkerImportDedupeSeconds,
+		DryRun:        req.DryRun,
+	}
+	if req.DedupeSeconds != nil {
+		opts.DedupeSeconds = *req.DedupeSeconds
+	}
+	for _, m := range req.Markers {
+		opts.Markers = append(opts.Markers, core.MarkerImportItem{Timestamp: m.Timestamp, Label: m.Label, Color: m.Color})
+	}
+
+	result, err := h.service.ImportMarkers(userID, uint(sceneID), opts)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// ExportMarkers downloads the user's markers on a scene as a chapters file
+func (h *MarkerHandler) ExportMarkers(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	sceneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	export, err := h.service.ExportChapters(userID, uint(sceneID), c.DefaultQuery("format", core.ChapterFormatWebVTT))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	c.Data(http.StatusOK, export.ContentType, export.Content)
+}
+
+// ListSharedMarkers lists the markers other users shared on a scene
+func (h *MarkerHandler) ListSharedMarkers(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	sceneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	markers, err := h.service.ListSharedMarkers(userID, uint(sceneID))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"markers": markers})
+}
+
+// CopySharedMarker copies another user's shared marker into the user's own markers
+func (h *MarkerHandler) CopySharedMarker(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	markerID, err := strconv.ParseUint(c.Param("markerID"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid marker ID")
+		return
+	}
+
+	marker, err := h.service.CopySharedMarker(userID, uint(markerID))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, marker)
+}
+
+// RegenerateThumbnails queues regeneration of the marker thumbnails on every scene the user has markers on
+func (h *MarkerHandler) RegenerateThumbnails(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.RegenerateThumbnails(userID, 0)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// RegenerateSceneThumbnails queues regeneration of the marker thumbnails of one scene
+func (h *MarkerHandler) RegenerateSceneThumbnails(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	sceneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	result, err := h.service.RegenerateThumbnails(userID, uint(sceneID))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// RegenerateAllThumbnails queues regeneration of the marker thumbnails on every scene with markers
+func (h *MarkerHandler) RegenerateAllThumbnails(c *gin.Context) {
+	result, err := h.service.RegenerateAllThumbnails()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// CreateCompilation queues a job that joins the clips of the selected markers into one MP4
+func (h *MarkerHandler) CreateCompilation(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	var req request.CreateMarkerCompilationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	compilation, err := h.compilationService.Create(userID, req.MarkerIDs, req.ClipSeconds, req.Title)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, compilation)
+}
+
+func (h *MarkerHandler) ListCompilations(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	compilations, err := h.compilationService.List(userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"compilations": compilations})
+}
+
+func (h *MarkerHandler) GetCompilation(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	compilation, err := h.compilationService.Get(userID, c.Param("jobID"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, compilation)
+}
+
+// DownloadCompilation serves the MP4 of a completed compilation as an attachment
+func (h *MarkerHandler) DownloadCompilation(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	path, filename, err := h.compilationService.Download(userID, c.Param("jobID"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.FileAttachment(path, filename)
+}
+
+func (h *MarkerHandler) DeleteCompilation(c *gin.Context) {
+	userID, ok := h.requireAuth(c)
+	if !ok {
+		return
+	}
+
+	if err := h.compilationService.Delete(userID, c.Param("jobID")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OfflineSyncHandler struct {
+	offlineSyncService *core.OfflineSyncService
+}
+
+func NewOfflineSyncHandler(offlineSyncService *core.OfflineSyncService) *OfflineSyncHandler {
+	return &OfflineSyncHandler{
+		offlineSyncService: offlineSyncService,
+	}
+}
+
+// GetManifest returns the current user's sync manifest. With ?since=<RFC3339>
+// only scenes changed or removed after that time are listed.
+func (h *OfflineSyncHandler) GetManifest(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var since *time.Time
+	if raw := c.Query("since"); raw != "" {
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			response.BadRequest(c, "Invalid since: must be an RFC 3339 timestamp")
+			return
+		}
+		since = &t
+	}
+
+	manifest, err := h.offlineSyncService.GetManifest(payload.UserID, payload.Role, since)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, manifest)
+}
+
+// AddScenes selects scenes for offline sync
+func (h *OfflineSyncHandler) AddScenes(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req request.AddOfflineScenesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.offlineSyncService.AddScenes(payload.UserID, payload.Role, req.SceneIDs); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// RemoveScene deselects a scene from offline sync
+func (h *OfflineSyncHandler) RemoveScene(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	if err := h.offlineSyncService.RemoveScene(payload.UserID, uint(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"goonhub/internal/api/v1/response"
 	"goonhub/internal/api/v1/validators"
 	"goonhub/internal/core"
 	"goonhub/internal/data"
@@ -13,23 +14,32 @@ import (
 type PoolConfigHandler struct {
 	processingService *core.SceneProcessingService
 	poolConfigRepo    data.PoolConfigRepository
+	autoscaler        *core.PoolAutoscaler
 }
 
 // NewPoolConfigHandler creates a new PoolConfigHandler
 func NewPoolConfigHandler(
 	processingService *core.SceneProcessingService,
 	poolConfigRepo data.PoolConfigRepository,
+	autoscaler *core.PoolAutoscaler,
 ) *PoolConfigHandler {
 	return &PoolConfigHandler{
 		processingService: processingService,
 		poolConfigRepo:    poolConfigRepo,
+		autoscaler:        autoscaler,
 	}
 }
 
-// GetPoolConfig returns the current pool configuration
+// GetPoolConfig returns the current pool configuration and the autoscaler's status
 func (h *PoolConfigHandler) GetPoolConfig(c *gin.Context) {
-	poolConfig := h.processingService.GetPoolConfig()
-	c.JSON(http.StatusOK, poolConfig)
+	c.JSON(http.StatusOK, h.poolConfig())
+}
+
+func (h *PoolConfigHandler) poolConfig() response.PoolConfig {
+	return response.PoolConfig{
+		PoolConfig: h.processingService.GetPoolConfig(),
+		Autoscale:  h.autoscaler.Status(),
+	}
 }
 
 // UpdatePoolConfig updates the pool configuration
@@ -40,12 +50,22 @@ func (h *PoolConfigHandler) UpdatePoolConfig(c *gin.Context) {
 		return
 	}
 
+	// Clients that predate the verify and checksum pools don't send their sizes; keep the current ones
+	if req.VerifyWorkers == 0 {
+		req.VerifyWorkers = h.processingService.GetPoolConfig().VerifyWorkers
+	}
+	if req.ChecksumWorkers == 0 {
+		req.ChecksumWorkers = h.processingService.GetPoolConfig().ChecksumWorkers
+	}
+
 	// Validate pool configuration
 	if err := validators.ValidatePoolConfig(validators.PoolConfigInput{
 		MetadataWorkers:           req.MetadataWorkers,
 		ThumbnailWorkers:          req.ThumbnailWorkers,
 		SpritesWorkers:            req.SpritesWorkers,
 		AnimatedThumbnailsWorkers: req.AnimatedThumbnailsWorkers,
+		VerifyWorkers:             req.VerifyWorkers,
+		ChecksumWorkers:           req.ChecksumWorkers,
 	}); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -61,11 +81,13 @@ func (h *PoolConfigHandler) UpdatePoolConfig(c *gin.Context) {
 		ThumbnailWorkers:          req.ThumbnailWorkers,
 		SpritesWorkers:            req.SpritesWorkers,
 		AnimatedThumbnailsWorkers: req.AnimatedThumbnailsWorkers,
+		VerifyWorkers:             req.VerifyWorkers,
+		ChecksumWorkers:           req.ChecksumWorkers,
 	}
 	if err := h.poolConfigRepo.Upsert(record); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Pool config applied but failed to persist: " + err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, h.processingService.GetPoolConfig())
+	c.JSON(http.StatusOK, h.poolConfig())
 }
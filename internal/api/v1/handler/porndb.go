@@ -1,29 +1,55 @@
 package handler
 
 import (
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
 	"goonhub/internal/core"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type PornDBHandler struct {
-	Service *core.PornDBService
+	Service          *core.PornDBService
+	Providers        *core.MetadataProviders
+	MatchService     *core.PornDBMatchService
+	ActorSyncService *core.ActorSyncService
 }
 
-func NewPornDBHandler(service *core.PornDBService) *PornDBHandler {
+func NewPornDBHandler(service *core.PornDBService, providers *core.MetadataProviders, matchService *core.PornDBMatchService, actorSyncService *core.ActorSyncService) *PornDBHandler {
 	return &PornDBHandler{
-		Service: service,
+		Service:          service,
+		Providers:        providers,
+		MatchService:     matchService,
+		ActorSyncService: actorSyncService,
 	}
 }
 
-// GetStatus returns whether the PornDB integration is configured
+// GetStatus returns whether the PornDB integration is configured, how the client is pacing its requests and which metadata providers are available
 func (h *PornDBHandler) GetStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"configured": h.Service.IsConfigured(),
+		"rate_limit": h.Service.RateLimitStats(),
+		"providers":  h.Providers.List(),
 	})
 }
 
+// provider returns the metadata provider chosen by the provider query parameter, PornDB by default
+func (h *PornDBHandler) provider(c *gin.Context) (core.MetadataProvider, bool) {
+	provider, err := h.Providers.Get(c.Query("provider"))
+	if err != nil {
+		response.Error(c, err)
+		return nil, false
+	}
+	if !provider.IsConfigured() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": provider.Label() + " integration is not configured"})
+		return nil, false
+	}
+	return provider, true
+}
+
 // SearchPerformers searches for performers by name
 func (h *PornDBHandler) SearchPerformers(c *gin.Context) {
 	query := c.Query("q")
@@ -32,12 +58,12 @@ func (h *PornDBHandler) SearchPerformers(c *gin.Context) {
 		return
 	}
 
-	if !h.Service.IsConfigured() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "PornDB integration is not configured"})
+	provider, ok := h.provider(c)
+	if !ok {
 		return
 	}
 
-	performers, err := h.Service.SearchPerformers(query)
+	performers, err := provider.SearchPerformers(query)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -97,10 +123,12 @@ func (h *PornDBHandler) GetPerformerSite(c *gin.Context) {
 	})
 }
 
-// SearchScenes searches for scenes with optional filters
+// SearchScenes searches for scenes by title or file fingerprint
 func (h *PornDBHandler) SearchScenes(c *gin.Context) {
 	opts := core.SceneSearchOptions{
-		Title: c.Query("title"),
+		Title:                c.Query("title"),
+		Fingerprint:          c.Query("fingerprint"),
+		FingerprintAlgorithm: c.DefaultQuery("algorithm", core.FingerprintOSHash),
 	}
 
 	// Require at least one search parameter
@@ -108,13 +136,17 @@ func (h *PornDBHandler) SearchScenes(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one search parameter is required"})
 		return
 	}
+	if err := core.ValidateFingerprintSearch(opts); err != nil {
+		response.Error(c, err)
+		return
+	}
 
-	if !h.Service.IsConfigured() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "PornDB integration is not configured"})
+	provider, ok := h.provider(c)
+	if !ok {
 		return
 	}
 
-	scenes, err := h.Service.SearchScenes(opts)
+	scenes, err := provider.SearchScenes(opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -133,12 +165,12 @@ func (h *PornDBHandler) GetScene(c *gin.Context) {
 		return
 	}
 
-	if !h.Service.IsConfigured() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "PornDB integration is not configured"})
+	provider, ok := h.provider(c)
+	if !ok {
 		return
 	}
 
-	scene, err := h.Service.GetSceneDetails(id)
+	scene, err := provider.GetSceneDetails(id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -196,3 +228,226 @@ func (h *PornDBHandler) GetSite(c *gin.Context) {
 		"data": site,
 	})
 }
+
+// GetCacheStats returns the PornDB response cache's hit counters and entry counts
+func (h *PornDBHandler) GetCacheStats(c *gin.Context) {
+	stats, err := h.Service.CacheStats()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// PurgeCache deletes cached PornDB responses, optionally of one kind or only the expired ones
+func (h *PornDBHandler) PurgeCache(c *gin.Context) {
+	expiredOnly := c.Query("expired_only") == "true"
+	deleted, err := h.Service.PurgeCache(c.Query("kind"), expiredOnly)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+// StartAutoMatch starts a background auto-match, against PornDB or StashDB, of every scene without a PornDB or StashDB scene ID
+func (h *PornDBHandler) StartAutoMatch(c *gin.Context) {
+	var req request.StartPornDBAutoMatchRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+
+	job, err := h.MatchService.Start(req.Provider, req.Threshold)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListMatchReviews returns auto-match reviews, most confident first, optionally filtered by status
+func (h *PornDBHandler) ListMatchReviews(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, 100)
+
+	reviews, total, err := h.MatchService.ListReviews(c.Query("status"), page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":    reviews,
+		"total":   total,
+		"page":    page,
+		"limit":   limit,
+		"running": h.MatchService.IsRunning(),
+	})
+}
+
+// AcceptMatchReview applies the chosen provider scene to a reviewed scene
+func (h *PornDBHandler) AcceptMatchReview(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	sceneID, ok := parseMatchReviewSceneID(c)
+	if !ok {
+		return
+	}
+
+	var req request.AcceptPornDBMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	review, err := h.MatchService.AcceptReview(sceneID, req.Provider, req.PornDBSceneID, req.Fields, userPayload.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// ApplyMatch applies the selected fields of a provider scene to a scene in one transaction
+func (h *PornDBHandler) ApplyMatch(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	sceneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+		return
+	}
+
+	var req request.ApplyPornDBMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	result, err := h.MatchService.ApplyMatch(uint(sceneID), req.Provider, req.PornDBSceneID, req.Fields, userPayload.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// DismissMatchReview rejects every candidate of a review so later runs skip the scene
+func (h *PornDBHandler) DismissMatchReview(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	sceneID, ok := parseMatchReviewSceneID(c)
+	if !ok {
+		return
+	}
+
+	review, err := h.MatchService.DismissReview(sceneID, userPayload.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// GetPerformerSyncStatus returns whether a performer sync is running and how the last one went
+func (h *PornDBHandler) GetPerformerSyncStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.ActorSyncService.Status())
+}
+
+// StartPerformerSync starts a background sync of the linked actors that are due for one
+func (h *PornDBHandler) StartPerformerSync(c *gin.Context) {
+	if err := h.ActorSyncService.StartSyncDue(); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, h.ActorSyncService.Status())
+}
+
+// UpdateActorSync links an actor to a PornDB performer and turns its scheduled sync on or off
+func (h *PornDBHandler) UpdateActorSync(c *gin.Context) {
+	actorID, ok := parseActorSyncActorID(c)
+	if !ok {
+		return
+	}
+
+	var req request.UpdateActorPornDBSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	actor, err := h.ActorSyncService.UpdateSettings(actorID, req.PornDBID, req.Enabled)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, actor)
+}
+
+// SyncActor refreshes an actor from its PornDB performer now
+func (h *PornDBHandler) SyncActor(c *gin.Context) {
+	actorID, ok := parseActorSyncActorID(c)
+	if !ok {
+		return
+	}
+
+	sync, err := h.ActorSyncService.SyncActor(actorID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sync)
+}
+
+// ListActorSyncs returns an actor's PornDB syncs and what each changed, newest first
+func (h *PornDBHandler) ListActorSyncs(c *gin.Context) {
+	actorID, ok := parseActorSyncActorID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, 100)
+
+	syncs, total, err := h.ActorSyncService.ListSyncs(actorID, page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  syncs,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+func parseActorSyncActorID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func parseMatchReviewSceneID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("sceneId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RegistrationHandler struct {
+	registrationService *core.RegistrationService
+	auditService        *core.AuditService
+}
+
+func NewRegistrationHandler(registrationService *core.RegistrationService, auditService *core.AuditService) *RegistrationHandler {
+	return &RegistrationHandler{
+		registrationService: registrationService,
+		auditService:        auditService,
+	}
+}
+
+// GetRegistrationStatus tells the login page whether it can offer sign-up without an invite
+func (h *RegistrationHandler) GetRegistrationStatus(c *gin.Context) {
+	status, err := h.registrationService.Status()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// PreviewInvite checks an invite token before the registration form is filled in
+func (h *RegistrationHandler) PreviewInvite(c *gin.Context) {
+	preview, err := h.registrationService.PreviewInvite(c.Param("token"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// Register creates an account with an invite, or without one under open registration.
+// The new user logs in normally afterwards.
+func (h *RegistrationHandler) Register(c *gin.Context) {
+	var req request.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	user, err := h.registrationService.Register(req.Username, req.Password, req.InviteToken)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Registration happens before there is a session, so the audit middleware never sees it
+	h.auditService.Record(data.AuditEntry{
+		Action:       core.AuditActionRegister,
+		ResourceType: "user",
+		ResourceID:   strconv.FormatUint(uint64(user.ID), 10),
+		UserID:       &user.ID,
+		Username:     user.Username,
+		Method:       c.Request.Method,
+		Route:        c.FullPath(),
+		Status:       http.StatusCreated,
+		IPAddress:    c.ClientIP(),
+		Details:      data.AuditDetails{"role": user.Role, "invited": req.InviteToken != ""},
+	})
+
+	c.JSON(http.StatusCreated, response.UserSummary{
+		ID:       user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+	})
+}
+
+// ListInvites returns every invite, newest first
+func (h *RegistrationHandler) ListInvites(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, 100)
+
+	invites, total, err := h.registrationService.ListInvites(page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  invites,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// CreateInvite creates an invite; the token is only returned by this call
+func (h *RegistrationHandler) CreateInvite(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req request.CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	created, err := h.registrationService.CreateInvite(userPayload.UserID, req.Role, req.Note, req.ExpiresInHours)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// RevokeInvite revokes an unused invite
+func (h *RegistrationHandler) RevokeInvite(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invite ID"})
+		return
+	}
+
+	if err := h.registrationService.RevokeInvite(uint(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Invite revoked"})
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReleaseHandler struct {
+	releaseService *core.ReleaseService
+}
+
+func NewReleaseHandler(releaseService *core.ReleaseService) *ReleaseHandler {
+	return &ReleaseHandler{
+		releaseService: releaseService,
+	}
+}
+
+// GetReleaseInfo returns the running version, recent changelog entries and pending
+// migration/deprecation notices
+func (h *ReleaseHandler) GetReleaseInfo(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if limit < 0 {
+		limit = 0
+	}
+
+	info, err := h.releaseService.GetReleaseInfo(limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
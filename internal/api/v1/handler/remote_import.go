@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+)
+
+type RemoteImportHandler struct {
+	Service *core.RemoteImportService
+}
+
+func NewRemoteImportHandler(service *core.RemoteImportService) *RemoteImportHandler {
+	return &RemoteImportHandler{
+		Service: service,
+	}
+}
+
+// Submit queues a URL to be downloaded into the library
+// POST /api/v1/admin/import/urls
+func (h *RemoteImportHandler) Submit(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req request.RemoteImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	item, err := h.Service.Submit(payload.UserID, req.URL, req.Title, req.StoragePathID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, item)
+}
+
+// List returns the URL imports, newest first
+// GET /api/v1/admin/import/urls
+func (h *RemoteImportHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, 100)
+
+	items, total, err := h.Service.List(page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// Get returns a URL import with its download progress
+// GET /api/v1/admin/import/urls/:jobID
+func (h *RemoteImportHandler) Get(c *gin.Context) {
+	item, err := h.Service.Get(c.Param("jobID"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// Cancel stops a running or queued URL import
+// POST /api/v1/admin/import/urls/:jobID/cancel
+func (h *RemoteImportHandler) Cancel(c *gin.Context) {
+	jobID := c.Param("jobID")
+	if err := h.Service.Cancel(jobID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Import cancelled", "job_id": jobID})
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"goonhub/internal/core"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RequestStatsHandler struct {
+	requestStatsService *core.RequestStatsService
+}
+
+func NewRequestStatsHandler(requestStatsService *core.RequestStatsService) *RequestStatsHandler {
+	return &RequestStatsHandler{
+		requestStatsService: requestStatsService,
+	}
+}
+
+// GetSlowestEndpoints lists the routes with the highest average latency over the last 24h
+func (h *RequestStatsHandler) GetSlowestEndpoints(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"slow_threshold_ms": h.requestStatsService.SlowThreshold().Milliseconds(),
+		"data":              h.requestStatsService.Slowest(limit),
+	})
+}
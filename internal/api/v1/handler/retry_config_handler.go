@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 )
 
 // RetryConfigHandler handles retry configuration requests
@@ -49,38 +50,64 @@ func (h *RetryConfigHandler) UpdateRetryConfig(c *gin.Context) {
 		return
 	}
 
+	// The policy fields are optional; omitted ones keep their current value
 	var req struct {
-		Phase               string  `json:"phase"`
-		MaxRetries          int     `json:"max_retries"`
-		InitialDelaySeconds int     `json:"initial_delay_seconds"`
-		MaxDelaySeconds     int     `json:"max_delay_seconds"`
-		BackoffFactor       float64 `json:"backoff_factor"`
+		Phase               string    `json:"phase"`
+		MaxRetries          int       `json:"max_retries"`
+		InitialDelaySeconds int       `json:"initial_delay_seconds"`
+		MaxDelaySeconds     int       `json:"max_delay_seconds"`
+		BackoffFactor       float64   `json:"backoff_factor"`
+		BackoffStrategy     *string   `json:"backoff_strategy"`
+		JitterPercent       *int      `json:"jitter_percent"`
+		NeverRetryErrors    *[]string `json:"never_retry_errors"`
+		AlwaysRetryErrors   *[]string `json:"always_retry_errors"`
+		RetryUnclassified   *bool     `json:"retry_unclassified"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
+	record := h.currentRetryConfig(req.Phase)
+	record.MaxRetries = req.MaxRetries
+	record.InitialDelaySeconds = req.InitialDelaySeconds
+	record.MaxDelaySeconds = req.MaxDelaySeconds
+	record.BackoffFactor = req.BackoffFactor
+	if req.BackoffStrategy != nil {
+		record.BackoffStrategy = *req.BackoffStrategy
+	}
+	if record.BackoffStrategy == "" {
+		record.BackoffStrategy = data.BackoffExponential
+	}
+	if req.JitterPercent != nil {
+		record.JitterPercent = *req.JitterPercent
+	}
+	if req.NeverRetryErrors != nil {
+		record.NeverRetryErrors = *req.NeverRetryErrors
+	}
+	if req.AlwaysRetryErrors != nil {
+		record.AlwaysRetryErrors = *req.AlwaysRetryErrors
+	}
+	if req.RetryUnclassified != nil {
+		record.RetryUnclassified = *req.RetryUnclassified
+	}
+
 	// Validate retry configuration
 	if err := validators.ValidateRetryConfig(validators.RetryConfigInput{
 		Phase:               req.Phase,
-		MaxRetries:          req.MaxRetries,
-		InitialDelaySeconds: req.InitialDelaySeconds,
-		MaxDelaySeconds:     req.MaxDelaySeconds,
-		BackoffFactor:       req.BackoffFactor,
+		MaxRetries:          record.MaxRetries,
+		InitialDelaySeconds: record.InitialDelaySeconds,
+		MaxDelaySeconds:     record.MaxDelaySeconds,
+		BackoffFactor:       record.BackoffFactor,
+		BackoffStrategy:     record.BackoffStrategy,
+		JitterPercent:       record.JitterPercent,
+		NeverRetryErrors:    record.NeverRetryErrors,
+		AlwaysRetryErrors:   record.AlwaysRetryErrors,
 	}); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	record := &data.RetryConfigRecord{
-		Phase:               req.Phase,
-		MaxRetries:          req.MaxRetries,
-		InitialDelaySeconds: req.InitialDelaySeconds,
-		MaxDelaySeconds:     req.MaxDelaySeconds,
-		BackoffFactor:       req.BackoffFactor,
-	}
-
 	if err := h.retryConfigRepo.Upsert(record); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update retry config"})
 		return
@@ -101,3 +128,19 @@ func (h *RetryConfigHandler) UpdateRetryConfig(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, configs)
 }
+
+// currentRetryConfig returns the stored policy of a phase, or the scheduler's
+// defaults when it has none, as the base of an update.
+func (h *RetryConfigHandler) currentRetryConfig(phase string) *data.RetryConfigRecord {
+	record := &data.RetryConfigRecord{Phase: phase, JitterPercent: 20, RetryUnclassified: true}
+	if existing, err := h.retryConfigRepo.GetByPhase(phase); err == nil {
+		record = existing
+	} else if h.retryScheduler != nil {
+		defaults := h.retryScheduler.GetConfigForPhase(phase)
+		record = &defaults
+	}
+	record.ID = 0
+	record.NeverRetryErrors = append(pq.StringArray{}, record.NeverRetryErrors...)
+	record.AlwaysRetryErrors = append(pq.StringArray{}, record.AlwaysRetryErrors...)
+	return record
+}
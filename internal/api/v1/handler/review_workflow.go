@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewWorkflowHandler struct {
+	reviewWorkflowService *core.ReviewWorkflowService
+}
+
+func NewReviewWorkflowHandler(reviewWorkflowService *core.ReviewWorkflowService) *ReviewWorkflowHandler {
+	return &ReviewWorkflowHandler{
+		reviewWorkflowService: reviewWorkflowService,
+	}
+}
+
+// GetWorkflow returns the configured review states and transitions with the
+// number of library scenes in each state
+func (h *ReviewWorkflowHandler) GetWorkflow(c *gin.Context) {
+	counts, err := h.reviewWorkflowService.GetCounts()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{
+		"workflow": h.reviewWorkflowService.Workflow(),
+		"counts":   counts,
+	})
+}
+
+// SetSceneState moves one scene to another review state
+func (h *ReviewWorkflowHandler) SetSceneState(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	var req request.SetReviewStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.reviewWorkflowService.SetState(uint(id), req.State); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"review_state": req.State})
+}
+
+// BulkSetState moves several scenes to the same review state
+func (h *ReviewWorkflowHandler) BulkSetState(c *gin.Context) {
+	var req request.BulkSetReviewStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	updated, err := h.reviewWorkflowService.BulkSetState(req.SceneIDs, req.State)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{
+		"updated":   updated,
+		"requested": len(req.SceneIDs),
+	})
+}
@@ -48,8 +48,14 @@ func (h *SavedSearchHandler) List(c *gin.Context) {
 		return
 	}
 
+	counts, err := h.Service.NewMatchCounts(searches)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list saved searches"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data": response.NewSavedSearchListResponse(searches),
+		"data": response.NewSavedSearchListResponse(searches, counts),
 	})
 }
 
@@ -99,6 +105,7 @@ func (h *SavedSearchHandler) Create(c *gin.Context) {
 	input := core.CreateSavedSearchInput{
 		Name:    req.Name,
 		Filters: requestFiltersToData(req.Filters),
+		Watched: req.Watched,
 	}
 
 	search, err := h.Service.Create(userID, input)
@@ -134,7 +141,8 @@ func (h *SavedSearchHandler) Update(c *gin.Context) {
 	}
 
 	input := core.UpdateSavedSearchInput{
-		Name: req.Name,
+		Name:    req.Name,
+		Watched: req.Watched,
 	}
 	if req.Filters != nil {
 		filters := requestFiltersToData(*req.Filters)
@@ -191,6 +199,74 @@ func (h *SavedSearchHandler) Delete(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// NewMatches lists the scenes a watched search found that the user has not seen yet.
+func (h *SavedSearchHandler) NewMatches(c *gin.Context) {
+	userID, ok := h.getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	uuidStr := c.Param("uuid")
+
+	if _, err := uuid.Parse(uuidStr); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search UUID"})
+		return
+	}
+
+	scenes, err := h.Service.NewMatches(userID, requestRole(c), uuidStr)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+			return
+		}
+		if apperrors.IsForbidden(err) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this saved search"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get new matches"})
+		return
+	}
+
+	listData, ok := sceneListData(c, scenes)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": listData,
+	})
+}
+
+// MarkSeen clears the new match badge of a saved search.
+func (h *SavedSearchHandler) MarkSeen(c *gin.Context) {
+	userID, ok := h.getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	uuidStr := c.Param("uuid")
+
+	if _, err := uuid.Parse(uuidStr); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search UUID"})
+		return
+	}
+
+	if err := h.Service.MarkMatchesSeen(userID, uuidStr); err != nil {
+		if apperrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+			return
+		}
+		if apperrors.IsForbidden(err) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to modify this saved search"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark matches seen"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 func requestFiltersToData(f request.SavedSearchFilters) data.Filters {
 	return data.Filters{
 		Query:          f.Query,
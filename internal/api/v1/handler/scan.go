@@ -1,7 +1,14 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
 	"goonhub/internal/core"
+	"goonhub/internal/data"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 
@@ -10,20 +17,40 @@ import (
 
 // ScanHandler handles HTTP requests for scan operations
 type ScanHandler struct {
-	scanService *core.ScanService
+	scanService       *core.ScanService
+	folderRuleService *core.FolderRuleService
+	dropboxService    *core.DropboxImportService
 }
 
 // NewScanHandler creates a new scan handler
-func NewScanHandler(scanService *core.ScanService) *ScanHandler {
+func NewScanHandler(scanService *core.ScanService, folderRuleService *core.FolderRuleService, dropboxService *core.DropboxImportService) *ScanHandler {
 	return &ScanHandler{
-		scanService: scanService,
+		scanService:       scanService,
+		folderRuleService: folderRuleService,
+		dropboxService:    dropboxService,
 	}
 }
 
-// StartScan initiates a new scan of all storage paths
+// StartScan initiates a new scan of all storage paths, or of one storage path
+// or folder when storage_path_id (and folder_path) are given
 // POST /api/v1/admin/scan
 func (h *ScanHandler) StartScan(c *gin.Context) {
-	scan, err := h.scanService.StartScan(c.Request.Context())
+	var req request.StartScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	var scope core.ScanScope
+	if req.StoragePathID != nil {
+		scope.StoragePathIDs = []uint{*req.StoragePathID}
+		scope.FolderPath = req.FolderPath
+	} else if req.FolderPath != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "folder_path requires storage_path_id"})
+		return
+	}
+
+	scan, err := h.scanService.StartScopedScan(c.Request.Context(), scope)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -79,3 +106,261 @@ func (h *ScanHandler) GetHistory(c *gin.Context) {
 		"limit": limit,
 	})
 }
+
+// GetReport returns a page of the paths a scan added, moved, removed or failed on,
+// with totals per kind. ?kind= filters to one of added, moved, removed, error.
+// GET /api/v1/admin/scan/history/:id/report
+func (h *ScanHandler) GetReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scan ID"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	report, err := h.scanService.GetReport(uint(id), c.Query("kind"), page, limit)
+	if err != nil {
+		if errors.Is(err, core.ErrScanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// DownloadReport streams a scan's full report as an attachment.
+// ?format= is csv (default) or json; the json form includes the scan summary.
+// GET /api/v1/admin/scan/history/:id/report/download
+func (h *ScanHandler) DownloadReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scan ID"})
+		return
+	}
+	format := c.DefaultQuery("format", "csv")
+
+	write, err := h.scanService.ExportReport(uint(id), format)
+	if err != nil {
+		if errors.Is(err, core.ErrScanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == "json" {
+		contentType = "application/json"
+	}
+	filename := fmt.Sprintf("scan-%d-report.%s", id, format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+
+	// Headers are sent; a failure now can only cut the download short
+	if err := write(c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// GetExclusions returns the global scan exclusion rules
+// GET /api/v1/admin/scan/exclusions
+func (h *ScanHandler) GetExclusions(c *gin.Context) {
+	cfg, err := h.scanService.GetScanConfig()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scan exclusions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cfg)
+}
+
+// UpdateExclusions replaces the global scan exclusion rules
+// PUT /api/v1/admin/scan/exclusions
+func (h *ScanHandler) UpdateExclusions(c *gin.Context) {
+	var req request.UpdateScanExclusionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	cfg := &data.ScanConfigRecord{
+		MinFileSizeBytes: req.MinFileSizeBytes,
+		IgnoreSamples:    req.IgnoreSamples,
+		IgnoreHiddenDirs: req.IgnoreHiddenDirs,
+		ExcludePatterns:  req.ExcludePatterns,
+	}
+	if err := h.scanService.UpdateScanConfig(cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cfg)
+}
+
+// TestExclusions lists the files and folders of a storage path that the
+// exclusion rules would skip, without changing anything
+// POST /api/v1/admin/scan/exclusions/test
+func (h *ScanHandler) TestExclusions(c *gin.Context) {
+	var req request.TestScanExclusionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	var patterns []string
+	if req.ExcludePatterns != nil {
+		patterns = append([]string{}, *req.ExcludePatterns...)
+	}
+
+	result, err := h.scanService.TestExclusions(req.StoragePathID, patterns)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListFolderRules returns the folder rules in the order they are applied
+// GET /api/v1/admin/scan/folder-rules
+func (h *ScanHandler) ListFolderRules(c *gin.Context) {
+	rules, err := h.folderRuleService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list folder rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// CreateFolderRule adds a folder rule
+// POST /api/v1/admin/scan/folder-rules
+func (h *ScanHandler) CreateFolderRule(c *gin.Context) {
+	var req request.FolderRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	rule, err := h.folderRuleService.Create(folderRuleInput(req))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateFolderRule replaces a folder rule
+// PUT /api/v1/admin/scan/folder-rules/:id
+func (h *ScanHandler) UpdateFolderRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	var req request.FolderRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	rule, err := h.folderRuleService.Update(uint(id), folderRuleInput(req))
+	if err != nil {
+		if errors.Is(err, core.ErrFolderRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteFolderRule removes a folder rule. Scenes it already tagged keep their tags.
+// DELETE /api/v1/admin/scan/folder-rules/:id
+func (h *ScanHandler) DeleteFolderRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	if err := h.folderRuleService.Delete(uint(id)); err != nil {
+		if errors.Is(err, core.ErrFolderRuleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete folder rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Folder rule deleted"})
+}
+
+// ApplyFolderRules starts applying the enabled folder rules to the scenes
+// already in a folder, in the background
+// POST /api/v1/admin/scan/folder-rules/apply
+func (h *ScanHandler) ApplyFolderRules(c *gin.Context) {
+	var req request.ApplyFolderRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	status, err := h.folderRuleService.StartApply(req.StoragePathID, req.FolderPath)
+	if err != nil {
+		if errors.Is(err, core.ErrFolderRulesApplying) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+// GetFolderRulesApplyStatus returns the running or last apply job
+// GET /api/v1/admin/scan/folder-rules/apply
+func (h *ScanHandler) GetFolderRulesApplyStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": h.folderRuleService.GetApplyStatus()})
+}
+
+// RunDropboxImport imports the settled files in the dropbox directory now
+// instead of waiting for the next poll
+// POST /api/v1/admin/scan/dropbox
+func (h *ScanHandler) RunDropboxImport(c *gin.Context) {
+	result, err := h.dropboxService.Run()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func folderRuleInput(req request.FolderRuleRequest) core.FolderRuleInput {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return core.FolderRuleInput{
+		Name:          req.Name,
+		StoragePathID: req.StoragePathID,
+		Pattern:       req.Pattern,
+		TagIDs:        req.TagIDs,
+		ActorIDs:      req.ActorIDs,
+		StudioID:      req.StudioID,
+		SceneType:     req.SceneType,
+		Enabled:       enabled,
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"goonhub/internal/core"
 	"goonhub/internal/data"
 	"goonhub/internal/streaming"
+	"math"
 	"mime"
 	"net/http"
 	"os"
@@ -22,32 +23,40 @@ import (
 )
 
 type SceneHandler struct {
-	Service              *core.SceneService
-	ProcessingService    *core.SceneProcessingService
-	TagService           *core.TagService
-	SearchService        *core.SearchService
-	RelatedScenesService *core.RelatedScenesService
-	MarkerService        *core.MarkerService
-	StreamManager        *streaming.Manager
-	InteractionRepo      data.InteractionRepository
-	TagRepo              data.TagRepository
-	ActorRepo            data.ActorRepository
-	MaxItemsPerPage      int
-}
-
-func NewSceneHandler(service *core.SceneService, processingService *core.SceneProcessingService, tagService *core.TagService, searchService *core.SearchService, relatedScenesService *core.RelatedScenesService, markerService *core.MarkerService, streamManager *streaming.Manager, interactionRepo data.InteractionRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, maxItemsPerPage int) *SceneHandler {
+	Service               *core.SceneService
+	ProcessingService     *core.SceneProcessingService
+	TagService            *core.TagService
+	SearchService         *core.SearchService
+	RelatedScenesService  *core.RelatedScenesService
+	MarkerService         *core.MarkerService
+	StreamManager         *streaming.Manager
+	InteractionRepo       data.InteractionRepository
+	TagRepo               data.TagRepository
+	ActorRepo             data.ActorRepository
+	ReviewWorkflowService *core.ReviewWorkflowService
+	CastService           *core.CastService
+	SpriteFrameService    *core.SpriteFrameService
+	MediaSigner           *core.MediaSigner
+	MaxItemsPerPage       int
+}
+
+func NewSceneHandler(service *core.SceneService, processingService *core.SceneProcessingService, tagService *core.TagService, searchService *core.SearchService, relatedScenesService *core.RelatedScenesService, markerService *core.MarkerService, streamManager *streaming.Manager, interactionRepo data.InteractionRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, reviewWorkflowService *core.ReviewWorkflowService, castService *core.CastService, spriteFrameService *core.SpriteFrameService, mediaSigner *core.MediaSigner, maxItemsPerPage int) *SceneHandler {
 	return &SceneHandler{
-		Service:              service,
-		ProcessingService:    processingService,
-		TagService:           tagService,
-		SearchService:        searchService,
-		RelatedScenesService: relatedScenesService,
-		MarkerService:        markerService,
-		StreamManager:        streamManager,
-		InteractionRepo:      interactionRepo,
-		TagRepo:              tagRepo,
-		ActorRepo:            actorRepo,
-		MaxItemsPerPage:      maxItemsPerPage,
+		Service:               service,
+		ProcessingService:     processingService,
+		TagService:            tagService,
+		SearchService:         searchService,
+		RelatedScenesService:  relatedScenesService,
+		MarkerService:         markerService,
+		StreamManager:         streamManager,
+		InteractionRepo:       interactionRepo,
+		TagRepo:               tagRepo,
+		ActorRepo:             actorRepo,
+		ReviewWorkflowService: reviewWorkflowService,
+		CastService:           castService,
+		SpriteFrameService:    spriteFrameService,
+		MediaSigner:           mediaSigner,
+		MaxItemsPerPage:       maxItemsPerPage,
 	}
 }
 
@@ -73,15 +82,6 @@ func (h *SceneHandler) UploadScene(c *gin.Context) {
 	c.JSON(http.StatusCreated, scene)
 }
 
-var resolutionToHeight = map[string][2]int{
-	"4k":    {2160, 0},
-	"1440p": {1440, 2159},
-	"1080p": {1080, 1439},
-	"720p":  {720, 1079},
-	"480p":  {480, 719},
-	"360p":  {0, 479},
-}
-
 func (h *SceneHandler) ListScenes(c *gin.Context) {
 	var req request.SearchScenesRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
@@ -92,10 +92,191 @@ func (h *SceneHandler) ListScenes(c *gin.Context) {
 	req.Page, req.Limit = clampPagination(req.Page, req.Limit, 20, h.MaxItemsPerPage)
 
 	var userID uint
+	var role string
+	if payload, err := middleware.GetUserFromContext(c); err == nil {
+		userID = payload.UserID
+		role = payload.Role
+	}
+
+	params, err := h.sceneSearchParams(&req, userID, role)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// Any cursor parameter, even an empty one for the first page, pages by cursor
+	params.Cursor, params.UseCursor = c.GetQuery("cursor")
+
+	result, err := h.SearchService.SearchWithContext(c.Request.Context(), params)
+	if err != nil {
+		if apperrors.IsValidation(err) {
+			response.Error(c, err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search scenes"})
+		return
+	}
+
+	fields := response.ParseSparseFields(c.Query("fields"))
+	cardFields := response.ParseCardFields(c.Query("card_fields")).Merge(fields.CardFields())
+
+	var items []response.SceneListItem
+	if cardFields.HasAny() {
+		items = response.ToSceneListItemsWithFields(result.Scenes, cardFields)
+	} else {
+		items = response.ToSceneListItems(result.Scenes)
+	}
+
+	sceneIDs := make([]uint, len(result.Scenes))
+	for i, s := range result.Scenes {
+		sceneIDs[i] = s.ID
+	}
+
+	// Load tags/actors from join tables when requested
+	if cardFields.Tags && len(sceneIDs) > 0 {
+		if tagsByScene, err := h.TagRepo.GetSceneTagsMultiple(sceneIDs); err == nil {
+			for i := range items {
+				if tags, ok := tagsByScene[items[i].ID]; ok {
+					names := make([]string, len(tags))
+					for j, t := range tags {
+						names[j] = t.Name
+					}
+					items[i].Tags = names
+				}
+			}
+		}
+	}
+	if cardFields.Actors && len(sceneIDs) > 0 {
+		if actorsByScene, err := h.ActorRepo.GetSceneActorsMultiple(sceneIDs); err == nil {
+			for i := range items {
+				if actors, ok := actorsByScene[items[i].ID]; ok {
+					names := make([]string, len(actors))
+					for j, a := range actors {
+						names[j] = a.Name
+					}
+					items[i].Actors = names
+				}
+			}
+		}
+	}
+
+	listData, ok := selectSceneFields(c, items, fields)
+	if !ok {
+		return
+	}
+
+	resp := gin.H{
+		"data":  listData,
+		"limit": req.Limit,
+	}
+	if params.UseCursor {
+		resp["next_cursor"] = result.NextCursor
+	} else {
+		resp["total"] = result.Total
+		resp["page"] = req.Page
+	}
+	if result.Seed != 0 {
+		resp["seed"] = result.Seed
+	}
+
+	// Load interaction sidecar maps if requested
+	if userID > 0 {
+		if cardFields.Rating {
+			if ratings, err := h.InteractionRepo.GetRatingsBySceneIDs(userID, sceneIDs); err == nil {
+				resp["ratings"] = ratings
+			}
+		}
+		if cardFields.Liked {
+			if likes, err := h.InteractionRepo.GetLikesBySceneIDs(userID, sceneIDs); err == nil {
+				resp["likes"] = likes
+			}
+		}
+		if cardFields.JizzCount {
+			if jizzCounts, err := h.InteractionRepo.GetJizzCountsBySceneIDs(userID, sceneIDs); err == nil {
+				resp["jizz_counts"] = jizzCounts
+			}
+		}
+	}
+
+	response.OKWithETag(c, resp)
+}
+
+// RandomScene picks one random scene matching the scene list filters. Passing
+// the returned seed back picks the same scene again.
+func (h *SceneHandler) RandomScene(c *gin.Context) {
+	var req request.SearchScenesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	var userID uint
+	var role string
+	if payload, err := middleware.GetUserFromContext(c); err == nil {
+		userID = payload.UserID
+		role = payload.Role
+	}
+
+	params, err := h.sceneSearchParams(&req, userID, role)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	scene, seed, err := h.SearchService.RandomScene(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"scene": response.ToSceneListItem(*scene), "seed": seed})
+}
+
+// ShuffleQueue returns the next batch of a shuffle of the scenes matching the
+// scene list filters. Clients pass the seed of the first batch and the
+// next_offset of the previous one to continue without repeats.
+func (h *SceneHandler) ShuffleQueue(c *gin.Context) {
+	var req request.ShuffleQueueRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid query parameters")
+		return
+	}
+
+	var userID uint
+	var role string
 	if payload, err := middleware.GetUserFromContext(c); err == nil {
 		userID = payload.UserID
+		role = payload.Role
 	}
 
+	params, err := h.sceneSearchParams(&req.SearchScenesRequest, userID, role)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	batch, err := h.SearchService.ShuffleQueue(c.Request.Context(), params, req.Offset, req.Count)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	listData, ok := sceneListData(c, batch.Scenes)
+	if !ok {
+		return
+	}
+
+	response.OK(c, gin.H{
+		"data":        listData,
+		"total":       batch.Total,
+		"seed":        batch.Seed,
+		"next_offset": batch.NextOffset,
+		"done":        batch.Done,
+	})
+}
+
+// sceneSearchParams converts the scene list query parameters to search
+// parameters for userID, limited to the scenes role may see.
+func (h *SceneHandler) sceneSearchParams(req *request.SearchScenesRequest, userID uint, role string) (data.SceneSearchParams, error) {
 	// Map frontend match_type to Meilisearch matching strategy
 	var matchingStrategy string
 	switch req.MatchType {
@@ -117,20 +298,29 @@ func (h *SceneHandler) ListScenes(c *gin.Context) {
 		Sort:             req.Sort,
 		UserID:           userID,
 		Liked:            req.Liked,
+		IsCorrupted:      req.Corrupted,
 		MinRating:        req.MinRating,
 		MaxRating:        req.MaxRating,
 		MinJizzCount:     req.MinJizzCount,
 		MaxJizzCount:     req.MaxJizzCount,
 		MatchingStrategy: matchingStrategy,
 		Seed:             req.Seed,
+		Restriction:      h.Service.ContentRestriction(role),
+	}
+
+	if req.ReviewState != "" {
+		states, err := h.ReviewWorkflowService.StoredStates(req.ReviewState)
+		if err != nil {
+			return params, err
+		}
+		params.ReviewStates = states
 	}
 
 	if req.Tags != "" {
 		tagNames := strings.Split(req.Tags, ",")
 		tags, err := h.TagService.GetTagsByNames(tagNames)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve tags"})
-			return
+			return params, apperrors.NewInternalError("failed to resolve tags", err)
 		}
 		for _, tag := range tags {
 			params.TagIDs = append(params.TagIDs, tag.ID)
@@ -160,90 +350,55 @@ func (h *SceneHandler) ListScenes(c *gin.Context) {
 	}
 
 	if req.Resolution != "" {
-		if heights, ok := resolutionToHeight[req.Resolution]; ok {
+		if heights, ok := core.ResolutionHeights[req.Resolution]; ok {
 			params.MinHeight = heights[0]
 			params.MaxHeight = heights[1]
 		}
 	}
 
-	result, err := h.SearchService.Search(params)
+	// q may use the advanced query syntax; its field terms override the
+	// matching query parameters
+	parsed, err := core.ParseSearchQuery(req.Query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search scenes"})
-		return
+		return params, apperrors.NewValidationError(err.Error())
 	}
-
-	cardFields := response.ParseCardFields(c.Query("card_fields"))
-
-	var items []response.SceneListItem
-	if cardFields.HasAny() {
-		items = response.ToSceneListItemsWithFields(result.Scenes, cardFields)
-	} else {
-		items = response.ToSceneListItems(result.Scenes)
+	if parsed.HasFields() {
+		if _, err := h.SearchService.ApplySearchQuery(&params, parsed); err != nil {
+			return params, apperrors.NewInternalError("failed to apply search query", err)
+		}
 	}
+	return params, nil
+}
 
-	sceneIDs := make([]uint, len(result.Scenes))
-	for i, s := range result.Scenes {
-		sceneIDs[i] = s.ID
+// SearchSegments finds scenes with marker labels matching q and returns the
+// matching positions, each with a watch URL that starts playback there.
+func (h *SceneHandler) SearchSegments(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, apperrors.NewUnauthorizedError("authentication required"))
+		return
 	}
 
-	// Load tags/actors from join tables when requested
-	if cardFields.Tags && len(sceneIDs) > 0 {
-		if tagsByScene, err := h.TagRepo.GetSceneTagsMultiple(sceneIDs); err == nil {
-			for i := range items {
-				if tags, ok := tagsByScene[items[i].ID]; ok {
-					names := make([]string, len(tags))
-					for j, t := range tags {
-						names[j] = t.Name
-					}
-					items[i].Tags = names
-				}
-			}
-		}
-	}
-	if cardFields.Actors && len(sceneIDs) > 0 {
-		if actorsByScene, err := h.ActorRepo.GetSceneActorsMultiple(sceneIDs); err == nil {
-			for i := range items {
-				if actors, ok := actorsByScene[items[i].ID]; ok {
-					names := make([]string, len(actors))
-					for j, a := range actors {
-						names[j] = a.Name
-					}
-					items[i].Actors = names
-				}
-			}
-		}
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		response.BadRequest(c, "Query is required")
+		return
 	}
 
-	resp := gin.H{
-		"data":  items,
-		"total": result.Total,
-		"page":  req.Page,
-		"limit": req.Limit,
-	}
-	if result.Seed != 0 {
-		resp["seed"] = result.Seed
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
 	}
 
-	// Load interaction sidecar maps if requested
-	if userID > 0 {
-		if cardFields.Rating {
-			if ratings, err := h.InteractionRepo.GetRatingsBySceneIDs(userID, sceneIDs); err == nil {
-				resp["ratings"] = ratings
-			}
-		}
-		if cardFields.Liked {
-			if likes, err := h.InteractionRepo.GetLikesBySceneIDs(userID, sceneIDs); err == nil {
-				resp["likes"] = likes
-			}
-		}
-		if cardFields.JizzCount {
-			if jizzCounts, err := h.InteractionRepo.GetJizzCountsBySceneIDs(userID, sceneIDs); err == nil {
-				resp["jizz_counts"] = jizzCounts
-			}
-		}
+	results, err := h.SearchService.SearchSegments(c.Request.Context(), payload.UserID, h.Service.ContentRestriction(payload.Role), query, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, resp)
+	response.OK(c, gin.H{"results": response.ToSceneSegmentResults(results)})
 }
 
 func (h *SceneHandler) GetFilterOptions(c *gin.Context) {
@@ -323,11 +478,15 @@ func (h *SceneHandler) DeleteScene(c *gin.Context) {
 
 	if req.Permanent {
 		// Permanent delete
-		if err := h.Service.HardDeleteScene(uint(id)); err != nil {
+		if err := h.Service.PermanentlyDeleteScene(uint(id), req.Force); err != nil {
 			if apperrors.IsNotFound(err) {
 				c.JSON(http.StatusNotFound, gin.H{"error": "Scene not found"})
 				return
 			}
+			if apperrors.IsSceneDeletionProtected(err) {
+				response.Error(c, err)
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete scene"})
 			return
 		}
@@ -336,12 +495,16 @@ func (h *SceneHandler) DeleteScene(c *gin.Context) {
 	}
 
 	// Move to trash
-	expiresAt, err := h.Service.MoveSceneToTrash(uint(id))
+	expiresAt, err := h.Service.MoveSceneToTrash(uint(id), req.Force)
 	if err != nil {
 		if apperrors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Scene not found"})
 			return
 		}
+		if apperrors.IsSceneDeletionProtected(err) {
+			response.Error(c, err)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move scene to trash"})
 		return
 	}
@@ -360,7 +523,7 @@ func (h *SceneHandler) GetScene(c *gin.Context) {
 		return
 	}
 
-	scene, err := h.Service.GetScene(uint(id))
+	scene, err := h.Service.GetSceneForRole(uint(id), requestRole(c))
 	if err != nil {
 		if apperrors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Scene not found"})
@@ -370,7 +533,25 @@ func (h *SceneHandler) GetScene(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, scene)
+	response.OKWithETag(c, scene)
+}
+
+// GetIntegrityReport returns the latest decode verification report for a scene
+func (h *SceneHandler) GetIntegrityReport(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+		return
+	}
+
+	report, err := h.Service.GetIntegrityReport(uint(id), requestRole(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
 }
 
 func (h *SceneHandler) StreamScene(c *gin.Context) {
@@ -381,7 +562,155 @@ func (h *SceneHandler) StreamScene(c *gin.Context) {
 		return
 	}
 
-	sceneID := uint(id)
+	if !h.checkSceneAccess(c, uint(id)) {
+		return
+	}
+
+	h.serveSceneFile(c, uint(id), streamUserID(c), func(path string) string {
+		mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+		if mimeType == "" {
+			mimeType = "video/mp4"
+		}
+		return mimeType
+	})
+}
+
+// StreamCast serves a scene to a cast device (Chromecast or DLNA renderer) from
+// a signed cast URL. Cast devices have no session, so the token authorizes the
+// request and attributes bandwidth to the user who started casting.
+func (h *SceneHandler) StreamCast(c *gin.Context) {
+	token, err := h.CastService.ResolveToken(c.Param("token"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// DLNA renderers expect these to treat the response as a seekable stream
+	c.Header("transferMode.dlna.org", "Streaming")
+	c.Header("contentFeatures.dlna.org", "DLNA.ORG_OP=01;DLNA.ORG_CI=0;DLNA.ORG_FLAGS=01700000000000000000000000000000")
+
+	h.serveSceneFile(c, token.SceneID, token.UserID, core.CastMimeType)
+}
+
+// GetCastInfo returns signed cast URLs for a scene.
+func (h *SceneHandler) GetCastInfo(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+		return
+	}
+
+	if !h.checkSceneAccess(c, uint(id)) {
+		return
+	}
+
+	info, err := h.CastService.GetCastInfo(uint(id), payload.UserID, requestBaseURL(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// GetMediaURLs returns the media URLs of a scene, signed when media signing is
+// enabled so they can be handed to clients without a session cookie.
+func (h *SceneHandler) GetMediaURLs(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	scene, err := h.Service.GetSceneForRole(uint(id), requestRole(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	sign := func(path string) string {
+		return h.MediaSigner.SignPath(path, scene.ID)
+	}
+	optional := func(available bool, path string) string {
+		if !available {
+			return ""
+		}
+		return sign(path)
+	}
+
+	var expiresAt *time.Time
+	if h.MediaSigner.Enabled() {
+		t := h.MediaSigner.ExpiresAt()
+		expiresAt = &t
+	}
+
+	response.OK(c, gin.H{
+		"thumbnail_url":    sign(fmt.Sprintf("/thumbnails/%d", scene.ID)),
+		"thumbnail_lg_url": sign(fmt.Sprintf("/thumbnails/%d?size=lg", scene.ID)),
+		"vtt_url":          optional(scene.VttPath != "", fmt.Sprintf("/vtt/%d", scene.ID)),
+		"trickplay_url":    optional(scene.TrickplayPath != "", fmt.Sprintf("/trickplay/%d", scene.ID)),
+		"preview_url":      optional(scene.PreviewVideoPath != "", fmt.Sprintf("/scene-previews/%d", scene.ID)),
+		"expires_at":       expiresAt,
+	})
+}
+
+// GetFrame returns the sprite sheet tile previewing second t of a scene, so
+// players can show a seek preview without downloading whole sprite sheets.
+func (h *SceneHandler) GetFrame(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	t, err := strconv.ParseFloat(c.Query("t"), 64)
+	if err != nil || math.IsNaN(t) || math.IsInf(t, 0) {
+		response.BadRequest(c, "Invalid time: t must be a number of seconds")
+		return
+	}
+
+	format, err := core.NormalizeFrameFormat(c.Query("format"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if !h.checkSceneAccess(c, uint(id)) {
+		return
+	}
+
+	frame, err := h.SpriteFrameService.ResolveFrame(uint(id), t)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	etag := frame.ETag(format)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=86400")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	img, err := h.SpriteFrameService.RenderFrame(c.Request.Context(), frame, format)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, core.FrameContentType(format), img)
+}
+
+// serveSceneFile streams a scene's file with Range support under the stream
+// slot limits and bandwidth throttle. contentType maps the file path to its MIME type.
+func (h *SceneHandler) serveSceneFile(c *gin.Context, sceneID, userID uint, contentType func(path string) string) {
 	clientIP := c.ClientIP()
 
 	// Acquire stream slot (global + per-IP limits).
@@ -424,20 +753,172 @@ func (h *SceneHandler) StreamScene(c *gin.Context) {
 		return
 	}
 
-	ext := strings.ToLower(filepath.Ext(filePath))
-	mimeType := mime.TypeByExtension(ext)
-	if mimeType == "" {
-		mimeType = "video/mp4"
-	}
-
-	c.Header("Content-Type", mimeType)
+	c.Header("Content-Type", contentType(filePath))
 	c.Header("Cache-Control", "public, max-age=86400")
 
 	// Use the buffer pool for efficient I/O (256KB vs Go's default 32KB)
 	buf := h.StreamManager.BufferPool().Get()
 	defer h.StreamManager.BufferPool().Put(buf)
 
-	streaming.ServeVideo(c.Writer, c.Request, filepath.Base(filePath), fileInfo.ModTime(), file, buf)
+	stream := h.StreamManager.OpenStream(c.Request.Context(), userID, clientIP)
+	defer stream.Close()
+
+	streaming.ServeVideo(stream.Wrap(c.Writer), c.Request, filepath.Base(filePath), fileInfo.ModTime(), file, buf)
+}
+
+// NegotiatePlayback decides between direct play and on-the-fly transcode for the
+// client's capabilities and returns the URL to stream from.
+func (h *SceneHandler) NegotiatePlayback(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+		return
+	}
+
+	if !h.checkSceneAccess(c, uint(id)) {
+		return
+	}
+
+	var req request.PlaybackRequest
+	// Ignore binding errors - body is optional, defaults assume browser formats
+	_ = c.ShouldBindJSON(&req)
+
+	decision, err := h.StreamManager.DecidePlayback(uint(id), streaming.ClientCapabilities{
+		Containers:  req.Containers,
+		VideoCodecs: req.VideoCodecs,
+		AudioCodecs: req.AudioCodecs,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scene not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scene"})
+		return
+	}
+
+	c.JSON(http.StatusOK, decision)
+}
+
+// StreamSceneTranscode streams the scene transcoded to fragmented H.264/AAC MP4.
+// The output cannot be range-requested; clients seek by reloading with ?start=<seconds>.
+func (h *SceneHandler) StreamSceneTranscode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+		return
+	}
+
+	var start float64
+	if startStr := c.Query("start"); startStr != "" {
+		start, err = strconv.ParseFloat(startStr, 64)
+		if err != nil || start < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start position"})
+			return
+		}
+	}
+
+	sceneID := uint(id)
+	if !h.checkSceneAccess(c, sceneID) {
+		return
+	}
+
+	clientIP := c.ClientIP()
+
+	if !h.StreamManager.Limiter().Acquire(clientIP, sceneID) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Too many concurrent streams",
+			"code":  "STREAM_LIMIT_EXCEEDED",
+		})
+		return
+	}
+	defer h.StreamManager.Limiter().Release(clientIP, sceneID)
+
+	filePath, opts, err := h.StreamManager.TranscodeOptions(sceneID, start)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scene not found"})
+			return
+		}
+		if strings.Contains(err.Error(), "disabled") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Transcoding is disabled"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scene"})
+		return
+	}
+
+	if _, err := os.Stat(filePath); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scene file not found"})
+		return
+	}
+
+	if !h.StreamManager.AcquireTranscode() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Too many concurrent transcodes",
+			"code":  "TRANSCODE_LIMIT_EXCEEDED",
+		})
+		return
+	}
+	defer h.StreamManager.ReleaseTranscode()
+
+	c.Header("Content-Type", "video/mp4")
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	userID := streamUserID(c)
+
+	// Chapters are best effort: a scene whose markers fail to load still streams
+	if h.StreamManager.EmbedChapters() && h.MarkerService != nil && userID != 0 {
+		if chapters, err := h.MarkerService.SceneChapters(userID, sceneID); err == nil {
+			opts.Chapters = chapters
+		}
+	}
+
+	stream := h.StreamManager.OpenStream(c.Request.Context(), userID, clientIP)
+	defer stream.Close()
+
+	// ffmpeg is killed when the client disconnects and the request context is cancelled
+	h.StreamManager.Transcode(c.Request.Context(), sceneID, filePath, opts, stream.Wrap(c.Writer))
+}
+
+// streamUserID returns the authenticated user's ID on a public stream route, or 0
+func streamUserID(c *gin.Context) uint {
+	if user, err := middleware.GetUserFromContext(c); err == nil {
+		return user.UserID
+	}
+	return 0
+}
+
+// requestRole returns the authenticated user's role, or "" when there is no user
+func requestRole(c *gin.Context) string {
+	if user, err := middleware.GetUserFromContext(c); err == nil {
+		return user.Role
+	}
+	return ""
+}
+
+// checkSceneAccess responds with not found and returns false when the user's
+// role is restricted from the scene. Requests without a user are treated as the
+// most restricted role, so they are denied once any role has a restriction.
+func (h *SceneHandler) checkSceneAccess(c *gin.Context, sceneID uint) bool {
+	if err := h.Service.CheckSceneAccess(sceneID, requestRole(c)); err != nil {
+		response.Error(c, err)
+		return false
+	}
+	return true
+}
+
+// requestBaseURL returns the scheme and host the client reached the server at.
+func requestBaseURL(c *gin.Context) string {
+	scheme := c.GetHeader("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		}
+	}
+	return scheme + "://" + c.Request.Host
 }
 
 func (h *SceneHandler) ExtractThumbnail(c *gin.Context) {
@@ -629,11 +1110,10 @@ func (h *SceneHandler) ApplySceneMetadata(c *gin.Context) {
 	// Import tags if provided (best-effort, skip on errors)
 	if len(req.TagNames) > 0 {
 		if existingTags, err := h.TagService.GetTagsByNames(req.TagNames); err == nil {
-			// Build set of existing tag names for fast lookup
-			existingNames := make(map[string]struct{}, len(existingTags))
+			// Build set of existing tag names and aliases for fast lookup
+			existingNames := data.TagIDsByName(existingTags)
 			allTagIDs := make([]uint, 0, len(req.TagNames))
 			for _, t := range existingTags {
-				existingNames[t.Name] = struct{}{}
 				allTagIDs = append(allTagIDs, t.ID)
 			}
 
@@ -671,6 +1151,37 @@ func (h *SceneHandler) ApplySceneMetadata(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedScene)
 }
 
+// GetSimilarScenes returns the "more like this" list of a scene, ranked by a
+// similarity score.
+func (h *SceneHandler) GetSimilarScenes(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid scene ID")
+		return
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	role := requestRole(c)
+	if _, err := h.Service.GetSceneForRole(uint(id), role); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	scenes, err := h.RelatedScenesService.GetSimilarScenes(uint(id), role, limit)
+	if err != nil {
+		response.Error(c, apperrors.NewInternalError("failed to get similar scenes", err))
+		return
+	}
+
+	response.OK(c, gin.H{"results": response.ToSimilarSceneResults(scenes)})
+}
+
 func (h *SceneHandler) GetRelatedScenes(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -690,8 +1201,9 @@ func (h *SceneHandler) GetRelatedScenes(c *gin.Context) {
 		limit = 50
 	}
 
-	// Verify the scene exists
-	_, err = h.Service.GetScene(uint(id))
+	// Verify the scene exists and the user may see it
+	role := requestRole(c)
+	_, err = h.Service.GetSceneForRole(uint(id), role)
 	if err != nil {
 		if apperrors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Scene not found"})
@@ -706,13 +1218,14 @@ func (h *SceneHandler) GetRelatedScenes(c *gin.Context) {
 		userID = payload.UserID
 	}
 
-	scenes, err := h.RelatedScenesService.GetRelatedScenes(uint(id), userID, limit)
+	scenes, err := h.RelatedScenesService.GetRelatedScenes(uint(id), userID, role, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get related scenes"})
 		return
 	}
 
-	cardFields := response.ParseCardFields(c.Query("card_fields"))
+	fields := response.ParseSparseFields(c.Query("fields"))
+	cardFields := response.ParseCardFields(c.Query("card_fields")).Merge(fields.CardFields())
 
 	var items []response.SceneListItem
 	if cardFields.HasAny() {
@@ -754,8 +1267,13 @@ func (h *SceneHandler) GetRelatedScenes(c *gin.Context) {
 		}
 	}
 
+	listData, ok := selectSceneFields(c, items, fields)
+	if !ok {
+		return
+	}
+
 	resp := gin.H{
-		"data":  items,
+		"data":  listData,
 		"total": len(scenes),
 	}
 
@@ -780,3 +1298,22 @@ func (h *SceneHandler) GetRelatedScenes(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// sceneListData converts scenes to list items reduced to the request's fields=
+// selection. It writes the error response and returns false for an unknown
+// field.
+func sceneListData(c *gin.Context, scenes []data.Scene) (any, bool) {
+	fields := response.ParseSparseFields(c.Query("fields"))
+	return selectSceneFields(c, response.ToSceneListItemsWithFields(scenes, fields.CardFields()), fields)
+}
+
+// selectSceneFields reduces scene list items to a fields= selection. It writes
+// the error response and returns false for an unknown field.
+func selectSceneFields(c *gin.Context, items []response.SceneListItem, fields response.SparseFields) (any, bool) {
+	selected, err := response.SelectFields(items, fields)
+	if err != nil {
+		response.Error(c, err)
+		return nil, false
+	}
+	return selected, true
+}
@@ -214,6 +214,38 @@ func TestStreamScene_SceneNotFound(t *testing.T) {
 	}
 }
 
+func TestStreamScene_AnonymousRestricted(t *testing.T) {
+	handler, sceneRepo, dataPath := newTestSceneHandler(t)
+	ctrl := gomock.NewController(t)
+	roleRepo := mocks.NewMockRoleRepository(ctrl)
+	restrictionRepo := mocks.NewMockContentRestrictionRepository(ctrl)
+
+	roleRepo.EXPECT().GetAllRolePermissions().Return(map[string][]string{"guest": {"scenes:view"}}, nil)
+	rbac, _ := core.NewRBACService(roleRepo, mocks.NewMockPermissionRepository(ctrl), zap.NewNop())
+	restrictionRepo.EXPECT().ListAll().Return([]data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", StoragePathIDs: []int64{1}},
+	}, nil)
+	if err := rbac.SetContentRestrictionRepository(restrictionRepo); err != nil {
+		t.Fatalf("failed to load content restrictions: %v", err)
+	}
+	handler.Service.SetRBACService(rbac)
+
+	// The scene is never loaded: anonymous clients are denied before the file lookup
+	filePath := createTestFile(t, dataPath, "test.mp4", []byte("restricted"))
+	sceneRepo.EXPECT().GetByID(uint(1)).Return(&data.Scene{ID: 1, StoredPath: filePath}, nil).Times(0)
+
+	router := gin.New()
+	router.GET("/stream/:id", handler.StreamScene)
+
+	req, _ := http.NewRequest("GET", "/stream/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
 func TestStreamScene_FileNotOnDisk(t *testing.T) {
 	handler, sceneRepo, _ := newTestSceneHandler(t)
 
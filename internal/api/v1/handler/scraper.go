@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScraperHandler struct {
+	Service *core.SceneScrapeService
+}
+
+func NewScraperHandler(service *core.SceneScrapeService) *ScraperHandler {
+	return &ScraperHandler{Service: service}
+}
+
+// ListScrapers returns the site scrapers and the generic one used for every other site
+func (h *ScraperHandler) ListScrapers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.Service.ListScrapers()})
+}
+
+// PreviewScrape returns what a scene page scrapes to without changing any scene
+func (h *ScraperHandler) PreviewScrape(c *gin.Context) {
+	var req request.PreviewScrapeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: url is required"})
+		return
+	}
+
+	scraped, err := h.Service.Preview(c.Request.Context(), req.URL)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, scraped)
+}
+
+// ScrapeScene populates a scene's metadata from the page at the given URL
+func (h *ScraperHandler) ScrapeScene(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+		return
+	}
+
+	var req request.ScrapeSceneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: url is required"})
+		return
+	}
+
+	result, err := h.Service.ScrapeScene(c.Request.Context(), uint(id), req.URL, req.Fields)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -1,27 +1,67 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"goonhub/internal/api/v1/response"
+	"goonhub/internal/apperrors"
 	"goonhub/internal/core"
 	"goonhub/internal/data"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type SearchHandler struct {
-	searchService    *core.SearchService
-	searchConfigRepo data.SearchConfigRepository
+	searchService            *core.SearchService
+	searchReindexService     *core.SearchReindexService
+	searchConsistencyService *core.SearchConsistencyService
+	searchConfigRepo         data.SearchConfigRepository
 }
 
-func NewSearchHandler(searchService *core.SearchService, searchConfigRepo data.SearchConfigRepository) *SearchHandler {
+func NewSearchHandler(searchService *core.SearchService, searchReindexService *core.SearchReindexService, searchConsistencyService *core.SearchConsistencyService, searchConfigRepo data.SearchConfigRepository) *SearchHandler {
 	return &SearchHandler{
-		searchService:    searchService,
-		searchConfigRepo: searchConfigRepo,
+		searchService:            searchService,
+		searchReindexService:     searchReindexService,
+		searchConsistencyService: searchConsistencyService,
+		searchConfigRepo:         searchConfigRepo,
 	}
 }
 
-// ReindexAll triggers a full reindex of all videos in Meilisearch.
+// ValidateQuery parses an advanced search query for the search bar. A query
+// that does not parse is reported with the error position; one that does is
+// returned parsed, with warnings for tag names that do not exist.
+// GET /search/validate?q=
+func (h *SearchHandler) ValidateQuery(c *gin.Context) {
+	parsed, err := core.ParseSearchQuery(c.Query("q"))
+	if err != nil {
+		var parseErr *core.QueryParseError
+		if !errors.As(err, &parseErr) {
+			response.Error(c, apperrors.NewInternalError("failed to parse query", err))
+			return
+		}
+		response.OK(c, gin.H{"valid": false, "error": parseErr})
+		return
+	}
+
+	warnings := []string{}
+	var params data.SceneSearchParams
+	unknown, err := h.searchService.ApplySearchQuery(&params, parsed)
+	if err != nil {
+		response.Error(c, apperrors.NewInternalError("failed to validate query", err))
+		return
+	}
+	for _, name := range unknown {
+		warnings = append(warnings, fmt.Sprintf("unknown tag %q is ignored", name))
+	}
+
+	response.OK(c, gin.H{"valid": true, "query": parsed, "warnings": warnings})
+}
+
+// ReindexAll starts a full rebuild of the search index in the background.
+// Progress is published as search:reindex_* SSE events. With ?resume=true an
+// interrupted rebuild continues from its checkpoint.
 // POST /admin/search/reindex
 func (h *SearchHandler) ReindexAll(c *gin.Context) {
 	if !h.searchService.IsAvailable() {
@@ -29,14 +69,44 @@ func (h *SearchHandler) ReindexAll(c *gin.Context) {
 		return
 	}
 
-	// Run reindex in background
-	go func() {
-		if err := h.searchService.ReindexAll(); err != nil {
-			// Error is logged in ReindexAll
-		}
-	}()
+	status, err := h.searchReindexService.Start(c.Query("resume") == "true")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Reindex started", "status": status})
+}
+
+// GetReindexStatus returns the progress of the running or last full reindex.
+// GET /admin/search/reindex
+func (h *SearchHandler) GetReindexStatus(c *gin.Context) {
+	status, err := h.searchReindexService.Status()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"status": status})
+}
+
+// GetConsistency returns the report of the last index consistency check.
+// GET /admin/search/consistency
+func (h *SearchHandler) GetConsistency(c *gin.Context) {
+	response.OK(c, gin.H{"report": h.searchConsistencyService.LastReport()})
+}
+
+// CheckConsistency compares scene IDs in the index with the database now.
+// With ?heal=true missing scenes are re-indexed and orphaned documents deleted.
+// POST /admin/search/consistency
+func (h *SearchHandler) CheckConsistency(c *gin.Context) {
+	report, err := h.searchConsistencyService.Check(c.Query("heal") == "true")
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
 
-	c.JSON(http.StatusAccepted, gin.H{"message": "Reindex started"})
+	response.OK(c, gin.H{"report": report})
 }
 
 // GetStatus returns the status of the search service.
@@ -44,9 +114,50 @@ func (h *SearchHandler) ReindexAll(c *gin.Context) {
 func (h *SearchHandler) GetStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"available": h.searchService.IsAvailable(),
+		"backend":   h.searchService.ActiveBackend(),
 	})
 }
 
+// GetDiagnostics compares the index against the database to debug scenes missing from search.
+// GET /admin/search/diagnostics
+func (h *SearchHandler) GetDiagnostics(c *gin.Context) {
+	if !h.searchService.IsAvailable() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Meilisearch is not available"})
+		return
+	}
+
+	diagnostics, err := h.searchService.Diagnostics()
+	if err != nil {
+		response.InternalError(c, "failed to get search diagnostics: "+err.Error())
+		return
+	}
+
+	response.OK(c, diagnostics)
+}
+
+// GetSceneIndexStatus reports whether a single scene is in the search index.
+// GET /admin/search/diagnostics/scenes/:id
+func (h *SearchHandler) GetSceneIndexStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "invalid scene ID")
+		return
+	}
+
+	if !h.searchService.IsAvailable() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Meilisearch is not available"})
+		return
+	}
+
+	status, err := h.searchService.SceneIndexStatus(uint(id))
+	if err != nil {
+		response.InternalError(c, "failed to check scene index status: "+err.Error())
+		return
+	}
+
+	response.OK(c, status)
+}
+
 // GetSearchConfig returns the current search configuration.
 // GET /admin/search/config
 func (h *SearchHandler) GetSearchConfig(c *gin.Context) {
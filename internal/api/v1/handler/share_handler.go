@@ -1,18 +1,18 @@
 package handler
 
 import (
+	"fmt"
 	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
+	"goonhub/internal/api/middleware"
 	"goonhub/internal/api/v1/request"
 	"goonhub/internal/api/v1/response"
 	"goonhub/internal/core"
-	"goonhub/internal/data"
 	"goonhub/internal/streaming"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +23,8 @@ type ShareHandler struct {
 	AuthService   *core.AuthService
 	StreamManager *streaming.Manager
 	ShareBaseURL  string
+	ThumbnailDir  string
+	SecureCookies bool
 }
 
 func NewShareHandler(
@@ -30,12 +32,16 @@ func NewShareHandler(
 	authService *core.AuthService,
 	streamManager *streaming.Manager,
 	shareBaseURL string,
+	thumbnailDir string,
+	secureCookies bool,
 ) *ShareHandler {
 	return &ShareHandler{
 		ShareService:  shareService,
 		AuthService:   authService,
 		StreamManager: streamManager,
 		ShareBaseURL:  shareBaseURL,
+		ThumbnailDir:  thumbnailDir,
+		SecureCookies: secureCookies,
 	}
 }
 
@@ -72,7 +78,7 @@ func (h *ShareHandler) CreateShareLink(c *gin.Context) {
 		return
 	}
 
-	link, err := h.ShareService.CreateShareLink(userID, uint(sceneID), req.ShareType, req.ExpiresIn)
+	link, err := h.ShareService.CreateShareLink(userID, uint(sceneID), req.ShareType, req.ExpiresIn, req.Password)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -133,71 +139,76 @@ func (h *ShareHandler) DeleteShareLink(c *gin.Context) {
 
 // isAuthenticated checks if the request has a valid auth cookie/token.
 func (h *ShareHandler) isAuthenticated(c *gin.Context) bool {
-	token := ""
-
-	if cookie, err := c.Cookie("goonhub_auth"); err == nil && cookie != "" {
-		token = cookie
-	}
-
-	if token == "" {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			t := strings.TrimPrefix(authHeader, "Bearer ")
-			if t != authHeader {
-				token = t
-			}
-		}
-	}
-
+	token := middleware.TokenFromRequest(c)
 	if token == "" {
 		return false
 	}
-
 	_, err := h.AuthService.ValidateToken(token)
 	return err == nil
 }
 
-// ResolveShareLink resolves a share token to scene data.
-func (h *ShareHandler) ResolveShareLink(c *gin.Context) {
+// UnlockShareLink checks a password-protected share link's password and sets the
+// share access cookie for the link. The access token is also returned for clients
+// that pass it as the "access" query parameter instead.
+func (h *ShareHandler) UnlockShareLink(c *gin.Context) {
 	token := c.Param("token")
-	if token == "" {
-		response.BadRequest(c, "missing share token")
+
+	var req request.UnlockShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request: "+err.Error())
 		return
 	}
 
-	resolved, err := h.ShareService.ResolveShareLink(token, h.isAuthenticated(c))
+	access, err := h.ShareService.UnlockShareLink(token, h.isAuthenticated(c), req.Password)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	response.OK(c, resolved)
+	http.SetCookie(c.Writer, middleware.ShareAccessCookie(token, access, h.SecureCookies))
+	response.OK(c, access)
 }
 
-// StreamShareLink streams the video for a share link.
-func (h *ShareHandler) StreamShareLink(c *gin.Context) {
-	token := c.Param("token")
-	if token == "" {
-		response.BadRequest(c, "missing share token")
+// ResolveShareLink returns the scene data of a share link authorized by the
+// ShareAccess middleware.
+func (h *ShareHandler) ResolveShareLink(c *gin.Context) {
+	link, ok := middleware.GetShareLinkFromContext(c)
+	if !ok {
+		response.BadRequest(c, "missing share link")
 		return
 	}
 
-	// Validate the share link
-	link, err := h.ShareService.GetShareLinkByToken(token)
+	resolved, err := h.ShareService.ResolveShareLink(link)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
+	// The poster goes through the share routes so it works for anonymous viewers
+	// and stays behind the link's password
+	resolved.ThumbnailURL = fmt.Sprintf("/api/v1/shares/%s/thumbnail", link.Token)
+
+	response.OK(c, resolved)
+}
 
-	// Check expiry
-	if link.ExpiresAt != nil && link.ExpiresAt.Before(time.Now()) {
-		c.JSON(http.StatusGone, gin.H{"error": "share link has expired", "code": "SHARE_LINK_EXPIRED"})
+// ShareThumbnail serves the large thumbnail of a share link's scene.
+func (h *ShareHandler) ShareThumbnail(c *gin.Context) {
+	link, ok := middleware.GetShareLinkFromContext(c)
+	if !ok {
+		response.BadRequest(c, "missing share link")
 		return
 	}
 
-	// Check auth for auth_required type
-	if link.ShareType == data.ShareTypeAuthRequired && !h.isAuthenticated(c) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required", "code": "AUTH_REQUIRED"})
+	c.Header("Content-Type", "image/webp")
+	c.Header("Cache-Control", "private, max-age=3600")
+	response.File(c, filepath.Join(h.ThumbnailDir, fmt.Sprintf("%d_thumb_lg.webp", link.SceneID)))
+}
+
+// StreamShareLink streams the video of a share link authorized by the
+// ShareAccess middleware.
+func (h *ShareHandler) StreamShareLink(c *gin.Context) {
+	link, ok := middleware.GetShareLinkFromContext(c)
+	if !ok {
+		response.BadRequest(c, "missing share link")
 		return
 	}
 
@@ -249,7 +260,14 @@ func (h *ShareHandler) StreamShareLink(c *gin.Context) {
 	}
 
 	c.Header("Content-Type", mimeType)
-	c.Header("Cache-Control", "public, max-age=86400")
+	if link.PasswordHash != "" {
+		c.Header("Cache-Control", "private, max-age=86400")
+	} else {
+		c.Header("Cache-Control", "public, max-age=86400")
+	}
+
+	stream := h.StreamManager.OpenStream(c.Request.Context(), 0, clientIP)
+	defer stream.Close()
 
-	http.ServeContent(c.Writer, c.Request, filepath.Base(filePath), fileInfo.ModTime(), file)
+	http.ServeContent(stream.Wrap(c.Writer), c.Request, filepath.Base(filePath), fileInfo.ModTime(), file)
 }
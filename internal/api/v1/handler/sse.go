@@ -51,7 +51,7 @@ func (h *SSEHandler) Stream(c *gin.Context) {
 		return
 	}
 
-	_, err = h.authService.ValidateToken(token)
+	payload, err := h.authService.ValidateToken(token)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 		return
@@ -94,6 +94,9 @@ func (h *SSEHandler) Stream(c *gin.Context) {
 			if !ok {
 				return
 			}
+			if event.UserID != 0 && event.UserID != payload.UserID {
+				continue
+			}
 			data, err := json.Marshal(event)
 			if err != nil {
 				h.logger.Error("Failed to marshal event", zap.Error(err))
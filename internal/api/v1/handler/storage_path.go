@@ -4,19 +4,25 @@ import (
 	"goonhub/internal/api/v1/request"
 	"goonhub/internal/api/v1/response"
 	"goonhub/internal/core"
+	"goonhub/internal/data"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type StoragePathHandler struct {
-	Service *core.StoragePathService
+	Service       *core.StoragePathService
+	ScanScheduler *core.ScanScheduler
+	Health        *core.StorageHealthService
 }
 
-func NewStoragePathHandler(service *core.StoragePathService) *StoragePathHandler {
+func NewStoragePathHandler(service *core.StoragePathService, scanScheduler *core.ScanScheduler, health *core.StorageHealthService) *StoragePathHandler {
 	return &StoragePathHandler{
-		Service: service,
+		Service:       service,
+		ScanScheduler: scanScheduler,
+		Health:        health,
 	}
 }
 
@@ -27,11 +33,59 @@ func (h *StoragePathHandler) List(c *gin.Context) {
 		return
 	}
 
+	var nextScans map[uint]time.Time
+	if h.ScanScheduler != nil {
+		nextScans = h.ScanScheduler.NextRuns()
+	}
+
+	var health map[uint]data.StoragePathHealthSample
+	if h.Health != nil {
+		health = h.Health.Statuses()
+	}
+
+	response.OK(c, gin.H{
+		"storage_paths": response.ToStoragePathsWithUsage(paths, usageMap, nextScans, health),
+	})
+}
+
+// GetHealthHistory returns a storage path's latest health check and its
+// checks over the last days.
+func (h *StoragePathHandler) GetHealthHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid storage path ID"})
+		return
+	}
+	days, err := strconv.Atoi(c.DefaultQuery("days", "0"))
+	if err != nil {
+		response.BadRequest(c, "Invalid days")
+		return
+	}
+
+	samples, err := h.Health.History(uint(id), days)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	response.OK(c, gin.H{
-		"storage_paths": response.ToStoragePathsWithUsage(paths, usageMap),
+		"status":  h.Health.Status(uint(id)),
+		"samples": samples,
 	})
 }
 
+// CheckHealth checks every storage path now instead of waiting for the next
+// periodic check.
+func (h *StoragePathHandler) CheckHealth(c *gin.Context) {
+	samples, err := h.Health.CheckAll()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"storage_paths": samples})
+}
+
 func (h *StoragePathHandler) Create(c *gin.Context) {
 	var req request.CreateStoragePathRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -81,10 +135,67 @@ func (h *StoragePathHandler) Delete(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	_ = h.refreshScanSchedules()
 
 	c.JSON(http.StatusOK, gin.H{"message": "Storage path deleted successfully"})
 }
 
+func (h *StoragePathHandler) UpdateScanSchedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid storage path ID"})
+		return
+	}
+
+	var req request.UpdateScanScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	storagePath, err := h.Service.UpdateScanSchedule(uint(id), req.ScanSchedule, req.Enabled)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.refreshScanSchedules(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Scan schedule saved but failed to refresh scheduler"})
+		return
+	}
+
+	c.JSON(http.StatusOK, storagePath)
+}
+
+func (h *StoragePathHandler) UpdateExcludePatterns(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid storage path ID"})
+		return
+	}
+
+	var req request.UpdateExcludePatternsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	storagePath, err := h.Service.UpdateExcludePatterns(uint(id), req.ExcludePatterns)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, storagePath)
+}
+
+func (h *StoragePathHandler) refreshScanSchedules() error {
+	if h.ScanScheduler == nil {
+		return nil
+	}
+	return h.ScanScheduler.RefreshSchedules()
+}
+
 func (h *StoragePathHandler) ValidatePath(c *gin.Context) {
 	var req request.ValidatePathRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
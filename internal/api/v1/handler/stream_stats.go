@@ -26,5 +26,6 @@ func (h *StreamStatsHandler) GetStreamStats(c *gin.Context) {
 		"max_per_ip":      stats.Stream.MaxPerIP,
 		"active_ips":      stats.Stream.ActiveIPs,
 		"path_cache_size": stats.CacheSize,
+		"bandwidth":       stats.Bandwidth,
 	})
 }
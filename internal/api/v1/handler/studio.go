@@ -109,20 +109,53 @@ func (h *StudioHandler) GetStudioScenes(c *gin.Context) {
 		return
 	}
 
-	scenes, total, err := h.Service.GetStudioScenes(studio.ID, page, limit)
+	includeChildren := c.Query("include_children") == "true"
+	scenes, total, err := h.Service.GetStudioScenes(studio.ID, requestRole(c), page, limit, includeChildren)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get studio scenes"})
 		return
 	}
 
+	listData, ok := sceneListData(c, scenes)
+	if !ok {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":  response.ToSceneListItems(scenes),
+		"data":  listData,
 		"total": total,
 		"page":  page,
 		"limit": limit,
 	})
 }
 
+// GetStudioChildren returns the studios whose parent or network is the studio
+func (h *StudioHandler) GetStudioChildren(c *gin.Context) {
+	uuidStr := c.Param("uuid")
+	if _, err := uuid.Parse(uuidStr); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid studio UUID"})
+		return
+	}
+
+	studio, err := h.Service.GetByUUID(uuidStr)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Studio not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get studio"})
+		return
+	}
+
+	children, err := h.Service.GetChildren(studio.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get child studios"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": children})
+}
+
 func (h *StudioHandler) CreateStudio(c *gin.Context) {
 	var req request.CreateStudioRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
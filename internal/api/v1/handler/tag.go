@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
 	"goonhub/internal/apperrors"
 	"goonhub/internal/core"
 	"net/http"
@@ -76,6 +78,83 @@ func (h *TagHandler) DeleteTag(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	var req request.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tag, err := h.Service.UpdateTag(uint(id), core.UpdateTagInput{
+		Name:    req.Name,
+		Color:   req.Color,
+		Aliases: req.Aliases,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	var req request.MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tag, err := h.Service.MergeTags(uint(id), req.SourceIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+func (h *TagHandler) DeleteUnusedTags(c *gin.Context) {
+	tags, err := h.Service.DeleteUnusedTags()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags, "deleted": len(tags)})
+}
+
+// GetTagUsage returns scenes added and the caller's watches per month for the
+// given tags, or the most used ones
+func (h *TagHandler) GetTagUsage(c *gin.Context) {
+	q, ok := bindUsageQuery(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.Service.GetUsage(q)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": series})
+}
+
 func (h *TagHandler) GetSceneTags(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+)
+
+type TorrentImportHandler struct {
+	Service *core.TorrentImportService
+}
+
+func NewTorrentImportHandler(service *core.TorrentImportService) *TorrentImportHandler {
+	return &TorrentImportHandler{
+		Service: service,
+	}
+}
+
+// List returns the files imported from the torrent client, newest first,
+// optionally only those of a scene
+// GET /api/v1/admin/import/torrents
+func (h *TorrentImportHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, 100)
+
+	var sceneID *uint
+	if raw := c.Query("scene_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scene ID"})
+			return
+		}
+		value := uint(id)
+		sceneID = &value
+	}
+
+	items, total, err := h.Service.List(page, limit, sceneID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// Run imports the completed torrents now
+// POST /api/v1/admin/import/torrents/run
+func (h *TorrentImportHandler) Run(c *gin.Context) {
+	result, err := h.Service.Run()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Retry forgets a failed import so the next pass imports its file again
+// POST /api/v1/admin/import/torrents/:id/retry
+func (h *TorrentImportHandler) Retry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import ID"})
+		return
+	}
+
+	if err := h.Service.Retry(uint(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Import will be retried on the next pass"})
+}
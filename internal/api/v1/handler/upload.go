@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/apperrors"
+	"goonhub/internal/core"
+)
+
+// maxBatchUploadFiles bounds the files of one batch upload request
+const maxBatchUploadFiles = 50
+
+type UploadHandler struct {
+	Service      *core.UploadService
+	SceneService *core.SceneService
+}
+
+func NewUploadHandler(service *core.UploadService, sceneService *core.SceneService) *UploadHandler {
+	return &UploadHandler{
+		Service:      service,
+		SceneService: sceneService,
+	}
+}
+
+// UploadBatch uploads several scene files in one request and reports the
+// outcome of each
+// POST /api/v1/scenes/batch
+func (h *UploadHandler) UploadBatch(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Scene files are required"})
+		return
+	}
+	files := form.File["scenes"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Scene files are required"})
+		return
+	}
+	if len(files) > maxBatchUploadFiles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many files, the limit is " + strconv.Itoa(maxBatchUploadFiles)})
+		return
+	}
+
+	results := h.SceneService.UploadScenes(files)
+	uploaded := 0
+	for _, result := range results {
+		if result.Error == "" {
+			uploaded++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"uploaded": uploaded,
+		"failed":   len(results) - uploaded,
+		"results":  results,
+	})
+}
+
+// CreateUpload starts a chunked upload
+// POST /api/v1/scenes/uploads
+func (h *UploadHandler) CreateUpload(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req request.CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	session, err := h.Service.CreateSession(payload.UserID, req.Filename, req.Title, req.Size)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// GetUpload returns a chunked upload with the offset to resume from
+// GET /api/v1/scenes/uploads/:uploadId
+func (h *UploadHandler) GetUpload(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	id, ok := parseUploadID(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.Service.GetSession(payload.UserID, id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.JSON(http.StatusOK, session)
+}
+
+// AppendChunk appends the request body to a chunked upload at the offset
+// given in the Upload-Offset header. On an offset mismatch the current offset
+// is returned with a 409 so the client can resume from it.
+// PATCH /api/v1/scenes/uploads/:uploadId
+func (h *UploadHandler) AppendChunk(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	id, ok := parseUploadID(c)
+	if !ok {
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Offset header is required"})
+		return
+	}
+
+	session, scene, err := h.Service.AppendChunk(payload.UserID, id, offset, c.Request.Body)
+	if session != nil {
+		c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	}
+	if err != nil {
+		if apperrors.IsConflict(err) && session != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "offset": session.Offset})
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+
+	if scene != nil {
+		c.JSON(http.StatusCreated, gin.H{"upload": session, "scene": scene})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"upload": session})
+}
+
+// CancelUpload discards a chunked upload
+// DELETE /api/v1/scenes/uploads/:uploadId
+func (h *UploadHandler) CancelUpload(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	id, ok := parseUploadID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Cancel(payload.UserID, id); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseUploadID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("uploadId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload ID"})
+		return uuid.UUID{}, false
+	}
+	return id, true
+}
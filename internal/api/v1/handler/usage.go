@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/core"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindUsageQuery reads the ids (comma-separated), months and limit query
+// parameters of the usage endpoints. It writes the error response and returns
+// false when they are invalid.
+func bindUsageQuery(c *gin.Context) (core.UsageQuery, bool) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return core.UsageQuery{}, false
+	}
+	q := core.UsageQuery{UserID: payload.UserID}
+
+	for _, part := range strings.Split(c.Query("ids"), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID: " + part})
+			return core.UsageQuery{}, false
+		}
+		q.IDs = append(q.IDs, uint(id))
+	}
+	for name, dst := range map[string]*int{"months": &q.Months, "limit": &q.Limit} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+			return core.UsageQuery{}, false
+		}
+		*dst = n
+	}
+	return q, true
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/apperrors"
+	"goonhub/internal/core"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// watchPartyReadTimeout drops connections that send nothing for this long;
+// clients send a ping message well within it.
+const watchPartyReadTimeout = 60 * time.Second
+
+type WatchPartyHandler struct {
+	watchPartyService *core.WatchPartyService
+	logger            *zap.Logger
+}
+
+func NewWatchPartyHandler(watchPartyService *core.WatchPartyService, logger *zap.Logger) *WatchPartyHandler {
+	return &WatchPartyHandler{
+		watchPartyService: watchPartyService,
+		logger:            logger.With(zap.String("handler", "watch_party")),
+	}
+}
+
+// Create starts a watch party hosted by the current user
+func (h *WatchPartyHandler) Create(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req request.CreateWatchPartyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	state, err := h.watchPartyService.Create(req.SceneID, payload.UserID, payload.Role)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, state)
+}
+
+// List returns the active watch parties
+func (h *WatchPartyHandler) List(c *gin.Context) {
+	parties, err := h.watchPartyService.List(requestRole(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"data": parties})
+}
+
+// Get returns the current state of a watch party
+func (h *WatchPartyHandler) Get(c *gin.Context) {
+	state, err := h.watchPartyService.Get(c.Param("id"), requestRole(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.OK(c, state)
+}
+
+// End closes a watch party; only its host may end it
+func (h *WatchPartyHandler) End(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.watchPartyService.End(c.Param("id"), payload.UserID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// Connect upgrades to a WebSocket that joins the watch party. The server pushes
+// state messages whenever playback, the host or the member list changes, and
+// the host sends play, pause, seek, transfer_host and end messages.
+func (h *WatchPartyHandler) Connect(c *gin.Context) {
+	payload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// Join before upgrading so a missing or full party is a plain HTTP error
+	sub, err := h.watchPartyService.Join(c.Param("id"), payload.UserID, payload.Role, payload.Username)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Origins are already checked by the CORS middleware; the websocket
+	// package's own check would reject clients that send no Origin
+	server := websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			h.serve(ws, sub)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *WatchPartyHandler) serve(ws *websocket.Conn, sub *core.WatchPartySubscription) {
+	defer ws.Close()
+	defer h.watchPartyService.Leave(sub)
+
+	// Forward party messages until the subscription closes, then hang up so
+	// the read loop below returns
+	go func() {
+		for msg := range sub.Messages {
+			if err := websocket.JSON.Send(ws, msg); err != nil {
+				break
+			}
+		}
+		ws.Close()
+	}()
+
+	for {
+		// The HTTP server's read deadline carries over to the hijacked connection
+		ws.SetReadDeadline(time.Now().Add(watchPartyReadTimeout))
+
+		var msg request.WatchPartyClientMessage
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			return
+		}
+
+		var err error
+		switch msg.Type {
+		case "ping":
+		case "sync":
+			h.watchPartyService.Sync(sub)
+		case "transfer_host":
+			err = h.watchPartyService.TransferHost(sub.PartyID, sub.UserID, msg.UserID)
+		case "end":
+			err = h.watchPartyService.End(sub.PartyID, sub.UserID)
+		default:
+			err = h.watchPartyService.Control(sub.PartyID, sub.UserID, msg.Type, msg.Position)
+		}
+		if err != nil {
+			websocket.JSON.Send(ws, gin.H{
+				"type":  "error",
+				"error": err.Error(),
+				"code":  apperrors.GetCode(err),
+			})
+		}
+	}
+}
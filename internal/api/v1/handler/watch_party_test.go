@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"goonhub/internal/config"
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+func newTestWatchPartyServer(t *testing.T) (*httptest.Server, *core.WatchPartyService) {
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	sceneRepo.EXPECT().GetByID(gomock.Any()).Return(&data.Scene{ID: 1}, nil).AnyTimes()
+
+	svc := core.NewWatchPartyService(sceneRepo, config.WatchPartyConfig{EmptyTimeout: time.Minute}, zap.NewNop())
+	h := NewWatchPartyHandler(svc, zap.NewNop())
+
+	r := gin.New()
+	// Stand in for AuthMiddleware: the user ID comes from a test header
+	r.Use(func(c *gin.Context) {
+		id := uint(1)
+		if c.GetHeader("X-Test-User") == "2" {
+			id = 2
+		}
+		c.Set("user", &core.UserPayload{UserID: id, Username: "user"})
+	})
+	r.GET("/watch-parties/:id/ws", h.Connect)
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv, svc
+}
+
+func dialWatchParty(t *testing.T, srv *httptest.Server, partyID, user string) *websocket.Conn {
+	t.Helper()
+	cfg, err := websocket.NewConfig("ws"+strings.TrimPrefix(srv.URL, "http")+"/watch-parties/"+partyID+"/ws", srv.URL)
+	if err != nil {
+		t.Fatalf("failed to build websocket config: %v", err)
+	}
+	cfg.Header = http.Header{"X-Test-User": {user}}
+	ws, err := websocket.DialConfig(cfg)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func receiveState(t *testing.T, ws *websocket.Conn) core.WatchPartyMessage {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg core.WatchPartyMessage
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		t.Fatalf("failed to receive: %v", err)
+	}
+	return msg
+}
+
+func TestWatchPartyConnect_SyncsPlayback(t *testing.T) {
+	srv, svc := newTestWatchPartyServer(t)
+
+	state, err := svc.Create(1, 1, "user")
+	if err != nil {
+		t.Fatalf("failed to create party: %v", err)
+	}
+
+	host := dialWatchParty(t, srv, state.ID, "1")
+	receiveState(t, host) // host joined
+
+	guest := dialWatchParty(t, srv, state.ID, "2")
+	if msg := receiveState(t, guest); len(msg.State.Members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(msg.State.Members))
+	}
+	receiveState(t, host) // guest joined
+
+	if err := websocket.JSON.Send(host, map[string]any{"type": "play", "position": 12}); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	msg := receiveState(t, guest)
+	if msg.Type != core.WatchPartyMsgState || msg.State.Paused || msg.State.Position != 12 {
+		t.Fatalf("expected guest to see playback at 12, got %+v", msg.State)
+	}
+
+	// Guests get an error instead of controlling playback
+	if err := websocket.JSON.Send(guest, map[string]any{"type": "pause", "position": 20}); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	receiveState(t, host) // host's copy of the play broadcast
+	var errMsg map[string]any
+	guest.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := websocket.JSON.Receive(guest, &errMsg); err != nil {
+		t.Fatalf("failed to receive: %v", err)
+	}
+	if errMsg["type"] != "error" {
+		raw, _ := json.Marshal(errMsg)
+		t.Fatalf("expected error message, got %s", raw)
+	}
+}
+
+func TestWatchPartyConnect_UnknownParty(t *testing.T) {
+	srv, _ := newTestWatchPartyServer(t)
+
+	resp, err := http.Get(srv.URL + "/watch-parties/missing/ws")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"goonhub/internal/api/middleware"
+	"goonhub/internal/api/v1/request"
+	"goonhub/internal/api/v1/response"
+	"goonhub/internal/core"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService *core.WebhookService
+}
+
+func NewWebhookHandler(webhookService *core.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// ListWebhooks returns every webhook along with the event types they can subscribe to
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.List()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":        webhooks,
+		"event_types": core.WebhookEventTypes,
+	})
+}
+
+// CreateWebhook registers a webhook; the signing secret is only returned by this call
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userPayload, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req request.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	created, err := h.webhookService.Create(userPayload.UserID, core.CreateWebhookInput{
+		Name:   req.Name,
+		URL:    req.URL,
+		Events: req.Events,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	webhook, err := h.webhookService.Get(id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var req request.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	webhook, err := h.webhookService.Update(id, core.UpdateWebhookInput{
+		Name:    req.Name,
+		URL:     req.URL,
+		Events:  req.Events,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	if err := h.webhookService.Delete(id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+// RotateWebhookSecret replaces the signing secret and returns the new one
+func (h *WebhookHandler) RotateWebhookSecret(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	rotated, err := h.webhookService.RotateSecret(id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rotated)
+}
+
+// PingWebhook sends a test event right away and returns the recorded delivery
+func (h *WebhookHandler) PingWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	delivery, err := h.webhookService.Ping(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// ListDeliveries returns a webhook's delivery history, newest first, optionally filtered by status
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	page, limit = clampPagination(page, limit, 20, 100)
+
+	deliveries, total, err := h.webhookService.ListDeliveries(id, c.Query("status"), page, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  deliveries,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseDeliveryID(c)
+	if !ok {
+		return
+	}
+	delivery, err := h.webhookService.GetDelivery(id, deliveryID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// RedeliverDelivery queues a delivery's payload to be sent again
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseDeliveryID(c)
+	if !ok {
+		return
+	}
+	delivery, err := h.webhookService.Redeliver(id, deliveryID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+func parseWebhookID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func parseDeliveryID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("deliveryID"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -53,3 +53,15 @@ type UpdateActorRequest struct {
 type SetSceneActorsRequest struct {
 	ActorIDs []uint `json:"actor_ids"`
 }
+
+// MergeActorsRequest names the duplicate actors to merge into an actor.
+type MergeActorsRequest struct {
+	SourceIDs []uint `json:"source_ids" binding:"required"`
+}
+
+// GenerateActorImageRequest optionally picks the frame an actor image is cut
+// from; the scene must feature the actor.
+type GenerateActorImageRequest struct {
+	SceneID   *uint `json:"scene_id"`
+	Timestamp *int  `json:"timestamp"` // seconds
+}
@@ -17,3 +17,58 @@ type ResetPasswordRequest struct {
 type SyncRolePermissionsRequest struct {
 	PermissionIDs []uint `json:"permission_ids" binding:"required"`
 }
+
+// UpdateContentRestrictionRequest replaces a role's content restriction. Empty
+// lists lift the restriction on that dimension.
+type UpdateContentRestrictionRequest struct {
+	StoragePathIDs []uint   `json:"storage_path_ids"`
+	TagIDs         []uint   `json:"tag_ids"`
+	SceneTypes     []string `json:"scene_types"`
+}
+
+type CreateInviteRequest struct {
+	Role           string `json:"role" binding:"required"`
+	Note           string `json:"note"`
+	ExpiresInHours int    `json:"expires_in_hours"`
+}
+
+// CreateWebhookRequest registers a webhook. An empty events list subscribes to every event.
+type CreateWebhookRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events"`
+}
+
+type UpdateWebhookRequest struct {
+	Name    *string   `json:"name"`
+	URL     *string   `json:"url"`
+	Events  *[]string `json:"events"`
+	Enabled *bool     `json:"enabled"`
+}
+
+type StartPornDBAutoMatchRequest struct {
+	Provider  string  `json:"provider"`  // porndb or stashdb, empty = porndb
+	Threshold float64 `json:"threshold"` // 0 uses the default
+}
+
+type AcceptPornDBMatchRequest struct {
+	Provider      string   `json:"provider"` // empty = the review's provider
+	PornDBSceneID string   `json:"porndb_scene_id" binding:"required"`
+	Fields        []string `json:"fields"` // empty = title, date, description and studio
+}
+
+// ApplyPornDBMatchRequest applies a provider scene to a scene. Fields are
+// among title, date, description, studio, performers, tags, markers and cover.
+type ApplyPornDBMatchRequest struct {
+	Provider      string   `json:"provider"` // empty = porndb
+	PornDBSceneID string   `json:"porndb_scene_id" binding:"required"`
+	Fields        []string `json:"fields"` // empty = title, date, description and studio
+}
+
+// UpdateActorPornDBSyncRequest links an actor to a PornDB performer and turns
+// its scheduled sync on or off. Nil leaves a setting alone; an empty
+// porndb_id unlinks the actor.
+type UpdateActorPornDBSyncRequest struct {
+	PornDBID *string `json:"porndb_id"`
+	Enabled  *bool   `json:"enabled"`
+}
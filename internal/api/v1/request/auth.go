@@ -4,3 +4,44 @@ type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
+
+type UnlockPrivacyLockRequest struct {
+	PIN string `json:"pin" binding:"required"`
+}
+
+type UpdatePrivacyLockRequest struct {
+	CurrentPassword    string `json:"current_password" binding:"required"`
+	Enabled            bool   `json:"enabled"`
+	PIN                string `json:"pin"`
+	IdleTimeoutMinutes int    `json:"idle_timeout_minutes"`
+}
+
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorPasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+type EnableTwoFactorRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Code            string `json:"code" binding:"required"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Permissions   []string `json:"permissions" binding:"required"`
+	ExpiresInDays int      `json:"expires_in_days"`
+}
+
+type RegisterRequest struct {
+	Username    string `json:"username" binding:"required,min=3,max=64"`
+	Password    string `json:"password" binding:"required,min=12,max=128"`
+	InviteToken string `json:"invite_token"`
+}
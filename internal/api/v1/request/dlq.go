@@ -0,0 +1,10 @@
+package request
+
+// DLQBulkRequest selects the dead letter queue entries of a bulk requeue or
+// purge. Empty fields match all entries.
+type DLQBulkRequest struct {
+	Status        string `json:"status"`         // pending_review, retrying or abandoned
+	Phase         string `json:"phase"`          // e.g. metadata, sprites
+	ErrorContains string `json:"error_contains"` // case-insensitive, in the original or last error
+	OlderThan     string `json:"older_than"`     // entries created longer ago than this, e.g. "7d", "12h"
+}
@@ -0,0 +1,10 @@
+package request
+
+type UpdateDuplicateGroupStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type ResolveDuplicateGroupRequest struct {
+	WinnerSceneID uint   `json:"winner_scene_id" binding:"required"`
+	Action        string `json:"action"`
+}
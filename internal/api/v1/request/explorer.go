@@ -20,6 +20,19 @@ type BulkUpdateStudioRequest struct {
 	Studio   string `json:"studio"`
 }
 
+// BulkUpdateMetadataRequest represents a partial metadata update for multiple
+// scenes. Omitted fields are left unchanged; an empty release_date, origin or
+// type and a studio_id of 0 clear them.
+type BulkUpdateMetadataRequest struct {
+	SceneIDs      []uint  `json:"scene_ids" binding:"required,min=1"`
+	TitleTemplate *string `json:"title_template"`
+	Description   *string `json:"description"`
+	ReleaseDate   *string `json:"release_date"`
+	Origin        *string `json:"origin"`
+	Type          *string `json:"type"`
+	StudioID      *uint   `json:"studio_id"`
+}
+
 // FolderSceneIDsRequest represents a request to get scene IDs in a folder
 // Supports optional filters to get only IDs matching search criteria
 type FolderSceneIDsRequest struct {
@@ -36,6 +49,30 @@ type FolderSceneIDsRequest struct {
 type BulkDeleteRequest struct {
 	SceneIDs  []uint `json:"scene_ids" binding:"required,min=1"`
 	Permanent bool   `json:"permanent"` // false = trash (default), true = permanent delete
+	Force     bool   `json:"force"`     // bypass deletion protection (when allowed by config)
+}
+
+// MoveScenesRequest represents a request to move scene files to a folder of a
+// storage path
+type MoveScenesRequest struct {
+	SceneIDs      []uint `json:"scene_ids" binding:"required,min=1"`
+	StoragePathID uint   `json:"storage_path_id" binding:"required"`
+	FolderPath    string `json:"folder_path"` // relative to the storage path, created if missing
+}
+
+// MigrateScenesRequest represents a request to move scenes to another storage
+// path: the given scenes, or every scene under a folder of the source storage
+// path
+type MigrateScenesRequest struct {
+	SceneIDs            []uint `json:"scene_ids"`
+	SourceStoragePathID uint   `json:"source_storage_path_id"` // with folder_path, instead of scene_ids
+	FolderPath          string `json:"folder_path"`            // relative to the source storage path, subfolders included
+	StoragePathID       uint   `json:"storage_path_id" binding:"required"`
+}
+
+// RenameSceneFileRequest represents a request to rename a scene's file in place
+type RenameSceneFileRequest struct {
+	Filename string `json:"filename" binding:"required"`
 }
 
 // ScenesMatchInfoRequest represents a request to get minimal scene data for bulk matching
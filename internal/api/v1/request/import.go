@@ -24,9 +24,10 @@ type ImportSceneRequest struct {
 
 // ImportMarkerRequest represents a request to import a marker with pre-existing data.
 type ImportMarkerRequest struct {
-	SceneID   uint   `json:"scene_id" binding:"required"`
-	UserID    uint   `json:"user_id" binding:"required"`
-	Timestamp int    `json:"timestamp" binding:"min=0"`
-	Label     string `json:"label"`
-	Color     string `json:"color"`
+	SceneID      uint   `json:"scene_id" binding:"required"`
+	UserID       uint   `json:"user_id" binding:"required"`
+	Timestamp    int    `json:"timestamp" binding:"min=0"`
+	EndTimestamp *int   `json:"end_timestamp"`
+	Label        string `json:"label"`
+	Color        string `json:"color"`
 }
@@ -0,0 +1,11 @@
+package request
+
+// CancelJobsRequest selects the processing jobs of a bulk cancel. Empty
+// fields match all jobs, which takes All set.
+type CancelJobsRequest struct {
+	Phase          string `json:"phase"`           // e.g. metadata, sprites
+	SceneIDs       []uint `json:"scene_ids"`       // jobs of these scenes
+	OlderThan      string `json:"older_than"`      // jobs created longer ago than this, e.g. "7d", "12h"
+	IncludeRunning bool   `json:"include_running"` // also stop the jobs already executing
+	All            bool   `json:"all"`             // confirms cancelling every job when nothing else is set
+}
@@ -1,15 +1,19 @@
 package request
 
 type CreateMarkerRequest struct {
-	Timestamp int    `json:"timestamp" binding:"min=0"`
-	Label     string `json:"label"`
-	Color     string `json:"color"`
+	Timestamp    int    `json:"timestamp" binding:"min=0"`
+	EndTimestamp *int   `json:"end_timestamp,omitempty"`
+	Label        string `json:"label"`
+	Color        string `json:"color"`
+	Visibility   string `json:"visibility"` // "private" (default) or "shared"
 }
 
 type UpdateMarkerRequest struct {
-	Timestamp *int    `json:"timestamp,omitempty"`
-	Label     *string `json:"label,omitempty"`
-	Color     *string `json:"color,omitempty"`
+	Timestamp    *int    `json:"timestamp,omitempty"`
+	EndTimestamp *int    `json:"end_timestamp,omitempty"` // 0 removes the end
+	Label        *string `json:"label,omitempty"`
+	Color        *string `json:"color,omitempty"`
+	Visibility   *string `json:"visibility,omitempty"`
 }
 
 type SetLabelTagsRequest struct {
@@ -19,3 +23,35 @@ type SetLabelTagsRequest struct {
 type SetMarkerTagsRequest struct {
 	TagIDs []uint `json:"tag_ids" binding:"required"`
 }
+
+type ImportMarkerItem struct {
+	Timestamp int    `json:"timestamp"`
+	Label     string `json:"label"`
+	Color     string `json:"color"`
+}
+
+// ImportMarkersRequest imports markers from a file's Content (csv, json,
+// funscript or vtt; detected when Format is empty) and/or structured Markers.
+type ImportMarkersRequest struct {
+	Format        string             `json:"format"`
+	Content       string             `json:"content"`
+	Markers       []ImportMarkerItem `json:"markers"`
+	Color         string             `json:"color"`
+	DedupeSeconds *int               `json:"dedupe_seconds"`
+	DryRun        bool               `json:"dry_run"`
+}
+
+// CreateMarkerCompilationRequest joins the clips of the markers, in order, into one MP4.
+// ClipSeconds is the clip length taken from markers without an end (0 uses the default).
+type SetLabelSettingRequest struct {
+	Label        string `json:"label" binding:"required"`
+	Color        string `json:"color"`
+	Icon         string `json:"icon"`
+	ClipDuration int    `json:"clip_duration"`
+}
+
+type CreateMarkerCompilationRequest struct {
+	MarkerIDs   []uint `json:"marker_ids" binding:"required"`
+	ClipSeconds int    `json:"clip_seconds"`
+	Title       string `json:"title"`
+}
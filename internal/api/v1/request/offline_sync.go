@@ -0,0 +1,6 @@
+package request
+
+// AddOfflineScenesRequest selects scenes for offline sync
+type AddOfflineScenesRequest struct {
+	SceneIDs []uint `json:"scene_ids" binding:"required"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type RemoteImportRequest struct {
+	URL           string `json:"url" binding:"required,max=2048"`
+	Title         string `json:"title" binding:"max=255"`
+	StoragePathID *uint  `json:"storage_path_id"` // omitted = the configured storage path
+}
@@ -0,0 +1,12 @@
+package request
+
+// SetReviewStateRequest moves a scene to another review state
+type SetReviewStateRequest struct {
+	State string `json:"state" binding:"required"`
+}
+
+// BulkSetReviewStateRequest moves several scenes to the same review state
+type BulkSetReviewStateRequest struct {
+	SceneIDs []uint `json:"scene_ids" binding:"required,min=1"`
+	State    string `json:"state" binding:"required"`
+}
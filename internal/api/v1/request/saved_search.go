@@ -22,9 +22,11 @@ type SavedSearchFilters struct {
 type CreateSavedSearchRequest struct {
 	Name    string             `json:"name" binding:"required"`
 	Filters SavedSearchFilters `json:"filters"`
+	Watched bool               `json:"watched"`
 }
 
 type UpdateSavedSearchRequest struct {
 	Name    *string             `json:"name,omitempty"`
 	Filters *SavedSearchFilters `json:"filters,omitempty"`
+	Watched *bool               `json:"watched,omitempty"`
 }
@@ -0,0 +1,42 @@
+package request
+
+// StartScanRequest optionally limits a scan to one storage path or a folder
+// within it. An empty body scans every storage path.
+type StartScanRequest struct {
+	StoragePathID *uint  `json:"storage_path_id"`
+	FolderPath    string `json:"folder_path" binding:"max=500"`
+}
+
+// UpdateScanExclusionsRequest replaces the global scan exclusion rules.
+type UpdateScanExclusionsRequest struct {
+	MinFileSizeBytes int64    `json:"min_file_size_bytes" binding:"min=0"`
+	IgnoreSamples    bool     `json:"ignore_samples"`
+	IgnoreHiddenDirs bool     `json:"ignore_hidden_dirs"`
+	ExcludePatterns  []string `json:"exclude_patterns" binding:"max=100,dive,max=255"`
+}
+
+// TestScanExclusionsRequest previews the exclusions for a storage path. When
+// exclude_patterns is omitted the storage path's saved patterns are used.
+type TestScanExclusionsRequest struct {
+	StoragePathID   uint      `json:"storage_path_id" binding:"required"`
+	ExcludePatterns *[]string `json:"exclude_patterns" binding:"omitempty,max=100,dive,max=255"`
+}
+
+// FolderRuleRequest creates or replaces a folder rule. Enabled defaults to true.
+type FolderRuleRequest struct {
+	Name          string `json:"name" binding:"required,max=255"`
+	StoragePathID *uint  `json:"storage_path_id"`
+	Pattern       string `json:"pattern" binding:"required,max=255"`
+	TagIDs        []uint `json:"tag_ids" binding:"max=100"`
+	ActorIDs      []uint `json:"actor_ids" binding:"max=100"`
+	StudioID      *uint  `json:"studio_id"`
+	SceneType     string `json:"scene_type" binding:"max=50"`
+	Enabled       *bool  `json:"enabled"`
+}
+
+// ApplyFolderRulesRequest applies the enabled folder rules to the scenes in a
+// folder of a storage path, or the whole path when folder_path is empty.
+type ApplyFolderRulesRequest struct {
+	StoragePathID uint   `json:"storage_path_id" binding:"required"`
+	FolderPath    string `json:"folder_path" binding:"max=500"`
+}
@@ -23,7 +23,10 @@ type SearchScenesRequest struct {
 	Sort         string  `form:"sort"`
 	Page         int     `form:"page"`
 	Limit        int     `form:"limit"`
+	Cursor       string  `form:"cursor"` // pages by cursor instead of page when present, empty for the first page
 	Liked        *bool   `form:"liked"`
+	Corrupted    *bool   `form:"corrupted"`
+	ReviewState  string  `form:"review_state"`
 	MinRating    float64 `form:"min_rating"`
 	MaxRating    float64 `form:"max_rating"`
 	MinJizzCount int     `form:"min_jizz_count"`
@@ -33,6 +36,13 @@ type SearchScenesRequest struct {
 	Seed         int64   `form:"seed"`           // Random shuffle seed (0 = auto-generate)
 }
 
+// ShuffleQueueRequest takes the scene list filters plus the queue position.
+type ShuffleQueueRequest struct {
+	SearchScenesRequest
+	Offset int `form:"offset"` // next_offset of the previous batch
+	Count  int `form:"count"`
+}
+
 type ApplySceneMetadataRequest struct {
 	Title         *string  `json:"title,omitempty"`
 	Description   *string  `json:"description,omitempty"`
@@ -46,4 +56,12 @@ type ApplySceneMetadataRequest struct {
 
 type DeleteSceneRequest struct {
 	Permanent bool `json:"permanent"`
+	Force     bool `json:"force"` // bypass deletion protection (when allowed by config)
+}
+
+// PlaybackRequest carries the formats the client can play natively.
+type PlaybackRequest struct {
+	Containers  []string `json:"containers"`
+	VideoCodecs []string `json:"video_codecs"`
+	AudioCodecs []string `json:"audio_codecs"`
 }
@@ -0,0 +1,13 @@
+package request
+
+// ScrapeSceneRequest scrapes a scene page into a scene. An empty fields list
+// writes every field the page has.
+type ScrapeSceneRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Fields []string `json:"fields"`
+}
+
+// PreviewScrapeRequest scrapes a scene page without writing it anywhere.
+type PreviewScrapeRequest struct {
+	URL string `json:"url" binding:"required"`
+}
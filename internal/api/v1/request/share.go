@@ -3,4 +3,9 @@ package request
 type CreateShareLinkRequest struct {
 	ShareType string `json:"share_type" binding:"required"`
 	ExpiresIn string `json:"expires_in" binding:"required"`
+	Password  string `json:"password"`
+}
+
+type UnlockShareLinkRequest struct {
+	Password string `json:"password" binding:"required"`
 }
@@ -15,3 +15,12 @@ type UpdateStoragePathRequest struct {
 type ValidatePathRequest struct {
 	Path string `json:"path" binding:"required,min=1,max=500"`
 }
+
+type UpdateScanScheduleRequest struct {
+	ScanSchedule string `json:"scan_schedule" binding:"max=100"`
+	Enabled      bool   `json:"enabled"`
+}
+
+type UpdateExcludePatternsRequest struct {
+	ExcludePatterns []string `json:"exclude_patterns" binding:"max=100,dive,max=255"`
+}
@@ -24,8 +24,8 @@ type UpdateStudioRequest struct {
 	Favicon     *string  `json:"favicon"`
 	Poster      *string  `json:"poster"`
 	PornDBID    *string  `json:"porndb_id"`
-	ParentID    *uint    `json:"parent_id"`
-	NetworkID   *uint    `json:"network_id"`
+	ParentID    *uint    `json:"parent_id"`  // 0 clears
+	NetworkID   *uint    `json:"network_id"` // 0 clears
 }
 
 type SetSceneStudioRequest struct {
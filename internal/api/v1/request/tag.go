@@ -0,0 +1,14 @@
+package request
+
+// UpdateTagRequest renames, recolors or sets the aliases of a tag. A renamed
+// tag keeps its old name as an alias.
+type UpdateTagRequest struct {
+	Name    *string   `json:"name"`
+	Color   *string   `json:"color"`
+	Aliases *[]string `json:"aliases"`
+}
+
+// MergeTagsRequest names the duplicate tags to merge into a tag.
+type MergeTagsRequest struct {
+	SourceIDs []uint `json:"source_ids" binding:"required"`
+}
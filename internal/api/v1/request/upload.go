@@ -0,0 +1,7 @@
+package request
+
+type CreateUploadRequest struct {
+	Filename string `json:"filename" binding:"required,min=1,max=255"`
+	Title    string `json:"title" binding:"max=255"`
+	Size     int64  `json:"size" binding:"required,min=1"`
+}
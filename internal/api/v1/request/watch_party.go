@@ -0,0 +1,14 @@
+package request
+
+// CreateWatchPartyRequest starts a watch party for a scene
+type CreateWatchPartyRequest struct {
+	SceneID uint `json:"scene_id" binding:"required"`
+}
+
+// WatchPartyClientMessage is a message sent by a client over the watch party
+// WebSocket. Type is one of play, pause, seek, transfer_host, end, sync or ping.
+type WatchPartyClientMessage struct {
+	Type     string  `json:"type"`
+	Position float64 `json:"position"`
+	UserID   uint    `json:"user_id"`
+}
@@ -6,8 +6,22 @@ type AuthResponse struct {
 	User UserSummary `json:"user"`
 }
 
+// TwoFactorChallengeResponse is returned by login when the password was right but a
+// two-factor code is still needed; the challenge token is sent with the code.
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+}
+
+// RecoveryCodesResponse carries newly generated recovery codes, shown once.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 type UserSummary struct {
 	ID       uint   `json:"id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// TwoFactorSetupRequired means the session is limited to setting up two-factor auth
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required"`
 }
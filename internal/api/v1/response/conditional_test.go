@@ -0,0 +1,99 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newConditionalContext(headers map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	for k, v := range headers {
+		c.Request.Header.Set(k, v)
+	}
+	return c, w
+}
+
+func TestOKWithETag(t *testing.T) {
+	c, w := newConditionalContext(nil)
+	OKWithETag(c, gin.H{"id": 1})
+
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.String() != `{"id":1}` {
+		t.Fatalf("expected a tagged 200, got %d %q %q", w.Code, etag, w.Body.String())
+	}
+
+	c, w = newConditionalContext(map[string]string{"If-None-Match": `"other", ` + etag})
+	OKWithETag(c, gin.H{"id": 1})
+	c.Writer.WriteHeaderNow()
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("expected 304 without a body, got %d %q", w.Code, w.Body.String())
+	}
+
+	c, w = newConditionalContext(map[string]string{"If-None-Match": etag})
+	OKWithETag(c, gin.H{"id": 2})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Fatalf("expected changed data to get a new tag, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestNotModifiedIfModifiedSince(t *testing.T) {
+	modified := time.Date(2026, 1, 1, 12, 0, 0, 500, time.UTC)
+
+	c, _ := newConditionalContext(map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)})
+	if !NotModified(c, "", modified) {
+		t.Fatal("expected an unchanged resource to be not modified")
+	}
+
+	c, _ = newConditionalContext(map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)})
+	if NotModified(c, "", modified) {
+		t.Fatal("expected a resource changed since to be served")
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since
+	c, _ = newConditionalContext(map[string]string{
+		"If-None-Match":     `"stale"`,
+		"If-Modified-Since": modified.Format(http.TimeFormat),
+	})
+	if NotModified(c, `"fresh"`, modified) {
+		t.Fatal("expected a mismatched ETag to be served")
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1_thumb_sm.webp")
+	if err := os.WriteFile(path, []byte("image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, w := newConditionalContext(nil)
+	File(c, path)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Header().Get("Last-Modified") == "" {
+		t.Fatalf("expected a tagged file, got %d %v", w.Code, w.Header())
+	}
+
+	c, w = newConditionalContext(map[string]string{"If-None-Match": etag})
+	File(c, path)
+	c.Writer.WriteHeaderNow()
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for a matching ETag, got %d", w.Code)
+	}
+
+	// Rewriting the file changes its tag
+	if err := os.WriteFile(path, []byte("new image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, w = newConditionalContext(map[string]string{"If-None-Match": etag})
+	File(c, path)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected a rewritten file to be served, got %d", w.Code)
+	}
+}
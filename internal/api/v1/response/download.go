@@ -0,0 +1,25 @@
+package response
+
+import (
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+)
+
+// DownloadEntryResponse represents a downloaded scene with lightweight scene data.
+type DownloadEntryResponse struct {
+	Download data.UserSceneDownload `json:"download"`
+	Scene    *SceneListItem         `json:"scene,omitempty"`
+}
+
+// ToDownloadEntriesResponse converts service DownloadEntries to response types.
+func ToDownloadEntriesResponse(entries []core.DownloadEntry) []DownloadEntryResponse {
+	result := make([]DownloadEntryResponse, len(entries))
+	for i, e := range entries {
+		result[i].Download = e.Download
+		if e.Scene != nil {
+			item := ToSceneListItem(*e.Scene)
+			result[i].Scene = &item
+		}
+	}
+	return result
+}
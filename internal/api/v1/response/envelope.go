@@ -2,6 +2,7 @@
 package response
 
 import (
+	"errors"
 	"goonhub/internal/apperrors"
 	"net/http"
 
@@ -47,6 +48,22 @@ func NewPaginatedResponse[T any](data []T, page, limit int, totalItems int64) Pa
 	}
 }
 
+// CursorResponse is a page of a list paged by cursor. NextCursor is empty on
+// the last page.
+type CursorResponse[T any] struct {
+	Data       []T    `json:"data"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// NewCursorResponse creates a new cursor-paged response.
+func NewCursorResponse[T any](data []T, limit int, nextCursor string) CursorResponse[T] {
+	if data == nil {
+		data = []T{}
+	}
+	return CursorResponse[T]{Data: data, Limit: limit, NextCursor: nextCursor}
+}
+
 // DataResponse is a simple data envelope for non-paginated responses.
 type DataResponse[T any] struct {
 	Data T `json:"data"`
@@ -62,6 +79,8 @@ type ErrorResponse struct {
 	Error   string            `json:"error"`
 	Code    string            `json:"code,omitempty"`
 	Details map[string]string `json:"details,omitempty"`
+
+	ProtectedScenes []apperrors.ProtectedScene `json:"protected_scenes,omitempty"`
 }
 
 // NewErrorResponse creates a new error response from a message.
@@ -101,6 +120,12 @@ func Error(c *gin.Context, err error) {
 		}
 	}
 
+	// List the guarded scenes and why for deletion protection conflicts
+	var protectedErr *apperrors.SceneDeletionProtectedError
+	if errors.As(err, &protectedErr) {
+		resp.ProtectedScenes = protectedErr.Scenes
+	}
+
 	c.JSON(status, resp)
 }
 
@@ -73,6 +73,17 @@ func TestNewPaginatedResponse_NilData(t *testing.T) {
 	}
 }
 
+func TestNewCursorResponse_NilData(t *testing.T) {
+	resp := NewCursorResponse[string](nil, 20, "")
+
+	if resp.Data == nil {
+		t.Fatal("expected non-nil data slice")
+	}
+	if resp.Limit != 20 || resp.NextCursor != "" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+}
+
 func TestNewDataResponse(t *testing.T) {
 	data := map[string]string{"foo": "bar"}
 	resp := NewDataResponse(data)
@@ -149,6 +160,29 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestErrorListsProtectedScenes(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	Error(c, apperrors.NewSceneDeletionProtectedError([]apperrors.ProtectedScene{
+		{SceneID: 7, Reasons: []string{apperrors.DeletionReasonRated, apperrors.DeletionReasonMarkers}},
+	}, true))
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Code != "SCENE_DELETION_PROTECTED" {
+		t.Fatalf("expected code SCENE_DELETION_PROTECTED, got %q", resp.Code)
+	}
+	if len(resp.ProtectedScenes) != 1 || resp.ProtectedScenes[0].SceneID != 7 || len(resp.ProtectedScenes[0].Reasons) != 2 {
+		t.Fatalf("unexpected protected scenes: %+v", resp.ProtectedScenes)
+	}
+}
+
 func TestOK(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -14,6 +14,7 @@ type ScenesMatchInfoResponse struct {
 type FolderContentsResponse struct {
 	StoragePath *data.StoragePath `json:"storage_path"`
 	CurrentPath string            `json:"current_path"`
+	Folder      *data.FolderInfo  `json:"folder"`
 	Subfolders  []data.FolderInfo `json:"subfolders"`
 	Scenes      []SceneListItem   `json:"scenes"`
 	TotalScenes int64             `json:"total_scenes"`
@@ -26,6 +27,7 @@ func ToFolderContentsResponse(resp *core.FolderContentsResponse) *FolderContents
 	return &FolderContentsResponse{
 		StoragePath: resp.StoragePath,
 		CurrentPath: resp.CurrentPath,
+		Folder:      resp.Folder,
 		Subfolders:  resp.Subfolders,
 		Scenes:      ToSceneListItems(resp.Scenes),
 		TotalScenes: resp.TotalScenes,
@@ -0,0 +1,136 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"goonhub/internal/apperrors"
+)
+
+// SparseFields is a parsed fields= query: the JSON keys each item of a list
+// response keeps. An empty SparseFields keeps every field.
+type SparseFields []string
+
+// ParseSparseFields parses a comma-separated fields= query. The id is always
+// kept so clients can key what they get back.
+func ParseSparseFields(raw string) SparseFields {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	fields := SparseFields{"id"}
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" && field != "id" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// CardFields returns the optional SceneListItem fields the selection needs,
+// so a fields= query doesn't also have to list them in card_fields.
+func (f SparseFields) CardFields() CardFields {
+	var cf CardFields
+	for _, field := range f {
+		switch field {
+		case "view_count":
+			cf.Views = true
+		case "width", "height":
+			cf.Resolution = true
+		case "frame_rate":
+			cf.FrameRate = true
+		case "description":
+			cf.Description = true
+		case "studio":
+			cf.Studio = true
+		case "tags":
+			cf.Tags = true
+		case "actors":
+			cf.Actors = true
+		}
+	}
+	return cf
+}
+
+// Merge returns the card fields requested by either f or other.
+func (f CardFields) Merge(other CardFields) CardFields {
+	return CardFields{
+		Views:       f.Views || other.Views,
+		Resolution:  f.Resolution || other.Resolution,
+		FrameRate:   f.FrameRate || other.FrameRate,
+		Description: f.Description || other.Description,
+		Studio:      f.Studio || other.Studio,
+		Tags:        f.Tags || other.Tags,
+		Actors:      f.Actors || other.Actors,
+		Rating:      f.Rating || other.Rating,
+		Liked:       f.Liked || other.Liked,
+		JizzCount:   f.JizzCount || other.JizzCount,
+	}
+}
+
+// SelectFields returns items reduced to the selected JSON keys, or items
+// unchanged when nothing is selected. Selecting a key T doesn't have is a
+// validation error listing the keys it does.
+func SelectFields[T any](items []T, fields SparseFields) (any, error) {
+	if len(fields) == 0 {
+		return items, nil
+	}
+
+	known := jsonFieldNames(reflect.TypeOf((*T)(nil)).Elem())
+	for _, field := range fields {
+		if !known[field] {
+			names := make([]string, 0, len(known))
+			for name := range known {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, apperrors.NewValidationErrorWithField("fields", "unknown field "+field+", expected one of "+strings.Join(names, ", "))
+		}
+	}
+
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to encode response", err)
+	}
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &objects); err != nil {
+		return nil, apperrors.NewInternalError("failed to encode response", err)
+	}
+
+	projected := make([]map[string]json.RawMessage, len(objects))
+	for i, object := range objects {
+		item := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := object[field]; ok {
+				item[field] = value
+			}
+		}
+		projected[i] = item
+	}
+	return projected, nil
+}
+
+// jsonFieldNames returns the JSON keys of a struct type's fields.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
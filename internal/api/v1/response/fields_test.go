@@ -0,0 +1,51 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+
+	"goonhub/internal/apperrors"
+)
+
+func TestParseSparseFields(t *testing.T) {
+	if fields := ParseSparseFields(" "); fields != nil {
+		t.Fatalf("expected no selection, got %v", fields)
+	}
+
+	fields := ParseSparseFields("title, thumbnail_path,,id")
+	if len(fields) != 3 || fields[0] != "id" || fields[1] != "title" || fields[2] != "thumbnail_path" {
+		t.Fatalf("expected the id plus the listed fields, got %v", fields)
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	items := []SceneListItem{{ID: 1, Title: "One", Duration: 60, StoredPath: "/data/one.mp4"}}
+
+	selected, err := SelectFields(items, ParseSparseFields("title,duration"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded, _ := json.Marshal(selected)
+	if string(encoded) != `[{"duration":60,"id":1,"title":"One"}]` {
+		t.Fatalf("unexpected projection %s", encoded)
+	}
+
+	if _, err := SelectFields(items, ParseSparseFields("title,bogus")); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a validation error for an unknown field, got %v", err)
+	}
+
+	all, err := SelectFields(items, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := all.([]SceneListItem); !ok {
+		t.Fatalf("expected the items unchanged without a selection, got %T", all)
+	}
+}
+
+func TestSparseFieldsCardFields(t *testing.T) {
+	cf := ParseSparseFields("title,width,tags").CardFields()
+	if !cf.Resolution || !cf.Tags || cf.Views || cf.Actors {
+		t.Fatalf("unexpected card fields %+v", cf)
+	}
+}
@@ -0,0 +1,10 @@
+package response
+
+import "goonhub/internal/core"
+
+// PoolConfig is the current size of every worker pool and the autoscaler's
+// bounds and recent resizes.
+type PoolConfig struct {
+	core.PoolConfig
+	Autoscale core.PoolAutoscaleStatus `json:"autoscale"`
+}
@@ -8,11 +8,14 @@ import (
 )
 
 type SavedSearchResponse struct {
-	UUID      uuid.UUID                `json:"uuid"`
-	Name      string                   `json:"name"`
-	Filters   SavedSearchFiltersOutput `json:"filters"`
-	CreatedAt time.Time                `json:"created_at"`
-	UpdatedAt time.Time                `json:"updated_at"`
+	UUID          uuid.UUID                `json:"uuid"`
+	Name          string                   `json:"name"`
+	Filters       SavedSearchFiltersOutput `json:"filters"`
+	Watched       bool                     `json:"watched"`
+	LastCheckedAt *time.Time               `json:"last_checked_at"`
+	NewMatchCount int64                    `json:"new_match_count"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
 }
 
 type SavedSearchFiltersOutput struct {
@@ -36,18 +39,22 @@ type SavedSearchFiltersOutput struct {
 
 func NewSavedSearchResponse(s *data.SavedSearch) SavedSearchResponse {
 	return SavedSearchResponse{
-		UUID:      s.UUID,
-		Name:      s.Name,
-		Filters:   filtersToOutput(s.Filters),
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: s.UpdatedAt,
+		UUID:          s.UUID,
+		Name:          s.Name,
+		Filters:       filtersToOutput(s.Filters),
+		Watched:       s.Watched,
+		LastCheckedAt: s.LastCheckedAt,
+		CreatedAt:     s.CreatedAt,
+		UpdatedAt:     s.UpdatedAt,
 	}
 }
 
-func NewSavedSearchListResponse(searches []data.SavedSearch) []SavedSearchResponse {
+// NewSavedSearchListResponse converts searches, adding their unseen match counts by search ID.
+func NewSavedSearchListResponse(searches []data.SavedSearch, newMatchCounts map[uint]int64) []SavedSearchResponse {
 	result := make([]SavedSearchResponse, len(searches))
 	for i, s := range searches {
 		result[i] = NewSavedSearchResponse(&s)
+		result[i].NewMatchCount = newMatchCounts[s.ID]
 	}
 	return result
 }
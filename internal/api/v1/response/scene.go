@@ -18,6 +18,7 @@ type SceneListItem struct {
 	PreviewVideoPath string    `json:"preview_video_path"`
 	ProcessingStatus string    `json:"processing_status"`
 	IsCorrupted      bool      `json:"is_corrupted"`
+	ReviewState      string    `json:"review_state"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 	StoredPath       string    `json:"stored_path"`
@@ -97,6 +98,7 @@ func ToSceneListItem(v data.Scene) SceneListItem {
 		PreviewVideoPath: v.PreviewVideoPath,
 		ProcessingStatus: v.ProcessingStatus,
 		IsCorrupted:      v.IsCorrupted,
+		ReviewState:      v.ReviewState,
 		CreatedAt:        v.CreatedAt,
 		UpdatedAt:        v.UpdatedAt,
 		StoredPath:       v.StoredPath,
@@ -0,0 +1,35 @@
+package response
+
+import (
+	"fmt"
+
+	"goonhub/internal/core"
+)
+
+// SegmentMatchItem is a segment search match with a link that starts playback at it.
+type SegmentMatchItem struct {
+	core.SegmentMatch
+	WatchURL string `json:"watch_url"`
+}
+
+// SceneSegmentResult is a scene with the segments that matched a search.
+type SceneSegmentResult struct {
+	Scene   SceneListItem      `json:"scene"`
+	Matches []SegmentMatchItem `json:"matches"`
+}
+
+// ToSceneSegmentResults converts segment search results to response types.
+func ToSceneSegmentResults(results []core.SceneSegmentMatches) []SceneSegmentResult {
+	out := make([]SceneSegmentResult, len(results))
+	for i, r := range results {
+		matches := make([]SegmentMatchItem, len(r.Matches))
+		for j, m := range r.Matches {
+			matches[j] = SegmentMatchItem{
+				SegmentMatch: m,
+				WatchURL:     fmt.Sprintf("/watch/%d?t=%d", r.Scene.ID, m.Timestamp),
+			}
+		}
+		out[i] = SceneSegmentResult{Scene: ToSceneListItem(r.Scene), Matches: matches}
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package response
+
+import "goonhub/internal/core"
+
+// SimilarSceneResult is a scene with its similarity score and what it shares with the source scene.
+type SimilarSceneResult struct {
+	Scene        SceneListItem `json:"scene"`
+	Score        float64       `json:"score"`
+	SharedActors []string      `json:"shared_actors"`
+	SharedTags   []string      `json:"shared_tags"`
+	SameStudio   bool          `json:"same_studio"`
+}
+
+// ToSimilarSceneResults converts similar scenes to response types.
+func ToSimilarSceneResults(scenes []core.SimilarScene) []SimilarSceneResult {
+	out := make([]SimilarSceneResult, len(scenes))
+	for i, s := range scenes {
+		out[i] = SimilarSceneResult{
+			Scene:        ToSceneListItem(s.Scene),
+			Score:        s.Score,
+			SharedActors: s.SharedActors,
+			SharedTags:   s.SharedTags,
+			SameStudio:   s.SameStudio,
+		}
+		if out[i].SharedActors == nil {
+			out[i].SharedActors = []string{}
+		}
+		if out[i].SharedTags == nil {
+			out[i].SharedTags = []string{}
+		}
+	}
+	return out
+}
@@ -3,6 +3,7 @@ package response
 import (
 	"goonhub/internal/core"
 	"goonhub/internal/data"
+	"time"
 )
 
 // DiskUsageResponse represents filesystem usage stats for a storage path.
@@ -22,10 +23,20 @@ type StoragePathWithUsage struct {
 	CreatedAt string             `json:"created_at"`
 	UpdatedAt string             `json:"updated_at"`
 	DiskUsage *DiskUsageResponse `json:"disk_usage"`
+
+	ScanSchedule        *string    `json:"scan_schedule"`
+	ScanScheduleEnabled bool       `json:"scan_schedule_enabled"`
+	NextScanAt          *time.Time `json:"next_scan_at"`
+
+	ExcludePatterns []string `json:"exclude_patterns"`
+
+	// Latest health check; nil until the path has been checked
+	Health *data.StoragePathHealthSample `json:"health"`
 }
 
-// ToStoragePathsWithUsage converts storage paths and a usage map into response DTOs.
-func ToStoragePathsWithUsage(paths []data.StoragePath, usageMap map[uint]*core.DiskUsage) []StoragePathWithUsage {
+// ToStoragePathsWithUsage converts storage paths, a usage map, the next
+// scheduled scan times and the latest health checks into response DTOs.
+func ToStoragePathsWithUsage(paths []data.StoragePath, usageMap map[uint]*core.DiskUsage, nextScans map[uint]time.Time, health map[uint]data.StoragePathHealthSample) []StoragePathWithUsage {
 	result := make([]StoragePathWithUsage, len(paths))
 	for i, p := range paths {
 		var usage *DiskUsageResponse
@@ -45,6 +56,20 @@ func ToStoragePathsWithUsage(paths []data.StoragePath, usageMap map[uint]*core.D
 			CreatedAt: p.CreatedAt.Format("2006-01-02T15:04:05Z"),
 			UpdatedAt: p.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 			DiskUsage: usage,
+
+			ScanSchedule:        p.ScanSchedule,
+			ScanScheduleEnabled: p.ScanScheduleEnabled,
+
+			ExcludePatterns: p.ExcludePatterns,
+		}
+		if result[i].ExcludePatterns == nil {
+			result[i].ExcludePatterns = []string{}
+		}
+		if next, ok := nextScans[p.ID]; ok {
+			result[i].NextScanAt = &next
+		}
+		if sample, ok := health[p.ID]; ok {
+			result[i].Health = &sample
 		}
 	}
 	return result
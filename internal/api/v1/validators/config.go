@@ -2,6 +2,10 @@ package validators
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+
+	"goonhub/internal/data"
 
 	"github.com/robfig/cron/v3"
 )
@@ -21,6 +25,7 @@ const (
 	MaxDelaySecondsLimit   = 86400
 	MinBackoffFactor       = 1.0
 	MaxBackoffFactor       = 5.0
+	MaxJitterPercent       = 100
 )
 
 // ValidateWorkerCount validates a worker count is within acceptable range
@@ -37,6 +42,8 @@ type PoolConfigInput struct {
 	ThumbnailWorkers          int
 	SpritesWorkers            int
 	AnimatedThumbnailsWorkers int
+	VerifyWorkers             int
+	ChecksumWorkers           int
 }
 
 // ValidatePoolConfig validates all pool configuration fields
@@ -53,6 +60,12 @@ func ValidatePoolConfig(cfg PoolConfigInput) error {
 	if err := ValidateWorkerCount(cfg.AnimatedThumbnailsWorkers, "animated_thumbnails_workers"); err != nil {
 		return err
 	}
+	if err := ValidateWorkerCount(cfg.VerifyWorkers, "verify_workers"); err != nil {
+		return err
+	}
+	if err := ValidateWorkerCount(cfg.ChecksumWorkers, "checksum_workers"); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -63,6 +76,10 @@ type RetryConfigInput struct {
 	InitialDelaySeconds int
 	MaxDelaySeconds     int
 	BackoffFactor       float64
+	BackoffStrategy     string
+	JitterPercent       int
+	NeverRetryErrors    []string
+	AlwaysRetryErrors   []string
 }
 
 // ValidateRetryConfig validates all retry configuration fields
@@ -82,6 +99,24 @@ func ValidateRetryConfig(cfg RetryConfigInput) error {
 	if cfg.BackoffFactor < MinBackoffFactor || cfg.BackoffFactor > MaxBackoffFactor {
 		return fmt.Errorf("backoff_factor must be between %.1f and %.1f", MinBackoffFactor, MaxBackoffFactor)
 	}
+	switch cfg.BackoffStrategy {
+	case "", data.BackoffExponential, data.BackoffLinear, data.BackoffFixed:
+	default:
+		return fmt.Errorf("backoff_strategy must be %s, %s or %s", data.BackoffExponential, data.BackoffLinear, data.BackoffFixed)
+	}
+	if cfg.JitterPercent < 0 || cfg.JitterPercent > MaxJitterPercent {
+		return fmt.Errorf("jitter_percent must be between 0 and %d", MaxJitterPercent)
+	}
+	for _, class := range append(slices.Clone(cfg.NeverRetryErrors), cfg.AlwaysRetryErrors...) {
+		if !slices.Contains(data.RetryErrorClasses, class) {
+			return fmt.Errorf("unknown error class %q, must be one of %s", class, strings.Join(data.RetryErrorClasses, ", "))
+		}
+	}
+	for _, class := range cfg.NeverRetryErrors {
+		if slices.Contains(cfg.AlwaysRetryErrors, class) {
+			return fmt.Errorf("error class %q can't be in both never_retry_errors and always_retry_errors", class)
+		}
+	}
 	return nil
 }
 
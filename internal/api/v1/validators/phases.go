@@ -5,10 +5,10 @@ import "fmt"
 // Valid phase constants
 var (
 	// AllPhases includes all processing phases including scan
-	AllPhases = map[string]bool{"metadata": true, "thumbnail": true, "sprites": true, "animated_thumbnails": true, "scan": true}
+	AllPhases = map[string]bool{"metadata": true, "thumbnail": true, "sprites": true, "animated_thumbnails": true, "verify": true, "checksum": true, "scan": true}
 
 	// ProcessingPhases includes only scene processing phases (not scan)
-	ProcessingPhases = map[string]bool{"metadata": true, "thumbnail": true, "sprites": true, "animated_thumbnails": true}
+	ProcessingPhases = map[string]bool{"metadata": true, "thumbnail": true, "sprites": true, "animated_thumbnails": true, "verify": true, "checksum": true}
 
 	// TriggerTypes includes all valid trigger types
 	TriggerTypes = map[string]bool{"on_import": true, "after_job": true, "manual": true, "scheduled": true}
@@ -26,7 +26,7 @@ var (
 // ValidatePhase validates a phase is one of the allowed phases
 func ValidatePhase(phase string) error {
 	if !AllPhases[phase] {
-		return fmt.Errorf("phase must be one of: metadata, thumbnail, sprites, animated_thumbnails, scan")
+		return fmt.Errorf("phase must be one of: metadata, thumbnail, sprites, animated_thumbnails, verify, checksum, scan")
 	}
 	return nil
 }
@@ -34,7 +34,7 @@ func ValidatePhase(phase string) error {
 // ValidateProcessingPhase validates a phase is one of the scene processing phases
 func ValidateProcessingPhase(phase string) error {
 	if !ProcessingPhases[phase] {
-		return fmt.Errorf("phase must be one of: metadata, thumbnail, sprites, animated_thumbnails")
+		return fmt.Errorf("phase must be one of: metadata, thumbnail, sprites, animated_thumbnails, verify, checksum")
 	}
 	return nil
 }
@@ -53,10 +53,10 @@ func ValidateTriggerType(phase, triggerType string) error {
 	return nil
 }
 
-// ValidateOnImportTrigger validates that on_import is only used with metadata
+// ValidateOnImportTrigger validates that on_import is only used with metadata or checksum
 func ValidateOnImportTrigger(phase, triggerType string) error {
-	if triggerType == "on_import" && phase != "metadata" {
-		return fmt.Errorf("only metadata phase can use on_import trigger")
+	if triggerType == "on_import" && phase != "metadata" && phase != "checksum" {
+		return fmt.Errorf("only metadata and checksum phases can use on_import trigger")
 	}
 	return nil
 }
@@ -67,7 +67,7 @@ func ValidateAfterJobTrigger(phase string, afterPhase *string) error {
 		return fmt.Errorf("after_phase is required when trigger_type is after_job")
 	}
 	if !ProcessingPhases[*afterPhase] {
-		return fmt.Errorf("after_phase must be one of: metadata, thumbnail, sprites, animated_thumbnails")
+		return fmt.Errorf("after_phase must be one of: metadata, thumbnail, sprites, animated_thumbnails, verify, checksum")
 	}
 	if *afterPhase == phase {
 		return fmt.Errorf("after_phase cannot be the same as phase")
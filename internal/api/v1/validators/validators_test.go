@@ -12,6 +12,8 @@ func TestValidatePhase(t *testing.T) {
 		{"valid thumbnail", "thumbnail", false},
 		{"valid sprites", "sprites", false},
 		{"valid animated_thumbnails", "animated_thumbnails", false},
+		{"valid verify", "verify", false},
+		{"valid checksum", "checksum", false},
 		{"valid scan", "scan", false},
 		{"invalid phase", "invalid", true},
 		{"empty phase", "", true},
@@ -37,6 +39,8 @@ func TestValidateProcessingPhase(t *testing.T) {
 		{"valid thumbnail", "thumbnail", false},
 		{"valid sprites", "sprites", false},
 		{"valid animated_thumbnails", "animated_thumbnails", false},
+		{"valid verify", "verify", false},
+		{"valid checksum", "checksum", false},
 		{"scan is invalid for processing", "scan", true},
 		{"invalid phase", "invalid", true},
 	}
@@ -89,6 +93,7 @@ func TestValidateOnImportTrigger(t *testing.T) {
 		{"on_import with metadata", "metadata", "on_import", false},
 		{"on_import with thumbnail", "thumbnail", "on_import", true},
 		{"on_import with sprites", "sprites", "on_import", true},
+		{"on_import with checksum", "checksum", "on_import", false},
 		{"after_job with any phase", "thumbnail", "after_job", false},
 	}
 
@@ -187,14 +192,18 @@ func TestValidatePoolConfig(t *testing.T) {
 		cfg     PoolConfigInput
 		wantErr bool
 	}{
-		{"all valid", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5}, false},
-		{"minimum all", PoolConfigInput{MetadataWorkers: 1, ThumbnailWorkers: 1, SpritesWorkers: 1, AnimatedThumbnailsWorkers: 1}, false},
-		{"maximum all", PoolConfigInput{MetadataWorkers: 10, ThumbnailWorkers: 10, SpritesWorkers: 10, AnimatedThumbnailsWorkers: 10}, false},
-		{"metadata too low", PoolConfigInput{MetadataWorkers: 0, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5}, true},
-		{"thumbnail too high", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 11, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5}, true},
-		{"sprites invalid", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: -1, AnimatedThumbnailsWorkers: 5}, true},
-		{"animated_thumbnails too low", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 0}, true},
-		{"animated_thumbnails too high", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 11}, true},
+		{"all valid", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5, VerifyWorkers: 5, ChecksumWorkers: 5}, false},
+		{"minimum all", PoolConfigInput{MetadataWorkers: 1, ThumbnailWorkers: 1, SpritesWorkers: 1, AnimatedThumbnailsWorkers: 1, VerifyWorkers: 1, ChecksumWorkers: 1}, false},
+		{"maximum all", PoolConfigInput{MetadataWorkers: 10, ThumbnailWorkers: 10, SpritesWorkers: 10, AnimatedThumbnailsWorkers: 10, VerifyWorkers: 10, ChecksumWorkers: 10}, false},
+		{"metadata too low", PoolConfigInput{MetadataWorkers: 0, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5, VerifyWorkers: 5, ChecksumWorkers: 5}, true},
+		{"thumbnail too high", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 11, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5, VerifyWorkers: 5, ChecksumWorkers: 5}, true},
+		{"sprites invalid", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: -1, AnimatedThumbnailsWorkers: 5, VerifyWorkers: 5, ChecksumWorkers: 5}, true},
+		{"animated_thumbnails too low", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 0, VerifyWorkers: 5, ChecksumWorkers: 5}, true},
+		{"animated_thumbnails too high", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 11, VerifyWorkers: 5, ChecksumWorkers: 5}, true},
+		{"verify too low", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5, VerifyWorkers: 0, ChecksumWorkers: 5}, true},
+		{"verify too high", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5, VerifyWorkers: 11, ChecksumWorkers: 5}, true},
+		{"checksum too low", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5, VerifyWorkers: 5, ChecksumWorkers: 0}, true},
+		{"checksum too high", PoolConfigInput{MetadataWorkers: 5, ThumbnailWorkers: 5, SpritesWorkers: 5, AnimatedThumbnailsWorkers: 5, VerifyWorkers: 5, ChecksumWorkers: 11}, true},
 	}
 
 	for _, tt := range tests {
@@ -253,6 +262,31 @@ func TestValidateRetryConfig(t *testing.T) {
 			RetryConfigInput{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 60, MaxDelaySeconds: 3600, BackoffFactor: 6.0},
 			true,
 		},
+		{
+			"policy valid",
+			RetryConfigInput{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 60, MaxDelaySeconds: 3600, BackoffFactor: 2.0, BackoffStrategy: "linear", JitterPercent: 50, NeverRetryErrors: []string{"not_found"}, AlwaysRetryErrors: []string{"timeout"}},
+			false,
+		},
+		{
+			"unknown backoff strategy",
+			RetryConfigInput{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 60, MaxDelaySeconds: 3600, BackoffFactor: 2.0, BackoffStrategy: "random"},
+			true,
+		},
+		{
+			"jitter too high",
+			RetryConfigInput{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 60, MaxDelaySeconds: 3600, BackoffFactor: 2.0, JitterPercent: 101},
+			true,
+		},
+		{
+			"unknown error class",
+			RetryConfigInput{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 60, MaxDelaySeconds: 3600, BackoffFactor: 2.0, NeverRetryErrors: []string{"gremlins"}},
+			true,
+		},
+		{
+			"error class in both lists",
+			RetryConfigInput{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 60, MaxDelaySeconds: 3600, BackoffFactor: 2.0, NeverRetryErrors: []string{"timeout"}, AlwaysRetryErrors: []string{"timeout"}},
+			true,
+		},
 	}
 
 	for _, tt := range tests {
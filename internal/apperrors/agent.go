@@ -0,0 +1,34 @@
+package apperrors
+
+import "net/http"
+
+// ErrAgentNotFound creates a NotFoundError for a remote agent that is not registered.
+// Agents re-register when they receive it.
+func ErrAgentNotFound(id string) *NotFoundError {
+	return NewNotFoundError("agent", id)
+}
+
+// ErrRemoteTaskNotFound creates a NotFoundError for a task not assigned to the calling agent.
+func ErrRemoteTaskNotFound(id string) *NotFoundError {
+	return NewNotFoundError("remote_task", id)
+}
+
+// ErrRemoteTaskCancelled is returned when an agent reports on a task the server has abandoned.
+var ErrRemoteTaskCancelled = &ConflictError{
+	baseError: baseError{
+		message:    "task was cancelled",
+		code:       "REMOTE_TASK_CANCELLED",
+		httpStatus: http.StatusGone,
+	},
+	Resource: "remote_task",
+}
+
+// ErrInvalidArtifactName is returned when an uploaded artifact name does not belong to the task.
+var ErrInvalidArtifactName = &ValidationError{
+	baseError: baseError{
+		message:    "invalid artifact name",
+		code:       "INVALID_ARTIFACT_NAME",
+		httpStatus: http.StatusBadRequest,
+	},
+	Field: "name",
+}
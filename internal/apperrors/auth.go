@@ -44,6 +44,64 @@ var ErrTokenRevoked = &UnauthorizedError{
 	},
 }
 
+// ErrPrivacyLocked is returned when a session is idle-locked and must be unlocked with the user's PIN.
+var ErrPrivacyLocked = &ForbiddenError{
+	baseError: baseError{
+		message:    "library is locked",
+		code:       "PRIVACY_LOCKED",
+		httpStatus: http.StatusLocked,
+	},
+}
+
+// ErrInvalidPIN is returned when the privacy lock PIN is incorrect.
+// Deliberately not 401 so clients don't treat it as a logout.
+var ErrInvalidPIN = &ForbiddenError{
+	baseError: baseError{
+		message:    "incorrect PIN",
+		code:       "INVALID_PIN",
+		httpStatus: http.StatusForbidden,
+	},
+}
+
+// ErrPINAttemptsExceeded is returned when too many wrong PINs were entered; the session is revoked.
+var ErrPINAttemptsExceeded = &UnauthorizedError{
+	baseError: baseError{
+		message:    "too many incorrect PIN attempts, please log in again",
+		code:       "PIN_ATTEMPTS_EXCEEDED",
+		httpStatus: http.StatusUnauthorized,
+	},
+}
+
+// ErrInvalidTwoFactorCode is returned when a TOTP or recovery code is incorrect.
+// Deliberately not 401 so clients don't treat it as a logout.
+var ErrInvalidTwoFactorCode = &ForbiddenError{
+	baseError: baseError{
+		message:    "incorrect two-factor code",
+		code:       "INVALID_TWO_FACTOR_CODE",
+		httpStatus: http.StatusForbidden,
+	},
+}
+
+// ErrTwoFactorChallengeExpired is returned when a login's two-factor step is unknown,
+// expired or used up; the user has to enter their password again.
+var ErrTwoFactorChallengeExpired = &UnauthorizedError{
+	baseError: baseError{
+		message:    "two-factor login expired, please log in again",
+		code:       "TWO_FACTOR_CHALLENGE_EXPIRED",
+		httpStatus: http.StatusUnauthorized,
+	},
+}
+
+// ErrTwoFactorSetupRequired is returned when policy requires the user to enroll in
+// two-factor authentication before using the app.
+var ErrTwoFactorSetupRequired = &ForbiddenError{
+	baseError: baseError{
+		message:    "two-factor authentication must be set up first",
+		code:       "TWO_FACTOR_SETUP_REQUIRED",
+		httpStatus: http.StatusForbidden,
+	},
+}
+
 // AccountLockedError represents an account lockout due to too many failed attempts.
 type AccountLockedError struct {
 	baseError
@@ -1,6 +1,7 @@
 package apperrors
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -41,6 +42,11 @@ func ErrSceneNotFound(id uint) *NotFoundError {
 	return NewNotFoundError("scene", id)
 }
 
+// ErrIntegrityReportNotFound creates a NotFoundError for a scene that has not been verified yet.
+func ErrIntegrityReportNotFound(sceneID uint) *NotFoundError {
+	return NewNotFoundError("integrity_report", sceneID)
+}
+
 // ErrTagNotFound creates a NotFoundError for a tag.
 func ErrTagNotFound(id uint) *NotFoundError {
 	return NewNotFoundError("tag", id)
@@ -103,3 +109,50 @@ func ErrSceneFileNotFound(path string) *NotFoundError {
 		ID:       path,
 	}
 }
+
+// Reasons a scene is protected from deletion.
+const (
+	DeletionReasonRated    = "rated"
+	DeletionReasonMarkers  = "has_markers"
+	DeletionReasonPlaylist = "in_playlist"
+)
+
+// ProtectedScene lists why a scene may not be trashed or deleted.
+type ProtectedScene struct {
+	SceneID uint     `json:"scene_id"`
+	Reasons []string `json:"reasons"`
+}
+
+// SceneDeletionProtectedError is returned when a trash/delete request hits scenes
+// guarded by deletion protection. ForceAllowed reports whether resending the
+// request with force=true would bypass the guard.
+type SceneDeletionProtectedError struct {
+	baseError
+	Scenes       []ProtectedScene
+	ForceAllowed bool
+}
+
+// NewSceneDeletionProtectedError creates a SceneDeletionProtectedError.
+func NewSceneDeletionProtectedError(scenes []ProtectedScene, forceAllowed bool) *SceneDeletionProtectedError {
+	code := "SCENE_DELETION_BLOCKED"
+	message := fmt.Sprintf("%d scene(s) are protected from deletion", len(scenes))
+	if forceAllowed {
+		code = "SCENE_DELETION_PROTECTED"
+		message += ", retry with force to delete anyway"
+	}
+	return &SceneDeletionProtectedError{
+		baseError: baseError{
+			message:    message,
+			code:       code,
+			httpStatus: http.StatusConflict,
+		},
+		Scenes:       scenes,
+		ForceAllowed: forceAllowed,
+	}
+}
+
+// IsSceneDeletionProtected checks if an error is a SceneDeletionProtectedError.
+func IsSceneDeletionProtected(err error) bool {
+	var protected *SceneDeletionProtectedError
+	return errors.As(err, &protected)
+}
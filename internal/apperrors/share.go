@@ -24,3 +24,22 @@ var ErrShareLinkAuthRequired = &UnauthorizedError{
 		httpStatus: http.StatusUnauthorized,
 	},
 }
+
+// ErrShareLinkPasswordRequired is returned when a password-protected share link is
+// accessed without having been unlocked.
+var ErrShareLinkPasswordRequired = &UnauthorizedError{
+	baseError: baseError{
+		message:    "this shared scene is password protected",
+		code:       "SHARE_PASSWORD_REQUIRED",
+		httpStatus: http.StatusUnauthorized,
+	},
+}
+
+// ErrShareLinkWrongPassword is returned when unlocking a share link with the wrong password.
+var ErrShareLinkWrongPassword = &UnauthorizedError{
+	baseError: baseError{
+		message:    "incorrect share link password",
+		code:       "INVALID_SHARE_PASSWORD",
+		httpStatus: http.StatusUnauthorized,
+	},
+}
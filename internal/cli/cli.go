@@ -0,0 +1,335 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"goonhub/internal/config"
+	"goonhub/internal/core"
+	"goonhub/internal/data"
+	"goonhub/internal/infrastructure/logging"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// scanPollInterval is how often the scan command checks whether the scan has finished
+const scanPollInterval = time.Second
+
+// command describes a single goonhubctl subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(a *App, ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"create-admin", "Create a new user with the admin role", (*App).runCreateAdmin},
+	{"reset-password", "Reset the password of an existing user", (*App).runResetPassword},
+	{"scan", "Scan all storage paths for new, moved and removed scenes", (*App).runScan},
+	{"reindex", "Rebuild the search index from the database", (*App).runReindex},
+	{"backup", "Export a database backup using pg_dump", (*App).runBackup},
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// IsCommand reports whether name is a known subcommand
+func IsCommand(name string) bool {
+	return findCommand(name) != nil
+}
+
+// PrintUsage writes the list of available subcommands to w
+func PrintUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: goonhubctl [-config path] <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'goonhubctl <command> -h' for command flags.")
+}
+
+// App runs administrative commands directly against the database and core services.
+// It is meant for containers and headless setups where the web UI is unreachable.
+type App struct {
+	cfg             *config.Config
+	logger          *zap.Logger
+	userRepo        data.UserRepository
+	scanHistoryRepo data.ScanHistoryRepository
+	adminService    *core.AdminService
+	scanService     *core.ScanService
+	searchService   *core.SearchService
+	reindexService  *core.SearchReindexService
+	stdin           io.Reader
+	stdout          io.Writer
+}
+
+// NewApp creates a new App
+func NewApp(
+	cfg *config.Config,
+	logger *logging.Logger,
+	userRepo data.UserRepository,
+	scanHistoryRepo data.ScanHistoryRepository,
+	adminService *core.AdminService,
+	scanService *core.ScanService,
+	searchService *core.SearchService,
+	reindexService *core.SearchReindexService,
+) *App {
+	return &App{
+		cfg:             cfg,
+		logger:          logger.Logger,
+		userRepo:        userRepo,
+		scanHistoryRepo: scanHistoryRepo,
+		adminService:    adminService,
+		scanService:     scanService,
+		searchService:   searchService,
+		reindexService:  reindexService,
+		stdin:           os.Stdin,
+		stdout:          os.Stdout,
+	}
+}
+
+// Run executes the subcommand named by args[0] with the remaining arguments
+func (a *App) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command given")
+	}
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	return cmd.run(a, ctx, args[1:])
+}
+
+func (a *App) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stdout)
+	return fs
+}
+
+// readPassword returns the flag value when set, otherwise reads the first line of stdin.
+// Reading from stdin keeps the password out of the process list and shell history.
+func (a *App) readPassword(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	line, err := bufio.NewReader(a.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("password is required (use -password or pipe it on stdin)")
+	}
+	return password, nil
+}
+
+func (a *App) runCreateAdmin(_ context.Context, args []string) error {
+	fs := a.newFlagSet("create-admin")
+	username := fs.String("username", "", "username of the new admin")
+	password := fs.String("password", "", "password of the new admin (read from stdin when omitted)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("-username is required")
+	}
+
+	pw, err := a.readPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	if err := a.adminService.CreateUser(*username, pw, "admin"); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Admin user %q created\n", *username)
+	return nil
+}
+
+func (a *App) runResetPassword(_ context.Context, args []string) error {
+	fs := a.newFlagSet("reset-password")
+	username := fs.String("username", "", "username of the user to update")
+	password := fs.String("password", "", "new password (read from stdin when omitted)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("-username is required")
+	}
+
+	user, err := a.userRepo.GetByUsername(*username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("user %q not found", *username)
+		}
+		return fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	pw, err := a.readPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	if err := a.adminService.ResetUserPassword(user.ID, pw); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Password reset for user %q\n", *username)
+	return nil
+}
+
+func (a *App) runScan(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("scan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// The server keeps its own in-memory scan state, so check the DB for a scan
+	// started elsewhere before starting a second one.
+	running, err := a.scanHistoryRepo.GetRunning()
+	if err != nil {
+		return fmt.Errorf("failed to check for running scans: %w", err)
+	}
+	if running != nil {
+		return fmt.Errorf("scan %d is already running", running.ID)
+	}
+
+	a.scanService.SetIndexer(a.searchService)
+
+	scan, err := a.scanService.StartScan(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "Scan %d started\n", scan.ID)
+
+	ticker := time.NewTicker(scanPollInterval)
+	defer ticker.Stop()
+
+	done := ctx.Done()
+	for a.scanService.GetStatus().Running {
+		select {
+		case <-done:
+			fmt.Fprintln(a.stdout, "Cancelling scan...")
+			if err := a.scanService.CancelScan(); err != nil {
+				a.logger.Warn("Failed to cancel scan", zap.Error(err))
+			}
+			// Keep polling so the scan can record its final state before we exit
+			done = nil
+		case <-ticker.C:
+		}
+	}
+
+	result, err := a.scanHistoryRepo.GetByID(scan.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load scan result: %w", err)
+	}
+
+	fmt.Fprintf(a.stdout, "Scan %d %s: %d paths, %d files, %d added, %d moved, %d removed, %d skipped, %d errors\n",
+		result.ID, result.Status, result.PathsScanned, result.FilesFound,
+		result.VideosAdded, result.VideosMoved, result.VideosRemoved, result.VideosSkipped, result.Errors,
+	)
+	if result.Status == "cancelled" {
+		return fmt.Errorf("scan cancelled")
+	}
+	if result.Status == "failed" {
+		if result.ErrorMessage != nil {
+			return fmt.Errorf("scan failed: %s", *result.ErrorMessage)
+		}
+		return fmt.Errorf("scan failed")
+	}
+	if result.VideosAdded > 0 {
+		fmt.Fprintln(a.stdout, "New scenes were queued for processing and will be picked up by the running server")
+	}
+	return nil
+}
+
+func (a *App) runReindex(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("reindex")
+	resume := fs.Bool("resume", false, "continue an interrupted reindex from its checkpoint instead of starting over")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if !a.searchService.IsAvailable() {
+		return fmt.Errorf("meilisearch is not reachable at %s", a.cfg.Meilisearch.Host)
+	}
+
+	start := time.Now()
+	status, err := a.reindexService.Run(ctx, *resume)
+	if err != nil {
+		if status != nil && status.Resumable {
+			fmt.Fprintf(a.stdout, "Indexed %d of %d scenes; run again with -resume to continue\n", status.Indexed, status.Total)
+		}
+		return fmt.Errorf("reindex failed: %w", err)
+	}
+
+	fmt.Fprintf(a.stdout, "Search index rebuilt (%d scenes) in %s\n", status.Indexed, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func (a *App) runBackup(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("backup")
+	output := fs.String("output", "", "file to write the backup to (default goonhub-<timestamp>.dump)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("goonhub-%s.dump", time.Now().Format("20060102-150405"))
+	}
+
+	pgDump, err := exec.LookPath("pg_dump")
+	if err != nil {
+		return fmt.Errorf("pg_dump not found in PATH: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, pgDump, pgDumpArgs(a.cfg.Database, path)...)
+	cmd.Env = append(os.Environ(),
+		"PGPASSWORD="+a.cfg.Database.Password,
+		"PGSSLMODE="+a.cfg.Database.SSLMode,
+	)
+	cmd.Stdout = a.stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		// Don't leave a truncated dump behind
+		_ = os.Remove(path)
+		return fmt.Errorf("pg_dump failed: %w", err)
+	}
+
+	fmt.Fprintf(a.stdout, "Backup written to %s (restore with pg_restore --clean -d %s %s)\n", path, a.cfg.Database.DBName, path)
+	return nil
+}
+
+// pgDumpArgs builds the pg_dump arguments for a custom-format dump of the configured database.
+// The password is passed through the environment rather than the command line.
+func pgDumpArgs(db config.DatabaseConfig, output string) []string {
+	return []string{
+		"--format=custom",
+		"--no-owner",
+		"--host", db.Host,
+		"--port", strconv.Itoa(db.Port),
+		"--username", db.User,
+		"--dbname", db.DBName,
+		"--file", output,
+	}
+}
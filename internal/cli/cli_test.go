@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"goonhub/internal/config"
+	"goonhub/internal/mocks"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestApp(stdin string) (*App, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &App{
+		cfg:    &config.Config{},
+		logger: zap.NewNop(),
+		stdin:  strings.NewReader(stdin),
+		stdout: out,
+	}, out
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	app, _ := newTestApp("")
+	if err := app.Run(context.Background(), []string{"nope"}); err == nil {
+		t.Fatal("expected error for unknown command")
+	}
+	if err := app.Run(context.Background(), nil); err == nil {
+		t.Fatal("expected error when no command is given")
+	}
+}
+
+func TestIsCommand(t *testing.T) {
+	for _, name := range []string{"create-admin", "reset-password", "scan", "reindex", "backup"} {
+		if !IsCommand(name) {
+			t.Fatalf("expected %q to be a command", name)
+		}
+	}
+	if IsCommand("serve") {
+		t.Fatal("expected serve not to be a command")
+	}
+}
+
+func TestCreateAdmin_RequiresUsername(t *testing.T) {
+	app, _ := newTestApp("")
+	err := app.Run(context.Background(), []string{"create-admin", "-password", "secret"})
+	if err == nil || !strings.Contains(err.Error(), "-username") {
+		t.Fatalf("expected username error, got %v", err)
+	}
+}
+
+func TestResetPassword_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().GetByUsername("ghost").Return(nil, gorm.ErrRecordNotFound)
+
+	app, _ := newTestApp("")
+	app.userRepo = userRepo
+
+	err := app.Run(context.Background(), []string{"reset-password", "-username", "ghost"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestReadPassword(t *testing.T) {
+	tests := []struct {
+		name      string
+		flagValue string
+		stdin     string
+		expected  string
+		wantErr   bool
+	}{
+		{"flag wins", "fromflag", "fromstdin\n", "fromflag", false},
+		{"stdin line", "", "fromstdin\n", "fromstdin", false},
+		{"stdin crlf", "", "fromstdin\r\n", "fromstdin", false},
+		{"stdin without newline", "", "fromstdin", "fromstdin", false},
+		{"empty", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _ := newTestApp(tt.stdin)
+			got, err := app.readPassword(tt.flagValue)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Fatalf("readPassword() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPgDumpArgs(t *testing.T) {
+	db := config.DatabaseConfig{
+		Host:     "postgres",
+		Port:     5432,
+		User:     "goonhub",
+		Password: "secret",
+		DBName:   "goonhub",
+	}
+
+	args := pgDumpArgs(db, "/backups/out.dump")
+	joined := strings.Join(args, " ")
+
+	for _, want := range []string{"--format=custom", "--host postgres", "--port 5432", "--username goonhub", "--dbname goonhub", "--file /backups/out.dump"} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected args to contain %q, got %q", want, joined)
+		}
+	}
+	if strings.Contains(joined, "secret") {
+		t.Fatal("password must not be passed on the command line")
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,11 +22,87 @@ type Config struct {
 	Processing  ProcessingConfig  `mapstructure:"processing"`
 	Auth        AuthConfig        `mapstructure:"auth"`
 	Meilisearch MeilisearchConfig `mapstructure:"meilisearch"`
+	Search      SearchConfig      `mapstructure:"search"`
+	Scan        ScanConfig        `mapstructure:"scan"`
 	PornDB      PornDBConfig      `mapstructure:"porndb"`
+	StashDB     StashDBConfig     `mapstructure:"stashdb"`
+	Scrapers    ScrapersConfig    `mapstructure:"scrapers"`
 	Shutdown    ShutdownConfig    `mapstructure:"shutdown"`
 	Streaming   StreamingConfig   `mapstructure:"streaming"`
 	Pagination  PaginationConfig  `mapstructure:"pagination"`
 	Sharing     SharingConfig     `mapstructure:"sharing"`
+	Agents      AgentsConfig      `mapstructure:"agents"`
+	Casting     CastingConfig     `mapstructure:"casting"`
+	WatchParty  WatchPartyConfig  `mapstructure:"watch_party"`
+
+	DeletionProtection DeletionProtectionConfig `mapstructure:"deletion_protection"`
+	ReviewWorkflow     ReviewWorkflowConfig     `mapstructure:"review_workflow"`
+	MediaSigning       MediaSigningConfig       `mapstructure:"media_signing"`
+}
+
+type ReviewWorkflowConfig struct {
+	States      []string            `mapstructure:"states"`      // review states in order; the first is the state of new scenes
+	Transitions map[string][]string `mapstructure:"transitions"` // allowed target states per state (empty = any transition allowed)
+}
+
+// Validate checks that states are unique, fit the review_state column and that
+// transitions only reference configured states.
+func (c ReviewWorkflowConfig) Validate() error {
+	if len(c.States) == 0 {
+		return fmt.Errorf("at least one state is required")
+	}
+	known := make(map[string]bool, len(c.States))
+	for _, state := range c.States {
+		if state == "" || len(state) > 50 {
+			return fmt.Errorf("state %q must be 1-50 characters", state)
+		}
+		if known[state] {
+			return fmt.Errorf("duplicate state %q", state)
+		}
+		known[state] = true
+	}
+	for from, targets := range c.Transitions {
+		if !known[from] {
+			return fmt.Errorf("transitions reference unknown state %q", from)
+		}
+		for _, to := range targets {
+			if !known[to] {
+				return fmt.Errorf("transition %s -> %s references unknown state %q", from, to, to)
+			}
+		}
+	}
+	return nil
+}
+
+type DeletionProtectionConfig struct {
+	Enabled          bool    `mapstructure:"enabled"`           // guard trash/delete of curated scenes
+	MinRating        float64 `mapstructure:"min_rating"`        // scenes rated at least this by any user are protected (0 = ignore ratings)
+	ProtectMarkers   bool    `mapstructure:"protect_markers"`   // scenes with markers are protected
+	ProtectPlaylists bool    `mapstructure:"protect_playlists"` // scenes in any playlist are protected
+	AllowForce       bool    `mapstructure:"allow_force"`       // a request with force=true bypasses the guard
+}
+
+type AgentsConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`           // accept remote job agents
+	Token            string        `mapstructure:"token"`             // shared secret agents authenticate with
+	Phases           []string      `mapstructure:"phases"`            // phases that may be dispatched to agents
+	HeartbeatTimeout time.Duration `mapstructure:"heartbeat_timeout"` // agents silent for longer are dropped
+}
+
+type CastingConfig struct {
+	BaseURL  string        `mapstructure:"base_url"`  // Base URL cast devices reach the server at (empty = the requesting client's host)
+	TokenTTL time.Duration `mapstructure:"token_ttl"` // how long a cast URL stays valid
+}
+
+type MediaSigningConfig struct {
+	Enabled bool          `mapstructure:"enabled"` // require a session or a signed URL for scene thumbnails, sprites and previews
+	TTL     time.Duration `mapstructure:"ttl"`     // signed URLs stay valid for between ttl and 2*ttl
+}
+
+type WatchPartyConfig struct {
+	MaxMembers   int           `mapstructure:"max_members"`   // users per watch party (0 = unlimited)
+	MaxParties   int           `mapstructure:"max_parties"`   // active watch parties across the server (0 = unlimited)
+	EmptyTimeout time.Duration `mapstructure:"empty_timeout"` // parties nobody is connected to end after this long
 }
 
 type SharingConfig struct {
@@ -40,23 +120,269 @@ type StreamingConfig struct {
 	BufferSize       int           `mapstructure:"buffer_size"`
 	PathCacheTTL     time.Duration `mapstructure:"path_cache_ttl"`
 	PathCacheMaxSize int           `mapstructure:"path_cache_max_size"`
+
+	TranscodeEnabled bool   `mapstructure:"transcode_enabled"` // allow on-the-fly transcode for clients that can't direct play
+	TranscodePreset  string `mapstructure:"transcode_preset"`  // x264 preset for on-the-fly transcodes
+	MaxTranscodes    int    `mapstructure:"max_transcodes"`    // concurrent on-the-fly transcodes
+	EmbedChapters    bool   `mapstructure:"embed_chapters"`    // embed the viewer's markers as chapters in transcoded output
 }
 
 type PornDBConfig struct {
-	APIKey string `mapstructure:"api_key"`
+	APIKey                string        `mapstructure:"api_key"`
+	CacheTTL              time.Duration `mapstructure:"cache_ttl"`               // how long PornDB lookups are cached (0 = no caching)
+	RequestsPerMinute     int           `mapstructure:"requests_per_minute"`     // API requests allowed per minute (0 = unlimited)
+	MaxRetries            int           `mapstructure:"max_retries"`             // retries of a request answered with 429
+	PerformerSyncInterval time.Duration `mapstructure:"performer_sync_interval"` // how often linked actors are refreshed from PornDB (0 = disabled)
+}
+
+type StashDBConfig struct {
+	Endpoint          string `mapstructure:"endpoint"`            // GraphQL endpoint of the stash-box instance
+	APIKey            string `mapstructure:"api_key"`             // empty = StashDB disabled
+	RequestsPerMinute int    `mapstructure:"requests_per_minute"` // API requests allowed per minute (0 = unlimited)
+}
+
+type ScrapersConfig struct {
+	Dir       string        `mapstructure:"dir"`        // directory of YAML site scrapers, read at startup
+	Timeout   time.Duration `mapstructure:"timeout"`    // page fetch timeout
+	UserAgent string        `mapstructure:"user_agent"` // sent with page fetches; some sites block unknown agents
 }
 
 type ShutdownConfig struct {
 	GracefulTimeout   time.Duration `mapstructure:"graceful_timeout"`    // Total shutdown time (default: 30s)
 	JobCompletionWait time.Duration `mapstructure:"job_completion_wait"` // Wait for running jobs (default: 15s)
 	OrphanTimeout     time.Duration `mapstructure:"orphan_timeout"`      // Orphan detection threshold (default: 30s)
-	StuckPendingTime  time.Duration `mapstructure:"stuck_pending_time"`  // Pending job stuck threshold (default: 10m)
+	MaxRequeues       int           `mapstructure:"max_requeues"`        // Requeues of jobs orphaned by a crash (default: 3)
 }
 
 type MeilisearchConfig struct {
 	Host      string `mapstructure:"host"`
 	APIKey    string `mapstructure:"api_key"`
 	IndexName string `mapstructure:"index_name"`
+	// ConsistencyCheckInterval is how often scene IDs in the index are compared
+	// with the database (0 disables the background check)
+	ConsistencyCheckInterval time.Duration `mapstructure:"consistency_check_interval"`
+	// ConsistencyAutoHeal re-indexes missing scenes and deletes orphaned documents
+	// found by the background check
+	ConsistencyAutoHeal bool `mapstructure:"consistency_auto_heal"`
+}
+
+// SearchConfig selects the scene search backend: "meilisearch" requires
+// Meilisearch at startup, "postgres" uses PostgreSQL full-text search only, and
+// "auto" uses Meilisearch with PostgreSQL as fallback while it is unreachable.
+type SearchConfig struct {
+	Backend string `mapstructure:"backend"`
+	// How often watched saved searches are re-run for new matches (0 = never)
+	SavedSearchWatchInterval time.Duration `mapstructure:"saved_search_watch_interval"`
+}
+
+// ScanConfig controls the storage watcher, which imports and removes video
+// files as they change on disk instead of waiting for the next full scan,
+// sidecar metadata ingestion, the dropbox import directory, URL and torrent
+// imports and storage path health checks.
+type ScanConfig struct {
+	WatchEnabled  bool               `mapstructure:"watch_enabled"`
+	WatchDebounce time.Duration      `mapstructure:"watch_debounce"` // quiet period before a changed file is handled
+	Sidecar       SidecarConfig      `mapstructure:"sidecar"`
+	Dropbox       DropboxConfig      `mapstructure:"dropbox"`
+	RemoteImport  RemoteImportConfig `mapstructure:"remote_import"`
+	Torrent       TorrentConfig      `mapstructure:"torrent"`
+
+	HealthCheckInterval    time.Duration `mapstructure:"health_check_interval"`    // how often storage paths are checked (0 = never)
+	HealthHistoryRetention time.Duration `mapstructure:"health_history_retention"` // how long health samples are kept (0 = forever)
+}
+
+// Dropbox collision policies: what happens when an organized file's
+// destination already exists.
+const (
+	DropboxCollisionRename = "rename" // add " (2)", " (3)", ... to the name
+	DropboxCollisionSkip   = "skip"   // leave the file in the dropbox
+)
+
+// DropboxTemplateFields are the placeholders a dropbox template can use.
+var DropboxTemplateFields = []string{"title", "studio", "year", "actor"}
+
+var dropboxPlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// DropboxConfig controls the import directory: video files dropped there are
+// moved into a storage path, organized by Template, and imported like uploads.
+type DropboxConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Path          string        `mapstructure:"path"`            // directory watched for new files; must be outside every storage path
+	StoragePathID uint          `mapstructure:"storage_path_id"` // destination (0 = the default storage path)
+	Template      string        `mapstructure:"template"`        // destination relative to the storage path, without extension ("" = keep the file name)
+	OnCollision   string        `mapstructure:"on_collision"`    // rename or skip
+	PollInterval  time.Duration `mapstructure:"poll_interval"`
+	SettleTime    time.Duration `mapstructure:"settle_time"` // files modified more recently are still being copied
+}
+
+// Validate checks the path, the collision policy and the template placeholders.
+func (c DropboxConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Path == "" || !filepath.IsAbs(c.Path) {
+		return fmt.Errorf("path must be an absolute directory")
+	}
+	switch c.OnCollision {
+	case DropboxCollisionRename, DropboxCollisionSkip:
+	default:
+		return fmt.Errorf("on_collision must be %s or %s (got %q)", DropboxCollisionRename, DropboxCollisionSkip, c.OnCollision)
+	}
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("poll_interval must be positive")
+	}
+	if strings.HasPrefix(c.Template, "/") {
+		return fmt.Errorf("template must be relative to the storage path")
+	}
+	for _, segment := range strings.Split(c.Template, "/") {
+		if segment == ".." {
+			return fmt.Errorf("template must stay inside the storage path")
+		}
+	}
+	for _, match := range dropboxPlaceholder.FindAllStringSubmatch(c.Template, -1) {
+		if !slices.Contains(DropboxTemplateFields, match[1]) {
+			return fmt.Errorf("template references unknown placeholder {%s}", match[1])
+		}
+	}
+	return nil
+}
+
+// RemoteImportConfig controls importing files from URLs: downloads run in
+// their own pool and the files are imported like uploads.
+type RemoteImportConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Workers       int           `mapstructure:"workers"`         // concurrent downloads
+	StoragePathID uint          `mapstructure:"storage_path_id"` // destination when a request names none (0 = the default storage path)
+	Folder        string        `mapstructure:"folder"`          // folder of the storage path files are saved in ("" = its root)
+	RateLimitKB   int64         `mapstructure:"rate_limit_kb"`   // speed limit per download in KiB/s (0 = unlimited)
+	MaxSizeMB     int64         `mapstructure:"max_size_mb"`     // larger downloads are aborted (0 = no limit)
+	Timeout       time.Duration `mapstructure:"timeout"`         // a download taking longer fails (0 = no limit)
+	YtDlpPath     string        `mapstructure:"ytdlp_path"`      // yt-dlp binary for page URLs of supported hosts ("" = direct file URLs only)
+}
+
+// Validate checks the pool size, the limits and that the folder stays inside
+// the storage path.
+func (c RemoteImportConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Workers < 1 {
+		return fmt.Errorf("workers must be at least 1")
+	}
+	if c.RateLimitKB < 0 || c.MaxSizeMB < 0 || c.Timeout < 0 {
+		return fmt.Errorf("rate_limit_kb, max_size_mb and timeout can't be negative")
+	}
+	if filepath.IsAbs(c.Folder) {
+		return fmt.Errorf("folder must be relative to the storage path")
+	}
+	for _, segment := range strings.Split(filepath.ToSlash(c.Folder), "/") {
+		if segment == ".." {
+			return fmt.Errorf("folder must stay inside the storage path")
+		}
+	}
+	return nil
+}
+
+// Torrent clients the torrent import can poll.
+const (
+	TorrentClientQBittorrent  = "qbittorrent"
+	TorrentClientTransmission = "transmission"
+)
+
+// Torrent import modes: what happens to a completed download's files.
+const (
+	TorrentModeCopy = "copy" // hard link, or copy across filesystems, so the torrent keeps seeding
+	TorrentModeMove = "move" // move into the library; the client loses the files
+)
+
+// TorrentConfig controls importing the completed torrents of a category from
+// a qBittorrent or Transmission client into a storage path.
+type TorrentConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Client        string        `mapstructure:"client"`          // qbittorrent or transmission
+	URL           string        `mapstructure:"url"`             // qBittorrent web UI, or Transmission RPC endpoint
+	Username      string        `mapstructure:"username"`        // web UI or RPC credentials, when required
+	Password      string        `mapstructure:"password"`        // set via GOONHUB_SCAN_TORRENT_PASSWORD
+	Category      string        `mapstructure:"category"`        // qBittorrent category or Transmission label watched
+	StoragePathID uint          `mapstructure:"storage_path_id"` // destination (0 = the default storage path)
+	Folder        string        `mapstructure:"folder"`          // folder of the storage path files are imported into ("" = its root)
+	Mode          string        `mapstructure:"mode"`            // copy or move
+	RemotePath    string        `mapstructure:"remote_path"`     // download folder as the client sees it...
+	LocalPath     string        `mapstructure:"local_path"`      // ...and where it is mounted here ("" = same path)
+	PollInterval  time.Duration `mapstructure:"poll_interval"`
+}
+
+// Validate checks the client, its URL, the mode and the path mapping.
+func (c TorrentConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Client {
+	case TorrentClientQBittorrent, TorrentClientTransmission:
+	default:
+		return fmt.Errorf("client must be %s or %s (got %q)", TorrentClientQBittorrent, TorrentClientTransmission, c.Client)
+	}
+	if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http or https URL")
+	}
+	if c.Category == "" {
+		return fmt.Errorf("category must be set, so only the library's downloads are imported")
+	}
+	switch c.Mode {
+	case TorrentModeCopy, TorrentModeMove:
+	default:
+		return fmt.Errorf("mode must be %s or %s (got %q)", TorrentModeCopy, TorrentModeMove, c.Mode)
+	}
+	if (c.RemotePath == "") != (c.LocalPath == "") {
+		return fmt.Errorf("remote_path and local_path must be set together")
+	}
+	if c.LocalPath != "" && !filepath.IsAbs(c.LocalPath) {
+		return fmt.Errorf("local_path must be an absolute directory")
+	}
+	if filepath.IsAbs(c.Folder) {
+		return fmt.Errorf("folder must be relative to the storage path")
+	}
+	for _, segment := range strings.Split(filepath.ToSlash(c.Folder), "/") {
+		if segment == ".." {
+			return fmt.Errorf("folder must stay inside the storage path")
+		}
+	}
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("poll_interval must be positive")
+	}
+	return nil
+}
+
+// Sidecar conflict policies: which side wins when a scene already has a value.
+const (
+	SidecarFileWins = "file_wins"
+	SidecarDBWins   = "db_wins"
+)
+
+// SidecarFields are the scene fields a sidecar file can fill.
+var SidecarFields = []string{"title", "description", "studio", "actors", "tags", "release_date"}
+
+// SidecarConfig controls reading .nfo, .json and .xml files next to a video
+// during scans to pre-populate its scene.
+type SidecarConfig struct {
+	Enabled        bool                `mapstructure:"enabled"`
+	ConflictPolicy string              `mapstructure:"conflict_policy"` // file_wins or db_wins, for scenes already in the library
+	FieldMapping   map[string][]string `mapstructure:"field_mapping"`   // scene field -> sidecar keys tried in order; "actor.name" walks into elements
+}
+
+// Validate checks the conflict policy and that the mapping only names known fields.
+func (c SidecarConfig) Validate() error {
+	switch c.ConflictPolicy {
+	case SidecarFileWins, SidecarDBWins:
+	default:
+		return fmt.Errorf("conflict_policy must be %s or %s (got %q)", SidecarFileWins, SidecarDBWins, c.ConflictPolicy)
+	}
+	for field := range c.FieldMapping {
+		if !slices.Contains(SidecarFields, field) {
+			return fmt.Errorf("field_mapping references unknown field %q", field)
+		}
+	}
+	return nil
 }
 
 type ServerConfig struct {
@@ -69,6 +395,11 @@ type ServerConfig struct {
 	TLSKeyFile     string        `mapstructure:"tls_key_file"`    // Path to TLS private key file
 	TrustedProxies []string      `mapstructure:"trusted_proxies"` // CIDR ranges for trusted proxies (for X-Forwarded-For)
 	SecureCookies  *bool         `mapstructure:"secure_cookies"`  // Override Secure flag on cookies (nil = auto from environment)
+
+	SlowRequestThreshold time.Duration `mapstructure:"slow_request_threshold"` // Requests slower than this are logged with DB/search timings (0 = disabled)
+
+	APIUsageRetentionDays int `mapstructure:"api_usage_retention_days"` // Days of per-user API usage stats to keep (0 = forever)
+	AuditRetentionDays    int `mapstructure:"audit_retention_days"`     // Days of audit log entries to keep (0 = forever)
 }
 
 type DatabaseConfig struct {
@@ -114,11 +445,22 @@ type ProcessingConfig struct {
 	ActorImageDir          string        `mapstructure:"actor_image_dir"`           // directory for actor images
 	StudioLogoDir          string        `mapstructure:"studio_logo_dir"`           // directory for studio logos
 	MarkerThumbnailDir     string        `mapstructure:"marker_thumbnail_dir"`      // directory for marker thumbnails
+	CompilationDir         string        `mapstructure:"compilation_dir"`           // directory for exported marker compilations
+	CompilationTimeout     time.Duration `mapstructure:"compilation_timeout"`       // timeout for a marker compilation job
+	HeatmapInterval        time.Duration `mapstructure:"heatmap_interval"`          // how often changed scene heatmaps are recomputed (0 = disabled)
+	UploadSessionTTL       time.Duration `mapstructure:"upload_session_ttl"`        // chunked uploads idle longer than this are discarded
 	GridCols               int           `mapstructure:"grid_cols"`                 // number of columns in sprite sheet
 	GridRows               int           `mapstructure:"grid_rows"`                 // number of rows in sprite sheet
+	TrickplayEnabled       bool          `mapstructure:"trickplay_enabled"`         // build a BIF trickplay file from the sprite sheets
 	SpritesConcurrency         int           `mapstructure:"sprites_concurrency"`           // concurrent ffmpeg processes for sprite extraction (0 = auto)
 	AnimatedThumbnailsWorkers  int           `mapstructure:"animated_thumbnails_workers"`   // concurrent animated thumbnail jobs
 	AnimatedThumbnailsTimeout  time.Duration `mapstructure:"animated_thumbnails_timeout"`   // timeout for animated thumbnail jobs
+	VerifyWorkers              int           `mapstructure:"verify_workers"`                // concurrent decode verification jobs
+	VerifyTimeout              time.Duration `mapstructure:"verify_timeout"`                // timeout for decode verification jobs
+	ChecksumWorkers            int           `mapstructure:"checksum_workers"`              // concurrent file checksum jobs
+	ChecksumTimeout            time.Duration `mapstructure:"checksum_timeout"`              // timeout for file checksum jobs
+	MaxFFmpegProcesses         int           `mapstructure:"max_ffmpeg_processes"`          // jobs running at once across all pools (0 = unlimited)
+	MetadataQuotaMB            int64         `mapstructure:"metadata_quota_mb"`             // pause sprites/animated thumbnails when metadata_dir exceeds this (0 = no quota)
 	MarkerThumbnailType            string        `mapstructure:"marker_thumbnail_type"`             // "static" or "animated"
 	MarkerAnimatedDuration         int           `mapstructure:"marker_animated_duration"`          // animated clip duration in seconds (3-15)
 	ScenePreviewEnabled            bool          `mapstructure:"scene_preview_enabled"`             // enable scene preview video generation
@@ -127,10 +469,77 @@ type ProcessingConfig struct {
 	ScenePreviewDir                string        `mapstructure:"scene_preview_dir"`                 // directory for scene preview videos
 	MarkerPreviewCRF               int           `mapstructure:"marker_preview_crf"`                // CRF for marker animated thumbnails (18-40)
 	ScenePreviewCRF                int           `mapstructure:"scene_preview_crf"`                 // CRF for scene preview videos (18-40)
-	JobHistoryRetention            string        `mapstructure:"job_history_retention"`             // duration string e.g. "7d", "24h"
+	JobHistoryRetention            string        `mapstructure:"job_history_retention"`             // finished jobs are pruned after this, e.g. "7d", "24h" ("0" = keep)
+	JobHistoryArchive              bool          `mapstructure:"job_history_archive"`               // move pruned jobs to job_history_archive instead of deleting them
+	DLQRetention                   string        `mapstructure:"dlq_retention"`                     // abandoned DLQ entries are deleted after this, e.g. "30d" ("0" = keep)
 	MetadataTimeout            time.Duration `mapstructure:"metadata_timeout"`              // timeout for metadata extraction jobs
 	ThumbnailTimeout           time.Duration `mapstructure:"thumbnail_timeout"`             // timeout for thumbnail extraction jobs
 	SpritesTimeout             time.Duration `mapstructure:"sprites_timeout"`               // timeout for sprite sheet generation jobs
+	Autoscale                  AutoscaleConfig `mapstructure:"autoscale"`
+}
+
+// AutoscaleConfig lets the worker pools grow and shrink between bounds from
+// their backlog and the CPU load average, on top of their configured sizes.
+type AutoscaleConfig struct {
+	Enabled    bool                       `mapstructure:"enabled"`
+	MinWorkers int                        `mapstructure:"min_workers"` // smallest size of a pool
+	MaxWorkers int                        `mapstructure:"max_workers"` // largest size of a pool (at most 10)
+	Pools      map[string]AutoscaleBounds `mapstructure:"pools"`       // per-phase bounds overriding min_workers/max_workers
+	Interval   time.Duration              `mapstructure:"interval"`    // how often the pools are evaluated
+	Cooldown   time.Duration              `mapstructure:"cooldown"`    // least time between two resizes of a pool
+	MaxLoad    float64                    `mapstructure:"max_load"`    // 1-minute load average per CPU above which pools shrink
+}
+
+// AutoscaleBounds are the sizes the autoscaler keeps a pool between.
+type AutoscaleBounds struct {
+	MinWorkers int `mapstructure:"min_workers" json:"min_workers"`
+	MaxWorkers int `mapstructure:"max_workers" json:"max_workers"`
+}
+
+// AutoscalePhases are the pools the autoscaler can resize.
+var AutoscalePhases = []string{"metadata", "thumbnail", "sprites", "animated_thumbnails", "verify", "checksum"}
+
+// Bounds returns the bounds of a phase's pool.
+func (c AutoscaleConfig) Bounds(phase string) AutoscaleBounds {
+	if bounds, ok := c.Pools[phase]; ok {
+		return bounds
+	}
+	return AutoscaleBounds{MinWorkers: c.MinWorkers, MaxWorkers: c.MaxWorkers}
+}
+
+// Validate checks the bounds, which must fit the 1-10 pool sizes, and the timings.
+func (c AutoscaleConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if err := validateAutoscaleBounds(AutoscaleBounds{MinWorkers: c.MinWorkers, MaxWorkers: c.MaxWorkers}); err != nil {
+		return err
+	}
+	for phase, bounds := range c.Pools {
+		if !slices.Contains(AutoscalePhases, phase) {
+			return fmt.Errorf("pools: unknown pool %q", phase)
+		}
+		if err := validateAutoscaleBounds(bounds); err != nil {
+			return fmt.Errorf("pools.%s: %w", phase, err)
+		}
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if c.Cooldown < 0 {
+		return fmt.Errorf("cooldown can't be negative")
+	}
+	if c.MaxLoad <= 0 {
+		return fmt.Errorf("max_load must be positive")
+	}
+	return nil
+}
+
+func validateAutoscaleBounds(b AutoscaleBounds) error {
+	if b.MinWorkers < 1 || b.MaxWorkers > 10 || b.MinWorkers > b.MaxWorkers {
+		return fmt.Errorf("min_workers and max_workers must be between 1 and 10, min_workers at most max_workers")
+	}
+	return nil
 }
 
 type AuthConfig struct {
@@ -159,6 +568,9 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("server.tls_cert_file", "")    // Empty = TLS disabled
 	v.SetDefault("server.tls_key_file", "")     // Empty = TLS disabled
 	v.SetDefault("server.trusted_proxies", nil) // nil = trust no proxies; set to ["127.0.0.1", "::1"] for loopback or CIDR ranges
+	v.SetDefault("server.slow_request_threshold", time.Second)
+	v.SetDefault("server.api_usage_retention_days", 90)
+	v.SetDefault("server.audit_retention_days", 365)
 	v.SetDefault("database.host", "localhost")
 	v.SetDefault("database.port", 5432)
 	v.SetDefault("database.user", "goonhub")
@@ -180,6 +592,7 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("processing.sprites_workers", 1)
 	v.SetDefault("processing.thumbnail_seek", "00:00:05")
 	v.SetDefault("processing.video_dir", "./data/videos")
+	v.SetDefault("processing.upload_session_ttl", 24*time.Hour)
 	v.SetDefault("processing.metadata_dir", "./data/metadata")
 	v.SetDefault("processing.frame_output_dir", "./data/metadata/frames")
 	v.SetDefault("processing.thumbnail_dir", "./data/metadata/thumbnails")
@@ -188,11 +601,21 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("processing.actor_image_dir", "./data/metadata/actors")
 	v.SetDefault("processing.studio_logo_dir", "./data/metadata/studios")
 	v.SetDefault("processing.marker_thumbnail_dir", "./data/metadata/marker-thumbnails")
+	v.SetDefault("processing.compilation_dir", "./data/compilations")
+	v.SetDefault("processing.compilation_timeout", 1*time.Hour)
+	v.SetDefault("processing.heatmap_interval", 15*time.Minute)
 	v.SetDefault("processing.grid_cols", 12)
 	v.SetDefault("processing.grid_rows", 8)
+	v.SetDefault("processing.trickplay_enabled", true)
 	v.SetDefault("processing.sprites_concurrency", 0)
 	v.SetDefault("processing.animated_thumbnails_workers", 1)
 	v.SetDefault("processing.animated_thumbnails_timeout", 5*time.Minute)
+	v.SetDefault("processing.verify_workers", 1)
+	v.SetDefault("processing.verify_timeout", 2*time.Hour)
+	v.SetDefault("processing.checksum_workers", 1)
+	v.SetDefault("processing.checksum_timeout", 2*time.Hour)
+	v.SetDefault("processing.max_ffmpeg_processes", 0)
+	v.SetDefault("processing.metadata_quota_mb", 0)
 	v.SetDefault("processing.marker_thumbnail_type", "static")
 	v.SetDefault("processing.marker_animated_duration", 10)
 	v.SetDefault("processing.scene_preview_enabled", false)
@@ -202,9 +625,17 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("processing.marker_preview_crf", 32)
 	v.SetDefault("processing.scene_preview_crf", 27)
 	v.SetDefault("processing.job_history_retention", "7d")
+	v.SetDefault("processing.job_history_archive", false)
+	v.SetDefault("processing.dlq_retention", "30d")
 	v.SetDefault("processing.metadata_timeout", 5*time.Minute)
 	v.SetDefault("processing.thumbnail_timeout", 2*time.Minute)
 	v.SetDefault("processing.sprites_timeout", 30*time.Minute)
+	v.SetDefault("processing.autoscale.enabled", false)
+	v.SetDefault("processing.autoscale.min_workers", 1)
+	v.SetDefault("processing.autoscale.max_workers", 4)
+	v.SetDefault("processing.autoscale.interval", 30*time.Second)
+	v.SetDefault("processing.autoscale.cooldown", 2*time.Minute)
+	v.SetDefault("processing.autoscale.max_load", 0.9)
 	v.SetDefault("auth.paseto_secret", "")
 	v.SetDefault("auth.admin_username", "admin")
 	v.SetDefault("auth.admin_password", "admin")
@@ -217,11 +648,65 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("meilisearch.host", "http://localhost:7700")
 	v.SetDefault("meilisearch.api_key", "goonhub_dev_master_key")
 	v.SetDefault("meilisearch.index_name", "videos")
+	v.SetDefault("meilisearch.consistency_check_interval", 6*time.Hour)
+	v.SetDefault("meilisearch.consistency_auto_heal", false)
+	v.SetDefault("search.backend", "auto")
+	v.SetDefault("search.saved_search_watch_interval", time.Hour)
+	v.SetDefault("scan.watch_enabled", false)
+	v.SetDefault("scan.watch_debounce", 5*time.Second)
+	v.SetDefault("scan.health_check_interval", 5*time.Minute)
+	v.SetDefault("scan.health_history_retention", 30*24*time.Hour)
+	v.SetDefault("scan.dropbox.enabled", false)
+	v.SetDefault("scan.dropbox.path", "")
+	v.SetDefault("scan.dropbox.storage_path_id", 0)
+	v.SetDefault("scan.dropbox.template", "{studio}/{year}/{title}")
+	v.SetDefault("scan.dropbox.on_collision", DropboxCollisionRename)
+	v.SetDefault("scan.dropbox.poll_interval", 30*time.Second)
+	v.SetDefault("scan.dropbox.settle_time", time.Minute)
+	v.SetDefault("scan.remote_import.enabled", true)
+	v.SetDefault("scan.remote_import.workers", 2)
+	v.SetDefault("scan.remote_import.storage_path_id", 0)
+	v.SetDefault("scan.remote_import.folder", "")
+	v.SetDefault("scan.remote_import.rate_limit_kb", 0)
+	v.SetDefault("scan.remote_import.max_size_mb", 0)
+	v.SetDefault("scan.remote_import.timeout", 6*time.Hour)
+	v.SetDefault("scan.remote_import.ytdlp_path", "")
+	v.SetDefault("scan.torrent.enabled", false)
+	v.SetDefault("scan.torrent.client", TorrentClientQBittorrent)
+	v.SetDefault("scan.torrent.url", "")
+	v.SetDefault("scan.torrent.username", "")
+	v.SetDefault("scan.torrent.password", "")
+	v.SetDefault("scan.torrent.category", "goonhub")
+	v.SetDefault("scan.torrent.storage_path_id", 0)
+	v.SetDefault("scan.torrent.folder", "")
+	v.SetDefault("scan.torrent.mode", TorrentModeCopy)
+	v.SetDefault("scan.torrent.remote_path", "")
+	v.SetDefault("scan.torrent.local_path", "")
+	v.SetDefault("scan.torrent.poll_interval", time.Minute)
+	v.SetDefault("scan.sidecar.enabled", false)
+	v.SetDefault("scan.sidecar.conflict_policy", SidecarDBWins)
+	// Set per field so a config file can override one field and keep the rest
+	v.SetDefault("scan.sidecar.field_mapping.title", []string{"title", "originaltitle"})
+	v.SetDefault("scan.sidecar.field_mapping.description", []string{"plot", "outline", "description", "details"})
+	v.SetDefault("scan.sidecar.field_mapping.studio", []string{"studio", "studio.name", "site"})
+	v.SetDefault("scan.sidecar.field_mapping.actors", []string{"actor.name", "performers.name", "performers", "actors"})
+	v.SetDefault("scan.sidecar.field_mapping.tags", []string{"tag", "genre", "tags.name", "tags"})
+	v.SetDefault("scan.sidecar.field_mapping.release_date", []string{"premiered", "releasedate", "release_date", "date", "aired"})
 	v.SetDefault("porndb.api_key", "")
+	v.SetDefault("porndb.cache_ttl", 7*24*time.Hour)
+	v.SetDefault("porndb.requests_per_minute", 60)
+	v.SetDefault("porndb.max_retries", 4)
+	v.SetDefault("porndb.performer_sync_interval", 24*time.Hour)
+	v.SetDefault("stashdb.endpoint", "https://stashdb.org/graphql")
+	v.SetDefault("stashdb.api_key", "")
+	v.SetDefault("stashdb.requests_per_minute", 60)
+	v.SetDefault("scrapers.dir", "./data/scrapers")
+	v.SetDefault("scrapers.timeout", 30*time.Second)
+	v.SetDefault("scrapers.user_agent", "Mozilla/5.0 (compatible; GoonHub)")
 	v.SetDefault("shutdown.graceful_timeout", 30*time.Second)
 	v.SetDefault("shutdown.job_completion_wait", 15*time.Second)
 	v.SetDefault("shutdown.orphan_timeout", 30*time.Second)
-	v.SetDefault("shutdown.stuck_pending_time", 10*time.Minute)
+	v.SetDefault("shutdown.max_requeues", 3)
 	v.SetDefault("pagination.max_items_per_page", 100)
 	v.SetDefault("sharing.base_url", "")
 	v.SetDefault("sharing.port", "")
@@ -230,6 +715,29 @@ func Load(path string) (*Config, error) {
 	v.SetDefault("streaming.buffer_size", 262144)       // 256KB (8x default 32KB)
 	v.SetDefault("streaming.path_cache_ttl", 5*time.Minute)
 	v.SetDefault("streaming.path_cache_max_size", 10000)
+	v.SetDefault("streaming.transcode_enabled", true)
+	v.SetDefault("streaming.transcode_preset", "veryfast")
+	v.SetDefault("streaming.max_transcodes", 2)
+	v.SetDefault("streaming.embed_chapters", false)
+	v.SetDefault("agents.enabled", false)
+	v.SetDefault("agents.token", "")
+	v.SetDefault("agents.phases", []string{"sprites"})
+	v.SetDefault("agents.heartbeat_timeout", 45*time.Second)
+	v.SetDefault("casting.base_url", "")
+	v.SetDefault("casting.token_ttl", 6*time.Hour)
+
+	v.SetDefault("media_signing.enabled", false)
+	v.SetDefault("media_signing.ttl", time.Hour)
+
+	v.SetDefault("watch_party.max_members", 10)
+	v.SetDefault("watch_party.max_parties", 50)
+	v.SetDefault("watch_party.empty_timeout", 5*time.Minute)
+	v.SetDefault("deletion_protection.enabled", true)
+	v.SetDefault("deletion_protection.min_rating", 4.0)
+	v.SetDefault("deletion_protection.protect_markers", true)
+	v.SetDefault("deletion_protection.protect_playlists", true)
+	v.SetDefault("deletion_protection.allow_force", true)
+	v.SetDefault("review_workflow.states", []string{"unreviewed", "needs_metadata", "curated"})
 
 	// Environment variables
 	v.SetEnvPrefix("GOONHUB")
@@ -266,6 +774,40 @@ func Load(path string) (*Config, error) {
 		fmt.Println("[WARNING] Set GOONHUB_AUTH_PASETO_SECRET environment variable for persistent sessions")
 	}
 
+	if cfg.Agents.Enabled && len(cfg.Agents.Token) < 16 {
+		return nil, fmt.Errorf("GOONHUB_AGENTS_TOKEN must be at least 16 characters when remote agents are enabled")
+	}
+
+	switch cfg.Search.Backend {
+	case "auto", "meilisearch", "postgres":
+	default:
+		return nil, fmt.Errorf("search.backend must be auto, meilisearch or postgres (got %q)", cfg.Search.Backend)
+	}
+
+	if err := cfg.ReviewWorkflow.Validate(); err != nil {
+		return nil, fmt.Errorf("review_workflow: %w", err)
+	}
+
+	if err := cfg.Scan.Sidecar.Validate(); err != nil {
+		return nil, fmt.Errorf("scan.sidecar: %w", err)
+	}
+
+	if err := cfg.Scan.Dropbox.Validate(); err != nil {
+		return nil, fmt.Errorf("scan.dropbox: %w", err)
+	}
+
+	if err := cfg.Processing.Autoscale.Validate(); err != nil {
+		return nil, fmt.Errorf("processing.autoscale: %w", err)
+	}
+
+	if err := cfg.Scan.RemoteImport.Validate(); err != nil {
+		return nil, fmt.Errorf("scan.remote_import: %w", err)
+	}
+
+	if err := cfg.Scan.Torrent.Validate(); err != nil {
+		return nil, fmt.Errorf("scan.torrent: %w", err)
+	}
+
 	// Validate production security requirements
 	if cfg.Environment == "production" {
 		if err := validateAdminPassword(cfg.Auth.AdminPassword); err != nil {
@@ -0,0 +1,350 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/pkg/ffmpeg"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ActorImagePhase is the job_history phase of runs generating missing actor
+// images. They run outside the scene processing pools, so they can't be
+// retried from the jobs page.
+const ActorImagePhase = "actor_image"
+
+const (
+	actorImageBatchSize = 100
+	// actorImageCandidateScenes is how many of an actor's latest scenes are
+	// considered when picking a frame
+	actorImageCandidateScenes = 20
+	actorImageHeight          = 600
+	actorImageQuality         = 85
+	// actorImageFallbackPosition is how far into a scene without markers the
+	// frame is taken, past intros and title cards
+	actorImageFallbackPosition = 0.4
+	actorImageExtractTimeout   = time.Minute
+)
+
+// errNoActorImageSource is returned for actors with no playable scene.
+var errNoActorImageSource = errors.New("actor has no scene to take an image from")
+
+// ActorImageJob is a running generation of missing actor images.
+type ActorImageJob struct {
+	JobID string `json:"job_id"`
+	Total int64  `json:"total"` // actors without an image
+}
+
+type actorImageSummary struct {
+	Generated int
+	Skipped   int
+	Failed    int
+}
+
+// portraitFrameExtractor writes a portrait frame of a video at a second to
+// outputPath.
+type portraitFrameExtractor func(ctx context.Context, videoPath, outputPath string, second int) error
+
+func extractPortraitFrame(ctx context.Context, videoPath, outputPath string, second int) error {
+	return ffmpeg.ExtractPortraitFrameWithContext(ctx, videoPath, outputPath, strconv.Itoa(second), actorImageHeight, actorImageQuality)
+}
+
+// ActorImageService gives actors an image cut from a frame of one of their
+// scenes. The frame comes from a scene the actor is alone in when there is
+// one, at one of the scene's markers when it has any, and is center-cropped
+// to a portrait.
+type ActorImageService struct {
+	actorRepo  data.ActorRepository
+	sceneRepo  data.SceneRepository
+	markerRepo data.MarkerRepository
+	jobHistory *JobHistoryService
+	imageDir   string
+	extract    portraitFrameExtractor
+	logger     *zap.Logger
+
+	mu      sync.Mutex
+	running bool
+}
+
+func NewActorImageService(
+	actorRepo data.ActorRepository,
+	sceneRepo data.SceneRepository,
+	markerRepo data.MarkerRepository,
+	jobHistory *JobHistoryService,
+	imageDir string,
+	logger *zap.Logger,
+) *ActorImageService {
+	return &ActorImageService{
+		actorRepo:  actorRepo,
+		sceneRepo:  sceneRepo,
+		markerRepo: markerRepo,
+		jobHistory: jobHistory,
+		imageDir:   imageDir,
+		extract:    extractPortraitFrame,
+		logger:     logger.With(zap.String("component", "actor_image")),
+	}
+}
+
+// Start generates an image for every actor without one in the background.
+// Actors without a playable scene are skipped.
+func (s *ActorImageService) Start() (*ActorImageJob, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, apperrors.NewConflictError("actor images", "actor image generation is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	total, err := s.actorRepo.CountWithoutImage()
+	if err != nil {
+		s.finish()
+		return nil, apperrors.NewInternalError("failed to count actors without an image", err)
+	}
+
+	job := &ActorImageJob{JobID: uuid.New().String(), Total: total}
+	s.jobHistory.RecordJobStart(job.JobID, 0, fmt.Sprintf("Images for %d actors", total), ActorImagePhase)
+
+	go func() {
+		defer s.finish()
+		summary, err := s.run(job)
+		if err != nil {
+			s.logger.Error("Actor image generation failed", zap.String("job_id", job.JobID), zap.Error(err))
+			s.jobHistory.RecordJobFailed(job.JobID, err)
+			return
+		}
+		s.logger.Info("Actor image generation completed",
+			zap.String("job_id", job.JobID),
+			zap.Int("generated", summary.Generated),
+			zap.Int("skipped", summary.Skipped),
+			zap.Int("failed", summary.Failed),
+		)
+		s.jobHistory.RecordJobComplete(job.JobID)
+	}()
+
+	return job, nil
+}
+
+// IsRunning reports whether a generation is running in this process.
+func (s *ActorImageService) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+func (s *ActorImageService) finish() {
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+}
+
+// run goes through the actors without an image in ID order. An actor that
+// fails is logged and left for the next run.
+func (s *ActorImageService) run(job *ActorImageJob) (actorImageSummary, error) {
+	var summary actorImageSummary
+	var afterID uint
+	done := 0
+	for {
+		actors, err := s.actorRepo.ListWithoutImage(afterID, actorImageBatchSize)
+		if err != nil {
+			return summary, fmt.Errorf("failed to list actors without an image: %w", err)
+		}
+		if len(actors) == 0 {
+			return summary, nil
+		}
+
+		for i := range actors {
+			actor := &actors[i]
+			afterID = actor.ID
+
+			scene, second, err := s.pickFrame(actor.ID)
+			if err == nil {
+				err = s.saveFrame(actor, scene, second)
+			}
+			switch {
+			case errors.Is(err, errNoActorImageSource):
+				summary.Skipped++
+			case err != nil:
+				summary.Failed++
+				s.logger.Warn("Failed to generate actor image", zap.Uint("actor_id", actor.ID), zap.Error(err))
+			default:
+				summary.Generated++
+			}
+
+			done++
+			if job.Total > 0 {
+				s.jobHistory.UpdateProgress(job.JobID, min(100, done*100/int(job.Total)))
+			}
+		}
+	}
+}
+
+// Generate replaces an actor's image with a frame of one of their scenes. The
+// scene and second are picked as for missing images unless given; a scene
+// given without a second uses its most central marker or, without markers,
+// the fallback position.
+func (s *ActorImageService) Generate(actorID uint, sceneID *uint, second *int) (*data.Actor, error) {
+	actor, err := s.actorRepo.GetByID(actorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrActorNotFound(actorID)
+		}
+		return nil, apperrors.NewInternalError("failed to find actor", err)
+	}
+
+	var scene *data.Scene
+	var at int
+	if sceneID == nil {
+		scene, at, err = s.pickFrame(actorID)
+		if errors.Is(err, errNoActorImageSource) {
+			return nil, apperrors.NewValidationError(err.Error())
+		}
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to pick a frame", err)
+		}
+	} else {
+		if scene, err = s.actorScene(actorID, *sceneID); err != nil {
+			return nil, err
+		}
+		if second == nil {
+			if at, err = s.frameSecond(scene, true); err != nil {
+				return nil, apperrors.NewInternalError("failed to get scene markers", err)
+			}
+		}
+	}
+	if second != nil {
+		if *second < 0 || *second >= scene.Duration {
+			return nil, apperrors.NewValidationErrorWithField("timestamp", "timestamp must be within the scene")
+		}
+		at = *second
+	}
+
+	if err := s.saveFrame(actor, scene, at); err != nil {
+		return nil, apperrors.NewInternalError("failed to generate actor image", err)
+	}
+	return actor, nil
+}
+
+// actorScene returns one of the actor's scenes that can be played.
+func (s *ActorImageService) actorScene(actorID, sceneID uint) (*data.Scene, error) {
+	sceneIDs, err := s.actorRepo.GetActorSceneIDs(actorID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get actor scenes", err)
+	}
+	if !slices.Contains(sceneIDs, sceneID) {
+		return nil, apperrors.NewValidationErrorWithField("scene_id", "the actor is not in this scene")
+	}
+	scene, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to find scene", err)
+	}
+	if scene.StoredPath == "" || scene.Duration <= 0 {
+		return nil, apperrors.NewValidationErrorWithField("scene_id", "the scene has no playable video")
+	}
+	return scene, nil
+}
+
+// pickFrame picks the scene and second to take an actor's image from: among
+// their latest playable scenes, one they are alone in, then the one with the
+// most markers, then the latest.
+func (s *ActorImageService) pickFrame(actorID uint) (*data.Scene, int, error) {
+	scenes, _, err := s.actorRepo.GetActorScenes(actorID, nil, 1, actorImageCandidateScenes)
+	if err != nil {
+		return nil, 0, err
+	}
+	scenes = slices.DeleteFunc(scenes, func(scene data.Scene) bool {
+		return scene.StoredPath == "" || scene.Duration <= 0
+	})
+	if len(scenes) == 0 {
+		return nil, 0, errNoActorImageSource
+	}
+
+	ids := make([]uint, len(scenes))
+	for i, scene := range scenes {
+		ids[i] = scene.ID
+	}
+	markerCounts, err := s.markerRepo.CountBySceneIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	slices.SortStableFunc(scenes, func(a, b data.Scene) int {
+		if soloA, soloB := len(a.Actors) == 1, len(b.Actors) == 1; soloA != soloB {
+			if soloA {
+				return -1
+			}
+			return 1
+		}
+		return int(markerCounts[b.ID] - markerCounts[a.ID])
+	})
+
+	scene := &scenes[0]
+	second, err := s.frameSecond(scene, markerCounts[scene.ID] > 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	return scene, second, nil
+}
+
+// frameSecond returns the timestamp of the scene's middle marker, or the
+// fallback position when it has none. Markers are only looked up when the
+// scene may have some.
+func (s *ActorImageService) frameSecond(scene *data.Scene, mayHaveMarkers bool) (int, error) {
+	fallback := int(float64(scene.Duration) * actorImageFallbackPosition)
+	if !mayHaveMarkers {
+		return fallback, nil
+	}
+	markers, err := s.markerRepo.GetAllByScene(scene.ID)
+	if err != nil {
+		return 0, err
+	}
+	markers = slices.DeleteFunc(markers, func(m data.UserSceneMarker) bool {
+		return m.Timestamp < 0 || m.Timestamp >= scene.Duration
+	})
+	if len(markers) == 0 {
+		return fallback, nil
+	}
+	slices.SortFunc(markers, func(a, b data.UserSceneMarker) int { return a.Timestamp - b.Timestamp })
+	return markers[len(markers)/2].Timestamp, nil
+}
+
+// saveFrame writes the frame to the actor image directory and points the
+// actor's image at it.
+func (s *ActorImageService) saveFrame(actor *data.Actor, scene *data.Scene, second int) error {
+	if err := os.MkdirAll(s.imageDir, 0755); err != nil {
+		return fmt.Errorf("failed to create image directory: %w", err)
+	}
+	filename := uuid.New().String() + ".webp"
+	path := filepath.Join(s.imageDir, filename)
+
+	ctx, cancel := context.WithTimeout(context.Background(), actorImageExtractTimeout)
+	defer cancel()
+	if err := s.extract(ctx, scene.StoredPath, path, second); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to extract frame from scene %d: %w", scene.ID, err)
+	}
+
+	actor.ImageURL = "/actor-images/" + filename
+	if err := s.actorRepo.Update(actor); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to update actor image: %w", err)
+	}
+
+	s.logger.Info("Actor image generated",
+		zap.Uint("actor_id", actor.ID),
+		zap.Uint("scene_id", scene.ID),
+		zap.Int("second", second),
+	)
+	return nil
+}
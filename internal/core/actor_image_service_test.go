@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"github.com/lib/pq"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestActorImageService(t *testing.T) (*ActorImageService, *mocks.MockActorRepository, *mocks.MockSceneRepository, *mocks.MockMarkerRepository) {
+	ctrl := gomock.NewController(t)
+	actorRepo := mocks.NewMockActorRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	svc := NewActorImageService(actorRepo, sceneRepo, markerRepo, nil, t.TempDir(), zap.NewNop())
+	return svc, actorRepo, sceneRepo, markerRepo
+}
+
+func TestActorImageService_pickFrame(t *testing.T) {
+	svc, actorRepo, _, markerRepo := newTestActorImageService(t)
+
+	actorRepo.EXPECT().GetActorScenes(uint(1), nil, 1, actorImageCandidateScenes).Return([]data.Scene{
+		{ID: 10, StoredPath: "/v/10.mp4", Duration: 600, Actors: pq.StringArray{"Jane", "John"}},
+		{ID: 11, StoredPath: "", Duration: 600, Actors: pq.StringArray{"Jane"}},
+		{ID: 12, StoredPath: "/v/12.mp4", Duration: 1000, Actors: pq.StringArray{"Jane"}},
+		{ID: 13, StoredPath: "/v/13.mp4", Duration: 300, Actors: pq.StringArray{"Jane"}},
+	}, int64(4), nil)
+	markerRepo.EXPECT().CountBySceneIDs([]uint{10, 12, 13}).Return(map[uint]int64{10: 5, 13: 3}, nil)
+	markerRepo.EXPECT().GetAllByScene(uint(13)).Return([]data.UserSceneMarker{
+		{Timestamp: 200}, {Timestamp: 20}, {Timestamp: 900}, {Timestamp: 120},
+	}, nil)
+
+	// Solo scenes come first, then the one with markers; the marker past the
+	// end of the scene is ignored
+	scene, second, err := svc.pickFrame(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scene.ID != 13 || second != 120 {
+		t.Fatalf("expected scene 13 at 120s, got scene %d at %ds", scene.ID, second)
+	}
+
+	actorRepo.EXPECT().GetActorScenes(uint(2), nil, 1, actorImageCandidateScenes).Return([]data.Scene{
+		{ID: 20, StoredPath: "/v/20.mp4", Duration: 1000},
+	}, int64(1), nil)
+	markerRepo.EXPECT().CountBySceneIDs([]uint{20}).Return(map[uint]int64{}, nil)
+	scene, second, err = svc.pickFrame(2)
+	if err != nil || scene.ID != 20 || second != 400 {
+		t.Fatalf("expected the fallback position of scene 20, got %v at %ds (err %v)", scene, second, err)
+	}
+
+	actorRepo.EXPECT().GetActorScenes(uint(3), nil, 1, actorImageCandidateScenes).Return([]data.Scene{{ID: 30}}, int64(1), nil)
+	if _, _, err := svc.pickFrame(3); err != errNoActorImageSource {
+		t.Fatalf("expected no source for an actor without playable scenes, got %v", err)
+	}
+}
+
+func TestActorImageService_Generate(t *testing.T) {
+	svc, actorRepo, sceneRepo, _ := newTestActorImageService(t)
+	var extracted string
+	svc.extract = func(ctx context.Context, videoPath, outputPath string, second int) error {
+		extracted = videoPath
+		return os.WriteFile(outputPath, []byte("webp"), 0644)
+	}
+
+	actor := &data.Actor{ID: 1, Name: "Jane"}
+	actorRepo.EXPECT().GetByID(uint(1)).Return(actor, nil).AnyTimes()
+	actorRepo.EXPECT().GetActorSceneIDs(uint(1)).Return([]uint{10}, nil).AnyTimes()
+
+	other := uint(11)
+	if _, err := svc.Generate(1, &other, nil); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a scene without the actor to be rejected, got %v", err)
+	}
+
+	sceneID := uint(10)
+	sceneRepo.EXPECT().GetByID(uint(10)).Return(&data.Scene{ID: 10, StoredPath: "/v/10.mp4", Duration: 100}, nil).Times(2)
+	late := 100
+	if _, err := svc.Generate(1, &sceneID, &late); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a timestamp past the end to be rejected, got %v", err)
+	}
+
+	at := 30
+	actorRepo.EXPECT().Update(actor).Return(nil)
+	if _, err := svc.Generate(1, &sceneID, &at); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if extracted != "/v/10.mp4" || !strings.HasPrefix(actor.ImageURL, "/actor-images/") {
+		t.Fatalf("expected the image taken from scene 10, got %q from %q", actor.ImageURL, extracted)
+	}
+}
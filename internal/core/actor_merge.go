@@ -0,0 +1,111 @@
+package core
+
+import (
+	"slices"
+	"strings"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// Merge combines duplicate actors into one. The sources' scenes, likes,
+// ratings, folder rules and PornDB syncs move to the target and the sources
+// are deleted. Their names and aliases become the target's aliases, so the
+// old names still find the target when searching and when performer names are
+// resolved, and fields the target lacks (image included) are taken from the
+// first source that has them.
+func (s *ActorService) Merge(targetID uint, sourceIDs []uint) (*data.Actor, error) {
+	var ids []uint
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, apperrors.NewValidationErrorWithField("source_ids", "an actor cannot be merged into itself")
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, apperrors.NewValidationErrorWithField("source_ids", "at least one actor to merge is required")
+	}
+
+	target, err := s.GetByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := s.actorRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to find actors", err)
+	}
+	for _, id := range ids {
+		if !slices.ContainsFunc(sources, func(a data.Actor) bool { return a.ID == id }) {
+			return nil, apperrors.ErrActorNotFound(id)
+		}
+	}
+	// Fill in from sources in the order given
+	slices.SortStableFunc(sources, func(a, b data.Actor) int {
+		return slices.Index(ids, a.ID) - slices.Index(ids, b.ID)
+	})
+
+	mergeActors(target, sources)
+
+	if err := s.actorRepo.Merge(target, ids); err != nil {
+		return nil, apperrors.NewInternalError("failed to merge actors", err)
+	}
+	s.reindexActorScenes(target.ID)
+
+	s.logger.Info("Actors merged",
+		zap.Uint("id", target.ID),
+		zap.String("name", target.Name),
+		zap.Uints("merged_ids", ids),
+	)
+	return target, nil
+}
+
+// mergeActors adds the sources' names and aliases to the target's aliases and
+// fills the target's empty fields from them.
+func mergeActors(target *data.Actor, sources []data.Actor) {
+	aliases := pq.StringArray{}
+	addAlias := func(alias string) {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || strings.EqualFold(alias, target.Name) {
+			return
+		}
+		for _, existing := range aliases {
+			if strings.EqualFold(existing, alias) {
+				return
+			}
+		}
+		aliases = append(aliases, alias)
+	}
+	for _, alias := range target.Aliases {
+		addAlias(alias)
+	}
+
+	for i := range sources {
+		source := &sources[i]
+		addAlias(source.Name)
+		for _, alias := range source.Aliases {
+			addAlias(alias)
+		}
+
+		for _, field := range actorSyncFields {
+			if field.name == "aliases" {
+				continue
+			}
+			if field.get(target) == "" && field.get(source) != "" {
+				field.set(target, field.get(source))
+			}
+		}
+
+		if target.PornDBID == "" && source.PornDBID != "" {
+			target.PornDBID = source.PornDBID
+			target.PornDBSyncEnabled = source.PornDBSyncEnabled
+			target.PornDBSyncedAt = source.PornDBSyncedAt
+			target.PornDBSyncedValues = source.PornDBSyncedValues
+		}
+	}
+	target.Aliases = aliases
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"github.com/lib/pq"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestActorService_Merge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	actorRepo := mocks.NewMockActorRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewActorService(actorRepo, sceneRepo, zap.NewNop())
+
+	height := 165
+	actorRepo.EXPECT().GetByID(uint(1)).Return(&data.Actor{ID: 1, Name: "Jane Doe", Aliases: pq.StringArray{"JD"}}, nil)
+	actorRepo.EXPECT().GetByIDs([]uint{3, 2}).Return([]data.Actor{
+		{ID: 2, Name: "Jane D.", Aliases: pq.StringArray{"jd", "Janey"}, HairColor: "Blonde"},
+		{ID: 3, Name: "jane doe", ImageURL: "/actor-images/3.jpg", HeightCm: &height, HairColor: "Red"},
+	}, nil)
+	var merged *data.Actor
+	actorRepo.EXPECT().Merge(gomock.Any(), []uint{3, 2}).DoAndReturn(func(target *data.Actor, sourceIDs []uint) error {
+		merged = target
+		return nil
+	})
+	actorRepo.EXPECT().GetActorSceneIDs(uint(1)).Return([]uint{10}, nil)
+	actorRepo.EXPECT().GetSceneActors(uint(10)).Return([]data.Actor{{ID: 1, Name: "Jane Doe"}}, nil)
+	sceneRepo.EXPECT().UpdateActors(uint(10), []string{"Jane Doe"}).Return(nil)
+
+	actor, err := svc.Merge(1, []uint{3, 2, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actor != merged {
+		t.Fatalf("expected the merged actor to be saved")
+	}
+	// The same name in another case and repeated aliases are dropped
+	if got := strings.Join(actor.Aliases, ","); got != "JD,Jane D.,Janey" {
+		t.Fatalf("unexpected aliases %q", got)
+	}
+	// Empty fields are filled from the sources in the order given
+	if actor.ImageURL != "/actor-images/3.jpg" || actor.HairColor != "Red" || actor.HeightCm == nil || *actor.HeightCm != 165 {
+		t.Fatalf("unexpected actor %+v", actor)
+	}
+}
+
+func TestActorService_Merge_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	actorRepo := mocks.NewMockActorRepository(ctrl)
+	svc := NewActorService(actorRepo, nil, zap.NewNop())
+
+	if _, err := svc.Merge(1, nil); !apperrors.IsValidation(err) {
+		t.Fatalf("expected no sources to be rejected, got %v", err)
+	}
+	if _, err := svc.Merge(1, []uint{2, 1}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected merging an actor into itself to be rejected, got %v", err)
+	}
+
+	actorRepo.EXPECT().GetByID(uint(1)).Return(&data.Actor{ID: 1, Name: "Jane Doe"}, nil)
+	actorRepo.EXPECT().GetByIDs([]uint{2}).Return([]data.Actor{}, nil)
+	if _, err := svc.Merge(1, []uint{2}); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected a missing source to be reported, got %v", err)
+	}
+}
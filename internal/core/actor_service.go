@@ -17,6 +17,7 @@ type ActorService struct {
 	sceneRepo data.SceneRepository
 	logger    *zap.Logger
 	indexer   SceneIndexer
+	rbac      *RBACService
 }
 
 func NewActorService(actorRepo data.ActorRepository, sceneRepo data.SceneRepository, logger *zap.Logger) *ActorService {
@@ -27,6 +28,11 @@ func NewActorService(actorRepo data.ActorRepository, sceneRepo data.SceneReposit
 	}
 }
 
+// SetRBACService limits actor scene lists to the scenes the user's role may see.
+func (s *ActorService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
+}
+
 // SetIndexer sets the scene indexer for search index updates.
 func (s *ActorService) SetIndexer(indexer SceneIndexer) {
 	s.indexer = indexer
@@ -392,7 +398,18 @@ func (s *ActorService) SetSceneActors(sceneID uint, actorIDs []uint) ([]data.Act
 	return actors, nil
 }
 
-func (s *ActorService) GetActorScenes(actorID uint, page, limit int) ([]data.Scene, int64, error) {
+// GetStats returns aggregate statistics of an actor's scenes, with the average
+// rating taken from userID's ratings.
+func (s *ActorService) GetStats(actorID, userID uint) (*data.ActorStats, error) {
+	stats, err := s.actorRepo.GetStats(actorID, userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get actor stats", err)
+	}
+	return stats, nil
+}
+
+// GetActorScenes returns an actor's scenes that role may see, newest first.
+func (s *ActorService) GetActorScenes(actorID uint, role string, page, limit int) ([]data.Scene, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -407,7 +424,7 @@ func (s *ActorService) GetActorScenes(actorID uint, page, limit int) ([]data.Sce
 		return nil, 0, apperrors.NewInternalError("failed to find actor", err)
 	}
 
-	return s.actorRepo.GetActorScenes(actorID, page, limit)
+	return s.actorRepo.GetActorScenes(actorID, restrictionFor(s.rbac, role), page, limit)
 }
 
 func (s *ActorService) UpdateImageURL(id uint, imageURL string) (*data.Actor, error) {
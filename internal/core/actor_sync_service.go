@@ -0,0 +1,460 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const actorSyncBatchSize = 100
+
+// performerDetailsSource is where actor syncs read performers from.
+type performerDetailsSource interface {
+	IsConfigured() bool
+	RefreshPerformerDetails(id string) (*PornDBPerformerDetails, error)
+}
+
+// actorSyncField is an actor field a sync can refresh, read and written in a
+// text form so PornDB's value, the actor's and the last synced one compare
+// directly. Empty means the field has no value.
+type actorSyncField struct {
+	name   string
+	get    func(a *data.Actor) string
+	set    func(a *data.Actor, value string)
+	remote func(p *PornDBPerformerDetails) string
+}
+
+var actorSyncFields = []actorSyncField{
+	stringSyncField("image_url", func(a *data.Actor) *string { return &a.ImageURL }, func(p *PornDBPerformerDetails) string { return p.Image }),
+	{
+		name: "aliases",
+		get:  func(a *data.Actor) string { return strings.Join(a.Aliases, ", ") },
+		set: func(a *data.Actor, v string) {
+			a.Aliases = pq.StringArray{}
+			for _, alias := range strings.Split(v, ", ") {
+				if alias != "" {
+					a.Aliases = append(a.Aliases, alias)
+				}
+			}
+		},
+		remote: func(p *PornDBPerformerDetails) string { return strings.Join(p.Aliases, ", ") },
+	},
+	stringSyncField("gender", func(a *data.Actor) *string { return &a.Gender }, func(p *PornDBPerformerDetails) string { return p.Gender }),
+	dateSyncField("birthday", func(a *data.Actor) **time.Time { return &a.Birthday }, func(p *PornDBPerformerDetails) string { return p.Birthday }),
+	dateSyncField("date_of_death", func(a *data.Actor) **time.Time { return &a.DateOfDeath }, func(p *PornDBPerformerDetails) string { return p.Deathday }),
+	stringSyncField("astrology", func(a *data.Actor) *string { return &a.Astrology }, func(p *PornDBPerformerDetails) string { return p.Astrology }),
+	stringSyncField("birthplace", func(a *data.Actor) *string { return &a.Birthplace }, func(p *PornDBPerformerDetails) string { return p.Birthplace }),
+	stringSyncField("ethnicity", func(a *data.Actor) *string { return &a.Ethnicity }, func(p *PornDBPerformerDetails) string { return p.Ethnicity }),
+	stringSyncField("nationality", func(a *data.Actor) *string { return &a.Nationality }, func(p *PornDBPerformerDetails) string { return p.Nationality }),
+	intSyncField("career_start_year", func(a *data.Actor) **int { return &a.CareerStartYear }, func(p *PornDBPerformerDetails) *int { return p.CareerStartYear }),
+	intSyncField("career_end_year", func(a *data.Actor) **int { return &a.CareerEndYear }, func(p *PornDBPerformerDetails) *int { return p.CareerEndYear }),
+	intSyncField("height_cm", func(a *data.Actor) **int { return &a.HeightCm }, func(p *PornDBPerformerDetails) *int { return p.Height }),
+	intSyncField("weight_kg", func(a *data.Actor) **int { return &a.WeightKg }, func(p *PornDBPerformerDetails) *int { return p.Weight }),
+	stringSyncField("measurements", func(a *data.Actor) *string { return &a.Measurements }, func(p *PornDBPerformerDetails) string { return p.Measurements }),
+	stringSyncField("cupsize", func(a *data.Actor) *string { return &a.Cupsize }, func(p *PornDBPerformerDetails) string { return p.Cupsize }),
+	stringSyncField("hair_color", func(a *data.Actor) *string { return &a.HairColor }, func(p *PornDBPerformerDetails) string { return p.HairColour }),
+	stringSyncField("eye_color", func(a *data.Actor) *string { return &a.EyeColor }, func(p *PornDBPerformerDetails) string { return p.EyeColour }),
+	stringSyncField("tattoos", func(a *data.Actor) *string { return &a.Tattoos }, func(p *PornDBPerformerDetails) string { return p.Tattoos }),
+	stringSyncField("piercings", func(a *data.Actor) *string { return &a.Piercings }, func(p *PornDBPerformerDetails) string { return p.Piercings }),
+}
+
+func stringSyncField(name string, field func(a *data.Actor) *string, remote func(p *PornDBPerformerDetails) string) actorSyncField {
+	return actorSyncField{
+		name:   name,
+		get:    func(a *data.Actor) string { return *field(a) },
+		set:    func(a *data.Actor, v string) { *field(a) = v },
+		remote: func(p *PornDBPerformerDetails) string { return strings.TrimSpace(remote(p)) },
+	}
+}
+
+func dateSyncField(name string, field func(a *data.Actor) **time.Time, remote func(p *PornDBPerformerDetails) string) actorSyncField {
+	return actorSyncField{
+		name: name,
+		get: func(a *data.Actor) string {
+			if t := *field(a); t != nil {
+				return t.Format("2006-01-02")
+			}
+			return ""
+		},
+		set: func(a *data.Actor, v string) {
+			if t, err := time.Parse("2006-01-02", v); err == nil {
+				*field(a) = &t
+			}
+		},
+		remote: func(p *PornDBPerformerDetails) string {
+			if t, err := time.Parse("2006-01-02", strings.TrimSpace(remote(p))); err == nil {
+				return t.Format("2006-01-02")
+			}
+			return ""
+		},
+	}
+}
+
+func intSyncField(name string, field func(a *data.Actor) **int, remote func(p *PornDBPerformerDetails) *int) actorSyncField {
+	return actorSyncField{
+		name: name,
+		get: func(a *data.Actor) string {
+			if n := *field(a); n != nil {
+				return strconv.Itoa(*n)
+			}
+			return ""
+		},
+		set: func(a *data.Actor, v string) {
+			if n, err := strconv.Atoi(v); err == nil {
+				*field(a) = &n
+			}
+		},
+		remote: func(p *PornDBPerformerDetails) string {
+			if n := remote(p); n != nil && *n > 0 {
+				return strconv.Itoa(*n)
+			}
+			return ""
+		},
+	}
+}
+
+// ActorSyncSummary counts what a sync run did.
+type ActorSyncSummary struct {
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+}
+
+// ActorSyncStatus is whether a sync run is going and how the last one went.
+type ActorSyncStatus struct {
+	Configured bool              `json:"configured"`
+	Running    bool              `json:"running"`
+	Interval   string            `json:"interval"` // empty when scheduled syncs are off
+	LastRunAt  *time.Time        `json:"last_run_at"`
+	LastRun    *ActorSyncSummary `json:"last_run"`
+}
+
+// ActorSyncService refreshes actors linked to a PornDB performer (porndb_id)
+// from PornDB, on an interval and on demand. A field is only overwritten when
+// it is empty or still holds what the last sync wrote; a value edited locally
+// since is kept, and the difference is recorded with the sync. Actors with
+// porndb_sync_enabled off are skipped by scheduled runs.
+type ActorSyncService struct {
+	actorRepo data.ActorRepository
+	syncRepo  data.ActorSyncRepository
+	porndb    performerDetailsSource
+	interval  time.Duration
+	logger    *zap.Logger
+	now       func() time.Time
+
+	mu        sync.Mutex
+	running   bool
+	lastRunAt *time.Time
+	lastRun   *ActorSyncSummary
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewActorSyncService(
+	actorRepo data.ActorRepository,
+	syncRepo data.ActorSyncRepository,
+	porndb *PornDBService,
+	interval time.Duration,
+	logger *zap.Logger,
+) *ActorSyncService {
+	s := &ActorSyncService{
+		actorRepo: actorRepo,
+		syncRepo:  syncRepo,
+		interval:  interval,
+		logger:    logger.With(zap.String("component", "actor_sync")),
+		now:       time.Now,
+	}
+	// Keep a nil service from becoming a non-nil interface
+	if porndb != nil {
+		s.porndb = porndb
+	}
+	return s
+}
+
+// Start runs a sync of the actors due for one every interval. Nothing is
+// scheduled without an interval or a PornDB API key.
+func (s *ActorSyncService) Start() {
+	if s.interval <= 0 || s.porndb == nil || !s.porndb.IsConfigured() {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.SyncDue(ctx); err != nil && !apperrors.IsValidation(err) {
+					s.logger.Error("Actor sync failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	s.logger.Info("Actor sync started", zap.Duration("interval", s.interval))
+}
+
+// Stop halts scheduled syncs, waiting for a running one to stop.
+func (s *ActorSyncService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// Status reports whether a run is going and how the last one went.
+func (s *ActorSyncService) Status() ActorSyncStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := ActorSyncStatus{
+		Configured: s.porndb != nil && s.porndb.IsConfigured(),
+		Running:    s.running,
+		LastRunAt:  s.lastRunAt,
+		LastRun:    s.lastRun,
+	}
+	if s.interval > 0 {
+		status.Interval = s.interval.String()
+	}
+	return status
+}
+
+// SyncDue syncs every enabled, linked actor not synced within the interval
+// (every one when there is none), one at a time in ID order. Only one run
+// goes at a time.
+func (s *ActorSyncService) SyncDue(ctx context.Context) (*ActorSyncSummary, error) {
+	if err := s.begin(); err != nil {
+		return nil, err
+	}
+	return s.run(ctx)
+}
+
+// StartSyncDue runs SyncDue in the background.
+func (s *ActorSyncService) StartSyncDue() error {
+	if err := s.begin(); err != nil {
+		return err
+	}
+	go func() {
+		if _, err := s.run(context.Background()); err != nil {
+			s.logger.Error("Actor sync failed", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// begin claims the run, failing when one is going.
+func (s *ActorSyncService) begin() error {
+	if s.porndb == nil || !s.porndb.IsConfigured() {
+		return apperrors.NewValidationError("PornDB integration is not configured")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return apperrors.NewConflictError("actor_sync", "an actor sync is already running")
+	}
+	s.running = true
+	return nil
+}
+
+func (s *ActorSyncService) run(ctx context.Context) (*ActorSyncSummary, error) {
+	summary := &ActorSyncSummary{}
+	defer func() {
+		now := s.now()
+		s.mu.Lock()
+		s.running = false
+		s.lastRunAt = &now
+		s.lastRun = summary
+		s.mu.Unlock()
+	}()
+
+	// Actors synced since the run started are not due again
+	cutoff := s.now()
+	if s.interval > 0 {
+		cutoff = cutoff.Add(-s.interval)
+	}
+	var afterID uint
+	for {
+		actors, err := s.syncRepo.ListDue(cutoff, afterID, actorSyncBatchSize)
+		if err != nil {
+			return summary, apperrors.NewInternalError("failed to list actors to sync", err)
+		}
+		if len(actors) == 0 {
+			break
+		}
+		for i := range actors {
+			if ctx.Err() != nil {
+				return summary, nil
+			}
+			record, err := s.syncActor(&actors[i])
+			switch {
+			case err != nil:
+				s.logger.Warn("Failed to sync actor", zap.Uint("actor_id", actors[i].ID), zap.Error(err))
+				summary.Failed++
+			case record.Status == data.ActorSyncUpdated:
+				summary.Updated++
+			case record.Status == data.ActorSyncFailed:
+				summary.Failed++
+			default:
+				summary.Unchanged++
+			}
+		}
+		afterID = actors[len(actors)-1].ID
+	}
+
+	s.logger.Info("Actor sync finished",
+		zap.Int("updated", summary.Updated),
+		zap.Int("unchanged", summary.Unchanged),
+		zap.Int("failed", summary.Failed),
+	)
+	return summary, nil
+}
+
+// SyncActor syncs one actor now, whether or not its sync is enabled.
+func (s *ActorSyncService) SyncActor(actorID uint) (*data.ActorPornDBSync, error) {
+	if s.porndb == nil || !s.porndb.IsConfigured() {
+		return nil, apperrors.NewValidationError("PornDB integration is not configured")
+	}
+	actor, err := s.getActor(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.PornDBID == "" {
+		return nil, apperrors.NewValidationErrorWithField("porndb_id", "the actor is not linked to a PornDB performer")
+	}
+	record, err := s.syncActor(actor)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to sync actor", err)
+	}
+	return record, nil
+}
+
+// syncActor refreshes an actor from PornDB and records the sync. A PornDB
+// failure is recorded as a failed sync rather than returned.
+func (s *ActorSyncService) syncActor(actor *data.Actor) (*data.ActorPornDBSync, error) {
+	record := &data.ActorPornDBSync{ActorID: actor.ID, PornDBID: actor.PornDBID, Changes: data.ActorSyncChanges{}}
+
+	performer, err := s.porndb.RefreshPerformerDetails(actor.PornDBID)
+	if err != nil {
+		record.Status = data.ActorSyncFailed
+		record.Error = err.Error()
+		return record, s.syncRepo.Create(record)
+	}
+
+	record.Changes = applyPerformerDetails(actor, performer)
+	record.Status = data.ActorSyncUnchanged
+	for _, change := range record.Changes {
+		if change.Applied {
+			record.Status = data.ActorSyncUpdated
+			break
+		}
+	}
+
+	now := s.now()
+	actor.PornDBSyncedAt = &now
+	if err := s.actorRepo.Update(actor); err != nil {
+		return nil, err
+	}
+	return record, s.syncRepo.Create(record)
+}
+
+// applyPerformerDetails writes a PornDB performer's values to an actor's
+// unedited fields and returns every field whose value differs. A field is
+// unedited when it is empty or holds the value the last sync wrote. The
+// actor's synced values are updated to what it now shares with PornDB.
+func applyPerformerDetails(actor *data.Actor, performer *PornDBPerformerDetails) data.ActorSyncChanges {
+	synced := make(data.ActorSyncedValues, len(actorSyncFields))
+	for field, value := range actor.PornDBSyncedValues {
+		synced[field] = value
+	}
+
+	changes := data.ActorSyncChanges{}
+	for _, field := range actorSyncFields {
+		remote := field.remote(performer)
+		if remote == "" {
+			continue
+		}
+		local := field.get(actor)
+		if local == remote {
+			synced[field.name] = remote
+			continue
+		}
+
+		last, wasSynced := synced[field.name]
+		edited := local != "" && (!wasSynced || local != last)
+		if !edited {
+			field.set(actor, remote)
+			synced[field.name] = remote
+		}
+		changes = append(changes, data.ActorSyncChange{Field: field.name, Old: local, New: remote, Applied: !edited})
+	}
+	actor.PornDBSyncedValues = synced
+	return changes
+}
+
+// UpdateSettings links an actor to a PornDB performer and turns its scheduled
+// sync on or off. Nil leaves a setting alone. Linking another performer
+// forgets what the last one synced.
+func (s *ActorSyncService) UpdateSettings(actorID uint, porndbID *string, enabled *bool) (*data.Actor, error) {
+	actor, err := s.getActor(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if porndbID != nil {
+		id := strings.TrimSpace(*porndbID)
+		if len(id) > 100 {
+			return nil, apperrors.NewValidationErrorWithField("porndb_id", "porndb_id must be 100 characters or less")
+		}
+		if id != actor.PornDBID {
+			actor.PornDBID = id
+			actor.PornDBSyncedAt = nil
+			actor.PornDBSyncedValues = data.ActorSyncedValues{}
+		}
+	}
+	if enabled != nil {
+		actor.PornDBSyncEnabled = *enabled
+	}
+	if err := s.actorRepo.Update(actor); err != nil {
+		return nil, apperrors.NewInternalError("failed to update actor", err)
+	}
+	return actor, nil
+}
+
+// ListSyncs returns an actor's syncs, newest first.
+func (s *ActorSyncService) ListSyncs(actorID uint, page, limit int) ([]data.ActorPornDBSync, int64, error) {
+	if _, err := s.getActor(actorID); err != nil {
+		return nil, 0, err
+	}
+	syncs, total, err := s.syncRepo.ListByActor(actorID, page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list actor syncs", err)
+	}
+	return syncs, total, nil
+}
+
+func (s *ActorSyncService) getActor(actorID uint) (*data.Actor, error) {
+	actor, err := s.actorRepo.GetByID(actorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrActorNotFound(actorID)
+		}
+		return nil, apperrors.NewInternalError("failed to find actor", err)
+	}
+	return actor, nil
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type fakePerformerSource struct {
+	performer *PornDBPerformerDetails
+	err       error
+	fetched   []string
+}
+
+func (f *fakePerformerSource) IsConfigured() bool { return true }
+
+func (f *fakePerformerSource) RefreshPerformerDetails(id string) (*PornDBPerformerDetails, error) {
+	f.fetched = append(f.fetched, id)
+	return f.performer, f.err
+}
+
+func newTestActorSyncService(t *testing.T, source *fakePerformerSource) (*ActorSyncService, *mocks.MockActorRepository, *mocks.MockActorSyncRepository) {
+	ctrl := gomock.NewController(t)
+	actorRepo := mocks.NewMockActorRepository(ctrl)
+	syncRepo := mocks.NewMockActorSyncRepository(ctrl)
+	svc := NewActorSyncService(actorRepo, syncRepo, nil, time.Hour, zap.NewNop())
+	svc.porndb = source
+	return svc, actorRepo, syncRepo
+}
+
+func TestApplyPerformerDetails_KeepsLocalEdits(t *testing.T) {
+	height := 170
+	actor := &data.Actor{
+		ImageURL:  "https://cdn.example.com/old.jpg", // written by the last sync
+		HairColor: "Red",                             // edited since the last sync
+		Ethnicity: "Latin",                           // set locally, never synced
+		PornDBSyncedValues: data.ActorSyncedValues{
+			"image_url":  "https://cdn.example.com/old.jpg",
+			"hair_color": "Blonde",
+		},
+	}
+	changes := applyPerformerDetails(actor, &PornDBPerformerDetails{
+		Image:      "https://cdn.example.com/new.jpg",
+		HairColour: "Brunette",
+		Ethnicity:  "Caucasian",
+		Birthday:   "1990-04-01",
+		Height:     &height,
+	})
+
+	applied := map[string]bool{}
+	for _, change := range changes {
+		applied[change.Field] = change.Applied
+	}
+	want := map[string]bool{"image_url": true, "hair_color": false, "ethnicity": false, "birthday": true, "height_cm": true}
+	if len(applied) != len(want) {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	for field, ok := range want {
+		if got, found := applied[field]; !found || got != ok {
+			t.Fatalf("expected %s applied=%v, got %+v", field, ok, changes)
+		}
+	}
+
+	if actor.ImageURL != "https://cdn.example.com/new.jpg" || actor.HairColor != "Red" || actor.Ethnicity != "Latin" {
+		t.Fatalf("unexpected actor %+v", actor)
+	}
+	if actor.Birthday == nil || actor.Birthday.Format("2006-01-02") != "1990-04-01" || actor.HeightCm == nil || *actor.HeightCm != 170 {
+		t.Fatalf("expected empty fields to be filled, got birthday=%v height=%v", actor.Birthday, actor.HeightCm)
+	}
+	// Kept edits keep their last synced value, so they stay edited
+	if actor.PornDBSyncedValues["hair_color"] != "Blonde" || actor.PornDBSyncedValues["image_url"] != "https://cdn.example.com/new.jpg" {
+		t.Fatalf("unexpected synced values %v", actor.PornDBSyncedValues)
+	}
+	if _, ok := actor.PornDBSyncedValues["ethnicity"]; ok {
+		t.Fatalf("expected the local ethnicity not to be recorded as synced")
+	}
+}
+
+func TestActorSyncService_SyncDue(t *testing.T) {
+	source := &fakePerformerSource{performer: &PornDBPerformerDetails{Gender: "Female"}}
+	svc, actorRepo, syncRepo := newTestActorSyncService(t, source)
+
+	syncRepo.EXPECT().ListDue(gomock.Any(), uint(0), actorSyncBatchSize).Return([]data.Actor{
+		{ID: 1, PornDBID: "p-1"},
+		{ID: 2, PornDBID: "p-2", Gender: "Female"},
+	}, nil)
+	syncRepo.EXPECT().ListDue(gomock.Any(), uint(2), actorSyncBatchSize).Return(nil, nil)
+	actorRepo.EXPECT().Update(gomock.Any()).Times(2)
+	var statuses []string
+	syncRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(sync *data.ActorPornDBSync) error {
+		statuses = append(statuses, sync.Status)
+		return nil
+	}).Times(2)
+
+	summary, err := svc.SyncDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Updated != 1 || summary.Unchanged != 1 || summary.Failed != 0 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if len(statuses) != 2 || statuses[0] != data.ActorSyncUpdated || statuses[1] != data.ActorSyncUnchanged {
+		t.Fatalf("unexpected sync statuses %v", statuses)
+	}
+	if status := svc.Status(); status.Running || status.LastRun != summary {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestActorSyncService_SyncActor_Failure(t *testing.T) {
+	source := &fakePerformerSource{err: errors.New("PornDB API returned status 404")}
+	svc, actorRepo, syncRepo := newTestActorSyncService(t, source)
+
+	actorRepo.EXPECT().GetByID(uint(3)).Return(&data.Actor{ID: 3, PornDBID: "p-3"}, nil)
+	syncRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+	record, err := svc.SyncActor(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Status != data.ActorSyncFailed || record.Error == "" || len(source.fetched) != 1 {
+		t.Fatalf("expected a recorded failure, got %+v", record)
+	}
+
+	actorRepo.EXPECT().GetByID(uint(4)).Return(&data.Actor{ID: 4}, nil)
+	if _, err := svc.SyncActor(4); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an unlinked actor to be rejected, got %v", err)
+	}
+}
+
+func TestActorSyncService_UpdateSettings(t *testing.T) {
+	svc, actorRepo, _ := newTestActorSyncService(t, &fakePerformerSource{})
+	syncedAt := time.Now()
+	actor := &data.Actor{ID: 5, PornDBID: "p-5", PornDBSyncEnabled: true, PornDBSyncedAt: &syncedAt, PornDBSyncedValues: data.ActorSyncedValues{"gender": "Female"}}
+	actorRepo.EXPECT().GetByID(uint(5)).Return(actor, nil).Times(2)
+	actorRepo.EXPECT().Update(actor).Times(2)
+
+	disabled := false
+	if _, err := svc.UpdateSettings(5, nil, &disabled); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actor.PornDBSyncEnabled || actor.PornDBSyncedAt == nil {
+		t.Fatalf("expected only the sync to be disabled, got %+v", actor)
+	}
+
+	relinked := " p-6 "
+	if _, err := svc.UpdateSettings(5, &relinked, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actor.PornDBID != "p-6" || actor.PornDBSyncedAt != nil || len(actor.PornDBSyncedValues) != 0 {
+		t.Fatalf("expected relinking to forget the last sync, got %+v", actor)
+	}
+}
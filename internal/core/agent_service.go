@@ -0,0 +1,511 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"goonhub/internal/agent"
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// allowedArtifactExts lists the file types agents may upload
+var allowedArtifactExts = map[string]bool{".webp": true, ".jpg": true}
+
+// RemoteAgent is a registered remote job agent as reported by the admin API
+type RemoteAgent struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Version        string    `json:"version"`
+	Address        string    `json:"address"`
+	Phases         []string  `json:"phases"`
+	Concurrency    int       `json:"concurrency"`
+	RegisteredAt   time.Time `json:"registered_at"`
+	LastSeen       time.Time `json:"last_seen"`
+	ActiveTasks    int       `json:"active_tasks"`
+	CompletedTasks int       `json:"completed_tasks"`
+	FailedTasks    int       `json:"failed_tasks"`
+}
+
+// remoteTask tracks a task from dispatch until an agent completes it
+type remoteTask struct {
+	task        agent.Task
+	artifactDir string
+	progress    func(int)
+	agentID     string // empty while queued
+	artifacts   map[string]bool
+	cancelled   bool
+	done        chan struct{}
+	result      *agent.TaskResult
+	err         error
+}
+
+// AgentService tracks remote agents and hands them tasks dispatched by worker pools.
+// Agents pull work over HTTP, so the server never needs to reach them directly.
+// State is in-memory: agents re-register after a server restart, and tasks that
+// were in flight fail through the normal job retry path.
+type AgentService struct {
+	cfg    config.AgentsConfig
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	agents map[string]*RemoteAgent
+	tasks  map[string]*remoteTask
+	queue  []*remoteTask
+	// wake is closed and replaced whenever a task is queued, waking blocked claims
+	wake chan struct{}
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAgentService creates a new AgentService
+func NewAgentService(cfg config.AgentsConfig, logger *zap.Logger) *AgentService {
+	if cfg.HeartbeatTimeout <= 0 {
+		cfg.HeartbeatTimeout = 45 * time.Second
+	}
+	return &AgentService{
+		cfg:    cfg,
+		logger: logger.With(zap.String("component", "agent_service")),
+		agents: make(map[string]*RemoteAgent),
+		tasks:  make(map[string]*remoteTask),
+		wake:   make(chan struct{}),
+	}
+}
+
+// Enabled reports whether remote agents are enabled in config
+func (s *AgentService) Enabled() bool {
+	return s.cfg.Enabled
+}
+
+// Start begins dropping agents that stop sending heartbeats
+func (s *AgentService) Start() {
+	if !s.cfg.Enabled {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.cfg.HeartbeatTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.reapStaleAgents()
+			}
+		}
+	}()
+
+	s.logger.Info("Agent service started",
+		zap.Strings("phases", s.cfg.Phases),
+		zap.Duration("heartbeat_timeout", s.cfg.HeartbeatTimeout),
+	)
+}
+
+// Stop stops the reaper and fails all tasks still in flight
+func (s *AgentService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, t := range s.tasks {
+		s.finishLocked(id, t, nil, fmt.Errorf("server shutting down"))
+	}
+}
+
+// Register adds an agent and returns its assigned ID
+func (s *AgentService) Register(req agent.RegisterRequest, address string) agent.RegisterResponse {
+	// Only keep phases the server is willing to dispatch
+	phases := make([]string, 0, len(req.Phases))
+	for _, p := range req.Phases {
+		if slices.Contains(s.cfg.Phases, p) {
+			phases = append(phases, p)
+		}
+	}
+
+	now := time.Now()
+	a := &RemoteAgent{
+		ID:           uuid.New().String(),
+		Name:         req.Name,
+		Version:      req.Version,
+		Address:      address,
+		Phases:       phases,
+		Concurrency:  req.Concurrency,
+		RegisteredAt: now,
+		LastSeen:     now,
+	}
+
+	s.mu.Lock()
+	s.agents[a.ID] = a
+	s.mu.Unlock()
+
+	s.logger.Info("Remote agent registered",
+		zap.String("agent_id", a.ID),
+		zap.String("name", a.Name),
+		zap.String("address", address),
+		zap.Strings("phases", phases),
+	)
+
+	return agent.RegisterResponse{
+		AgentID:           a.ID,
+		HeartbeatInterval: int((s.cfg.HeartbeatTimeout / 3).Seconds()),
+	}
+}
+
+// Heartbeat marks the agent as alive and returns tasks it should abandon
+func (s *AgentService) Heartbeat(agentID string) (*agent.HeartbeatResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.agents[agentID]
+	if !ok {
+		return nil, apperrors.ErrAgentNotFound(agentID)
+	}
+	a.LastSeen = time.Now()
+
+	resp := &agent.HeartbeatResponse{CancelTasks: []string{}}
+	for id, t := range s.tasks {
+		if t.agentID == agentID && t.cancelled {
+			resp.CancelTasks = append(resp.CancelTasks, id)
+		}
+	}
+	return resp, nil
+}
+
+// ListAgents returns all registered agents ordered by name
+func (s *AgentService) ListAgents() []RemoteAgent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]RemoteAgent, 0, len(s.agents))
+	for _, a := range s.agents {
+		list = append(list, *a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// CanExecute reports whether a live agent accepts the phase
+func (s *AgentService) CanExecute(phase string) bool {
+	if !s.cfg.Enabled || !slices.Contains(s.cfg.Phases, phase) {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hasAgentForPhaseLocked(phase)
+}
+
+// Execute queues a task for agents and blocks until it finishes or ctx is done
+func (s *AgentService) Execute(ctx context.Context, task agent.Task, artifactDir string, progress func(int)) (*agent.TaskResult, error) {
+	t := &remoteTask{
+		task:        task,
+		artifactDir: artifactDir,
+		progress:    progress,
+		artifacts:   make(map[string]bool),
+		done:        make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	if _, exists := s.tasks[task.ID]; exists {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("task %s is already dispatched", task.ID)
+	}
+	s.tasks[task.ID] = t
+	s.queue = append(s.queue, t)
+	close(s.wake)
+	s.wake = make(chan struct{})
+	s.mu.Unlock()
+
+	select {
+	case <-t.done:
+		return t.result, t.err
+	case <-ctx.Done():
+		s.mu.Lock()
+		s.removeFromQueueLocked(t)
+		if t.agentID == "" {
+			delete(s.tasks, task.ID)
+		} else {
+			// Keep the task around so the agent learns about the cancellation
+			t.cancelled = true
+		}
+		s.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Claim assigns the oldest queued task the agent can run. It waits up to wait
+// for one to arrive and returns nil when none did.
+func (s *AgentService) Claim(ctx context.Context, agentID string, wait time.Duration) (*agent.Task, error) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		a, ok := s.agents[agentID]
+		if !ok {
+			s.mu.Unlock()
+			return nil, apperrors.ErrAgentNotFound(agentID)
+		}
+		a.LastSeen = time.Now()
+
+		for i, t := range s.queue {
+			if !slices.Contains(a.Phases, t.task.Phase) {
+				continue
+			}
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			t.agentID = agentID
+			a.ActiveTasks++
+			task := t.task
+			s.mu.Unlock()
+
+			s.logger.Info("Remote task claimed",
+				zap.String("task_id", task.ID),
+				zap.String("phase", task.Phase),
+				zap.Uint("scene_id", task.SceneID),
+				zap.String("agent_id", agentID),
+			)
+			return &task, nil
+		}
+		wake := s.wake
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-timer.C:
+			return nil, nil
+		case <-wake:
+		}
+	}
+}
+
+// SourcePath returns the server-side path of the task's source file for download
+func (s *AgentService) SourcePath(agentID, taskID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.assignedTaskLocked(agentID, taskID)
+	if err != nil {
+		return "", err
+	}
+	return t.task.SourcePath, nil
+}
+
+// ReportProgress forwards task progress and reports whether the task was cancelled
+func (s *AgentService) ReportProgress(agentID, taskID string, progress int) (bool, error) {
+	s.mu.Lock()
+	t, err := s.assignedTaskLocked(agentID, taskID)
+	if err != nil {
+		s.mu.Unlock()
+		return false, err
+	}
+	if a, ok := s.agents[agentID]; ok {
+		a.LastSeen = time.Now()
+	}
+	cancelled := t.cancelled
+	callback := t.progress
+	s.mu.Unlock()
+
+	if !cancelled && callback != nil {
+		callback(min(max(progress, 0), 100))
+	}
+	return cancelled, nil
+}
+
+// StoreArtifact writes an uploaded artifact into the task's artifact directory
+func (s *AgentService) StoreArtifact(agentID, taskID, name string, body io.Reader) error {
+	s.mu.Lock()
+	t, err := s.assignedTaskLocked(agentID, taskID)
+	if err == nil && t.cancelled {
+		err = apperrors.ErrRemoteTaskCancelled
+	}
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	dir := t.artifactDir
+	sceneID := t.task.SceneID
+	s.mu.Unlock()
+
+	if !validArtifactName(name, sceneID) {
+		return apperrors.ErrInvalidArtifactName
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	// Write to a temp file first so a broken upload never replaces a good artifact
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create artifact file: %w", err)
+	}
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write artifact: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write artifact: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to store artifact: %w", err)
+	}
+
+	s.mu.Lock()
+	t.artifacts[name] = true
+	s.mu.Unlock()
+	return nil
+}
+
+// Complete finishes a task with the agent's result. Every artifact named in the
+// result must have been uploaded first.
+func (s *AgentService) Complete(agentID, taskID string, result agent.TaskResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.assignedTaskLocked(agentID, taskID)
+	if err != nil {
+		return err
+	}
+	if t.cancelled {
+		s.finishLocked(taskID, t, nil, fmt.Errorf("task cancelled"))
+		return apperrors.ErrRemoteTaskCancelled
+	}
+
+	if result.Error != "" {
+		s.finishLocked(taskID, t, nil, fmt.Errorf("remote agent failed: %s", result.Error))
+		return nil
+	}
+
+	for _, name := range result.SpriteSheets {
+		if !t.artifacts[name] {
+			s.finishLocked(taskID, t, nil, fmt.Errorf("remote agent did not upload %s", name))
+			return apperrors.NewValidationError(fmt.Sprintf("artifact %s was not uploaded", name))
+		}
+	}
+
+	s.finishLocked(taskID, t, &result, nil)
+	return nil
+}
+
+// assignedTaskLocked returns the task if it is assigned to the agent. Caller must hold s.mu.
+func (s *AgentService) assignedTaskLocked(agentID, taskID string) (*remoteTask, error) {
+	if _, ok := s.agents[agentID]; !ok {
+		return nil, apperrors.ErrAgentNotFound(agentID)
+	}
+	t, ok := s.tasks[taskID]
+	if !ok || t.agentID != agentID {
+		return nil, apperrors.ErrRemoteTaskNotFound(taskID)
+	}
+	return t, nil
+}
+
+// finishLocked resolves a task and updates the owning agent's counters. Caller must hold s.mu.
+func (s *AgentService) finishLocked(taskID string, t *remoteTask, result *agent.TaskResult, err error) {
+	delete(s.tasks, taskID)
+	s.removeFromQueueLocked(t)
+
+	if a, ok := s.agents[t.agentID]; ok {
+		a.ActiveTasks--
+		if err != nil {
+			a.FailedTasks++
+		} else {
+			a.CompletedTasks++
+		}
+	}
+
+	t.result = result
+	t.err = err
+	close(t.done)
+}
+
+// removeFromQueueLocked drops a task from the unassigned queue. Caller must hold s.mu.
+func (s *AgentService) removeFromQueueLocked(t *remoteTask) {
+	for i, queued := range s.queue {
+		if queued == t {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// reapStaleAgents drops agents that missed their heartbeats and fails their tasks
+func (s *AgentService) reapStaleAgents() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-s.cfg.HeartbeatTimeout)
+	for id, a := range s.agents {
+		if a.LastSeen.After(cutoff) {
+			continue
+		}
+
+		for taskID, t := range s.tasks {
+			if t.agentID == id {
+				s.finishLocked(taskID, t, nil, fmt.Errorf("remote agent %s went offline", a.Name))
+			}
+		}
+		delete(s.agents, id)
+
+		s.logger.Warn("Remote agent timed out",
+			zap.String("agent_id", id),
+			zap.String("name", a.Name),
+			zap.Time("last_seen", a.LastSeen),
+		)
+	}
+
+	// Queued tasks nobody can run anymore fail now so the retry path can run them locally
+	for _, t := range slices.Clone(s.queue) {
+		if !s.hasAgentForPhaseLocked(t.task.Phase) {
+			s.finishLocked(t.task.ID, t, nil, fmt.Errorf("no remote agent available for %s", t.task.Phase))
+		}
+	}
+}
+
+// hasAgentForPhaseLocked reports whether any registered agent accepts the phase. Caller must hold s.mu.
+func (s *AgentService) hasAgentForPhaseLocked(phase string) bool {
+	for _, a := range s.agents {
+		if slices.Contains(a.Phases, phase) {
+			return true
+		}
+	}
+	return false
+}
+
+// validArtifactName only accepts plain file names owned by the task's scene
+func validArtifactName(name string, sceneID uint) bool {
+	if name == "" || filepath.Base(name) != name || strings.HasPrefix(name, ".") {
+		return false
+	}
+	if !strings.HasPrefix(name, fmt.Sprintf("%d_", sceneID)) {
+		return false
+	}
+	return allowedArtifactExts[strings.ToLower(filepath.Ext(name))]
+}
@@ -0,0 +1,302 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"goonhub/internal/agent"
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestAgentService() *AgentService {
+	return NewAgentService(config.AgentsConfig{
+		Enabled:          true,
+		Token:            "0123456789abcdef",
+		Phases:           []string{agent.PhaseSprites},
+		HeartbeatTimeout: 45 * time.Second,
+	}, zap.NewNop())
+}
+
+type executeResult struct {
+	result *agent.TaskResult
+	err    error
+}
+
+// startExecute runs Execute in the background and returns a channel with its outcome
+func startExecute(ctx context.Context, svc *AgentService, task agent.Task, dir string) <-chan executeResult {
+	ch := make(chan executeResult, 1)
+	go func() {
+		result, err := svc.Execute(ctx, task, dir, nil)
+		ch <- executeResult{result, err}
+	}()
+	return ch
+}
+
+func claimTask(t *testing.T, svc *AgentService, agentID string) *agent.Task {
+	t.Helper()
+	task, err := svc.Claim(context.Background(), agentID, 2*time.Second)
+	if err != nil {
+		t.Fatalf("claim failed: %v", err)
+	}
+	if task == nil {
+		t.Fatal("expected a task to be claimed")
+	}
+	return task
+}
+
+func TestAgentService_RegisterFiltersPhases(t *testing.T) {
+	svc := newTestAgentService()
+
+	if svc.CanExecute(agent.PhaseSprites) {
+		t.Fatal("expected no agent available before registration")
+	}
+
+	resp := svc.Register(agent.RegisterRequest{Name: "gpu", Phases: []string{agent.PhaseSprites, "transcode"}}, "10.0.0.2")
+	if resp.AgentID == "" {
+		t.Fatal("expected an agent ID")
+	}
+	if resp.HeartbeatInterval != 15 {
+		t.Fatalf("expected heartbeat interval 15, got %d", resp.HeartbeatInterval)
+	}
+
+	agents := svc.ListAgents()
+	if len(agents) != 1 {
+		t.Fatalf("expected 1 agent, got %d", len(agents))
+	}
+	if len(agents[0].Phases) != 1 || agents[0].Phases[0] != agent.PhaseSprites {
+		t.Fatalf("expected only the sprites phase to be kept, got %v", agents[0].Phases)
+	}
+	if !svc.CanExecute(agent.PhaseSprites) {
+		t.Fatal("expected sprites to be executable")
+	}
+	if svc.CanExecute("transcode") {
+		t.Fatal("expected transcode to be rejected")
+	}
+}
+
+func TestAgentService_CanExecuteDisabled(t *testing.T) {
+	svc := NewAgentService(config.AgentsConfig{Phases: []string{agent.PhaseSprites}}, zap.NewNop())
+	svc.Register(agent.RegisterRequest{Name: "gpu", Phases: []string{agent.PhaseSprites}}, "10.0.0.2")
+
+	if svc.CanExecute(agent.PhaseSprites) {
+		t.Fatal("expected disabled service to reject all phases")
+	}
+}
+
+func TestAgentService_ExecuteRoundTrip(t *testing.T) {
+	svc := newTestAgentService()
+	agentID := svc.Register(agent.RegisterRequest{Name: "gpu", Phases: []string{agent.PhaseSprites}}, "10.0.0.2").AgentID
+	dir := t.TempDir()
+
+	var progress []int
+	task := agent.Task{ID: "job-1", SceneID: 7, Phase: agent.PhaseSprites, SourcePath: "/videos/a.mp4"}
+	ch := make(chan executeResult, 1)
+	go func() {
+		result, err := svc.Execute(context.Background(), task, dir, func(p int) { progress = append(progress, p) })
+		ch <- executeResult{result, err}
+	}()
+
+	claimed := claimTask(t, svc, agentID)
+	if claimed.ID != "job-1" {
+		t.Fatalf("expected job-1, got %s", claimed.ID)
+	}
+
+	path, err := svc.SourcePath(agentID, "job-1")
+	if err != nil || path != "/videos/a.mp4" {
+		t.Fatalf("unexpected source path %q, err %v", path, err)
+	}
+
+	cancelled, err := svc.ReportProgress(agentID, "job-1", 150)
+	if err != nil || cancelled {
+		t.Fatalf("unexpected progress result cancelled=%v err=%v", cancelled, err)
+	}
+
+	if err := svc.StoreArtifact(agentID, "job-1", "7_sheet_001.webp", strings.NewReader("webp")); err != nil {
+		t.Fatalf("store artifact failed: %v", err)
+	}
+	if err := svc.Complete(agentID, "job-1", agent.TaskResult{SpriteSheets: []string{"7_sheet_001.webp"}}); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+
+	res := <-ch
+	if res.err != nil {
+		t.Fatalf("expected no error, got %v", res.err)
+	}
+	if len(res.result.SpriteSheets) != 1 {
+		t.Fatalf("expected 1 sprite sheet, got %v", res.result.SpriteSheets)
+	}
+	if len(progress) != 1 || progress[0] != 100 {
+		t.Fatalf("expected clamped progress [100], got %v", progress)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "7_sheet_001.webp"))
+	if err != nil || string(content) != "webp" {
+		t.Fatalf("artifact not stored correctly: %q, %v", content, err)
+	}
+
+	agents := svc.ListAgents()
+	if agents[0].ActiveTasks != 0 || agents[0].CompletedTasks != 1 {
+		t.Fatalf("unexpected counters: %+v", agents[0])
+	}
+}
+
+func TestAgentService_CompleteRequiresUploadedArtifacts(t *testing.T) {
+	svc := newTestAgentService()
+	agentID := svc.Register(agent.RegisterRequest{Name: "gpu", Phases: []string{agent.PhaseSprites}}, "10.0.0.2").AgentID
+
+	ch := startExecute(context.Background(), svc, agent.Task{ID: "job-1", SceneID: 7, Phase: agent.PhaseSprites}, t.TempDir())
+	claimTask(t, svc, agentID)
+
+	err := svc.Complete(agentID, "job-1", agent.TaskResult{SpriteSheets: []string{"7_sheet_001.webp"}})
+	if !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if res := <-ch; res.err == nil {
+		t.Fatal("expected Execute to fail")
+	}
+}
+
+func TestAgentService_RemoteError(t *testing.T) {
+	svc := newTestAgentService()
+	agentID := svc.Register(agent.RegisterRequest{Name: "gpu", Phases: []string{agent.PhaseSprites}}, "10.0.0.2").AgentID
+
+	ch := startExecute(context.Background(), svc, agent.Task{ID: "job-1", SceneID: 7, Phase: agent.PhaseSprites}, t.TempDir())
+	claimTask(t, svc, agentID)
+
+	if err := svc.Complete(agentID, "job-1", agent.TaskResult{Error: "ffmpeg exploded"}); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	res := <-ch
+	if res.err == nil || !strings.Contains(res.err.Error(), "ffmpeg exploded") {
+		t.Fatalf("expected remote error, got %v", res.err)
+	}
+	if agents := svc.ListAgents(); agents[0].FailedTasks != 1 {
+		t.Fatalf("expected 1 failed task, got %d", agents[0].FailedTasks)
+	}
+}
+
+func TestAgentService_StoreArtifactRejectsInvalidNames(t *testing.T) {
+	svc := newTestAgentService()
+	agentID := svc.Register(agent.RegisterRequest{Name: "gpu", Phases: []string{agent.PhaseSprites}}, "10.0.0.2").AgentID
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startExecute(ctx, svc, agent.Task{ID: "job-1", SceneID: 7, Phase: agent.PhaseSprites}, t.TempDir())
+	claimTask(t, svc, agentID)
+
+	names := []string{"", "../7_sheet.webp", "8_sheet.webp", "7_sheet.exe", ".7_sheet.webp", "sub/7_sheet.webp"}
+	for _, name := range names {
+		err := svc.StoreArtifact(agentID, "job-1", name, strings.NewReader("x"))
+		if !errors.Is(err, apperrors.ErrInvalidArtifactName) {
+			t.Errorf("name %q: expected ErrInvalidArtifactName, got %v", name, err)
+		}
+	}
+}
+
+func TestAgentService_TaskScopedToAgent(t *testing.T) {
+	svc := newTestAgentService()
+	ownerID := svc.Register(agent.RegisterRequest{Name: "a", Phases: []string{agent.PhaseSprites}}, "10.0.0.2").AgentID
+	otherID := svc.Register(agent.RegisterRequest{Name: "b", Phases: []string{agent.PhaseSprites}}, "10.0.0.3").AgentID
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startExecute(ctx, svc, agent.Task{ID: "job-1", SceneID: 7, Phase: agent.PhaseSprites}, t.TempDir())
+	claimTask(t, svc, ownerID)
+
+	if _, err := svc.SourcePath(otherID, "job-1"); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found for other agent, got %v", err)
+	}
+	if _, err := svc.Heartbeat("unknown"); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found for unknown agent, got %v", err)
+	}
+}
+
+func TestAgentService_ClaimTimesOut(t *testing.T) {
+	svc := newTestAgentService()
+	agentID := svc.Register(agent.RegisterRequest{Name: "gpu", Phases: []string{agent.PhaseSprites}}, "10.0.0.2").AgentID
+
+	task, err := svc.Claim(context.Background(), agentID, 10*time.Millisecond)
+	if err != nil || task != nil {
+		t.Fatalf("expected no task and no error, got %v, %v", task, err)
+	}
+}
+
+func TestAgentService_CancelAssignedTask(t *testing.T) {
+	svc := newTestAgentService()
+	agentID := svc.Register(agent.RegisterRequest{Name: "gpu", Phases: []string{agent.PhaseSprites}}, "10.0.0.2").AgentID
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := startExecute(ctx, svc, agent.Task{ID: "job-1", SceneID: 7, Phase: agent.PhaseSprites}, t.TempDir())
+	claimTask(t, svc, agentID)
+
+	cancel()
+	if res := <-ch; !errors.Is(res.err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", res.err)
+	}
+
+	hb, err := svc.Heartbeat(agentID)
+	if err != nil {
+		t.Fatalf("heartbeat failed: %v", err)
+	}
+	if len(hb.CancelTasks) != 1 || hb.CancelTasks[0] != "job-1" {
+		t.Fatalf("expected job-1 to be cancelled, got %v", hb.CancelTasks)
+	}
+
+	cancelled, err := svc.ReportProgress(agentID, "job-1", 50)
+	if err != nil || !cancelled {
+		t.Fatalf("expected cancelled progress, got %v, %v", cancelled, err)
+	}
+	if err := svc.Complete(agentID, "job-1", agent.TaskResult{}); !errors.Is(err, apperrors.ErrRemoteTaskCancelled) {
+		t.Fatalf("expected ErrRemoteTaskCancelled, got %v", err)
+	}
+	if agents := svc.ListAgents(); agents[0].ActiveTasks != 0 {
+		t.Fatalf("expected no active tasks, got %d", agents[0].ActiveTasks)
+	}
+}
+
+func TestAgentService_ReapStaleAgents(t *testing.T) {
+	svc := newTestAgentService()
+	agentID := svc.Register(agent.RegisterRequest{Name: "gpu", Phases: []string{agent.PhaseSprites}}, "10.0.0.2").AgentID
+
+	assigned := startExecute(context.Background(), svc, agent.Task{ID: "job-1", SceneID: 7, Phase: agent.PhaseSprites}, t.TempDir())
+	claimTask(t, svc, agentID)
+	queued := startExecute(context.Background(), svc, agent.Task{ID: "job-2", SceneID: 8, Phase: agent.PhaseSprites}, t.TempDir())
+
+	// Wait for the second task to be queued before reaping
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		svc.mu.Lock()
+		n := len(svc.queue)
+		svc.agents[agentID].LastSeen = time.Now().Add(-time.Hour)
+		svc.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for task to be queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	svc.reapStaleAgents()
+
+	if res := <-assigned; res.err == nil || !strings.Contains(res.err.Error(), "went offline") {
+		t.Fatalf("expected offline error for assigned task, got %v", res.err)
+	}
+	if res := <-queued; res.err == nil || !strings.Contains(res.err.Error(), "no remote agent") {
+		t.Fatalf("expected no-agent error for queued task, got %v", res.err)
+	}
+	if len(svc.ListAgents()) != 0 {
+		t.Fatal("expected stale agent to be removed")
+	}
+	if svc.CanExecute(agent.PhaseSprites) {
+		t.Fatal("expected no agent available after reaping")
+	}
+}
@@ -0,0 +1,209 @@
+package core
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	maxAPIKeysPerUser     = 20
+	maxAPIKeyNameLength   = 100
+	maxAPIKeyLifetimeDays = 3650
+	apiKeySecretBytes     = 32
+	apiKeyDisplayLength   = len(data.APIKeyPrefix) + 8
+	// Last-used times are only written when they are at least this old, so busy
+	// scripts don't cause a write per request
+	apiKeyTouchInterval = time.Minute
+)
+
+// ErrInvalidAPIKey is returned when an API key is unknown, revoked or expired.
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// CreatedAPIKey is a new API key. Key is the only time the secret is available.
+type CreatedAPIKey struct {
+	APIKey data.APIKey `json:"api_key"`
+	Key    string      `json:"key"`
+}
+
+// APIKeyService manages long-lived API keys. A key acts as its owner, limited to the
+// permissions it was created with; the owner's role is checked on every use, so
+// a key never grants more than its owner currently has.
+type APIKeyService struct {
+	repo     data.APIKeyRepository
+	userRepo data.UserRepository
+	rbac     *RBACService
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+func NewAPIKeyService(repo data.APIKeyRepository, userRepo data.UserRepository, rbac *RBACService, logger *zap.Logger) *APIKeyService {
+	return &APIKeyService{
+		repo:     repo,
+		userRepo: userRepo,
+		rbac:     rbac,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Create generates a key for the user. Every permission must be granted to the
+// user's role. expiresInDays 0 creates a key that never expires.
+func (s *APIKeyService) Create(userID uint, role, name string, permissions []string, expiresInDays int) (*CreatedAPIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		return nil, apperrors.NewValidationErrorWithField("name", fmt.Sprintf("name must be 1 to %d characters", maxAPIKeyNameLength))
+	}
+	if expiresInDays < 0 || expiresInDays > maxAPIKeyLifetimeDays {
+		return nil, apperrors.NewValidationErrorWithField("expires_in_days", fmt.Sprintf("expiry must be between 0 and %d days", maxAPIKeyLifetimeDays))
+	}
+
+	scopes := make([]string, 0, len(permissions))
+	seen := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		if !s.rbac.HasPermission(role, permission) {
+			return nil, apperrors.NewValidationErrorWithField("permissions", fmt.Sprintf("permission %q is not granted to your role", permission))
+		}
+		scopes = append(scopes, permission)
+	}
+	if len(scopes) == 0 {
+		return nil, apperrors.NewValidationErrorWithField("permissions", "at least one permission is required")
+	}
+
+	now := s.now()
+	count, err := s.repo.CountActiveByUser(userID, now)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to count API keys", err)
+	}
+	if count >= maxAPIKeysPerUser {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("you can have at most %d active API keys", maxAPIKeysPerUser))
+	}
+
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate API key", err)
+	}
+
+	key := data.APIKey{
+		UserID:      userID,
+		Name:        name,
+		KeyPrefix:   secret[:apiKeyDisplayLength],
+		KeyHash:     hashAPIKey(secret),
+		Permissions: scopes,
+		CreatedAt:   now,
+	}
+	if expiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, expiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := s.repo.Create(&key); err != nil {
+		return nil, apperrors.NewInternalError("failed to create API key", err)
+	}
+
+	s.logger.Info("API key created",
+		zap.Uint("user_id", userID),
+		zap.Uint("api_key_id", key.ID),
+		zap.Strings("permissions", scopes),
+	)
+	return &CreatedAPIKey{APIKey: key, Key: secret}, nil
+}
+
+// List returns the user's API keys, including revoked and expired ones.
+func (s *APIKeyService) List(userID uint) ([]data.APIKey, error) {
+	keys, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list API keys", err)
+	}
+	return keys, nil
+}
+
+// ListAll returns every user's API keys, for admins.
+func (s *APIKeyService) ListAll(page, limit int) ([]data.APIKey, int64, error) {
+	keys, total, err := s.repo.List(page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list API keys", err)
+	}
+	return keys, total, nil
+}
+
+// Revoke revokes one of the user's keys. userID 0 revokes a key of any user.
+func (s *APIKeyService) Revoke(userID, id uint) error {
+	if err := s.repo.Revoke(id, userID, s.now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewNotFoundError("api_key", id)
+		}
+		return apperrors.NewInternalError("failed to revoke API key", err)
+	}
+	s.logger.Info("API key revoked", zap.Uint("api_key_id", id), zap.Uint("revoked_by_user_id", userID))
+	return nil
+}
+
+// Authenticate resolves an API key to a payload for its owner, with the key's
+// permissions as scopes. It records when the key was last used.
+func (s *APIKeyService) Authenticate(secret string) (*UserPayload, error) {
+	key, err := s.repo.GetByHash(hashAPIKey(secret))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to look up API key: %w", err)
+	}
+
+	now := s.now()
+	if !key.IsActive(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.userRepo.GetByID(key.UserID)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.repo.TouchLastUsed(key.ID, now); err != nil {
+			s.logger.Warn("Failed to record API key use", zap.Uint("api_key_id", key.ID), zap.Error(err))
+		}
+	}
+
+	return &UserPayload{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		IssuedAt: key.CreatedAt.Unix(),
+		APIKeyID: key.ID,
+		Scopes:   key.Permissions,
+	}, nil
+}
+
+// IsAPIKey reports whether a bearer token is an API key rather than a session token.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, data.APIKeyPrefix)
+}
+
+func newAPIKeySecret() (string, error) {
+	buf := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return data.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestAPIKeyService(t *testing.T) (*APIKeyService, *mocks.MockAPIKeyRepository, *mocks.MockUserRepository) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	rbac, _, _ := newTestRBACService(t, map[string][]string{
+		"user": {"scenes:view", "scenes:download"},
+	})
+	svc := NewAPIKeyService(repo, userRepo, rbac, zap.NewNop())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	return svc, repo, userRepo
+}
+
+func TestAPIKeyCreate_StoresOnlyTheHash(t *testing.T) {
+	svc, repo, _ := newTestAPIKeyService(t)
+
+	var stored *data.APIKey
+	repo.EXPECT().CountActiveByUser(uint(1), gomock.Any()).Return(int64(0), nil)
+	repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(key *data.APIKey) error {
+		key.ID = 7
+		stored = key
+		return nil
+	})
+
+	created, err := svc.Create(1, "user", " backup script ", []string{"scenes:view", "scenes:view"}, 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(created.Key, data.APIKeyPrefix) {
+		t.Fatalf("expected key to start with %q, got %q", data.APIKeyPrefix, created.Key)
+	}
+	if stored.KeyHash != hashAPIKey(created.Key) || strings.Contains(stored.KeyHash, created.Key) {
+		t.Fatal("expected only the key's hash to be stored")
+	}
+	if !strings.HasPrefix(created.Key, stored.KeyPrefix) || len(stored.KeyPrefix) != apiKeyDisplayLength {
+		t.Fatalf("unexpected display prefix %q", stored.KeyPrefix)
+	}
+	if stored.Name != "backup script" {
+		t.Fatalf("expected trimmed name, got %q", stored.Name)
+	}
+	if len(stored.Permissions) != 1 {
+		t.Fatalf("expected duplicate permissions to be collapsed, got %v", stored.Permissions)
+	}
+	if stored.ExpiresAt == nil || !stored.ExpiresAt.Equal(svc.now().AddDate(0, 0, 30)) {
+		t.Fatalf("expected expiry in 30 days, got %v", stored.ExpiresAt)
+	}
+}
+
+func TestAPIKeyCreate_RejectsPermissionsOutsideRole(t *testing.T) {
+	svc, _, _ := newTestAPIKeyService(t)
+
+	_, err := svc.Create(1, "user", "script", []string{"scenes:view", "scenes:delete"}, 0)
+	if !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	_, err = svc.Create(1, "user", "script", nil, 0)
+	if !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for no permissions, got %v", err)
+	}
+}
+
+func TestAPIKeyCreate_LimitsActiveKeys(t *testing.T) {
+	svc, repo, _ := newTestAPIKeyService(t)
+	repo.EXPECT().CountActiveByUser(uint(1), gomock.Any()).Return(int64(maxAPIKeysPerUser), nil)
+
+	_, err := svc.Create(1, "user", "script", []string{"scenes:view"}, 0)
+	if !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	svc, repo, userRepo := newTestAPIKeyService(t)
+	now := svc.now()
+	recently := now.Add(-10 * time.Second)
+	key := &data.APIKey{ID: 7, UserID: 1, Permissions: []string{"scenes:view"}, LastUsedAt: &recently}
+
+	repo.EXPECT().GetByHash(hashAPIKey("ghk_secret")).Return(key, nil)
+	userRepo.EXPECT().GetByID(uint(1)).Return(&data.User{ID: 1, Username: "alice", Role: "user"}, nil)
+
+	payload, err := svc.Authenticate("ghk_secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload.APIKeyID != 7 || payload.Role != "user" || payload.Username != "alice" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	if !payload.AllowsPermission("scenes:view") || payload.AllowsPermission("scenes:download") {
+		t.Fatalf("expected key to be limited to its scopes, got %v", payload.Scopes)
+	}
+}
+
+func TestAPIKeyAuthenticate_RecordsUseAtMostOncePerInterval(t *testing.T) {
+	svc, repo, userRepo := newTestAPIKeyService(t)
+	now := svc.now()
+	stale := now.Add(-2 * apiKeyTouchInterval)
+
+	repo.EXPECT().GetByHash(gomock.Any()).Return(&data.APIKey{ID: 7, UserID: 1, LastUsedAt: &stale}, nil)
+	userRepo.EXPECT().GetByID(uint(1)).Return(&data.User{ID: 1, Role: "user"}, nil)
+	repo.EXPECT().TouchLastUsed(uint(7), now).Return(nil)
+
+	if _, err := svc.Authenticate("ghk_secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAPIKeyAuthenticate_RejectsUnusableKeys(t *testing.T) {
+	svc, repo, _ := newTestAPIKeyService(t)
+	now := svc.now()
+	past := now.Add(-time.Hour)
+
+	repo.EXPECT().GetByHash(hashAPIKey("ghk_revoked")).Return(&data.APIKey{ID: 1, RevokedAt: &past}, nil)
+	repo.EXPECT().GetByHash(hashAPIKey("ghk_expired")).Return(&data.APIKey{ID: 2, ExpiresAt: &past}, nil)
+	repo.EXPECT().GetByHash(hashAPIKey("ghk_unknown")).Return(nil, gorm.ErrRecordNotFound)
+
+	for _, secret := range []string{"ghk_revoked", "ghk_expired", "ghk_unknown"} {
+		if _, err := svc.Authenticate(secret); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Fatalf("%s: expected ErrInvalidAPIKey, got %v", secret, err)
+		}
+	}
+}
+
+func TestAPIKeyRevoke_NotFound(t *testing.T) {
+	svc, repo, _ := newTestAPIKeyService(t)
+	repo.EXPECT().Revoke(uint(9), uint(1), gomock.Any()).Return(gorm.ErrRecordNotFound)
+
+	if err := svc.Revoke(1, 9); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestValidateToken_AcceptsAPIKeys(t *testing.T) {
+	authSvc, _, _ := newTestAuthService(t)
+	keySvc, repo, userRepo := newTestAPIKeyService(t)
+	authSvc.SetAPIKeyAuthenticator(keySvc)
+
+	repo.EXPECT().GetByHash(hashAPIKey("ghk_secret")).Return(&data.APIKey{ID: 7, UserID: 1, LastUsedAt: timePtr(keySvc.now())}, nil)
+	userRepo.EXPECT().GetByID(uint(1)).Return(&data.User{ID: 1, Role: "user"}, nil)
+
+	payload, err := authSvc.ValidateToken("ghk_secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload.APIKeyID != 7 {
+		t.Fatalf("expected API key payload, got %+v", payload)
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,284 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// apiUsageFlushInterval is how often buffered usage counters are written to the database
+	apiUsageFlushInterval = time.Minute
+	// apiUsagePruneInterval is how often usage older than the retention window is deleted
+	apiUsagePruneInterval = 24 * time.Hour
+	// apiUsageMaxDays caps the report window
+	apiUsageMaxDays = 365
+	// apiUsageTopRoutes is how many routes a per-user report lists
+	apiUsageTopRoutes = 20
+)
+
+type apiUsageKey struct {
+	userID uint
+	day    string // YYYY-MM-DD in UTC
+	method string
+	route  string
+}
+
+type apiUsageCounts struct {
+	requests int64
+	errors   int64
+	bytes    int64
+}
+
+// APIUsageReport summarises one user's API usage over a window of days
+type APIUsageReport struct {
+	UserID    uint                 `json:"user_id"`
+	Days      int                  `json:"days"`
+	Totals    APIUsageSummary      `json:"totals"`
+	Daily     []data.APIUsageDay   `json:"daily"`
+	TopRoutes []data.APIUsageRoute `json:"top_routes"`
+}
+
+// APIUsageSummary is the total usage over a report window
+type APIUsageSummary struct {
+	RequestCount int64 `json:"request_count"`
+	ErrorCount   int64 `json:"error_count"`
+	BytesSent    int64 `json:"bytes_sent"`
+}
+
+// APIUsageOverview lists the busiest users over a window of days
+type APIUsageOverview struct {
+	Days  int                   `json:"days"`
+	Users []data.APIUsageTotals `json:"users"`
+}
+
+// APIUsageService counts API calls and response bytes per authenticated user.
+// Requests are aggregated in memory and flushed to daily rows once a minute so
+// the request path never waits on the database.
+type APIUsageService struct {
+	repo          data.APIUsageRepository
+	retentionDays int
+	logger        *zap.Logger
+
+	mu      sync.Mutex
+	pending map[apiUsageKey]*apiUsageCounts
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewAPIUsageService(repo data.APIUsageRepository, retentionDays int, logger *zap.Logger) *APIUsageService {
+	return &APIUsageService{
+		repo:          repo,
+		retentionDays: retentionDays,
+		logger:        logger,
+		pending:       make(map[apiUsageKey]*apiUsageCounts),
+	}
+}
+
+// Start launches the background flush and retention loop
+func (s *APIUsageService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		flushTicker := time.NewTicker(apiUsageFlushInterval)
+		defer flushTicker.Stop()
+		pruneTicker := time.NewTicker(apiUsagePruneInterval)
+		defer pruneTicker.Stop()
+
+		s.prune()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-flushTicker.C:
+				s.Flush()
+			case <-pruneTicker.C:
+				s.prune()
+			}
+		}
+	}()
+
+	s.logger.Info("API usage service started", zap.Int("retention_days", s.retentionDays))
+}
+
+// Stop halts the background loop and writes out any buffered counters
+func (s *APIUsageService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+	s.Flush()
+}
+
+// Record counts one request by a user. Requests that matched no route are ignored
+// so that probing unknown paths cannot grow the table without bound.
+func (s *APIUsageService) Record(userID uint, method, route string, status, bytes int) {
+	if s == nil || userID == 0 || route == "" {
+		return
+	}
+
+	key := apiUsageKey{
+		userID: userID,
+		day:    time.Now().UTC().Format(time.DateOnly),
+		method: method,
+		route:  route,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts, ok := s.pending[key]
+	if !ok {
+		counts = &apiUsageCounts{}
+		s.pending[key] = counts
+	}
+	counts.requests++
+	if status >= 400 {
+		counts.errors++
+	}
+	if bytes > 0 {
+		counts.bytes += int64(bytes)
+	}
+}
+
+// Flush writes buffered counters to the database. On failure the counters are
+// merged back so they are retried on the next flush.
+func (s *APIUsageService) Flush() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[apiUsageKey]*apiUsageCounts)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	rows := make([]data.UserAPIUsage, 0, len(pending))
+	for key, counts := range pending {
+		day, err := time.Parse(time.DateOnly, key.day)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, data.UserAPIUsage{
+			UserID:       key.userID,
+			UsageDate:    day,
+			Method:       key.method,
+			Route:        key.route,
+			RequestCount: counts.requests,
+			ErrorCount:   counts.errors,
+			BytesSent:    counts.bytes,
+		})
+	}
+
+	if err := s.repo.IncrementBatch(rows); err != nil {
+		s.logger.Warn("Failed to flush API usage stats", zap.Int("rows", len(rows)), zap.Error(err))
+		s.restore(pending)
+	}
+}
+
+// restore merges counters from a failed flush back into the pending buffer
+func (s *APIUsageService) restore(pending map[apiUsageKey]*apiUsageCounts) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, counts := range pending {
+		existing, ok := s.pending[key]
+		if !ok {
+			s.pending[key] = counts
+			continue
+		}
+		existing.requests += counts.requests
+		existing.errors += counts.errors
+		existing.bytes += counts.bytes
+	}
+}
+
+func (s *APIUsageService) prune() {
+	if s.retentionDays <= 0 {
+		return
+	}
+	cutoff := startOfUsageDay(time.Now()).AddDate(0, 0, -s.retentionDays)
+	deleted, err := s.repo.DeleteOlderThan(cutoff)
+	if err != nil {
+		s.logger.Warn("Failed to prune API usage stats", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("Pruned API usage stats", zap.Int64("rows", deleted), zap.Time("before", cutoff))
+	}
+}
+
+// GetUserUsage returns a user's usage over the last days days, including today.
+// Counters not yet flushed are not included.
+func (s *APIUsageService) GetUserUsage(userID uint, days int) (*APIUsageReport, error) {
+	days = clampUsageDays(days)
+	since := usageWindowStart(days)
+
+	daily, err := s.repo.GetDailyForUser(userID, since)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get API usage", err)
+	}
+	routes, err := s.repo.GetTopRoutesForUser(userID, since, apiUsageTopRoutes)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get API usage by route", err)
+	}
+
+	report := &APIUsageReport{
+		UserID:    userID,
+		Days:      days,
+		Daily:     daily,
+		TopRoutes: routes,
+	}
+	if report.Daily == nil {
+		report.Daily = []data.APIUsageDay{}
+	}
+	if report.TopRoutes == nil {
+		report.TopRoutes = []data.APIUsageRoute{}
+	}
+	for _, d := range daily {
+		report.Totals.RequestCount += d.RequestCount
+		report.Totals.ErrorCount += d.ErrorCount
+		report.Totals.BytesSent += d.BytesSent
+	}
+	return report, nil
+}
+
+// GetOverview returns the limit busiest users over the last days days
+func (s *APIUsageService) GetOverview(days, limit int) (*APIUsageOverview, error) {
+	days = clampUsageDays(days)
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	users, err := s.repo.GetTotalsByUser(usageWindowStart(days), limit)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get API usage overview", err)
+	}
+	if users == nil {
+		users = []data.APIUsageTotals{}
+	}
+	return &APIUsageOverview{Days: days, Users: users}, nil
+}
+
+func clampUsageDays(days int) int {
+	if days <= 0 {
+		return 30
+	}
+	return min(days, apiUsageMaxDays)
+}
+
+// usageWindowStart returns the first UTC day of a window of days ending today
+func usageWindowStart(days int) time.Time {
+	return startOfUsageDay(time.Now()).AddDate(0, 0, -(days - 1))
+}
+
+func startOfUsageDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
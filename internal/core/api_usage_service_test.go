@@ -0,0 +1,111 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestAPIUsageService(t *testing.T) (*APIUsageService, *mocks.MockAPIUsageRepository) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAPIUsageRepository(ctrl)
+	return NewAPIUsageService(repo, 90, zap.NewNop()), repo
+}
+
+func TestAPIUsageService_FlushAggregatesRequests(t *testing.T) {
+	svc, repo := newTestAPIUsageService(t)
+
+	svc.Record(1, "GET", "/api/v1/scenes", 200, 1000)
+	svc.Record(1, "GET", "/api/v1/scenes", 500, 50)
+	svc.Record(2, "GET", "/api/v1/scenes", 200, 10)
+	svc.Record(1, "GET", "", 404, 20) // unmatched route
+	svc.Record(0, "GET", "/api/v1/scenes", 200, 20)
+
+	repo.EXPECT().IncrementBatch(gomock.Any()).DoAndReturn(func(rows []data.UserAPIUsage) error {
+		if len(rows) != 2 {
+			t.Fatalf("expected 2 rows, got %d", len(rows))
+		}
+		for _, row := range rows {
+			if row.UserID == 1 && (row.RequestCount != 2 || row.ErrorCount != 1 || row.BytesSent != 1050) {
+				t.Fatalf("unexpected aggregate for user 1: %+v", row)
+			}
+		}
+		return nil
+	})
+	svc.Flush()
+
+	// Nothing pending, so no further writes
+	svc.Flush()
+}
+
+func TestAPIUsageService_FlushRetriesOnFailure(t *testing.T) {
+	svc, repo := newTestAPIUsageService(t)
+
+	svc.Record(1, "GET", "/api/v1/scenes", 200, 100)
+	repo.EXPECT().IncrementBatch(gomock.Any()).Return(errors.New("db down"))
+	svc.Flush()
+
+	svc.Record(1, "GET", "/api/v1/scenes", 200, 100)
+	repo.EXPECT().IncrementBatch(gomock.Any()).DoAndReturn(func(rows []data.UserAPIUsage) error {
+		if len(rows) != 1 || rows[0].RequestCount != 2 || rows[0].BytesSent != 200 {
+			t.Fatalf("expected failed counts to be merged into next flush, got %+v", rows)
+		}
+		return nil
+	})
+	svc.Flush()
+}
+
+func TestAPIUsageService_GetUserUsage(t *testing.T) {
+	svc, repo := newTestAPIUsageService(t)
+
+	repo.EXPECT().GetDailyForUser(uint(3), gomock.Any()).DoAndReturn(func(_ uint, since time.Time) ([]data.APIUsageDay, error) {
+		expected := startOfUsageDay(time.Now()).AddDate(0, 0, -6)
+		if !since.Equal(expected) {
+			t.Fatalf("expected window start %v, got %v", expected, since)
+		}
+		return []data.APIUsageDay{
+			{RequestCount: 10, ErrorCount: 1, BytesSent: 500},
+			{RequestCount: 5, BytesSent: 100},
+		}, nil
+	})
+	repo.EXPECT().GetTopRoutesForUser(uint(3), gomock.Any(), apiUsageTopRoutes).Return(nil, nil)
+
+	report, err := svc.GetUserUsage(3, 7)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Totals.RequestCount != 15 || report.Totals.ErrorCount != 1 || report.Totals.BytesSent != 600 {
+		t.Fatalf("unexpected totals: %+v", report.Totals)
+	}
+	if report.TopRoutes == nil {
+		t.Fatal("expected empty top routes slice, got nil")
+	}
+}
+
+func TestAPIUsageService_GetOverviewClampsParams(t *testing.T) {
+	svc, repo := newTestAPIUsageService(t)
+
+	repo.EXPECT().GetTotalsByUser(gomock.Any(), 50).Return(nil, nil)
+
+	overview, err := svc.GetOverview(10000, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if overview.Days != apiUsageMaxDays {
+		t.Fatalf("expected days clamped to %d, got %d", apiUsageMaxDays, overview.Days)
+	}
+	if overview.Users == nil {
+		t.Fatal("expected empty users slice, got nil")
+	}
+}
+
+func TestAPIUsageService_NilSafeRecord(t *testing.T) {
+	var svc *APIUsageService
+	svc.Record(1, "GET", "/api/v1/scenes", 200, 10)
+}
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// metadataUsageTTL is how long a measured metadata_dir size is reused before walking the tree again
+const metadataUsageTTL = time.Minute
+
+// quotaPausedPhases are the phases held back while the metadata quota is exceeded
+var quotaPausedPhases = map[string]bool{"sprites": true, "animated_thumbnails": true}
+
+// ArtifactStats summarizes generated artifact disk usage.
+type ArtifactStats struct {
+	StoragePaths  []data.StoragePathArtifactStats `json:"storage_paths"`
+	TotalBytes    int64                           `json:"total_bytes"` // sum of tracked per-scene artifacts
+	MetadataBytes int64                           `json:"metadata_bytes"`
+	QuotaBytes    int64                           `json:"quota_bytes"` // 0 = no quota
+	QuotaExceeded bool                            `json:"quota_exceeded"`
+	PausedPhases  []string                        `json:"paused_phases"`
+}
+
+// ArtifactService tracks the disk size of generated thumbnails, sprites, previews and
+// marker clips per scene, and enforces the optional metadata_dir quota.
+type ArtifactService struct {
+	repo       data.SceneArtifactRepository
+	sceneRepo  data.SceneRepository
+	markerRepo data.MarkerRepository
+	cfg        config.ProcessingConfig
+	quotaBytes int64
+	logger     *zap.Logger
+
+	mu              sync.Mutex
+	usageBytes      int64
+	usageMeasuredAt time.Time
+	quotaExceeded   bool
+	now             func() time.Time
+}
+
+func NewArtifactService(
+	repo data.SceneArtifactRepository,
+	sceneRepo data.SceneRepository,
+	markerRepo data.MarkerRepository,
+	cfg config.ProcessingConfig,
+	logger *zap.Logger,
+) *ArtifactService {
+	return &ArtifactService{
+		repo:       repo,
+		sceneRepo:  sceneRepo,
+		markerRepo: markerRepo,
+		cfg:        cfg,
+		quotaBytes: cfg.MetadataQuotaMB * 1024 * 1024,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// RecordScene measures the artifacts currently on disk for a scene and stores the sizes.
+func (s *ArtifactService) RecordScene(sceneID uint) error {
+	size := &data.SceneArtifactSize{
+		SceneID: sceneID,
+		ThumbnailBytes: sumFileSizes(
+			filepath.Join(s.cfg.ThumbnailDir, fmt.Sprintf("%d_thumb_sm.webp", sceneID)),
+			filepath.Join(s.cfg.ThumbnailDir, fmt.Sprintf("%d_thumb_lg.webp", sceneID)),
+		),
+		PreviewBytes: sumFileSizes(filepath.Join(s.cfg.ScenePreviewDir, fmt.Sprintf("%d_preview.mp4", sceneID))),
+		UpdatedAt:    s.now(),
+	}
+
+	sprites, _ := filepath.Glob(filepath.Join(s.cfg.SpriteDir, fmt.Sprintf("%d_sheet_*", sceneID)))
+	sprites = append(sprites,
+		filepath.Join(s.cfg.VttDir, fmt.Sprintf("%d_thumbnails.vtt", sceneID)),
+		filepath.Join(s.cfg.SpriteDir, fmt.Sprintf("%d_trickplay.bif", sceneID)),
+	)
+	size.SpriteBytes = sumFileSizes(sprites...)
+
+	markers, err := s.markerRepo.GetAllByScene(sceneID)
+	if err != nil {
+		return fmt.Errorf("failed to get scene markers: %w", err)
+	}
+	var markerFiles []string
+	for _, m := range markers {
+		if m.ThumbnailPath != "" {
+			markerFiles = append(markerFiles, filepath.Join(s.cfg.MarkerThumbnailDir, m.ThumbnailPath))
+		}
+		if m.AnimatedThumbnailPath != "" {
+			markerFiles = append(markerFiles, filepath.Join(s.cfg.MarkerThumbnailDir, m.AnimatedThumbnailPath))
+		}
+	}
+	size.MarkerBytes = sumFileSizes(markerFiles...)
+
+	if err := s.repo.Upsert(size); err != nil {
+		return fmt.Errorf("failed to save artifact sizes: %w", err)
+	}
+	return nil
+}
+
+// RecordPhaseComplete is called by the result handler after a phase writes artifacts.
+// Failures are logged; size accounting never fails a job.
+func (s *ArtifactService) RecordPhaseComplete(sceneID uint, phase string) {
+	if phase != "thumbnail" && phase != "sprites" && phase != "animated_thumbnails" {
+		return
+	}
+	if err := s.RecordScene(sceneID); err != nil {
+		s.logger.Warn("Failed to record scene artifact sizes",
+			zap.Uint("scene_id", sceneID),
+			zap.String("phase", phase),
+			zap.Error(err),
+		)
+	}
+}
+
+// RecalculateAll re-measures artifacts for every non-trashed scene. Used to backfill
+// scenes processed before sizes were tracked.
+func (s *ArtifactService) RecalculateAll() error {
+	scenes, err := s.sceneRepo.GetAll()
+	if err != nil {
+		s.logger.Error("Failed to list scenes for artifact recalculation", zap.Error(err))
+		return fmt.Errorf("failed to get scenes: %w", err)
+	}
+
+	failed := 0
+	for _, scene := range scenes {
+		if err := s.RecordScene(scene.ID); err != nil {
+			failed++
+			s.logger.Warn("Failed to recalculate scene artifact sizes", zap.Uint("scene_id", scene.ID), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Recalculated scene artifact sizes",
+		zap.Int("scenes", len(scenes)),
+		zap.Int("failed", failed),
+	)
+	return nil
+}
+
+// GetStats returns artifact usage per storage path together with quota state.
+func (s *ArtifactService) GetStats() (*ArtifactStats, error) {
+	perPath, err := s.repo.GetStatsByStoragePath()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get artifact stats", err)
+	}
+	if perPath == nil {
+		perPath = []data.StoragePathArtifactStats{}
+	}
+
+	stats := &ArtifactStats{
+		StoragePaths: perPath,
+		QuotaBytes:   s.quotaBytes,
+		PausedPhases: []string{},
+	}
+	for _, p := range perPath {
+		stats.TotalBytes += p.TotalBytes
+	}
+
+	stats.MetadataBytes, stats.QuotaExceeded = s.metadataUsage()
+	if stats.QuotaExceeded {
+		stats.PausedPhases = []string{"sprites", "animated_thumbnails"}
+	}
+
+	return stats, nil
+}
+
+// PhasePaused reports whether new jobs for a phase should be held back because
+// metadata_dir is over quota. Paused jobs stay pending and resume once space is freed.
+func (s *ArtifactService) PhasePaused(phase string) bool {
+	if s.quotaBytes <= 0 || !quotaPausedPhases[phase] {
+		return false
+	}
+	_, exceeded := s.metadataUsage()
+	return exceeded
+}
+
+// metadataUsage returns the size of metadata_dir, re-walking the tree at most once per TTL.
+func (s *ArtifactService) metadataUsage() (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.usageMeasuredAt.IsZero() && s.now().Sub(s.usageMeasuredAt) < metadataUsageTTL {
+		return s.usageBytes, s.quotaExceeded
+	}
+
+	usage, err := dirSize(s.cfg.MetadataDir)
+	if err != nil {
+		s.logger.Warn("Failed to measure metadata directory", zap.String("dir", s.cfg.MetadataDir), zap.Error(err))
+	}
+
+	exceeded := s.quotaBytes > 0 && usage > s.quotaBytes
+	if exceeded != s.quotaExceeded {
+		if exceeded {
+			s.logger.Warn("Metadata quota exceeded, pausing sprites and animated thumbnails",
+				zap.Int64("usage_bytes", usage),
+				zap.Int64("quota_bytes", s.quotaBytes),
+			)
+		} else {
+			s.logger.Info("Metadata usage back under quota, resuming sprites and animated thumbnails",
+				zap.Int64("usage_bytes", usage),
+				zap.Int64("quota_bytes", s.quotaBytes),
+			)
+		}
+	}
+
+	s.usageBytes = usage
+	s.quotaExceeded = exceeded
+	s.usageMeasuredAt = s.now()
+	return usage, exceeded
+}
+
+// sumFileSizes returns the combined size of the given files, ignoring missing ones.
+func sumFileSizes(paths ...string) int64 {
+	var total int64
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// dirSize returns the total size of regular files under root.
+func dirSize(root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total, err
+}
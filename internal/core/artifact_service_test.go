@@ -0,0 +1,107 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestArtifactService(t *testing.T, quotaMB int64) (*ArtifactService, *mocks.MockSceneArtifactRepository, *mocks.MockMarkerRepository, config.ProcessingConfig) {
+	ctrl := gomock.NewController(t)
+	artifactRepo := mocks.NewMockSceneArtifactRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+
+	root := t.TempDir()
+	cfg := config.ProcessingConfig{
+		MetadataDir:        root,
+		ThumbnailDir:       filepath.Join(root, "thumbnails"),
+		SpriteDir:          filepath.Join(root, "sprites"),
+		VttDir:             filepath.Join(root, "vtt"),
+		ScenePreviewDir:    filepath.Join(root, "scene-previews"),
+		MarkerThumbnailDir: filepath.Join(root, "marker-thumbnails"),
+		MetadataQuotaMB:    quotaMB,
+	}
+
+	svc := NewArtifactService(artifactRepo, sceneRepo, markerRepo, cfg, zap.NewNop())
+	return svc, artifactRepo, markerRepo, cfg
+}
+
+func writeSizedFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArtifactService_RecordScene(t *testing.T) {
+	svc, artifactRepo, markerRepo, cfg := newTestArtifactService(t, 0)
+
+	writeSizedFile(t, filepath.Join(cfg.ThumbnailDir, "7_thumb_sm.webp"), 10)
+	writeSizedFile(t, filepath.Join(cfg.ThumbnailDir, "7_thumb_lg.webp"), 20)
+	writeSizedFile(t, filepath.Join(cfg.SpriteDir, "7_sheet_001.webp"), 100)
+	writeSizedFile(t, filepath.Join(cfg.SpriteDir, "7_sheet_002.webp"), 100)
+	writeSizedFile(t, filepath.Join(cfg.SpriteDir, "70_sheet_001.webp"), 999) // other scene
+	writeSizedFile(t, filepath.Join(cfg.VttDir, "7_thumbnails.vtt"), 5)
+	writeSizedFile(t, filepath.Join(cfg.ScenePreviewDir, "7_preview.mp4"), 300)
+	writeSizedFile(t, filepath.Join(cfg.MarkerThumbnailDir, "marker_1.webp"), 40)
+	writeSizedFile(t, filepath.Join(cfg.MarkerThumbnailDir, "marker_1.mp4"), 60)
+
+	markerRepo.EXPECT().GetAllByScene(uint(7)).Return([]data.UserSceneMarker{
+		{ID: 1, SceneID: 7, ThumbnailPath: "marker_1.webp", AnimatedThumbnailPath: "marker_1.mp4"},
+	}, nil)
+	artifactRepo.EXPECT().Upsert(gomock.Any()).DoAndReturn(func(size *data.SceneArtifactSize) error {
+		if size.ThumbnailBytes != 30 || size.SpriteBytes != 205 || size.PreviewBytes != 300 || size.MarkerBytes != 100 {
+			t.Fatalf("unexpected sizes: %+v", size)
+		}
+		return nil
+	})
+
+	if err := svc.RecordScene(7); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestArtifactService_PhasePausedOverQuota(t *testing.T) {
+	svc, _, _, cfg := newTestArtifactService(t, 1)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	writeSizedFile(t, filepath.Join(cfg.SpriteDir, "1_sheet_001.webp"), 512*1024)
+	if svc.PhasePaused("sprites") {
+		t.Fatal("expected sprites to run under quota")
+	}
+
+	writeSizedFile(t, filepath.Join(cfg.ScenePreviewDir, "1_preview.mp4"), 1024*1024)
+	if svc.PhasePaused("sprites") {
+		t.Fatal("expected cached usage to be reused within the TTL")
+	}
+
+	now = now.Add(metadataUsageTTL)
+	if !svc.PhasePaused("sprites") || !svc.PhasePaused("animated_thumbnails") {
+		t.Fatal("expected sprites and animated thumbnails to pause over quota")
+	}
+	if svc.PhasePaused("thumbnail") || svc.PhasePaused("metadata") {
+		t.Fatal("expected other phases to keep running over quota")
+	}
+}
+
+func TestArtifactService_NoQuotaNeverPauses(t *testing.T) {
+	svc, _, _, cfg := newTestArtifactService(t, 0)
+	writeSizedFile(t, filepath.Join(cfg.SpriteDir, "1_sheet_001.webp"), 2*1024*1024)
+
+	if svc.PhasePaused("sprites") {
+		t.Fatal("expected no pause without a quota")
+	}
+}
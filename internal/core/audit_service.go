@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// auditPruneInterval is how often entries older than the retention window are deleted
+const auditPruneInterval = 24 * time.Hour
+
+// Audit actions recorded by services rather than the audit middleware
+const (
+	AuditActionLogin       = "auth.login"
+	AuditActionLoginFailed = "auth.login_failed"
+	AuditActionScenePurge  = "scene.purge"
+	AuditActionRegister    = "user.register"
+)
+
+// AuditService keeps the append-only audit log of destructive and administrative
+// actions. Entries come from middleware.AuditMiddleware for API requests and from
+// services for actions that don't map to a single request.
+type AuditService struct {
+	repo          data.AuditRepository
+	retentionDays int
+	logger        *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewAuditService(repo data.AuditRepository, retentionDays int, logger *zap.Logger) *AuditService {
+	return &AuditService{
+		repo:          repo,
+		retentionDays: retentionDays,
+		logger:        logger,
+	}
+}
+
+// Start launches the background retention loop
+func (s *AuditService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(auditPruneInterval)
+		defer ticker.Stop()
+
+		s.prune()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.prune()
+			}
+		}
+	}()
+
+	s.logger.Info("Audit log service started", zap.Int("retention_days", s.retentionDays))
+}
+
+// Stop halts the background loop
+func (s *AuditService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// Record appends an entry. Failures are logged rather than returned so that an
+// audit problem never undoes or fails the action being recorded.
+func (s *AuditService) Record(entry data.AuditEntry) {
+	if s == nil || entry.Action == "" {
+		return
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if entry.Details == nil {
+		entry.Details = data.AuditDetails{}
+	}
+	if err := s.repo.Create(&entry); err != nil {
+		s.logger.Error("Failed to write audit log entry",
+			zap.String("action", entry.Action),
+			zap.String("username", entry.Username),
+			zap.String("resource_id", entry.ResourceID),
+			zap.Error(err),
+		)
+	}
+}
+
+// List returns matching entries, newest first.
+func (s *AuditService) List(filter data.AuditFilter, page, limit int) ([]data.AuditEntry, int64, error) {
+	entries, total, err := s.repo.List(filter, page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list audit log", err)
+	}
+	if entries == nil {
+		entries = []data.AuditEntry{}
+	}
+	return entries, total, nil
+}
+
+func (s *AuditService) prune() {
+	if s.retentionDays <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -s.retentionDays)
+	deleted, err := s.repo.DeleteOlderThan(cutoff)
+	if err != nil {
+		s.logger.Warn("Failed to prune audit log", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("Pruned audit log", zap.Int64("rows", deleted), zap.Time("before", cutoff))
+	}
+}
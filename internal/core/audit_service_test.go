@@ -0,0 +1,88 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestAuditService(t *testing.T, retentionDays int) (*AuditService, *mocks.MockAuditRepository) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockAuditRepository(ctrl)
+	return NewAuditService(repo, retentionDays, zap.NewNop()), repo
+}
+
+func TestAuditService_RecordFillsDefaults(t *testing.T) {
+	svc, repo := newTestAuditService(t, 365)
+
+	repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(entry *data.AuditEntry) error {
+		if entry.CreatedAt.IsZero() {
+			t.Fatal("expected created_at to be set")
+		}
+		if entry.Details == nil {
+			t.Fatal("expected empty details rather than nil")
+		}
+		return nil
+	})
+	svc.Record(data.AuditEntry{Action: AuditActionLogin, Username: "admin"})
+}
+
+func TestAuditService_RecordSkipsEmptyAction(t *testing.T) {
+	svc, _ := newTestAuditService(t, 365)
+
+	// No Create expected
+	svc.Record(data.AuditEntry{Username: "admin"})
+}
+
+func TestAuditService_RecordSwallowsErrors(t *testing.T) {
+	svc, repo := newTestAuditService(t, 365)
+
+	repo.EXPECT().Create(gomock.Any()).Return(errors.New("db down"))
+	svc.Record(data.AuditEntry{Action: AuditActionScenePurge})
+}
+
+func TestAuditService_RecordNilService(t *testing.T) {
+	var svc *AuditService
+	svc.Record(data.AuditEntry{Action: AuditActionLogin})
+}
+
+func TestAuditService_ListReturnsEmptySlice(t *testing.T) {
+	svc, repo := newTestAuditService(t, 365)
+
+	filter := data.AuditFilter{Action: "scene.trash"}
+	repo.EXPECT().List(filter, 1, 50).Return(nil, int64(0), nil)
+
+	entries, total, err := svc.List(filter, 1, 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries == nil || len(entries) != 0 || total != 0 {
+		t.Fatalf("expected empty non-nil result, got %v (%d)", entries, total)
+	}
+}
+
+func TestAuditService_PruneUsesRetention(t *testing.T) {
+	svc, repo := newTestAuditService(t, 30)
+
+	repo.EXPECT().DeleteOlderThan(gomock.Any()).DoAndReturn(func(before time.Time) (int64, error) {
+		expected := time.Now().AddDate(0, 0, -30)
+		if before.Sub(expected).Abs() > time.Minute {
+			t.Fatalf("expected cutoff near %v, got %v", expected, before)
+		}
+		return 3, nil
+	})
+	svc.prune()
+}
+
+func TestAuditService_PruneDisabled(t *testing.T) {
+	svc, _ := newTestAuditService(t, 0)
+
+	// Retention of 0 keeps entries forever, so nothing is deleted
+	svc.prune()
+}
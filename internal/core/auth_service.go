@@ -126,13 +126,22 @@ func (l *AccountLockout) GetRemainingLockoutTime(username string) time.Duration
 }
 
 type AuthService struct {
-	repo        data.UserRepository
-	revokedRepo data.RevokedTokenRepository
-	pasetoKey   []byte
-	tokenTTL    time.Duration
-	logger      *zap.Logger
-	v2          *paseto.V2
-	lockout     *AccountLockout
+	repo            data.UserRepository
+	revokedRepo     data.RevokedTokenRepository
+	appSettingsRepo data.AppSettingsRepository
+	apiKeys         APIKeyAuthenticator
+	sessions        SessionTracker
+	pasetoKey       []byte
+	tokenTTL        time.Duration
+	logger          *zap.Logger
+	v2              *paseto.V2
+	lockout         *AccountLockout
+
+	// Pending two-factor logins by challenge token hash, and the last TOTP period
+	// each user logged in with (a code is only accepted once)
+	twoFactorMu     sync.Mutex
+	challenges      map[string]*twoFactorChallenge
+	lastTOTPCounter map[uint]uint64
 }
 
 type UserPayload struct {
@@ -141,6 +150,39 @@ type UserPayload struct {
 	Role      string `json:"role"`
 	IssuedAt  int64  `json:"iat"`
 	ExpiresAt int64  `json:"exp"`
+	// TwoFactorSetup is set when policy required two-factor enrollment at login;
+	// the session can only reach the auth endpoints until it is done
+	TwoFactorSetup bool `json:"tfs,omitempty"`
+	// Set when the request authenticated with an API key rather than a session
+	// token; the key only grants the permissions in Scopes
+	APIKeyID uint     `json:"-"`
+	Scopes   []string `json:"-"`
+}
+
+// AllowsPermission reports whether the credential the user authenticated with may
+// use a permission their role grants. Session tokens may use all of them.
+func (p *UserPayload) AllowsPermission(permission string) bool {
+	if p.APIKeyID == 0 {
+		return true
+	}
+	for _, scope := range p.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyAuthenticator resolves API keys presented in place of a session token.
+type APIKeyAuthenticator interface {
+	Authenticate(key string) (*UserPayload, error)
+}
+
+// SessionTracker keeps the list of active session tokens up to date as tokens are
+// used and revoked. Tokens are identified by their SHA-256.
+type SessionTracker interface {
+	Seen(tokenHash string)
+	Ended(tokenHash string)
 }
 
 // ErrPasetoKeyTooShort is returned when the PASETO secret is less than 32 bytes
@@ -171,16 +213,33 @@ func NewAuthService(repo data.UserRepository, revokedRepo data.RevokedTokenRepos
 	}
 
 	return &AuthService{
-		repo:        repo,
-		revokedRepo: revokedRepo,
-		pasetoKey:   key,
-		tokenTTL:    tokenTTL,
-		logger:      logger,
-		v2:          paseto.NewV2(),
-		lockout:     NewAccountLockout(lockoutThreshold, lockoutDuration),
+		repo:            repo,
+		revokedRepo:     revokedRepo,
+		pasetoKey:       key,
+		tokenTTL:        tokenTTL,
+		logger:          logger,
+		v2:              paseto.NewV2(),
+		lockout:         NewAccountLockout(lockoutThreshold, lockoutDuration),
+		challenges:      make(map[string]*twoFactorChallenge),
+		lastTOTPCounter: make(map[uint]uint64),
 	}, nil
 }
 
+// SetAPIKeyAuthenticator lets ValidateToken accept API keys.
+func (s *AuthService) SetAPIKeyAuthenticator(apiKeys APIKeyAuthenticator) {
+	s.apiKeys = apiKeys
+}
+
+// SetSessionTracker reports session token use and revocation to the tracker.
+func (s *AuthService) SetSessionTracker(sessions SessionTracker) {
+	s.sessions = sessions
+}
+
+// SetAppSettingsRepository sets where the admin two-factor policy is read from.
+func (s *AuthService) SetAppSettingsRepository(appSettingsRepo data.AppSettingsRepository) {
+	s.appSettingsRepo = appSettingsRepo
+}
+
 // ErrInvalidCredentials is returned for all authentication failures to prevent user enumeration
 var ErrInvalidCredentials = fmt.Errorf("invalid credentials")
 
@@ -214,21 +273,41 @@ func (s *AuthService) Login(username, password string) (string, *data.User, erro
 		return "", nil, ErrInvalidCredentials
 	}
 
+	// With two-factor auth the login continues in CompleteTwoFactorLogin; failed
+	// attempts are only cleared once the code was right too
+	if user.TOTPEnabled {
+		challenge, err := s.newTwoFactorChallenge(user.ID)
+		if err != nil {
+			s.logger.Error("Failed to create two-factor challenge", zap.Error(err))
+			return "", nil, fmt.Errorf("failed to create two-factor challenge")
+		}
+		return "", user, &TwoFactorRequiredError{ChallengeToken: challenge}
+	}
+
 	// Clear failed attempts on successful login
 	s.lockout.RecordSuccess(username)
 
+	token, err := s.startSession(user)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, user, nil
+}
+
+// startSession issues a session token for a user who passed every login step.
+func (s *AuthService) startSession(user *data.User) (string, error) {
 	token, err := s.generateToken(user)
 	if err != nil {
 		s.logger.Error("Failed to generate token", zap.Error(err))
-		return "", nil, fmt.Errorf("failed to generate token")
+		return "", fmt.Errorf("failed to generate token")
 	}
 
 	if err := s.repo.UpdateLastLogin(user.ID); err != nil {
 		s.logger.Warn("Failed to update last login time", zap.Uint("user_id", user.ID), zap.Error(err))
 	}
 
-	s.logger.Info("User logged in", zap.String("username", username), zap.Uint("user_id", user.ID))
-	return token, user, nil
+	s.logger.Info("User logged in", zap.String("username", user.Username), zap.Uint("user_id", user.ID))
+	return token, nil
 }
 
 // StartLockoutCleanup starts a background goroutine to clean up old lockout entries
@@ -248,6 +327,15 @@ func (s *AuthService) StartLockoutCleanup(interval time.Duration, done <-chan st
 }
 
 func (s *AuthService) ValidateToken(token string) (*UserPayload, error) {
+	if s.apiKeys != nil && IsAPIKey(token) {
+		payload, err := s.apiKeys.Authenticate(token)
+		if err != nil {
+			s.logger.Debug("Invalid API key", zap.Error(err))
+			return nil, fmt.Errorf("invalid token")
+		}
+		return payload, nil
+	}
+
 	tokenHash := s.hashToken(token)
 
 	isRevoked, err := s.revokedRepo.IsRevoked(tokenHash)
@@ -260,9 +348,7 @@ func (s *AuthService) ValidateToken(token string) (*UserPayload, error) {
 		return nil, fmt.Errorf("token is revoked")
 	}
 
-	var payload UserPayload
-
-	err = s.v2.Decrypt(token, s.pasetoKey, &payload, nil)
+	payload, err := s.decodeToken(token)
 	if err != nil {
 		s.logger.Error("Invalid token", zap.Error(err))
 		return nil, fmt.Errorf("invalid token")
@@ -274,27 +360,49 @@ func (s *AuthService) ValidateToken(token string) (*UserPayload, error) {
 		return nil, fmt.Errorf("token expired")
 	}
 
-	return &payload, nil
+	if s.sessions != nil {
+		s.sessions.Seen(tokenHash)
+	}
+
+	return payload, nil
 }
 
 func (s *AuthService) RevokeToken(token string, reason string) error {
-	tokenHash := s.hashToken(token)
+	payload, err := s.decodeToken(token)
+	if err != nil {
+		return nil
+	}
+	return s.revokeTokenHash(s.hashToken(token), time.Unix(payload.ExpiresAt, 0), reason)
+}
 
-	var payload UserPayload
-	if err := s.v2.Decrypt(token, s.pasetoKey, &payload, nil); err == nil {
-		revokedToken := &data.RevokedToken{
-			TokenHash: tokenHash,
-			ExpiresAt: time.Unix(payload.ExpiresAt, 0),
-			Reason:    reason,
-		}
-		if err := s.revokedRepo.Create(revokedToken); err != nil {
-			return fmt.Errorf("failed to revoke token: %w", err)
-		}
-		s.logger.Info("Token revoked", zap.String("token_hash", tokenHash), zap.String("reason", reason))
+// revokeTokenHash revokes a session token known only by its hash. The revocation
+// is kept until the token would have expired anyway.
+func (s *AuthService) revokeTokenHash(tokenHash string, expiresAt time.Time, reason string) error {
+	revokedToken := &data.RevokedToken{
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+		Reason:    reason,
+	}
+	if err := s.revokedRepo.Create(revokedToken); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	s.logger.Info("Token revoked", zap.String("token_hash", tokenHash), zap.String("reason", reason))
+
+	if s.sessions != nil {
+		s.sessions.Ended(tokenHash)
 	}
 	return nil
 }
 
+// decodeToken decrypts a session token without checking expiry or revocation.
+func (s *AuthService) decodeToken(token string) (*UserPayload, error) {
+	var payload UserPayload
+	if err := s.v2.Decrypt(token, s.pasetoKey, &payload, nil); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
 func (s *AuthService) hashToken(token string) string {
 	hasher := sha256.New()
 	hasher.Write([]byte(token))
@@ -316,11 +424,12 @@ func (s *AuthService) checkPassword(hashedPassword, password string) error {
 func (s *AuthService) generateToken(user *data.User) (string, error) {
 	now := time.Now()
 	payload := UserPayload{
-		UserID:    user.ID,
-		Username:  user.Username,
-		Role:      user.Role,
-		IssuedAt:  now.Unix(),
-		ExpiresAt: now.Add(s.tokenTTL).Unix(),
+		UserID:         user.ID,
+		Username:       user.Username,
+		Role:           user.Role,
+		IssuedAt:       now.Unix(),
+		ExpiresAt:      now.Add(s.tokenTTL).Unix(),
+		TwoFactorSetup: s.RequiresTwoFactorSetup(user),
 	}
 
 	token, err := s.v2.Encrypt(s.pasetoKey, payload, nil)
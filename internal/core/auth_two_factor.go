@@ -0,0 +1,365 @@
+package core
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	twoFactorChallengeTTL   = 5 * time.Minute
+	twoFactorMaxAttempts    = 5
+	recoveryCodeCount       = 10
+	recoveryCodeGroupLength = 5
+)
+
+// TwoFactorRequiredError is returned by Login when the password was right but the
+// user has two-factor authentication enabled. The login is finished by passing the
+// challenge token and a code to CompleteTwoFactorLogin.
+type TwoFactorRequiredError struct {
+	ChallengeToken string
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return "two-factor authentication required"
+}
+
+// twoFactorChallenge is a login waiting for its second step.
+type twoFactorChallenge struct {
+	userID    uint
+	expiresAt time.Time
+	attempts  int
+}
+
+// TwoFactorStatus describes a user's two-factor enrollment.
+type TwoFactorStatus struct {
+	Enabled                bool `json:"enabled"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+	Required               bool `json:"required"`
+}
+
+// TwoFactorSetup is the secret an authenticator app is set up with, and the same
+// secret as an otpauth:// URI for QR codes.
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// CompleteTwoFactorLogin finishes a login started by Login with a TOTP code or an
+// unused recovery code. Wrong codes count as failed logins for the account lockout,
+// and the challenge is dropped after twoFactorMaxAttempts of them.
+func (s *AuthService) CompleteTwoFactorLogin(challengeToken, code string) (string, *data.User, error) {
+	tokenHash := s.hashToken(challengeToken)
+
+	s.twoFactorMu.Lock()
+	challenge, ok := s.challenges[tokenHash]
+	if ok && time.Now().After(challenge.expiresAt) {
+		delete(s.challenges, tokenHash)
+		ok = false
+	}
+	s.twoFactorMu.Unlock()
+	if !ok {
+		return "", nil, apperrors.ErrTwoFactorChallengeExpired
+	}
+
+	user, err := s.repo.GetByID(challenge.userID)
+	if err != nil || !user.TOTPEnabled {
+		s.dropChallenge(tokenHash)
+		return "", nil, apperrors.ErrTwoFactorChallengeExpired
+	}
+	if s.lockout.IsLocked(user.Username) {
+		s.dropChallenge(tokenHash)
+		return "", nil, ErrInvalidCredentials
+	}
+
+	if err := s.verifyTwoFactorCode(user, code); err != nil {
+		if locked := s.lockout.RecordFailure(user.Username); locked {
+			s.logger.Warn("Account locked due to failed attempts", zap.String("username", user.Username))
+		}
+		s.twoFactorMu.Lock()
+		challenge.attempts++
+		if challenge.attempts >= twoFactorMaxAttempts {
+			delete(s.challenges, tokenHash)
+		}
+		s.twoFactorMu.Unlock()
+		return "", nil, err
+	}
+
+	s.dropChallenge(tokenHash)
+	s.lockout.RecordSuccess(user.Username)
+
+	token, err := s.startSession(user)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, user, nil
+}
+
+// TwoFactorStatus returns whether the user has two-factor authentication enabled and
+// whether policy requires it of them.
+func (s *AuthService) TwoFactorStatus(userID uint) (*TwoFactorStatus, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound(userID)
+	}
+	status := &TwoFactorStatus{
+		Enabled:  user.TOTPEnabled,
+		Required: s.twoFactorRequired(user),
+	}
+	if user.TOTPEnabled {
+		status.RecoveryCodesRemaining = len(user.TOTPRecoveryCodes)
+	}
+	return status, nil
+}
+
+// BeginTwoFactorSetup generates a new secret for the user. Two-factor authentication
+// is only turned on once EnableTwoFactor confirms a code from it.
+func (s *AuthService) BeginTwoFactorSetup(userID uint, currentPassword string) (*TwoFactorSetup, error) {
+	user, err := s.verifyCurrentPassword(userID, currentPassword)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, apperrors.NewValidationError("two-factor authentication is already enabled")
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate two-factor secret", err)
+	}
+	if err := s.repo.UpdateTwoFactor(userID, secret, false, nil); err != nil {
+		return nil, apperrors.NewInternalError("failed to save two-factor secret", err)
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(user.Username, secret),
+	}, nil
+}
+
+// EnableTwoFactor turns on two-factor authentication once the user proves their
+// authenticator produces valid codes. It returns the recovery codes, which are only
+// stored hashed and cannot be shown again.
+func (s *AuthService) EnableTwoFactor(userID uint, code string) ([]string, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound(userID)
+	}
+	if user.TOTPEnabled {
+		return nil, apperrors.NewValidationError("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, apperrors.NewValidationError("two-factor setup has not been started")
+	}
+
+	counter, ok := matchTOTP(user.TOTPSecret, normalizeTwoFactorCode(code), time.Now())
+	if !ok {
+		return nil, apperrors.ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate recovery codes", err)
+	}
+	if err := s.repo.UpdateTwoFactor(userID, user.TOTPSecret, true, hashes); err != nil {
+		return nil, apperrors.NewInternalError("failed to enable two-factor authentication", err)
+	}
+
+	s.twoFactorMu.Lock()
+	s.lastTOTPCounter[userID] = counter
+	s.twoFactorMu.Unlock()
+
+	s.logger.Info("Two-factor authentication enabled", zap.Uint("user_id", userID))
+	return codes, nil
+}
+
+// DisableTwoFactor turns off two-factor authentication. It needs both the password
+// and a current code, and is refused while policy requires it of the user.
+func (s *AuthService) DisableTwoFactor(userID uint, currentPassword, code string) error {
+	user, err := s.verifyCurrentPassword(userID, currentPassword)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return apperrors.NewValidationError("two-factor authentication is not enabled")
+	}
+	if s.twoFactorRequired(user) {
+		return apperrors.NewForbiddenError("two-factor authentication is required for admins")
+	}
+	if err := s.verifyTwoFactorCode(user, code); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateTwoFactor(userID, "", false, nil); err != nil {
+		return apperrors.NewInternalError("failed to disable two-factor authentication", err)
+	}
+
+	s.logger.Info("Two-factor authentication disabled", zap.Uint("user_id", userID))
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes with new ones.
+func (s *AuthService) RegenerateRecoveryCodes(userID uint, currentPassword string) ([]string, error) {
+	user, err := s.verifyCurrentPassword(userID, currentPassword)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, apperrors.NewValidationError("two-factor authentication is not enabled")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate recovery codes", err)
+	}
+	if err := s.repo.UpdateTwoFactor(userID, user.TOTPSecret, true, hashes); err != nil {
+		return nil, apperrors.NewInternalError("failed to save recovery codes", err)
+	}
+	return codes, nil
+}
+
+// ReissueToken revokes a session token and issues a new one for the same user, so
+// claims derived from the user's state (like TwoFactorSetup) are refreshed.
+func (s *AuthService) ReissueToken(token string, userID uint) (string, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return "", apperrors.ErrUserNotFound(userID)
+	}
+	newToken, err := s.generateToken(user)
+	if err != nil {
+		return "", apperrors.NewInternalError("failed to generate token", err)
+	}
+	if err := s.RevokeToken(token, "reissued"); err != nil {
+		s.logger.Warn("Failed to revoke reissued token", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	return newToken, nil
+}
+
+// RequiresTwoFactorSetup reports whether policy requires two-factor authentication
+// of the user but they have not set it up yet.
+func (s *AuthService) RequiresTwoFactorSetup(user *data.User) bool {
+	return !user.TOTPEnabled && s.twoFactorRequired(user)
+}
+
+// twoFactorRequired reports whether policy requires the user to use two-factor
+// authentication. Settings that cannot be read don't lock anyone out.
+func (s *AuthService) twoFactorRequired(user *data.User) bool {
+	if user.Role != "admin" || s.appSettingsRepo == nil {
+		return false
+	}
+	settings, err := s.appSettingsRepo.Get()
+	if err != nil {
+		s.logger.Warn("Failed to read two-factor policy", zap.Error(err))
+		return false
+	}
+	return settings.RequireAdminTwoFactor
+}
+
+// verifyTwoFactorCode accepts a TOTP code not used before or an unused recovery
+// code, which is then spent.
+func (s *AuthService) verifyTwoFactorCode(user *data.User, code string) error {
+	code = normalizeTwoFactorCode(code)
+
+	if counter, ok := matchTOTP(user.TOTPSecret, code, time.Now()); ok {
+		s.twoFactorMu.Lock()
+		defer s.twoFactorMu.Unlock()
+		if last, seen := s.lastTOTPCounter[user.ID]; seen && counter <= last {
+			return apperrors.ErrInvalidTwoFactorCode
+		}
+		s.lastTOTPCounter[user.ID] = counter
+		return nil
+	}
+
+	hash := hashRecoveryCode(code)
+	for i, stored := range user.TOTPRecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) != 1 {
+			continue
+		}
+		remaining := make([]string, 0, len(user.TOTPRecoveryCodes)-1)
+		remaining = append(remaining, user.TOTPRecoveryCodes[:i]...)
+		remaining = append(remaining, user.TOTPRecoveryCodes[i+1:]...)
+		if err := s.repo.UpdateTwoFactor(user.ID, user.TOTPSecret, true, remaining); err != nil {
+			return apperrors.NewInternalError("failed to use recovery code", err)
+		}
+		user.TOTPRecoveryCodes = remaining
+		s.logger.Info("Recovery code used", zap.Uint("user_id", user.ID), zap.Int("remaining", len(remaining)))
+		return nil
+	}
+
+	return apperrors.ErrInvalidTwoFactorCode
+}
+
+func (s *AuthService) verifyCurrentPassword(userID uint, password string) (*data.User, error) {
+	user, err := s.repo.GetByID(userID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound(userID)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return nil, apperrors.NewValidationErrorWithField("current_password", "current password is incorrect")
+	}
+	return user, nil
+}
+
+// newTwoFactorChallenge stores a pending login for the user and returns its token.
+func (s *AuthService) newTwoFactorChallenge(userID uint) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	now := time.Now()
+	s.twoFactorMu.Lock()
+	defer s.twoFactorMu.Unlock()
+	for hash, challenge := range s.challenges {
+		if now.After(challenge.expiresAt) {
+			delete(s.challenges, hash)
+		}
+	}
+	s.challenges[s.hashToken(token)] = &twoFactorChallenge{
+		userID:    userID,
+		expiresAt: now.Add(twoFactorChallengeTTL),
+	}
+	return token, nil
+}
+
+func (s *AuthService) dropChallenge(tokenHash string) {
+	s.twoFactorMu.Lock()
+	delete(s.challenges, tokenHash)
+	s.twoFactorMu.Unlock()
+}
+
+// normalizeTwoFactorCode strips the spaces and dashes people type into codes.
+func normalizeTwoFactorCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+// newRecoveryCodes returns recovery codes formatted as xxxxx-xxxxx, and their hashes.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 2*recoveryCodeGroupLength)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(totpEncoding.EncodeToString(buf))[:2*recoveryCodeGroupLength]
+		codes[i] = raw[:recoveryCodeGroupLength] + "-" + raw[recoveryCodeGroupLength:]
+		hashes[i] = hashRecoveryCode(raw)
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeTwoFactorCode(code)))
+	return hex.EncodeToString(sum[:])
+}
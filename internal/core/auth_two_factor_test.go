@@ -0,0 +1,233 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+)
+
+// RFC 6238 test secret "12345678901234567890" in base32
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := totpCode(testTOTPSecret, uint64(tt.unix)/30)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.code {
+			t.Fatalf("at %d expected %s, got %s", tt.unix, tt.code, got)
+		}
+	}
+}
+
+func TestMatchTOTP_AllowsOnePeriodOfSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, _ := totpCode(testTOTPSecret, uint64(now.Unix())/30-1)
+	tooOld, _ := totpCode(testTOTPSecret, uint64(now.Unix())/30-2)
+
+	if _, ok := matchTOTP(testTOTPSecret, previous, now); !ok {
+		t.Fatal("expected the previous period's code to match")
+	}
+	if _, ok := matchTOTP(testTOTPSecret, tooOld, now); ok {
+		t.Fatal("expected a code two periods old to be rejected")
+	}
+	if _, ok := matchTOTP(testTOTPSecret, "12345", now); ok {
+		t.Fatal("expected a short code to be rejected")
+	}
+}
+
+func currentTestTOTP(t *testing.T) string {
+	t.Helper()
+	code, err := totpCode(testTOTPSecret, uint64(time.Now().Unix())/30)
+	if err != nil {
+		t.Fatalf("failed to compute code: %v", err)
+	}
+	return code
+}
+
+func startTwoFactorLogin(t *testing.T, svc *AuthService, userRepo *mocks.MockUserRepository, user *data.User) string {
+	t.Helper()
+	userRepo.EXPECT().GetByUsername(user.Username).Return(user, nil)
+
+	token, _, err := svc.Login(user.Username, "correctpass")
+	var challengeErr *TwoFactorRequiredError
+	if !errors.As(err, &challengeErr) {
+		t.Fatalf("expected two-factor challenge, got token %q and error %v", token, err)
+	}
+	if token != "" {
+		t.Fatal("expected no session token before the second step")
+	}
+	return challengeErr.ChallengeToken
+}
+
+func TestLogin_TwoFactorChallenge(t *testing.T) {
+	svc, userRepo, _ := newTestAuthService(t)
+	user := &data.User{ID: 1, Username: "alice", Password: hashPassword(t, "correctpass"), Role: "user",
+		TOTPSecret: testTOTPSecret, TOTPEnabled: true}
+
+	challenge := startTwoFactorLogin(t, svc, userRepo, user)
+
+	userRepo.EXPECT().GetByID(uint(1)).Return(user, nil).Times(2)
+	if _, _, err := svc.CompleteTwoFactorLogin(challenge, "000000"); !errors.Is(err, apperrors.ErrInvalidTwoFactorCode) {
+		t.Fatalf("expected invalid code error, got %v", err)
+	}
+
+	userRepo.EXPECT().UpdateLastLogin(uint(1)).Return(nil)
+	token, _, err := svc.CompleteTwoFactorLogin(challenge, currentTestTOTP(t))
+	if err != nil {
+		t.Fatalf("expected login to complete, got %v", err)
+	}
+	if token == "" {
+		t.Fatal("expected session token")
+	}
+
+	// The challenge is single use
+	if _, _, err := svc.CompleteTwoFactorLogin(challenge, currentTestTOTP(t)); !errors.Is(err, apperrors.ErrTwoFactorChallengeExpired) {
+		t.Fatalf("expected used challenge to be rejected, got %v", err)
+	}
+}
+
+func TestCompleteTwoFactorLogin_RejectsReplayedCode(t *testing.T) {
+	svc, userRepo, _ := newTestAuthService(t)
+	user := &data.User{ID: 1, Username: "alice", Password: hashPassword(t, "correctpass"), Role: "user",
+		TOTPSecret: testTOTPSecret, TOTPEnabled: true}
+	code := currentTestTOTP(t)
+
+	first := startTwoFactorLogin(t, svc, userRepo, user)
+	userRepo.EXPECT().GetByID(uint(1)).Return(user, nil).Times(2)
+	userRepo.EXPECT().UpdateLastLogin(uint(1)).Return(nil)
+	if _, _, err := svc.CompleteTwoFactorLogin(first, code); err != nil {
+		t.Fatalf("expected first login to complete, got %v", err)
+	}
+
+	second := startTwoFactorLogin(t, svc, userRepo, user)
+	if _, _, err := svc.CompleteTwoFactorLogin(second, code); !errors.Is(err, apperrors.ErrInvalidTwoFactorCode) {
+		t.Fatalf("expected replayed code to be rejected, got %v", err)
+	}
+}
+
+func TestCompleteTwoFactorLogin_RecoveryCodeIsSpent(t *testing.T) {
+	svc, userRepo, _ := newTestAuthService(t)
+	user := &data.User{ID: 1, Username: "alice", Password: hashPassword(t, "correctpass"), Role: "user",
+		TOTPSecret: testTOTPSecret, TOTPEnabled: true,
+		TOTPRecoveryCodes: []string{hashRecoveryCode("aaaaa-bbbbb"), hashRecoveryCode("ccccc-ddddd")}}
+
+	challenge := startTwoFactorLogin(t, svc, userRepo, user)
+	userRepo.EXPECT().GetByID(uint(1)).Return(user, nil)
+	userRepo.EXPECT().UpdateTwoFactor(uint(1), testTOTPSecret, true, []string{hashRecoveryCode("ccccc-ddddd")}).Return(nil)
+	userRepo.EXPECT().UpdateLastLogin(uint(1)).Return(nil)
+
+	if _, _, err := svc.CompleteTwoFactorLogin(challenge, "AAAAA BBBBB"); err != nil {
+		t.Fatalf("expected recovery code to be accepted, got %v", err)
+	}
+}
+
+func TestCompleteTwoFactorLogin_ChallengeDroppedAfterMaxAttempts(t *testing.T) {
+	svc, userRepo, _ := newTestAuthService(t)
+	user := &data.User{ID: 1, Username: "alice", Password: hashPassword(t, "correctpass"), Role: "user",
+		TOTPSecret: testTOTPSecret, TOTPEnabled: true}
+
+	challenge := startTwoFactorLogin(t, svc, userRepo, user)
+	userRepo.EXPECT().GetByID(uint(1)).Return(user, nil).Times(twoFactorMaxAttempts)
+	for i := 0; i < twoFactorMaxAttempts; i++ {
+		if _, _, err := svc.CompleteTwoFactorLogin(challenge, "000000"); err == nil {
+			t.Fatal("expected wrong code to fail")
+		}
+	}
+
+	if _, _, err := svc.CompleteTwoFactorLogin(challenge, currentTestTOTP(t)); !errors.Is(err, apperrors.ErrTwoFactorChallengeExpired) {
+		t.Fatalf("expected exhausted challenge to be rejected, got %v", err)
+	}
+	if !svc.lockout.IsLocked("alice") {
+		t.Fatal("expected wrong codes to count towards the account lockout")
+	}
+}
+
+func TestEnableTwoFactor(t *testing.T) {
+	svc, userRepo, _ := newTestAuthService(t)
+	user := &data.User{ID: 1, Username: "alice", TOTPSecret: testTOTPSecret}
+
+	userRepo.EXPECT().GetByID(uint(1)).Return(user, nil).Times(2)
+	if _, err := svc.EnableTwoFactor(1, "000000"); !errors.Is(err, apperrors.ErrInvalidTwoFactorCode) {
+		t.Fatalf("expected invalid code error, got %v", err)
+	}
+
+	var stored []string
+	userRepo.EXPECT().UpdateTwoFactor(uint(1), testTOTPSecret, true, gomock.Any()).
+		DoAndReturn(func(_ uint, _ string, _ bool, hashes []string) error {
+			stored = hashes
+			return nil
+		})
+	codes, err := svc.EnableTwoFactor(1, currentTestTOTP(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(codes) != recoveryCodeCount || len(stored) != recoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d (%d stored)", recoveryCodeCount, len(codes), len(stored))
+	}
+	if stored[0] != hashRecoveryCode(codes[0]) {
+		t.Fatal("expected recovery codes to be stored hashed")
+	}
+}
+
+func TestDisableTwoFactor_RefusedWhileRequiredForAdmins(t *testing.T) {
+	svc, userRepo, _ := newTestAuthService(t)
+	appSettingsRepo := mocks.NewMockAppSettingsRepository(gomock.NewController(t))
+	svc.SetAppSettingsRepository(appSettingsRepo)
+	user := &data.User{ID: 1, Username: "alice", Password: hashPassword(t, "correctpass"), Role: "admin",
+		TOTPSecret: testTOTPSecret, TOTPEnabled: true}
+
+	userRepo.EXPECT().GetByID(uint(1)).Return(user, nil)
+	appSettingsRepo.EXPECT().Get().Return(&data.AppSettingsRecord{RequireAdminTwoFactor: true}, nil)
+
+	err := svc.DisableTwoFactor(1, "correctpass", currentTestTOTP(t))
+	var forbidden *apperrors.ForbiddenError
+	if !errors.As(err, &forbidden) {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+}
+
+func TestGenerateToken_FlagsAdminsWithoutTwoFactorUnderPolicy(t *testing.T) {
+	svc, userRepo, revokedRepo := newTestAuthService(t)
+	appSettingsRepo := mocks.NewMockAppSettingsRepository(gomock.NewController(t))
+	svc.SetAppSettingsRepository(appSettingsRepo)
+	appSettingsRepo.EXPECT().Get().Return(&data.AppSettingsRecord{RequireAdminTwoFactor: true}, nil).AnyTimes()
+
+	admin := &data.User{ID: 1, Username: "root", Password: hashPassword(t, "correctpass"), Role: "admin"}
+	userRepo.EXPECT().GetByUsername("root").Return(admin, nil)
+	userRepo.EXPECT().UpdateLastLogin(uint(1)).Return(nil)
+	revokedRepo.EXPECT().IsRevoked(gomock.Any()).Return(false, nil)
+
+	token, _, err := svc.Login("root", "correctpass")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, err := svc.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !payload.TwoFactorSetup {
+		t.Fatal("expected admin without two-factor auth to get a setup-only session")
+	}
+
+	viewer := &data.User{ID: 2, Username: "bob", Role: "user"}
+	if svc.RequiresTwoFactorSetup(viewer) {
+		t.Fatal("expected policy to only apply to admins")
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// castMimeTypes covers containers the OS mime database often lacks; cast
+// receivers pick a player from the Content-Type, so it must be accurate.
+var castMimeTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".webm": "video/webm",
+	".mkv":  "video/x-matroska",
+	".mov":  "video/quicktime",
+	".avi":  "video/x-msvideo",
+	".wmv":  "video/x-ms-wmv",
+	".ts":   "video/mp2t",
+}
+
+// CastInfo is what a cast sender hands to a Chromecast or DLNA renderer.
+type CastInfo struct {
+	SceneID      uint      `json:"scene_id"`
+	Title        string    `json:"title"`
+	Duration     int       `json:"duration"`
+	MimeType     string    `json:"mime_type"`
+	StreamURL    string    `json:"stream_url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// CastToken is a verified cast URL token.
+type CastToken struct {
+	SceneID   uint
+	UserID    uint
+	ExpiresAt time.Time
+}
+
+// CastService issues signed, expiring stream URLs that cast devices can play
+// without a session. Casting is toggled by the cast_discovery_enabled app setting.
+type CastService struct {
+	sceneRepo       data.SceneRepository
+	appSettingsRepo data.AppSettingsRepository
+	mediaSigner     *MediaSigner
+	key             []byte
+	cfg             config.CastingConfig
+	logger          *zap.Logger
+}
+
+func NewCastService(sceneRepo data.SceneRepository, appSettingsRepo data.AppSettingsRepository, mediaSigner *MediaSigner, secret string, cfg config.CastingConfig, logger *zap.Logger) *CastService {
+	// Derive a dedicated key so cast tokens can never be confused with auth tokens
+	key := sha256.Sum256([]byte("goonhub-cast:" + secret))
+	return &CastService{
+		sceneRepo:       sceneRepo,
+		appSettingsRepo: appSettingsRepo,
+		mediaSigner:     mediaSigner,
+		key:             key[:],
+		cfg:             cfg,
+		logger:          logger,
+	}
+}
+
+// Enabled reports whether casting is turned on in app settings.
+func (s *CastService) Enabled() bool {
+	settings, err := s.appSettingsRepo.Get()
+	if err != nil {
+		s.logger.Warn("Failed to read app settings for casting", zap.Error(err))
+		return false
+	}
+	return settings.CastDiscoveryEnabled
+}
+
+// GetCastInfo returns cast URLs for a scene. requestBaseURL is used when no
+// casting.base_url is configured.
+func (s *CastService) GetCastInfo(sceneID, userID uint, requestBaseURL string) (*CastInfo, error) {
+	if !s.Enabled() {
+		return nil, apperrors.NewForbiddenError("casting is disabled")
+	}
+
+	scene, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrSceneNotFound(sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+
+	expiresAt := time.Now().Add(s.cfg.TokenTTL).Truncate(time.Second)
+	token := s.signToken(CastToken{SceneID: scene.ID, UserID: userID, ExpiresAt: expiresAt})
+
+	baseURL := strings.TrimRight(s.cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = strings.TrimRight(requestBaseURL, "/")
+	}
+
+	return &CastInfo{
+		SceneID:      scene.ID,
+		Title:        scene.Title,
+		Duration:     scene.Duration,
+		MimeType:     CastMimeType(scene.StoredPath),
+		StreamURL:    fmt.Sprintf("%s/cast/%s/stream", baseURL, token),
+		ThumbnailURL: baseURL + s.mediaSigner.SignPath(fmt.Sprintf("/thumbnails/%d?size=lg", scene.ID), scene.ID),
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// ResolveToken verifies a cast URL token. Tokens stop working when they expire
+// or when casting is disabled.
+func (s *CastService) ResolveToken(token string) (*CastToken, error) {
+	parsed, err := s.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(parsed.ExpiresAt) {
+		return nil, apperrors.NewForbiddenError("cast link has expired")
+	}
+	if !s.Enabled() {
+		return nil, apperrors.NewForbiddenError("casting is disabled")
+	}
+	return parsed, nil
+}
+
+// signToken encodes "sceneID.userID.expiresUnix.signature".
+func (s *CastService) signToken(t CastToken) string {
+	payload := fmt.Sprintf("%d.%d.%d", t.SceneID, t.UserID, t.ExpiresAt.Unix())
+	return payload + "." + s.sign(payload)
+}
+
+func (s *CastService) parseToken(token string) (*CastToken, error) {
+	invalid := apperrors.NewForbiddenError("invalid cast link")
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return nil, invalid
+	}
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(s.sign(payload))) {
+		return nil, invalid
+	}
+
+	sceneID, err1 := strconv.ParseUint(parts[0], 10, 32)
+	userID, err2 := strconv.ParseUint(parts[1], 10, 32)
+	expires, err3 := strconv.ParseInt(parts[2], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, invalid
+	}
+	return &CastToken{
+		SceneID:   uint(sceneID),
+		UserID:    uint(userID),
+		ExpiresAt: time.Unix(expires, 0),
+	}, nil
+}
+
+func (s *CastService) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// CastMimeType returns the Content-Type cast receivers expect for a video file.
+func CastMimeType(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if t, ok := castMimeTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "video/mp4"
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestCastService(t *testing.T, cfg config.CastingConfig) (*CastService, *mocks.MockSceneRepository, *mocks.MockAppSettingsRepository) {
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	appSettingsRepo := mocks.NewMockAppSettingsRepository(ctrl)
+	if cfg.TokenTTL == 0 {
+		cfg.TokenTTL = time.Hour
+	}
+	return NewCastService(sceneRepo, appSettingsRepo, nil, "secret", cfg, zap.NewNop()), sceneRepo, appSettingsRepo
+}
+
+func castEnabled(enabled bool) *data.AppSettingsRecord {
+	return &data.AppSettingsRecord{ID: 1, CastDiscoveryEnabled: enabled}
+}
+
+func TestGetCastInfo_Disabled(t *testing.T) {
+	svc, _, appSettingsRepo := newTestCastService(t, config.CastingConfig{})
+	appSettingsRepo.EXPECT().Get().Return(castEnabled(false), nil)
+
+	if _, err := svc.GetCastInfo(1, 2, "http://host"); !apperrors.IsForbidden(err) {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+}
+
+func TestGetCastInfo_SceneNotFound(t *testing.T) {
+	svc, sceneRepo, appSettingsRepo := newTestCastService(t, config.CastingConfig{})
+	appSettingsRepo.EXPECT().Get().Return(castEnabled(true), nil)
+	sceneRepo.EXPECT().GetByID(uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	if _, err := svc.GetCastInfo(9, 2, "http://host"); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestGetCastInfo_RoundTrip(t *testing.T) {
+	svc, sceneRepo, appSettingsRepo := newTestCastService(t, config.CastingConfig{BaseURL: "http://tv.lan:8080/"})
+	appSettingsRepo.EXPECT().Get().Return(castEnabled(true), nil).Times(2)
+	sceneRepo.EXPECT().GetByID(uint(7)).Return(&data.Scene{ID: 7, Title: "Scene", StoredPath: "/v/a.MKV"}, nil)
+
+	info, err := svc.GetCastInfo(7, 3, "http://localhost:3000")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.MimeType != "video/x-matroska" {
+		t.Fatalf("expected matroska mime type, got %s", info.MimeType)
+	}
+	prefix := "http://tv.lan:8080/cast/"
+	if !strings.HasPrefix(info.StreamURL, prefix) || !strings.HasSuffix(info.StreamURL, "/stream") {
+		t.Fatalf("expected stream URL on configured base URL, got %s", info.StreamURL)
+	}
+
+	token := strings.TrimSuffix(strings.TrimPrefix(info.StreamURL, prefix), "/stream")
+	resolved, err := svc.ResolveToken(token)
+	if err != nil {
+		t.Fatalf("expected token to resolve, got %v", err)
+	}
+	if resolved.SceneID != 7 || resolved.UserID != 3 {
+		t.Fatalf("unexpected token contents: %+v", resolved)
+	}
+}
+
+func TestResolveToken_Tampered(t *testing.T) {
+	svc, _, _ := newTestCastService(t, config.CastingConfig{})
+	token := svc.signToken(CastToken{SceneID: 1, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)})
+
+	tampered := "2" + token[1:]
+	if _, err := svc.ResolveToken(tampered); !apperrors.IsForbidden(err) {
+		t.Fatalf("expected forbidden error for tampered token, got %v", err)
+	}
+	if _, err := svc.ResolveToken("garbage"); !apperrors.IsForbidden(err) {
+		t.Fatalf("expected forbidden error for malformed token, got %v", err)
+	}
+}
+
+func TestResolveToken_Expired(t *testing.T) {
+	svc, _, _ := newTestCastService(t, config.CastingConfig{})
+	token := svc.signToken(CastToken{SceneID: 1, UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)})
+
+	if _, err := svc.ResolveToken(token); !apperrors.IsForbidden(err) {
+		t.Fatalf("expected forbidden error for expired token, got %v", err)
+	}
+}
+
+func TestCastMimeType(t *testing.T) {
+	cases := map[string]string{
+		"a.mp4":  "video/mp4",
+		"a.webm": "video/webm",
+		"a.mov":  "video/quicktime",
+		"a":      "video/mp4",
+	}
+	for path, want := range cases {
+		if got := CastMimeType(path); got != want {
+			t.Errorf("CastMimeType(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
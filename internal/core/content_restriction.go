@@ -0,0 +1,281 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// unrestrictedRole can never be limited to part of the library, so admins can
+// always see and fix what other roles are restricted to.
+const unrestrictedRole = "admin"
+
+// anonymousRole is the role of requests without a user, such as public stream
+// URLs. It can't be told apart from a restricted user, so once any role is
+// restricted it is treated as the most restricted role and sees nothing.
+const anonymousRole = ""
+
+// SetContentRestrictionRepository enables per-role content restrictions and
+// loads them into the cache.
+func (s *RBACService) SetContentRestrictionRepository(repo data.ContentRestrictionRepository) error {
+	s.restrictionRepo = repo
+	return s.refreshRestrictions()
+}
+
+func (s *RBACService) refreshRestrictions() error {
+	if s.restrictionRepo == nil {
+		return nil
+	}
+
+	list, err := s.restrictionRepo.ListAll()
+	if err != nil {
+		return fmt.Errorf("failed to load content restrictions: %w", err)
+	}
+
+	restrictions := make(map[string]*data.ContentRestriction, len(list))
+	for i := range list {
+		if !list[i].IsEmpty() {
+			restrictions[list[i].RoleName] = &list[i]
+		}
+	}
+
+	s.mu.Lock()
+	s.restrictions = restrictions
+	s.mu.Unlock()
+	return nil
+}
+
+// ContentRestriction returns the part of the library role is limited to, or nil
+// when the role can see everything.
+func (s *RBACService) ContentRestriction(role string) *data.ContentRestriction {
+	if role == unrestrictedRole {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.restrictions[role]
+}
+
+// hasRestrictions reports whether any role is restricted.
+func (s *RBACService) hasRestrictions() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.restrictions) > 0
+}
+
+// CanAccessScene reports whether role may see and stream a scene.
+func (s *RBACService) CanAccessScene(role string, sceneID uint) (bool, error) {
+	if role == anonymousRole {
+		return !s.hasRestrictions(), nil
+	}
+	restriction := s.ContentRestriction(role)
+	if restriction == nil {
+		return true, nil
+	}
+	visible, err := s.restrictionRepo.IsSceneVisible(restriction, sceneID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check scene access: %w", err)
+	}
+	return visible, nil
+}
+
+// FilterVisibleSceneIDs returns the scene IDs role may see, keeping their order.
+func (s *RBACService) FilterVisibleSceneIDs(role string, sceneIDs []uint) ([]uint, error) {
+	if role == anonymousRole {
+		if s.hasRestrictions() {
+			return []uint{}, nil
+		}
+		return sceneIDs, nil
+	}
+	restriction := s.ContentRestriction(role)
+	if restriction == nil || len(sceneIDs) == 0 {
+		return sceneIDs, nil
+	}
+
+	visibleIDs, err := s.restrictionRepo.FilterVisibleSceneIDs(restriction, sceneIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter visible scenes: %w", err)
+	}
+	visible := make(map[uint]bool, len(visibleIDs))
+	for _, id := range visibleIDs {
+		visible[id] = true
+	}
+	filtered := make([]uint, 0, len(visibleIDs))
+	for _, id := range sceneIDs {
+		if visible[id] {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered, nil
+}
+
+// FilterVisibleScenes drops the scenes role may not see, keeping their order.
+func (s *RBACService) FilterVisibleScenes(role string, scenes []data.Scene) ([]data.Scene, error) {
+	ids := make([]uint, len(scenes))
+	for i := range scenes {
+		ids[i] = scenes[i].ID
+	}
+	visibleIDs, err := s.FilterVisibleSceneIDs(role, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(visibleIDs) == len(scenes) {
+		return scenes, nil
+	}
+
+	visible := make(map[uint]bool, len(visibleIDs))
+	for _, id := range visibleIDs {
+		visible[id] = true
+	}
+	filtered := make([]data.Scene, 0, len(visibleIDs))
+	for _, scene := range scenes {
+		if visible[scene.ID] {
+			filtered = append(filtered, scene)
+		}
+	}
+	return filtered, nil
+}
+
+// Services that list scenes take the RBAC service through a setter and may run
+// without one in tests; these helpers treat a missing RBAC service as no
+// restriction.
+
+func visibleSceneIDs(rbac *RBACService, role string, sceneIDs []uint) ([]uint, error) {
+	if rbac == nil {
+		return sceneIDs, nil
+	}
+	return rbac.FilterVisibleSceneIDs(role, sceneIDs)
+}
+
+func visibleScenes(rbac *RBACService, role string, scenes []data.Scene) ([]data.Scene, error) {
+	if rbac == nil {
+		return scenes, nil
+	}
+	return rbac.FilterVisibleScenes(role, scenes)
+}
+
+func restrictionFor(rbac *RBACService, role string) *data.ContentRestriction {
+	if rbac == nil {
+		return nil
+	}
+	return rbac.ContentRestriction(role)
+}
+
+// GetContentRestriction returns a role's restriction. Roles without one get an
+// empty restriction.
+func (s *RBACService) GetContentRestriction(roleID uint) (*data.ContentRestriction, error) {
+	if s.restrictionRepo == nil {
+		return nil, apperrors.NewInternalError("content restrictions are not configured", nil)
+	}
+	role, err := s.getRole(roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	restriction, err := s.restrictionRepo.GetByRoleID(roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return emptyContentRestriction(role), nil
+		}
+		return nil, apperrors.NewInternalError("failed to get content restriction", err)
+	}
+	restriction.RoleName = role.Name
+	return restriction, nil
+}
+
+// SetContentRestriction replaces a role's restriction. Empty lists lift the
+// restriction on that dimension; all empty lifts it entirely.
+func (s *RBACService) SetContentRestriction(roleID uint, storagePathIDs, tagIDs []uint, sceneTypes []string) (*data.ContentRestriction, error) {
+	if s.restrictionRepo == nil {
+		return nil, apperrors.NewInternalError("content restrictions are not configured", nil)
+	}
+	role, err := s.getRole(roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == unrestrictedRole {
+		return nil, apperrors.NewValidationError("the admin role cannot be restricted")
+	}
+	for _, sceneType := range sceneTypes {
+		if !data.IsValidSceneType(sceneType) {
+			return nil, apperrors.NewValidationErrorWithField("scene_types", fmt.Sprintf("invalid scene type %q", sceneType))
+		}
+	}
+
+	restriction := emptyContentRestriction(role)
+	restriction.StoragePathIDs = uniqueInt64s(storagePathIDs)
+	restriction.TagIDs = uniqueInt64s(tagIDs)
+	restriction.SceneTypes = uniqueStrings(sceneTypes)
+	restriction.UpdatedAt = time.Now()
+
+	if restriction.IsEmpty() {
+		err = s.restrictionRepo.Delete(roleID)
+	} else {
+		err = s.restrictionRepo.Upsert(restriction)
+	}
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to save content restriction", err)
+	}
+	if err := s.refreshRestrictions(); err != nil {
+		return nil, apperrors.NewInternalError("failed to reload content restrictions", err)
+	}
+
+	s.logger.Info("Content restriction updated",
+		zap.String("role", role.Name),
+		zap.Int("storage_paths", len(restriction.StoragePathIDs)),
+		zap.Int("tags", len(restriction.TagIDs)),
+		zap.Strings("scene_types", restriction.SceneTypes),
+	)
+	return restriction, nil
+}
+
+func (s *RBACService) getRole(roleID uint) (*data.Role, error) {
+	role, err := s.roleRepo.GetByID(roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("role", roleID)
+		}
+		return nil, apperrors.NewInternalError("failed to get role", err)
+	}
+	return role, nil
+}
+
+func emptyContentRestriction(role *data.Role) *data.ContentRestriction {
+	return &data.ContentRestriction{
+		RoleID:         role.ID,
+		RoleName:       role.Name,
+		StoragePathIDs: []int64{},
+		TagIDs:         []int64{},
+		SceneTypes:     []string{},
+	}
+}
+
+func uniqueInt64s(ids []uint) []int64 {
+	out := make([]int64, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, int64(id))
+		}
+	}
+	return out
+}
+
+func uniqueStrings(values []string) []string {
+	out := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func newTestRestrictedRBACService(t *testing.T, restrictions []data.ContentRestriction) (*RBACService, *mocks.MockRoleRepository, *mocks.MockContentRestrictionRepository) {
+	svc, roleRepo, _ := newTestRBACService(t, map[string][]string{"guest": {"scenes:view"}})
+	restrictionRepo := mocks.NewMockContentRestrictionRepository(gomock.NewController(t))
+	restrictionRepo.EXPECT().ListAll().Return(restrictions, nil)
+	if err := svc.SetContentRestrictionRepository(restrictionRepo); err != nil {
+		t.Fatalf("failed to load content restrictions: %v", err)
+	}
+	return svc, roleRepo, restrictionRepo
+}
+
+func TestContentRestriction_Lookup(t *testing.T) {
+	svc, _, _ := newTestRestrictedRBACService(t, []data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", SceneTypes: []string{"standard"}},
+		{RoleID: 3, RoleName: "user"},
+		{RoleID: 1, RoleName: "admin", TagIDs: []int64{4}},
+	})
+
+	if r := svc.ContentRestriction("guest"); r == nil || r.SceneTypes[0] != "standard" {
+		t.Fatalf("expected guest to be restricted, got %+v", r)
+	}
+	if r := svc.ContentRestriction("user"); r != nil {
+		t.Fatalf("expected an empty restriction to be ignored, got %+v", r)
+	}
+	if r := svc.ContentRestriction("admin"); r != nil {
+		t.Fatalf("expected admin to never be restricted, got %+v", r)
+	}
+}
+
+func TestCanAccessScene(t *testing.T) {
+	svc, _, restrictionRepo := newTestRestrictedRBACService(t, []data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", StoragePathIDs: []int64{1}},
+	})
+	restrictionRepo.EXPECT().IsSceneVisible(gomock.Any(), uint(9)).Return(false, nil)
+
+	if ok, err := svc.CanAccessScene("guest", 9); err != nil || ok {
+		t.Fatalf("expected guest to be refused scene 9, got %v, %v", ok, err)
+	}
+	// Unrestricted roles don't query the database
+	if ok, err := svc.CanAccessScene("user", 9); err != nil || !ok {
+		t.Fatalf("expected user to see scene 9, got %v, %v", ok, err)
+	}
+}
+
+func TestCanAccessScene_Anonymous(t *testing.T) {
+	svc, _, _ := newTestRestrictedRBACService(t, nil)
+	if ok, err := svc.CanAccessScene("", 9); err != nil || !ok {
+		t.Fatalf("expected anonymous access without restrictions, got %v, %v", ok, err)
+	}
+
+	// Once any role is restricted, requests without a user see nothing
+	svc, _, _ = newTestRestrictedRBACService(t, []data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", StoragePathIDs: []int64{1}},
+	})
+	if ok, err := svc.CanAccessScene("", 9); err != nil || ok {
+		t.Fatalf("expected anonymous access to be refused, got %v, %v", ok, err)
+	}
+	if ids, err := svc.FilterVisibleSceneIDs("", []uint{9}); err != nil || len(ids) != 0 {
+		t.Fatalf("expected no visible scenes for anonymous requests, got %v, %v", ids, err)
+	}
+}
+
+func TestFilterVisibleScenes(t *testing.T) {
+	svc, _, restrictionRepo := newTestRestrictedRBACService(t, []data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", TagIDs: []int64{4}},
+	})
+	restrictionRepo.EXPECT().FilterVisibleSceneIDs(gomock.Any(), []uint{5, 3, 8}).Return([]uint{3, 5}, nil)
+
+	scenes, err := svc.FilterVisibleScenes("guest", []data.Scene{{ID: 5}, {ID: 3}, {ID: 8}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scenes) != 2 || scenes[0].ID != 5 || scenes[1].ID != 3 {
+		t.Fatalf("expected scenes 5 and 3 in their original order, got %+v", scenes)
+	}
+
+	// Unrestricted roles don't query the database
+	if scenes, err := svc.FilterVisibleScenes("user", []data.Scene{{ID: 8}}); err != nil || len(scenes) != 1 {
+		t.Fatalf("expected user to see scene 8, got %+v, %v", scenes, err)
+	}
+}
+
+func TestSetContentRestriction(t *testing.T) {
+	svc, roleRepo, restrictionRepo := newTestRestrictedRBACService(t, nil)
+	roleRepo.EXPECT().GetByID(uint(2)).Return(&data.Role{ID: 2, Name: "guest"}, nil).Times(2)
+
+	restrictionRepo.EXPECT().Upsert(gomock.Any()).DoAndReturn(func(r *data.ContentRestriction) error {
+		if !reflect.DeepEqual([]int64(r.TagIDs), []int64{5, 6}) || r.RoleID != 2 {
+			t.Fatalf("unexpected restriction %+v", r)
+		}
+		return nil
+	})
+	restrictionRepo.EXPECT().ListAll().Return([]data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", TagIDs: []int64{5, 6}},
+	}, nil)
+	if _, err := svc.SetContentRestriction(2, nil, []uint{5, 6, 5}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.ContentRestriction("guest") == nil {
+		t.Fatal("expected the new restriction to be cached")
+	}
+
+	// Clearing every list lifts the restriction
+	restrictionRepo.EXPECT().Delete(uint(2)).Return(nil)
+	restrictionRepo.EXPECT().ListAll().Return(nil, nil)
+	if _, err := svc.SetContentRestriction(2, nil, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.ContentRestriction("guest") != nil {
+		t.Fatal("expected the restriction to be lifted")
+	}
+}
+
+func TestSetContentRestriction_Rejected(t *testing.T) {
+	svc, roleRepo, _ := newTestRestrictedRBACService(t, nil)
+	roleRepo.EXPECT().GetByID(uint(1)).Return(&data.Role{ID: 1, Name: "admin"}, nil)
+	roleRepo.EXPECT().GetByID(uint(2)).Return(&data.Role{ID: 2, Name: "guest"}, nil)
+	roleRepo.EXPECT().GetByID(uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	if _, err := svc.SetContentRestriction(1, []uint{1}, nil, nil); !apperrors.IsValidation(err) {
+		t.Fatalf("expected admin role to be refused, got %v", err)
+	}
+	if _, err := svc.SetContentRestriction(2, nil, nil, []string{"bogus"}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected invalid scene type to be refused, got %v", err)
+	}
+	if _, err := svc.SetContentRestriction(9, []uint{1}, nil, nil); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected unknown role to be not found, got %v", err)
+	}
+}
+
+func TestSearchService_RestrictionLimitsResults(t *testing.T) {
+	svc, textRepo, sceneRepo := newTestBackendSearchService(t, SearchBackendPostgres)
+	restrictionRepo := mocks.NewMockContentRestrictionRepository(gomock.NewController(t))
+	svc.SetContentRestrictionRepository(restrictionRepo)
+
+	restriction := &data.ContentRestriction{RoleID: 2, StoragePathIDs: []int64{1}}
+	restrictionRepo.EXPECT().GetVisibleSceneIDs(restriction).Return([]uint{2, 3, 4}, nil)
+	textRepo.EXPECT().Search(gomock.Any()).DoAndReturn(func(q data.SceneTextQuery) ([]uint, int64, error) {
+		if !reflect.DeepEqual(q.SceneIDs, []uint{3, 4}) {
+			t.Fatalf("expected search to be limited to visible scenes, got %v", q.SceneIDs)
+		}
+		return []uint{3}, 1, nil
+	})
+	sceneRepo.EXPECT().GetByIDs([]uint{3}).Return([]data.Scene{{ID: 3}}, nil)
+
+	result, err := svc.SearchWithContext(context.Background(), data.SceneSearchParams{
+		Page: 1, Limit: 20, SceneIDs: []uint{1, 3, 4}, Restriction: restriction,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Total != 1 {
+		t.Fatalf("expected one result, got %d", result.Total)
+	}
+}
+
+func TestSceneService_GetSceneForRole_HidesRestrictedScenes(t *testing.T) {
+	rbac, _, restrictionRepo := newTestRestrictedRBACService(t, []data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", SceneTypes: []string{"standard"}},
+	})
+	sceneRepo := mocks.NewMockSceneRepository(gomock.NewController(t))
+	svc := &SceneService{Repo: sceneRepo}
+	svc.SetRBACService(rbac)
+
+	sceneRepo.EXPECT().GetByID(uint(5)).Return(&data.Scene{ID: 5, Type: "vr"}, nil)
+	restrictionRepo.EXPECT().IsSceneVisible(gomock.Any(), uint(5)).Return(false, nil)
+
+	if _, err := svc.GetSceneForRole(5, "guest"); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected restricted scene to be not found, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// DeletionGuard blocks trashing or deleting scenes that hold curated data: high
+// ratings, markers or playlist membership. It only applies to user-initiated
+// deletes of library scenes; emptying the trash is not guarded since trashed
+// scenes already passed the check.
+type DeletionGuard struct {
+	interactionRepo data.InteractionRepository
+	markerRepo      data.MarkerRepository
+	playlistRepo    data.PlaylistRepository
+	cfg             config.DeletionProtectionConfig
+	logger          *zap.Logger
+}
+
+func NewDeletionGuard(
+	interactionRepo data.InteractionRepository,
+	markerRepo data.MarkerRepository,
+	playlistRepo data.PlaylistRepository,
+	cfg config.DeletionProtectionConfig,
+	logger *zap.Logger,
+) *DeletionGuard {
+	return &DeletionGuard{
+		interactionRepo: interactionRepo,
+		markerRepo:      markerRepo,
+		playlistRepo:    playlistRepo,
+		cfg:             cfg,
+		logger:          logger,
+	}
+}
+
+// Check returns a SceneDeletionProtectedError listing every protected scene in
+// sceneIDs, or nil when deletion may proceed. force bypasses the guard only when
+// the config allows it.
+func (g *DeletionGuard) Check(sceneIDs []uint, force bool) error {
+	if g == nil || !g.cfg.Enabled || len(sceneIDs) == 0 {
+		return nil
+	}
+	if force && g.cfg.AllowForce {
+		return nil
+	}
+
+	reasons := make(map[uint][]string)
+
+	if g.cfg.MinRating > 0 {
+		ratings, err := g.interactionRepo.GetMaxRatingsBySceneIDs(sceneIDs)
+		if err != nil {
+			return apperrors.NewInternalError("failed to check scene ratings", err)
+		}
+		for id, rating := range ratings {
+			if rating >= g.cfg.MinRating {
+				reasons[id] = append(reasons[id], apperrors.DeletionReasonRated)
+			}
+		}
+	}
+
+	if g.cfg.ProtectMarkers {
+		counts, err := g.markerRepo.CountBySceneIDs(sceneIDs)
+		if err != nil {
+			return apperrors.NewInternalError("failed to check scene markers", err)
+		}
+		for id, count := range counts {
+			if count > 0 {
+				reasons[id] = append(reasons[id], apperrors.DeletionReasonMarkers)
+			}
+		}
+	}
+
+	if g.cfg.ProtectPlaylists {
+		counts, err := g.playlistRepo.CountPlaylistsBySceneIDs(sceneIDs)
+		if err != nil {
+			return apperrors.NewInternalError("failed to check scene playlists", err)
+		}
+		for id, count := range counts {
+			if count > 0 {
+				reasons[id] = append(reasons[id], apperrors.DeletionReasonPlaylist)
+			}
+		}
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	// Report in request order so responses are stable
+	protected := make([]apperrors.ProtectedScene, 0, len(reasons))
+	for _, id := range sceneIDs {
+		if r, ok := reasons[id]; ok {
+			protected = append(protected, apperrors.ProtectedScene{SceneID: id, Reasons: r})
+			delete(reasons, id)
+		}
+	}
+
+	g.logger.Info("Blocked deletion of protected scenes",
+		zap.Int("requested", len(sceneIDs)),
+		zap.Int("protected", len(protected)),
+		zap.Bool("force", force),
+	)
+	return apperrors.NewSceneDeletionProtectedError(protected, g.cfg.AllowForce)
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestDeletionGuard(t *testing.T, cfg config.DeletionProtectionConfig) (*DeletionGuard, *mocks.MockInteractionRepository, *mocks.MockMarkerRepository, *mocks.MockPlaylistRepository) {
+	ctrl := gomock.NewController(t)
+	interactionRepo := mocks.NewMockInteractionRepository(ctrl)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	playlistRepo := mocks.NewMockPlaylistRepository(ctrl)
+	return NewDeletionGuard(interactionRepo, markerRepo, playlistRepo, cfg, zap.NewNop()), interactionRepo, markerRepo, playlistRepo
+}
+
+var defaultDeletionProtection = config.DeletionProtectionConfig{
+	Enabled:          true,
+	MinRating:        4,
+	ProtectMarkers:   true,
+	ProtectPlaylists: true,
+	AllowForce:       true,
+}
+
+func TestDeletionGuard_ListsReasonsInRequestOrder(t *testing.T) {
+	guard, interactionRepo, markerRepo, playlistRepo := newTestDeletionGuard(t, defaultDeletionProtection)
+	ids := []uint{3, 1, 2, 4}
+
+	interactionRepo.EXPECT().GetMaxRatingsBySceneIDs(ids).Return(map[uint]float64{1: 4.5, 2: 3}, nil)
+	markerRepo.EXPECT().CountBySceneIDs(ids).Return(map[uint]int64{1: 2, 3: 1}, nil)
+	playlistRepo.EXPECT().CountPlaylistsBySceneIDs(ids).Return(map[uint]int64{}, nil)
+
+	err := guard.Check(ids, false)
+
+	var protectedErr *apperrors.SceneDeletionProtectedError
+	if !errors.As(err, &protectedErr) {
+		t.Fatalf("expected SceneDeletionProtectedError, got %v", err)
+	}
+	if !protectedErr.ForceAllowed {
+		t.Fatal("expected force to be allowed")
+	}
+	if len(protectedErr.Scenes) != 2 {
+		t.Fatalf("expected 2 protected scenes, got %+v", protectedErr.Scenes)
+	}
+	if protectedErr.Scenes[0].SceneID != 3 || protectedErr.Scenes[0].Reasons[0] != apperrors.DeletionReasonMarkers {
+		t.Fatalf("unexpected first protected scene: %+v", protectedErr.Scenes[0])
+	}
+	second := protectedErr.Scenes[1]
+	if second.SceneID != 1 || len(second.Reasons) != 2 ||
+		second.Reasons[0] != apperrors.DeletionReasonRated || second.Reasons[1] != apperrors.DeletionReasonMarkers {
+		t.Fatalf("unexpected second protected scene: %+v", second)
+	}
+}
+
+func TestDeletionGuard_UnprotectedScenesPass(t *testing.T) {
+	guard, interactionRepo, markerRepo, playlistRepo := newTestDeletionGuard(t, defaultDeletionProtection)
+	ids := []uint{1}
+
+	interactionRepo.EXPECT().GetMaxRatingsBySceneIDs(ids).Return(map[uint]float64{1: 2}, nil)
+	markerRepo.EXPECT().CountBySceneIDs(ids).Return(map[uint]int64{}, nil)
+	playlistRepo.EXPECT().CountPlaylistsBySceneIDs(ids).Return(map[uint]int64{}, nil)
+
+	if err := guard.Check(ids, false); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestDeletionGuard_ForceBypassesWhenAllowed(t *testing.T) {
+	guard, _, _, _ := newTestDeletionGuard(t, defaultDeletionProtection)
+
+	if err := guard.Check([]uint{1}, true); err != nil {
+		t.Fatalf("expected force to bypass guard, got %v", err)
+	}
+}
+
+func TestDeletionGuard_ForceIgnoredWhenNotAllowed(t *testing.T) {
+	cfg := defaultDeletionProtection
+	cfg.AllowForce = false
+	cfg.MinRating = 0
+	cfg.ProtectMarkers = false
+	guard, _, _, playlistRepo := newTestDeletionGuard(t, cfg)
+	ids := []uint{1}
+
+	playlistRepo.EXPECT().CountPlaylistsBySceneIDs(ids).Return(map[uint]int64{1: 1}, nil)
+
+	err := guard.Check(ids, true)
+	if !apperrors.IsSceneDeletionProtected(err) {
+		t.Fatalf("expected protected error, got %v", err)
+	}
+	if apperrors.GetCode(err) != "SCENE_DELETION_BLOCKED" {
+		t.Fatalf("expected SCENE_DELETION_BLOCKED, got %s", apperrors.GetCode(err))
+	}
+}
+
+func TestDeletionGuard_DisabledOrNil(t *testing.T) {
+	cfg := defaultDeletionProtection
+	cfg.Enabled = false
+	guard, _, _, _ := newTestDeletionGuard(t, cfg)
+
+	if err := guard.Check([]uint{1}, false); err != nil {
+		t.Fatalf("expected disabled guard to pass, got %v", err)
+	}
+
+	var nilGuard *DeletionGuard
+	if err := nilGuard.Check([]uint{1}, false); err != nil {
+		t.Fatalf("expected nil guard to pass, got %v", err)
+	}
+}
+
+func TestDeletionGuard_RepositoryError(t *testing.T) {
+	guard, interactionRepo, _, _ := newTestDeletionGuard(t, defaultDeletionProtection)
+
+	interactionRepo.EXPECT().GetMaxRatingsBySceneIDs(gomock.Any()).Return(nil, errors.New("db down"))
+
+	if err := guard.Check([]uint{1}, false); !apperrors.IsInternal(err) {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+}
@@ -2,12 +2,34 @@ package core
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
 	"goonhub/internal/data"
 
 	"go.uber.org/zap"
 )
 
+// dlqBulkLimit caps the entries requeued by one bulk request
+const dlqBulkLimit = 1000
+
+// DLQBulkFilter selects the DLQ entries of a bulk requeue or purge.
+type DLQBulkFilter struct {
+	Status        string `json:"status"`
+	Phase         string `json:"phase"`
+	ErrorContains string `json:"error_contains"`
+	OlderThan     string `json:"older_than"` // e.g. "7d", "12h"
+}
+
+// DLQBulkResult reports a bulk requeue.
+type DLQBulkResult struct {
+	Matched  int               `json:"matched"`
+	Requeued int               `json:"requeued"`
+	Failed   map[string]string `json:"failed"` // job ID -> error
+}
+
 // DLQService manages the dead letter queue.
 type DLQService struct {
 	dlqRepo           data.DLQRepository
@@ -133,6 +155,84 @@ func (s *DLQService) Abandon(jobID string) error {
 	return nil
 }
 
+// RequeueMatching resubmits the entries matching the filter, oldest first,
+// up to dlqBulkLimit at a time. Entries already being retried are skipped.
+func (s *DLQService) RequeueMatching(bulk DLQBulkFilter) (*DLQBulkResult, error) {
+	if s.processingService == nil {
+		return nil, apperrors.NewInternalError("processing service not configured", nil)
+	}
+	filter, err := parseDLQBulkFilter(bulk)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.dlqRepo.ListByFilter(filter, dlqBulkLimit)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list DLQ entries", err)
+	}
+
+	result := &DLQBulkResult{Failed: map[string]string{}}
+	for _, entry := range entries {
+		if entry.Status == "retrying" {
+			continue
+		}
+		result.Matched++
+		if err := s.RetryFromDLQ(entry.JobID); err != nil {
+			result.Failed[entry.JobID] = err.Error()
+			continue
+		}
+		result.Requeued++
+	}
+
+	s.logger.Info("Requeued DLQ entries",
+		zap.Int("matched", result.Matched),
+		zap.Int("requeued", result.Requeued),
+		zap.Int("failed", len(result.Failed)),
+	)
+	return result, nil
+}
+
+// PurgeMatching deletes the entries matching the filter. At least one
+// criterion is required, so an empty filter can't wipe the queue.
+func (s *DLQService) PurgeMatching(bulk DLQBulkFilter) (int64, error) {
+	filter, err := parseDLQBulkFilter(bulk)
+	if err != nil {
+		return 0, err
+	}
+	if filter == (data.DLQFilter{}) {
+		return 0, apperrors.NewValidationError("at least one of status, phase, error_contains or older_than is required")
+	}
+
+	purged, err := s.dlqRepo.DeleteByFilter(filter)
+	if err != nil {
+		return 0, apperrors.NewInternalError("failed to purge DLQ entries", err)
+	}
+
+	s.logger.Info("Purged DLQ entries", zap.Int64("count", purged))
+	return purged, nil
+}
+
+func parseDLQBulkFilter(bulk DLQBulkFilter) (data.DLQFilter, error) {
+	filter := data.DLQFilter{
+		Status:        bulk.Status,
+		Phase:         bulk.Phase,
+		ErrorContains: strings.TrimSpace(bulk.ErrorContains),
+	}
+	switch filter.Status {
+	case "", "pending_review", "retrying", "abandoned":
+	default:
+		return data.DLQFilter{}, apperrors.NewValidationErrorWithField("status", "must be pending_review, retrying or abandoned")
+	}
+	if bulk.OlderThan != "" {
+		age, err := config.ParseRetentionDuration(bulk.OlderThan)
+		if err != nil || age <= 0 {
+			return data.DLQFilter{}, apperrors.NewValidationErrorWithField("older_than", "must be a positive duration like 7d or 12h")
+		}
+		filter.CreatedBefore = time.Now().Add(-age)
+	}
+	return filter, nil
+}
+
 // GetStats returns counts of DLQ entries by status.
 func (s *DLQService) GetStats() (map[string]int64, error) {
 	stats := make(map[string]int64)
@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"goonhub/internal/apperrors"
 	"goonhub/internal/data"
 	"goonhub/internal/mocks"
 	"testing"
@@ -155,3 +156,48 @@ func TestDLQService_RetryFromDLQ_NoProcessingService(t *testing.T) {
 		t.Fatal("expected error when processing service not configured")
 	}
 }
+
+func TestDLQService_RequeueMatching_NoProcessingService(t *testing.T) {
+	svc, _, _, _ := newTestDLQService(t)
+
+	if _, err := svc.RequeueMatching(DLQBulkFilter{Phase: "metadata"}); err == nil {
+		t.Fatal("expected error when processing service not configured")
+	}
+}
+
+func TestDLQService_PurgeMatching(t *testing.T) {
+	svc, dlqRepo, _, _ := newTestDLQService(t)
+
+	dlqRepo.EXPECT().DeleteByFilter(gomock.Any()).DoAndReturn(func(filter data.DLQFilter) (int64, error) {
+		if filter.Phase != "sprites" || filter.ErrorContains != "timeout" {
+			t.Fatalf("unexpected filter %+v", filter)
+		}
+		if age := time.Since(filter.CreatedBefore); age < 48*time.Hour || age > 49*time.Hour {
+			t.Fatalf("expected entries older than 2 days, got cutoff %v", filter.CreatedBefore)
+		}
+		return 4, nil
+	})
+
+	purged, err := svc.PurgeMatching(DLQBulkFilter{Phase: "sprites", ErrorContains: " timeout ", OlderThan: "2d"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if purged != 4 {
+		t.Fatalf("expected 4 purged, got %d", purged)
+	}
+}
+
+func TestDLQService_PurgeMatching_RejectsFilter(t *testing.T) {
+	svc, _, _, _ := newTestDLQService(t)
+
+	tests := map[string]DLQBulkFilter{
+		"empty":              {},
+		"unknown status":     {Status: "done"},
+		"invalid older_than": {OlderThan: "soon"},
+	}
+	for name, filter := range tests {
+		if _, err := svc.PurgeMatching(filter); !apperrors.IsValidation(err) {
+			t.Fatalf("%s: expected a validation error, got %v", name, err)
+		}
+	}
+}
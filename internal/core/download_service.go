@@ -0,0 +1,167 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MaxDownloadBundleScenes caps how many scenes one zip bundle may contain
+const MaxDownloadBundleScenes = 50
+
+// DownloadEntry is a downloaded scene with the user's download counter
+type DownloadEntry struct {
+	Download data.UserSceneDownload `json:"download"`
+	Scene    *data.Scene            `json:"scene,omitempty"`
+}
+
+// DownloadService resolves scenes for original-file downloads and keeps per-user
+// download counters.
+type DownloadService struct {
+	sceneRepo    data.SceneRepository
+	downloadRepo data.DownloadRepository
+	logger       *zap.Logger
+}
+
+func NewDownloadService(sceneRepo data.SceneRepository, downloadRepo data.DownloadRepository, logger *zap.Logger) *DownloadService {
+	return &DownloadService{
+		sceneRepo:    sceneRepo,
+		downloadRepo: downloadRepo,
+		logger:       logger,
+	}
+}
+
+// GetDownloadableScene returns a scene whose original file exists on disk.
+// Trashed scenes cannot be downloaded.
+func (s *DownloadService) GetDownloadableScene(sceneID uint) (*data.Scene, error) {
+	scene, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrSceneNotFound(sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+	if _, err := os.Stat(scene.StoredPath); err != nil {
+		return nil, apperrors.NewNotFoundErrorWithCause("scene file", sceneID, err)
+	}
+	return scene, nil
+}
+
+// GetBundleScenes returns the scenes for a zip bundle in the requested order,
+// ignoring duplicate IDs. Every scene must exist and have its file on disk.
+func (s *DownloadService) GetBundleScenes(sceneIDs []uint) ([]data.Scene, error) {
+	ids := make([]uint, 0, len(sceneIDs))
+	seen := make(map[uint]bool, len(sceneIDs))
+	for _, id := range sceneIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, apperrors.NewValidationErrorWithField("ids", "at least one scene ID is required")
+	}
+	if len(ids) > MaxDownloadBundleScenes {
+		return nil, apperrors.NewValidationErrorWithField("ids", fmt.Sprintf("at most %d scenes can be bundled", MaxDownloadBundleScenes))
+	}
+
+	scenes, err := s.sceneRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get scenes", err)
+	}
+
+	found := make(map[uint]bool, len(scenes))
+	for _, scene := range scenes {
+		found[scene.ID] = true
+		if _, err := os.Stat(scene.StoredPath); err != nil {
+			return nil, apperrors.NewNotFoundErrorWithCause("scene file", scene.ID, err)
+		}
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, apperrors.ErrSceneNotFound(id)
+		}
+	}
+	return scenes, nil
+}
+
+// RecordDownload counts a download of the given scenes for the user. Failures are
+// logged only; the file has already been sent by the time this is called.
+func (s *DownloadService) RecordDownload(userID uint, sceneIDs ...uint) {
+	if err := s.downloadRepo.RecordDownloads(userID, sceneIDs); err != nil {
+		s.logger.Warn("Failed to record scene download",
+			zap.Uint("user_id", userID),
+			zap.Uints("scene_ids", sceneIDs),
+			zap.Error(err),
+		)
+	}
+}
+
+// ListUserDownloads returns the user's downloads with scene details, most recent first
+func (s *DownloadService) ListUserDownloads(userID uint, page, limit int) ([]DownloadEntry, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	downloads, total, err := s.downloadRepo.ListUserDownloads(userID, page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list downloads", err)
+	}
+
+	sceneIDs := make([]uint, len(downloads))
+	for i, d := range downloads {
+		sceneIDs[i] = d.SceneID
+	}
+
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	if err != nil {
+		s.logger.Warn("Failed to fetch scenes for downloads", zap.Error(err))
+		scenes = nil
+	}
+	sceneMap := make(map[uint]*data.Scene, len(scenes))
+	for i := range scenes {
+		sceneMap[scenes[i].ID] = &scenes[i]
+	}
+
+	entries := make([]DownloadEntry, len(downloads))
+	for i, d := range downloads {
+		entries[i] = DownloadEntry{Download: d, Scene: sceneMap[d.SceneID]}
+	}
+	return entries, total, nil
+}
+
+// DownloadFilename returns the name a scene's file is saved as: the original
+// upload filename, or the title with the stored file's extension.
+func DownloadFilename(scene *data.Scene) string {
+	name := filepath.Base(scene.OriginalFilename)
+	if scene.OriginalFilename == "" || name == "." || name == string(filepath.Separator) {
+		title := strings.TrimSpace(scene.Title)
+		if title == "" {
+			title = fmt.Sprintf("scene-%d", scene.ID)
+		}
+		name = title + filepath.Ext(scene.StoredPath)
+	}
+	// Keep the name safe for Content-Disposition and zip entries
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', '"', ':', '*', '?', '<', '>', '|':
+			return '_'
+		}
+		if r < 0x20 {
+			return -1
+		}
+		return r
+	}, name)
+}
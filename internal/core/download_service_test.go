@@ -0,0 +1,108 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestDownloadService(t *testing.T) (*DownloadService, *mocks.MockSceneRepository, *mocks.MockDownloadRepository) {
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	downloadRepo := mocks.NewMockDownloadRepository(ctrl)
+	return NewDownloadService(sceneRepo, downloadRepo, zap.NewNop()), sceneRepo, downloadRepo
+}
+
+func writeTempVideo(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte("video"), 0o644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	return path
+}
+
+func TestGetDownloadableScene(t *testing.T) {
+	svc, sceneRepo, _ := newTestDownloadService(t)
+	path := writeTempVideo(t, "a.mp4")
+
+	sceneRepo.EXPECT().GetByID(uint(1)).Return(&data.Scene{ID: 1, StoredPath: path}, nil)
+	if _, err := svc.GetDownloadableScene(1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	sceneRepo.EXPECT().GetByID(uint(2)).Return(nil, gorm.ErrRecordNotFound)
+	if _, err := svc.GetDownloadableScene(2); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found for missing scene, got %v", err)
+	}
+
+	sceneRepo.EXPECT().GetByID(uint(3)).Return(&data.Scene{ID: 3, StoredPath: filepath.Join(t.TempDir(), "gone.mp4")}, nil)
+	if _, err := svc.GetDownloadableScene(3); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found for missing file, got %v", err)
+	}
+}
+
+func TestGetBundleScenes(t *testing.T) {
+	svc, sceneRepo, _ := newTestDownloadService(t)
+	path := writeTempVideo(t, "a.mp4")
+
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{
+		{ID: 1, StoredPath: path},
+		{ID: 2, StoredPath: path},
+	}, nil)
+
+	scenes, err := svc.GetBundleScenes([]uint{1, 2, 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(scenes) != 2 {
+		t.Fatalf("expected duplicates to be dropped, got %d scenes", len(scenes))
+	}
+}
+
+func TestGetBundleScenes_Validation(t *testing.T) {
+	svc, sceneRepo, _ := newTestDownloadService(t)
+
+	if _, err := svc.GetBundleScenes(nil); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for empty bundle, got %v", err)
+	}
+
+	tooMany := make([]uint, MaxDownloadBundleScenes+1)
+	for i := range tooMany {
+		tooMany[i] = uint(i + 1)
+	}
+	if _, err := svc.GetBundleScenes(tooMany); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for oversized bundle, got %v", err)
+	}
+
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{{ID: 1, StoredPath: writeTempVideo(t, "a.mp4")}}, nil)
+	if _, err := svc.GetBundleScenes([]uint{1, 2}); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found when a scene is missing, got %v", err)
+	}
+}
+
+func TestDownloadFilename(t *testing.T) {
+	tests := []struct {
+		scene    data.Scene
+		expected string
+	}{
+		{data.Scene{ID: 1, OriginalFilename: "holiday.mkv", StoredPath: "/v/x.mkv"}, "holiday.mkv"},
+		{data.Scene{ID: 2, OriginalFilename: "../../etc/passwd", StoredPath: "/v/x.mp4"}, "passwd"},
+		{data.Scene{ID: 3, Title: `A "quoted": title`, StoredPath: "/v/x.mp4"}, "A _quoted__ title.mp4"},
+		{data.Scene{ID: 4, StoredPath: "/v/x.webm"}, "scene-4.webm"},
+	}
+
+	for _, tt := range tests {
+		if got := DownloadFilename(&tt.scene); got != tt.expected {
+			t.Fatalf("DownloadFilename(%+v) = %q, want %q", tt.scene, got, tt.expected)
+		}
+	}
+}
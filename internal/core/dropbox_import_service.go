@@ -0,0 +1,392 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxCollisionSuffix bounds the " (n)" suffixes tried for a taken destination
+	maxCollisionSuffix = 100
+	// maxPathSegmentBytes keeps rendered names under the usual 255 byte limit,
+	// with room for a suffix and the extension
+	maxPathSegmentBytes = 200
+)
+
+var importTemplatePlaceholder = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// DropboxImportedFile is a dropbox file moved into the library.
+type DropboxImportedFile struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Error       string `json:"error,omitempty"` // moved, but creating the scene failed; the next scan retries
+}
+
+// DropboxFileError is a dropbox file left where it is.
+type DropboxFileError struct {
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+// DropboxImportResult reports one pass over the dropbox. Files still being
+// copied are not listed.
+type DropboxImportResult struct {
+	Imported []DropboxImportedFile `json:"imported"`
+	Failed   []DropboxFileError    `json:"failed"`
+}
+
+// dropboxFileStamp identifies a version of a file, so a file that failed is
+// only retried once it changes.
+type dropboxFileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// DropboxImportService polls the dropbox directory for video files, moves
+// each one into the configured storage path at the place the template gives
+// it, and imports it through the scan logic: sidecar metadata, folder rules,
+// indexing and processing all apply as for any new file.
+type DropboxImportService struct {
+	cfg                config.DropboxConfig
+	storagePathService *StoragePathService
+	scanService        *ScanService
+	sidecars           *SidecarMetadataService
+	storageHealth      *StorageHealthService
+	logger             *zap.Logger
+
+	mu     sync.Mutex // one pass at a time
+	failed map[string]dropboxFileStamp
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewDropboxImportService(
+	cfg config.DropboxConfig,
+	storagePathService *StoragePathService,
+	scanService *ScanService,
+	sidecars *SidecarMetadataService,
+	logger *zap.Logger,
+) *DropboxImportService {
+	return &DropboxImportService{
+		cfg:                cfg,
+		storagePathService: storagePathService,
+		scanService:        scanService,
+		sidecars:           sidecars,
+		logger:             logger.With(zap.String("component", "dropbox_import")),
+		failed:             make(map[string]dropboxFileStamp),
+	}
+}
+
+// SetStorageHealth holds imports back while the destination storage path is offline
+func (s *DropboxImportService) SetStorageHealth(storageHealth *StorageHealthService) {
+	s.storageHealth = storageHealth
+}
+
+// Start polls the dropbox every poll interval. It is a no-op when disabled.
+func (s *DropboxImportService) Start() {
+	if !s.cfg.Enabled {
+		return
+	}
+	if err := os.MkdirAll(s.cfg.Path, 0755); err != nil {
+		s.logger.Error("Failed to create dropbox directory", zap.String("path", s.cfg.Path), zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Run(); err != nil {
+					if apperrors.IsConflict(err) {
+						s.logger.Debug("Dropbox import postponed", zap.Error(err))
+					} else {
+						s.logger.Warn("Dropbox import failed", zap.Error(err))
+					}
+				}
+			}
+		}
+	}()
+
+	s.logger.Info("Dropbox import started",
+		zap.String("path", s.cfg.Path),
+		zap.String("template", s.cfg.Template),
+		zap.Duration("poll_interval", s.cfg.PollInterval),
+	)
+}
+
+// Stop halts polling. A pass in progress finishes first.
+func (s *DropboxImportService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// Run imports the settled video files in the dropbox now.
+func (s *DropboxImportService) Run() (*DropboxImportResult, error) {
+	if !s.cfg.Enabled {
+		return nil, apperrors.NewValidationError("dropbox import is not enabled")
+	}
+	// Like the storage watcher, leave files alone while a scan runs
+	if s.scanService.IsRunning() {
+		return nil, apperrors.NewConflictError("dropbox import", "a scan is running")
+	}
+	if !s.mu.TryLock() {
+		return nil, apperrors.NewConflictError("dropbox import", "an import is already running")
+	}
+	defer s.mu.Unlock()
+
+	storagePath, err := s.destination()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.settledFiles(time.Now())
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to read dropbox", err)
+	}
+
+	result := &DropboxImportResult{Imported: []DropboxImportedFile{}, Failed: []DropboxFileError{}}
+	for _, file := range files {
+		dest, err := s.organizeFile(file, storagePath)
+		if err != nil {
+			s.logger.Warn("Failed to move dropbox file", zap.String("path", file), zap.Error(err))
+			s.rememberFailed(file)
+			result.Failed = append(result.Failed, DropboxFileError{Source: file, Error: err.Error()})
+			continue
+		}
+
+		imported := DropboxImportedFile{Source: file, Destination: dest}
+		if err := s.scanService.ImportFile(dest, storagePath); err != nil {
+			s.logger.Warn("Failed to import dropbox file", zap.String("path", dest), zap.Error(err))
+			imported.Error = err.Error()
+		} else {
+			s.logger.Info("Imported dropbox file", zap.String("source", file), zap.String("path", dest))
+		}
+		result.Imported = append(result.Imported, imported)
+	}
+	return result, nil
+}
+
+// destination returns the storage path files are imported into, checking
+// that it is usable and that the dropbox is not inside a storage path, where
+// scans would import files before they are organized.
+func (s *DropboxImportService) destination() (*data.StoragePath, error) {
+	paths, err := s.storagePathService.List()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list storage paths", err)
+	}
+	dropbox := filepath.Clean(s.cfg.Path)
+
+	var dest *data.StoragePath
+	for i := range paths {
+		root := filepath.Clean(paths[i].Path)
+		if isWithin(dropbox, root) || isWithin(root, dropbox) {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("dropbox %s overlaps storage path %s", dropbox, root))
+		}
+		if (s.cfg.StoragePathID == 0 && paths[i].IsDefault) || paths[i].ID == s.cfg.StoragePathID {
+			dest = &paths[i]
+		}
+	}
+	if dest == nil {
+		if s.cfg.StoragePathID == 0 {
+			return nil, apperrors.NewValidationError("no default storage path to import into")
+		}
+		return nil, apperrors.NewNotFoundError("storage path", s.cfg.StoragePathID)
+	}
+	if s.storageHealth != nil && s.storageHealth.IsOffline(dest.ID) {
+		return nil, apperrors.NewConflictError("dropbox import", fmt.Sprintf("storage path %s is offline", dest.Name))
+	}
+	return dest, nil
+}
+
+// settledFiles returns the video files in the dropbox, including subfolders,
+// that have not been modified for the settle time. Hidden files and folders
+// are skipped, as are files that failed before and have not changed since.
+func (s *DropboxImportService) settledFiles(now time.Time) ([]string, error) {
+	var files []string
+	seen := make(map[string]struct{})
+	err := filepath.WalkDir(s.cfg.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == s.cfg.Path {
+				return err
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != s.cfg.Path {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !isVideoExtension(strings.ToLower(filepath.Ext(path))) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || now.Sub(info.ModTime()) < s.cfg.SettleTime {
+			return nil
+		}
+		seen[path] = struct{}{}
+		if stamp, ok := s.failed[path]; ok && stamp == (dropboxFileStamp{info.Size(), info.ModTime()}) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+
+	// Forget failures of files that are gone
+	for path := range s.failed {
+		if _, ok := seen[path]; !ok {
+			delete(s.failed, path)
+		}
+	}
+	return files, err
+}
+
+func (s *DropboxImportService) rememberFailed(path string) {
+	if info, err := os.Stat(path); err == nil {
+		s.failed[path] = dropboxFileStamp{info.Size(), info.ModTime()}
+	}
+}
+
+// organizeFile moves a file and its sidecars to where the template places it
+// in the storage path, and returns its new path.
+func (s *DropboxImportService) organizeFile(src string, storagePath *data.StoragePath) (string, error) {
+	var meta *SidecarMetadata
+	if s.sidecars.Enabled() {
+		var err error
+		if meta, err = s.sidecars.Read(src); err != nil {
+			s.logger.Warn("Failed to read sidecar metadata", zap.String("path", src), zap.Error(err))
+		}
+	}
+
+	rel := renderImportTemplate(s.cfg.Template, importTemplateValues(src, meta))
+	dest := filepath.Join(storagePath.Path, rel+filepath.Ext(src))
+	if _, err := os.Lstat(dest); err == nil {
+		if s.cfg.OnCollision != config.DropboxCollisionRename {
+			return "", errDestinationExists
+		}
+		if dest = availablePath(dest); dest == "" {
+			return "", errDestinationExists
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create destination folder: %w", err)
+	}
+	if err := moveFile(src, dest); err != nil {
+		return "", err
+	}
+
+	srcBase := strings.TrimSuffix(src, filepath.Ext(src))
+	destBase := strings.TrimSuffix(dest, filepath.Ext(dest))
+	for _, ext := range sidecarExtensions {
+		if _, err := os.Stat(srcBase + ext); err != nil {
+			continue
+		}
+		if err := moveFile(srcBase+ext, destBase+ext); err != nil {
+			s.logger.Warn("Failed to move sidecar file", zap.String("path", srcBase+ext), zap.Error(err))
+		}
+	}
+	return dest, nil
+}
+
+// importTemplateValues returns the placeholder values for a file: the title
+// falls back to the file name, the rest only come from its sidecar.
+func importTemplateValues(path string, meta *SidecarMetadata) map[string]string {
+	values := map[string]string{
+		"title": strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+	}
+	if meta == nil {
+		return values
+	}
+	if meta.Title != "" {
+		values["title"] = meta.Title
+	}
+	values["studio"] = meta.Studio
+	if meta.ReleaseDate != nil {
+		values["year"] = strconv.Itoa(meta.ReleaseDate.Year())
+	}
+	if len(meta.Actors) > 0 {
+		values["actor"] = meta.Actors[0]
+	}
+	return values
+}
+
+// renderImportTemplate fills a "/"-separated template, without extension,
+// into a path relative to the storage path. Values are made safe as file
+// names and folders that end up empty are dropped. An empty result falls back
+// to the title.
+func renderImportTemplate(template string, values map[string]string) string {
+	var segments []string
+	for _, segment := range strings.Split(template, "/") {
+		rendered := importTemplatePlaceholder.ReplaceAllStringFunc(segment, func(placeholder string) string {
+			return values[placeholder[1:len(placeholder)-1]]
+		})
+		if rendered = sanitizePathSegment(rendered); rendered != "" {
+			segments = append(segments, rendered)
+		}
+	}
+	if len(segments) == 0 {
+		if title := sanitizePathSegment(values["title"]); title != "" {
+			return title
+		}
+		return "untitled"
+	}
+	return filepath.Join(segments...)
+}
+
+// sanitizePathSegment removes the characters that are not allowed in file
+// names on common filesystems and the leading and trailing dots and spaces,
+// and shortens names that are too long.
+func sanitizePathSegment(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > maxPathSegmentBytes {
+		s = strings.ToValidUTF8(s[:maxPathSegmentBytes], "")
+	}
+	return strings.Trim(s, ". ")
+}
+
+// availablePath returns path with the first " (n)" suffix not taken yet, or
+// "" when none is free.
+func availablePath(path string) string {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for n := 2; n <= maxCollisionSuffix; n++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, n, filepath.Ext(path))
+		if _, err := os.Lstat(candidate); errors.Is(err, fs.ErrNotExist) {
+			return candidate
+		}
+	}
+	return ""
+}
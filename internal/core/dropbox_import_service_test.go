@@ -0,0 +1,112 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestRenderImportTemplate(t *testing.T) {
+	values := map[string]string{"title": "Scene: One?", "studio": "Studio/X", "year": ""}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{studio}/{year}/{title}", filepath.Join("StudioX", "Scene One")},
+		{"{studio} - {title}", "StudioX - Scene One"},
+		{"{year}/{actor}", "Scene One"},
+		{"", "Scene One"},
+		{"../{title}", "Scene One"},
+	}
+	for _, tt := range tests {
+		if got := renderImportTemplate(tt.template, values); got != tt.want {
+			t.Fatalf("%q: expected %q, got %q", tt.template, tt.want, got)
+		}
+	}
+}
+
+func TestDropboxImportService_Run(t *testing.T) {
+	dropbox := t.TempDir()
+	library := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	writeDropboxFile := func(rel string, modTime time.Time) string {
+		path := filepath.Join(dropbox, rel)
+		writeTestFile(t, path)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	writeDropboxFile("sub/new.mp4", old)
+	writeDropboxFile("sub/new.nfo", old)
+	writeDropboxFile("copying.mp4", time.Now())
+	writeDropboxFile(".hidden/skip.mp4", old)
+	taken := writeDropboxFile("taken.mkv", old)
+	writeTestFile(t, filepath.Join(library, "taken.mkv"))
+
+	ctrl := gomock.NewController(t)
+	storagePathRepo := mocks.NewMockStoragePathRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	storagePathService := NewStoragePathService(storagePathRepo, zap.NewNop())
+	scanService := NewScanService(storagePathService, sceneRepo, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	cfg := config.DropboxConfig{
+		Enabled:     true,
+		Path:        dropbox,
+		Template:    "{studio}/{title}",
+		OnCollision: config.DropboxCollisionSkip,
+		SettleTime:  time.Minute,
+	}
+	svc := NewDropboxImportService(cfg, storagePathService, scanService, nil, zap.NewNop())
+
+	storagePathRepo.EXPECT().List().Return([]data.StoragePath{{ID: 1, Path: library, IsDefault: true}}, nil).Times(2)
+	dest := filepath.Join(library, "new.mp4")
+	sceneRepo.EXPECT().ExistsByStoredPath(dest).Return(false, nil)
+	sceneRepo.EXPECT().GetBySizeAndFilename(gomock.Any(), "new.mp4").Return(nil, nil)
+	sceneRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+	result, err := svc.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Imported) != 1 || result.Imported[0].Destination != dest || result.Imported[0].Error != "" {
+		t.Fatalf("unexpected imports %+v", result.Imported)
+	}
+	if _, err := os.Stat(filepath.Join(library, "new.nfo")); err != nil {
+		t.Fatalf("expected the sidecar moved along: %v", err)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Source != taken {
+		t.Fatalf("expected the taken file left in the dropbox, got %+v", result.Failed)
+	}
+
+	// The skipped file is not retried until it changes
+	result, err = svc.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Imported) != 0 || len(result.Failed) != 0 {
+		t.Fatalf("expected nothing to do, got %+v", result)
+	}
+}
+
+func TestDropboxImportService_RejectsDropboxInStoragePath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storagePathRepo := mocks.NewMockStoragePathRepository(ctrl)
+	storagePathService := NewStoragePathService(storagePathRepo, zap.NewNop())
+	scanService := NewScanService(storagePathService, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	cfg := config.DropboxConfig{Enabled: true, Path: "/data/library/incoming", OnCollision: config.DropboxCollisionRename}
+	svc := NewDropboxImportService(cfg, storagePathService, scanService, nil, zap.NewNop())
+
+	storagePathRepo.EXPECT().List().Return([]data.StoragePath{{ID: 1, Path: "/data/library", IsDefault: true}}, nil)
+	if _, err := svc.Run(); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a dropbox inside a storage path to be rejected, got %v", err)
+	}
+}
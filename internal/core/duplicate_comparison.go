@@ -0,0 +1,171 @@
+package core
+
+import (
+	"errors"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"gorm.io/gorm"
+)
+
+// duplicateFramePositions are the points, as a fraction of each scene's duration,
+// sampled for side-by-side frame comparison.
+var duplicateFramePositions = []float64{0.1, 0.3, 0.5, 0.7, 0.9}
+
+// DuplicateComparisonScene is a group member with the details compared between copies.
+type DuplicateComparisonScene struct {
+	DuplicateSceneSummary
+	FrameRate     float64  `json:"frame_rate"`
+	BitRate       int64    `json:"bit_rate"`
+	AudioCodec    string   `json:"audio_codec"`
+	ThumbnailPath string   `json:"thumbnail_path"`
+	FavoredBy     []string `json:"favored_by"`
+}
+
+// DuplicateFrame is the time of one scene's frame in a comparison row.
+type DuplicateFrame struct {
+	SceneID   uint    `json:"scene_id"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// DuplicateFrameRow is a set of frames taken at the same relative position of each
+// member. Frames are fetched from GET /scenes/:id/frame?t=<timestamp>.
+type DuplicateFrameRow struct {
+	Position float64          `json:"position"`
+	Frames   []DuplicateFrame `json:"frames"`
+}
+
+// DuplicateKeepRule lists the members a keep-best rule favors (ties favor several).
+type DuplicateKeepRule struct {
+	Rule     string `json:"rule"`
+	SceneIDs []uint `json:"scene_ids"`
+}
+
+// DuplicateComparison is everything needed to compare the members of a duplicate group.
+type DuplicateComparison struct {
+	Group             data.DuplicateGroup        `json:"group"`
+	Scenes            []DuplicateComparisonScene `json:"scenes"`
+	Frames            []DuplicateFrameRow        `json:"frames"`
+	Rules             []DuplicateKeepRule        `json:"rules"`
+	SuggestedWinnerID uint                       `json:"suggested_winner_id"`
+}
+
+// duplicateKeepRules rank copies of the same content; a higher value is better.
+var duplicateKeepRules = []struct {
+	name  string
+	value func(s *data.Scene) float64
+}{
+	{"resolution", func(s *data.Scene) float64 { return float64(s.Width) * float64(s.Height) }},
+	{"bit_rate", func(s *data.Scene) float64 { return float64(s.BitRate) }},
+	{"frame_rate", func(s *data.Scene) float64 { return s.FrameRate }},
+	{"duration", func(s *data.Scene) float64 { return float64(s.Duration) }},
+	{"size", func(s *data.Scene) float64 { return float64(s.Size) }},
+}
+
+// CompareGroup returns a side-by-side comparison of a duplicate group's scenes that
+// are not in trash: their technical details, frames at matching relative positions
+// and the keep-best rules each one wins. The suggested winner wins the most rules,
+// ties going to the lowest scene ID.
+func (s *DuplicateService) CompareGroup(id uint) (*DuplicateComparison, error) {
+	group, err := s.duplicateRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("duplicate group", id)
+		}
+		return nil, apperrors.NewInternalError("failed to get duplicate group", err)
+	}
+
+	scenes, err := s.sceneRepo.GetByIDs(group.SceneIDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load duplicate scenes", err)
+	}
+	byID := make(map[uint]*data.Scene, len(scenes))
+	for i := range scenes {
+		byID[scenes[i].ID] = &scenes[i]
+	}
+	members := make([]*data.Scene, 0, len(scenes))
+	for _, sceneID := range group.SceneIDs {
+		if scene, ok := byID[sceneID]; ok {
+			members = append(members, scene)
+		}
+	}
+
+	comparison := &DuplicateComparison{
+		Group:  *group,
+		Scenes: make([]DuplicateComparisonScene, len(members)),
+		Frames: []DuplicateFrameRow{},
+		Rules:  make([]DuplicateKeepRule, 0, len(duplicateKeepRules)),
+	}
+	index := make(map[uint]int, len(members))
+	for i, scene := range members {
+		index[scene.ID] = i
+		comparison.Scenes[i] = DuplicateComparisonScene{
+			DuplicateSceneSummary: DuplicateSceneSummary{
+				ID:               scene.ID,
+				Title:            scene.Title,
+				OriginalFilename: scene.OriginalFilename,
+				Duration:         scene.Duration,
+				Size:             scene.Size,
+				Width:            scene.Width,
+				Height:           scene.Height,
+				VideoCodec:       scene.VideoCodec,
+			},
+			FrameRate:     scene.FrameRate,
+			BitRate:       scene.BitRate,
+			AudioCodec:    scene.AudioCodec,
+			ThumbnailPath: scene.ThumbnailPath,
+			FavoredBy:     []string{},
+		}
+	}
+
+	for _, position := range duplicateFramePositions {
+		row := DuplicateFrameRow{Position: position, Frames: []DuplicateFrame{}}
+		for _, scene := range members {
+			if scene.Duration <= 0 {
+				continue
+			}
+			row.Frames = append(row.Frames, DuplicateFrame{
+				SceneID:   scene.ID,
+				Timestamp: float64(int(position * float64(scene.Duration))),
+			})
+		}
+		if len(row.Frames) > 0 {
+			comparison.Frames = append(comparison.Frames, row)
+		}
+	}
+
+	wins := make([]int, len(members))
+	for _, rule := range duplicateKeepRules {
+		best := 0.0
+		for _, scene := range members {
+			if v := rule.value(scene); v > best {
+				best = v
+			}
+		}
+		favored := DuplicateKeepRule{Rule: rule.name, SceneIDs: []uint{}}
+		if best > 0 {
+			for _, scene := range members {
+				if rule.value(scene) == best {
+					favored.SceneIDs = append(favored.SceneIDs, scene.ID)
+					i := index[scene.ID]
+					wins[i]++
+					comparison.Scenes[i].FavoredBy = append(comparison.Scenes[i].FavoredBy, rule.name)
+				}
+			}
+		}
+		comparison.Rules = append(comparison.Rules, favored)
+	}
+
+	if len(members) > 0 {
+		winner := 0
+		for i := 1; i < len(members); i++ {
+			if wins[i] > wins[winner] {
+				winner = i
+			}
+		}
+		comparison.SuggestedWinnerID = members[winner].ID
+	}
+
+	return comparison, nil
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"gorm.io/gorm"
+)
+
+func TestCompareGroup(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+
+	duplicateRepo.EXPECT().GetByID(uint(7)).Return(&data.DuplicateGroup{ID: 7, SceneIDs: []uint{1, 2, 3}}, nil)
+	// Scene 3 is in trash and not returned
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2, 3}).Return([]data.Scene{
+		{ID: 2, Width: 1920, Height: 1080, BitRate: 4000, FrameRate: 30, Duration: 600, Size: 900},
+		{ID: 1, Width: 1280, Height: 720, BitRate: 6000, FrameRate: 30, Duration: 610, Size: 1000},
+	}, nil)
+
+	comparison, err := svc.CompareGroup(7)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(comparison.Scenes) != 2 || comparison.Scenes[0].ID != 1 {
+		t.Fatalf("expected members in group order, got %+v", comparison.Scenes)
+	}
+	if len(comparison.Frames) != len(duplicateFramePositions) {
+		t.Fatalf("expected %d frame rows, got %d", len(duplicateFramePositions), len(comparison.Frames))
+	}
+	if middle := comparison.Frames[2]; middle.Frames[0].Timestamp != 305 || middle.Frames[1].Timestamp != 300 {
+		t.Fatalf("expected frames at half of each duration, got %+v", middle)
+	}
+
+	favored := map[string][]uint{}
+	for _, rule := range comparison.Rules {
+		favored[rule.Rule] = rule.SceneIDs
+	}
+	if len(favored["resolution"]) != 1 || favored["resolution"][0] != 2 || len(favored["frame_rate"]) != 2 {
+		t.Fatalf("unexpected rules %+v", comparison.Rules)
+	}
+	// Scene 1 wins bit rate, duration and size; scene 2 only resolution (both tie on frame rate)
+	if comparison.SuggestedWinnerID != 1 {
+		t.Fatalf("expected scene 1 suggested, got %d", comparison.SuggestedWinnerID)
+	}
+}
+
+func TestCompareGroup_NotFound(t *testing.T) {
+	svc, duplicateRepo, _ := newTestDuplicateService(t)
+
+	duplicateRepo.EXPECT().GetByID(uint(99)).Return(nil, gorm.ErrRecordNotFound)
+
+	if _, err := svc.CompareGroup(99); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+}
@@ -0,0 +1,548 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DuplicateSceneSummary is the scene data shown when reviewing a duplicate group.
+type DuplicateSceneSummary struct {
+	ID               uint   `json:"id"`
+	Title            string `json:"title"`
+	OriginalFilename string `json:"original_filename"`
+	Duration         int    `json:"duration"`
+	Size             int64  `json:"size"`
+	Width            int    `json:"width"`
+	Height           int    `json:"height"`
+	VideoCodec       string `json:"video_codec"`
+}
+
+// DuplicateGroupDetails is a duplicate group with its member scenes.
+type DuplicateGroupDetails struct {
+	data.DuplicateGroup
+	Scenes []DuplicateSceneSummary `json:"scenes"`
+}
+
+// DuplicateResolution is the outcome of resolving a duplicate group.
+type DuplicateResolution struct {
+	GroupID         uint   `json:"group_id"`
+	WinnerSceneID   uint   `json:"winner_scene_id"`
+	Action          string `json:"action"`
+	TrashedSceneIDs []uint `json:"trashed_scene_ids"`
+}
+
+// SceneTrasher moves scenes to trash.
+type SceneTrasher interface {
+	MoveSceneToTrash(id uint, force bool) (*time.Time, error)
+}
+
+// DuplicateService flags scenes that are likely copies of the same content.
+// Flags are grouped for admin review; scenes are only merged or trashed when an
+// admin resolves a group with the merge action.
+type DuplicateService struct {
+	duplicateRepo data.DuplicateGroupRepository
+	sceneRepo     data.SceneRepository
+	trasher       SceneTrasher
+	indexer       SceneIndexer
+	eventBus      *EventBus
+	logger        *zap.Logger
+}
+
+func NewDuplicateService(
+	duplicateRepo data.DuplicateGroupRepository,
+	sceneRepo data.SceneRepository,
+	eventBus *EventBus,
+	logger *zap.Logger,
+) *DuplicateService {
+	return &DuplicateService{
+		duplicateRepo: duplicateRepo,
+		sceneRepo:     sceneRepo,
+		eventBus:      eventBus,
+		logger:        logger,
+	}
+}
+
+// SetSceneTrasher sets what merged scenes are moved to trash through.
+// This is called after service initialization to avoid circular dependencies.
+func (s *DuplicateService) SetSceneTrasher(trasher SceneTrasher) {
+	s.trasher = trasher
+}
+
+// SetIndexer sets the scene indexer for search index updates.
+func (s *DuplicateService) SetIndexer(indexer SceneIndexer) {
+	s.indexer = indexer
+}
+
+// FlagPornDBMatch groups every library scene matched to the same PornDB scene as the
+// given one into a pending duplicate group. Returns nil when the scene has no siblings.
+func (s *DuplicateService) FlagPornDBMatch(sceneID uint, porndbSceneID string) (*data.DuplicateGroup, error) {
+	if porndbSceneID == "" {
+		return nil, nil
+	}
+
+	sceneIDs, err := s.sceneRepo.GetSceneIDsByPornDBID(porndbSceneID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find scenes matched to porndb scene: %w", err)
+	}
+	return s.flagGroup(data.DuplicateReasonPornDBMatch, porndbSceneID, sceneID, sceneIDs)
+}
+
+// FlagFileHashMatch groups every library scene whose file has the same checksum as the
+// given one into a pending duplicate group. Same-checksum files are byte-identical, so
+// no further matching is needed. Returns nil when the scene has no copies.
+func (s *DuplicateService) FlagFileHashMatch(sceneID uint, fileHash string) (*data.DuplicateGroup, error) {
+	if fileHash == "" {
+		return nil, nil
+	}
+
+	sceneIDs, err := s.sceneRepo.GetSceneIDsByFileHash(fileHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find scenes with the same checksum: %w", err)
+	}
+	return s.flagGroup(data.DuplicateReasonFileHash, fileHash, sceneID, sceneIDs)
+}
+
+// FlagFileHashDuplicates groups the scenes of every checksum shared by more than one
+// scene, for libraries hashed before checksum duplicates were flagged. Returns the
+// number of pending groups covering them.
+func (s *DuplicateService) FlagFileHashDuplicates() (int, error) {
+	hashes, err := s.sceneRepo.GetSharedFileHashes()
+	if err != nil {
+		return 0, apperrors.NewInternalError("failed to find shared checksums", err)
+	}
+
+	flagged := 0
+	for _, hash := range hashes {
+		sceneIDs, err := s.sceneRepo.GetSceneIDsByFileHash(hash)
+		if err != nil {
+			return flagged, apperrors.NewInternalError("failed to find scenes with the same checksum", err)
+		}
+		if len(sceneIDs) == 0 {
+			continue
+		}
+		group, err := s.flagGroup(data.DuplicateReasonFileHash, hash, sceneIDs[0], sceneIDs)
+		if err != nil {
+			return flagged, apperrors.NewInternalError("failed to flag checksum duplicates", err)
+		}
+		if group != nil {
+			flagged++
+		}
+	}
+	return flagged, nil
+}
+
+// flagGroup puts sceneIDs into a pending group for the reason and external ID. An
+// existing pending group is extended; a group that was already dismissed or resolved
+// with the same members is left alone so reviewed matches are not flagged again, and
+// scenes whose every pair was dismissed before (for any reason) are not grouped.
+// Returns nil when there are fewer than two scenes.
+func (s *DuplicateService) flagGroup(reason, externalID string, sceneID uint, sceneIDs []uint) (*data.DuplicateGroup, error) {
+	if len(sceneIDs) < 2 {
+		return nil, nil
+	}
+
+	suppressed, err := s.duplicateRepo.AllPairsSuppressed(sceneIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check duplicate suppressions: %w", err)
+	}
+	if suppressed {
+		return nil, nil
+	}
+
+	groups, err := s.duplicateRepo.FindByExternalID(reason, externalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate groups: %w", err)
+	}
+
+	for i := range groups {
+		group := &groups[i]
+		if group.Status != data.DuplicateGroupStatusPending {
+			continue
+		}
+		missing := missingIDs(group.SceneIDs, sceneIDs)
+		if len(missing) == 0 {
+			return group, nil
+		}
+		if err := s.duplicateRepo.AddScenes(group.ID, missing); err != nil {
+			return nil, fmt.Errorf("failed to add scenes to duplicate group: %w", err)
+		}
+		group.SceneIDs = append(group.SceneIDs, missing...)
+		s.logger.Info("Extended duplicate group",
+			zap.Uint("group_id", group.ID),
+			zap.Uint("scene_id", sceneID),
+			zap.String("reason", reason),
+			zap.String("external_id", externalID),
+			zap.Int("scene_count", len(group.SceneIDs)),
+		)
+		s.publishDetected(group)
+		return group, nil
+	}
+
+	for _, group := range groups {
+		if len(missingIDs(group.SceneIDs, sceneIDs)) == 0 {
+			return nil, nil
+		}
+	}
+
+	group := &data.DuplicateGroup{
+		Status:     data.DuplicateGroupStatusPending,
+		Reason:     reason,
+		ExternalID: externalID,
+	}
+	if err := s.duplicateRepo.Create(group, sceneIDs); err != nil {
+		return nil, fmt.Errorf("failed to create duplicate group: %w", err)
+	}
+
+	s.logger.Info("Flagged duplicate group",
+		zap.Uint("group_id", group.ID),
+		zap.Uint("scene_id", sceneID),
+		zap.String("reason", reason),
+		zap.String("external_id", externalID),
+		zap.Int("scene_count", len(sceneIDs)),
+	)
+	group.SceneIDs = sceneIDs
+	s.publishDetected(group)
+	return group, nil
+}
+
+// publishDetected announces a new or extended duplicate group as a
+// "duplicate:detected" event.
+func (s *DuplicateService) publishDetected(group *data.DuplicateGroup) {
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(SceneEvent{
+		Type: "duplicate:detected",
+		Data: map[string]any{
+			"group_id":  group.ID,
+			"reason":    group.Reason,
+			"scene_ids": group.SceneIDs,
+		},
+	})
+}
+
+// ListGroups returns duplicate groups with their scenes, filtered by status when non-empty.
+func (s *DuplicateService) ListGroups(status string, page, limit int) ([]DuplicateGroupDetails, int64, error) {
+	if status != "" && !data.IsValidDuplicateGroupStatus(status) {
+		return nil, 0, apperrors.NewValidationErrorWithField("status", fmt.Sprintf("invalid status: %s", status))
+	}
+
+	groups, total, err := s.duplicateRepo.List(status, page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list duplicate groups", err)
+	}
+
+	var allIDs []uint
+	for _, g := range groups {
+		allIDs = append(allIDs, g.SceneIDs...)
+	}
+
+	scenesByID := make(map[uint]data.Scene, len(allIDs))
+	if len(allIDs) > 0 {
+		scenes, err := s.sceneRepo.GetByIDs(allIDs)
+		if err != nil {
+			return nil, 0, apperrors.NewInternalError("failed to load duplicate scenes", err)
+		}
+		for _, scene := range scenes {
+			scenesByID[scene.ID] = scene
+		}
+	}
+
+	result := make([]DuplicateGroupDetails, len(groups))
+	for i, g := range groups {
+		details := DuplicateGroupDetails{DuplicateGroup: g, Scenes: []DuplicateSceneSummary{}}
+		for _, id := range g.SceneIDs {
+			scene, ok := scenesByID[id]
+			if !ok {
+				continue
+			}
+			details.Scenes = append(details.Scenes, DuplicateSceneSummary{
+				ID:               scene.ID,
+				Title:            scene.Title,
+				OriginalFilename: scene.OriginalFilename,
+				Duration:         scene.Duration,
+				Size:             scene.Size,
+				Width:            scene.Width,
+				Height:           scene.Height,
+				VideoCodec:       scene.VideoCodec,
+			})
+		}
+		result[i] = details
+	}
+
+	return result, total, nil
+}
+
+// UpdateGroupStatus marks a duplicate group as pending, resolved or dismissed.
+// Dismissing records every pair of its scenes as not duplicates, so they are not
+// grouped together again until the suppression is deleted.
+func (s *DuplicateService) UpdateGroupStatus(id uint, status string) error {
+	if !data.IsValidDuplicateGroupStatus(status) {
+		return apperrors.NewValidationErrorWithField("status", fmt.Sprintf("invalid status: %s", status))
+	}
+	var err error
+	if status == data.DuplicateGroupStatusDismissed {
+		err = s.duplicateRepo.Dismiss(id)
+	} else {
+		err = s.duplicateRepo.UpdateStatus(id, status)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewNotFoundError("duplicate group", id)
+		}
+		return apperrors.NewInternalError("failed to update duplicate group", err)
+	}
+	return nil
+}
+
+// ResolveGroup resolves a duplicate group, keeping winnerSceneID. Without an action
+// only the winner is recorded. The merge action first moves the other scenes'
+// metadata, tags, actors, markers, watch history and interactions onto the winner,
+// then moves them to trash. If trashing fails part way the group stays pending;
+// resolving it again finishes the job, as already merged scenes are in trash.
+func (s *DuplicateService) ResolveGroup(id, winnerSceneID uint, action string) (*DuplicateResolution, error) {
+	if action != "" && action != data.DuplicateActionMerge {
+		return nil, apperrors.NewValidationErrorWithField("action", fmt.Sprintf("invalid action: %s", action))
+	}
+
+	group, err := s.duplicateRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("duplicate group", id)
+		}
+		return nil, apperrors.NewInternalError("failed to get duplicate group", err)
+	}
+	if !slices.Contains(group.SceneIDs, winnerSceneID) {
+		return nil, apperrors.NewValidationErrorWithField("winner_scene_id", "winner must be a scene in the group")
+	}
+
+	result := &DuplicateResolution{
+		GroupID:         id,
+		WinnerSceneID:   winnerSceneID,
+		Action:          action,
+		TrashedSceneIDs: []uint{},
+	}
+	if action == data.DuplicateActionMerge {
+		trashed, err := s.mergeInto(winnerSceneID, group.SceneIDs)
+		if err != nil {
+			return nil, err
+		}
+		result.TrashedSceneIDs = trashed
+	}
+
+	if err := s.duplicateRepo.Resolve(id, winnerSceneID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("duplicate group", id)
+		}
+		return nil, apperrors.NewInternalError("failed to resolve duplicate group", err)
+	}
+
+	s.logger.Info("Resolved duplicate group",
+		zap.Uint("group_id", id),
+		zap.Uint("winner_scene_id", winnerSceneID),
+		zap.String("action", action),
+		zap.Int("trashed_count", len(result.TrashedSceneIDs)),
+	)
+	return result, nil
+}
+
+// mergeInto merges the group's scenes that are not in trash into the winner and
+// trashes them, returning the trashed scene IDs.
+func (s *DuplicateService) mergeInto(winnerSceneID uint, sceneIDs []uint) ([]uint, error) {
+	if s.trasher == nil {
+		return nil, apperrors.NewInternalError("scene trash is not available", nil)
+	}
+
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load duplicate scenes", err)
+	}
+	slices.SortFunc(scenes, func(a, b data.Scene) int { return int(a.ID) - int(b.ID) })
+
+	var winner *data.Scene
+	var losers []data.Scene
+	var loserIDs []uint
+	for i := range scenes {
+		if scenes[i].ID == winnerSceneID {
+			winner = &scenes[i]
+			continue
+		}
+		losers = append(losers, scenes[i])
+		loserIDs = append(loserIDs, scenes[i].ID)
+	}
+	if winner == nil {
+		return nil, apperrors.ErrSceneNotFound(winnerSceneID)
+	}
+	if len(losers) == 0 {
+		return []uint{}, nil
+	}
+
+	if err := s.duplicateRepo.MergeScenes(winnerSceneID, loserIDs, mergedSceneFields(winner, losers)); err != nil {
+		return nil, apperrors.NewInternalError("failed to merge duplicate scenes", err)
+	}
+	s.reindexScene(winnerSceneID)
+
+	trashed := make([]uint, 0, len(loserIDs))
+	for _, id := range loserIDs {
+		if _, err := s.trasher.MoveSceneToTrash(id, false); err != nil {
+			s.logger.Warn("Failed to trash merged duplicate scene",
+				zap.Uint("scene_id", id),
+				zap.Uint("winner_scene_id", winnerSceneID),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+		trashed = append(trashed, id)
+	}
+	return trashed, nil
+}
+
+func (s *DuplicateService) reindexScene(id uint) {
+	if s.indexer == nil {
+		return
+	}
+	scene, err := s.sceneRepo.GetByID(id)
+	if err == nil {
+		err = s.indexer.UpdateSceneIndex(scene)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to update merged scene in search index",
+			zap.Uint("scene_id", id),
+			zap.Error(err),
+		)
+	}
+}
+
+// mergedSceneFields returns the winner columns to change: fields the winner leaves
+// empty take the first loser's value, view counts are summed and the tag and actor
+// name lists are combined.
+func mergedSceneFields(winner *data.Scene, losers []data.Scene) map[string]any {
+	merged := *winner
+	merged.Tags = slices.Clone(winner.Tags)
+	merged.Actors = slices.Clone(winner.Actors)
+	for _, l := range losers {
+		if merged.Title == "" {
+			merged.Title = l.Title
+		}
+		if merged.Description == "" {
+			merged.Description = l.Description
+		}
+		if merged.Studio == "" {
+			merged.Studio = l.Studio
+		}
+		if merged.StudioID == nil {
+			merged.StudioID = l.StudioID
+		}
+		if merged.ReleaseDate == nil {
+			merged.ReleaseDate = l.ReleaseDate
+		}
+		if merged.PornDBSceneID == "" {
+			merged.PornDBSceneID = l.PornDBSceneID
+		}
+		if merged.Origin == "" {
+			merged.Origin = l.Origin
+		}
+		if merged.Type == "" {
+			merged.Type = l.Type
+		}
+		merged.ViewCount += l.ViewCount
+		merged.Tags = appendMissingNames(merged.Tags, l.Tags)
+		merged.Actors = appendMissingNames(merged.Actors, l.Actors)
+	}
+
+	fields := map[string]any{}
+	if merged.Title != winner.Title {
+		fields["title"] = merged.Title
+	}
+	if merged.Description != winner.Description {
+		fields["description"] = merged.Description
+	}
+	if merged.Studio != winner.Studio {
+		fields["studio"] = merged.Studio
+	}
+	if merged.StudioID != winner.StudioID {
+		fields["studio_id"] = merged.StudioID
+	}
+	if merged.ReleaseDate != winner.ReleaseDate {
+		fields["release_date"] = merged.ReleaseDate
+	}
+	if merged.PornDBSceneID != winner.PornDBSceneID {
+		fields["porndb_scene_id"] = merged.PornDBSceneID
+	}
+	if merged.Origin != winner.Origin {
+		fields["origin"] = merged.Origin
+	}
+	if merged.Type != winner.Type {
+		fields["type"] = merged.Type
+	}
+	if merged.ViewCount != winner.ViewCount {
+		fields["view_count"] = merged.ViewCount
+	}
+	if len(merged.Tags) != len(winner.Tags) {
+		fields["tags"] = merged.Tags
+	}
+	if len(merged.Actors) != len(winner.Actors) {
+		fields["actors"] = merged.Actors
+	}
+	return fields
+}
+
+// appendMissingNames appends the names in add that are not already in names.
+func appendMissingNames(names, add []string) []string {
+	for _, name := range add {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ListSuppressions returns scene pairs recorded as not duplicates, limited to pairs
+// containing sceneID when non-zero.
+func (s *DuplicateService) ListSuppressions(sceneID uint, page, limit int) ([]data.DuplicateSuppression, int64, error) {
+	suppressions, total, err := s.duplicateRepo.ListSuppressions(sceneID, page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list duplicate suppressions", err)
+	}
+	if suppressions == nil {
+		suppressions = []data.DuplicateSuppression{}
+	}
+	return suppressions, total, nil
+}
+
+// DeleteSuppression lets two scenes be grouped as duplicates again.
+func (s *DuplicateService) DeleteSuppression(sceneIDA, sceneIDB uint) error {
+	if sceneIDA == sceneIDB {
+		return apperrors.NewValidationError("a suppression needs two different scenes")
+	}
+	if err := s.duplicateRepo.DeleteSuppression(sceneIDA, sceneIDB); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewNotFoundError("duplicate suppression", fmt.Sprintf("%d-%d", sceneIDA, sceneIDB))
+		}
+		return apperrors.NewInternalError("failed to delete duplicate suppression", err)
+	}
+	return nil
+}
+
+// missingIDs returns the IDs in want that are not in have.
+func missingIDs(have, want []uint) []uint {
+	set := make(map[uint]struct{}, len(have))
+	for _, id := range have {
+		set[id] = struct{}{}
+	}
+	var missing []uint
+	for _, id := range want {
+		if _, ok := set[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,307 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestDuplicateService(t *testing.T) (*DuplicateService, *mocks.MockDuplicateGroupRepository, *mocks.MockSceneRepository) {
+	ctrl := gomock.NewController(t)
+	duplicateRepo := mocks.NewMockDuplicateGroupRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+
+	svc := NewDuplicateService(duplicateRepo, sceneRepo, nil, zap.NewNop())
+	return svc, duplicateRepo, sceneRepo
+}
+
+func TestFlagPornDBMatch_SingleSceneNotFlagged(t *testing.T) {
+	svc, _, sceneRepo := newTestDuplicateService(t)
+
+	sceneRepo.EXPECT().GetSceneIDsByPornDBID("abc").Return([]uint{1}, nil)
+
+	group, err := svc.FlagPornDBMatch(1, "abc")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if group != nil {
+		t.Fatalf("expected no group, got %+v", group)
+	}
+}
+
+func TestFlagPornDBMatch_CreatesPendingGroup(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+
+	sceneRepo.EXPECT().GetSceneIDsByPornDBID("abc").Return([]uint{1, 2}, nil)
+	duplicateRepo.EXPECT().AllPairsSuppressed([]uint{1, 2}).Return(false, nil)
+	duplicateRepo.EXPECT().FindByExternalID(data.DuplicateReasonPornDBMatch, "abc").Return(nil, nil)
+	duplicateRepo.EXPECT().Create(gomock.Any(), []uint{1, 2}).DoAndReturn(func(group *data.DuplicateGroup, sceneIDs []uint) error {
+		if group.Status != data.DuplicateGroupStatusPending {
+			t.Fatalf("expected pending status, got %q", group.Status)
+		}
+		if group.ExternalID != "abc" {
+			t.Fatalf("expected external_id abc, got %q", group.ExternalID)
+		}
+		group.ID = 7
+		return nil
+	})
+
+	group, err := svc.FlagPornDBMatch(2, "abc")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if group == nil || group.ID != 7 {
+		t.Fatalf("expected created group 7, got %+v", group)
+	}
+}
+
+func TestFlagPornDBMatch_ExtendsPendingGroup(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+
+	sceneRepo.EXPECT().GetSceneIDsByPornDBID("abc").Return([]uint{1, 2, 3}, nil)
+	duplicateRepo.EXPECT().AllPairsSuppressed([]uint{1, 2, 3}).Return(false, nil)
+	duplicateRepo.EXPECT().FindByExternalID(data.DuplicateReasonPornDBMatch, "abc").Return([]data.DuplicateGroup{
+		{ID: 7, Status: data.DuplicateGroupStatusPending, SceneIDs: []uint{1, 2}},
+	}, nil)
+	duplicateRepo.EXPECT().AddScenes(uint(7), []uint{3}).Return(nil)
+
+	group, err := svc.FlagPornDBMatch(3, "abc")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(group.SceneIDs) != 3 {
+		t.Fatalf("expected 3 scenes in group, got %v", group.SceneIDs)
+	}
+}
+
+func TestFlagPornDBMatch_SkipsReviewedGroup(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+
+	sceneRepo.EXPECT().GetSceneIDsByPornDBID("abc").Return([]uint{1, 2}, nil)
+	duplicateRepo.EXPECT().AllPairsSuppressed([]uint{1, 2}).Return(false, nil)
+	duplicateRepo.EXPECT().FindByExternalID(data.DuplicateReasonPornDBMatch, "abc").Return([]data.DuplicateGroup{
+		{ID: 7, Status: data.DuplicateGroupStatusDismissed, SceneIDs: []uint{1, 2}},
+	}, nil)
+
+	group, err := svc.FlagPornDBMatch(2, "abc")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if group != nil {
+		t.Fatalf("expected dismissed match not to be re-flagged, got %+v", group)
+	}
+}
+
+func TestFlagFileHashMatch_CreatesChecksumGroup(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+
+	sceneRepo.EXPECT().GetSceneIDsByFileHash("f00d").Return([]uint{4, 9}, nil)
+	duplicateRepo.EXPECT().AllPairsSuppressed([]uint{4, 9}).Return(false, nil)
+	duplicateRepo.EXPECT().FindByExternalID(data.DuplicateReasonFileHash, "f00d").Return(nil, nil)
+	duplicateRepo.EXPECT().Create(gomock.Any(), []uint{4, 9}).DoAndReturn(func(group *data.DuplicateGroup, sceneIDs []uint) error {
+		if group.Reason != data.DuplicateReasonFileHash || group.ExternalID != "f00d" {
+			t.Fatalf("expected a file_hash group for f00d, got %+v", group)
+		}
+		return nil
+	})
+
+	group, err := svc.FlagFileHashMatch(9, "f00d")
+	if err != nil || group == nil {
+		t.Fatalf("expected a group, got %+v, %v", group, err)
+	}
+}
+
+func TestFlagFileHashDuplicates(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+
+	sceneRepo.EXPECT().GetSharedFileHashes().Return([]string{"aa", "bb"}, nil)
+	sceneRepo.EXPECT().GetSceneIDsByFileHash("aa").Return([]uint{1, 2}, nil)
+	sceneRepo.EXPECT().GetSceneIDsByFileHash("bb").Return([]uint{3, 5}, nil)
+	duplicateRepo.EXPECT().AllPairsSuppressed(gomock.Any()).Return(false, nil).Times(2)
+	duplicateRepo.EXPECT().FindByExternalID(data.DuplicateReasonFileHash, "aa").Return(nil, nil)
+	duplicateRepo.EXPECT().Create(gomock.Any(), []uint{1, 2}).Return(nil)
+	// Already reviewed: not flagged again
+	duplicateRepo.EXPECT().FindByExternalID(data.DuplicateReasonFileHash, "bb").Return([]data.DuplicateGroup{
+		{ID: 8, Status: data.DuplicateGroupStatusDismissed, SceneIDs: []uint{3, 5}},
+	}, nil)
+
+	flagged, err := svc.FlagFileHashDuplicates()
+	if err != nil || flagged != 1 {
+		t.Fatalf("expected 1 flagged group, got %d, %v", flagged, err)
+	}
+}
+
+func TestFlagPornDBMatch_SkipsSuppressedPairs(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+
+	sceneRepo.EXPECT().GetSceneIDsByPornDBID("abc").Return([]uint{1, 2}, nil)
+	duplicateRepo.EXPECT().AllPairsSuppressed([]uint{1, 2}).Return(true, nil)
+
+	group, err := svc.FlagPornDBMatch(2, "abc")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if group != nil {
+		t.Fatalf("expected suppressed pair not to be flagged, got %+v", group)
+	}
+}
+
+func TestUpdateGroupStatus_DismissRecordsSuppressions(t *testing.T) {
+	svc, duplicateRepo, _ := newTestDuplicateService(t)
+
+	duplicateRepo.EXPECT().Dismiss(uint(7)).Return(nil)
+
+	if err := svc.UpdateGroupStatus(7, data.DuplicateGroupStatusDismissed); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestDeleteSuppression(t *testing.T) {
+	svc, duplicateRepo, _ := newTestDuplicateService(t)
+
+	if err := svc.DeleteSuppression(3, 3); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+
+	duplicateRepo.EXPECT().DeleteSuppression(uint(5), uint(3)).Return(gorm.ErrRecordNotFound)
+	if err := svc.DeleteSuppression(5, 3); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+}
+
+func TestUpdateGroupStatus_InvalidStatus(t *testing.T) {
+	svc, _, _ := newTestDuplicateService(t)
+
+	err := svc.UpdateGroupStatus(1, "merged")
+	if !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+}
+
+func TestUpdateGroupStatus_NotFound(t *testing.T) {
+	svc, duplicateRepo, _ := newTestDuplicateService(t)
+
+	duplicateRepo.EXPECT().UpdateStatus(uint(99), data.DuplicateGroupStatusResolved).Return(gorm.ErrRecordNotFound)
+
+	err := svc.UpdateGroupStatus(99, data.DuplicateGroupStatusResolved)
+	if !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+}
+
+type fakeSceneTrasher struct {
+	trashed []uint
+	failOn  uint
+}
+
+func (f *fakeSceneTrasher) MoveSceneToTrash(id uint, force bool) (*time.Time, error) {
+	if id == f.failOn {
+		return nil, apperrors.NewValidationError("scene is protected")
+	}
+	f.trashed = append(f.trashed, id)
+	now := time.Now()
+	return &now, nil
+}
+
+func TestResolveGroup_RecordsWinner(t *testing.T) {
+	svc, duplicateRepo, _ := newTestDuplicateService(t)
+
+	duplicateRepo.EXPECT().GetByID(uint(7)).Return(&data.DuplicateGroup{ID: 7, SceneIDs: []uint{1, 2}}, nil)
+	duplicateRepo.EXPECT().Resolve(uint(7), uint(2)).Return(nil)
+
+	result, err := svc.ResolveGroup(7, 2, "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if result.WinnerSceneID != 2 || len(result.TrashedSceneIDs) != 0 {
+		t.Fatalf("unexpected resolution %+v", result)
+	}
+}
+
+func TestResolveGroup_Validation(t *testing.T) {
+	svc, duplicateRepo, _ := newTestDuplicateService(t)
+
+	if _, err := svc.ResolveGroup(7, 1, "delete"); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for an unknown action, got: %v", err)
+	}
+
+	duplicateRepo.EXPECT().GetByID(uint(7)).Return(&data.DuplicateGroup{ID: 7, SceneIDs: []uint{1, 2}}, nil)
+	if _, err := svc.ResolveGroup(7, 3, ""); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for a winner outside the group, got: %v", err)
+	}
+
+	duplicateRepo.EXPECT().GetByID(uint(99)).Return(nil, gorm.ErrRecordNotFound)
+	if _, err := svc.ResolveGroup(99, 1, ""); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+}
+
+func TestResolveGroup_MergeTrashesLosers(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+	trasher := &fakeSceneTrasher{}
+	svc.SetSceneTrasher(trasher)
+
+	studioID := uint(4)
+	duplicateRepo.EXPECT().GetByID(uint(7)).Return(&data.DuplicateGroup{ID: 7, SceneIDs: []uint{1, 2, 3}}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2, 3}).Return([]data.Scene{
+		{ID: 3, Description: "later", ViewCount: 1, Actors: []string{"B"}},
+		{ID: 2, Title: "Winner", ViewCount: 5, Actors: []string{"A"}},
+		{ID: 1, Description: "first", StudioID: &studioID, ViewCount: 2, Actors: []string{"A", "C"}},
+	}, nil)
+	duplicateRepo.EXPECT().MergeScenes(uint(2), []uint{1, 3}, gomock.Any()).DoAndReturn(func(winner uint, losers []uint, fields map[string]any) error {
+		if fields["description"] != "first" || fields["view_count"] != int64(8) || fields["studio_id"] != &studioID {
+			t.Fatalf("unexpected merged fields %v", fields)
+		}
+		if _, ok := fields["title"]; ok {
+			t.Fatalf("expected the winner title to be kept, got %v", fields)
+		}
+		if fmt.Sprint(fields["actors"]) != "[A C B]" {
+			t.Fatalf("expected combined actors, got %v", fields["actors"])
+		}
+		return nil
+	})
+	duplicateRepo.EXPECT().Resolve(uint(7), uint(2)).Return(nil)
+
+	result, err := svc.ResolveGroup(7, 2, data.DuplicateActionMerge)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(trasher.trashed) != 2 || len(result.TrashedSceneIDs) != 2 {
+		t.Fatalf("expected both losers trashed, got %v / %+v", trasher.trashed, result)
+	}
+}
+
+func TestResolveGroup_MergeStopsWhenTrashFails(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+	svc.SetSceneTrasher(&fakeSceneTrasher{failOn: 1})
+
+	duplicateRepo.EXPECT().GetByID(uint(7)).Return(&data.DuplicateGroup{ID: 7, SceneIDs: []uint{1, 2}}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{{ID: 1}, {ID: 2}}, nil)
+	duplicateRepo.EXPECT().MergeScenes(uint(2), []uint{1}, gomock.Any()).Return(nil)
+
+	_, err := svc.ResolveGroup(7, 2, data.DuplicateActionMerge)
+	if !apperrors.IsValidation(err) {
+		t.Fatalf("expected the trash error, got: %v", err)
+	}
+}
+
+func TestResolveGroup_MergeFailure(t *testing.T) {
+	svc, duplicateRepo, sceneRepo := newTestDuplicateService(t)
+	svc.SetSceneTrasher(&fakeSceneTrasher{})
+
+	duplicateRepo.EXPECT().GetByID(uint(7)).Return(&data.DuplicateGroup{ID: 7, SceneIDs: []uint{1, 2}}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{{ID: 1}, {ID: 2}}, nil)
+	duplicateRepo.EXPECT().MergeScenes(uint(2), []uint{1}, gomock.Any()).Return(errors.New("db down"))
+
+	if _, err := svc.ResolveGroup(7, 2, data.DuplicateActionMerge); err == nil {
+		t.Fatal("expected error when the merge fails")
+	}
+}
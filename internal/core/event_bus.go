@@ -10,7 +10,9 @@ import (
 type SceneEvent struct {
 	Type    string `json:"type"`
 	SceneID uint   `json:"scene_id"`
-	Data    any    `json:"data,omitempty"`
+	// UserID limits delivery to that user's SSE connections; 0 sends to everyone
+	UserID uint `json:"-"`
+	Data   any  `json:"data,omitempty"`
 }
 
 type EventBus struct {
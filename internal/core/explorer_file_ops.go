@@ -0,0 +1,306 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// errDestinationExists is returned when a move or rename would overwrite a file.
+var errDestinationExists = errors.New("a file with this name already exists at the destination")
+
+// MoveScenesRequest moves scene files to a folder of a storage path
+type MoveScenesRequest struct {
+	SceneIDs      []uint `json:"scene_ids"`
+	StoragePathID uint   `json:"storage_path_id"`
+	FolderPath    string `json:"folder_path"` // relative to the storage path, created if missing
+}
+
+// SceneFileError is a scene whose file could not be moved
+type SceneFileError struct {
+	SceneID uint   `json:"scene_id"`
+	Error   string `json:"error"`
+}
+
+// MoveScenesResult reports a bulk move. Scenes already in the folder count as
+// unchanged.
+type MoveScenesResult struct {
+	Moved     int              `json:"moved"`
+	Unchanged int              `json:"unchanged"`
+	Failed    []SceneFileError `json:"failed"`
+}
+
+// MoveScenes moves scene files on disk to a folder of a storage path, along
+// with their sidecar files, and updates their stored paths. Generated
+// artifacts (thumbnails, sprites, previews) are keyed by scene ID and stay as
+// they are. A scene that fails is reported and the others still move.
+func (s *ExplorerService) MoveScenes(req MoveScenesRequest) (*MoveScenesResult, error) {
+	if len(req.SceneIDs) == 0 {
+		return nil, apperrors.NewValidationError("at least one scene ID is required")
+	}
+
+	storagePath, err := s.storagePathRepo.GetByID(req.StoragePathID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("storage path", req.StoragePathID)
+		}
+		return nil, apperrors.NewInternalError("failed to get storage path", err)
+	}
+	destDir, err := folderInStoragePath(storagePath.Path, req.FolderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// A scene listed twice is moved once
+	sceneIDs := uniqueIDs(req.SceneIDs)
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to verify scenes", err)
+	}
+	if len(scenes) != len(sceneIDs) {
+		return nil, apperrors.NewValidationError("one or more scenes not found")
+	}
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return nil, apperrors.NewInternalError("failed to create destination folder", err)
+	}
+
+	result := &MoveScenesResult{Failed: []SceneFileError{}}
+	var movedIDs []uint
+	for i := range scenes {
+		scene := &scenes[i]
+		if scene.TrashedAt != nil || scene.StoredPath == "" {
+			result.Failed = append(result.Failed, SceneFileError{SceneID: scene.ID, Error: "scene has no file to move"})
+			continue
+		}
+		newPath := filepath.Join(destDir, filepath.Base(scene.StoredPath))
+		if newPath == scene.StoredPath {
+			result.Unchanged++
+			continue
+		}
+		if err := s.relocateSceneFile(scene, newPath, storagePath.ID); err != nil {
+			s.logger.Warn("Failed to move scene file",
+				zap.Uint("id", scene.ID),
+				zap.String("path", scene.StoredPath),
+				zap.String("destination", newPath),
+				zap.Error(err),
+			)
+			result.Failed = append(result.Failed, SceneFileError{SceneID: scene.ID, Error: err.Error()})
+			continue
+		}
+		movedIDs = append(movedIDs, scene.ID)
+		result.Moved++
+	}
+
+	s.reindexMovedScenes(movedIDs)
+
+	s.logger.Info("Bulk move completed",
+		zap.Uint("storage_path_id", storagePath.ID),
+		zap.String("folder", destDir),
+		zap.Int("moved", result.Moved),
+		zap.Int("unchanged", result.Unchanged),
+		zap.Int("failed", len(result.Failed)),
+	)
+	return result, nil
+}
+
+// RenameSceneFile renames a scene's file, and its sidecar files, in place.
+// The file keeps its extension when the new name has none.
+func (s *ExplorerService) RenameSceneFile(sceneID uint, filename string) (*data.Scene, error) {
+	filename = strings.TrimSpace(filename)
+	if filename == "" || filename == "." || filename == ".." || strings.ContainsAny(filename, `/\`) {
+		return nil, apperrors.NewValidationErrorWithField("filename", "filename must be a file name without folders")
+	}
+
+	scene, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrSceneNotFound(sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+	if scene.TrashedAt != nil || scene.StoredPath == "" {
+		return nil, apperrors.NewValidationError("scene has no file to rename")
+	}
+
+	ext := filepath.Ext(scene.StoredPath)
+	if filepath.Ext(filename) == "" {
+		filename += ext
+	} else if !isVideoExtension(strings.ToLower(filepath.Ext(filename))) {
+		return nil, apperrors.NewValidationErrorWithField("filename", "filename must keep a video extension")
+	}
+	newPath := filepath.Join(filepath.Dir(scene.StoredPath), filename)
+	if newPath == scene.StoredPath {
+		return scene, nil
+	}
+
+	var storagePathID uint
+	if scene.StoragePathID != nil {
+		storagePathID = *scene.StoragePathID
+	}
+	if err := s.relocateSceneFile(scene, newPath, storagePathID); err != nil {
+		if errors.Is(err, errDestinationExists) {
+			return nil, apperrors.NewConflictError("file", err.Error())
+		}
+		return nil, apperrors.NewInternalError("failed to rename scene file", err)
+	}
+	s.reindexMovedScenes([]uint{scene.ID})
+
+	s.logger.Info("Scene file renamed", zap.Uint("id", scene.ID), zap.String("path", scene.StoredPath))
+	return scene, nil
+}
+
+// relocateSceneFile moves a scene's file and sidecars to newPath and points
+// the scene at it, moving the file back when the update fails. A
+// storagePathID of 0 leaves the scene's storage path unchanged.
+func (s *ExplorerService) relocateSceneFile(scene *data.Scene, newPath string, storagePathID uint) error {
+	oldPath := scene.StoredPath
+	if err := moveFile(oldPath, newPath); err != nil {
+		return err
+	}
+
+	var pathID *uint
+	if storagePathID != 0 {
+		pathID = &storagePathID
+	}
+	if err := s.sceneRepo.UpdateStoredPath(scene.ID, newPath, pathID); err != nil {
+		if rbErr := moveFile(newPath, oldPath); rbErr != nil {
+			s.logger.Error("Failed to move scene file back after a failed update",
+				zap.Uint("id", scene.ID),
+				zap.String("path", newPath),
+				zap.Error(rbErr),
+			)
+		}
+		return fmt.Errorf("failed to update stored path: %w", err)
+	}
+	scene.StoredPath = newPath
+	if pathID != nil {
+		scene.StoragePathID = pathID
+	}
+
+	moveSidecars(scene.ID, oldPath, newPath, s.logger)
+	publishFileMoved(s.eventBus, scene, oldPath)
+	return nil
+}
+
+// moveSidecars moves the sidecar files of a scene's video along with it, so
+// they are still found on the next scan.
+func moveSidecars(sceneID uint, oldPath, newPath string, logger *zap.Logger) {
+	oldBase := strings.TrimSuffix(oldPath, filepath.Ext(oldPath))
+	newBase := strings.TrimSuffix(newPath, filepath.Ext(newPath))
+	for _, ext := range sidecarExtensions {
+		if _, err := os.Stat(oldBase + ext); err != nil {
+			continue
+		}
+		if err := moveFile(oldBase+ext, newBase+ext); err != nil {
+			logger.Warn("Failed to move sidecar file",
+				zap.Uint("id", sceneID),
+				zap.String("path", oldBase+ext),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// publishFileMoved announces that a scene's file moved from oldPath.
+func publishFileMoved(eventBus *EventBus, scene *data.Scene, oldPath string) {
+	if eventBus == nil {
+		return
+	}
+	eventBus.Publish(SceneEvent{
+		Type:    "scene:file_moved",
+		SceneID: scene.ID,
+		Data: map[string]any{
+			"old_path":        oldPath,
+			"new_path":        scene.StoredPath,
+			"storage_path_id": scene.StoragePathID,
+		},
+	})
+}
+
+func (s *ExplorerService) reindexMovedScenes(sceneIDs []uint) {
+	if s.indexer == nil || len(sceneIDs) == 0 {
+		return
+	}
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	if err != nil {
+		s.logger.Warn("Failed to fetch scenes for index update", zap.Error(err))
+		return
+	}
+	if err := s.indexer.BulkUpdateSceneIndex(scenes); err != nil {
+		s.logger.Warn("Failed to bulk update search index", zap.Error(err))
+	}
+}
+
+// folderInStoragePath resolves a folder relative to a storage path, rejecting
+// folders that would leave it.
+func folderInStoragePath(storagePath, folder string) (string, error) {
+	base := filepath.Clean(storagePath)
+	dir := filepath.Join(base, filepath.FromSlash(strings.Trim(folder, `/\`)))
+	if rel, err := filepath.Rel(base, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", apperrors.NewValidationErrorWithField("folder_path", "folder must be inside the storage path")
+	}
+	return dir, nil
+}
+
+// moveFile moves src to dst without overwriting dst. Across filesystems, where
+// a rename is not possible, it copies the file and removes src.
+func moveFile(src, dst string) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return errDestinationExists
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	err := os.Rename(src, dst)
+	var linkErr *os.LinkError
+	if err == nil || !errors.As(err, &linkErr) || !errors.Is(linkErr.Err, syscall.EXDEV) {
+		return err
+	}
+
+	if err := copyFile(src, dst); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
+
+// copyFile copies src to a new file dst, keeping its mode and modification time.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/mock/gomock"
+)
+
+func writeTestFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(filepath.Base(path)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMoveScenes(t *testing.T) {
+	svc, _, storagePathRepo, sceneRepo, _, _, _ := newTestExplorerService(t)
+	root := t.TempDir()
+	src := filepath.Join(root, "a", "scene.mp4")
+	writeTestFile(t, src)
+	writeTestFile(t, filepath.Join(root, "a", "scene.nfo"))
+	taken := filepath.Join(root, "a", "taken.mp4")
+	writeTestFile(t, taken)
+	writeTestFile(t, filepath.Join(root, "b", "taken.mp4"))
+	inPlace := filepath.Join(root, "b", "here.mp4")
+	writeTestFile(t, inPlace)
+
+	storagePathRepo.EXPECT().GetByID(uint(1)).Return(&data.StoragePath{ID: 1, Path: root}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2, 3}).Return([]data.Scene{
+		{ID: 1, StoredPath: src},
+		{ID: 2, StoredPath: taken},
+		{ID: 3, StoredPath: inPlace},
+	}, nil)
+	dst := filepath.Join(root, "b", "scene.mp4")
+	sceneRepo.EXPECT().UpdateStoredPath(uint(1), dst, gomock.Any()).Return(nil)
+
+	// A repeated ID is not taken for a missing scene
+	result, err := svc.MoveScenes(MoveScenesRequest{SceneIDs: []uint{1, 2, 3, 1}, StoragePathID: 1, FolderPath: "/b/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Moved != 1 || result.Unchanged != 1 || len(result.Failed) != 1 || result.Failed[0].SceneID != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if _, err := os.Stat(dst); err != nil {
+		t.Fatalf("expected the file at the destination: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "b", "scene.nfo")); err != nil {
+		t.Fatalf("expected the sidecar moved along: %v", err)
+	}
+	// The existing file was not overwritten
+	if content, _ := os.ReadFile(filepath.Join(root, "b", "taken.mp4")); string(content) != "taken.mp4" {
+		t.Fatalf("expected the existing file untouched")
+	}
+}
+
+func TestMoveScenes_FolderOutsideStoragePath(t *testing.T) {
+	svc, _, storagePathRepo, _, _, _, _ := newTestExplorerService(t)
+	storagePathRepo.EXPECT().GetByID(uint(1)).Return(&data.StoragePath{ID: 1, Path: "/data/movies"}, nil)
+
+	_, err := svc.MoveScenes(MoveScenesRequest{SceneIDs: []uint{1}, StoragePathID: 1, FolderPath: "../series"})
+	if !apperrors.IsValidation(err) {
+		t.Fatalf("expected a folder outside the storage path to be rejected, got %v", err)
+	}
+}
+
+func TestRenameSceneFile(t *testing.T) {
+	svc, _, _, sceneRepo, _, _, _ := newTestExplorerService(t)
+	root := t.TempDir()
+	src := filepath.Join(root, "old.mkv")
+	writeTestFile(t, src)
+	writeTestFile(t, filepath.Join(root, "other.mkv"))
+
+	if _, err := svc.RenameSceneFile(1, "../escape"); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a name with folders to be rejected, got %v", err)
+	}
+
+	sceneRepo.EXPECT().GetByID(uint(1)).DoAndReturn(func(id uint) (*data.Scene, error) {
+		return &data.Scene{ID: 1, StoredPath: src}, nil
+	}).Times(2)
+	if _, err := svc.RenameSceneFile(1, "other"); !apperrors.IsConflict(err) {
+		t.Fatalf("expected renaming over another file to conflict, got %v", err)
+	}
+
+	dst := filepath.Join(root, "new name.mkv")
+	sceneRepo.EXPECT().UpdateStoredPath(uint(1), dst, nil).Return(nil)
+	scene, err := svc.RenameSceneFile(1, "new name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scene.StoredPath != dst {
+		t.Fatalf("expected the extension kept, got %s", scene.StoredPath)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("expected the old file gone, got %v", err)
+	}
+}
@@ -19,12 +19,15 @@ type ExplorerService struct {
 	sceneRepo       data.SceneRepository
 	tagRepo         data.TagRepository
 	actorRepo       data.ActorRepository
+	studioRepo      data.StudioRepository
 	jobHistoryRepo  data.JobHistoryRepository
 	eventBus        *EventBus
 	logger          *zap.Logger
 	indexer         SceneIndexer
 	metadataPath    string
 	searchService   *SearchService
+	deletionGuard   *DeletionGuard
+	rbac            *RBACService
 }
 
 // NewExplorerService creates a new ExplorerService
@@ -34,10 +37,12 @@ func NewExplorerService(
 	sceneRepo data.SceneRepository,
 	tagRepo data.TagRepository,
 	actorRepo data.ActorRepository,
+	studioRepo data.StudioRepository,
 	jobHistoryRepo data.JobHistoryRepository,
 	eventBus *EventBus,
 	logger *zap.Logger,
 	metadataPath string,
+	deletionGuard *DeletionGuard,
 ) *ExplorerService {
 	return &ExplorerService{
 		explorerRepo:    explorerRepo,
@@ -45,10 +50,12 @@ func NewExplorerService(
 		sceneRepo:       sceneRepo,
 		tagRepo:         tagRepo,
 		actorRepo:       actorRepo,
+		studioRepo:      studioRepo,
 		jobHistoryRepo:  jobHistoryRepo,
 		eventBus:        eventBus,
 		logger:          logger,
 		metadataPath:    metadataPath,
+		deletionGuard:   deletionGuard,
 	}
 }
 
@@ -58,6 +65,11 @@ func (s *ExplorerService) SetSearchService(searchService *SearchService) {
 	s.searchService = searchService
 }
 
+// SetRBACService limits folder listings to the scenes the user's role may see.
+func (s *ExplorerService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
+}
+
 // SetIndexer sets the scene indexer for search index updates
 func (s *ExplorerService) SetIndexer(indexer SceneIndexer) {
 	s.indexer = indexer
@@ -67,6 +79,7 @@ func (s *ExplorerService) SetIndexer(indexer SceneIndexer) {
 type FolderContentsResponse struct {
 	StoragePath *data.StoragePath `json:"storage_path"`
 	CurrentPath string            `json:"current_path"`
+	Folder      *data.FolderInfo  `json:"folder"` // totals of the folder and everything under it
 	Subfolders  []data.FolderInfo `json:"subfolders"`
 	Scenes      []data.Scene      `json:"scenes"`
 	TotalScenes int64             `json:"total_scenes"`
@@ -83,8 +96,9 @@ func (s *ExplorerService) GetStoragePathsWithCounts() ([]data.StoragePathWithCou
 	return paths, nil
 }
 
-// GetFolderContents returns the contents of a folder (subfolders and scenes)
-func (s *ExplorerService) GetFolderContents(storagePathID uint, folderPath string, page, limit int) (*FolderContentsResponse, error) {
+// GetFolderContents returns the contents of a folder (subfolders and the
+// scenes role may see)
+func (s *ExplorerService) GetFolderContents(storagePathID uint, folderPath string, role string, page, limit int) (*FolderContentsResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -104,14 +118,18 @@ func (s *ExplorerService) GetFolderContents(storagePathID uint, folderPath strin
 		return nil, apperrors.NewNotFoundError("storage path", storagePathID)
 	}
 
-	// Get subfolders
+	// Get subfolders, each with the totals of everything under it
 	subfolders, err := s.explorerRepo.GetSubfolders(storagePathID, folderPath)
 	if err != nil {
 		return nil, apperrors.NewInternalError("failed to get subfolders", err)
 	}
+	folder, err := s.explorerRepo.GetFolderSummary(storagePathID, folderPath)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get folder totals", err)
+	}
 
 	// Get scenes in this folder (direct children only)
-	scenes, total, err := s.explorerRepo.GetScenesByFolder(storagePathID, folderPath, page, limit)
+	scenes, total, err := s.explorerRepo.GetScenesByFolder(storagePathID, folderPath, restrictionFor(s.rbac, role), page, limit)
 	if err != nil {
 		return nil, apperrors.NewInternalError("failed to get scenes", err)
 	}
@@ -119,6 +137,7 @@ func (s *ExplorerService) GetFolderContents(storagePathID uint, folderPath strin
 	return &FolderContentsResponse{
 		StoragePath: storagePath,
 		CurrentPath: folderPath,
+		Folder:      folder,
 		Subfolders:  subfolders,
 		Scenes:      scenes,
 		TotalScenes: total,
@@ -136,6 +155,7 @@ type FolderSceneIDsRequest struct {
 	TagIDs        []uint
 	Actors        []string
 	HasPornDBID   *bool
+	Role          string // limits the IDs to the scenes the role may see
 }
 
 // GetFolderSceneIDs returns all scene IDs in a folder
@@ -162,7 +182,7 @@ func (s *ExplorerService) GetFolderSceneIDsFiltered(req FolderSceneIDsRequest) (
 	}
 
 	// Get all scene IDs in the folder first
-	folderSceneIDs, err := s.explorerRepo.GetSceneIDsByFolder(req.StoragePathID, req.FolderPath, req.Recursive)
+	folderSceneIDs, err := s.explorerRepo.GetSceneIDsByFolder(req.StoragePathID, req.FolderPath, req.Recursive, restrictionFor(s.rbac, req.Role))
 	if err != nil {
 		return nil, apperrors.NewInternalError("failed to get scene IDs", err)
 	}
@@ -439,7 +459,8 @@ func (s *ExplorerService) BulkUpdateStudio(req BulkUpdateStudioRequest) (int, er
 // BulkDeleteScenes deletes multiple scenes.
 // If permanent is false, scenes are moved to trash (files preserved).
 // If permanent is true, scenes are hard deleted (files removed).
-func (s *ExplorerService) BulkDeleteScenes(sceneIDs []uint, permanent bool) (int, error) {
+// Nothing is deleted when any scene is protected, unless force bypasses the guard.
+func (s *ExplorerService) BulkDeleteScenes(sceneIDs []uint, permanent, force bool) (int, error) {
 	if len(sceneIDs) == 0 {
 		return 0, apperrors.NewValidationError("at least one scene ID is required")
 	}
@@ -450,6 +471,14 @@ func (s *ExplorerService) BulkDeleteScenes(sceneIDs []uint, permanent bool) (int
 		return 0, apperrors.NewInternalError("failed to verify scenes", err)
 	}
 
+	existingIDs := make([]uint, len(scenes))
+	for i, scene := range scenes {
+		existingIDs[i] = scene.ID
+	}
+	if err := s.deletionGuard.Check(existingIDs, force); err != nil {
+		return 0, err
+	}
+
 	deleted := 0
 	deletedIDs := make([]uint, 0, len(scenes))
 	for _, scene := range scenes {
@@ -573,6 +602,17 @@ func (s *ExplorerService) deleteSceneFiles(scene *data.Scene) {
 			)
 		}
 	}
+
+	// Remove trickplay file
+	if scene.TrickplayPath != "" {
+		if err := os.Remove(scene.TrickplayPath); err != nil && !os.IsNotExist(err) {
+			s.logger.Warn("Failed to delete trickplay file",
+				zap.Uint("id", scene.ID),
+				zap.String("path", scene.TrickplayPath),
+				zap.Error(err),
+			)
+		}
+	}
 }
 
 // FolderSearchRequest represents a request to search within a folder
@@ -590,6 +630,7 @@ type FolderSearchRequest struct {
 	HasPornDBID   *bool // nil = no filter, true = has, false = missing
 	Page          int
 	Limit         int
+	Role          string // limits results to the scenes the role may see
 }
 
 // FolderSearchResponse contains the search results
@@ -613,7 +654,12 @@ type SceneMatchInfo struct {
 }
 
 // GetScenesMatchInfo returns minimal scene data needed for bulk PornDB matching
-func (s *ExplorerService) GetScenesMatchInfo(sceneIDs []uint) ([]SceneMatchInfo, error) {
+// of the scenes role may see
+func (s *ExplorerService) GetScenesMatchInfo(sceneIDs []uint, role string) ([]SceneMatchInfo, error) {
+	sceneIDs, err := visibleSceneIDs(s.rbac, role, sceneIDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to filter scenes", err)
+	}
 	if len(sceneIDs) == 0 {
 		return []SceneMatchInfo{}, nil
 	}
@@ -695,7 +741,7 @@ func (s *ExplorerService) SearchInFolder(req FolderSearchRequest) (*FolderSearch
 	}
 
 	// Get all scene IDs in the folder
-	folderSceneIDs, err := s.explorerRepo.GetSceneIDsByFolder(req.StoragePathID, req.FolderPath, req.Recursive)
+	folderSceneIDs, err := s.explorerRepo.GetSceneIDsByFolder(req.StoragePathID, req.FolderPath, req.Recursive, restrictionFor(s.rbac, req.Role))
 	if err != nil {
 		return nil, apperrors.NewInternalError("failed to get folder scene IDs", err)
 	}
@@ -35,10 +35,12 @@ func newTestExplorerService(t *testing.T) (
 		sceneRepo,
 		tagRepo,
 		actorRepo,
+		mocks.NewMockStudioRepository(ctrl),
 		jobHistoryRepo,
 		nil, // EventBus
 		zap.NewNop(),
-		"", // metadataPath
+		"",  // metadataPath
+		nil, // DeletionGuard
 	)
 	return svc, explorerRepo, storagePathRepo, sceneRepo, tagRepo, actorRepo, jobHistoryRepo
 }
@@ -100,14 +102,16 @@ func TestGetFolderContents_Success(t *testing.T) {
 		{Name: "Comedy", Path: "Comedy", SceneCount: 5},
 	}
 	explorerRepo.EXPECT().GetSubfolders(uint(1), "").Return(subfolders, nil)
+	folder := &data.FolderInfo{Name: "Movies", SceneCount: 17, TotalSize: 4096, UnprocessedCount: 1, AverageWidth: 1920, AverageHeight: 1080}
+	explorerRepo.EXPECT().GetFolderSummary(uint(1), "").Return(folder, nil)
 
 	scenes := []data.Scene{
 		{ID: 1, Title: "Movie 1"},
 		{ID: 2, Title: "Movie 2"},
 	}
-	explorerRepo.EXPECT().GetScenesByFolder(uint(1), "", 1, 24).Return(scenes, int64(2), nil)
+	explorerRepo.EXPECT().GetScenesByFolder(uint(1), "", nil, 1, 24).Return(scenes, int64(2), nil)
 
-	result, err := svc.GetFolderContents(1, "", 1, 24)
+	result, err := svc.GetFolderContents(1, "", "user", 1, 24)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -123,6 +127,9 @@ func TestGetFolderContents_Success(t *testing.T) {
 	if result.TotalScenes != 2 {
 		t.Fatalf("expected total scenes 2, got %d", result.TotalScenes)
 	}
+	if result.Folder != folder {
+		t.Fatalf("expected the folder totals, got %+v", result.Folder)
+	}
 }
 
 func TestGetFolderContents_StoragePathNotFound(t *testing.T) {
@@ -130,7 +137,7 @@ func TestGetFolderContents_StoragePathNotFound(t *testing.T) {
 
 	storagePathRepo.EXPECT().GetByID(uint(999)).Return(nil, gorm.ErrRecordNotFound)
 
-	_, err := svc.GetFolderContents(999, "", 1, 24)
+	_, err := svc.GetFolderContents(999, "", "user", 1, 24)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -145,10 +152,11 @@ func TestGetFolderContents_DefaultPagination(t *testing.T) {
 	storagePath := &data.StoragePath{ID: 1, Name: "Movies", Path: "/data/movies"}
 	storagePathRepo.EXPECT().GetByID(uint(1)).Return(storagePath, nil)
 	explorerRepo.EXPECT().GetSubfolders(uint(1), "").Return(nil, nil)
-	explorerRepo.EXPECT().GetScenesByFolder(uint(1), "", 1, 24).Return(nil, int64(0), nil)
+	explorerRepo.EXPECT().GetFolderSummary(uint(1), "").Return(&data.FolderInfo{}, nil)
+	explorerRepo.EXPECT().GetScenesByFolder(uint(1), "", nil, 1, 24).Return(nil, int64(0), nil)
 
 	// Pass invalid page and limit values
-	result, err := svc.GetFolderContents(1, "", 0, 0)
+	result, err := svc.GetFolderContents(1, "", "user", 0, 0)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -172,7 +180,7 @@ func TestGetFolderSceneIDs_Success(t *testing.T) {
 	storagePathRepo.EXPECT().GetByID(uint(1)).Return(storagePath, nil)
 
 	expectedIDs := []uint{1, 2, 3, 4, 5}
-	explorerRepo.EXPECT().GetSceneIDsByFolder(uint(1), "Action", false).Return(expectedIDs, nil)
+	explorerRepo.EXPECT().GetSceneIDsByFolder(uint(1), "Action", false, nil).Return(expectedIDs, nil)
 
 	ids, err := svc.GetFolderSceneIDs(1, "Action", false)
 	if err != nil {
@@ -0,0 +1,544 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/data"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// folderRuleApplyBatch is the number of scenes loaded per query when rules are
+// applied to the scenes already in a folder
+const folderRuleApplyBatch = 500
+
+var (
+	// ErrFolderRuleNotFound is returned for an unknown rule ID
+	ErrFolderRuleNotFound = fmt.Errorf("folder rule not found")
+	// ErrFolderRulesApplying is returned when an apply job is already running
+	ErrFolderRulesApplying = fmt.Errorf("folder rules are already being applied")
+)
+
+// FolderRuleInput is the editable part of a folder rule.
+type FolderRuleInput struct {
+	Name          string
+	StoragePathID *uint
+	Pattern       string
+	TagIDs        []uint
+	ActorIDs      []uint
+	StudioID      *uint
+	SceneType     string
+	Enabled       bool
+}
+
+// FolderRuleApplyStatus is the progress of applying the rules to a folder.
+type FolderRuleApplyStatus struct {
+	Running       bool       `json:"running"`
+	StoragePathID uint       `json:"storage_path_id"`
+	FolderPath    string     `json:"folder_path"`
+	Total         int        `json:"total"`     // scenes in the folder
+	Processed     int        `json:"processed"` // scenes checked so far
+	Matched       int        `json:"matched"`   // of those, scenes at least one rule applied to
+	Error         string     `json:"error,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// FolderRuleService manages folder rules, which tag scenes by the folder they
+// are in. Scans apply them to new scenes; StartApply applies them to the
+// scenes already in a folder.
+type FolderRuleService struct {
+	repo            data.FolderRuleRepository
+	storagePathRepo data.StoragePathRepository
+	explorerRepo    data.ExplorerRepository
+	sceneRepo       data.SceneRepository
+	tagRepo         data.TagRepository
+	actorRepo       data.ActorRepository
+	studioRepo      data.StudioRepository
+	eventBus        *EventBus
+	logger          *zap.Logger
+	indexer         SceneIndexer
+
+	mu       sync.Mutex
+	applying *FolderRuleApplyStatus
+}
+
+func NewFolderRuleService(
+	repo data.FolderRuleRepository,
+	storagePathRepo data.StoragePathRepository,
+	explorerRepo data.ExplorerRepository,
+	sceneRepo data.SceneRepository,
+	tagRepo data.TagRepository,
+	actorRepo data.ActorRepository,
+	studioRepo data.StudioRepository,
+	eventBus *EventBus,
+	logger *zap.Logger,
+) *FolderRuleService {
+	return &FolderRuleService{
+		repo:            repo,
+		storagePathRepo: storagePathRepo,
+		explorerRepo:    explorerRepo,
+		sceneRepo:       sceneRepo,
+		tagRepo:         tagRepo,
+		actorRepo:       actorRepo,
+		studioRepo:      studioRepo,
+		eventBus:        eventBus,
+		logger:          logger.With(zap.String("component", "folder_rules")),
+	}
+}
+
+// SetIndexer sets the scene indexer for search index updates
+func (s *FolderRuleService) SetIndexer(indexer SceneIndexer) {
+	s.indexer = indexer
+}
+
+// List returns all folder rules in the order they are applied.
+func (s *FolderRuleService) List() ([]data.FolderRule, error) {
+	rules, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folder rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *FolderRuleService) Create(input FolderRuleInput) (*data.FolderRule, error) {
+	rule := &data.FolderRule{}
+	if err := s.fill(rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(rule); err != nil {
+		return nil, fmt.Errorf("failed to create folder rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *FolderRuleService) Update(id uint, input FolderRuleInput) (*data.FolderRule, error) {
+	rule, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder rule: %w", err)
+	}
+	if rule == nil {
+		return nil, ErrFolderRuleNotFound
+	}
+	if err := s.fill(rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(rule); err != nil {
+		return nil, fmt.Errorf("failed to update folder rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *FolderRuleService) Delete(id uint) error {
+	rule, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get folder rule: %w", err)
+	}
+	if rule == nil {
+		return ErrFolderRuleNotFound
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete folder rule: %w", err)
+	}
+	return nil
+}
+
+// fill validates the input and copies it into the rule.
+func (s *FolderRuleService) fill(rule *data.FolderRule, input FolderRuleInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	pattern := strings.TrimSpace(input.Pattern)
+	if pattern == "" {
+		return fmt.Errorf("pattern is required")
+	}
+	if _, err := compileExcludePattern(pattern); err != nil {
+		return fmt.Errorf("invalid folder pattern %q", pattern)
+	}
+	if len(input.TagIDs) == 0 && len(input.ActorIDs) == 0 && input.StudioID == nil && input.SceneType == "" {
+		return fmt.Errorf("a rule must set at least one of tags, actors, studio or type")
+	}
+	if input.SceneType != "" && !data.IsValidSceneType(input.SceneType) {
+		return fmt.Errorf("invalid scene type %q", input.SceneType)
+	}
+
+	if input.StoragePathID != nil {
+		storagePath, err := s.storagePathRepo.GetByID(*input.StoragePathID)
+		if err != nil {
+			return fmt.Errorf("failed to get storage path: %w", err)
+		}
+		if storagePath == nil {
+			return fmt.Errorf("storage path not found")
+		}
+	}
+	if len(input.TagIDs) > 0 {
+		tags, err := s.tagRepo.GetByIDs(input.TagIDs)
+		if err != nil {
+			return fmt.Errorf("failed to verify tags: %w", err)
+		}
+		if len(tags) != len(input.TagIDs) {
+			return fmt.Errorf("one or more tags not found")
+		}
+	}
+	if len(input.ActorIDs) > 0 {
+		actors, err := s.actorRepo.GetByIDs(input.ActorIDs)
+		if err != nil {
+			return fmt.Errorf("failed to verify actors: %w", err)
+		}
+		if len(actors) != len(input.ActorIDs) {
+			return fmt.Errorf("one or more actors not found")
+		}
+	}
+	if input.StudioID != nil {
+		if _, err := s.studioRepo.GetByID(*input.StudioID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("studio not found")
+			}
+			return fmt.Errorf("failed to verify studio: %w", err)
+		}
+	}
+
+	rule.Name = name
+	rule.StoragePathID = input.StoragePathID
+	rule.Pattern = pattern
+	rule.TagIDs = toInt64Array(input.TagIDs)
+	rule.ActorIDs = toInt64Array(input.ActorIDs)
+	rule.StudioID = input.StudioID
+	rule.SceneType = input.SceneType
+	rule.Enabled = input.Enabled
+	return nil
+}
+
+// folderRuleMatcher holds the enabled rules compiled once for a batch of scenes.
+type folderRuleMatcher struct {
+	rules []compiledFolderRule
+	roots map[uint]string // storage path ID -> root directory
+}
+
+type compiledFolderRule struct {
+	rule     data.FolderRule
+	segments []string
+}
+
+// loadMatcher compiles the enabled rules. It returns nil, which matches
+// nothing, when there are none or they cannot be loaded.
+func (s *FolderRuleService) loadMatcher() *folderRuleMatcher {
+	if s == nil {
+		return nil
+	}
+	m, err := s.newMatcher()
+	if err != nil {
+		s.logger.Warn("Failed to load folder rules", zap.Error(err))
+		return nil
+	}
+	return m
+}
+
+func (s *FolderRuleService) newMatcher() (*folderRuleMatcher, error) {
+	rules, err := s.repo.ListEnabled()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folder rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	paths, err := s.storagePathRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage paths: %w", err)
+	}
+
+	m := &folderRuleMatcher{roots: make(map[uint]string, len(paths))}
+	for _, p := range paths {
+		m.roots[p.ID] = p.Path
+	}
+	for _, rule := range rules {
+		segments, err := compileExcludePattern(rule.Pattern)
+		if err != nil || segments == nil {
+			s.logger.Warn("Skipping folder rule with an invalid pattern",
+				zap.Uint("rule_id", rule.ID),
+				zap.String("pattern", rule.Pattern),
+			)
+			continue
+		}
+		m.rules = append(m.rules, compiledFolderRule{rule: rule, segments: segments})
+	}
+	return m, nil
+}
+
+// match returns the rules whose pattern matches the scene's folder or one of
+// the folders above it, relative to the scene's storage path.
+func (m *folderRuleMatcher) match(scene *data.Scene) []*data.FolderRule {
+	if m == nil || scene.StoragePathID == nil {
+		return nil
+	}
+	root, ok := m.roots[*scene.StoragePathID]
+	if !ok {
+		return nil
+	}
+	rel, err := filepath.Rel(root, filepath.Dir(scene.StoredPath))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	segments := splitRelPath(rel)
+
+	var matched []*data.FolderRule
+	for i := range m.rules {
+		r := &m.rules[i]
+		if r.rule.StoragePathID != nil && *r.rule.StoragePathID != *scene.StoragePathID {
+			continue
+		}
+		for n := 1; n <= len(segments); n++ {
+			if matchSegments(r.segments, segments[:n]) {
+				matched = append(matched, &r.rule)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// folderRuleActions is what the rules matching a scene do to it. Tags and
+// actors are merged; the first matching rule with a studio or type sets it.
+type folderRuleActions struct {
+	tagIDs    []uint
+	actorIDs  []uint
+	studioID  *uint
+	sceneType string
+}
+
+func mergeFolderRules(rules []*data.FolderRule) folderRuleActions {
+	var a folderRuleActions
+	for _, r := range rules {
+		for _, id := range r.TagIDs {
+			if !slices.Contains(a.tagIDs, uint(id)) {
+				a.tagIDs = append(a.tagIDs, uint(id))
+			}
+		}
+		for _, id := range r.ActorIDs {
+			if !slices.Contains(a.actorIDs, uint(id)) {
+				a.actorIDs = append(a.actorIDs, uint(id))
+			}
+		}
+		if a.studioID == nil && r.StudioID != nil {
+			a.studioID = r.StudioID
+		}
+		if a.sceneType == "" {
+			a.sceneType = r.SceneType
+		}
+	}
+	return a
+}
+
+// apply runs the matching rules on the scenes and returns how many matched.
+// Scenes matched by the same rules are updated together. The studio and type
+// are also filled in on the given structs.
+func (s *FolderRuleService) apply(m *folderRuleMatcher, scenes []*data.Scene) (int, error) {
+	if m == nil {
+		return 0, nil
+	}
+
+	type group struct {
+		rules    []*data.FolderRule
+		sceneIDs []uint
+	}
+	groups := make(map[string]*group)
+	var order []string
+	matched := 0
+	for _, scene := range scenes {
+		rules := m.match(scene)
+		if len(rules) == 0 {
+			continue
+		}
+		matched++
+
+		ids := make([]string, len(rules))
+		for i, r := range rules {
+			ids[i] = strconv.FormatUint(uint64(r.ID), 10)
+		}
+		key := strings.Join(ids, ",")
+		g, ok := groups[key]
+		if !ok {
+			g = &group{rules: rules}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.sceneIDs = append(g.sceneIDs, scene.ID)
+
+		actions := mergeFolderRules(rules)
+		if scene.StudioID == nil && actions.studioID != nil {
+			id := *actions.studioID
+			scene.StudioID = &id
+		}
+		if scene.Type == "" {
+			scene.Type = actions.sceneType
+		}
+	}
+
+	for _, key := range order {
+		g := groups[key]
+		actions := mergeFolderRules(g.rules)
+		if err := s.tagRepo.BulkAddTagsToScenes(g.sceneIDs, actions.tagIDs); err != nil {
+			return matched, fmt.Errorf("failed to add tags: %w", err)
+		}
+		if err := s.actorRepo.BulkAddActorsToScenes(g.sceneIDs, actions.actorIDs); err != nil {
+			return matched, fmt.Errorf("failed to add actors: %w", err)
+		}
+		if err := s.repo.FillStudioAndType(g.sceneIDs, actions.studioID, actions.sceneType); err != nil {
+			return matched, fmt.Errorf("failed to set studio and type: %w", err)
+		}
+	}
+	return matched, nil
+}
+
+// StartApply applies the enabled rules to every scene in a folder of a storage
+// path (the whole path when folderPath is empty) in the background. Progress
+// is published as folder_rules:progress events and from GetApplyStatus.
+func (s *FolderRuleService) StartApply(storagePathID uint, folderPath string) (*FolderRuleApplyStatus, error) {
+	storagePath, err := s.storagePathRepo.GetByID(storagePathID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage path: %w", err)
+	}
+	if storagePath == nil {
+		return nil, fmt.Errorf("storage path not found")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.applying != nil && s.applying.Running {
+		return nil, ErrFolderRulesApplying
+	}
+
+	m, err := s.newMatcher()
+	if err != nil {
+		return nil, err
+	}
+	if m == nil || len(m.rules) == 0 {
+		return nil, fmt.Errorf("there are no enabled folder rules")
+	}
+
+	folderPath = strings.Trim(filepath.ToSlash(folderPath), "/")
+	sceneIDs, err := s.explorerRepo.GetSceneIDsByFolder(storagePathID, folderPath, true, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder scenes: %w", err)
+	}
+
+	status := &FolderRuleApplyStatus{
+		Running:       true,
+		StoragePathID: storagePathID,
+		FolderPath:    folderPath,
+		Total:         len(sceneIDs),
+		StartedAt:     time.Now(),
+	}
+	s.applying = status
+	snapshot := *status
+
+	go s.runApply(m, sceneIDs)
+
+	return &snapshot, nil
+}
+
+// GetApplyStatus returns the running or last apply job, nil when none ran.
+func (s *FolderRuleService) GetApplyStatus() *FolderRuleApplyStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.applying == nil {
+		return nil
+	}
+	status := *s.applying
+	return &status
+}
+
+func (s *FolderRuleService) runApply(m *folderRuleMatcher, sceneIDs []uint) {
+	var applyErr error
+	for start := 0; start < len(sceneIDs); start += folderRuleApplyBatch {
+		end := min(start+folderRuleApplyBatch, len(sceneIDs))
+		matched, err := s.applyBatch(m, sceneIDs[start:end])
+		if err != nil {
+			applyErr = err
+			break
+		}
+
+		s.mu.Lock()
+		s.applying.Processed = end
+		s.applying.Matched += matched
+		progress := *s.applying
+		s.mu.Unlock()
+		s.publish("folder_rules:progress", progress)
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.applying.Running = false
+	s.applying.CompletedAt = &now
+	if applyErr != nil {
+		s.applying.Error = applyErr.Error()
+	}
+	final := *s.applying
+	s.mu.Unlock()
+
+	if applyErr != nil {
+		s.logger.Error("Failed to apply folder rules", zap.Error(applyErr))
+	} else {
+		s.logger.Info("Folder rules applied",
+			zap.Uint("storage_path_id", final.StoragePathID),
+			zap.String("folder_path", final.FolderPath),
+			zap.Int("scenes", final.Total),
+			zap.Int("matched", final.Matched),
+		)
+	}
+	s.publish("folder_rules:completed", final)
+	if final.Matched > 0 {
+		s.publish("scenes_bulk_updated", nil)
+	}
+}
+
+func (s *FolderRuleService) applyBatch(m *folderRuleMatcher, sceneIDs []uint) (int, error) {
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get scenes: %w", err)
+	}
+	ptrs := make([]*data.Scene, len(scenes))
+	for i := range scenes {
+		ptrs[i] = &scenes[i]
+	}
+
+	matched, err := s.apply(m, ptrs)
+	if err != nil {
+		return matched, err
+	}
+
+	if matched > 0 && s.indexer != nil {
+		updated, err := s.sceneRepo.GetByIDs(sceneIDs)
+		if err != nil {
+			s.logger.Warn("Failed to fetch scenes for index update", zap.Error(err))
+		} else if err := s.indexer.BulkUpdateSceneIndex(updated); err != nil {
+			s.logger.Warn("Failed to bulk update search index", zap.Error(err))
+		}
+	}
+	return matched, nil
+}
+
+func (s *FolderRuleService) publish(eventType string, data any) {
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(SceneEvent{Type: eventType, Data: data})
+}
+
+func toInt64Array(ids []uint) pq.Int64Array {
+	out := make(pq.Int64Array, len(ids))
+	for i, id := range ids {
+		out[i] = int64(id)
+	}
+	return out
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"github.com/lib/pq"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type folderRuleMocks struct {
+	repo        *mocks.MockFolderRuleRepository
+	storagePath *mocks.MockStoragePathRepository
+	tags        *mocks.MockTagRepository
+	actors      *mocks.MockActorRepository
+}
+
+func newTestFolderRuleService(t *testing.T) (*FolderRuleService, folderRuleMocks) {
+	ctrl := gomock.NewController(t)
+	m := folderRuleMocks{
+		repo:        mocks.NewMockFolderRuleRepository(ctrl),
+		storagePath: mocks.NewMockStoragePathRepository(ctrl),
+		tags:        mocks.NewMockTagRepository(ctrl),
+		actors:      mocks.NewMockActorRepository(ctrl),
+	}
+	svc := NewFolderRuleService(m.repo, m.storagePath, nil, nil, m.tags, m.actors, nil, nil, zap.NewNop())
+	return svc, m
+}
+
+func testScene(id, storagePathID uint, path string) *data.Scene {
+	return &data.Scene{ID: id, StoragePathID: &storagePathID, StoredPath: path}
+}
+
+func TestFolderRuleMatcher_Match(t *testing.T) {
+	svc, m := newTestFolderRuleService(t)
+	other := uint(2)
+	m.repo.EXPECT().ListEnabled().Return([]data.FolderRule{
+		{ID: 1, Pattern: "/JAV/"},                            // anchored at the storage path root
+		{ID: 2, Pattern: "vr"},                               // any folder named vr
+		{ID: 3, Pattern: "studios/*", StoragePathID: &other}, // only in storage path 2
+	}, nil)
+	m.storagePath.EXPECT().List().Return([]data.StoragePath{
+		{ID: 1, Path: "/media"},
+		{ID: 2, Path: "/archive"},
+	}, nil)
+
+	matcher := svc.loadMatcher()
+
+	tests := []struct {
+		scene *data.Scene
+		want  []uint
+	}{
+		{testScene(1, 1, "/media/JAV/ABC-123.mp4"), []uint{1}},
+		{testScene(2, 1, "/media/JAV/studio/vr/clip.mp4"), []uint{1, 2}},
+		{testScene(3, 1, "/media/other/JAV/clip.mp4"), nil},
+		{testScene(4, 1, "/media/clip.mp4"), nil},
+		{testScene(5, 1, "/media/studios/acme/clip.mp4"), nil},
+		{testScene(6, 2, "/archive/studios/acme/2020/clip.mp4"), []uint{3}},
+		{&data.Scene{ID: 7, StoredPath: "/media/JAV/clip.mp4"}, nil},
+	}
+	for _, tt := range tests {
+		var got []uint
+		for _, r := range matcher.match(tt.scene) {
+			got = append(got, r.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: expected rules %v, got %v", tt.scene.StoredPath, tt.want, got)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("%s: expected rules %v, got %v", tt.scene.StoredPath, tt.want, got)
+			}
+		}
+	}
+}
+
+func TestFolderRuleService_Apply(t *testing.T) {
+	svc, m := newTestFolderRuleService(t)
+	studioID := uint(9)
+	m.repo.EXPECT().ListEnabled().Return([]data.FolderRule{
+		{ID: 1, Pattern: "/JAV/", TagIDs: pq.Int64Array{10}, SceneType: data.SceneTypeJAV},
+		{ID: 2, Pattern: "uncensored", TagIDs: pq.Int64Array{10, 11}, ActorIDs: pq.Int64Array{5}, StudioID: &studioID},
+	}, nil)
+	m.storagePath.EXPECT().List().Return([]data.StoragePath{{ID: 1, Path: "/media"}}, nil)
+	matcher := svc.loadMatcher()
+
+	existingStudio := uint(3)
+	a := testScene(1, 1, "/media/JAV/a.mp4")
+	b := testScene(2, 1, "/media/JAV/b.mp4")
+	c := testScene(3, 1, "/media/JAV/uncensored/c.mp4")
+	c.StudioID = &existingStudio
+	d := testScene(4, 1, "/media/misc/d.mp4")
+
+	// Scenes matched by the same rules are updated together
+	gomock.InOrder(
+		m.tags.EXPECT().BulkAddTagsToScenes([]uint{1, 2}, []uint{10}).Return(nil),
+		m.actors.EXPECT().BulkAddActorsToScenes([]uint{1, 2}, nil).Return(nil),
+		m.repo.EXPECT().FillStudioAndType([]uint{1, 2}, nil, data.SceneTypeJAV).Return(nil),
+		m.tags.EXPECT().BulkAddTagsToScenes([]uint{3}, []uint{10, 11}).Return(nil),
+		m.actors.EXPECT().BulkAddActorsToScenes([]uint{3}, []uint{5}).Return(nil),
+		m.repo.EXPECT().FillStudioAndType([]uint{3}, &studioID, data.SceneTypeJAV).Return(nil),
+	)
+
+	matched, err := svc.apply(matcher, []*data.Scene{a, b, c, d})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if matched != 3 {
+		t.Fatalf("expected 3 matched scenes, got %d", matched)
+	}
+	if a.Type != data.SceneTypeJAV || a.StudioID != nil {
+		t.Fatalf("unexpected scene a: type %q studio %v", a.Type, a.StudioID)
+	}
+	// A studio the scene already has is kept
+	if *c.StudioID != existingStudio {
+		t.Fatalf("expected studio %d to be kept, got %d", existingStudio, *c.StudioID)
+	}
+	if d.Type != "" {
+		t.Fatalf("expected unmatched scene untouched, got type %q", d.Type)
+	}
+
+	// No rules, nothing to do
+	if n, err := svc.apply(nil, []*data.Scene{a}); n != 0 || err != nil {
+		t.Fatalf("expected no-op for nil matcher, got %d, %v", n, err)
+	}
+}
+
+func TestFolderRuleService_CreateValidation(t *testing.T) {
+	svc, m := newTestFolderRuleService(t)
+
+	invalid := []FolderRuleInput{
+		{Pattern: "/JAV/", TagIDs: []uint{1}},
+		{Name: "jav", TagIDs: []uint{1}},
+		{Name: "jav", Pattern: "[", TagIDs: []uint{1}},
+		{Name: "jav", Pattern: "/JAV/"},
+		{Name: "jav", Pattern: "/JAV/", SceneType: "anime"},
+	}
+	for _, input := range invalid {
+		if _, err := svc.Create(input); err == nil {
+			t.Fatalf("expected error for %+v", input)
+		}
+	}
+
+	m.tags.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Tag{{ID: 1}}, nil)
+	if _, err := svc.Create(FolderRuleInput{Name: "jav", Pattern: "/JAV/", TagIDs: []uint{1, 2}}); err == nil {
+		t.Fatal("expected error for unknown tag")
+	}
+
+	m.tags.EXPECT().GetByIDs([]uint{1}).Return([]data.Tag{{ID: 1}}, nil)
+	m.repo.EXPECT().Create(gomock.Any()).Return(nil)
+	rule, err := svc.Create(FolderRuleInput{Name: " jav ", Pattern: "/JAV/", TagIDs: []uint{1}, SceneType: data.SceneTypeJAV, Enabled: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rule.Name != "jav" || len(rule.TagIDs) != 1 || rule.TagIDs[0] != 1 || !rule.Enabled {
+		t.Fatalf("unexpected rule %+v", rule)
+	}
+}
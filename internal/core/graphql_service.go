@@ -0,0 +1,542 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/graphql"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// graphQLMaxDepth bounds how deeply a query can nest, e.g.
+	// scenes > items > markers > tags is 4
+	graphQLMaxDepth = 8
+	// graphQLMaxPageSize caps the limit argument of paginated fields
+	graphQLMaxPageSize = 100
+)
+
+// graphQLSearcher runs the scene searches behind the scenes and search fields.
+type graphQLSearcher interface {
+	SearchWithContext(ctx context.Context, params data.SceneSearchParams) (*SearchResult, error)
+}
+
+// GraphQLService serves the read-only GraphQL API. Nested fields such as a
+// scene's tags, actors, studio and markers are loaded in one batched query per
+// level for every scene in the response.
+type GraphQLService struct {
+	sceneService *SceneService
+	searcher     graphQLSearcher
+	tagRepo      data.TagRepository
+	actorRepo    data.ActorRepository
+	studioRepo   data.StudioRepository
+	markerRepo   data.MarkerRepository
+	schema       *graphql.Schema
+	logger       *zap.Logger
+}
+
+func NewGraphQLService(
+	sceneService *SceneService,
+	searchService *SearchService,
+	tagRepo data.TagRepository,
+	actorRepo data.ActorRepository,
+	studioRepo data.StudioRepository,
+	markerRepo data.MarkerRepository,
+	logger *zap.Logger,
+) *GraphQLService {
+	s := &GraphQLService{
+		sceneService: sceneService,
+		searcher:     searchService,
+		tagRepo:      tagRepo,
+		actorRepo:    actorRepo,
+		studioRepo:   studioRepo,
+		markerRepo:   markerRepo,
+		logger:       logger,
+	}
+	schema, err := graphql.NewSchema(s.queryType(), graphQLMaxDepth)
+	if err != nil {
+		// The schema is static, so this only fails on a programming error
+		panic(err)
+	}
+	s.schema = schema
+	return s
+}
+
+type graphQLViewer struct {
+	userID uint
+	role   string
+}
+
+type graphQLViewerKey struct{}
+
+func graphQLViewerFrom(ctx context.Context) graphQLViewer {
+	v, _ := ctx.Value(graphQLViewerKey{}).(graphQLViewer)
+	return v
+}
+
+// Execute runs a query for a user. Scenes are limited to the ones role may see
+// and markers to the user's own.
+func (s *GraphQLService) Execute(ctx context.Context, userID uint, role string, req graphql.Request) *graphql.Response {
+	ctx = context.WithValue(ctx, graphQLViewerKey{}, graphQLViewer{userID: userID, role: role})
+	return s.schema.Execute(ctx, req)
+}
+
+// SDL returns the schema in the GraphQL schema definition language.
+func (s *GraphQLService) SDL() string {
+	return s.schema.SDL()
+}
+
+// resolverError keeps client-facing errors as they are and replaces internal
+// ones with a generic message, so database details don't end up in responses.
+func (s *GraphQLService) resolverError(err error) error {
+	var appErr apperrors.AppError
+	if errors.As(err, &appErr) && !apperrors.IsInternal(err) {
+		return err
+	}
+	s.logger.Error("graphql resolver failed", zap.Error(err))
+	return errors.New("internal error")
+}
+
+func (s *GraphQLService) queryType() *graphql.Object {
+	tag := &graphql.Object{Name: "Tag", Fields: []*graphql.Field{
+		{Name: "id", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "name", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "color", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "sceneCount", Type: graphql.Int, Description: "Set when listing tags"},
+	}}
+	markerTag := &graphql.Object{Name: "MarkerTag", Fields: []*graphql.Field{
+		{Name: "id", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "name", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "color", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "isFromLabel", Type: graphql.NonNullOf(graphql.Boolean), Description: "Whether the tag comes from the marker's label"},
+	}}
+	actor := &graphql.Object{Name: "Actor", Fields: []*graphql.Field{
+		{Name: "id", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "uuid", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "name", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "aliases", Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(graphql.String)))},
+		{Name: "imageUrl", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "gender", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "birthday", Type: graphql.DateTime},
+		{Name: "nationality", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "heightCm", Type: graphql.Int},
+		{Name: "sceneCount", Type: graphql.Int, Description: "Set when listing actors"},
+	}}
+	studio := &graphql.Object{Name: "Studio", Fields: []*graphql.Field{
+		{Name: "id", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "uuid", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "name", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "shortName", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "url", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "description", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "rating", Type: graphql.Float},
+		{Name: "logo", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "sceneCount", Type: graphql.Int, Description: "Set when listing studios"},
+	}}
+	marker := &graphql.Object{Name: "Marker", Fields: []*graphql.Field{
+		{Name: "id", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "sceneId", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "timestamp", Type: graphql.NonNullOf(graphql.Int), Description: "Seconds from the start of the scene"},
+		{Name: "endTimestamp", Type: graphql.Int, Description: "Set when the marker is a range"},
+		{Name: "label", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "color", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "visibility", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "createdAt", Type: graphql.NonNullOf(graphql.DateTime)},
+		{Name: "tags", Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(markerTag))), Batch: s.markerTags},
+	}}
+	scene := &graphql.Object{Name: "Scene", Fields: []*graphql.Field{
+		{Name: "id", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "title", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "description", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "duration", Type: graphql.NonNullOf(graphql.Int), Description: "Seconds"},
+		{Name: "width", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "height", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "size", Type: graphql.NonNullOf(graphql.Int), Description: "Bytes; may exceed 32 bits"},
+		{Name: "viewCount", Type: graphql.NonNullOf(graphql.Int)},
+		{Name: "frameRate", Type: graphql.NonNullOf(graphql.Float)},
+		{Name: "videoCodec", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "audioCodec", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "thumbnailPath", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "processingStatus", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "origin", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "type", Type: graphql.NonNullOf(graphql.String)},
+		{Name: "isCorrupted", Type: graphql.NonNullOf(graphql.Boolean)},
+		{Name: "releaseDate", Type: graphql.DateTime},
+		{Name: "createdAt", Type: graphql.NonNullOf(graphql.DateTime)},
+		{Name: "tags", Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(tag))), Batch: s.sceneTags},
+		{Name: "actors", Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(actor))), Batch: s.sceneActors},
+		{Name: "studio", Type: studio, Batch: s.sceneStudios},
+		{Name: "markers", Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(marker))), Batch: s.sceneMarkers, Description: "The viewer's markers"},
+	}}
+
+	pageOf := func(item *graphql.Object) *graphql.Object {
+		return &graphql.Object{Name: item.Name + "Page", Fields: []*graphql.Field{
+			{Name: "items", Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(item)))},
+			{Name: "total", Type: graphql.NonNullOf(graphql.Int)},
+			{Name: "page", Type: graphql.NonNullOf(graphql.Int)},
+			{Name: "limit", Type: graphql.NonNullOf(graphql.Int)},
+		}}
+	}
+	scenePage, actorPage, studioPage := pageOf(scene), pageOf(actor), pageOf(studio)
+	pageArgs := func(args ...*graphql.Argument) []*graphql.Argument {
+		return append(args,
+			&graphql.Argument{Name: "page", Type: graphql.Int, Default: 1},
+			&graphql.Argument{Name: "limit", Type: graphql.Int, Default: 20, Description: "At most 100"},
+		)
+	}
+	idArg := []*graphql.Argument{{Name: "id", Type: graphql.NonNullOf(graphql.Int)}}
+
+	return &graphql.Object{Name: "Query", Fields: []*graphql.Field{
+		{Name: "scene", Type: scene, Args: idArg, Resolve: s.resolveScene},
+		{
+			Name:        "scenes",
+			Description: "Lists scenes; query accepts the advanced search syntax",
+			Type:        graphql.NonNullOf(scenePage),
+			Args: pageArgs(
+				&graphql.Argument{Name: "query", Type: graphql.String},
+				&graphql.Argument{Name: "tags", Type: graphql.ListOf(graphql.NonNullOf(graphql.String)), Description: "Scenes must have all of these"},
+				&graphql.Argument{Name: "actors", Type: graphql.ListOf(graphql.NonNullOf(graphql.String)), Description: "Scenes must have at least one of these"},
+				&graphql.Argument{Name: "studio", Type: graphql.String},
+				&graphql.Argument{Name: "sort", Type: graphql.String},
+			),
+			Resolve: s.resolveScenes,
+		},
+		{
+			Name:        "search",
+			Description: "Full-text scene search using the advanced search syntax",
+			Type:        graphql.NonNullOf(scenePage),
+			Args:        pageArgs(&graphql.Argument{Name: "query", Type: graphql.NonNullOf(graphql.String)}),
+			Resolve:     s.resolveScenes,
+		},
+		{Name: "tags", Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(tag))), Resolve: s.resolveTags},
+		{Name: "tag", Type: tag, Args: idArg, Resolve: s.resolveTag},
+		{
+			Name: "actors",
+			Type: graphql.NonNullOf(actorPage),
+			Args: pageArgs(
+				&graphql.Argument{Name: "query", Type: graphql.String},
+				&graphql.Argument{Name: "sort", Type: graphql.String},
+			),
+			Resolve: s.resolveActors,
+		},
+		{Name: "actor", Type: actor, Args: idArg, Resolve: s.resolveActor},
+		{
+			Name: "studios",
+			Type: graphql.NonNullOf(studioPage),
+			Args: pageArgs(
+				&graphql.Argument{Name: "query", Type: graphql.String},
+				&graphql.Argument{Name: "sort", Type: graphql.String},
+			),
+			Resolve: s.resolveStudios,
+		},
+		{Name: "studio", Type: studio, Args: idArg, Resolve: s.resolveStudio},
+		{
+			Name:        "markers",
+			Description: "The viewer's markers on a scene",
+			Type:        graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(marker))),
+			Args:        []*graphql.Argument{{Name: "sceneId", Type: graphql.NonNullOf(graphql.Int)}},
+			Resolve:     s.resolveMarkers,
+		},
+	}}
+}
+
+// graphQLPagination reads the page and limit arguments, clamping them to valid values.
+func graphQLPagination(args map[string]any) (page, limit int) {
+	page, _ = args["page"].(int)
+	limit, _ = args["limit"].(int)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > graphQLMaxPageSize {
+		limit = graphQLMaxPageSize
+	}
+	return page, limit
+}
+
+func graphQLPage(items any, total int64, page, limit int) map[string]any {
+	return map[string]any{"items": items, "total": total, "page": page, "limit": limit}
+}
+
+func graphQLStringArg(args map[string]any, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+func graphQLStringsArg(args map[string]any, name string) []string {
+	values, _ := args[name].([]any)
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, v.(string))
+	}
+	return out
+}
+
+func graphQLIDArg(args map[string]any, name string) uint {
+	id, _ := args[name].(int)
+	if id < 0 {
+		return 0
+	}
+	return uint(id)
+}
+
+func (s *GraphQLService) resolveScene(p graphql.ResolveParams) (any, error) {
+	scene, err := s.sceneService.GetSceneForRole(graphQLIDArg(p.Args, "id"), graphQLViewerFrom(p.Context).role)
+	if apperrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	return scene, nil
+}
+
+// resolveScenes serves both scenes and search, which differ only in their
+// arguments.
+func (s *GraphQLService) resolveScenes(p graphql.ResolveParams) (any, error) {
+	viewer := graphQLViewerFrom(p.Context)
+	pageNum, limit := graphQLPagination(p.Args)
+	query := graphQLStringArg(p.Args, "query")
+	params := data.SceneSearchParams{
+		Page:        pageNum,
+		Limit:       limit,
+		Query:       query,
+		Studio:      graphQLStringArg(p.Args, "studio"),
+		Actors:      graphQLStringsArg(p.Args, "actors"),
+		Sort:        graphQLStringArg(p.Args, "sort"),
+		UserID:      viewer.userID,
+		Restriction: s.sceneService.ContentRestriction(viewer.role),
+	}
+
+	// Unknown tag names are ignored, as in the REST scene list
+	if names := graphQLStringsArg(p.Args, "tags"); len(names) > 0 {
+		tags, err := s.tagRepo.GetByNames(names)
+		if err != nil {
+			return nil, s.resolverError(err)
+		}
+		for _, tag := range tags {
+			params.TagIDs = append(params.TagIDs, tag.ID)
+		}
+	}
+
+	parsed, err := ParseSearchQuery(query)
+	if err != nil {
+		return nil, apperrors.NewValidationError(err.Error())
+	}
+	if parsed.HasFields() {
+		if _, err := applySearchQuery(s.tagRepo, &params, parsed); err != nil {
+			return nil, s.resolverError(err)
+		}
+	}
+
+	result, err := s.searcher.SearchWithContext(p.Context, params)
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	return graphQLPage(result.Scenes, result.Total, pageNum, limit), nil
+}
+
+func (s *GraphQLService) resolveTags(graphql.ResolveParams) (any, error) {
+	tags, err := s.tagRepo.ListWithCounts()
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	return tags, nil
+}
+
+func (s *GraphQLService) resolveTag(p graphql.ResolveParams) (any, error) {
+	tags, err := s.tagRepo.GetByIDs([]uint{graphQLIDArg(p.Args, "id")})
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	return tags[0], nil
+}
+
+func (s *GraphQLService) resolveActors(p graphql.ResolveParams) (any, error) {
+	pageNum, limit := graphQLPagination(p.Args)
+	query, sort := graphQLStringArg(p.Args, "query"), graphQLStringArg(p.Args, "sort")
+
+	var actors []data.ActorWithCount
+	var total int64
+	var err error
+	if query != "" {
+		actors, total, err = s.actorRepo.Search(query, pageNum, limit, sort, nil)
+	} else {
+		actors, total, err = s.actorRepo.List(pageNum, limit, sort, nil)
+	}
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	return graphQLPage(actors, total, pageNum, limit), nil
+}
+
+func (s *GraphQLService) resolveActor(p graphql.ResolveParams) (any, error) {
+	actors, err := s.actorRepo.GetByIDs([]uint{graphQLIDArg(p.Args, "id")})
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	if len(actors) == 0 {
+		return nil, nil
+	}
+	return actors[0], nil
+}
+
+func (s *GraphQLService) resolveStudios(p graphql.ResolveParams) (any, error) {
+	pageNum, limit := graphQLPagination(p.Args)
+	query, sort := graphQLStringArg(p.Args, "query"), graphQLStringArg(p.Args, "sort")
+
+	var studios []data.StudioWithCount
+	var total int64
+	var err error
+	if query != "" {
+		studios, total, err = s.studioRepo.Search(query, pageNum, limit, sort)
+	} else {
+		studios, total, err = s.studioRepo.List(pageNum, limit, sort)
+	}
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	return graphQLPage(studios, total, pageNum, limit), nil
+}
+
+func (s *GraphQLService) resolveStudio(p graphql.ResolveParams) (any, error) {
+	studios, err := s.studioRepo.GetByIDs([]uint{graphQLIDArg(p.Args, "id")})
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	if len(studios) == 0 {
+		return nil, nil
+	}
+	return studios[0], nil
+}
+
+func (s *GraphQLService) resolveMarkers(p graphql.ResolveParams) (any, error) {
+	viewer := graphQLViewerFrom(p.Context)
+	sceneID := graphQLIDArg(p.Args, "sceneId")
+	if err := s.sceneService.CheckSceneAccess(sceneID, viewer.role); err != nil {
+		return nil, s.resolverError(err)
+	}
+	markers, err := s.markerRepo.GetByUserAndScene(viewer.userID, sceneID)
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	return markers, nil
+}
+
+// graphQLSceneIDs returns the IDs of the scenes being resolved, which are Scene values
+// from lists or pointers from the scene field.
+func graphQLSceneIDs(sources []any) []uint {
+	ids := make([]uint, len(sources))
+	for i, source := range sources {
+		switch scene := source.(type) {
+		case data.Scene:
+			ids[i] = scene.ID
+		case *data.Scene:
+			ids[i] = scene.ID
+		}
+	}
+	return ids
+}
+
+func (s *GraphQLService) sceneTags(p graphql.BatchParams) ([]any, error) {
+	ids := graphQLSceneIDs(p.Sources)
+	tags, err := s.tagRepo.GetSceneTagsMultiple(ids)
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	out := make([]any, len(ids))
+	for i, id := range ids {
+		out[i] = tags[id]
+	}
+	return out, nil
+}
+
+func (s *GraphQLService) sceneActors(p graphql.BatchParams) ([]any, error) {
+	ids := graphQLSceneIDs(p.Sources)
+	actors, err := s.actorRepo.GetSceneActorsMultiple(ids)
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	out := make([]any, len(ids))
+	for i, id := range ids {
+		out[i] = actors[id]
+	}
+	return out, nil
+}
+
+func (s *GraphQLService) sceneStudios(p graphql.BatchParams) ([]any, error) {
+	studioIDs := make([]*uint, len(p.Sources))
+	var unique []uint
+	seen := map[uint]bool{}
+	for i, source := range p.Sources {
+		switch scene := source.(type) {
+		case data.Scene:
+			studioIDs[i] = scene.StudioID
+		case *data.Scene:
+			studioIDs[i] = scene.StudioID
+		}
+		if id := studioIDs[i]; id != nil && !seen[*id] {
+			seen[*id] = true
+			unique = append(unique, *id)
+		}
+	}
+
+	out := make([]any, len(p.Sources))
+	if len(unique) == 0 {
+		return out, nil
+	}
+	studios, err := s.studioRepo.GetByIDs(unique)
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	byID := make(map[uint]data.Studio, len(studios))
+	for _, studio := range studios {
+		byID[studio.ID] = studio
+	}
+	for i, id := range studioIDs {
+		if id == nil {
+			continue
+		}
+		if studio, ok := byID[*id]; ok {
+			out[i] = studio
+		}
+	}
+	return out, nil
+}
+
+func (s *GraphQLService) sceneMarkers(p graphql.BatchParams) ([]any, error) {
+	ids := graphQLSceneIDs(p.Sources)
+	markers, err := s.markerRepo.GetByUserAndScenes(graphQLViewerFrom(p.Context).userID, ids)
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	out := make([]any, len(ids))
+	for i, id := range ids {
+		out[i] = markers[id]
+	}
+	return out, nil
+}
+
+func (s *GraphQLService) markerTags(p graphql.BatchParams) ([]any, error) {
+	ids := make([]uint, len(p.Sources))
+	for i, source := range p.Sources {
+		ids[i] = source.(data.UserSceneMarker).ID
+	}
+	tags, err := s.markerRepo.GetMarkerTagsMultiple(ids)
+	if err != nil {
+		return nil, s.resolverError(err)
+	}
+	out := make([]any, len(ids))
+	for i, id := range ids {
+		out[i] = tags[id]
+	}
+	return out, nil
+}
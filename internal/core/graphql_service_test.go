@@ -0,0 +1,145 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"goonhub/internal/data"
+	"goonhub/internal/graphql"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type fakeGraphQLSearcher struct {
+	params []data.SceneSearchParams
+	scenes []data.Scene
+}
+
+func (f *fakeGraphQLSearcher) SearchWithContext(_ context.Context, params data.SceneSearchParams) (*SearchResult, error) {
+	f.params = append(f.params, params)
+	return &SearchResult{Scenes: f.scenes, Total: int64(len(f.scenes))}, nil
+}
+
+type graphQLTestMocks struct {
+	sceneRepo  *mocks.MockSceneRepository
+	tagRepo    *mocks.MockTagRepository
+	actorRepo  *mocks.MockActorRepository
+	studioRepo *mocks.MockStudioRepository
+	markerRepo *mocks.MockMarkerRepository
+}
+
+func newTestGraphQLService(t *testing.T, searcher *fakeGraphQLSearcher) (*GraphQLService, graphQLTestMocks) {
+	ctrl := gomock.NewController(t)
+	m := graphQLTestMocks{
+		sceneRepo:  mocks.NewMockSceneRepository(ctrl),
+		tagRepo:    mocks.NewMockTagRepository(ctrl),
+		actorRepo:  mocks.NewMockActorRepository(ctrl),
+		studioRepo: mocks.NewMockStudioRepository(ctrl),
+		markerRepo: mocks.NewMockMarkerRepository(ctrl),
+	}
+	svc := NewGraphQLService(&SceneService{Repo: m.sceneRepo}, nil, m.tagRepo, m.actorRepo, m.studioRepo, m.markerRepo, zap.NewNop())
+	svc.searcher = searcher
+	return svc, m
+}
+
+func graphQLData(t *testing.T, resp *graphql.Response) string {
+	t.Helper()
+	if len(resp.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", resp.Errors[0].Message)
+	}
+	out, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestGraphQLService_BatchesSceneRelations(t *testing.T) {
+	studioID := uint(9)
+	searcher := &fakeGraphQLSearcher{scenes: []data.Scene{{ID: 1, Title: "One", StudioID: &studioID}, {ID: 2, Title: "Two"}}}
+	svc, m := newTestGraphQLService(t, searcher)
+
+	m.tagRepo.EXPECT().GetSceneTagsMultiple([]uint{1, 2}).Return(map[uint][]data.Tag{1: {{ID: 4, Name: "pov"}}}, nil).Times(1)
+	m.actorRepo.EXPECT().GetSceneActorsMultiple([]uint{1, 2}).Return(map[uint][]data.Actor{2: {{Name: "Jane"}}}, nil).Times(1)
+	m.studioRepo.EXPECT().GetByIDs([]uint{9}).Return([]data.Studio{{ID: 9, Name: "Acme"}}, nil).Times(1)
+	m.markerRepo.EXPECT().GetByUserAndScenes(uint(3), []uint{1, 2}).Return(map[uint][]data.UserSceneMarker{
+		1: {{ID: 11, SceneID: 1, Timestamp: 30}, {ID: 12, SceneID: 1, Timestamp: 60}},
+	}, nil).Times(1)
+	m.markerRepo.EXPECT().GetMarkerTagsMultiple([]uint{11, 12}).Return(map[uint][]data.MarkerTagInfo{
+		12: {{ID: 5, Name: "kiss"}},
+	}, nil).Times(1)
+
+	resp := svc.Execute(context.Background(), 3, "user", graphql.Request{Query: `{
+		scenes(limit: 500, sort: "title_asc") {
+			total limit
+			items { id title tags { name } actors { name } studio { name } markers { timestamp tags { name } } }
+		}
+	}`})
+
+	want := `{"scenes":{"total":2,"limit":100,"items":[` +
+		`{"id":1,"title":"One","tags":[{"name":"pov"}],"actors":[],"studio":{"name":"Acme"},"markers":[{"timestamp":30,"tags":[]},{"timestamp":60,"tags":[{"name":"kiss"}]}]},` +
+		`{"id":2,"title":"Two","tags":[],"actors":[{"name":"Jane"}],"studio":null,"markers":[]}]}}`
+	if got := graphQLData(t, resp); got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+
+	params := searcher.params[0]
+	if params.UserID != 3 || params.Limit != graphQLMaxPageSize || params.Sort != "title_asc" {
+		t.Fatalf("unexpected search params: %+v", params)
+	}
+}
+
+func TestGraphQLService_SearchAppliesQuerySyntax(t *testing.T) {
+	searcher := &fakeGraphQLSearcher{}
+	svc, m := newTestGraphQLService(t, searcher)
+	m.tagRepo.EXPECT().GetByNames([]string{"pov"}).Return([]data.Tag{{ID: 4, Name: "pov"}}, nil)
+
+	graphQLData(t, svc.Execute(context.Background(), 3, "user", graphql.Request{
+		Query:     `query($q: String!) { search(query: $q) { total } }`,
+		Variables: map[string]any{"q": "jane tag:pov"},
+	}))
+
+	params := searcher.params[0]
+	if params.Query != "jane" || len(params.TagIDs) != 1 || params.TagIDs[0] != 4 {
+		t.Fatalf("expected the query syntax to be applied, got %+v", params)
+	}
+
+	resp := svc.Execute(context.Background(), 3, "user", graphql.Request{Query: `{ search(query: "duration:>") { total } }`})
+	if len(resp.Errors) != 1 || resp.Errors[0].Path[0] != "search" {
+		t.Fatalf("expected a field error for the invalid query, got %+v", resp.Errors)
+	}
+}
+
+func TestGraphQLService_SceneNotFoundIsNull(t *testing.T) {
+	svc, m := newTestGraphQLService(t, &fakeGraphQLSearcher{})
+	m.sceneRepo.EXPECT().GetByID(uint(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	if got := graphQLData(t, svc.Execute(context.Background(), 3, "user", graphql.Request{Query: `{ scene(id: 7) { title } }`})); got != `{"scene":null}` {
+		t.Fatalf("expected a null scene, got %s", got)
+	}
+}
+
+func TestGraphQLService_HidesInternalErrors(t *testing.T) {
+	svc, m := newTestGraphQLService(t, &fakeGraphQLSearcher{})
+	m.tagRepo.EXPECT().ListWithCounts().Return(nil, gorm.ErrInvalidDB)
+
+	resp := svc.Execute(context.Background(), 3, "user", graphql.Request{Query: `{ tags { name } }`})
+	if len(resp.Errors) != 1 || strings.Contains(resp.Errors[0].Message, "invalid db") {
+		t.Fatalf("expected a generic error, got %+v", resp.Errors)
+	}
+}
+
+func TestGraphQLService_SDL(t *testing.T) {
+	svc, _ := newTestGraphQLService(t, &fakeGraphQLSearcher{})
+	sdl := svc.SDL()
+	for _, want := range []string{"type Scene {", "markers: [Marker!]!", "scenes(query: String, tags: [String!]"} {
+		if !strings.Contains(sdl, want) {
+			t.Fatalf("expected the SDL to contain %q", want)
+		}
+	}
+}
@@ -28,8 +28,9 @@ type HomepageSectionData struct {
 
 // HomepageResponse represents the full homepage data
 type HomepageResponse struct {
-	Config   data.HomepageConfig   `json:"config"`
-	Sections []HomepageSectionData `json:"sections"`
+	Config       data.HomepageConfig   `json:"config"`
+	Sections     []HomepageSectionData `json:"sections"`
+	ReviewCounts *ReviewCounts         `json:"review_counts,omitempty"`
 }
 
 // HomepageService handles fetching homepage section data
@@ -44,6 +45,8 @@ type HomepageService struct {
 	tagRepo            data.TagRepository
 	actorRepo          data.ActorRepository
 	studioRepo         data.StudioRepository
+	reviewWorkflow     *ReviewWorkflowService
+	rbac               *RBACService
 	logger             *zap.Logger
 }
 
@@ -76,8 +79,19 @@ func NewHomepageService(
 	}
 }
 
-// GetHomepageData fetches the full homepage data for a user
-func (s *HomepageService) GetHomepageData(userID uint) (*HomepageResponse, error) {
+// SetReviewWorkflowService enables the review workflow counters on the homepage.
+func (s *HomepageService) SetReviewWorkflowService(reviewWorkflow *ReviewWorkflowService) {
+	s.reviewWorkflow = reviewWorkflow
+}
+
+// SetRBACService limits sections to the scenes the user's role may see.
+func (s *HomepageService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
+}
+
+// GetHomepageData fetches the full homepage data for a user, limited to the
+// scenes their role may see
+func (s *HomepageService) GetHomepageData(userID uint, role string) (*HomepageResponse, error) {
 	config, err := s.settingsService.GetHomepageConfig(userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get homepage config: %w", err)
@@ -101,7 +115,7 @@ func (s *HomepageService) GetHomepageData(userID uint) (*HomepageResponse, error
 			continue
 		}
 
-		sectionData, err := s.fetchSectionData(userID, section)
+		sectionData, err := s.fetchSectionData(userID, role, section)
 		if err != nil {
 			s.logger.Warn("failed to fetch section data",
 				zap.String("section_id", section.ID),
@@ -118,11 +132,20 @@ func (s *HomepageService) GetHomepageData(userID uint) (*HomepageResponse, error
 		response.Sections = append(response.Sections, *sectionData)
 	}
 
+	if s.reviewWorkflow != nil {
+		counts, err := s.reviewWorkflow.GetCounts()
+		if err != nil {
+			s.logger.Warn("failed to count scenes by review state", zap.Error(err))
+		} else {
+			response.ReviewCounts = counts
+		}
+	}
+
 	return response, nil
 }
 
 // GetSectionData fetches data for a single section
-func (s *HomepageService) GetSectionData(userID uint, sectionID string) (*HomepageSectionData, error) {
+func (s *HomepageService) GetSectionData(userID uint, role string, sectionID string) (*HomepageSectionData, error) {
 	config, err := s.settingsService.GetHomepageConfig(userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get homepage config: %w", err)
@@ -140,32 +163,32 @@ func (s *HomepageService) GetSectionData(userID uint, sectionID string) (*Homepa
 		return nil, fmt.Errorf("section not found: %s", sectionID)
 	}
 
-	return s.fetchSectionData(userID, *section)
+	return s.fetchSectionData(userID, role, *section)
 }
 
-func (s *HomepageService) fetchSectionData(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchSectionData(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	var sectionData *HomepageSectionData
 	var err error
 
 	switch section.Type {
 	case "latest":
-		sectionData, err = s.fetchLatestSection(userID, section)
+		sectionData, err = s.fetchLatestSection(userID, role, section)
 	case "actor":
-		sectionData, err = s.fetchActorSection(userID, section)
+		sectionData, err = s.fetchActorSection(userID, role, section)
 	case "studio":
-		sectionData, err = s.fetchStudioSection(userID, section)
+		sectionData, err = s.fetchStudioSection(userID, role, section)
 	case "tag":
-		sectionData, err = s.fetchTagSection(userID, section)
+		sectionData, err = s.fetchTagSection(userID, role, section)
 	case "saved_search":
-		sectionData, err = s.fetchSavedSearchSection(userID, section)
+		sectionData, err = s.fetchSavedSearchSection(userID, role, section)
 	case "continue_watching":
-		sectionData, err = s.fetchContinueWatchingSection(userID, section)
+		sectionData, err = s.fetchContinueWatchingSection(userID, role, section)
 	case "most_viewed":
-		sectionData, err = s.fetchMostViewedSection(userID, section)
+		sectionData, err = s.fetchMostViewedSection(userID, role, section)
 	case "liked":
-		sectionData, err = s.fetchLikedSection(userID, section)
+		sectionData, err = s.fetchLikedSection(userID, role, section)
 	case "playlist":
-		sectionData, err = s.fetchPlaylistSection(userID, section)
+		sectionData, err = s.fetchPlaylistSection(userID, role, section)
 	default:
 		return nil, fmt.Errorf("unknown section type: %s", section.Type)
 	}
@@ -202,17 +225,18 @@ func (s *HomepageService) fetchRatingsForScenes(userID uint, scenes []data.Scene
 	return ratings
 }
 
-func (s *HomepageService) fetchLatestSection(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchLatestSection(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	sortOrder := section.Sort
 	if sortOrder == "" {
 		sortOrder = "created_at_desc"
 	}
 
 	params := data.SceneSearchParams{
-		Page:   1,
-		Limit:  section.Limit,
-		Sort:   sortOrder,
-		UserID: userID,
+		Page:        1,
+		Limit:       section.Limit,
+		Sort:        sortOrder,
+		UserID:      userID,
+		Restriction: restrictionFor(s.rbac, role),
 	}
 
 	result, err := s.searchService.Search(params)
@@ -228,7 +252,7 @@ func (s *HomepageService) fetchLatestSection(userID uint, section data.HomepageS
 	}, nil
 }
 
-func (s *HomepageService) fetchActorSection(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchActorSection(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	actorUUID, ok := section.Config["actor_uuid"].(string)
 	if !ok || actorUUID == "" {
 		return nil, fmt.Errorf("actor_uuid not found in config")
@@ -246,11 +270,12 @@ func (s *HomepageService) fetchActorSection(userID uint, section data.HomepageSe
 	}
 
 	params := data.SceneSearchParams{
-		Page:   1,
-		Limit:  section.Limit,
-		Sort:   sortOrder,
-		Actors: []string{actor.Name},
-		UserID: userID,
+		Page:        1,
+		Limit:       section.Limit,
+		Sort:        sortOrder,
+		Actors:      []string{actor.Name},
+		UserID:      userID,
+		Restriction: restrictionFor(s.rbac, role),
 	}
 
 	result, err := s.searchService.Search(params)
@@ -266,7 +291,7 @@ func (s *HomepageService) fetchActorSection(userID uint, section data.HomepageSe
 	}, nil
 }
 
-func (s *HomepageService) fetchStudioSection(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchStudioSection(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	studioUUID, ok := section.Config["studio_uuid"].(string)
 	if !ok || studioUUID == "" {
 		return nil, fmt.Errorf("studio_uuid not found in config")
@@ -284,11 +309,12 @@ func (s *HomepageService) fetchStudioSection(userID uint, section data.HomepageS
 	}
 
 	params := data.SceneSearchParams{
-		Page:   1,
-		Limit:  section.Limit,
-		Sort:   sortOrder,
-		Studio: studio.Name,
-		UserID: userID,
+		Page:        1,
+		Limit:       section.Limit,
+		Sort:        sortOrder,
+		Studio:      studio.Name,
+		UserID:      userID,
+		Restriction: restrictionFor(s.rbac, role),
 	}
 
 	result, err := s.searchService.Search(params)
@@ -304,7 +330,7 @@ func (s *HomepageService) fetchStudioSection(userID uint, section data.HomepageS
 	}, nil
 }
 
-func (s *HomepageService) fetchTagSection(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchTagSection(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	var tagID uint
 	switch v := section.Config["tag_id"].(type) {
 	case float64:
@@ -325,11 +351,12 @@ func (s *HomepageService) fetchTagSection(userID uint, section data.HomepageSect
 	}
 
 	params := data.SceneSearchParams{
-		Page:   1,
-		Limit:  section.Limit,
-		Sort:   sortOrder,
-		TagIDs: []uint{tagID},
-		UserID: userID,
+		Page:        1,
+		Limit:       section.Limit,
+		Sort:        sortOrder,
+		TagIDs:      []uint{tagID},
+		UserID:      userID,
+		Restriction: restrictionFor(s.rbac, role),
 	}
 
 	result, err := s.searchService.Search(params)
@@ -345,7 +372,7 @@ func (s *HomepageService) fetchTagSection(userID uint, section data.HomepageSect
 	}, nil
 }
 
-func (s *HomepageService) fetchSavedSearchSection(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchSavedSearchSection(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	savedSearchUUID, ok := section.Config["saved_search_uuid"].(string)
 	if !ok || savedSearchUUID == "" {
 		return nil, fmt.Errorf("saved_search_uuid not found in config")
@@ -364,56 +391,12 @@ func (s *HomepageService) fetchSavedSearchSection(userID uint, section data.Home
 		sortOrder = "created_at_desc"
 	}
 
-	// Convert saved search filters to SceneSearchParams
-	params := data.SceneSearchParams{
-		Page:   1,
-		Limit:  section.Limit,
-		Sort:   sortOrder,
-		Query:  savedSearch.Filters.Query,
-		Studio: savedSearch.Filters.Studio,
-		UserID: userID,
-	}
-
-	if savedSearch.Filters.MatchType != "" {
-		params.MatchingStrategy = savedSearch.Filters.MatchType
-	}
-
-	// Convert tag names to tag IDs
-	if len(savedSearch.Filters.SelectedTags) > 0 {
-		tagIDs, err := s.tagRepo.GetIDsByNames(savedSearch.Filters.SelectedTags)
-		if err != nil {
-			s.logger.Warn("failed to get tag IDs", zap.Error(err))
-		} else {
-			params.TagIDs = tagIDs
-		}
-	}
-
-	if len(savedSearch.Filters.SelectedActors) > 0 {
-		params.Actors = savedSearch.Filters.SelectedActors
-	}
-
-	if savedSearch.Filters.MinDuration != nil {
-		params.MinDuration = *savedSearch.Filters.MinDuration
-	}
-	if savedSearch.Filters.MaxDuration != nil {
-		params.MaxDuration = *savedSearch.Filters.MaxDuration
-	}
-
-	if savedSearch.Filters.Liked != nil {
-		params.Liked = savedSearch.Filters.Liked
-	}
-	if savedSearch.Filters.MinRating != nil {
-		params.MinRating = *savedSearch.Filters.MinRating
-	}
-	if savedSearch.Filters.MaxRating != nil {
-		params.MaxRating = *savedSearch.Filters.MaxRating
-	}
-	if savedSearch.Filters.MinJizzCount != nil {
-		params.MinJizzCount = *savedSearch.Filters.MinJizzCount
-	}
-	if savedSearch.Filters.MaxJizzCount != nil {
-		params.MaxJizzCount = *savedSearch.Filters.MaxJizzCount
-	}
+	params := s.savedSearchService.SearchParams(savedSearch)
+	params.Page = 1
+	params.Limit = section.Limit
+	params.Sort = sortOrder
+	params.UserID = userID
+	params.Restriction = restrictionFor(s.rbac, role)
 
 	result, err := s.searchService.Search(params)
 	if err != nil {
@@ -428,7 +411,7 @@ func (s *HomepageService) fetchSavedSearchSection(userID uint, section data.Home
 	}, nil
 }
 
-func (s *HomepageService) fetchContinueWatchingSection(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchContinueWatchingSection(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	// Get scenes with resume positions (not completed)
 	// Fetch more than needed to filter for incomplete watches
 	watches, _, err := s.watchHistoryRepo.ListUserHistory(userID, 1, section.Limit*3)
@@ -450,6 +433,10 @@ func (s *HomepageService) fetchContinueWatchingSection(userID uint, section data
 		}
 	}
 
+	sceneIDs, err = visibleSceneIDs(s.rbac, role, sceneIDs)
+	if err != nil {
+		return nil, err
+	}
 	if len(sceneIDs) == 0 {
 		return &HomepageSectionData{
 			Section: section,
@@ -483,17 +470,18 @@ func (s *HomepageService) fetchContinueWatchingSection(userID uint, section data
 	}, nil
 }
 
-func (s *HomepageService) fetchMostViewedSection(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchMostViewedSection(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	sortOrder := section.Sort
 	if sortOrder == "" {
 		sortOrder = "view_count_desc"
 	}
 
 	params := data.SceneSearchParams{
-		Page:   1,
-		Limit:  section.Limit,
-		Sort:   sortOrder,
-		UserID: userID,
+		Page:        1,
+		Limit:       section.Limit,
+		Sort:        sortOrder,
+		UserID:      userID,
+		Restriction: restrictionFor(s.rbac, role),
 	}
 
 	result, err := s.searchService.Search(params)
@@ -509,7 +497,7 @@ func (s *HomepageService) fetchMostViewedSection(userID uint, section data.Homep
 	}, nil
 }
 
-func (s *HomepageService) fetchLikedSection(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchLikedSection(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	sortOrder := section.Sort
 	if sortOrder == "" {
 		sortOrder = "created_at_desc"
@@ -517,11 +505,12 @@ func (s *HomepageService) fetchLikedSection(userID uint, section data.HomepageSe
 
 	liked := true
 	params := data.SceneSearchParams{
-		Page:   1,
-		Limit:  section.Limit,
-		Sort:   sortOrder,
-		UserID: userID,
-		Liked:  &liked,
+		Page:        1,
+		Limit:       section.Limit,
+		Sort:        sortOrder,
+		UserID:      userID,
+		Liked:       &liked,
+		Restriction: restrictionFor(s.rbac, role),
 	}
 
 	result, err := s.searchService.Search(params)
@@ -537,7 +526,7 @@ func (s *HomepageService) fetchLikedSection(userID uint, section data.HomepageSe
 	}, nil
 }
 
-func (s *HomepageService) fetchPlaylistSection(userID uint, section data.HomepageSection) (*HomepageSectionData, error) {
+func (s *HomepageService) fetchPlaylistSection(userID uint, role string, section data.HomepageSection) (*HomepageSectionData, error) {
 	if s.playlistService == nil {
 		return &HomepageSectionData{
 			Section: section,
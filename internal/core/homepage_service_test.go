@@ -1,6 +1,7 @@
 package core
 
 import (
+	"goonhub/internal/config"
 	"goonhub/internal/data"
 	"goonhub/internal/mocks"
 	"testing"
@@ -31,7 +32,7 @@ func TestHomepageService_GetHomepageData_EmptySections(t *testing.T) {
 		zap.NewNop(),
 	)
 
-	response, err := svc.GetHomepageData(1)
+	response, err := svc.GetHomepageData(1, "user")
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -43,6 +44,40 @@ func TestHomepageService_GetHomepageData_EmptySections(t *testing.T) {
 	}
 }
 
+func TestHomepageService_GetHomepageData_ReviewCounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	settingsRepo := mocks.NewMockUserSettingsRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	settingsService := NewSettingsService(settingsRepo, userRepo, zap.NewNop())
+
+	settingsRepo.EXPECT().GetByUserID(uint(1)).Return(&data.UserSettings{
+		UserID:         1,
+		HomepageConfig: data.HomepageConfig{Sections: []data.HomepageSection{}},
+	}, nil)
+	sceneRepo.EXPECT().CountByReviewState().Return(map[string]int64{"": 5, "curated": 2}, nil)
+
+	svc := NewHomepageService(
+		settingsService,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		zap.NewNop(),
+	)
+	svc.SetReviewWorkflowService(NewReviewWorkflowService(sceneRepo, config.ReviewWorkflowConfig{
+		States: []string{"unreviewed", "curated"},
+	}, nil, zap.NewNop()))
+
+	response, err := svc.GetHomepageData(1, "user")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if response.ReviewCounts == nil || response.ReviewCounts.Total != 7 {
+		t.Fatalf("expected review counts with total 7, got %+v", response.ReviewCounts)
+	}
+	if response.ReviewCounts.States[0].Count != 5 {
+		t.Fatalf("expected 5 unreviewed scenes, got %d", response.ReviewCounts.States[0].Count)
+	}
+}
+
 func TestHomepageService_GetHomepageData_DisabledSectionsSkipped(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	settingsRepo := mocks.NewMockUserSettingsRepository(ctrl)
@@ -67,7 +102,7 @@ func TestHomepageService_GetHomepageData_DisabledSectionsSkipped(t *testing.T) {
 		zap.NewNop(),
 	)
 
-	response, err := svc.GetHomepageData(1)
+	response, err := svc.GetHomepageData(1, "user")
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -99,7 +134,7 @@ func TestHomepageService_GetSectionData_NotFound(t *testing.T) {
 		zap.NewNop(),
 	)
 
-	_, err := svc.GetSectionData(1, "nonexistent")
+	_, err := svc.GetSectionData(1, "user", "nonexistent")
 	if err == nil {
 		t.Fatal("expected error for nonexistent section")
 	}
@@ -130,7 +165,7 @@ func TestHomepageService_ContinueWatching_EmptyHistory(t *testing.T) {
 	// Return empty watch history
 	watchHistoryRepo.EXPECT().ListUserHistory(uint(1), 1, 15).Return([]data.UserSceneWatch{}, int64(0), nil)
 
-	result, err := svc.fetchContinueWatchingSection(1, section)
+	result, err := svc.fetchContinueWatchingSection(1, "user", section)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -179,7 +214,7 @@ func TestHomepageService_ContinueWatching_FiltersCompletedVideos(t *testing.T) {
 		{ID: 4, Title: "Video 4"},
 	}, nil)
 
-	result, err := svc.fetchContinueWatchingSection(1, section)
+	result, err := svc.fetchContinueWatchingSection(1, "user", section)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -192,6 +227,50 @@ func TestHomepageService_ContinueWatching_FiltersCompletedVideos(t *testing.T) {
 	}
 }
 
+func TestHomepageService_ContinueWatching_DropsRestrictedScenes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	watchHistoryRepo := mocks.NewMockWatchHistoryRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	rbac, _, restrictionRepo := newTestRestrictedRBACService(t, []data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", StoragePathIDs: []int64{1}},
+	})
+
+	svc := NewHomepageService(
+		nil, nil, nil, nil,
+		watchHistoryRepo,
+		nil,
+		sceneRepo,
+		nil, nil, nil,
+		zap.NewNop(),
+	)
+	svc.SetRBACService(rbac)
+
+	section := data.HomepageSection{
+		ID:      "continue",
+		Type:    "continue_watching",
+		Title:   "Continue Watching",
+		Enabled: true,
+		Limit:   5,
+	}
+
+	watchHistoryRepo.EXPECT().ListUserHistory(uint(1), 1, 15).Return([]data.UserSceneWatch{
+		{SceneID: 2, Completed: false, LastPosition: 50},
+		{SceneID: 4, Completed: false, LastPosition: 75},
+	}, int64(2), nil)
+	restrictionRepo.EXPECT().FilterVisibleSceneIDs(gomock.Any(), []uint{2, 4}).Return([]uint{4}, nil)
+
+	// Scene 2 is outside the guest role's storage paths and never fetched
+	sceneRepo.EXPECT().GetByIDs([]uint{4}).Return([]data.Scene{{ID: 4, Title: "Video 4"}}, nil)
+
+	result, err := svc.fetchContinueWatchingSection(1, "guest", section)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(result.Scenes) != 1 || result.Scenes[0].ID != 4 {
+		t.Fatalf("expected only scene 4, got %+v", result.Scenes)
+	}
+}
+
 func TestHomepageService_ContinueWatching_RespectsLimit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	watchHistoryRepo := mocks.NewMockWatchHistoryRepository(ctrl)
@@ -228,7 +307,7 @@ func TestHomepageService_ContinueWatching_RespectsLimit(t *testing.T) {
 		{ID: 2, Title: "Video 2"},
 	}, nil)
 
-	result, err := svc.fetchContinueWatchingSection(1, section)
+	result, err := svc.fetchContinueWatchingSection(1, "user", section)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -251,7 +330,7 @@ func TestHomepageService_FetchSectionData_UnknownType(t *testing.T) {
 		Limit:   10,
 	}
 
-	_, err := svc.fetchSectionData(1, section)
+	_, err := svc.fetchSectionData(1, "user", section)
 	if err == nil {
 		t.Fatal("expected error for unknown section type")
 	}
@@ -275,7 +354,7 @@ func TestHomepageService_ActorSection_MissingUUID(t *testing.T) {
 		Config:  map[string]interface{}{}, // No actor_uuid
 	}
 
-	_, err := svc.fetchActorSection(1, section)
+	_, err := svc.fetchActorSection(1, "user", section)
 	if err == nil {
 		t.Fatal("expected error for missing actor_uuid")
 	}
@@ -299,7 +378,7 @@ func TestHomepageService_StudioSection_MissingUUID(t *testing.T) {
 		Config:  map[string]interface{}{}, // No studio_uuid
 	}
 
-	_, err := svc.fetchStudioSection(1, section)
+	_, err := svc.fetchStudioSection(1, "user", section)
 	if err == nil {
 		t.Fatal("expected error for missing studio_uuid")
 	}
@@ -323,7 +402,7 @@ func TestHomepageService_TagSection_MissingID(t *testing.T) {
 		Config:  map[string]interface{}{}, // No tag_id
 	}
 
-	_, err := svc.fetchTagSection(1, section)
+	_, err := svc.fetchTagSection(1, "user", section)
 	if err == nil {
 		t.Fatal("expected error for missing tag_id")
 	}
@@ -347,7 +426,7 @@ func TestHomepageService_SavedSearchSection_MissingUUID(t *testing.T) {
 		Config:  map[string]interface{}{}, // No saved_search_uuid
 	}
 
-	_, err := svc.fetchSavedSearchSection(1, section)
+	_, err := svc.fetchSavedSearchSection(1, "user", section)
 	if err == nil {
 		t.Fatal("expected error for missing saved_search_uuid")
 	}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+)
+
+const (
+	// jobAnalyticsDefaultWindow is the period analytics cover by default
+	jobAnalyticsDefaultWindow = 7 * 24 * time.Hour
+	// jobAnalyticsMaxHourlyWindow keeps hourly timelines to a chartable size
+	jobAnalyticsMaxHourlyWindow = 14 * 24 * time.Hour
+)
+
+// JobPhaseAnalytics is a phase's performance over the window.
+type JobPhaseAnalytics struct {
+	data.JobPhaseStats
+	Finished    int64   `json:"finished"`
+	FailureRate float64 `json:"failure_rate"`  // failed and timed out jobs over finished ones, 0-1
+	JobsPerHour float64 `json:"jobs_per_hour"` // completed jobs per hour of the window
+}
+
+// JobAnalytics is the performance of each phase over a window.
+type JobAnalytics struct {
+	Since  time.Time           `json:"since"`
+	Phases []JobPhaseAnalytics `json:"phases"`
+}
+
+// ParseAnalyticsWindow parses the period analytics cover, like
+// job_history_retention ("7d", "12h"). Empty means 7 days.
+func ParseAnalyticsWindow(window string) (time.Duration, error) {
+	if window == "" {
+		return jobAnalyticsDefaultWindow, nil
+	}
+	d, err := config.ParseRetentionDuration(window)
+	if err != nil || d <= 0 {
+		return 0, apperrors.NewValidationErrorWithField("window", "must be a positive duration like 7d or 12h")
+	}
+	return d, nil
+}
+
+// GetAnalytics returns each phase's outcomes, failure rate, throughput and
+// duration percentiles over the jobs finished in the window.
+func (s *JobHistoryService) GetAnalytics(window time.Duration) (*JobAnalytics, error) {
+	since := time.Now().Add(-window)
+	stats, err := s.repo.PhaseStats(since)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to aggregate job history", err)
+	}
+
+	analytics := &JobAnalytics{Since: since, Phases: make([]JobPhaseAnalytics, 0, len(stats))}
+	for _, st := range stats {
+		phase := JobPhaseAnalytics{
+			JobPhaseStats: st,
+			Finished:      st.Completed + st.Failed + st.TimedOut + st.Cancelled,
+			JobsPerHour:   float64(st.Completed) / window.Hours(),
+		}
+		if phase.Finished > 0 {
+			phase.FailureRate = float64(st.Failed+st.TimedOut) / float64(phase.Finished)
+		}
+		analytics.Phases = append(analytics.Phases, phase)
+	}
+	return analytics, nil
+}
+
+// GetOutcomeTimeline returns the outcomes of the jobs finished in the window
+// per phase, by hour or day.
+func (s *JobHistoryService) GetOutcomeTimeline(window time.Duration, bucket string) ([]data.JobTimelineBucket, error) {
+	switch bucket {
+	case "":
+		bucket = "day"
+		if window <= 2*24*time.Hour {
+			bucket = "hour"
+		}
+	case "hour":
+		if window > jobAnalyticsMaxHourlyWindow {
+			return nil, apperrors.NewValidationErrorWithField("bucket", "hourly buckets cover at most 14 days")
+		}
+	case "day":
+	default:
+		return nil, apperrors.NewValidationErrorWithField("bucket", "must be hour or day")
+	}
+
+	buckets, err := s.repo.OutcomeTimeline(time.Now().Add(-window), bucket)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to aggregate job history", err)
+	}
+	if buckets == nil {
+		buckets = []data.JobTimelineBucket{}
+	}
+	return buckets, nil
+}
+
+// GetSlowestJobs returns the longest completed scene jobs finished in the
+// window, optionally of one phase.
+func (s *JobHistoryService) GetSlowestJobs(window time.Duration, phase string, limit int) ([]data.JobDuration, error) {
+	jobs, err := s.repo.SlowestJobs(time.Now().Add(-window), phase, limit)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list slowest jobs", err)
+	}
+	if jobs == nil {
+		jobs = []data.JobDuration{}
+	}
+	return jobs, nil
+}
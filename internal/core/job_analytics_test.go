@@ -0,0 +1,91 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestJobHistoryService_GetAnalytics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockJobHistoryRepository(ctrl)
+	svc := NewJobHistoryService(repo, config.ProcessingConfig{}, zap.NewNop())
+
+	p95 := 42.0
+	repo.EXPECT().PhaseStats(gomock.Any()).DoAndReturn(func(since time.Time) ([]data.JobPhaseStats, error) {
+		if age := time.Since(since); age < 24*time.Hour || age > 25*time.Hour {
+			t.Fatalf("expected the window to start a day ago, got %v", since)
+		}
+		return []data.JobPhaseStats{
+			{Phase: "metadata", Completed: 48, Failed: 1, TimedOut: 1, Cancelled: 0, P95Seconds: &p95},
+			{Phase: "sprites", Cancelled: 2},
+		}, nil
+	})
+
+	analytics, err := svc.GetAnalytics(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(analytics.Phases) != 2 {
+		t.Fatalf("expected 2 phases, got %d", len(analytics.Phases))
+	}
+	metadata := analytics.Phases[0]
+	if metadata.Finished != 50 || math.Abs(metadata.FailureRate-0.04) > 1e-9 || metadata.JobsPerHour != 2 {
+		t.Fatalf("unexpected metadata analytics %+v", metadata)
+	}
+	if *metadata.P95Seconds != 42 {
+		t.Fatalf("expected the percentiles passed through, got %v", *metadata.P95Seconds)
+	}
+	if sprites := analytics.Phases[1]; sprites.FailureRate != 0 || sprites.JobsPerHour != 0 {
+		t.Fatalf("unexpected sprites analytics %+v", sprites)
+	}
+}
+
+func TestJobHistoryService_GetOutcomeTimeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockJobHistoryRepository(ctrl)
+	svc := NewJobHistoryService(repo, config.ProcessingConfig{}, zap.NewNop())
+
+	// Short windows default to hourly buckets, longer ones to daily
+	repo.EXPECT().OutcomeTimeline(gomock.Any(), "hour").Return(nil, nil)
+	buckets, err := svc.GetOutcomeTimeline(24*time.Hour, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buckets == nil {
+		t.Fatal("expected an empty list, not nil")
+	}
+	repo.EXPECT().OutcomeTimeline(gomock.Any(), "day").Return(nil, nil)
+	if _, err := svc.GetOutcomeTimeline(30*24*time.Hour, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.GetOutcomeTimeline(30*24*time.Hour, "hour"); !apperrors.IsValidation(err) {
+		t.Fatalf("expected hourly buckets over 30 days to be rejected, got %v", err)
+	}
+	if _, err := svc.GetOutcomeTimeline(24*time.Hour, "week"); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an unknown bucket to be rejected, got %v", err)
+	}
+}
+
+func TestParseAnalyticsWindow(t *testing.T) {
+	if d, err := ParseAnalyticsWindow(""); err != nil || d != 7*24*time.Hour {
+		t.Fatalf("expected 7 days by default, got %v, %v", d, err)
+	}
+	if d, err := ParseAnalyticsWindow("12h"); err != nil || d != 12*time.Hour {
+		t.Fatalf("expected 12h, got %v, %v", d, err)
+	}
+	for _, window := range []string{"soon", "0", "-1d"} {
+		if _, err := ParseAnalyticsWindow(window); !apperrors.IsValidation(err) {
+			t.Fatalf("%q: expected a validation error, got %v", window, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/jobs"
+
+	"go.uber.org/zap"
+)
+
+// JobCancelFilter selects the processing jobs of a bulk cancel.
+type JobCancelFilter struct {
+	Phase          string
+	SceneIDs       []uint
+	OlderThan      string // e.g. "7d", "12h"
+	IncludeRunning bool   // also stop the jobs already executing
+	All            bool   // required to cancel without any other criteria
+}
+
+// JobCancelResult reports a bulk cancel.
+type JobCancelResult struct {
+	Pending int64 `json:"pending"` // jobs cancelled before a pool claimed them
+	Queued  int   `json:"queued"`  // jobs cancelled while waiting in a pool
+	Running int   `json:"running"` // executing jobs stopped
+}
+
+// CancelMatching cancels the processing jobs matching filter: the pending
+// ones in a single update, then the claimed ones waiting in the pools, and
+// with IncludeRunning the ones executing. Claiming is held off meanwhile so
+// no job moves from pending to a pool unseen. Jobs stopped in a pool are
+// recorded as cancelled once their worker returns them.
+func (f *JobQueueFeeder) CancelMatching(bulk JobCancelFilter) (*JobCancelResult, error) {
+	filter, err := parseJobCancelFilter(bulk)
+	if err != nil {
+		return nil, err
+	}
+
+	f.claimMu.Lock()
+	defer f.claimMu.Unlock()
+
+	result := &JobCancelResult{}
+	result.Pending, err = f.repo.CancelPendingByFilter(filter)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to cancel pending jobs", err)
+	}
+
+	claimed, err := f.repo.ListRunningByFilter(filter)
+	if err != nil {
+		return result, apperrors.NewInternalError("failed to list claimed jobs", err)
+	}
+	for _, record := range claimed {
+		job, ok := f.poolManager.GetJob(record.JobID)
+		if !ok {
+			continue
+		}
+		executing := job.GetStatus() != jobs.JobStatusPending
+		if executing && !bulk.IncludeRunning {
+			continue
+		}
+		if err := f.poolManager.CancelJob(record.JobID); err != nil {
+			continue
+		}
+		if executing {
+			result.Running++
+		} else {
+			result.Queued++
+		}
+	}
+
+	f.logger.Info("Cancelled jobs in bulk",
+		zap.String("phase", filter.Phase),
+		zap.Int("scenes", len(filter.SceneIDs)),
+		zap.Int64("pending", result.Pending),
+		zap.Int("queued", result.Queued),
+		zap.Int("running", result.Running),
+	)
+	return result, nil
+}
+
+func parseJobCancelFilter(bulk JobCancelFilter) (data.JobCancelFilter, error) {
+	filter := data.JobCancelFilter{
+		Phase:    bulk.Phase,
+		SceneIDs: bulk.SceneIDs,
+	}
+	if bulk.OlderThan != "" {
+		age, err := config.ParseRetentionDuration(bulk.OlderThan)
+		if err != nil || age <= 0 {
+			return data.JobCancelFilter{}, apperrors.NewValidationErrorWithField("older_than", "must be a positive duration like 7d or 12h")
+		}
+		filter.CreatedBefore = time.Now().Add(-age)
+	}
+	if filter.Phase == "" && len(filter.SceneIDs) == 0 && filter.CreatedBefore.IsZero() && !bulk.All {
+		return data.JobCancelFilter{}, apperrors.NewValidationError("set all to cancel every job, or at least one of phase, scene_ids or older_than")
+	}
+	return filter, nil
+}
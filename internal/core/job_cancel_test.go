@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+)
+
+func TestJobQueueFeeder_CancelMatching(t *testing.T) {
+	feeder, jobHistoryRepo, _ := newTestFeeder(t)
+
+	jobHistoryRepo.EXPECT().CancelPendingByFilter(data.JobCancelFilter{Phase: "sprites", SceneIDs: []uint{4}}).Return(int64(3), nil)
+	// A claimed job no pool holds any more finished meanwhile and is left alone
+	jobHistoryRepo.EXPECT().ListRunningByFilter(data.JobCancelFilter{Phase: "sprites", SceneIDs: []uint{4}}).
+		Return([]data.JobHistory{{JobID: "gone", SceneID: 4, Phase: "sprites"}}, nil)
+
+	result, err := feeder.CancelMatching(JobCancelFilter{Phase: "sprites", SceneIDs: []uint{4}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Pending != 3 || result.Queued != 0 || result.Running != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestParseJobCancelFilter(t *testing.T) {
+	if _, err := parseJobCancelFilter(JobCancelFilter{}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an empty filter without all to be rejected, got %v", err)
+	}
+	if filter, err := parseJobCancelFilter(JobCancelFilter{All: true}); err != nil || filter.Phase != "" || len(filter.SceneIDs) != 0 {
+		t.Fatalf("expected all to match every job, got %+v, %v", filter, err)
+	}
+
+	filter, err := parseJobCancelFilter(JobCancelFilter{OlderThan: "2h"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if age := time.Since(filter.CreatedBefore); age < 2*time.Hour || age > 2*time.Hour+time.Minute {
+		t.Fatalf("expected jobs created over 2h ago, got %v", filter.CreatedBefore)
+	}
+	if _, err := parseJobCancelFilter(JobCancelFilter{OlderThan: "soon"}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an invalid older_than to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// jobHistoryPruneInterval is how often finished jobs past the retention window are pruned
+const jobHistoryPruneInterval = time.Hour
+
+// jobHistoryPruneBatch is how many jobs are pruned per transaction
+const jobHistoryPruneBatch = 1000
+
+// JobHistoryRetentionStats reports the retention policy and the rows it
+// reclaimed, on the last run and since startup.
+type JobHistoryRetentionStats struct {
+	Retention string              `json:"retention"`
+	Archive   bool                `json:"archive"`
+	LastRunAt *time.Time          `json:"last_run_at"`
+	LastRun   data.JobPruneResult `json:"last_run"`
+	LastError string              `json:"last_error,omitempty"`
+	Total     data.JobPruneResult `json:"total"`
+}
+
+// JobHistoryRetentionWorker prunes the finished jobs of job_history older than
+// processing.job_history_retention, moving them to job_history_archive first
+// with processing.job_history_archive. Failed and timed out jobs leave a
+// failure summary behind either way. Queued, running and retry-scheduled jobs
+// are never pruned.
+type JobHistoryRetentionWorker struct {
+	repo         data.JobHistoryRepository
+	retention    time.Duration
+	retentionStr string
+	archive      bool
+	logger       *zap.Logger
+
+	runMu sync.Mutex // one prune at a time
+	mu    sync.Mutex
+	stats JobHistoryRetentionStats
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewJobHistoryRetentionWorker(repo data.JobHistoryRepository, cfg config.ProcessingConfig, logger *zap.Logger) *JobHistoryRetentionWorker {
+	retention, err := config.ParseRetentionDuration(cfg.JobHistoryRetention)
+	if err != nil {
+		logger.Warn("Failed to parse job_history_retention, using default 7d",
+			zap.String("value", cfg.JobHistoryRetention),
+			zap.Error(err),
+		)
+		retention = 7 * 24 * time.Hour
+	}
+
+	retentionStr := cfg.JobHistoryRetention
+	if retentionStr == "" {
+		retentionStr = "7d"
+	}
+
+	return &JobHistoryRetentionWorker{
+		repo:         repo,
+		retention:    retention,
+		retentionStr: retentionStr,
+		archive:      cfg.JobHistoryArchive,
+		logger:       logger.With(zap.String("component", "job_history_retention")),
+		stats: JobHistoryRetentionStats{
+			Retention: retentionStr,
+			Archive:   cfg.JobHistoryArchive,
+		},
+	}
+}
+
+// Start launches the background prune loop, pruning once right away
+func (w *JobHistoryRetentionWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(jobHistoryPruneInterval)
+		defer ticker.Stop()
+
+		w.Run()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.Run()
+			}
+		}
+	}()
+
+	w.logger.Info("Job history retention worker started",
+		zap.Duration("retention", w.retention),
+		zap.Bool("archive", w.archive),
+	)
+}
+
+// Stop halts the background loop, waiting for a running prune
+func (w *JobHistoryRetentionWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+		w.wg.Wait()
+	}
+}
+
+// Run prunes the finished jobs past the retention window in batches and
+// returns the rows reclaimed. A retention of 0 keeps every job. On error the
+// batches pruned before it are still counted.
+func (w *JobHistoryRetentionWorker) Run() (data.JobPruneResult, error) {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	var result data.JobPruneResult
+	if w.retention <= 0 {
+		return result, nil
+	}
+
+	before := time.Now().Add(-w.retention)
+	var err error
+	for {
+		batch, batchErr := w.repo.PruneFinished(before, w.archive, jobHistoryPruneBatch)
+		result.Deleted += batch.Deleted
+		result.Archived += batch.Archived
+		result.Summarized += batch.Summarized
+		if batchErr != nil {
+			err = batchErr
+			break
+		}
+		if batch.Deleted < jobHistoryPruneBatch {
+			break
+		}
+	}
+	w.record(result, err)
+
+	if err != nil {
+		w.logger.Error("Failed to prune job history", zap.Error(err))
+		return result, apperrors.NewInternalError("failed to prune job history", err)
+	}
+	if result.Deleted > 0 {
+		w.logger.Info("Pruned job history",
+			zap.Int64("deleted", result.Deleted),
+			zap.Int64("archived", result.Archived),
+			zap.Int64("summarized", result.Summarized),
+			zap.Time("before", before),
+		)
+	}
+	return result, nil
+}
+
+func (w *JobHistoryRetentionWorker) record(result data.JobPruneResult, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := time.Now()
+	w.stats.LastRunAt = &now
+	w.stats.LastRun = result
+	w.stats.LastError = ""
+	if err != nil {
+		w.stats.LastError = err.Error()
+	}
+	w.stats.Total.Deleted += result.Deleted
+	w.stats.Total.Archived += result.Archived
+	w.stats.Total.Summarized += result.Summarized
+}
+
+// Stats returns the retention policy and what it reclaimed
+func (w *JobHistoryRetentionWorker) Stats() JobHistoryRetentionStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestJobHistoryRetentionWorker_PrunesInBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockJobHistoryRepository(ctrl)
+	w := NewJobHistoryRetentionWorker(repo, config.ProcessingConfig{JobHistoryRetention: "2d", JobHistoryArchive: true}, zap.NewNop())
+
+	cutoff := time.Now().Add(-48 * time.Hour)
+	checkCutoff := func(before time.Time) {
+		if before.Sub(cutoff).Abs() > time.Minute {
+			t.Fatalf("expected cutoff near %v, got %v", cutoff, before)
+		}
+	}
+	gomock.InOrder(
+		repo.EXPECT().PruneFinished(gomock.Any(), true, jobHistoryPruneBatch).DoAndReturn(func(before time.Time, archive bool, limit int) (data.JobPruneResult, error) {
+			checkCutoff(before)
+			return data.JobPruneResult{Deleted: jobHistoryPruneBatch, Archived: jobHistoryPruneBatch, Summarized: 3}, nil
+		}),
+		repo.EXPECT().PruneFinished(gomock.Any(), true, jobHistoryPruneBatch).Return(data.JobPruneResult{Deleted: 10, Archived: 10, Summarized: 1}, nil),
+	)
+
+	result, err := w.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := data.JobPruneResult{Deleted: jobHistoryPruneBatch + 10, Archived: jobHistoryPruneBatch + 10, Summarized: 4}
+	if result != want {
+		t.Fatalf("expected %+v, got %+v", want, result)
+	}
+
+	// A second run adds to the totals
+	repo.EXPECT().PruneFinished(gomock.Any(), true, jobHistoryPruneBatch).Return(data.JobPruneResult{Deleted: 2, Archived: 2}, nil)
+	if _, err := w.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := w.Stats()
+	if stats.Retention != "2d" || !stats.Archive || stats.LastRunAt == nil || stats.LastError != "" {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if stats.LastRun.Deleted != 2 || stats.Total.Deleted != want.Deleted+2 || stats.Total.Summarized != 4 {
+		t.Fatalf("unexpected counts %+v", stats)
+	}
+}
+
+func TestJobHistoryRetentionWorker_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockJobHistoryRepository(ctrl)
+	w := NewJobHistoryRetentionWorker(repo, config.ProcessingConfig{}, zap.NewNop())
+
+	gomock.InOrder(
+		repo.EXPECT().PruneFinished(gomock.Any(), false, jobHistoryPruneBatch).Return(data.JobPruneResult{Deleted: jobHistoryPruneBatch}, nil),
+		repo.EXPECT().PruneFinished(gomock.Any(), false, jobHistoryPruneBatch).Return(data.JobPruneResult{}, errors.New("connection reset")),
+	)
+
+	result, err := w.Run()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	// The batches pruned before the failure still count
+	if result.Deleted != jobHistoryPruneBatch {
+		t.Fatalf("expected %d deleted, got %d", jobHistoryPruneBatch, result.Deleted)
+	}
+	if stats := w.Stats(); stats.Retention != "7d" || stats.LastError == "" || stats.Total.Deleted != jobHistoryPruneBatch {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestJobHistoryRetentionWorker_KeepForever(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockJobHistoryRepository(ctrl)
+	w := NewJobHistoryRetentionWorker(repo, config.ProcessingConfig{JobHistoryRetention: "0"}, zap.NewNop())
+
+	// No PruneFinished call is expected
+	result, err := w.Run()
+	if err != nil || result != (data.JobPruneResult{}) {
+		t.Fatalf("unexpected result %+v, %v", result, err)
+	}
+}
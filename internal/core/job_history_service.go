@@ -1,7 +1,7 @@
 package core
 
 import (
-	"context"
+	"errors"
 	"time"
 
 	"goonhub/internal/apperrors"
@@ -9,28 +9,19 @@ import (
 	"goonhub/internal/data"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type JobHistoryService struct {
 	repo              data.JobHistoryRepository
-	retention         time.Duration
+	logRepo           data.JobLogRepository
 	retentionStr      string
 	logger            *zap.Logger
-	cancel            context.CancelFunc
 	retryScheduler    *RetryScheduler
 	processingService *SceneProcessingService
 }
 
 func NewJobHistoryService(repo data.JobHistoryRepository, cfg config.ProcessingConfig, logger *zap.Logger) *JobHistoryService {
-	retention, err := config.ParseRetentionDuration(cfg.JobHistoryRetention)
-	if err != nil {
-		logger.Warn("Failed to parse job_history_retention, using default 7d",
-			zap.String("value", cfg.JobHistoryRetention),
-			zap.Error(err),
-		)
-		retention = 7 * 24 * time.Hour
-	}
-
 	retentionStr := cfg.JobHistoryRetention
 	if retentionStr == "" {
 		retentionStr = "7d"
@@ -38,7 +29,6 @@ func NewJobHistoryService(repo data.JobHistoryRepository, cfg config.ProcessingC
 
 	return &JobHistoryService{
 		repo:         repo,
-		retention:    retention,
 		retentionStr: retentionStr,
 		logger:       logger.With(zap.String("component", "job_history")),
 	}
@@ -107,52 +97,25 @@ func (s *JobHistoryService) RecordJobTimedOut(jobID string) {
 	}
 }
 
-func (s *JobHistoryService) StartCleanupTicker() {
-	ctx, cancel := context.WithCancel(context.Background())
-	s.cancel = cancel
-
-	// Run cleanup immediately on startup
-	s.Cleanup()
-
-	go func() {
-		ticker := time.NewTicker(1 * time.Hour)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				s.Cleanup()
-			}
-		}
-	}()
-
-	s.logger.Info("Job history cleanup ticker started",
-		zap.Duration("retention", s.retention),
-	)
-}
-
-func (s *JobHistoryService) StopCleanupTicker() {
-	if s.cancel != nil {
-		s.cancel()
-		s.logger.Info("Job history cleanup ticker stopped")
-	}
+func (s *JobHistoryService) ListJobs(page, limit int, status string) ([]data.JobHistory, int64, error) {
+	return s.repo.ListAll(page, limit, status)
 }
 
-func (s *JobHistoryService) Cleanup() {
-	before := time.Now().Add(-s.retention)
-	deleted, err := s.repo.DeleteOlderThan(before)
+// ListJobsAfter pages job history by cursor, newest first. It returns the
+// next page's cursor, empty on the last page.
+func (s *JobHistoryService) ListJobsAfter(cursor string, limit int, status string) ([]data.JobHistory, string, error) {
+	after, err := data.DecodeCursor(cursor, data.JobHistoryCursorSort)
 	if err != nil {
-		s.logger.Error("Failed to cleanup job history", zap.Error(err))
-		return
+		return nil, "", apperrors.NewValidationErrorWithField("cursor", "invalid cursor")
 	}
-	if deleted > 0 {
-		s.logger.Info("Cleaned up old job history records", zap.Int64("deleted", deleted))
+	jobs, next, err := s.repo.ListAfter(after, limit, status)
+	if err != nil {
+		if errors.Is(err, data.ErrInvalidCursor) {
+			return nil, "", apperrors.NewValidationErrorWithField("cursor", "invalid cursor")
+		}
+		return nil, "", apperrors.NewInternalError("failed to list jobs", err)
 	}
-}
-
-func (s *JobHistoryService) ListJobs(page, limit int, status string) ([]data.JobHistory, int64, error) {
-	return s.repo.ListAll(page, limit, status)
+	return jobs, next.Encode(), nil
 }
 
 func (s *JobHistoryService) ListActiveJobs() ([]data.JobHistory, error) {
@@ -168,6 +131,11 @@ func (s *JobHistoryService) SetRetryScheduler(scheduler *RetryScheduler) {
 	s.retryScheduler = scheduler
 }
 
+// SetLogRepository sets where the logs of job runs are stored.
+func (s *JobHistoryService) SetLogRepository(repo data.JobLogRepository) {
+	s.logRepo = repo
+}
+
 // SetProcessingService sets the processing service for manual job retries.
 func (s *JobHistoryService) SetProcessingService(service *SceneProcessingService) {
 	s.processingService = service
@@ -189,6 +157,26 @@ func (s *JobHistoryService) RetryJob(jobID string) error {
 		return apperrors.NewValidationError("only failed jobs can be retried")
 	}
 
+	if job.Phase == MarkerCompilationPhase {
+		return apperrors.NewValidationError("marker compilations can't be retried, start a new one instead")
+	}
+
+	if job.Phase == PornDBMatchPhase {
+		return apperrors.NewValidationError("PornDB auto-match runs can't be retried, start a new one instead")
+	}
+
+	if job.Phase == ActorImagePhase {
+		return apperrors.NewValidationError("actor image runs can't be retried, start a new one instead")
+	}
+
+	if job.Phase == RemoteImportPhase {
+		return apperrors.NewValidationError("URL imports can't be retried, import the URL again instead")
+	}
+
+	if job.Phase == StorageMigrationPhase {
+		return apperrors.NewValidationError("storage migrations can't be retried, start a new one instead")
+	}
+
 	if s.processingService == nil {
 		return apperrors.NewInternalError("processing service not configured", nil)
 	}
@@ -293,8 +281,47 @@ func (s *JobHistoryService) GetByJobID(jobID string) (*data.JobHistory, error) {
 	return s.repo.GetByJobID(jobID)
 }
 
+// RecordJobLog stores the log of a finished job run.
+func (s *JobHistoryService) RecordJobLog(jobID string, content string, truncated bool) {
+	if s.logRepo == nil {
+		return
+	}
+	record := &data.JobLog{
+		JobID:     jobID,
+		Content:   content,
+		Truncated: truncated,
+		CreatedAt: time.Now(),
+	}
+	if err := s.logRepo.Save(record); err != nil {
+		s.logger.Error("Failed to record job log",
+			zap.String("job_id", jobID),
+			zap.Error(err),
+		)
+	}
+}
+
+// GetJobLog returns the log of a job's last run.
+func (s *JobHistoryService) GetJobLog(jobID string) (*data.JobLog, error) {
+	if s.logRepo == nil {
+		return nil, apperrors.NewInternalError("job logs not configured", nil)
+	}
+	if _, err := s.repo.GetByJobID(jobID); err != nil {
+		return nil, apperrors.NewNotFoundError("job", jobID)
+	}
+	log, err := s.logRepo.GetByJobID(jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("job log", jobID)
+		}
+		return nil, apperrors.NewInternalError("failed to get job log", err)
+	}
+	return log, nil
+}
+
 // CreatePendingJob creates a job with status='pending' in the database.
 // Used for DB-backed job queue where jobs are created pending and later claimed by the feeder.
+// Returns data.ErrActiveJobExists when the scene already has a pending or running
+// job of the phase with the same parameters.
 func (s *JobHistoryService) CreatePendingJob(jobID string, sceneID uint, sceneTitle string, phase string, forceTarget string) error {
 	return s.CreatePendingJobWithPriority(jobID, sceneID, sceneTitle, phase, 0, forceTarget)
 }
@@ -315,6 +342,9 @@ func (s *JobHistoryService) CreatePendingJobWithPriority(jobID string, sceneID u
 		ForceTarget: forceTarget,
 	}
 	if err := s.repo.CreatePending(record); err != nil {
+		if errors.Is(err, data.ErrActiveJobExists) {
+			return err
+		}
 		s.logger.Error("Failed to create pending job",
 			zap.String("job_id", jobID),
 			zap.Uint("scene_id", sceneID),
@@ -350,6 +380,9 @@ func (s *JobHistoryService) CreatePendingJobWithRetry(jobID string, sceneID uint
 		ForceTarget: forceTarget,
 	}
 	if err := s.repo.CreatePending(record); err != nil {
+		if errors.Is(err, data.ErrActiveJobExists) {
+			return err
+		}
 		s.logger.Error("Failed to create pending job with retry info",
 			zap.String("job_id", jobID),
 			zap.Uint("scene_id", sceneID),
@@ -370,12 +403,6 @@ func (s *JobHistoryService) CreatePendingJobWithRetry(jobID string, sceneID uint
 	return nil
 }
 
-// ExistsPendingOrRunning checks if a pending or running job exists for scene+phase.
-// Used for deduplication before creating new pending jobs.
-func (s *JobHistoryService) ExistsPendingOrRunning(sceneID uint, phase string) (bool, error) {
-	return s.repo.ExistsPendingOrRunning(sceneID, phase)
-}
-
 // CountPendingByPhase returns the count of pending jobs per phase.
 func (s *JobHistoryService) CountPendingByPhase() (map[string]int, error) {
 	return s.repo.CountPendingByPhase()
@@ -405,6 +432,9 @@ func (s *JobHistoryService) RetryAllFailed() (int, error) {
 
 	retried := 0
 	for _, job := range jobs {
+		if job.Phase == MarkerCompilationPhase || job.Phase == PornDBMatchPhase || job.Phase == ActorImagePhase || job.Phase == RemoteImportPhase || job.Phase == StorageMigrationPhase {
+			continue
+		}
 		if err := s.repo.MarkNotRetryable(job.JobID); err != nil {
 			s.logger.Error("Failed to mark job as not retryable during bulk retry",
 				zap.String("job_id", job.JobID),
@@ -466,3 +496,16 @@ func (s *JobHistoryService) ClearFailed() (int64, error) {
 	s.logger.Info("Cleared failed jobs", zap.Int64("deleted", deleted))
 	return deleted, nil
 }
+
+// ListFailureSummaries returns what failed among the jobs retention pruned,
+// most recent first, optionally of one phase.
+func (s *JobHistoryService) ListFailureSummaries(phase string, limit int) ([]data.JobFailureSummary, error) {
+	summaries, err := s.repo.ListFailureSummaries(phase, limit)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list job failure summaries", err)
+	}
+	if summaries == nil {
+		summaries = []data.JobFailureSummary{}
+	}
+	return summaries, nil
+}
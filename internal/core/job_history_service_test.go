@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestJobHistoryService_JobLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockJobHistoryRepository(ctrl)
+	logRepo := mocks.NewMockJobLogRepository(ctrl)
+	svc := NewJobHistoryService(repo, config.ProcessingConfig{}, zap.NewNop())
+	svc.SetLogRepository(logRepo)
+
+	logRepo.EXPECT().Save(gomock.Any()).DoAndReturn(func(log *data.JobLog) error {
+		if log.JobID != "job-1" || log.Content != "$ ffprobe scene.mp4\n" || !log.Truncated {
+			t.Fatalf("unexpected log %+v", log)
+		}
+		return nil
+	})
+	svc.RecordJobLog("job-1", "$ ffprobe scene.mp4\n", true)
+
+	repo.EXPECT().GetByJobID("job-1").Return(&data.JobHistory{JobID: "job-1"}, nil)
+	logRepo.EXPECT().GetByJobID("job-1").Return(&data.JobLog{JobID: "job-1", Content: "done\n"}, nil)
+	log, err := svc.GetJobLog("job-1")
+	if err != nil || log.Content != "done\n" {
+		t.Fatalf("unexpected log %+v, %v", log, err)
+	}
+
+	// A job that ran before logs were captured has none
+	repo.EXPECT().GetByJobID("job-2").Return(&data.JobHistory{JobID: "job-2"}, nil)
+	logRepo.EXPECT().GetByJobID("job-2").Return(nil, gorm.ErrRecordNotFound)
+	if _, err := svc.GetJobLog("job-2"); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected a missing log to be not found, got %v", err)
+	}
+
+	repo.EXPECT().GetByJobID("missing").Return(nil, gorm.ErrRecordNotFound)
+	if _, err := svc.GetJobLog("missing"); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected an unknown job to be not found, got %v", err)
+	}
+}
@@ -13,6 +13,11 @@ import (
 	"go.uber.org/zap"
 )
 
+// feederPhases are the phases whose pending jobs the feeder claims. Jobs of
+// other phases (URL imports, compilations, ...) are recorded as running by
+// their own services and never pass through the pending queue.
+var feederPhases = []string{"metadata", "thumbnail", "sprites", "animated_thumbnails", "verify", "checksum"}
+
 // JobQueueFeeder polls the database for pending jobs and feeds them to worker pools.
 // It acts as a bridge between the infinite-capacity DB queue and the bounded worker pool channels.
 type JobQueueFeeder struct {
@@ -20,16 +25,24 @@ type JobQueueFeeder struct {
 	sceneRepo         data.SceneRepository
 	markerThumbGen    jobs.MarkerThumbnailGenerator
 	animatedThumbGen  jobs.AnimatedThumbnailGenerator
+	integrityRepo     data.SceneIntegrityRepository
 	poolManager       *processing.PoolManager
+	eventBus          *EventBus
+	artifactService   *ArtifactService
+	storageHealth     *StorageHealthService
 	logger            *zap.Logger
 
 	pollInterval     time.Duration
 	batchSize        int
 	bufferMultiplier int // Max buffered jobs per worker (threshold = workerCount * bufferMultiplier)
 
-	// Configurable timeouts for orphan/stuck job recovery
-	orphanTimeout    time.Duration
-	stuckPendingTime time.Duration
+	// Configurable timeout for orphaned job recovery
+	orphanTimeout time.Duration
+	maxRequeues   int // Times a job left running by a crash is put back in the queue
+
+	// claimMu is held for reading from claiming jobs to submitting them, so a
+	// bulk cancel never misses a job between the two
+	claimMu sync.RWMutex
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -42,7 +55,9 @@ func NewJobQueueFeeder(
 	sceneRepo data.SceneRepository,
 	markerThumbGen jobs.MarkerThumbnailGenerator,
 	animatedThumbGen jobs.AnimatedThumbnailGenerator,
+	integrityRepo data.SceneIntegrityRepository,
 	poolManager *processing.PoolManager,
+	eventBus *EventBus,
 	logger *zap.Logger,
 ) *JobQueueFeeder {
 	return &JobQueueFeeder{
@@ -50,13 +65,15 @@ func NewJobQueueFeeder(
 		sceneRepo:        sceneRepo,
 		markerThumbGen:   markerThumbGen,
 		animatedThumbGen: animatedThumbGen,
+		integrityRepo:    integrityRepo,
 		poolManager:      poolManager,
+		eventBus:         eventBus,
 		logger:           logger.With(zap.String("component", "job_queue_feeder")),
 		pollInterval:     2 * time.Second,
 		batchSize:        50,
 		bufferMultiplier: 10, // Keep up to workerCount*10 jobs buffered per phase
 		orphanTimeout:    30 * time.Second,
-		stuckPendingTime: 10 * time.Minute,
+		maxRequeues:      3,
 	}
 }
 
@@ -65,9 +82,19 @@ func (f *JobQueueFeeder) SetOrphanTimeout(d time.Duration) {
 	f.orphanTimeout = d
 }
 
-// SetStuckPendingTime sets the threshold for detecting stuck pending jobs
-func (f *JobQueueFeeder) SetStuckPendingTime(d time.Duration) {
-	f.stuckPendingTime = d
+// SetMaxRequeues sets how many times a job orphaned by a crash is put back in the queue
+func (f *JobQueueFeeder) SetMaxRequeues(n int) {
+	f.maxRequeues = n
+}
+
+// SetArtifactService enables holding back phases while the metadata quota is exceeded
+func (f *JobQueueFeeder) SetArtifactService(artifactService *ArtifactService) {
+	f.artifactService = artifactService
+}
+
+// SetStorageHealth leaves jobs of scenes on offline storage paths pending
+func (f *JobQueueFeeder) SetStorageHealth(storageHealth *StorageHealthService) {
+	f.storageHealth = storageHealth
 }
 
 // Start starts the feeder goroutines for each processing phase
@@ -78,8 +105,7 @@ func (f *JobQueueFeeder) Start() {
 	f.recoverOrphanedJobs()
 
 	// Start a feeder goroutine for each phase
-	phases := []string{"metadata", "thumbnail", "sprites", "animated_thumbnails"}
-	for _, phase := range phases {
+	for _, phase := range feederPhases {
 		f.wg.Add(1)
 		go f.runFeeder(phase)
 	}
@@ -99,27 +125,30 @@ func (f *JobQueueFeeder) Stop() {
 	f.logger.Info("Job queue feeder stopped")
 }
 
-// recoverOrphanedJobs marks jobs that were running when the server crashed as failed
+// recoverOrphanedJobs recovers jobs that were claimed when the server crashed.
+// Pending jobs need no recovery: they stay in job_history and are fed again in order.
 func (f *JobQueueFeeder) recoverOrphanedJobs() {
-	// Recover orphaned running jobs (using configurable timeout, default 30s)
-	count, err := f.repo.MarkOrphanedRunningAsFailed(f.orphanTimeout)
+	// Put claimed jobs back in the queue; most were still waiting in a channel buffer.
+	// Jobs of phases the feeder doesn't claim would stay pending forever.
+	requeued, err := f.repo.RequeueOrphanedRunningJobs(f.orphanTimeout, f.maxRequeues, feederPhases)
 	if err != nil {
-		f.logger.Error("Failed to recover orphaned running jobs", zap.Error(err))
-	} else if count > 0 {
-		f.logger.Info("Recovered orphaned running jobs from previous run",
-			zap.Int64("count", count),
+		f.logger.Error("Failed to requeue orphaned running jobs", zap.Error(err))
+	} else if requeued > 0 {
+		f.logger.Info("Requeued orphaned running jobs from previous run",
+			zap.Int64("count", requeued),
 			zap.Duration("timeout", f.orphanTimeout),
 		)
 	}
 
-	// Recover stuck pending jobs (jobs stuck in pending state for too long)
-	stuckCount, err := f.repo.MarkStuckPendingJobsAsFailed(f.stuckPendingTime)
+	// Whatever is left has been requeued too often or belongs to another phase,
+	// fail it (retryable)
+	count, err := f.repo.MarkOrphanedRunningAsFailed(f.orphanTimeout)
 	if err != nil {
-		f.logger.Error("Failed to recover stuck pending jobs", zap.Error(err))
-	} else if stuckCount > 0 {
-		f.logger.Info("Recovered stuck pending jobs from previous run",
-			zap.Int64("count", stuckCount),
-			zap.Duration("threshold", f.stuckPendingTime),
+		f.logger.Error("Failed to recover orphaned running jobs", zap.Error(err))
+	} else if count > 0 {
+		f.logger.Warn("Failed orphaned running jobs that exceeded the requeue limit",
+			zap.Int64("count", count),
+			zap.Int("max_requeues", f.maxRequeues),
 		)
 	}
 }
@@ -143,6 +172,11 @@ func (f *JobQueueFeeder) runFeeder(phase string) {
 
 // feedPhase checks if the worker pool has capacity and claims pending jobs
 func (f *JobQueueFeeder) feedPhase(phase string) {
+	// Leave jobs pending while metadata storage is over quota
+	if f.artifactService != nil && f.artifactService.PhasePaused(phase) {
+		return
+	}
+
 	// Get current queue status and pool config to determine capacity
 	queueStatus := f.poolManager.GetQueueStatus()
 	poolConfig := f.poolManager.GetPoolConfig()
@@ -162,6 +196,12 @@ func (f *JobQueueFeeder) feedPhase(phase string) {
 	case "animated_thumbnails":
 		currentQueued = queueStatus.AnimatedThumbnailsQueued
 		workerCount = poolConfig.AnimatedThumbnailsWorkers
+	case "verify":
+		currentQueued = queueStatus.VerifyQueued
+		workerCount = poolConfig.VerifyWorkers
+	case "checksum":
+		currentQueued = queueStatus.ChecksumQueued
+		workerCount = poolConfig.ChecksumWorkers
 	}
 
 	// Dynamic threshold: only buffer a small multiple of the worker count.
@@ -181,8 +221,21 @@ func (f *JobQueueFeeder) feedPhase(phase string) {
 	spaceAvailable := threshold - currentQueued
 	claimLimit := min(spaceAvailable, f.batchSize)
 
-	// Claim pending jobs from DB
-	claimedJobs, err := f.repo.ClaimPendingJobs(phase, claimLimit)
+	f.claimMu.RLock()
+	defer f.claimMu.RUnlock()
+
+	// Claim pending jobs from DB, skipping scenes on offline storage paths
+	var offlinePaths []uint
+	if f.storageHealth != nil {
+		offlinePaths = f.storageHealth.OfflineIDs()
+	}
+	var claimedJobs []data.JobHistory
+	var err error
+	if len(offlinePaths) > 0 {
+		claimedJobs, err = f.repo.ClaimPendingJobsExcluding(phase, claimLimit, offlinePaths)
+	} else {
+		claimedJobs, err = f.repo.ClaimPendingJobs(phase, claimLimit)
+	}
 	if err != nil {
 		f.logger.Error("Failed to claim pending jobs",
 			zap.String("phase", phase),
@@ -334,27 +387,74 @@ func (f *JobQueueFeeder) submitJobToPool(jobRecord data.JobHistory, scene *data.
 			f.sceneRepo,
 			f.logger,
 		)
-		spritesJob.SetProgressCallback(func(jobID string, progress int) {
-			if err := f.repo.UpdateProgress(jobID, progress); err != nil {
-				f.logger.Warn("Failed to update sprite job progress",
-					zap.String("job_id", jobID), zap.Int("progress", progress), zap.Error(err))
-			}
-		})
+		spritesJob.SetTrickplayEnabled(cfg.TrickplayEnabled)
+		spritesJob.SetProgressCallback(f.progressCallback(jobRecord))
 		return f.poolManager.SubmitToSpritesPool(spritesJob)
 
 	case "animated_thumbnails":
 		if scene.Duration == 0 {
 			return fmt.Errorf("scene duration is 0: metadata not yet extracted")
 		}
-		job = jobs.NewAnimatedThumbnailJobWithID(
+		animatedJob := jobs.NewAnimatedThumbnailJobWithID(
 			jobRecord.JobID,
 			jobRecord.SceneID,
 			jobRecord.ForceTarget,
 			f.animatedThumbGen,
 			f.logger,
 		)
-		return f.poolManager.SubmitToAnimatedThumbnailsPool(job)
+		animatedJob.SetProgressCallback(f.progressCallback(jobRecord))
+		return f.poolManager.SubmitToAnimatedThumbnailsPool(animatedJob)
+
+	case "verify":
+		verifyJob := jobs.NewVerifyJobWithID(
+			jobRecord.JobID,
+			jobRecord.SceneID,
+			scene.StoredPath,
+			scene.Duration,
+			f.sceneRepo,
+			f.integrityRepo,
+			f.logger,
+		)
+		verifyJob.SetProgressCallback(f.progressCallback(jobRecord))
+		return f.poolManager.SubmitToVerifyPool(verifyJob)
+
+	case "checksum":
+		checksumJob := jobs.NewChecksumJobWithID(
+			jobRecord.JobID,
+			jobRecord.SceneID,
+			scene.StoredPath,
+			scene.FileHash,
+			f.sceneRepo,
+			f.logger,
+		)
+		checksumJob.SetProgressCallback(f.progressCallback(jobRecord))
+		return f.poolManager.SubmitToChecksumPool(checksumJob)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// progressCallback persists a job's progress and publishes it over SSE so the
+// jobs dashboard can update its progress bars between status polls.
+func (f *JobQueueFeeder) progressCallback(jobRecord data.JobHistory) jobs.ProgressCallback {
+	return func(jobID string, progress int) {
+		if err := f.repo.UpdateProgress(jobID, progress); err != nil {
+			f.logger.Warn("Failed to update job progress",
+				zap.String("job_id", jobID),
+				zap.String("phase", jobRecord.Phase),
+				zap.Int("progress", progress),
+				zap.Error(err))
+		}
+		if f.eventBus != nil {
+			f.eventBus.Publish(SceneEvent{
+				Type:    "job:progress",
+				SceneID: jobRecord.SceneID,
+				Data: map[string]any{
+					"job_id":   jobID,
+					"phase":    jobRecord.Phase,
+					"progress": progress,
+				},
+			})
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package core
 
 import (
+	"errors"
 	"goonhub/internal/config"
 	"goonhub/internal/core/processing"
 	"goonhub/internal/data"
 	"goonhub/internal/mocks"
 	"strings"
 	"testing"
+	"time"
 
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
@@ -39,7 +41,7 @@ func newTestFeeder(t *testing.T) (*JobQueueFeeder, *mocks.MockJobHistoryReposito
 
 	poolManager := processing.NewPoolManager(cfg, zap.NewNop(), nil, nil)
 
-	feeder := NewJobQueueFeeder(jobHistoryRepo, sceneRepo, nil, nil, poolManager, zap.NewNop())
+	feeder := NewJobQueueFeeder(jobHistoryRepo, sceneRepo, nil, nil, nil, poolManager, nil, zap.NewNop())
 	return feeder, jobHistoryRepo, sceneRepo
 }
 
@@ -164,3 +166,62 @@ func TestSubmitJobToPool_SpritesWithDuration(t *testing.T) {
 		t.Fatalf("expected no error for sprites job with valid duration, got: %v", err)
 	}
 }
+
+func TestRecoverOrphanedJobs_RequeuesBeforeFailing(t *testing.T) {
+	feeder, jobHistoryRepo, _ := newTestFeeder(t)
+	feeder.SetOrphanTimeout(30 * time.Second)
+	feeder.SetMaxRequeues(3)
+
+	gomock.InOrder(
+		jobHistoryRepo.EXPECT().RequeueOrphanedRunningJobs(30*time.Second, 3, feederPhases).Return(int64(120), nil),
+		jobHistoryRepo.EXPECT().MarkOrphanedRunningAsFailed(30*time.Second).Return(int64(1), nil),
+	)
+
+	// Pending jobs must not be touched on startup, so no other repository calls are expected
+	feeder.recoverOrphanedJobs()
+}
+
+func TestFeederPhases_ExcludeServicePhases(t *testing.T) {
+	// Jobs of these phases are never claimed from the pending queue, so a
+	// crash must fail them rather than requeue them
+	for _, phase := range []string{RemoteImportPhase, MarkerCompilationPhase, StorageMigrationPhase, PornDBMatchPhase, ActorImagePhase} {
+		for _, fed := range feederPhases {
+			if phase == fed {
+				t.Fatalf("phase %s must not be requeued by the feeder", phase)
+			}
+		}
+	}
+}
+
+func TestRecoverOrphanedJobs_RequeueErrorStillFailsLeftovers(t *testing.T) {
+	feeder, jobHistoryRepo, _ := newTestFeeder(t)
+
+	jobHistoryRepo.EXPECT().RequeueOrphanedRunningJobs(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), errors.New("db down"))
+	jobHistoryRepo.EXPECT().MarkOrphanedRunningAsFailed(gomock.Any()).Return(int64(0), nil)
+
+	feeder.recoverOrphanedJobs()
+}
+
+func TestProgressCallback_PersistsAndPublishes(t *testing.T) {
+	feeder, jobHistoryRepo, _ := newTestFeeder(t)
+	feeder.eventBus = NewEventBus(zap.NewNop())
+	_, events := feeder.eventBus.Subscribe()
+
+	jobHistoryRepo.EXPECT().UpdateProgress("job-1", 42).Return(nil)
+
+	callback := feeder.progressCallback(data.JobHistory{JobID: "job-1", SceneID: 7, Phase: "animated_thumbnails"})
+	callback("job-1", 42)
+
+	select {
+	case event := <-events:
+		if event.Type != "job:progress" || event.SceneID != 7 {
+			t.Fatalf("unexpected event %+v", event)
+		}
+		payload := event.Data.(map[string]any)
+		if payload["job_id"] != "job-1" || payload["phase"] != "animated_thumbnails" || payload["progress"] != 42 {
+			t.Fatalf("unexpected event data %+v", payload)
+		}
+	default:
+		t.Fatal("expected a job:progress event")
+	}
+}
@@ -32,6 +32,7 @@ type ActiveJob struct {
 	SceneTitle string `json:"scene_title"`
 	Phase      string `json:"phase"`
 	StartedAt  string `json:"started_at"`
+	Progress   int    `json:"progress"`
 }
 
 // JobStatusService provides aggregated job status for real-time header display
@@ -90,6 +91,8 @@ func (s *JobStatusService) GetJobStatus() *JobStatus {
 	thumbnailRunning := queueStatus.ThumbnailActive
 	spritesRunning := queueStatus.SpritesActive
 	animatedThumbnailsRunning := queueStatus.AnimatedThumbnailsActive
+	verifyRunning := queueStatus.VerifyActive
+	checksumRunning := queueStatus.ChecksumActive
 
 	// Build phase status map with pending and failed counts
 	byPhase := map[string]PhaseStatus{
@@ -117,13 +120,25 @@ func (s *JobStatusService) GetJobStatus() *JobStatus {
 			Pending: pendingByPhase["animated_thumbnails"],
 			Failed:  failedByPhase["animated_thumbnails"],
 		},
+		"verify": {
+			Running: verifyRunning,
+			Queued:  queueStatus.VerifyQueued,
+			Pending: pendingByPhase["verify"],
+			Failed:  failedByPhase["verify"],
+		},
+		"checksum": {
+			Running: checksumRunning,
+			Queued:  queueStatus.ChecksumQueued,
+			Pending: pendingByPhase["checksum"],
+			Failed:  failedByPhase["checksum"],
+		},
 	}
 
 	// Calculate totals
-	totalRunning := metadataRunning + thumbnailRunning + spritesRunning + animatedThumbnailsRunning
-	totalQueued := queueStatus.MetadataQueued + queueStatus.ThumbnailQueued + queueStatus.SpritesQueued + queueStatus.AnimatedThumbnailsQueued
-	totalPending := pendingByPhase["metadata"] + pendingByPhase["thumbnail"] + pendingByPhase["sprites"] + pendingByPhase["animated_thumbnails"]
-	totalFailed := failedByPhase["metadata"] + failedByPhase["thumbnail"] + failedByPhase["sprites"] + failedByPhase["animated_thumbnails"]
+	totalRunning := metadataRunning + thumbnailRunning + spritesRunning + animatedThumbnailsRunning + verifyRunning + checksumRunning
+	totalQueued := queueStatus.MetadataQueued + queueStatus.ThumbnailQueued + queueStatus.SpritesQueued + queueStatus.AnimatedThumbnailsQueued + queueStatus.VerifyQueued + queueStatus.ChecksumQueued
+	totalPending := pendingByPhase["metadata"] + pendingByPhase["thumbnail"] + pendingByPhase["sprites"] + pendingByPhase["animated_thumbnails"] + pendingByPhase["verify"] + pendingByPhase["checksum"]
+	totalFailed := failedByPhase["metadata"] + failedByPhase["thumbnail"] + failedByPhase["sprites"] + failedByPhase["animated_thumbnails"] + failedByPhase["verify"] + failedByPhase["checksum"]
 
 	// Filter active jobs to only those actually in the worker pool.
 	// The DB marks jobs as 'running' when claimed by the feeder, but the job may
@@ -138,6 +153,7 @@ func (s *JobStatusService) GetJobStatus() *JobStatus {
 				SceneTitle: job.SceneTitle,
 				Phase:      job.Phase,
 				StartedAt:  job.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
+				Progress:   job.Progress,
 			})
 		}
 	}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/pkg/ffmpeg"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// markerClipTimeout bounds how long cutting a single range may take.
+const markerClipTimeout = 10 * time.Minute
+
+// validateMarkerEnd checks the end of a range marker against its start and the scene.
+func validateMarkerEnd(timestamp, endTimestamp, duration int) error {
+	if endTimestamp <= timestamp {
+		return apperrors.NewValidationError("end_timestamp must be after timestamp")
+	}
+	if duration > 0 && endTimestamp > duration {
+		return apperrors.NewValidationError(fmt.Sprintf("end_timestamp %d exceeds scene duration %d", endTimestamp, duration))
+	}
+	return nil
+}
+
+func sameMarkerEnd(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (s *MarkerService) clipDir() string {
+	return filepath.Join(s.markerThumbnailDir, "clips")
+}
+
+// clipPath names a clip after its range, so a marker moved to a new range never
+// serves the old clip.
+func (s *MarkerService) clipPath(marker *data.UserSceneMarker) string {
+	return filepath.Join(s.clipDir(), fmt.Sprintf("marker_%d_%d-%d.mp4", marker.ID, marker.Timestamp, *marker.EndTimestamp))
+}
+
+// removeClips deletes the cached clips of a marker (best effort).
+func (s *MarkerService) removeClips(markerID uint) {
+	matches, _ := filepath.Glob(filepath.Join(s.clipDir(), fmt.Sprintf("marker_%d_*.mp4", markerID)))
+	for _, path := range matches {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			s.logger.Warn("failed to delete marker clip",
+				zap.Uint("markerID", markerID),
+				zap.String("path", path),
+				zap.Error(err))
+		}
+	}
+}
+
+// MarkerClip returns the path of an MP4 of the marker's range, cutting it from
+// the scene on first request and serving the cached file afterwards.
+func (s *MarkerService) MarkerClip(userID uint, role string, markerID uint) (string, error) {
+	marker, err := s.markerRepo.GetByID(markerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", apperrors.NewNotFoundError("marker", markerID)
+		}
+		s.logger.Error("failed to get marker", zap.Uint("markerID", markerID), zap.Error(err))
+		return "", apperrors.NewInternalError("failed to get marker", err)
+	}
+	if marker.UserID != userID {
+		return "", apperrors.NewForbiddenError("you do not own this marker")
+	}
+	visibleIDs, err := visibleSceneIDs(s.rbac, role, []uint{marker.SceneID})
+	if err != nil {
+		return "", apperrors.NewInternalError("failed to check scene access", err)
+	}
+	if len(visibleIDs) == 0 {
+		return "", apperrors.ErrSceneNotFound(marker.SceneID)
+	}
+	if marker.EndTimestamp == nil {
+		return "", apperrors.NewValidationError("marker has no end_timestamp to clip to")
+	}
+
+	path := s.clipPath(marker)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	scene, err := s.sceneRepo.GetByID(marker.SceneID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", apperrors.NewNotFoundError("scene", marker.SceneID)
+		}
+		s.logger.Error("failed to get scene", zap.Uint("sceneID", marker.SceneID), zap.Error(err))
+		return "", apperrors.NewInternalError("failed to get scene", err)
+	}
+	if scene.StoredPath == "" {
+		return "", apperrors.NewNotFoundError("scene file", marker.SceneID)
+	}
+	if _, err := os.Stat(scene.StoredPath); err != nil {
+		return "", apperrors.NewNotFoundError("scene file", marker.SceneID)
+	}
+
+	s.clipMu.Lock()
+	defer s.clipMu.Unlock()
+
+	// Another request may have cut it while this one waited
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(s.clipDir(), 0755); err != nil {
+		return "", apperrors.NewInternalError("failed to create marker clip directory", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), markerClipTimeout)
+	defer cancel()
+
+	// Cut to a temporary name so a failed or interrupted cut is never served
+	tmpPath := path + ".tmp"
+	if err := ffmpeg.ExtractClipWithContext(ctx, scene.StoredPath, tmpPath, marker.Timestamp, *marker.EndTimestamp-marker.Timestamp); err != nil {
+		os.Remove(tmpPath)
+		s.logger.Error("failed to cut marker clip", zap.Uint("markerID", markerID), zap.Error(err))
+		return "", apperrors.NewInternalError("failed to cut marker clip", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return "", apperrors.NewInternalError("failed to save marker clip", err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestValidateMarkerEnd(t *testing.T) {
+	if err := validateMarkerEnd(10, 20, 0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := validateMarkerEnd(10, 10, 0); err == nil {
+		t.Fatal("expected error for an empty range")
+	}
+	if err := validateMarkerEnd(10, 700, 600); err == nil {
+		t.Fatal("expected error for an end past the scene")
+	}
+}
+
+func TestMarkerService_UpdateMarkerRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewMarkerService(markerRepo, sceneRepo, nil, &config.Config{Processing: config.ProcessingConfig{MarkerThumbnailDir: t.TempDir()}}, zap.NewNop())
+
+	intPtr := func(v int) *int { return &v }
+	end := 60
+
+	// Setting an end turns the marker into a range
+	markerRepo.EXPECT().GetByID(uint(1)).Return(&data.UserSceneMarker{ID: 1, UserID: 2, SceneID: 3, Timestamp: 30}, nil)
+	sceneRepo.EXPECT().GetByID(uint(3)).Return(&data.Scene{ID: 3, Duration: 600}, nil)
+	markerRepo.EXPECT().Update(gomock.Any()).Return(nil)
+	marker, err := svc.UpdateMarker(2, 1, nil, nil, nil, intPtr(end), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if marker.EndTimestamp == nil || *marker.EndTimestamp != end {
+		t.Fatalf("expected end %d, got %v", end, marker.EndTimestamp)
+	}
+
+	// Moving the start past the stored end is rejected
+	markerRepo.EXPECT().GetByID(uint(1)).Return(&data.UserSceneMarker{ID: 1, UserID: 2, SceneID: 3, Timestamp: 30, EndTimestamp: &end}, nil)
+	sceneRepo.EXPECT().GetByID(uint(3)).Return(&data.Scene{ID: 3, Duration: 600}, nil)
+	if _, err := svc.UpdateMarker(2, 1, nil, nil, intPtr(90), nil, nil); err == nil {
+		t.Fatal("expected error for a start after the end")
+	}
+
+	// An end of 0 clears the range
+	markerRepo.EXPECT().GetByID(uint(1)).Return(&data.UserSceneMarker{ID: 1, UserID: 2, SceneID: 3, Timestamp: 30, EndTimestamp: &end}, nil)
+	markerRepo.EXPECT().Update(gomock.Any()).Return(nil)
+	marker, err = svc.UpdateMarker(2, 1, nil, nil, nil, intPtr(0), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if marker.EndTimestamp != nil {
+		t.Fatalf("expected no end, got %d", *marker.EndTimestamp)
+	}
+}
+
+func TestMarkerService_MarkerClipRequiresRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	svc := NewMarkerService(markerRepo, nil, nil, &config.Config{}, zap.NewNop())
+
+	markerRepo.EXPECT().GetByID(uint(1)).Return(&data.UserSceneMarker{ID: 1, UserID: 2, Timestamp: 30}, nil)
+	if _, err := svc.MarkerClip(2, "user", 1); err == nil {
+		t.Fatal("expected error for a marker without an end")
+	}
+
+	end := 60
+	markerRepo.EXPECT().GetByID(uint(1)).Return(&data.UserSceneMarker{ID: 1, UserID: 3, Timestamp: 30, EndTimestamp: &end}, nil)
+	if _, err := svc.MarkerClip(2, "user", 1); err == nil {
+		t.Fatal("expected error for another user's marker")
+	}
+}
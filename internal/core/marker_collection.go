@@ -0,0 +1,152 @@
+package core
+
+import (
+	"math/rand"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// maxCollectionQueue caps how many clips a collection's playback queue holds.
+const maxCollectionQueue = 500
+
+// CollectionClip is one entry of a collection's playback queue: a range of a scene.
+type CollectionClip struct {
+	MarkerID   uint   `json:"marker_id"`
+	SceneID    uint   `json:"scene_id"`
+	SceneTitle string `json:"scene_title"`
+	Label      string `json:"label"`
+	Start      int    `json:"start"`
+	End        int    `json:"end"`
+}
+
+// CollectionQueue is a shuffled playback queue of a tag collection.
+type CollectionQueue struct {
+	Clips []CollectionClip `json:"clips"`
+	Total int64            `json:"total"`
+	Seed  int64            `json:"seed"`
+}
+
+// ListTagCollections returns the virtual collections built from the tags on the user's markers.
+func (s *MarkerService) ListTagCollections(userID uint, page, limit int, sortBy string) ([]data.MarkerTagCollection, int64, error) {
+	page, limit = normalizeMarkerPage(page, limit)
+	if sortBy == "" {
+		sortBy = "density"
+	}
+	if sortBy != "density" && sortBy != "count" && sortBy != "name" {
+		return nil, 0, apperrors.NewValidationError("sort must be one of: density, count, name")
+	}
+
+	collections, total, err := s.markerRepo.GetTagCollectionsForUser(userID, (page-1)*limit, limit, sortBy)
+	if err != nil {
+		s.logger.Error("failed to get tag collections", zap.Uint("userID", userID), zap.Error(err))
+		return nil, 0, apperrors.NewInternalError("failed to get tag collections", err)
+	}
+	if collections == nil {
+		collections = []data.MarkerTagCollection{}
+	}
+	return collections, total, nil
+}
+
+// ListCollectionMarkers returns the user's markers tagged tagID.
+func (s *MarkerService) ListCollectionMarkers(userID uint, role string, tagID uint, page, limit int, sortBy string) ([]data.CollectionMarker, int64, error) {
+	page, limit = normalizeMarkerPage(page, limit)
+	if sortBy == "" {
+		sortBy = "density"
+	}
+	if sortBy != "density" && sortBy != "scene" && sortBy != "recent" {
+		return nil, 0, apperrors.NewValidationError("sort must be one of: density, scene, recent")
+	}
+	if err := s.verifyTagExists(tagID); err != nil {
+		return nil, 0, err
+	}
+
+	markers, total, err := s.markerRepo.GetCollectionMarkersForUser(userID, tagID, (page-1)*limit, limit, sortBy)
+	if err != nil {
+		s.logger.Error("failed to get collection markers", zap.Uint("userID", userID), zap.Uint("tagID", tagID), zap.Error(err))
+		return nil, 0, apperrors.NewInternalError("failed to get collection markers", err)
+	}
+	markers, err = visibleMarkers(s.rbac, role, markers, collectionMarkerSceneID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if markers == nil {
+		markers = []data.CollectionMarker{}
+	}
+	return markers, total, nil
+}
+
+// CollectionQueue shuffles the clips of a tag collection. Point markers play for
+// the configured marker preview length. A seed of 0 picks a new shuffle; passing
+// the returned seed back reproduces it.
+func (s *MarkerService) CollectionQueue(userID uint, role string, tagID uint, seed int64) (*CollectionQueue, error) {
+	if err := s.verifyTagExists(tagID); err != nil {
+		return nil, err
+	}
+
+	markers, total, err := s.markerRepo.GetCollectionMarkersForUser(userID, tagID, 0, maxCollectionQueue, "recent")
+	if err != nil {
+		s.logger.Error("failed to get collection markers", zap.Uint("userID", userID), zap.Uint("tagID", tagID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get collection markers", err)
+	}
+	markers, err = visibleMarkers(s.rbac, role, markers, collectionMarkerSceneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if seed == 0 {
+		// Within JavaScript's Number.MAX_SAFE_INTEGER so the seed survives a JSON round-trip
+		seed = rand.Int63n(9007199254740991) + 1
+	}
+
+	clips := make([]CollectionClip, len(markers))
+	for i, m := range markers {
+		clips[i] = collectionClip(m, s.markerAnimatedDuration)
+	}
+	rand.New(rand.NewSource(seed)).Shuffle(len(clips), func(i, j int) {
+		clips[i], clips[j] = clips[j], clips[i]
+	})
+
+	return &CollectionQueue{Clips: clips, Total: total, Seed: seed}, nil
+}
+
+func collectionClip(m data.CollectionMarker, pointSeconds int) CollectionClip {
+	end := m.Timestamp + pointSeconds
+	if m.EndTimestamp != nil {
+		end = *m.EndTimestamp
+	} else if m.SceneDuration > 0 && end > m.SceneDuration {
+		end = m.SceneDuration
+	}
+	return CollectionClip{
+		MarkerID:   m.ID,
+		SceneID:    m.SceneID,
+		SceneTitle: m.SceneTitle,
+		Label:      m.Label,
+		Start:      m.Timestamp,
+		End:        end,
+	}
+}
+
+func (s *MarkerService) verifyTagExists(tagID uint) error {
+	tags, err := s.tagRepo.GetByIDs([]uint{tagID})
+	if err != nil {
+		s.logger.Error("failed to get tag", zap.Uint("tagID", tagID), zap.Error(err))
+		return apperrors.NewInternalError("failed to get tag", err)
+	}
+	if len(tags) == 0 {
+		return apperrors.NewNotFoundError("tag", tagID)
+	}
+	return nil
+}
+
+func normalizeMarkerPage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	return page, limit
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func collectionMarker(id uint, timestamp int, end *int, sceneDuration int) data.CollectionMarker {
+	return data.CollectionMarker{
+		MarkerWithScene: data.MarkerWithScene{
+			UserSceneMarker: data.UserSceneMarker{ID: id, SceneID: 5, Timestamp: timestamp, EndTimestamp: end},
+		},
+		SceneDuration: sceneDuration,
+	}
+}
+
+func TestMarkerService_CollectionQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	tagRepo := mocks.NewMockTagRepository(ctrl)
+	svc := NewMarkerService(markerRepo, mocks.NewMockSceneRepository(ctrl), tagRepo, &config.Config{}, zap.NewNop())
+
+	end := 200
+	markers := []data.CollectionMarker{
+		collectionMarker(1, 100, &end, 600),
+		collectionMarker(2, 595, nil, 600),
+		collectionMarker(3, 10, nil, 600),
+		collectionMarker(4, 300, nil, 0),
+	}
+	tagRepo.EXPECT().GetByIDs([]uint{7}).Return([]data.Tag{{ID: 7}}, nil).Times(2)
+	markerRepo.EXPECT().GetCollectionMarkersForUser(uint(2), uint(7), 0, maxCollectionQueue, "recent").Return(markers, int64(4), nil).Times(2)
+
+	first, err := svc.CollectionQueue(2, "user", 7, 42)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := svc.CollectionQueue(2, "user", 7, 42)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first.Seed != 42 || len(first.Clips) != 4 || first.Total != 4 {
+		t.Fatalf("unexpected queue %+v", first)
+	}
+
+	ends := map[uint]int{}
+	for i, clip := range first.Clips {
+		if clip.MarkerID != second.Clips[i].MarkerID {
+			t.Fatalf("expected the same seed to give the same order, got %v and %v", first.Clips, second.Clips)
+		}
+		ends[clip.MarkerID] = clip.End
+	}
+	// Ranges keep their end; points play the preview length, cut at the end of the scene
+	if ends[1] != 200 || ends[2] != 600 || ends[3] != 20 || ends[4] != 310 {
+		t.Fatalf("unexpected clip ends %v", ends)
+	}
+}
+
+func TestMarkerService_ListCollectionMarkers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	tagRepo := mocks.NewMockTagRepository(ctrl)
+	svc := NewMarkerService(markerRepo, mocks.NewMockSceneRepository(ctrl), tagRepo, &config.Config{}, zap.NewNop())
+
+	if _, _, err := svc.ListCollectionMarkers(2, "user", 7, 1, 20, "label_asc"); err == nil {
+		t.Fatal("expected error for an unknown sort")
+	}
+
+	tagRepo.EXPECT().GetByIDs([]uint{8}).Return(nil, nil)
+	if _, _, err := svc.ListCollectionMarkers(2, "user", 8, 1, 20, ""); err == nil {
+		t.Fatal("expected error for a missing tag")
+	}
+
+	tagRepo.EXPECT().GetByIDs([]uint{7}).Return([]data.Tag{{ID: 7}}, nil)
+	markerRepo.EXPECT().GetCollectionMarkersForUser(uint(2), uint(7), 20, 20, "density").Return(nil, int64(20), nil)
+	markers, total, err := svc.ListCollectionMarkers(2, "user", 7, 2, 20, "")
+	if err != nil || markers == nil || total != 20 {
+		t.Fatalf("expected an empty page, got %v, %d, %v", markers, total, err)
+	}
+}
+
+func TestMarkerService_CollectionQueue_DropsRestrictedScenes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	tagRepo := mocks.NewMockTagRepository(ctrl)
+	svc := NewMarkerService(markerRepo, mocks.NewMockSceneRepository(ctrl), tagRepo, &config.Config{}, zap.NewNop())
+	rbac, _, restrictionRepo := newTestRestrictedRBACService(t, []data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", TagIDs: []int64{3}},
+	})
+	svc.SetRBACService(rbac)
+
+	hidden := collectionMarker(2, 50, nil, 600)
+	hidden.SceneID = 6
+	tagRepo.EXPECT().GetByIDs([]uint{7}).Return([]data.Tag{{ID: 7}}, nil)
+	markerRepo.EXPECT().GetCollectionMarkersForUser(uint(2), uint(7), 0, maxCollectionQueue, "recent").
+		Return([]data.CollectionMarker{collectionMarker(1, 10, nil, 600), hidden}, int64(2), nil)
+	restrictionRepo.EXPECT().FilterVisibleSceneIDs(gomock.Any(), []uint{5, 6}).Return([]uint{5}, nil)
+
+	queue, err := svc.CollectionQueue(2, "guest", 7, 42)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(queue.Clips) != 1 || queue.Clips[0].MarkerID != 1 {
+		t.Fatalf("expected only the marker on the visible scene, got %+v", queue.Clips)
+	}
+}
@@ -0,0 +1,442 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/pkg/ffmpeg"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// MarkerCompilationPhase is the job_history phase of marker compilations. They run
+// outside the scene processing pools, so they can't be retried from the jobs page.
+const MarkerCompilationPhase = "compilation"
+
+const (
+	maxCompilationMarkers         = 100
+	maxCompilationQueue           = 20
+	maxCompilationSegmentSeconds  = 600
+	DefaultCompilationClipSeconds = 10
+	maxCompilationClipSeconds     = 120
+)
+
+var compilationFilenameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._ -]+`)
+
+// MarkerCompilationService joins the clips of a user's markers into a single MP4.
+// Compilations are queued and built one at a time by a background worker, and
+// mirrored into job history so they show up with progress on the jobs page.
+type MarkerCompilationService struct {
+	compilationRepo data.MarkerCompilationRepository
+	markerRepo      data.MarkerRepository
+	sceneRepo       data.SceneRepository
+	jobHistory      *JobHistoryService
+	eventBus        *EventBus
+	dir             string
+	timeout         time.Duration
+	logger          *zap.Logger
+
+	queue  chan string
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewMarkerCompilationService(
+	compilationRepo data.MarkerCompilationRepository,
+	markerRepo data.MarkerRepository,
+	sceneRepo data.SceneRepository,
+	jobHistory *JobHistoryService,
+	eventBus *EventBus,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *MarkerCompilationService {
+	timeout := cfg.Processing.CompilationTimeout
+	if timeout <= 0 {
+		timeout = time.Hour
+	}
+	return &MarkerCompilationService{
+		compilationRepo: compilationRepo,
+		markerRepo:      markerRepo,
+		sceneRepo:       sceneRepo,
+		jobHistory:      jobHistory,
+		eventBus:        eventBus,
+		dir:             cfg.Processing.CompilationDir,
+		timeout:         timeout,
+		logger:          logger.With(zap.String("component", "marker_compilation")),
+		queue:           make(chan string, maxCompilationQueue),
+	}
+}
+
+// Start fails the compilations a previous run left unfinished and starts the worker.
+func (s *MarkerCompilationService) Start() {
+	s.recoverInterrupted()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case jobID := <-s.queue:
+				s.run(ctx, jobID)
+			}
+		}
+	}()
+}
+
+// Stop cancels the running compilation and waits for the worker to exit. Queued
+// compilations are failed on the next start.
+func (s *MarkerCompilationService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+func (s *MarkerCompilationService) recoverInterrupted() {
+	message := "compilation interrupted by server restart"
+	jobIDs, err := s.compilationRepo.FailInterrupted(message)
+	if err != nil {
+		s.logger.Error("Failed to recover interrupted compilations", zap.Error(err))
+		return
+	}
+	for _, jobID := range jobIDs {
+		s.jobHistory.RecordJobFailed(jobID, errors.New(message))
+	}
+	if len(jobIDs) > 0 {
+		s.logger.Warn("Failed compilations interrupted by a restart", zap.Int("count", len(jobIDs)))
+	}
+}
+
+// Create validates the markers and queues a compilation of their clips, in the
+// given order. Range markers contribute their range; point markers contribute
+// clipSeconds from their timestamp.
+func (s *MarkerCompilationService) Create(userID uint, markerIDs []uint, clipSeconds int, title string) (*data.MarkerCompilation, error) {
+	if clipSeconds == 0 {
+		clipSeconds = DefaultCompilationClipSeconds
+	}
+	if clipSeconds < 1 || clipSeconds > maxCompilationClipSeconds {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("clip_seconds must be between 1 and %d", maxCompilationClipSeconds))
+	}
+	title = strings.TrimSpace(title)
+	if len(title) > 255 {
+		return nil, apperrors.NewValidationError("title must be 255 characters or less")
+	}
+	if title == "" {
+		title = fmt.Sprintf("Compilation of %d markers", len(markerIDs))
+	}
+
+	if _, _, err := s.planSegments(userID, markerIDs, clipSeconds); err != nil {
+		return nil, err
+	}
+
+	ids := make(pq.Int64Array, len(markerIDs))
+	for i, id := range markerIDs {
+		ids[i] = int64(id)
+	}
+	compilation := &data.MarkerCompilation{
+		JobID:       uuid.New().String(),
+		UserID:      userID,
+		Title:       title,
+		MarkerIDs:   ids,
+		ClipSeconds: clipSeconds,
+		Status:      data.JobStatusPending,
+	}
+
+	if err := s.compilationRepo.Create(compilation); err != nil {
+		s.logger.Error("Failed to create compilation", zap.Uint("userID", userID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to create compilation", err)
+	}
+
+	select {
+	case s.queue <- compilation.JobID:
+	default:
+		errMsg := "compilation queue is full"
+		if err := s.compilationRepo.UpdateStatus(compilation.JobID, data.JobStatusFailed, &errMsg); err != nil {
+			s.logger.Error("Failed to fail unqueued compilation", zap.String("job_id", compilation.JobID), zap.Error(err))
+		}
+		return nil, apperrors.NewConflictError("compilation", "too many compilations are queued, try again later")
+	}
+
+	return compilation, nil
+}
+
+// planSegments loads the markers and their scenes and returns the segments to
+// cut, in the order of markerIDs, with their total length in seconds.
+func (s *MarkerCompilationService) planSegments(userID uint, markerIDs []uint, clipSeconds int) ([]ffmpeg.CompilationSegment, int, error) {
+	if len(markerIDs) == 0 {
+		return nil, 0, apperrors.NewValidationError("at least one marker is required")
+	}
+	if len(markerIDs) > maxCompilationMarkers {
+		return nil, 0, apperrors.NewValidationError(fmt.Sprintf("a compilation can have at most %d markers", maxCompilationMarkers))
+	}
+
+	markers, err := s.markerRepo.GetByIDs(markerIDs)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to get markers", err)
+	}
+	byID := make(map[uint]data.UserSceneMarker, len(markers))
+	var sceneIDs []uint
+	seenScene := make(map[uint]bool)
+	for _, m := range markers {
+		byID[m.ID] = m
+		if !seenScene[m.SceneID] {
+			seenScene[m.SceneID] = true
+			sceneIDs = append(sceneIDs, m.SceneID)
+		}
+	}
+
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to get scenes", err)
+	}
+	scenesByID := make(map[uint]data.Scene, len(scenes))
+	for _, scene := range scenes {
+		scenesByID[scene.ID] = scene
+	}
+
+	segments := make([]ffmpeg.CompilationSegment, 0, len(markerIDs))
+	total := 0
+	seen := make(map[uint]bool, len(markerIDs))
+	for _, id := range markerIDs {
+		if seen[id] {
+			return nil, 0, apperrors.NewValidationError(fmt.Sprintf("marker %d is listed more than once", id))
+		}
+		seen[id] = true
+
+		marker, ok := byID[id]
+		if !ok {
+			return nil, 0, apperrors.NewNotFoundError("marker", id)
+		}
+		if marker.UserID != userID {
+			return nil, 0, apperrors.NewForbiddenError("you do not own this marker")
+		}
+		scene, ok := scenesByID[marker.SceneID]
+		if !ok || scene.StoredPath == "" {
+			return nil, 0, apperrors.NewNotFoundError("scene file", marker.SceneID)
+		}
+
+		duration := clipSeconds
+		if marker.EndTimestamp != nil {
+			duration = *marker.EndTimestamp - marker.Timestamp
+		}
+		if scene.Duration > 0 && marker.Timestamp+duration > scene.Duration {
+			duration = scene.Duration - marker.Timestamp
+		}
+		if duration > maxCompilationSegmentSeconds {
+			duration = maxCompilationSegmentSeconds
+		}
+		if duration < 1 {
+			return nil, 0, apperrors.NewValidationError(fmt.Sprintf("marker %d is at the end of its scene", id))
+		}
+
+		segments = append(segments, ffmpeg.CompilationSegment{
+			VideoPath: scene.StoredPath,
+			Start:     marker.Timestamp,
+			Duration:  duration,
+			HasAudio:  scene.AudioCodec != "",
+		})
+		total += duration
+	}
+
+	return segments, total, nil
+}
+
+func (s *MarkerCompilationService) run(ctx context.Context, jobID string) {
+	compilation, err := s.compilationRepo.GetByJobID(jobID)
+	if err != nil || compilation == nil {
+		// Deleted while queued
+		return
+	}
+
+	s.jobHistory.RecordJobStart(jobID, 0, compilation.Title, MarkerCompilationPhase)
+	if err := s.compilationRepo.UpdateStatus(jobID, data.JobStatusRunning, nil); err != nil {
+		s.logger.Warn("Failed to mark compilation running", zap.String("job_id", jobID), zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	size, duration, err := s.build(ctx, compilation)
+	if err != nil {
+		switch ctx.Err() {
+		case context.DeadlineExceeded:
+			err = fmt.Errorf("compilation timed out after %s", s.timeout)
+		case context.Canceled:
+			err = errors.New("compilation interrupted by server shutdown")
+		}
+		s.logger.Error("Compilation failed", zap.String("job_id", jobID), zap.Error(err))
+		errMsg := err.Error()
+		if updateErr := s.compilationRepo.UpdateStatus(jobID, data.JobStatusFailed, &errMsg); updateErr != nil {
+			s.logger.Error("Failed to record compilation failure", zap.String("job_id", jobID), zap.Error(updateErr))
+		}
+		s.jobHistory.RecordJobFailed(jobID, err)
+		s.publish(compilation.UserID, "marker_compilation:failed", map[string]any{
+			"job_id":        jobID,
+			"error_message": errMsg,
+		})
+		return
+	}
+
+	if err := s.compilationRepo.Complete(jobID, size, duration); err != nil {
+		s.logger.Error("Failed to record compilation completion", zap.String("job_id", jobID), zap.Error(err))
+	}
+	s.jobHistory.RecordJobComplete(jobID)
+	s.publish(compilation.UserID, "marker_compilation:completed", map[string]any{
+		"job_id":    jobID,
+		"file_size": size,
+		"duration":  duration,
+	})
+}
+
+// build cuts every segment into a temporary directory and joins them into the
+// compilation file, returning its size and length.
+func (s *MarkerCompilationService) build(ctx context.Context, compilation *data.MarkerCompilation) (int64, int, error) {
+	markerIDs := make([]uint, len(compilation.MarkerIDs))
+	for i, id := range compilation.MarkerIDs {
+		markerIDs[i] = uint(id)
+	}
+	// Markers may have changed or been deleted since the compilation was queued
+	segments, duration, err := s.planSegments(compilation.UserID, markerIDs, compilation.ClipSeconds)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return 0, 0, fmt.Errorf("failed to create compilation directory: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(s.dir, compilation.JobID+"-")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Cutting takes most of the time; joining is a stream copy
+	paths := make([]string, len(segments))
+	for i, seg := range segments {
+		paths[i] = filepath.Join(tmpDir, fmt.Sprintf("segment_%03d.ts", i))
+		if err := ffmpeg.ExtractCompilationSegmentWithContext(ctx, seg, paths[i]); err != nil {
+			return 0, 0, fmt.Errorf("failed to cut clip of marker %d: %w", markerIDs[i], err)
+		}
+		s.updateProgress(compilation, (i+1)*90/len(segments))
+	}
+
+	tmpPath := filepath.Join(tmpDir, "compilation.mp4")
+	if err := ffmpeg.ConcatSegmentsWithContext(ctx, paths, tmpPath); err != nil {
+		return 0, 0, err
+	}
+	path := s.filePath(compilation.JobID)
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, 0, fmt.Errorf("failed to save compilation: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to stat compilation: %w", err)
+	}
+	return info.Size(), duration, nil
+}
+
+func (s *MarkerCompilationService) updateProgress(compilation *data.MarkerCompilation, progress int) {
+	if err := s.compilationRepo.UpdateProgress(compilation.JobID, progress); err != nil {
+		s.logger.Warn("Failed to update compilation progress", zap.String("job_id", compilation.JobID), zap.Error(err))
+	}
+	s.jobHistory.UpdateProgress(compilation.JobID, progress)
+	s.publish(compilation.UserID, "marker_compilation:progress", map[string]any{
+		"job_id":   compilation.JobID,
+		"progress": progress,
+	})
+}
+
+func (s *MarkerCompilationService) publish(userID uint, eventType string, payload map[string]any) {
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(SceneEvent{Type: eventType, UserID: userID, Data: payload})
+}
+
+func (s *MarkerCompilationService) filePath(jobID string) string {
+	return filepath.Join(s.dir, jobID+".mp4")
+}
+
+// List returns the user's compilations, newest first.
+func (s *MarkerCompilationService) List(userID uint) ([]data.MarkerCompilation, error) {
+	compilations, err := s.compilationRepo.ListByUser(userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list compilations", err)
+	}
+	if compilations == nil {
+		compilations = []data.MarkerCompilation{}
+	}
+	return compilations, nil
+}
+
+// Get returns one of the user's compilations.
+func (s *MarkerCompilationService) Get(userID uint, jobID string) (*data.MarkerCompilation, error) {
+	compilation, err := s.compilationRepo.GetByJobID(jobID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get compilation", err)
+	}
+	if compilation == nil {
+		return nil, apperrors.NewNotFoundError("compilation", jobID)
+	}
+	if compilation.UserID != userID {
+		return nil, apperrors.NewForbiddenError("you do not own this compilation")
+	}
+	return compilation, nil
+}
+
+// Download returns the file of a completed compilation and a name to save it as.
+func (s *MarkerCompilationService) Download(userID uint, jobID string) (string, string, error) {
+	compilation, err := s.Get(userID, jobID)
+	if err != nil {
+		return "", "", err
+	}
+	if compilation.Status != data.JobStatusCompleted {
+		return "", "", apperrors.NewConflictError("compilation", "compilation is not complete")
+	}
+	path := s.filePath(jobID)
+	if _, err := os.Stat(path); err != nil {
+		return "", "", apperrors.NewNotFoundError("compilation file", jobID)
+	}
+
+	name := strings.TrimSpace(compilationFilenameUnsafe.ReplaceAllString(compilation.Title, ""))
+	if name == "" {
+		name = "compilation"
+	}
+	return path, name + ".mp4", nil
+}
+
+// Delete removes a finished compilation and its file.
+func (s *MarkerCompilationService) Delete(userID uint, jobID string) error {
+	compilation, err := s.Get(userID, jobID)
+	if err != nil {
+		return err
+	}
+	if compilation.Status == data.JobStatusRunning {
+		return apperrors.NewConflictError("compilation", "compilation is still running")
+	}
+
+	if err := s.compilationRepo.Delete(jobID); err != nil {
+		return apperrors.NewInternalError("failed to delete compilation", err)
+	}
+	if err := os.Remove(s.filePath(jobID)); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("Failed to delete compilation file", zap.String("job_id", jobID), zap.Error(err))
+	}
+	return nil
+}
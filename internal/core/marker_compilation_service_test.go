@@ -0,0 +1,119 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestMarkerCompilationService(t *testing.T) (*MarkerCompilationService, *mocks.MockMarkerCompilationRepository, *mocks.MockMarkerRepository, *mocks.MockSceneRepository) {
+	ctrl := gomock.NewController(t)
+	compilationRepo := mocks.NewMockMarkerCompilationRepository(ctrl)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	jobHistory := NewJobHistoryService(mocks.NewMockJobHistoryRepository(ctrl), config.ProcessingConfig{}, zap.NewNop())
+	cfg := &config.Config{}
+	cfg.Processing.CompilationDir = t.TempDir()
+	svc := NewMarkerCompilationService(compilationRepo, markerRepo, sceneRepo, jobHistory, nil, cfg, zap.NewNop())
+	return svc, compilationRepo, markerRepo, sceneRepo
+}
+
+func TestMarkerCompilationService_PlanSegments(t *testing.T) {
+	svc, _, markerRepo, sceneRepo := newTestMarkerCompilationService(t)
+
+	end := 95
+	markerRepo.EXPECT().GetByIDs([]uint{3, 1, 2}).Return([]data.UserSceneMarker{
+		{ID: 1, UserID: 7, SceneID: 10, Timestamp: 60},
+		{ID: 2, UserID: 7, SceneID: 20, Timestamp: 80, EndTimestamp: &end},
+		{ID: 3, UserID: 7, SceneID: 10, Timestamp: 295},
+	}, nil)
+	sceneRepo.EXPECT().GetByIDs(gomock.Any()).Return([]data.Scene{
+		{ID: 10, StoredPath: "/videos/a.mp4", Duration: 300, AudioCodec: "aac"},
+		{ID: 20, StoredPath: "/videos/b.mp4", Duration: 600},
+	}, nil)
+
+	segments, total, err := svc.planSegments(7, []uint{3, 1, 2}, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// Segments keep the requested order; point markers are cut short at the end of the scene
+	if len(segments) != 3 || total != 5+10+15 {
+		t.Fatalf("unexpected plan %+v (total %d)", segments, total)
+	}
+	if segments[0].Start != 295 || segments[0].Duration != 5 || !segments[0].HasAudio {
+		t.Fatalf("unexpected first segment %+v", segments[0])
+	}
+	if segments[2].VideoPath != "/videos/b.mp4" || segments[2].Duration != 15 || segments[2].HasAudio {
+		t.Fatalf("unexpected range segment %+v", segments[2])
+	}
+}
+
+func TestMarkerCompilationService_PlanSegmentsRejectsInvalidSelections(t *testing.T) {
+	svc, _, markerRepo, sceneRepo := newTestMarkerCompilationService(t)
+
+	if _, _, err := svc.planSegments(7, nil, 10); err == nil {
+		t.Fatal("expected error for an empty selection")
+	}
+	if _, _, err := svc.planSegments(7, make([]uint, maxCompilationMarkers+1), 10); err == nil {
+		t.Fatal("expected error for too many markers")
+	}
+
+	markers := []data.UserSceneMarker{{ID: 1, UserID: 7, SceneID: 10}, {ID: 2, UserID: 8, SceneID: 10}}
+	scenes := []data.Scene{{ID: 10, StoredPath: "/videos/a.mp4"}}
+	markerRepo.EXPECT().GetByIDs(gomock.Any()).Return(markers, nil).Times(3)
+	sceneRepo.EXPECT().GetByIDs(gomock.Any()).Return(scenes, nil).Times(3)
+
+	for name, ids := range map[string][]uint{
+		"duplicate": {1, 1},
+		"foreign":   {1, 2},
+		"missing":   {1, 3},
+	} {
+		if _, _, err := svc.planSegments(7, ids, 10); err == nil {
+			t.Fatalf("expected error for %s marker", name)
+		}
+	}
+}
+
+func TestMarkerCompilationService_CreateValidatesClipSeconds(t *testing.T) {
+	svc, compilationRepo, _, _ := newTestMarkerCompilationService(t)
+	compilationRepo.EXPECT().Create(gomock.Any()).Times(0)
+
+	for _, clipSeconds := range []int{-1, maxCompilationClipSeconds + 1} {
+		if _, err := svc.Create(7, []uint{1}, clipSeconds, ""); err == nil {
+			t.Fatalf("expected error for clip_seconds %d", clipSeconds)
+		}
+	}
+}
+
+func TestMarkerCompilationService_DownloadRequiresCompletion(t *testing.T) {
+	svc, compilationRepo, _, _ := newTestMarkerCompilationService(t)
+
+	compilationRepo.EXPECT().GetByJobID("running").Return(&data.MarkerCompilation{JobID: "running", UserID: 7, Status: data.JobStatusRunning}, nil)
+	if _, _, err := svc.Download(7, "running"); err == nil {
+		t.Fatal("expected error for a running compilation")
+	}
+
+	compilationRepo.EXPECT().GetByJobID("other").Return(&data.MarkerCompilation{JobID: "other", UserID: 8, Status: data.JobStatusCompleted}, nil)
+	if _, _, err := svc.Download(7, "other"); err == nil {
+		t.Fatal("expected error for another user's compilation")
+	}
+}
+
+func TestJobHistoryService_RetryJobRejectsCompilations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockJobHistoryRepository(ctrl)
+	svc := NewJobHistoryService(repo, config.ProcessingConfig{}, zap.NewNop())
+	svc.SetProcessingService(&SceneProcessingService{})
+
+	repo.EXPECT().GetByJobID("job").Return(&data.JobHistory{JobID: "job", Status: data.JobStatusFailed, Phase: MarkerCompilationPhase}, nil)
+	repo.EXPECT().MarkNotRetryable(gomock.Any()).Times(0)
+
+	if err := svc.RetryJob("job"); err == nil {
+		t.Fatal("expected error when retrying a compilation")
+	}
+}
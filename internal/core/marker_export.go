@@ -0,0 +1,119 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/pkg/ffmpeg"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Chapter formats markers can be exported to
+const (
+	ChapterFormatMatroska   = "mkv"        // Matroska chapters XML (mkvmerge, mkvpropedit)
+	ChapterFormatFFMetadata = "ffmetadata" // ffmpeg -f ffmetadata
+	ChapterFormatWebVTT     = "vtt"        // WebVTT chapters track
+)
+
+// ChapterExport is a scene's markers rendered as a chapters file.
+type ChapterExport struct {
+	Content     []byte
+	ContentType string
+	Filename    string
+}
+
+// SceneChapters returns the user's markers on a scene as chapters. Each
+// chapter runs until the next marker, the last one until the end of the scene.
+func (s *MarkerService) SceneChapters(userID, sceneID uint) ([]ffmpeg.Chapter, error) {
+	scene, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperrors.NewNotFoundError("scene", sceneID)
+		}
+		s.logger.Error("failed to get scene", zap.Uint("sceneID", sceneID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+
+	markers, err := s.markerRepo.GetByUserAndScene(userID, sceneID)
+	if err != nil {
+		s.logger.Error("failed to list markers", zap.Uint("userID", userID), zap.Uint("sceneID", sceneID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to list markers", err)
+	}
+
+	return markersToChapters(markers, scene.Duration), nil
+}
+
+// ExportChapters renders the user's markers on a scene in one of the chapter formats.
+func (s *MarkerService) ExportChapters(userID, sceneID uint, format string) (*ChapterExport, error) {
+	if format != ChapterFormatMatroska && format != ChapterFormatFFMetadata && format != ChapterFormatWebVTT {
+		return nil, apperrors.NewValidationError("format must be one of: mkv, ffmetadata, vtt")
+	}
+
+	chapters, err := s.SceneChapters(userID, sceneID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case ChapterFormatMatroska:
+		content, err := ffmpeg.FormatMatroskaChapters(chapters)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to render chapters", err)
+		}
+		return &ChapterExport{
+			Content:     content,
+			ContentType: "application/xml",
+			Filename:    fmt.Sprintf("scene-%d-chapters.xml", sceneID),
+		}, nil
+	case ChapterFormatFFMetadata:
+		return &ChapterExport{
+			Content:     []byte(ffmpeg.FormatFFMetadata(chapters)),
+			ContentType: "text/plain; charset=utf-8",
+			Filename:    fmt.Sprintf("scene-%d-chapters.ffmetadata", sceneID),
+		}, nil
+	default:
+		return &ChapterExport{
+			Content:     []byte(ffmpeg.FormatWebVTTChapters(chapters)),
+			ContentType: "text/vtt; charset=utf-8",
+			Filename:    fmt.Sprintf("scene-%d-chapters.vtt", sceneID),
+		}, nil
+	}
+}
+
+// markersToChapters orders markers by time and turns each into a chapter.
+// Markers at the same second are merged into one chapter; unlabeled ones are
+// numbered.
+func markersToChapters(markers []data.UserSceneMarker, duration int) []ffmpeg.Chapter {
+	sorted := slices.Clone(markers)
+	slices.SortStableFunc(sorted, func(a, b data.UserSceneMarker) int {
+		return a.Timestamp - b.Timestamp
+	})
+
+	var chapters []ffmpeg.Chapter
+	for _, m := range sorted {
+		if n := len(chapters); n > 0 && chapters[n-1].Start == float64(m.Timestamp) {
+			if m.Label != "" && chapters[n-1].Title != m.Label {
+				chapters[n-1].Title += " / " + m.Label
+			}
+			continue
+		}
+		title := m.Label
+		if title == "" {
+			title = fmt.Sprintf("Chapter %d", len(chapters)+1)
+		}
+		chapters = append(chapters, ffmpeg.Chapter{Start: float64(m.Timestamp), Title: title})
+	}
+
+	for i := range chapters {
+		if i+1 < len(chapters) {
+			chapters[i].End = chapters[i+1].Start
+		} else {
+			chapters[i].End = max(float64(duration), chapters[i].Start)
+		}
+	}
+	return chapters
+}
@@ -0,0 +1,38 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/data"
+)
+
+func TestMarkersToChapters(t *testing.T) {
+	chapters := markersToChapters([]data.UserSceneMarker{
+		{Timestamp: 300, Label: "Outro"},
+		{Timestamp: 0},
+		{Timestamp: 120, Label: "Scene"},
+		{Timestamp: 120, Label: "Dialogue"},
+	}, 240)
+
+	want := []struct {
+		start, end float64
+		title      string
+	}{
+		{0, 120, "Chapter 1"},
+		{120, 300, "Scene / Dialogue"},
+		{300, 300, "Outro"}, // past the probed duration, the chapter is empty rather than negative
+	}
+	if len(chapters) != len(want) {
+		t.Fatalf("expected %d chapters, got %+v", len(want), chapters)
+	}
+	for i, w := range want {
+		c := chapters[i]
+		if c.Start != w.start || c.End != w.end || c.Title != w.title {
+			t.Fatalf("chapter %d: expected %+v, got %+v", i, w, c)
+		}
+	}
+
+	if got := markersToChapters(nil, 100); len(got) != 0 {
+		t.Fatalf("expected no chapters without markers, got %+v", got)
+	}
+}
@@ -0,0 +1,422 @@
+package core
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Formats markers can be imported from. An empty format is detected from the content.
+const (
+	MarkerImportCSV       = "csv"       // timestamp,label[,color] rows, with an optional header
+	MarkerImportJSON      = "json"      // array of {timestamp, label} (ThePornDB {start_time, title} also works)
+	MarkerImportFunscript = "funscript" // funscript metadata.chapters
+	MarkerImportWebVTT    = "vtt"       // WebVTT chapters track
+)
+
+// DefaultMarkerImportDedupeSeconds is how close an imported marker may be to
+// another one before it is skipped as a duplicate, when the request sets none.
+const DefaultMarkerImportDedupeSeconds = 2
+
+const maxMarkerImportSize = 1 << 20
+
+// MarkerImportItem is one marker to import.
+type MarkerImportItem struct {
+	Timestamp int    `json:"timestamp"`
+	Label     string `json:"label"`
+	Color     string `json:"color,omitempty"`
+}
+
+// MarkerImportSkip is an imported marker that was not created, and why.
+type MarkerImportSkip struct {
+	MarkerImportItem
+	Reason string `json:"reason"`
+}
+
+// MarkerImportOptions holds the markers to import: parsed from Content in
+// Format, plus the already structured Markers.
+type MarkerImportOptions struct {
+	Format        string
+	Content       string
+	Markers       []MarkerImportItem
+	Color         string // default color for markers that have none
+	DedupeSeconds int
+	DryRun        bool
+}
+
+// MarkerImportResult lists the markers an import creates (or would create on a
+// dry run) and the ones it skips.
+type MarkerImportResult struct {
+	DryRun  bool                   `json:"dry_run"`
+	Markers []MarkerImportItem     `json:"markers"`
+	Created []data.UserSceneMarker `json:"created,omitempty"`
+	Skipped []MarkerImportSkip     `json:"skipped"`
+}
+
+// ImportMarkers creates markers on a scene in bulk. Markers that are invalid,
+// past the scene's end, within DedupeSeconds of an existing or earlier
+// imported marker, or over the per-scene limit are skipped. Thumbnails for the
+// new markers are generated in the background.
+func (s *MarkerService) ImportMarkers(userID, sceneID uint, opts MarkerImportOptions) (*MarkerImportResult, error) {
+	if opts.DedupeSeconds < 0 {
+		return nil, apperrors.NewValidationError("dedupe_seconds must be non-negative")
+	}
+	if opts.Color != "" && !isHexColor(opts.Color) {
+		return nil, apperrors.NewValidationError("color must be a valid hex color (e.g., #FF4D4D)")
+	}
+	if len(opts.Content) > maxMarkerImportSize {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("import content must be %d bytes or fewer", maxMarkerImportSize))
+	}
+
+	items, skipped, err := parseMarkerImport(opts.Format, opts.Content)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, opts.Markers...)
+	if len(items) == 0 && len(skipped) == 0 {
+		return nil, apperrors.NewValidationError("no markers to import")
+	}
+
+	scene, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperrors.NewNotFoundError("scene", sceneID)
+		}
+		s.logger.Error("failed to get scene", zap.Uint("sceneID", sceneID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+
+	existing, err := s.markerRepo.GetByUserAndScene(userID, sceneID)
+	if err != nil {
+		s.logger.Error("failed to list markers", zap.Uint("userID", userID), zap.Uint("sceneID", sceneID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to list markers", err)
+	}
+
+	accepted, rejected := planMarkerImport(items, existing, scene.Duration, opts.DedupeSeconds, opts.Color)
+	result := &MarkerImportResult{
+		DryRun:  opts.DryRun,
+		Markers: accepted,
+		Skipped: append(append([]MarkerImportSkip{}, skipped...), rejected...),
+	}
+	if opts.DryRun || len(accepted) == 0 {
+		return result, nil
+	}
+
+	for _, item := range accepted {
+		marker := &data.UserSceneMarker{
+			UserID:    userID,
+			SceneID:   sceneID,
+			Timestamp: item.Timestamp,
+			Label:     item.Label,
+			Color:     item.Color,
+		}
+		if err := s.saveMarker(marker); err != nil {
+			return nil, err
+		}
+		result.Created = append(result.Created, *marker)
+	}
+
+	s.logger.Info("Imported markers",
+		zap.Uint("userID", userID),
+		zap.Uint("sceneID", sceneID),
+		zap.Int("created", len(result.Created)),
+		zap.Int("skipped", len(result.Skipped)))
+
+	if !s.queueMarkerThumbnails(sceneID) {
+		go s.generateImportedThumbnails(sceneID)
+	}
+
+	return result, nil
+}
+
+// generateImportedThumbnails generates the thumbnails CreateMarker would have,
+// for every marker of the scene that lacks one.
+func (s *MarkerService) generateImportedThumbnails(sceneID uint) {
+	var err error
+	if s.markerThumbnailType == "animated" {
+		_, err = s.GenerateMissingAnimatedForScene(context.Background(), sceneID, "", nil)
+	} else {
+		_, err = s.GenerateMissingForScene(context.Background(), sceneID)
+	}
+	if err != nil {
+		s.logger.Warn("failed to generate imported marker thumbnails", zap.Uint("sceneID", sceneID), zap.Error(err))
+	}
+}
+
+// planMarkerImport validates items in order against the scene and the markers
+// accepted so far, returning the markers to create and the ones skipped.
+func planMarkerImport(items []MarkerImportItem, existing []data.UserSceneMarker, duration, dedupeSeconds int, defaultColor string) ([]MarkerImportItem, []MarkerImportSkip) {
+	if defaultColor == "" {
+		defaultColor = "#FFFFFF"
+	}
+
+	taken := make([]int, 0, len(existing)+len(items))
+	for _, m := range existing {
+		taken = append(taken, m.Timestamp)
+	}
+
+	accepted := []MarkerImportItem{}
+	skipped := []MarkerImportSkip{}
+	for _, item := range items {
+		item.Label = strings.TrimSpace(item.Label)
+		if item.Color == "" {
+			item.Color = defaultColor
+		}
+
+		reason := ""
+		switch {
+		case item.Timestamp < 0:
+			reason = "timestamp must be non-negative"
+		case duration > 0 && item.Timestamp > duration:
+			reason = fmt.Sprintf("timestamp exceeds scene duration %d", duration)
+		case len(item.Label) > 100:
+			reason = "label must be 100 characters or fewer"
+		case !isHexColor(item.Color):
+			reason = "color must be a valid hex color"
+		case len(taken) >= maxMarkersPerScene:
+			reason = fmt.Sprintf("maximum of %d markers per scene reached", maxMarkersPerScene)
+		}
+		if reason == "" {
+			for _, ts := range taken {
+				if item.Timestamp-ts <= dedupeSeconds && ts-item.Timestamp <= dedupeSeconds {
+					reason = fmt.Sprintf("duplicate of marker at %d", ts)
+					break
+				}
+			}
+		}
+
+		if reason != "" {
+			skipped = append(skipped, MarkerImportSkip{MarkerImportItem: item, Reason: reason})
+			continue
+		}
+		taken = append(taken, item.Timestamp)
+		accepted = append(accepted, item)
+	}
+	return accepted, skipped
+}
+
+// parseMarkerImport parses content in format, detecting the format when it is
+// empty. Rows that cannot be read are returned as skipped.
+func parseMarkerImport(format, content string) ([]MarkerImportItem, []MarkerImportSkip, error) {
+	switch format {
+	case "", MarkerImportCSV, MarkerImportJSON, MarkerImportFunscript, MarkerImportWebVTT:
+	default:
+		return nil, nil, apperrors.NewValidationError("format must be one of: csv, json, funscript, vtt")
+	}
+
+	content = strings.TrimPrefix(content, "\ufeff")
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return nil, nil, nil
+	}
+
+	if format == "" {
+		switch {
+		case strings.HasPrefix(trimmed, "WEBVTT"):
+			format = MarkerImportWebVTT
+		case strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{"):
+			format = MarkerImportJSON
+		default:
+			format = MarkerImportCSV
+		}
+	}
+
+	switch format {
+	case MarkerImportCSV:
+		return parseMarkerCSV(content)
+	case MarkerImportJSON, MarkerImportFunscript:
+		return parseMarkerJSON(content)
+	default:
+		items, skipped := parseMarkerVTT(content)
+		return items, skipped, nil
+	}
+}
+
+func parseMarkerCSV(content string) ([]MarkerImportItem, []MarkerImportSkip, error) {
+	r := csv.NewReader(strings.NewReader(content))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var items []MarkerImportItem
+	var skipped []MarkerImportSkip
+	for row := 1; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, apperrors.NewValidationError(fmt.Sprintf("invalid csv: %v", err))
+		}
+
+		ts, err := parseMarkerTimestamp(record[0])
+		if err != nil {
+			// A first row that is not a timestamp is a header
+			if row > 1 {
+				skipped = append(skipped, MarkerImportSkip{Reason: fmt.Sprintf("row %d: %v", row, err)})
+			}
+			continue
+		}
+		item := MarkerImportItem{Timestamp: ts}
+		if len(record) > 1 {
+			item.Label = record[1]
+		}
+		if len(record) > 2 {
+			item.Color = strings.TrimSpace(record[2])
+		}
+		items = append(items, item)
+	}
+	return items, skipped, nil
+}
+
+// markerImportRecord is a JSON marker under any of the names the supported
+// sources use for its time and label.
+type markerImportRecord struct {
+	Timestamp      json.RawMessage `json:"timestamp"`
+	StartTime      json.RawMessage `json:"start_time"` // ThePornDB markers
+	StartTimeCamel json.RawMessage `json:"startTime"`  // funscript chapters
+	Time           json.RawMessage `json:"time"`
+	Label          string          `json:"label"`
+	Title          string          `json:"title"`
+	Name           string          `json:"name"`
+	Color          string          `json:"color"`
+}
+
+func parseMarkerJSON(content string) ([]MarkerImportItem, []MarkerImportSkip, error) {
+	var records []markerImportRecord
+	if strings.HasPrefix(strings.TrimSpace(content), "[") {
+		if err := json.Unmarshal([]byte(content), &records); err != nil {
+			return nil, nil, apperrors.NewValidationError(fmt.Sprintf("invalid json: %v", err))
+		}
+	} else {
+		var doc struct {
+			Markers  []markerImportRecord `json:"markers"`
+			Chapters []markerImportRecord `json:"chapters"`
+			Metadata struct {
+				Chapters []markerImportRecord `json:"chapters"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal([]byte(content), &doc); err != nil {
+			return nil, nil, apperrors.NewValidationError(fmt.Sprintf("invalid json: %v", err))
+		}
+		records = append(append(doc.Markers, doc.Chapters...), doc.Metadata.Chapters...)
+	}
+
+	var items []MarkerImportItem
+	var skipped []MarkerImportSkip
+	for i, rec := range records {
+		label := rec.Label
+		if label == "" {
+			label = rec.Title
+		}
+		if label == "" {
+			label = rec.Name
+		}
+		raw := rec.Timestamp
+		for _, alt := range []json.RawMessage{rec.StartTime, rec.StartTimeCamel, rec.Time} {
+			if len(raw) == 0 || string(raw) == "null" {
+				raw = alt
+			}
+		}
+		ts, err := parseMarkerJSONTimestamp(raw)
+		if err != nil {
+			skipped = append(skipped, MarkerImportSkip{
+				MarkerImportItem: MarkerImportItem{Label: label},
+				Reason:           fmt.Sprintf("marker %d: %v", i+1, err),
+			})
+			continue
+		}
+		items = append(items, MarkerImportItem{Timestamp: ts, Label: label, Color: rec.Color})
+	}
+	return items, skipped, nil
+}
+
+func parseMarkerJSONTimestamp(raw json.RawMessage) (int, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, fmt.Errorf("missing timestamp")
+	}
+	var seconds float64
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("timestamp must be non-negative")
+		}
+		return int(seconds), nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return 0, fmt.Errorf("invalid timestamp %s", raw)
+	}
+	return parseMarkerTimestamp(text)
+}
+
+func parseMarkerVTT(content string) ([]MarkerImportItem, []MarkerImportSkip) {
+	var items []MarkerImportItem
+	var skipped []MarkerImportSkip
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		start, _, ok := strings.Cut(lines[i], "-->")
+		if !ok {
+			continue
+		}
+		var title []string
+		for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+			i++
+			title = append(title, strings.TrimSpace(lines[i]))
+		}
+		label := strings.Join(title, " ")
+		ts, err := parseMarkerTimestamp(start)
+		if err != nil {
+			skipped = append(skipped, MarkerImportSkip{
+				MarkerImportItem: MarkerImportItem{Label: label},
+				Reason:           fmt.Sprintf("cue at line %d: %v", i+1-len(title), err),
+			})
+			continue
+		}
+		items = append(items, MarkerImportItem{Timestamp: ts, Label: label})
+	}
+	return items, skipped
+}
+
+// parseMarkerTimestamp reads seconds ("90", "90.5") or a clock time ("1:30",
+// "01:01:30.500"), dropping fractions of a second.
+func parseMarkerTimestamp(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("missing timestamp")
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var seconds float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		// Only the seconds may have a fraction
+		if i < len(parts)-1 && v != math.Trunc(v) {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		seconds = seconds*60 + v
+	}
+	if seconds > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return int(seconds), nil
+}
+
+func isHexColor(color string) bool {
+	return len(color) == 7 && color[0] == '#'
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestParseMarkerImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+		want    []MarkerImportItem
+		skipped int
+	}{
+		{
+			name:    "csv with header",
+			content: "timestamp,label,color\n90,Intro,#FF0000\n01:02:03.750,\"Scene, part 2\"\nsoon,Broken\n",
+			want:    []MarkerImportItem{{90, "Intro", "#FF0000"}, {3723, "Scene, part 2", ""}},
+			skipped: 1,
+		},
+		{
+			name:    "theporndb json",
+			content: `[{"id": 1, "title": "Start", "start_time": 12, "end_time": 30}, {"title": "No time"}]`,
+			want:    []MarkerImportItem{{12, "Start", ""}},
+			skipped: 1,
+		},
+		{
+			name:    "funscript chapters",
+			format:  MarkerImportFunscript,
+			content: `{"actions": [], "metadata": {"chapters": [{"name": "Warmup", "startTime": "00:01:05.200", "endTime": "00:02:00.000"}]}}`,
+			want:    []MarkerImportItem{{65, "Warmup", ""}},
+		},
+		{
+			name:    "webvtt chapters",
+			content: "WEBVTT\n\n1\n00:00:00.000 --> 00:01:00.000\nIntro\n\n2\n00:01:00.000 --> 00:02:00.000\nMain\nscene\n",
+			want:    []MarkerImportItem{{0, "Intro", ""}, {60, "Main scene", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, skipped, err := parseMarkerImport(tt.format, tt.content)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(items) != len(tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, items)
+			}
+			for i := range items {
+				if items[i] != tt.want[i] {
+					t.Fatalf("expected %+v, got %+v", tt.want, items)
+				}
+			}
+			if len(skipped) != tt.skipped {
+				t.Fatalf("expected %d skipped, got %+v", tt.skipped, skipped)
+			}
+		})
+	}
+
+	if _, _, err := parseMarkerImport("srt", "1\n"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+	if _, _, err := parseMarkerImport(MarkerImportJSON, "{"); err == nil {
+		t.Fatal("expected error for invalid json")
+	}
+}
+
+func TestParseMarkerTimestamp(t *testing.T) {
+	valid := map[string]int{"0": 0, "90.9": 90, "1:30": 90, "01:00:00.500": 3600, " 5 ": 5}
+	for in, want := range valid {
+		got, err := parseMarkerTimestamp(in)
+		if err != nil || got != want {
+			t.Fatalf("parseMarkerTimestamp(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "-5", "1:2:3:4", "1.5:00", "abc", "NaN"} {
+		if _, err := parseMarkerTimestamp(in); err == nil {
+			t.Fatalf("expected error for %q", in)
+		}
+	}
+}
+
+func TestPlanMarkerImport(t *testing.T) {
+	existing := []data.UserSceneMarker{{Timestamp: 100}}
+	items := []MarkerImportItem{
+		{Timestamp: 10, Label: " Intro "},
+		{Timestamp: 11, Label: "Too close to the intro"},
+		{Timestamp: 102, Label: "Too close to an existing marker"},
+		{Timestamp: 500, Label: "Past the end"},
+		{Timestamp: 200, Label: strings.Repeat("a", 101)},
+		{Timestamp: 300, Color: "red"},
+		{Timestamp: 103, Label: "Just outside the window", Color: "#00FF00"},
+	}
+
+	accepted, skipped := planMarkerImport(items, existing, 400, 2, "#123456")
+	if len(accepted) != 2 || len(skipped) != 5 {
+		t.Fatalf("expected 2 accepted and 5 skipped, got %+v / %+v", accepted, skipped)
+	}
+	if accepted[0] != (MarkerImportItem{10, "Intro", "#123456"}) || accepted[1].Color != "#00FF00" {
+		t.Fatalf("unexpected accepted markers %+v", accepted)
+	}
+	if skipped[0].Reason != "duplicate of marker at 10" || skipped[1].Reason != "duplicate of marker at 100" {
+		t.Fatalf("unexpected skip reasons %+v", skipped)
+	}
+
+	// The per-scene limit counts existing markers
+	full := make([]data.UserSceneMarker, maxMarkersPerScene)
+	for i := range full {
+		full[i].Timestamp = i * 10
+	}
+	if accepted, _ := planMarkerImport([]MarkerImportItem{{Timestamp: 5000}}, full, 0, 0, ""); len(accepted) != 0 {
+		t.Fatalf("expected nothing accepted over the limit, got %+v", accepted)
+	}
+}
+
+func TestMarkerService_ImportMarkersDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewMarkerService(markerRepo, sceneRepo, nil, &config.Config{}, zap.NewNop())
+
+	sceneRepo.EXPECT().GetByID(uint(1)).Return(&data.Scene{ID: 1, Duration: 600}, nil)
+	markerRepo.EXPECT().GetByUserAndScene(uint(2), uint(1)).Return([]data.UserSceneMarker{{Timestamp: 60}}, nil)
+	// A dry run never creates markers
+	markerRepo.EXPECT().Create(gomock.Any()).Times(0)
+
+	result, err := svc.ImportMarkers(2, 1, MarkerImportOptions{
+		Content:       "60,Duplicate\n120,New",
+		Markers:       []MarkerImportItem{{Timestamp: 180, Label: "From ThePornDB"}},
+		DedupeSeconds: DefaultMarkerImportDedupeSeconds,
+		DryRun:        true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.DryRun || len(result.Markers) != 2 || len(result.Skipped) != 1 || len(result.Created) != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	if _, err := svc.ImportMarkers(2, 1, MarkerImportOptions{DedupeSeconds: -1}); err == nil {
+		t.Fatal("expected error for negative dedupe window")
+	}
+	if _, err := svc.ImportMarkers(2, 1, MarkerImportOptions{}); err == nil {
+		t.Fatal("expected error for an empty import")
+	}
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"fmt"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// maxLabelClipDuration caps the per-label animated thumbnail length in seconds.
+const maxLabelClipDuration = 60
+
+// GetLabelSettings returns the user's label defaults.
+func (s *MarkerService) GetLabelSettings(userID uint) ([]data.MarkerLabelSetting, error) {
+	settings, err := s.markerRepo.GetLabelSettings(userID)
+	if err != nil {
+		s.logger.Error("failed to get label settings", zap.Uint("userID", userID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get label settings", err)
+	}
+	if settings == nil {
+		settings = []data.MarkerLabelSetting{}
+	}
+	return settings, nil
+}
+
+// SetLabelSetting creates or replaces the defaults of a label. They apply to
+// markers created afterwards; existing markers keep their color.
+func (s *MarkerService) SetLabelSetting(userID uint, label, color, icon string, clipDuration int) (*data.MarkerLabelSetting, error) {
+	if label == "" {
+		return nil, apperrors.NewValidationError("label is required")
+	}
+	if len(label) > 100 {
+		return nil, apperrors.NewValidationError("label must be 100 characters or fewer")
+	}
+	if color != "" && (len(color) != 7 || color[0] != '#') {
+		return nil, apperrors.NewValidationError("color must be a valid hex color (e.g., #FF4D4D)")
+	}
+	if len(icon) > 50 {
+		return nil, apperrors.NewValidationError("icon must be 50 characters or fewer")
+	}
+	if clipDuration < 0 || clipDuration > maxLabelClipDuration {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("clip_duration must be between 0 and %d seconds", maxLabelClipDuration))
+	}
+
+	setting := &data.MarkerLabelSetting{
+		UserID:       userID,
+		Label:        label,
+		Color:        color,
+		Icon:         icon,
+		ClipDuration: clipDuration,
+	}
+	if err := s.markerRepo.UpsertLabelSetting(setting); err != nil {
+		s.logger.Error("failed to set label setting", zap.Uint("userID", userID), zap.String("label", label), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to set label setting", err)
+	}
+	return setting, nil
+}
+
+// DeleteLabelSetting removes the defaults of a label.
+func (s *MarkerService) DeleteLabelSetting(userID uint, label string) error {
+	if label == "" {
+		return apperrors.NewValidationError("label is required")
+	}
+	if err := s.markerRepo.DeleteLabelSetting(userID, label); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return apperrors.NewNotFoundError("label setting", label)
+		}
+		s.logger.Error("failed to delete label setting", zap.Uint("userID", userID), zap.String("label", label), zap.Error(err))
+		return apperrors.NewInternalError("failed to delete label setting", err)
+	}
+	return nil
+}
+
+// labelSetting looks up the defaults of a label (best effort - nil when there are none).
+func (s *MarkerService) labelSetting(userID uint, label string) *data.MarkerLabelSetting {
+	if label == "" {
+		return nil
+	}
+	setting, err := s.markerRepo.GetLabelSetting(userID, label)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			s.logger.Warn("failed to get label setting", zap.Uint("userID", userID), zap.String("label", label), zap.Error(err))
+		}
+		return nil
+	}
+	return setting
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestMarkerService_SetLabelSetting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	svc := NewMarkerService(markerRepo, mocks.NewMockSceneRepository(ctrl), nil, &config.Config{}, zap.NewNop())
+
+	for _, tc := range []struct {
+		label, color, icon string
+		clipDuration       int
+	}{
+		{"", "", "", 0},
+		{"Intro", "red", "", 0},
+		{"Intro", "", "", -1},
+		{"Intro", "", "", maxLabelClipDuration + 1},
+	} {
+		if _, err := svc.SetLabelSetting(2, tc.label, tc.color, tc.icon, tc.clipDuration); err == nil {
+			t.Fatalf("expected validation error for %+v", tc)
+		}
+	}
+
+	markerRepo.EXPECT().UpsertLabelSetting(gomock.Any()).Return(nil)
+	setting, err := svc.SetLabelSetting(2, "Intro", "#00FF00", "star", 5)
+	if err != nil || setting.UserID != 2 || setting.Icon != "star" || setting.ClipDuration != 5 {
+		t.Fatalf("unexpected setting %+v, %v", setting, err)
+	}
+
+	markerRepo.EXPECT().DeleteLabelSetting(uint(2), "Outro").Return(gorm.ErrRecordNotFound)
+	if err := svc.DeleteLabelSetting(2, "Outro"); err == nil {
+		t.Fatal("expected error deleting a missing setting")
+	}
+}
+
+func TestMarkerService_CreateMarkerAppliesLabelColor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewMarkerService(markerRepo, sceneRepo, nil, &config.Config{}, zap.NewNop())
+	svc.SetThumbnailQueue(&fakeThumbnailQueue{})
+
+	sceneRepo.EXPECT().GetByID(uint(5)).Return(&data.Scene{ID: 5, Duration: 600}, nil).Times(2)
+	markerRepo.EXPECT().CountByUserAndScene(uint(2), uint(5)).Return(int64(0), nil).Times(2)
+	markerRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+	markerRepo.EXPECT().ApplyLabelTagsToMarker(uint(2), gomock.Any(), "Intro").Return(nil).Times(2)
+	markerRepo.EXPECT().GetLabelSetting(uint(2), "Intro").Return(&data.MarkerLabelSetting{Label: "Intro", Color: "#00FF00"}, nil)
+
+	marker, err := svc.CreateMarker(2, 5, 60, nil, "Intro", "", "")
+	if err != nil || marker.Color != "#00FF00" {
+		t.Fatalf("expected the label color, got %+v, %v", marker, err)
+	}
+
+	// An explicit color wins without looking at the label
+	marker, err = svc.CreateMarker(2, 5, 60, nil, "Intro", "#0000FF", "")
+	if err != nil || marker.Color != "#0000FF" {
+		t.Fatalf("expected the given color, got %+v, %v", marker, err)
+	}
+}
@@ -2,10 +2,12 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"goonhub/internal/apperrors"
@@ -21,6 +23,7 @@ const maxMarkersPerScene = 50
 
 type MarkerService struct {
 	markerRepo                  data.MarkerRepository
+	indexer                     MarkerIndexer
 	sceneRepo                   data.SceneRepository
 	tagRepo                     data.TagRepository
 	markerThumbnailDir          string
@@ -35,7 +38,11 @@ type MarkerService struct {
 	scenePreviewMaxDim          int
 	markerPreviewCRF            int
 	scenePreviewCRF             int
+	thumbnailQueue              MarkerThumbnailQueue
+	eventBus                    *EventBus
+	rbac                        *RBACService
 	logger                      *zap.Logger
+	clipMu                      sync.Mutex // serializes clip generation so a clip is cut once
 }
 
 func NewMarkerService(markerRepo data.MarkerRepository, sceneRepo data.SceneRepository, tagRepo data.TagRepository, cfg *config.Config, logger *zap.Logger) *MarkerService {
@@ -83,6 +90,61 @@ func NewMarkerService(markerRepo data.MarkerRepository, sceneRepo data.SceneRepo
 	}
 }
 
+// SetIndexer sets the indexer that keeps marker labels searchable.
+func (s *MarkerService) SetIndexer(indexer MarkerIndexer) {
+	s.indexer = indexer
+}
+
+// SetRBACService limits marker lists to markers on scenes the user's role may see.
+func (s *MarkerService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
+}
+
+// visibleMarkers drops markers on scenes role may not see. Markers outlive a
+// change of their owner's role or of its restriction, so lists are filtered on
+// read.
+func visibleMarkers[M any](rbac *RBACService, role string, markers []M, sceneID func(M) uint) ([]M, error) {
+	if rbac == nil || len(markers) == 0 {
+		return markers, nil
+	}
+	ids := make([]uint, len(markers))
+	for i, m := range markers {
+		ids[i] = sceneID(m)
+	}
+	visibleIDs, err := rbac.FilterVisibleSceneIDs(role, ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to filter markers", err)
+	}
+	if len(visibleIDs) == len(ids) {
+		return markers, nil
+	}
+
+	visible := make(map[uint]bool, len(visibleIDs))
+	for _, id := range visibleIDs {
+		visible[id] = true
+	}
+	filtered := make([]M, 0, len(visibleIDs))
+	for _, m := range markers {
+		if visible[sceneID(m)] {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered, nil
+}
+
+func markerWithSceneID(m data.MarkerWithScene) uint        { return m.SceneID }
+func collectionMarkerSceneID(m data.CollectionMarker) uint { return m.SceneID }
+
+// indexMarker updates the marker's search segment (best effort - a rebuild restores it).
+func (s *MarkerService) indexMarker(marker *data.UserSceneMarker) {
+	if s.indexer == nil {
+		return
+	}
+	if err := s.indexer.IndexMarker(marker); err != nil {
+		s.logger.Warn("failed to index marker", zap.Uint("markerID", marker.ID), zap.Error(err))
+	}
+}
+
 func (s *MarkerService) ListMarkers(userID, sceneID uint) ([]data.MarkerWithTags, error) {
 	// Verify scene exists before returning markers
 	_, err := s.sceneRepo.GetByID(sceneID)
@@ -133,7 +195,9 @@ func (s *MarkerService) ListMarkers(userID, sceneID uint) ([]data.MarkerWithTags
 	return result, nil
 }
 
-func (s *MarkerService) CreateMarker(userID, sceneID uint, timestamp int, label, color string) (*data.UserSceneMarker, error) {
+// CreateMarker adds a marker at timestamp. A non-nil endTimestamp makes it a range.
+// An empty visibility creates a private marker.
+func (s *MarkerService) CreateMarker(userID, sceneID uint, timestamp int, endTimestamp *int, label, color, visibility string) (*data.UserSceneMarker, error) {
 	// Validate scene exists and get duration
 	scene, err := s.sceneRepo.GetByID(sceneID)
 	if err != nil {
@@ -151,6 +215,11 @@ func (s *MarkerService) CreateMarker(userID, sceneID uint, timestamp int, label,
 	if scene.Duration > 0 && timestamp > scene.Duration {
 		return nil, apperrors.NewValidationError(fmt.Sprintf("timestamp %d exceeds scene duration %d", timestamp, scene.Duration))
 	}
+	if endTimestamp != nil {
+		if err := validateMarkerEnd(timestamp, *endTimestamp, scene.Duration); err != nil {
+			return nil, err
+		}
+	}
 
 	// Check marker limit
 	count, err := s.markerRepo.CountByUserAndScene(userID, sceneID)
@@ -162,7 +231,12 @@ func (s *MarkerService) CreateMarker(userID, sceneID uint, timestamp int, label,
 		return nil, apperrors.NewValidationError(fmt.Sprintf("maximum of %d markers per scene reached", maxMarkersPerScene))
 	}
 
-	// Validate color format (hex color)
+	// Validate color format (hex color); without one the label's default applies
+	if color == "" {
+		if setting := s.labelSetting(userID, label); setting != nil && setting.Color != "" {
+			color = setting.Color
+		}
+	}
 	if color == "" {
 		color = "#FFFFFF" // default white
 	}
@@ -175,27 +249,31 @@ func (s *MarkerService) CreateMarker(userID, sceneID uint, timestamp int, label,
 		return nil, apperrors.NewValidationError("label must be 100 characters or fewer")
 	}
 
+	if visibility == "" {
+		visibility = data.MarkerVisibilityPrivate
+	}
+	if err := validateMarkerVisibility(visibility); err != nil {
+		return nil, err
+	}
+
 	marker := &data.UserSceneMarker{
-		UserID:    userID,
-		SceneID:   sceneID,
-		Timestamp: timestamp,
-		Label:     label,
-		Color:     color,
+		UserID:       userID,
+		SceneID:      sceneID,
+		Timestamp:    timestamp,
+		EndTimestamp: endTimestamp,
+		Label:        label,
+		Color:        color,
+		Visibility:   visibility,
 	}
 
-	if err := s.markerRepo.Create(marker); err != nil {
-		s.logger.Error("failed to create marker", zap.Uint("userID", userID), zap.Uint("sceneID", sceneID), zap.Error(err))
-		return nil, apperrors.NewInternalError("failed to create marker", err)
+	if err := s.saveMarker(marker); err != nil {
+		return nil, err
 	}
 
-	// Apply label tags if the marker has a label
-	if label != "" {
-		if err := s.markerRepo.ApplyLabelTagsToMarker(userID, marker.ID, label); err != nil {
-			s.logger.Warn("failed to apply label tags to marker",
-				zap.Uint("markerID", marker.ID),
-				zap.String("label", label),
-				zap.Error(err))
-		}
+	// Thumbnails are generated by the animated_thumbnails pool, which publishes
+	// marker:thumbnail_ready when done
+	if s.queueMarkerThumbnails(sceneID) {
+		return marker, nil
 	}
 
 	// Generate the appropriate thumbnail type (best effort - marker is still useful without it)
@@ -218,7 +296,30 @@ func (s *MarkerService) CreateMarker(userID, sceneID uint, timestamp int, label,
 	return marker, nil
 }
 
-func (s *MarkerService) UpdateMarker(userID, markerID uint, label *string, color *string, timestamp *int) (*data.UserSceneMarker, error) {
+// saveMarker creates a validated marker, applies its label's default tags and indexes it.
+func (s *MarkerService) saveMarker(marker *data.UserSceneMarker) error {
+	if err := s.markerRepo.Create(marker); err != nil {
+		s.logger.Error("failed to create marker", zap.Uint("userID", marker.UserID), zap.Uint("sceneID", marker.SceneID), zap.Error(err))
+		return apperrors.NewInternalError("failed to create marker", err)
+	}
+
+	// Apply label tags if the marker has a label
+	if marker.Label != "" {
+		if err := s.markerRepo.ApplyLabelTagsToMarker(marker.UserID, marker.ID, marker.Label); err != nil {
+			s.logger.Warn("failed to apply label tags to marker",
+				zap.Uint("markerID", marker.ID),
+				zap.String("label", marker.Label),
+				zap.Error(err))
+		}
+	}
+
+	s.indexMarker(marker)
+	return nil
+}
+
+// UpdateMarker changes the fields that are set. An endTimestamp of 0 turns a
+// range back into a single point.
+func (s *MarkerService) UpdateMarker(userID, markerID uint, label *string, color *string, timestamp *int, endTimestamp *int, visibility *string) (*data.UserSceneMarker, error) {
 	marker, err := s.markerRepo.GetByID(markerID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -248,6 +349,13 @@ func (s *MarkerService) UpdateMarker(userID, markerID uint, label *string, color
 		marker.Color = *color
 	}
 
+	if visibility != nil {
+		if err := validateMarkerVisibility(*visibility); err != nil {
+			return nil, err
+		}
+		marker.Visibility = *visibility
+	}
+
 	var timestampChanged bool
 	var scene *data.Scene
 
@@ -272,11 +380,95 @@ func (s *MarkerService) UpdateMarker(userID, markerID uint, label *string, color
 		marker.Timestamp = *timestamp
 	}
 
+	oldEnd := marker.EndTimestamp
+	if endTimestamp != nil {
+		if *endTimestamp == 0 {
+			marker.EndTimestamp = nil
+		} else {
+			if scene == nil {
+				var err error
+				scene, err = s.sceneRepo.GetByID(marker.SceneID)
+				if err != nil {
+					s.logger.Error("failed to get scene", zap.Uint("sceneID", marker.SceneID), zap.Error(err))
+					return nil, apperrors.NewInternalError("failed to get scene", err)
+				}
+			}
+			end := *endTimestamp
+			marker.EndTimestamp = &end
+		}
+	}
+	// Checked after both ends are applied: moving the start past the end is invalid too
+	if marker.EndTimestamp != nil {
+		duration := 0
+		if scene != nil {
+			duration = scene.Duration
+		}
+		if err := validateMarkerEnd(marker.Timestamp, *marker.EndTimestamp, duration); err != nil {
+			return nil, err
+		}
+	}
+	rangeChanged := timestampChanged || !sameMarkerEnd(oldEnd, marker.EndTimestamp)
+
+	// With a queue, stale thumbnails are dropped and the pool generates the
+	// missing ones; the animated thumbnail covers the range, the static one only the start
+	queued := s.thumbnailQueue != nil && rangeChanged
+	var staleThumbnails []string
+	if queued {
+		if marker.AnimatedThumbnailPath != "" {
+			staleThumbnails = append(staleThumbnails, marker.AnimatedThumbnailPath)
+			marker.AnimatedThumbnailPath = ""
+		}
+		if timestampChanged && marker.ThumbnailPath != "" {
+			staleThumbnails = append(staleThumbnails, marker.ThumbnailPath)
+			marker.ThumbnailPath = ""
+		}
+	}
+
 	if err := s.markerRepo.Update(marker); err != nil {
 		s.logger.Error("failed to update marker", zap.Uint("markerID", markerID), zap.Error(err))
 		return nil, apperrors.NewInternalError("failed to update marker", err)
 	}
 
+	s.indexMarker(marker)
+
+	if rangeChanged {
+		s.removeClips(marker.ID)
+	}
+
+	if queued {
+		for _, name := range staleThumbnails {
+			path := filepath.Join(s.markerThumbnailDir, name)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				s.logger.Warn("failed to delete stale marker thumbnail",
+					zap.Uint("markerID", marker.ID),
+					zap.String("path", path),
+					zap.Error(err))
+			}
+		}
+		s.queueMarkerThumbnails(marker.SceneID)
+		return marker, nil
+	}
+
+	// Only the animated thumbnail covers the range, so a new end regenerates just that
+	if rangeChanged && !timestampChanged && s.markerThumbnailType == "animated" {
+		if scene == nil {
+			var err error
+			scene, err = s.sceneRepo.GetByID(marker.SceneID)
+			if err != nil {
+				s.logger.Warn("failed to get scene for thumbnail regeneration",
+					zap.Uint("sceneID", marker.SceneID),
+					zap.Error(err))
+			}
+		}
+		if scene != nil {
+			if err := s.generateAnimatedThumbnail(marker, scene); err != nil {
+				s.logger.Warn("failed to regenerate animated marker thumbnail",
+					zap.Uint("markerID", marker.ID),
+					zap.Error(err))
+			}
+		}
+	}
+
 	// Regenerate thumbnail if timestamp changed
 	if timestampChanged {
 		// Delete old thumbnail
@@ -364,6 +556,12 @@ func (s *MarkerService) DeleteMarker(userID, markerID uint) error {
 		return apperrors.NewInternalError("failed to delete marker", err)
 	}
 
+	if s.indexer != nil {
+		if err := s.indexer.DeleteMarkerIndex(markerID); err != nil {
+			s.logger.Warn("failed to remove marker from search index", zap.Uint("markerID", markerID), zap.Error(err))
+		}
+	}
+
 	// Clean up thumbnail files after successful DB delete (best effort)
 	if thumbnailPath != "" {
 		if err := os.Remove(thumbnailPath); err != nil && !os.IsNotExist(err) {
@@ -381,6 +579,7 @@ func (s *MarkerService) DeleteMarker(userID, markerID uint) error {
 				zap.Error(err))
 		}
 	}
+	s.removeClips(markerID)
 
 	return nil
 }
@@ -448,7 +647,7 @@ func (s *MarkerService) GetLabelGroups(userID uint, page, limit int, sortBy stri
 	return groups, total, nil
 }
 
-func (s *MarkerService) GetMarkersByLabel(userID uint, label string, page, limit int) ([]data.MarkerWithScene, int64, error) {
+func (s *MarkerService) GetMarkersByLabel(userID uint, role string, label string, page, limit int) ([]data.MarkerWithScene, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -462,10 +661,14 @@ func (s *MarkerService) GetMarkersByLabel(userID uint, label string, page, limit
 		s.logger.Error("failed to get markers by label", zap.Uint("userID", userID), zap.String("label", label), zap.Error(err))
 		return nil, 0, apperrors.NewInternalError("failed to get markers by label", err)
 	}
+	markers, err = visibleMarkers(s.rbac, role, markers, markerWithSceneID)
+	if err != nil {
+		return nil, 0, err
+	}
 	return markers, total, nil
 }
 
-func (s *MarkerService) GetAllMarkers(userID uint, page, limit int, sortBy string) ([]data.MarkerWithScene, int64, error) {
+func (s *MarkerService) GetAllMarkers(userID uint, role string, page, limit int, sortBy string) ([]data.MarkerWithScene, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -490,9 +693,44 @@ func (s *MarkerService) GetAllMarkers(userID uint, page, limit int, sortBy strin
 		s.logger.Error("failed to get all markers", zap.Uint("userID", userID), zap.Error(err))
 		return nil, 0, apperrors.NewInternalError("failed to get all markers", err)
 	}
+	markers, err = visibleMarkers(s.rbac, role, markers, markerWithSceneID)
+	if err != nil {
+		return nil, 0, err
+	}
 	return markers, total, nil
 }
 
+// GetAllMarkersAfter pages the user's markers by cursor. It returns the next
+// page's cursor, empty on the last page.
+func (s *MarkerService) GetAllMarkersAfter(userID uint, role string, cursor string, limit int, sortBy string) ([]data.MarkerWithScene, string, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	switch sortBy {
+	case "label_asc", "label_desc", "recent", "oldest":
+	default:
+		sortBy = "label_asc"
+	}
+
+	after, err := data.DecodeCursor(cursor, sortBy)
+	if err != nil {
+		return nil, "", apperrors.NewValidationErrorWithField("cursor", "invalid cursor")
+	}
+	markers, next, err := s.markerRepo.GetAllMarkersForUserAfter(userID, after, limit, sortBy)
+	if err != nil {
+		if errors.Is(err, data.ErrInvalidCursor) {
+			return nil, "", apperrors.NewValidationErrorWithField("cursor", "invalid cursor")
+		}
+		s.logger.Error("failed to get all markers", zap.Uint("userID", userID), zap.Error(err))
+		return nil, "", apperrors.NewInternalError("failed to get all markers", err)
+	}
+	markers, err = visibleMarkers(s.rbac, role, markers, markerWithSceneID)
+	if err != nil {
+		return nil, "", err
+	}
+	return markers, next.Encode(), nil
+}
+
 // GenerateMissingForScene finds all markers for a scene that lack thumbnails and generates them.
 // This is best-effort: individual failures are logged and skipped.
 // Implements jobs.MarkerThumbnailGenerator.
@@ -532,6 +770,7 @@ func (s *MarkerService) GenerateMissingForScene(ctx context.Context, sceneID uin
 				zap.Error(err))
 			continue
 		}
+		s.publishThumbnailReady(&markers[i])
 		generated++
 	}
 
@@ -605,7 +844,16 @@ func (s *MarkerService) generateAnimatedThumbnail(marker *data.UserSceneMarker,
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
-	if err := ffmpeg.ExtractAnimatedThumbnailWithContext(ctx, scene.StoredPath, animatedPath, seekPosition, s.markerAnimatedDuration, s.markerThumbnailMaxDim, s.markerPreviewCRF); err != nil {
+	// A range shorter than the configured length is previewed in full, and no further
+	duration := s.markerAnimatedDuration
+	if setting := s.labelSetting(marker.UserID, marker.Label); setting != nil && setting.ClipDuration > 0 {
+		duration = setting.ClipDuration
+	}
+	if marker.EndTimestamp != nil {
+		duration = min(duration, *marker.EndTimestamp-marker.Timestamp)
+	}
+
+	if err := ffmpeg.ExtractAnimatedThumbnailWithContext(ctx, scene.StoredPath, animatedPath, seekPosition, duration, s.markerThumbnailMaxDim, s.markerPreviewCRF); err != nil {
 		return fmt.Errorf("failed to extract animated thumbnail: %w", err)
 	}
 
@@ -620,12 +868,15 @@ func (s *MarkerService) generateAnimatedThumbnail(marker *data.UserSceneMarker,
 
 // GenerateMissingAnimatedForScene finds all markers for a scene that lack animated thumbnails and generates them.
 // When forceTarget is "markers" or "both", all markers are regenerated regardless of existing thumbnails.
+// With static marker thumbnails configured, the static thumbnail of each marker is (re)generated alongside.
+// onProgress, if set, receives the share of markers processed so far.
 // Implements jobs.AnimatedThumbnailGenerator.
-func (s *MarkerService) GenerateMissingAnimatedForScene(ctx context.Context, sceneID uint, forceTarget string) (int, error) {
+func (s *MarkerService) GenerateMissingAnimatedForScene(ctx context.Context, sceneID uint, forceTarget string, onProgress func(percent int)) (int, error) {
 	var markers []data.UserSceneMarker
 	var err error
 
-	if forceTarget == "markers" || forceTarget == "both" {
+	force := forceTarget == "markers" || forceTarget == "both"
+	if force {
 		markers, err = s.markerRepo.GetAllByScene(sceneID)
 	} else {
 		markers, err = s.markerRepo.GetBySceneWithoutAnimatedThumbnail(sceneID)
@@ -647,27 +898,82 @@ func (s *MarkerService) GenerateMissingAnimatedForScene(ctx context.Context, sce
 		zap.Uint("scene_id", sceneID),
 		zap.Int("count", len(markers)))
 
+	generated := s.generateSceneMarkerThumbnails(ctx, scene, markers, force, onProgress)
+
+	// A marker created while this job runs isn't queued again (the job is still
+	// pending or running), so pick those up before finishing
+	if !force {
+		attempted := make(map[uint]bool, len(markers))
+		for _, m := range markers {
+			attempted[m.ID] = true
+		}
+		for ctx.Err() == nil {
+			more, err := s.markerRepo.GetBySceneWithoutAnimatedThumbnail(sceneID)
+			if err != nil {
+				s.logger.Warn("Failed to re-check markers for animated thumbnails", zap.Uint("scene_id", sceneID), zap.Error(err))
+				break
+			}
+			var added []data.UserSceneMarker
+			for _, m := range more {
+				if !attempted[m.ID] {
+					attempted[m.ID] = true
+					added = append(added, m)
+				}
+			}
+			if len(added) == 0 {
+				break
+			}
+			generated += s.generateSceneMarkerThumbnails(ctx, scene, added, false, nil)
+		}
+	}
+
+	return generated, nil
+}
+
+// generateSceneMarkerThumbnails generates the animated thumbnail of each marker
+// (and the static one when configured) and notifies the marker's owner.
+// It returns how many animated thumbnails were generated.
+func (s *MarkerService) generateSceneMarkerThumbnails(ctx context.Context, scene *data.Scene, markers []data.UserSceneMarker, force bool, onProgress func(percent int)) int {
 	generated := 0
 	for i := range markers {
 		if ctx.Err() != nil {
 			s.logger.Info("Animated marker thumbnail generation interrupted",
-				zap.Uint("scene_id", sceneID),
+				zap.Uint("scene_id", scene.ID),
 				zap.Int("generated", generated),
 				zap.Int("remaining", len(markers)-i))
 			break
 		}
 
-		if err := s.generateAnimatedThumbnail(&markers[i], scene); err != nil {
+		staticGenerated := false
+		if s.markerThumbnailType == "static" && (force || markers[i].ThumbnailPath == "") {
+			if err := s.generateThumbnail(&markers[i], scene); err != nil {
+				s.logger.Warn("Failed to generate marker thumbnail",
+					zap.Uint("marker_id", markers[i].ID),
+					zap.Int("timestamp", markers[i].Timestamp),
+					zap.Error(err))
+			} else {
+				staticGenerated = true
+			}
+		}
+
+		err := s.generateAnimatedThumbnail(&markers[i], scene)
+		if onProgress != nil {
+			onProgress((i + 1) * 100 / len(markers))
+		}
+		if err != nil {
 			s.logger.Warn("Failed to generate animated marker thumbnail",
 				zap.Uint("marker_id", markers[i].ID),
 				zap.Int("timestamp", markers[i].Timestamp),
 				zap.Error(err))
+			if staticGenerated {
+				s.publishThumbnailReady(&markers[i])
+			}
 			continue
 		}
+		s.publishThumbnailReady(&markers[i])
 		generated++
 	}
-
-	return generated, nil
+	return generated
 }
 
 // GetMarkerThumbnailType returns the current marker thumbnail type setting
@@ -800,8 +1106,9 @@ func (s *MarkerService) SetMarkerTags(userID, markerID uint, tagIDs []uint) erro
 // GenerateScenePreview generates a preview video for a scene by sampling multiple segments.
 // Returns nil immediately if scene preview generation is disabled.
 // When forceTarget is "previews" or "both", the existing preview is regenerated.
+// onProgress, if set, receives the encoding progress reported by ffmpeg.
 // Implements jobs.AnimatedThumbnailGenerator.
-func (s *MarkerService) GenerateScenePreview(ctx context.Context, sceneID uint, forceTarget string) error {
+func (s *MarkerService) GenerateScenePreview(ctx context.Context, sceneID uint, forceTarget string, onProgress func(percent int)) error {
 	if !s.scenePreviewEnabled {
 		return nil
 	}
@@ -836,8 +1143,8 @@ func (s *MarkerService) GenerateScenePreview(ctx context.Context, sceneID uint,
 	outputFilename := fmt.Sprintf("%d_preview.mp4", scene.ID)
 	outputPath := filepath.Join(s.scenePreviewDir, outputFilename)
 
-	if err := ffmpeg.ExtractScenePreviewWithContext(ctx, scene.StoredPath, outputPath,
-		scene.Duration, s.scenePreviewSegments, s.scenePreviewSegmentDuration, s.scenePreviewMaxDim, s.scenePreviewCRF); err != nil {
+	if err := ffmpeg.ExtractScenePreviewWithProgress(ctx, scene.StoredPath, outputPath,
+		scene.Duration, s.scenePreviewSegments, s.scenePreviewSegmentDuration, s.scenePreviewMaxDim, s.scenePreviewCRF, onProgress); err != nil {
 		return fmt.Errorf("failed to generate scene preview: %w", err)
 	}
 
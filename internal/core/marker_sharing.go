@@ -0,0 +1,59 @@
+package core
+
+import (
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func validateMarkerVisibility(visibility string) error {
+	if visibility != data.MarkerVisibilityPrivate && visibility != data.MarkerVisibilityShared {
+		return apperrors.NewValidationError("visibility must be 'private' or 'shared'")
+	}
+	return nil
+}
+
+// ListSharedMarkers returns the markers other users shared on a scene.
+func (s *MarkerService) ListSharedMarkers(userID, sceneID uint) ([]data.SharedMarker, error) {
+	if _, err := s.sceneRepo.GetByID(sceneID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperrors.NewNotFoundError("scene", sceneID)
+		}
+		s.logger.Error("failed to verify scene exists", zap.Uint("sceneID", sceneID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to verify scene", err)
+	}
+
+	markers, err := s.markerRepo.GetSharedByScene(sceneID, userID)
+	if err != nil {
+		s.logger.Error("failed to list shared markers", zap.Uint("sceneID", sceneID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to list shared markers", err)
+	}
+	if markers == nil {
+		markers = []data.SharedMarker{}
+	}
+	return markers, nil
+}
+
+// CopySharedMarker adds a private copy of another user's shared marker to the
+// user's own markers. The copy gets the user's label tags, not the owner's tags.
+func (s *MarkerService) CopySharedMarker(userID, markerID uint) (*data.UserSceneMarker, error) {
+	source, err := s.markerRepo.GetByID(markerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, apperrors.NewNotFoundError("marker", markerID)
+		}
+		s.logger.Error("failed to get marker", zap.Uint("markerID", markerID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get marker", err)
+	}
+	// Private markers of other users are reported as missing, not forbidden
+	if source.Visibility != data.MarkerVisibilityShared {
+		return nil, apperrors.NewNotFoundError("marker", markerID)
+	}
+	if source.UserID == userID {
+		return nil, apperrors.NewValidationError("marker is already yours")
+	}
+
+	return s.CreateMarker(userID, source.SceneID, source.Timestamp, source.EndTimestamp, source.Label, source.Color, data.MarkerVisibilityPrivate)
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestMarkerService_CopySharedMarker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewMarkerService(markerRepo, sceneRepo, nil, &config.Config{Processing: config.ProcessingConfig{MarkerThumbnailDir: t.TempDir()}}, zap.NewNop())
+
+	end := 90
+	markerRepo.EXPECT().GetByID(uint(1)).Return(&data.UserSceneMarker{
+		ID: 1, UserID: 3, SceneID: 5, Timestamp: 60, EndTimestamp: &end, Label: "Intro", Color: "#FF0000", Visibility: data.MarkerVisibilityShared,
+	}, nil)
+	sceneRepo.EXPECT().GetByID(uint(5)).Return(&data.Scene{ID: 5, Duration: 600}, nil)
+	markerRepo.EXPECT().CountByUserAndScene(uint(2), uint(5)).Return(int64(0), nil)
+	markerRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(m *data.UserSceneMarker) error {
+		m.ID = 9
+		return nil
+	})
+	markerRepo.EXPECT().ApplyLabelTagsToMarker(uint(2), uint(9), "Intro").Return(nil)
+
+	copied, err := svc.CopySharedMarker(2, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if copied.UserID != 2 || copied.Visibility != data.MarkerVisibilityPrivate || copied.EndTimestamp == nil || *copied.EndTimestamp != 90 || copied.Label != "Intro" {
+		t.Fatalf("unexpected copy %+v", copied)
+	}
+
+	// Private markers can't be copied, and neither can the user's own
+	markerRepo.EXPECT().GetByID(uint(2)).Return(&data.UserSceneMarker{ID: 2, UserID: 3, Visibility: data.MarkerVisibilityPrivate}, nil)
+	if _, err := svc.CopySharedMarker(2, 2); err == nil {
+		t.Fatal("expected error copying a private marker")
+	}
+	markerRepo.EXPECT().GetByID(uint(3)).Return(&data.UserSceneMarker{ID: 3, UserID: 2, Visibility: data.MarkerVisibilityShared}, nil)
+	if _, err := svc.CopySharedMarker(2, 3); err == nil {
+		t.Fatal("expected error copying an own marker")
+	}
+}
+
+func TestMarkerService_UpdateMarkerVisibility(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	svc := NewMarkerService(markerRepo, mocks.NewMockSceneRepository(ctrl), nil, &config.Config{}, zap.NewNop())
+
+	markerRepo.EXPECT().GetByID(uint(1)).Return(&data.UserSceneMarker{ID: 1, UserID: 2, Visibility: data.MarkerVisibilityPrivate}, nil).Times(2)
+	markerRepo.EXPECT().Update(gomock.Any()).Return(nil)
+
+	shared := data.MarkerVisibilityShared
+	marker, err := svc.UpdateMarker(2, 1, nil, nil, nil, nil, &shared)
+	if err != nil || marker.Visibility != data.MarkerVisibilityShared {
+		t.Fatalf("expected a shared marker, got %+v, %v", marker, err)
+	}
+
+	public := "public"
+	if _, err := svc.UpdateMarker(2, 1, nil, nil, nil, nil, &public); err == nil {
+		t.Fatal("expected error for an unknown visibility")
+	}
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// markerThumbnailPhase is the processing phase that generates marker thumbnails.
+const markerThumbnailPhase = "animated_thumbnails"
+
+// MarkerThumbnailQueue submits marker thumbnail generation to the processing
+// pools, where it gets job history entries like any other phase.
+// Implemented by SceneProcessingService.
+type MarkerThumbnailQueue interface {
+	SubmitPhaseWithForce(sceneID uint, phase string, priority int, forceTarget string) error
+	SubmitBulkPhase(phase string, mode string, forceTarget string, sceneIDs []uint) (*BulkPhaseResult, error)
+}
+
+// SetThumbnailQueue sets the queue bulk thumbnail generation is submitted to.
+func (s *MarkerService) SetThumbnailQueue(queue MarkerThumbnailQueue) {
+	s.thumbnailQueue = queue
+}
+
+// SetEventBus sets the bus thumbnail readiness is published on.
+func (s *MarkerService) SetEventBus(eventBus *EventBus) {
+	s.eventBus = eventBus
+}
+
+// queueMarkerThumbnails submits generation of the scene's missing marker
+// thumbnails to the processing pools. It returns false when no queue is set,
+// leaving the generation to the caller.
+func (s *MarkerService) queueMarkerThumbnails(sceneID uint) bool {
+	if s.thumbnailQueue == nil {
+		return false
+	}
+	if err := s.thumbnailQueue.SubmitPhaseWithForce(sceneID, markerThumbnailPhase, 1, ""); err != nil {
+		s.logger.Warn("failed to queue marker thumbnails", zap.Uint("sceneID", sceneID), zap.Error(err))
+	}
+	return true
+}
+
+// publishThumbnailReady tells the marker's owner that its thumbnails changed.
+func (s *MarkerService) publishThumbnailReady(marker *data.UserSceneMarker) {
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(SceneEvent{
+		Type:    "marker:thumbnail_ready",
+		SceneID: marker.SceneID,
+		UserID:  marker.UserID,
+		Data: map[string]any{
+			"marker_id":               marker.ID,
+			"thumbnail_path":          marker.ThumbnailPath,
+			"animated_thumbnail_path": marker.AnimatedThumbnailPath,
+		},
+	})
+}
+
+// RegenerateThumbnails queues regeneration of the marker thumbnails on every
+// scene the user has markers on, or only on sceneID when it is non-zero.
+// Thumbnails are generated per scene, so other users' markers on those scenes
+// are regenerated too.
+func (s *MarkerService) RegenerateThumbnails(userID, sceneID uint) (*BulkPhaseResult, error) {
+	if sceneID != 0 {
+		if _, err := s.sceneRepo.GetByID(sceneID); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, apperrors.NewNotFoundError("scene", sceneID)
+			}
+			s.logger.Error("failed to verify scene exists", zap.Uint("sceneID", sceneID), zap.Error(err))
+			return nil, apperrors.NewInternalError("failed to verify scene", err)
+		}
+		count, err := s.markerRepo.CountByUserAndScene(userID, sceneID)
+		if err != nil {
+			s.logger.Error("failed to count markers", zap.Uint("userID", userID), zap.Uint("sceneID", sceneID), zap.Error(err))
+			return nil, apperrors.NewInternalError("failed to count markers", err)
+		}
+		if count == 0 {
+			return nil, apperrors.NewValidationError("you have no markers on this scene")
+		}
+		return s.queueThumbnailRegeneration([]uint{sceneID})
+	}
+
+	sceneIDs, err := s.markerRepo.GetMarkedSceneIDs(userID)
+	if err != nil {
+		s.logger.Error("failed to get marked scenes", zap.Uint("userID", userID), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get marked scenes", err)
+	}
+	return s.queueThumbnailRegeneration(sceneIDs)
+}
+
+// RegenerateAllThumbnails queues regeneration of the marker thumbnails on every
+// scene that has markers.
+func (s *MarkerService) RegenerateAllThumbnails() (*BulkPhaseResult, error) {
+	sceneIDs, err := s.markerRepo.GetAllMarkedSceneIDs()
+	if err != nil {
+		s.logger.Error("failed to get marked scenes", zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get marked scenes", err)
+	}
+	return s.queueThumbnailRegeneration(sceneIDs)
+}
+
+func (s *MarkerService) queueThumbnailRegeneration(sceneIDs []uint) (*BulkPhaseResult, error) {
+	if s.thumbnailQueue == nil {
+		return nil, apperrors.NewInternalError("marker thumbnail queue is not configured", nil)
+	}
+	if len(sceneIDs) == 0 {
+		return &BulkPhaseResult{}, nil
+	}
+
+	result, err := s.thumbnailQueue.SubmitBulkPhase(markerThumbnailPhase, "all", "markers", sceneIDs)
+	if err != nil {
+		s.logger.Error("failed to queue marker thumbnail regeneration", zap.Int("scenes", len(sceneIDs)), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to queue marker thumbnail regeneration", err)
+	}
+	return result, nil
+}
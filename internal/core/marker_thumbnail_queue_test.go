@@ -0,0 +1,142 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type fakeThumbnailQueue struct {
+	queued       []uint
+	bulkSceneIDs [][]uint
+	forceTargets []string
+}
+
+func (f *fakeThumbnailQueue) SubmitPhaseWithForce(sceneID uint, phase string, priority int, forceTarget string) error {
+	f.queued = append(f.queued, sceneID)
+	return nil
+}
+
+func (f *fakeThumbnailQueue) SubmitBulkPhase(phase string, mode string, forceTarget string, sceneIDs []uint) (*BulkPhaseResult, error) {
+	f.bulkSceneIDs = append(f.bulkSceneIDs, sceneIDs)
+	f.forceTargets = append(f.forceTargets, forceTarget)
+	return &BulkPhaseResult{Submitted: len(sceneIDs)}, nil
+}
+
+func TestMarkerService_RegenerateThumbnails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewMarkerService(markerRepo, sceneRepo, nil, &config.Config{}, zap.NewNop())
+
+	// Without a queue there is nowhere to run the jobs
+	markerRepo.EXPECT().GetAllMarkedSceneIDs().Return([]uint{1}, nil)
+	if _, err := svc.RegenerateAllThumbnails(); err == nil {
+		t.Fatal("expected error without a thumbnail queue")
+	}
+
+	queue := &fakeThumbnailQueue{}
+	svc.SetThumbnailQueue(queue)
+
+	markerRepo.EXPECT().GetMarkedSceneIDs(uint(2)).Return([]uint{4, 7}, nil)
+	result, err := svc.RegenerateThumbnails(2, 0)
+	if err != nil || result.Submitted != 2 {
+		t.Fatalf("expected 2 submitted scenes, got %+v, %v", result, err)
+	}
+
+	sceneRepo.EXPECT().GetByID(uint(7)).Return(&data.Scene{ID: 7}, nil)
+	markerRepo.EXPECT().CountByUserAndScene(uint(2), uint(7)).Return(int64(3), nil)
+	if _, err := svc.RegenerateThumbnails(2, 7); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// A scene the user has no markers on is refused
+	sceneRepo.EXPECT().GetByID(uint(8)).Return(&data.Scene{ID: 8}, nil)
+	markerRepo.EXPECT().CountByUserAndScene(uint(2), uint(8)).Return(int64(0), nil)
+	if _, err := svc.RegenerateThumbnails(2, 8); err == nil {
+		t.Fatal("expected error for a scene without markers")
+	}
+
+	if len(queue.bulkSceneIDs) != 2 || len(queue.bulkSceneIDs[1]) != 1 || queue.bulkSceneIDs[1][0] != 7 {
+		t.Fatalf("unexpected submissions %v", queue.bulkSceneIDs)
+	}
+	for _, target := range queue.forceTargets {
+		if target != "markers" {
+			t.Fatalf("expected marker force target, got %q", target)
+		}
+	}
+}
+
+func TestMarkerService_CreateMarkerQueuesThumbnails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewMarkerService(markerRepo, sceneRepo, nil, &config.Config{}, zap.NewNop())
+	queue := &fakeThumbnailQueue{}
+	svc.SetThumbnailQueue(queue)
+
+	// The scene file doesn't exist, so generating in the request would fail loudly
+	sceneRepo.EXPECT().GetByID(uint(5)).Return(&data.Scene{ID: 5, Duration: 600, StoredPath: "/missing.mp4"}, nil)
+	markerRepo.EXPECT().CountByUserAndScene(uint(2), uint(5)).Return(int64(0), nil)
+	markerRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(m *data.UserSceneMarker) error {
+		m.ID = 9
+		return nil
+	})
+	markerRepo.EXPECT().ApplyLabelTagsToMarker(uint(2), uint(9), "Intro").Return(nil)
+
+	marker, err := svc.CreateMarker(2, 5, 60, nil, "Intro", "#FF0000", "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if marker.ThumbnailPath != "" || len(queue.queued) != 1 || queue.queued[0] != 5 {
+		t.Fatalf("expected the scene to be queued, got %v for %+v", queue.queued, marker)
+	}
+}
+
+func TestMarkerService_UpdateMarkerDropsStaleThumbnails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	dir := t.TempDir()
+	svc := NewMarkerService(markerRepo, sceneRepo, nil, &config.Config{Processing: config.ProcessingConfig{MarkerThumbnailDir: dir}}, zap.NewNop())
+	queue := &fakeThumbnailQueue{}
+	svc.SetThumbnailQueue(queue)
+
+	for _, name := range []string{"marker_1.webp", "marker_1.mp4"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the end moves: the static thumbnail still shows the start
+	markerRepo.EXPECT().GetByID(uint(1)).Return(&data.UserSceneMarker{
+		ID: 1, UserID: 2, SceneID: 5, Timestamp: 60, ThumbnailPath: "marker_1.webp", AnimatedThumbnailPath: "marker_1.mp4",
+	}, nil)
+	sceneRepo.EXPECT().GetByID(uint(5)).Return(&data.Scene{ID: 5, Duration: 600}, nil)
+	markerRepo.EXPECT().Update(gomock.Any()).Return(nil)
+
+	end := 90
+	marker, err := svc.UpdateMarker(2, 1, nil, nil, nil, &end, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if marker.ThumbnailPath != "marker_1.webp" || marker.AnimatedThumbnailPath != "" {
+		t.Fatalf("unexpected thumbnails %+v", marker)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "marker_1.mp4")); !os.IsNotExist(err) {
+		t.Fatal("expected the animated thumbnail to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "marker_1.webp")); err != nil {
+		t.Fatal("expected the static thumbnail to be kept")
+	}
+	if len(queue.queued) != 1 {
+		t.Fatalf("expected one queued scene, got %v", queue.queued)
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"goonhub/internal/config"
+)
+
+func newTestMediaSigner(now time.Time) *MediaSigner {
+	s := NewMediaSigner("secret", config.MediaSigningConfig{Enabled: true, TTL: time.Hour})
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestMediaSigner_SignVerify(t *testing.T) {
+	s := newTestMediaSigner(time.Unix(10_000, 0))
+
+	exp, sig := s.Sign(42)
+	if !s.Verify(42, strconv.FormatInt(exp, 10), sig) {
+		t.Fatal("expected signature to verify")
+	}
+	if s.Verify(43, strconv.FormatInt(exp, 10), sig) {
+		t.Fatal("expected signature for another scene to be rejected")
+	}
+	if s.Verify(42, strconv.FormatInt(exp+1, 10), sig) {
+		t.Fatal("expected tampered expiry to be rejected")
+	}
+	if s.Verify(42, "abc", sig) || s.Verify(42, strconv.FormatInt(exp, 10), "") {
+		t.Fatal("expected malformed parameters to be rejected")
+	}
+}
+
+func TestMediaSigner_Expiry(t *testing.T) {
+	now := time.Unix(10_000, 0)
+	s := newTestMediaSigner(now)
+
+	exp, sig := s.Sign(1)
+	validFor := time.Unix(exp, 0).Sub(now)
+	if validFor < time.Hour || validFor > 2*time.Hour {
+		t.Fatalf("expected validity between TTL and twice the TTL, got %s", validFor)
+	}
+	// Signing again within the same window yields the same URL
+	s.now = func() time.Time { return now.Add(time.Minute) }
+	if exp2, sig2 := s.Sign(1); exp2 != exp || sig2 != sig {
+		t.Fatal("expected stable signature within a TTL window")
+	}
+
+	s.now = func() time.Time { return time.Unix(exp+1, 0) }
+	if s.Verify(1, strconv.FormatInt(exp, 10), sig) {
+		t.Fatal("expected expired signature to be rejected")
+	}
+}
+
+func TestMediaSigner_DifferentSecret(t *testing.T) {
+	s := newTestMediaSigner(time.Unix(10_000, 0))
+	other := NewMediaSigner("other", config.MediaSigningConfig{Enabled: true, TTL: time.Hour})
+	other.now = s.now
+
+	exp, sig := s.Sign(1)
+	if other.Verify(1, strconv.FormatInt(exp, 10), sig) {
+		t.Fatal("expected signature from another secret to be rejected")
+	}
+}
+
+func TestMediaSigner_SignPath(t *testing.T) {
+	s := newTestMediaSigner(time.Unix(10_000, 0))
+
+	if got := s.SignPath("/thumbnails/1?size=lg", 1); !strings.HasPrefix(got, "/thumbnails/1?size=lg&exp=") || !strings.Contains(got, "&sig=") {
+		t.Fatalf("unexpected signed path %q", got)
+	}
+	if got := s.SignPath("/vtt/1", 1); !strings.HasPrefix(got, "/vtt/1?exp=") {
+		t.Fatalf("unexpected signed path %q", got)
+	}
+
+	disabled := NewMediaSigner("secret", config.MediaSigningConfig{})
+	if got := disabled.SignPath("/vtt/1", 1); got != "/vtt/1" {
+		t.Fatalf("expected unchanged path when disabled, got %q", got)
+	}
+	var nilSigner *MediaSigner
+	if nilSigner.Enabled() || nilSigner.SignPath("/vtt/1", 1) != "/vtt/1" {
+		t.Fatal("expected nil signer to be disabled")
+	}
+}
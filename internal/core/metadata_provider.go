@@ -0,0 +1,92 @@
+package core
+
+import (
+	"strings"
+
+	"goonhub/internal/apperrors"
+)
+
+// Metadata provider names
+const (
+	MetadataProviderPornDB  = "porndb"
+	MetadataProviderStashDB = "stashdb"
+)
+
+// MetadataProvider is a scene and performer metadata source. Results use the
+// PornDB shapes the frontend already renders, whichever provider they came
+// from.
+type MetadataProvider interface {
+	Name() string  // one of the MetadataProvider* names
+	Label() string // shown in error messages
+	IsConfigured() bool
+	SearchScenes(opts SceneSearchOptions) ([]PornDBScene, error)
+	GetSceneDetails(id string) (*PornDBScene, error)
+	SearchPerformers(query string) ([]PornDBPerformer, error)
+}
+
+// MetadataProviderStatus is a provider as listed on the PornDB status endpoint.
+type MetadataProviderStatus struct {
+	Name       string `json:"name"`
+	Configured bool   `json:"configured"`
+}
+
+// MetadataProviders looks up metadata providers by name. PornDB is the
+// default when no provider is chosen.
+type MetadataProviders struct {
+	providers []MetadataProvider
+}
+
+// NewMetadataProviders registers the PornDB and StashDB clients. Either may
+// be nil.
+func NewMetadataProviders(porndb *PornDBService, stashdb *StashDBService) *MetadataProviders {
+	var providers []MetadataProvider
+	// Keep nil services from becoming non-nil interfaces
+	if porndb != nil {
+		providers = append(providers, porndb)
+	}
+	if stashdb != nil {
+		providers = append(providers, stashdb)
+	}
+	return newMetadataProviders(providers...)
+}
+
+func newMetadataProviders(providers ...MetadataProvider) *MetadataProviders {
+	return &MetadataProviders{providers: providers}
+}
+
+// Get returns the provider with the given name, or PornDB for "". The
+// provider may not be configured.
+func (p *MetadataProviders) Get(name string) (MetadataProvider, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = MetadataProviderPornDB
+	}
+	for _, provider := range p.providers {
+		if provider.Name() == name {
+			return provider, nil
+		}
+	}
+	return nil, apperrors.NewValidationErrorWithField("provider", "provider must be one of porndb, stashdb")
+}
+
+// List returns every registered provider and whether it is configured.
+func (p *MetadataProviders) List() []MetadataProviderStatus {
+	statuses := make([]MetadataProviderStatus, 0, len(p.providers))
+	for _, provider := range p.providers {
+		statuses = append(statuses, MetadataProviderStatus{Name: provider.Name(), Configured: provider.IsConfigured()})
+	}
+	return statuses
+}
+
+// ValidateFingerprintSearch checks the fingerprint half of a scene search.
+func ValidateFingerprintSearch(opts SceneSearchOptions) error {
+	if opts.Fingerprint == "" {
+		return nil
+	}
+	switch opts.FingerprintAlgorithm {
+	case FingerprintMD5, FingerprintOSHash, FingerprintPHash:
+		return nil
+	default:
+		return apperrors.NewValidationErrorWithField("algorithm", "algorithm must be one of md5, oshash, phash")
+	}
+}
@@ -0,0 +1,318 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxOfflineSyncScenes caps how many scenes one user may keep offline
+	MaxOfflineSyncScenes = 500
+	// OfflineSyncTombstoneRetention is how long removals are remembered for delta
+	// manifests; clients that last synced earlier get a full manifest.
+	OfflineSyncTombstoneRetention = 30 * 24 * time.Hour
+)
+
+// SyncMarker is a marker of an offline scene.
+type SyncMarker struct {
+	ID        uint   `json:"id"`
+	Timestamp int    `json:"timestamp"`
+	Label     string `json:"label"`
+	Color     string `json:"color"`
+}
+
+// SyncSceneMetadata is the part of a manifest entry covered by its checksum.
+type SyncSceneMetadata struct {
+	ID          uint         `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Duration    int          `json:"duration"`
+	Size        int64        `json:"size"`
+	Width       int          `json:"width"`
+	Height      int          `json:"height"`
+	Studio      string       `json:"studio"`
+	Tags        []string     `json:"tags"`
+	Actors      []string     `json:"actors"`
+	ReleaseDate *time.Time   `json:"release_date"`
+	FileHash    string       `json:"file_hash"`
+	HasVtt      bool         `json:"has_vtt"`
+	Markers     []SyncMarker `json:"markers"`
+}
+
+// SyncSceneEntry is one scene in a sync manifest. Checksum changes whenever the
+// scene's metadata or the user's markers change; FileHash identifies the video file.
+type SyncSceneEntry struct {
+	SyncSceneMetadata
+	Checksum     string    `json:"checksum"`
+	ChangedAt    time.Time `json:"changed_at"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	VttURL       string    `json:"vtt_url,omitempty"`
+	DownloadURL  string    `json:"download_url"`
+}
+
+// SyncManifest lists a user's offline scenes. Full manifests list every scene;
+// delta manifests only list scenes changed after the requested time and the
+// scenes removed since. Clients pass GeneratedAt as the next "since".
+type SyncManifest struct {
+	GeneratedAt     time.Time        `json:"generated_at"`
+	Full            bool             `json:"full"`
+	Scenes          []SyncSceneEntry `json:"scenes"`
+	RemovedSceneIDs []uint           `json:"removed_scene_ids"`
+}
+
+// OfflineSyncService keeps the per-user selection of scenes cached by mobile
+// clients and builds sync manifests for it.
+type OfflineSyncService struct {
+	syncRepo    data.OfflineSyncRepository
+	sceneRepo   data.SceneRepository
+	markerRepo  data.MarkerRepository
+	mediaSigner *MediaSigner
+	rbac        *RBACService
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+func NewOfflineSyncService(syncRepo data.OfflineSyncRepository, sceneRepo data.SceneRepository, markerRepo data.MarkerRepository, mediaSigner *MediaSigner, logger *zap.Logger) *OfflineSyncService {
+	return &OfflineSyncService{
+		syncRepo:    syncRepo,
+		sceneRepo:   sceneRepo,
+		markerRepo:  markerRepo,
+		mediaSigner: mediaSigner,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// SetRBACService sets the RBAC service used to hide restricted scenes.
+func (s *OfflineSyncService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
+}
+
+// AddScenes selects scenes for offline sync. Every scene must exist and be
+// visible to the role.
+func (s *OfflineSyncService) AddScenes(userID uint, role string, sceneIDs []uint) error {
+	ids := uniqueIDs(sceneIDs)
+	if len(ids) == 0 {
+		return apperrors.NewValidationErrorWithField("scene_ids", "at least one scene ID is required")
+	}
+
+	scenes, err := s.sceneRepo.GetByIDs(ids)
+	if err != nil {
+		return apperrors.NewInternalError("failed to get scenes", err)
+	}
+	scenes, err = visibleScenes(s.rbac, role, scenes)
+	if err != nil {
+		return apperrors.NewInternalError("failed to filter scenes", err)
+	}
+	if len(scenes) != len(ids) {
+		found := make(map[uint]bool, len(scenes))
+		for _, scene := range scenes {
+			found[scene.ID] = true
+		}
+		for _, id := range ids {
+			if !found[id] {
+				return apperrors.ErrSceneNotFound(id)
+			}
+		}
+	}
+
+	count, err := s.syncRepo.CountActive(userID)
+	if err != nil {
+		return apperrors.NewInternalError("failed to count offline scenes", err)
+	}
+	// Counting re-added scenes twice keeps this check to one query; it only
+	// matters right at the limit
+	if count+int64(len(ids)) > MaxOfflineSyncScenes {
+		return apperrors.NewValidationErrorWithField("scene_ids", fmt.Sprintf("at most %d scenes can be kept offline", MaxOfflineSyncScenes))
+	}
+
+	if err := s.syncRepo.AddScenes(userID, ids); err != nil {
+		return apperrors.NewInternalError("failed to add offline scenes", err)
+	}
+	return nil
+}
+
+// RemoveScene deselects a scene; the next delta manifest reports it as removed.
+func (s *OfflineSyncService) RemoveScene(userID, sceneID uint) error {
+	removed, err := s.syncRepo.RemoveScenes(userID, []uint{sceneID})
+	if err != nil {
+		return apperrors.NewInternalError("failed to remove offline scene", err)
+	}
+	if removed == 0 {
+		return apperrors.NewNotFoundError("offline scene", sceneID)
+	}
+	return nil
+}
+
+// GetManifest builds the user's sync manifest. A nil since, or one older than
+// the tombstone retention, yields a full manifest. Scenes the role may no
+// longer see are left out, and reported as removed in delta manifests.
+func (s *OfflineSyncService) GetManifest(userID uint, role string, since *time.Time) (*SyncManifest, error) {
+	now := s.now().UTC()
+	cutoff := now.Add(-OfflineSyncTombstoneRetention)
+	if err := s.syncRepo.DeleteTombstonesBefore(userID, cutoff); err != nil {
+		return nil, apperrors.NewInternalError("failed to prune offline scenes", err)
+	}
+
+	rows, err := s.syncRepo.ListByUser(userID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list offline scenes", err)
+	}
+
+	full := since == nil || since.Before(cutoff)
+	manifest := &SyncManifest{
+		GeneratedAt:     now,
+		Full:            full,
+		Scenes:          []SyncSceneEntry{},
+		RemovedSceneIDs: []uint{},
+	}
+	changedSince := func(t time.Time) bool {
+		return full || t.After(*since)
+	}
+
+	var activeIDs []uint
+	for _, row := range rows {
+		if row.RemovedAt == nil {
+			activeIDs = append(activeIDs, row.SceneID)
+		}
+	}
+	scenes, err := s.sceneRepo.GetByIDs(activeIDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get scenes", err)
+	}
+	visible, err := visibleScenes(s.rbac, role, scenes)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to filter scenes", err)
+	}
+	sceneByID := make(map[uint]data.Scene, len(visible))
+	for _, scene := range visible {
+		sceneByID[scene.ID] = scene
+	}
+	// Hidden scenes stay selected so they come back if the restriction is lifted
+	hidden := make(map[uint]bool, len(scenes)-len(visible))
+	for _, scene := range scenes {
+		if _, ok := sceneByID[scene.ID]; !ok {
+			hidden[scene.ID] = true
+		}
+	}
+	markers, err := s.markerRepo.GetByUserAndScenes(userID, activeIDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get markers", err)
+	}
+
+	var gone []uint
+	for _, row := range rows {
+		if row.RemovedAt != nil {
+			if !full && row.RemovedAt.After(*since) {
+				manifest.RemovedSceneIDs = append(manifest.RemovedSceneIDs, row.SceneID)
+			}
+			continue
+		}
+
+		if hidden[row.SceneID] {
+			if !full {
+				manifest.RemovedSceneIDs = append(manifest.RemovedSceneIDs, row.SceneID)
+			}
+			continue
+		}
+		scene, ok := sceneByID[row.SceneID]
+		if !ok {
+			// Trashed or deleted since it was selected
+			gone = append(gone, row.SceneID)
+			if !full {
+				manifest.RemovedSceneIDs = append(manifest.RemovedSceneIDs, row.SceneID)
+			}
+			continue
+		}
+
+		entry, err := s.buildEntry(&scene, markers[scene.ID])
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to build sync entry", err)
+		}
+		// Changes are detected here, by comparing against the checksum sent last
+		// time, so edits anywhere in the app need no sync bookkeeping
+		entry.ChangedAt = row.ChangedAt
+		if entry.Checksum != row.Checksum {
+			if err := s.syncRepo.UpdateChecksum(row.ID, entry.Checksum, now); err != nil {
+				return nil, apperrors.NewInternalError("failed to update offline scene", err)
+			}
+			entry.ChangedAt = now
+		}
+		if changedSince(entry.ChangedAt) {
+			manifest.Scenes = append(manifest.Scenes, *entry)
+		}
+	}
+
+	if len(gone) > 0 {
+		if _, err := s.syncRepo.RemoveScenes(userID, gone); err != nil {
+			s.logger.Warn("Failed to remove missing offline scenes", zap.Uint("user_id", userID), zap.Error(err))
+		}
+	}
+
+	return manifest, nil
+}
+
+func (s *OfflineSyncService) buildEntry(scene *data.Scene, markers []data.UserSceneMarker) (*SyncSceneEntry, error) {
+	meta := SyncSceneMetadata{
+		ID:          scene.ID,
+		Title:       scene.Title,
+		Description: scene.Description,
+		Duration:    scene.Duration,
+		Size:        scene.Size,
+		Width:       scene.Width,
+		Height:      scene.Height,
+		Studio:      scene.Studio,
+		Tags:        []string(scene.Tags),
+		Actors:      []string(scene.Actors),
+		ReleaseDate: scene.ReleaseDate,
+		FileHash:    scene.FileHash,
+		HasVtt:      scene.VttPath != "",
+		Markers:     make([]SyncMarker, 0, len(markers)),
+	}
+	if meta.Tags == nil {
+		meta.Tags = []string{}
+	}
+	if meta.Actors == nil {
+		meta.Actors = []string{}
+	}
+	for _, m := range markers {
+		meta.Markers = append(meta.Markers, SyncMarker{ID: m.ID, Timestamp: m.Timestamp, Label: m.Label, Color: m.Color})
+	}
+
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(encoded)
+
+	entry := &SyncSceneEntry{
+		SyncSceneMetadata: meta,
+		Checksum:          hex.EncodeToString(sum[:]),
+		ThumbnailURL:      s.mediaSigner.SignPath(fmt.Sprintf("/thumbnails/%d?size=lg", scene.ID), scene.ID),
+		DownloadURL:       fmt.Sprintf("/api/v1/scenes/%d/download", scene.ID),
+	}
+	if meta.HasVtt {
+		entry.VttURL = s.mediaSigner.SignPath(fmt.Sprintf("/vtt/%d", scene.ID), scene.ID)
+	}
+	return entry, nil
+}
+
+func uniqueIDs(ids []uint) []uint {
+	result := make([]uint, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestOfflineSyncService(t *testing.T, now time.Time) (*OfflineSyncService, *mocks.MockOfflineSyncRepository, *mocks.MockSceneRepository, *mocks.MockMarkerRepository) {
+	ctrl := gomock.NewController(t)
+	syncRepo := mocks.NewMockOfflineSyncRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	svc := NewOfflineSyncService(syncRepo, sceneRepo, markerRepo, nil, zap.NewNop())
+	svc.now = func() time.Time { return now }
+	return svc, syncRepo, sceneRepo, markerRepo
+}
+
+func TestOfflineSyncAddScenes(t *testing.T) {
+	svc, syncRepo, sceneRepo, _ := newTestOfflineSyncService(t, time.Now())
+
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{{ID: 1}, {ID: 2}}, nil)
+	syncRepo.EXPECT().CountActive(uint(7)).Return(int64(3), nil)
+	syncRepo.EXPECT().AddScenes(uint(7), []uint{1, 2}).Return(nil)
+	if err := svc.AddScenes(7, "user", []uint{1, 2, 1}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 9}).Return([]data.Scene{{ID: 1}}, nil)
+	if err := svc.AddScenes(7, "user", []uint{1, 9}); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found for missing scene, got %v", err)
+	}
+
+	sceneRepo.EXPECT().GetByIDs([]uint{1}).Return([]data.Scene{{ID: 1}}, nil)
+	syncRepo.EXPECT().CountActive(uint(7)).Return(int64(MaxOfflineSyncScenes), nil)
+	if err := svc.AddScenes(7, "user", []uint{1}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error at the limit, got %v", err)
+	}
+
+	if err := svc.AddScenes(7, "user", nil); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for no scenes, got %v", err)
+	}
+}
+
+func TestOfflineSyncRemoveScene(t *testing.T) {
+	svc, syncRepo, _, _ := newTestOfflineSyncService(t, time.Now())
+
+	syncRepo.EXPECT().RemoveScenes(uint(7), []uint{1}).Return(int64(1), nil)
+	if err := svc.RemoveScene(7, 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	syncRepo.EXPECT().RemoveScenes(uint(7), []uint{2}).Return(int64(0), nil)
+	if err := svc.RemoveScene(7, 2); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestOfflineSyncManifest_FullUpdatesChecksums(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, syncRepo, sceneRepo, markerRepo := newTestOfflineSyncService(t, now)
+
+	syncRepo.EXPECT().DeleteTombstonesBefore(uint(7), now.Add(-OfflineSyncTombstoneRetention)).Return(nil)
+	syncRepo.EXPECT().ListByUser(uint(7)).Return([]data.UserOfflineScene{
+		{ID: 10, UserID: 7, SceneID: 1, ChangedAt: now.Add(-time.Hour)},
+	}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1}).Return([]data.Scene{{ID: 1, Title: "A", VttPath: "/vtt/1.vtt"}}, nil)
+	markerRepo.EXPECT().GetByUserAndScenes(uint(7), []uint{1}).Return(map[uint][]data.UserSceneMarker{
+		1: {{ID: 3, SceneID: 1, Timestamp: 30, Label: "intro"}},
+	}, nil)
+	syncRepo.EXPECT().UpdateChecksum(uint(10), gomock.Any(), now).Return(nil)
+
+	manifest, err := svc.GetManifest(7, "user", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !manifest.Full || len(manifest.Scenes) != 1 {
+		t.Fatalf("expected full manifest with one scene, got %+v", manifest)
+	}
+	entry := manifest.Scenes[0]
+	if entry.Checksum == "" || !entry.ChangedAt.Equal(now) {
+		t.Fatalf("expected new checksum changed now, got %+v", entry)
+	}
+	if len(entry.Markers) != 1 || entry.Markers[0].Label != "intro" {
+		t.Fatalf("expected marker in entry, got %+v", entry.Markers)
+	}
+	if entry.ThumbnailURL != "/thumbnails/1?size=lg" || entry.VttURL != "/vtt/1" || entry.DownloadURL != "/api/v1/scenes/1/download" {
+		t.Fatalf("unexpected URLs %+v", entry)
+	}
+}
+
+func TestOfflineSyncManifest_Delta(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour)
+	svc, syncRepo, sceneRepo, markerRepo := newTestOfflineSyncService(t, now)
+
+	unchanged := data.Scene{ID: 1, Title: "A"}
+	entry, err := svc.buildEntry(&unchanged, nil)
+	if err != nil {
+		t.Fatalf("failed to build entry: %v", err)
+	}
+	removedAt := now.Add(-time.Minute)
+	oldRemoval := now.Add(-2 * time.Hour)
+
+	syncRepo.EXPECT().DeleteTombstonesBefore(uint(7), gomock.Any()).Return(nil)
+	syncRepo.EXPECT().ListByUser(uint(7)).Return([]data.UserOfflineScene{
+		{ID: 10, SceneID: 1, Checksum: entry.Checksum, ChangedAt: now.Add(-2 * time.Hour)},
+		{ID: 11, SceneID: 2, Checksum: "stale", ChangedAt: now.Add(-2 * time.Hour)},
+		{ID: 12, SceneID: 3, ChangedAt: removedAt, RemovedAt: &removedAt},
+		{ID: 13, SceneID: 4, ChangedAt: oldRemoval, RemovedAt: &oldRemoval},
+		{ID: 14, SceneID: 5, Checksum: "x", ChangedAt: now.Add(-2 * time.Hour)},
+	}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2, 5}).Return([]data.Scene{unchanged, {ID: 2, Title: "B"}}, nil)
+	markerRepo.EXPECT().GetByUserAndScenes(uint(7), []uint{1, 2, 5}).Return(map[uint][]data.UserSceneMarker{}, nil)
+	syncRepo.EXPECT().UpdateChecksum(uint(11), gomock.Any(), now).Return(nil)
+	syncRepo.EXPECT().RemoveScenes(uint(7), []uint{5}).Return(int64(1), nil)
+
+	manifest, err := svc.GetManifest(7, "user", &since)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if manifest.Full {
+		t.Fatal("expected delta manifest")
+	}
+	if len(manifest.Scenes) != 1 || manifest.Scenes[0].ID != 2 {
+		t.Fatalf("expected only the changed scene, got %+v", manifest.Scenes)
+	}
+	if len(manifest.RemovedSceneIDs) != 2 || manifest.RemovedSceneIDs[0] != 3 || manifest.RemovedSceneIDs[1] != 5 {
+		t.Fatalf("expected scenes 3 and 5 removed, got %v", manifest.RemovedSceneIDs)
+	}
+}
+
+func TestOfflineSyncManifest_OldSinceIsFull(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-OfflineSyncTombstoneRetention - time.Hour)
+	svc, syncRepo, sceneRepo, markerRepo := newTestOfflineSyncService(t, now)
+
+	syncRepo.EXPECT().DeleteTombstonesBefore(uint(7), gomock.Any()).Return(nil)
+	syncRepo.EXPECT().ListByUser(uint(7)).Return(nil, nil)
+	sceneRepo.EXPECT().GetByIDs(gomock.Any()).Return([]data.Scene{}, nil)
+	markerRepo.EXPECT().GetByUserAndScenes(uint(7), gomock.Any()).Return(map[uint][]data.UserSceneMarker{}, nil)
+
+	manifest, err := svc.GetManifest(7, "user", &since)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !manifest.Full {
+		t.Fatal("expected a full manifest when since predates the tombstone retention")
+	}
+}
+
+func TestOfflineSyncManifest_HidesRestrictedScenes(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour)
+	svc, syncRepo, sceneRepo, markerRepo := newTestOfflineSyncService(t, now)
+	rbac, _, restrictionRepo := newTestRestrictedRBACService(t, []data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", TagIDs: []int64{3}},
+	})
+	svc.SetRBACService(rbac)
+
+	syncRepo.EXPECT().DeleteTombstonesBefore(uint(7), gomock.Any()).Return(nil)
+	syncRepo.EXPECT().ListByUser(uint(7)).Return([]data.UserOfflineScene{
+		{ID: 10, SceneID: 1, Checksum: "stale", ChangedAt: now.Add(-2 * time.Hour)},
+		{ID: 11, SceneID: 2, Checksum: "stale", ChangedAt: now.Add(-2 * time.Hour)},
+	}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{{ID: 1}, {ID: 2}}, nil)
+	restrictionRepo.EXPECT().FilterVisibleSceneIDs(rbac.ContentRestriction("guest"), []uint{1, 2}).Return([]uint{1}, nil)
+	markerRepo.EXPECT().GetByUserAndScenes(uint(7), []uint{1, 2}).Return(map[uint][]data.UserSceneMarker{}, nil)
+	syncRepo.EXPECT().UpdateChecksum(uint(10), gomock.Any(), now).Return(nil)
+
+	manifest, err := svc.GetManifest(7, "guest", &since)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(manifest.Scenes) != 1 || manifest.Scenes[0].ID != 1 {
+		t.Fatalf("expected only the visible scene, got %+v", manifest.Scenes)
+	}
+	// The hidden scene is reported removed but stays selected
+	if len(manifest.RemovedSceneIDs) != 1 || manifest.RemovedSceneIDs[0] != 2 {
+		t.Fatalf("expected scene 2 removed, got %v", manifest.RemovedSceneIDs)
+	}
+}
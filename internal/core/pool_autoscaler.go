@@ -0,0 +1,274 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// autoscaleDecisionHistory is how many resizes are kept for the pool config API
+const autoscaleDecisionHistory = 50
+
+// poolResizer is the part of SceneProcessingService the autoscaler drives
+type poolResizer interface {
+	GetPoolLoads() []PoolLoad
+	GetQueueStatus() QueueStatus
+	ResizePool(phase string, workers int) error
+}
+
+// AutoscaleDecision is a pool resize made by the autoscaler.
+type AutoscaleDecision struct {
+	Time       time.Time `json:"time"`
+	Phase      string    `json:"phase"`
+	From       int       `json:"from"`
+	To         int       `json:"to"`
+	Reason     string    `json:"reason"`
+	Backlog    int       `json:"backlog"` // pending jobs plus jobs queued in the pool
+	Active     int       `json:"active"`
+	LoadPerCPU *float64  `json:"load_per_cpu,omitempty"`
+}
+
+// PoolAutoscaleStatus reports the autoscaler's bounds and recent resizes.
+type PoolAutoscaleStatus struct {
+	Enabled     bool                              `json:"enabled"`
+	Interval    string                            `json:"interval,omitempty"`
+	Cooldown    string                            `json:"cooldown,omitempty"`
+	MaxLoad     float64                           `json:"max_load,omitempty"`
+	Bounds      map[string]config.AutoscaleBounds `json:"bounds,omitempty"`
+	LoadPerCPU  *float64                          `json:"load_per_cpu,omitempty"` // at the last evaluation, when known
+	EvaluatedAt *time.Time                        `json:"evaluated_at,omitempty"`
+	Decisions   []AutoscaleDecision               `json:"decisions"` // newest first
+}
+
+// PoolAutoscaler resizes the worker pools within processing.autoscale bounds.
+// A pool grows by one worker when jobs wait for it and all its workers are
+// busy, unless the load average is too high or jobs already wait for an
+// ffmpeg process slot; it shrinks by one when the load average per CPU
+// exceeds max_load or when it has idle workers and no backlog. Each pool is
+// resized at most once per cooldown. Resizes are not persisted: the sizes
+// set through the pool config API are the ones used on startup.
+type PoolAutoscaler struct {
+	cfg         config.AutoscaleConfig
+	pools       poolResizer
+	jobRepo     data.JobHistoryRepository
+	loadAverage func() (float64, error)
+	cpus        int
+	logger      *zap.Logger
+
+	mu          sync.Mutex
+	lastResize  map[string]time.Time
+	decisions   []AutoscaleDecision // oldest first
+	loadPerCPU  *float64
+	evaluatedAt *time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPoolAutoscaler(cfg config.AutoscaleConfig, processingService *SceneProcessingService, jobRepo data.JobHistoryRepository, logger *zap.Logger) *PoolAutoscaler {
+	return newPoolAutoscaler(cfg, processingService, jobRepo, logger)
+}
+
+func newPoolAutoscaler(cfg config.AutoscaleConfig, pools poolResizer, jobRepo data.JobHistoryRepository, logger *zap.Logger) *PoolAutoscaler {
+	return &PoolAutoscaler{
+		cfg:         cfg,
+		pools:       pools,
+		jobRepo:     jobRepo,
+		loadAverage: readLoadAverage,
+		cpus:        runtime.NumCPU(),
+		logger:      logger.With(zap.String("component", "pool_autoscaler")),
+		lastResize:  make(map[string]time.Time),
+	}
+}
+
+// Start launches the evaluation loop when autoscaling is enabled
+func (a *PoolAutoscaler) Start() {
+	if !a.cfg.Enabled {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+
+	if _, err := a.loadAverage(); err != nil {
+		a.logger.Warn("Load average unavailable, autoscaling on backlog only", zap.Error(err))
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(a.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.evaluate()
+			}
+		}
+	}()
+
+	a.logger.Info("Pool autoscaler started",
+		zap.Int("min_workers", a.cfg.MinWorkers),
+		zap.Int("max_workers", a.cfg.MaxWorkers),
+		zap.Duration("interval", a.cfg.Interval),
+		zap.Duration("cooldown", a.cfg.Cooldown),
+		zap.Float64("max_load", a.cfg.MaxLoad),
+	)
+}
+
+// Stop halts the evaluation loop
+func (a *PoolAutoscaler) Stop() {
+	if a.cancel != nil {
+		a.cancel()
+		a.wg.Wait()
+	}
+}
+
+// evaluate resizes the pools that need it
+func (a *PoolAutoscaler) evaluate() {
+	pending, err := a.jobRepo.CountPendingByPhase()
+	if err != nil {
+		a.logger.Warn("Failed to count pending jobs, skipping autoscale", zap.Error(err))
+		return
+	}
+
+	var loadPerCPU *float64
+	if load, err := a.loadAverage(); err == nil {
+		perCPU := load / float64(a.cpus)
+		loadPerCPU = &perCPU
+	}
+	ffmpegWaiting := a.pools.GetQueueStatus().FFmpegWaiting
+
+	now := time.Now()
+	for _, pool := range a.pools.GetPoolLoads() {
+		backlog := pending[pool.Phase] + pool.Queued
+		// Checksum jobs don't take an ffmpeg process slot
+		slotsExhausted := ffmpegWaiting > 0 && pool.Phase != "checksum"
+		target, reason, urgent := a.decide(pool, backlog, loadPerCPU, slotsExhausted)
+		if target == pool.Workers {
+			continue
+		}
+
+		a.mu.Lock()
+		cooling := !urgent && now.Sub(a.lastResize[pool.Phase]) < a.cfg.Cooldown
+		a.mu.Unlock()
+		if cooling {
+			continue
+		}
+
+		if err := a.pools.ResizePool(pool.Phase, target); err != nil {
+			a.logger.Warn("Failed to autoscale worker pool", zap.String("phase", pool.Phase), zap.Error(err))
+			continue
+		}
+		a.record(AutoscaleDecision{
+			Time:       now,
+			Phase:      pool.Phase,
+			From:       pool.Workers,
+			To:         target,
+			Reason:     reason,
+			Backlog:    backlog,
+			Active:     pool.Active,
+			LoadPerCPU: loadPerCPU,
+		})
+
+		fields := []zap.Field{
+			zap.String("phase", pool.Phase),
+			zap.Int("from", pool.Workers),
+			zap.Int("to", target),
+			zap.String("reason", reason),
+			zap.Int("backlog", backlog),
+			zap.Int("active", pool.Active),
+		}
+		if loadPerCPU != nil {
+			fields = append(fields, zap.Float64("load_per_cpu", *loadPerCPU))
+		}
+		a.logger.Info("Autoscaled worker pool", fields...)
+	}
+
+	a.mu.Lock()
+	a.loadPerCPU = loadPerCPU
+	a.evaluatedAt = &now
+	a.mu.Unlock()
+}
+
+// decide returns the size a pool should have and why. Urgent resizes, which
+// bring a pool back within its bounds, skip the cooldown.
+func (a *PoolAutoscaler) decide(pool PoolLoad, backlog int, loadPerCPU *float64, slotsExhausted bool) (int, string, bool) {
+	bounds := a.cfg.Bounds(pool.Phase)
+	workers := pool.Workers
+	overloaded := loadPerCPU != nil && *loadPerCPU > a.cfg.MaxLoad
+
+	switch {
+	case workers < bounds.MinWorkers:
+		return bounds.MinWorkers, "below min_workers", true
+	case workers > bounds.MaxWorkers:
+		return bounds.MaxWorkers, "above max_workers", true
+	case overloaded && workers > bounds.MinWorkers:
+		return workers - 1, fmt.Sprintf("load %.2f per CPU above %.2f", *loadPerCPU, a.cfg.MaxLoad), false
+	case backlog > 0 && pool.Active >= workers && workers < bounds.MaxWorkers && !overloaded && !slotsExhausted:
+		return workers + 1, fmt.Sprintf("%d jobs waiting with all %d workers busy", backlog, workers), false
+	case backlog == 0 && pool.Active < workers && workers > bounds.MinWorkers:
+		return workers - 1, "idle workers and no backlog", false
+	}
+	return workers, "", false
+}
+
+func (a *PoolAutoscaler) record(decision AutoscaleDecision) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastResize[decision.Phase] = decision.Time
+	a.decisions = append(a.decisions, decision)
+	if len(a.decisions) > autoscaleDecisionHistory {
+		a.decisions = a.decisions[len(a.decisions)-autoscaleDecisionHistory:]
+	}
+}
+
+// Status returns the bounds and the recent resizes
+func (a *PoolAutoscaler) Status() PoolAutoscaleStatus {
+	status := PoolAutoscaleStatus{Enabled: a.cfg.Enabled, Decisions: []AutoscaleDecision{}}
+	if !a.cfg.Enabled {
+		return status
+	}
+
+	status.Interval = a.cfg.Interval.String()
+	status.Cooldown = a.cfg.Cooldown.String()
+	status.MaxLoad = a.cfg.MaxLoad
+	status.Bounds = make(map[string]config.AutoscaleBounds, len(config.AutoscalePhases))
+	for _, phase := range config.AutoscalePhases {
+		status.Bounds[phase] = a.cfg.Bounds(phase)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	status.LoadPerCPU = a.loadPerCPU
+	status.EvaluatedAt = a.evaluatedAt
+	for i := len(a.decisions) - 1; i >= 0; i-- {
+		status.Decisions = append(status.Decisions, a.decisions[i])
+	}
+	return status
+}
+
+// readLoadAverage returns the 1-minute load average. Only Linux exposes it.
+func readLoadAverage() (float64, error) {
+	content, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty /proc/loadavg")
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
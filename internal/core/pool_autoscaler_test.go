@@ -0,0 +1,142 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"goonhub/internal/config"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type fakePools struct {
+	loads   []PoolLoad
+	waiting int
+	resized map[string]int
+}
+
+func (f *fakePools) GetPoolLoads() []PoolLoad { return f.loads }
+
+func (f *fakePools) GetQueueStatus() QueueStatus { return QueueStatus{FFmpegWaiting: f.waiting} }
+
+func (f *fakePools) ResizePool(phase string, workers int) error {
+	f.resized[phase] = workers
+	for i := range f.loads {
+		if f.loads[i].Phase == phase {
+			f.loads[i].Workers = workers
+		}
+	}
+	return nil
+}
+
+func newTestAutoscaler(t *testing.T, pools *fakePools, pending map[string]int, load float64) *PoolAutoscaler {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockJobHistoryRepository(ctrl)
+	repo.EXPECT().CountPendingByPhase().Return(pending, nil).AnyTimes()
+
+	cfg := config.AutoscaleConfig{
+		Enabled:    true,
+		MinWorkers: 1,
+		MaxWorkers: 4,
+		Pools:      map[string]config.AutoscaleBounds{"checksum": {MinWorkers: 2, MaxWorkers: 3}},
+		Interval:   time.Minute,
+		Cooldown:   time.Hour,
+		MaxLoad:    0.9,
+	}
+	a := newPoolAutoscaler(cfg, pools, repo, zap.NewNop())
+	a.cpus = 4
+	a.loadAverage = func() (float64, error) { return load, nil }
+	return a
+}
+
+func TestPoolAutoscaler_GrowsBusyPoolsWithBacklog(t *testing.T) {
+	pools := &fakePools{
+		loads: []PoolLoad{
+			{Phase: "metadata", Workers: 1, Active: 1},
+			{Phase: "thumbnail", Workers: 2, Active: 1, Queued: 3}, // a worker is free
+			{Phase: "sprites", Workers: 4, Active: 4},              // at max_workers
+		},
+		resized: map[string]int{},
+	}
+	a := newTestAutoscaler(t, pools, map[string]int{"metadata": 5, "sprites": 10}, 1.0)
+
+	a.evaluate()
+	if len(pools.resized) != 1 || pools.resized["metadata"] != 2 {
+		t.Fatalf("expected only metadata to grow to 2, got %v", pools.resized)
+	}
+
+	status := a.Status()
+	if len(status.Decisions) != 1 {
+		t.Fatalf("expected 1 decision, got %+v", status.Decisions)
+	}
+	d := status.Decisions[0]
+	if d.Phase != "metadata" || d.From != 1 || d.To != 2 || d.Backlog != 5 || d.LoadPerCPU == nil || *d.LoadPerCPU != 0.25 {
+		t.Fatalf("unexpected decision %+v", d)
+	}
+
+	// The pool is cooling down
+	pools.loads[0].Active = 2
+	a.evaluate()
+	if pools.resized["metadata"] != 2 || len(a.Status().Decisions) != 1 {
+		t.Fatalf("expected no resize during the cooldown, got %v", pools.resized)
+	}
+}
+
+func TestPoolAutoscaler_ShrinksUnderLoad(t *testing.T) {
+	pools := &fakePools{
+		loads: []PoolLoad{
+			{Phase: "sprites", Workers: 3, Active: 3},
+			{Phase: "metadata", Workers: 1, Active: 1},
+		},
+		resized: map[string]int{},
+	}
+	// 4.4 over 4 CPUs is above max_load, so the backlog doesn't grow pools
+	a := newTestAutoscaler(t, pools, map[string]int{"sprites": 20, "metadata": 20}, 4.4)
+
+	a.evaluate()
+	if len(pools.resized) != 1 || pools.resized["sprites"] != 2 {
+		t.Fatalf("expected only sprites to shrink to 2, got %v", pools.resized)
+	}
+}
+
+func TestPoolAutoscaler_IdleAndBounds(t *testing.T) {
+	pools := &fakePools{
+		loads: []PoolLoad{
+			{Phase: "thumbnail", Workers: 3, Active: 1},
+			{Phase: "verify", Workers: 8, Active: 8},
+			{Phase: "checksum", Workers: 1},
+			{Phase: "metadata", Workers: 2, Active: 2}, // backlog, but jobs already wait for ffmpeg slots
+		},
+		waiting: 2,
+		resized: map[string]int{},
+	}
+	a := newTestAutoscaler(t, pools, map[string]int{"metadata": 4, "verify": 4}, 0.5)
+	// Bound corrections skip the cooldown
+	now := time.Now()
+	a.lastResize["verify"] = now
+	a.lastResize["checksum"] = now
+
+	a.evaluate()
+	want := map[string]int{"thumbnail": 2, "verify": 4, "checksum": 2}
+	if len(pools.resized) != len(want) {
+		t.Fatalf("expected %v, got %v", want, pools.resized)
+	}
+	for phase, workers := range want {
+		if pools.resized[phase] != workers {
+			t.Fatalf("expected %v, got %v", want, pools.resized)
+		}
+	}
+}
+
+func TestPoolAutoscaler_Disabled(t *testing.T) {
+	a := newPoolAutoscaler(config.AutoscaleConfig{}, &fakePools{}, nil, zap.NewNop())
+	a.Start()
+	a.Stop()
+
+	status := a.Status()
+	if status.Enabled || status.Bounds != nil || len(status.Decisions) != 0 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// PornDBCacheStats describes the PornDB response cache. Hits and misses count
+// lookups since the server started.
+type PornDBCacheStats struct {
+	Enabled    bool                        `json:"enabled"`
+	TTLSeconds int64                       `json:"ttl_seconds"`
+	Hits       int64                       `json:"hits"`
+	Misses     int64                       `json:"misses"`
+	Kinds      []data.PornDBCacheKindStats `json:"kinds"`
+}
+
+// SearchPerformers searches for performers by name, querying both /performers and /performer-sites endpoints
+func (s *PornDBService) SearchPerformers(query string) ([]PornDBPerformer, error) {
+	return cachedPornDB(s, data.PornDBCachePerformer, "performers/search", query, func() ([]PornDBPerformer, error) {
+		return s.searchPerformers(query)
+	})
+}
+
+// GetPerformerDetails fetches detailed information about a performer
+func (s *PornDBService) GetPerformerDetails(id string) (*PornDBPerformerDetails, error) {
+	return cachedPornDB(s, data.PornDBCachePerformer, "performers", id, func() (*PornDBPerformerDetails, error) {
+		return s.getPerformerDetails(id)
+	})
+}
+
+// RefreshPerformerDetails fetches a performer from PornDB even when it is
+// cached, and caches the result
+func (s *PornDBService) RefreshPerformerDetails(id string) (*PornDBPerformerDetails, error) {
+	return refreshPornDB(s, data.PornDBCachePerformer, "performers", id, func() (*PornDBPerformerDetails, error) {
+		return s.getPerformerDetails(id)
+	})
+}
+
+// GetPerformerSiteDetails fetches detailed information about a performer from the performer-sites endpoint
+func (s *PornDBService) GetPerformerSiteDetails(id string) (*PornDBPerformerDetails, error) {
+	return cachedPornDB(s, data.PornDBCachePerformer, "performer-sites", id, func() (*PornDBPerformerDetails, error) {
+		return s.getPerformerSiteDetails(id)
+	})
+}
+
+// SearchScenes searches for scenes with optional filters
+func (s *PornDBService) SearchScenes(opts SceneSearchOptions) ([]PornDBScene, error) {
+	return cachedPornDB(s, data.PornDBCacheScene, "scenes/search", opts.cacheArg(), func() ([]PornDBScene, error) {
+		return s.searchScenes(opts)
+	})
+}
+
+// GetSceneDetails fetches detailed information about a scene
+func (s *PornDBService) GetSceneDetails(id string) (*PornDBScene, error) {
+	return cachedPornDB(s, data.PornDBCacheScene, "scenes", id, func() (*PornDBScene, error) {
+		return s.getSceneDetails(id)
+	})
+}
+
+// SearchSites searches for sites/studios by name
+func (s *PornDBService) SearchSites(query string) ([]PornDBSiteDetails, error) {
+	return cachedPornDB(s, data.PornDBCacheSite, "sites/search", query, func() ([]PornDBSiteDetails, error) {
+		return s.searchSites(query)
+	})
+}
+
+// GetSiteDetails fetches detailed information about a site
+func (s *PornDBService) GetSiteDetails(id string) (*PornDBSiteDetails, error) {
+	return cachedPornDB(s, data.PornDBCacheSite, "sites", id, func() (*PornDBSiteDetails, error) {
+		return s.getSiteDetails(id)
+	})
+}
+
+// CacheStats returns the cache's hit counters and what it holds.
+func (s *PornDBService) CacheStats() (*PornDBCacheStats, error) {
+	stats := &PornDBCacheStats{
+		Enabled:    s.cacheEnabled(),
+		TTLSeconds: int64(s.cacheTTL.Seconds()),
+		Hits:       s.cacheHits.Load(),
+		Misses:     s.cacheMisses.Load(),
+		Kinds:      []data.PornDBCacheKindStats{},
+	}
+	if s.cache == nil {
+		return stats, nil
+	}
+	kinds, err := s.cache.Stats(s.now())
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get PornDB cache stats", err)
+	}
+	if kinds != nil {
+		stats.Kinds = kinds
+	}
+	return stats, nil
+}
+
+// PurgeCache deletes cached lookups of kind, or of every kind when kind is
+// empty, and returns how many were deleted. With expiredOnly, entries that
+// are still fresh are kept.
+func (s *PornDBService) PurgeCache(kind string, expiredOnly bool) (int64, error) {
+	switch kind {
+	case "", data.PornDBCachePerformer, data.PornDBCacheScene, data.PornDBCacheSite:
+	default:
+		return 0, apperrors.NewValidationErrorWithField("kind", "kind must be one of performer, scene, site")
+	}
+	if s.cache == nil {
+		return 0, nil
+	}
+	deleted, err := s.cache.Purge(kind, expiredOnly, s.now())
+	if err != nil {
+		return 0, apperrors.NewInternalError("failed to purge PornDB cache", err)
+	}
+	s.logger.Info("Purged PornDB cache",
+		zap.String("kind", kind),
+		zap.Bool("expired_only", expiredOnly),
+		zap.Int64("deleted", deleted),
+	)
+	return deleted, nil
+}
+
+func (s *PornDBService) cacheEnabled() bool {
+	return s.cache != nil && s.cacheTTL > 0
+}
+
+// pornDBCacheKey builds the cache key of a lookup. Queries are compared
+// case-insensitively, IDs as they are. Arguments too long for the key column
+// are hashed.
+func pornDBCacheKey(endpoint, arg string) string {
+	if strings.HasSuffix(endpoint, "/search") {
+		arg = strings.Join(strings.Fields(strings.ToLower(arg)), " ")
+	}
+	key := endpoint + ":" + arg
+	if len(key) > 512 {
+		sum := sha256.Sum256([]byte(arg))
+		key = endpoint + ":sha256:" + hex.EncodeToString(sum[:])
+	}
+	return key
+}
+
+// cachedPornDB returns the cached result of a lookup, or calls fetch and
+// caches what it returns. Errors are never cached, and a cache that can't be
+// read or written only costs an API request.
+func cachedPornDB[T any](s *PornDBService, kind, endpoint, arg string, fetch func() (T, error)) (T, error) {
+	if !s.cacheEnabled() || !s.IsConfigured() {
+		return fetch()
+	}
+
+	key := pornDBCacheKey(endpoint, arg)
+	entry, err := s.cache.Get(key, s.now())
+	if err != nil {
+		s.logger.Warn("Failed to read PornDB cache", zap.String("key", key), zap.Error(err))
+	} else if entry != nil {
+		var cached T
+		if err := json.Unmarshal(entry.Body, &cached); err == nil {
+			s.cacheHits.Add(1)
+			if err := s.cache.RecordHit(key); err != nil {
+				s.logger.Debug("Failed to record PornDB cache hit", zap.String("key", key), zap.Error(err))
+			}
+			return cached, nil
+		}
+	}
+
+	s.cacheMisses.Add(1)
+	return fetchPornDB(s, kind, key, fetch)
+}
+
+// refreshPornDB calls fetch even when the lookup is cached, replacing the
+// cached result with what it returns.
+func refreshPornDB[T any](s *PornDBService, kind, endpoint, arg string, fetch func() (T, error)) (T, error) {
+	if !s.cacheEnabled() || !s.IsConfigured() {
+		return fetch()
+	}
+	return fetchPornDB(s, kind, pornDBCacheKey(endpoint, arg), fetch)
+}
+
+// fetchPornDB calls fetch and caches its result under key.
+func fetchPornDB[T any](s *PornDBService, kind, key string, fetch func() (T, error)) (T, error) {
+	result, err := fetch()
+	if err != nil {
+		return result, err
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		s.logger.Warn("Failed to encode PornDB response for the cache", zap.String("key", key), zap.Error(err))
+		return result, nil
+	}
+	if err := s.cache.Set(&data.PornDBCacheEntry{
+		Key:       key,
+		Kind:      kind,
+		Body:      body,
+		ExpiresAt: s.now().Add(s.cacheTTL),
+	}); err != nil {
+		s.logger.Warn("Failed to write PornDB cache", zap.String("key", key), zap.Error(err))
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestCachedPornDBService(t *testing.T) (*PornDBService, *mocks.MockPornDBCacheRepository, time.Time) {
+	ctrl := gomock.NewController(t)
+	cache := mocks.NewMockPornDBCacheRepository(ctrl)
+	svc := NewPornDBService(config.PornDBConfig{APIKey: "key", CacheTTL: time.Hour}, cache, zap.NewNop())
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	return svc, cache, now
+}
+
+func TestCachedPornDB_MissThenHit(t *testing.T) {
+	svc, cache, now := newTestCachedPornDBService(t)
+	fetches := 0
+	fetch := func() ([]PornDBScene, error) {
+		fetches++
+		return []PornDBScene{{ID: "pd-1", Title: "Pool Party"}}, nil
+	}
+
+	var stored *data.PornDBCacheEntry
+	cache.EXPECT().Get("scenes/search:jane doe pool party", now).Return(nil, nil)
+	cache.EXPECT().Set(gomock.Any()).DoAndReturn(func(entry *data.PornDBCacheEntry) error {
+		stored = entry
+		return nil
+	})
+	scenes, err := cachedPornDB(svc, data.PornDBCacheScene, "scenes/search", "  Jane  Doe Pool Party", fetch)
+	if err != nil || len(scenes) != 1 || fetches != 1 {
+		t.Fatalf("expected a fetched result, got %+v, %v after %d fetches", scenes, err, fetches)
+	}
+	if stored.Kind != data.PornDBCacheScene || !stored.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected a scene entry expiring after the TTL, got %+v", stored)
+	}
+
+	cache.EXPECT().Get("scenes/search:jane doe pool party", now).Return(stored, nil)
+	cache.EXPECT().RecordHit("scenes/search:jane doe pool party").Return(nil)
+	scenes, err = cachedPornDB(svc, data.PornDBCacheScene, "scenes/search", "jane doe pool party", fetch)
+	if err != nil || len(scenes) != 1 || scenes[0].ID != "pd-1" || fetches != 1 {
+		t.Fatalf("expected the cached result without a fetch, got %+v, %v after %d fetches", scenes, err, fetches)
+	}
+
+	cache.EXPECT().Stats(now).Return(nil, nil)
+	stats, err := svc.CacheStats()
+	if err != nil || stats.Hits != 1 || stats.Misses != 1 || !stats.Enabled || stats.TTLSeconds != 3600 {
+		t.Fatalf("unexpected stats %+v, %v", stats, err)
+	}
+}
+
+func TestCachedPornDB_ErrorsAreNotCached(t *testing.T) {
+	svc, cache, now := newTestCachedPornDBService(t)
+
+	cache.EXPECT().Get("scenes:abc", now).Return(nil, nil)
+	_, err := cachedPornDB(svc, data.PornDBCacheScene, "scenes", "abc", func() (*PornDBScene, error) {
+		return nil, errors.New("PornDB API returned status 500")
+	})
+	if err == nil {
+		t.Fatal("expected the fetch error")
+	}
+}
+
+func TestCachedPornDB_BrokenCacheFallsBackToFetch(t *testing.T) {
+	svc, cache, now := newTestCachedPornDBService(t)
+
+	cache.EXPECT().Get("sites:7", now).Return(nil, errors.New("connection refused"))
+	cache.EXPECT().Set(gomock.Any()).Return(errors.New("connection refused"))
+	site, err := cachedPornDB(svc, data.PornDBCacheSite, "sites", "7", func() (*PornDBSiteDetails, error) {
+		return &PornDBSiteDetails{ID: "7", Name: "Brazzers"}, nil
+	})
+	if err != nil || site.Name != "Brazzers" {
+		t.Fatalf("expected the fetched site, got %+v, %v", site, err)
+	}
+
+	// A cached body that no longer decodes is refetched
+	cache.EXPECT().Get("sites:7", now).Return(&data.PornDBCacheEntry{Body: json.RawMessage(`"not a site"`)}, nil)
+	cache.EXPECT().Set(gomock.Any()).Return(nil)
+	if site, err := cachedPornDB(svc, data.PornDBCacheSite, "sites", "7", func() (*PornDBSiteDetails, error) {
+		return &PornDBSiteDetails{ID: "7"}, nil
+	}); err != nil || site.ID != "7" {
+		t.Fatalf("expected a refetch, got %+v, %v", site, err)
+	}
+}
+
+func TestPornDBCacheKey(t *testing.T) {
+	if got := pornDBCacheKey("performers", "AbC"); got != "performers:AbC" {
+		t.Fatalf("expected IDs to keep their case, got %q", got)
+	}
+	long := pornDBCacheKey("scenes/search", strings.Repeat("x", 600))
+	if len(long) > 512 || !strings.HasPrefix(long, "scenes/search:sha256:") {
+		t.Fatalf("expected a long query to be hashed, got %q", long)
+	}
+}
+
+func TestPornDBService_PurgeCache(t *testing.T) {
+	svc, cache, now := newTestCachedPornDBService(t)
+
+	if _, err := svc.PurgeCache("tag", false); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an unknown kind to be rejected, got %v", err)
+	}
+	cache.EXPECT().Purge(data.PornDBCachePerformer, true, now).Return(int64(4), nil)
+	if deleted, err := svc.PurgeCache(data.PornDBCachePerformer, true); err != nil || deleted != 4 {
+		t.Fatalf("expected 4 deleted entries, got %d, %v", deleted, err)
+	}
+}
@@ -0,0 +1,666 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PornDBMatchPhase is the job_history phase of PornDB auto-match runs. They
+// run outside the scene processing pools, so they can't be retried from the
+// jobs page.
+const PornDBMatchPhase = "porndb_match"
+
+const (
+	// DefaultPornDBMatchThreshold is the score a candidate needs to be applied
+	// without review
+	DefaultPornDBMatchThreshold = 0.8
+	// pornDBMatchMargin is how far the best candidate has to beat the runner-up
+	// to be applied without review
+	pornDBMatchMargin = 0.1
+	// pornDBMatchCandidates is how many candidates a review keeps
+	pornDBMatchCandidates = 5
+	pornDBMatchBatchSize  = 100
+)
+
+var (
+	// filenameDate matches a release date in a filename, as YYYY-MM-DD or the
+	// scene-release YY.MM.DD form
+	filenameDate = regexp.MustCompile(`(?:^|\D)((?:19|20)?\d{2})[.\-_ ](\d{2})[.\-_ ](\d{2})(?:\D|$)`)
+	// filenameNoise matches release tags that only confuse a title search
+	filenameNoise = regexp.MustCompile(`(?i)\b(?:\d{3,4}p|4k|uhd|hd|sd|xxx|mp4|web-?dl|hevc|x26[45]|h26[45]|av1|aac|\d+fps)\b`)
+	// matchTokenSplit splits text into the words scores are compared on
+	matchTokenSplit = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// Fields of a provider scene that can be applied to a scene
+const (
+	PornDBFieldTitle       = "title"
+	PornDBFieldDate        = "date"
+	PornDBFieldDescription = "description"
+	PornDBFieldStudio      = "studio"
+	PornDBFieldPerformers  = "performers"
+	PornDBFieldTags        = "tags"
+	PornDBFieldMarkers     = "markers"
+	PornDBFieldCover       = "cover"
+)
+
+var (
+	pornDBApplyFields = []string{
+		PornDBFieldTitle, PornDBFieldDate, PornDBFieldDescription, PornDBFieldStudio,
+		PornDBFieldPerformers, PornDBFieldTags, PornDBFieldMarkers, PornDBFieldCover,
+	}
+	// pornDBDetailFields are applied when no fields are given
+	pornDBDetailFields = []string{PornDBFieldTitle, PornDBFieldDate, PornDBFieldDescription, PornDBFieldStudio}
+)
+
+// sceneMetadataUpdater applies a match to a scene.
+type sceneMetadataUpdater interface {
+	ApplyMetadata(sceneID uint, input SceneMetadataInput) (*SceneMetadataResult, error)
+}
+
+// PornDBAutoMatchJob is a started auto-match run.
+type PornDBAutoMatchJob struct {
+	JobID     string  `json:"job_id"`
+	Provider  string  `json:"provider"`
+	Total     int64   `json:"total"` // scenes the run will look at
+	Threshold float64 `json:"threshold"`
+}
+
+// pornDBMatchSummary counts what an auto-match run did.
+type pornDBMatchSummary struct {
+	Applied int
+	Pending int
+	NoMatch int
+	Failed  int
+}
+
+// PornDBMatchService matches scenes without a PornDB or StashDB scene ID
+// against one of the metadata providers, PornDB by default, in the background. Each scene is searched by its cleaned-up filename, or its
+// title when that finds nothing, and every candidate is scored on how well its
+// title, site and performers match the search and how close its duration is.
+// A confident, unambiguous best candidate is applied to the scene; otherwise
+// the best candidates are queued for an admin to review.
+type PornDBMatchService struct {
+	matchRepo  data.PornDBMatchRepository
+	sceneRepo  data.SceneRepository
+	providers  *MetadataProviders
+	scenes     sceneMetadataUpdater
+	jobHistory *JobHistoryService
+	logger     *zap.Logger
+
+	mu      sync.Mutex
+	running bool
+}
+
+func NewPornDBMatchService(
+	matchRepo data.PornDBMatchRepository,
+	sceneRepo data.SceneRepository,
+	providers *MetadataProviders,
+	sceneService *SceneService,
+	jobHistory *JobHistoryService,
+	logger *zap.Logger,
+) *PornDBMatchService {
+	s := &PornDBMatchService{
+		matchRepo:  matchRepo,
+		sceneRepo:  sceneRepo,
+		providers:  providers,
+		jobHistory: jobHistory,
+		logger:     logger.With(zap.String("component", "porndb_match")),
+	}
+	// Keep a nil service from becoming a non-nil interface
+	if sceneService != nil {
+		s.scenes = sceneService
+	}
+	return s
+}
+
+// Start runs an auto-match over every unmatched scene in the background
+// against the named provider, applying candidates scoring at least threshold.
+// A threshold of 0 uses DefaultPornDBMatchThreshold.
+func (s *PornDBMatchService) Start(providerName string, threshold float64) (*PornDBAutoMatchJob, error) {
+	provider, err := s.configuredProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	if threshold == 0 {
+		threshold = DefaultPornDBMatchThreshold
+	}
+	if threshold < 0 || threshold > 1 {
+		return nil, apperrors.NewValidationErrorWithField("threshold", "threshold must be between 0 and 1")
+	}
+
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, apperrors.NewConflictError("porndb auto-match", "a PornDB auto-match is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	total, err := s.matchRepo.CountUnmatchedScenes()
+	if err != nil {
+		s.finish()
+		return nil, apperrors.NewInternalError("failed to count unmatched scenes", err)
+	}
+
+	job := &PornDBAutoMatchJob{JobID: uuid.New().String(), Provider: provider.Name(), Total: total, Threshold: threshold}
+	s.jobHistory.RecordJobStart(job.JobID, 0, fmt.Sprintf("%s auto-match of %d scenes", provider.Label(), total), PornDBMatchPhase)
+
+	go func() {
+		defer s.finish()
+		summary, err := s.run(job, provider)
+		if err != nil {
+			s.logger.Error("PornDB auto-match failed", zap.String("job_id", job.JobID), zap.Error(err))
+			s.jobHistory.RecordJobFailed(job.JobID, err)
+			return
+		}
+		s.logger.Info("PornDB auto-match completed",
+			zap.String("job_id", job.JobID),
+			zap.String("provider", job.Provider),
+			zap.Int("applied", summary.Applied),
+			zap.Int("pending", summary.Pending),
+			zap.Int("no_match", summary.NoMatch),
+			zap.Int("failed", summary.Failed),
+		)
+		s.jobHistory.RecordJobComplete(job.JobID)
+	}()
+
+	return job, nil
+}
+
+// IsRunning reports whether an auto-match is running in this process.
+func (s *PornDBMatchService) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+func (s *PornDBMatchService) finish() {
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+}
+
+// run matches the unmatched scenes in ID order. A scene that fails to search
+// is logged and left for the next run.
+func (s *PornDBMatchService) run(job *PornDBAutoMatchJob, provider MetadataProvider) (pornDBMatchSummary, error) {
+	var summary pornDBMatchSummary
+	var afterID uint
+	done := 0
+	for {
+		scenes, err := s.matchRepo.ListUnmatchedScenes(afterID, pornDBMatchBatchSize)
+		if err != nil {
+			return summary, fmt.Errorf("failed to list unmatched scenes: %w", err)
+		}
+		if len(scenes) == 0 {
+			return summary, nil
+		}
+
+		for i := range scenes {
+			scene := &scenes[i]
+			afterID = scene.ID
+
+			status, err := s.matchScene(job, provider, scene)
+			switch {
+			case err != nil:
+				summary.Failed++
+				s.logger.Warn("Failed to auto-match scene",
+					zap.Uint("scene_id", scene.ID),
+					zap.Error(err),
+				)
+			case status == data.PornDBMatchApplied:
+				summary.Applied++
+			case status == data.PornDBMatchPending:
+				summary.Pending++
+			default:
+				summary.NoMatch++
+			}
+
+			done++
+			if job.Total > 0 {
+				s.jobHistory.UpdateProgress(job.JobID, min(100, done*100/int(job.Total)))
+			}
+		}
+	}
+}
+
+// matchScene searches the provider for a scene, then applies the best
+// candidate or records the candidates for review. It returns the review
+// status.
+func (s *PornDBMatchService) matchScene(job *PornDBAutoMatchJob, provider MetadataProvider, scene *data.Scene) (string, error) {
+	query := pornDBFilenameQuery(scene.OriginalFilename)
+	results, err := searchProvider(provider, query)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 && scene.Title != "" && !strings.EqualFold(scene.Title, query) {
+		query = scene.Title
+		if results, err = searchProvider(provider, query); err != nil {
+			return "", err
+		}
+	}
+
+	review := &data.PornDBMatchReview{
+		SceneID:    scene.ID,
+		JobID:      job.JobID,
+		Provider:   provider.Name(),
+		Query:      query,
+		Candidates: scorePornDBCandidates(scene, query, results),
+	}
+	if len(review.Candidates) > pornDBMatchCandidates {
+		review.Candidates = review.Candidates[:pornDBMatchCandidates]
+	}
+
+	switch {
+	case len(review.Candidates) == 0:
+		review.Status = data.PornDBMatchNoMatch
+	case isConfidentMatch(review.Candidates, job.Threshold):
+		best := review.Candidates[0]
+		review.BestScore = best.Score
+		if err := s.apply(provider, scene, results, best.PornDBSceneID); err != nil {
+			return "", err
+		}
+		review.Status = data.PornDBMatchApplied
+		review.PornDBSceneID = best.PornDBSceneID
+	default:
+		review.Status = data.PornDBMatchPending
+		review.BestScore = review.Candidates[0].Score
+	}
+
+	if err := s.matchRepo.Upsert(review); err != nil {
+		return "", fmt.Errorf("failed to save match review: %w", err)
+	}
+	return review.Status, nil
+}
+
+func searchProvider(provider MetadataProvider, query string) ([]PornDBScene, error) {
+	if query == "" {
+		return nil, nil
+	}
+	return provider.SearchScenes(SceneSearchOptions{Title: query})
+}
+
+// configuredProvider returns the named provider, failing when it isn't
+// configured.
+func (s *PornDBMatchService) configuredProvider(name string) (MetadataProvider, error) {
+	if s.providers == nil {
+		return nil, apperrors.NewValidationError("PornDB integration is not configured")
+	}
+	provider, err := s.providers.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if !provider.IsConfigured() {
+		return nil, apperrors.NewValidationError(provider.Label() + " integration is not configured")
+	}
+	return provider, nil
+}
+
+// apply writes the provider scene with the given ID from results to the scene.
+func (s *PornDBMatchService) apply(provider MetadataProvider, scene *data.Scene, results []PornDBScene, matchID string) error {
+	for i := range results {
+		if results[i].ID == matchID {
+			selected, _ := selectFields("fields", nil, pornDBApplyFields, pornDBDetailFields)
+			_, err := s.applyMatch(provider, scene, &results[i], selected, 0)
+			return err
+		}
+	}
+	return fmt.Errorf("%s scene %s is not in the search results", provider.Label(), matchID)
+}
+
+// applyMatch writes the selected fields of a provider scene to a scene in one
+// transaction, keeping the scene's own title, description and studio where
+// the provider has none. The match's ID is recorded as the scene's PornDB or
+// StashDB scene ID. Markers are created for userID, and skipped without one.
+func (s *PornDBMatchService) applyMatch(provider MetadataProvider, scene *data.Scene, match *PornDBScene, selected map[string]bool, userID uint) (*SceneMetadataResult, error) {
+	var input SceneMetadataInput
+	if selected[PornDBFieldTitle] {
+		title := match.Title
+		if title == "" {
+			title = scene.Title
+		}
+		input.Title = &title
+	}
+	if selected[PornDBFieldDescription] {
+		description := match.Description
+		if description == "" {
+			description = scene.Description
+		}
+		input.Description = &description
+	}
+	if selected[PornDBFieldStudio] {
+		studio := scene.Studio
+		if match.Site != nil && match.Site.Name != "" {
+			studio = match.Site.Name
+		}
+		input.Studio = &studio
+	}
+	if selected[PornDBFieldDate] {
+		if date, err := time.Parse("2006-01-02", match.Date); err == nil {
+			input.ReleaseDate = &date
+		}
+	}
+	if selected[PornDBFieldPerformers] {
+		for _, performer := range match.Performers {
+			if name := strings.TrimSpace(performer.Name); name != "" {
+				input.Performers = append(input.Performers, name)
+			}
+		}
+	}
+	if selected[PornDBFieldTags] {
+		for _, tag := range match.Tags {
+			if name := strings.TrimSpace(tag.Name); name != "" && len(name) <= 100 {
+				input.Tags = append(input.Tags, name)
+			}
+		}
+	}
+	if selected[PornDBFieldMarkers] && userID != 0 {
+		for _, marker := range match.Markers {
+			label := strings.TrimSpace(marker.Title)
+			if len(label) > 100 {
+				label = label[:100]
+			}
+			input.Markers = append(input.Markers, data.UserSceneMarker{
+				UserID:       userID,
+				Timestamp:    marker.StartTime,
+				EndTimestamp: marker.EndTime,
+				Label:        label,
+			})
+		}
+	}
+	if selected[PornDBFieldCover] {
+		input.CoverURL = match.Image
+		if input.CoverURL == "" {
+			input.CoverURL = match.Poster
+		}
+	}
+
+	id := match.ID
+	if provider.Name() == MetadataProviderStashDB {
+		input.StashDBSceneID = &id
+	} else {
+		input.PornDBSceneID = &id
+	}
+	return s.scenes.ApplyMetadata(scene.ID, input)
+}
+
+// ApplyMatch applies the selected fields of a provider scene to a scene, the
+// title, date, description and studio when none are given. The scene can be
+// any; it needs no match review.
+func (s *PornDBMatchService) ApplyMatch(sceneID uint, providerName, matchID string, fields []string, userID uint) (*SceneMetadataResult, error) {
+	matchID = strings.TrimSpace(matchID)
+	if matchID == "" {
+		return nil, apperrors.NewValidationErrorWithField("porndb_scene_id", "porndb_scene_id is required")
+	}
+	selected, err := selectFields("fields", fields, pornDBApplyFields, pornDBDetailFields)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := s.configuredProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	scene, err := s.getScene(sceneID)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := provider.GetSceneDetails(matchID)
+	if err != nil {
+		return nil, apperrors.NewValidationErrorWithField("porndb_scene_id", "failed to fetch "+provider.Label()+" scene: "+err.Error())
+	}
+	result, err := s.applyMatch(provider, scene, match, selected, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A pending review of the scene is settled by applying any match to it
+	if review, err := s.matchRepo.GetBySceneID(sceneID); err == nil && review.Status != data.PornDBMatchApplied {
+		now := time.Now()
+		review.Status = data.PornDBMatchApplied
+		review.Provider = provider.Name()
+		review.PornDBSceneID = match.ID
+		review.ReviewedBy = &userID
+		review.ReviewedAt = &now
+		if err := s.matchRepo.Update(review); err != nil {
+			s.logger.Warn("Failed to settle match review", zap.Uint("scene_id", sceneID), zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+func (s *PornDBMatchService) getScene(sceneID uint) (*data.Scene, error) {
+	scene, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrSceneNotFound(sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+	return scene, nil
+}
+
+// ListReviews returns match reviews with the given status, or all of them,
+// most confident first.
+func (s *PornDBMatchService) ListReviews(status string, page, limit int) ([]data.PornDBMatchReview, int64, error) {
+	switch status {
+	case "", data.PornDBMatchPending, data.PornDBMatchApplied, data.PornDBMatchDismissed, data.PornDBMatchNoMatch:
+	default:
+		return nil, 0, apperrors.NewValidationErrorWithField("status", "status must be one of pending, applied, dismissed, no_match")
+	}
+	reviews, total, err := s.matchRepo.List(status, page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list match reviews", err)
+	}
+	return reviews, total, nil
+}
+
+// AcceptReview applies a provider scene to a reviewed scene. The scene is
+// usually one of the review's candidates, but can be any the admin found, at
+// the review's provider unless providerName picks another. Fields selects what
+// is applied as for ApplyMatch.
+func (s *PornDBMatchService) AcceptReview(sceneID uint, providerName, porndbSceneID string, fields []string, userID uint) (*data.PornDBMatchReview, error) {
+	porndbSceneID = strings.TrimSpace(porndbSceneID)
+	if porndbSceneID == "" {
+		return nil, apperrors.NewValidationErrorWithField("porndb_scene_id", "porndb_scene_id is required")
+	}
+	selected, err := selectFields("fields", fields, pornDBApplyFields, pornDBDetailFields)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.getReview(sceneID)
+	if err != nil {
+		return nil, err
+	}
+	if providerName == "" {
+		providerName = review.Provider
+	}
+	provider, err := s.configuredProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status == data.PornDBMatchApplied {
+		return nil, apperrors.NewValidationError("the match was already applied")
+	}
+	scene, err := s.getScene(sceneID)
+	if err != nil {
+		return nil, err
+	}
+
+	match, err := provider.GetSceneDetails(porndbSceneID)
+	if err != nil {
+		return nil, apperrors.NewValidationErrorWithField("porndb_scene_id", "failed to fetch "+provider.Label()+" scene: "+err.Error())
+	}
+	if _, err := s.applyMatch(provider, scene, match, selected, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review.Status = data.PornDBMatchApplied
+	review.Provider = provider.Name()
+	review.PornDBSceneID = match.ID
+	review.ReviewedBy = &userID
+	review.ReviewedAt = &now
+	if err := s.matchRepo.Update(review); err != nil {
+		return nil, apperrors.NewInternalError("failed to update match review", err)
+	}
+	return review, nil
+}
+
+// DismissReview rejects every candidate of a review, so later runs skip the scene.
+func (s *PornDBMatchService) DismissReview(sceneID uint, userID uint) (*data.PornDBMatchReview, error) {
+	review, err := s.getReview(sceneID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status == data.PornDBMatchApplied {
+		return nil, apperrors.NewValidationError("the match was already applied")
+	}
+
+	now := time.Now()
+	review.Status = data.PornDBMatchDismissed
+	review.ReviewedBy = &userID
+	review.ReviewedAt = &now
+	if err := s.matchRepo.Update(review); err != nil {
+		return nil, apperrors.NewInternalError("failed to update match review", err)
+	}
+	return review, nil
+}
+
+func (s *PornDBMatchService) getReview(sceneID uint) (*data.PornDBMatchReview, error) {
+	review, err := s.matchRepo.GetBySceneID(sceneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("match review", sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to get match review", err)
+	}
+	return review, nil
+}
+
+// isConfidentMatch reports whether the best of candidates, sorted best first,
+// reaches threshold and clearly beats the runner-up.
+func isConfidentMatch(candidates data.PornDBMatchCandidates, threshold float64) bool {
+	if len(candidates) == 0 || candidates[0].Score < threshold {
+		return false
+	}
+	return len(candidates) == 1 || candidates[0].Score-candidates[1].Score >= pornDBMatchMargin
+}
+
+// scorePornDBCandidates scores search results against a scene, best first.
+// Text similarity weighs 0.7 and duration closeness 0.3, and a release date
+// matching one in the filename adds 0.1, capped at 1.
+func scorePornDBCandidates(scene *data.Scene, query string, results []PornDBScene) data.PornDBMatchCandidates {
+	queryTokens := matchTokens(query)
+	filenameDate := pornDBFilenameDate(scene.OriginalFilename)
+
+	candidates := make(data.PornDBMatchCandidates, 0, len(results))
+	for _, result := range results {
+		candidate := data.PornDBMatchCandidate{
+			PornDBSceneID: result.ID,
+			Title:         result.Title,
+			Date:          result.Date,
+			Duration:      result.Duration,
+			Image:         result.Image,
+		}
+		text := result.Title
+		if result.Site != nil {
+			candidate.Site = result.Site.Name
+			text += " " + result.Site.Name
+		}
+		for _, p := range result.Performers {
+			candidate.Performers = append(candidate.Performers, p.Name)
+			text += " " + p.Name
+		}
+
+		score := 0.7*diceCoefficient(queryTokens, matchTokens(text)) + 0.3*durationCloseness(scene.Duration, result.Duration)
+		if filenameDate != "" && result.Date == filenameDate {
+			score += 0.1
+		}
+		candidate.Score = math.Round(math.Min(score, 1)*1000) / 1000
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// durationCloseness is 1 for equal durations, falling to 0 a minute apart, and
+// 0.5 when either is unknown.
+func durationCloseness(a, b int) float64 {
+	if a <= 0 || b <= 0 {
+		return 0.5
+	}
+	diff := math.Abs(float64(a - b))
+	return math.Max(0, 1-diff/60)
+}
+
+// diceCoefficient is the Sørensen–Dice similarity of two token sets.
+func diceCoefficient(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for token := range a {
+		if b[token] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// matchTokens returns the distinct lowercase words of text.
+func matchTokens(text string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, token := range matchTokenSplit.Split(strings.ToLower(text), -1) {
+		if token != "" {
+			tokens[token] = true
+		}
+	}
+	return tokens
+}
+
+// pornDBFilenameQuery turns a filename into a PornDB search: the extension and
+// release tags are dropped and separators become spaces. The date is kept, as
+// PornDB's parser uses it.
+func pornDBFilenameQuery(filename string) string {
+	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+	name = filenameNoise.ReplaceAllString(name, " ")
+	name = strings.NewReplacer(".", " ", "_", " ", "-", " ", "[", " ", "]", " ", "(", " ", ")", " ").Replace(name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// pornDBFilenameDate returns the release date in a filename as YYYY-MM-DD, or
+// "" when it has none.
+func pornDBFilenameDate(filename string) string {
+	m := filenameDate.FindStringSubmatch(filename)
+	if m == nil {
+		return ""
+	}
+	year := m[1]
+	if len(year) == 2 {
+		year = "20" + year
+	}
+	date := year + "-" + m[2] + "-" + m[3]
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return ""
+	}
+	return date
+}
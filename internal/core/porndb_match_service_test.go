@@ -0,0 +1,318 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type fakePornDBSource struct {
+	name    string
+	results map[string][]PornDBScene
+	details map[string]*PornDBScene
+	queries []string
+}
+
+func (f *fakePornDBSource) Name() string       { return f.name }
+func (f *fakePornDBSource) Label() string      { return f.name }
+func (f *fakePornDBSource) IsConfigured() bool { return true }
+
+func (f *fakePornDBSource) SearchPerformers(query string) ([]PornDBPerformer, error) {
+	return nil, nil
+}
+
+func (f *fakePornDBSource) SearchScenes(opts SceneSearchOptions) ([]PornDBScene, error) {
+	f.queries = append(f.queries, opts.Title)
+	return f.results[opts.Title], nil
+}
+
+func (f *fakePornDBSource) GetSceneDetails(id string) (*PornDBScene, error) {
+	if scene, ok := f.details[id]; ok {
+		return scene, nil
+	}
+	return nil, apperrors.NewNotFoundError("porndb scene", id)
+}
+
+type appliedMetadata struct {
+	id                           uint
+	title, studio, pdID, stashID string
+	releaseDate                  *time.Time
+	input                        SceneMetadataInput
+}
+
+type fakeMetadataUpdater struct {
+	applied []appliedMetadata
+}
+
+func (f *fakeMetadataUpdater) ApplyMetadata(sceneID uint, input SceneMetadataInput) (*SceneMetadataResult, error) {
+	deref := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+	f.applied = append(f.applied, appliedMetadata{
+		id: sceneID, title: deref(input.Title), studio: deref(input.Studio), pdID: deref(input.PornDBSceneID),
+		stashID: deref(input.StashDBSceneID), releaseDate: input.ReleaseDate, input: input,
+	})
+	return &SceneMetadataResult{Scene: &data.Scene{ID: sceneID}}, nil
+}
+
+func newTestPornDBMatchService(t *testing.T) (*PornDBMatchService, *mocks.MockPornDBMatchRepository, *mocks.MockSceneRepository, *fakePornDBSource, *fakeMetadataUpdater) {
+	ctrl := gomock.NewController(t)
+	matchRepo := mocks.NewMockPornDBMatchRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	jobHistory := NewJobHistoryService(mocks.NewMockJobHistoryRepository(ctrl), config.ProcessingConfig{}, zap.NewNop())
+	svc := NewPornDBMatchService(matchRepo, sceneRepo, nil, nil, jobHistory, zap.NewNop())
+	source := &fakePornDBSource{name: MetadataProviderPornDB, results: map[string][]PornDBScene{}, details: map[string]*PornDBScene{}}
+	updater := &fakeMetadataUpdater{}
+	svc.providers = newMetadataProviders(source)
+	svc.scenes = updater
+	return svc, matchRepo, sceneRepo, source, updater
+}
+
+func TestPornDBFilenameQuery(t *testing.T) {
+	cases := map[string]string{
+		"Brazzers.24.03.15.Jane.Doe.Pool.Party.1080p.mp4": "Brazzers 24 03 15 Jane Doe Pool Party",
+		"[Site] Some_Title (2160p) XXX.mkv":               "Site Some Title",
+		"plain title.mp4":                                 "plain title",
+	}
+	for filename, want := range cases {
+		if got := pornDBFilenameQuery(filename); got != want {
+			t.Errorf("pornDBFilenameQuery(%q) = %q, want %q", filename, got, want)
+		}
+	}
+
+	if got := pornDBFilenameDate("Brazzers.24.03.15.Jane.Doe.mp4"); got != "2024-03-15" {
+		t.Errorf("expected the short date to expand, got %q", got)
+	}
+	if got := pornDBFilenameDate("site 2023-11-02 title.mp4"); got != "2023-11-02" {
+		t.Errorf("expected the ISO date, got %q", got)
+	}
+	if got := pornDBFilenameDate("site.24.13.45.title.mp4"); got != "" {
+		t.Errorf("expected an impossible date to be ignored, got %q", got)
+	}
+}
+
+func TestScorePornDBCandidates(t *testing.T) {
+	scene := &data.Scene{OriginalFilename: "Brazzers.24.03.15.Jane.Doe.Pool.Party.mp4", Duration: 1800}
+	query := pornDBFilenameQuery(scene.OriginalFilename)
+
+	candidates := scorePornDBCandidates(scene, query, []PornDBScene{
+		{ID: "other", Title: "Office Hours", Duration: 2400, Site: &PornDBSite{Name: "Elsewhere"}},
+		{ID: "match", Title: "Pool Party", Date: "2024-03-15", Duration: 1805, Site: &PornDBSite{Name: "Brazzers"},
+			Performers: []PornDBScenePerformer{{Name: "Jane Doe"}}},
+	})
+	if len(candidates) != 2 || candidates[0].PornDBSceneID != "match" {
+		t.Fatalf("expected the matching scene first, got %+v", candidates)
+	}
+	if candidates[0].Score < DefaultPornDBMatchThreshold {
+		t.Fatalf("expected a confident score for the match, got %v", candidates[0].Score)
+	}
+	if candidates[1].Score > 0.2 {
+		t.Fatalf("expected a low score for the unrelated scene, got %v", candidates[1].Score)
+	}
+	if candidates[0].Site != "Brazzers" || len(candidates[0].Performers) != 1 {
+		t.Fatalf("expected the site and performers to be kept, got %+v", candidates[0])
+	}
+}
+
+func TestIsConfidentMatch(t *testing.T) {
+	cases := []struct {
+		scores []float64
+		want   bool
+	}{
+		{[]float64{0.9}, true},
+		{[]float64{0.7}, false},
+		{[]float64{0.95, 0.8}, true},
+		{[]float64{0.9, 0.85}, false}, // too close to the runner-up
+	}
+	for _, tc := range cases {
+		var candidates data.PornDBMatchCandidates
+		for _, score := range tc.scores {
+			candidates = append(candidates, data.PornDBMatchCandidate{Score: score})
+		}
+		if got := isConfidentMatch(candidates, 0.8); got != tc.want {
+			t.Errorf("isConfidentMatch(%v) = %v, want %v", tc.scores, got, tc.want)
+		}
+	}
+}
+
+func TestPornDBMatchService_MatchScene(t *testing.T) {
+	svc, matchRepo, _, source, updater := newTestPornDBMatchService(t)
+	job := &PornDBAutoMatchJob{JobID: "job", Provider: MetadataProviderPornDB, Threshold: DefaultPornDBMatchThreshold}
+
+	confident := &data.Scene{ID: 1, OriginalFilename: "Brazzers.24.03.15.Jane.Doe.Pool.Party.mp4", Duration: 1800}
+	source.results["Brazzers 24 03 15 Jane Doe Pool Party"] = []PornDBScene{
+		{ID: "pd-1", Title: "Pool Party", Date: "2024-03-15", Duration: 1800, Site: &PornDBSite{Name: "Brazzers"},
+			Performers: []PornDBScenePerformer{{Name: "Jane Doe"}}},
+	}
+	matchRepo.EXPECT().Upsert(gomock.Any()).DoAndReturn(func(review *data.PornDBMatchReview) error {
+		if review.Status != data.PornDBMatchApplied || review.PornDBSceneID != "pd-1" || review.JobID != "job" {
+			t.Fatalf("expected an applied review, got %+v", review)
+		}
+		return nil
+	})
+	if status, err := svc.matchScene(job, source, confident); err != nil || status != data.PornDBMatchApplied {
+		t.Fatalf("expected the match to be applied, got %q, %v", status, err)
+	}
+	if len(updater.applied) != 1 || updater.applied[0].studio != "Brazzers" || updater.applied[0].title != "Pool Party" ||
+		updater.applied[0].releaseDate == nil || updater.applied[0].releaseDate.Format("2006-01-02") != "2024-03-15" {
+		t.Fatalf("expected the PornDB metadata to be written, got %+v", updater.applied)
+	}
+
+	// Two candidates with the same title are left for review, searched by title once the filename finds nothing
+	ambiguous := &data.Scene{ID: 2, Title: "Beach Day", OriginalFilename: "clip_0042.mp4"}
+	source.results["Beach Day"] = []PornDBScene{
+		{ID: "pd-2", Title: "Beach Day", Site: &PornDBSite{Name: "Sunny"}},
+		{ID: "pd-3", Title: "Beach Day", Site: &PornDBSite{Name: "Other"}},
+	}
+	matchRepo.EXPECT().Upsert(gomock.Any()).DoAndReturn(func(review *data.PornDBMatchReview) error {
+		if review.Status != data.PornDBMatchPending || len(review.Candidates) != 2 || review.Query != "Beach Day" {
+			t.Fatalf("expected a pending review with both candidates, got %+v", review)
+		}
+		return nil
+	})
+	if status, err := svc.matchScene(job, source, ambiguous); err != nil || status != data.PornDBMatchPending {
+		t.Fatalf("expected the match to be queued for review, got %q, %v", status, err)
+	}
+	if len(updater.applied) != 1 {
+		t.Fatalf("expected an ambiguous match not to be applied")
+	}
+
+	missing := &data.Scene{ID: 3, OriginalFilename: "unknown.mp4", Title: "unknown"}
+	matchRepo.EXPECT().Upsert(gomock.Any()).DoAndReturn(func(review *data.PornDBMatchReview) error {
+		if review.Status != data.PornDBMatchNoMatch {
+			t.Fatalf("expected a no_match review, got %+v", review)
+		}
+		return nil
+	})
+	if status, err := svc.matchScene(job, source, missing); err != nil || status != data.PornDBMatchNoMatch {
+		t.Fatalf("expected no match, got %q, %v", status, err)
+	}
+}
+
+func TestPornDBMatchService_AcceptReview(t *testing.T) {
+	svc, matchRepo, sceneRepo, source, updater := newTestPornDBMatchService(t)
+
+	source.details["pd-3"] = &PornDBScene{ID: "pd-3", Title: "Beach Day 2"}
+	matchRepo.EXPECT().GetBySceneID(uint(2)).Return(&data.PornDBMatchReview{SceneID: 2, Status: data.PornDBMatchPending}, nil)
+	sceneRepo.EXPECT().GetByID(uint(2)).Return(&data.Scene{ID: 2, Title: "Beach Day", Studio: "Local"}, nil)
+	matchRepo.EXPECT().Update(gomock.Any()).Return(nil)
+
+	review, err := svc.AcceptReview(2, "", "pd-3", nil, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if review.Status != data.PornDBMatchApplied || review.PornDBSceneID != "pd-3" || review.ReviewedBy == nil || *review.ReviewedBy != 7 {
+		t.Fatalf("expected an applied review, got %+v", review)
+	}
+	if len(updater.applied) != 1 || updater.applied[0].pdID != "pd-3" || updater.applied[0].studio != "Local" {
+		t.Fatalf("expected the chosen scene to be applied keeping the local studio, got %+v", updater.applied)
+	}
+
+	matchRepo.EXPECT().GetBySceneID(uint(2)).Return(&data.PornDBMatchReview{SceneID: 2, Status: data.PornDBMatchApplied}, nil)
+	if _, err := svc.DismissReview(2, 7); !apperrors.IsValidation(err) {
+		t.Fatalf("expected dismissing an applied match to fail, got %v", err)
+	}
+}
+
+func TestPornDBMatchService_AcceptReviewFromStashDB(t *testing.T) {
+	svc, matchRepo, sceneRepo, source, updater := newTestPornDBMatchService(t)
+	stashdb := &fakePornDBSource{name: MetadataProviderStashDB, details: map[string]*PornDBScene{
+		"0b6b7c1e": {ID: "0b6b7c1e", Title: "Beach Day"},
+	}}
+	svc.providers = newMetadataProviders(source, stashdb)
+
+	matchRepo.EXPECT().GetBySceneID(uint(2)).Return(&data.PornDBMatchReview{SceneID: 2, Provider: MetadataProviderPornDB, Status: data.PornDBMatchNoMatch}, nil)
+	sceneRepo.EXPECT().GetByID(uint(2)).Return(&data.Scene{ID: 2, PornDBSceneID: ""}, nil)
+	matchRepo.EXPECT().Update(gomock.Any()).Return(nil)
+
+	review, err := svc.AcceptReview(2, MetadataProviderStashDB, "0b6b7c1e", nil, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if review.Provider != MetadataProviderStashDB || review.PornDBSceneID != "0b6b7c1e" {
+		t.Fatalf("expected a StashDB match, got %+v", review)
+	}
+	if len(updater.applied) != 1 || updater.applied[0].pdID != "" || updater.applied[0].input.PornDBSceneID != nil || updater.applied[0].stashID != "0b6b7c1e" {
+		t.Fatalf("expected the StashDB ID not to be written as the PornDB ID, got %+v", updater.applied)
+	}
+
+	matchRepo.EXPECT().GetBySceneID(uint(2)).Return(&data.PornDBMatchReview{SceneID: 2, Status: data.PornDBMatchPending}, nil)
+	if _, err := svc.AcceptReview(2, "iafd", "x", nil, 7); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an unknown provider to be rejected, got %v", err)
+	}
+}
+
+func TestPornDBMatchService_StartRejectsConcurrentRuns(t *testing.T) {
+	svc, _, _, _, _ := newTestPornDBMatchService(t)
+	svc.running = true
+
+	if _, err := svc.Start("", 0); err == nil {
+		t.Fatal("expected a second run to be rejected")
+	}
+	if _, err := svc.Start(MetadataProviderStashDB, 0); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an unregistered provider to be rejected, got %v", err)
+	}
+	if _, err := svc.Start("", 1.5); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an out of range threshold to be rejected, got %v", err)
+	}
+}
+
+func TestPornDBMatchService_ApplyMatch(t *testing.T) {
+	svc, matchRepo, sceneRepo, source, updater := newTestPornDBMatchService(t)
+	end := 95
+	source.details["pd-9"] = &PornDBScene{
+		ID: "pd-9", Title: "Pool Party", Date: "2024-03-15", Image: "https://cdn.example.com/pd-9.jpg",
+		Site:       &PornDBSite{Name: "Brazzers"},
+		Performers: []PornDBScenePerformer{{Name: "Jane Doe"}, {Name: " "}},
+		Tags:       []PornDBTag{{Name: "Outdoor"}},
+		Markers:    []PornDBMarker{{Title: "Intro", StartTime: 0, EndTime: &end}},
+	}
+
+	sceneRepo.EXPECT().GetByID(uint(4)).Return(&data.Scene{ID: 4, Title: "clip", Studio: "Local"}, nil)
+	matchRepo.EXPECT().GetBySceneID(uint(4)).Return(nil, gorm.ErrRecordNotFound)
+	if _, err := svc.ApplyMatch(4, "", "pd-9", []string{"Performers", "tags", "markers", "cover", "date"}, 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	input := updater.applied[0].input
+	if input.Title != nil || input.Studio != nil || input.ReleaseDate == nil || *input.PornDBSceneID != "pd-9" {
+		t.Fatalf("expected only the selected details to be applied, got %+v", input)
+	}
+	if strings.Join(input.Performers, ",") != "Jane Doe" || strings.Join(input.Tags, ",") != "Outdoor" || input.CoverURL != "https://cdn.example.com/pd-9.jpg" {
+		t.Fatalf("unexpected performers, tags or cover %+v", input)
+	}
+	if len(input.Markers) != 1 || input.Markers[0].UserID != 7 || input.Markers[0].Label != "Intro" || *input.Markers[0].EndTimestamp != 95 {
+		t.Fatalf("expected the marker to be created for the user, got %+v", input.Markers)
+	}
+
+	// A pending review of the scene is settled by applying a match
+	sceneRepo.EXPECT().GetByID(uint(4)).Return(&data.Scene{ID: 4}, nil)
+	matchRepo.EXPECT().GetBySceneID(uint(4)).Return(&data.PornDBMatchReview{SceneID: 4, Status: data.PornDBMatchPending}, nil)
+	matchRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(review *data.PornDBMatchReview) error {
+		if review.Status != data.PornDBMatchApplied || review.PornDBSceneID != "pd-9" {
+			t.Fatalf("expected the review to be applied, got %+v", review)
+		}
+		return nil
+	})
+	if _, err := svc.ApplyMatch(4, "", "pd-9", nil, 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if input := updater.applied[1].input; input.Title == nil || input.Studio == nil || *input.Studio != "Brazzers" || input.Performers != nil || input.CoverURL != "" {
+		t.Fatalf("expected the details to be applied by default, got %+v", input)
+	}
+
+	if _, err := svc.ApplyMatch(4, "", "pd-9", []string{"rating"}, 7); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an unknown field to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// pornDBBackoffBase is the first wait after a 429 without a Retry-After
+	// header; it doubles with every retry
+	pornDBBackoffBase = 2 * time.Second
+	// pornDBMaxBackoff caps how long one 429 can pause the client
+	pornDBMaxBackoff = 5 * time.Minute
+)
+
+// PornDBRateLimitStats reports how the PornDB client is pacing its requests.
+// Counters are since the server started; the quota is what PornDB reported on
+// its latest response.
+type PornDBRateLimitStats struct {
+	RequestsPerMinute int        `json:"requests_per_minute"` // 0 = unlimited
+	Requests          int64      `json:"requests"`
+	Throttled         int64      `json:"throttled"` // 429 responses
+	Retries           int64      `json:"retries"`
+	Queued            int64      `json:"queued"` // requests waiting for their turn
+	QuotaLimit        *int       `json:"quota_limit"`
+	QuotaRemaining    *int       `json:"quota_remaining"`
+	PausedUntil       *time.Time `json:"paused_until,omitempty"`
+}
+
+// metadataLimiter sends a metadata provider's requests one at a time, no
+// faster than the configured rate, and pauses every request after a 429 until
+// the provider allows more. PornDB and StashDB each have their own.
+type metadataLimiter struct {
+	limiter           *rate.Limiter
+	requestsPerMinute int
+	maxRetries        int
+	queue             chan struct{} // one slot: requests are sent one at a time
+	now               func() time.Time
+	sleep             func(time.Duration)
+
+	mu             sync.Mutex
+	pausedUntil    time.Time
+	quotaLimit     *int
+	quotaRemaining *int
+
+	requests  atomic.Int64
+	throttled atomic.Int64
+	retries   atomic.Int64
+	queued    atomic.Int64
+}
+
+func newMetadataLimiter(requestsPerMinute, maxRetries int) *metadataLimiter {
+	limit := rate.Inf
+	if requestsPerMinute > 0 {
+		limit = rate.Limit(float64(requestsPerMinute) / 60)
+	}
+	return &metadataLimiter{
+		limiter:           rate.NewLimiter(limit, 1),
+		requestsPerMinute: requestsPerMinute,
+		maxRetries:        maxRetries,
+		queue:             make(chan struct{}, 1),
+		now:               time.Now,
+		sleep:             time.Sleep,
+	}
+}
+
+// do sends a PornDB request through the limiter.
+func (s *PornDBService) do(req *http.Request) (*http.Response, error) {
+	return s.limiter.do(s.client, req, s.logger)
+}
+
+// do sends a request through the limiter, retrying it after a 429 until the
+// retries run out. The final response, 429 or not, is returned.
+func (l *metadataLimiter) do(client *http.Client, req *http.Request, logger *zap.Logger) (*http.Response, error) {
+	l.queued.Add(1)
+	l.queue <- struct{}{}
+	l.queued.Add(-1)
+	defer func() { <-l.queue }()
+
+	for attempt := 0; ; attempt++ {
+		l.waitForPause()
+		if err := l.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		l.requests.Add(1)
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		l.recordQuota(resp.Header)
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+
+		l.throttled.Add(1)
+		delay := pornDBRetryDelay(resp.Header, attempt, l.now())
+		l.pause(delay)
+		if attempt >= l.maxRetries {
+			logger.Warn("Metadata provider rate limit exceeded, giving up",
+				zap.String("path", req.URL.Path),
+				zap.Int("attempts", attempt+1),
+			)
+			return resp, nil
+		}
+		logger.Warn("Metadata provider rate limit exceeded, retrying",
+			zap.String("path", req.URL.Path),
+			zap.Duration("delay", delay),
+		)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if req.GetBody != nil {
+			// The first attempt consumed the body of a POST
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		l.retries.Add(1)
+	}
+}
+
+// RateLimitStats returns the client's request counters and PornDB's quota.
+func (s *PornDBService) RateLimitStats() PornDBRateLimitStats {
+	return s.limiter.stats()
+}
+
+func (l *metadataLimiter) stats() PornDBRateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := PornDBRateLimitStats{
+		RequestsPerMinute: l.requestsPerMinute,
+		Requests:          l.requests.Load(),
+		Throttled:         l.throttled.Load(),
+		Retries:           l.retries.Load(),
+		Queued:            l.queued.Load(),
+		QuotaLimit:        l.quotaLimit,
+		QuotaRemaining:    l.quotaRemaining,
+	}
+	if l.pausedUntil.After(l.now()) {
+		pausedUntil := l.pausedUntil
+		stats.PausedUntil = &pausedUntil
+	}
+	return stats
+}
+
+func (l *metadataLimiter) waitForPause() {
+	l.mu.Lock()
+	wait := l.pausedUntil.Sub(l.now())
+	l.mu.Unlock()
+	if wait > 0 {
+		l.sleep(wait)
+	}
+}
+
+func (l *metadataLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := l.now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// recordQuota keeps the X-RateLimit headers of a response. When the quota is
+// used up and PornDB says when it resets, requests pause until then.
+func (l *metadataLimiter) recordQuota(header http.Header) {
+	limit, limitErr := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	remaining, remainingErr := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+
+	l.mu.Lock()
+	if limitErr == nil {
+		l.quotaLimit = &limit
+	}
+	if remainingErr == nil {
+		l.quotaRemaining = &remaining
+	}
+	l.mu.Unlock()
+
+	if remainingErr == nil && remaining <= 0 {
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			if wait := time.Unix(reset, 0).Sub(l.now()); wait > 0 {
+				l.pause(min(wait, pornDBMaxBackoff))
+			}
+		}
+	}
+}
+
+// pornDBRetryDelay is how long to wait after a 429: the Retry-After header,
+// as seconds or a date, or else an exponential backoff.
+func pornDBRetryDelay(header http.Header, attempt int, now time.Time) time.Duration {
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, pornDBMaxBackoff)
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return min(max(at.Sub(now), 0), pornDBMaxBackoff)
+		}
+	}
+	if attempt >= 10 {
+		return pornDBMaxBackoff
+	}
+	return min(pornDBBackoffBase<<attempt, pornDBMaxBackoff)
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"goonhub/internal/config"
+
+	"go.uber.org/zap"
+)
+
+func newTestRateLimitedPornDBService(maxRetries int) (*PornDBService, *[]time.Duration) {
+	svc := NewPornDBService(config.PornDBConfig{APIKey: "key", MaxRetries: maxRetries}, nil, zap.NewNop())
+	var slept []time.Duration
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.limiter.now = func() time.Time { return now }
+	svc.limiter.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		now = now.Add(d)
+	}
+	return svc, &slept
+}
+
+func TestPornDBService_RetriesAfter429(t *testing.T) {
+	svc, slept := newTestRateLimitedPornDBService(3)
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "60")
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Header().Set("X-RateLimit-Remaining", "58")
+			w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/scenes", nil)
+	resp, err := svc.do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("expected success on the third attempt, got %d after %d calls", resp.StatusCode, calls)
+	}
+	// Retry-After first, then the backoff for the second attempt
+	if len(*slept) != 2 || (*slept)[0] != 7*time.Second || (*slept)[1] != 2*pornDBBackoffBase {
+		t.Fatalf("expected waits of 7s and %v, got %v", 2*pornDBBackoffBase, *slept)
+	}
+
+	stats := svc.RateLimitStats()
+	if stats.Requests != 3 || stats.Throttled != 2 || stats.Retries != 2 {
+		t.Fatalf("unexpected counters %+v", stats)
+	}
+	if stats.QuotaLimit == nil || *stats.QuotaLimit != 60 || stats.QuotaRemaining == nil || *stats.QuotaRemaining != 58 {
+		t.Fatalf("expected the latest quota, got %+v", stats)
+	}
+}
+
+func TestPornDBService_GivesUpAfterMaxRetries(t *testing.T) {
+	svc, _ := newTestRateLimitedPornDBService(1)
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/scenes", nil)
+	resp, err := svc.do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls != 2 {
+		t.Fatalf("expected the 429 after one retry, got %d after %d calls", resp.StatusCode, calls)
+	}
+	if stats := svc.RateLimitStats(); stats.PausedUntil == nil {
+		t.Fatal("expected later requests to stay paused")
+	}
+}
+
+func TestPornDBRetryDelay(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		retryAfter string
+		attempt    int
+		want       time.Duration
+	}{
+		{"30", 0, 30 * time.Second},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 0, 90 * time.Second},
+		{"86400", 0, pornDBMaxBackoff},
+		{"", 0, pornDBBackoffBase},
+		{"", 2, 4 * pornDBBackoffBase},
+		{"", 40, pornDBMaxBackoff},
+	}
+	for _, tc := range cases {
+		header := http.Header{}
+		if tc.retryAfter != "" {
+			header.Set("Retry-After", tc.retryAfter)
+		}
+		if got := pornDBRetryDelay(header, tc.attempt, now); got != tc.want {
+			t.Errorf("pornDBRetryDelay(%q, %d) = %v, want %v", tc.retryAfter, tc.attempt, got, tc.want)
+		}
+	}
+}
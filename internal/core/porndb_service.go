@@ -8,8 +8,13 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
 	"go.uber.org/zap"
 )
 
@@ -250,31 +255,56 @@ type pornDBSceneResponse struct {
 	Data pornDBSceneRaw `json:"data"`
 }
 
-// PornDBService handles communication with ThePornDB API
+// PornDBService handles communication with ThePornDB API. Lookups are cached
+// in the database for cacheTTL (see porndb_cache.go), and requests are sent
+// one at a time through a rate limiter that backs off on 429s (see
+// porndb_ratelimit.go).
 type PornDBService struct {
-	apiKey string
-	client *http.Client
-	logger *zap.Logger
+	apiKey   string
+	client   *http.Client
+	logger   *zap.Logger
+	cache    data.PornDBCacheRepository
+	cacheTTL time.Duration
+	limiter  *metadataLimiter
+	now      func() time.Time
+
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
 }
 
-// NewPornDBService creates a new PornDB service
-func NewPornDBService(apiKey string, logger *zap.Logger) *PornDBService {
+// NewPornDBService creates a new PornDB service. A nil cache or zero
+// CacheTTL disables caching.
+func NewPornDBService(cfg config.PornDBConfig, cache data.PornDBCacheRepository, logger *zap.Logger) *PornDBService {
 	return &PornDBService{
-		apiKey: apiKey,
+		apiKey: cfg.APIKey,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		logger: logger,
+		logger:   logger,
+		cache:    cache,
+		cacheTTL: cfg.CacheTTL,
+		limiter:  newMetadataLimiter(cfg.RequestsPerMinute, cfg.MaxRetries),
+		now:      time.Now,
 	}
 }
 
+// Name returns the metadata provider name of PornDB
+func (s *PornDBService) Name() string {
+	return MetadataProviderPornDB
+}
+
+// Label returns how PornDB is shown in error messages
+func (s *PornDBService) Label() string {
+	return "PornDB"
+}
+
 // IsConfigured returns true if the API key is configured
 func (s *PornDBService) IsConfigured() bool {
 	return s.apiKey != ""
 }
 
-// SearchPerformers searches for performers by name, querying both /performers and /performer-sites endpoints
-func (s *PornDBService) SearchPerformers(query string) ([]PornDBPerformer, error) {
+// searchPerformers searches for performers by name, querying both /performers and /performer-sites endpoints
+func (s *PornDBService) searchPerformers(query string) ([]PornDBPerformer, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("PornDB API key is not configured")
 	}
@@ -313,7 +343,7 @@ func (s *PornDBService) searchPerformersEndpoint(query string) ([]PornDBPerforme
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -349,7 +379,7 @@ func (s *PornDBService) searchPerformerSites(query string) ([]PornDBPerformer, e
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -425,8 +455,8 @@ func parseNumericValue(s string) *int {
 	return &val
 }
 
-// GetPerformerDetails fetches detailed information about a performer
-func (s *PornDBService) GetPerformerDetails(id string) (*PornDBPerformerDetails, error) {
+// getPerformerDetails fetches detailed information about a performer
+func (s *PornDBService) getPerformerDetails(id string) (*PornDBPerformerDetails, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("PornDB API key is not configured")
 	}
@@ -439,7 +469,7 @@ func (s *PornDBService) GetPerformerDetails(id string) (*PornDBPerformerDetails,
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -504,9 +534,9 @@ func (s *PornDBService) GetPerformerDetails(id string) (*PornDBPerformerDetails,
 	return details, nil
 }
 
-// GetPerformerSiteDetails fetches detailed information about a performer from the performer-sites endpoint
+// getPerformerSiteDetails fetches detailed information about a performer from the performer-sites endpoint
 // This is needed because IDs from /performer-sites search cannot be used with /performers endpoint
-func (s *PornDBService) GetPerformerSiteDetails(id string) (*PornDBPerformerDetails, error) {
+func (s *PornDBService) getPerformerSiteDetails(id string) (*PornDBPerformerDetails, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("PornDB API key is not configured")
 	}
@@ -519,7 +549,7 @@ func (s *PornDBService) GetPerformerSiteDetails(id string) (*PornDBPerformerDeta
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -636,24 +666,44 @@ func convertRawSceneToScene(raw pornDBSceneRaw) PornDBScene {
 	return scene
 }
 
+// Fingerprint algorithms scenes can be searched by
+const (
+	FingerprintMD5    = "md5"
+	FingerprintOSHash = "oshash"
+	FingerprintPHash  = "phash"
+)
+
 // SceneSearchOptions contains optional search parameters for scene search
 type SceneSearchOptions struct {
-	Title string // Scene title (sent as "parse" to PornDB API)
+	Title                string // Scene title (sent as "parse" to PornDB API)
+	Fingerprint          string // File fingerprint, searched instead of the title when set
+	FingerprintAlgorithm string // One of the Fingerprint* algorithms
 }
 
 // IsEmpty returns true if no search parameters are set
 func (o SceneSearchOptions) IsEmpty() bool {
-	return o.Title == ""
+	return o.Title == "" && o.Fingerprint == ""
+}
+
+// cacheArg is what identifies the search in the lookup cache.
+func (o SceneSearchOptions) cacheArg() string {
+	if o.Fingerprint != "" {
+		return o.FingerprintAlgorithm + ":" + o.Fingerprint
+	}
+	return o.Title
 }
 
-// SearchScenes searches for scenes with optional filters
-func (s *PornDBService) SearchScenes(opts SceneSearchOptions) ([]PornDBScene, error) {
+// searchScenes searches for scenes with optional filters
+func (s *PornDBService) searchScenes(opts SceneSearchOptions) ([]PornDBScene, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("PornDB API key is not configured")
 	}
 
 	params := url.Values{}
-	if opts.Title != "" {
+	if opts.Fingerprint != "" {
+		params.Set("hash", opts.Fingerprint)
+		params.Set("hashType", strings.ToUpper(opts.FingerprintAlgorithm))
+	} else if opts.Title != "" {
 		params.Set("parse", opts.Title)
 	}
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/scenes?%s", pornDBBaseURL, params.Encode()), nil)
@@ -664,7 +714,7 @@ func (s *PornDBService) SearchScenes(opts SceneSearchOptions) ([]PornDBScene, er
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -692,8 +742,8 @@ func (s *PornDBService) SearchScenes(opts SceneSearchOptions) ([]PornDBScene, er
 	return scenes, nil
 }
 
-// GetSceneDetails fetches detailed information about a scene
-func (s *PornDBService) GetSceneDetails(id string) (*PornDBScene, error) {
+// getSceneDetails fetches detailed information about a scene
+func (s *PornDBService) getSceneDetails(id string) (*PornDBScene, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("PornDB API key is not configured")
 	}
@@ -706,7 +756,7 @@ func (s *PornDBService) GetSceneDetails(id string) (*PornDBScene, error) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -799,8 +849,8 @@ func convertRawSiteToSiteDetails(raw pornDBSiteRaw) PornDBSiteDetails {
 	return site
 }
 
-// SearchSites searches for sites/studios by name
-func (s *PornDBService) SearchSites(query string) ([]PornDBSiteDetails, error) {
+// searchSites searches for sites/studios by name
+func (s *PornDBService) searchSites(query string) ([]PornDBSiteDetails, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("PornDB API key is not configured")
 	}
@@ -816,7 +866,7 @@ func (s *PornDBService) SearchSites(query string) ([]PornDBSiteDetails, error) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -844,8 +894,8 @@ func (s *PornDBService) SearchSites(query string) ([]PornDBSiteDetails, error) {
 	return sites, nil
 }
 
-// GetSiteDetails fetches detailed information about a site
-func (s *PornDBService) GetSiteDetails(id string) (*PornDBSiteDetails, error) {
+// getSiteDetails fetches detailed information about a site
+func (s *PornDBService) getSiteDetails(id string) (*PornDBSiteDetails, error) {
 	if !s.IsConfigured() {
 		return nil, fmt.Errorf("PornDB API key is not configured")
 	}
@@ -858,7 +908,7 @@ func (s *PornDBService) GetSiteDetails(id string) (*PornDBSiteDetails, error) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
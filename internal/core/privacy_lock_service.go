@@ -0,0 +1,289 @@
+package core
+
+import (
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// maxPINAttempts is how many wrong PINs a session may enter before it is revoked
+	maxPINAttempts = 5
+	// minPrivacyLockMinutes and maxPrivacyLockMinutes bound the idle timeout
+	minPrivacyLockMinutes = 1
+	maxPrivacyLockMinutes = 24 * 60
+)
+
+// PrivacyLockStatus describes the privacy lock state of the current session
+type PrivacyLockStatus struct {
+	Enabled            bool `json:"enabled"`
+	Locked             bool `json:"locked"`
+	IdleTimeoutMinutes int  `json:"idle_timeout_minutes"`
+}
+
+// privacyLockConfig is the cached per-user lock configuration
+type privacyLockConfig struct {
+	pinHash string
+	timeout time.Duration
+}
+
+// PrivacyLockService locks idle sessions behind a short per-user PIN.
+// Activity is tracked per session (token hash) in memory, so a server restart
+// locks every session of users with a PIN, which errs on the side of privacy.
+type PrivacyLockService struct {
+	userRepo    data.UserRepository
+	authService *AuthService
+	logger      *zap.Logger
+
+	mu           sync.Mutex
+	configs      map[uint]privacyLockConfig
+	lastActivity map[string]time.Time
+	failures     map[string]int
+	now          func() time.Time
+}
+
+// NewPrivacyLockService creates a new PrivacyLockService
+func NewPrivacyLockService(userRepo data.UserRepository, authService *AuthService, logger *zap.Logger) *PrivacyLockService {
+	return &PrivacyLockService{
+		userRepo:     userRepo,
+		authService:  authService,
+		logger:       logger,
+		configs:      make(map[uint]privacyLockConfig),
+		lastActivity: make(map[string]time.Time),
+		failures:     make(map[string]int),
+		now:          time.Now,
+	}
+}
+
+// Status returns the lock state of the session
+func (s *PrivacyLockService) Status(userID uint, token string) (*PrivacyLockStatus, error) {
+	cfg, err := s.getConfig(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &PrivacyLockStatus{
+		Enabled:            cfg.pinHash != "",
+		Locked:             s.isLockedLocked(cfg, s.authService.hashToken(token)),
+		IdleTimeoutMinutes: int(cfg.timeout.Minutes()),
+	}, nil
+}
+
+// Check returns ErrPrivacyLocked when the session is locked; otherwise it records activity
+func (s *PrivacyLockService) Check(userID uint, token string) error {
+	cfg, err := s.getConfig(userID)
+	if err != nil {
+		return err
+	}
+	if cfg.pinHash == "" {
+		return nil
+	}
+
+	hash := s.authService.hashToken(token)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isLockedLocked(cfg, hash) {
+		return apperrors.ErrPrivacyLocked
+	}
+	s.lastActivity[hash] = s.now()
+	return nil
+}
+
+// StartSession marks a freshly issued token as unlocked; the user just entered their password
+func (s *PrivacyLockService) StartSession(token string) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Entries idle past the longest allowed timeout are locked either way, drop them
+	for hash, last := range s.lastActivity {
+		if now.Sub(last) > maxPrivacyLockMinutes*time.Minute {
+			delete(s.lastActivity, hash)
+			delete(s.failures, hash)
+		}
+	}
+	s.lastActivity[s.authService.hashToken(token)] = now
+}
+
+// EndSession forgets a session on logout
+func (s *PrivacyLockService) EndSession(token string) {
+	hash := s.authService.hashToken(token)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.lastActivity, hash)
+	delete(s.failures, hash)
+}
+
+// Lock locks the session immediately
+func (s *PrivacyLockService) Lock(userID uint, token string) error {
+	cfg, err := s.getConfig(userID)
+	if err != nil {
+		return err
+	}
+	if cfg.pinHash == "" {
+		return apperrors.NewValidationError("privacy lock is not enabled")
+	}
+
+	hash := s.authService.hashToken(token)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.lastActivity, hash)
+	return nil
+}
+
+// Unlock unlocks the session with the user's PIN. After too many wrong PINs the
+// session token is revoked and the user has to log in with their password again.
+func (s *PrivacyLockService) Unlock(userID uint, token, pin string) error {
+	cfg, err := s.getConfig(userID)
+	if err != nil {
+		return err
+	}
+	hash := s.authService.hashToken(token)
+
+	if cfg.pinHash == "" || bcrypt.CompareHashAndPassword([]byte(cfg.pinHash), []byte(pin)) == nil {
+		s.mu.Lock()
+		s.lastActivity[hash] = s.now()
+		delete(s.failures, hash)
+		s.mu.Unlock()
+		return nil
+	}
+
+	s.mu.Lock()
+	s.failures[hash]++
+	exceeded := s.failures[hash] >= maxPINAttempts
+	if exceeded {
+		delete(s.failures, hash)
+		delete(s.lastActivity, hash)
+	}
+	s.mu.Unlock()
+
+	if !exceeded {
+		return apperrors.ErrInvalidPIN
+	}
+
+	s.logger.Warn("Too many incorrect privacy PIN attempts, revoking session", zap.Uint("user_id", userID))
+	if err := s.authService.RevokeToken(token, "privacy lock PIN attempts exceeded"); err != nil {
+		s.logger.Error("Failed to revoke token after PIN attempts", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	return apperrors.ErrPINAttemptsExceeded
+}
+
+// Configure enables the privacy lock or updates it. An empty PIN keeps the
+// current PIN and only changes the idle timeout.
+func (s *PrivacyLockService) Configure(userID uint, currentPassword, pin string, idleTimeoutMinutes int) error {
+	if idleTimeoutMinutes < minPrivacyLockMinutes || idleTimeoutMinutes > maxPrivacyLockMinutes {
+		return apperrors.NewValidationErrorWithField("idle_timeout_minutes", "idle timeout must be between 1 and 1440 minutes")
+	}
+
+	user, err := s.verifyPassword(userID, currentPassword)
+	if err != nil {
+		return err
+	}
+
+	pinHash := user.PrivacyPinHash
+	if pin != "" {
+		if !validPIN(pin) {
+			return apperrors.NewValidationErrorWithField("pin", "PIN must be 4 to 8 digits")
+		}
+		hashed, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+		if err != nil {
+			return apperrors.NewInternalError("failed to hash PIN", err)
+		}
+		pinHash = string(hashed)
+	}
+	if pinHash == "" {
+		return apperrors.NewValidationErrorWithField("pin", "PIN is required")
+	}
+
+	if err := s.userRepo.UpdatePrivacyLock(userID, pinHash, idleTimeoutMinutes); err != nil {
+		return apperrors.NewInternalError("failed to update privacy lock", err)
+	}
+	s.invalidate(userID)
+
+	s.logger.Info("Privacy lock configured", zap.Uint("user_id", userID), zap.Int("idle_timeout_minutes", idleTimeoutMinutes))
+	return nil
+}
+
+// Disable turns the privacy lock off
+func (s *PrivacyLockService) Disable(userID uint, currentPassword string) error {
+	user, err := s.verifyPassword(userID, currentPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePrivacyLock(userID, "", user.PrivacyLockMinutes); err != nil {
+		return apperrors.NewInternalError("failed to update privacy lock", err)
+	}
+	s.invalidate(userID)
+
+	s.logger.Info("Privacy lock disabled", zap.Uint("user_id", userID))
+	return nil
+}
+
+func (s *PrivacyLockService) verifyPassword(userID uint, password string) (*data.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, apperrors.ErrUserNotFound(userID)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return nil, apperrors.NewValidationErrorWithField("current_password", "current password is incorrect")
+	}
+	return user, nil
+}
+
+// getConfig returns the user's lock configuration, loading it on first use
+func (s *PrivacyLockService) getConfig(userID uint) (privacyLockConfig, error) {
+	s.mu.Lock()
+	cfg, ok := s.configs[userID]
+	s.mu.Unlock()
+	if ok {
+		return cfg, nil
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return privacyLockConfig{}, apperrors.ErrUserNotFound(userID)
+	}
+	cfg = privacyLockConfig{
+		pinHash: user.PrivacyPinHash,
+		timeout: time.Duration(user.PrivacyLockMinutes) * time.Minute,
+	}
+
+	s.mu.Lock()
+	s.configs[userID] = cfg
+	s.mu.Unlock()
+	return cfg, nil
+}
+
+func (s *PrivacyLockService) invalidate(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.configs, userID)
+}
+
+// isLockedLocked reports whether the session is locked. Caller must hold s.mu.
+func (s *PrivacyLockService) isLockedLocked(cfg privacyLockConfig, tokenHash string) bool {
+	if cfg.pinHash == "" {
+		return false
+	}
+	last, ok := s.lastActivity[tokenHash]
+	return !ok || s.now().Sub(last) > cfg.timeout
+}
+
+func validPIN(pin string) bool {
+	if len(pin) < 4 || len(pin) > 8 {
+		return false
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
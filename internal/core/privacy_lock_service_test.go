@@ -0,0 +1,173 @@
+package core
+
+import (
+	"errors"
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestPrivacyLockService(t *testing.T) (*PrivacyLockService, *mocks.MockUserRepository, *mocks.MockRevokedTokenRepository) {
+	authSvc, userRepo, revokedRepo := newTestAuthService(t)
+	return NewPrivacyLockService(userRepo, authSvc, zap.NewNop()), userRepo, revokedRepo
+}
+
+func lockedUser(t *testing.T) *data.User {
+	return &data.User{
+		ID:                 1,
+		Username:           "alice",
+		Password:           hashPassword(t, "correctpass"),
+		PrivacyPinHash:     hashPassword(t, "1234"),
+		PrivacyLockMinutes: 15,
+	}
+}
+
+func TestPrivacyLock_DisabledNeverLocks(t *testing.T) {
+	svc, userRepo, _ := newTestPrivacyLockService(t)
+	userRepo.EXPECT().GetByID(uint(1)).Return(&data.User{ID: 1, PrivacyLockMinutes: 15}, nil)
+
+	if err := svc.Check(1, "token"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	status, err := svc.Status(1, "token")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.Enabled || status.Locked {
+		t.Fatalf("expected disabled and unlocked, got %+v", status)
+	}
+}
+
+func TestPrivacyLock_UnknownSessionIsLocked(t *testing.T) {
+	svc, userRepo, _ := newTestPrivacyLockService(t)
+	userRepo.EXPECT().GetByID(uint(1)).Return(lockedUser(t), nil)
+
+	if err := svc.Check(1, "token"); !errors.Is(err, apperrors.ErrPrivacyLocked) {
+		t.Fatalf("expected ErrPrivacyLocked, got %v", err)
+	}
+}
+
+func TestPrivacyLock_IdleTimeout(t *testing.T) {
+	svc, userRepo, _ := newTestPrivacyLockService(t)
+	userRepo.EXPECT().GetByID(uint(1)).Return(lockedUser(t), nil)
+
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	svc.StartSession("token")
+
+	now = now.Add(10 * time.Minute)
+	if err := svc.Check(1, "token"); err != nil {
+		t.Fatalf("expected session to be unlocked, got %v", err)
+	}
+
+	// Activity at +10m pushes the deadline to +25m
+	now = now.Add(14 * time.Minute)
+	if err := svc.Check(1, "token"); err != nil {
+		t.Fatalf("expected activity to extend the session, got %v", err)
+	}
+
+	now = now.Add(16 * time.Minute)
+	if err := svc.Check(1, "token"); !errors.Is(err, apperrors.ErrPrivacyLocked) {
+		t.Fatalf("expected ErrPrivacyLocked after idle timeout, got %v", err)
+	}
+
+	// Other sessions of the same user are tracked separately
+	svc.StartSession("other")
+	if err := svc.Check(1, "other"); err != nil {
+		t.Fatalf("expected other session to be unlocked, got %v", err)
+	}
+}
+
+func TestPrivacyLock_LockAndUnlock(t *testing.T) {
+	svc, userRepo, _ := newTestPrivacyLockService(t)
+	userRepo.EXPECT().GetByID(uint(1)).Return(lockedUser(t), nil)
+
+	svc.StartSession("token")
+	if err := svc.Lock(1, "token"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := svc.Check(1, "token"); !errors.Is(err, apperrors.ErrPrivacyLocked) {
+		t.Fatalf("expected ErrPrivacyLocked, got %v", err)
+	}
+
+	if err := svc.Unlock(1, "token", "0000"); !errors.Is(err, apperrors.ErrInvalidPIN) {
+		t.Fatalf("expected ErrInvalidPIN, got %v", err)
+	}
+	if err := svc.Unlock(1, "token", "1234"); err != nil {
+		t.Fatalf("expected unlock to succeed, got %v", err)
+	}
+	if err := svc.Check(1, "token"); err != nil {
+		t.Fatalf("expected unlocked session, got %v", err)
+	}
+}
+
+func TestPrivacyLock_TooManyAttemptsRevokesSession(t *testing.T) {
+	svc, userRepo, revokedRepo := newTestPrivacyLockService(t)
+	userRepo.EXPECT().GetByID(uint(1)).Return(lockedUser(t), nil)
+
+	token, err := svc.authService.generateToken(&data.User{ID: 1, Username: "alice", Role: "user"})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	revokedRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+	for i := 0; i < maxPINAttempts-1; i++ {
+		if err := svc.Unlock(1, token, "0000"); !errors.Is(err, apperrors.ErrInvalidPIN) {
+			t.Fatalf("attempt %d: expected ErrInvalidPIN, got %v", i+1, err)
+		}
+	}
+	if err := svc.Unlock(1, token, "0000"); !errors.Is(err, apperrors.ErrPINAttemptsExceeded) {
+		t.Fatalf("expected ErrPINAttemptsExceeded, got %v", err)
+	}
+}
+
+func TestPrivacyLock_Configure(t *testing.T) {
+	svc, userRepo, _ := newTestPrivacyLockService(t)
+	user := &data.User{ID: 1, Password: hashPassword(t, "correctpass"), PrivacyLockMinutes: 15}
+
+	userRepo.EXPECT().GetByID(uint(1)).Return(user, nil).AnyTimes()
+	userRepo.EXPECT().UpdatePrivacyLock(uint(1), gomock.Any(), 5).Return(nil)
+
+	if err := svc.Configure(1, "wrongpass", "1234", 5); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for wrong password, got %v", err)
+	}
+	if err := svc.Configure(1, "correctpass", "12ab", 5); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for non-digit PIN, got %v", err)
+	}
+	if err := svc.Configure(1, "correctpass", "1234", 0); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for zero timeout, got %v", err)
+	}
+	if err := svc.Configure(1, "correctpass", "", 5); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for missing PIN, got %v", err)
+	}
+	if err := svc.Configure(1, "correctpass", "1234", 5); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestPrivacyLock_ConfigureInvalidatesCache(t *testing.T) {
+	svc, userRepo, _ := newTestPrivacyLockService(t)
+	user := &data.User{ID: 1, Password: hashPassword(t, "correctpass"), PrivacyLockMinutes: 15}
+
+	gomock.InOrder(
+		userRepo.EXPECT().GetByID(uint(1)).Return(user, nil), // Check
+		userRepo.EXPECT().GetByID(uint(1)).Return(user, nil), // Configure
+		userRepo.EXPECT().UpdatePrivacyLock(uint(1), gomock.Any(), 15).Return(nil),
+		userRepo.EXPECT().GetByID(uint(1)).Return(lockedUser(t), nil), // reload after invalidation
+	)
+
+	if err := svc.Check(1, "token"); err != nil {
+		t.Fatalf("expected no error while disabled, got %v", err)
+	}
+	if err := svc.Configure(1, "correctpass", "1234", 15); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := svc.Check(1, "token"); !errors.Is(err, apperrors.ErrPrivacyLocked) {
+		t.Fatalf("expected new configuration to apply, got %v", err)
+	}
+}
@@ -29,17 +29,32 @@ type JobHistoryRecorder interface {
 // JobQueueRecorder extends JobHistoryRecorder with DB-backed queue methods
 type JobQueueRecorder interface {
 	JobHistoryRecorder
-	// CreatePendingJob creates a job with status='pending' in the database
+	// CreatePendingJob creates a job with status='pending' in the database, or
+	// returns data.ErrActiveJobExists when the scene has one of the phase with
+	// the same parameters pending or running
 	CreatePendingJob(jobID string, sceneID uint, sceneTitle string, phase string, forceTarget string) error
 	// CreatePendingJobWithPriority creates a pending job with a specific priority (higher = processed first)
 	CreatePendingJobWithPriority(jobID string, sceneID uint, sceneTitle string, phase string, priority int, forceTarget string) error
 	// CreatePendingJobWithRetry creates a pending job with retry tracking information
 	CreatePendingJobWithRetry(jobID string, sceneID uint, sceneTitle string, phase string, retryCount, maxRetries int, forceTarget string) error
-	// ExistsPendingOrRunning checks if a pending or running job exists for scene+phase
-	ExistsPendingOrRunning(sceneID uint, phase string) (bool, error)
 }
 
 // SceneIndexer handles search index updates for scenes
 type SceneIndexer interface {
 	UpdateSceneIndex(scene *data.Scene) error
 }
+
+// DuplicateFlagger groups scenes whose files have the same checksum
+type DuplicateFlagger interface {
+	FlagFileHashMatch(sceneID uint, fileHash string) (*data.DuplicateGroup, error)
+}
+
+// JobLogRecorder stores the log of a finished job run
+type JobLogRecorder interface {
+	RecordJobLog(jobID string, content string, truncated bool)
+}
+
+// ArtifactRecorder records the disk size of artifacts a completed phase wrote
+type ArtifactRecorder interface {
+	RecordPhaseComplete(sceneID uint, phase string)
+}
@@ -1,6 +1,7 @@
 package processing
 
 import (
+	"errors"
 	"fmt"
 	"goonhub/internal/data"
 
@@ -44,6 +45,16 @@ func (js *JobSubmitter) SubmitScene(sceneID uint, scenePath string) error {
 		zap.String("scene_path", scenePath),
 	)
 
+	// Checksums are independent of metadata and only computed when opted in
+	if checksumTrigger := js.phaseTracker.GetTriggerForPhase("checksum"); checksumTrigger != nil && checksumTrigger.TriggerType == "on_import" {
+		if err := js.createPendingJob(sceneID, "checksum"); err != nil {
+			js.logger.Warn("Failed to submit checksum job",
+				zap.Uint("scene_id", sceneID),
+				zap.Error(err),
+			)
+		}
+	}
+
 	// Check if metadata trigger is on_import
 	metaTrigger := js.phaseTracker.GetTriggerForPhase("metadata")
 	if metaTrigger != nil && metaTrigger.TriggerType != "on_import" {
@@ -67,7 +78,7 @@ func (js *JobSubmitter) SubmitPhase(sceneID uint, phase string) error {
 // Used for manual triggers and DLQ retries.
 func (js *JobSubmitter) SubmitPhaseWithPriority(sceneID uint, phase string, priority int) error {
 	switch phase {
-	case "metadata", "thumbnail", "sprites", "animated_thumbnails":
+	case "metadata", "thumbnail", "sprites", "animated_thumbnails", "verify", "checksum":
 	default:
 		return fmt.Errorf("unknown phase: %s", phase)
 	}
@@ -89,7 +100,7 @@ func (js *JobSubmitter) SubmitPhaseWithPriority(sceneID uint, phase string, prio
 // Used for manual per-scene triggers where force regeneration is requested.
 func (js *JobSubmitter) SubmitPhaseWithForce(sceneID uint, phase string, priority int, forceTarget string) error {
 	switch phase {
-	case "metadata", "thumbnail", "sprites", "animated_thumbnails":
+	case "metadata", "thumbnail", "sprites", "animated_thumbnails", "verify", "checksum":
 	default:
 		return fmt.Errorf("unknown phase: %s", phase)
 	}
@@ -114,7 +125,7 @@ func (js *JobSubmitter) SubmitPhaseWithForce(sceneID uint, phase string, priorit
 func (js *JobSubmitter) SubmitPhaseWithRetry(sceneID uint, phase string, retryCount, maxRetries int) error {
 	// Validate the phase
 	switch phase {
-	case "metadata", "thumbnail", "sprites", "animated_thumbnails":
+	case "metadata", "thumbnail", "sprites", "animated_thumbnails", "verify", "checksum":
 		// Valid phases
 	default:
 		return fmt.Errorf("unknown phase: %s", phase)
@@ -151,24 +162,6 @@ func (js *JobSubmitter) createPendingJobWithRetry(sceneID uint, phase string, re
 		return fmt.Errorf("job queue recorder not configured")
 	}
 
-	// Check for deduplication: skip if there's already a pending or running job
-	exists, err := js.jobQueue.ExistsPendingOrRunning(sceneID, phase)
-	if err != nil {
-		js.logger.Error("Failed to check for existing job",
-			zap.Uint("scene_id", sceneID),
-			zap.String("phase", phase),
-			zap.Error(err),
-		)
-		return fmt.Errorf("failed to check for existing job: %w", err)
-	}
-	if exists {
-		js.logger.Debug("Job already pending or running, skipping",
-			zap.Uint("scene_id", sceneID),
-			zap.String("phase", phase),
-		)
-		return nil
-	}
-
 	// Get scene title for the job record
 	sceneTitle := ""
 	if s, err := js.repo.GetByID(sceneID); err == nil {
@@ -178,7 +171,12 @@ func (js *JobSubmitter) createPendingJobWithRetry(sceneID uint, phase string, re
 	// Generate a new job ID
 	jobID := uuid.New().String()
 
-	if createErr := js.jobQueue.CreatePendingJobWithRetry(jobID, sceneID, sceneTitle, phase, retryCount, maxRetries, ""); createErr != nil {
+	createErr := js.jobQueue.CreatePendingJobWithRetry(jobID, sceneID, sceneTitle, phase, retryCount, maxRetries, "")
+	if errors.Is(createErr, data.ErrActiveJobExists) {
+		js.logSkippedDuplicate(sceneID, phase)
+		return nil
+	}
+	if createErr != nil {
 		js.logger.Error("Failed to create pending job with retry info",
 			zap.String("job_id", jobID),
 			zap.Uint("scene_id", sceneID),
@@ -207,24 +205,6 @@ func (js *JobSubmitter) createPendingJobWithPriority(sceneID uint, phase string,
 		return fmt.Errorf("job queue recorder not configured")
 	}
 
-	// Check for deduplication: skip if there's already a pending or running job
-	exists, err := js.jobQueue.ExistsPendingOrRunning(sceneID, phase)
-	if err != nil {
-		js.logger.Error("Failed to check for existing job",
-			zap.Uint("scene_id", sceneID),
-			zap.String("phase", phase),
-			zap.Error(err),
-		)
-		return fmt.Errorf("failed to check for existing job: %w", err)
-	}
-	if exists {
-		js.logger.Debug("Job already pending or running, skipping",
-			zap.Uint("scene_id", sceneID),
-			zap.String("phase", phase),
-		)
-		return nil
-	}
-
 	// Get scene title for the job record
 	sceneTitle := ""
 	if s, err := js.repo.GetByID(sceneID); err == nil {
@@ -241,6 +221,10 @@ func (js *JobSubmitter) createPendingJobWithPriority(sceneID uint, phase string,
 	} else {
 		createErr = js.jobQueue.CreatePendingJob(jobID, sceneID, sceneTitle, phase, forceTarget)
 	}
+	if errors.Is(createErr, data.ErrActiveJobExists) {
+		js.logSkippedDuplicate(sceneID, phase)
+		return nil
+	}
 	if createErr != nil {
 		js.logger.Error("Failed to create pending job",
 			zap.String("job_id", jobID),
@@ -260,37 +244,29 @@ func (js *JobSubmitter) createPendingJobWithPriority(sceneID uint, phase string,
 	return nil
 }
 
+// logSkippedDuplicate notes a submission the database turned down because the
+// scene already has the same job pending or running
+func (js *JobSubmitter) logSkippedDuplicate(sceneID uint, phase string) {
+	js.logger.Debug("Job already pending or running, skipping",
+		zap.Uint("scene_id", sceneID),
+		zap.String("phase", phase),
+	)
+}
+
 // SubmitBulkPhase submits a processing phase for multiple scenes
 // mode can be "missing" (only scenes needing the phase) or "all" (all scenes)
 // forceTarget is only used for animated_thumbnails phase to control what gets regenerated
 // sceneIDs optionally scopes the operation to specific scenes (nil = all scenes)
 func (js *JobSubmitter) SubmitBulkPhase(phase string, mode string, forceTarget string, sceneIDs []uint) (*BulkPhaseResult, error) {
-	var scenes []data.Scene
-	var err error
-
-	if len(sceneIDs) > 0 {
-		scenes, err = js.repo.GetByIDs(sceneIDs)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get scenes by IDs: %w", err)
-		}
-	} else if mode == "all" {
-		scenes, err = js.repo.GetAll()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get scenes: %w", err)
-		}
-	} else {
-		// Default to "missing" mode
-		scenes, err = js.repo.GetScenesNeedingPhase(phase)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get scenes needing %s: %w", phase, err)
-		}
+	scenes, err := js.bulkPhaseCandidates(phase, mode, sceneIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	result := &BulkPhaseResult{}
 
 	for _, scene := range scenes {
-		// For thumbnail/sprites/animated_thumbnails in "all" mode, skip scenes without metadata
-		if mode == "all" && (phase == "thumbnail" || phase == "sprites" || phase == "animated_thumbnails") && scene.Duration == 0 {
+		if skipBulkPhaseScene(phase, mode, scene) {
 			result.Skipped++
 			continue
 		}
@@ -323,3 +299,61 @@ func (js *JobSubmitter) SubmitBulkPhase(phase string, mode string, forceTarget s
 
 	return result, nil
 }
+
+// PreviewBulkPhase resolves the scenes SubmitBulkPhase would enqueue for the same
+// arguments and estimates the work involved, without creating any jobs.
+func (js *JobSubmitter) PreviewBulkPhase(phase string, mode string, sceneIDs []uint) (*BulkPhasePreview, error) {
+	scenes, err := js.bulkPhaseCandidates(phase, mode, sceneIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := js.poolManager.GetConfig()
+	qualityConfig := js.poolManager.GetQualityConfig()
+
+	preview := &BulkPhasePreview{SceneIDs: []uint{}}
+	for _, scene := range scenes {
+		if skipBulkPhaseScene(phase, mode, scene) {
+			preview.Skipped++
+			continue
+		}
+		preview.SceneIDs = append(preview.SceneIDs, scene.ID)
+		preview.TotalDuration += scene.Duration
+		preview.EstimatedBytes += estimatePhaseOutputBytes(phase, scene, cfg, qualityConfig)
+	}
+	preview.SceneCount = len(preview.SceneIDs)
+
+	return preview, nil
+}
+
+// bulkPhaseCandidates loads the scenes a bulk phase operation applies to
+func (js *JobSubmitter) bulkPhaseCandidates(phase string, mode string, sceneIDs []uint) ([]data.Scene, error) {
+	if len(sceneIDs) > 0 {
+		scenes, err := js.repo.GetByIDs(sceneIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get scenes by IDs: %w", err)
+		}
+		return scenes, nil
+	}
+
+	if mode == "all" {
+		scenes, err := js.repo.GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get scenes: %w", err)
+		}
+		return scenes, nil
+	}
+
+	// Default to "missing" mode
+	scenes, err := js.repo.GetScenesNeedingPhase(phase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scenes needing %s: %w", phase, err)
+	}
+	return scenes, nil
+}
+
+// skipBulkPhaseScene reports whether a scene is skipped by a bulk operation.
+// In "all" mode, frame-based phases skip scenes without metadata.
+func skipBulkPhaseScene(phase string, mode string, scene data.Scene) bool {
+	return mode == "all" && (phase == "thumbnail" || phase == "sprites" || phase == "animated_thumbnails") && scene.Duration == 0
+}
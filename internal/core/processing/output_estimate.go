@@ -0,0 +1,51 @@
+package processing
+
+import (
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/pkg/ffmpeg"
+)
+
+// previewBytesPerSecond approximates the size of one second of encoded scene preview video
+const previewBytesPerSecond = 100 * 1024
+
+// webpBytesPerPixel approximates the size of a WebP video frame per pixel at the given
+// quality. It is a ballpark figure for dry-run previews, not an exact prediction.
+func webpBytesPerPixel(quality int) float64 {
+	return 0.02 + 0.002*float64(quality)
+}
+
+// estimatePhaseOutputBytes roughly estimates the disk space a phase writes for a scene.
+// Metadata and verify only update database rows and report zero.
+func estimatePhaseOutputBytes(phase string, scene data.Scene, cfg config.ProcessingConfig, quality QualityConfig) int64 {
+	switch phase {
+	case "thumbnail":
+		smW, smH := scene.ThumbnailWidth, scene.ThumbnailHeight
+		if smW == 0 || smH == 0 {
+			smW, smH = ffmpeg.CalculateTileDimensions(scene.Width, scene.Height, quality.MaxFrameDimensionSm)
+		}
+		lgW, lgH := ffmpeg.CalculateTileDimensions(scene.Width, scene.Height, cfg.MaxFrameDimensionLarge)
+		return int64(float64(smW*smH)*webpBytesPerPixel(quality.FrameQualitySm) +
+			float64(lgW*lgH)*webpBytesPerPixel(quality.FrameQualityLg))
+
+	case "sprites":
+		if cfg.FrameInterval <= 0 {
+			return 0
+		}
+		tileW, tileH := scene.ThumbnailWidth, scene.ThumbnailHeight
+		if tileW == 0 || tileH == 0 {
+			tileW, tileH = ffmpeg.CalculateTileDimensions(scene.Width, scene.Height, quality.MaxFrameDimensionSm)
+		}
+		frames := (scene.Duration + cfg.FrameInterval - 1) / cfg.FrameInterval
+		return int64(float64(frames*tileW*tileH) * webpBytesPerPixel(quality.FrameQualitySprites))
+
+	case "animated_thumbnails":
+		if !quality.ScenePreviewEnabled {
+			return 0
+		}
+		seconds := float64(quality.ScenePreviewSegments) * quality.ScenePreviewSegmentDuration
+		return int64(seconds * previewBytesPerSecond)
+	}
+
+	return 0
+}
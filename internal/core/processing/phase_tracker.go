@@ -100,6 +100,10 @@ func (pt *PhaseTracker) MarkPhaseComplete(sceneID uint, phase string) {
 		state.SpritesDone = true
 	case "animated_thumbnails":
 		state.AnimatedThumbnailsDone = true
+	case "verify":
+		state.VerifyDone = true
+	case "checksum":
+		state.ChecksumDone = true
 	}
 }
 
@@ -127,6 +131,8 @@ func (pt *PhaseTracker) CheckAllPhasesComplete(sceneID uint, completedPhase stri
 	thumbnailInPipeline := false
 	spritesInPipeline := false
 	animatedThumbnailsInPipeline := false
+	verifyInPipeline := false
+	checksumInPipeline := false
 	for _, p := range phasesAfterMeta {
 		if p == "thumbnail" {
 			thumbnailInPipeline = true
@@ -137,14 +143,22 @@ func (pt *PhaseTracker) CheckAllPhasesComplete(sceneID uint, completedPhase stri
 		if p == "animated_thumbnails" {
 			animatedThumbnailsInPipeline = true
 		}
+		if p == "verify" {
+			verifyInPipeline = true
+		}
+		if p == "checksum" {
+			checksumInPipeline = true
+		}
 	}
 
 	// Check completion: only phases in the pipeline matter
 	thumbnailReady := !thumbnailInPipeline || state.ThumbnailDone
 	spritesReady := !spritesInPipeline || state.SpritesDone
 	animatedThumbnailsReady := !animatedThumbnailsInPipeline || state.AnimatedThumbnailsDone
+	verifyReady := !verifyInPipeline || state.VerifyDone
+	checksumReady := !checksumInPipeline || state.ChecksumDone
 
-	if thumbnailReady && spritesReady && animatedThumbnailsReady {
+	if thumbnailReady && spritesReady && animatedThumbnailsReady && verifyReady && checksumReady {
 		pt.ClearPhaseState(sceneID)
 		return true
 	}
@@ -20,6 +20,9 @@ type PoolManager struct {
 	thumbnailPool           *jobs.WorkerPool
 	spritesPool             *jobs.WorkerPool
 	animatedThumbnailsPool  *jobs.WorkerPool
+	verifyPool              *jobs.WorkerPool
+	checksumPool            *jobs.WorkerPool
+	processLimiter          *jobs.ProcessLimiter // shared by all pools; nil = unlimited
 	mu                      sync.RWMutex
 	config                  config.ProcessingConfig
 	qualityConfig           QualityConfig
@@ -27,6 +30,9 @@ type PoolManager struct {
 
 	// resultHandler is called when a job completes
 	resultHandler func(*jobs.WorkerPool)
+
+	// remoteExecutor is attached to remote-capable jobs on submit (nil = always run locally)
+	remoteExecutor jobs.RemoteExecutor
 }
 
 // NewPoolManager creates a new PoolManager with the given configuration
@@ -44,6 +50,14 @@ func NewPoolManager(
 	if animatedThumbnailsWorkers <= 0 {
 		animatedThumbnailsWorkers = 1
 	}
+	verifyWorkers := cfg.VerifyWorkers
+	if verifyWorkers <= 0 {
+		verifyWorkers = 1
+	}
+	checksumWorkers := cfg.ChecksumWorkers
+	if checksumWorkers <= 0 {
+		checksumWorkers = 1
+	}
 
 	if poolConfigRepo != nil {
 		if dbConfig, err := poolConfigRepo.Get(); err == nil && dbConfig != nil {
@@ -53,11 +67,19 @@ func NewPoolManager(
 			if dbConfig.AnimatedThumbnailsWorkers > 0 {
 				animatedThumbnailsWorkers = dbConfig.AnimatedThumbnailsWorkers
 			}
+			if dbConfig.VerifyWorkers > 0 {
+				verifyWorkers = dbConfig.VerifyWorkers
+			}
+			if dbConfig.ChecksumWorkers > 0 {
+				checksumWorkers = dbConfig.ChecksumWorkers
+			}
 			logger.Info("Loaded pool config from database",
 				zap.Int("metadata_workers", metadataWorkers),
 				zap.Int("thumbnail_workers", thumbnailWorkers),
 				zap.Int("sprites_workers", spritesWorkers),
 				zap.Int("animated_thumbnails_workers", animatedThumbnailsWorkers),
+				zap.Int("verify_workers", verifyWorkers),
+				zap.Int("checksum_workers", checksumWorkers),
 			)
 		}
 	}
@@ -156,6 +178,8 @@ func NewPoolManager(
 		zap.Int("metadata_workers", metadataWorkers),
 		zap.Int("thumbnail_workers", thumbnailWorkers),
 		zap.Int("sprites_workers", spritesWorkers),
+		zap.Int("verify_workers", verifyWorkers),
+		zap.Int("checksum_workers", checksumWorkers),
 		zap.Int("frame_interval", cfg.FrameInterval),
 		zap.Int("max_frame_dimension_sm", qualityConfig.MaxFrameDimensionSm),
 		zap.Int("max_frame_dimension_lg", qualityConfig.MaxFrameDimensionLg),
@@ -171,8 +195,15 @@ func NewPoolManager(
 
 	const queueBufferSize = 1000
 
+	// One limiter for every pool, so resizing a pool cannot oversubscribe the CPU
+	processLimiter := jobs.NewProcessLimiter(cfg.MaxFFmpegProcesses)
+	if processLimiter != nil {
+		logger.Info("Global ffmpeg process limit set", zap.Int("max_processes", cfg.MaxFFmpegProcesses))
+	}
+
 	metadataPool := jobs.NewWorkerPool(metadataWorkers, queueBufferSize)
 	metadataPool.SetLogger(logger.With(zap.String("pool", "metadata")))
+	metadataPool.SetProcessLimiter(processLimiter)
 	if cfg.MetadataTimeout > 0 {
 		metadataPool.SetTimeout(cfg.MetadataTimeout)
 		logger.Info("Metadata pool timeout set", zap.Duration("timeout", cfg.MetadataTimeout))
@@ -180,6 +211,7 @@ func NewPoolManager(
 
 	thumbnailPool := jobs.NewWorkerPool(thumbnailWorkers, queueBufferSize)
 	thumbnailPool.SetLogger(logger.With(zap.String("pool", "thumbnail")))
+	thumbnailPool.SetProcessLimiter(processLimiter)
 	if cfg.ThumbnailTimeout > 0 {
 		thumbnailPool.SetTimeout(cfg.ThumbnailTimeout)
 		logger.Info("Thumbnail pool timeout set", zap.Duration("timeout", cfg.ThumbnailTimeout))
@@ -187,6 +219,7 @@ func NewPoolManager(
 
 	spritesPool := jobs.NewWorkerPool(spritesWorkers, queueBufferSize)
 	spritesPool.SetLogger(logger.With(zap.String("pool", "sprites")))
+	spritesPool.SetProcessLimiter(processLimiter)
 	if cfg.SpritesTimeout > 0 {
 		spritesPool.SetTimeout(cfg.SpritesTimeout)
 		logger.Info("Sprites pool timeout set", zap.Duration("timeout", cfg.SpritesTimeout))
@@ -194,11 +227,28 @@ func NewPoolManager(
 
 	animatedThumbnailsPool := jobs.NewWorkerPool(animatedThumbnailsWorkers, queueBufferSize)
 	animatedThumbnailsPool.SetLogger(logger.With(zap.String("pool", "animated_thumbnails")))
+	animatedThumbnailsPool.SetProcessLimiter(processLimiter)
 	if cfg.AnimatedThumbnailsTimeout > 0 {
 		animatedThumbnailsPool.SetTimeout(cfg.AnimatedThumbnailsTimeout)
 		logger.Info("Animated thumbnails pool timeout set", zap.Duration("timeout", cfg.AnimatedThumbnailsTimeout))
 	}
 
+	verifyPool := jobs.NewWorkerPool(verifyWorkers, queueBufferSize)
+	verifyPool.SetLogger(logger.With(zap.String("pool", "verify")))
+	verifyPool.SetProcessLimiter(processLimiter)
+	if cfg.VerifyTimeout > 0 {
+		verifyPool.SetTimeout(cfg.VerifyTimeout)
+		logger.Info("Verify pool timeout set", zap.Duration("timeout", cfg.VerifyTimeout))
+	}
+
+	// Checksum jobs only read the file, so they don't take an ffmpeg process slot
+	checksumPool := jobs.NewWorkerPool(checksumWorkers, queueBufferSize)
+	checksumPool.SetLogger(logger.With(zap.String("pool", "checksum")))
+	if cfg.ChecksumTimeout > 0 {
+		checksumPool.SetTimeout(cfg.ChecksumTimeout)
+		logger.Info("Checksum pool timeout set", zap.Duration("timeout", cfg.ChecksumTimeout))
+	}
+
 	// Create output directories
 	createDirIfNotExists(cfg.SpriteDir, logger)
 	createDirIfNotExists(cfg.VttDir, logger)
@@ -211,6 +261,9 @@ func NewPoolManager(
 		thumbnailPool:          thumbnailPool,
 		spritesPool:            spritesPool,
 		animatedThumbnailsPool: animatedThumbnailsPool,
+		verifyPool:             verifyPool,
+		checksumPool:           checksumPool,
+		processLimiter:         processLimiter,
 		config:                 cfg,
 		qualityConfig:          qualityConfig,
 		logger:                 logger,
@@ -233,6 +286,13 @@ func (pm *PoolManager) SetResultHandler(handler func(*jobs.WorkerPool)) {
 	pm.resultHandler = handler
 }
 
+// SetRemoteExecutor sets the executor used to dispatch remote-capable jobs to agents
+func (pm *PoolManager) SetRemoteExecutor(executor jobs.RemoteExecutor) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.remoteExecutor = executor
+}
+
 // Start starts all worker pools and their result handlers
 func (pm *PoolManager) Start() {
 	pm.migrateOldThumbnails()
@@ -241,12 +301,16 @@ func (pm *PoolManager) Start() {
 	pm.thumbnailPool.Start()
 	pm.spritesPool.Start()
 	pm.animatedThumbnailsPool.Start()
+	pm.verifyPool.Start()
+	pm.checksumPool.Start()
 
 	if pm.resultHandler != nil {
 		go pm.resultHandler(pm.metadataPool)
 		go pm.resultHandler(pm.thumbnailPool)
 		go pm.resultHandler(pm.spritesPool)
 		go pm.resultHandler(pm.animatedThumbnailsPool)
+		go pm.resultHandler(pm.verifyPool)
+		go pm.resultHandler(pm.checksumPool)
 	}
 
 	pm.logger.Info("Pool manager started",
@@ -254,6 +318,8 @@ func (pm *PoolManager) Start() {
 		zap.Int("thumbnail_workers", pm.thumbnailPool.ActiveWorkers()),
 		zap.Int("sprites_workers", pm.spritesPool.ActiveWorkers()),
 		zap.Int("animated_thumbnails_workers", pm.animatedThumbnailsPool.ActiveWorkers()),
+		zap.Int("verify_workers", pm.verifyPool.ActiveWorkers()),
+		zap.Int("checksum_workers", pm.checksumPool.ActiveWorkers()),
 	)
 }
 
@@ -264,6 +330,8 @@ func (pm *PoolManager) Stop() {
 	pm.thumbnailPool.Stop()
 	pm.spritesPool.Stop()
 	pm.animatedThumbnailsPool.Stop()
+	pm.verifyPool.Stop()
+	pm.checksumPool.Stop()
 }
 
 // GracefulStop performs graceful shutdown of all worker pools.
@@ -286,7 +354,7 @@ func (pm *PoolManager) GracefulStop(timeout time.Duration) map[string][]string {
 		phase  string
 		jobIDs []string
 	}
-	resultChan := make(chan poolResult, 4)
+	resultChan := make(chan poolResult, 6)
 
 	// Gracefully stop all pools in parallel
 	go func() {
@@ -305,9 +373,17 @@ func (pm *PoolManager) GracefulStop(timeout time.Duration) map[string][]string {
 		jobIDs := pm.animatedThumbnailsPool.GracefulStop(timeout)
 		resultChan <- poolResult{phase: "animated_thumbnails", jobIDs: jobIDs}
 	}()
+	go func() {
+		jobIDs := pm.verifyPool.GracefulStop(timeout)
+		resultChan <- poolResult{phase: "verify", jobIDs: jobIDs}
+	}()
+	go func() {
+		jobIDs := pm.checksumPool.GracefulStop(timeout)
+		resultChan <- poolResult{phase: "checksum", jobIDs: jobIDs}
+	}()
 
 	// Collect results
-	for i := 0; i < 4; i++ {
+	for i := 0; i < 6; i++ {
 		res := <-resultChan
 		if len(res.jobIDs) > 0 {
 			result[res.phase] = res.jobIDs
@@ -325,6 +401,8 @@ func (pm *PoolManager) GracefulStop(timeout time.Duration) map[string][]string {
 		zap.Int("thumbnail_reclaimed", len(result["thumbnail"])),
 		zap.Int("sprites_reclaimed", len(result["sprites"])),
 		zap.Int("animated_thumbnails_reclaimed", len(result["animated_thumbnails"])),
+		zap.Int("verify_reclaimed", len(result["verify"])),
+		zap.Int("checksum_reclaimed", len(result["checksum"])),
 	)
 
 	return result
@@ -371,6 +449,8 @@ func (pm *PoolManager) GetPoolConfig() PoolConfig {
 		ThumbnailWorkers:          pm.thumbnailPool.ActiveWorkers(),
 		SpritesWorkers:            pm.spritesPool.ActiveWorkers(),
 		AnimatedThumbnailsWorkers: pm.animatedThumbnailsPool.ActiveWorkers(),
+		VerifyWorkers:             pm.verifyPool.ActiveWorkers(),
+		ChecksumWorkers:           pm.checksumPool.ActiveWorkers(),
 	}
 }
 
@@ -378,15 +458,23 @@ func (pm *PoolManager) GetPoolConfig() PoolConfig {
 func (pm *PoolManager) GetQueueStatus() QueueStatus {
 	pm.mu.RLock()
 	defer pm.mu.RUnlock()
+	limiterStats := pm.processLimiter.Stats()
 	return QueueStatus{
 		MetadataQueued:           pm.metadataPool.QueueSize(),
 		ThumbnailQueued:          pm.thumbnailPool.QueueSize(),
 		SpritesQueued:            pm.spritesPool.QueueSize(),
 		AnimatedThumbnailsQueued: pm.animatedThumbnailsPool.QueueSize(),
+		VerifyQueued:             pm.verifyPool.QueueSize(),
+		ChecksumQueued:           pm.checksumPool.QueueSize(),
 		MetadataActive:           pm.metadataPool.ActiveJobCount(),
 		ThumbnailActive:          pm.thumbnailPool.ActiveJobCount(),
 		SpritesActive:            pm.spritesPool.ActiveJobCount(),
 		AnimatedThumbnailsActive: pm.animatedThumbnailsPool.ActiveJobCount(),
+		VerifyActive:             pm.verifyPool.ActiveJobCount(),
+		ChecksumActive:           pm.checksumPool.ActiveJobCount(),
+		FFmpegRunning:            limiterStats.InUse,
+		FFmpegWaiting:            limiterStats.Waiting,
+		FFmpegLimit:              limiterStats.Limit,
 	}
 }
 
@@ -483,77 +571,84 @@ func (pm *PoolManager) UpdatePoolConfig(cfg PoolConfig) error {
 	if cfg.AnimatedThumbnailsWorkers < 1 || cfg.AnimatedThumbnailsWorkers > 10 {
 		return fmt.Errorf("animated_thumbnails_workers must be between 1 and 10")
 	}
+	if cfg.VerifyWorkers < 1 || cfg.VerifyWorkers > 10 {
+		return fmt.Errorf("verify_workers must be between 1 and 10")
+	}
+	if cfg.ChecksumWorkers < 1 || cfg.ChecksumWorkers > 10 {
+		return fmt.Errorf("checksum_workers must be between 1 and 10")
+	}
 
-	pm.mu.Lock()
-	defer pm.mu.Unlock()
-
-	const queueBufferSize = 1000
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
 
-	// Resize metadata pool if needed
-	if cfg.MetadataWorkers != pm.metadataPool.ActiveWorkers() {
-		newPool := jobs.NewWorkerPool(cfg.MetadataWorkers, queueBufferSize)
-		newPool.SetLogger(pm.logger.With(zap.String("pool", "metadata")))
-		newPool.Start()
-		if pm.resultHandler != nil {
-			go pm.resultHandler(newPool)
+	// Pools are resized in place, so running and queued jobs carry on
+	sizes := map[string]int{
+		"metadata":            cfg.MetadataWorkers,
+		"thumbnail":           cfg.ThumbnailWorkers,
+		"sprites":             cfg.SpritesWorkers,
+		"animated_thumbnails": cfg.AnimatedThumbnailsWorkers,
+		"verify":              cfg.VerifyWorkers,
+		"checksum":            cfg.ChecksumWorkers,
+	}
+	for _, p := range pm.pools() {
+		if err := p.pool.Resize(sizes[p.phase]); err != nil {
+			return fmt.Errorf("%s pool: %w", p.phase, err)
 		}
-
-		oldPool := pm.metadataPool
-		pm.metadataPool = newPool
-		oldPool.Stop()
-
-		pm.logger.Info("Resized metadata pool", zap.Int("workers", cfg.MetadataWorkers))
 	}
 
-	// Resize thumbnail pool if needed
-	if cfg.ThumbnailWorkers != pm.thumbnailPool.ActiveWorkers() {
-		newPool := jobs.NewWorkerPool(cfg.ThumbnailWorkers, queueBufferSize)
-		newPool.SetLogger(pm.logger.With(zap.String("pool", "thumbnail")))
-		newPool.Start()
-		if pm.resultHandler != nil {
-			go pm.resultHandler(newPool)
-		}
+	return nil
+}
+
+// phasePool is a worker pool and the phase it runs
+type phasePool struct {
+	phase string
+	pool  *jobs.WorkerPool
+}
 
-		oldPool := pm.thumbnailPool
-		pm.thumbnailPool = newPool
-		oldPool.Stop()
+// pools returns every pool with its phase. The caller holds mu.
+func (pm *PoolManager) pools() []phasePool {
+	return []phasePool{
+		{"metadata", pm.metadataPool},
+		{"thumbnail", pm.thumbnailPool},
+		{"sprites", pm.spritesPool},
+		{"animated_thumbnails", pm.animatedThumbnailsPool},
+		{"verify", pm.verifyPool},
+		{"checksum", pm.checksumPool},
+	}
+}
 
-		pm.logger.Info("Resized thumbnail pool", zap.Int("workers", cfg.ThumbnailWorkers))
+// ResizePool sets the number of workers of a phase's pool in place. Retired
+// workers finish their current job first.
+func (pm *PoolManager) ResizePool(phase string, workers int) error {
+	if workers < 1 || workers > 10 {
+		return fmt.Errorf("workers must be between 1 and 10")
 	}
 
-	// Resize sprites pool if needed
-	if cfg.SpritesWorkers != pm.spritesPool.ActiveWorkers() {
-		newPool := jobs.NewWorkerPool(cfg.SpritesWorkers, queueBufferSize)
-		newPool.SetLogger(pm.logger.With(zap.String("pool", "sprites")))
-		newPool.Start()
-		if pm.resultHandler != nil {
-			go pm.resultHandler(newPool)
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	for _, p := range pm.pools() {
+		if p.phase == phase {
+			return p.pool.Resize(workers)
 		}
-
-		oldPool := pm.spritesPool
-		pm.spritesPool = newPool
-		oldPool.Stop()
-
-		pm.logger.Info("Resized sprites pool", zap.Int("workers", cfg.SpritesWorkers))
 	}
+	return fmt.Errorf("unknown pool: %s", phase)
+}
 
-	// Resize animated thumbnails pool if needed
-	if cfg.AnimatedThumbnailsWorkers != pm.animatedThumbnailsPool.ActiveWorkers() {
-		newPool := jobs.NewWorkerPool(cfg.AnimatedThumbnailsWorkers, queueBufferSize)
-		newPool.SetLogger(pm.logger.With(zap.String("pool", "animated_thumbnails")))
-		newPool.Start()
-		if pm.resultHandler != nil {
-			go pm.resultHandler(newPool)
+// GetPoolLoads returns the size and load of every pool
+func (pm *PoolManager) GetPoolLoads() []PoolLoad {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	pools := pm.pools()
+	loads := make([]PoolLoad, len(pools))
+	for i, p := range pools {
+		loads[i] = PoolLoad{
+			Phase:   p.phase,
+			Workers: p.pool.ActiveWorkers(),
+			Queued:  p.pool.QueueSize(),
+			Active:  p.pool.ActiveJobCount(),
 		}
-
-		oldPool := pm.animatedThumbnailsPool
-		pm.animatedThumbnailsPool = newPool
-		oldPool.Stop()
-
-		pm.logger.Info("Resized animated thumbnails pool", zap.Int("workers", cfg.AnimatedThumbnailsWorkers))
 	}
-
-	return nil
+	return loads
 }
 
 // CancelJob cancels a running job by its ID. It searches all pools.
@@ -581,6 +676,16 @@ func (pm *PoolManager) CancelJob(jobID string) error {
 		return nil
 	}
 
+	if err := pm.verifyPool.CancelJob(jobID); err == nil {
+		pm.logger.Info("Job cancelled in verify pool", zap.String("job_id", jobID))
+		return nil
+	}
+
+	if err := pm.checksumPool.CancelJob(jobID); err == nil {
+		pm.logger.Info("Job cancelled in checksum pool", zap.String("job_id", jobID))
+		return nil
+	}
+
 	return fmt.Errorf("job not found: %s", jobID)
 }
 
@@ -601,6 +706,12 @@ func (pm *PoolManager) GetJob(jobID string) (jobs.Job, bool) {
 	if job, ok := pm.animatedThumbnailsPool.GetJob(jobID); ok {
 		return job, true
 	}
+	if job, ok := pm.verifyPool.GetJob(jobID); ok {
+		return job, true
+	}
+	if job, ok := pm.checksumPool.GetJob(jobID); ok {
+		return job, true
+	}
 	return nil, false
 }
 
@@ -622,6 +733,9 @@ func (pm *PoolManager) SubmitToThumbnailPool(job jobs.Job) error {
 func (pm *PoolManager) SubmitToSpritesPool(job jobs.Job) error {
 	pm.mu.RLock()
 	defer pm.mu.RUnlock()
+	if rc, ok := job.(jobs.RemoteCapable); ok && pm.remoteExecutor != nil {
+		rc.SetRemoteExecutor(pm.remoteExecutor)
+	}
 	return pm.spritesPool.Submit(job)
 }
 
@@ -632,6 +746,20 @@ func (pm *PoolManager) SubmitToAnimatedThumbnailsPool(job jobs.Job) error {
 	return pm.animatedThumbnailsPool.Submit(job)
 }
 
+// SubmitToVerifyPool submits a job to the verify pool
+func (pm *PoolManager) SubmitToVerifyPool(job jobs.Job) error {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.verifyPool.Submit(job)
+}
+
+// SubmitToChecksumPool submits a job to the checksum pool
+func (pm *PoolManager) SubmitToChecksumPool(job jobs.Job) error {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.checksumPool.Submit(job)
+}
+
 // LogStatus logs the status of all pools
 func (pm *PoolManager) LogStatus() {
 	pm.logger.Info("Pool manager status")
@@ -641,4 +769,6 @@ func (pm *PoolManager) LogStatus() {
 	pm.thumbnailPool.LogStatus()
 	pm.spritesPool.LogStatus()
 	pm.animatedThumbnailsPool.LogStatus()
+	pm.verifyPool.LogStatus()
+	pm.checksumPool.LogStatus()
 }
@@ -17,6 +17,9 @@ type ResultHandler struct {
 	phaseTracker   *PhaseTracker
 	poolManager    *PoolManager
 	indexer        SceneIndexer
+	artifacts      ArtifactRecorder
+	duplicates     DuplicateFlagger
+	jobLogs        JobLogRecorder
 	logger         *zap.Logger
 
 	// onPhaseComplete is called when a phase completes to submit follow-up phases
@@ -49,6 +52,21 @@ func (rh *ResultHandler) SetIndexer(indexer SceneIndexer) {
 	rh.indexer = indexer
 }
 
+// SetArtifactRecorder sets the recorder for generated artifact sizes
+func (rh *ResultHandler) SetArtifactRecorder(artifacts ArtifactRecorder) {
+	rh.artifacts = artifacts
+}
+
+// SetDuplicateFlagger sets what groups scenes with the same checksum after the checksum phase
+func (rh *ResultHandler) SetDuplicateFlagger(duplicates DuplicateFlagger) {
+	rh.duplicates = duplicates
+}
+
+// SetJobLogRecorder sets where the logs of finished job runs are stored
+func (rh *ResultHandler) SetJobLogRecorder(jobLogs JobLogRecorder) {
+	rh.jobLogs = jobLogs
+}
+
 // SetOnPhaseComplete sets the callback for phase completion
 func (rh *ResultHandler) SetOnPhaseComplete(fn func(sceneID uint, phase string) error) {
 	rh.onPhaseComplete = fn
@@ -57,6 +75,10 @@ func (rh *ResultHandler) SetOnPhaseComplete(fn func(sceneID uint, phase string)
 // ProcessPoolResults processes results from a worker pool
 func (rh *ResultHandler) ProcessPoolResults(pool *jobs.WorkerPool) {
 	for result := range pool.Results() {
+		if rh.jobLogs != nil && result.Log != nil {
+			rh.jobLogs.RecordJobLog(result.JobID, result.Log.String(), result.Log.Truncated())
+		}
+
 		switch result.Status {
 		case jobs.JobStatusCompleted:
 			rh.handleCompleted(result)
@@ -81,6 +103,10 @@ func (rh *ResultHandler) handleCompleted(result jobs.JobResult) {
 		rh.jobHistory.RecordJobComplete(result.JobID)
 	}
 
+	if rh.artifacts != nil {
+		rh.artifacts.RecordPhaseComplete(result.SceneID, result.Phase)
+	}
+
 	switch result.Phase {
 	case "metadata":
 		rh.onMetadataComplete(result)
@@ -90,6 +116,10 @@ func (rh *ResultHandler) handleCompleted(result jobs.JobResult) {
 		rh.onSpritesComplete(result)
 	case "animated_thumbnails":
 		rh.onAnimatedThumbnailsComplete(result)
+	case "verify":
+		rh.onVerifyComplete(result)
+	case "checksum":
+		rh.onChecksumComplete(result)
 	}
 }
 
@@ -143,126 +173,23 @@ func (rh *ResultHandler) onMetadataComplete(result jobs.JobResult) {
 	// Initialize phase tracking for this scene
 	rh.phaseTracker.InitPhaseState(result.SceneID)
 
-	// Retrieve the scene path from the metadata job
-	scenePath := metadataJob.GetScenePath()
-
-	// Read runtime quality config
-	qualityConfig := rh.poolManager.GetQualityConfig()
-	cfg := rh.poolManager.GetConfig()
-
-	submitThumbnail := false
-	submitSprites := false
+	// Follow-up phases go through the DB-backed queue so they survive a restart.
+	// Metadata has already been persisted, so the feeder builds the jobs from the scene record.
 	for _, phase := range phasesToTrigger {
-		if phase == "thumbnail" {
-			submitThumbnail = true
-		}
-		if phase == "sprites" {
-			submitSprites = true
-		}
-	}
-
-	var thumbnailJob *jobs.ThumbnailJob
-	var spritesJob *jobs.SpritesJob
-
-	if submitThumbnail {
-		rh.logger.Info("Creating thumbnail job from metadata result",
-			zap.Uint("result_scene_id", result.SceneID),
-			zap.Uint("metadata_job_scene_id", metadataJob.GetSceneID()),
-			zap.String("scene_path", scenePath),
-		)
-		thumbnailJob = jobs.NewThumbnailJob(
-			result.SceneID,
-			scenePath,
-			cfg.ThumbnailDir,
-			meta.TileWidth,
-			meta.TileHeight,
-			meta.TileWidthLarge,
-			meta.TileHeightLarge,
-			meta.Duration,
-			qualityConfig.FrameQualitySm,
-			qualityConfig.FrameQualityLg,
-			rh.repo,
-			rh.logger,
-			rh.markerThumbGen,
-		)
-
-		thumbnailErr := rh.poolManager.SubmitToThumbnailPool(thumbnailJob)
-		if thumbnailErr != nil {
-			if jobs.IsDuplicateJobError(thumbnailErr) {
-				rh.logger.Info("Duplicate thumbnail job skipped",
-					zap.Uint("scene_id", result.SceneID),
-				)
-				thumbnailJob = nil
-			} else {
-				rh.logger.Error("Failed to submit thumbnail job",
-					zap.Uint("scene_id", result.SceneID),
-					zap.Error(thumbnailErr),
-				)
-				rh.repo.UpdateProcessingStatus(result.SceneID, "failed", "failed to submit thumbnail job")
-				return
-			}
-		}
-	}
-
-	if submitSprites {
-		spritesJob = jobs.NewSpritesJob(
-			result.SceneID,
-			scenePath,
-			cfg.SpriteDir,
-			cfg.VttDir,
-			meta.TileWidth,
-			meta.TileHeight,
-			meta.Duration,
-			cfg.FrameInterval,
-			qualityConfig.FrameQualitySprites,
-			cfg.GridCols,
-			cfg.GridRows,
-			qualityConfig.SpritesConcurrency,
-			rh.repo,
-			rh.logger,
-		)
-		if rh.jobHistory != nil {
-			jh := rh.jobHistory
-			spritesJob.SetProgressCallback(func(jobID string, progress int) {
-				jh.UpdateProgress(jobID, progress)
-			})
-		}
-
-		spritesErr := rh.poolManager.SubmitToSpritesPool(spritesJob)
-		if spritesErr != nil {
-			if jobs.IsDuplicateJobError(spritesErr) {
-				rh.logger.Info("Duplicate sprites job skipped",
-					zap.Uint("scene_id", result.SceneID),
-				)
-				spritesJob = nil
-			} else {
-				rh.logger.Error("Failed to submit sprites job",
+		if rh.onPhaseComplete != nil {
+			if err := rh.onPhaseComplete(result.SceneID, phase); err != nil {
+				rh.logger.Error("Failed to submit phase after metadata",
 					zap.Uint("scene_id", result.SceneID),
-					zap.Error(spritesErr),
+					zap.String("phase", phase),
+					zap.Error(err),
 				)
-				rh.repo.UpdateProcessingStatus(result.SceneID, "failed", "failed to submit sprites job")
-				return
 			}
 		}
 	}
 
-	if rh.jobHistory != nil {
-		sceneTitle := ""
-		if s, err := rh.repo.GetByID(result.SceneID); err == nil {
-			sceneTitle = s.Title
-		}
-		if thumbnailJob != nil {
-			rh.jobHistory.RecordJobStart(thumbnailJob.GetID(), result.SceneID, sceneTitle, "thumbnail")
-		}
-		if spritesJob != nil {
-			rh.jobHistory.RecordJobStart(spritesJob.GetID(), result.SceneID, sceneTitle, "sprites")
-		}
-	}
-
-	rh.logger.Info("Submitted trigger-based jobs after metadata",
+	rh.logger.Info("Queued trigger-based jobs after metadata",
 		zap.Uint("scene_id", result.SceneID),
-		zap.Bool("thumbnail", submitThumbnail),
-		zap.Bool("sprites", submitSprites),
+		zap.Strings("phases", phasesToTrigger),
 	)
 }
 
@@ -309,6 +236,7 @@ func (rh *ResultHandler) onSpritesComplete(result jobs.JobResult) {
 				Data: map[string]any{
 					"vtt_path":          spritesResult.VttPath,
 					"sprite_sheet_path": spritesResult.SpriteSheetPath,
+					"trickplay_path":    spritesResult.TrickplayPath,
 				},
 			})
 		}
@@ -354,6 +282,81 @@ func (rh *ResultHandler) onAnimatedThumbnailsComplete(result jobs.JobResult) {
 	rh.checkAndMarkComplete(result.SceneID, "animated_thumbnails")
 }
 
+func (rh *ResultHandler) onVerifyComplete(result jobs.JobResult) {
+	eventData := map[string]any{}
+	if verifyJob, ok := result.Data.(*jobs.VerifyJob); ok {
+		if res := verifyJob.GetResult(); res != nil {
+			eventData["is_corrupted"] = res.IsCorrupted
+			eventData["error_count"] = res.ErrorCount
+		}
+	}
+
+	rh.eventBus.Publish(SceneEvent{
+		Type:    "scene:verify_complete",
+		SceneID: result.SceneID,
+		Data:    eventData,
+	})
+
+	// Trigger any phases configured to run after verify
+	for _, phase := range rh.phaseTracker.GetPhasesTriggeredAfter("verify") {
+		if rh.onPhaseComplete != nil {
+			if err := rh.onPhaseComplete(result.SceneID, phase); err != nil {
+				rh.logger.Error("Failed to submit phase after verify",
+					zap.Uint("scene_id", result.SceneID),
+					zap.String("phase", phase),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	rh.phaseTracker.MarkPhaseComplete(result.SceneID, "verify")
+	rh.checkAndMarkComplete(result.SceneID, "verify")
+}
+
+func (rh *ResultHandler) onChecksumComplete(result jobs.JobResult) {
+	eventData := map[string]any{}
+	if checksumJob, ok := result.Data.(*jobs.ChecksumJob); ok {
+		if res := checksumJob.GetResult(); res != nil {
+			eventData["file_hash"] = res.FileHash
+			eventData["mismatch"] = res.Mismatch
+
+			// Byte-identical copies are grouped straight away; a mismatched file no
+			// longer has the stored hash, so it is left out
+			if rh.duplicates != nil && res.FileHash != "" && !res.Mismatch {
+				if _, err := rh.duplicates.FlagFileHashMatch(result.SceneID, res.FileHash); err != nil {
+					rh.logger.Warn("Failed to flag checksum duplicate",
+						zap.Uint("scene_id", result.SceneID),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+	}
+
+	rh.eventBus.Publish(SceneEvent{
+		Type:    "scene:checksum_complete",
+		SceneID: result.SceneID,
+		Data:    eventData,
+	})
+
+	// Trigger any phases configured to run after checksum
+	for _, phase := range rh.phaseTracker.GetPhasesTriggeredAfter("checksum") {
+		if rh.onPhaseComplete != nil {
+			if err := rh.onPhaseComplete(result.SceneID, phase); err != nil {
+				rh.logger.Error("Failed to submit phase after checksum",
+					zap.Uint("scene_id", result.SceneID),
+					zap.String("phase", phase),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	rh.phaseTracker.MarkPhaseComplete(result.SceneID, "checksum")
+	rh.checkAndMarkComplete(result.SceneID, "checksum")
+}
+
 func (rh *ResultHandler) checkAndMarkComplete(sceneID uint, completedPhase string) {
 	if rh.phaseTracker.CheckAllPhasesComplete(sceneID, completedPhase) {
 		if err := rh.repo.UpdateProcessingStatus(sceneID, "completed", ""); err != nil {
@@ -380,6 +383,7 @@ func (rh *ResultHandler) handleFailed(result jobs.JobResult) {
 		zap.String("job_id", result.JobID),
 		zap.String("phase", result.Phase),
 		zap.Uint("scene_id", result.SceneID),
+		zap.Int("progress", result.Progress),
 		zap.Error(result.Error),
 	)
 
@@ -404,6 +408,7 @@ func (rh *ResultHandler) handleCancelled(result jobs.JobResult) {
 		zap.String("job_id", result.JobID),
 		zap.String("phase", result.Phase),
 		zap.Uint("scene_id", result.SceneID),
+		zap.Int("progress", result.Progress),
 	)
 
 	if rh.jobHistory != nil {
@@ -426,6 +431,7 @@ func (rh *ResultHandler) handleTimedOut(result jobs.JobResult) {
 		zap.String("job_id", result.JobID),
 		zap.String("phase", result.Phase),
 		zap.Uint("scene_id", result.SceneID),
+		zap.Int("progress", result.Progress),
 	)
 
 	if rh.jobHistory != nil {
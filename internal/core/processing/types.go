@@ -6,6 +6,16 @@ type PoolConfig struct {
 	ThumbnailWorkers          int `json:"thumbnail_workers"`
 	SpritesWorkers            int `json:"sprites_workers"`
 	AnimatedThumbnailsWorkers int `json:"animated_thumbnails_workers"`
+	VerifyWorkers             int `json:"verify_workers"`
+	ChecksumWorkers           int `json:"checksum_workers"`
+}
+
+// PoolLoad is the size and load of a phase's worker pool
+type PoolLoad struct {
+	Phase   string `json:"phase"`
+	Workers int    `json:"workers"`
+	Queued  int    `json:"queued"` // jobs waiting in the pool's queue
+	Active  int    `json:"active"` // jobs being executed
 }
 
 // QualityConfig holds the processing quality configuration
@@ -31,10 +41,17 @@ type QueueStatus struct {
 	ThumbnailQueued           int `json:"thumbnail_queued"`
 	SpritesQueued             int `json:"sprites_queued"`
 	AnimatedThumbnailsQueued  int `json:"animated_thumbnails_queued"`
+	VerifyQueued              int `json:"verify_queued"`
+	ChecksumQueued            int `json:"checksum_queued"`
 	MetadataActive            int `json:"metadata_active"`
 	ThumbnailActive           int `json:"thumbnail_active"`
 	SpritesActive             int `json:"sprites_active"`
 	AnimatedThumbnailsActive  int `json:"animated_thumbnails_active"`
+	VerifyActive              int `json:"verify_active"`
+	ChecksumActive            int `json:"checksum_active"`
+	FFmpegRunning             int `json:"ffmpeg_running"` // jobs holding a global process slot
+	FFmpegWaiting             int `json:"ffmpeg_waiting"` // jobs waiting for a global process slot
+	FFmpegLimit               int `json:"ffmpeg_limit"`   // global process limit (0 = unlimited)
 }
 
 // BulkPhaseResult contains the results of a bulk phase submission
@@ -44,9 +61,20 @@ type BulkPhaseResult struct {
 	Errors    int `json:"errors"`
 }
 
+// BulkPhasePreview describes what a bulk phase submission would enqueue
+type BulkPhasePreview struct {
+	SceneIDs       []uint `json:"scene_ids"`
+	SceneCount     int    `json:"scene_count"`
+	Skipped        int    `json:"skipped"`
+	TotalDuration  int    `json:"total_duration"`  // sum of scene durations in seconds
+	EstimatedBytes int64  `json:"estimated_bytes"` // rough size of the generated output
+}
+
 // phaseState tracks completion of parallel phases for a scene
 type PhaseState struct {
 	ThumbnailDone           bool
 	SpritesDone             bool
 	AnimatedThumbnailsDone  bool
+	VerifyDone              bool
+	ChecksumDone            bool
 }
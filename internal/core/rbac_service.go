@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"goonhub/internal/data"
+	"sort"
 	"sync"
 
 	"go.uber.org/zap"
@@ -14,6 +15,9 @@ type RBACService struct {
 	logger   *zap.Logger
 	cache    map[string]map[string]bool
 	mu       sync.RWMutex
+
+	restrictionRepo data.ContentRestrictionRepository
+	restrictions    map[string]*data.ContentRestriction
 }
 
 func NewRBACService(roleRepo data.RoleRepository, permRepo data.PermissionRepository, logger *zap.Logger) (*RBACService, error) {
@@ -40,6 +44,19 @@ func (s *RBACService) HasPermission(role, permission string) bool {
 	return perms[permission]
 }
 
+// RolePermissions returns the permissions granted to a role, sorted by name.
+func (s *RBACService) RolePermissions(role string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	perms := make([]string, 0, len(s.cache[role]))
+	for permission := range s.cache[role] {
+		perms = append(perms, permission)
+	}
+	sort.Strings(perms)
+	return perms
+}
+
 func (s *RBACService) RefreshCache() error {
 	rolePerms, err := s.roleRepo.GetAllRolePermissions()
 	if err != nil {
@@ -59,6 +76,10 @@ func (s *RBACService) RefreshCache() error {
 	s.cache = newCache
 	s.mu.Unlock()
 
+	if err := s.refreshRestrictions(); err != nil {
+		return err
+	}
+
 	s.logger.Info("RBAC cache refreshed", zap.Int("roles", len(newCache)))
 	return nil
 }
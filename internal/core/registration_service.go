@@ -0,0 +1,253 @@
+package core
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	inviteTokenBytes         = 32
+	defaultInviteExpiryHours = 72
+	maxInviteExpiryHours     = 30 * 24
+	maxInviteNoteLength      = 255
+)
+
+// ErrRegistrationClosed is returned when someone registers without an invite
+// while open registration is off.
+var ErrRegistrationClosed = apperrors.NewForbiddenError("registration requires an invite")
+
+// ErrInvalidInvite is returned for unknown, used, revoked and expired invites alike.
+var ErrInvalidInvite = apperrors.NewValidationErrorWithField("invite_token", "invite is invalid or has expired")
+
+// CreatedInvite is a new invite. Token is the only time the invite token is available.
+type CreatedInvite struct {
+	Invite data.UserInvite `json:"invite"`
+	Token  string          `json:"token"`
+}
+
+// RegistrationStatus tells the login page whether accounts can be created without an invite
+type RegistrationStatus struct {
+	OpenRegistration bool `json:"open_registration"`
+}
+
+// InvitePreview describes a usable invite to the person registering with it
+type InvitePreview struct {
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RegistrationService lets people create their own accounts, either with a
+// single-use invite an admin created for a role or, when the open_registration app
+// setting is on, with no invite at all.
+type RegistrationService struct {
+	inviteRepo      data.InviteRepository
+	userRepo        data.UserRepository
+	roleRepo        data.RoleRepository
+	appSettingsRepo data.AppSettingsRepository
+	adminService    *AdminService
+	logger          *zap.Logger
+	now             func() time.Time
+}
+
+func NewRegistrationService(inviteRepo data.InviteRepository, userRepo data.UserRepository, roleRepo data.RoleRepository, appSettingsRepo data.AppSettingsRepository, adminService *AdminService, logger *zap.Logger) *RegistrationService {
+	return &RegistrationService{
+		inviteRepo:      inviteRepo,
+		userRepo:        userRepo,
+		roleRepo:        roleRepo,
+		appSettingsRepo: appSettingsRepo,
+		adminService:    adminService,
+		logger:          logger,
+		now:             time.Now,
+	}
+}
+
+// CreateInvite creates an invite for the role. expiresInHours 0 uses the default of three days.
+func (s *RegistrationService) CreateInvite(createdBy uint, role, note string, expiresInHours int) (*CreatedInvite, error) {
+	if expiresInHours == 0 {
+		expiresInHours = defaultInviteExpiryHours
+	}
+	if expiresInHours < 0 || expiresInHours > maxInviteExpiryHours {
+		return nil, apperrors.NewValidationErrorWithField("expires_in_hours", fmt.Sprintf("expiry must be between 1 and %d hours", maxInviteExpiryHours))
+	}
+	note = strings.TrimSpace(note)
+	if len(note) > maxInviteNoteLength {
+		return nil, apperrors.NewValidationErrorWithField("note", fmt.Sprintf("note must be at most %d characters", maxInviteNoteLength))
+	}
+	if _, err := s.roleRepo.GetByName(role); err != nil {
+		return nil, apperrors.NewValidationErrorWithField("role", fmt.Sprintf("unknown role %q", role))
+	}
+
+	token, err := newInviteToken()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate invite", err)
+	}
+
+	now := s.now()
+	invite := data.UserInvite{
+		TokenHash: hashInviteToken(token),
+		Role:      role,
+		Note:      note,
+		CreatedBy: &createdBy,
+		ExpiresAt: now.Add(time.Duration(expiresInHours) * time.Hour),
+		CreatedAt: now,
+	}
+	if err := s.inviteRepo.Create(&invite); err != nil {
+		return nil, apperrors.NewInternalError("failed to create invite", err)
+	}
+
+	s.logger.Info("Invite created",
+		zap.Uint("invite_id", invite.ID),
+		zap.Uint("created_by", createdBy),
+		zap.String("role", role),
+	)
+	return &CreatedInvite{Invite: invite, Token: token}, nil
+}
+
+// ListInvites returns every invite, newest first, including used and expired ones.
+func (s *RegistrationService) ListInvites(page, limit int) ([]data.UserInvite, int64, error) {
+	invites, total, err := s.inviteRepo.List(page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list invites", err)
+	}
+	return invites, total, nil
+}
+
+// RevokeInvite revokes an invite that hasn't been used yet.
+func (s *RegistrationService) RevokeInvite(id uint) error {
+	if err := s.inviteRepo.Revoke(id, s.now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewNotFoundError("invite", id)
+		}
+		return apperrors.NewInternalError("failed to revoke invite", err)
+	}
+	s.logger.Info("Invite revoked", zap.Uint("invite_id", id))
+	return nil
+}
+
+// Status reports whether registration is open to people without an invite.
+func (s *RegistrationService) Status() (*RegistrationStatus, error) {
+	settings, err := s.appSettingsRepo.Get()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to read app settings", err)
+	}
+	return &RegistrationStatus{OpenRegistration: settings.OpenRegistration}, nil
+}
+
+// PreviewInvite returns what registering with a usable invite will give.
+func (s *RegistrationService) PreviewInvite(token string) (*InvitePreview, error) {
+	invite, err := s.usableInvite(token)
+	if err != nil {
+		return nil, err
+	}
+	return &InvitePreview{Role: invite.Role, ExpiresAt: invite.ExpiresAt}, nil
+}
+
+// Register creates an account. With an invite token the account gets the invite's
+// role and the invite is used up; without one, open registration must be on.
+func (s *RegistrationService) Register(username, password, inviteToken string) (*data.User, error) {
+	var invite *data.UserInvite
+	var role string
+	if inviteToken != "" {
+		var err error
+		invite, err = s.usableInvite(inviteToken)
+		if err != nil {
+			return nil, err
+		}
+		role = invite.Role
+	} else {
+		settings, err := s.appSettingsRepo.Get()
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to read app settings", err)
+		}
+		if !settings.OpenRegistration {
+			return nil, ErrRegistrationClosed
+		}
+		role = settings.OpenRegistrationRole
+		if role == "" {
+			role = "user"
+		}
+	}
+
+	exists, err := s.userRepo.Exists(username)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to check user existence", err)
+	}
+	if exists {
+		return nil, apperrors.ErrUsernameAlreadyExists(username)
+	}
+
+	// Claiming first means two people racing with one invite can't both register
+	if invite != nil {
+		if err := s.inviteRepo.Claim(invite.ID, s.now()); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrInvalidInvite
+			}
+			return nil, apperrors.NewInternalError("failed to use invite", err)
+		}
+	}
+
+	if err := s.adminService.CreateUser(username, password, role); err != nil {
+		if invite != nil {
+			if releaseErr := s.inviteRepo.Release(invite.ID); releaseErr != nil {
+				s.logger.Warn("Failed to release invite after failed registration", zap.Uint("invite_id", invite.ID), zap.Error(releaseErr))
+			}
+		}
+		return nil, apperrors.NewValidationError(err.Error())
+	}
+
+	user, err := s.userRepo.GetByUsername(username)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load registered user", err)
+	}
+	if invite != nil {
+		if err := s.inviteRepo.SetUsedBy(invite.ID, user.ID); err != nil {
+			s.logger.Warn("Failed to record invite user", zap.Uint("invite_id", invite.ID), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("User registered",
+		zap.String("username", username),
+		zap.String("role", role),
+		zap.Bool("invited", invite != nil),
+	)
+	return user, nil
+}
+
+func (s *RegistrationService) usableInvite(token string) (*data.UserInvite, error) {
+	invite, err := s.inviteRepo.GetByHash(hashInviteToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvite
+		}
+		return nil, apperrors.NewInternalError("failed to look up invite", err)
+	}
+	if !invite.IsUsable(s.now()) {
+		return nil, ErrInvalidInvite
+	}
+	return invite, nil
+}
+
+func newInviteToken() (string, error) {
+	buf := make([]byte, inviteTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
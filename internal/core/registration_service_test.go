@@ -0,0 +1,151 @@
+package core
+
+import (
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type registrationTestDeps struct {
+	invites  *mocks.MockInviteRepository
+	users    *mocks.MockUserRepository
+	roles    *mocks.MockRoleRepository
+	settings *mocks.MockAppSettingsRepository
+}
+
+func newTestRegistrationService(t *testing.T) (*RegistrationService, registrationTestDeps) {
+	adminSvc, userRepo, roleRepo := newTestAdminService(t)
+	ctrl := gomock.NewController(t)
+	deps := registrationTestDeps{
+		invites:  mocks.NewMockInviteRepository(ctrl),
+		users:    userRepo,
+		roles:    roleRepo,
+		settings: mocks.NewMockAppSettingsRepository(ctrl),
+	}
+	svc := NewRegistrationService(deps.invites, userRepo, roleRepo, deps.settings, adminSvc, zap.NewNop())
+	return svc, deps
+}
+
+func TestRegistrationService_CreateInviteStoresHashOnly(t *testing.T) {
+	svc, deps := newTestRegistrationService(t)
+
+	deps.roles.EXPECT().GetByName("viewer").Return(&data.Role{ID: 2, Name: "viewer"}, nil)
+	var stored data.UserInvite
+	deps.invites.EXPECT().Create(gomock.Any()).DoAndReturn(func(invite *data.UserInvite) error {
+		stored = *invite
+		return nil
+	})
+
+	created, err := svc.CreateInvite(1, "viewer", "for bob", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Token == "" || stored.TokenHash != hashInviteToken(created.Token) {
+		t.Fatal("expected only the token hash to be stored")
+	}
+	expiry := stored.ExpiresAt.Sub(stored.CreatedAt)
+	if expiry != defaultInviteExpiryHours*time.Hour {
+		t.Fatalf("expected default expiry, got %v", expiry)
+	}
+}
+
+func TestRegistrationService_CreateInviteUnknownRole(t *testing.T) {
+	svc, deps := newTestRegistrationService(t)
+
+	deps.roles.EXPECT().GetByName("nope").Return(nil, gorm.ErrRecordNotFound)
+
+	if _, err := svc.CreateInvite(1, "nope", "", 24); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestRegistrationService_RegisterClosedWithoutInvite(t *testing.T) {
+	svc, deps := newTestRegistrationService(t)
+
+	deps.settings.EXPECT().Get().Return(&data.AppSettingsRecord{OpenRegistration: false}, nil)
+
+	if _, err := svc.Register("newuser", "SecurePass123!", ""); !apperrors.IsForbidden(err) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+}
+
+func TestRegistrationService_RegisterRejectsUsedInvite(t *testing.T) {
+	svc, deps := newTestRegistrationService(t)
+	used := time.Now().Add(-time.Hour)
+
+	deps.invites.EXPECT().GetByHash(hashInviteToken("tok")).Return(&data.UserInvite{
+		ID: 4, Role: "viewer", ExpiresAt: time.Now().Add(time.Hour), UsedAt: &used,
+	}, nil)
+
+	if _, err := svc.Register("newuser", "SecurePass123!", "tok"); err != ErrInvalidInvite {
+		t.Fatalf("expected invalid invite, got %v", err)
+	}
+}
+
+func TestRegistrationService_RegisterWithInvite(t *testing.T) {
+	svc, deps := newTestRegistrationService(t)
+
+	deps.invites.EXPECT().GetByHash(hashInviteToken("tok")).Return(&data.UserInvite{
+		ID: 4, Role: "viewer", ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	deps.users.EXPECT().Exists("newuser").Return(false, nil).Times(2)
+	deps.invites.EXPECT().Claim(uint(4), gomock.Any()).Return(nil)
+	deps.roles.EXPECT().GetByName("viewer").Return(&data.Role{ID: 2, Name: "viewer"}, nil)
+	deps.users.EXPECT().Create(gomock.Any()).DoAndReturn(func(user *data.User) error {
+		if user.Role != "viewer" {
+			t.Fatalf("expected the invite's role, got %q", user.Role)
+		}
+		return nil
+	})
+	deps.users.EXPECT().GetByUsername("newuser").Return(&data.User{ID: 12, Username: "newuser", Role: "viewer"}, nil)
+	deps.invites.EXPECT().SetUsedBy(uint(4), uint(12)).Return(nil)
+
+	user, err := svc.Register("newuser", "SecurePass123!", "tok")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.ID != 12 {
+		t.Fatalf("expected registered user, got %+v", user)
+	}
+}
+
+func TestRegistrationService_RegisterReleasesInviteOnFailure(t *testing.T) {
+	svc, deps := newTestRegistrationService(t)
+
+	deps.invites.EXPECT().GetByHash(hashInviteToken("tok")).Return(&data.UserInvite{
+		ID: 4, Role: "viewer", ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	deps.users.EXPECT().Exists("newuser").Return(false, nil)
+	deps.invites.EXPECT().Claim(uint(4), gomock.Any()).Return(nil)
+	deps.invites.EXPECT().Release(uint(4)).Return(nil)
+
+	// Too weak for ValidatePassword, so CreateUser fails
+	if _, err := svc.Register("newuser", "weak", "tok"); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestRegistrationService_RegisterOpenUsesConfiguredRole(t *testing.T) {
+	svc, deps := newTestRegistrationService(t)
+
+	deps.settings.EXPECT().Get().Return(&data.AppSettingsRecord{OpenRegistration: true, OpenRegistrationRole: "guest"}, nil)
+	deps.users.EXPECT().Exists("newuser").Return(false, nil).Times(2)
+	deps.roles.EXPECT().GetByName("guest").Return(&data.Role{ID: 3, Name: "guest"}, nil)
+	deps.users.EXPECT().Create(gomock.Any()).DoAndReturn(func(user *data.User) error {
+		if user.Role != "guest" {
+			t.Fatalf("expected the open registration role, got %q", user.Role)
+		}
+		return nil
+	})
+	deps.users.EXPECT().GetByUsername("newuser").Return(&data.User{ID: 13, Username: "newuser", Role: "guest"}, nil)
+
+	if _, err := svc.Register("newuser", "SecurePass123!", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
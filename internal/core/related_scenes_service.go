@@ -18,6 +18,7 @@ type RelatedScenesService struct {
 	actorInteractionRepo  data.ActorInteractionRepository
 	studioInteractionRepo data.StudioInteractionRepository
 	watchHistoryRepo      data.WatchHistoryRepository
+	rbac                  *RBACService
 	logger                *zap.Logger
 }
 
@@ -69,10 +70,15 @@ func NewRelatedScenesService(
 	}
 }
 
+// SetRBACService limits results to the scenes the caller's role may see.
+func (s *RelatedScenesService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
+}
+
 // GetRelatedScenes returns scenes related to the given scene ID using a
 // gather-then-score model. All signals (actors, tags, studio, type, popularity,
 // user preferences) are accumulated for each candidate before ranking.
-func (s *RelatedScenesService) GetRelatedScenes(sceneID uint, userID uint, limit int) ([]data.Scene, error) {
+func (s *RelatedScenesService) GetRelatedScenes(sceneID uint, userID uint, role string, limit int) ([]data.Scene, error) {
 	if limit <= 0 {
 		limit = 15
 	}
@@ -235,7 +241,7 @@ func (s *RelatedScenesService) GetRelatedScenes(sceneID uint, userID uint, limit
 	delete(candidateIDSet, sceneID)
 
 	if len(candidateIDSet) == 0 {
-		return s.fallbackPopular(sceneID, limit)
+		return s.fallbackPopular(sceneID, role, limit)
 	}
 
 	// Step 4: Build ID slice for batch fetch
@@ -243,6 +249,13 @@ func (s *RelatedScenesService) GetRelatedScenes(sceneID uint, userID uint, limit
 	for id := range candidateIDSet {
 		candidateIDs = append(candidateIDs, id)
 	}
+	candidateIDs, err := visibleSceneIDs(s.rbac, role, candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidateIDs) == 0 {
+		return s.fallbackPopular(sceneID, role, limit)
+	}
 
 	// Step 5: Batch-fetch scene data, tags, and actors in parallel
 	var scenes []data.Scene
@@ -277,7 +290,7 @@ func (s *RelatedScenesService) GetRelatedScenes(sceneID uint, userID uint, limit
 	}
 
 	if len(scenes) == 0 {
-		return s.fallbackPopular(sceneID, limit)
+		return s.fallbackPopular(sceneID, role, limit)
 	}
 
 	// Build source data lookups
@@ -379,19 +392,23 @@ func (s *RelatedScenesService) GetRelatedScenes(sceneID uint, userID uint, limit
 
 	// Step 8: Fill with popular scenes if under limit
 	if len(result) < limit {
-		result = s.fillWithPopular(result, sceneID, limit)
+		result = s.fillWithPopular(result, sceneID, role, limit)
 	}
 
 	return result, nil
 }
 
 // fallbackPopular returns popular scenes when no candidates are found.
-func (s *RelatedScenesService) fallbackPopular(excludeID uint, limit int) ([]data.Scene, error) {
+func (s *RelatedScenesService) fallbackPopular(excludeID uint, role string, limit int) ([]data.Scene, error) {
 	popular, err := s.sceneRepo.ListPopular(limit + 1)
 	if err != nil {
 		s.logger.Warn("failed to get popular scenes for fallback", zap.Error(err))
 		return []data.Scene{}, nil
 	}
+	popular, err = visibleScenes(s.rbac, role, popular)
+	if err != nil {
+		return nil, err
+	}
 
 	result := make([]data.Scene, 0, limit)
 	for _, sc := range popular {
@@ -407,7 +424,7 @@ func (s *RelatedScenesService) fallbackPopular(excludeID uint, limit int) ([]dat
 }
 
 // fillWithPopular appends popular scenes to fill up to limit.
-func (s *RelatedScenesService) fillWithPopular(existing []data.Scene, excludeID uint, limit int) []data.Scene {
+func (s *RelatedScenesService) fillWithPopular(existing []data.Scene, excludeID uint, role string, limit int) []data.Scene {
 	needed := limit - len(existing)
 	if needed <= 0 {
 		return existing
@@ -424,6 +441,11 @@ func (s *RelatedScenesService) fillWithPopular(existing []data.Scene, excludeID
 		s.logger.Warn("failed to get popular scenes for fill", zap.Error(err))
 		return existing
 	}
+	popular, err = visibleScenes(s.rbac, role, popular)
+	if err != nil {
+		s.logger.Warn("failed to filter popular scenes for fill", zap.Error(err))
+		return existing
+	}
 
 	for _, sc := range popular {
 		if _, seen := seenIDs[sc.ID]; seen {
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"math"
 	"testing"
 
 	"goonhub/internal/data"
@@ -69,7 +70,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 		// fillWithPopular called because 1 result < limit (12)
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, 0, 12)
+		scenes, err := service.GetRelatedScenes(sceneID, 0, "user", 12)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -106,7 +107,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, 0, 12)
+		scenes, err := service.GetRelatedScenes(sceneID, 0, "user", 12)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -134,7 +135,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{popularScene}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, 0, 12)
+		scenes, err := service.GetRelatedScenes(sceneID, 0, "user", 12)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -170,7 +171,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, 0, 12)
+		scenes, err := service.GetRelatedScenes(sceneID, 0, "user", 12)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -211,7 +212,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, 0, 12)
+		scenes, err := service.GetRelatedScenes(sceneID, 0, "user", 12)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -257,7 +258,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 		mockTagRepo.EXPECT().GetSceneTagsMultiple(gomock.Any()).Return(tagsByScene, nil)
 		mockActorRepo.EXPECT().GetSceneActorsMultiple(gomock.Any()).Return(map[uint][]data.Actor{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, 0, limit)
+		scenes, err := service.GetRelatedScenes(sceneID, 0, "user", limit)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -281,7 +282,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 		mockTagRepo.EXPECT().GetSceneTags(sceneID).Return([]data.Tag{}, nil)
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, 0, 100)
+		scenes, err := service.GetRelatedScenes(sceneID, 0, "user", 100)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -323,7 +324,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, 0, 12)
+		scenes, err := service.GetRelatedScenes(sceneID, 0, "user", 12)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -370,7 +371,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, userID, 12)
+		scenes, err := service.GetRelatedScenes(sceneID, userID, "user", 12)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -419,7 +420,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, userID, 12)
+		scenes, err := service.GetRelatedScenes(sceneID, userID, "user", 12)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -457,7 +458,7 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 
 		mockSceneRepo.EXPECT().ListPopular(gomock.Any()).Return([]data.Scene{}, nil)
 
-		scenes, err := service.GetRelatedScenes(sceneID, 0, 12)
+		scenes, err := service.GetRelatedScenes(sceneID, 0, "user", 12)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -467,3 +468,79 @@ func TestRelatedScenesService_GetRelatedScenes(t *testing.T) {
 		}
 	})
 }
+
+func TestRelatedScenesService_GetSimilarScenes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service, mockSceneRepo, mockTagRepo, mockActorRepo, mockStudioRepo,
+		_, _, _ := setupRelatedScenesService(ctrl)
+
+	studioID := uint(5)
+	otherStudioID := uint(6)
+	actorA := data.Actor{ID: 10, Name: "Actor A"}
+	actorB := data.Actor{ID: 11, Name: "Actor B"}
+	tagX := data.Tag{ID: 20, Name: "Tag X"}
+	tagY := data.Tag{ID: 21, Name: "Tag Y"}
+
+	mockSceneRepo.EXPECT().GetByID(uint(1)).Return(&data.Scene{ID: 1, StudioID: &studioID, Duration: 600}, nil)
+	mockActorRepo.EXPECT().GetSceneActors(uint(1)).Return([]data.Actor{actorA, actorB}, nil)
+	mockTagRepo.EXPECT().GetSceneTags(uint(1)).Return([]data.Tag{tagX, tagY}, nil)
+
+	mockActorRepo.EXPECT().GetActorSceneIDs(uint(10)).Return([]uint{1, 2, 3}, nil)
+	mockActorRepo.EXPECT().GetActorSceneIDs(uint(11)).Return([]uint{2}, nil)
+	mockTagRepo.EXPECT().GetSceneIDsByTag(uint(20), candidateCapTags).Return([]uint{3}, nil)
+	mockTagRepo.EXPECT().GetSceneIDsByTag(uint(21), candidateCapTags).Return([]uint{}, nil)
+	mockStudioRepo.EXPECT().GetStudioSceneIDs(studioID, candidateCapStudio).Return([]uint{2}, nil)
+
+	mockSceneRepo.EXPECT().GetByIDs([]uint{2, 3}).Return([]data.Scene{
+		{ID: 2, StudioID: &studioID, Duration: 600},
+		{ID: 3, StudioID: &otherStudioID, Duration: 300},
+	}, nil)
+	mockActorRepo.EXPECT().GetSceneActorsMultiple([]uint{2, 3}).Return(map[uint][]data.Actor{
+		2: {actorA, actorB},
+		3: {actorA},
+	}, nil)
+	mockTagRepo.EXPECT().GetSceneTagsMultiple([]uint{2, 3}).Return(map[uint][]data.Tag{
+		2: {tagX, tagY},
+		3: {tagX, {ID: 22, Name: "Tag Z"}},
+	}, nil)
+
+	results, err := service.GetSimilarScenes(1, "user", 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 similar scenes, got %d", len(results))
+	}
+
+	// Same cast, tags, studio and length
+	if results[0].Scene.ID != 2 || results[0].Score != 1 || !results[0].SameStudio {
+		t.Fatalf("expected scene 2 to be identical, got %+v", results[0])
+	}
+	// One of two actors, one of three tags, other studio, half the length
+	want := 0.4*0.5 + 0.4*(1.0/3) + 0.1*0.5
+	if results[1].Scene.ID != 3 || results[1].Score != math.Round(want*1000)/1000 {
+		t.Fatalf("expected scene 3 with score %.3f, got %+v", want, results[1])
+	}
+	if len(results[1].SharedActors) != 1 || results[1].SharedActors[0] != "Actor A" ||
+		len(results[1].SharedTags) != 1 || results[1].SharedTags[0] != "Tag X" {
+		t.Fatalf("unexpected shared actors and tags %+v", results[1])
+	}
+}
+
+func TestRelatedScenesService_GetSimilarScenesWithoutCandidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	service, mockSceneRepo, mockTagRepo, mockActorRepo, _,
+		_, _, _ := setupRelatedScenesService(ctrl)
+
+	mockSceneRepo.EXPECT().GetByID(uint(1)).Return(&data.Scene{ID: 1}, nil)
+	mockActorRepo.EXPECT().GetSceneActors(uint(1)).Return([]data.Actor{}, nil)
+	mockTagRepo.EXPECT().GetSceneTags(uint(1)).Return([]data.Tag{}, nil)
+
+	results, err := service.GetSimilarScenes(1, "user", 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no similar scenes without shared metadata, got %d", len(results))
+	}
+}
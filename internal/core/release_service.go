@@ -0,0 +1,141 @@
+package core
+
+import (
+	"fmt"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/version"
+
+	"go.uber.org/zap"
+)
+
+// Notice severities, from informational to requiring operator action.
+const (
+	NoticeInfo    = "info"
+	NoticeWarning = "warning"
+	NoticeError   = "error"
+)
+
+// ReleaseNotice is something an operator should look at after upgrading: a
+// pending data migration, a backfill that can be run, or a deprecation.
+type ReleaseNotice struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Action   string `json:"action,omitempty"` // endpoint or command that resolves the notice
+}
+
+// SchemaStatus compares the applied database schema with the migrations in the binary.
+type SchemaStatus struct {
+	CurrentVersion uint `json:"current_version"`
+	LatestVersion  uint `json:"latest_version"`
+	Dirty          bool `json:"dirty"`
+}
+
+// ReleaseInfo is the running version with its changelog and outstanding notices.
+type ReleaseInfo struct {
+	Version   string                   `json:"version"`
+	Commit    string                   `json:"commit,omitempty"`
+	Schema    SchemaStatus             `json:"schema"`
+	Changelog []version.ChangelogEntry `json:"changelog"`
+	Notices   []ReleaseNotice          `json:"notices"`
+}
+
+// ReleaseService reports what changed in the running build and what operators
+// still need to do after an upgrade.
+type ReleaseService struct {
+	schemaRepo          data.SchemaRepository
+	artifactRepo        data.SceneArtifactRepository
+	latestSchemaVersion uint
+	logger              *zap.Logger
+}
+
+func NewReleaseService(
+	schemaRepo data.SchemaRepository,
+	artifactRepo data.SceneArtifactRepository,
+	latestSchemaVersion uint,
+	logger *zap.Logger,
+) *ReleaseService {
+	return &ReleaseService{
+		schemaRepo:          schemaRepo,
+		artifactRepo:        artifactRepo,
+		latestSchemaVersion: latestSchemaVersion,
+		logger:              logger,
+	}
+}
+
+// GetReleaseInfo returns the running version, the newest limit changelog entries
+// (0 = all) and the current notices.
+func (s *ReleaseService) GetReleaseInfo(limit int) (*ReleaseInfo, error) {
+	changelog, err := version.Changelog()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load changelog", err)
+	}
+	if limit > 0 && len(changelog) > limit {
+		changelog = changelog[:limit]
+	}
+
+	currentVersion, dirty, err := s.schemaRepo.GetVersion()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get schema version", err)
+	}
+
+	info := &ReleaseInfo{
+		Version: version.Version,
+		Commit:  version.Commit,
+		Schema: SchemaStatus{
+			CurrentVersion: currentVersion,
+			LatestVersion:  s.latestSchemaVersion,
+			Dirty:          dirty,
+		},
+		Changelog: changelog,
+		Notices:   []ReleaseNotice{},
+	}
+
+	switch {
+	case dirty:
+		info.Notices = append(info.Notices, ReleaseNotice{
+			ID:       "schema_dirty",
+			Severity: NoticeError,
+			Message:  fmt.Sprintf("Database migration %d failed partway; fix the schema and clear the dirty flag before restarting", currentVersion),
+		})
+	case currentVersion < s.latestSchemaVersion:
+		info.Notices = append(info.Notices, ReleaseNotice{
+			ID:       "schema_pending",
+			Severity: NoticeWarning,
+			Message:  fmt.Sprintf("Database schema is at version %d but this build expects %d; restart to apply pending migrations", currentVersion, s.latestSchemaVersion),
+		})
+	case currentVersion > s.latestSchemaVersion:
+		info.Notices = append(info.Notices, ReleaseNotice{
+			ID:       "schema_newer",
+			Severity: NoticeWarning,
+			Message:  fmt.Sprintf("Database schema version %d is newer than this build (%d); the server may have been downgraded", currentVersion, s.latestSchemaVersion),
+		})
+	}
+
+	missingSizes, err := s.artifactRepo.CountScenesWithoutSizes()
+	if err != nil {
+		// Notices are advisory; report what we have rather than failing the page
+		s.logger.Warn("Failed to count scenes without artifact sizes", zap.Error(err))
+	} else if missingSizes > 0 {
+		info.Notices = append(info.Notices, ReleaseNotice{
+			ID:       "artifact_sizes_backfill",
+			Severity: NoticeInfo,
+			Message:  fmt.Sprintf("%d processed scenes have no recorded artifact sizes; storage stats are incomplete until they are measured", missingSizes),
+			Action:   "POST /api/v1/admin/artifacts/recalculate",
+		})
+	}
+
+	for _, entry := range changelog {
+		for i, deprecation := range entry.Deprecations {
+			info.Notices = append(info.Notices, ReleaseNotice{
+				ID:       fmt.Sprintf("deprecation_%s_%d", entry.Version, i+1),
+				Severity: NoticeWarning,
+				Message:  deprecation,
+			})
+		}
+	}
+
+	return info, nil
+}
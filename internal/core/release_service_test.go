@@ -0,0 +1,90 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestReleaseService(t *testing.T, latest uint) (*ReleaseService, *mocks.MockSchemaRepository, *mocks.MockSceneArtifactRepository) {
+	ctrl := gomock.NewController(t)
+	schemaRepo := mocks.NewMockSchemaRepository(ctrl)
+	artifactRepo := mocks.NewMockSceneArtifactRepository(ctrl)
+	return NewReleaseService(schemaRepo, artifactRepo, latest, zap.NewNop()), schemaRepo, artifactRepo
+}
+
+func noticeIDs(info *ReleaseInfo) map[string]string {
+	ids := make(map[string]string, len(info.Notices))
+	for _, n := range info.Notices {
+		ids[n.ID] = n.Severity
+	}
+	return ids
+}
+
+func TestGetReleaseInfo_UpToDate(t *testing.T) {
+	svc, schemaRepo, artifactRepo := newTestReleaseService(t, 59)
+	schemaRepo.EXPECT().GetVersion().Return(uint(59), false, nil)
+	artifactRepo.EXPECT().CountScenesWithoutSizes().Return(int64(0), nil)
+
+	info, err := svc.GetReleaseInfo(1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(info.Changelog) != 1 {
+		t.Fatalf("expected changelog limited to 1 entry, got %d", len(info.Changelog))
+	}
+	if info.Schema.CurrentVersion != 59 || info.Schema.LatestVersion != 59 {
+		t.Fatalf("unexpected schema status: %+v", info.Schema)
+	}
+	for _, n := range info.Notices {
+		if n.ID == "schema_pending" || n.ID == "schema_dirty" || n.ID == "artifact_sizes_backfill" {
+			t.Fatalf("expected no schema or backfill notices, got %+v", n)
+		}
+	}
+}
+
+func TestGetReleaseInfo_PendingMigrationAndBackfill(t *testing.T) {
+	svc, schemaRepo, artifactRepo := newTestReleaseService(t, 59)
+	schemaRepo.EXPECT().GetVersion().Return(uint(57), false, nil)
+	artifactRepo.EXPECT().CountScenesWithoutSizes().Return(int64(12), nil)
+
+	info, err := svc.GetReleaseInfo(0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	ids := noticeIDs(info)
+	if ids["schema_pending"] != NoticeWarning {
+		t.Fatalf("expected schema_pending warning, got %v", ids)
+	}
+	if ids["artifact_sizes_backfill"] != NoticeInfo {
+		t.Fatalf("expected artifact_sizes_backfill info, got %v", ids)
+	}
+}
+
+func TestGetReleaseInfo_DirtySchema(t *testing.T) {
+	svc, schemaRepo, artifactRepo := newTestReleaseService(t, 59)
+	schemaRepo.EXPECT().GetVersion().Return(uint(59), true, nil)
+	artifactRepo.EXPECT().CountScenesWithoutSizes().Return(int64(0), errors.New("db down"))
+
+	info, err := svc.GetReleaseInfo(0)
+	if err != nil {
+		t.Fatalf("expected backfill count failure to be non-fatal, got %v", err)
+	}
+	if noticeIDs(info)["schema_dirty"] != NoticeError {
+		t.Fatalf("expected schema_dirty error notice, got %+v", info.Notices)
+	}
+}
+
+func TestGetReleaseInfo_SchemaError(t *testing.T) {
+	svc, schemaRepo, _ := newTestReleaseService(t, 59)
+	schemaRepo.EXPECT().GetVersion().Return(uint(0), false, errors.New("db down"))
+
+	if _, err := svc.GetReleaseInfo(0); err == nil {
+		t.Fatal("expected error when schema version cannot be read")
+	}
+}
@@ -0,0 +1,760 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+)
+
+// RemoteImportPhase is the job_history phase of URL downloads. They run in
+// their own pool, so they can't be retried from the jobs page.
+const RemoteImportPhase = "remote_import"
+
+const (
+	// remoteImportQueueSize bounds the imports waiting for a download slot
+	remoteImportQueueSize = 1000
+	// remoteImportProgressInterval throttles progress updates and events
+	remoteImportProgressInterval = 2 * time.Second
+	// remoteImportChunkSize is the largest read, and the burst of the speed limit
+	remoteImportChunkSize     = 32 * 1024
+	remoteImportDialTimeout   = 30 * time.Second
+	remoteImportHeaderTimeout = time.Minute
+	// remoteImportDownloadDir holds partial downloads inside the destination
+	// storage path, so completed files are moved with a rename. Scans always
+	// skip it, see workDirs.
+	remoteImportDownloadDir = ".downloads"
+)
+
+// ytDlpProgressLine matches yt-dlp's "[download]  42.0% of ~ 1.20GiB ..." lines.
+var ytDlpProgressLine = regexp.MustCompile(`^\[download\]\s+([\d.]+)%(?:\s+of\s+~?\s*([\d.]+)(B|KiB|MiB|GiB|TiB))?`)
+
+var ytDlpSizeUnits = map[string]float64{"B": 1, "KiB": 1 << 10, "MiB": 1 << 20, "GiB": 1 << 30, "TiB": 1 << 40}
+
+// RemoteImportService downloads files from URLs into a storage path and
+// imports them through the scan logic, like the dropbox. Downloads run in a
+// pool of their own, are tracked in job_history under RemoteImportPhase and
+// report their progress as import:progress events. Direct file URLs are
+// downloaded over HTTP; web pages go through yt-dlp when it is configured.
+type RemoteImportService struct {
+	cfg                config.RemoteImportConfig
+	repo               data.RemoteImportRepository
+	storagePathService *StoragePathService
+	scanService        *ScanService
+	sceneRepo          data.SceneRepository
+	jobHistory         *JobHistoryService
+	eventBus           *EventBus
+	storageHealth      *StorageHealthService
+	client             *http.Client
+	logger             *zap.Logger
+
+	queue chan string
+
+	mu        sync.Mutex
+	running   map[string]context.CancelFunc
+	cancelled map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRemoteImportService(
+	cfg config.RemoteImportConfig,
+	repo data.RemoteImportRepository,
+	storagePathService *StoragePathService,
+	scanService *ScanService,
+	sceneRepo data.SceneRepository,
+	jobHistory *JobHistoryService,
+	eventBus *EventBus,
+	logger *zap.Logger,
+) *RemoteImportService {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = remoteImportHeaderTimeout
+	transport.DialContext = (&net.Dialer{Timeout: remoteImportDialTimeout}).DialContext
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RemoteImportService{
+		cfg:                cfg,
+		repo:               repo,
+		storagePathService: storagePathService,
+		scanService:        scanService,
+		sceneRepo:          sceneRepo,
+		jobHistory:         jobHistory,
+		eventBus:           eventBus,
+		client:             &http.Client{Transport: transport},
+		logger:             logger.With(zap.String("component", "remote_import")),
+		queue:              make(chan string, remoteImportQueueSize),
+		running:            make(map[string]context.CancelFunc),
+		cancelled:          make(map[string]bool),
+		ctx:                ctx,
+		cancel:             cancel,
+	}
+}
+
+// SetStorageHealth fails downloads into an offline storage path up front
+func (s *RemoteImportService) SetStorageHealth(storageHealth *StorageHealthService) {
+	s.storageHealth = storageHealth
+}
+
+// Start runs the download workers and queues the imports left waiting by the
+// previous run. Imports interrupted mid-download are failed, as their partial
+// file is gone. It is a no-op when disabled.
+func (s *RemoteImportService) Start() {
+	if !s.cfg.Enabled {
+		return
+	}
+
+	if unfinished, err := s.repo.ListUnfinished(); err != nil {
+		s.logger.Warn("Failed to load unfinished URL imports", zap.Error(err))
+	} else {
+		for i := range unfinished {
+			item := &unfinished[i]
+			if item.Status == data.RemoteImportQueued {
+				s.enqueue(item.JobID)
+				continue
+			}
+			s.fail(item, errors.New("interrupted by a server restart"))
+		}
+	}
+
+	for i := 0; i < s.cfg.Workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+
+	s.logger.Info("URL import workers started",
+		zap.Int("workers", s.cfg.Workers),
+		zap.Int64("rate_limit_kb", s.cfg.RateLimitKB),
+		zap.Bool("ytdlp", s.cfg.YtDlpPath != ""),
+	)
+}
+
+// Stop cancels the running downloads, which are failed, and waits for the
+// workers. Queued imports stay queued for the next start.
+func (s *RemoteImportService) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Submit queues a download of a URL into a storage path, the configured one
+// when storagePathID is nil. The title names the file and so the scene; the
+// file name from the server is used without one.
+func (s *RemoteImportService) Submit(userID uint, rawURL, title string, storagePathID *uint) (*data.RemoteImport, error) {
+	if !s.cfg.Enabled {
+		return nil, apperrors.NewValidationError("URL imports are not enabled")
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, apperrors.NewValidationErrorWithField("url", "url must be an http or https URL")
+	}
+	if storagePathID != nil {
+		if _, err := s.storagePathService.GetByID(*storagePathID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.NewNotFoundError("storage path", *storagePathID)
+			}
+			return nil, apperrors.NewInternalError("failed to get storage path", err)
+		}
+	}
+
+	item := &data.RemoteImport{
+		JobID:         uuid.New().String(),
+		URL:           u.String(),
+		Title:         strings.TrimSpace(title),
+		StoragePathID: storagePathID,
+		Status:        data.RemoteImportQueued,
+	}
+	if userID != 0 {
+		item.UserID = &userID
+	}
+	if err := s.repo.Create(item); err != nil {
+		return nil, apperrors.NewInternalError("failed to create URL import", err)
+	}
+	if !s.enqueue(item.JobID) {
+		s.fail(item, errors.New("the import queue is full"))
+		return nil, apperrors.NewConflictError("URL import", "the import queue is full, try again later")
+	}
+
+	s.logger.Info("URL import queued", zap.String("job_id", item.JobID), zap.String("url", item.URL))
+	return item, nil
+}
+
+// Get returns an import by its job ID.
+func (s *RemoteImportService) Get(jobID string) (*data.RemoteImport, error) {
+	item, err := s.repo.GetByJobID(jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("URL import", jobID)
+		}
+		return nil, apperrors.NewInternalError("failed to get URL import", err)
+	}
+	return item, nil
+}
+
+// List returns the imports, newest first.
+func (s *RemoteImportService) List(page, limit int) ([]data.RemoteImport, int64, error) {
+	items, total, err := s.repo.List(page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list URL imports", err)
+	}
+	return items, total, nil
+}
+
+// Owns reports whether a job ID is a URL import, for cancelling it from the
+// jobs page.
+func (s *RemoteImportService) Owns(jobID string) bool {
+	_, err := s.repo.GetByJobID(jobID)
+	return err == nil
+}
+
+// Cancel stops a running download, deleting the partial file, or takes a
+// queued import out of the queue.
+func (s *RemoteImportService) Cancel(jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.Get(jobID)
+	if err != nil {
+		return err
+	}
+	if item.IsFinished() {
+		return apperrors.NewValidationError("the import has already finished")
+	}
+
+	if cancel, ok := s.running[jobID]; ok {
+		// The worker records the cancellation once the download stops
+		s.cancelled[jobID] = true
+		cancel()
+		return nil
+	}
+
+	if err := s.repo.UpdateStatus(jobID, data.RemoteImportCancelled, ""); err != nil {
+		return apperrors.NewInternalError("failed to cancel URL import", err)
+	}
+	item.Status = data.RemoteImportCancelled
+	s.publish(item)
+	s.logger.Info("URL import cancelled", zap.String("job_id", jobID))
+	return nil
+}
+
+func (s *RemoteImportService) enqueue(jobID string) bool {
+	select {
+	case s.queue <- jobID:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *RemoteImportService) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case jobID := <-s.queue:
+			s.process(jobID)
+		}
+	}
+}
+
+// claim registers a queued import as running, unless it was cancelled while
+// waiting.
+func (s *RemoteImportService) claim(jobID string) (*data.RemoteImport, context.Context, context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.repo.GetByJobID(jobID)
+	if err != nil {
+		s.logger.Warn("Failed to load URL import", zap.String("job_id", jobID), zap.Error(err))
+		return nil, nil, nil
+	}
+	if item.Status != data.RemoteImportQueued {
+		return nil, nil, nil
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if s.cfg.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(s.ctx, s.cfg.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(s.ctx)
+	}
+	s.running[jobID] = cancel
+	return item, ctx, cancel
+}
+
+func (s *RemoteImportService) release(jobID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cancelled := s.cancelled[jobID]
+	delete(s.running, jobID)
+	delete(s.cancelled, jobID)
+	return cancelled
+}
+
+func (s *RemoteImportService) process(jobID string) {
+	item, ctx, cancel := s.claim(jobID)
+	if item == nil {
+		return
+	}
+	defer cancel()
+
+	now := time.Now()
+	if err := s.repo.MarkStarted(jobID, now); err != nil {
+		s.logger.Warn("Failed to mark URL import started", zap.String("job_id", jobID), zap.Error(err))
+	}
+	item.Status, item.StartedAt = data.RemoteImportDownloading, &now
+	label := item.Title
+	if label == "" {
+		label = item.URL
+	}
+	s.jobHistory.RecordJobStart(jobID, 0, label, RemoteImportPhase)
+
+	dest, storagePath, err := s.download(ctx, item)
+	if cancelled := s.release(jobID); err != nil {
+		switch {
+		case cancelled:
+			s.finishCancelled(item)
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			s.fail(item, fmt.Errorf("download timed out after %s", s.cfg.Timeout))
+		case s.ctx.Err() != nil:
+			s.fail(item, errors.New("interrupted by server shutdown"))
+		default:
+			s.fail(item, err)
+		}
+		return
+	}
+
+	item.Status, item.Filename = data.RemoteImportImporting, dest
+	if err := s.repo.MarkDownloaded(jobID, dest); err != nil {
+		s.logger.Warn("Failed to record URL import file", zap.String("job_id", jobID), zap.Error(err))
+	}
+	if err := s.scanService.ImportFile(dest, storagePath); err != nil {
+		// The file stays in the library, where the next scan picks it up
+		s.fail(item, fmt.Errorf("downloaded to %s, but the import failed: %w", dest, err))
+		return
+	}
+	if scene, err := s.sceneRepo.GetByStoredPath(dest); err == nil {
+		item.SceneID = &scene.ID
+	}
+
+	completedAt := time.Now()
+	if err := s.repo.MarkCompleted(jobID, item.SceneID, completedAt); err != nil {
+		s.logger.Warn("Failed to mark URL import completed", zap.String("job_id", jobID), zap.Error(err))
+	}
+	item.Status, item.CompletedAt = data.RemoteImportCompleted, &completedAt
+	s.jobHistory.RecordJobComplete(jobID)
+	s.publish(item)
+	s.logger.Info("URL import completed", zap.String("job_id", jobID), zap.String("path", dest))
+}
+
+func (s *RemoteImportService) fail(item *data.RemoteImport, err error) {
+	s.logger.Warn("URL import failed", zap.String("job_id", item.JobID), zap.String("url", item.URL), zap.Error(err))
+	if updateErr := s.repo.UpdateStatus(item.JobID, data.RemoteImportFailed, err.Error()); updateErr != nil {
+		s.logger.Warn("Failed to mark URL import failed", zap.String("job_id", item.JobID), zap.Error(updateErr))
+	}
+	item.Status, item.Error = data.RemoteImportFailed, err.Error()
+	// Only imports that started have a job_history entry
+	if item.StartedAt != nil {
+		s.jobHistory.RecordJobFailed(item.JobID, err)
+	}
+	s.publish(item)
+}
+
+func (s *RemoteImportService) finishCancelled(item *data.RemoteImport) {
+	if err := s.repo.UpdateStatus(item.JobID, data.RemoteImportCancelled, ""); err != nil {
+		s.logger.Warn("Failed to mark URL import cancelled", zap.String("job_id", item.JobID), zap.Error(err))
+	}
+	item.Status = data.RemoteImportCancelled
+	s.jobHistory.RecordJobCancelled(item.JobID)
+	s.publish(item)
+	s.logger.Info("URL import cancelled", zap.String("job_id", item.JobID))
+}
+
+// publish announces an import reaching a final status.
+func (s *RemoteImportService) publish(item *data.RemoteImport) {
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(SceneEvent{
+		Type: "import:" + item.Status,
+		Data: map[string]any{
+			"job_id":   item.JobID,
+			"url":      item.URL,
+			"filename": item.Filename,
+			"scene_id": item.SceneID,
+			"error":    item.Error,
+		},
+	})
+}
+
+// destination returns the storage path an import is saved in.
+func (s *RemoteImportService) destination(item *data.RemoteImport) (*data.StoragePath, error) {
+	id := s.cfg.StoragePathID
+	if item.StoragePathID != nil {
+		id = *item.StoragePathID
+	}
+
+	var storagePath *data.StoragePath
+	var err error
+	if id == 0 {
+		storagePath, err = s.storagePathService.GetDefault()
+	} else {
+		storagePath, err = s.storagePathService.GetByID(id)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("the destination storage path does not exist")
+		}
+		return nil, fmt.Errorf("failed to get the destination storage path: %w", err)
+	}
+	if s.storageHealth != nil && s.storageHealth.IsOffline(storagePath.ID) {
+		return nil, fmt.Errorf("storage path %s is offline", storagePath.Name)
+	}
+	return storagePath, nil
+}
+
+// download fetches an import into the download folder of its storage path
+// and moves it into place, returning its final path.
+func (s *RemoteImportService) download(ctx context.Context, item *data.RemoteImport) (string, *data.StoragePath, error) {
+	storagePath, err := s.destination(item)
+	if err != nil {
+		return "", nil, err
+	}
+	downloadDir := filepath.Join(storagePath.Path, remoteImportDownloadDir)
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create download folder: %w", err)
+	}
+
+	progress := &remoteImportProgress{service: s, item: item}
+	tmpPath, name, err := s.fetch(ctx, item, downloadDir, progress)
+	if err != nil {
+		if tmpPath != "" {
+			os.Remove(tmpPath)
+		}
+		return "", nil, err
+	}
+	progress.flush()
+
+	dest, err := s.placeFile(tmpPath, name, item.Title, storagePath)
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", nil, err
+	}
+	return dest, storagePath, nil
+}
+
+// fetch downloads a URL to a temporary file and returns its path with the
+// file name the source gives it. Web pages are handed to yt-dlp.
+func (s *RemoteImportService) fetch(ctx context.Context, item *data.RemoteImport, downloadDir string, progress *remoteImportProgress) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, item.URL, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "GoonHub")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("download failed: HTTP %d", resp.StatusCode)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if contentType == "text/html" || contentType == "application/xhtml+xml" {
+		if s.cfg.YtDlpPath == "" {
+			return "", "", errors.New("the URL is a web page, not a video file; set scan.remote_import.ytdlp_path to import from supported sites")
+		}
+		resp.Body.Close()
+		return s.fetchWithYtDlp(ctx, item, downloadDir, progress)
+	}
+
+	name := remoteFileName(resp, item.URL)
+	if !isVideoExtension(strings.ToLower(filepath.Ext(name))) {
+		return "", "", fmt.Errorf("%s is not a supported video file", name)
+	}
+	maxBytes := s.cfg.MaxSizeMB << 20
+	if maxBytes > 0 && resp.ContentLength > maxBytes {
+		return "", "", fmt.Errorf("the file is larger than %d MB", s.cfg.MaxSizeMB)
+	}
+	if resp.ContentLength > 0 {
+		total := resp.ContentLength
+		progress.total = &total
+	}
+
+	tmpPath := filepath.Join(downloadDir, item.JobID+".part")
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create download file: %w", err)
+	}
+
+	var body io.Reader = resp.Body
+	if s.cfg.RateLimitKB > 0 {
+		body = &rateLimitedReader{
+			ctx:     ctx,
+			r:       body,
+			limiter: rate.NewLimiter(rate.Limit(s.cfg.RateLimitKB*1024), remoteImportChunkSize),
+		}
+	}
+	if maxBytes > 0 {
+		body = io.LimitReader(body, maxBytes+1)
+	}
+	written, copyErr := io.Copy(io.MultiWriter(f, progress), body)
+	if err := f.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	if copyErr != nil {
+		return tmpPath, "", fmt.Errorf("download failed: %w", copyErr)
+	}
+	if maxBytes > 0 && written > maxBytes {
+		return tmpPath, "", fmt.Errorf("the file is larger than %d MB", s.cfg.MaxSizeMB)
+	}
+	if resp.ContentLength > 0 && written != resp.ContentLength {
+		return tmpPath, "", fmt.Errorf("download incomplete: got %d of %d bytes", written, resp.ContentLength)
+	}
+	return tmpPath, name, nil
+}
+
+// fetchWithYtDlp downloads a page URL with yt-dlp, which prints the file's
+// path and the video's title once done.
+func (s *RemoteImportService) fetchWithYtDlp(ctx context.Context, item *data.RemoteImport, downloadDir string, progress *remoteImportProgress) (string, string, error) {
+	args := []string{
+		"--newline", "--progress", "--no-playlist",
+		"-o", filepath.Join(downloadDir, item.JobID+".%(ext)s"),
+		"--print", "after_move:filepath",
+		"--print", "after_move:title",
+	}
+	if s.cfg.RateLimitKB > 0 {
+		args = append(args, "--limit-rate", fmt.Sprintf("%dK", s.cfg.RateLimitKB))
+	}
+	if s.cfg.MaxSizeMB > 0 {
+		args = append(args, "--max-filesize", fmt.Sprintf("%dM", s.cfg.MaxSizeMB))
+	}
+	args = append(args, "--", item.URL)
+
+	cmd := exec.CommandContext(ctx, s.cfg.YtDlpPath, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", "", err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return "", "", fmt.Errorf("failed to run yt-dlp: %w", err)
+	}
+
+	var printed []string
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if match := ytDlpProgressLine.FindStringSubmatch(line); match != nil {
+			percent, _ := strconv.ParseFloat(match[1], 64)
+			if match[2] != "" {
+				size, _ := strconv.ParseFloat(match[2], 64)
+				total := int64(size * ytDlpSizeUnits[match[3]])
+				progress.total = &total
+				progress.set(int64(float64(total) * percent / 100))
+			}
+			continue
+		}
+		if line != "" && !strings.HasPrefix(line, "[") {
+			printed = append(printed, line)
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return "", "", ctx.Err()
+		}
+		message := strings.TrimSpace(stderr.String())
+		if i := strings.LastIndex(message, "\n"); i >= 0 {
+			message = message[i+1:]
+		}
+		return "", "", fmt.Errorf("yt-dlp failed: %s", message)
+	}
+	if len(printed) == 0 {
+		return "", "", errors.New("yt-dlp did not download a file")
+	}
+
+	tmpPath := printed[0]
+	if !isWithin(tmpPath, downloadDir) {
+		return "", "", fmt.Errorf("yt-dlp saved the file outside the download folder: %s", tmpPath)
+	}
+	ext := strings.ToLower(filepath.Ext(tmpPath))
+	if !isVideoExtension(ext) {
+		return tmpPath, "", fmt.Errorf("yt-dlp downloaded an unsupported %s file", ext)
+	}
+	name := item.JobID + ext
+	if len(printed) > 1 {
+		name = printed[1] + ext
+	}
+	if info, err := os.Stat(tmpPath); err == nil {
+		progress.set(info.Size())
+	}
+	return tmpPath, name, nil
+}
+
+// placeFile moves a downloaded file into the configured folder of the storage
+// path, named after the title when there is one, adding " (n)" to the name of
+// a taken destination.
+func (s *RemoteImportService) placeFile(tmpPath, name, title string, storagePath *data.StoragePath) (string, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	base := sanitizePathSegment(title)
+	if base == "" {
+		base = sanitizePathSegment(strings.TrimSuffix(name, filepath.Ext(name)))
+	}
+	if base == "" {
+		base = "download"
+	}
+
+	dest := filepath.Join(storagePath.Path, s.cfg.Folder, base+ext)
+	if _, err := os.Lstat(dest); err == nil {
+		if dest = availablePath(dest); dest == "" {
+			return "", errDestinationExists
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create destination folder: %w", err)
+	}
+	if err := moveFile(tmpPath, dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// remoteFileName returns the file name of a download: the one given by the
+// Content-Disposition header, else the last segment of the URL. Without a
+// video extension, one is derived from the Content-Type.
+func remoteFileName(resp *http.Response, rawURL string) string {
+	var name string
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		name = filepath.Base(params["filename"])
+	}
+	if name == "" || name == "." || name == "/" {
+		if u, err := url.Parse(rawURL); err == nil {
+			name = path.Base(u.Path)
+		}
+	}
+	if name == "." || name == "/" {
+		name = ""
+	}
+
+	if !isVideoExtension(strings.ToLower(filepath.Ext(name))) {
+		contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if exts, err := mime.ExtensionsByType(contentType); err == nil {
+			for _, ext := range exts {
+				if isVideoExtension(ext) {
+					return name + ext
+				}
+			}
+		}
+	}
+	return name
+}
+
+// remoteImportProgress counts the bytes of a download and reports them every
+// remoteImportProgressInterval: on the import, its job and as an
+// import:progress event with the current speed.
+type remoteImportProgress struct {
+	service *RemoteImportService
+	item    *data.RemoteImport
+	total   *int64
+
+	written       int64
+	lastReport    time.Time
+	lastReportedN int64
+}
+
+func (p *remoteImportProgress) Write(b []byte) (int, error) {
+	p.set(p.written + int64(len(b)))
+	return len(b), nil
+}
+
+func (p *remoteImportProgress) set(written int64) {
+	p.written = written
+	if p.lastReport.IsZero() {
+		p.lastReport = time.Now()
+		return
+	}
+	if time.Since(p.lastReport) >= remoteImportProgressInterval {
+		p.flush()
+	}
+}
+
+func (p *remoteImportProgress) flush() {
+	now := time.Now()
+	elapsed := now.Sub(p.lastReport).Seconds()
+	var speed int64
+	if elapsed > 0 {
+		speed = int64(float64(p.written-p.lastReportedN) / elapsed)
+	}
+	p.lastReport, p.lastReportedN = now, p.written
+
+	s := p.service
+	p.item.BytesDownloaded, p.item.TotalBytes = p.written, p.total
+	if err := s.repo.UpdateProgress(p.item.JobID, p.written, p.total); err != nil {
+		s.logger.Debug("Failed to update URL import progress", zap.String("job_id", p.item.JobID), zap.Error(err))
+	}
+	percent := -1
+	if p.total != nil && *p.total > 0 {
+		percent = int(min(100, p.written*100 / *p.total))
+		s.jobHistory.UpdateProgress(p.item.JobID, percent)
+	}
+	if s.eventBus != nil {
+		s.eventBus.Publish(SceneEvent{
+			Type: "import:progress",
+			Data: map[string]any{
+				"job_id":           p.item.JobID,
+				"bytes_downloaded": p.written,
+				"total_bytes":      p.total,
+				"progress":         percent, // -1 when the size is unknown
+				"speed":            speed,   // bytes per second
+			},
+		})
+	}
+}
+
+// rateLimitedReader caps the speed of a download. Reads are kept under the
+// limiter's burst.
+type rateLimitedReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	if len(p) > remoteImportChunkSize {
+		p = p[:remoteImportChunkSize]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type remoteImportTest struct {
+	svc       *RemoteImportService
+	repo      *mocks.MockRemoteImportRepository
+	sceneRepo *mocks.MockSceneRepository
+	history   *mocks.MockJobHistoryRepository
+	library   string
+}
+
+func newTestRemoteImportService(t *testing.T, cfg config.RemoteImportConfig) *remoteImportTest {
+	ctrl := gomock.NewController(t)
+	library := t.TempDir()
+	storagePathRepo := mocks.NewMockStoragePathRepository(ctrl)
+	storagePathRepo.EXPECT().GetDefault().Return(&data.StoragePath{ID: 1, Name: "Library", Path: library, IsDefault: true}, nil).AnyTimes()
+	storagePathService := NewStoragePathService(storagePathRepo, zap.NewNop())
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	scanService := NewScanService(storagePathService, sceneRepo, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	history := mocks.NewMockJobHistoryRepository(ctrl)
+	jobHistory := NewJobHistoryService(history, config.ProcessingConfig{}, zap.NewNop())
+	repo := mocks.NewMockRemoteImportRepository(ctrl)
+
+	cfg.Enabled = true
+	svc := NewRemoteImportService(cfg, repo, storagePathService, scanService, sceneRepo, jobHistory, nil, zap.NewNop())
+	return &remoteImportTest{svc: svc, repo: repo, sceneRepo: sceneRepo, history: history, library: library}
+}
+
+func TestRemoteImportService_Download(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+		w.Header().Set("Content-Disposition", `attachment; filename="clip.mp4"`)
+		w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	tt := newTestRemoteImportService(t, config.RemoteImportConfig{Folder: "downloads"})
+	item := &data.RemoteImport{JobID: "job-1", URL: server.URL + "/get?id=1", Title: "My: Scene", Status: data.RemoteImportQueued}
+	dest := filepath.Join(tt.library, "downloads", "My Scene.mp4")
+
+	tt.repo.EXPECT().GetByJobID("job-1").Return(item, nil)
+	tt.repo.EXPECT().MarkStarted("job-1", gomock.Any()).Return(nil)
+	tt.history.EXPECT().Create(gomock.Any()).Return(nil)
+	tt.repo.EXPECT().UpdateProgress("job-1", int64(10), gomock.Any()).Return(nil)
+	tt.history.EXPECT().UpdateProgress("job-1", 100).Return(nil)
+	tt.repo.EXPECT().MarkDownloaded("job-1", dest).Return(nil)
+	tt.sceneRepo.EXPECT().ExistsByStoredPath(dest).Return(false, nil)
+	tt.sceneRepo.EXPECT().GetBySizeAndFilename(int64(10), "My Scene.mp4").Return(nil, nil)
+	tt.sceneRepo.EXPECT().Create(gomock.Any()).Return(nil)
+	tt.sceneRepo.EXPECT().GetByStoredPath(dest).Return(&data.Scene{ID: 42}, nil)
+	tt.repo.EXPECT().MarkCompleted("job-1", gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, sceneID *uint, _ any) error {
+		if sceneID == nil || *sceneID != 42 {
+			t.Fatalf("expected the import linked to scene 42, got %v", sceneID)
+		}
+		return nil
+	})
+	tt.history.EXPECT().UpdateStatus("job-1", "completed", nil, gomock.Any()).Return(nil)
+
+	tt.svc.process("job-1")
+
+	if item.Status != data.RemoteImportCompleted {
+		t.Fatalf("expected the import completed, got %q (%s)", item.Status, item.Error)
+	}
+	if content, _ := os.ReadFile(dest); string(content) != "0123456789" {
+		t.Fatalf("unexpected content %q", content)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(tt.library, remoteImportDownloadDir)); len(entries) != 0 {
+		t.Fatalf("expected no partial download left, got %d files", len(entries))
+	}
+}
+
+func TestRemoteImportService_RejectsWebPageWithoutYtDlp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	tt := newTestRemoteImportService(t, config.RemoteImportConfig{})
+	item := &data.RemoteImport{JobID: "job-1", URL: server.URL + "/watch", Status: data.RemoteImportQueued}
+
+	tt.repo.EXPECT().GetByJobID("job-1").Return(item, nil)
+	tt.repo.EXPECT().MarkStarted("job-1", gomock.Any()).Return(nil)
+	tt.history.EXPECT().Create(gomock.Any()).Return(nil)
+	tt.repo.EXPECT().UpdateStatus("job-1", data.RemoteImportFailed, gomock.Any()).Return(nil)
+	tt.history.EXPECT().UpdateStatus("job-1", "failed", gomock.Any(), gomock.Any()).Return(nil)
+
+	tt.svc.process("job-1")
+
+	if item.Status != data.RemoteImportFailed || item.Error == "" {
+		t.Fatalf("expected the web page to be rejected, got %q", item.Status)
+	}
+}
+
+func TestRemoteImportService_ConcurrentImportsRecordedInHistory(t *testing.T) {
+	// Both downloads are answered only once both started, so both imports are
+	// active in job history at the same time
+	var started sync.WaitGroup
+	started.Add(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started.Done()
+		started.Wait()
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	tt := newTestRemoteImportService(t, config.RemoteImportConfig{Workers: 2})
+
+	// Stand in for idx_job_history_active_key
+	var mu sync.Mutex
+	active := map[string]string{}
+	recorded := 0
+	tt.history.EXPECT().Create(gomock.Any()).Times(2).DoAndReturn(func(record *data.JobHistory) error {
+		record.BeforeCreate(nil)
+		key := fmt.Sprintf("%d:%s:%s", record.SceneID, record.Phase, record.ParamsHash)
+		mu.Lock()
+		defer mu.Unlock()
+		if other, ok := active[key]; ok {
+			t.Errorf("job %s has the active key of job %s", record.JobID, other)
+			return errors.New("duplicate key value violates unique constraint")
+		}
+		active[key] = record.JobID
+		recorded++
+		return nil
+	})
+	tt.history.EXPECT().UpdateStatus(gomock.Any(), "failed", gomock.Any(), gomock.Any()).Times(2).Return(nil)
+
+	var wg sync.WaitGroup
+	for _, jobID := range []string{"job-1", "job-2"} {
+		item := &data.RemoteImport{JobID: jobID, URL: server.URL + "/" + jobID, Status: data.RemoteImportQueued}
+		tt.repo.EXPECT().GetByJobID(jobID).Return(item, nil)
+		tt.repo.EXPECT().MarkStarted(jobID, gomock.Any()).Return(nil)
+		tt.repo.EXPECT().UpdateStatus(jobID, data.RemoteImportFailed, gomock.Any()).Return(nil)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tt.svc.process(jobID)
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("imports did not finish")
+	}
+	if recorded != 2 {
+		t.Fatalf("expected both imports recorded in job history, got %d", recorded)
+	}
+}
+
+func TestRemoteImportService_Submit(t *testing.T) {
+	tt := newTestRemoteImportService(t, config.RemoteImportConfig{})
+
+	if _, err := tt.svc.Submit(1, "ftp://example.com/clip.mp4", "", nil); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a non-http URL to be rejected, got %v", err)
+	}
+
+	tt.repo.EXPECT().Create(gomock.Any()).Return(nil)
+	item, err := tt.svc.Submit(1, " https://example.com/clip.mp4 ", "Title", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Status != data.RemoteImportQueued || item.URL != "https://example.com/clip.mp4" || item.UserID == nil {
+		t.Fatalf("unexpected import %+v", item)
+	}
+
+	// Cancelling before a worker picks it up skips the download
+	tt.repo.EXPECT().GetByJobID(item.JobID).Return(item, nil)
+	tt.repo.EXPECT().UpdateStatus(item.JobID, data.RemoteImportCancelled, "").Return(nil)
+	if err := tt.svc.Cancel(item.JobID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancelled := *item
+	tt.repo.EXPECT().GetByJobID(item.JobID).Return(&cancelled, nil)
+	tt.svc.process(item.JobID)
+}
+
+func TestRemoteFileName(t *testing.T) {
+	tests := []struct {
+		disposition string
+		contentType string
+		url         string
+		want        string
+	}{
+		{`attachment; filename="../clip.mkv"`, "video/x-matroska", "https://example.com/download", "clip.mkv"},
+		{"", "video/mp4", "https://example.com/videos/clip.mp4?token=1", "clip.mp4"},
+		{"", "video/webm", "https://example.com/videos/clip", "clip.webm"},
+		{"", "application/octet-stream", "https://example.com/", ""},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Content-Disposition", tt.disposition)
+		resp.Header.Set("Content-Type", tt.contentType)
+		if got := remoteFileName(resp, tt.url); got != tt.want {
+			t.Fatalf("%s: expected %q, got %q", tt.url, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// requestStatsWindow is how far back endpoint latency stats are kept
+	requestStatsWindow = 24 * time.Hour
+	// requestStatsBucket is the granularity stats age out at
+	requestStatsBucket = time.Hour
+)
+
+// Timing kinds recorded against a request context
+const (
+	TimingDB     = "db"
+	TimingSearch = "search"
+)
+
+type requestTimingsKey struct{}
+
+// RequestTimings accumulates time spent in backing stores while serving one request
+type RequestTimings struct {
+	mu     sync.Mutex
+	totals map[string]time.Duration
+	counts map[string]int
+}
+
+// WithRequestTimings returns a context carrying a fresh RequestTimings accumulator
+func WithRequestTimings(ctx context.Context) (context.Context, *RequestTimings) {
+	t := &RequestTimings{
+		totals: make(map[string]time.Duration),
+		counts: make(map[string]int),
+	}
+	return context.WithValue(ctx, requestTimingsKey{}, t), t
+}
+
+// TrackTiming starts timing an operation of the given kind and returns the function
+// that stops it. It is a no-op when the context carries no RequestTimings.
+func TrackTiming(ctx context.Context, kind string) func() {
+	t, _ := ctx.Value(requestTimingsKey{}).(*RequestTimings)
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		t.Add(kind, time.Since(start))
+	}
+}
+
+// Add records one operation of the given kind
+func (t *RequestTimings) Add(kind string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.totals[kind] += d
+	t.counts[kind]++
+}
+
+// Total returns the accumulated duration and operation count for a kind
+func (t *RequestTimings) Total(kind string) (time.Duration, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.totals[kind], t.counts[kind]
+}
+
+// EndpointLatency summarises the latency of one route over the stats window
+type EndpointLatency struct {
+	Method    string  `json:"method"`
+	Route     string  `json:"route"`
+	Count     int64   `json:"count"`
+	SlowCount int64   `json:"slow_count"`
+	ErrorRate float64 `json:"error_rate"`
+	AvgMs     float64 `json:"avg_ms"`
+	MaxMs     float64 `json:"max_ms"`
+}
+
+type endpointKey struct {
+	method string
+	route  string
+}
+
+type endpointAgg struct {
+	count     int64
+	slowCount int64
+	errors    int64
+	total     time.Duration
+	max       time.Duration
+}
+
+type requestStatsBucketData struct {
+	start     time.Time
+	endpoints map[endpointKey]*endpointAgg
+}
+
+// RequestStatsService keeps per-route latency aggregates in hourly buckets so the
+// slowest endpoints of the last 24h can be listed without persisting every request.
+type RequestStatsService struct {
+	slowThreshold time.Duration
+
+	mu      sync.Mutex
+	buckets []*requestStatsBucketData
+	now     func() time.Time
+}
+
+// NewRequestStatsService creates a new RequestStatsService
+func NewRequestStatsService(slowThreshold time.Duration) *RequestStatsService {
+	return &RequestStatsService{
+		slowThreshold: slowThreshold,
+		now:           time.Now,
+	}
+}
+
+// SlowThreshold returns the latency above which a request counts as slow (0 = disabled)
+func (s *RequestStatsService) SlowThreshold() time.Duration {
+	return s.slowThreshold
+}
+
+// IsSlow reports whether a request latency is over the slow threshold
+func (s *RequestStatsService) IsSlow(latency time.Duration) bool {
+	return s.slowThreshold > 0 && latency >= s.slowThreshold
+}
+
+// Record adds a completed request to the current bucket. Requests that matched
+// no route are ignored so arbitrary paths cannot grow the stats unboundedly.
+func (s *RequestStatsService) Record(method, route string, status int, latency time.Duration) {
+	if route == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket := s.currentBucketLocked()
+	key := endpointKey{method: method, route: route}
+	agg, ok := bucket.endpoints[key]
+	if !ok {
+		agg = &endpointAgg{}
+		bucket.endpoints[key] = agg
+	}
+
+	agg.count++
+	agg.total += latency
+	if latency > agg.max {
+		agg.max = latency
+	}
+	if s.IsSlow(latency) {
+		agg.slowCount++
+	}
+	if status >= 500 {
+		agg.errors++
+	}
+}
+
+// Slowest returns up to limit routes seen in the last 24h, ordered by average latency
+func (s *RequestStatsService) Slowest(limit int) []EndpointLatency {
+	s.mu.Lock()
+	s.pruneLocked()
+	merged := make(map[endpointKey]*endpointAgg)
+	for _, bucket := range s.buckets {
+		for key, agg := range bucket.endpoints {
+			m, ok := merged[key]
+			if !ok {
+				m = &endpointAgg{}
+				merged[key] = m
+			}
+			m.count += agg.count
+			m.slowCount += agg.slowCount
+			m.errors += agg.errors
+			m.total += agg.total
+			if agg.max > m.max {
+				m.max = agg.max
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	result := make([]EndpointLatency, 0, len(merged))
+	for key, agg := range merged {
+		result = append(result, EndpointLatency{
+			Method:    key.method,
+			Route:     key.route,
+			Count:     agg.count,
+			SlowCount: agg.slowCount,
+			ErrorRate: float64(agg.errors) / float64(agg.count),
+			AvgMs:     durationMs(agg.total / time.Duration(agg.count)),
+			MaxMs:     durationMs(agg.max),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AvgMs != result[j].AvgMs {
+			return result[i].AvgMs > result[j].AvgMs
+		}
+		if result[i].Route != result[j].Route {
+			return result[i].Route < result[j].Route
+		}
+		return result[i].Method < result[j].Method
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func (s *RequestStatsService) currentBucketLocked() *requestStatsBucketData {
+	start := s.now().Truncate(requestStatsBucket)
+	if n := len(s.buckets); n > 0 && s.buckets[n-1].start.Equal(start) {
+		return s.buckets[n-1]
+	}
+
+	s.pruneLocked()
+	bucket := &requestStatsBucketData{
+		start:     start,
+		endpoints: make(map[endpointKey]*endpointAgg),
+	}
+	s.buckets = append(s.buckets, bucket)
+	return bucket
+}
+
+func (s *RequestStatsService) pruneLocked() {
+	cutoff := s.now().Add(-requestStatsWindow)
+	i := 0
+	for i < len(s.buckets) && !s.buckets[i].start.Add(requestStatsBucket).After(cutoff) {
+		i++
+	}
+	s.buckets = s.buckets[i:]
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
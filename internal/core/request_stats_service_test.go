@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRequestStats_SlowestOrdersByAverage(t *testing.T) {
+	svc := NewRequestStatsService(500 * time.Millisecond)
+
+	svc.Record("GET", "/api/v1/scenes", 200, 100*time.Millisecond)
+	svc.Record("GET", "/api/v1/scenes", 200, 300*time.Millisecond)
+	svc.Record("GET", "/api/v1/search", 200, 900*time.Millisecond)
+	svc.Record("POST", "/api/v1/scenes", 500, 50*time.Millisecond)
+
+	stats := svc.Slowest(10)
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 endpoints, got %d", len(stats))
+	}
+	if stats[0].Route != "/api/v1/search" || stats[0].SlowCount != 1 {
+		t.Fatalf("expected search first with 1 slow request, got %+v", stats[0])
+	}
+	if stats[1].Route != "/api/v1/scenes" || stats[1].Method != "GET" {
+		t.Fatalf("expected GET scenes second, got %+v", stats[1])
+	}
+	if stats[1].Count != 2 || stats[1].AvgMs != 200 || stats[1].MaxMs != 300 {
+		t.Fatalf("unexpected aggregate for GET scenes: %+v", stats[1])
+	}
+	if stats[2].ErrorRate != 1 {
+		t.Fatalf("expected error rate 1 for POST scenes, got %v", stats[2].ErrorRate)
+	}
+
+	if limited := svc.Slowest(1); len(limited) != 1 {
+		t.Fatalf("expected limit to apply, got %d", len(limited))
+	}
+}
+
+func TestRequestStats_IgnoresUnmatchedRoutes(t *testing.T) {
+	svc := NewRequestStatsService(time.Second)
+	svc.Record("GET", "", 404, time.Second)
+
+	if stats := svc.Slowest(10); len(stats) != 0 {
+		t.Fatalf("expected no endpoints, got %+v", stats)
+	}
+}
+
+func TestRequestStats_DropsRequestsOlderThanWindow(t *testing.T) {
+	svc := NewRequestStatsService(time.Second)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	svc.Record("GET", "/api/v1/old", 200, 2*time.Second)
+	now = now.Add(23 * time.Hour)
+	svc.Record("GET", "/api/v1/new", 200, time.Second)
+
+	if stats := svc.Slowest(10); len(stats) != 2 {
+		t.Fatalf("expected both endpoints within 24h, got %d", len(stats))
+	}
+
+	now = now.Add(2 * time.Hour)
+	stats := svc.Slowest(10)
+	if len(stats) != 1 || stats[0].Route != "/api/v1/new" {
+		t.Fatalf("expected only the recent endpoint, got %+v", stats)
+	}
+}
+
+func TestRequestStats_IsSlowDisabledWithZeroThreshold(t *testing.T) {
+	svc := NewRequestStatsService(0)
+	if svc.IsSlow(time.Hour) {
+		t.Fatal("expected zero threshold to disable slow detection")
+	}
+}
+
+func TestTrackTiming(t *testing.T) {
+	ctx, timings := WithRequestTimings(context.Background())
+
+	TrackTiming(ctx, TimingDB)()
+	TrackTiming(ctx, TimingDB)()
+	TrackTiming(ctx, TimingSearch)()
+
+	if _, n := timings.Total(TimingDB); n != 2 {
+		t.Fatalf("expected 2 db operations, got %d", n)
+	}
+	if _, n := timings.Total(TimingSearch); n != 1 {
+		t.Fatalf("expected 1 search operation, got %d", n)
+	}
+
+	// Untracked contexts must be safe to time against
+	TrackTiming(context.Background(), TimingDB)()
+}
@@ -3,6 +3,9 @@ package core
 import (
 	"context"
 	"math"
+	"math/rand"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +28,8 @@ type RetryScheduler struct {
 	configCache   map[string]data.RetryConfigRecord
 	configCacheMu sync.RWMutex
 
+	dlqRetention time.Duration // abandoned DLQ entries are deleted after this; 0 keeps them
+
 	cancel     context.CancelFunc
 	pollTicker *time.Ticker
 }
@@ -59,6 +64,12 @@ func (rs *RetryScheduler) SetJobHistoryService(svc *JobHistoryService) {
 	rs.jobHistoryService = svc
 }
 
+// SetDLQRetention sets how long abandoned DLQ entries are kept before the
+// hourly cleanup deletes them. 0 keeps them forever.
+func (rs *RetryScheduler) SetDLQRetention(retention time.Duration) {
+	rs.dlqRetention = retention
+}
+
 // Start begins the retry scheduler's background polling.
 func (rs *RetryScheduler) Start() {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -139,22 +150,83 @@ func (rs *RetryScheduler) GetConfigForPhase(phase string) data.RetryConfigRecord
 		InitialDelaySeconds: 30,
 		MaxDelaySeconds:     3600,
 		BackoffFactor:       2.0,
+		BackoffStrategy:     data.BackoffExponential,
+		JitterPercent:       20,
+		NeverRetryErrors:    []string{data.RetryErrorNotFound},
+		AlwaysRetryErrors:   []string{data.RetryErrorTimeout},
+		RetryUnclassified:   true,
 	}
 }
 
 // CalculateNextRetryTime calculates the next retry time based on retry count.
+// The phase's jitter adds a random extra delay, so jobs that failed together
+// don't all retry at once; it never makes a retry come sooner.
 func (rs *RetryScheduler) CalculateNextRetryTime(phase string, retryCount int) time.Time {
 	cfg := rs.GetConfigForPhase(phase)
 
-	// Calculate delay with exponential backoff
-	delay := float64(cfg.InitialDelaySeconds) * math.Pow(cfg.BackoffFactor, float64(retryCount))
+	var delay float64
+	switch cfg.BackoffStrategy {
+	case data.BackoffFixed:
+		delay = float64(cfg.InitialDelaySeconds)
+	case data.BackoffLinear:
+		delay = float64(cfg.InitialDelaySeconds) * float64(retryCount+1)
+	default:
+		delay = float64(cfg.InitialDelaySeconds) * math.Pow(cfg.BackoffFactor, float64(retryCount))
+	}
+
+	if cfg.JitterPercent > 0 {
+		delay += delay * float64(cfg.JitterPercent) / 100 * rand.Float64()
+	}
 
 	// Cap at max delay
 	if delay > float64(cfg.MaxDelaySeconds) {
 		delay = float64(cfg.MaxDelaySeconds)
 	}
 
-	return time.Now().Add(time.Duration(delay) * time.Second)
+	return time.Now().Add(time.Duration(delay * float64(time.Second)))
+}
+
+// retryErrorPatterns maps the error classes to substrings of the errors they
+// cover, matched case-insensitively. The first matching class wins.
+var retryErrorPatterns = []struct {
+	class    string
+	patterns []string
+}{
+	{data.RetryErrorNotFound, []string{"no such file", "file not found", "does not exist"}},
+	{data.RetryErrorTimeout, []string{"timed out", "timeout", "deadline exceeded"}},
+	{data.RetryErrorPermission, []string{"permission denied", "operation not permitted", "access denied"}},
+	{data.RetryErrorCorrupt, []string{"invalid data found", "moov atom not found", "corrupt", "unexpected end of file", "could not find codec"}},
+	{data.RetryErrorResources, []string{"no space left", "too many open files", "cannot allocate memory", "out of memory"}},
+}
+
+// ClassifyJobError returns the error class of a failed job's error, or ""
+// when it matches none.
+func ClassifyJobError(errorMsg string) string {
+	msg := strings.ToLower(errorMsg)
+	for _, c := range retryErrorPatterns {
+		for _, pattern := range c.patterns {
+			if strings.Contains(msg, pattern) {
+				return c.class
+			}
+		}
+	}
+	return ""
+}
+
+// shouldRetry applies a phase's error class rules to a failed job's error:
+// never_retry_errors go to the DLQ, always_retry_errors are retried, and
+// anything else follows retry_unclassified.
+func shouldRetry(cfg data.RetryConfigRecord, errorMsg string) (bool, string) {
+	class := ClassifyJobError(errorMsg)
+	if class != "" {
+		if slices.Contains(cfg.NeverRetryErrors, class) {
+			return false, class
+		}
+		if slices.Contains(cfg.AlwaysRetryErrors, class) {
+			return true, class
+		}
+	}
+	return cfg.RetryUnclassified, class
 }
 
 // ScheduleRetry schedules a retry for a failed job.
@@ -174,6 +246,22 @@ func (rs *RetryScheduler) ScheduleRetry(jobID string, phase string, sceneID uint
 		return rs.moveToDLQ(jobID, phase, sceneID, errorMsg, retryCount)
 	}
 
+	// Errors the phase's policy won't retry go straight to the DLQ
+	if retry, class := shouldRetry(cfg, errorMsg); !retry {
+		rs.logger.Info("Not retrying job, its error is excluded by the retry policy",
+			zap.String("job_id", jobID),
+			zap.String("phase", phase),
+			zap.String("error_class", class),
+		)
+		if err := rs.jobHistoryRepo.UpdateRetryInfo(jobID, retryCount, cfg.MaxRetries, nil); err != nil {
+			rs.logger.Warn("Failed to update final retry info before DLQ",
+				zap.String("job_id", jobID),
+				zap.Error(err),
+			)
+		}
+		return rs.moveToDLQ(jobID, phase, sceneID, errorMsg, retryCount)
+	}
+
 	// Calculate next retry time
 	nextRetryAt := rs.CalculateNextRetryTime(phase, retryCount)
 
@@ -347,7 +435,8 @@ func (rs *RetryScheduler) retryJob(job data.JobHistory) {
 	)
 }
 
-// cleanupOldDLQEntries auto-abandons DLQ entries older than 7 days.
+// cleanupOldDLQEntries auto-abandons DLQ entries older than 7 days and
+// deletes abandoned entries past the DLQ retention.
 func (rs *RetryScheduler) cleanupOldDLQEntries() {
 	abandoned, err := rs.dlqRepo.AutoAbandon(7 * 24 * time.Hour)
 	if err != nil {
@@ -358,6 +447,18 @@ func (rs *RetryScheduler) cleanupOldDLQEntries() {
 	if abandoned > 0 {
 		rs.logger.Info("Auto-abandoned old DLQ entries", zap.Int64("count", abandoned))
 	}
+
+	if rs.dlqRetention <= 0 {
+		return
+	}
+	purged, err := rs.dlqRepo.PurgeAbandoned(rs.dlqRetention)
+	if err != nil {
+		rs.logger.Error("Failed to purge abandoned DLQ entries", zap.Error(err))
+		return
+	}
+	if purged > 0 {
+		rs.logger.Info("Purged abandoned DLQ entries", zap.Int64("count", purged))
+	}
 }
 
 // RefreshConfigCache refreshes the retry configuration cache.
@@ -110,7 +110,7 @@ func TestRetryScheduler_ScheduleRetry_WithinMaxRetries(t *testing.T) {
 
 	// Setup config
 	retryConfigRepo.EXPECT().GetAll().Return([]data.RetryConfigRecord{
-		{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 30, MaxDelaySeconds: 3600, BackoffFactor: 2.0},
+		{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 30, MaxDelaySeconds: 3600, BackoffFactor: 2.0, RetryUnclassified: true},
 	}, nil)
 	if err := svc.refreshConfigCache(); err != nil {
 		t.Fatalf("expected no error, got: %v", err)
@@ -131,7 +131,7 @@ func TestRetryScheduler_ScheduleRetry_LastRetryBeforeDLQ(t *testing.T) {
 
 	// Setup config with MaxRetries=3
 	retryConfigRepo.EXPECT().GetAll().Return([]data.RetryConfigRecord{
-		{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 30, MaxDelaySeconds: 3600, BackoffFactor: 2.0},
+		{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 30, MaxDelaySeconds: 3600, BackoffFactor: 2.0, RetryUnclassified: true},
 	}, nil)
 	if err := svc.refreshConfigCache(); err != nil {
 		t.Fatalf("expected no error, got: %v", err)
@@ -151,7 +151,7 @@ func TestRetryScheduler_ScheduleRetry_ExhaustedRetries(t *testing.T) {
 
 	// Setup config with MaxRetries=3
 	retryConfigRepo.EXPECT().GetAll().Return([]data.RetryConfigRecord{
-		{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 30, MaxDelaySeconds: 3600, BackoffFactor: 2.0},
+		{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 30, MaxDelaySeconds: 3600, BackoffFactor: 2.0, RetryUnclassified: true},
 	}, nil)
 	if err := svc.refreshConfigCache(); err != nil {
 		t.Fatalf("expected no error, got: %v", err)
@@ -222,3 +222,90 @@ func TestRetryScheduler_RefreshConfigCache(t *testing.T) {
 		t.Fatalf("expected max_retries 10 after refresh, got %d", cfg.MaxRetries)
 	}
 }
+
+func TestRetryScheduler_CleanupOldDLQEntries(t *testing.T) {
+	svc, _, dlqRepo, _, _ := newTestRetryScheduler(t)
+
+	// Without a retention abandoned entries are kept
+	dlqRepo.EXPECT().AutoAbandon(7*24*time.Hour).Return(int64(0), nil)
+	svc.cleanupOldDLQEntries()
+
+	svc.SetDLQRetention(30 * 24 * time.Hour)
+	dlqRepo.EXPECT().AutoAbandon(7*24*time.Hour).Return(int64(2), nil)
+	dlqRepo.EXPECT().PurgeAbandoned(30*24*time.Hour).Return(int64(5), nil)
+	svc.cleanupOldDLQEntries()
+}
+
+func TestRetryScheduler_CalculateNextRetry_Strategies(t *testing.T) {
+	svc, _, _, retryConfigRepo, _ := newTestRetryScheduler(t)
+
+	retryConfigRepo.EXPECT().GetAll().Return([]data.RetryConfigRecord{
+		{Phase: "metadata", InitialDelaySeconds: 10, MaxDelaySeconds: 3600, BackoffFactor: 2.0, BackoffStrategy: data.BackoffLinear},
+		{Phase: "sprites", InitialDelaySeconds: 10, MaxDelaySeconds: 3600, BackoffFactor: 2.0, BackoffStrategy: data.BackoffFixed},
+		{Phase: "thumbnail", InitialDelaySeconds: 100, MaxDelaySeconds: 3600, BackoffFactor: 2.0, JitterPercent: 50},
+	}, nil)
+	if err := svc.refreshConfigCache(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	within := func(phase string, retryCount int, min, max time.Duration) {
+		t.Helper()
+		now := time.Now()
+		delay := svc.CalculateNextRetryTime(phase, retryCount).Sub(now)
+		if delay < min-time.Second || delay > max+time.Second {
+			t.Fatalf("%s retry %d: expected a delay between %v and %v, got %v", phase, retryCount, min, max, delay)
+		}
+	}
+	within("metadata", 3, 40*time.Second, 40*time.Second)
+	within("sprites", 3, 10*time.Second, 10*time.Second)
+	// Jitter only ever adds to the delay
+	for range 20 {
+		within("thumbnail", 0, 100*time.Second, 150*time.Second)
+	}
+}
+
+func TestRetryScheduler_ScheduleRetry_NeverRetryError(t *testing.T) {
+	svc, jobHistoryRepo, dlqRepo, retryConfigRepo, sceneRepo := newTestRetryScheduler(t)
+
+	retryConfigRepo.EXPECT().GetAll().Return([]data.RetryConfigRecord{
+		{Phase: "metadata", MaxRetries: 3, InitialDelaySeconds: 30, MaxDelaySeconds: 3600, BackoffFactor: 2.0, NeverRetryErrors: []string{data.RetryErrorNotFound}, RetryUnclassified: true},
+	}, nil)
+	if err := svc.refreshConfigCache(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// The first failure goes straight to the DLQ
+	jobHistoryRepo.EXPECT().UpdateRetryInfo("job-123", 0, 3, nil).Return(nil)
+	jobHistoryRepo.EXPECT().MarkNotRetryable("job-123").Return(nil)
+	sceneRepo.EXPECT().GetByID(uint(1)).Return(&data.Scene{ID: 1, Title: "Test Scene"}, nil)
+	dlqRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+	err := svc.ScheduleRetry("job-123", "metadata", 1, 0, "open /videos/a.mp4: no such file or directory")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	cfg := data.RetryConfigRecord{
+		NeverRetryErrors:  []string{data.RetryErrorNotFound},
+		AlwaysRetryErrors: []string{data.RetryErrorTimeout},
+		RetryUnclassified: false,
+	}
+	tests := []struct {
+		err   string
+		retry bool
+		class string
+	}{
+		{"stat /v/a.mp4: no such file or directory", false, data.RetryErrorNotFound},
+		{"ffprobe: context deadline exceeded", true, data.RetryErrorTimeout},
+		{"moov atom not found", false, data.RetryErrorCorrupt},
+		{"something odd", false, ""},
+	}
+	for _, tt := range tests {
+		retry, class := shouldRetry(cfg, tt.err)
+		if retry != tt.retry || class != tt.class {
+			t.Fatalf("%q: expected (%v, %q), got (%v, %q)", tt.err, tt.retry, tt.class, retry, class)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// ReviewWorkflow is the configured review states with their allowed transitions.
+type ReviewWorkflow struct {
+	States       []string            `json:"states"`
+	InitialState string              `json:"initial_state"`
+	Transitions  map[string][]string `json:"transitions,omitempty"` // omitted when any transition is allowed
+}
+
+// ReviewStateCount is the number of library scenes in one review state.
+type ReviewStateCount struct {
+	State string `json:"state"`
+	Count int64  `json:"count"`
+}
+
+// ReviewCounts summarises how far the library is through the review workflow.
+// Scenes whose stored state is no longer configured are counted as Unknown.
+type ReviewCounts struct {
+	States  []ReviewStateCount `json:"states"`
+	Unknown int64              `json:"unknown"`
+	Total   int64              `json:"total"`
+}
+
+// ReviewWorkflowService moves scenes through the configured review states, for
+// example unreviewed -> needs_metadata -> curated while cleaning up an import.
+// Scenes with an empty stored state are in the initial state.
+type ReviewWorkflowService struct {
+	sceneRepo data.SceneRepository
+	cfg       config.ReviewWorkflowConfig
+	eventBus  *EventBus
+	logger    *zap.Logger
+}
+
+func NewReviewWorkflowService(sceneRepo data.SceneRepository, cfg config.ReviewWorkflowConfig, eventBus *EventBus, logger *zap.Logger) *ReviewWorkflowService {
+	return &ReviewWorkflowService{
+		sceneRepo: sceneRepo,
+		cfg:       cfg,
+		eventBus:  eventBus,
+		logger:    logger,
+	}
+}
+
+// Workflow returns the configured states and transitions.
+func (s *ReviewWorkflowService) Workflow() ReviewWorkflow {
+	return ReviewWorkflow{
+		States:       s.cfg.States,
+		InitialState: s.initialState(),
+		Transitions:  s.cfg.Transitions,
+	}
+}
+
+func (s *ReviewWorkflowService) initialState() string {
+	if len(s.cfg.States) == 0 {
+		return ""
+	}
+	return s.cfg.States[0]
+}
+
+// StateOf returns the effective review state of a scene.
+func (s *ReviewWorkflowService) StateOf(scene *data.Scene) string {
+	if scene.ReviewState == "" {
+		return s.initialState()
+	}
+	return scene.ReviewState
+}
+
+func (s *ReviewWorkflowService) isState(state string) bool {
+	return slices.Contains(s.cfg.States, state)
+}
+
+// canTransition reports whether a scene may move from one state to another.
+// Scenes left in a state that is no longer configured may move anywhere.
+func (s *ReviewWorkflowService) canTransition(from, to string) bool {
+	if from == to || len(s.cfg.Transitions) == 0 || !s.isState(from) {
+		return true
+	}
+	return slices.Contains(s.cfg.Transitions[from], to)
+}
+
+// SetState moves a single scene to state.
+func (s *ReviewWorkflowService) SetState(sceneID uint, state string) error {
+	_, err := s.BulkSetState([]uint{sceneID}, state)
+	return err
+}
+
+// BulkSetState moves scenes to state. Nothing is changed when any scene is
+// missing or any move is not an allowed transition.
+func (s *ReviewWorkflowService) BulkSetState(sceneIDs []uint, state string) (int, error) {
+	if len(sceneIDs) == 0 {
+		return 0, apperrors.NewValidationError("at least one scene ID is required")
+	}
+	if !s.isState(state) {
+		return 0, apperrors.NewValidationErrorWithField("state", fmt.Sprintf("unknown review state %q", state))
+	}
+
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	if err != nil {
+		return 0, apperrors.NewInternalError("failed to get scenes", err)
+	}
+	found := make(map[uint]bool, len(scenes))
+	for _, scene := range scenes {
+		found[scene.ID] = true
+	}
+	for _, id := range sceneIDs {
+		if !found[id] {
+			return 0, apperrors.ErrSceneNotFound(id)
+		}
+	}
+
+	rejected := make(map[string]string)
+	for i := range scenes {
+		if from := s.StateOf(&scenes[i]); !s.canTransition(from, state) {
+			rejected[strconv.FormatUint(uint64(scenes[i].ID), 10)] = fmt.Sprintf("cannot move from %s to %s", from, state)
+		}
+	}
+	if len(rejected) > 0 {
+		return 0, apperrors.NewValidationErrorWithDetails("review state transition not allowed", rejected)
+	}
+
+	ids := make([]uint, len(scenes))
+	for i, scene := range scenes {
+		ids[i] = scene.ID
+	}
+	if err := s.sceneRepo.UpdateReviewState(ids, state); err != nil {
+		return 0, apperrors.NewInternalError("failed to update review state", err)
+	}
+
+	if s.eventBus != nil {
+		if len(ids) == 1 {
+			s.eventBus.Publish(SceneEvent{
+				Type:    "scene:review_state_changed",
+				SceneID: ids[0],
+				Data:    map[string]any{"review_state": state},
+			})
+		} else {
+			s.eventBus.Publish(SceneEvent{
+				Type:    "scenes_bulk_updated",
+				SceneID: 0,
+			})
+		}
+	}
+
+	s.logger.Info("Review state updated",
+		zap.Int("scenes", len(ids)),
+		zap.String("state", state),
+	)
+	return len(ids), nil
+}
+
+// StoredStates returns the review_state values stored for scenes in state. The
+// initial state also matches scenes that were never moved (empty state).
+func (s *ReviewWorkflowService) StoredStates(state string) ([]string, error) {
+	if !s.isState(state) {
+		return nil, apperrors.NewValidationErrorWithField("review_state", fmt.Sprintf("unknown review state %q", state))
+	}
+	if state == s.initialState() {
+		return []string{state, ""}, nil
+	}
+	return []string{state}, nil
+}
+
+// GetCounts returns the number of library scenes in each configured state.
+func (s *ReviewWorkflowService) GetCounts() (*ReviewCounts, error) {
+	stored, err := s.sceneRepo.CountByReviewState()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to count scenes by review state", err)
+	}
+
+	counts := &ReviewCounts{States: make([]ReviewStateCount, len(s.cfg.States))}
+	for i, state := range s.cfg.States {
+		counts.States[i] = ReviewStateCount{State: state, Count: stored[state]}
+	}
+	if len(counts.States) > 0 {
+		counts.States[0].Count += stored[""]
+	}
+	for state, n := range stored {
+		counts.Total += n
+		if state != "" && !s.isState(state) {
+			counts.Unknown += n
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestReviewWorkflowService(t *testing.T, transitions map[string][]string) (*ReviewWorkflowService, *mocks.MockSceneRepository) {
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	cfg := config.ReviewWorkflowConfig{
+		States:      []string{"unreviewed", "needs_metadata", "curated"},
+		Transitions: transitions,
+	}
+	return NewReviewWorkflowService(sceneRepo, cfg, nil, zap.NewNop()), sceneRepo
+}
+
+func TestBulkSetState_AnyTransitionWithoutRules(t *testing.T) {
+	svc, sceneRepo := newTestReviewWorkflowService(t, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{
+		{ID: 1},
+		{ID: 2, ReviewState: "needs_metadata"},
+	}, nil)
+	sceneRepo.EXPECT().UpdateReviewState([]uint{1, 2}, "curated").Return(nil)
+
+	n, err := svc.BulkSetState([]uint{1, 2}, "curated")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 scenes updated, got %d", n)
+	}
+}
+
+func TestBulkSetState_UnknownState(t *testing.T) {
+	svc, _ := newTestReviewWorkflowService(t, nil)
+
+	_, err := svc.BulkSetState([]uint{1}, "archived")
+	if !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestBulkSetState_MissingScene(t *testing.T) {
+	svc, sceneRepo := newTestReviewWorkflowService(t, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{{ID: 1}}, nil)
+
+	_, err := svc.BulkSetState([]uint{1, 2}, "curated")
+	if !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestBulkSetState_RejectsDisallowedTransition(t *testing.T) {
+	svc, sceneRepo := newTestReviewWorkflowService(t, map[string][]string{
+		"unreviewed":     {"needs_metadata"},
+		"needs_metadata": {"curated"},
+	})
+	// Scene 1 (initial state) may not skip straight to curated; nothing is updated
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{
+		{ID: 1},
+		{ID: 2, ReviewState: "needs_metadata"},
+	}, nil)
+
+	_, err := svc.BulkSetState([]uint{1, 2}, "curated")
+	var validationErr *apperrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if _, ok := validationErr.Details["1"]; !ok || len(validationErr.Details) != 1 {
+		t.Fatalf("expected only scene 1 rejected, got %v", validationErr.Details)
+	}
+}
+
+func TestBulkSetState_UnconfiguredStateMovesFreely(t *testing.T) {
+	svc, sceneRepo := newTestReviewWorkflowService(t, map[string][]string{
+		"unreviewed": {"needs_metadata"},
+	})
+	sceneRepo.EXPECT().GetByIDs([]uint{3}).Return([]data.Scene{{ID: 3, ReviewState: "legacy"}}, nil)
+	sceneRepo.EXPECT().UpdateReviewState([]uint{3}, "curated").Return(nil)
+
+	if err := svc.SetState(3, "curated"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestStoredStates(t *testing.T) {
+	svc, _ := newTestReviewWorkflowService(t, nil)
+
+	states, err := svc.StoredStates("unreviewed")
+	if err != nil || len(states) != 2 || states[1] != "" {
+		t.Fatalf("expected initial state to include empty state, got %v (%v)", states, err)
+	}
+	states, err = svc.StoredStates("curated")
+	if err != nil || len(states) != 1 {
+		t.Fatalf("expected only curated, got %v (%v)", states, err)
+	}
+	if _, err := svc.StoredStates("archived"); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for unknown state, got %v", err)
+	}
+}
+
+func TestGetCounts(t *testing.T) {
+	svc, sceneRepo := newTestReviewWorkflowService(t, nil)
+	sceneRepo.EXPECT().CountByReviewState().Return(map[string]int64{
+		"":           40,
+		"unreviewed": 2,
+		"curated":    7,
+		"legacy":     1,
+	}, nil)
+
+	counts, err := svc.GetCounts()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []int64{42, 0, 7}
+	for i, c := range counts.States {
+		if c.Count != want[i] {
+			t.Fatalf("state %s: expected %d, got %d", c.State, want[i], c.Count)
+		}
+	}
+	if counts.Unknown != 1 || counts.Total != 50 {
+		t.Fatalf("expected unknown 1 and total 50, got %d and %d", counts.Unknown, counts.Total)
+	}
+}
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"context"
 	"errors"
+	"sync"
+	"time"
 
 	"goonhub/internal/apperrors"
 	"goonhub/internal/data"
@@ -10,26 +13,80 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	// savedSearchLookback widens each watch run back in time, so scenes whose
+	// tags, actors or title were filled in after they were added still count
+	savedSearchLookback = 24 * time.Hour
+	// savedSearchMatchLimit caps how many scenes one watch run looks at per search
+	savedSearchMatchLimit = 500
+)
+
+// sceneSearcher runs scene searches for watched saved searches.
+type sceneSearcher interface {
+	Search(params data.SceneSearchParams) (*SearchResult, error)
+}
+
+// SavedSearchNewMatches is the payload of the "saved_search:new_matches" event.
+type SavedSearchNewMatches struct {
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	NewMatches  int64  `json:"new_matches"`  // found by this run
+	UnseenCount int64  `json:"unseen_count"` // badge count for the search
+}
+
 type SavedSearchService struct {
-	repo   data.SavedSearchRepository
-	logger *zap.Logger
+	repo          data.SavedSearchRepository
+	tagRepo       data.TagRepository
+	sceneRepo     data.SceneRepository
+	searcher      sceneSearcher
+	rbac          *RBACService
+	eventBus      *EventBus
+	watchInterval time.Duration
+	logger        *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
-func NewSavedSearchService(repo data.SavedSearchRepository, logger *zap.Logger) *SavedSearchService {
-	return &SavedSearchService{
-		repo:   repo,
-		logger: logger,
+func NewSavedSearchService(
+	repo data.SavedSearchRepository,
+	tagRepo data.TagRepository,
+	sceneRepo data.SceneRepository,
+	searchService *SearchService,
+	eventBus *EventBus,
+	watchInterval time.Duration,
+	logger *zap.Logger,
+) *SavedSearchService {
+	s := &SavedSearchService{
+		repo:          repo,
+		tagRepo:       tagRepo,
+		sceneRepo:     sceneRepo,
+		eventBus:      eventBus,
+		watchInterval: watchInterval,
+		logger:        logger,
 	}
+	// Keep a nil service from becoming a non-nil interface
+	if searchService != nil {
+		s.searcher = searchService
+	}
+	return s
+}
+
+// SetRBACService limits new matches to the scenes the user's role may see.
+func (s *SavedSearchService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
 }
 
 type CreateSavedSearchInput struct {
 	Name    string
 	Filters data.Filters
+	Watched bool
 }
 
 type UpdateSavedSearchInput struct {
 	Name    *string
 	Filters *data.Filters
+	Watched *bool
 }
 
 func (s *SavedSearchService) Create(userID uint, input CreateSavedSearchInput) (*data.SavedSearch, error) {
@@ -45,6 +102,7 @@ func (s *SavedSearchService) Create(userID uint, input CreateSavedSearchInput) (
 		Name:    input.Name,
 		Filters: input.Filters,
 	}
+	setWatched(search, input.Watched)
 
 	if err := s.repo.Create(search); err != nil {
 		return nil, apperrors.NewInternalError("failed to create saved search", err)
@@ -110,6 +168,10 @@ func (s *SavedSearchService) Update(userID uint, uuid string, input UpdateSavedS
 		search.Filters = *input.Filters
 	}
 
+	if input.Watched != nil {
+		setWatched(search, *input.Watched)
+	}
+
 	if err := s.repo.Update(search); err != nil {
 		return nil, apperrors.NewInternalError("failed to update saved search", err)
 	}
@@ -146,3 +208,265 @@ func (s *SavedSearchService) Delete(userID uint, uuid string) error {
 
 	return nil
 }
+
+// setWatched starts or stops watching a search. Watching starts from now, so
+// scenes already in the library are not reported as new.
+func setWatched(search *data.SavedSearch, watched bool) {
+	if watched == search.Watched {
+		return
+	}
+	search.Watched = watched
+	if watched {
+		now := time.Now()
+		search.WatchedAt = &now
+		search.LastCheckedAt = &now
+	} else {
+		search.WatchedAt = nil
+	}
+}
+
+// NewMatchCounts returns the unseen match count of each search by ID. Searches
+// without unseen matches are absent from the map.
+func (s *SavedSearchService) NewMatchCounts(searches []data.SavedSearch) (map[uint]int64, error) {
+	ids := make([]uint, 0, len(searches))
+	for _, search := range searches {
+		ids = append(ids, search.ID)
+	}
+	counts, err := s.repo.CountUnseenMatches(ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to count saved search matches", err)
+	}
+	return counts, nil
+}
+
+// NewMatches returns the scenes a watched search found that the user has not
+// seen yet, newest first. Trashed scenes and scenes the role may not see are
+// left out.
+func (s *SavedSearchService) NewMatches(userID uint, role string, uuid string) ([]data.Scene, error) {
+	search, err := s.GetByUUID(userID, uuid)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.repo.GetUnseenMatchSceneIDs(search.ID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get saved search matches", err)
+	}
+	ids, err = visibleSceneIDs(s.rbac, role, ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to filter saved search matches", err)
+	}
+	scenes, err := s.sceneRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get scenes", err)
+	}
+	return scenes, nil
+}
+
+// MarkMatchesSeen clears the new match badge of a search.
+func (s *SavedSearchService) MarkMatchesSeen(userID uint, uuid string) error {
+	search, err := s.GetByUUID(userID, uuid)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.MarkMatchesSeen(search.ID); err != nil {
+		return apperrors.NewInternalError("failed to mark saved search matches seen", err)
+	}
+	return nil
+}
+
+// SearchParams converts a saved search's filters to scene search parameters for
+// its owner. Unknown tag names are dropped.
+func (s *SavedSearchService) SearchParams(search *data.SavedSearch) data.SceneSearchParams {
+	f := search.Filters
+	params := data.SceneSearchParams{
+		Page:             1,
+		Sort:             f.Sort,
+		Query:            f.Query,
+		Studio:           f.Studio,
+		Actors:           f.SelectedActors,
+		MatchingStrategy: f.MatchType,
+		Liked:            f.Liked,
+		UserID:           search.UserID,
+	}
+
+	if len(f.SelectedTags) > 0 {
+		tagIDs, err := s.tagRepo.GetIDsByNames(f.SelectedTags)
+		if err != nil {
+			s.logger.Warn("failed to get tag IDs", zap.Error(err))
+		} else {
+			params.TagIDs = tagIDs
+		}
+	}
+
+	if f.MinDuration != nil {
+		params.MinDuration = *f.MinDuration
+	}
+	if f.MaxDuration != nil {
+		params.MaxDuration = *f.MaxDuration
+	}
+	if f.MinRating != nil {
+		params.MinRating = *f.MinRating
+	}
+	if f.MaxRating != nil {
+		params.MaxRating = *f.MaxRating
+	}
+	if f.MinJizzCount != nil {
+		params.MinJizzCount = *f.MinJizzCount
+	}
+	if f.MaxJizzCount != nil {
+		params.MaxJizzCount = *f.MaxJizzCount
+	}
+	if heights, ok := ResolutionHeights[f.Resolution]; ok {
+		params.MinHeight = heights[0]
+		params.MaxHeight = heights[1]
+	}
+	if t, err := time.Parse("2006-01-02", f.MinDate); err == nil {
+		params.MinDate = &t
+	}
+	if t, err := time.Parse("2006-01-02", f.MaxDate); err == nil {
+		endOfDay := t.Add(24*time.Hour - time.Second)
+		params.MaxDate = &endOfDay
+	}
+
+	// A query in the advanced syntax is applied like on the search page; one
+	// that no longer parses is searched as plain text
+	if parsed, err := ParseSearchQuery(f.Query); err == nil && parsed.HasFields() {
+		if _, err := applySearchQuery(s.tagRepo, &params, parsed); err != nil {
+			s.logger.Warn("failed to apply saved search query", zap.Error(err))
+		}
+	}
+
+	return params
+}
+
+// Start re-runs watched searches every watch interval. It is a no-op when the
+// interval is 0.
+func (s *SavedSearchService) Start() {
+	if s.searcher == nil || s.watchInterval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.watchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.CheckWatched(ctx)
+			}
+		}
+	}()
+
+	s.logger.Info("Saved search watcher started", zap.Duration("interval", s.watchInterval))
+}
+
+// Stop halts the watcher.
+func (s *SavedSearchService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// CheckWatched re-runs every watched search once. A failing search is logged
+// and skipped; it is retried on the next run.
+func (s *SavedSearchService) CheckWatched(ctx context.Context) {
+	searches, err := s.repo.ListWatched()
+	if err != nil {
+		s.logger.Error("Failed to list watched saved searches", zap.Error(err))
+		return
+	}
+
+	for i := range searches {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.checkSearch(&searches[i]); err != nil {
+			s.logger.Warn("Failed to check watched saved search",
+				zap.String("uuid", searches[i].UUID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// checkSearch records the scenes added since the last run that match the search.
+func (s *SavedSearchService) checkSearch(search *data.SavedSearch) error {
+	runAt := time.Now()
+
+	params := s.SearchParams(search)
+	params.Sort = "created_at_desc"
+	params.Limit = savedSearchMatchLimit
+
+	// Only scenes added since the last run (minus the lookback) can be new,
+	// and never scenes added before watching started
+	since := runAt
+	if search.LastCheckedAt != nil {
+		since = search.LastCheckedAt.Add(-savedSearchLookback)
+	}
+	if search.WatchedAt != nil && search.WatchedAt.After(since) {
+		since = *search.WatchedAt
+	}
+	if params.MinDate == nil || params.MinDate.Before(since) {
+		params.MinDate = &since
+	}
+
+	result, err := s.searcher.Search(params)
+	if err != nil {
+		return err
+	}
+
+	sceneIDs := make([]uint, len(result.Scenes))
+	for i, scene := range result.Scenes {
+		sceneIDs[i] = scene.ID
+	}
+	added, err := s.repo.AddMatches(search.ID, sceneIDs)
+	if err != nil {
+		return err
+	}
+
+	search.LastCheckedAt = &runAt
+	if err := s.repo.Update(search); err != nil {
+		return err
+	}
+
+	if added > 0 {
+		s.notifyNewMatches(search, added)
+	}
+	return nil
+}
+
+func (s *SavedSearchService) notifyNewMatches(search *data.SavedSearch, added int64) {
+	counts, err := s.repo.CountUnseenMatches([]uint{search.ID})
+	if err != nil {
+		s.logger.Warn("Failed to count saved search matches", zap.Error(err))
+	}
+
+	s.logger.Info("Watched saved search has new matches",
+		zap.Uint("user_id", search.UserID),
+		zap.String("uuid", search.UUID.String()),
+		zap.Int64("new_matches", added),
+	)
+
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(SceneEvent{
+		Type:   "saved_search:new_matches",
+		UserID: search.UserID,
+		Data: SavedSearchNewMatches{
+			UUID:        search.UUID.String(),
+			Name:        search.Name,
+			NewMatches:  added,
+			UnseenCount: counts[search.ID],
+		},
+	})
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type fakeSceneSearcher struct {
+	params []data.SceneSearchParams
+	scenes []data.Scene
+	err    error
+}
+
+func (f *fakeSceneSearcher) Search(params data.SceneSearchParams) (*SearchResult, error) {
+	f.params = append(f.params, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &SearchResult{Scenes: f.scenes, Total: int64(len(f.scenes))}, nil
+}
+
+func newTestSavedSearchService(t *testing.T, searcher *fakeSceneSearcher) (*SavedSearchService, *mocks.MockSavedSearchRepository, *EventBus) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSavedSearchRepository(ctrl)
+	eventBus := NewEventBus(zap.NewNop())
+	svc := NewSavedSearchService(repo, nil, nil, nil, eventBus, time.Hour, zap.NewNop())
+	svc.searcher = searcher
+	return svc, repo, eventBus
+}
+
+func TestSetWatched(t *testing.T) {
+	search := &data.SavedSearch{}
+
+	setWatched(search, true)
+	if !search.Watched || search.WatchedAt == nil || search.LastCheckedAt == nil {
+		t.Fatalf("expected watch timestamps to be set, got %+v", search)
+	}
+
+	setWatched(search, false)
+	if search.Watched || search.WatchedAt != nil {
+		t.Fatalf("expected watching to stop, got %+v", search)
+	}
+}
+
+func TestSavedSearchService_CheckWatchedRecordsNewMatches(t *testing.T) {
+	searcher := &fakeSceneSearcher{scenes: []data.Scene{{ID: 5}, {ID: 3}}}
+	svc, repo, eventBus := newTestSavedSearchService(t, searcher)
+	subID, events := eventBus.Subscribe()
+	defer eventBus.Unsubscribe(subID)
+
+	watchedAt := time.Now().Add(-72 * time.Hour)
+	lastChecked := time.Now().Add(-time.Hour)
+	search := data.SavedSearch{
+		ID:            7,
+		UUID:          uuid.New(),
+		UserID:        2,
+		Name:          "New releases",
+		Filters:       data.Filters{Query: "jane", Sort: "title_asc"},
+		Watched:       true,
+		WatchedAt:     &watchedAt,
+		LastCheckedAt: &lastChecked,
+	}
+
+	repo.EXPECT().ListWatched().Return([]data.SavedSearch{search}, nil)
+	repo.EXPECT().AddMatches(uint(7), []uint{5, 3}).Return(int64(1), nil)
+	repo.EXPECT().Update(gomock.Any()).DoAndReturn(func(s *data.SavedSearch) error {
+		if s.LastCheckedAt == nil || !s.LastCheckedAt.After(lastChecked) {
+			t.Fatalf("expected last checked time to advance, got %v", s.LastCheckedAt)
+		}
+		return nil
+	})
+	repo.EXPECT().CountUnseenMatches([]uint{7}).Return(map[uint]int64{7: 4}, nil)
+
+	svc.CheckWatched(context.Background())
+
+	if len(searcher.params) != 1 {
+		t.Fatalf("expected one search, got %d", len(searcher.params))
+	}
+	params := searcher.params[0]
+	if params.Query != "jane" || params.UserID != 2 || params.Sort != "created_at_desc" || params.Limit != savedSearchMatchLimit {
+		t.Fatalf("unexpected search params %+v", params)
+	}
+	wantSince := lastChecked.Add(-savedSearchLookback)
+	if params.MinDate == nil || !params.MinDate.Equal(wantSince) {
+		t.Fatalf("expected min date %v, got %v", wantSince, params.MinDate)
+	}
+
+	select {
+	case event := <-events:
+		payload, ok := event.Data.(SavedSearchNewMatches)
+		if event.Type != "saved_search:new_matches" || event.UserID != 2 || !ok {
+			t.Fatalf("unexpected event %+v", event)
+		}
+		if payload.NewMatches != 1 || payload.UnseenCount != 4 {
+			t.Fatalf("unexpected payload %+v", payload)
+		}
+	default:
+		t.Fatal("expected a new matches event")
+	}
+}
+
+func TestSavedSearchService_CheckWatchedNeverLooksBeforeWatching(t *testing.T) {
+	searcher := &fakeSceneSearcher{}
+	svc, repo, eventBus := newTestSavedSearchService(t, searcher)
+	subID, events := eventBus.Subscribe()
+	defer eventBus.Unsubscribe(subID)
+
+	watchedAt := time.Now().Add(-time.Hour)
+	search := data.SavedSearch{ID: 1, UUID: uuid.New(), Watched: true, WatchedAt: &watchedAt, LastCheckedAt: &watchedAt}
+
+	repo.EXPECT().ListWatched().Return([]data.SavedSearch{search}, nil)
+	repo.EXPECT().AddMatches(uint(1), []uint{}).Return(int64(0), nil)
+	repo.EXPECT().Update(gomock.Any()).Return(nil)
+
+	svc.CheckWatched(context.Background())
+
+	if params := searcher.params[0]; params.MinDate == nil || !params.MinDate.Equal(watchedAt) {
+		t.Fatalf("expected min date to be the watch start %v, got %v", watchedAt, params.MinDate)
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("expected no event without new matches, got %+v", event)
+	default:
+	}
+}
+
+func TestSavedSearchService_CheckWatchedSkipsFailingSearch(t *testing.T) {
+	searcher := &fakeSceneSearcher{err: errors.New("search down")}
+	svc, repo, _ := newTestSavedSearchService(t, searcher)
+
+	now := time.Now()
+	repo.EXPECT().ListWatched().Return([]data.SavedSearch{
+		{ID: 1, UUID: uuid.New(), Watched: true, WatchedAt: &now, LastCheckedAt: &now},
+		{ID: 2, UUID: uuid.New(), Watched: true, WatchedAt: &now, LastCheckedAt: &now},
+	}, nil)
+
+	// Neither search records matches or advances its last checked time
+	svc.CheckWatched(context.Background())
+
+	if len(searcher.params) != 2 {
+		t.Fatalf("expected both searches to be tried, got %d", len(searcher.params))
+	}
+}
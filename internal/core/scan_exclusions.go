@@ -0,0 +1,308 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"goonhub/internal/data"
+)
+
+// Reasons reported for excluded files and directories.
+const (
+	excludeReasonPattern   = "pattern"
+	excludeReasonHiddenDir = "hidden_dir"
+	excludeReasonMinSize   = "min_size"
+	excludeReasonSample    = "sample"
+	excludeReasonWorkDir   = "work_dir"
+)
+
+// workDirs are the folders partial downloads and uploads are written to
+// inside storage paths. They are always skipped, whatever the hidden folder
+// setting.
+var workDirs = map[string]bool{
+	remoteImportDownloadDir: true,
+	UploadDirName:           true,
+}
+
+// samplePattern matches file names marking a sample or trailer, e.g.
+// "movie-sample.mkv" or "Trailer.mp4", but not "samples_of_jazz.mp4".
+var samplePattern = regexp.MustCompile(`(?i)(^|[^a-z0-9])(sample|trailer)([^a-z0-9]|$)`)
+
+// scanExclusions combines the global scan rules with the patterns of one
+// storage path. Paths are matched relative to the storage path root.
+type scanExclusions struct {
+	patterns         [][]string // compiled patterns, split into segments
+	minFileSizeBytes int64
+	ignoreSamples    bool
+	ignoreHiddenDirs bool
+}
+
+// newScanExclusions compiles the global rules and the storage path patterns.
+// A nil config applies no global rules.
+func newScanExclusions(cfg *data.ScanConfigRecord, storagePathPatterns []string) (*scanExclusions, error) {
+	e := &scanExclusions{}
+	var patterns []string
+	if cfg != nil {
+		e.minFileSizeBytes = cfg.MinFileSizeBytes
+		e.ignoreSamples = cfg.IgnoreSamples
+		e.ignoreHiddenDirs = cfg.IgnoreHiddenDirs
+		patterns = append(patterns, cfg.ExcludePatterns...)
+	}
+	patterns = append(patterns, storagePathPatterns...)
+
+	for _, p := range patterns {
+		segments, err := compileExcludePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		if segments != nil {
+			e.patterns = append(e.patterns, segments)
+		}
+	}
+	return e, nil
+}
+
+// ValidateExcludePatterns checks that every pattern is a valid glob.
+func ValidateExcludePatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := compileExcludePattern(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileExcludePattern splits a glob into path segments. Patterns without a
+// slash match any single path segment (a file or directory name anywhere),
+// patterns with one are anchored at the storage path root, and "**" matches
+// any number of directories.
+func compileExcludePattern(pattern string) ([]string, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return nil, nil
+	}
+
+	var segments []string
+	if strings.Contains(pattern, "/") {
+		segments = strings.Split(strings.Trim(pattern, "/"), "/")
+	} else {
+		segments = []string{"**", pattern}
+	}
+	for _, seg := range segments {
+		if seg == "**" {
+			continue
+		}
+		if _, err := path.Match(seg, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return segments, nil
+}
+
+// matchSegments reports whether the path segments match the pattern segments.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+func splitRelPath(rel string) []string {
+	return strings.Split(strings.Trim(filepath.ToSlash(rel), "/"), "/")
+}
+
+func (e *scanExclusions) matchesPattern(segments []string) bool {
+	for _, p := range e.patterns {
+		if matchSegments(p, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludeDir reports whether a directory, given relative to the storage path
+// root, is skipped together with everything below it.
+func (e *scanExclusions) excludeDir(rel string) (bool, string) {
+	segments := splitRelPath(rel)
+	if workDirs[segments[len(segments)-1]] {
+		return true, excludeReasonWorkDir
+	}
+	if e.ignoreHiddenDirs && strings.HasPrefix(segments[len(segments)-1], ".") {
+		return true, excludeReasonHiddenDir
+	}
+	if e.matchesPattern(segments) {
+		return true, excludeReasonPattern
+	}
+	return false, ""
+}
+
+// excludeFile reports whether a video file, given relative to the storage path
+// root, is skipped. Its directories are not checked; see excludePath.
+func (e *scanExclusions) excludeFile(rel string, size int64) (bool, string) {
+	segments := splitRelPath(rel)
+	if e.matchesPattern(segments) {
+		return true, excludeReasonPattern
+	}
+	name := segments[len(segments)-1]
+	if e.ignoreSamples && samplePattern.MatchString(strings.TrimSuffix(name, filepath.Ext(name))) {
+		return true, excludeReasonSample
+	}
+	if e.minFileSizeBytes > 0 && size < e.minFileSizeBytes {
+		return true, excludeReasonMinSize
+	}
+	return false, ""
+}
+
+// excludePath checks a file and every directory above it, for callers that do
+// not walk the tree from the root.
+func (e *scanExclusions) excludePath(rel string, size int64) (bool, string) {
+	segments := splitRelPath(rel)
+	for i := 1; i < len(segments); i++ {
+		if excluded, reason := e.excludeDir(strings.Join(segments[:i], "/")); excluded {
+			return true, reason
+		}
+	}
+	return e.excludeFile(rel, size)
+}
+
+// maxExclusionTestEntries caps the entries returned by TestExclusions.
+const maxExclusionTestEntries = 500
+
+// ExcludedEntry is a file or directory a scan would skip.
+type ExcludedEntry struct {
+	Path   string `json:"path"` // relative to the storage path
+	IsDir  bool   `json:"is_dir"`
+	Reason string `json:"reason"`
+}
+
+// ExclusionTestResult lists what the exclusion rules skip in a storage path.
+type ExclusionTestResult struct {
+	Excluded      []ExcludedEntry `json:"excluded"`
+	FilesChecked  int             `json:"files_checked"`  // video files outside excluded directories
+	FilesExcluded int             `json:"files_excluded"` // of those, the excluded ones
+	DirsExcluded  int             `json:"dirs_excluded"`
+	Truncated     bool            `json:"truncated"`
+}
+
+// GetScanConfig returns the global scan exclusion rules.
+func (s *ScanService) GetScanConfig() (*data.ScanConfigRecord, error) {
+	cfg, err := s.loadScanConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan config: %w", err)
+	}
+	if cfg == nil {
+		cfg = &data.ScanConfigRecord{}
+	}
+	return cfg, nil
+}
+
+// UpdateScanConfig validates and saves the global scan exclusion rules.
+func (s *ScanService) UpdateScanConfig(cfg *data.ScanConfigRecord) error {
+	if cfg.MinFileSizeBytes < 0 {
+		return fmt.Errorf("min_file_size_bytes must not be negative")
+	}
+	cfg.ExcludePatterns = cleanExcludePatterns(cfg.ExcludePatterns)
+	if err := ValidateExcludePatterns(cfg.ExcludePatterns); err != nil {
+		return err
+	}
+	if err := s.scanConfigRepo.Upsert(cfg); err != nil {
+		return fmt.Errorf("failed to save scan config: %w", err)
+	}
+	return nil
+}
+
+// TestExclusions walks a storage path and lists what the global rules plus the
+// given patterns would exclude. Nil patterns test the storage path's saved
+// patterns. Nothing is imported or changed.
+func (s *ScanService) TestExclusions(storagePathID uint, patterns []string) (*ExclusionTestResult, error) {
+	storagePath, err := s.storagePathService.GetByID(storagePathID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage path: %w", err)
+	}
+	if storagePath == nil {
+		return nil, fmt.Errorf("storage path not found")
+	}
+	if patterns == nil {
+		patterns = storagePath.ExcludePatterns
+	}
+	if err := ValidateExcludePatterns(patterns); err != nil {
+		return nil, err
+	}
+
+	cfg, err := s.loadScanConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan config: %w", err)
+	}
+	exclusions, err := newScanExclusions(cfg, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExclusionTestResult{Excluded: []ExcludedEntry{}}
+	add := func(entry ExcludedEntry) {
+		if len(result.Excluded) >= maxExclusionTestEntries {
+			result.Truncated = true
+			return
+		}
+		result.Excluded = append(result.Excluded, entry)
+	}
+
+	_ = filepath.WalkDir(storagePath.Path, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil || path == storagePath.Path {
+			return nil
+		}
+		rel := s.relScanPath(*storagePath, path)
+		if d.IsDir() {
+			if excluded, reason := exclusions.excludeDir(rel); excluded {
+				result.DirsExcluded++
+				add(ExcludedEntry{Path: rel, IsDir: true, Reason: reason})
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isVideoExtension(strings.ToLower(filepath.Ext(path))) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		result.FilesChecked++
+		if excluded, reason := exclusions.excludeFile(rel, info.Size()); excluded {
+			result.FilesExcluded++
+			add(ExcludedEntry{Path: rel, Reason: reason})
+		}
+		return nil
+	})
+
+	return result, nil
+}
+
+// cleanExcludePatterns trims patterns and drops empty ones.
+func cleanExcludePatterns(patterns []string) []string {
+	cleaned := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p != "" {
+			cleaned = append(cleaned, p)
+		}
+	}
+	return cleaned
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"github.com/lib/pq"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestScanExclusions_Patterns(t *testing.T) {
+	e, err := newScanExclusions(nil, []string{"*.part.mp4", "Extras", "incoming/**/tmp", "/raw/*.mkv"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		rel      string
+		dir      bool
+		excluded bool
+	}{
+		{"clip.part.mp4", false, true},
+		{"a/b/clip.part.mp4", false, true},
+		{"clip.mp4", false, false},
+		{"show/Extras", true, true},
+		{"show/extras", true, false},
+		{"incoming/tmp", true, true},
+		{"incoming/x/y/tmp", true, true},
+		{"other/tmp", true, false},
+		{"raw/a.mkv", false, true},
+		{"sub/raw/a.mkv", false, false},
+		// Partial downloads are skipped even without the hidden folder rule
+		{".downloads", true, true},
+		{"sub/.downloads", true, true},
+		{".uploads", true, true},
+	}
+	for _, tt := range tests {
+		var excluded bool
+		if tt.dir {
+			excluded, _ = e.excludeDir(tt.rel)
+		} else {
+			excluded, _ = e.excludeFile(tt.rel, 1)
+		}
+		if excluded != tt.excluded {
+			t.Errorf("%s: expected excluded=%v, got %v", tt.rel, tt.excluded, excluded)
+		}
+	}
+}
+
+func TestScanExclusions_GlobalRules(t *testing.T) {
+	e, err := newScanExclusions(&data.ScanConfigRecord{
+		MinFileSizeBytes: 100,
+		IgnoreSamples:    true,
+		IgnoreHiddenDirs: true,
+		ExcludePatterns:  pq.StringArray{"@eaDir"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		rel    string
+		size   int64
+		reason string
+	}{
+		{"movie.mp4", 100, ""},
+		{"movie.mp4", 99, excludeReasonMinSize},
+		{"movie-sample.mkv", 1000, excludeReasonSample},
+		{"Trailer.mp4", 1000, excludeReasonSample},
+		{"samples_of_jazz.mp4", 1000, ""},
+		{".cache/movie.mp4", 1000, excludeReasonHiddenDir},
+		{"a/@eaDir/movie.mp4", 1000, excludeReasonPattern},
+		{".hidden.mp4", 1000, ""},
+	}
+	for _, tt := range tests {
+		excluded, reason := e.excludePath(tt.rel, tt.size)
+		if excluded != (tt.reason != "") || reason != tt.reason {
+			t.Errorf("%s: expected reason %q, got %v %q", tt.rel, tt.reason, excluded, reason)
+		}
+	}
+}
+
+func TestValidateExcludePatterns(t *testing.T) {
+	if err := ValidateExcludePatterns([]string{"*.mp4", "a/**/b", " "}); err != nil {
+		t.Fatalf("expected valid patterns, got %v", err)
+	}
+	if err := ValidateExcludePatterns([]string{"[abc"}); err == nil {
+		t.Fatal("expected error for malformed pattern")
+	}
+}
+
+func TestScanService_TestExclusions(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"keep.mp4", "skip.tmp.mp4", filepath.Join(".trash", "old.mp4"), "notes.txt"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStoragePathRepository(ctrl)
+	repo.EXPECT().GetByID(uint(1)).Return(&data.StoragePath{ID: 1, Path: root, ExcludePatterns: pq.StringArray{"*.tmp.mp4"}}, nil).Times(2)
+	svc := NewScanService(NewStoragePathService(repo, zap.NewNop()), nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	// Saved patterns; no global config means hidden directories are scanned
+	result, err := svc.TestExclusions(1, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.FilesChecked != 3 || result.FilesExcluded != 1 || result.Excluded[0].Path != "skip.tmp.mp4" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	// Explicit empty patterns override the saved ones
+	result, err = svc.TestExclusions(1, []string{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.FilesExcluded != 0 || len(result.Excluded) != 0 {
+		t.Fatalf("expected nothing excluded, got %+v", result)
+	}
+}
@@ -0,0 +1,248 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"goonhub/internal/data"
+	"io"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// scanReportFlushSize is the number of buffered report entries written at once
+	scanReportFlushSize = 500
+	// scanReportExportBatch is the number of entries read per query when exporting
+	scanReportExportBatch = 1000
+)
+
+// ErrScanNotFound is returned when a report is requested for an unknown scan
+var ErrScanNotFound = fmt.Errorf("scan not found")
+
+// ScanReportKinds lists the report entry kinds that can be filtered on
+var ScanReportKinds = []string{data.ScanReportAdded, data.ScanReportMoved, data.ScanReportRemoved, data.ScanReportError}
+
+// scanReport buffers the per-path outcome of a running scan and writes it in
+// batches. A nil report records nothing, so callers outside a scan pass nil.
+type scanReport struct {
+	repo    data.ScanReportRepository
+	scanID  uint
+	pending []data.ScanReportEntry
+	logger  *zap.Logger
+}
+
+func (s *ScanService) newScanReport(scanID uint) *scanReport {
+	if s.scanReportRepo == nil {
+		return nil
+	}
+	return &scanReport{repo: s.scanReportRepo, scanID: scanID, logger: s.logger}
+}
+
+func (r *scanReport) add(kind, path, oldPath string, sceneID *uint, message string) {
+	if r == nil {
+		return
+	}
+	r.pending = append(r.pending, data.ScanReportEntry{
+		ScanID:  r.scanID,
+		Kind:    kind,
+		Path:    path,
+		OldPath: oldPath,
+		SceneID: sceneID,
+		Message: message,
+	})
+	if len(r.pending) >= scanReportFlushSize {
+		r.flush()
+	}
+}
+
+func (r *scanReport) added(sceneID uint, path string) {
+	r.add(data.ScanReportAdded, path, "", &sceneID, "")
+}
+
+func (r *scanReport) moved(sceneID uint, oldPath, newPath string) {
+	r.add(data.ScanReportMoved, newPath, oldPath, &sceneID, "")
+}
+
+func (r *scanReport) removed(sceneID uint, path string) {
+	r.add(data.ScanReportRemoved, path, "", &sceneID, "")
+}
+
+func (r *scanReport) failed(path string, err error) {
+	r.add(data.ScanReportError, path, "", nil, err.Error())
+}
+
+// flush writes buffered entries. Report writes are best effort: a failure is
+// logged and never fails the scan itself.
+func (r *scanReport) flush() {
+	if r == nil || len(r.pending) == 0 {
+		return
+	}
+	if err := r.repo.CreateBatch(r.pending); err != nil {
+		r.logger.Warn("Failed to save scan report entries",
+			zap.Uint("scan_id", r.scanID),
+			zap.Int("count", len(r.pending)),
+			zap.Error(err),
+		)
+	}
+	r.pending = nil
+}
+
+// ScanReport is a page of a scan's report with totals per entry kind
+type ScanReport struct {
+	Scan    *data.ScanHistory      `json:"scan"`
+	Counts  map[string]int64       `json:"counts"`
+	Entries []data.ScanReportEntry `json:"data"`
+	Total   int64                  `json:"total"`
+	Page    int                    `json:"page"`
+	Limit   int                    `json:"limit"`
+}
+
+func validateScanReportKind(kind string) error {
+	if kind == "" {
+		return nil
+	}
+	for _, k := range ScanReportKinds {
+		if k == kind {
+			return nil
+		}
+	}
+	return fmt.Errorf("kind must be one of: added, moved, removed, error")
+}
+
+func (s *ScanService) getScanForReport(scanID uint) (*data.ScanHistory, error) {
+	if s.scanReportRepo == nil {
+		return nil, fmt.Errorf("scan reports are not available")
+	}
+	scan, err := s.scanHistoryRepo.GetByID(scanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan: %w", err)
+	}
+	if scan == nil {
+		return nil, ErrScanNotFound
+	}
+	return scan, nil
+}
+
+// GetReport returns a page of the paths a scan added, moved, removed or failed
+// on, optionally filtered to one kind.
+func (s *ScanService) GetReport(scanID uint, kind string, page, limit int) (*ScanReport, error) {
+	if err := validateScanReportKind(kind); err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	scan, err := s.getScanForReport(scanID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.scanReportRepo.CountByKind(scanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scan report entries: %w", err)
+	}
+	entries, total, err := s.scanReportRepo.List(scanID, kind, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scan report entries: %w", err)
+	}
+
+	return &ScanReport{
+		Scan:    scan,
+		Counts:  counts,
+		Entries: entries,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+// ExportReport validates the request and returns a function that writes the
+// scan's full report as "csv" or "json". The JSON form carries the scan summary
+// next to the entries. Splitting the two lets handlers report errors before
+// any of the response is written.
+func (s *ScanService) ExportReport(scanID uint, format string) (func(w io.Writer) error, error) {
+	if format != "csv" && format != "json" {
+		return nil, fmt.Errorf("format must be one of: csv, json")
+	}
+	scan, err := s.getScanForReport(scanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "json" {
+		return func(w io.Writer) error { return s.exportReportJSON(scan, w) }, nil
+	}
+	return func(w io.Writer) error { return s.exportReportCSV(scan, w) }, nil
+}
+
+func (s *ScanService) exportReportCSV(scan *data.ScanHistory, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"kind", "path", "old_path", "scene_id", "message"}); err != nil {
+		return err
+	}
+	err := s.scanReportRepo.ForEach(scan.ID, scanReportExportBatch, func(entries []data.ScanReportEntry) error {
+		for _, e := range entries {
+			sceneID := ""
+			if e.SceneID != nil {
+				sceneID = strconv.FormatUint(uint64(*e.SceneID), 10)
+			}
+			if err := cw.Write([]string{e.Kind, e.Path, e.OldPath, sceneID, e.Message}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportReportJSON streams {"scan": ..., "entries": [...]} without holding
+// the whole report in memory.
+func (s *ScanService) exportReportJSON(scan *data.ScanHistory, w io.Writer) error {
+	scanJSON, err := json.Marshal(scan)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, `{"scan":%s,"entries":[`, scanJSON); err != nil {
+		return err
+	}
+
+	first := true
+	err = s.scanReportRepo.ForEach(scan.ID, scanReportExportBatch, func(entries []data.ScanReportEntry) error {
+		for _, e := range entries {
+			entryJSON, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := w.Write(entryJSON); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]}")
+	return err
+}
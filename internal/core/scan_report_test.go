@@ -0,0 +1,144 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestScanReport_BuffersAndFlushes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockScanReportRepository(ctrl)
+	svc := NewScanService(nil, nil, nil, repo, nil, nil, nil, nil, nil, zap.NewNop())
+
+	report := svc.newScanReport(9)
+
+	// A full buffer is written on its own
+	repo.EXPECT().CreateBatch(gomock.Len(scanReportFlushSize)).Return(nil)
+	for i := 0; i < scanReportFlushSize; i++ {
+		report.added(uint(i+1), fmt.Sprintf("/media/%d.mp4", i))
+	}
+
+	repo.EXPECT().CreateBatch(gomock.Any()).DoAndReturn(func(entries []data.ScanReportEntry) error {
+		if len(entries) != 3 {
+			t.Fatalf("expected 3 entries, got %d", len(entries))
+		}
+		moved, failed := entries[1], entries[2]
+		if moved.Kind != data.ScanReportMoved || moved.OldPath != "/old/a.mp4" || moved.Path != "/new/a.mp4" || moved.ScanID != 9 {
+			t.Fatalf("unexpected moved entry %+v", moved)
+		}
+		if failed.Kind != data.ScanReportError || failed.SceneID != nil || failed.Message != "permission denied" {
+			t.Fatalf("unexpected error entry %+v", failed)
+		}
+		return nil
+	})
+	report.removed(4, "/media/gone.mp4")
+	report.moved(5, "/old/a.mp4", "/new/a.mp4")
+	report.failed("/media/locked", errors.New("permission denied"))
+	report.flush()
+
+	// Nothing pending, nothing written
+	report.flush()
+
+	// A nil report (no repository, or outside a scan) is a no-op
+	var none *scanReport
+	none.added(1, "/media/x.mp4")
+	none.flush()
+}
+
+func TestScanService_GetReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	historyRepo := mocks.NewMockScanHistoryRepository(ctrl)
+	reportRepo := mocks.NewMockScanReportRepository(ctrl)
+	svc := NewScanService(nil, nil, historyRepo, reportRepo, nil, nil, nil, nil, nil, zap.NewNop())
+
+	if _, err := svc.GetReport(1, "skipped", 1, 10); err == nil {
+		t.Fatal("expected error for unknown kind")
+	}
+
+	historyRepo.EXPECT().GetByID(uint(2)).Return(nil, nil)
+	if _, err := svc.GetReport(2, "", 1, 10); !errors.Is(err, ErrScanNotFound) {
+		t.Fatalf("expected ErrScanNotFound, got %v", err)
+	}
+
+	historyRepo.EXPECT().GetByID(uint(1)).Return(&data.ScanHistory{ID: 1}, nil)
+	reportRepo.EXPECT().CountByKind(uint(1)).Return(map[string]int64{"added": 2, "error": 1}, nil)
+	reportRepo.EXPECT().List(uint(1), "error", 1, 500).Return([]data.ScanReportEntry{{ID: 3, Kind: "error"}}, int64(1), nil)
+	report, err := svc.GetReport(1, "error", 0, 1000)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Total != 1 || report.Counts["added"] != 2 || report.Page != 1 || report.Limit != 500 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestScanService_ExportReport(t *testing.T) {
+	sceneID := uint(7)
+	entries := []data.ScanReportEntry{
+		{ID: 1, ScanID: 1, Kind: "added", Path: "/media/a.mp4", SceneID: &sceneID},
+		{ID: 2, ScanID: 1, Kind: "error", Path: "/media/b,c.mp4", Message: "read failed"},
+	}
+	newService := func(t *testing.T) *ScanService {
+		ctrl := gomock.NewController(t)
+		historyRepo := mocks.NewMockScanHistoryRepository(ctrl)
+		reportRepo := mocks.NewMockScanReportRepository(ctrl)
+		historyRepo.EXPECT().GetByID(uint(1)).Return(&data.ScanHistory{ID: 1, Status: "completed"}, nil)
+		reportRepo.EXPECT().ForEach(uint(1), scanReportExportBatch, gomock.Any()).
+			DoAndReturn(func(_ uint, _ int, fn func([]data.ScanReportEntry) error) error {
+				return fn(entries)
+			})
+		return NewScanService(nil, nil, historyRepo, reportRepo, nil, nil, nil, nil, nil, zap.NewNop())
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		write, err := newService(t).ExportReport(1, "csv")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := "kind,path,old_path,scene_id,message\nadded,/media/a.mp4,,7,\nerror,\"/media/b,c.mp4\",,,read failed\n"
+		if buf.String() != want {
+			t.Fatalf("unexpected csv:\n%s", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		write, err := newService(t).ExportReport(1, "json")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var out struct {
+			Scan    data.ScanHistory       `json:"scan"`
+			Entries []data.ScanReportEntry `json:"entries"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatalf("invalid json %q: %v", buf.String(), err)
+		}
+		if out.Scan.Status != "completed" || len(out.Entries) != 2 || *out.Entries[0].SceneID != 7 {
+			t.Fatalf("unexpected export %+v", out)
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		svc := NewScanService(nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+		if _, err := svc.ExportReport(1, "xml"); err == nil {
+			t.Fatal("expected error for unknown format")
+		}
+	})
+}
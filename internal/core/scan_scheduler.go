@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"goonhub/internal/data"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// scanScheduleParser parses storage path scan schedules (5-field cron, same as
+// trigger schedules).
+var scanScheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// ScanScheduler runs scoped scans of storage paths on their own cron schedules.
+// Only one scan runs at a time, so paths that come due while a scan is running
+// are queued and scanned together once it ends.
+type ScanScheduler struct {
+	storagePathRepo data.StoragePathRepository
+	scanService     *ScanService
+	logger          *zap.Logger
+
+	mu       sync.Mutex
+	cron     *cron.Cron
+	entryIDs map[uint]cron.EntryID // storage path ID -> cron entry
+	pending  map[uint]struct{}     // storage paths due while a scan was running
+}
+
+func NewScanScheduler(storagePathRepo data.StoragePathRepository, scanService *ScanService, logger *zap.Logger) *ScanScheduler {
+	return &ScanScheduler{
+		storagePathRepo: storagePathRepo,
+		scanService:     scanService,
+		logger:          logger,
+		entryIDs:        make(map[uint]cron.EntryID),
+		pending:         make(map[uint]struct{}),
+	}
+}
+
+func (s *ScanScheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cron = cron.New(cron.WithParser(scanScheduleParser))
+
+	if err := s.loadSchedules(); err != nil {
+		s.logger.Error("Failed to load storage path scan schedules on start", zap.Error(err))
+	}
+	// Drain paths queued behind a running scan
+	if _, err := s.cron.AddFunc("* * * * *", s.dispatch); err != nil {
+		s.logger.Error("Failed to register scan queue drain", zap.Error(err))
+	}
+
+	s.cron.Start()
+	s.logger.Info("Scan scheduler started", zap.Int("schedules", len(s.entryIDs)))
+}
+
+func (s *ScanScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cron != nil {
+		ctx := s.cron.Stop()
+		<-ctx.Done()
+		s.logger.Info("Scan scheduler stopped")
+	}
+}
+
+// RefreshSchedules re-reads the storage path schedules, e.g. after one changed.
+func (s *ScanScheduler) RefreshSchedules() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cron == nil {
+		return nil
+	}
+	for pathID, id := range s.entryIDs {
+		s.cron.Remove(id)
+		delete(s.entryIDs, pathID)
+	}
+	return s.loadSchedules()
+}
+
+// NextRuns returns the next scheduled scan time per storage path with an
+// enabled schedule.
+func (s *ScanScheduler) NextRuns() map[uint]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := make(map[uint]time.Time, len(s.entryIDs))
+	if s.cron == nil {
+		return next
+	}
+	for pathID, id := range s.entryIDs {
+		if entry := s.cron.Entry(id); entry.Valid() && !entry.Next.IsZero() {
+			next[pathID] = entry.Next
+		}
+	}
+	return next
+}
+
+func (s *ScanScheduler) loadSchedules() error {
+	paths, err := s.storagePathRepo.List()
+	if err != nil {
+		return fmt.Errorf("failed to load storage paths: %w", err)
+	}
+
+	for _, p := range paths {
+		if !p.ScanScheduleEnabled || p.ScanSchedule == nil {
+			continue
+		}
+
+		pathID := p.ID
+		expr := *p.ScanSchedule
+		id, err := s.cron.AddFunc(expr, func() {
+			s.enqueue(pathID)
+		})
+		if err != nil {
+			s.logger.Error("Failed to register storage path scan schedule",
+				zap.Uint("storage_path_id", pathID),
+				zap.String("scan_schedule", expr),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		s.entryIDs[pathID] = id
+		s.logger.Info("Registered storage path scan schedule",
+			zap.Uint("storage_path_id", pathID),
+			zap.String("scan_schedule", expr),
+		)
+	}
+
+	return nil
+}
+
+func (s *ScanScheduler) enqueue(pathID uint) {
+	s.mu.Lock()
+	s.pending[pathID] = struct{}{}
+	s.mu.Unlock()
+
+	s.dispatch()
+}
+
+// dispatch starts one scan covering every queued storage path, unless a scan
+// is already running.
+func (s *ScanScheduler) dispatch() {
+	if s.scanService == nil || s.scanService.IsRunning() {
+		return
+	}
+
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	ids := make([]uint, 0, len(s.pending))
+	for id := range s.pending {
+		ids = append(ids, id)
+	}
+	s.pending = make(map[uint]struct{})
+	s.mu.Unlock()
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	s.logger.Info("Starting scheduled storage path scan", zap.Any("storage_path_ids", ids))
+	if _, err := s.scanService.StartScopedScan(context.Background(), ScanScope{StoragePathIDs: ids}); err != nil {
+		// Lost a race with another scan; try again on the next drain
+		s.logger.Warn("Failed to start scheduled storage path scan", zap.Error(err))
+		s.mu.Lock()
+		for _, id := range ids {
+			s.pending[id] = struct{}{}
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestScanScheduler_RegistersEnabledSchedules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStoragePathRepository(ctrl)
+	schedule, bad := "0 3 * * *", "nope"
+	repo.EXPECT().List().Return([]data.StoragePath{
+		{ID: 1, ScanSchedule: &schedule, ScanScheduleEnabled: true},
+		{ID: 2, ScanSchedule: &schedule},
+		{ID: 3, ScanScheduleEnabled: true},
+		{ID: 4, ScanSchedule: &bad, ScanScheduleEnabled: true},
+	}, nil).Times(2)
+
+	s := NewScanScheduler(repo, nil, zap.NewNop())
+	s.Start()
+	defer s.Stop()
+
+	next := s.NextRuns()
+	if len(next) != 1 {
+		t.Fatalf("expected one scheduled path, got %v", next)
+	}
+	if at, ok := next[1]; !ok || at.Hour() != 3 || at.Minute() != 0 {
+		t.Fatalf("expected path 1 at 03:00, got %v", next)
+	}
+
+	if err := s.RefreshSchedules(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(s.NextRuns()) != 1 {
+		t.Fatalf("expected refresh to replace the entries, got %v", s.NextRuns())
+	}
+}
+
+func TestScanScheduler_QueuesWhileScanRuns(t *testing.T) {
+	scanService := NewScanService(nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	scanService.currentScan = &data.ScanHistory{Status: "running"}
+	s := NewScanScheduler(nil, scanService, zap.NewNop())
+
+	s.enqueue(2)
+	s.enqueue(1)
+
+	if len(s.pending) != 2 {
+		t.Fatalf("expected both paths to stay queued, got %v", s.pending)
+	}
+}
+
+func TestScopeStoragePaths(t *testing.T) {
+	paths := []data.StoragePath{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	if got := scopeStoragePaths(paths, nil); len(got) != 3 {
+		t.Fatalf("expected all paths without scope, got %v", got)
+	}
+	got := scopeStoragePaths(paths, []int64{3, 1, 9})
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 3 {
+		t.Fatalf("expected paths 1 and 3, got %v", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"goonhub/internal/data"
+	"goonhub/internal/jobs"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -36,6 +37,7 @@ type ScanStatus struct {
 type pendingScene struct {
 	scene       *data.Scene
 	storagePath string
+	sidecar     *SidecarMetadata // actors and tags to link once the scene has an ID
 }
 
 // scanLookupIndex provides in-memory lookup structures built once before a scan
@@ -44,6 +46,9 @@ type scanLookupIndex struct {
 	knownPaths map[string]struct{}
 	// lookupByKey maps "size:filename" -> []ScanLookupEntry for move detection
 	lookupByKey map[string][]data.ScanLookupEntry
+	// lookupBySize maps size -> []ScanLookupEntry for scenes with a file hash,
+	// so renamed files can be matched by content
+	lookupBySize map[int64][]data.ScanLookupEntry
 }
 
 func buildScanLookupKey(size int64, filename string) string {
@@ -55,10 +60,15 @@ type ScanService struct {
 	storagePathService *StoragePathService
 	sceneRepo          data.SceneRepository
 	scanHistoryRepo    data.ScanHistoryRepository
+	scanReportRepo     data.ScanReportRepository
+	scanConfigRepo     data.ScanConfigRepository
+	sidecars           *SidecarMetadataService
+	folderRules        *FolderRuleService
 	processingService  *SceneProcessingService
 	eventBus           *EventBus
 	logger             *zap.Logger
 	indexer            SceneIndexer
+	storageHealth      *StorageHealthService
 
 	mu          sync.Mutex
 	currentScan *data.ScanHistory
@@ -70,6 +80,10 @@ func NewScanService(
 	storagePathService *StoragePathService,
 	sceneRepo data.SceneRepository,
 	scanHistoryRepo data.ScanHistoryRepository,
+	scanReportRepo data.ScanReportRepository,
+	scanConfigRepo data.ScanConfigRepository,
+	sidecars *SidecarMetadataService,
+	folderRules *FolderRuleService,
 	processingService *SceneProcessingService,
 	eventBus *EventBus,
 	logger *zap.Logger,
@@ -78,6 +92,10 @@ func NewScanService(
 		storagePathService: storagePathService,
 		sceneRepo:          sceneRepo,
 		scanHistoryRepo:    scanHistoryRepo,
+		scanReportRepo:     scanReportRepo,
+		scanConfigRepo:     scanConfigRepo,
+		sidecars:           sidecars,
+		folderRules:        folderRules,
 		processingService:  processingService,
 		eventBus:           eventBus,
 		logger:             logger.With(zap.String("component", "scan_service")),
@@ -89,6 +107,11 @@ func (s *ScanService) SetIndexer(indexer SceneIndexer) {
 	s.indexer = indexer
 }
 
+// SetStorageHealth makes scans skip storage paths that are offline
+func (s *ScanService) SetStorageHealth(storageHealth *StorageHealthService) {
+	s.storageHealth = storageHealth
+}
+
 // RecoverInterruptedScans marks any scans left in running state as failed
 func (s *ScanService) RecoverInterruptedScans() {
 	if err := s.scanHistoryRepo.MarkInterruptedAsFailedOnStartup(); err != nil {
@@ -98,8 +121,28 @@ func (s *ScanService) RecoverInterruptedScans() {
 	}
 }
 
+// ScanScope limits a scan to some storage paths, or to one folder of a storage
+// path. The zero value scans everything.
+type ScanScope struct {
+	StoragePathIDs []uint
+	// FolderPath is relative to the storage path and requires exactly one
+	// storage path ID
+	FolderPath string
+}
+
 // StartScan initiates a new scan of all storage paths
-func (s *ScanService) StartScan(_ context.Context) (*data.ScanHistory, error) {
+func (s *ScanService) StartScan(ctx context.Context) (*data.ScanHistory, error) {
+	return s.StartScopedScan(ctx, ScanScope{})
+}
+
+// StartScopedScan initiates a scan limited to the given scope. Missing file
+// detection is limited to the same paths and folder.
+func (s *ScanService) StartScopedScan(_ context.Context, scope ScanScope) (*data.ScanHistory, error) {
+	folderPath, err := s.validateScanFolder(scope)
+	if err != nil {
+		return nil, err
+	}
+
 	s.mu.Lock()
 	if s.currentScan != nil && s.currentScan.Status == "running" {
 		s.mu.Unlock()
@@ -113,6 +156,12 @@ func (s *ScanService) StartScan(_ context.Context) (*data.ScanHistory, error) {
 		StartedAt: now,
 		CreatedAt: now,
 	}
+	for _, id := range scope.StoragePathIDs {
+		scan.StoragePathIDs = append(scan.StoragePathIDs, int64(id))
+	}
+	if folderPath != "" {
+		scan.FolderPath = &folderPath
+	}
 
 	if err := s.scanHistoryRepo.Create(scan); err != nil {
 		s.mu.Unlock()
@@ -167,6 +216,146 @@ func (s *ScanService) GetStatus() ScanStatus {
 	return ScanStatus{Running: false}
 }
 
+// validateScanFolder checks that a folder scope names an existing directory
+// inside its storage path and returns the cleaned relative folder path.
+func (s *ScanService) validateScanFolder(scope ScanScope) (string, error) {
+	if scope.FolderPath == "" {
+		return "", nil
+	}
+	if len(scope.StoragePathIDs) != 1 {
+		return "", fmt.Errorf("a folder scan needs exactly one storage path")
+	}
+
+	// Cleaning from the root keeps ".." from leaving the storage path
+	folderPath := filepath.Clean("/" + scope.FolderPath)
+	if folderPath == "/" {
+		return "", nil
+	}
+
+	storagePath, err := s.storagePathService.GetByID(scope.StoragePathIDs[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to get storage path: %w", err)
+	}
+	if storagePath == nil {
+		return "", fmt.Errorf("storage path not found")
+	}
+	info, err := os.Stat(filepath.Join(storagePath.Path, folderPath))
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("folder does not exist: %s", folderPath)
+	}
+	return folderPath, nil
+}
+
+// scanRoot returns the directory a scan walks in a storage path.
+func scanRoot(storagePath data.StoragePath, scan *data.ScanHistory) string {
+	if scan.FolderPath == nil {
+		return storagePath.Path
+	}
+	return filepath.Join(storagePath.Path, *scan.FolderPath)
+}
+
+// IsRunning reports whether a full scan is in progress
+func (s *ScanService) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentScan != nil && s.currentScan.Status == "running"
+}
+
+// ImportFile applies the scan logic to a single video file: files already known
+// are skipped, files matching a missing or soft-deleted scene by size and
+// filename are treated as a move, anything else becomes a new scene.
+func (s *ScanService) ImportFile(path string, storagePath *data.StoragePath) error {
+	exists, err := s.sceneRepo.ExistsByStoredPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to check stored path: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil
+	}
+
+	scanConfig, err := s.loadScanConfig()
+	if err != nil {
+		return err
+	}
+	exclusions, err := newScanExclusions(scanConfig, storagePath.ExcludePatterns)
+	if err != nil {
+		return err
+	}
+	if excluded, _ := exclusions.excludePath(s.relScanPath(*storagePath, path), info.Size()); excluded {
+		return nil
+	}
+
+	candidate, err := s.sceneRepo.GetBySizeAndFilename(info.Size(), filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("failed to look up moved scene: %w", err)
+	}
+	if candidate != nil {
+		var moved, errs int
+		entry := data.ScanLookupEntry{
+			ID:               candidate.ID,
+			StoredPath:       candidate.StoredPath,
+			Size:             candidate.Size,
+			OriginalFilename: candidate.OriginalFilename,
+			FileHash:         candidate.FileHash,
+			IsDeleted:        candidate.DeletedAt.Valid,
+		}
+		if s.handleMovedFile(context.Background(), nil, []data.ScanLookupEntry{entry}, path, info, storagePath, &moved, &errs) {
+			if errs > 0 {
+				return fmt.Errorf("failed to update moved scene %d", candidate.ID)
+			}
+			return nil
+		}
+	}
+
+	scene := s.buildSceneRecord(path, info, storagePath)
+	sidecar := s.readSidecar(path)
+	if sidecar != nil {
+		s.sidecars.Prepare(scene, sidecar)
+	}
+	if err := s.sceneRepo.Create(scene); err != nil {
+		return fmt.Errorf("failed to create scene: %w", err)
+	}
+	s.linkSidecar(scene, sidecar)
+	s.applyFolderRules(s.folderRules.loadMatcher(), []*data.Scene{scene})
+	s.announceNewScenes([]*data.Scene{scene})
+	return nil
+}
+
+// RemoveMissing soft-deletes the scenes stored at path, or below it when path
+// was a directory, whose files no longer exist. Scenes on offline storage
+// paths are kept. Returns the number of scenes removed.
+func (s *ScanService) RemoveMissing(path string) (int, error) {
+	sceneInfos, err := s.sceneRepo.GetScenePathsForMissingDetection()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get scene paths: %w", err)
+	}
+
+	var removed int
+	for _, info := range sceneInfos {
+		if !isWithin(info.StoredPath, path) {
+			continue
+		}
+		if s.storageHealth != nil && s.storageHealth.IsOffline(info.StoragePathID) {
+			continue
+		}
+		if _, err := os.Stat(info.StoredPath); !os.IsNotExist(err) {
+			continue
+		}
+		if s.markSceneMissing(info.ID, info.StoredPath, info.Title) {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
 // GetHistory returns paginated scan history
 func (s *ScanService) GetHistory(page, limit int) ([]data.ScanHistory, int64, error) {
 	if page < 1 {
@@ -197,9 +386,13 @@ func (s *ScanService) buildLookupIndex() (*scanLookupIndex, error) {
 	}
 
 	lookupByKey := make(map[string][]data.ScanLookupEntry, len(entries))
+	lookupBySize := make(map[int64][]data.ScanLookupEntry)
 	for _, e := range entries {
 		key := buildScanLookupKey(e.Size, e.OriginalFilename)
 		lookupByKey[key] = append(lookupByKey[key], e)
+		if e.FileHash != "" {
+			lookupBySize[e.Size] = append(lookupBySize[e.Size], e)
+		}
 	}
 
 	s.logger.Info("Scan lookup index built",
@@ -208,8 +401,9 @@ func (s *ScanService) buildLookupIndex() (*scanLookupIndex, error) {
 	)
 
 	return &scanLookupIndex{
-		knownPaths:  knownPaths,
-		lookupByKey: lookupByKey,
+		knownPaths:   knownPaths,
+		lookupByKey:  lookupByKey,
+		lookupBySize: lookupBySize,
 	}, nil
 }
 
@@ -227,6 +421,7 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 		s.completeScan(scan, "failed", fmt.Sprintf("failed to get storage paths: %v", err))
 		return
 	}
+	paths = scopeStoragePaths(paths, scan.StoragePathIDs)
 
 	if len(paths) == 0 {
 		s.completeScan(scan, "completed", "")
@@ -240,14 +435,39 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 		return
 	}
 
+	scanConfig, err := s.loadScanConfig()
+	if err != nil {
+		s.completeScan(scan, "failed", err.Error())
+		return
+	}
+
+	// Folder rules are loaded once; editing them mid-scan affects the next one
+	folderRules := s.folderRules.loadMatcher()
+
 	var filesFound, scenesAdded, scenesSkipped, scenesRemoved, scenesMoved, scanErrors int
 	lastProgressDBWrite := time.Now()
 	lastProgressEvent := time.Now()
 
+	// Per-path report; written before the scan is marked finished so it is
+	// complete once clients see the final status
+	report := s.newScanReport(scan.ID)
+	finish := func(status string) {
+		report.flush()
+		s.completeScan(scan, status, "")
+	}
+
+	// An unmounted path would have all its scenes marked missing
+	paths = s.skipOfflinePaths(paths, report, &scanErrors)
+	if len(paths) == 0 {
+		scan.Errors = scanErrors
+		finish("completed")
+		return
+	}
+
 	// Phase 1: Detect missing files (scenes whose source files no longer exist)
-	scenesRemoved = s.detectMissingFiles(ctx, scan, paths)
+	scenesRemoved = s.detectMissingFiles(ctx, scan, paths, report)
 	if ctx.Err() != nil {
-		s.completeScan(scan, "cancelled", "")
+		finish("cancelled")
 		return
 	}
 
@@ -273,6 +493,9 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 		if err := s.sceneRepo.CreateInBatches(scenes, scanBatchSize); err != nil {
 			s.logger.Error("Failed to batch create scenes", zap.Error(err), zap.Int("count", len(scenes)))
 			scanErrors += len(batch)
+			for _, sc := range scenes {
+				report.failed(sc.StoredPath, err)
+			}
 			return
 		}
 
@@ -281,43 +504,13 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 		for _, sc := range scenes {
 			lookupIdx.knownPaths[sc.StoredPath] = struct{}{}
 		}
-
-		// Log each created scene and publish events
-		for _, sc := range scenes {
-			s.logger.Info("Scene record created",
-				zap.Uint("scene_id", sc.ID),
-				zap.String("stored_path", sc.StoredPath),
-				zap.String("title", sc.Title),
-			)
-			s.publishEvent("scan:scene_added", map[string]any{
-				"scene_id":   sc.ID,
-				"scene_path": sc.StoredPath,
-				"title":      sc.Title,
-			})
-		}
-
-		// Batch index in search engine
-		if s.indexer != nil {
-			sceneValues := make([]data.Scene, len(scenes))
-			for i, sc := range scenes {
-				sceneValues[i] = *sc
-			}
-			if err := s.indexer.BulkUpdateSceneIndex(sceneValues); err != nil {
-				s.logger.Warn("Failed to batch index scenes for search", zap.Error(err), zap.Int("count", len(scenes)))
-			}
+		for _, p := range batch {
+			s.linkSidecar(p.scene, p.sidecar)
+			report.added(p.scene.ID, p.scene.StoredPath)
 		}
+		s.applyFolderRules(folderRules, scenes)
 
-		// Submit for processing
-		if s.processingService != nil {
-			for _, sc := range scenes {
-				if err := s.processingService.SubmitScene(sc.ID, sc.StoredPath); err != nil {
-					s.logger.Warn("Failed to submit scene for processing",
-						zap.Uint("scene_id", sc.ID),
-						zap.Error(err),
-					)
-				}
-			}
-		}
+		s.announceNewScenes(scenes)
 	}
 
 	for _, storagePath := range paths {
@@ -325,7 +518,7 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 		case <-ctx.Done():
 			// Flush any remaining pending scenes before cancelling
 			flushBatch()
-			s.completeScan(scan, "cancelled", "")
+			finish("cancelled")
 			return
 		default:
 		}
@@ -333,7 +526,19 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 		// Update current path (in-memory only, DB write is batched)
 		s.updateScanProgressInMemory(scan, &storagePath.Path, nil, scan.PathsScanned, filesFound, scenesAdded, scenesSkipped, scenesRemoved, scenesMoved, scanErrors)
 
-		err := filepath.WalkDir(storagePath.Path, func(path string, d os.DirEntry, walkErr error) error {
+		exclusions, err := newScanExclusions(scanConfig, storagePath.ExcludePatterns)
+		if err != nil {
+			s.logger.Error("Invalid scan exclusions, skipping storage path",
+				zap.String("path", storagePath.Path),
+				zap.Error(err),
+			)
+			scanErrors++
+			report.failed(storagePath.Path, err)
+			continue
+		}
+
+		root := scanRoot(storagePath, scan)
+		err = filepath.WalkDir(root, func(path string, d os.DirEntry, walkErr error) error {
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -346,10 +551,17 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 					zap.Error(walkErr),
 				)
 				scanErrors++
+				report.failed(path, walkErr)
 				return nil // Continue walking
 			}
 
 			if d.IsDir() {
+				if path != root {
+					if excluded, reason := exclusions.excludeDir(s.relScanPath(storagePath, path)); excluded {
+						s.logger.Debug("Skipping excluded directory", zap.String("path", path), zap.String("reason", reason))
+						return filepath.SkipDir
+					}
+				}
 				return nil
 			}
 
@@ -371,6 +583,7 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 
 			// In-memory check: does scene already exist at this path?
 			if _, exists := lookupIdx.knownPaths[path]; exists {
+				s.syncSidecar(path)
 				scenesSkipped++
 				return nil
 			}
@@ -383,6 +596,13 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 					zap.Error(err),
 				)
 				scanErrors++
+				report.failed(path, err)
+				return nil
+			}
+
+			if excluded, reason := exclusions.excludeFile(s.relScanPath(storagePath, path), info.Size()); excluded {
+				s.logger.Debug("Skipping excluded file", zap.String("path", path), zap.String("reason", reason))
+				scenesSkipped++
 				return nil
 			}
 
@@ -390,16 +610,28 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 			filename := filepath.Base(path)
 			lookupKey := buildScanLookupKey(info.Size(), filename)
 			if candidates, ok := lookupIdx.lookupByKey[lookupKey]; ok {
-				if handled := s.handleMovedFile(candidates, path, info, &storagePath, &scenesMoved, &scanErrors); handled {
+				if handled := s.handleMovedFile(ctx, report, candidates, path, info, &storagePath, &scenesMoved, &scanErrors); handled {
 					// Also add the new path to knownPaths so we don't re-process it
 					lookupIdx.knownPaths[path] = struct{}{}
 					return nil
 				}
 			}
 
+			// Renamed files keep their size and contents; match them by hash
+			if candidates, ok := lookupIdx.lookupBySize[info.Size()]; ok {
+				if handled := s.handleMovedFile(ctx, report, candidates, path, info, &storagePath, &scenesMoved, &scanErrors); handled {
+					lookupIdx.knownPaths[path] = struct{}{}
+					return nil
+				}
+			}
+
 			// New scene: build record and add to pending batch
 			scene := s.buildSceneRecord(path, info, &storagePath)
-			pendingBatch = append(pendingBatch, pendingScene{scene: scene, storagePath: storagePath.Path})
+			sidecar := s.readSidecar(path)
+			if sidecar != nil {
+				s.sidecars.Prepare(scene, sidecar)
+			}
+			pendingBatch = append(pendingBatch, pendingScene{scene: scene, storagePath: storagePath.Path, sidecar: sidecar})
 			scenesAdded++
 
 			// Flush batch if it's full
@@ -428,7 +660,7 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 		if err != nil {
 			if err == context.Canceled {
 				flushBatch()
-				s.completeScan(scan, "cancelled", "")
+				finish("cancelled")
 				return
 			}
 			s.logger.Error("Error scanning storage path",
@@ -436,6 +668,7 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 				zap.Error(err),
 			)
 			scanErrors++
+			report.failed(storagePath.Path, err)
 		}
 
 		scan.PathsScanned++
@@ -452,12 +685,192 @@ func (s *ScanService) runScan(ctx context.Context, scan *data.ScanHistory) {
 	scan.VideosMoved = scenesMoved
 	scan.Errors = scanErrors
 
-	s.completeScan(scan, "completed", "")
+	finish("completed")
+}
+
+// announceNewScenes publishes scene_added events for freshly created scenes,
+// indexes them and submits them for processing.
+func (s *ScanService) announceNewScenes(scenes []*data.Scene) {
+	// Log each created scene and publish events
+	for _, sc := range scenes {
+		s.logger.Info("Scene record created",
+			zap.Uint("scene_id", sc.ID),
+			zap.String("stored_path", sc.StoredPath),
+			zap.String("title", sc.Title),
+		)
+		s.publishEvent("scan:scene_added", map[string]any{
+			"scene_id":   sc.ID,
+			"scene_path": sc.StoredPath,
+			"title":      sc.Title,
+		})
+	}
+
+	// Batch index in search engine
+	if s.indexer != nil {
+		sceneValues := make([]data.Scene, len(scenes))
+		for i, sc := range scenes {
+			sceneValues[i] = *sc
+		}
+		if err := s.indexer.BulkUpdateSceneIndex(sceneValues); err != nil {
+			s.logger.Warn("Failed to batch index scenes for search", zap.Error(err), zap.Int("count", len(scenes)))
+		}
+	}
+
+	// Submit for processing
+	if s.processingService != nil {
+		for _, sc := range scenes {
+			if err := s.processingService.SubmitScene(sc.ID, sc.StoredPath); err != nil {
+				s.logger.Warn("Failed to submit scene for processing",
+					zap.Uint("scene_id", sc.ID),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
+
+// readSidecar returns the sidecar metadata next to a video, nil when sidecars
+// are disabled, missing or unreadable.
+func (s *ScanService) readSidecar(path string) *SidecarMetadata {
+	if !s.sidecars.Enabled() {
+		return nil
+	}
+	meta, err := s.sidecars.Read(path)
+	if err != nil {
+		s.logger.Warn("Failed to read sidecar metadata", zap.String("path", path), zap.Error(err))
+		return nil
+	}
+	return meta
+}
+
+// linkSidecar sets the actors and tags of a newly created scene from its sidecar.
+func (s *ScanService) linkSidecar(scene *data.Scene, sidecar *SidecarMetadata) {
+	if sidecar == nil {
+		return
+	}
+	if err := s.sidecars.Link(scene.ID, sidecar); err != nil {
+		s.logger.Warn("Failed to link sidecar actors and tags",
+			zap.Uint("scene_id", scene.ID),
+			zap.String("sidecar", sidecar.Path),
+			zap.Error(err),
+		)
+	}
+}
+
+// applyFolderRules tags newly created scenes by their folder. It runs after
+// linkSidecar, which replaces tags and actors, so rules only ever add to them.
+func (s *ScanService) applyFolderRules(m *folderRuleMatcher, scenes []*data.Scene) {
+	if m == nil {
+		return
+	}
+	if _, err := s.folderRules.apply(m, scenes); err != nil {
+		s.logger.Warn("Failed to apply folder rules", zap.Int("count", len(scenes)), zap.Error(err))
+	}
+}
+
+// syncSidecar applies the sidecar of a video already in the library following
+// the conflict policy, re-indexing the scene when it changed.
+func (s *ScanService) syncSidecar(path string) {
+	sidecar := s.readSidecar(path)
+	if sidecar == nil {
+		return
+	}
+	scene, err := s.sceneRepo.GetByStoredPath(path)
+	if err != nil {
+		s.logger.Warn("Failed to get scene for sidecar metadata", zap.String("path", path), zap.Error(err))
+		return
+	}
+	changed, err := s.sidecars.ApplyToExisting(scene, sidecar)
+	if err != nil {
+		s.logger.Warn("Failed to apply sidecar metadata",
+			zap.Uint("scene_id", scene.ID),
+			zap.String("sidecar", sidecar.Path),
+			zap.Error(err),
+		)
+	}
+	if !changed {
+		return
+	}
+	s.logger.Info("Updated scene from sidecar metadata", zap.Uint("scene_id", scene.ID), zap.String("sidecar", sidecar.Path))
+	if s.indexer != nil {
+		if err := s.indexer.UpdateSceneIndex(scene); err != nil {
+			s.logger.Warn("Failed to update scene index", zap.Uint("scene_id", scene.ID), zap.Error(err))
+		}
+	}
+}
+
+// loadScanConfig returns the global scan exclusion rules, nil without a repository.
+func (s *ScanService) loadScanConfig() (*data.ScanConfigRecord, error) {
+	if s.scanConfigRepo == nil {
+		return nil, nil
+	}
+	cfg, err := s.scanConfigRepo.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scan config: %w", err)
+	}
+	return cfg, nil
+}
+
+// relScanPath returns path relative to the storage path root, for exclusion matching.
+func (s *ScanService) relScanPath(storagePath data.StoragePath, path string) string {
+	rel, err := filepath.Rel(storagePath.Path, path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return rel
+}
+
+// skipOfflinePaths re-checks storage path health and returns the paths that
+// are online, reporting the others as failed.
+func (s *ScanService) skipOfflinePaths(paths []data.StoragePath, report *scanReport, scanErrors *int) []data.StoragePath {
+	if s.storageHealth == nil {
+		return paths
+	}
+	if _, err := s.storageHealth.CheckAll(); err != nil {
+		s.logger.Warn("Failed to check storage path health before scan", zap.Error(err))
+	}
+
+	online := make([]data.StoragePath, 0, len(paths))
+	for _, path := range paths {
+		sample := s.storageHealth.Status(path.ID)
+		if sample == nil || sample.Status != data.StorageHealthOffline {
+			online = append(online, path)
+			continue
+		}
+		s.logger.Warn("Skipping offline storage path",
+			zap.String("path", path.Path),
+			zap.String("error", sample.Error),
+		)
+		*scanErrors++
+		report.failed(path.Path, fmt.Errorf("storage path is offline: %s", sample.Error))
+	}
+	return online
+}
+
+// scopeStoragePaths keeps the storage paths listed in ids; no IDs keeps all.
+func scopeStoragePaths(paths []data.StoragePath, ids []int64) []data.StoragePath {
+	if len(ids) == 0 {
+		return paths
+	}
+	wanted := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		wanted[uint(id)] = struct{}{}
+	}
+	scoped := make([]data.StoragePath, 0, len(ids))
+	for _, p := range paths {
+		if _, ok := wanted[p.ID]; ok {
+			scoped = append(scoped, p)
+		}
+	}
+	return scoped
 }
 
 // handleMovedFile checks lookup candidates and handles a moved/restored file.
+// Candidates with a stored file hash only match when the new file has the same hash.
+// Moves and failures are recorded in report, which is nil outside a scan.
 // Returns true if the file was handled as a move (caller should skip creation).
-func (s *ScanService) handleMovedFile(candidates []data.ScanLookupEntry, newPath string, info fs.FileInfo, storagePath *data.StoragePath, scenesMoved, scanErrors *int) bool {
+func (s *ScanService) handleMovedFile(ctx context.Context, report *scanReport, candidates []data.ScanLookupEntry, newPath string, info fs.FileInfo, storagePath *data.StoragePath, scenesMoved, scanErrors *int) bool {
+	var newHash string
 	for _, candidate := range candidates {
 		wasSoftDeleted := candidate.IsDeleted
 		oldPathMissing := false
@@ -471,6 +884,10 @@ func (s *ScanService) handleMovedFile(candidates []data.ScanLookupEntry, newPath
 			continue // Old file still exists - this is a copy, not a move
 		}
 
+		if candidate.FileHash != "" && !s.matchesFileHash(ctx, candidate, newPath, &newHash) {
+			continue // Same size but different contents
+		}
+
 		oldPath := candidate.StoredPath
 
 		// Restore soft-deleted scene first
@@ -481,6 +898,7 @@ func (s *ScanService) handleMovedFile(candidates []data.ScanLookupEntry, newPath
 					zap.Error(err),
 				)
 				*scanErrors++
+				report.failed(newPath, err)
 				return true
 			}
 		}
@@ -494,6 +912,7 @@ func (s *ScanService) handleMovedFile(candidates []data.ScanLookupEntry, newPath
 				zap.Error(err),
 			)
 			*scanErrors++
+			report.failed(newPath, err)
 			return true
 		}
 
@@ -511,6 +930,7 @@ func (s *ScanService) handleMovedFile(candidates []data.ScanLookupEntry, newPath
 		}
 
 		*scenesMoved++
+		report.moved(candidate.ID, oldPath, newPath)
 		s.logger.Info("Scene file moved/restored detected",
 			zap.Uint("scene_id", candidate.ID),
 			zap.String("old_path", oldPath),
@@ -530,13 +950,30 @@ func (s *ScanService) handleMovedFile(candidates []data.ScanLookupEntry, newPath
 	return false
 }
 
+// matchesFileHash reports whether the file at path has the candidate's stored hash.
+// The computed hash is cached in hash so each new file is read at most once.
+func (s *ScanService) matchesFileHash(ctx context.Context, candidate data.ScanLookupEntry, path string, hash *string) bool {
+	if *hash == "" {
+		computed, err := jobs.ComputeFileChecksum(ctx, path, nil)
+		if err != nil {
+			s.logger.Warn("Failed to hash file for move detection",
+				zap.String("path", path),
+				zap.Error(err),
+			)
+			return false
+		}
+		*hash = computed
+	}
+	return *hash == candidate.FileHash
+}
+
 // detectMissingFiles checks all scenes with storage paths and soft-deletes those whose files no longer exist.
 // Uses lightweight ScenePathInfo instead of full Scene objects.
-func (s *ScanService) detectMissingFiles(ctx context.Context, scan *data.ScanHistory, storagePaths []data.StoragePath) int {
-	// Build a set of valid storage path IDs
-	validPathIDs := make(map[uint]struct{})
+func (s *ScanService) detectMissingFiles(ctx context.Context, scan *data.ScanHistory, storagePaths []data.StoragePath, report *scanReport) int {
+	// Map valid storage path IDs to the directory scanned in each
+	validPathIDs := make(map[uint]string)
 	for _, sp := range storagePaths {
-		validPathIDs[sp.ID] = struct{}{}
+		validPathIDs[sp.ID] = scanRoot(sp, scan)
 	}
 
 	// Get lightweight scene path info (only id, stored_path, storage_path_id, title)
@@ -554,49 +991,58 @@ func (s *ScanService) detectMissingFiles(ctx context.Context, scan *data.ScanHis
 		default:
 		}
 
-		// Skip scenes not in our scanned storage paths
-		if _, ok := validPathIDs[info.StoragePathID]; !ok {
+		// Skip scenes not in our scanned storage paths and folder
+		root, ok := validPathIDs[info.StoragePathID]
+		if !ok || !isWithin(info.StoredPath, root) {
 			continue
 		}
 
 		// Check if file exists
 		if _, err := os.Stat(info.StoredPath); os.IsNotExist(err) {
-			// File doesn't exist - soft-delete the scene
-			if err := s.sceneRepo.MarkAsMissing(info.ID); err != nil {
-				s.logger.Warn("Failed to soft-delete missing scene",
-					zap.Uint("scene_id", info.ID),
-					zap.String("stored_path", info.StoredPath),
-					zap.Error(err),
-				)
-				continue
+			if s.markSceneMissing(info.ID, info.StoredPath, info.Title) {
+				scenesRemoved++
+				report.removed(info.ID, info.StoredPath)
 			}
+		}
+	}
 
-			// Remove from search index
-			if s.indexer != nil {
-				if err := s.indexer.DeleteSceneIndex(info.ID); err != nil {
-					s.logger.Warn("Failed to remove missing scene from search index",
-						zap.Uint("scene_id", info.ID),
-						zap.Error(err),
-					)
-				}
-			}
+	return scenesRemoved
+}
 
-			scenesRemoved++
-			s.logger.Info("Scene file missing - soft deleted",
-				zap.Uint("scene_id", info.ID),
-				zap.String("stored_path", info.StoredPath),
-				zap.String("title", info.Title),
-			)
+// markSceneMissing soft-deletes a scene whose file no longer exists and removes
+// it from the search index. Returns false if the scene could not be updated.
+func (s *ScanService) markSceneMissing(id uint, storedPath, title string) bool {
+	if err := s.sceneRepo.MarkAsMissing(id); err != nil {
+		s.logger.Warn("Failed to soft-delete missing scene",
+			zap.Uint("scene_id", id),
+			zap.String("stored_path", storedPath),
+			zap.Error(err),
+		)
+		return false
+	}
 
-			s.publishEvent("scan:scene_removed", map[string]any{
-				"scene_id":   info.ID,
-				"scene_path": info.StoredPath,
-				"title":      info.Title,
-			})
+	// Remove from search index
+	if s.indexer != nil {
+		if err := s.indexer.DeleteSceneIndex(id); err != nil {
+			s.logger.Warn("Failed to remove missing scene from search index",
+				zap.Uint("scene_id", id),
+				zap.Error(err),
+			)
 		}
 	}
 
-	return scenesRemoved
+	s.logger.Info("Scene file missing - soft deleted",
+		zap.Uint("scene_id", id),
+		zap.String("stored_path", storedPath),
+		zap.String("title", title),
+	)
+
+	s.publishEvent("scan:scene_removed", map[string]any{
+		"scene_id":   id,
+		"scene_path": storedPath,
+		"title":      title,
+	})
+	return true
 }
 
 // buildSceneRecord creates a Scene struct from file path and info without writing to DB.
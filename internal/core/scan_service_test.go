@@ -0,0 +1,92 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestScanService_ValidateScanFolder(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockStoragePathRepository(ctrl)
+	repo.EXPECT().GetByID(uint(1)).Return(&data.StoragePath{ID: 1, Path: root}, nil).AnyTimes()
+	svc := NewScanService(NewStoragePathService(repo, zap.NewNop()), nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	tests := []struct {
+		scope   ScanScope
+		want    string
+		wantErr bool
+	}{
+		{ScanScope{}, "", false},
+		{ScanScope{StoragePathIDs: []uint{1}, FolderPath: "a/b/"}, "/a/b", false},
+		{ScanScope{StoragePathIDs: []uint{1}, FolderPath: "/"}, "", false},
+		{ScanScope{StoragePathIDs: []uint{1}, FolderPath: "../../a"}, "/a", false},
+		{ScanScope{StoragePathIDs: []uint{1}, FolderPath: "missing"}, "", true},
+		{ScanScope{StoragePathIDs: []uint{1, 2}, FolderPath: "a"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := svc.validateScanFolder(tt.scope)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Fatalf("%+v: expected %q (error %v), got %q (%v)", tt.scope, tt.want, tt.wantErr, got, err)
+		}
+	}
+}
+
+func TestScanRoot(t *testing.T) {
+	sp := data.StoragePath{Path: "/media"}
+	if got := scanRoot(sp, &data.ScanHistory{}); got != "/media" {
+		t.Fatalf("expected the storage path, got %q", got)
+	}
+	folder := "/clips/2024"
+	if got := scanRoot(sp, &data.ScanHistory{FolderPath: &folder}); got != "/media/clips/2024" {
+		t.Fatalf("expected the folder, got %q", got)
+	}
+}
+
+func TestScanService_HandleMovedFileChecksHash(t *testing.T) {
+	dir := t.TempDir()
+	newPath := filepath.Join(dir, "renamed.mp4")
+	if err := os.WriteFile(newPath, []byte("hello world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const helloWorldSHA256 = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewScanService(nil, sceneRepo, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	storagePath := &data.StoragePath{ID: 2, Path: dir}
+
+	// Same size but different contents is not a move
+	var moved, errs int
+	other := data.ScanLookupEntry{ID: 1, StoredPath: "/old/other.mp4", Size: 11, FileHash: "deadbeef", IsDeleted: true}
+	if svc.handleMovedFile(context.Background(), nil, []data.ScanLookupEntry{other}, newPath, info, storagePath, &moved, &errs) {
+		t.Fatal("expected a hash mismatch to not be handled as a move")
+	}
+
+	// A matching hash restores the scene at its new path
+	match := data.ScanLookupEntry{ID: 3, StoredPath: "/old/clip.mp4", Size: 11, FileHash: helloWorldSHA256, IsDeleted: true}
+	sceneRepo.EXPECT().Restore(uint(3)).Return(nil)
+	sceneRepo.EXPECT().UpdateStoredPath(uint(3), newPath, &storagePath.ID).Return(nil)
+	if !svc.handleMovedFile(context.Background(), nil, []data.ScanLookupEntry{other, match}, newPath, info, storagePath, &moved, &errs) {
+		t.Fatal("expected a hash match to be handled as a move")
+	}
+	if moved != 1 || errs != 0 {
+		t.Fatalf("expected 1 move and no errors, got %d and %d", moved, errs)
+	}
+}
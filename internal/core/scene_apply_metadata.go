@@ -0,0 +1,192 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SceneMetadataInput is matched metadata to apply to a scene. Nil values and
+// empty lists leave the scene's own alone; performers, tags and markers are
+// added to the scene's.
+type SceneMetadataInput struct {
+	Title          *string
+	Description    *string
+	Studio         *string
+	ReleaseDate    *time.Time
+	PornDBSceneID  *string
+	StashDBSceneID *string
+	Performers     []string
+	Tags           []string
+	Markers        []data.UserSceneMarker
+	CoverURL       string
+}
+
+// SceneMetadataResult is the scene after applying metadata and what applying
+// it created.
+type SceneMetadataResult struct {
+	Scene *data.Scene `json:"scene"`
+	data.SceneMetadataApplicationResult
+}
+
+// ApplyMetadata applies matched metadata to a scene as one unit. The cover is
+// downloaded and resized first, next to the scene's thumbnails; the scene
+// columns, performers, tags and markers are then written in one transaction,
+// and the new thumbnails only replace the old ones once it commits. Any
+// failure leaves the scene as it was.
+func (s *SceneService) ApplyMetadata(sceneID uint, input SceneMetadataInput) (*SceneMetadataResult, error) {
+	scene, err := s.Repo.GetByID(sceneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrSceneNotFound(sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+
+	columns := map[string]any{}
+	if input.Title != nil {
+		columns["title"] = *input.Title
+	}
+	if input.Description != nil {
+		columns["description"] = *input.Description
+	}
+	if input.Studio != nil {
+		columns["studio"] = *input.Studio
+	}
+	if input.ReleaseDate != nil {
+		columns["release_date"] = *input.ReleaseDate
+	}
+	if input.PornDBSceneID != nil {
+		columns["porndb_scene_id"] = *input.PornDBSceneID
+	}
+	if input.StashDBSceneID != nil {
+		columns["stashdb_scene_id"] = *input.StashDBSceneID
+	}
+
+	var cover *stagedThumbnail
+	if input.CoverURL != "" {
+		if cover, err = s.stageThumbnail(scene, input.CoverURL); err != nil {
+			return nil, apperrors.NewValidationErrorWithField("cover", "failed to import cover: "+err.Error())
+		}
+		defer cover.discard()
+		columns["thumbnail_path"] = cover.smPath
+		columns["thumbnail_width"] = cover.width
+		columns["thumbnail_height"] = cover.height
+	}
+
+	applied, err := s.Repo.ApplyMetadata(data.SceneMetadataApplication{
+		SceneID:    sceneID,
+		Columns:    columns,
+		ActorNames: input.Performers,
+		TagNames:   input.Tags,
+		Markers:    input.Markers,
+	})
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to apply scene metadata", err)
+	}
+
+	if cover != nil {
+		if err := cover.commit(); err != nil {
+			s.logger.Warn("Failed to move imported cover into place",
+				zap.Uint("scene_id", sceneID),
+				zap.Error(err),
+			)
+		} else if s.EventBus != nil {
+			s.EventBus.Publish(SceneEvent{
+				Type:    "scene:thumbnail_complete",
+				SceneID: sceneID,
+				Data: map[string]any{
+					"thumbnail_path": cover.smPath,
+				},
+			})
+		}
+	}
+
+	scene, err = s.Repo.GetByID(sceneID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+	if s.indexer != nil {
+		if err := s.indexer.UpdateSceneIndex(scene); err != nil {
+			s.logger.Warn("Failed to update scene in search index",
+				zap.Uint("scene_id", sceneID),
+				zap.Error(err),
+			)
+		}
+	}
+	if input.PornDBSceneID != nil && *input.PornDBSceneID != "" && s.duplicateService != nil {
+		if _, err := s.duplicateService.FlagPornDBMatch(sceneID, *input.PornDBSceneID); err != nil {
+			s.logger.Warn("Failed to flag PornDB duplicate",
+				zap.Uint("scene_id", sceneID),
+				zap.String("porndb_scene_id", *input.PornDBSceneID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return &SceneMetadataResult{Scene: scene, SceneMetadataApplicationResult: *applied}, nil
+}
+
+// stagedThumbnail is a cover resized into a staging directory next to the
+// thumbnails, moved over the scene's thumbnails by commit.
+type stagedThumbnail struct {
+	dir            string
+	smPath, lgPath string
+	width, height  int
+}
+
+// stageThumbnail downloads an image and resizes it to a scene's thumbnails
+// without touching the current ones.
+func (s *SceneService) stageThumbnail(scene *data.Scene, imageURL string) (*stagedThumbnail, error) {
+	if scene.Width == 0 || scene.Height == 0 {
+		return nil, apperrors.ErrSceneDimensionsNotAvailable
+	}
+	smPath, lgPath, err := s.thumbnailPaths(scene.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	srcPath, err := downloadThumbnailImage(imageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(srcPath)
+
+	// Stage on the thumbnails' filesystem so commit is a rename
+	dir, err := os.MkdirTemp(filepath.Dir(smPath), ".staged-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	staged := &stagedThumbnail{dir: dir, smPath: smPath, lgPath: lgPath}
+	staged.width, staged.height, err = s.resizeThumbnail(scene, srcPath, staged.staged(smPath), staged.staged(lgPath))
+	if err != nil {
+		staged.discard()
+		return nil, err
+	}
+	return staged, nil
+}
+
+func (t *stagedThumbnail) staged(path string) string {
+	return filepath.Join(t.dir, filepath.Base(path))
+}
+
+// commit moves the staged thumbnails over the scene's.
+func (t *stagedThumbnail) commit() error {
+	if err := os.Rename(t.staged(t.smPath), t.smPath); err != nil {
+		return err
+	}
+	return os.Rename(t.staged(t.lgPath), t.lgPath)
+}
+
+// discard removes whatever is left of the staged thumbnails.
+func (t *stagedThumbnail) discard() {
+	os.RemoveAll(t.dir)
+}
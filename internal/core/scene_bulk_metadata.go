@@ -0,0 +1,267 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MaxBulkMetadataScenes caps how many scenes one bulk metadata update can touch.
+const MaxBulkMetadataScenes = 1000
+
+// Bulk metadata result statuses
+const (
+	BulkMetadataUpdated   = "updated"
+	BulkMetadataUnchanged = "unchanged"
+	BulkMetadataFailed    = "failed"
+)
+
+// titleTemplatePlaceholder matches a {placeholder} in a title template.
+var titleTemplatePlaceholder = regexp.MustCompile(`\{[a-z_]*\}`)
+
+// titleTemplateFields are the placeholders a title template can use.
+var titleTemplateFields = map[string]bool{
+	"{title}":    true, // current title
+	"{filename}": true, // original filename without its extension
+	"{date}":     true, // release date as YYYY-MM-DD, after this update
+	"{id}":       true,
+	"{index}":    true, // 1-based position in the request's scene list
+}
+
+// BulkUpdateMetadataRequest is a partial update applied to every listed scene.
+// Nil fields are left unchanged.
+type BulkUpdateMetadataRequest struct {
+	SceneIDs      []uint
+	TitleTemplate *string // e.g. "{date} - {title}"
+	Description   *string
+	ReleaseDate   *time.Time // zero clears the date
+	Origin        *string    // empty clears
+	Type          *string    // empty clears
+	StudioID      *uint      // 0 clears the studio
+}
+
+// BulkMetadataResult reports what a bulk metadata update did to one scene.
+type BulkMetadataResult struct {
+	SceneID uint   `json:"scene_id"`
+	Status  string `json:"status"`
+	Title   string `json:"title,omitempty"` // the rendered title, when the template applies
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkUpdateMetadataResponse is the outcome of a bulk metadata update. The
+// update is all or nothing: when any scene fails, Applied is false and no
+// scene was changed.
+type BulkUpdateMetadataResponse struct {
+	Applied   bool                 `json:"applied"`
+	Updated   int                  `json:"updated"`
+	Unchanged int                  `json:"unchanged"`
+	Failed    int                  `json:"failed"`
+	Results   []BulkMetadataResult `json:"results"`
+}
+
+// BulkUpdateMetadata applies a partial metadata update to many scenes in one
+// transaction, reporting the outcome for each scene.
+func (s *ExplorerService) BulkUpdateMetadata(req BulkUpdateMetadataRequest) (*BulkUpdateMetadataResponse, error) {
+	sceneIDs := uniqueIDs(req.SceneIDs)
+	if len(sceneIDs) == 0 {
+		return nil, apperrors.NewValidationErrorWithField("scene_ids", "at least one scene ID is required")
+	}
+	if len(sceneIDs) > MaxBulkMetadataScenes {
+		return nil, apperrors.NewValidationErrorWithField("scene_ids", "at most "+strconv.Itoa(MaxBulkMetadataScenes)+" scenes can be updated at once")
+	}
+	if req.TitleTemplate == nil && req.Description == nil && req.ReleaseDate == nil &&
+		req.Origin == nil && req.Type == nil && req.StudioID == nil {
+		return nil, apperrors.NewValidationError("no fields to update")
+	}
+	if req.TitleTemplate != nil {
+		if err := validateTitleTemplate(*req.TitleTemplate); err != nil {
+			return nil, err
+		}
+	}
+	if req.Origin != nil && len(*req.Origin) > 100 {
+		return nil, apperrors.NewValidationErrorWithField("origin", "origin must be at most 100 characters")
+	}
+	if req.Type != nil && len(*req.Type) > 50 {
+		return nil, apperrors.NewValidationErrorWithField("type", "type must be at most 50 characters")
+	}
+	if req.StudioID != nil && *req.StudioID != 0 {
+		if _, err := s.studioRepo.GetByID(*req.StudioID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.ErrStudioNotFound(*req.StudioID)
+			}
+			return nil, apperrors.NewInternalError("failed to find studio", err)
+		}
+	}
+
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load scenes", err)
+	}
+	byID := make(map[uint]*data.Scene, len(scenes))
+	for i := range scenes {
+		byID[scenes[i].ID] = &scenes[i]
+	}
+
+	resp := &BulkUpdateMetadataResponse{Results: make([]BulkMetadataResult, 0, len(sceneIDs))}
+	var updates []data.SceneColumnUpdate
+	for i, id := range sceneIDs {
+		result := BulkMetadataResult{SceneID: id}
+		scene, ok := byID[id]
+		if !ok {
+			result.Status = BulkMetadataFailed
+			result.Error = "scene not found"
+			resp.Failed++
+			resp.Results = append(resp.Results, result)
+			continue
+		}
+
+		columns, title, err := bulkMetadataColumns(scene, req, i+1)
+		if req.TitleTemplate != nil {
+			result.Title = title
+		}
+		switch {
+		case err != nil:
+			result.Status = BulkMetadataFailed
+			result.Error = err.Error()
+			resp.Failed++
+		case len(columns) == 0:
+			result.Status = BulkMetadataUnchanged
+			resp.Unchanged++
+		default:
+			result.Status = BulkMetadataUpdated
+			resp.Updated++
+			updates = append(updates, data.SceneColumnUpdate{SceneID: id, Columns: columns})
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if resp.Failed > 0 {
+		return resp, nil
+	}
+	resp.Applied = true
+	if len(updates) == 0 {
+		return resp, nil
+	}
+
+	if err := s.sceneRepo.BulkUpdateColumns(updates); err != nil {
+		return nil, apperrors.NewInternalError("failed to update scenes", err)
+	}
+
+	updatedIDs := make([]uint, len(updates))
+	for i, u := range updates {
+		updatedIDs[i] = u.SceneID
+	}
+	if s.indexer != nil {
+		updatedScenes, err := s.sceneRepo.GetByIDs(updatedIDs)
+		if err != nil {
+			s.logger.Warn("Failed to fetch scenes for index update", zap.Error(err))
+		} else if err := s.indexer.BulkUpdateSceneIndex(updatedScenes); err != nil {
+			s.logger.Warn("Failed to bulk update search index", zap.Error(err))
+		}
+	}
+
+	if s.eventBus != nil {
+		s.eventBus.Publish(SceneEvent{
+			Type:    "scenes_bulk_updated",
+			SceneID: 0, // Bulk operation
+		})
+	}
+
+	s.logger.Info("Bulk metadata update completed",
+		zap.Int("updated", resp.Updated),
+		zap.Int("unchanged", resp.Unchanged),
+	)
+
+	return resp, nil
+}
+
+// bulkMetadataColumns returns the columns of scene the update changes and the
+// rendered title, if the request has a title template.
+func bulkMetadataColumns(scene *data.Scene, req BulkUpdateMetadataRequest, index int) (map[string]any, string, error) {
+	columns := map[string]any{}
+
+	releaseDate := scene.ReleaseDate
+	if req.ReleaseDate != nil {
+		if req.ReleaseDate.IsZero() {
+			if scene.ReleaseDate != nil {
+				columns["release_date"] = nil
+			}
+			releaseDate = nil
+		} else {
+			if scene.ReleaseDate == nil || !scene.ReleaseDate.Equal(*req.ReleaseDate) {
+				columns["release_date"] = *req.ReleaseDate
+			}
+			releaseDate = req.ReleaseDate
+		}
+	}
+
+	var title string
+	if req.TitleTemplate != nil {
+		title = renderTitleTemplate(*req.TitleTemplate, scene, releaseDate, index)
+		if title == "" {
+			return nil, "", errors.New("title template renders an empty title")
+		}
+		if title != scene.Title {
+			columns["title"] = title
+		}
+	}
+	if req.Description != nil && *req.Description != scene.Description {
+		columns["description"] = *req.Description
+	}
+	if req.Origin != nil && *req.Origin != scene.Origin {
+		columns["origin"] = *req.Origin
+	}
+	if req.Type != nil && *req.Type != scene.Type {
+		columns["type"] = *req.Type
+	}
+	if req.StudioID != nil {
+		if *req.StudioID == 0 {
+			if scene.StudioID != nil {
+				columns["studio_id"] = nil
+			}
+		} else if scene.StudioID == nil || *scene.StudioID != *req.StudioID {
+			columns["studio_id"] = *req.StudioID
+		}
+	}
+
+	return columns, title, nil
+}
+
+func validateTitleTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return apperrors.NewValidationErrorWithField("title_template", "title template cannot be empty")
+	}
+	for _, placeholder := range titleTemplatePlaceholder.FindAllString(template, -1) {
+		if !titleTemplateFields[placeholder] {
+			return apperrors.NewValidationErrorWithField("title_template", "unknown placeholder "+placeholder)
+		}
+	}
+	return nil
+}
+
+func renderTitleTemplate(template string, scene *data.Scene, releaseDate *time.Time, index int) string {
+	date := ""
+	if releaseDate != nil {
+		date = releaseDate.Format("2006-01-02")
+	}
+	filename := strings.TrimSuffix(scene.OriginalFilename, filepath.Ext(scene.OriginalFilename))
+	title := strings.NewReplacer(
+		"{title}", scene.Title,
+		"{filename}", filename,
+		"{date}", date,
+		"{id}", strconv.FormatUint(uint64(scene.ID), 10),
+		"{index}", strconv.Itoa(index),
+	).Replace(template)
+	// Collapse the gaps and separators an empty placeholder leaves behind
+	title = strings.Join(strings.Fields(title), " ")
+	return strings.Trim(title, " -_|")
+}
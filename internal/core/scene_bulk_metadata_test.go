@@ -0,0 +1,126 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestBulkMetadataService(t *testing.T) (*ExplorerService, *mocks.MockSceneRepository, *mocks.MockStudioRepository) {
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	studioRepo := mocks.NewMockStudioRepository(ctrl)
+	svc := NewExplorerService(nil, nil, sceneRepo, nil, nil, studioRepo, nil, nil, zap.NewNop(), "", nil)
+	return svc, sceneRepo, studioRepo
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestBulkUpdateMetadata_AppliesTemplateAndFields(t *testing.T) {
+	svc, sceneRepo, studioRepo := newTestBulkMetadataService(t)
+
+	studioID := uint(4)
+	date := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+	studioRepo.EXPECT().GetByID(studioID).Return(&data.Studio{ID: studioID}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{
+		{ID: 1, Title: "Intro", OriginalFilename: "intro.mp4"},
+		{ID: 2, Title: "2024-03-09 Outro", OriginalFilename: "outro.mkv", ReleaseDate: &date, StudioID: &studioID},
+	}, nil)
+
+	var applied []data.SceneColumnUpdate
+	sceneRepo.EXPECT().BulkUpdateColumns(gomock.Any()).DoAndReturn(func(updates []data.SceneColumnUpdate) error {
+		applied = updates
+		return nil
+	})
+
+	result, err := svc.BulkUpdateMetadata(BulkUpdateMetadataRequest{
+		SceneIDs:      []uint{1, 2, 1},
+		TitleTemplate: strPtr("{date} {filename}"),
+		ReleaseDate:   &date,
+		StudioID:      &studioID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Applied || result.Updated != 2 || len(result.Results) != 2 {
+		t.Fatalf("expected both scenes updated, got %+v", result)
+	}
+	if result.Results[0].Title != "2024-03-09 intro" {
+		t.Fatalf("expected the rendered title, got %q", result.Results[0].Title)
+	}
+
+	if len(applied) != 2 {
+		t.Fatalf("expected two scene updates, got %+v", applied)
+	}
+	first := applied[0].Columns
+	if first["title"] != "2024-03-09 intro" || first["studio_id"] != studioID || first["release_date"] != date {
+		t.Fatalf("unexpected columns for the first scene: %+v", first)
+	}
+	// Only the title differs for the second scene
+	if second := applied[1].Columns; len(second) != 1 || second["title"] != "2024-03-09 outro" {
+		t.Fatalf("unexpected columns for the second scene: %+v", second)
+	}
+}
+
+func TestBulkUpdateMetadata_NothingAppliedWhenASceneFails(t *testing.T) {
+	svc, sceneRepo, _ := newTestBulkMetadataService(t)
+
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2, 3}).Return([]data.Scene{
+		{ID: 1, Title: "Intro"},
+		{ID: 2, Title: "Kept", Description: "same"},
+	}, nil)
+	sceneRepo.EXPECT().BulkUpdateColumns(gomock.Any()).Times(0)
+
+	result, err := svc.BulkUpdateMetadata(BulkUpdateMetadataRequest{
+		SceneIDs:    []uint{1, 2, 3},
+		Description: strPtr("same"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Applied || result.Failed != 1 || result.Unchanged != 1 || result.Updated != 1 {
+		t.Fatalf("expected a rejected update with per-scene results, got %+v", result)
+	}
+	if r := result.Results[2]; r.SceneID != 3 || r.Status != BulkMetadataFailed || r.Error == "" {
+		t.Fatalf("expected the missing scene to fail, got %+v", r)
+	}
+}
+
+func TestBulkUpdateMetadata_Validates(t *testing.T) {
+	svc, _, studioRepo := newTestBulkMetadataService(t)
+
+	cases := []BulkUpdateMetadataRequest{
+		{SceneIDs: nil, Description: strPtr("x")},
+		{SceneIDs: []uint{1}},
+		{SceneIDs: []uint{1}, TitleTemplate: strPtr("{title} {studio}")},
+		{SceneIDs: []uint{1}, TitleTemplate: strPtr("  ")},
+	}
+	for _, req := range cases {
+		if _, err := svc.BulkUpdateMetadata(req); !apperrors.IsValidation(err) {
+			t.Fatalf("expected a validation error for %+v, got %v", req, err)
+		}
+	}
+
+	missing := uint(9)
+	studioRepo.EXPECT().GetByID(missing).Return(nil, gorm.ErrRecordNotFound)
+	if _, err := svc.BulkUpdateMetadata(BulkUpdateMetadataRequest{SceneIDs: []uint{1}, StudioID: &missing}); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected a not found error for an unknown studio, got %v", err)
+	}
+}
+
+func TestRenderTitleTemplate_CollapsesEmptyPlaceholders(t *testing.T) {
+	scene := &data.Scene{ID: 5, Title: "Scene", OriginalFilename: "clip.final.mp4"}
+	if got := renderTitleTemplate("{date} - {title}", scene, nil, 1); got != "Scene" {
+		t.Fatalf("expected the separator of an empty date to be dropped, got %q", got)
+	}
+	if got := renderTitleTemplate("#{index} {filename} ({id})", scene, nil, 3); got != "#3 clip.final (5)" {
+		t.Fatalf("unexpected title %q", got)
+	}
+}
@@ -0,0 +1,202 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// HeatmapBuckets is the number of equal slices a scene's heatmap divides it into.
+	HeatmapBuckets = 100
+	// Markers are deliberate, so they weigh more than a session stopping somewhere.
+	heatmapMarkerWeight = 2
+	heatmapBatchSize    = 200
+	// Heatmaps are rebuilt at least this often, since deleted markers leave no trace to detect.
+	heatmapMaxAge = 24 * time.Hour
+)
+
+// SceneHeatmapService aggregates the markers of all users and the positions
+// where watch sessions stopped into a per-scene popularity heatmap, stored on
+// the scene. A background loop recomputes the heatmaps of scenes whose
+// activity changed.
+type SceneHeatmapService struct {
+	repo      data.HeatmapRepository
+	sceneRepo data.SceneRepository
+	interval  time.Duration
+	logger    *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewSceneHeatmapService(repo data.HeatmapRepository, sceneRepo data.SceneRepository, interval time.Duration, logger *zap.Logger) *SceneHeatmapService {
+	return &SceneHeatmapService{
+		repo:      repo,
+		sceneRepo: sceneRepo,
+		interval:  interval,
+		logger:    logger.With(zap.String("component", "scene_heatmap")),
+	}
+}
+
+// Start refreshes stale heatmaps now and then every interval. It is a no-op when
+// the interval is 0.
+func (s *SceneHeatmapService) Start() {
+	if s.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			if n, err := s.RefreshStale(ctx); err != nil {
+				s.logger.Warn("Failed to refresh scene heatmaps", zap.Error(err))
+			} else if n > 0 {
+				s.logger.Info("Refreshed scene heatmaps", zap.Int("count", n))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	s.logger.Info("Scene heatmap refresher started", zap.Duration("interval", s.interval))
+}
+
+// Stop halts the background refresh.
+func (s *SceneHeatmapService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// RefreshStale recomputes every stale heatmap and returns how many were updated.
+// Each scene is refreshed at most once per call, so a scene that stays stale
+// (e.g. activity keeps coming in) can't keep the loop going.
+func (s *SceneHeatmapService) RefreshStale(ctx context.Context) (int, error) {
+	done := make(map[uint]bool)
+	for ctx.Err() == nil {
+		ids, err := s.repo.ListStaleSceneIDs(time.Now().Add(-heatmapMaxAge), heatmapBatchSize)
+		if err != nil {
+			return len(done), err
+		}
+		progressed := false
+		for _, id := range ids {
+			if ctx.Err() != nil || done[id] {
+				continue
+			}
+			if err := s.RefreshScene(id); err != nil {
+				return len(done), err
+			}
+			done[id] = true
+			progressed = true
+		}
+		if !progressed {
+			break
+		}
+	}
+	return len(done), nil
+}
+
+// RefreshScene recomputes one scene's heatmap.
+func (s *SceneHeatmapService) RefreshScene(sceneID uint) error {
+	// Taken before reading so activity during the computation marks the scene stale again
+	computedAt := time.Now()
+
+	spans, err := s.repo.GetMarkerSpans(sceneID)
+	if err != nil {
+		return err
+	}
+	positions, err := s.repo.GetWatchPositions(sceneID)
+	if err != nil {
+		return err
+	}
+	scene, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.SaveHeatmap(sceneID, computeHeatmap(scene.Duration, spans, positions), computedAt)
+}
+
+// computeHeatmap buckets marker spans and watch positions over the scene,
+// smooths neighbouring buckets and scales the result to 0-100. It returns nil
+// when there is nothing to show.
+func computeHeatmap(duration int, spans []data.HeatmapSpan, positions []int) []int64 {
+	if duration <= 0 {
+		return nil
+	}
+
+	bucketOf := func(second int) int {
+		b := second * HeatmapBuckets / duration
+		if b < 0 {
+			return 0
+		}
+		if b >= HeatmapBuckets {
+			return HeatmapBuckets - 1
+		}
+		return b
+	}
+
+	raw := make([]float64, HeatmapBuckets)
+	for _, span := range spans {
+		if span.Start > duration {
+			continue
+		}
+		last := bucketOf(span.Start)
+		if span.End != nil {
+			last = bucketOf(*span.End)
+		}
+		for b := bucketOf(span.Start); b <= last; b++ {
+			raw[b] += heatmapMarkerWeight
+		}
+	}
+	for _, pos := range positions {
+		if pos < duration {
+			raw[bucketOf(pos)]++
+		}
+	}
+
+	// 1-2-1 smoothing so a single hit reads as a bump rather than a spike
+	smoothed := make([]float64, HeatmapBuckets)
+	max := 0.0
+	for i := range raw {
+		sum, weight := 2*raw[i], 2.0
+		if i > 0 {
+			sum += raw[i-1]
+			weight++
+		}
+		if i < HeatmapBuckets-1 {
+			sum += raw[i+1]
+			weight++
+		}
+		smoothed[i] = sum / weight
+		if smoothed[i] > max {
+			max = smoothed[i]
+		}
+	}
+	if max == 0 {
+		return nil
+	}
+
+	buckets := make([]int64, HeatmapBuckets)
+	for i, v := range smoothed {
+		buckets[i] = int64(v/max*100 + 0.5)
+	}
+	return buckets
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestComputeHeatmap(t *testing.T) {
+	if got := computeHeatmap(0, []data.HeatmapSpan{{Start: 10}}, nil); got != nil {
+		t.Fatalf("expected no heatmap without a duration, got %v", got)
+	}
+	if got := computeHeatmap(1000, nil, []int{1000, 2000}); got != nil {
+		t.Fatalf("expected no heatmap without activity inside the scene, got %v", got)
+	}
+
+	end := 209
+	buckets := computeHeatmap(1000, []data.HeatmapSpan{
+		{Start: 500},
+		{Start: 505},
+		{Start: 200, End: &end},
+	}, []int{500, 990})
+	if len(buckets) != HeatmapBuckets {
+		t.Fatalf("expected %d buckets, got %d", HeatmapBuckets, len(buckets))
+	}
+	// Two markers and a stopped session land in bucket 50, the hottest spot
+	if buckets[50] != 100 {
+		t.Fatalf("expected bucket 50 to be the peak, got %v", buckets)
+	}
+	// The range marker covers buckets 20 and smoothing spreads into 19 and 21
+	if buckets[20] == 0 || buckets[19] == 0 || buckets[21] == 0 || buckets[10] != 0 {
+		t.Fatalf("unexpected range coverage %v", buckets[8:24])
+	}
+	if buckets[99] == 0 || buckets[99] >= buckets[50] {
+		t.Fatalf("unexpected weight for a single watch position: %d", buckets[99])
+	}
+}
+
+func TestSceneHeatmapService_RefreshStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockHeatmapRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewSceneHeatmapService(repo, sceneRepo, time.Minute, zap.NewNop())
+
+	// Scene 1 stays stale; it must not be refreshed twice in one run
+	repo.EXPECT().ListStaleSceneIDs(gomock.Any(), heatmapBatchSize).Return([]uint{1, 2}, nil)
+	repo.EXPECT().ListStaleSceneIDs(gomock.Any(), heatmapBatchSize).Return([]uint{1}, nil)
+	for _, id := range []uint{1, 2} {
+		repo.EXPECT().GetMarkerSpans(id).Return([]data.HeatmapSpan{{Start: 30}}, nil)
+		repo.EXPECT().GetWatchPositions(id).Return(nil, nil)
+		sceneRepo.EXPECT().GetByID(id).Return(&data.Scene{ID: id, Duration: 600}, nil)
+		repo.EXPECT().SaveHeatmap(id, gomock.Len(HeatmapBuckets), gomock.Any()).Return(nil)
+	}
+
+	n, err := svc.RefreshStale(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 refreshed heatmaps, got %d, %v", n, err)
+	}
+}
@@ -14,7 +14,9 @@ import (
 type PoolConfig = processing.PoolConfig
 type ProcessingQualityConfig = processing.QualityConfig
 type QueueStatus = processing.QueueStatus
+type PoolLoad = processing.PoolLoad
 type BulkPhaseResult = processing.BulkPhaseResult
+type BulkPhasePreview = processing.BulkPhasePreview
 
 // eventBusAdapter adapts EventBus to the processing.EventPublisher interface
 type eventBusAdapter struct {
@@ -70,10 +72,6 @@ func (a *jobHistoryAdapter) CreatePendingJobWithRetry(jobID string, sceneID uint
 	return a.service.CreatePendingJobWithRetry(jobID, sceneID, sceneTitle, phase, retryCount, maxRetries, forceTarget)
 }
 
-func (a *jobHistoryAdapter) ExistsPendingOrRunning(sceneID uint, phase string) (bool, error) {
-	return a.service.ExistsPendingOrRunning(sceneID, phase)
-}
-
 // SceneProcessingService orchestrates scene processing using worker pools
 type SceneProcessingService struct {
 	poolManager   *processing.PoolManager
@@ -143,6 +141,26 @@ func (s *SceneProcessingService) SetIndexer(indexer SceneIndexer) {
 	s.resultHandler.SetIndexer(indexer)
 }
 
+// SetArtifactRecorder records artifact sizes after phases that write files complete
+func (s *SceneProcessingService) SetArtifactRecorder(artifacts processing.ArtifactRecorder) {
+	s.resultHandler.SetArtifactRecorder(artifacts)
+}
+
+// SetJobLogRecorder stores the logs of job runs as they finish
+func (s *SceneProcessingService) SetJobLogRecorder(jobLogs processing.JobLogRecorder) {
+	s.resultHandler.SetJobLogRecorder(jobLogs)
+}
+
+// SetDuplicateFlagger groups scenes with the same checksum when the checksum phase completes
+func (s *SceneProcessingService) SetDuplicateFlagger(duplicates processing.DuplicateFlagger) {
+	s.resultHandler.SetDuplicateFlagger(duplicates)
+}
+
+// SetRemoteExecutor enables dispatching remote-capable jobs to remote agents
+func (s *SceneProcessingService) SetRemoteExecutor(executor jobs.RemoteExecutor) {
+	s.poolManager.SetRemoteExecutor(executor)
+}
+
 // Start starts all worker pools
 func (s *SceneProcessingService) Start() {
 	s.poolManager.Start()
@@ -196,6 +214,12 @@ func (s *SceneProcessingService) SubmitBulkPhase(phase string, mode string, forc
 	return s.jobSubmitter.SubmitBulkPhase(phase, mode, forceTarget, sceneIDs)
 }
 
+// PreviewBulkPhase returns the scenes SubmitBulkPhase would enqueue and the estimated
+// work involved, without enqueuing anything.
+func (s *SceneProcessingService) PreviewBulkPhase(phase string, mode string, sceneIDs []uint) (*BulkPhasePreview, error) {
+	return s.jobSubmitter.PreviewBulkPhase(phase, mode, sceneIDs)
+}
+
 // CancelJob cancels a job by its ID.
 // First attempts to cancel in the worker pool (running/queued jobs).
 // Falls back to cancelling a pending job directly in the database.
@@ -231,6 +255,16 @@ func (s *SceneProcessingService) UpdatePoolConfig(cfg PoolConfig) error {
 	return s.poolManager.UpdatePoolConfig(cfg)
 }
 
+// GetPoolLoads returns the size and load of every pool
+func (s *SceneProcessingService) GetPoolLoads() []PoolLoad {
+	return s.poolManager.GetPoolLoads()
+}
+
+// ResizePool sets the number of workers of a phase's pool
+func (s *SceneProcessingService) ResizePool(phase string, workers int) error {
+	return s.poolManager.ResizePool(phase, workers)
+}
+
 // GetProcessingQualityConfig returns the current quality configuration
 func (s *SceneProcessingService) GetProcessingQualityConfig() ProcessingQualityConfig {
 	return s.poolManager.GetQualityConfig()
@@ -0,0 +1,300 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/core/scraper"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Scene fields a scrape can populate
+const (
+	ScrapeFieldTitle       = "title"
+	ScrapeFieldDescription = "description"
+	ScrapeFieldDate        = "date"
+	ScrapeFieldStudio      = "studio"
+	ScrapeFieldPerformers  = "performers"
+	ScrapeFieldTags        = "tags"
+	ScrapeFieldCover       = "cover"
+)
+
+var scrapeFields = []string{
+	ScrapeFieldTitle, ScrapeFieldDescription, ScrapeFieldDate, ScrapeFieldStudio,
+	ScrapeFieldPerformers, ScrapeFieldTags, ScrapeFieldCover,
+}
+
+// sceneScraper is the part of the scraper registry the service uses.
+type sceneScraper interface {
+	Scrape(ctx context.Context, rawURL string) (*scraper.ScrapedScene, error)
+	List() []scraper.Info
+}
+
+// scrapeSceneWriter writes scraped metadata and covers to scenes.
+type scrapeSceneWriter interface {
+	GetScene(id uint) (*data.Scene, error)
+	UpdateSceneMetadata(id uint, title, description, studio string, releaseDate *time.Time, porndbSceneID string) (*data.Scene, error)
+	SetThumbnailFromURL(sceneID uint, imageURL string) error
+}
+
+// scrapeTagWriter adds scraped tags to scenes.
+type scrapeTagWriter interface {
+	GetTagsByNames(names []string) ([]data.Tag, error)
+	CreateTag(name, color string) (*data.Tag, error)
+	GetSceneTags(sceneID uint) ([]data.Tag, error)
+	SetSceneTags(sceneID uint, tagIDs []uint) ([]data.Tag, error)
+}
+
+// scrapeActorWriter adds scraped performers to scenes.
+type scrapeActorWriter interface {
+	Create(input CreateActorInput) (*data.Actor, error)
+	GetSceneActors(sceneID uint) ([]data.Actor, error)
+	SetSceneActors(sceneID uint, actorIDs []uint) ([]data.Actor, error)
+}
+
+// SceneScrapeResult is what a scrape found and what it changed.
+type SceneScrapeResult struct {
+	Scraped  *scraper.ScrapedScene `json:"scraped"`
+	Scene    *data.Scene           `json:"scene"`
+	Applied  []string              `json:"applied"`            // fields written to the scene
+	Warnings []string              `json:"warnings,omitempty"` // parts that failed without failing the scrape
+}
+
+// SceneScrapeService populates scenes from the web page of a scene, using the
+// scraper registry to extract its metadata. Scraped tags and performers are
+// added to the scene's own, creating the ones that don't exist yet.
+type SceneScrapeService struct {
+	scrapers  sceneScraper
+	scenes    scrapeSceneWriter
+	tags      scrapeTagWriter
+	actors    scrapeActorWriter
+	actorRepo data.ActorRepository
+	logger    *zap.Logger
+}
+
+func NewSceneScrapeService(
+	registry *scraper.Registry,
+	sceneService *SceneService,
+	tagService *TagService,
+	actorService *ActorService,
+	actorRepo data.ActorRepository,
+	logger *zap.Logger,
+) *SceneScrapeService {
+	s := &SceneScrapeService{
+		actorRepo: actorRepo,
+		logger:    logger.With(zap.String("component", "scene_scrape")),
+	}
+	// Keep nil services from becoming non-nil interfaces
+	if registry != nil {
+		s.scrapers = registry
+	}
+	if sceneService != nil {
+		s.scenes = sceneService
+	}
+	if tagService != nil {
+		s.tags = tagService
+	}
+	if actorService != nil {
+		s.actors = actorService
+	}
+	return s
+}
+
+// ListScrapers returns the registered scrapers.
+func (s *SceneScrapeService) ListScrapers() []scraper.Info {
+	return s.scrapers.List()
+}
+
+// Preview scrapes a page without touching any scene.
+func (s *SceneScrapeService) Preview(ctx context.Context, rawURL string) (*scraper.ScrapedScene, error) {
+	return s.scrapers.Scrape(ctx, rawURL)
+}
+
+// ScrapeScene scrapes a page and writes the given fields of what it found to
+// the scene, or every field when fields is empty. Fields the page doesn't have
+// are left alone.
+func (s *SceneScrapeService) ScrapeScene(ctx context.Context, sceneID uint, rawURL string, fields []string) (*SceneScrapeResult, error) {
+	selected, err := selectFields("fields", fields, scrapeFields, scrapeFields)
+	if err != nil {
+		return nil, err
+	}
+	scene, err := s.scenes.GetScene(sceneID)
+	if err != nil {
+		return nil, err
+	}
+	scraped, err := s.scrapers.Scrape(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SceneScrapeResult{Scraped: scraped, Applied: []string{}}
+	if err := s.applyDetails(scene, scraped, selected, result); err != nil {
+		return nil, err
+	}
+	if selected[ScrapeFieldPerformers] && len(scraped.Performers) > 0 {
+		if err := s.addPerformers(sceneID, scraped.Performers); err != nil {
+			result.Warnings = append(result.Warnings, "performers: "+err.Error())
+		} else {
+			result.Applied = append(result.Applied, ScrapeFieldPerformers)
+		}
+	}
+	if selected[ScrapeFieldTags] && len(scraped.Tags) > 0 {
+		if err := s.addTags(sceneID, scraped.Tags); err != nil {
+			result.Warnings = append(result.Warnings, "tags: "+err.Error())
+		} else {
+			result.Applied = append(result.Applied, ScrapeFieldTags)
+		}
+	}
+	if selected[ScrapeFieldCover] && scraped.CoverImage != "" {
+		if err := s.scenes.SetThumbnailFromURL(sceneID, scraped.CoverImage); err != nil {
+			result.Warnings = append(result.Warnings, "cover: "+err.Error())
+		} else {
+			result.Applied = append(result.Applied, ScrapeFieldCover)
+		}
+	}
+
+	if result.Scene, err = s.scenes.GetScene(sceneID); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Scraped scene metadata",
+		zap.Uint("scene_id", sceneID),
+		zap.String("scraper", scraped.Scraper),
+		zap.Strings("applied", result.Applied),
+		zap.Int("warnings", len(result.Warnings)),
+	)
+	return result, nil
+}
+
+// applyDetails writes the scraped title, description, date and studio.
+func (s *SceneScrapeService) applyDetails(scene *data.Scene, scraped *scraper.ScrapedScene, selected map[string]bool, result *SceneScrapeResult) error {
+	title, description, studio, releaseDate := scene.Title, scene.Description, scene.Studio, scene.ReleaseDate
+	var applied []string
+	if selected[ScrapeFieldTitle] && scraped.Title != "" {
+		title = scraped.Title
+		applied = append(applied, ScrapeFieldTitle)
+	}
+	if selected[ScrapeFieldDescription] && scraped.Description != "" {
+		description = scraped.Description
+		applied = append(applied, ScrapeFieldDescription)
+	}
+	if selected[ScrapeFieldStudio] && scraped.Studio != "" {
+		studio = scraped.Studio
+		applied = append(applied, ScrapeFieldStudio)
+	}
+	if selected[ScrapeFieldDate] && scraped.Date != "" {
+		if date, err := time.Parse("2006-01-02", scraped.Date); err == nil {
+			releaseDate = &date
+			applied = append(applied, ScrapeFieldDate)
+		}
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	if _, err := s.scenes.UpdateSceneMetadata(scene.ID, title, description, studio, releaseDate, scene.PornDBSceneID); err != nil {
+		return apperrors.NewInternalError("failed to update scene metadata", err)
+	}
+	result.Applied = append(result.Applied, applied...)
+	return nil
+}
+
+// addPerformers adds the named actors to the scene, creating missing ones.
+func (s *SceneScrapeService) addPerformers(sceneID uint, names []string) error {
+	current, err := s.actors.GetSceneActors(sceneID)
+	if err != nil {
+		return err
+	}
+	ids := make([]uint, 0, len(current)+len(names))
+	seen := make(map[uint]bool, len(current)+len(names))
+	for _, actor := range current {
+		ids = append(ids, actor.ID)
+		seen[actor.ID] = true
+	}
+
+	for _, name := range names {
+		actor, err := s.actorRepo.GetByName(name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			actor, err = s.actors.Create(CreateActorInput{Name: name})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find or create actor %q: %w", name, err)
+		}
+		if !seen[actor.ID] {
+			ids = append(ids, actor.ID)
+			seen[actor.ID] = true
+		}
+	}
+
+	_, err = s.actors.SetSceneActors(sceneID, ids)
+	return err
+}
+
+// addTags adds the named tags to the scene, creating missing ones.
+func (s *SceneScrapeService) addTags(sceneID uint, names []string) error {
+	current, err := s.tags.GetSceneTags(sceneID)
+	if err != nil {
+		return err
+	}
+	existing, err := s.tags.GetTagsByNames(names)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uint, 0, len(current)+len(names))
+	seen := make(map[uint]bool, len(current)+len(names))
+	add := func(id uint) {
+		if !seen[id] {
+			ids = append(ids, id)
+			seen[id] = true
+		}
+	}
+	for _, tag := range current {
+		add(tag.ID)
+	}
+	byName := data.TagIDsByName(existing)
+	for _, name := range names {
+		if id, ok := byName[name]; ok {
+			add(id)
+			continue
+		}
+		tag, err := s.tags.CreateTag(name, "")
+		if err != nil {
+			return fmt.Errorf("failed to create tag %q: %w", name, err)
+		}
+		add(tag.ID)
+	}
+
+	_, err = s.tags.SetSceneTags(sceneID, ids)
+	return err
+}
+
+// selectFields validates the requested fields against known; none selects
+// defaults.
+func selectFields(param string, fields, known, defaults []string) (map[string]bool, error) {
+	selected := make(map[string]bool, len(known))
+	if len(fields) == 0 {
+		fields = defaults
+	}
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		valid := false
+		for _, k := range known {
+			if field == k {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, apperrors.NewValidationErrorWithField(param, param+" must be among "+strings.Join(known, ", "))
+		}
+		selected[field] = true
+	}
+	return selected, nil
+}
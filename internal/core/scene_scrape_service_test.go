@@ -0,0 +1,184 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/core/scraper"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type fakeSceneScraper struct {
+	scene *scraper.ScrapedScene
+}
+
+func (f *fakeSceneScraper) Scrape(ctx context.Context, rawURL string) (*scraper.ScrapedScene, error) {
+	return f.scene, nil
+}
+
+func (f *fakeSceneScraper) List() []scraper.Info { return nil }
+
+type fakeScrapeScenes struct {
+	scene    data.Scene
+	coverErr error
+	cover    string
+}
+
+func (f *fakeScrapeScenes) GetScene(id uint) (*data.Scene, error) {
+	scene := f.scene
+	return &scene, nil
+}
+
+func (f *fakeScrapeScenes) UpdateSceneMetadata(id uint, title, description, studio string, releaseDate *time.Time, porndbSceneID string) (*data.Scene, error) {
+	f.scene.Title, f.scene.Description, f.scene.Studio, f.scene.ReleaseDate, f.scene.PornDBSceneID = title, description, studio, releaseDate, porndbSceneID
+	return &f.scene, nil
+}
+
+func (f *fakeScrapeScenes) SetThumbnailFromURL(sceneID uint, imageURL string) error {
+	if f.coverErr != nil {
+		return f.coverErr
+	}
+	f.cover = imageURL
+	return nil
+}
+
+type fakeScrapeTags struct {
+	tags      []data.Tag
+	sceneTags []uint
+}
+
+func (f *fakeScrapeTags) GetTagsByNames(names []string) ([]data.Tag, error) {
+	var found []data.Tag
+	for _, tag := range f.tags {
+		for _, name := range names {
+			if tag.Name == name {
+				found = append(found, tag)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeScrapeTags) CreateTag(name, color string) (*data.Tag, error) {
+	tag := data.Tag{ID: uint(len(f.tags) + 1), Name: name}
+	f.tags = append(f.tags, tag)
+	return &tag, nil
+}
+
+func (f *fakeScrapeTags) GetSceneTags(sceneID uint) ([]data.Tag, error) {
+	var tags []data.Tag
+	for _, id := range f.sceneTags {
+		tags = append(tags, data.Tag{ID: id})
+	}
+	return tags, nil
+}
+
+func (f *fakeScrapeTags) SetSceneTags(sceneID uint, tagIDs []uint) ([]data.Tag, error) {
+	f.sceneTags = tagIDs
+	return nil, nil
+}
+
+type fakeScrapeActors struct {
+	created     []string
+	sceneActors []uint
+}
+
+func (f *fakeScrapeActors) Create(input CreateActorInput) (*data.Actor, error) {
+	f.created = append(f.created, input.Name)
+	return &data.Actor{ID: uint(100 + len(f.created)), Name: input.Name}, nil
+}
+
+func (f *fakeScrapeActors) GetSceneActors(sceneID uint) ([]data.Actor, error) {
+	var actors []data.Actor
+	for _, id := range f.sceneActors {
+		actors = append(actors, data.Actor{ID: id})
+	}
+	return actors, nil
+}
+
+func (f *fakeScrapeActors) SetSceneActors(sceneID uint, actorIDs []uint) ([]data.Actor, error) {
+	f.sceneActors = actorIDs
+	return nil, nil
+}
+
+func newTestSceneScrapeService(t *testing.T, scraped *scraper.ScrapedScene) (*SceneScrapeService, *fakeScrapeScenes, *fakeScrapeTags, *fakeScrapeActors, *mocks.MockActorRepository) {
+	ctrl := gomock.NewController(t)
+	actorRepo := mocks.NewMockActorRepository(ctrl)
+	scenes := &fakeScrapeScenes{scene: data.Scene{ID: 7, Title: "clip_0042", Studio: "Old Studio", PornDBSceneID: "pdb-1"}}
+	tags := &fakeScrapeTags{tags: []data.Tag{{ID: 1, Name: "Outdoor"}}, sceneTags: []uint{1}}
+	actors := &fakeScrapeActors{sceneActors: []uint{5}}
+	svc := NewSceneScrapeService(nil, nil, nil, nil, actorRepo, zap.NewNop())
+	svc.scrapers = &fakeSceneScraper{scene: scraped}
+	svc.scenes, svc.tags, svc.actors = scenes, tags, actors
+	return svc, scenes, tags, actors, actorRepo
+}
+
+func TestSceneScrapeService_ScrapeScene(t *testing.T) {
+	svc, scenes, tags, actors, actorRepo := newTestSceneScrapeService(t, &scraper.ScrapedScene{
+		Scraper:    "Example",
+		Title:      "Pool Party",
+		Date:       "2024-03-15",
+		Performers: []string{"Jane Doe", "Amy Poe"},
+		Tags:       []string{"Outdoor", "Summer"},
+		CoverImage: "https://cdn.example.com/poster.jpg",
+	})
+	actorRepo.EXPECT().GetByName("Jane Doe").Return(&data.Actor{ID: 5, Name: "Jane Doe"}, nil)
+	actorRepo.EXPECT().GetByName("Amy Poe").Return(nil, gorm.ErrRecordNotFound)
+
+	result, err := svc.ScrapeScene(context.Background(), 7, "https://example.com/scenes/1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(result.Applied, ","); got != "title,date,performers,tags,cover" {
+		t.Fatalf("unexpected applied fields %q", got)
+	}
+	// The studio the page lacks and the PornDB link are kept
+	if scenes.scene.Title != "Pool Party" || scenes.scene.Studio != "Old Studio" || scenes.scene.PornDBSceneID != "pdb-1" {
+		t.Fatalf("unexpected scene %+v", scenes.scene)
+	}
+	if scenes.scene.ReleaseDate == nil || scenes.scene.ReleaseDate.Format("2006-01-02") != "2024-03-15" {
+		t.Fatalf("expected the release date to be set, got %v", scenes.scene.ReleaseDate)
+	}
+	if len(actors.created) != 1 || actors.created[0] != "Amy Poe" || len(actors.sceneActors) != 2 {
+		t.Fatalf("expected Amy Poe to be created and added, got created=%v actors=%v", actors.created, actors.sceneActors)
+	}
+	if len(tags.sceneTags) != 2 || len(tags.tags) != 2 {
+		t.Fatalf("expected Summer to be created and added, got %v", tags.sceneTags)
+	}
+	if scenes.cover != "https://cdn.example.com/poster.jpg" || result.Scene == nil {
+		t.Fatalf("expected the cover to be downloaded, got %q", scenes.cover)
+	}
+}
+
+func TestSceneScrapeService_ScrapeScene_Fields(t *testing.T) {
+	svc, scenes, _, actors, _ := newTestSceneScrapeService(t, &scraper.ScrapedScene{
+		Title:      "Pool Party",
+		Performers: []string{"Jane Doe"},
+		CoverImage: "https://cdn.example.com/poster.jpg",
+	})
+	scenes.coverErr = errors.New("404 Not Found")
+
+	result, err := svc.ScrapeScene(context.Background(), 7, "https://example.com/scenes/1", []string{"Cover", "title"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(result.Applied, ",") != "title" || len(actors.sceneActors) != 1 {
+		t.Fatalf("expected only the title to be applied, got %v", result.Applied)
+	}
+	if len(result.Warnings) != 1 || !strings.HasPrefix(result.Warnings[0], "cover: ") {
+		t.Fatalf("expected a cover warning, got %v", result.Warnings)
+	}
+
+	if _, err := svc.ScrapeScene(context.Background(), 7, "https://example.com/scenes/1", []string{"rating"}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an unknown field to be rejected, got %v", err)
+	}
+}
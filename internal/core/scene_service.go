@@ -32,6 +32,11 @@ type SceneService struct {
 	jobHistoryRepo    data.JobHistoryRepository
 	dlqRepo           data.DLQRepository
 	appSettingsRepo   data.AppSettingsRepository
+	integrityRepo     data.SceneIntegrityRepository
+	duplicateService  *DuplicateService
+	deletionGuard     *DeletionGuard
+	rbac              *RBACService
+	audit             *AuditService
 }
 
 func NewSceneService(
@@ -44,6 +49,9 @@ func NewSceneService(
 	jobHistoryRepo data.JobHistoryRepository,
 	dlqRepo data.DLQRepository,
 	appSettingsRepo data.AppSettingsRepository,
+	integrityRepo data.SceneIntegrityRepository,
+	duplicateService *DuplicateService,
+	deletionGuard *DeletionGuard,
 ) *SceneService {
 	// Ensure scene directory exists
 	if err := os.MkdirAll(scenePath, 0755); err != nil {
@@ -69,6 +77,9 @@ func NewSceneService(
 		jobHistoryRepo:    jobHistoryRepo,
 		dlqRepo:           dlqRepo,
 		appSettingsRepo:   appSettingsRepo,
+		integrityRepo:     integrityRepo,
+		duplicateService:  duplicateService,
+		deletionGuard:     deletionGuard,
 	}
 }
 
@@ -78,6 +89,16 @@ func (s *SceneService) SetIndexer(indexer SceneIndexer) {
 	s.indexer = indexer
 }
 
+// SetAuditService records permanent scene deletions in the audit log.
+func (s *SceneService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// SetRBACService enables role content restrictions for scene access checks.
+func (s *SceneService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
+}
+
 var AllowedExtensions = map[string]bool{
 	".mp4":  true,
 	".mkv":  true,
@@ -104,9 +125,7 @@ func (s *SceneService) UploadScene(file *multipart.FileHeader, title string) (*d
 	}
 	defer src.Close()
 
-	// Generate unique filename
-	uniqueName := fmt.Sprintf("%s_%s", uuid.New().String(), file.Filename)
-	storedPath := filepath.Join(s.ScenePath, uniqueName)
+	storedPath := s.uploadedScenePath(file.Filename)
 
 	// Save file
 	dst, err := os.Create(storedPath)
@@ -119,15 +138,76 @@ func (s *SceneService) UploadScene(file *multipart.FileHeader, title string) (*d
 		return nil, err
 	}
 
+	return s.registerUploadedScene(storedPath, file.Filename, title, file.Size)
+}
+
+// UploadResult is the outcome of one file of a batch upload.
+type UploadResult struct {
+	Filename string      `json:"filename"`
+	Scene    *data.Scene `json:"scene,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// UploadScenes uploads several files, each titled after its filename. A
+// failing file does not stop the others; the results follow the input order.
+func (s *SceneService) UploadScenes(files []*multipart.FileHeader) []UploadResult {
+	results := make([]UploadResult, 0, len(files))
+	for _, file := range files {
+		result := UploadResult{Filename: file.Filename}
+		scene, err := s.UploadScene(file, "")
+		if err != nil {
+			s.logger.Warn("Failed to upload scene",
+				zap.String("filename", file.Filename),
+				zap.Error(err),
+			)
+			result.Error = err.Error()
+		} else {
+			result.Scene = scene
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// CreateUploadedScene moves a fully received upload into the scene directory
+// and creates its scene, as UploadScene does for a single request upload.
+func (s *SceneService) CreateUploadedScene(tempPath, filename, title string) (*data.Scene, error) {
+	if !s.ValidateExtension(filename) {
+		return nil, apperrors.ErrInvalidFileExtension
+	}
+
+	info, err := os.Stat(tempPath)
+	if err != nil {
+		return nil, err
+	}
+
+	storedPath := s.uploadedScenePath(filename)
+	if err := moveFile(tempPath, storedPath); err != nil {
+		return nil, err
+	}
+
+	return s.registerUploadedScene(storedPath, filename, title, info.Size())
+}
+
+// uploadedScenePath returns a unique path in the scene directory for an upload.
+func (s *SceneService) uploadedScenePath(filename string) string {
+	uniqueName := fmt.Sprintf("%s_%s", uuid.New().String(), filename)
+	return filepath.Join(s.ScenePath, uniqueName)
+}
+
+// registerUploadedScene creates the scene of an uploaded file saved at
+// storedPath, submits it for processing and indexes it. The file is removed
+// when the scene cannot be created.
+func (s *SceneService) registerUploadedScene(storedPath, filename, title string, size int64) (*data.Scene, error) {
 	if title == "" {
-		title = file.Filename
+		title = filename
 	}
 
 	scene := &data.Scene{
 		Title:            title,
-		OriginalFilename: file.Filename,
+		OriginalFilename: filename,
 		StoredPath:       storedPath,
-		Size:             file.Size,
+		Size:             size,
 		ProcessingStatus: "pending",
 		Tags:             pq.StringArray{},
 		Actors:           pq.StringArray{},
@@ -200,6 +280,59 @@ func (s *SceneService) GetScene(id uint) (*data.Scene, error) {
 	return scene, nil
 }
 
+// ContentRestriction returns the part of the library role is limited to, or nil
+// when the role can see everything.
+func (s *SceneService) ContentRestriction(role string) *data.ContentRestriction {
+	if s.rbac == nil {
+		return nil
+	}
+	return s.rbac.ContentRestriction(role)
+}
+
+// CheckSceneAccess returns a not found error when role may not see a scene, so
+// restricted users can't tell excluded scenes from missing ones.
+func (s *SceneService) CheckSceneAccess(id uint, role string) error {
+	if s.rbac == nil {
+		return nil
+	}
+	allowed, err := s.rbac.CanAccessScene(role, id)
+	if err != nil {
+		return apperrors.NewInternalError("failed to check scene access", err)
+	}
+	if !allowed {
+		return apperrors.ErrSceneNotFound(id)
+	}
+	return nil
+}
+
+// GetSceneForRole is GetScene for a user with the given role.
+func (s *SceneService) GetSceneForRole(id uint, role string) (*data.Scene, error) {
+	scene, err := s.GetScene(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckSceneAccess(id, role); err != nil {
+		return nil, err
+	}
+	return scene, nil
+}
+
+// GetIntegrityReport returns the most recent decode verification report for a
+// scene the role may see
+func (s *SceneService) GetIntegrityReport(sceneID uint, role string) (*data.SceneIntegrityReport, error) {
+	if _, err := s.GetSceneForRole(sceneID, role); err != nil {
+		return nil, err
+	}
+	report, err := s.integrityRepo.GetBySceneID(sceneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrIntegrityReportNotFound(sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to get integrity report", err)
+	}
+	return report, nil
+}
+
 func (s *SceneService) UpdateSceneDetails(id uint, title, description string, releaseDate *time.Time) (*data.Scene, error) {
 	if err := s.Repo.UpdateDetails(id, title, description, releaseDate); err != nil {
 		return nil, fmt.Errorf("failed to update scene details: %w", err)
@@ -243,6 +376,17 @@ func (s *SceneService) UpdateSceneMetadata(id uint, title, description, studio s
 		}
 	}
 
+	// Flag other scenes matched to the same PornDB scene as potential duplicates
+	if porndbSceneID != "" && s.duplicateService != nil {
+		if _, err := s.duplicateService.FlagPornDBMatch(id, porndbSceneID); err != nil {
+			s.logger.Warn("Failed to flag PornDB duplicate",
+				zap.Uint("scene_id", id),
+				zap.String("porndb_scene_id", porndbSceneID),
+				zap.Error(err),
+			)
+		}
+	}
+
 	return scene, nil
 }
 
@@ -288,6 +432,10 @@ func (s *SceneService) DeleteScene(id uint) error {
 		os.Remove(scene.VttPath)
 	}
 
+	if scene.TrickplayPath != "" {
+		os.Remove(scene.TrickplayPath)
+	}
+
 	return nil
 }
 
@@ -405,54 +553,53 @@ func (s *SceneService) SetThumbnailFromURL(sceneID uint, imageURL string) error
 		return apperrors.ErrSceneDimensionsNotAvailable
 	}
 
+	tmpPath, err := downloadThumbnailImage(imageURL)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	return s.processAndSaveThumbnail(sceneID, scene, tmpPath)
+}
+
+// downloadThumbnailImage downloads an image to a temp file and returns its
+// path. The caller removes it.
+func downloadThumbnailImage(imageURL string) (string, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Get(imageURL)
 	if err != nil {
-		return fmt.Errorf("failed to download image: %w", err)
+		return "", fmt.Errorf("failed to download image: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download image: HTTP %d", resp.StatusCode)
+		return "", fmt.Errorf("failed to download image: HTTP %d", resp.StatusCode)
 	}
 
 	tmpFile, err := os.CreateTemp("", "goonhub-thumb-url-*.jpg")
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
 	tmpPath := tmpFile.Name()
-	defer os.Remove(tmpPath)
 
 	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
 		tmpFile.Close()
-		return fmt.Errorf("failed to save downloaded image: %w", err)
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to save downloaded image: %w", err)
 	}
 	tmpFile.Close()
-
-	return s.processAndSaveThumbnail(sceneID, scene, tmpPath)
+	return tmpPath, nil
 }
 
 // processAndSaveThumbnail resizes an image file to sm/lg WebP thumbnails and updates the database.
 func (s *SceneService) processAndSaveThumbnail(sceneID uint, scene *data.Scene, srcPath string) error {
-	qualityConfig := s.ProcessingService.GetProcessingQualityConfig()
-
-	tileWidthSm, tileHeightSm := ffmpeg.CalculateTileDimensions(scene.Width, scene.Height, qualityConfig.MaxFrameDimensionSm)
-	tileWidthLg, tileHeightLg := ffmpeg.CalculateTileDimensions(scene.Width, scene.Height, qualityConfig.MaxFrameDimensionLg)
-
-	thumbnailDir := filepath.Join(s.MetadataPath, "thumbnails")
-	if err := os.MkdirAll(thumbnailDir, 0755); err != nil {
-		return fmt.Errorf("failed to create thumbnail directory: %w", err)
-	}
-
-	smPath := filepath.Join(thumbnailDir, fmt.Sprintf("%d_thumb_sm.webp", sceneID))
-	lgPath := filepath.Join(thumbnailDir, fmt.Sprintf("%d_thumb_lg.webp", sceneID))
-
-	if err := ffmpeg.ResizeImageToWebp(srcPath, smPath, tileWidthSm, tileHeightSm, qualityConfig.FrameQualitySm); err != nil {
-		return fmt.Errorf("failed to resize to small thumbnail: %w", err)
+	smPath, lgPath, err := s.thumbnailPaths(sceneID)
+	if err != nil {
+		return err
 	}
-
-	if err := ffmpeg.ResizeImageToWebp(srcPath, lgPath, tileWidthLg, tileHeightLg, qualityConfig.FrameQualityLg); err != nil {
-		return fmt.Errorf("failed to resize to large thumbnail: %w", err)
+	tileWidthSm, tileHeightSm, err := s.resizeThumbnail(scene, srcPath, smPath, lgPath)
+	if err != nil {
+		return err
 	}
 
 	if err := s.Repo.UpdateThumbnail(sceneID, smPath, tileWidthSm, tileHeightSm); err != nil {
@@ -472,9 +619,39 @@ func (s *SceneService) processAndSaveThumbnail(sceneID uint, scene *data.Scene,
 	return nil
 }
 
+// thumbnailPaths returns the paths of a scene's small and large thumbnails,
+// creating their directory.
+func (s *SceneService) thumbnailPaths(sceneID uint) (string, string, error) {
+	thumbnailDir := filepath.Join(s.MetadataPath, "thumbnails")
+	if err := os.MkdirAll(thumbnailDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create thumbnail directory: %w", err)
+	}
+	smPath := filepath.Join(thumbnailDir, fmt.Sprintf("%d_thumb_sm.webp", sceneID))
+	lgPath := filepath.Join(thumbnailDir, fmt.Sprintf("%d_thumb_lg.webp", sceneID))
+	return smPath, lgPath, nil
+}
+
+// resizeThumbnail resizes an image to the small and large WebP thumbnails of a
+// scene and returns the small one's size.
+func (s *SceneService) resizeThumbnail(scene *data.Scene, srcPath, smPath, lgPath string) (int, int, error) {
+	qualityConfig := s.ProcessingService.GetProcessingQualityConfig()
+
+	tileWidthSm, tileHeightSm := ffmpeg.CalculateTileDimensions(scene.Width, scene.Height, qualityConfig.MaxFrameDimensionSm)
+	tileWidthLg, tileHeightLg := ffmpeg.CalculateTileDimensions(scene.Width, scene.Height, qualityConfig.MaxFrameDimensionLg)
+
+	if err := ffmpeg.ResizeImageToWebp(srcPath, smPath, tileWidthSm, tileHeightSm, qualityConfig.FrameQualitySm); err != nil {
+		return 0, 0, fmt.Errorf("failed to resize to small thumbnail: %w", err)
+	}
+	if err := ffmpeg.ResizeImageToWebp(srcPath, lgPath, tileWidthLg, tileHeightLg, qualityConfig.FrameQualityLg); err != nil {
+		return 0, 0, fmt.Errorf("failed to resize to large thumbnail: %w", err)
+	}
+	return tileWidthSm, tileHeightSm, nil
+}
+
 // MoveSceneToTrash moves a scene to trash (soft delete with retention).
-// Returns the expiry date based on retention settings.
-func (s *SceneService) MoveSceneToTrash(id uint) (*time.Time, error) {
+// Returns the expiry date based on retention settings. Protected scenes are
+// refused unless force is set and allowed by the deletion protection config.
+func (s *SceneService) MoveSceneToTrash(id uint, force bool) (*time.Time, error) {
 	// Verify scene exists (and is not already trashed)
 	scene, err := s.Repo.GetByID(id)
 	if err != nil {
@@ -484,6 +661,10 @@ func (s *SceneService) MoveSceneToTrash(id uint) (*time.Time, error) {
 		return nil, apperrors.NewInternalError("failed to get scene", err)
 	}
 
+	if err := s.deletionGuard.Check([]uint{id}, force); err != nil {
+		return nil, err
+	}
+
 	// Cancel pending jobs for this scene
 	if s.jobHistoryRepo != nil {
 		cancelled, err := s.jobHistoryRepo.CancelPendingJobsForScene(id)
@@ -586,6 +767,23 @@ func (s *SceneService) RestoreSceneFromTrash(id uint) error {
 	return nil
 }
 
+// PermanentlyDeleteScene hard deletes a library scene after checking deletion
+// protection. Trash management uses HardDeleteScene directly.
+func (s *SceneService) PermanentlyDeleteScene(id uint, force bool) error {
+	if _, err := s.Repo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrSceneNotFound(id)
+		}
+		return apperrors.NewInternalError("failed to get scene", err)
+	}
+
+	if err := s.deletionGuard.Check([]uint{id}, force); err != nil {
+		return err
+	}
+
+	return s.HardDeleteScene(id)
+}
+
 // HardDeleteScene permanently deletes a scene and all associated files.
 func (s *SceneService) HardDeleteScene(id uint) error {
 	// Delete DLQ entries for this scene
@@ -617,6 +815,20 @@ func (s *SceneService) HardDeleteScene(id uint) error {
 	// Delete physical files (log warnings if missing, don't fail)
 	s.deleteSceneFiles(deletedScene)
 
+	// Whoever asked is recorded by the request's own audit entry; this one keeps
+	// what was lost, since the scene row is gone
+	s.audit.Record(data.AuditEntry{
+		Action:       AuditActionScenePurge,
+		ResourceType: "scene",
+		ResourceID:   strconv.FormatUint(uint64(id), 10),
+		Details: data.AuditDetails{
+			"title":             deletedScene.Title,
+			"original_filename": deletedScene.OriginalFilename,
+			"stored_path":       deletedScene.StoredPath,
+			"size":              deletedScene.Size,
+		},
+	})
+
 	// Remove from search index (in case it wasn't removed during trash)
 	if s.indexer != nil {
 		if err := s.indexer.DeleteSceneIndex(id); err != nil {
@@ -678,6 +890,11 @@ func (s *SceneService) deleteSceneFiles(scene *data.Scene) {
 	if scene.VttPath != "" {
 		os.Remove(scene.VttPath)
 	}
+
+	// Delete trickplay file
+	if scene.TrickplayPath != "" {
+		os.Remove(scene.TrickplayPath)
+	}
 }
 
 // ListTrashedScenes returns paginated list of trashed scenes.
@@ -0,0 +1,187 @@
+package scraper
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// genericScraper reads the schema.org JSON-LD and OpenGraph metadata most
+// video sites publish for link previews. It handles every URL no site
+// scraper matches.
+type genericScraper struct{}
+
+func (genericScraper) Name() string { return "generic" }
+
+func (genericScraper) Matches(*url.URL) bool { return true }
+
+func (genericScraper) Scrape(page *Page) (*ScrapedScene, error) {
+	scene := &ScrapedScene{}
+	for _, n := range jsonLDSelector.matchAll(page.Doc) {
+		if video := findJSONLDVideo(text(n)); video != nil {
+			fillEmpty(scene, video)
+		}
+	}
+	fillEmpty(scene, openGraphScene(page.Doc))
+	if scene.Title == "" {
+		if titles := titleSelector.matchAll(page.Doc); len(titles) > 0 {
+			scene.Title = text(titles[0])
+		}
+	}
+	return scene, nil
+}
+
+var (
+	jsonLDSelector = mustParseSelector(`script[type="application/ld+json"]`)
+	metaSelector   = mustParseSelector("meta")
+	titleSelector  = mustParseSelector("head title")
+)
+
+func mustParseSelector(s string) *selector {
+	sel, err := parseSelector(s)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
+
+// openGraphScene reads the og: and video: meta tags.
+func openGraphScene(doc *html.Node) *ScrapedScene {
+	scene := &ScrapedScene{}
+	for _, n := range metaSelector.matchAll(doc) {
+		property := attr(n, "property")
+		if property == "" {
+			property = attr(n, "name")
+		}
+		content := strings.TrimSpace(attr(n, "content"))
+		if content == "" {
+			continue
+		}
+		switch strings.ToLower(property) {
+		case "og:title":
+			scene.Title = content
+		case "og:description":
+			scene.Description = content
+		case "og:image", "og:image:url":
+			if scene.CoverImage == "" {
+				scene.CoverImage = content
+			}
+		case "og:site_name":
+			scene.Studio = content
+		case "video:release_date":
+			scene.Date = content
+		case "video:actor":
+			scene.Performers = append(scene.Performers, content)
+		case "video:tag":
+			scene.Tags = append(scene.Tags, content)
+		}
+	}
+	return scene
+}
+
+// jsonLDVideoTypes are the schema.org types a scene is published as
+var jsonLDVideoTypes = map[string]bool{"VideoObject": true, "Movie": true, "Episode": true, "TVEpisode": true}
+
+// findJSONLDVideo returns the first video object in a JSON-LD document,
+// looking inside arrays and @graph.
+func findJSONLDVideo(doc string) *ScrapedScene {
+	var value any
+	if err := json.Unmarshal([]byte(doc), &value); err != nil {
+		return nil
+	}
+	var find func(v any) *ScrapedScene
+	find = func(v any) *ScrapedScene {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				if scene := find(item); scene != nil {
+					return scene
+				}
+			}
+		case map[string]any:
+			for _, t := range jsonLDStrings(v["@type"]) {
+				if jsonLDVideoTypes[t] {
+					return jsonLDScene(v)
+				}
+			}
+			return find(v["@graph"])
+		}
+		return nil
+	}
+	return find(value)
+}
+
+func jsonLDScene(v map[string]any) *ScrapedScene {
+	scene := &ScrapedScene{
+		Title:       firstString(jsonLDStrings(v["name"])),
+		Description: firstString(jsonLDStrings(v["description"])),
+		CoverImage:  firstString(jsonLDStrings(v["thumbnailUrl"])),
+		Performers:  jsonLDNames(v["actor"]),
+	}
+	if scene.CoverImage == "" {
+		scene.CoverImage = firstString(jsonLDStrings(v["image"]))
+	}
+	for _, key := range []string{"uploadDate", "datePublished", "dateCreated"} {
+		if date := firstString(jsonLDStrings(v[key])); date != "" {
+			scene.Date = date
+			break
+		}
+	}
+	for _, key := range []string{"productionCompany", "publisher"} {
+		if studio := firstString(jsonLDNames(v[key])); studio != "" {
+			scene.Studio = studio
+			break
+		}
+	}
+	for _, keyword := range jsonLDStrings(v["keywords"]) {
+		scene.Tags = append(scene.Tags, strings.Split(keyword, ",")...)
+	}
+	return scene
+}
+
+// jsonLDStrings returns a JSON-LD value that may be a string, an object with
+// a url or a list of either as strings.
+func jsonLDStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case map[string]any:
+		return jsonLDStrings(v["url"])
+	case []any:
+		var values []string
+		for _, item := range v {
+			values = append(values, jsonLDStrings(item)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// jsonLDNames returns the names of a JSON-LD person or organization, or a
+// list of them.
+func jsonLDNames(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case map[string]any:
+		return jsonLDStrings(v["name"])
+	case []any:
+		var names []string
+		for _, item := range v {
+			names = append(names, jsonLDNames(item)...)
+		}
+		return names
+	}
+	return nil
+}
+
+func firstString(values []string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,281 @@
+// Package scraper fetches scene metadata from web pages. Site scrapers are
+// either defined in YAML files (see yaml_scraper.go) or written in Go and
+// registered with Registry.Register; a generic scraper reading OpenGraph and
+// JSON-LD metadata handles every other site and fills what a site scraper
+// leaves empty.
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"goonhub/internal/apperrors"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+)
+
+// maxPageSize caps how much of a page is read
+const maxPageSize = 5 << 20
+
+// ScrapedScene is the metadata a scraper found on a page. Empty fields were
+// not found.
+type ScrapedScene struct {
+	URL         string   `json:"url"`
+	Scraper     string   `json:"scraper"` // the scraper that matched the URL
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Date        string   `json:"date,omitempty"` // YYYY-MM-DD
+	Studio      string   `json:"studio,omitempty"`
+	Performers  []string `json:"performers,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	CoverImage  string   `json:"cover_image,omitempty"` // absolute URL
+}
+
+// Page is a fetched HTML page.
+type Page struct {
+	URL *url.URL
+	Doc *html.Node
+}
+
+// Scraper extracts scene metadata from the pages of the sites it matches.
+type Scraper interface {
+	Name() string
+	Matches(u *url.URL) bool
+	Scrape(page *Page) (*ScrapedScene, error)
+}
+
+// Info describes a registered scraper.
+type Info struct {
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"` // yaml, go or generic
+	Domains []string `json:"domains,omitempty"`
+}
+
+// Options configures a Registry.
+type Options struct {
+	Dir       string        // directory of YAML scraper definitions, "" = none
+	Timeout   time.Duration // page fetch timeout
+	UserAgent string
+}
+
+// Registry picks the scraper for a URL, fetches the page and runs it.
+type Registry struct {
+	scrapers  []Scraper
+	infos     []Info
+	generic   Scraper
+	client    *http.Client
+	userAgent string
+	logger    *zap.Logger
+}
+
+// NewRegistry creates a registry with the YAML scrapers in opts.Dir. A
+// definition that fails to load is logged and skipped.
+func NewRegistry(opts Options, logger *zap.Logger) *Registry {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	r := &Registry{
+		generic:   genericScraper{},
+		client:    &http.Client{Timeout: timeout},
+		userAgent: opts.UserAgent,
+		logger:    logger,
+	}
+	if opts.Dir != "" {
+		scrapers, err := LoadYAMLScrapers(opts.Dir, logger)
+		if err != nil {
+			logger.Warn("Failed to load YAML scrapers", zap.String("dir", opts.Dir), zap.Error(err))
+		}
+		for _, s := range scrapers {
+			r.register(s, Info{Name: s.Name(), Kind: "yaml", Domains: s.Domains})
+		}
+	}
+	return r
+}
+
+// Register adds a Go scraper. Scrapers are tried in registration order, YAML
+// scrapers first.
+func (r *Registry) Register(s Scraper) {
+	r.register(s, Info{Name: s.Name(), Kind: "go"})
+}
+
+func (r *Registry) register(s Scraper, info Info) {
+	r.scrapers = append(r.scrapers, s)
+	r.infos = append(r.infos, info)
+}
+
+// List returns the registered scrapers, the generic one last.
+func (r *Registry) List() []Info {
+	infos := append([]Info{}, r.infos...)
+	return append(infos, Info{Name: r.generic.Name(), Kind: "generic"})
+}
+
+// Scrape fetches rawURL and extracts its scene metadata with the first
+// scraper matching it. Fields the site scraper leaves empty are filled from
+// the page's OpenGraph and JSON-LD metadata.
+func (r *Registry) Scrape(ctx context.Context, rawURL string) (*ScrapedScene, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, apperrors.NewValidationErrorWithField("url", "url must be an absolute http or https URL")
+	}
+
+	scraper := r.generic
+	for _, s := range r.scrapers {
+		if s.Matches(u) {
+			scraper = s
+			break
+		}
+	}
+
+	page, err := r.fetch(ctx, u)
+	if err != nil {
+		return nil, apperrors.NewValidationErrorWithField("url", err.Error())
+	}
+
+	scene, err := scraper.Scrape(page)
+	if err != nil {
+		return nil, apperrors.NewValidationErrorWithField("url", fmt.Sprintf("scraper %s failed: %v", scraper.Name(), err))
+	}
+	if scraper != r.generic {
+		if fallback, err := r.generic.Scrape(page); err == nil {
+			fillEmpty(scene, fallback)
+		}
+	}
+	scene.URL = u.String()
+	scene.Scraper = scraper.Name()
+	normalize(scene, u)
+	return scene, nil
+}
+
+func (r *Registry) fetch(ctx context.Context, u *url.URL) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if r.userAgent != "" {
+		req.Header.Set("User-Agent", r.userAgent)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch page: HTTP %d", resp.StatusCode)
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+	// Relative links resolve against the URL the redirects ended at
+	return &Page{URL: resp.Request.URL, Doc: doc}, nil
+}
+
+// fillEmpty copies the fields of from into the empty fields of scene.
+func fillEmpty(scene, from *ScrapedScene) {
+	if scene.Title == "" {
+		scene.Title = from.Title
+	}
+	if scene.Description == "" {
+		scene.Description = from.Description
+	}
+	if scene.Date == "" {
+		scene.Date = from.Date
+	}
+	if scene.Studio == "" {
+		scene.Studio = from.Studio
+	}
+	if len(scene.Performers) == 0 {
+		scene.Performers = from.Performers
+	}
+	if len(scene.Tags) == 0 {
+		scene.Tags = from.Tags
+	}
+	if scene.CoverImage == "" {
+		scene.CoverImage = from.CoverImage
+	}
+}
+
+// normalize collapses whitespace, drops duplicate names, turns the date into
+// YYYY-MM-DD (or drops it) and makes the cover URL absolute.
+func normalize(scene *ScrapedScene, base *url.URL) {
+	scene.Title = collapseSpace(scene.Title)
+	scene.Description = strings.TrimSpace(scene.Description)
+	scene.Studio = collapseSpace(scene.Studio)
+	scene.Date = normalizeDate(scene.Date, "")
+	scene.Performers = uniqueNames(scene.Performers)
+	scene.Tags = uniqueNames(scene.Tags)
+	if scene.CoverImage != "" {
+		if ref, err := url.Parse(strings.TrimSpace(scene.CoverImage)); err == nil {
+			scene.CoverImage = base.ResolveReference(ref).String()
+		} else {
+			scene.CoverImage = ""
+		}
+	}
+}
+
+// dateLayouts are the date formats tried when a scraper names none
+var dateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006/01/02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"January 2 2006",
+}
+
+// normalizeDate parses value with layout, or the common layouts when layout
+// is empty, and returns it as YYYY-MM-DD, or "" when it doesn't parse.
+func normalizeDate(value, layout string) string {
+	value = collapseSpace(value)
+	if value == "" {
+		return ""
+	}
+	layouts := dateLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+	for _, l := range layouts {
+		if t, err := time.Parse(l, value); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	// Date-times in other formats often start with the date
+	if len(value) > 10 && layout == "" {
+		if t, err := time.Parse("2006-01-02", value[:10]); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var unique []string
+	for _, name := range names {
+		name = collapseSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, name)
+	}
+	return unique
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
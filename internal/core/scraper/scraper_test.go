@@ -0,0 +1,193 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goonhub/internal/apperrors"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+)
+
+const scenePage = `<html>
+<head>
+	<title>Pool Party - Example</title>
+	<meta property="og:title" content="Pool Party (OG)">
+	<meta property="og:image" content="/covers/pool-party.jpg">
+	<meta property="og:description" content="A summer afternoon.">
+	<script type="application/ld+json">{"@context":"https://schema.org","@graph":[
+		{"@type":"WebPage","name":"Example"},
+		{"@type":"VideoObject","name":"Pool Party","uploadDate":"2024-03-15T10:00:00Z",
+		 "actor":[{"@type":"Person","name":"Jane Doe"},{"@type":"Person","name":"John Roe"}],
+		 "keywords":"outdoor, pool","publisher":{"@type":"Organization","name":"Example Studio"}}
+	]}</script>
+</head>
+<body>
+	<h1 class="scene-title main">  Pool   Party </h1>
+	<span class="release">March 15, 2024</span>
+	<div class="models"><a href="/m/1">Jane Doe</a> <a href="/m/2">jane doe</a> <a href="/m/3">Amy Poe</a></div>
+	<ul class="tags"><li><a>Outdoor</a></li><li><a>Summer</a></li></ul>
+	<video poster="https://cdn.example.com/poster.jpg"></video>
+</body>
+</html>`
+
+func parsePage(t *testing.T, body string) *Page {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse("https://www.example.com/scenes/pool-party")
+	return &Page{URL: u, Doc: doc}
+}
+
+func TestSelector(t *testing.T) {
+	page := parsePage(t, scenePage)
+	cases := map[string][]string{
+		"h1.scene-title":                 {"Pool Party"},
+		".models > a":                    {"Jane Doe", "jane doe", "Amy Poe"},
+		"ul.tags li a":                   {"Outdoor", "Summer"},
+		"body > a":                       nil,
+		`meta[property="og:title"]`:      {""},
+		"span.release, h1.main":          {"Pool Party", "March 15, 2024"},
+		`script[type*="ld+json"] > span`: nil,
+	}
+	for s, want := range cases {
+		sel, err := parseSelector(s)
+		if err != nil {
+			t.Fatalf("parseSelector(%q): %v", s, err)
+		}
+		var got []string
+		for _, n := range sel.matchAll(page.Doc) {
+			got = append(got, text(n))
+		}
+		if strings.Join(got, "|") != strings.Join(want, "|") || len(got) != len(want) {
+			t.Errorf("%q matched %q, want %q", s, got, want)
+		}
+	}
+
+	for _, invalid := range []string{"a >", "> a", "a[href", "a.", ""} {
+		if _, err := parseSelector(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestYAMLScraper(t *testing.T) {
+	s, err := ParseYAMLScraper([]byte(`
+name: Example
+domains: [www.example.com]
+scene:
+  title: {selector: "h1.scene-title"}
+  date: {selector: "span.release", layout: "January 2, 2006"}
+  studio: {value: "Example Studio"}
+  performers: {selector: ".models a"}
+  tags: {selector: ".tags a"}
+  cover: {selector: "video", attribute: "poster"}
+  description: {selector: "h1", regex: "Pool (\\w+)"}
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for host, want := range map[string]bool{"example.com": true, "cdn.example.com": true, "notexample.com": false} {
+		if got := s.Matches(&url.URL{Scheme: "https", Host: host}); got != want {
+			t.Errorf("Matches(%s) = %v, want %v", host, got, want)
+		}
+	}
+
+	scene, err := s.Scrape(parsePage(t, scenePage))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scene.Title != "Pool Party" || scene.Date != "2024-03-15" || scene.Studio != "Example Studio" || scene.Description != "Party" {
+		t.Fatalf("unexpected scene %+v", scene)
+	}
+	if len(scene.Performers) != 3 || len(scene.Tags) != 2 || scene.CoverImage != "https://cdn.example.com/poster.jpg" {
+		t.Fatalf("unexpected lists %+v", scene)
+	}
+
+	for _, invalid := range []string{
+		"domains: [example.com]",
+		"name: x",
+		"name: x\ndomains: [a.com]\nscene:\n  title: {attribute: href}",
+		"name: x\ndomains: [a.com]\nscene:\n  title: {selector: \"a[\"}",
+	} {
+		if _, err := ParseYAMLScraper([]byte(invalid)); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestGenericScraper(t *testing.T) {
+	scene, _ := genericScraper{}.Scrape(parsePage(t, scenePage))
+	// JSON-LD wins over OpenGraph, which fills the rest
+	if scene.Title != "Pool Party" || scene.Studio != "Example Studio" || scene.Description != "A summer afternoon." {
+		t.Fatalf("unexpected scene %+v", scene)
+	}
+	if strings.Join(scene.Performers, ",") != "Jane Doe,John Roe" || len(scene.Tags) != 2 || scene.CoverImage != "/covers/pool-party.jpg" {
+		t.Fatalf("unexpected scene %+v", scene)
+	}
+
+	scene, _ = genericScraper{}.Scrape(parsePage(t, `<html><head><title>Just a title</title></head></html>`))
+	if scene.Title != "Just a title" {
+		t.Fatalf("expected the page title, got %+v", scene)
+	}
+}
+
+func TestRegistry_Scrape(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(scenePage))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	host, _ := url.Parse(srv.URL)
+	os.WriteFile(filepath.Join(dir, "local.yaml"), []byte(`
+name: Local
+domains: [`+host.Hostname()+`]
+scene:
+  title: {selector: "h1.scene-title"}
+  performers: {selector: ".models a"}
+`), 0o644)
+	os.WriteFile(filepath.Join(dir, "broken.yml"), []byte("name: [unclosed"), 0o644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a scraper"), 0o644)
+
+	r := NewRegistry(Options{Dir: dir}, zap.NewNop())
+	if infos := r.List(); len(infos) != 2 || infos[0].Name != "Local" || infos[1].Kind != "generic" {
+		t.Fatalf("expected the YAML scraper and the generic one, got %+v", infos)
+	}
+
+	scene, err := r.Scrape(context.Background(), srv.URL+"/scenes/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scene.Scraper != "Local" || scene.Title != "Pool Party" {
+		t.Fatalf("expected the site scraper's title, got %+v", scene)
+	}
+	// Duplicate names are dropped, and what the site scraper lacks comes from the page's metadata
+	if strings.Join(scene.Performers, ",") != "Jane Doe,Amy Poe" || scene.Date != "2024-03-15" || scene.Studio != "Example Studio" {
+		t.Fatalf("unexpected scene %+v", scene)
+	}
+	if scene.CoverImage != srv.URL+"/covers/pool-party.jpg" {
+		t.Fatalf("expected an absolute cover URL, got %q", scene.CoverImage)
+	}
+
+	if _, err := r.Scrape(context.Background(), "ftp://example.com/x"); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a non-HTTP URL to be rejected, got %v", err)
+	}
+	if _, err := r.Scrape(context.Background(), srv.URL+"/missing"); !apperrors.IsValidation(err) || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected the HTTP status in the error, got %v", err)
+	}
+}
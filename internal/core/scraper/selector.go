@@ -0,0 +1,255 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// selector is a parsed CSS selector. Scraper definitions only need a subset:
+// compound selectors of a tag, #id, .class and [attr], [attr=value],
+// [attr*=value] parts, joined by descendant (space) or child (>) combinators,
+// with comma-separated alternatives.
+type selector struct {
+	alternatives [][]selectorStep
+}
+
+// selectorStep is one compound selector and how it relates to the step
+// before it.
+type selectorStep struct {
+	child   bool // > rather than a descendant
+	tag     string
+	id      string
+	classes []string
+	attrs   []attrMatcher
+}
+
+type attrMatcher struct {
+	name  string
+	op    string // "", "=" or "*="
+	value string
+}
+
+func parseSelector(s string) (*selector, error) {
+	sel := &selector{}
+	for _, alternative := range strings.Split(s, ",") {
+		steps, err := parseSteps(alternative)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", s, err)
+		}
+		if len(steps) == 0 {
+			return nil, fmt.Errorf("invalid selector %q: empty alternative", s)
+		}
+		sel.alternatives = append(sel.alternatives, steps)
+	}
+	return sel, nil
+}
+
+func parseSteps(s string) ([]selectorStep, error) {
+	var steps []selectorStep
+	child := false
+	s = strings.TrimSpace(s)
+	for len(s) > 0 {
+		if s[0] == '>' {
+			if child || len(steps) == 0 {
+				return nil, fmt.Errorf("misplaced >")
+			}
+			child = true
+			s = strings.TrimSpace(s[1:])
+			continue
+		}
+
+		step := selectorStep{child: child}
+		child = false
+		end := 0
+		for end < len(s) && s[end] != ' ' && s[end] != '>' {
+			if s[end] == '[' {
+				closing := strings.IndexByte(s[end:], ']')
+				if closing < 0 {
+					return nil, fmt.Errorf("unclosed [")
+				}
+				end += closing
+			}
+			end++
+		}
+		if err := step.parse(s[:end]); err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+		s = strings.TrimSpace(s[end:])
+	}
+	if child {
+		return nil, fmt.Errorf("trailing >")
+	}
+	return steps, nil
+}
+
+// parse reads a compound selector like a.title[href]
+func (st *selectorStep) parse(s string) error {
+	i := 0
+	readName := func() string {
+		start := i
+		for i < len(s) && s[i] != '.' && s[i] != '#' && s[i] != '[' {
+			i++
+		}
+		return s[start:i]
+	}
+
+	st.tag = strings.ToLower(readName())
+	if st.tag == "*" {
+		st.tag = ""
+	}
+	for i < len(s) {
+		switch s[i] {
+		case '.':
+			i++
+			class := readName()
+			if class == "" {
+				return fmt.Errorf("empty class in %q", s)
+			}
+			st.classes = append(st.classes, class)
+		case '#':
+			i++
+			if st.id = readName(); st.id == "" {
+				return fmt.Errorf("empty id in %q", s)
+			}
+		case '[':
+			closing := strings.IndexByte(s[i:], ']')
+			attr, err := parseAttrMatcher(s[i+1 : i+closing])
+			if err != nil {
+				return err
+			}
+			st.attrs = append(st.attrs, attr)
+			i += closing + 1
+		}
+	}
+	return nil
+}
+
+func parseAttrMatcher(s string) (attrMatcher, error) {
+	for _, op := range []string{"*=", "="} {
+		if name, value, ok := strings.Cut(s, op); ok {
+			value = strings.Trim(strings.TrimSpace(value), `"'`)
+			return attrMatcher{name: strings.ToLower(strings.TrimSpace(name)), op: op, value: value}, nil
+		}
+	}
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "" {
+		return attrMatcher{}, fmt.Errorf("empty attribute selector")
+	}
+	return attrMatcher{name: name}, nil
+}
+
+func (st *selectorStep) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode || (st.tag != "" && n.Data != st.tag) {
+		return false
+	}
+	if st.id != "" && attr(n, "id") != st.id {
+		return false
+	}
+	if len(st.classes) > 0 {
+		classes := strings.Fields(attr(n, "class"))
+		for _, want := range st.classes {
+			found := false
+			for _, class := range classes {
+				if class == want {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	for _, m := range st.attrs {
+		value, ok := attrOK(n, m.name)
+		if !ok {
+			return false
+		}
+		switch {
+		case m.op == "=" && value != m.value:
+			return false
+		case m.op == "*=" && !strings.Contains(value, m.value):
+			return false
+		}
+	}
+	return true
+}
+
+// matchAll returns the nodes under root matching the selector, in document
+// order.
+func (sel *selector) matchAll(root *html.Node) []*html.Node {
+	var matches []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for _, steps := range sel.alternatives {
+			if matchesSteps(n, steps) {
+				matches = append(matches, n)
+				break
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return matches
+}
+
+// matchesSteps reports whether n matches the last step and its ancestors
+// match the steps before it.
+func matchesSteps(n *html.Node, steps []selectorStep) bool {
+	last := steps[len(steps)-1]
+	if !last.matches(n) {
+		return false
+	}
+	if len(steps) == 1 {
+		return true
+	}
+	rest := steps[:len(steps)-1]
+	if last.child {
+		return n.Parent != nil && matchesSteps(n.Parent, rest)
+	}
+	for p := n.Parent; p != nil; p = p.Parent {
+		if matchesSteps(p, rest) {
+			return true
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, name string) string {
+	value, _ := attrOK(n, name)
+	return value
+}
+
+func attrOK(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// text returns the text content of n, skipping scripts and styles inside it.
+func text(root *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+		}
+		if n != root && n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style") {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return collapseSpace(b.String())
+}
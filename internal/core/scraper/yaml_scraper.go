@@ -0,0 +1,212 @@
+package scraper
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/net/html"
+)
+
+// YAMLDefinition is a site scraper written in YAML, one per file:
+//
+//	name: Example
+//	domains: [example.com]
+//	scene:
+//	  title:      {selector: "h1.title"}
+//	  date:       {selector: ".release-date", layout: "January 2, 2006"}
+//	  studio:     {value: "Example"}
+//	  performers: {selector: ".models a"}
+//	  tags:       {selector: ".tags a"}
+//	  cover:      {selector: "video", attribute: "poster"}
+type YAMLDefinition struct {
+	Name    string   `yaml:"name"`
+	Domains []string `yaml:"domains"` // hosts the scraper handles, subdomains included
+	Scene   struct {
+		Title       *YAMLField `yaml:"title"`
+		Description *YAMLField `yaml:"description"`
+		Date        *YAMLField `yaml:"date"`
+		Studio      *YAMLField `yaml:"studio"`
+		Performers  *YAMLField `yaml:"performers"`
+		Tags        *YAMLField `yaml:"tags"`
+		Cover       *YAMLField `yaml:"cover"`
+	} `yaml:"scene"`
+}
+
+// YAMLField says where a field is on the page. The text of the elements
+// matching Selector is used, or their Attribute; Regex keeps its first
+// capture group. Value is a fixed value used instead of the page.
+type YAMLField struct {
+	Selector  string `yaml:"selector"`
+	Attribute string `yaml:"attribute"`
+	Regex     string `yaml:"regex"`
+	Layout    string `yaml:"layout"` // Go time layout of a date
+	Value     string `yaml:"value"`
+
+	selector *selector
+	regex    *regexp.Regexp
+}
+
+// YAMLScraper is a scraper built from a YAMLDefinition.
+type YAMLScraper struct {
+	YAMLDefinition
+}
+
+// LoadYAMLScrapers loads the *.yaml and *.yml scraper definitions in dir, in
+// file name order. A missing directory has no scrapers; a definition that
+// fails to parse is logged and skipped.
+func LoadYAMLScrapers(dir string, logger *zap.Logger) ([]*YAMLScraper, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var scrapers []*YAMLScraper
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			logger.Warn("Failed to read scraper definition", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		s, err := ParseYAMLScraper(content)
+		if err != nil {
+			logger.Warn("Skipping invalid scraper definition", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		scrapers = append(scrapers, s)
+	}
+	return scrapers, nil
+}
+
+// ParseYAMLScraper parses and validates a scraper definition.
+func ParseYAMLScraper(content []byte) (*YAMLScraper, error) {
+	var def YAMLDefinition
+	if err := yaml.Unmarshal(content, &def); err != nil {
+		return nil, err
+	}
+	if def.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if len(def.Domains) == 0 {
+		return nil, fmt.Errorf("at least one domain is required")
+	}
+	for i, domain := range def.Domains {
+		def.Domains[i] = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "www."))
+	}
+
+	fields := map[string]*YAMLField{
+		"title": def.Scene.Title, "description": def.Scene.Description, "date": def.Scene.Date, "studio": def.Scene.Studio,
+		"performers": def.Scene.Performers, "tags": def.Scene.Tags, "cover": def.Scene.Cover,
+	}
+	for name, field := range fields {
+		if field == nil {
+			continue
+		}
+		if field.Selector == "" && field.Value == "" {
+			return nil, fmt.Errorf("%s: selector or value is required", name)
+		}
+		if field.Selector != "" {
+			sel, err := parseSelector(field.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			field.selector = sel
+		}
+		if field.Regex != "" {
+			re, err := regexp.Compile(field.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid regex: %w", name, err)
+			}
+			field.regex = re
+		}
+	}
+	return &YAMLScraper{YAMLDefinition: def}, nil
+}
+
+func (s *YAMLScraper) Name() string {
+	return s.YAMLDefinition.Name
+}
+
+// Matches reports whether the URL's host is one of the domains or a
+// subdomain of one.
+func (s *YAMLScraper) Matches(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range s.Domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *YAMLScraper) Scrape(page *Page) (*ScrapedScene, error) {
+	scene := &ScrapedScene{
+		Title:       s.Scene.Title.first(page.Doc),
+		Description: s.Scene.Description.first(page.Doc),
+		Studio:      s.Scene.Studio.first(page.Doc),
+		Performers:  s.Scene.Performers.all(page.Doc),
+		Tags:        s.Scene.Tags.all(page.Doc),
+		CoverImage:  s.Scene.Cover.first(page.Doc),
+	}
+	if s.Scene.Date != nil {
+		scene.Date = normalizeDate(s.Scene.Date.first(page.Doc), s.Scene.Date.Layout)
+	}
+	return scene, nil
+}
+
+// first returns the field's first non-empty value.
+func (f *YAMLField) first(doc *html.Node) string {
+	values := f.all(doc)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// all returns every non-empty value of the field.
+func (f *YAMLField) all(doc *html.Node) []string {
+	if f == nil {
+		return nil
+	}
+	if f.Value != "" {
+		return []string{f.Value}
+	}
+
+	var values []string
+	for _, n := range f.selector.matchAll(doc) {
+		value := text(n)
+		if f.Attribute != "" {
+			value = strings.TrimSpace(attr(n, f.Attribute))
+		}
+		if f.regex != nil {
+			m := f.regex.FindStringSubmatch(value)
+			switch {
+			case m == nil:
+				value = ""
+			case len(m) > 1:
+				value = m[1]
+			default:
+				value = m[0]
+			}
+		}
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
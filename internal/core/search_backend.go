@@ -0,0 +1,116 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"goonhub/internal/data"
+	"goonhub/internal/infrastructure/meilisearch"
+)
+
+// Search backends, selected with search.backend. Auto uses Meilisearch and
+// falls back to PostgreSQL while it is unconfigured or unreachable.
+const (
+	SearchBackendAuto        = "auto"
+	SearchBackendMeilisearch = "meilisearch"
+	SearchBackendPostgres    = "postgres"
+)
+
+// SearchBackend runs the text query, filters and sort of a scene search and
+// returns matching scene IDs. User-specific filters are resolved by
+// SearchService beforehand and passed in as SceneIDs.
+type SearchBackend interface {
+	Name() string
+	Search(params meilisearch.SearchParams) (*meilisearch.SearchResult, error)
+}
+
+// meilisearchBackend searches the Meilisearch index.
+type meilisearchBackend struct {
+	client *meilisearch.Client
+}
+
+func (b *meilisearchBackend) Name() string {
+	return SearchBackendMeilisearch
+}
+
+func (b *meilisearchBackend) Search(params meilisearch.SearchParams) (*meilisearch.SearchResult, error) {
+	return b.client.Search(params)
+}
+
+// PostgresSearchBackend searches scenes with PostgreSQL full-text search, so
+// search keeps working without Meilisearch. It reads the scenes table directly
+// and needs no index maintenance. Matching is stricter than Meilisearch: every
+// query word must match the start of a word, and typos are not tolerated.
+type PostgresSearchBackend struct {
+	repo data.SceneTextSearchRepository
+}
+
+func NewPostgresSearchBackend(repo data.SceneTextSearchRepository) *PostgresSearchBackend {
+	return &PostgresSearchBackend{repo: repo}
+}
+
+func (b *PostgresSearchBackend) Name() string {
+	return SearchBackendPostgres
+}
+
+func (b *PostgresSearchBackend) Search(params meilisearch.SearchParams) (*meilisearch.SearchResult, error) {
+	if params.Keyset {
+		ids, next, err := b.repo.SearchAfter(toSceneTextQuery(params))
+		if err != nil {
+			return nil, fmt.Errorf("postgres search failed: %w", err)
+		}
+		result := &meilisearch.SearchResult{IDs: ids}
+		if next != nil {
+			result.Next = &meilisearch.SortKey{Value: next.Value, ID: next.ID}
+		}
+		return result, nil
+	}
+
+	ids, total, err := b.repo.Search(toSceneTextQuery(params))
+	if err != nil {
+		return nil, fmt.Errorf("postgres search failed: %w", err)
+	}
+	return &meilisearch.SearchResult{IDs: ids, TotalCount: total}, nil
+}
+
+func toSceneTextQuery(params meilisearch.SearchParams) data.SceneTextQuery {
+	q := data.SceneTextQuery{
+		Query:            params.Query,
+		TagIDs:           params.TagIDs,
+		ExcludeTagIDs:    params.ExcludeTagIDs,
+		Actors:           params.Actors,
+		Studio:           params.Studio,
+		SubStudios:       params.SubStudios,
+		ProcessingStatus: params.ProcessingStatus,
+		SceneIDs:         params.SceneIDs,
+		Sort:             params.Sort,
+		SortDesc:         params.SortDir != "asc",
+		Offset:           params.Offset,
+		Limit:            params.Limit,
+		AllIDs:           params.FetchAllIDs,
+	}
+	if params.After != nil {
+		q.After = &data.SceneSortKey{Value: params.After.Value, ID: params.After.ID}
+	}
+	if params.MinDuration != nil {
+		q.MinDuration = int(*params.MinDuration)
+	}
+	if params.MaxDuration != nil {
+		q.MaxDuration = int(*params.MaxDuration)
+	}
+	if params.MinHeight != nil {
+		q.MinHeight = *params.MinHeight
+	}
+	if params.MaxHeight != nil {
+		q.MaxHeight = *params.MaxHeight
+	}
+	if params.DateAfter != nil {
+		t := time.Unix(*params.DateAfter, 0)
+		q.CreatedAfter = &t
+	}
+	if params.DateBefore != nil {
+		t := time.Unix(*params.DateBefore, 0)
+		q.CreatedBefore = &t
+	}
+	return q
+}
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/infrastructure/meilisearch"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type fakeSearchBackend struct {
+	err   error
+	ids   []uint
+	calls int
+}
+
+func (f *fakeSearchBackend) Name() string {
+	return SearchBackendMeilisearch
+}
+
+func (f *fakeSearchBackend) Search(params meilisearch.SearchParams) (*meilisearch.SearchResult, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &meilisearch.SearchResult{IDs: f.ids, TotalCount: int64(len(f.ids))}, nil
+}
+
+func TestToSceneTextQuery(t *testing.T) {
+	minDur, maxDur := 60.0, 600.0
+	minHeight := 720
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	q := toSceneTextQuery(meilisearch.SearchParams{
+		Query:       "jane",
+		TagIDs:      []uint{3},
+		MinDuration: &minDur,
+		MaxDuration: &maxDur,
+		MinHeight:   &minHeight,
+		DateAfter:   &after,
+		Sort:        "title",
+		SortDir:     "asc",
+		Offset:      20,
+		Limit:       10,
+	})
+
+	if q.Query != "jane" || len(q.TagIDs) != 1 || q.MinDuration != 60 || q.MaxDuration != 600 || q.MinHeight != 720 {
+		t.Fatalf("unexpected query %+v", q)
+	}
+	if q.CreatedAfter == nil || q.CreatedAfter.Unix() != after || q.CreatedBefore != nil {
+		t.Fatalf("unexpected date range %v - %v", q.CreatedAfter, q.CreatedBefore)
+	}
+	if q.Sort != "title" || q.SortDesc || q.Offset != 20 || q.Limit != 10 {
+		t.Fatalf("unexpected sort or paging %+v", q)
+	}
+
+	if !toSceneTextQuery(meilisearch.SearchParams{Sort: "created_at"}).SortDesc {
+		t.Fatal("expected sorts without a direction to be descending")
+	}
+}
+
+func newTestBackendSearchService(t *testing.T, backend string) (*SearchService, *mocks.MockSceneTextSearchRepository, *mocks.MockSceneRepository) {
+	ctrl := gomock.NewController(t)
+	textRepo := mocks.NewMockSceneTextSearchRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewSearchService(nil, textRepo, backend, sceneRepo, nil, nil, nil, nil, zap.NewNop())
+	return svc, textRepo, sceneRepo
+}
+
+func TestSearchService_PostgresBackend(t *testing.T) {
+	svc, textRepo, sceneRepo := newTestBackendSearchService(t, SearchBackendPostgres)
+	if svc.ActiveBackend() != SearchBackendPostgres {
+		t.Fatalf("expected postgres backend, got %q", svc.ActiveBackend())
+	}
+
+	textRepo.EXPECT().Search(gomock.Any()).DoAndReturn(func(q data.SceneTextQuery) ([]uint, int64, error) {
+		if q.Query != "jane" || q.Offset != 20 || q.Limit != 20 {
+			t.Fatalf("unexpected query %+v", q)
+		}
+		return []uint{4, 2}, 42, nil
+	})
+	sceneRepo.EXPECT().GetByIDs([]uint{4, 2}).Return([]data.Scene{{ID: 4}, {ID: 2}}, nil)
+
+	result, err := svc.Search(data.SceneSearchParams{Page: 2, Limit: 20, Query: "jane"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Total != 42 || len(result.Scenes) != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestSearchService_AutoFallsBackWhenMeilisearchFails(t *testing.T) {
+	svc, textRepo, sceneRepo := newTestBackendSearchService(t, SearchBackendAuto)
+	meili := &fakeSearchBackend{err: errors.New("connection refused")}
+	svc.primary = meili
+
+	textRepo.EXPECT().Search(gomock.Any()).Return([]uint{1}, int64(1), nil).Times(2)
+	sceneRepo.EXPECT().GetByIDs([]uint{1}).Return([]data.Scene{{ID: 1}}, nil).Times(2)
+
+	for i := 0; i < 2; i++ {
+		result, err := svc.Search(data.SceneSearchParams{Page: 1, Limit: 20})
+		if err != nil {
+			t.Fatalf("expected fallback to succeed, got %v", err)
+		}
+		if result.Total != 1 {
+			t.Fatalf("unexpected result %+v", result)
+		}
+	}
+	if meili.calls != 1 {
+		t.Fatalf("expected meilisearch to be skipped after a failure, called %d times", meili.calls)
+	}
+	if svc.ActiveBackend() != SearchBackendPostgres {
+		t.Fatalf("expected postgres while meilisearch is down, got %q", svc.ActiveBackend())
+	}
+
+	// Once the retry interval passed, meilisearch is tried again
+	svc.meiliDownUntil = time.Now().Add(-time.Second)
+	meili.err = nil
+	meili.ids = []uint{7}
+	sceneRepo.EXPECT().GetByIDs([]uint{7}).Return([]data.Scene{{ID: 7}}, nil)
+	if _, err := svc.Search(data.SceneSearchParams{Page: 1, Limit: 20}); err != nil {
+		t.Fatalf("expected meilisearch search to succeed, got %v", err)
+	}
+	if meili.calls != 2 || svc.ActiveBackend() != SearchBackendMeilisearch {
+		t.Fatalf("expected meilisearch to be used again, calls %d, backend %q", meili.calls, svc.ActiveBackend())
+	}
+}
+
+func TestSearchService_MeilisearchBackendDoesNotFallBack(t *testing.T) {
+	svc, _, _ := newTestBackendSearchService(t, SearchBackendMeilisearch)
+	svc.primary = &fakeSearchBackend{err: errors.New("connection refused")}
+
+	if _, err := svc.Search(data.SceneSearchParams{Page: 1, Limit: 20}); err == nil {
+		t.Fatal("expected the meilisearch error without a fallback")
+	}
+}
+
+func TestSearchService_CursorPaging(t *testing.T) {
+	svc, textRepo, sceneRepo := newTestBackendSearchService(t, SearchBackendPostgres)
+
+	textRepo.EXPECT().SearchAfter(gomock.Any()).DoAndReturn(func(q data.SceneTextQuery) ([]uint, *data.SceneSortKey, error) {
+		if q.After != nil || q.Limit != 2 {
+			t.Fatalf("unexpected first page query %+v", q)
+		}
+		return []uint{9, 8}, &data.SceneSortKey{Value: 120, ID: 8}, nil
+	})
+	sceneRepo.EXPECT().GetByIDs([]uint{9, 8}).Return([]data.Scene{{ID: 9}, {ID: 8}}, nil)
+
+	params := data.SceneSearchParams{Limit: 2, Sort: "duration_desc", UseCursor: true}
+	result, err := svc.Search(params)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.NextCursor == "" || len(result.Scenes) != 2 {
+		t.Fatalf("expected a page with a next cursor, got %+v", result)
+	}
+
+	textRepo.EXPECT().SearchAfter(gomock.Any()).DoAndReturn(func(q data.SceneTextQuery) ([]uint, *data.SceneSortKey, error) {
+		if q.After == nil || q.After.Value != 120 || q.After.ID != 8 {
+			t.Fatalf("expected the query to continue after the cursor, got %+v", q.After)
+		}
+		return []uint{7}, nil, nil
+	})
+	sceneRepo.EXPECT().GetByIDs([]uint{7}).Return([]data.Scene{{ID: 7}}, nil)
+
+	params.Cursor = result.NextCursor
+	result, err = svc.Search(params)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.NextCursor != "" {
+		t.Fatalf("expected the last page to have no next cursor, got %q", result.NextCursor)
+	}
+
+	// A cursor is tied to the sort it was issued for
+	params.Sort = "duration_asc"
+	params.Cursor = data.NewCursor("duration_desc", 120.0, 8).Encode()
+	if _, err := svc.Search(params); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a validation error for a cursor from another sort, got %v", err)
+	}
+	params.Sort = "title_asc"
+	params.Cursor = ""
+	if _, err := svc.Search(params); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a validation error for a sort without cursor support, got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// consistencySampleSize caps how many missing and orphaned IDs a report lists
+const consistencySampleSize = 100
+
+// SearchConsistencyReport is the result of comparing the search index with the database.
+type SearchConsistencyReport struct {
+	CheckedAt    time.Time `json:"checked_at"`
+	DBSceneCount int       `json:"db_scene_count"` // non-trashed scenes
+	IndexedCount int       `json:"indexed_count"`
+	// Missing scenes are in the database but not in the index
+	MissingCount    int    `json:"missing_count"`
+	MissingSceneIDs []uint `json:"missing_scene_ids"`
+	// Orphans are indexed documents without a non-trashed scene
+	OrphanCount    int    `json:"orphan_count"`
+	OrphanSceneIDs []uint `json:"orphan_scene_ids"`
+	Healed         bool   `json:"healed"`
+	HealError      string `json:"heal_error,omitempty"`
+}
+
+// Consistent reports whether the index matches the database.
+func (r *SearchConsistencyReport) Consistent() bool {
+	return r.MissingCount == 0 && r.OrphanCount == 0
+}
+
+// consistencyIndex is the part of the search service the checker reads and heals.
+type consistencyIndex interface {
+	IndexedSceneIDs() ([]uint, error)
+	BulkUpdateSceneIndex(scenes []data.Scene) error
+	BulkDeleteSceneIndex(ids []uint) error
+}
+
+// SearchConsistencyService periodically diffs scene IDs in PostgreSQL against
+// the Meilisearch index to catch index writes that were lost, e.g. while
+// Meilisearch was down. With auto-heal, missing scenes are re-indexed and
+// orphaned documents deleted.
+type SearchConsistencyService struct {
+	index          consistencyIndex
+	reindexService *SearchReindexService
+	sceneRepo      data.SceneRepository
+	interval       time.Duration
+	autoHeal       bool
+	logger         *zap.Logger
+
+	mu       sync.Mutex
+	checking bool
+	last     *SearchConsistencyReport
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewSearchConsistencyService(
+	searchService *SearchService,
+	reindexService *SearchReindexService,
+	sceneRepo data.SceneRepository,
+	interval time.Duration,
+	autoHeal bool,
+	logger *zap.Logger,
+) *SearchConsistencyService {
+	s := &SearchConsistencyService{
+		reindexService: reindexService,
+		sceneRepo:      sceneRepo,
+		interval:       interval,
+		autoHeal:       autoHeal,
+		logger:         logger.With(zap.String("component", "search_consistency")),
+	}
+	if searchService != nil && searchService.meiliClient != nil {
+		s.index = searchService
+	}
+	return s
+}
+
+// Start runs the check every interval. It is a no-op when the interval is 0 or
+// Meilisearch is not configured.
+func (s *SearchConsistencyService) Start() {
+	if s.index == nil || s.interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		// The first check waits a full interval so it does not compete with
+		// startup indexing and scan recovery
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Check(s.autoHeal); err != nil {
+					s.logger.Warn("Search consistency check failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	s.logger.Info("Search consistency checker started",
+		zap.Duration("interval", s.interval),
+		zap.Bool("auto_heal", s.autoHeal),
+	)
+}
+
+// Stop halts the background check.
+func (s *SearchConsistencyService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// LastReport returns the most recent report, or nil if no check has run yet.
+func (s *SearchConsistencyService) LastReport() *SearchConsistencyReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// Check compares the index with the database and, with heal, fixes the differences.
+func (s *SearchConsistencyService) Check(heal bool) (*SearchConsistencyReport, error) {
+	if s.index == nil {
+		return nil, apperrors.NewValidationError("meilisearch is not configured")
+	}
+	// A rebuild clears the index first, so everything would look missing
+	if s.reindexService != nil && s.reindexService.IsRunning() {
+		return nil, apperrors.NewConflictError("search consistency check", "a search reindex is running")
+	}
+
+	s.mu.Lock()
+	if s.checking {
+		s.mu.Unlock()
+		return nil, apperrors.NewConflictError("search consistency check", "a consistency check is already running")
+	}
+	s.checking = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.checking = false
+		s.mu.Unlock()
+	}()
+
+	report, missing, orphans, err := s.diff()
+	if err != nil {
+		return nil, err
+	}
+
+	if heal && !report.Consistent() {
+		if err := s.heal(missing, orphans); err != nil {
+			report.HealError = err.Error()
+			s.logger.Error("Failed to heal search index", zap.Error(err))
+		} else {
+			report.Healed = true
+		}
+	}
+
+	if report.Consistent() {
+		s.logger.Debug("Search index is consistent", zap.Int("scenes", report.DBSceneCount))
+	} else {
+		s.logger.Warn("Search index is inconsistent",
+			zap.Int("missing", report.MissingCount),
+			zap.Int("orphans", report.OrphanCount),
+			zap.Bool("healed", report.Healed),
+		)
+	}
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+	return report, nil
+}
+
+// diff returns the report with the full lists of missing and orphaned IDs.
+func (s *SearchConsistencyService) diff() (*SearchConsistencyReport, []uint, []uint, error) {
+	// Read the index before the database: a scene created in between then only
+	// shows up as missing, which healing re-indexes harmlessly, instead of as an
+	// orphan that would be deleted
+	indexedIDs, err := s.index.IndexedSceneIDs()
+	if err != nil {
+		return nil, nil, nil, apperrors.NewInternalError("failed to list indexed scenes", err)
+	}
+	dbIDs, err := s.sceneRepo.GetActiveIDs()
+	if err != nil {
+		return nil, nil, nil, apperrors.NewInternalError("failed to list scenes", err)
+	}
+
+	indexed := make(map[uint]struct{}, len(indexedIDs))
+	for _, id := range indexedIDs {
+		indexed[id] = struct{}{}
+	}
+	inDB := make(map[uint]struct{}, len(dbIDs))
+	var missing []uint
+	for _, id := range dbIDs {
+		inDB[id] = struct{}{}
+		if _, ok := indexed[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	var orphans []uint
+	for _, id := range indexedIDs {
+		if _, ok := inDB[id]; !ok {
+			orphans = append(orphans, id)
+		}
+	}
+
+	report := &SearchConsistencyReport{
+		CheckedAt:       time.Now(),
+		DBSceneCount:    len(dbIDs),
+		IndexedCount:    len(indexedIDs),
+		MissingCount:    len(missing),
+		MissingSceneIDs: sampleIDs(missing),
+		OrphanCount:     len(orphans),
+		OrphanSceneIDs:  sampleIDs(orphans),
+	}
+	return report, missing, orphans, nil
+}
+
+func (s *SearchConsistencyService) heal(missing, orphans []uint) error {
+	for start := 0; start < len(missing); start += reindexBatchSize {
+		end := min(start+reindexBatchSize, len(missing))
+		scenes, err := s.sceneRepo.GetByIDs(missing[start:end])
+		if err != nil {
+			return apperrors.NewInternalError("failed to get missing scenes", err)
+		}
+		if err := s.index.BulkUpdateSceneIndex(scenes); err != nil {
+			return apperrors.NewInternalError("failed to index missing scenes", err)
+		}
+	}
+
+	if err := s.index.BulkDeleteSceneIndex(orphans); err != nil {
+		return apperrors.NewInternalError("failed to delete orphaned documents", err)
+	}
+
+	s.logger.Info("Healed search index",
+		zap.Int("reindexed", len(missing)),
+		zap.Int("deleted", len(orphans)),
+	)
+	return nil
+}
+
+func sampleIDs(ids []uint) []uint {
+	if len(ids) > consistencySampleSize {
+		ids = ids[:consistencySampleSize]
+	}
+	if ids == nil {
+		return []uint{}
+	}
+	return ids
+}
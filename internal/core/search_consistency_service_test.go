@@ -0,0 +1,134 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type fakeConsistencyIndex struct {
+	ids       []uint
+	indexed   []uint
+	deleted   []uint
+	indexErr  error
+	listCalls int
+}
+
+func (f *fakeConsistencyIndex) IndexedSceneIDs() ([]uint, error) {
+	f.listCalls++
+	return f.ids, nil
+}
+
+func (f *fakeConsistencyIndex) BulkUpdateSceneIndex(scenes []data.Scene) error {
+	if f.indexErr != nil {
+		return f.indexErr
+	}
+	for _, scene := range scenes {
+		f.indexed = append(f.indexed, scene.ID)
+	}
+	return nil
+}
+
+func (f *fakeConsistencyIndex) BulkDeleteSceneIndex(ids []uint) error {
+	f.deleted = append(f.deleted, ids...)
+	return nil
+}
+
+func newTestConsistencyService(t *testing.T, index *fakeConsistencyIndex) (*SearchConsistencyService, *mocks.MockSceneRepository) {
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewSearchConsistencyService(nil, nil, sceneRepo, 0, false, zap.NewNop())
+	svc.index = index
+	return svc, sceneRepo
+}
+
+func TestSearchConsistency_ReportsDifferences(t *testing.T) {
+	index := &fakeConsistencyIndex{ids: []uint{1, 2, 9}}
+	svc, sceneRepo := newTestConsistencyService(t, index)
+	sceneRepo.EXPECT().GetActiveIDs().Return([]uint{1, 2, 3, 4}, nil)
+
+	report, err := svc.Check(false)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.DBSceneCount != 4 || report.IndexedCount != 3 {
+		t.Fatalf("unexpected counts %+v", report)
+	}
+	if report.MissingCount != 2 || report.MissingSceneIDs[0] != 3 || report.MissingSceneIDs[1] != 4 {
+		t.Fatalf("expected scenes 3 and 4 missing, got %v", report.MissingSceneIDs)
+	}
+	if report.OrphanCount != 1 || report.OrphanSceneIDs[0] != 9 {
+		t.Fatalf("expected scene 9 orphaned, got %v", report.OrphanSceneIDs)
+	}
+	if report.Healed || len(index.indexed) != 0 || len(index.deleted) != 0 {
+		t.Fatal("expected no healing without heal")
+	}
+	if svc.LastReport() != report {
+		t.Fatal("expected the report to be kept as the last report")
+	}
+}
+
+func TestSearchConsistency_Heals(t *testing.T) {
+	index := &fakeConsistencyIndex{ids: []uint{1, 9}}
+	svc, sceneRepo := newTestConsistencyService(t, index)
+	sceneRepo.EXPECT().GetActiveIDs().Return([]uint{1, 3}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{3}).Return([]data.Scene{{ID: 3}}, nil)
+
+	report, err := svc.Check(true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !report.Healed || report.HealError != "" {
+		t.Fatalf("expected healed report, got %+v", report)
+	}
+	if len(index.indexed) != 1 || index.indexed[0] != 3 {
+		t.Fatalf("expected scene 3 re-indexed, got %v", index.indexed)
+	}
+	if len(index.deleted) != 1 || index.deleted[0] != 9 {
+		t.Fatalf("expected scene 9 deleted, got %v", index.deleted)
+	}
+}
+
+func TestSearchConsistency_HealFailureIsReported(t *testing.T) {
+	index := &fakeConsistencyIndex{indexErr: errors.New("meilisearch down")}
+	svc, sceneRepo := newTestConsistencyService(t, index)
+	sceneRepo.EXPECT().GetActiveIDs().Return([]uint{1}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1}).Return([]data.Scene{{ID: 1}}, nil)
+
+	report, err := svc.Check(true)
+	if err != nil {
+		t.Fatalf("expected the report despite the heal failure, got %v", err)
+	}
+	if report.Healed || report.HealError == "" {
+		t.Fatalf("expected heal error in report, got %+v", report)
+	}
+}
+
+func TestSearchConsistency_SkipsDuringReindex(t *testing.T) {
+	index := &fakeConsistencyIndex{}
+	svc, _ := newTestConsistencyService(t, index)
+	svc.reindexService = &SearchReindexService{running: true}
+
+	if _, err := svc.Check(true); !apperrors.IsConflict(err) {
+		t.Fatalf("expected conflict during reindex, got %v", err)
+	}
+	if index.listCalls != 0 {
+		t.Fatal("expected the index not to be read during a reindex")
+	}
+}
+
+func TestSearchConsistency_NotConfigured(t *testing.T) {
+	svc := NewSearchConsistencyService(nil, nil, nil, 0, false, zap.NewNop())
+	if _, err := svc.Check(false); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if svc.LastReport() != nil {
+		t.Fatal("expected no report before the first check")
+	}
+}
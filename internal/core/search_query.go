@@ -0,0 +1,546 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"goonhub/internal/data"
+)
+
+// SearchQuery is a parsed advanced search query, e.g.
+//
+//	jane studio:"Acme Films" duration:>1200 tag:(pov AND outdoor) -tag:vr
+//
+// Words that are not field terms are kept as free text. Only the field names
+// below are recognized, so ordinary text containing a colon still searches as
+// text.
+type SearchQuery struct {
+	Text        string     `json:"text"`
+	Studio      string     `json:"studio,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`         // scenes must have all of these
+	ExcludeTags []string   `json:"exclude_tags,omitempty"` // scenes must have none of these
+	Actors      []string   `json:"actors,omitempty"`       // scenes must have at least one of these
+	MinDuration int        `json:"min_duration,omitempty"` // seconds
+	MaxDuration int        `json:"max_duration,omitempty"`
+	Resolution  string     `json:"resolution,omitempty"`
+	AddedAfter  *time.Time `json:"added_after,omitempty"`
+	AddedBefore *time.Time `json:"added_before,omitempty"`
+	MinRating   float64    `json:"min_rating,omitempty"`
+	MaxRating   float64    `json:"max_rating,omitempty"`
+	Liked       *bool      `json:"liked,omitempty"`
+}
+
+// QueryParseError is a syntax error in an advanced search query. Position is
+// the byte offset in the query where the problem starts.
+type QueryParseError struct {
+	Message  string `json:"message"`
+	Position int    `json:"position"`
+}
+
+func (e *QueryParseError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+// Query fields. tag and actor accept a single value or a parenthesized group;
+// duration, added and rating accept comparisons (>, >=, <, <=) and ranges (a..b).
+const (
+	queryFieldStudio     = "studio"
+	queryFieldTag        = "tag"
+	queryFieldActor      = "actor"
+	queryFieldDuration   = "duration"
+	queryFieldResolution = "resolution"
+	queryFieldAdded      = "added"
+	queryFieldRating     = "rating"
+	queryFieldLiked      = "liked"
+)
+
+var queryFields = map[string]bool{
+	queryFieldStudio:     true,
+	queryFieldTag:        true,
+	queryFieldActor:      true,
+	queryFieldDuration:   true,
+	queryFieldResolution: true,
+	queryFieldAdded:      true,
+	queryFieldRating:     true,
+	queryFieldLiked:      true,
+}
+
+// ratingStep is the rating granularity, used to turn > and < into bounds.
+const ratingStep = 0.5
+
+// ParseSearchQuery parses an advanced search query. A query without field
+// terms parses to its text unchanged. Errors are *QueryParseError.
+func ParseSearchQuery(input string) (*SearchQuery, error) {
+	p := &queryParser{input: input}
+	q := &SearchQuery{}
+	var text []string
+
+	for {
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		start := p.pos
+
+		negated := false
+		if p.peek() == '-' {
+			negated = true
+			p.pos++
+		}
+		field, ok := p.fieldName()
+		if !ok {
+			p.pos = start
+			text = append(text, p.word())
+			continue
+		}
+		if negated && field != queryFieldTag {
+			return nil, &QueryParseError{Message: fmt.Sprintf("%s: cannot be negated, only tag: can", field), Position: start}
+		}
+
+		if err := p.term(q, field, negated, start); err != nil {
+			return nil, err
+		}
+	}
+
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// HasFields reports whether the query has any field terms.
+func (q *SearchQuery) HasFields() bool {
+	return q.Studio != "" || len(q.Tags) > 0 || len(q.ExcludeTags) > 0 || len(q.Actors) > 0 ||
+		q.MinDuration > 0 || q.MaxDuration > 0 || q.Resolution != "" ||
+		q.AddedAfter != nil || q.AddedBefore != nil || q.MinRating > 0 || q.MaxRating > 0 || q.Liked != nil
+}
+
+// ApplySearchQuery merges a parsed query into params: the text replaces the
+// query, field terms add to or override the other filters. Tag names are
+// resolved to IDs; the names of tags that do not exist are returned and
+// ignored, like unknown names in the tags filter.
+func (s *SearchService) ApplySearchQuery(params *data.SceneSearchParams, q *SearchQuery) ([]string, error) {
+	return applySearchQuery(s.tagRepo, params, q)
+}
+
+func applySearchQuery(tagRepo data.TagRepository, params *data.SceneSearchParams, q *SearchQuery) ([]string, error) {
+	params.Query = q.Text
+	if q.Studio != "" {
+		params.Studio = q.Studio
+	}
+	params.Actors = append(params.Actors, q.Actors...)
+	if q.MinDuration > 0 {
+		params.MinDuration = q.MinDuration
+	}
+	if q.MaxDuration > 0 {
+		params.MaxDuration = q.MaxDuration
+	}
+	if heights, ok := ResolutionHeights[q.Resolution]; ok {
+		params.MinHeight = heights[0]
+		params.MaxHeight = heights[1]
+	}
+	if q.AddedAfter != nil {
+		params.MinDate = q.AddedAfter
+	}
+	if q.AddedBefore != nil {
+		params.MaxDate = q.AddedBefore
+	}
+	if q.MinRating > 0 {
+		params.MinRating = q.MinRating
+	}
+	if q.MaxRating > 0 {
+		params.MaxRating = q.MaxRating
+	}
+	if q.Liked != nil {
+		params.Liked = q.Liked
+	}
+
+	var unknown []string
+	resolve := func(names []string) ([]uint, error) {
+		if len(names) == 0 {
+			return nil, nil
+		}
+		tags, err := tagRepo.GetByNames(names)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tags: %w", err)
+		}
+		byName := make(map[string]uint, len(tags))
+		for name, id := range data.TagIDsByName(tags) {
+			byName[strings.ToLower(name)] = id
+		}
+		ids := make([]uint, 0, len(names))
+		for _, name := range names {
+			if id, ok := byName[strings.ToLower(name)]; ok {
+				ids = append(ids, id)
+			} else {
+				unknown = append(unknown, name)
+			}
+		}
+		return ids, nil
+	}
+
+	tagIDs, err := resolve(q.Tags)
+	if err != nil {
+		return nil, err
+	}
+	params.TagIDs = append(params.TagIDs, tagIDs...)
+	excludeIDs, err := resolve(q.ExcludeTags)
+	if err != nil {
+		return nil, err
+	}
+	params.ExcludeTagIDs = append(params.ExcludeTagIDs, excludeIDs...)
+
+	return unknown, nil
+}
+
+type queryParser struct {
+	input string
+	pos   int
+}
+
+func (p *queryParser) done() bool { return p.pos >= len(p.input) }
+
+func (p *queryParser) peek() byte { return p.input[p.pos] }
+
+func (p *queryParser) skipSpace() {
+	for !p.done() && isQuerySpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func isQuerySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// fieldName consumes "name:" when name is a known field.
+func (p *queryParser) fieldName() (string, bool) {
+	end := p.pos
+	for end < len(p.input) && (unicode.IsLetter(rune(p.input[end])) || p.input[end] == '_') {
+		end++
+	}
+	if end == p.pos || end >= len(p.input) || p.input[end] != ':' {
+		return "", false
+	}
+	name := strings.ToLower(p.input[p.pos:end])
+	if name == "tags" {
+		name = queryFieldTag
+	}
+	if name == "actors" {
+		name = queryFieldActor
+	}
+	if !queryFields[name] {
+		return "", false
+	}
+	p.pos = end + 1
+	return name, true
+}
+
+// word consumes a free text word, keeping a quoted phrase with its quotes so
+// the search backend can match it as a phrase.
+func (p *queryParser) word() string {
+	start := p.pos
+	if p.peek() == '"' {
+		if end := strings.IndexByte(p.input[p.pos+1:], '"'); end >= 0 {
+			p.pos += end + 2
+			return p.input[start:p.pos]
+		}
+	}
+	for !p.done() && !isQuerySpace(p.peek()) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// value consumes a bare or quoted value. stop lists extra bytes that end a bare value.
+func (p *queryParser) value(stop string) (string, error) {
+	start := p.pos
+	if p.done() || isQuerySpace(p.peek()) || strings.IndexByte(stop, p.peek()) >= 0 {
+		return "", &QueryParseError{Message: "missing value", Position: start}
+	}
+	if p.peek() == '"' {
+		end := strings.IndexByte(p.input[p.pos+1:], '"')
+		if end < 0 {
+			return "", &QueryParseError{Message: "unterminated quote", Position: start}
+		}
+		p.pos += end + 2
+		v := strings.TrimSpace(p.input[start+1 : p.pos-1])
+		if v == "" {
+			return "", &QueryParseError{Message: "missing value", Position: start}
+		}
+		return v, nil
+	}
+	for !p.done() && !isQuerySpace(p.peek()) && strings.IndexByte(stop, p.peek()) < 0 {
+		p.pos++
+	}
+	return p.input[start:p.pos], nil
+}
+
+// group consumes "(a AND b)" or "(a OR b)" and returns the values. op is the
+// only operator allowed; values next to each other without one imply it.
+func (p *queryParser) group(field, op string) ([]string, error) {
+	open := p.pos
+	p.pos++ // (
+	var values []string
+	expectValue := true
+	for {
+		p.skipSpace()
+		if p.done() {
+			return nil, &QueryParseError{Message: "unterminated group, missing )", Position: open}
+		}
+		if p.peek() == ')' {
+			if len(values) == 0 {
+				return nil, &QueryParseError{Message: "empty group", Position: open}
+			}
+			if expectValue {
+				return nil, &QueryParseError{Message: "missing value after " + op, Position: p.pos}
+			}
+			p.pos++
+			return values, nil
+		}
+		start := p.pos
+		v, err := p.value(")")
+		if err != nil {
+			return nil, err
+		}
+		if p.input[start] != '"' {
+			switch strings.ToUpper(v) {
+			case "AND", "OR":
+				if strings.ToUpper(v) != op {
+					return nil, &QueryParseError{Message: fmt.Sprintf("%s: groups only support %s", field, op), Position: start}
+				}
+				if expectValue {
+					return nil, &QueryParseError{Message: "missing value before " + op, Position: start}
+				}
+				expectValue = true
+				continue
+			}
+		}
+		values = append(values, v)
+		expectValue = false
+	}
+}
+
+func (p *queryParser) term(q *SearchQuery, field string, negated bool, start int) error {
+	valueStart := p.pos
+	switch field {
+	case queryFieldTag, queryFieldActor:
+		op := "AND"
+		if field == queryFieldActor {
+			op = "OR"
+		}
+		var values []string
+		if !p.done() && p.peek() == '(' {
+			group, err := p.group(field, op)
+			if err != nil {
+				return err
+			}
+			values = group
+		} else {
+			v, err := p.value("")
+			if err != nil {
+				return err
+			}
+			values = []string{v}
+		}
+		switch {
+		case field == queryFieldActor:
+			q.Actors = append(q.Actors, values...)
+		case negated:
+			q.ExcludeTags = append(q.ExcludeTags, values...)
+		default:
+			q.Tags = append(q.Tags, values...)
+		}
+		return nil
+	}
+
+	v, err := p.value("")
+	if err != nil {
+		return err
+	}
+
+	switch field {
+	case queryFieldStudio:
+		q.Studio = v
+	case queryFieldResolution:
+		if _, ok := ResolutionHeights[strings.ToLower(v)]; !ok {
+			return &QueryParseError{Message: fmt.Sprintf("unknown resolution %q", v), Position: valueStart}
+		}
+		q.Resolution = strings.ToLower(v)
+	case queryFieldLiked:
+		liked, err := strconv.ParseBool(v)
+		if err != nil {
+			return &QueryParseError{Message: "liked: must be true or false", Position: valueStart}
+		}
+		q.Liked = &liked
+	case queryFieldDuration:
+		lo, hi, err := parseIntRange(v, valueStart, parseQueryDuration)
+		if err != nil {
+			return err
+		}
+		q.MinDuration, q.MaxDuration = lo, hi
+	case queryFieldRating:
+		lo, hi, err := parseRatingRange(v, valueStart)
+		if err != nil {
+			return err
+		}
+		q.MinRating, q.MaxRating = lo, hi
+	case queryFieldAdded:
+		after, before, err := parseDateRange(v, valueStart)
+		if err != nil {
+			return err
+		}
+		q.AddedAfter, q.AddedBefore = after, before
+	default:
+		return &QueryParseError{Message: "unknown field " + field, Position: start}
+	}
+	return nil
+}
+
+// splitComparison splits ">=1200" into ">=" and "1200". A plain value has no operator.
+func splitComparison(v string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(v, op) {
+			return op, v[len(op):]
+		}
+	}
+	return "", v
+}
+
+// parseQueryDuration parses seconds, optionally with an s, m or h suffix.
+func parseQueryDuration(v string) (int, bool) {
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(v, "h"):
+		multiplier, v = 3600, strings.TrimSuffix(v, "h")
+	case strings.HasSuffix(v, "m"):
+		multiplier, v = 60, strings.TrimSuffix(v, "m")
+	case strings.HasSuffix(v, "s"):
+		v = strings.TrimSuffix(v, "s")
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n * multiplier, true
+}
+
+// parseIntRange parses a comparison or a..b range into inclusive bounds; 0 means unbounded.
+func parseIntRange(v string, pos int, parse func(string) (int, bool)) (int, int, error) {
+	invalid := &QueryParseError{Message: fmt.Sprintf("invalid number %q", v), Position: pos}
+	if lo, hi, ok := strings.Cut(v, ".."); ok {
+		min, ok1 := parse(lo)
+		max, ok2 := parse(hi)
+		if !ok1 || !ok2 || min > max {
+			return 0, 0, invalid
+		}
+		return min, max, nil
+	}
+	op, num := splitComparison(v)
+	n, ok := parse(num)
+	if !ok {
+		return 0, 0, invalid
+	}
+	switch op {
+	case ">":
+		return n + 1, 0, nil
+	case ">=":
+		return n, 0, nil
+	case "<":
+		if n <= 1 {
+			return 0, 0, &QueryParseError{Message: fmt.Sprintf("%q matches nothing", v), Position: pos}
+		}
+		return 0, n - 1, nil
+	case "<=":
+		return 0, n, nil
+	default:
+		return n, n, nil
+	}
+}
+
+func parseRatingRange(v string, pos int) (float64, float64, error) {
+	invalid := &QueryParseError{Message: fmt.Sprintf("invalid rating %q, ratings go from 0.5 to 5", v), Position: pos}
+	parse := func(s string) (float64, bool) {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil && f >= 0 && f <= 5
+	}
+	if lo, hi, ok := strings.Cut(v, ".."); ok {
+		min, ok1 := parse(lo)
+		max, ok2 := parse(hi)
+		if !ok1 || !ok2 || min > max {
+			return 0, 0, invalid
+		}
+		return min, max, nil
+	}
+	op, num := splitComparison(v)
+	n, ok := parse(num)
+	if !ok {
+		return 0, 0, invalid
+	}
+	switch op {
+	case ">":
+		return n + ratingStep, 0, nil
+	case ">=":
+		return n, 0, nil
+	case "<":
+		if n <= ratingStep {
+			return 0, 0, &QueryParseError{Message: fmt.Sprintf("%q matches nothing", v), Position: pos}
+		}
+		return 0, n - ratingStep, nil
+	case "<=":
+		return 0, n, nil
+	default:
+		return n, n, nil
+	}
+}
+
+// parseDateRange parses YYYY-MM-DD comparisons and ranges into a time window.
+// Days are whole: >= starts at midnight and <= ends at the end of the day.
+func parseDateRange(v string, pos int) (*time.Time, *time.Time, error) {
+	parse := func(s string) (time.Time, error) {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return t, &QueryParseError{Message: fmt.Sprintf("invalid date %q, use YYYY-MM-DD", s), Position: pos}
+		}
+		return t, nil
+	}
+	endOfDay := func(t time.Time) *time.Time {
+		end := t.Add(24*time.Hour - time.Second)
+		return &end
+	}
+
+	if lo, hi, ok := strings.Cut(v, ".."); ok {
+		from, err := parse(lo)
+		if err != nil {
+			return nil, nil, err
+		}
+		to, err := parse(hi)
+		if err != nil {
+			return nil, nil, err
+		}
+		if to.Before(from) {
+			return nil, nil, &QueryParseError{Message: fmt.Sprintf("empty date range %q", v), Position: pos}
+		}
+		return &from, endOfDay(to), nil
+	}
+
+	op, date := splitComparison(v)
+	t, err := parse(date)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch op {
+	case ">":
+		after := t.Add(24 * time.Hour)
+		return &after, nil, nil
+	case ">=":
+		return &t, nil, nil
+	case "<":
+		before := t.Add(-time.Second)
+		return nil, &before, nil
+	case "<=":
+		return nil, endOfDay(t), nil
+	default:
+		return &t, endOfDay(t), nil
+	}
+}
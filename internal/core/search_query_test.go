@@ -0,0 +1,144 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestParseSearchQuery_FieldTerms(t *testing.T) {
+	q, err := ParseSearchQuery(`jane studio:"Acme Films" duration:>1200 tag:(pov AND outdoor) -tag:vr actor:(Ann OR "Bo Lee") "exact phrase"`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if q.Text != `jane "exact phrase"` {
+		t.Fatalf("unexpected text %q", q.Text)
+	}
+	if q.Studio != "Acme Films" {
+		t.Fatalf("unexpected studio %q", q.Studio)
+	}
+	if q.MinDuration != 1201 || q.MaxDuration != 0 {
+		t.Fatalf("unexpected duration %d-%d", q.MinDuration, q.MaxDuration)
+	}
+	if len(q.Tags) != 2 || q.Tags[0] != "pov" || q.Tags[1] != "outdoor" {
+		t.Fatalf("unexpected tags %v", q.Tags)
+	}
+	if len(q.ExcludeTags) != 1 || q.ExcludeTags[0] != "vr" {
+		t.Fatalf("unexpected excluded tags %v", q.ExcludeTags)
+	}
+	if len(q.Actors) != 2 || q.Actors[1] != "Bo Lee" {
+		t.Fatalf("unexpected actors %v", q.Actors)
+	}
+}
+
+func TestParseSearchQuery_PlainText(t *testing.T) {
+	for _, input := range []string{"", "jane doe", "part 2: the return", "-foo bar", "note:something"} {
+		q, err := ParseSearchQuery(input)
+		if err != nil {
+			t.Fatalf("%q: expected no error, got %v", input, err)
+		}
+		if q.HasFields() {
+			t.Fatalf("%q: expected no field terms, got %+v", input, q)
+		}
+	}
+}
+
+func TestParseSearchQuery_Ranges(t *testing.T) {
+	q, err := ParseSearchQuery("duration:10m..1h rating:>4 added:2024-03-01 resolution:1080P liked:true")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if q.MinDuration != 600 || q.MaxDuration != 3600 {
+		t.Fatalf("unexpected duration %d-%d", q.MinDuration, q.MaxDuration)
+	}
+	if q.MinRating != 4.5 || q.MaxRating != 0 {
+		t.Fatalf("unexpected rating %v-%v", q.MinRating, q.MaxRating)
+	}
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if q.AddedAfter == nil || !q.AddedAfter.Equal(day) || q.AddedBefore == nil || !q.AddedBefore.Equal(day.Add(24*time.Hour-time.Second)) {
+		t.Fatalf("unexpected added window %v - %v", q.AddedAfter, q.AddedBefore)
+	}
+	if q.Resolution != "1080p" || q.Liked == nil || !*q.Liked {
+		t.Fatalf("unexpected resolution or liked %+v", q)
+	}
+
+	q, err = ParseSearchQuery("duration:<=300 added:<2024-01-01")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if q.MinDuration != 0 || q.MaxDuration != 300 {
+		t.Fatalf("unexpected duration %d-%d", q.MinDuration, q.MaxDuration)
+	}
+	if q.AddedAfter != nil || q.AddedBefore == nil || !q.AddedBefore.Equal(time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC)) {
+		t.Fatalf("unexpected added window %v - %v", q.AddedAfter, q.AddedBefore)
+	}
+}
+
+func TestParseSearchQuery_Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+	}{
+		{`studio:"Acme`, 7},
+		{"tag:(a AND b", 4},
+		{"tag:(a OR b)", 7},
+		{"actor:(a AND b)", 9},
+		{"tag:(a AND)", 10},
+		{"tag:()", 4},
+		{"jane -studio:acme", 5},
+		{"duration:>abc", 9},
+		{"added:2024-13-01", 6},
+		{"resolution:8k", 11},
+		{"liked:maybe", 6},
+		{"studio: x", 7},
+	}
+	for _, tt := range tests {
+		_, err := ParseSearchQuery(tt.input)
+		var parseErr *QueryParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("%q: expected a parse error, got %v", tt.input, err)
+		}
+		if parseErr.Position != tt.position {
+			t.Fatalf("%q: expected error at %d, got %d (%s)", tt.input, tt.position, parseErr.Position, parseErr.Message)
+		}
+	}
+}
+
+func TestSearchService_ApplySearchQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tagRepo := mocks.NewMockTagRepository(ctrl)
+	svc := NewSearchService(nil, nil, SearchBackendAuto, nil, nil, tagRepo, nil, nil, zap.NewNop())
+
+	tagRepo.EXPECT().GetByNames([]string{"POV", "missing"}).Return([]data.Tag{{ID: 3, Name: "pov"}}, nil)
+	tagRepo.EXPECT().GetByNames([]string{"vr"}).Return([]data.Tag{{ID: 9, Name: "VR"}}, nil)
+
+	q, err := ParseSearchQuery("jane tag:(POV missing) -tag:vr studio:Acme duration:>=600")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	params := data.SceneSearchParams{Query: "ignored", Studio: "Other", TagIDs: []uint{1}}
+	unknown, err := svc.ApplySearchQuery(&params, q)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if params.Query != "jane" || params.Studio != "Acme" || params.MinDuration != 600 {
+		t.Fatalf("unexpected params %+v", params)
+	}
+	if len(params.TagIDs) != 2 || params.TagIDs[1] != 3 {
+		t.Fatalf("expected tag 3 added to the existing tag, got %v", params.TagIDs)
+	}
+	if len(params.ExcludeTagIDs) != 1 || params.ExcludeTagIDs[0] != 9 {
+		t.Fatalf("expected tag 9 excluded, got %v", params.ExcludeTagIDs)
+	}
+	if len(unknown) != 1 || unknown[0] != "missing" {
+		t.Fatalf("expected the missing tag reported, got %v", unknown)
+	}
+}
@@ -0,0 +1,335 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/infrastructure/meilisearch"
+
+	"go.uber.org/zap"
+)
+
+// reindexBatchSize is how many scenes are indexed, and checkpointed, at a time
+const reindexBatchSize = 100
+
+// ReindexStatusInterrupted reports a checkpoint left running by a process that
+// stopped mid-rebuild. It is never stored.
+const ReindexStatusInterrupted = "interrupted"
+
+// ReindexStatus is the progress of a full search reindex.
+type ReindexStatus struct {
+	data.SearchReindexCheckpoint
+	// Resumable is true when a rebuild stopped before finishing and can continue
+	// from LastSceneID instead of starting over
+	Resumable bool `json:"resumable"`
+}
+
+// reindexTarget is the part of the Meilisearch client a rebuild writes to.
+type reindexTarget interface {
+	ClearIndex() error
+	BulkIndex(docs []meilisearch.SceneDocument) error
+	ClearSegments() error
+	IndexSegments(docs []meilisearch.SegmentDocument) error
+}
+
+// SearchReindexService rebuilds the whole scene search index in batches. Each
+// batch is checkpointed in the database and published as a
+// "search:reindex_progress" event, so the admin UI can follow along and an
+// interrupted rebuild can resume where it stopped.
+type SearchReindexService struct {
+	target         reindexTarget
+	sceneRepo      data.SceneRepository
+	tagRepo        data.TagRepository
+	actorRepo      data.ActorRepository
+	markerRepo     data.MarkerRepository
+	checkpointRepo data.SearchReindexRepository
+	eventBus       *EventBus
+	logger         *zap.Logger
+	batchSize      int
+
+	mu      sync.Mutex
+	running bool
+}
+
+func NewSearchReindexService(
+	searchService *SearchService,
+	sceneRepo data.SceneRepository,
+	tagRepo data.TagRepository,
+	actorRepo data.ActorRepository,
+	checkpointRepo data.SearchReindexRepository,
+	eventBus *EventBus,
+	logger *zap.Logger,
+) *SearchReindexService {
+	s := &SearchReindexService{
+		sceneRepo:      sceneRepo,
+		tagRepo:        tagRepo,
+		actorRepo:      actorRepo,
+		checkpointRepo: checkpointRepo,
+		eventBus:       eventBus,
+		logger:         logger,
+		batchSize:      reindexBatchSize,
+	}
+	// Keep a nil client from becoming a non-nil interface
+	if searchService != nil && searchService.meiliClient != nil {
+		s.target = searchService.meiliClient
+		s.markerRepo = searchService.markerRepo
+	}
+	return s
+}
+
+// Start runs a reindex in the background. With resume, an unfinished rebuild
+// continues from its checkpoint; otherwise the index is cleared and rebuilt.
+func (s *SearchReindexService) Start(resume bool) (*ReindexStatus, error) {
+	if s.target == nil {
+		return nil, apperrors.NewValidationError("meilisearch is not configured")
+	}
+
+	checkpoint, err := s.begin(resume)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := s.run(context.Background(), checkpoint); err != nil {
+			s.logger.Error("search reindex failed", zap.Error(err))
+		}
+	}()
+
+	return &ReindexStatus{SearchReindexCheckpoint: *checkpoint}, nil
+}
+
+// Run reindexes synchronously until done or ctx is cancelled. Used for
+// offline rebuilds from the command line.
+func (s *SearchReindexService) Run(ctx context.Context, resume bool) (*ReindexStatus, error) {
+	if s.target == nil {
+		return nil, fmt.Errorf("meilisearch is not configured")
+	}
+
+	checkpoint, err := s.begin(resume)
+	if err != nil {
+		return nil, err
+	}
+	err = s.run(ctx, checkpoint)
+	return &ReindexStatus{SearchReindexCheckpoint: *checkpoint, Resumable: isResumable(checkpoint)}, err
+}
+
+// Status returns the progress of the running or last reindex, or nil if no
+// reindex has run yet.
+func (s *SearchReindexService) Status() (*ReindexStatus, error) {
+	checkpoint, err := s.checkpointRepo.Get()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get reindex checkpoint", err)
+	}
+	if checkpoint == nil {
+		return nil, nil
+	}
+
+	if checkpoint.Status == data.ReindexStatusRunning && !s.IsRunning() {
+		checkpoint.Status = ReindexStatusInterrupted
+	}
+	return &ReindexStatus{SearchReindexCheckpoint: *checkpoint, Resumable: isResumable(checkpoint)}, nil
+}
+
+// IsRunning reports whether a reindex is running in this process.
+func (s *SearchReindexService) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// begin claims the single reindex slot and prepares the checkpoint to run from.
+func (s *SearchReindexService) begin(resume bool) (*data.SearchReindexCheckpoint, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, apperrors.NewConflictError("search reindex", "a search reindex is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	checkpoint, err := s.prepare(resume)
+	if err != nil {
+		s.finish()
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+func (s *SearchReindexService) prepare(resume bool) (*data.SearchReindexCheckpoint, error) {
+	total, err := s.sceneRepo.CountActive()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to count scenes", err)
+	}
+
+	if resume {
+		previous, err := s.checkpointRepo.Get()
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to get reindex checkpoint", err)
+		}
+		if previous != nil && previous.Status == data.ReindexStatusRunning {
+			// We hold the reindex slot, so a running checkpoint is left over from a stopped process
+			previous.Status = ReindexStatusInterrupted
+		}
+		if previous != nil && isResumable(previous) {
+			previous.Status = data.ReindexStatusRunning
+			previous.Error = ""
+			previous.Total = int(total)
+			if err := s.checkpointRepo.Save(previous); err != nil {
+				return nil, apperrors.NewInternalError("failed to save reindex checkpoint", err)
+			}
+			s.logger.Info("resuming search reindex", zap.Uint("after_scene_id", previous.LastSceneID), zap.Int("indexed", previous.Indexed))
+			return previous, nil
+		}
+	}
+
+	if err := s.target.ClearIndex(); err != nil {
+		return nil, apperrors.NewInternalError("failed to clear search index", err)
+	}
+	if err := s.target.ClearSegments(); err != nil {
+		return nil, apperrors.NewInternalError("failed to clear segments index", err)
+	}
+	checkpoint := &data.SearchReindexCheckpoint{
+		Status:    data.ReindexStatusRunning,
+		Total:     int(total),
+		StartedAt: time.Now(),
+	}
+	if err := s.checkpointRepo.Save(checkpoint); err != nil {
+		return nil, apperrors.NewInternalError("failed to save reindex checkpoint", err)
+	}
+	s.logger.Info("starting full search reindex", zap.Int("total", checkpoint.Total))
+	return checkpoint, nil
+}
+
+func (s *SearchReindexService) run(ctx context.Context, checkpoint *data.SearchReindexCheckpoint) error {
+	defer s.finish()
+	s.publish("search:reindex_started", checkpoint)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			checkpoint.Status = data.ReindexStatusCancelled
+			s.save(checkpoint)
+			s.publish("search:reindex_cancelled", checkpoint)
+			return err
+		}
+
+		indexed, err := s.indexBatch(checkpoint)
+		if err != nil {
+			checkpoint.Status = data.ReindexStatusFailed
+			checkpoint.Error = err.Error()
+			s.save(checkpoint)
+			s.publish("search:reindex_failed", checkpoint)
+			return err
+		}
+		if indexed == 0 {
+			break
+		}
+
+		s.save(checkpoint)
+		s.publish("search:reindex_progress", checkpoint)
+		s.logger.Info("reindexed batch", zap.Int("indexed", checkpoint.Indexed), zap.Int("total", checkpoint.Total))
+	}
+
+	now := time.Now()
+	checkpoint.Status = data.ReindexStatusCompleted
+	checkpoint.CompletedAt = &now
+	s.save(checkpoint)
+	s.publish("search:reindex_completed", checkpoint)
+	s.logger.Info("full search reindex completed", zap.Int("indexed", checkpoint.Indexed))
+	return nil
+}
+
+// indexBatch indexes the scenes after the checkpoint and advances it.
+func (s *SearchReindexService) indexBatch(checkpoint *data.SearchReindexCheckpoint) (int, error) {
+	batch, err := s.sceneRepo.GetBatchAfterID(checkpoint.LastSceneID, s.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get scenes: %w", err)
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	batchIDs := make([]uint, len(batch))
+	for i, scene := range batch {
+		batchIDs[i] = scene.ID
+	}
+
+	tagsByScene, err := s.tagRepo.GetSceneTagsMultiple(batchIDs)
+	if err != nil {
+		s.logger.Warn("failed to get scene tags for reindexing batch", zap.Error(err))
+		tagsByScene = make(map[uint][]data.Tag)
+	}
+	actorsByScene, err := s.actorRepo.GetSceneActorsMultiple(batchIDs)
+	if err != nil {
+		s.logger.Warn("failed to get scene actors for reindexing batch", zap.Error(err))
+		actorsByScene = make(map[uint][]data.Actor)
+	}
+
+	docs := make([]meilisearch.SceneDocument, len(batch))
+	for i, scene := range batch {
+		docs[i] = buildSceneDocument(&scene, tagsByScene[scene.ID], actorsByScene[scene.ID])
+	}
+	if err := s.target.BulkIndex(docs); err != nil {
+		return 0, fmt.Errorf("failed to bulk index batch: %w", err)
+	}
+	if err := s.indexSegments(batchIDs); err != nil {
+		return 0, err
+	}
+
+	checkpoint.LastSceneID = batch[len(batch)-1].ID
+	checkpoint.Indexed += len(batch)
+	return len(batch), nil
+}
+
+// indexSegments indexes the marker labels of a batch of scenes.
+func (s *SearchReindexService) indexSegments(sceneIDs []uint) error {
+	if s.markerRepo == nil {
+		return nil
+	}
+	markers, err := s.markerRepo.GetLabeledBySceneIDs(sceneIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get markers: %w", err)
+	}
+	docs := make([]meilisearch.SegmentDocument, len(markers))
+	for i := range markers {
+		docs[i] = buildMarkerSegment(&markers[i])
+	}
+	if err := s.target.IndexSegments(docs); err != nil {
+		return fmt.Errorf("failed to index marker segments: %w", err)
+	}
+	return nil
+}
+
+func (s *SearchReindexService) finish() {
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+}
+
+// save stores the checkpoint. A failed save only costs re-indexing a batch on resume.
+func (s *SearchReindexService) save(checkpoint *data.SearchReindexCheckpoint) {
+	if err := s.checkpointRepo.Save(checkpoint); err != nil {
+		s.logger.Warn("failed to save reindex checkpoint", zap.Error(err))
+	}
+}
+
+func (s *SearchReindexService) publish(eventType string, checkpoint *data.SearchReindexCheckpoint) {
+	if s.eventBus == nil {
+		return
+	}
+	s.eventBus.Publish(SceneEvent{
+		Type: eventType,
+		Data: ReindexStatus{SearchReindexCheckpoint: *checkpoint, Resumable: isResumable(checkpoint)},
+	})
+}
+
+func isResumable(checkpoint *data.SearchReindexCheckpoint) bool {
+	switch checkpoint.Status {
+	case data.ReindexStatusFailed, data.ReindexStatusCancelled, ReindexStatusInterrupted:
+		return checkpoint.LastSceneID > 0
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/infrastructure/meilisearch"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type fakeReindexTarget struct {
+	cleared int
+	indexed []uint
+	failAt  int // fail the nth BulkIndex call (1-based), 0 = never
+	calls   int
+
+	segments []meilisearch.SegmentDocument
+}
+
+func (f *fakeReindexTarget) ClearIndex() error {
+	f.cleared++
+	return nil
+}
+
+func (f *fakeReindexTarget) BulkIndex(docs []meilisearch.SceneDocument) error {
+	f.calls++
+	if f.calls == f.failAt {
+		return errors.New("meilisearch down")
+	}
+	for _, d := range docs {
+		f.indexed = append(f.indexed, d.ID)
+	}
+	return nil
+}
+
+func (f *fakeReindexTarget) ClearSegments() error {
+	return nil
+}
+
+func (f *fakeReindexTarget) IndexSegments(docs []meilisearch.SegmentDocument) error {
+	f.segments = append(f.segments, docs...)
+	return nil
+}
+
+type fakeCheckpointRepo struct {
+	checkpoint *data.SearchReindexCheckpoint
+}
+
+func (f *fakeCheckpointRepo) Get() (*data.SearchReindexCheckpoint, error) {
+	if f.checkpoint == nil {
+		return nil, nil
+	}
+	c := *f.checkpoint
+	return &c, nil
+}
+
+func (f *fakeCheckpointRepo) Save(checkpoint *data.SearchReindexCheckpoint) error {
+	c := *checkpoint
+	f.checkpoint = &c
+	return nil
+}
+
+func newTestReindexService(t *testing.T, target *fakeReindexTarget, checkpoints *fakeCheckpointRepo) (*SearchReindexService, *mocks.MockSceneRepository) {
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	tagRepo := mocks.NewMockTagRepository(ctrl)
+	actorRepo := mocks.NewMockActorRepository(ctrl)
+	tagRepo.EXPECT().GetSceneTagsMultiple(gomock.Any()).Return(map[uint][]data.Tag{}, nil).AnyTimes()
+	actorRepo.EXPECT().GetSceneActorsMultiple(gomock.Any()).Return(map[uint][]data.Actor{}, nil).AnyTimes()
+
+	svc := NewSearchReindexService(nil, sceneRepo, tagRepo, actorRepo, checkpoints, nil, zap.NewNop())
+	svc.target = target
+	svc.batchSize = 2
+	return svc, sceneRepo
+}
+
+func TestSearchReindex_FullRun(t *testing.T) {
+	target := &fakeReindexTarget{}
+	checkpoints := &fakeCheckpointRepo{}
+	svc, sceneRepo := newTestReindexService(t, target, checkpoints)
+
+	sceneRepo.EXPECT().CountActive().Return(int64(3), nil)
+	sceneRepo.EXPECT().GetBatchAfterID(uint(0), 2).Return([]data.Scene{{ID: 1}, {ID: 4}}, nil)
+	sceneRepo.EXPECT().GetBatchAfterID(uint(4), 2).Return([]data.Scene{{ID: 7}}, nil)
+	sceneRepo.EXPECT().GetBatchAfterID(uint(7), 2).Return(nil, nil)
+
+	status, err := svc.Run(context.Background(), false)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if target.cleared != 1 || len(target.indexed) != 3 {
+		t.Fatalf("expected cleared index and 3 scenes indexed, got %+v", target)
+	}
+	if status.Status != data.ReindexStatusCompleted || status.Indexed != 3 || status.Resumable {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if checkpoints.checkpoint.LastSceneID != 7 || checkpoints.checkpoint.CompletedAt == nil {
+		t.Fatalf("unexpected checkpoint %+v", checkpoints.checkpoint)
+	}
+}
+
+func TestSearchReindex_ResumeAfterFailure(t *testing.T) {
+	target := &fakeReindexTarget{failAt: 2}
+	checkpoints := &fakeCheckpointRepo{}
+	svc, sceneRepo := newTestReindexService(t, target, checkpoints)
+
+	sceneRepo.EXPECT().CountActive().Return(int64(3), nil).Times(2)
+	sceneRepo.EXPECT().GetBatchAfterID(uint(0), 2).Return([]data.Scene{{ID: 1}, {ID: 2}}, nil)
+	sceneRepo.EXPECT().GetBatchAfterID(uint(2), 2).Return([]data.Scene{{ID: 3}}, nil).Times(2)
+	sceneRepo.EXPECT().GetBatchAfterID(uint(3), 2).Return(nil, nil)
+
+	status, err := svc.Run(context.Background(), false)
+	if err == nil {
+		t.Fatal("expected the second batch to fail")
+	}
+	if status.Status != data.ReindexStatusFailed || !status.Resumable || status.LastSceneID != 2 {
+		t.Fatalf("expected resumable failure after scene 2, got %+v", status)
+	}
+
+	status, err = svc.Run(context.Background(), true)
+	if err != nil {
+		t.Fatalf("expected resume to succeed, got %v", err)
+	}
+	if target.cleared != 1 {
+		t.Fatalf("expected resume not to clear the index, cleared %d times", target.cleared)
+	}
+	if status.Status != data.ReindexStatusCompleted || status.Indexed != 3 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestSearchReindex_StatusReportsInterrupted(t *testing.T) {
+	checkpoints := &fakeCheckpointRepo{checkpoint: &data.SearchReindexCheckpoint{Status: data.ReindexStatusRunning, LastSceneID: 5}}
+	svc, _ := newTestReindexService(t, &fakeReindexTarget{}, checkpoints)
+
+	status, err := svc.Status()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.Status != ReindexStatusInterrupted || !status.Resumable {
+		t.Fatalf("expected resumable interrupted status, got %+v", status)
+	}
+}
+
+func TestSearchReindex_RejectsConcurrentRun(t *testing.T) {
+	svc, _ := newTestReindexService(t, &fakeReindexTarget{}, &fakeCheckpointRepo{})
+	svc.running = true
+
+	if _, err := svc.Run(context.Background(), false); !apperrors.IsConflict(err) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+}
+
+func TestSearchReindex_Cancelled(t *testing.T) {
+	checkpoints := &fakeCheckpointRepo{}
+	svc, sceneRepo := newTestReindexService(t, &fakeReindexTarget{}, checkpoints)
+	sceneRepo.EXPECT().CountActive().Return(int64(3), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	status, err := svc.Run(ctx, false)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context cancellation, got %v", err)
+	}
+	if status.Status != data.ReindexStatusCancelled || svc.running {
+		t.Fatalf("expected cancelled status and a released slot, got %+v", status)
+	}
+}
+
+func TestSearchReindex_IndexesMarkerSegments(t *testing.T) {
+	target := &fakeReindexTarget{}
+	svc, sceneRepo := newTestReindexService(t, target, &fakeCheckpointRepo{})
+	markerRepo := mocks.NewMockMarkerRepository(gomock.NewController(t))
+	svc.markerRepo = markerRepo
+
+	sceneRepo.EXPECT().CountActive().Return(int64(1), nil)
+	sceneRepo.EXPECT().GetBatchAfterID(uint(0), 2).Return([]data.Scene{{ID: 1}}, nil)
+	sceneRepo.EXPECT().GetBatchAfterID(uint(1), 2).Return(nil, nil)
+	markerRepo.EXPECT().GetLabeledBySceneIDs([]uint{1}).Return([]data.UserSceneMarker{
+		{ID: 5, UserID: 2, SceneID: 1, Label: "kitchen", Timestamp: 95},
+	}, nil)
+
+	if _, err := svc.Run(context.Background(), false); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(target.segments) != 1 {
+		t.Fatalf("expected one segment, got %+v", target.segments)
+	}
+	seg := target.segments[0]
+	if seg.ID != "marker-5" || seg.UserID != 2 || seg.SceneID != 1 || seg.Text != "kitchen" || seg.Timestamp != 95 {
+		t.Fatalf("unexpected segment %+v", seg)
+	}
+}
@@ -1,20 +1,43 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
+	"goonhub/internal/apperrors"
 	"goonhub/internal/data"
 	"goonhub/internal/infrastructure/meilisearch"
 )
 
 // SearchResult contains the result of a search query.
 type SearchResult struct {
-	Scenes []data.Scene
-	Total  int64
-	Seed   int64 // Non-zero only for random sort
+	Scenes     []data.Scene
+	Total      int64  // Not counted when paging by cursor
+	Seed       int64  // Non-zero only for random sort
+	NextCursor string // Cursor paging only: empty on the last page
+}
+
+// errSceneCursorSort is returned when paging by cursor with a sort that has no
+// numeric key to page by.
+var errSceneCursorSort = apperrors.NewValidationErrorWithField("cursor", "cursor pagination supports the created_at, duration and view_count sorts")
+
+// ResolutionHeights maps resolution filter values to the [min, max] scene
+// height they cover (0 = unbounded).
+var ResolutionHeights = map[string][2]int{
+	"4k":    {2160, 0},
+	"1440p": {1440, 2159},
+	"1080p": {1080, 1439},
+	"720p":  {720, 1079},
+	"480p":  {480, 719},
+	"360p":  {0, 479},
 }
 
 // SceneIndexer defines the interface for scene search indexing operations.
@@ -28,22 +51,39 @@ type SceneIndexer interface {
 	BulkDeleteSceneIndex(ids []uint) error
 }
 
-// SearchService orchestrates search operations using Meilisearch.
+// meilisearchRetryInterval is how long auto mode sends searches straight to
+// PostgreSQL after a Meilisearch search failed, before trying Meilisearch again
+const meilisearchRetryInterval = 30 * time.Second
+
+// SearchService orchestrates search operations through a SearchBackend.
 // User-specific filters (liked, rating, jizz_count, marker_labels) are handled by pre-querying
-// PostgreSQL for matching scene IDs, then passing those as filters to Meilisearch.
+// PostgreSQL for matching scene IDs, then passing those as filters to the backend.
+// Index maintenance only applies to Meilisearch; the PostgreSQL backend reads the
+// scenes table directly.
 type SearchService struct {
 	meiliClient     *meilisearch.Client
+	primary         SearchBackend // Meilisearch, nil when not used
+	fallback        SearchBackend // PostgreSQL, nil when not used
+	segments        segmentIndex  // Meilisearch segments index, nil when not used
 	sceneRepo       data.SceneRepository
 	interactionRepo data.InteractionRepository
 	tagRepo         data.TagRepository
 	actorRepo       data.ActorRepository
 	markerRepo      data.MarkerRepository
+	restrictionRepo data.ContentRestrictionRepository
+	studioRepo      data.StudioRepository
 	logger          *zap.Logger
+
+	mu             sync.Mutex
+	meiliDownUntil time.Time
 }
 
-// NewSearchService creates a new SearchService.
+// NewSearchService creates a new SearchService. backend is one of the
+// SearchBackend* constants; an unknown value behaves like auto.
 func NewSearchService(
 	meiliClient *meilisearch.Client,
+	textSearchRepo data.SceneTextSearchRepository,
+	backend string,
 	sceneRepo data.SceneRepository,
 	interactionRepo data.InteractionRepository,
 	tagRepo data.TagRepository,
@@ -51,7 +91,7 @@ func NewSearchService(
 	markerRepo data.MarkerRepository,
 	logger *zap.Logger,
 ) *SearchService {
-	return &SearchService{
+	s := &SearchService{
 		meiliClient:     meiliClient,
 		sceneRepo:       sceneRepo,
 		interactionRepo: interactionRepo,
@@ -60,11 +100,35 @@ func NewSearchService(
 		markerRepo:      markerRepo,
 		logger:          logger,
 	}
+	if meiliClient != nil && backend != SearchBackendPostgres {
+		s.primary = &meilisearchBackend{client: meiliClient}
+		s.segments = meiliClient
+	}
+	if textSearchRepo != nil && backend != SearchBackendMeilisearch {
+		s.fallback = NewPostgresSearchBackend(textSearchRepo)
+	}
+	return s
+}
+
+// SetContentRestrictionRepository enables the Restriction search parameter.
+func (s *SearchService) SetContentRestrictionRepository(repo data.ContentRestrictionRepository) {
+	s.restrictionRepo = repo
+}
+
+// SetStudioRepository makes a studio filter include the studios under it.
+func (s *SearchService) SetStudioRepository(repo data.StudioRepository) {
+	s.studioRepo = repo
 }
 
 // Search performs a search for scenes using Meilisearch.
 func (s *SearchService) Search(params data.SceneSearchParams) (*SearchResult, error) {
-	if s.meiliClient == nil {
+	return s.SearchWithContext(context.Background(), params)
+}
+
+// SearchWithContext is Search with PostgreSQL and Meilisearch time recorded
+// against the request timings carried by ctx, if any.
+func (s *SearchService) SearchWithContext(ctx context.Context, params data.SceneSearchParams) (*SearchResult, error) {
+	if s.primary == nil && s.fallback == nil {
 		return nil, fmt.Errorf("meilisearch is not configured")
 	}
 
@@ -78,7 +142,9 @@ func (s *SearchService) Search(params data.SceneSearchParams) (*SearchResult, er
 
 	// Handle user-specific filters by pre-querying PostgreSQL for scene IDs
 	if s.hasUserFilters(params) {
+		stop := TrackTiming(ctx, TimingDB)
 		ids, err := s.getUserFilteredIDs(params)
+		stop()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get user-filtered IDs: %w", err)
 		}
@@ -97,15 +163,41 @@ func (s *SearchService) Search(params data.SceneSearchParams) (*SearchResult, er
 		}
 	}
 
+	// Handle role content restrictions by pre-querying PostgreSQL
+	if params.Restriction != nil {
+		if s.restrictionRepo == nil {
+			return nil, fmt.Errorf("content restrictions are not configured")
+		}
+		stop := TrackTiming(ctx, TimingDB)
+		visibleIDs, err := s.restrictionRepo.GetVisibleSceneIDs(params.Restriction)
+		stop()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get visible scene IDs: %w", err)
+		}
+		if len(visibleIDs) == 0 {
+			return &SearchResult{Scenes: []data.Scene{}, Total: 0}, nil
+		}
+		if len(preFilteredIDs) > 0 {
+			preFilteredIDs = intersect(preFilteredIDs, visibleIDs)
+			if len(preFilteredIDs) == 0 {
+				return &SearchResult{Scenes: []data.Scene{}, Total: 0}, nil
+			}
+		} else {
+			preFilteredIDs = visibleIDs
+		}
+	}
+
 	// Handle PornDB ID filter by pre-querying PostgreSQL
 	if params.HasPornDBID != nil {
 		var porndbIDs []uint
 		var err error
+		stop := TrackTiming(ctx, TimingDB)
 		if *params.HasPornDBID {
 			porndbIDs, err = s.sceneRepo.GetSceneIDsWithPornDBID()
 		} else {
 			porndbIDs, err = s.sceneRepo.GetSceneIDsWithoutPornDBID()
 		}
+		stop()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get PornDB scene IDs: %w", err)
 		}
@@ -122,17 +214,71 @@ func (s *SearchService) Search(params data.SceneSearchParams) (*SearchResult, er
 		}
 	}
 
-	// Build Meilisearch search params
+	// Handle corruption filter by pre-querying PostgreSQL
+	if params.IsCorrupted != nil {
+		stop := TrackTiming(ctx, TimingDB)
+		corruptionIDs, err := s.sceneRepo.GetSceneIDsByCorruption(*params.IsCorrupted)
+		stop()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get scene IDs by corruption: %w", err)
+		}
+		if len(corruptionIDs) == 0 {
+			return &SearchResult{Scenes: []data.Scene{}, Total: 0}, nil
+		}
+		if len(preFilteredIDs) > 0 {
+			preFilteredIDs = intersect(preFilteredIDs, corruptionIDs)
+			if len(preFilteredIDs) == 0 {
+				return &SearchResult{Scenes: []data.Scene{}, Total: 0}, nil
+			}
+		} else {
+			preFilteredIDs = corruptionIDs
+		}
+	}
+
+	// Handle review state filter by pre-querying PostgreSQL
+	if len(params.ReviewStates) > 0 {
+		stop := TrackTiming(ctx, TimingDB)
+		reviewIDs, err := s.sceneRepo.GetSceneIDsByReviewStates(params.ReviewStates)
+		stop()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get scene IDs by review state: %w", err)
+		}
+		if len(reviewIDs) == 0 {
+			return &SearchResult{Scenes: []data.Scene{}, Total: 0}, nil
+		}
+		if len(preFilteredIDs) > 0 {
+			preFilteredIDs = intersect(preFilteredIDs, reviewIDs)
+			if len(preFilteredIDs) == 0 {
+				return &SearchResult{Scenes: []data.Scene{}, Total: 0}, nil
+			}
+		} else {
+			preFilteredIDs = reviewIDs
+		}
+	}
+
+	// Build backend search params
+	params.Actors = s.resolveActorAliases(params.Actors)
 	meiliParams := s.buildMeiliParams(params, preFilteredIDs)
+	meiliParams.SubStudios = s.childStudioNames(params.Studio)
 
 	if isRandomSort {
 		meiliParams.FetchAllIDs = true
 	}
 
-	// Perform Meilisearch search
-	result, err := s.meiliClient.Search(meiliParams)
+	var cursorSort string
+	if params.UseCursor {
+		var err error
+		if cursorSort, err = applySceneCursor(&meiliParams, params); err != nil {
+			return nil, err
+		}
+	}
+
+	// Perform the backend search
+	stopSearch := TrackTiming(ctx, TimingSearch)
+	result, err := s.backendSearch(meiliParams)
+	stopSearch()
 	if err != nil {
-		return nil, fmt.Errorf("meilisearch search failed: %w", err)
+		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
 	// If no results, return empty
@@ -142,22 +288,94 @@ func (s *SearchService) Search(params data.SceneSearchParams) (*SearchResult, er
 
 	// For random sort, shuffle all IDs and paginate in Go
 	if isRandomSort {
-		return s.handleRandomSort(result.IDs, params)
+		return s.handleRandomSort(ctx, result.IDs, params)
 	}
 
 	// Fetch full scene records from PostgreSQL
+	stopDB := TrackTiming(ctx, TimingDB)
 	scenes, err := s.sceneRepo.GetByIDs(result.IDs)
+	stopDB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch scenes by IDs: %w", err)
 	}
 
+	if params.UseCursor {
+		searchResult := &SearchResult{Scenes: scenes}
+		if result.Next != nil {
+			searchResult.NextCursor = data.NewCursor(cursorSort, result.Next.Value, result.Next.ID).Encode()
+		}
+		return searchResult, nil
+	}
 	return &SearchResult{Scenes: scenes, Total: result.TotalCount}, nil
 }
 
+// applySceneCursor switches the backend search to keyset paging from the
+// params' cursor and returns the sort the cursor is tied to.
+func applySceneCursor(meiliParams *meilisearch.SearchParams, params data.SceneSearchParams) (string, error) {
+	switch meiliParams.Sort {
+	case "created_at", "duration", "view_count":
+	default:
+		return "", errSceneCursorSort
+	}
+	if params.Sort == "random" {
+		return "", errSceneCursorSort
+	}
+
+	sort := meiliParams.Sort + "_" + meiliParams.SortDir
+	cursor, err := data.DecodeCursor(params.Cursor, sort)
+	if err != nil {
+		return "", apperrors.NewValidationErrorWithField("cursor", "invalid cursor")
+	}
+	meiliParams.Keyset = true
+	if cursor != nil {
+		value, err := cursor.FloatValue()
+		if err != nil {
+			return "", apperrors.NewValidationErrorWithField("cursor", "invalid cursor")
+		}
+		meiliParams.After = &meilisearch.SortKey{Value: value, ID: cursor.ID}
+	}
+	return sort, nil
+}
+
+// backendSearch runs the search on Meilisearch, falling back to PostgreSQL in
+// auto mode when Meilisearch fails. After a failure, Meilisearch is skipped for
+// meilisearchRetryInterval so every search doesn't wait for it to time out.
+func (s *SearchService) backendSearch(params meilisearch.SearchParams) (*meilisearch.SearchResult, error) {
+	if s.primary != nil && (s.fallback == nil || s.meilisearchUp()) {
+		result, err := s.primary.Search(params)
+		if err == nil || s.fallback == nil {
+			return result, err
+		}
+		s.logger.Warn("meilisearch search failed, falling back to postgres", zap.Error(err))
+		s.mu.Lock()
+		s.meiliDownUntil = time.Now().Add(meilisearchRetryInterval)
+		s.mu.Unlock()
+	}
+	return s.fallback.Search(params)
+}
+
+func (s *SearchService) meilisearchUp() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().After(s.meiliDownUntil)
+}
+
+// ActiveBackend returns the name of the backend the next search will use, or
+// an empty string if search is not configured.
+func (s *SearchService) ActiveBackend() string {
+	if s.primary != nil && (s.fallback == nil || s.meilisearchUp()) {
+		return s.primary.Name()
+	}
+	if s.fallback != nil {
+		return s.fallback.Name()
+	}
+	return ""
+}
+
 // handleRandomSort deterministically selects a random page of IDs and returns the matching scenes.
 // Uses a virtual Fisher-Yates shuffle that only performs offset+limit iterations instead of
 // shuffling the entire array, achieving O(offset+limit) time complexity instead of O(n).
-func (s *SearchService) handleRandomSort(allIDs []uint, params data.SceneSearchParams) (*SearchResult, error) {
+func (s *SearchService) handleRandomSort(ctx context.Context, allIDs []uint, params data.SceneSearchParams) (*SearchResult, error) {
 	seed := params.Seed
 	if seed == 0 {
 		// Generate a random seed within JavaScript's Number.MAX_SAFE_INTEGER (2^53 - 1)
@@ -169,6 +387,9 @@ func (s *SearchService) handleRandomSort(allIDs []uint, params data.SceneSearchP
 	total := int64(n)
 
 	offset := (params.Page - 1) * params.Limit
+	if params.Offset > 0 {
+		offset = params.Offset
+	}
 	if offset >= n {
 		return &SearchResult{Scenes: []data.Scene{}, Total: total, Seed: seed}, nil
 	}
@@ -207,7 +428,9 @@ func (s *SearchService) handleRandomSort(allIDs []uint, params data.SceneSearchP
 	}
 
 	// Fetch full scene records from PostgreSQL
+	stop := TrackTiming(ctx, TimingDB)
 	scenes, err := s.sceneRepo.GetByIDs(pageIDs)
+	stop()
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch scenes by IDs: %w", err)
 	}
@@ -215,6 +438,81 @@ func (s *SearchService) handleRandomSort(allIDs []uint, params data.SceneSearchP
 	return &SearchResult{Scenes: scenes, Total: total, Seed: seed}, nil
 }
 
+// RandomScene picks one random scene matching params. The same seed picks the
+// same scene as long as the matching set does not change; a zero seed picks a
+// fresh one. Returns a not found error when nothing matches.
+func (s *SearchService) RandomScene(ctx context.Context, params data.SceneSearchParams) (*data.Scene, int64, error) {
+	params.Sort = "random"
+	params.Page = 1
+	params.Limit = 1
+	params.Offset = 0
+
+	result, err := s.SearchWithContext(ctx, params)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to search scenes", err)
+	}
+	if len(result.Scenes) == 0 {
+		return nil, 0, apperrors.NewNotFoundError("matching scene", "random")
+	}
+	return &result.Scenes[0], result.Seed, nil
+}
+
+// ShuffleBatch is the next part of a shuffle queue.
+type ShuffleBatch struct {
+	Scenes     []data.Scene
+	Total      int64
+	Seed       int64
+	NextOffset int  // offset of the following batch
+	Done       bool // every matching scene has been handed out
+}
+
+const (
+	defaultShuffleBatch = 20
+	maxShuffleBatch     = 100
+)
+
+// ShuffleQueue returns count scenes of the seeded shuffle of the scenes matching
+// params, starting at offset. The seed and offset are the whole queue state:
+// clients keep the seed of the first batch and pass NextOffset to walk the
+// shuffle without repeats. A zero seed starts a new shuffle.
+func (s *SearchService) ShuffleQueue(ctx context.Context, params data.SceneSearchParams, offset, count int) (*ShuffleBatch, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if count <= 0 {
+		count = defaultShuffleBatch
+	}
+	if count > maxShuffleBatch {
+		count = maxShuffleBatch
+	}
+	params.Sort = "random"
+	params.Page = 1
+	params.Limit = count
+	params.Offset = offset
+
+	result, err := s.SearchWithContext(ctx, params)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to search scenes", err)
+	}
+
+	// Trashed scenes drop out when the page is loaded, so the next offset
+	// follows the shuffle position rather than the number of scenes returned
+	next := offset + count
+	if int64(next) > result.Total {
+		next = int(result.Total)
+	}
+	if next < offset {
+		next = offset
+	}
+	return &ShuffleBatch{
+		Scenes:     result.Scenes,
+		Total:      result.Total,
+		Seed:       result.Seed,
+		NextOffset: next,
+		Done:       int64(next) >= result.Total,
+	}, nil
+}
+
 // hasUserFilters returns true if the params include user-specific filters.
 func (s *SearchService) hasUserFilters(params data.SceneSearchParams) bool {
 	if params.UserID == 0 {
@@ -308,11 +606,62 @@ func (s *SearchService) getUserFilteredIDs(params data.SceneSearchParams) ([]uin
 	return result, nil
 }
 
-// buildMeiliParams converts SceneSearchParams to Meilisearch SearchParams.
+// resolveActorAliases replaces actor names that are another actor's alias,
+// such as the name of a merged duplicate, with that actor's name. Names no
+// actor has are kept.
+func (s *SearchService) resolveActorAliases(names []string) []string {
+	if len(names) == 0 || s.actorRepo == nil {
+		return names
+	}
+	resolved := make([]string, 0, len(names))
+	for _, name := range names {
+		if actor, err := s.actorRepo.GetByName(name); err == nil {
+			name = actor.Name
+		}
+		if !slices.Contains(resolved, name) {
+			resolved = append(resolved, name)
+		}
+	}
+	return resolved
+}
+
+// childStudioNames returns the names of the studios under the named one, such
+// as a network's sites, so filtering by it includes their scenes.
+func (s *SearchService) childStudioNames(name string) []string {
+	if name == "" || s.studioRepo == nil {
+		return nil
+	}
+	studio, err := s.studioRepo.GetByName(name)
+	if err != nil {
+		return nil
+	}
+	ids, err := s.studioRepo.GetDescendantIDs(studio.ID)
+	if err != nil || len(ids) == 0 {
+		if err != nil {
+			s.logger.Warn("failed to get child studios for search", zap.Uint("studio_id", studio.ID), zap.Error(err))
+		}
+		return nil
+	}
+	children, err := s.studioRepo.GetByIDs(ids)
+	if err != nil {
+		s.logger.Warn("failed to get child studios for search", zap.Uint("studio_id", studio.ID), zap.Error(err))
+		return nil
+	}
+	names := make([]string, 0, len(children))
+	for _, child := range children {
+		if !slices.Contains(names, child.Name) && child.Name != name {
+			names = append(names, child.Name)
+		}
+	}
+	return names
+}
+
+// buildMeiliParams converts SceneSearchParams to backend SearchParams.
 func (s *SearchService) buildMeiliParams(params data.SceneSearchParams, preFilteredIDs []uint) meilisearch.SearchParams {
 	meiliParams := meilisearch.SearchParams{
 		Query:            params.Query,
 		TagIDs:           params.TagIDs,
+		ExcludeTagIDs:    params.ExcludeTagIDs,
 		Actors:           params.Actors,
 		Studio:           params.Studio,
 		SceneIDs:         preFilteredIDs,
@@ -383,14 +732,18 @@ func (s *SearchService) buildMeiliParams(params data.SceneSearchParams, preFilte
 func buildSceneDocument(scene *data.Scene, tags []data.Tag, actors []data.Actor) meilisearch.SceneDocument {
 	tagIDs := make([]uint, len(tags))
 	tagNames := make([]string, len(tags))
+	var tagAliases []string
 	for i, tag := range tags {
 		tagIDs[i] = tag.ID
 		tagNames[i] = tag.Name
+		tagAliases = append(tagAliases, tag.Aliases...)
 	}
 
 	actorNames := make([]string, len(actors))
+	var actorAliases []string
 	for i, actor := range actors {
 		actorNames[i] = actor.Name
+		actorAliases = append(actorAliases, actor.Aliases...)
 	}
 
 	return meilisearch.SceneDocument{
@@ -401,8 +754,10 @@ func buildSceneDocument(scene *data.Scene, tags []data.Tag, actors []data.Actor)
 		Description:      scene.Description,
 		Studio:           scene.Studio,
 		Actors:           actorNames,
+		ActorAliases:     actorAliases,
 		TagIDs:           tagIDs,
 		TagNames:         tagNames,
+		TagAliases:       tagAliases,
 		Duration:         float64(scene.Duration),
 		Height:           scene.Height,
 		CreatedAt:        scene.CreatedAt.Unix(),
@@ -487,81 +842,96 @@ func (s *SearchService) BulkDeleteSceneIndex(ids []uint) error {
 	return s.meiliClient.BulkDeleteScenes(ids)
 }
 
-// ReindexAll rebuilds the entire Meilisearch index from PostgreSQL.
-func (s *SearchService) ReindexAll() error {
+// UpdateMaxTotalHits updates the Meilisearch pagination maxTotalHits setting.
+func (s *SearchService) UpdateMaxTotalHits(maxTotalHits int64) error {
 	if s.meiliClient == nil {
 		return fmt.Errorf("meilisearch is not configured")
 	}
+	return s.meiliClient.UpdateMaxTotalHits(maxTotalHits)
+}
+
+// GetMaxTotalHits returns the current Meilisearch maxTotalHits setting.
+func (s *SearchService) GetMaxTotalHits() int64 {
+	if s.meiliClient == nil {
+		return 0
+	}
+	return s.meiliClient.GetMaxTotalHits()
+}
 
-	s.logger.Info("starting full reindex")
+// SearchDiagnostics compares the Meilisearch index against PostgreSQL.
+type SearchDiagnostics struct {
+	DBSceneCount  int64                   `json:"db_scene_count"` // non-trashed scenes
+	IndexStats    *meilisearch.IndexStats `json:"index"`
+	DocumentDelta int64                   `json:"document_delta"` // db_scene_count minus indexed documents
+}
+
+// SceneIndexStatus reports whether a single scene is present in the search index.
+type SceneIndexStatus struct {
+	SceneID         uint `json:"scene_id"`
+	InDatabase      bool `json:"in_database"`
+	Trashed         bool `json:"trashed"`
+	Indexed         bool `json:"indexed"`
+	ShouldBeIndexed bool `json:"should_be_indexed"`
+}
 
-	if err := s.meiliClient.ClearIndex(); err != nil {
-		return fmt.Errorf("failed to clear index: %w", err)
+// Diagnostics reports index document count vs PostgreSQL scene count, pending
+// Meilisearch tasks and the last successful and failed index writes.
+func (s *SearchService) Diagnostics() (*SearchDiagnostics, error) {
+	if s.meiliClient == nil {
+		return nil, fmt.Errorf("meilisearch is not configured")
 	}
 
-	scenes, err := s.sceneRepo.GetAll()
+	dbCount, err := s.sceneRepo.CountActive()
 	if err != nil {
-		return fmt.Errorf("failed to get all scenes: %w", err)
+		return nil, fmt.Errorf("failed to count scenes: %w", err)
 	}
 
-	batchSize := 100
-	for i := 0; i < len(scenes); i += batchSize {
-		end := i + batchSize
-		if end > len(scenes) {
-			end = len(scenes)
-		}
-		batch := scenes[i:end]
-
-		// Get scene IDs for this batch
-		batchIDs := make([]uint, len(batch))
-		for j, v := range batch {
-			batchIDs[j] = v.ID
-		}
-
-		// Fetch all tags for this batch in a single query
-		tagsByScene, err := s.tagRepo.GetSceneTagsMultiple(batchIDs)
-		if err != nil {
-			s.logger.Warn("failed to get scene tags for reindexing batch", zap.Error(err))
-			tagsByScene = make(map[uint][]data.Tag)
-		}
+	stats, err := s.meiliClient.Stats()
+	if err != nil {
+		return nil, err
+	}
 
-		// Fetch all actors for this batch in a single query
-		actorsByScene, err := s.actorRepo.GetSceneActorsMultiple(batchIDs)
-		if err != nil {
-			s.logger.Warn("failed to get scene actors for reindexing batch", zap.Error(err))
-			actorsByScene = make(map[uint][]data.Actor)
-		}
+	return &SearchDiagnostics{
+		DBSceneCount:  dbCount,
+		IndexStats:    stats,
+		DocumentDelta: dbCount - stats.DocumentCount,
+	}, nil
+}
 
-		docs := make([]meilisearch.SceneDocument, len(batch))
-		for i, scene := range batch {
-			docs[i] = buildSceneDocument(&scene, tagsByScene[scene.ID], actorsByScene[scene.ID])
-		}
+// SceneIndexStatus checks whether a scene is indexed and whether it should be.
+// Trashed scenes are removed from the index, so only live scenes are expected there.
+func (s *SearchService) SceneIndexStatus(sceneID uint) (*SceneIndexStatus, error) {
+	if s.meiliClient == nil {
+		return nil, fmt.Errorf("meilisearch is not configured")
+	}
 
-		if err := s.meiliClient.BulkIndex(docs); err != nil {
-			return fmt.Errorf("failed to bulk index batch: %w", err)
-		}
+	status := &SceneIndexStatus{SceneID: sceneID}
 
-		s.logger.Info("reindexed batch", zap.Int("start", i), zap.Int("end", end), zap.Int("total", len(scenes)))
+	scene, err := s.sceneRepo.GetByIDIncludingTrashed(sceneID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get scene: %w", err)
+	}
+	if scene != nil && err == nil {
+		status.InDatabase = true
+		status.Trashed = scene.TrashedAt != nil
+		status.ShouldBeIndexed = !status.Trashed
 	}
 
-	s.logger.Info("full reindex completed", zap.Int("total_scenes", len(scenes)))
-	return nil
-}
-
-// UpdateMaxTotalHits updates the Meilisearch pagination maxTotalHits setting.
-func (s *SearchService) UpdateMaxTotalHits(maxTotalHits int64) error {
-	if s.meiliClient == nil {
-		return fmt.Errorf("meilisearch is not configured")
+	indexed, err := s.meiliClient.HasDocument(sceneID)
+	if err != nil {
+		return nil, err
 	}
-	return s.meiliClient.UpdateMaxTotalHits(maxTotalHits)
+	status.Indexed = indexed
+
+	return status, nil
 }
 
-// GetMaxTotalHits returns the current Meilisearch maxTotalHits setting.
-func (s *SearchService) GetMaxTotalHits() int64 {
+// IndexedSceneIDs returns the IDs of every scene document in the index.
+func (s *SearchService) IndexedSceneIDs() ([]uint, error) {
 	if s.meiliClient == nil {
-		return 0
+		return nil, fmt.Errorf("meilisearch is not configured")
 	}
-	return s.meiliClient.GetMaxTotalHits()
+	return s.meiliClient.DocumentIDs()
 }
 
 // IsAvailable returns true if Meilisearch is configured and healthy.
@@ -1,8 +1,10 @@
 package core
 
 import (
+	"context"
 	"testing"
 
+	"goonhub/internal/apperrors"
 	"goonhub/internal/data"
 	"goonhub/internal/mocks"
 
@@ -14,7 +16,7 @@ func TestSearchService_Search_RequiresMeilisearch(t *testing.T) {
 	logger := zap.NewNop()
 
 	// Create search service without Meilisearch client (nil)
-	service := NewSearchService(nil, nil, nil, nil, nil, nil, logger)
+	service := NewSearchService(nil, nil, SearchBackendMeilisearch, nil, nil, nil, nil, nil, logger)
 
 	params := data.SceneSearchParams{
 		Page:  1,
@@ -191,7 +193,7 @@ func TestHandleRandomSort_SameSeedSameOrder(t *testing.T) {
 	// First call
 	ids1 := make([]uint, len(allIDs))
 	copy(ids1, allIDs)
-	result1, err := service.handleRandomSort(ids1, params)
+	result1, err := service.handleRandomSort(context.Background(), ids1, params)
 	if err != nil {
 		t.Fatalf("handleRandomSort() error: %v", err)
 	}
@@ -199,7 +201,7 @@ func TestHandleRandomSort_SameSeedSameOrder(t *testing.T) {
 	// Second call with same seed
 	ids2 := make([]uint, len(allIDs))
 	copy(ids2, allIDs)
-	result2, err := service.handleRandomSort(ids2, params)
+	result2, err := service.handleRandomSort(context.Background(), ids2, params)
 	if err != nil {
 		t.Fatalf("handleRandomSort() error: %v", err)
 	}
@@ -243,14 +245,14 @@ func TestHandleRandomSort_DifferentSeedsDifferentOrder(t *testing.T) {
 
 	ids1 := make([]uint, len(allIDs))
 	copy(ids1, allIDs)
-	result1, err := service.handleRandomSort(ids1, data.SceneSearchParams{Page: 1, Limit: 20, Seed: 42})
+	result1, err := service.handleRandomSort(context.Background(), ids1, data.SceneSearchParams{Page: 1, Limit: 20, Seed: 42})
 	if err != nil {
 		t.Fatalf("handleRandomSort() error: %v", err)
 	}
 
 	ids2 := make([]uint, len(allIDs))
 	copy(ids2, allIDs)
-	result2, err := service.handleRandomSort(ids2, data.SceneSearchParams{Page: 1, Limit: 20, Seed: 9999})
+	result2, err := service.handleRandomSort(context.Background(), ids2, data.SceneSearchParams{Page: 1, Limit: 20, Seed: 9999})
 	if err != nil {
 		t.Fatalf("handleRandomSort() error: %v", err)
 	}
@@ -295,7 +297,7 @@ func TestHandleRandomSort_PaginationNoOverlap(t *testing.T) {
 	// Page 1
 	ids1 := make([]uint, len(allIDs))
 	copy(ids1, allIDs)
-	result1, err := service.handleRandomSort(ids1, data.SceneSearchParams{Page: 1, Limit: 10, Seed: seed})
+	result1, err := service.handleRandomSort(context.Background(), ids1, data.SceneSearchParams{Page: 1, Limit: 10, Seed: seed})
 	if err != nil {
 		t.Fatalf("page 1 error: %v", err)
 	}
@@ -303,7 +305,7 @@ func TestHandleRandomSort_PaginationNoOverlap(t *testing.T) {
 	// Page 2
 	ids2 := make([]uint, len(allIDs))
 	copy(ids2, allIDs)
-	result2, err := service.handleRandomSort(ids2, data.SceneSearchParams{Page: 2, Limit: 10, Seed: seed})
+	result2, err := service.handleRandomSort(context.Background(), ids2, data.SceneSearchParams{Page: 2, Limit: 10, Seed: seed})
 	if err != nil {
 		t.Fatalf("page 2 error: %v", err)
 	}
@@ -334,7 +336,7 @@ func TestHandleRandomSort_EmptyIDs(t *testing.T) {
 		logger: zap.NewNop(),
 	}
 
-	result, err := service.handleRandomSort([]uint{}, data.SceneSearchParams{Page: 1, Limit: 10, Seed: 42})
+	result, err := service.handleRandomSort(context.Background(), []uint{}, data.SceneSearchParams{Page: 1, Limit: 10, Seed: 42})
 	if err != nil {
 		t.Fatalf("handleRandomSort() error: %v", err)
 	}
@@ -352,7 +354,7 @@ func TestHandleRandomSort_PageBeyondTotal(t *testing.T) {
 	}
 
 	allIDs := []uint{1, 2, 3, 4, 5}
-	result, err := service.handleRandomSort(allIDs, data.SceneSearchParams{Page: 10, Limit: 10, Seed: 42})
+	result, err := service.handleRandomSort(context.Background(), allIDs, data.SceneSearchParams{Page: 10, Limit: 10, Seed: 42})
 	if err != nil {
 		t.Fatalf("handleRandomSort() error: %v", err)
 	}
@@ -383,7 +385,7 @@ func TestHandleRandomSort_AutoGenerateSeed(t *testing.T) {
 	}).Times(1)
 
 	allIDs := []uint{1, 2, 3, 4, 5}
-	result, err := service.handleRandomSort(allIDs, data.SceneSearchParams{Page: 1, Limit: 10, Seed: 0})
+	result, err := service.handleRandomSort(context.Background(), allIDs, data.SceneSearchParams{Page: 1, Limit: 10, Seed: 0})
 	if err != nil {
 		t.Fatalf("handleRandomSort() error: %v", err)
 	}
@@ -400,3 +402,78 @@ func TestHandleRandomSort_AutoGenerateSeed(t *testing.T) {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestSearchService_ShuffleQueueWalksWithoutRepeats(t *testing.T) {
+	svc, textRepo, sceneRepo := newTestBackendSearchService(t, SearchBackendPostgres)
+
+	allIDs := []uint{1, 2, 3, 4, 5, 6, 7}
+	textRepo.EXPECT().Search(gomock.Any()).DoAndReturn(func(q data.SceneTextQuery) ([]uint, int64, error) {
+		if !q.AllIDs {
+			t.Fatal("expected a shuffle to fetch every matching ID")
+		}
+		return append([]uint(nil), allIDs...), int64(len(allIDs)), nil
+	}).Times(3)
+	sceneRepo.EXPECT().GetByIDs(gomock.Any()).DoAndReturn(func(ids []uint) ([]data.Scene, error) {
+		scenes := make([]data.Scene, len(ids))
+		for i, id := range ids {
+			scenes[i].ID = id
+		}
+		return scenes, nil
+	}).Times(3)
+
+	seen := make(map[uint]bool)
+	seed := int64(0)
+	offset := 0
+	for i := 0; i < 3; i++ {
+		batch, err := svc.ShuffleQueue(context.Background(), data.SceneSearchParams{Seed: seed}, offset, 3)
+		if err != nil {
+			t.Fatalf("batch %d: expected no error, got %v", i, err)
+		}
+		if i == 0 {
+			seed = batch.Seed
+		} else if batch.Seed != seed {
+			t.Fatalf("expected the seed to be kept, got %d and %d", seed, batch.Seed)
+		}
+		for _, scene := range batch.Scenes {
+			if seen[scene.ID] {
+				t.Fatalf("scene %d handed out twice", scene.ID)
+			}
+			seen[scene.ID] = true
+		}
+		if batch.Done != (i == 2) {
+			t.Fatalf("batch %d: unexpected done %v", i, batch.Done)
+		}
+		offset = batch.NextOffset
+	}
+	if len(seen) != len(allIDs) || offset != len(allIDs) {
+		t.Fatalf("expected every scene once, got %d scenes ending at offset %d", len(seen), offset)
+	}
+}
+
+func TestSearchService_RandomSceneNoMatches(t *testing.T) {
+	svc, textRepo, _ := newTestBackendSearchService(t, SearchBackendPostgres)
+	textRepo.EXPECT().Search(gomock.Any()).Return([]uint{}, int64(0), nil)
+
+	if _, _, err := svc.RandomScene(context.Background(), data.SceneSearchParams{Query: "nothing"}); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestSearchService_childStudioNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	studioRepo := mocks.NewMockStudioRepository(ctrl)
+	service := NewSearchService(nil, nil, SearchBackendPostgres, nil, nil, nil, nil, nil, zap.NewNop())
+	service.SetStudioRepository(studioRepo)
+
+	studioRepo.EXPECT().GetByName("Network").Return(&data.Studio{ID: 1, Name: "Network"}, nil)
+	studioRepo.EXPECT().GetDescendantIDs(uint(1)).Return([]uint{2, 3}, nil)
+	studioRepo.EXPECT().GetByIDs([]uint{2, 3}).Return([]data.Studio{{ID: 2, Name: "Site A"}, {ID: 3, Name: "Site B"}}, nil)
+
+	names := service.childStudioNames("Network")
+	if len(names) != 2 || names[0] != "Site A" || names[1] != "Site B" {
+		t.Fatalf("expected the network's sites, got %v", names)
+	}
+	if names := service.childStudioNames(""); names != nil {
+		t.Fatalf("expected no studios without a studio filter, got %v", names)
+	}
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/infrastructure/meilisearch"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSegmentSearchLimit = 50
+	maxSegmentSearchLimit     = 200
+)
+
+// MarkerIndexer keeps marker labels searchable as scene segments.
+type MarkerIndexer interface {
+	IndexMarker(marker *data.UserSceneMarker) error
+	DeleteMarkerIndex(id uint) error
+}
+
+// segmentIndex is the part of the Meilisearch client segment search reads.
+type segmentIndex interface {
+	SearchSegments(params meilisearch.SegmentSearchParams) ([]meilisearch.SegmentDocument, error)
+}
+
+// SegmentMatch is a point in a scene whose text matched a segment search.
+type SegmentMatch struct {
+	Kind      string `json:"kind"`
+	MarkerID  uint   `json:"marker_id,omitempty"`
+	Text      string `json:"text"`
+	Timestamp int    `json:"timestamp"` // seconds
+}
+
+// SceneSegmentMatches groups the segment matches of one scene in timestamp order.
+type SceneSegmentMatches struct {
+	Scene   data.Scene
+	Matches []SegmentMatch
+}
+
+func markerSegmentID(markerID uint) string {
+	return fmt.Sprintf("%s-%d", meilisearch.SegmentKindMarker, markerID)
+}
+
+func buildMarkerSegment(marker *data.UserSceneMarker) meilisearch.SegmentDocument {
+	return meilisearch.SegmentDocument{
+		ID:        markerSegmentID(marker.ID),
+		SceneID:   marker.SceneID,
+		UserID:    marker.UserID,
+		Kind:      meilisearch.SegmentKindMarker,
+		SourceID:  marker.ID,
+		Text:      marker.Label,
+		Timestamp: marker.Timestamp,
+	}
+}
+
+// IndexMarker adds or updates a marker in the segments index. Markers without a
+// label have nothing to search and are removed instead.
+func (s *SearchService) IndexMarker(marker *data.UserSceneMarker) error {
+	if s.meiliClient == nil {
+		return nil
+	}
+	if marker.Label == "" {
+		return s.meiliClient.DeleteSegment(markerSegmentID(marker.ID))
+	}
+	return s.meiliClient.IndexSegments([]meilisearch.SegmentDocument{buildMarkerSegment(marker)})
+}
+
+// DeleteMarkerIndex removes a marker from the segments index.
+func (s *SearchService) DeleteMarkerIndex(id uint) error {
+	if s.meiliClient == nil {
+		return nil
+	}
+	return s.meiliClient.DeleteSegment(markerSegmentID(id))
+}
+
+// SearchSegments finds marker labels (and, once indexed, subtitle text) matching
+// query and returns them grouped by scene, scenes with the best match first.
+// Only the user's own markers and shared segments are searched. Like scene
+// search, it falls back to PostgreSQL in auto mode while Meilisearch is down.
+// A non-nil restriction drops scenes outside the part of the library it allows.
+func (s *SearchService) SearchSegments(ctx context.Context, userID uint, restriction *data.ContentRestriction, query string, limit int) ([]SceneSegmentMatches, error) {
+	if query == "" {
+		return nil, apperrors.NewValidationError("query is required")
+	}
+	if limit <= 0 {
+		limit = defaultSegmentSearchLimit
+	}
+	if limit > maxSegmentSearchLimit {
+		limit = maxSegmentSearchLimit
+	}
+
+	stopSearch := TrackTiming(ctx, TimingSearch)
+	docs, err := s.segmentSearch(meilisearch.SegmentSearchParams{Query: query, UserID: userID, Limit: limit})
+	stopSearch()
+	if err != nil {
+		return nil, apperrors.NewInternalError("segment search failed", err)
+	}
+	if len(docs) == 0 {
+		return []SceneSegmentMatches{}, nil
+	}
+
+	var sceneIDs []uint
+	bySceneID := make(map[uint][]SegmentMatch)
+	for _, doc := range docs {
+		if _, ok := bySceneID[doc.SceneID]; !ok {
+			sceneIDs = append(sceneIDs, doc.SceneID)
+		}
+		match := SegmentMatch{Kind: doc.Kind, Text: doc.Text, Timestamp: doc.Timestamp}
+		if doc.Kind == meilisearch.SegmentKindMarker {
+			match.MarkerID = doc.SourceID
+		}
+		bySceneID[doc.SceneID] = append(bySceneID[doc.SceneID], match)
+	}
+
+	if restriction != nil {
+		if s.restrictionRepo == nil {
+			return nil, fmt.Errorf("content restrictions are not configured")
+		}
+		stop := TrackTiming(ctx, TimingDB)
+		sceneIDs, err = s.restrictionRepo.FilterVisibleSceneIDs(restriction, sceneIDs)
+		stop()
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to filter visible scenes", err)
+		}
+		if len(sceneIDs) == 0 {
+			return []SceneSegmentMatches{}, nil
+		}
+	}
+
+	stopDB := TrackTiming(ctx, TimingDB)
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	stopDB()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to fetch scenes", err)
+	}
+
+	// Segments outlive their scene in the index until the next rebuild; GetByIDs
+	// drops deleted and trashed scenes
+	results := make([]SceneSegmentMatches, 0, len(scenes))
+	for _, scene := range scenes {
+		matches := bySceneID[scene.ID]
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Timestamp < matches[j].Timestamp })
+		results = append(results, SceneSegmentMatches{Scene: scene, Matches: matches})
+	}
+	return results, nil
+}
+
+// segmentSearch picks the backend the same way scene search does.
+func (s *SearchService) segmentSearch(params meilisearch.SegmentSearchParams) ([]meilisearch.SegmentDocument, error) {
+	if s.segments != nil && (s.fallback == nil || s.meilisearchUp()) {
+		docs, err := s.segments.SearchSegments(params)
+		if err == nil || s.fallback == nil {
+			return docs, err
+		}
+		s.logger.Warn("meilisearch segment search failed, falling back to postgres", zap.Error(err))
+		s.mu.Lock()
+		s.meiliDownUntil = time.Now().Add(meilisearchRetryInterval)
+		s.mu.Unlock()
+	}
+	if s.fallback == nil {
+		return nil, fmt.Errorf("search is not configured")
+	}
+
+	markers, err := s.markerRepo.SearchLabels(params.UserID, params.Query, params.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("postgres segment search failed: %w", err)
+	}
+	docs := make([]meilisearch.SegmentDocument, len(markers))
+	for i := range markers {
+		docs[i] = buildMarkerSegment(&markers[i])
+	}
+	return docs, nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/infrastructure/meilisearch"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type fakeSegmentIndex struct {
+	err    error
+	docs   []meilisearch.SegmentDocument
+	params meilisearch.SegmentSearchParams
+}
+
+func (f *fakeSegmentIndex) SearchSegments(params meilisearch.SegmentSearchParams) ([]meilisearch.SegmentDocument, error) {
+	f.params = params
+	return f.docs, f.err
+}
+
+func newTestSegmentSearchService(t *testing.T, backend string) (*SearchService, *mocks.MockMarkerRepository, *mocks.MockSceneRepository) {
+	ctrl := gomock.NewController(t)
+	textRepo := mocks.NewMockSceneTextSearchRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	markerRepo := mocks.NewMockMarkerRepository(ctrl)
+	svc := NewSearchService(nil, textRepo, backend, sceneRepo, nil, nil, nil, markerRepo, zap.NewNop())
+	return svc, markerRepo, sceneRepo
+}
+
+func TestSearchSegments_GroupsMatchesByScene(t *testing.T) {
+	svc, _, sceneRepo := newTestSegmentSearchService(t, SearchBackendAuto)
+	index := &fakeSegmentIndex{docs: []meilisearch.SegmentDocument{
+		{SceneID: 4, Kind: meilisearch.SegmentKindMarker, SourceID: 10, Text: "kitchen", Timestamp: 300},
+		{SceneID: 2, Kind: meilisearch.SegmentKindMarker, SourceID: 11, Text: "kitchen table", Timestamp: 12},
+		{SceneID: 4, Kind: meilisearch.SegmentKindMarker, SourceID: 12, Text: "kitchen", Timestamp: 60},
+	}}
+	svc.segments = index
+	sceneRepo.EXPECT().GetByIDs([]uint{4, 2}).Return([]data.Scene{{ID: 4}, {ID: 2}}, nil)
+
+	results, err := svc.SearchSegments(context.Background(), 3, nil, "kitchen", 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if index.params.UserID != 3 || index.params.Limit != defaultSegmentSearchLimit {
+		t.Fatalf("unexpected search params %+v", index.params)
+	}
+	if len(results) != 2 || results[0].Scene.ID != 4 || results[1].Scene.ID != 2 {
+		t.Fatalf("expected scenes in best match order, got %+v", results)
+	}
+	matches := results[0].Matches
+	if len(matches) != 2 || matches[0].Timestamp != 60 || matches[0].MarkerID != 12 || matches[1].Timestamp != 300 {
+		t.Fatalf("expected matches in timestamp order, got %+v", matches)
+	}
+}
+
+func TestSearchSegments_FallsBackToPostgres(t *testing.T) {
+	svc, markerRepo, sceneRepo := newTestSegmentSearchService(t, SearchBackendAuto)
+	svc.segments = &fakeSegmentIndex{err: errors.New("connection refused")}
+
+	markerRepo.EXPECT().SearchLabels(uint(3), "kitchen", 20).Return([]data.UserSceneMarker{
+		{ID: 7, UserID: 3, SceneID: 1, Label: "Kitchen", Timestamp: 42},
+	}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1}).Return([]data.Scene{{ID: 1}}, nil)
+
+	results, err := svc.SearchSegments(context.Background(), 3, nil, "kitchen", 20)
+	if err != nil {
+		t.Fatalf("expected fallback to succeed, got %v", err)
+	}
+	if len(results) != 1 || results[0].Matches[0].MarkerID != 7 || results[0].Matches[0].Timestamp != 42 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if svc.ActiveBackend() != SearchBackendPostgres {
+		t.Fatalf("expected postgres while meilisearch is down, got %q", svc.ActiveBackend())
+	}
+}
+
+func TestSearchSegments_DropsMissingScenes(t *testing.T) {
+	svc, markerRepo, sceneRepo := newTestSegmentSearchService(t, SearchBackendPostgres)
+	markerRepo.EXPECT().SearchLabels(uint(1), "kitchen", maxSegmentSearchLimit).Return([]data.UserSceneMarker{
+		{ID: 1, SceneID: 8, Label: "kitchen"},
+	}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{8}).Return([]data.Scene{}, nil)
+
+	results, err := svc.SearchSegments(context.Background(), 1, nil, "kitchen", 1000)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected trashed scene to be dropped, got %+v", results)
+	}
+}
+
+func TestSearchSegments_DropsRestrictedScenes(t *testing.T) {
+	svc, markerRepo, sceneRepo := newTestSegmentSearchService(t, SearchBackendPostgres)
+	restrictionRepo := mocks.NewMockContentRestrictionRepository(gomock.NewController(t))
+	svc.SetContentRestrictionRepository(restrictionRepo)
+	restriction := &data.ContentRestriction{RoleName: "guest", TagIDs: []int64{3}}
+
+	markerRepo.EXPECT().SearchLabels(uint(1), "kitchen", 20).Return([]data.UserSceneMarker{
+		{ID: 1, SceneID: 8, Label: "kitchen"},
+		{ID: 2, SceneID: 9, Label: "kitchen"},
+	}, nil)
+	restrictionRepo.EXPECT().FilterVisibleSceneIDs(restriction, []uint{8, 9}).Return([]uint{9}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{9}).Return([]data.Scene{{ID: 9}}, nil)
+
+	results, err := svc.SearchSegments(context.Background(), 1, restriction, "kitchen", 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 1 || results[0].Scene.ID != 9 {
+		t.Fatalf("expected only the visible scene, got %+v", results)
+	}
+}
+
+func TestSearchSegments_RequiresQuery(t *testing.T) {
+	svc, _, _ := newTestSegmentSearchService(t, SearchBackendAuto)
+	if _, err := svc.SearchSegments(context.Background(), 1, nil, "", 10); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"errors"
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// sessionSeenInterval is how often a session's last_seen_at is written while it is in use
+	sessionSeenInterval = time.Minute
+	// maxSessionUserAgentLength matches the user_sessions.user_agent column
+	maxSessionUserAgentLength = 512
+)
+
+// SessionInfo is an active session as listed to its owner
+type SessionInfo struct {
+	data.UserSession
+	// Current is set for the session the listing request was made with
+	Current bool `json:"current"`
+}
+
+// SessionService records the session tokens issued at login so users can list
+// where they are signed in and revoke sessions. Revocation goes through
+// AuthService, which adds the token to revoked_tokens. Tokens issued before
+// sessions were tracked are not listed and can only be revoked by logging out.
+type SessionService struct {
+	repo        data.UserSessionRepository
+	authService *AuthService
+	logger      *zap.Logger
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+	now      func() time.Time
+}
+
+// NewSessionService creates a SessionService and registers it with the auth
+// service as its session tracker
+func NewSessionService(repo data.UserSessionRepository, authService *AuthService, logger *zap.Logger) *SessionService {
+	s := &SessionService{
+		repo:        repo,
+		authService: authService,
+		logger:      logger,
+		lastSeen:    make(map[string]time.Time),
+		now:         time.Now,
+	}
+	authService.SetSessionTracker(s)
+	return s
+}
+
+// Track records a session token just issued to a client. Failures are logged:
+// the login itself has already succeeded.
+func (s *SessionService) Track(token, userAgent, ipAddress string) {
+	if s == nil {
+		return
+	}
+	payload, err := s.authService.decodeToken(token)
+	if err != nil {
+		s.logger.Warn("Failed to decode session token for tracking", zap.Error(err))
+		return
+	}
+
+	if len(userAgent) > maxSessionUserAgentLength {
+		userAgent = userAgent[:maxSessionUserAgentLength]
+	}
+	now := s.now()
+	tokenHash := s.authService.hashToken(token)
+	session := &data.UserSession{
+		UserID:     payload.UserID,
+		TokenHash:  tokenHash,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  time.Unix(payload.ExpiresAt, 0),
+	}
+	if err := s.repo.Create(session); err != nil {
+		s.logger.Warn("Failed to record session", zap.Uint("user_id", payload.UserID), zap.Error(err))
+		return
+	}
+
+	s.mu.Lock()
+	s.lastSeen[tokenHash] = now
+	for hash, seen := range s.lastSeen {
+		if now.Sub(seen) > s.authService.tokenTTL {
+			delete(s.lastSeen, hash)
+		}
+	}
+	s.mu.Unlock()
+
+	if _, err := s.repo.DeleteExpired(now); err != nil {
+		s.logger.Warn("Failed to delete expired sessions", zap.Error(err))
+	}
+}
+
+// Seen records that a session token was used, at most once a minute per session.
+func (s *SessionService) Seen(tokenHash string) {
+	now := s.now()
+	s.mu.Lock()
+	if last, ok := s.lastSeen[tokenHash]; ok && now.Sub(last) < sessionSeenInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSeen[tokenHash] = now
+	s.mu.Unlock()
+
+	if err := s.repo.TouchLastSeen(tokenHash, now); err != nil {
+		s.logger.Warn("Failed to record session use", zap.Error(err))
+	}
+}
+
+// Ended forgets a session whose token was revoked.
+func (s *SessionService) Ended(tokenHash string) {
+	s.mu.Lock()
+	delete(s.lastSeen, tokenHash)
+	s.mu.Unlock()
+
+	if err := s.repo.DeleteByTokenHash(tokenHash); err != nil {
+		s.logger.Warn("Failed to delete ended session", zap.Error(err))
+	}
+}
+
+// List returns the user's active sessions, marking the one made with currentToken.
+func (s *SessionService) List(userID uint, currentToken string) ([]SessionInfo, error) {
+	sessions, err := s.repo.ListActiveByUser(userID, s.now())
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list sessions", err)
+	}
+
+	currentHash := s.authService.hashToken(currentToken)
+	infos := make([]SessionInfo, len(sessions))
+	for i, session := range sessions {
+		infos[i] = SessionInfo{UserSession: session, Current: session.TokenHash == currentHash}
+	}
+	return infos, nil
+}
+
+// Revoke signs one of the user's sessions out.
+func (s *SessionService) Revoke(userID, sessionID uint) error {
+	session, err := s.repo.GetByID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewNotFoundError("session", sessionID)
+		}
+		return apperrors.NewInternalError("failed to look up session", err)
+	}
+	if session.UserID != userID {
+		return apperrors.NewNotFoundError("session", sessionID)
+	}
+
+	if err := s.authService.revokeTokenHash(session.TokenHash, session.ExpiresAt, "session revoked"); err != nil {
+		return apperrors.NewInternalError("failed to revoke session", err)
+	}
+	return nil
+}
+
+// RevokeOthers signs out every session of the user except the one made with
+// currentToken, returning how many were revoked.
+func (s *SessionService) RevokeOthers(userID uint, currentToken string) (int, error) {
+	sessions, err := s.repo.ListActiveByUser(userID, s.now())
+	if err != nil {
+		return 0, apperrors.NewInternalError("failed to list sessions", err)
+	}
+
+	currentHash := s.authService.hashToken(currentToken)
+	revoked := 0
+	for _, session := range sessions {
+		if session.TokenHash == currentHash {
+			continue
+		}
+		if err := s.authService.revokeTokenHash(session.TokenHash, session.ExpiresAt, "signed out by other session"); err != nil {
+			return revoked, apperrors.NewInternalError("failed to revoke session", err)
+		}
+		revoked++
+	}
+
+	s.logger.Info("Revoked other sessions", zap.Uint("user_id", userID), zap.Int("count", revoked))
+	return revoked, nil
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestSessionService(t *testing.T) (*SessionService, *AuthService, *mocks.MockUserSessionRepository, *mocks.MockRevokedTokenRepository) {
+	authSvc, _, revokedRepo := newTestAuthService(t)
+	repo := mocks.NewMockUserSessionRepository(gomock.NewController(t))
+	return NewSessionService(repo, authSvc, zap.NewNop()), authSvc, repo, revokedRepo
+}
+
+func issueTestToken(t *testing.T, authSvc *AuthService, userID uint) string {
+	t.Helper()
+	token, err := authSvc.generateToken(&data.User{ID: userID, Username: "alice", Role: "user"})
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	return token
+}
+
+func TestSessionService_TrackRecordsSession(t *testing.T) {
+	svc, authSvc, repo, _ := newTestSessionService(t)
+	token := issueTestToken(t, authSvc, 3)
+
+	repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(session *data.UserSession) error {
+		if session.UserID != 3 || session.TokenHash != authSvc.hashToken(token) {
+			t.Fatalf("unexpected session: %+v", session)
+		}
+		if session.UserAgent != "Firefox" || session.IPAddress != "10.0.0.2" {
+			t.Fatalf("expected device metadata to be recorded, got %+v", session)
+		}
+		if session.ExpiresAt.Before(time.Now()) {
+			t.Fatalf("expected expiry from the token, got %v", session.ExpiresAt)
+		}
+		return nil
+	})
+	repo.EXPECT().DeleteExpired(gomock.Any()).Return(int64(0), nil)
+
+	svc.Track(token, "Firefox", "10.0.0.2")
+}
+
+func TestSessionService_SeenIsThrottled(t *testing.T) {
+	svc, _, repo, _ := newTestSessionService(t)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	repo.EXPECT().TouchLastSeen("hash", now).Return(nil)
+	svc.Seen("hash")
+	svc.Seen("hash")
+
+	later := now.Add(sessionSeenInterval)
+	svc.now = func() time.Time { return later }
+	repo.EXPECT().TouchLastSeen("hash", later).Return(nil)
+	svc.Seen("hash")
+}
+
+func TestSessionService_RevokeOtherUsersSessionIsNotFound(t *testing.T) {
+	svc, _, repo, _ := newTestSessionService(t)
+	repo.EXPECT().GetByID(uint(9)).Return(&data.UserSession{ID: 9, UserID: 2, TokenHash: "hash"}, nil)
+
+	err := svc.Revoke(1, 9)
+	if !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestSessionService_RevokeMissingSession(t *testing.T) {
+	svc, _, repo, _ := newTestSessionService(t)
+	repo.EXPECT().GetByID(uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	if err := svc.Revoke(1, 9); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestSessionService_RevokeAddsTokenToRevokedList(t *testing.T) {
+	svc, _, repo, revokedRepo := newTestSessionService(t)
+	expires := time.Now().Add(time.Hour)
+	repo.EXPECT().GetByID(uint(9)).Return(&data.UserSession{ID: 9, UserID: 1, TokenHash: "hash", ExpiresAt: expires}, nil)
+	revokedRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *data.RevokedToken) error {
+		if token.TokenHash != "hash" || !token.ExpiresAt.Equal(expires) {
+			t.Fatalf("unexpected revoked token: %+v", token)
+		}
+		return nil
+	})
+	repo.EXPECT().DeleteByTokenHash("hash").Return(nil)
+
+	if err := svc.Revoke(1, 9); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSessionService_RevokeOthersKeepsCurrent(t *testing.T) {
+	svc, authSvc, repo, revokedRepo := newTestSessionService(t)
+	current := issueTestToken(t, authSvc, 1)
+	currentHash := authSvc.hashToken(current)
+
+	repo.EXPECT().ListActiveByUser(uint(1), gomock.Any()).Return([]data.UserSession{
+		{ID: 1, UserID: 1, TokenHash: currentHash},
+		{ID: 2, UserID: 1, TokenHash: "other-a"},
+		{ID: 3, UserID: 1, TokenHash: "other-b"},
+	}, nil)
+	revokedRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+	repo.EXPECT().DeleteByTokenHash("other-a").Return(nil)
+	repo.EXPECT().DeleteByTokenHash("other-b").Return(nil)
+
+	revoked, err := svc.RevokeOthers(1, current)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if revoked != 2 {
+		t.Fatalf("expected 2 sessions revoked, got %d", revoked)
+	}
+}
+
+func TestSessionService_ListMarksCurrent(t *testing.T) {
+	svc, authSvc, repo, _ := newTestSessionService(t)
+	current := issueTestToken(t, authSvc, 1)
+
+	repo.EXPECT().ListActiveByUser(uint(1), gomock.Any()).Return([]data.UserSession{
+		{ID: 1, TokenHash: "other"},
+		{ID: 2, TokenHash: authSvc.hashToken(current)},
+	}, nil)
+
+	sessions, err := svc.List(1, current)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sessions[0].Current || !sessions[1].Current {
+		t.Fatalf("expected only the second session to be current, got %+v", sessions)
+	}
+}
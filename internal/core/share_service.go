@@ -1,18 +1,32 @@
 package core
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"goonhub/internal/apperrors"
 	"goonhub/internal/data"
 
 	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+const (
+	// shareAccessTTL is how long unlocking a password-protected share link lasts
+	shareAccessTTL         = 12 * time.Hour
+	minSharePasswordLength = 4
+	// maxSharePasswordLength is bcrypt's input limit
+	maxSharePasswordLength = 72
+)
+
 // ShareSceneData holds the scene data returned when resolving a share link.
 type ShareSceneData struct {
 	ID          uint       `json:"id"`
@@ -28,25 +42,40 @@ type ShareSceneData struct {
 
 // ResolvedShareLink holds the resolved share link with scene data.
 type ResolvedShareLink struct {
-	ShareLink data.ShareLink `json:"share_link"`
-	Scene     ShareSceneData `json:"scene"`
+	ShareLink    data.ShareLink `json:"share_link"`
+	Scene        ShareSceneData `json:"scene"`
+	ThumbnailURL string         `json:"thumbnail_url"`
+}
+
+// ShareAccess is the short-lived grant handed out when a password-protected share
+// link is unlocked. It only opens that link's scene data, stream and thumbnail.
+type ShareAccess struct {
+	Token     string    `json:"access_token"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type ShareService struct {
 	shareLinkRepo data.ShareLinkRepository
 	sceneRepo     data.SceneRepository
+	accessKey     []byte
 	logger        *zap.Logger
+	now           func() time.Time
 }
 
 func NewShareService(
 	shareLinkRepo data.ShareLinkRepository,
 	sceneRepo data.SceneRepository,
+	secret string,
 	logger *zap.Logger,
 ) *ShareService {
+	// Derive a dedicated key so share grants can never be confused with other tokens
+	key := sha256.Sum256([]byte("goonhub-share:" + secret))
 	return &ShareService{
 		shareLinkRepo: shareLinkRepo,
 		sceneRepo:     sceneRepo,
+		accessKey:     key[:],
 		logger:        logger,
+		now:           time.Now,
 	}
 }
 
@@ -58,8 +87,9 @@ var validExpirations = map[string]time.Duration{
 	"never": 0,
 }
 
-// CreateShareLink generates a new share link for a scene.
-func (s *ShareService) CreateShareLink(userID, sceneID uint, shareType, expiresIn string) (*data.ShareLink, error) {
+// CreateShareLink generates a new share link for a scene. An empty password
+// leaves the link unprotected.
+func (s *ShareService) CreateShareLink(userID, sceneID uint, shareType, expiresIn, password string) (*data.ShareLink, error) {
 	if !data.IsValidShareType(shareType) {
 		return nil, apperrors.NewValidationErrorWithField("share_type", fmt.Sprintf("invalid share type: %s", shareType))
 	}
@@ -69,6 +99,10 @@ func (s *ShareService) CreateShareLink(userID, sceneID uint, shareType, expiresI
 		return nil, apperrors.NewValidationErrorWithField("expires_in", fmt.Sprintf("invalid expiration: %s", expiresIn))
 	}
 
+	if password != "" && (len(password) < minSharePasswordLength || len(password) > maxSharePasswordLength) {
+		return nil, apperrors.NewValidationErrorWithField("password", fmt.Sprintf("password must be between %d and %d characters", minSharePasswordLength, maxSharePasswordLength))
+	}
+
 	// Verify scene exists
 	scene, err := s.sceneRepo.GetByID(sceneID)
 	if err != nil {
@@ -94,10 +128,19 @@ func (s *ShareService) CreateShareLink(userID, sceneID uint, shareType, expiresI
 	}
 
 	if dur > 0 {
-		exp := time.Now().Add(dur)
+		exp := s.now().Add(dur)
 		link.ExpiresAt = &exp
 	}
 
+	if password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to hash share link password", err)
+		}
+		link.PasswordHash = string(hash)
+		link.HasPassword = true
+	}
+
 	if err := s.shareLinkRepo.Create(link); err != nil {
 		return nil, apperrors.NewInternalError("failed to create share link", err)
 	}
@@ -107,6 +150,7 @@ func (s *ShareService) CreateShareLink(userID, sceneID uint, shareType, expiresI
 		zap.Uint("scene_id", sceneID),
 		zap.String("share_type", shareType),
 		zap.String("expires_in", expiresIn),
+		zap.Bool("password", password != ""),
 	)
 
 	return link, nil
@@ -118,6 +162,9 @@ func (s *ShareService) ListShareLinks(userID, sceneID uint) ([]data.ShareLink, e
 	if err != nil {
 		return nil, apperrors.NewInternalError("failed to list share links", err)
 	}
+	for i := range links {
+		links[i].HasPassword = links[i].PasswordHash != ""
+	}
 	return links, nil
 }
 
@@ -132,28 +179,47 @@ func (s *ShareService) DeleteShareLink(linkID, userID uint) error {
 	return nil
 }
 
-// ResolveShareLink resolves a token to the shared scene data.
-// It checks expiry and increments view count. For auth_required type,
-// isAuthenticated must be true or an error is returned.
-func (s *ShareService) ResolveShareLink(token string, isAuthenticated bool) (*ResolvedShareLink, error) {
-	link, err := s.shareLinkRepo.GetByToken(token)
+// AuthorizeShareLink checks that a share link may be viewed: it must exist and
+// not be expired, auth_required links need isAuthenticated, and password-protected
+// links need an access token from UnlockShareLink.
+func (s *ShareService) AuthorizeShareLink(token string, isAuthenticated bool, accessToken string) (*data.ShareLink, error) {
+	link, err := s.openShareLink(token, isAuthenticated)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, apperrors.ErrShareLinkNotFound(token)
-		}
-		return nil, apperrors.NewInternalError("failed to resolve share link", err)
+		return nil, err
 	}
+	if link.PasswordHash != "" && !s.verifyAccess(link, accessToken) {
+		return nil, apperrors.ErrShareLinkPasswordRequired
+	}
+	return link, nil
+}
 
-	// Check expiry
-	if link.ExpiresAt != nil && link.ExpiresAt.Before(time.Now()) {
-		return nil, apperrors.ErrShareLinkExpired
+// UnlockShareLink checks a password-protected share link's password and returns
+// an access token for it. The token lasts shareAccessTTL or until the link expires.
+func (s *ShareService) UnlockShareLink(token string, isAuthenticated bool, password string) (*ShareAccess, error) {
+	link, err := s.openShareLink(token, isAuthenticated)
+	if err != nil {
+		return nil, err
+	}
+	if link.PasswordHash == "" {
+		return nil, apperrors.NewValidationErrorWithField("password", "share link is not password protected")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) != nil {
+		return nil, apperrors.ErrShareLinkWrongPassword
 	}
 
-	// Check auth requirement
-	if link.ShareType == data.ShareTypeAuthRequired && !isAuthenticated {
-		return nil, apperrors.ErrShareLinkAuthRequired
+	expiresAt := s.now().Add(shareAccessTTL)
+	if link.ExpiresAt != nil && link.ExpiresAt.Before(expiresAt) {
+		expiresAt = *link.ExpiresAt
 	}
+	return &ShareAccess{
+		Token:     s.signAccess(link, expiresAt.Unix()),
+		ExpiresAt: expiresAt,
+	}, nil
+}
 
+// ResolveShareLink returns the shared scene data for an authorized share link and
+// increments its view count.
+func (s *ShareService) ResolveShareLink(link *data.ShareLink) (*ResolvedShareLink, error) {
 	// Increment view count (best-effort, don't fail on error)
 	if err := s.shareLinkRepo.IncrementViewCount(link.ID); err != nil {
 		s.logger.Warn("failed to increment share link view count",
@@ -190,25 +256,56 @@ func (s *ShareService) ResolveShareLink(token string, isAuthenticated bool) (*Re
 		sceneData.Actors = []string{}
 	}
 
-	return &ResolvedShareLink{
+	resolved := &ResolvedShareLink{
 		ShareLink: *link,
 		Scene:     sceneData,
-	}, nil
+	}
+	resolved.ShareLink.HasPassword = link.PasswordHash != ""
+	return resolved, nil
 }
 
-// GetShareLinkByToken fetches a share link by token without incrementing views.
-// Used by streaming and OG middleware.
-func (s *ShareService) GetShareLinkByToken(token string) (*data.ShareLink, error) {
+// openShareLink looks a share link up and checks its expiry and auth requirement.
+func (s *ShareService) openShareLink(token string, isAuthenticated bool) (*data.ShareLink, error) {
 	link, err := s.shareLinkRepo.GetByToken(token)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, apperrors.ErrShareLinkNotFound(token)
 		}
-		return nil, apperrors.NewInternalError("failed to get share link", err)
+		return nil, apperrors.NewInternalError("failed to resolve share link", err)
+	}
+
+	// Check expiry
+	if link.ExpiresAt != nil && link.ExpiresAt.Before(s.now()) {
+		return nil, apperrors.ErrShareLinkExpired
+	}
+
+	// Check auth requirement
+	if link.ShareType == data.ShareTypeAuthRequired && !isAuthenticated {
+		return nil, apperrors.ErrShareLinkAuthRequired
 	}
 	return link, nil
 }
 
+// signAccess returns "<expiry>.<signature>". The signature covers the link ID and
+// password hash, so a grant only opens its own link and dies with the password.
+func (s *ShareService) signAccess(link *data.ShareLink, expires int64) string {
+	mac := hmac.New(sha256.New, s.accessKey)
+	fmt.Fprintf(mac, "%d:%s:%d", link.ID, link.PasswordHash, expires)
+	return strconv.FormatInt(expires, 10) + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *ShareService) verifyAccess(link *data.ShareLink, accessToken string) bool {
+	exp, _, ok := strings.Cut(accessToken, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || s.now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(accessToken), []byte(s.signAccess(link, expires)))
+}
+
 // generateToken creates a URL-safe random token (22 characters, base64url encoding of 16 random bytes).
 func generateToken() (string, error) {
 	b := make([]byte, 16)
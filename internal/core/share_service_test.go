@@ -9,6 +9,7 @@ import (
 
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -17,7 +18,7 @@ func newTestShareService(t *testing.T) (*ShareService, *mocks.MockShareLinkRepos
 	shareLinkRepo := mocks.NewMockShareLinkRepository(ctrl)
 	sceneRepo := mocks.NewMockSceneRepository(ctrl)
 
-	svc := NewShareService(shareLinkRepo, sceneRepo, zap.NewNop())
+	svc := NewShareService(shareLinkRepo, sceneRepo, "test-secret", zap.NewNop())
 	return svc, shareLinkRepo, sceneRepo
 }
 
@@ -45,7 +46,7 @@ func TestCreateShareLink_Success(t *testing.T) {
 		return nil
 	})
 
-	link, err := svc.CreateShareLink(10, 1, "public", "24h")
+	link, err := svc.CreateShareLink(10, 1, "public", "24h", "")
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -66,7 +67,7 @@ func TestCreateShareLink_NeverExpires(t *testing.T) {
 		return nil
 	})
 
-	link, err := svc.CreateShareLink(10, 5, "auth_required", "never")
+	link, err := svc.CreateShareLink(10, 5, "auth_required", "never", "")
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -78,7 +79,7 @@ func TestCreateShareLink_NeverExpires(t *testing.T) {
 func TestCreateShareLink_InvalidShareType(t *testing.T) {
 	svc, _, _ := newTestShareService(t)
 
-	_, err := svc.CreateShareLink(10, 1, "invalid", "24h")
+	_, err := svc.CreateShareLink(10, 1, "invalid", "24h", "")
 	if err == nil {
 		t.Fatal("expected error for invalid share type")
 	}
@@ -90,7 +91,7 @@ func TestCreateShareLink_InvalidShareType(t *testing.T) {
 func TestCreateShareLink_InvalidExpiration(t *testing.T) {
 	svc, _, _ := newTestShareService(t)
 
-	_, err := svc.CreateShareLink(10, 1, "public", "99d")
+	_, err := svc.CreateShareLink(10, 1, "public", "99d", "")
 	if err == nil {
 		t.Fatal("expected error for invalid expiration")
 	}
@@ -104,7 +105,7 @@ func TestCreateShareLink_SceneNotFound(t *testing.T) {
 
 	sceneRepo.EXPECT().GetByID(uint(999)).Return(nil, gorm.ErrRecordNotFound)
 
-	_, err := svc.CreateShareLink(10, 999, "public", "24h")
+	_, err := svc.CreateShareLink(10, 999, "public", "24h", "")
 	if err == nil {
 		t.Fatal("expected error for non-existent scene")
 	}
@@ -176,7 +177,11 @@ func TestResolveShareLink_Success(t *testing.T) {
 		Studio:      "Test Studio",
 	}, nil)
 
-	resolved, err := svc.ResolveShareLink("test-token", false)
+	authorized, err := svc.AuthorizeShareLink("test-token", false, "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	resolved, err := svc.ResolveShareLink(authorized)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -188,12 +193,12 @@ func TestResolveShareLink_Success(t *testing.T) {
 	}
 }
 
-func TestResolveShareLink_NotFound(t *testing.T) {
+func TestAuthorizeShareLink_NotFound(t *testing.T) {
 	svc, shareLinkRepo, _ := newTestShareService(t)
 
 	shareLinkRepo.EXPECT().GetByToken("missing").Return(nil, gorm.ErrRecordNotFound)
 
-	_, err := svc.ResolveShareLink("missing", false)
+	_, err := svc.AuthorizeShareLink("missing", false, "")
 	if err == nil {
 		t.Fatal("expected error for non-existent token")
 	}
@@ -202,7 +207,7 @@ func TestResolveShareLink_NotFound(t *testing.T) {
 	}
 }
 
-func TestResolveShareLink_Expired(t *testing.T) {
+func TestAuthorizeShareLink_Expired(t *testing.T) {
 	svc, shareLinkRepo, _ := newTestShareService(t)
 
 	expired := time.Now().Add(-time.Hour)
@@ -216,7 +221,7 @@ func TestResolveShareLink_Expired(t *testing.T) {
 	}
 	shareLinkRepo.EXPECT().GetByToken("expired-token").Return(link, nil)
 
-	_, err := svc.ResolveShareLink("expired-token", false)
+	_, err := svc.AuthorizeShareLink("expired-token", false, "")
 	if err == nil {
 		t.Fatal("expected error for expired link")
 	}
@@ -225,7 +230,7 @@ func TestResolveShareLink_Expired(t *testing.T) {
 	}
 }
 
-func TestResolveShareLink_AuthRequired_NotAuthenticated(t *testing.T) {
+func TestAuthorizeShareLink_AuthRequired_NotAuthenticated(t *testing.T) {
 	svc, shareLinkRepo, _ := newTestShareService(t)
 
 	link := &data.ShareLink{
@@ -237,7 +242,7 @@ func TestResolveShareLink_AuthRequired_NotAuthenticated(t *testing.T) {
 	}
 	shareLinkRepo.EXPECT().GetByToken("auth-token").Return(link, nil)
 
-	_, err := svc.ResolveShareLink("auth-token", false)
+	_, err := svc.AuthorizeShareLink("auth-token", false, "")
 	if err == nil {
 		t.Fatal("expected error for unauthenticated access to auth_required link")
 	}
@@ -263,7 +268,11 @@ func TestResolveShareLink_AuthRequired_Authenticated(t *testing.T) {
 		Title: "Auth Scene",
 	}, nil)
 
-	resolved, err := svc.ResolveShareLink("auth-token", true)
+	authorized, err := svc.AuthorizeShareLink("auth-token", true, "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	resolved, err := svc.ResolveShareLink(authorized)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -271,3 +280,107 @@ func TestResolveShareLink_AuthRequired_Authenticated(t *testing.T) {
 		t.Fatalf("expected title 'Auth Scene', got %q", resolved.Scene.Title)
 	}
 }
+
+func TestCreateShareLink_WithPassword(t *testing.T) {
+	svc, shareLinkRepo, sceneRepo := newTestShareService(t)
+
+	sceneRepo.EXPECT().GetByID(uint(1)).Return(&data.Scene{ID: 1}, nil)
+	shareLinkRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(link *data.ShareLink) error {
+		if link.PasswordHash == "" || link.PasswordHash == "hunter22" {
+			t.Fatalf("expected a password hash, got %q", link.PasswordHash)
+		}
+		return nil
+	})
+
+	link, err := svc.CreateShareLink(10, 1, "public", "24h", "hunter22")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !link.HasPassword {
+		t.Fatal("expected has_password to be set")
+	}
+}
+
+func TestCreateShareLink_PasswordTooShort(t *testing.T) {
+	svc, _, _ := newTestShareService(t)
+
+	_, err := svc.CreateShareLink(10, 1, "public", "24h", "abc")
+	if !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+}
+
+func newPasswordShareLink(t *testing.T, id uint, token string) *data.ShareLink {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("hunter22"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	return &data.ShareLink{ID: id, Token: token, SceneID: 1, ShareType: "public", PasswordHash: string(hash)}
+}
+
+func TestAuthorizeShareLink_PasswordRequired(t *testing.T) {
+	svc, shareLinkRepo, _ := newTestShareService(t)
+
+	shareLinkRepo.EXPECT().GetByToken("locked").Return(newPasswordShareLink(t, 1, "locked"), nil)
+
+	_, err := svc.AuthorizeShareLink("locked", true, "")
+	if err != apperrors.ErrShareLinkPasswordRequired {
+		t.Fatalf("expected password required, got: %v", err)
+	}
+}
+
+func TestUnlockShareLink_WrongPassword(t *testing.T) {
+	svc, shareLinkRepo, _ := newTestShareService(t)
+
+	shareLinkRepo.EXPECT().GetByToken("locked").Return(newPasswordShareLink(t, 1, "locked"), nil)
+
+	_, err := svc.UnlockShareLink("locked", false, "wrong-password")
+	if err != apperrors.ErrShareLinkWrongPassword {
+		t.Fatalf("expected wrong password error, got: %v", err)
+	}
+}
+
+func TestUnlockShareLink_AccessTokenIsScopedToLink(t *testing.T) {
+	svc, shareLinkRepo, _ := newTestShareService(t)
+	link := newPasswordShareLink(t, 1, "locked")
+	other := newPasswordShareLink(t, 2, "other")
+	other.PasswordHash = link.PasswordHash
+
+	shareLinkRepo.EXPECT().GetByToken("locked").Return(link, nil).Times(2)
+	shareLinkRepo.EXPECT().GetByToken("other").Return(other, nil)
+
+	access, err := svc.UnlockShareLink("locked", false, "hunter22")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := svc.AuthorizeShareLink("locked", false, access.Token); err != nil {
+		t.Fatalf("expected access token to open the link, got: %v", err)
+	}
+	if _, err := svc.AuthorizeShareLink("other", false, access.Token); err != apperrors.ErrShareLinkPasswordRequired {
+		t.Fatalf("expected access token to be rejected for another link, got: %v", err)
+	}
+}
+
+func TestUnlockShareLink_AccessTokenExpires(t *testing.T) {
+	svc, shareLinkRepo, _ := newTestShareService(t)
+	link := newPasswordShareLink(t, 1, "locked")
+	linkExpiry := time.Now().Add(time.Hour)
+	link.ExpiresAt = &linkExpiry
+
+	shareLinkRepo.EXPECT().GetByToken("locked").Return(link, nil).Times(2)
+
+	access, err := svc.UnlockShareLink("locked", false, "hunter22")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !access.ExpiresAt.Equal(linkExpiry) {
+		t.Fatalf("expected access to end with the link, got %v", access.ExpiresAt)
+	}
+
+	svc.now = func() time.Time { return linkExpiry.Add(-time.Minute).Add(shareAccessTTL) }
+	link.ExpiresAt = nil
+	if _, err := svc.AuthorizeShareLink("locked", false, access.Token); err != apperrors.ErrShareLinkPasswordRequired {
+		t.Fatalf("expected expired access token to be rejected, got: %v", err)
+	}
+}
@@ -0,0 +1,529 @@
+package core
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// sidecarExtensions are the sidecar files looked up next to a video, in order:
+// "clip.mp4" is described by "clip.nfo", "clip.json" or "clip.xml".
+var sidecarExtensions = []string{".nfo", ".json", ".xml"}
+
+// maxSidecarSize guards against reading something that is not a sidecar.
+const maxSidecarSize = 1 << 20
+
+// sidecarDateLayouts are the release date formats accepted in sidecars.
+var sidecarDateLayouts = []string{"2006-01-02", time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+
+// SidecarMetadata is the scene metadata read from a sidecar file.
+type SidecarMetadata struct {
+	Path        string
+	Title       string
+	Description string
+	Studio      string
+	Actors      []string
+	Tags        []string
+	ReleaseDate *time.Time
+}
+
+// SidecarMetadataService reads .nfo, .json and .xml files next to videos and
+// applies their metadata to scenes during scans. New scenes are pre-populated;
+// scenes already in the library follow the configured conflict policy.
+type SidecarMetadataService struct {
+	cfg        config.SidecarConfig
+	sceneRepo  data.SceneRepository
+	studioRepo data.StudioRepository
+	tagRepo    data.TagRepository
+	actorRepo  data.ActorRepository
+	logger     *zap.Logger
+}
+
+func NewSidecarMetadataService(
+	cfg config.SidecarConfig,
+	sceneRepo data.SceneRepository,
+	studioRepo data.StudioRepository,
+	tagRepo data.TagRepository,
+	actorRepo data.ActorRepository,
+	logger *zap.Logger,
+) *SidecarMetadataService {
+	return &SidecarMetadataService{
+		cfg:        cfg,
+		sceneRepo:  sceneRepo,
+		studioRepo: studioRepo,
+		tagRepo:    tagRepo,
+		actorRepo:  actorRepo,
+		logger:     logger,
+	}
+}
+
+// Enabled reports whether sidecars are read during scans.
+func (s *SidecarMetadataService) Enabled() bool {
+	return s != nil && s.cfg.Enabled
+}
+
+// Read parses the sidecar next to videoPath. It returns nil when there is none.
+func (s *SidecarMetadataService) Read(videoPath string) (*SidecarMetadata, error) {
+	sidecarPath := findSidecar(videoPath)
+	if sidecarPath == "" {
+		return nil, nil
+	}
+
+	tree, err := parseSidecarFile(sidecarPath)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := extractSidecarMetadata(tree, s.cfg.FieldMapping)
+	meta.Path = sidecarPath
+	return meta, nil
+}
+
+// Prepare fills a scene that is about to be created. The sidecar replaces the
+// filename-derived title whatever the policy, since nothing is in the library
+// yet. Actors and tags need the scene ID; see Link.
+func (s *SidecarMetadataService) Prepare(scene *data.Scene, meta *SidecarMetadata) {
+	mergeSidecarFields(scene, meta, true)
+	if meta.Studio == "" {
+		return
+	}
+	studio, err := s.studioByName(meta.Studio)
+	if err != nil {
+		s.logger.Warn("Failed to resolve sidecar studio", zap.String("studio", meta.Studio), zap.Error(err))
+		return
+	}
+	scene.StudioID = &studio.ID
+}
+
+// Link sets the actors and tags of a newly created scene from its sidecar.
+func (s *SidecarMetadataService) Link(sceneID uint, meta *SidecarMetadata) error {
+	_, err := s.link(sceneID, meta, true)
+	return err
+}
+
+// ApplyToExisting merges a sidecar into a scene already in the library and
+// reports whether anything changed. With file_wins the sidecar overwrites the
+// scene; with db_wins it only fills fields the scene does not have yet.
+func (s *SidecarMetadataService) ApplyToExisting(scene *data.Scene, meta *SidecarMetadata) (bool, error) {
+	fileWins := s.cfg.ConflictPolicy == config.SidecarFileWins
+
+	changed := mergeSidecarFields(scene, meta, fileWins)
+	if changed {
+		if err := s.sceneRepo.UpdateDetails(scene.ID, scene.Title, scene.Description, scene.ReleaseDate); err != nil {
+			return false, fmt.Errorf("failed to update scene details: %w", err)
+		}
+	}
+
+	if meta.Studio != "" && (fileWins || scene.StudioID == nil) {
+		studio, err := s.studioByName(meta.Studio)
+		if err != nil {
+			return changed, err
+		}
+		if scene.StudioID == nil || *scene.StudioID != studio.ID {
+			if err := s.studioRepo.SetSceneStudio(scene.ID, &studio.ID); err != nil {
+				return changed, fmt.Errorf("failed to set scene studio: %w", err)
+			}
+			scene.StudioID = &studio.ID
+			changed = true
+		}
+	}
+
+	linked, err := s.link(scene.ID, meta, fileWins)
+	return changed || linked, err
+}
+
+// link replaces the scene's actors and tags with the sidecar's. Without
+// overwrite, a scene that already has actors (or tags) keeps them.
+func (s *SidecarMetadataService) link(sceneID uint, meta *SidecarMetadata, overwrite bool) (bool, error) {
+	changed := false
+
+	if len(meta.Tags) > 0 {
+		existing, err := s.tagRepo.GetSceneTags(sceneID)
+		if err != nil {
+			return changed, fmt.Errorf("failed to get scene tags: %w", err)
+		}
+		if overwrite || len(existing) == 0 {
+			ids, err := s.tagIDs(meta.Tags)
+			if err != nil {
+				return changed, err
+			}
+			current := make([]uint, len(existing))
+			for i, t := range existing {
+				current[i] = t.ID
+			}
+			if !sameIDs(current, ids) {
+				if err := s.tagRepo.SetSceneTags(sceneID, ids); err != nil {
+					return changed, fmt.Errorf("failed to set scene tags: %w", err)
+				}
+				changed = true
+			}
+		}
+	}
+
+	if len(meta.Actors) > 0 {
+		existing, err := s.actorRepo.GetSceneActors(sceneID)
+		if err != nil {
+			return changed, fmt.Errorf("failed to get scene actors: %w", err)
+		}
+		if overwrite || len(existing) == 0 {
+			ids, err := s.actorIDs(meta.Actors)
+			if err != nil {
+				return changed, err
+			}
+			current := make([]uint, len(existing))
+			for i, a := range existing {
+				current[i] = a.ID
+			}
+			if !sameIDs(current, ids) {
+				if err := s.actorRepo.SetSceneActors(sceneID, ids); err != nil {
+					return changed, fmt.Errorf("failed to set scene actors: %w", err)
+				}
+				changed = true
+			}
+		}
+	}
+
+	return changed, nil
+}
+
+// studioByName returns the studio with this name, creating it if needed.
+func (s *SidecarMetadataService) studioByName(name string) (*data.Studio, error) {
+	studio, err := s.studioRepo.GetByName(name)
+	if err == nil {
+		return studio, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get studio: %w", err)
+	}
+	studio = &data.Studio{UUID: uuid.New(), Name: name}
+	if err := s.studioRepo.Create(studio); err != nil {
+		return nil, fmt.Errorf("failed to create studio: %w", err)
+	}
+	s.logger.Info("Created studio from sidecar", zap.String("name", name))
+	return studio, nil
+}
+
+// tagIDs resolves tag names, creating the missing tags.
+func (s *SidecarMetadataService) tagIDs(names []string) ([]uint, error) {
+	tags, err := s.tagRepo.GetByNames(names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	byName := data.TagIDsByName(tags)
+
+	ids := make([]uint, 0, len(names))
+	for _, name := range names {
+		if id, ok := byName[name]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		if len(name) > 100 {
+			continue
+		}
+		tag := &data.Tag{Name: name}
+		if err := s.tagRepo.Create(tag); err != nil {
+			return nil, fmt.Errorf("failed to create tag %q: %w", name, err)
+		}
+		byName[name] = tag.ID
+		ids = append(ids, tag.ID)
+	}
+	return ids, nil
+}
+
+// actorIDs resolves actor names, creating the missing actors.
+func (s *SidecarMetadataService) actorIDs(names []string) ([]uint, error) {
+	ids := make([]uint, 0, len(names))
+	for _, name := range names {
+		if len(name) > 255 {
+			continue
+		}
+		actor, err := s.actorRepo.GetByName(name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			actor = &data.Actor{UUID: uuid.New(), Name: name, Aliases: pq.StringArray{}}
+			if err := s.actorRepo.Create(actor); err != nil {
+				return nil, fmt.Errorf("failed to create actor %q: %w", name, err)
+			}
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get actor %q: %w", name, err)
+		}
+		if !slices.Contains(ids, actor.ID) {
+			ids = append(ids, actor.ID)
+		}
+	}
+	return ids, nil
+}
+
+// mergeSidecarFields copies the sidecar's title, description and release date
+// into the scene and reports whether any changed. Without overwrite only
+// empty fields are filled; a title still equal to the file name counts as empty.
+func mergeSidecarFields(scene *data.Scene, meta *SidecarMetadata, overwrite bool) bool {
+	changed := false
+
+	filenameTitle := strings.TrimSuffix(scene.OriginalFilename, filepath.Ext(scene.OriginalFilename))
+	if meta.Title != "" && meta.Title != scene.Title &&
+		(overwrite || scene.Title == "" || scene.Title == filenameTitle) {
+		scene.Title = meta.Title
+		changed = true
+	}
+	if meta.Description != "" && meta.Description != scene.Description &&
+		(overwrite || scene.Description == "") {
+		scene.Description = meta.Description
+		changed = true
+	}
+	if meta.ReleaseDate != nil && (overwrite || scene.ReleaseDate == nil) &&
+		(scene.ReleaseDate == nil || !scene.ReleaseDate.Equal(*meta.ReleaseDate)) {
+		date := *meta.ReleaseDate
+		scene.ReleaseDate = &date
+		changed = true
+	}
+
+	return changed
+}
+
+func sameIDs(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// findSidecar returns the sidecar file next to videoPath, or "" without one.
+func findSidecar(videoPath string) string {
+	base := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	for _, ext := range sidecarExtensions {
+		if info, err := os.Stat(base + ext); err == nil && !info.IsDir() {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+// parseSidecarFile reads a sidecar into a tree of maps (keys lowercased),
+// slices and strings. XML elements become map keys, repeated elements become
+// slices and attributes are ignored.
+func parseSidecarFile(path string) (map[string]any, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxSidecarSize {
+		return nil, fmt.Errorf("sidecar %s is larger than %d bytes", path, maxSidecarSize)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var doc any
+		if err := json.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("invalid sidecar %s: %w", path, err)
+		}
+		tree, ok := lowercaseKeys(doc).(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid sidecar %s: expected a JSON object", path)
+		}
+		return tree, nil
+	}
+
+	tree, err := parseXMLTree(strings.NewReader(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sidecar %s: %w", path, err)
+	}
+	return tree, nil
+}
+
+type xmlNode struct {
+	name     string
+	text     strings.Builder
+	children []*xmlNode
+}
+
+// parseXMLTree parses the first root element. Kodi .nfo files may carry a
+// trailing URL after it, which is ignored.
+func parseXMLTree(r io.Reader) (map[string]any, error) {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	var stack []*xmlNode
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: strings.ToLower(t.Name.Local)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.EndElement:
+			if len(stack) == 0 {
+				continue
+			}
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				tree, ok := node.value().(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("root element <%s> has no fields", node.name)
+				}
+				return tree, nil
+			}
+		}
+	}
+}
+
+func (n *xmlNode) value() any {
+	if len(n.children) == 0 {
+		return strings.TrimSpace(n.text.String())
+	}
+	m := make(map[string]any, len(n.children))
+	for _, child := range n.children {
+		v := child.value()
+		switch existing := m[child.name].(type) {
+		case nil:
+			m[child.name] = v
+		case []any:
+			m[child.name] = append(existing, v)
+		default:
+			m[child.name] = []any{existing, v}
+		}
+	}
+	return m
+}
+
+func lowercaseKeys(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, child := range t {
+			m[strings.ToLower(k)] = lowercaseKeys(child)
+		}
+		return m
+	case []any:
+		for i, child := range t {
+			t[i] = lowercaseKeys(child)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// lookupSidecar returns the values at a dotted key such as "actor.name".
+// Slices along the way are flattened, so every actor's name is returned.
+func lookupSidecar(node any, key string) []string {
+	return lookupSegments(node, strings.Split(strings.ToLower(key), "."))
+}
+
+func lookupSegments(node any, segments []string) []string {
+	switch v := node.(type) {
+	case []any:
+		var values []string
+		for _, child := range v {
+			values = append(values, lookupSegments(child, segments)...)
+		}
+		return values
+	case map[string]any:
+		if len(segments) == 0 {
+			return nil
+		}
+		return lookupSegments(v[segments[0]], segments[1:])
+	case string:
+		if len(segments) == 0 && strings.TrimSpace(v) != "" {
+			return []string{strings.TrimSpace(v)}
+		}
+	case float64:
+		if len(segments) == 0 {
+			return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+		}
+	}
+	return nil
+}
+
+// extractSidecarMetadata maps a parsed sidecar onto scene fields. For each
+// field the first key with a value wins.
+func extractSidecarMetadata(tree map[string]any, mapping map[string][]string) *SidecarMetadata {
+	values := func(field string) []string {
+		for _, key := range mapping[field] {
+			if found := lookupSidecar(tree, key); len(found) > 0 {
+				return found
+			}
+		}
+		return nil
+	}
+	first := func(field string) string {
+		if found := values(field); len(found) > 0 {
+			return found[0]
+		}
+		return ""
+	}
+
+	meta := &SidecarMetadata{
+		Title:       first("title"),
+		Description: first("description"),
+		Studio:      first("studio"),
+		Actors:      uniqueNames(values("actors")),
+		Tags:        uniqueNames(values("tags")),
+	}
+	if date := first("release_date"); date != "" {
+		meta.ReleaseDate = parseSidecarDate(date)
+	}
+	return meta
+}
+
+// uniqueNames drops repeated names, ignoring case and keeping the first spelling.
+func uniqueNames(names []string) []string {
+	seen := make(map[string]struct{}, len(names))
+	var unique []string
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, name)
+	}
+	return unique
+}
+
+func parseSidecarDate(value string) *time.Time {
+	for _, layout := range sidecarDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			return &date
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var testSidecarMapping = map[string][]string{
+	"title":        {"title", "originaltitle"},
+	"description":  {"plot", "description", "details"},
+	"studio":       {"studio", "studio.name"},
+	"actors":       {"actor.name", "performers.name", "performers"},
+	"tags":         {"tag", "genre", "tags.name", "tags"},
+	"release_date": {"premiered", "date"},
+}
+
+func writeSidecar(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSidecarMetadata_ReadNFO(t *testing.T) {
+	dir := t.TempDir()
+	video := writeSidecar(t, dir, "clip.mp4", "video")
+	writeSidecar(t, dir, "clip.nfo", `<?xml version="1.0" encoding="UTF-8"?>
+<movie>
+  <title>Beach Day &amp; Night</title>
+  <plot>A long day.</plot>
+  <studio>Sunset</studio>
+  <premiered>2021-06-03</premiered>
+  <genre>Outdoor</genre>
+  <genre>outdoor</genre>
+  <tag>Summer</tag>
+  <actor><name>Jane Doe</name><role>Lead</role></actor>
+  <actor><name>John Roe</name></actor>
+</movie>
+https://example.com/scene/1`)
+
+	svc := NewSidecarMetadataService(config.SidecarConfig{Enabled: true, FieldMapping: testSidecarMapping}, nil, nil, nil, nil, zap.NewNop())
+	meta, err := svc.Read(video)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if meta.Title != "Beach Day & Night" || meta.Description != "A long day." || meta.Studio != "Sunset" {
+		t.Fatalf("unexpected fields %+v", meta)
+	}
+	if len(meta.Actors) != 2 || meta.Actors[0] != "Jane Doe" || meta.Actors[1] != "John Roe" {
+		t.Fatalf("unexpected actors %v", meta.Actors)
+	}
+	// "tag" is tried first and wins over "genre"
+	if len(meta.Tags) != 1 || meta.Tags[0] != "Summer" {
+		t.Fatalf("unexpected tags %v", meta.Tags)
+	}
+	if meta.ReleaseDate == nil || !meta.ReleaseDate.Equal(time.Date(2021, 6, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected release date %v", meta.ReleaseDate)
+	}
+}
+
+func TestSidecarMetadata_ReadJSON(t *testing.T) {
+	dir := t.TempDir()
+	video := writeSidecar(t, dir, "clip.mkv", "video")
+	writeSidecar(t, dir, "clip.json", `{
+		"Title": "Studio Cut",
+		"Details": "Extended.",
+		"Studio": {"Name": "Acme"},
+		"Performers": [{"Name": "Jane Doe"}, {"Name": "jane doe"}],
+		"Tags": ["A", "B"],
+		"Date": "2020-01-02T10:00:00Z"
+	}`)
+
+	svc := NewSidecarMetadataService(config.SidecarConfig{Enabled: true, FieldMapping: testSidecarMapping}, nil, nil, nil, nil, zap.NewNop())
+	meta, err := svc.Read(video)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if meta.Title != "Studio Cut" || meta.Description != "Extended." || meta.Studio != "Acme" {
+		t.Fatalf("unexpected fields %+v", meta)
+	}
+	if len(meta.Actors) != 1 || len(meta.Tags) != 2 {
+		t.Fatalf("unexpected actors %v or tags %v", meta.Actors, meta.Tags)
+	}
+	if meta.ReleaseDate == nil || meta.ReleaseDate.Format("2006-01-02") != "2020-01-02" {
+		t.Fatalf("unexpected release date %v", meta.ReleaseDate)
+	}
+}
+
+func TestSidecarMetadata_ReadWithoutSidecar(t *testing.T) {
+	dir := t.TempDir()
+	video := writeSidecar(t, dir, "clip.mp4", "video")
+
+	svc := NewSidecarMetadataService(config.SidecarConfig{Enabled: true, FieldMapping: testSidecarMapping}, nil, nil, nil, nil, zap.NewNop())
+	meta, err := svc.Read(video)
+	if err != nil || meta != nil {
+		t.Fatalf("expected no metadata, got %+v (%v)", meta, err)
+	}
+}
+
+func TestMergeSidecarFields(t *testing.T) {
+	date := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	meta := &SidecarMetadata{Title: "Real Title", Description: "From file", ReleaseDate: &date}
+
+	// db_wins fills the filename-derived title and the empty release date only
+	scene := &data.Scene{Title: "clip", OriginalFilename: "clip.mp4", Description: "Curated"}
+	if !mergeSidecarFields(scene, meta, false) {
+		t.Fatal("expected changes")
+	}
+	if scene.Title != "Real Title" || scene.Description != "Curated" || scene.ReleaseDate == nil {
+		t.Fatalf("unexpected scene %+v", scene)
+	}
+
+	// db_wins keeps an edited title
+	scene = &data.Scene{Title: "Edited", OriginalFilename: "clip.mp4"}
+	mergeSidecarFields(scene, meta, false)
+	if scene.Title != "Edited" {
+		t.Fatalf("expected edited title to be kept, got %q", scene.Title)
+	}
+
+	// file_wins overwrites and reports no change once in sync
+	scene = &data.Scene{Title: "Edited", OriginalFilename: "clip.mp4", Description: "Curated"}
+	if !mergeSidecarFields(scene, meta, true) || scene.Title != "Real Title" || scene.Description != "From file" {
+		t.Fatalf("unexpected scene %+v", scene)
+	}
+	if mergeSidecarFields(scene, meta, true) {
+		t.Fatal("expected no changes when already in sync")
+	}
+}
+
+func TestSidecarMetadata_ApplyToExisting(t *testing.T) {
+	meta := &SidecarMetadata{Title: "Real Title", Studio: "Acme", Actors: []string{"Jane Doe"}, Tags: []string{"A"}}
+
+	t.Run("db_wins keeps existing relations", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		sceneRepo := mocks.NewMockSceneRepository(ctrl)
+		studioRepo := mocks.NewMockStudioRepository(ctrl)
+		tagRepo := mocks.NewMockTagRepository(ctrl)
+		actorRepo := mocks.NewMockActorRepository(ctrl)
+		svc := NewSidecarMetadataService(config.SidecarConfig{ConflictPolicy: config.SidecarDBWins}, sceneRepo, studioRepo, tagRepo, actorRepo, zap.NewNop())
+
+		studioID := uint(3)
+		scene := &data.Scene{ID: 7, Title: "Edited", OriginalFilename: "clip.mp4", StudioID: &studioID}
+		tagRepo.EXPECT().GetSceneTags(uint(7)).Return([]data.Tag{{ID: 1, Name: "B"}}, nil)
+		actorRepo.EXPECT().GetSceneActors(uint(7)).Return([]data.Actor{{ID: 2, Name: "John Roe"}}, nil)
+
+		changed, err := svc.ApplyToExisting(scene, meta)
+		if err != nil || changed {
+			t.Fatalf("expected no changes, got %v (%v)", changed, err)
+		}
+	})
+
+	t.Run("file_wins replaces metadata", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		sceneRepo := mocks.NewMockSceneRepository(ctrl)
+		studioRepo := mocks.NewMockStudioRepository(ctrl)
+		tagRepo := mocks.NewMockTagRepository(ctrl)
+		actorRepo := mocks.NewMockActorRepository(ctrl)
+		svc := NewSidecarMetadataService(config.SidecarConfig{ConflictPolicy: config.SidecarFileWins}, sceneRepo, studioRepo, tagRepo, actorRepo, zap.NewNop())
+
+		scene := &data.Scene{ID: 7, Title: "Edited", OriginalFilename: "clip.mp4"}
+		sceneRepo.EXPECT().UpdateDetails(uint(7), "Real Title", "", nil).Return(nil)
+		studioRepo.EXPECT().GetByName("Acme").Return(nil, gorm.ErrRecordNotFound)
+		studioRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(studio *data.Studio) error {
+			studio.ID = 5
+			return nil
+		})
+		studioRepo.EXPECT().SetSceneStudio(uint(7), gomock.Any()).Return(nil)
+		tagRepo.EXPECT().GetSceneTags(uint(7)).Return([]data.Tag{{ID: 1, Name: "B"}}, nil)
+		tagRepo.EXPECT().GetByNames([]string{"A"}).Return([]data.Tag{{ID: 4, Name: "A"}}, nil)
+		tagRepo.EXPECT().SetSceneTags(uint(7), []uint{4}).Return(nil)
+		actorRepo.EXPECT().GetSceneActors(uint(7)).Return(nil, nil)
+		actorRepo.EXPECT().GetByName("Jane Doe").Return(&data.Actor{ID: 9, Name: "Jane Doe"}, nil)
+		actorRepo.EXPECT().SetSceneActors(uint(7), []uint{9}).Return(nil)
+
+		changed, err := svc.ApplyToExisting(scene, meta)
+		if err != nil || !changed {
+			t.Fatalf("expected changes, got %v (%v)", changed, err)
+		}
+		if scene.StudioID == nil || *scene.StudioID != 5 {
+			t.Fatalf("expected studio 5, got %v", scene.StudioID)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+)
+
+// Similarity weights. They sum to 1, so a scene with the same cast, the same
+// tags, the same studio and the same length scores 1.
+const (
+	similarityWeightActors   = 0.4
+	similarityWeightTags     = 0.4
+	similarityWeightStudio   = 0.1
+	similarityWeightDuration = 0.1
+)
+
+const (
+	defaultSimilarLimit = 12
+	maxSimilarLimit     = 50
+)
+
+// SimilarScene is a scene ranked by how much it has in common with another one.
+type SimilarScene struct {
+	Scene        data.Scene
+	Score        float64  // 0-1
+	SharedActors []string // names, in the candidate's order
+	SharedTags   []string
+	SameStudio   bool
+}
+
+// GetSimilarScenes ranks the scenes sharing actors, tags or the studio with the
+// given scene by a 0-1 similarity score: the overlap (Jaccard index) of the
+// actor and tag sets, a studio match and how close the durations are. Unlike
+// GetRelatedScenes it ignores popularity and the user's history, so the score
+// only describes the scenes themselves, and it does not pad the list with
+// unrelated popular scenes.
+func (s *RelatedScenesService) GetSimilarScenes(sceneID uint, role string, limit int) ([]SimilarScene, error) {
+	if limit <= 0 {
+		limit = defaultSimilarLimit
+	}
+	if limit > maxSimilarLimit {
+		limit = maxSimilarLimit
+	}
+
+	source, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source scene: %w", err)
+	}
+	sourceActors, err := s.actorRepo.GetSceneActors(sceneID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source scene actors: %w", err)
+	}
+	sourceTags, err := s.tagRepo.GetSceneTags(sceneID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source scene tags: %w", err)
+	}
+
+	candidateIDs, err := visibleSceneIDs(s.rbac, role, s.similarCandidateIDs(source, sourceActors, sourceTags))
+	if err != nil {
+		return nil, err
+	}
+	if len(candidateIDs) == 0 {
+		return []SimilarScene{}, nil
+	}
+
+	scenes, err := s.sceneRepo.GetByIDs(candidateIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate scenes: %w", err)
+	}
+	actorsByScene, err := s.actorRepo.GetSceneActorsMultiple(candidateIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate actors: %w", err)
+	}
+	tagsByScene, err := s.tagRepo.GetSceneTagsMultiple(candidateIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate tags: %w", err)
+	}
+
+	sourceActorIDs := make(map[uint]struct{}, len(sourceActors))
+	for _, a := range sourceActors {
+		sourceActorIDs[a.ID] = struct{}{}
+	}
+	sourceTagIDs := make(map[uint]struct{}, len(sourceTags))
+	for _, t := range sourceTags {
+		sourceTagIDs[t.ID] = struct{}{}
+	}
+
+	results := make([]SimilarScene, 0, len(scenes))
+	for _, sc := range scenes {
+		similar := SimilarScene{Scene: sc}
+
+		candidateActors := actorsByScene[sc.ID]
+		for _, a := range candidateActors {
+			if _, ok := sourceActorIDs[a.ID]; ok {
+				similar.SharedActors = append(similar.SharedActors, a.Name)
+			}
+		}
+		candidateTags := tagsByScene[sc.ID]
+		for _, t := range candidateTags {
+			if _, ok := sourceTagIDs[t.ID]; ok {
+				similar.SharedTags = append(similar.SharedTags, t.Name)
+			}
+		}
+		similar.SameStudio = source.StudioID != nil && sc.StudioID != nil && *source.StudioID == *sc.StudioID
+
+		score := similarityWeightActors*jaccard(len(similar.SharedActors), len(sourceActors), len(candidateActors)) +
+			similarityWeightTags*jaccard(len(similar.SharedTags), len(sourceTags), len(candidateTags)) +
+			similarityWeightDuration*durationSimilarity(source.Duration, sc.Duration)
+		if similar.SameStudio {
+			score += similarityWeightStudio
+		}
+		similar.Score = math.Round(score*1000) / 1000
+
+		results = append(results, similar)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Scene.ID > results[j].Scene.ID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// similarCandidateIDs collects the scenes sharing at least one actor or tag or
+// the studio with the source, capped like the related scenes candidate pool.
+func (s *RelatedScenesService) similarCandidateIDs(source *data.Scene, actors []data.Actor, tags []data.Tag) []uint {
+	seen := map[uint]struct{}{source.ID: {}}
+	var ids []uint
+	add := func(sceneIDs []uint, limit int) {
+		added := 0
+		for _, id := range sceneIDs {
+			if added >= limit {
+				return
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+			added++
+		}
+	}
+
+	for _, actor := range actors {
+		sceneIDs, err := s.actorRepo.GetActorSceneIDs(actor.ID)
+		if err != nil {
+			s.logger.Debug("failed to get scene IDs for actor", zap.Uint("actor_id", actor.ID), zap.Error(err))
+			continue
+		}
+		add(sceneIDs, candidateCapActors)
+	}
+	for _, tag := range tags {
+		sceneIDs, err := s.tagRepo.GetSceneIDsByTag(tag.ID, candidateCapTags)
+		if err != nil {
+			s.logger.Debug("failed to get scene IDs for tag", zap.Uint("tag_id", tag.ID), zap.Error(err))
+			continue
+		}
+		add(sceneIDs, candidateCapTags)
+	}
+	if source.StudioID != nil {
+		sceneIDs, err := s.studioRepo.GetStudioSceneIDs(*source.StudioID, candidateCapStudio)
+		if err != nil {
+			s.logger.Debug("failed to get scene IDs for studio", zap.Uint("studio_id", *source.StudioID), zap.Error(err))
+		} else {
+			add(sceneIDs, candidateCapStudio)
+		}
+	}
+	return ids
+}
+
+// jaccard is the size of the intersection over the size of the union of two
+// sets, given the intersection and set sizes.
+func jaccard(shared, a, b int) float64 {
+	union := a + b - shared
+	if union <= 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// durationSimilarity is the ratio of the shorter to the longer duration, 0 when
+// either is unknown.
+func durationSimilarity(a, b int) float64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	return float64(min(a, b)) / float64(max(a, b))
+}
@@ -0,0 +1,194 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/pkg/ffmpeg"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxConcurrentFrameCrops bounds the ffmpeg processes started for frame requests;
+	// scrubbing a seek bar can fire many requests at once.
+	maxConcurrentFrameCrops = 4
+	// maxCachedCueFiles bounds the parsed VTT cache; it is reset when full.
+	maxCachedCueFiles = 256
+)
+
+// spriteFrameContentTypes maps the supported output formats to their Content-Type.
+var spriteFrameContentTypes = map[string]string{
+	"webp": "image/webp",
+	"jpeg": "image/jpeg",
+}
+
+// SpriteFrame is the sprite sheet tile previewing a point in a scene.
+type SpriteFrame struct {
+	SceneID   uint
+	SheetPath string
+	Cue       ffmpeg.SpriteCue
+	ModTime   time.Time
+}
+
+// ETag identifies the tile for a given output format. Sprite regeneration
+// changes the sheet mtime, which invalidates cached frames.
+func (f *SpriteFrame) ETag(format string) string {
+	return fmt.Sprintf(`"%d-%s-%d-%d-%d-%d-%d-%s"`, f.SceneID, filepath.Base(f.SheetPath),
+		f.Cue.X, f.Cue.Y, f.Cue.Width, f.Cue.Height, f.ModTime.Unix(), format)
+}
+
+type cachedCues struct {
+	modTime time.Time
+	cues    []ffmpeg.SpriteCue
+}
+
+// SpriteFrameService cuts single preview frames out of generated sprite sheets
+// so clients can show a seek preview without downloading whole sheets.
+type SpriteFrameService struct {
+	sceneRepo data.SceneRepository
+	spriteDir string
+	quality   int
+	logger    *zap.Logger
+
+	sem       chan struct{}
+	mu        sync.Mutex
+	cueByFile map[string]cachedCues
+}
+
+func NewSpriteFrameService(sceneRepo data.SceneRepository, spriteDir string, quality int, logger *zap.Logger) *SpriteFrameService {
+	return &SpriteFrameService{
+		sceneRepo: sceneRepo,
+		spriteDir: spriteDir,
+		quality:   quality,
+		logger:    logger,
+		sem:       make(chan struct{}, maxConcurrentFrameCrops),
+		cueByFile: make(map[string]cachedCues),
+	}
+}
+
+// NormalizeFrameFormat maps a requested format to "webp" or "jpeg".
+func NormalizeFrameFormat(format string) (string, error) {
+	switch format {
+	case "", "webp":
+		return "webp", nil
+	case "jpg", "jpeg":
+		return "jpeg", nil
+	}
+	return "", apperrors.NewValidationErrorWithField("format", "format must be webp or jpg")
+}
+
+// FrameContentType returns the Content-Type of a normalized frame format.
+func FrameContentType(format string) string {
+	return spriteFrameContentTypes[format]
+}
+
+// ResolveFrame finds the sprite tile covering second t of a scene.
+func (s *SpriteFrameService) ResolveFrame(sceneID uint, t float64) (*SpriteFrame, error) {
+	if t < 0 {
+		return nil, apperrors.NewValidationErrorWithField("t", "t must not be negative")
+	}
+
+	scene, err := s.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrSceneNotFound(sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+	if scene.VttPath == "" {
+		return nil, apperrors.NewNotFoundError("sprite frames", sceneID)
+	}
+
+	cues, err := s.loadCues(scene.VttPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, apperrors.NewNotFoundError("sprite frames", sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to read sprite VTT", err)
+	}
+
+	cue, ok := ffmpeg.FindSpriteCue(cues, t)
+	if !ok {
+		return nil, apperrors.NewNotFoundError("sprite frames", sceneID)
+	}
+
+	// Only the file name is taken from the VTT so cues cannot point outside the sprite dir
+	sheetPath := filepath.Join(s.spriteDir, filepath.Base(cue.Sheet))
+	info, err := os.Stat(sheetPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, apperrors.NewNotFoundError("sprite sheet", filepath.Base(cue.Sheet))
+		}
+		return nil, apperrors.NewInternalError("failed to stat sprite sheet", err)
+	}
+
+	return &SpriteFrame{
+		SceneID:   scene.ID,
+		SheetPath: sheetPath,
+		Cue:       cue,
+		ModTime:   info.ModTime(),
+	}, nil
+}
+
+// RenderFrame crops the tile out of its sprite sheet and encodes it as format.
+func (s *SpriteFrameService) RenderFrame(ctx context.Context, frame *SpriteFrame, format string) ([]byte, error) {
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var buf bytes.Buffer
+	cue := frame.Cue
+	if err := ffmpeg.CropImageToWriter(ctx, frame.SheetPath, cue.X, cue.Y, cue.Width, cue.Height, format, s.quality, &buf); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		s.logger.Warn("Failed to crop sprite frame",
+			zap.Uint("scene_id", frame.SceneID),
+			zap.String("sheet", frame.SheetPath),
+			zap.Error(err),
+		)
+		return nil, apperrors.NewInternalError("failed to extract frame", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// loadCues parses a VTT file, reusing the previous parse while its mtime is unchanged.
+func (s *SpriteFrameService) loadCues(vttPath string) ([]ffmpeg.SpriteCue, error) {
+	info, err := os.Stat(vttPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	cached, ok := s.cueByFile[vttPath]
+	s.mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.cues, nil
+	}
+
+	cues, err := ffmpeg.ParseVttFile(vttPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if len(s.cueByFile) >= maxCachedCueFiles {
+		s.cueByFile = make(map[string]cachedCues)
+	}
+	s.cueByFile[vttPath] = cachedCues{modTime: info.ModTime(), cues: cues}
+	s.mu.Unlock()
+	return cues, nil
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+	"goonhub/pkg/ffmpeg"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestSpriteFrameService(t *testing.T) (*SpriteFrameService, *mocks.MockSceneRepository, string) {
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	dir := t.TempDir()
+	return NewSpriteFrameService(sceneRepo, dir, 75, zap.NewNop()), sceneRepo, dir
+}
+
+// writeSprites writes a 2x2 sprite VTT for a 40s scene with one sheet on disk.
+func writeSprites(t *testing.T, dir string) string {
+	t.Helper()
+	vttPath := filepath.Join(dir, "5_thumbnails.vtt")
+	if err := ffmpeg.GenerateVttFile(vttPath, []string{"5_sheet_000.webp"}, 40, 10, 2, 2, 160, 90); err != nil {
+		t.Fatalf("GenerateVttFile failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "5_sheet_000.webp"), []byte("sheet"), 0644); err != nil {
+		t.Fatalf("failed to write sheet: %v", err)
+	}
+	return vttPath
+}
+
+func TestResolveFrame_NegativeTime(t *testing.T) {
+	svc, _, _ := newTestSpriteFrameService(t)
+
+	if _, err := svc.ResolveFrame(5, -1); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestResolveFrame_SceneNotFound(t *testing.T) {
+	svc, sceneRepo, _ := newTestSpriteFrameService(t)
+	sceneRepo.EXPECT().GetByID(uint(5)).Return(nil, gorm.ErrRecordNotFound)
+
+	if _, err := svc.ResolveFrame(5, 1); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestResolveFrame_NoSprites(t *testing.T) {
+	svc, sceneRepo, _ := newTestSpriteFrameService(t)
+	sceneRepo.EXPECT().GetByID(uint(5)).Return(&data.Scene{ID: 5}, nil)
+
+	if _, err := svc.ResolveFrame(5, 1); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestResolveFrame_FindsTile(t *testing.T) {
+	svc, sceneRepo, dir := newTestSpriteFrameService(t)
+	vttPath := writeSprites(t, dir)
+	sceneRepo.EXPECT().GetByID(uint(5)).Return(&data.Scene{ID: 5, VttPath: vttPath}, nil).Times(2)
+
+	frame, err := svc.ResolveFrame(5, 25)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if frame.SheetPath != filepath.Join(dir, "5_sheet_000.webp") {
+		t.Fatalf("unexpected sheet path %q", frame.SheetPath)
+	}
+	if frame.Cue.X != 0 || frame.Cue.Y != 90 || frame.Cue.Width != 160 || frame.Cue.Height != 90 {
+		t.Fatalf("unexpected tile %+v", frame.Cue)
+	}
+
+	// Second lookup is served from the cue cache and picks a different tile
+	frame2, err := svc.ResolveFrame(5, 15)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if frame2.Cue.X != 160 || frame2.Cue.Y != 0 {
+		t.Fatalf("unexpected tile %+v", frame2.Cue)
+	}
+	if frame.ETag("webp") == frame2.ETag("webp") || frame.ETag("webp") == frame.ETag("jpeg") {
+		t.Fatal("expected ETags to differ by tile and format")
+	}
+}
+
+func TestResolveFrame_MissingSheet(t *testing.T) {
+	svc, sceneRepo, dir := newTestSpriteFrameService(t)
+	vttPath := writeSprites(t, dir)
+	os.Remove(filepath.Join(dir, "5_sheet_000.webp"))
+	sceneRepo.EXPECT().GetByID(uint(5)).Return(&data.Scene{ID: 5, VttPath: vttPath}, nil)
+
+	if _, err := svc.ResolveFrame(5, 1); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestNormalizeFrameFormat(t *testing.T) {
+	for in, want := range map[string]string{"": "webp", "webp": "webp", "jpg": "jpeg", "jpeg": "jpeg"} {
+		if got, err := NormalizeFrameFormat(in); err != nil || got != want {
+			t.Errorf("NormalizeFrameFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := NormalizeFrameFormat("png"); !apperrors.IsValidation(err) {
+		t.Errorf("expected validation error for png, got %v", err)
+	}
+}
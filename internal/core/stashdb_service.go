@@ -0,0 +1,280 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"goonhub/internal/config"
+
+	"go.uber.org/zap"
+)
+
+// stashDBMaxRetries is how often a StashDB request answered with 429 is retried
+const stashDBMaxRetries = 4
+
+// stashDBSearchLimit caps how many results a StashDB search returns
+const stashDBSearchLimit = 25
+
+const stashDBSceneFields = `
+	id
+	title
+	details
+	release_date
+	duration
+	urls { url }
+	images { url width height }
+	studio { name }
+	performers { performer { id name images { url } } }
+	tags { name }
+`
+
+// StashDBService handles communication with a stash-box GraphQL API, StashDB
+// by default. Results are converted to the PornDB shapes so either provider
+// can be used for matching. Requests go through their own rate limiter.
+type StashDBService struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+	limiter  *metadataLimiter
+	logger   *zap.Logger
+}
+
+// NewStashDBService creates a new StashDB service. An empty API key disables it.
+func NewStashDBService(cfg config.StashDBConfig, logger *zap.Logger) *StashDBService {
+	return &StashDBService{
+		endpoint: cfg.Endpoint,
+		apiKey:   cfg.APIKey,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		limiter: newMetadataLimiter(cfg.RequestsPerMinute, stashDBMaxRetries),
+		logger:  logger,
+	}
+}
+
+// Name returns the metadata provider name of StashDB
+func (s *StashDBService) Name() string {
+	return MetadataProviderStashDB
+}
+
+// Label returns how StashDB is shown in error messages
+func (s *StashDBService) Label() string {
+	return "StashDB"
+}
+
+// IsConfigured returns true if the endpoint and API key are configured
+func (s *StashDBService) IsConfigured() bool {
+	return s.endpoint != "" && s.apiKey != ""
+}
+
+// stashDBScene is the raw GraphQL scene structure
+type stashDBScene struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Details     string `json:"details"`
+	ReleaseDate string `json:"release_date"`
+	Duration    int    `json:"duration"`
+	URLs        []struct {
+		URL string `json:"url"`
+	} `json:"urls"`
+	Images []struct {
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	} `json:"images"`
+	Studio *struct {
+		Name string `json:"name"`
+	} `json:"studio"`
+	Performers []struct {
+		Performer stashDBPerformer `json:"performer"`
+	} `json:"performers"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+// stashDBPerformer is the raw GraphQL performer structure
+type stashDBPerformer struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Disambiguation string `json:"disambiguation"`
+	Images         []struct {
+		URL string `json:"url"`
+	} `json:"images"`
+}
+
+type stashDBResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// SearchScenes searches scenes by fingerprint, or by title when no
+// fingerprint is given
+func (s *StashDBService) SearchScenes(opts SceneSearchOptions) ([]PornDBScene, error) {
+	var raw []stashDBScene
+	if opts.Fingerprint != "" {
+		var result struct {
+			Scenes []stashDBScene `json:"findSceneByFingerprint"`
+		}
+		query := `query($fingerprint: FingerprintQueryInput!) { findSceneByFingerprint(fingerprint: $fingerprint) {` + stashDBSceneFields + `} }`
+		variables := map[string]any{
+			"fingerprint": map[string]string{
+				"hash":      opts.Fingerprint,
+				"algorithm": strings.ToUpper(opts.FingerprintAlgorithm),
+			},
+		}
+		if err := s.query(query, variables, &result); err != nil {
+			return nil, err
+		}
+		raw = result.Scenes
+	} else {
+		var result struct {
+			Scenes []stashDBScene `json:"searchScene"`
+		}
+		query := `query($term: String!, $limit: Int) { searchScene(term: $term, limit: $limit) {` + stashDBSceneFields + `} }`
+		if err := s.query(query, map[string]any{"term": opts.Title, "limit": stashDBSearchLimit}, &result); err != nil {
+			return nil, err
+		}
+		raw = result.Scenes
+	}
+
+	scenes := make([]PornDBScene, 0, len(raw))
+	for _, r := range raw {
+		scenes = append(scenes, convertStashDBScene(r))
+	}
+	return scenes, nil
+}
+
+// GetSceneDetails fetches a scene by its StashDB ID
+func (s *StashDBService) GetSceneDetails(id string) (*PornDBScene, error) {
+	var result struct {
+		Scene *stashDBScene `json:"findScene"`
+	}
+	query := `query($id: ID!) { findScene(id: $id) {` + stashDBSceneFields + `} }`
+	if err := s.query(query, map[string]any{"id": id}, &result); err != nil {
+		return nil, err
+	}
+	if result.Scene == nil {
+		return nil, fmt.Errorf("StashDB scene %s not found", id)
+	}
+	scene := convertStashDBScene(*result.Scene)
+	return &scene, nil
+}
+
+// SearchPerformers searches performers by name
+func (s *StashDBService) SearchPerformers(query string) ([]PornDBPerformer, error) {
+	var result struct {
+		Performers []stashDBPerformer `json:"searchPerformer"`
+	}
+	gql := `query($term: String!, $limit: Int) { searchPerformer(term: $term, limit: $limit) { id name disambiguation images { url } } }`
+	if err := s.query(gql, map[string]any{"term": query, "limit": stashDBSearchLimit}, &result); err != nil {
+		return nil, err
+	}
+
+	performers := make([]PornDBPerformer, 0, len(result.Performers))
+	for _, p := range result.Performers {
+		performer := PornDBPerformer{ID: p.ID, Name: p.Name, Bio: p.Disambiguation}
+		if len(p.Images) > 0 {
+			performer.Image = p.Images[0].URL
+		}
+		performers = append(performers, performer)
+	}
+	return performers, nil
+}
+
+// query runs a GraphQL query and decodes its data into out
+func (s *StashDBService) query(query string, variables map[string]any, out any) error {
+	if !s.IsConfigured() {
+		return fmt.Errorf("StashDB API key is not configured")
+	}
+
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return fmt.Errorf("failed to encode query: %w", err)
+	}
+	req, err := http.NewRequest("POST", s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("ApiKey", s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.limiter.do(s.client, req, s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		s.logger.Warn("StashDB query failed",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(respBody)),
+		)
+		return fmt.Errorf("StashDB API returned status %d", resp.StatusCode)
+	}
+
+	var result stashDBResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("StashDB API returned an error: %s", result.Errors[0].Message)
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// convertStashDBScene converts a StashDB scene to a PornDBScene. The widest
+// image is used as the scene image.
+func convertStashDBScene(raw stashDBScene) PornDBScene {
+	scene := PornDBScene{
+		ID:          raw.ID,
+		Title:       raw.Title,
+		Description: raw.Details,
+		Date:        raw.ReleaseDate,
+		Duration:    raw.Duration,
+	}
+
+	width := -1
+	for _, image := range raw.Images {
+		if image.Width > width {
+			scene.Image = image.URL
+			width = image.Width
+		}
+	}
+
+	if raw.Studio != nil {
+		scene.Site = &PornDBSite{Name: raw.Studio.Name}
+		if len(raw.URLs) > 0 {
+			scene.Site.URL = raw.URLs[0].URL
+		}
+	}
+
+	for _, p := range raw.Performers {
+		performer := PornDBScenePerformer{ID: p.Performer.ID, Name: p.Performer.Name}
+		if len(p.Performer.Images) > 0 {
+			performer.Image = p.Performer.Images[0].URL
+		}
+		scene.Performers = append(scene.Performers, performer)
+	}
+
+	for _, t := range raw.Tags {
+		scene.Tags = append(scene.Tags, PornDBTag{Name: t.Name})
+	}
+
+	return scene
+}
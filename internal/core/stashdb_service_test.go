@@ -0,0 +1,75 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"goonhub/internal/config"
+
+	"go.uber.org/zap"
+)
+
+func TestStashDBService_SearchScenesByFingerprint(t *testing.T) {
+	var got struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("ApiKey") != "key" {
+			t.Errorf("expected the API key header, got %q", r.Header.Get("ApiKey"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"data":{"findSceneByFingerprint":[{
+			"id":"0b6b7c1e","title":"Pool Party","details":"Summer","release_date":"2024-03-15","duration":1805,
+			"urls":[{"url":"https://brazzers.com/scene/1"}],
+			"images":[{"url":"small.jpg","width":320,"height":180},{"url":"large.jpg","width":1280,"height":720}],
+			"studio":{"name":"Brazzers"},
+			"performers":[{"performer":{"id":"p1","name":"Jane Doe","images":[{"url":"jane.jpg"}]}}],
+			"tags":[{"name":"Outdoor"}]
+		}]}}`))
+	}))
+	defer srv.Close()
+
+	svc := NewStashDBService(config.StashDBConfig{Endpoint: srv.URL, APIKey: "key"}, zap.NewNop())
+	scenes, err := svc.SearchScenes(SceneSearchOptions{Fingerprint: "abc123", FingerprintAlgorithm: FingerprintOSHash})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got.Query, "findSceneByFingerprint") {
+		t.Fatalf("expected a fingerprint query, got %q", got.Query)
+	}
+	if fp, _ := got.Variables["fingerprint"].(map[string]any); fp["hash"] != "abc123" || fp["algorithm"] != "OSHASH" {
+		t.Fatalf("unexpected fingerprint variables %+v", got.Variables)
+	}
+
+	if len(scenes) != 1 {
+		t.Fatalf("expected one scene, got %+v", scenes)
+	}
+	scene := scenes[0]
+	if scene.ID != "0b6b7c1e" || scene.Date != "2024-03-15" || scene.Description != "Summer" || scene.Image != "large.jpg" {
+		t.Fatalf("unexpected scene %+v", scene)
+	}
+	if scene.Site == nil || scene.Site.Name != "Brazzers" || len(scene.Performers) != 1 || scene.Performers[0].Image != "jane.jpg" || len(scene.Tags) != 1 {
+		t.Fatalf("expected the studio, performers and tags to be kept, got %+v", scene)
+	}
+}
+
+func TestStashDBService_GraphQLErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":null,"errors":[{"message":"Not authorized"}]}`))
+	}))
+	defer srv.Close()
+
+	svc := NewStashDBService(config.StashDBConfig{Endpoint: srv.URL, APIKey: "key"}, zap.NewNop())
+	if _, err := svc.SearchPerformers("jane"); err == nil || !strings.Contains(err.Error(), "Not authorized") {
+		t.Fatalf("expected the GraphQL error, got %v", err)
+	}
+
+	unconfigured := NewStashDBService(config.StashDBConfig{Endpoint: srv.URL}, zap.NewNop())
+	if _, err := unconfigured.GetSceneDetails("x"); err == nil {
+		t.Fatal("expected an unconfigured client to fail")
+	}
+}
@@ -0,0 +1,358 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// storageProbeTimeout bounds a single path check, since a stat on a dead
+// network mount can block for minutes
+const storageProbeTimeout = 10 * time.Second
+
+const (
+	defaultHealthHistoryDays = 7
+	maxHealthHistoryDays     = 90
+)
+
+// StorageHealthService periodically checks that every storage path is
+// mounted, readable and reports free space. Samples are kept as history and
+// the latest status of each path is held in memory: scans skip offline paths,
+// so their scenes are not marked missing, and the job feeder leaves their jobs
+// pending until they come back.
+type StorageHealthService struct {
+	storagePathRepo data.StoragePathRepository
+	explorerRepo    data.ExplorerRepository
+	repo            data.StorageHealthRepository
+	eventBus        *EventBus
+	interval        time.Duration
+	retention       time.Duration
+	logger          *zap.Logger
+
+	// probe checks one path; replaced in tests
+	probe func(path string, hasScenes bool) (free, total *int64, err error)
+
+	checkMu sync.Mutex // one check at a time
+	mu      sync.RWMutex
+	current map[uint]data.StoragePathHealthSample
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewStorageHealthService(
+	storagePathRepo data.StoragePathRepository,
+	explorerRepo data.ExplorerRepository,
+	repo data.StorageHealthRepository,
+	eventBus *EventBus,
+	interval time.Duration,
+	retention time.Duration,
+	logger *zap.Logger,
+) *StorageHealthService {
+	return &StorageHealthService{
+		storagePathRepo: storagePathRepo,
+		explorerRepo:    explorerRepo,
+		repo:            repo,
+		eventBus:        eventBus,
+		interval:        interval,
+		retention:       retention,
+		logger:          logger.With(zap.String("component", "storage_health")),
+		probe:           probeStoragePath,
+		current:         make(map[uint]data.StoragePathHealthSample),
+	}
+}
+
+// Start restores the last known status of each path and checks them every
+// interval, starting right away. It is a no-op when the interval is 0.
+func (s *StorageHealthService) Start() {
+	if s.interval <= 0 {
+		return
+	}
+
+	if latest, err := s.repo.GetLatest(); err != nil {
+		s.logger.Warn("Failed to load storage path health", zap.Error(err))
+	} else {
+		s.mu.Lock()
+		for _, sample := range latest {
+			s.current[sample.StoragePathID] = sample
+		}
+		s.mu.Unlock()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.CheckAll(); err != nil {
+				s.logger.Warn("Storage path health check failed", zap.Error(err))
+			}
+			s.prune()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	s.logger.Info("Storage path health checker started",
+		zap.Duration("interval", s.interval),
+		zap.Duration("retention", s.retention),
+	)
+}
+
+// Stop halts the background check.
+func (s *StorageHealthService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// CheckAll checks every storage path, records a sample for each and announces
+// paths going offline or coming back.
+func (s *StorageHealthService) CheckAll() ([]data.StoragePathHealthSample, error) {
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+
+	paths, err := s.explorerRepo.GetStoragePathsWithCounts()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list storage paths", err)
+	}
+
+	samples := make([]data.StoragePathHealthSample, 0, len(paths))
+	seen := make(map[uint]struct{}, len(paths))
+	for _, path := range paths {
+		sample := s.checkPath(path.StoragePath, path.SceneCount > 0)
+		if err := s.repo.Create(&sample); err != nil {
+			s.logger.Warn("Failed to record storage path health",
+				zap.Uint("storage_path_id", path.ID),
+				zap.Error(err),
+			)
+		}
+		s.update(path.StoragePath, sample)
+		seen[path.ID] = struct{}{}
+		samples = append(samples, sample)
+	}
+
+	// Forget deleted paths
+	s.mu.Lock()
+	for id := range s.current {
+		if _, ok := seen[id]; !ok {
+			delete(s.current, id)
+		}
+	}
+	s.mu.Unlock()
+
+	return samples, nil
+}
+
+// checkPath probes a storage path, giving up after storageProbeTimeout.
+func (s *StorageHealthService) checkPath(path data.StoragePath, hasScenes bool) data.StoragePathHealthSample {
+	sample := data.StoragePathHealthSample{
+		StoragePathID: path.ID,
+		CheckedAt:     time.Now(),
+		Status:        data.StorageHealthOnline,
+	}
+
+	type probeResult struct {
+		free, total *int64
+		err         error
+	}
+	done := make(chan probeResult, 1)
+	go func() {
+		free, total, err := s.probe(path.Path, hasScenes)
+		done <- probeResult{free, total, err}
+	}()
+
+	select {
+	case res := <-done:
+		sample.FreeBytes, sample.TotalBytes = res.free, res.total
+		if res.err != nil {
+			sample.Status = data.StorageHealthOffline
+			sample.Error = res.err.Error()
+		}
+	case <-time.After(storageProbeTimeout):
+		sample.Status = data.StorageHealthOffline
+		sample.Error = fmt.Sprintf("check timed out after %s", storageProbeTimeout)
+	}
+	return sample
+}
+
+// update stores a path's new status and publishes an SSE event when it changed.
+// A path without a previous status is only announced when it is offline.
+func (s *StorageHealthService) update(path data.StoragePath, sample data.StoragePathHealthSample) {
+	s.mu.Lock()
+	previous, known := s.current[path.ID]
+	s.current[path.ID] = sample
+	s.mu.Unlock()
+
+	if known && previous.Status == sample.Status {
+		return
+	}
+	if !known && sample.Status == data.StorageHealthOnline {
+		return
+	}
+
+	if sample.Status == data.StorageHealthOffline {
+		s.logger.Warn("Storage path is offline",
+			zap.Uint("storage_path_id", path.ID),
+			zap.String("path", path.Path),
+			zap.String("error", sample.Error),
+		)
+	} else {
+		s.logger.Info("Storage path is back online",
+			zap.Uint("storage_path_id", path.ID),
+			zap.String("path", path.Path),
+		)
+	}
+
+	if s.eventBus != nil {
+		s.eventBus.Publish(SceneEvent{
+			Type: "storage:" + sample.Status,
+			Data: map[string]any{
+				"storage_path_id": path.ID,
+				"name":            path.Name,
+				"path":            path.Path,
+				"error":           sample.Error,
+			},
+		})
+	}
+}
+
+func (s *StorageHealthService) prune() {
+	if s.retention <= 0 {
+		return
+	}
+	deleted, err := s.repo.DeleteOlderThan(time.Now().Add(-s.retention))
+	if err != nil {
+		s.logger.Warn("Failed to prune storage path health history", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		s.logger.Debug("Pruned storage path health history", zap.Int64("deleted", deleted))
+	}
+}
+
+// Status returns the latest sample of a storage path, or nil when it has not
+// been checked yet.
+func (s *StorageHealthService) Status(storagePathID uint) *data.StoragePathHealthSample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sample, ok := s.current[storagePathID]
+	if !ok {
+		return nil
+	}
+	return &sample
+}
+
+// Statuses returns the latest sample of every checked storage path by ID.
+func (s *StorageHealthService) Statuses() map[uint]data.StoragePathHealthSample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	statuses := make(map[uint]data.StoragePathHealthSample, len(s.current))
+	for id, sample := range s.current {
+		statuses[id] = sample
+	}
+	return statuses
+}
+
+// IsOffline reports whether a storage path failed its last check. Unchecked
+// paths count as online.
+func (s *StorageHealthService) IsOffline(storagePathID uint) bool {
+	sample := s.Status(storagePathID)
+	return sample != nil && sample.Status == data.StorageHealthOffline
+}
+
+// OfflineIDs returns the IDs of the storage paths that failed their last check.
+func (s *StorageHealthService) OfflineIDs() []uint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var ids []uint
+	for id, sample := range s.current {
+		if sample.Status == data.StorageHealthOffline {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// History returns a storage path's samples over the last days, oldest first.
+func (s *StorageHealthService) History(storagePathID uint, days int) ([]data.StoragePathHealthSample, error) {
+	if days == 0 {
+		days = defaultHealthHistoryDays
+	}
+	if days < 1 || days > maxHealthHistoryDays {
+		return nil, apperrors.NewValidationErrorWithField("days", "days must be between 1 and 90")
+	}
+	if _, err := s.storagePathRepo.GetByID(storagePathID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("storage path", storagePathID)
+		}
+		return nil, apperrors.NewInternalError("failed to get storage path", err)
+	}
+	samples, err := s.repo.ListByStoragePath(storagePathID, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get storage path health history", err)
+	}
+	return samples, nil
+}
+
+// probeStoragePath checks that a path is a readable directory and reads its
+// free space. An empty directory counts as unmounted when scenes are expected
+// in it, as that is what a missing mount usually looks like.
+func probeStoragePath(path string, hasScenes bool) (free, total *int64, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, errors.New("path does not exist")
+		}
+		return nil, nil, fmt.Errorf("path is not accessible: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, nil, errors.New("path is not a directory")
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("path is not readable: %w", err)
+	}
+	_, err = dir.Readdirnames(1)
+	dir.Close()
+	if errors.Is(err, io.EOF) {
+		if hasScenes {
+			return nil, nil, errors.New("path is empty but has scenes; it may not be mounted")
+		}
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("path is not readable: %w", err)
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		// Readable but not reporting space, which does not stop scans
+		return nil, nil, nil
+	}
+	freeBytes := int64(stat.Bavail * uint64(stat.Bsize))
+	totalBytes := int64(stat.Blocks * uint64(stat.Bsize))
+	return &freeBytes, &totalBytes, nil
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestProbeStoragePath(t *testing.T) {
+	root := t.TempDir()
+
+	if _, _, err := probeStoragePath(filepath.Join(root, "missing"), false); err == nil {
+		t.Fatal("expected a missing path to be offline")
+	}
+	if _, _, err := probeStoragePath(root, true); err == nil {
+		t.Fatal("expected an empty path with scenes to be offline")
+	}
+	if _, _, err := probeStoragePath(root, false); err != nil {
+		t.Fatalf("expected an empty path without scenes to be online, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "scene.mp4"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	free, total, err := probeStoragePath(root, true)
+	if err != nil {
+		t.Fatalf("expected a readable path to be online, got %v", err)
+	}
+	if free == nil || total == nil || *total < *free {
+		t.Fatalf("expected free space, got free=%v total=%v", free, total)
+	}
+}
+
+func TestStorageHealthService_CheckAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	explorerRepo := mocks.NewMockExplorerRepository(ctrl)
+	healthRepo := mocks.NewMockStorageHealthRepository(ctrl)
+	eventBus := NewEventBus(zap.NewNop())
+	_, events := eventBus.Subscribe()
+	svc := NewStorageHealthService(nil, explorerRepo, healthRepo, eventBus, 0, 0, zap.NewNop())
+
+	mounted := true
+	svc.probe = func(path string, hasScenes bool) (*int64, *int64, error) {
+		if path == "/mnt/nas" && !mounted {
+			return nil, nil, errors.New("path does not exist")
+		}
+		return nil, nil, nil
+	}
+	explorerRepo.EXPECT().GetStoragePathsWithCounts().Return([]data.StoragePathWithCount{
+		{StoragePath: data.StoragePath{ID: 1, Name: "Local", Path: "/data"}},
+		{StoragePath: data.StoragePath{ID: 2, Name: "NAS", Path: "/mnt/nas"}, SceneCount: 3},
+	}, nil).Times(3)
+	healthRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(6)
+
+	// Paths found online on the first check are not announced
+	if _, err := svc.CheckAll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 0 || len(svc.OfflineIDs()) != 0 {
+		t.Fatalf("expected no events and no offline paths, got %d events", len(events))
+	}
+
+	mounted = false
+	samples, err := svc.CheckAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if samples[1].Status != data.StorageHealthOffline || samples[1].Error == "" {
+		t.Fatalf("expected the unmounted path offline, got %+v", samples[1])
+	}
+	if ids := svc.OfflineIDs(); len(ids) != 1 || ids[0] != 2 || !svc.IsOffline(2) || svc.IsOffline(1) {
+		t.Fatalf("unexpected offline paths %v", ids)
+	}
+	if event := <-events; event.Type != "storage:offline" || event.Data.(map[string]any)["storage_path_id"] != uint(2) {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	mounted = true
+	if _, err := svc.CheckAll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event := <-events; event.Type != "storage:online" || svc.IsOffline(2) {
+		t.Fatalf("expected the path back online, got %+v", event)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected one event per change, got %d more", len(events))
+	}
+}
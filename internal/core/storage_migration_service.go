@@ -0,0 +1,498 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// StorageMigrationPhase is the job_history phase of storage path migrations.
+// They run outside the scene processing pools, so they can't be retried from
+// the jobs page.
+const StorageMigrationPhase = "storage_migration"
+
+// storageMigrationProgressInterval throttles progress updates and events
+const storageMigrationProgressInterval = 2 * time.Second
+
+// StorageMigrationRequest moves scenes to another storage path: the given
+// scenes, or every scene under a folder of the source storage path.
+type StorageMigrationRequest struct {
+	SceneIDs            []uint `json:"scene_ids"`
+	SourceStoragePathID uint   `json:"source_storage_path_id"` // with folder_path, instead of scene_ids
+	FolderPath          string `json:"folder_path"`            // relative to the source storage path, subfolders included
+	StoragePathID       uint   `json:"storage_path_id"`        // destination
+}
+
+// StorageMigrationJob is a started migration.
+type StorageMigrationJob struct {
+	JobID         string `json:"job_id"`
+	StoragePathID uint   `json:"storage_path_id"`
+	Scenes        int    `json:"scenes"`
+	TotalBytes    int64  `json:"total_bytes"`
+}
+
+// storageMigrationSummary counts what a migration did.
+type storageMigrationSummary struct {
+	Moved     int
+	Unchanged int
+	Failed    []SceneFileError
+}
+
+// StorageMigrationService moves scene files from one storage path to another
+// in the background, for when a disk fills up. Files keep their place
+// relative to the storage path root. Across filesystems each file is copied,
+// the copy is checked against the source's checksum, the scene is pointed at
+// it and only then is the source removed. On the same filesystem files are
+// renamed. Migrations are tracked in job_history under StorageMigrationPhase
+// and report their progress as storage_migration:progress events.
+type StorageMigrationService struct {
+	sceneRepo          data.SceneRepository
+	storagePathService *StoragePathService
+	explorer           *ExplorerService
+	jobHistory         *JobHistoryService
+	eventBus           *EventBus
+	storageHealth      *StorageHealthService
+	logger             *zap.Logger
+
+	mu     sync.Mutex
+	jobID  string // the running migration
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewStorageMigrationService(
+	sceneRepo data.SceneRepository,
+	storagePathService *StoragePathService,
+	explorer *ExplorerService,
+	jobHistory *JobHistoryService,
+	eventBus *EventBus,
+	logger *zap.Logger,
+) *StorageMigrationService {
+	return &StorageMigrationService{
+		sceneRepo:          sceneRepo,
+		storagePathService: storagePathService,
+		explorer:           explorer,
+		jobHistory:         jobHistory,
+		eventBus:           eventBus,
+		logger:             logger.With(zap.String("component", "storage_migration")),
+	}
+}
+
+// SetStorageHealth refuses migrations into an offline storage path
+func (s *StorageMigrationService) SetStorageHealth(storageHealth *StorageHealthService) {
+	s.storageHealth = storageHealth
+}
+
+// Start checks a migration and runs it in the background. Only one migration
+// runs at a time, and the destination must have room for every file.
+func (s *StorageMigrationService) Start(req StorageMigrationRequest) (*StorageMigrationJob, error) {
+	dest, err := s.storagePath(req.StoragePathID)
+	if err != nil {
+		return nil, err
+	}
+	if s.storageHealth != nil && s.storageHealth.IsOffline(dest.ID) {
+		return nil, apperrors.NewConflictError("storage migration", fmt.Sprintf("storage path %s is offline", dest.Name))
+	}
+
+	sceneIDs := req.SceneIDs
+	if len(sceneIDs) == 0 && req.SourceStoragePathID != 0 {
+		if sceneIDs, err = s.explorer.GetFolderSceneIDs(req.SourceStoragePathID, req.FolderPath, true); err != nil {
+			return nil, err
+		}
+	}
+	if len(sceneIDs) == 0 {
+		return nil, apperrors.NewValidationError("no scenes to migrate: give scene_ids, or a source storage path and folder with scenes")
+	}
+	scenes, err := s.sceneRepo.GetByIDs(sceneIDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get scenes", err)
+	}
+	if len(scenes) != len(sceneIDs) {
+		return nil, apperrors.NewValidationError("one or more scenes not found")
+	}
+
+	job := &StorageMigrationJob{JobID: uuid.New().String(), StoragePathID: dest.ID, Scenes: len(scenes)}
+	for i := range scenes {
+		if scenes[i].StoragePathID == nil || *scenes[i].StoragePathID != dest.ID {
+			job.TotalBytes += scenes[i].Size
+		}
+	}
+	if usage := s.storagePathService.GetDiskUsage(dest.Path); usage != nil && uint64(job.TotalBytes) > usage.FreeBytes {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("storage path %s has %d bytes free, the scenes need %d", dest.Name, usage.FreeBytes, job.TotalBytes))
+	}
+
+	s.mu.Lock()
+	if s.jobID != "" {
+		s.mu.Unlock()
+		return nil, apperrors.NewConflictError("storage migration", "a storage migration is already running")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.jobID, s.cancel = job.JobID, cancel
+	s.mu.Unlock()
+
+	// Migrations have no scene; their history row is keyed by the job ID, so a
+	// row a crash left running doesn't block the next migration
+	s.jobHistory.RecordJobStart(job.JobID, 0, fmt.Sprintf("Migrate %d scenes to %s", len(scenes), dest.Name), StorageMigrationPhase)
+	s.logger.Info("Storage migration started",
+		zap.String("job_id", job.JobID),
+		zap.Uint("storage_path_id", dest.ID),
+		zap.Int("scenes", len(scenes)),
+		zap.Int64("bytes", job.TotalBytes),
+	)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.finish(cancel)
+		s.run(ctx, job, dest, scenes)
+	}()
+	return job, nil
+}
+
+// Owns reports whether a job ID is the running migration, for cancelling it
+// from the jobs page.
+func (s *StorageMigrationService) Owns(jobID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobID != "" && s.jobID == jobID
+}
+
+// Cancel stops the running migration after its current file. A file being
+// copied is discarded and stays at its source.
+func (s *StorageMigrationService) Cancel(jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jobID == "" || s.jobID != jobID {
+		return apperrors.NewNotFoundError("storage migration", jobID)
+	}
+	s.cancel()
+	return nil
+}
+
+// Stop cancels a running migration and waits for it, so no copy is left
+// half-written at shutdown.
+func (s *StorageMigrationService) Stop() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *StorageMigrationService) finish(cancel context.CancelFunc) {
+	cancel()
+	s.mu.Lock()
+	s.jobID, s.cancel = "", nil
+	s.mu.Unlock()
+}
+
+func (s *StorageMigrationService) storagePath(id uint) (*data.StoragePath, error) {
+	storagePath, err := s.storagePathService.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("storage path", id)
+		}
+		return nil, apperrors.NewInternalError("failed to get storage path", err)
+	}
+	return storagePath, nil
+}
+
+// run migrates the scenes one at a time. A scene that fails is reported and
+// the others still move.
+func (s *StorageMigrationService) run(ctx context.Context, job *StorageMigrationJob, dest *data.StoragePath, scenes []data.Scene) {
+	roots := make(map[uint]string)
+	if paths, err := s.storagePathService.List(); err == nil {
+		for _, p := range paths {
+			roots[p.ID] = p.Path
+		}
+	}
+
+	progress := &storageMigrationProgress{service: s, job: job, lastReport: time.Now()}
+	summary := storageMigrationSummary{Failed: []SceneFileError{}}
+	var movedIDs []uint
+	for i := range scenes {
+		if ctx.Err() != nil {
+			break
+		}
+		scene := &scenes[i]
+		if scene.StoragePathID != nil && *scene.StoragePathID == dest.ID {
+			summary.Unchanged++
+			progress.sceneDone(0)
+			continue
+		}
+		if scene.TrashedAt != nil || scene.StoredPath == "" {
+			summary.Failed = append(summary.Failed, SceneFileError{SceneID: scene.ID, Error: "scene has no file to move"})
+			progress.sceneDone(scene.Size)
+			continue
+		}
+
+		newPath := filepath.Join(dest.Path, migrationRelPath(scene, roots))
+		before := progress.bytes
+		if err := s.migrateScene(ctx, scene, newPath, dest.ID, progress); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			s.logger.Warn("Failed to migrate scene file",
+				zap.Uint("id", scene.ID),
+				zap.String("path", scene.StoredPath),
+				zap.String("destination", newPath),
+				zap.Error(err),
+			)
+			summary.Failed = append(summary.Failed, SceneFileError{SceneID: scene.ID, Error: err.Error()})
+		} else {
+			movedIDs = append(movedIDs, scene.ID)
+			summary.Moved++
+		}
+		// Renames and failures count the whole file
+		progress.bytes = before
+		progress.sceneDone(scene.Size)
+	}
+
+	s.explorer.reindexMovedScenes(movedIDs)
+
+	status := "completed"
+	switch {
+	case ctx.Err() != nil:
+		status = "cancelled"
+		s.jobHistory.RecordJobCancelled(job.JobID)
+	case len(summary.Failed) > 0 && summary.Moved == 0:
+		status = "failed"
+		s.jobHistory.RecordJobFailed(job.JobID, fmt.Errorf("no scene could be migrated: %s", summary.Failed[0].Error))
+	default:
+		s.jobHistory.RecordJobComplete(job.JobID)
+	}
+	if s.eventBus != nil {
+		s.eventBus.Publish(SceneEvent{
+			Type: "storage_migration:" + status,
+			Data: map[string]any{
+				"job_id":          job.JobID,
+				"storage_path_id": dest.ID,
+				"moved":           summary.Moved,
+				"unchanged":       summary.Unchanged,
+				"failed":          summary.Failed,
+			},
+		})
+	}
+	s.logger.Info("Storage migration finished",
+		zap.String("job_id", job.JobID),
+		zap.String("status", status),
+		zap.Int("moved", summary.Moved),
+		zap.Int("unchanged", summary.Unchanged),
+		zap.Int("failed", len(summary.Failed)),
+	)
+}
+
+// migrateScene moves a scene's file to newPath and points the scene at it.
+// Across filesystems the file is copied and verified first, and the source is
+// only removed once the scene is updated.
+func (s *StorageMigrationService) migrateScene(ctx context.Context, scene *data.Scene, newPath string, storagePathID uint, progress *storageMigrationProgress) error {
+	oldPath := scene.StoredPath
+	if _, err := os.Lstat(newPath); err == nil {
+		return errDestinationExists
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create destination folder: %w", err)
+	}
+
+	copied := false
+	err := os.Rename(oldPath, newPath)
+	var linkErr *os.LinkError
+	if err != nil {
+		if !errors.As(err, &linkErr) || !errors.Is(linkErr.Err, syscall.EXDEV) {
+			return err
+		}
+		// A hidden name keeps scans away from the partial copy
+		tmpPath := filepath.Join(filepath.Dir(newPath), "."+filepath.Base(newPath)+".migrating")
+		if err := copyFileVerified(ctx, oldPath, tmpPath, progress); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+		if err := moveFile(tmpPath, newPath); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+		copied = true
+	}
+
+	if err := s.sceneRepo.UpdateStoredPath(scene.ID, newPath, &storagePathID); err != nil {
+		if copied {
+			os.Remove(newPath)
+		} else if rbErr := os.Rename(newPath, oldPath); rbErr != nil {
+			s.logger.Error("Failed to move scene file back after a failed update",
+				zap.Uint("id", scene.ID),
+				zap.String("path", newPath),
+				zap.Error(rbErr),
+			)
+		}
+		return fmt.Errorf("failed to update stored path: %w", err)
+	}
+	scene.StoredPath, scene.StoragePathID = newPath, &storagePathID
+
+	if copied {
+		if err := os.Remove(oldPath); err != nil {
+			s.logger.Warn("Failed to remove migrated source file",
+				zap.Uint("id", scene.ID),
+				zap.String("path", oldPath),
+				zap.Error(err),
+			)
+		}
+	}
+	moveSidecars(scene.ID, oldPath, newPath, s.logger)
+	publishFileMoved(s.eventBus, scene, oldPath)
+	return nil
+}
+
+// migrationRelPath returns where a scene goes relative to the destination
+// root: its place in its storage path, or just its file name when it is not
+// inside one.
+func migrationRelPath(scene *data.Scene, roots map[uint]string) string {
+	if scene.StoragePathID != nil {
+		if root, ok := roots[*scene.StoragePathID]; ok && isWithin(scene.StoredPath, root) {
+			if rel, err := filepath.Rel(root, scene.StoredPath); err == nil && !strings.HasPrefix(rel, "..") {
+				return rel
+			}
+		}
+	}
+	return filepath.Base(scene.StoredPath)
+}
+
+// copyFileVerified copies src to a new file dst, like copyFile, then reads
+// dst back and compares its checksum with the source's. The copy stops when
+// ctx is cancelled.
+func copyFileVerified(ctx context.Context, src, dst string, progress io.Writer) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	srcHash := sha256.New()
+	reader := io.TeeReader(&contextReader{ctx: ctx, r: in}, srcHash)
+	written, err := io.Copy(io.MultiWriter(out, progress), reader)
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != info.Size() {
+		return fmt.Errorf("copy incomplete: wrote %d of %d bytes", written, info.Size())
+	}
+
+	dstHash, err := fileChecksum(ctx, dst, sha256.New())
+	if err != nil {
+		return fmt.Errorf("failed to verify copy: %w", err)
+	}
+	if !bytes.Equal(srcHash.Sum(nil), dstHash) {
+		return errors.New("copy does not match the source")
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+func fileChecksum(ctx context.Context, path string, h hash.Hash) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, &contextReader{ctx: ctx, r: f}); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// contextReader stops a copy once its context is cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// storageMigrationProgress counts the migrated bytes and reports them every
+// storageMigrationProgressInterval: on the job and as a
+// storage_migration:progress event.
+type storageMigrationProgress struct {
+	service *StorageMigrationService
+	job     *StorageMigrationJob
+
+	bytes      int64
+	scenes     int
+	lastReport time.Time
+}
+
+func (p *storageMigrationProgress) Write(b []byte) (int, error) {
+	p.bytes += int64(len(b))
+	if time.Since(p.lastReport) >= storageMigrationProgressInterval {
+		p.report()
+	}
+	return len(b), nil
+}
+
+func (p *storageMigrationProgress) sceneDone(size int64) {
+	p.bytes += size
+	p.scenes++
+	p.report()
+}
+
+func (p *storageMigrationProgress) report() {
+	p.lastReport = time.Now()
+	percent := 100
+	if p.job.TotalBytes > 0 {
+		percent = int(min(100, p.bytes*100/p.job.TotalBytes))
+	} else if p.job.Scenes > 0 {
+		percent = p.scenes * 100 / p.job.Scenes
+	}
+
+	s := p.service
+	s.jobHistory.UpdateProgress(p.job.JobID, percent)
+	if s.eventBus != nil {
+		s.eventBus.Publish(SceneEvent{
+			Type: "storage_migration:progress",
+			Data: map[string]any{
+				"job_id":      p.job.JobID,
+				"progress":    percent,
+				"scenes_done": p.scenes,
+				"scenes":      p.job.Scenes,
+				"bytes_done":  p.bytes,
+				"total_bytes": p.job.TotalBytes,
+			},
+		})
+	}
+}
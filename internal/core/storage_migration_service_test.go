@@ -0,0 +1,218 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestStorageMigrationService(t *testing.T) (
+	*StorageMigrationService,
+	*mocks.MockStoragePathRepository,
+	*mocks.MockSceneRepository,
+	*mocks.MockJobHistoryRepository,
+) {
+	ctrl := gomock.NewController(t)
+	storagePathRepo := mocks.NewMockStoragePathRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	jobHistoryRepo := mocks.NewMockJobHistoryRepository(ctrl)
+	storagePathService := NewStoragePathService(storagePathRepo, zap.NewNop())
+	jobHistory := NewJobHistoryService(jobHistoryRepo, config.ProcessingConfig{}, zap.NewNop())
+	explorer := &ExplorerService{sceneRepo: sceneRepo, logger: zap.NewNop()}
+	svc := NewStorageMigrationService(sceneRepo, storagePathService, explorer, jobHistory, nil, zap.NewNop())
+	return svc, storagePathRepo, sceneRepo, jobHistoryRepo
+}
+
+func TestStorageMigrationService_Start(t *testing.T) {
+	svc, storagePathRepo, sceneRepo, jobHistoryRepo := newTestStorageMigrationService(t)
+	src := t.TempDir()
+	dest := t.TempDir()
+	oldPath := filepath.Join(src, "a", "scene.mp4")
+	writeTestFile(t, oldPath)
+	writeTestFile(t, filepath.Join(src, "a", "scene.nfo"))
+	already := filepath.Join(dest, "here.mp4")
+	writeTestFile(t, already)
+	srcID, destID := uint(1), uint(2)
+
+	storagePathRepo.EXPECT().GetByID(destID).Return(&data.StoragePath{ID: destID, Name: "Spare", Path: dest}, nil)
+	storagePathRepo.EXPECT().List().Return([]data.StoragePath{{ID: srcID, Path: src}, {ID: destID, Path: dest}}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{1, 2}).Return([]data.Scene{
+		{ID: 1, StoredPath: oldPath, Size: 9, StoragePathID: &srcID},
+		{ID: 2, StoredPath: already, Size: 8, StoragePathID: &destID},
+	}, nil)
+	newPath := filepath.Join(dest, "a", "scene.mp4")
+	sceneRepo.EXPECT().UpdateStoredPath(uint(1), newPath, gomock.Any()).Return(nil)
+	jobHistoryRepo.EXPECT().Create(gomock.Any()).Return(nil)
+	jobHistoryRepo.EXPECT().UpdateProgress(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	done := make(chan string, 1)
+	jobHistoryRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ string, status string, _ *string, _ *time.Time) error {
+			done <- status
+			return nil
+		})
+
+	job, err := svc.Start(StorageMigrationRequest{SceneIDs: []uint{1, 2}, StoragePathID: destID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Scenes != 2 || job.TotalBytes != 9 {
+		t.Fatalf("unexpected job %+v", job)
+	}
+	if !svc.Owns(job.JobID) {
+		t.Fatal("expected the job to be owned while running")
+	}
+
+	select {
+	case status := <-done:
+		if status != "completed" {
+			t.Fatalf("expected the migration to complete, got %s", status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("migration did not finish")
+	}
+	svc.Stop()
+
+	if _, err := os.Stat(newPath); err != nil {
+		t.Fatalf("expected the file in the destination: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "a", "scene.nfo")); err != nil {
+		t.Fatalf("expected the sidecar moved along: %v", err)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Fatalf("expected the source removed, got %v", err)
+	}
+	if svc.Owns(job.JobID) {
+		t.Fatal("expected the job released once finished")
+	}
+}
+
+func TestStorageMigrationService_HistoryKeyedPerJob(t *testing.T) {
+	svc, storagePathRepo, sceneRepo, jobHistoryRepo := newTestStorageMigrationService(t)
+	dest := t.TempDir()
+	already := filepath.Join(dest, "here.mp4")
+	writeTestFile(t, already)
+	destID := uint(2)
+
+	storagePathRepo.EXPECT().GetByID(destID).Return(&data.StoragePath{ID: destID, Name: "Spare", Path: dest}, nil).Times(2)
+	storagePathRepo.EXPECT().List().Return([]data.StoragePath{{ID: destID, Path: dest}}, nil).AnyTimes()
+	sceneRepo.EXPECT().GetByIDs([]uint{2}).Return([]data.Scene{
+		{ID: 2, StoredPath: already, Size: 8, StoragePathID: &destID},
+	}, nil).Times(2)
+	jobHistoryRepo.EXPECT().UpdateProgress(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	// Stand in for idx_job_history_active_key. Rows are never released, like
+	// the running row of a migration the server crashed during
+	active := map[string]string{}
+	jobHistoryRepo.EXPECT().Create(gomock.Any()).Times(2).DoAndReturn(func(record *data.JobHistory) error {
+		record.BeforeCreate(nil)
+		key := fmt.Sprintf("%d:%s:%s", record.SceneID, record.Phase, record.ParamsHash)
+		if other, ok := active[key]; ok {
+			t.Errorf("job %s has the active key of job %s", record.JobID, other)
+			return errors.New("duplicate key value violates unique constraint")
+		}
+		active[key] = record.JobID
+		return nil
+	})
+	done := make(chan string, 2)
+	jobHistoryRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ string, status string, _ *string, _ *time.Time) error {
+			done <- status
+			return nil
+		})
+
+	for range 2 {
+		if _, err := svc.Start(StorageMigrationRequest{SceneIDs: []uint{2}, StoragePathID: destID}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("migration did not finish")
+		}
+		svc.Stop()
+	}
+	if len(active) != 2 {
+		t.Fatalf("expected both migrations recorded, got %d", len(active))
+	}
+}
+
+func TestStorageMigrationService_StartRejects(t *testing.T) {
+	svc, storagePathRepo, _, _ := newTestStorageMigrationService(t)
+	storagePathRepo.EXPECT().GetByID(uint(2)).Return(&data.StoragePath{ID: 2, Path: t.TempDir()}, nil)
+	if _, err := svc.Start(StorageMigrationRequest{StoragePathID: 2}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a migration without scenes to be rejected, got %v", err)
+	}
+
+	storagePathRepo.EXPECT().GetByID(uint(3)).Return(nil, errors.New("record not found"))
+	if _, err := svc.Start(StorageMigrationRequest{SceneIDs: []uint{1}, StoragePathID: 3}); err == nil {
+		t.Fatal("expected an unknown storage path to be rejected")
+	}
+}
+
+func TestStorageMigrationService_MigrateSceneRollsBack(t *testing.T) {
+	svc, _, sceneRepo, _ := newTestStorageMigrationService(t)
+	root := t.TempDir()
+	oldPath := filepath.Join(root, "src", "scene.mp4")
+	writeTestFile(t, oldPath)
+	newPath := filepath.Join(root, "dest", "scene.mp4")
+
+	sceneRepo.EXPECT().UpdateStoredPath(uint(1), newPath, gomock.Any()).Return(errors.New("db down"))
+	scene := &data.Scene{ID: 1, StoredPath: oldPath}
+	progress := &storageMigrationProgress{service: svc, job: &StorageMigrationJob{}}
+	if err := svc.migrateScene(context.Background(), scene, newPath, 2, progress); err == nil {
+		t.Fatal("expected the failed update to be reported")
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		t.Fatalf("expected the file moved back: %v", err)
+	}
+	if scene.StoredPath != oldPath {
+		t.Fatalf("expected the scene untouched, got %s", scene.StoredPath)
+	}
+}
+
+func TestCopyFileVerified(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "scene.mp4")
+	writeTestFile(t, src)
+	dst := filepath.Join(root, "copy.mp4")
+
+	var progress countingWriter
+	if err := copyFileVerified(context.Background(), src, dst, &progress); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content, _ := os.ReadFile(dst); string(content) != "scene.mp4" {
+		t.Fatalf("unexpected copy %q", content)
+	}
+	if progress != countingWriter(len("scene.mp4")) {
+		t.Fatalf("expected the copied bytes reported, got %d", progress)
+	}
+	// An existing destination is never overwritten
+	if err := copyFileVerified(context.Background(), src, dst, &progress); err == nil {
+		t.Fatal("expected an existing destination to be refused")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := copyFileVerified(ctx, src, filepath.Join(root, "cancelled.mp4"), &progress); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancelled copy to stop, got %v", err)
+	}
+}
+
+type countingWriter int
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"goonhub/internal/data"
 	"os"
+	"strings"
 	"syscall"
 
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -88,9 +90,10 @@ func (s *StoragePathService) Create(name, path string, isDefault bool) (*data.St
 	}
 
 	storagePath := &data.StoragePath{
-		Name:      name,
-		Path:      path,
-		IsDefault: isDefault,
+		Name:            name,
+		Path:            path,
+		IsDefault:       isDefault,
+		ExcludePatterns: pq.StringArray{},
 	}
 
 	if err := s.repo.Create(storagePath); err != nil {
@@ -157,6 +160,74 @@ func (s *StoragePathService) Update(id uint, name, path string, isDefault bool)
 	return existing, nil
 }
 
+// UpdateScanSchedule sets the cron expression for scheduled scans of a storage
+// path and turns the schedule on or off. An empty expression clears it, which
+// also disables it.
+func (s *StoragePathService) UpdateScanSchedule(id uint, schedule string, enabled bool) (*data.StoragePath, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage path: %w", err)
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("storage path not found")
+	}
+
+	schedule = strings.TrimSpace(schedule)
+	if schedule == "" {
+		if enabled {
+			return nil, fmt.Errorf("a scan schedule is required to enable scheduled scans")
+		}
+		existing.ScanSchedule = nil
+	} else {
+		if _, err := scanScheduleParser.Parse(schedule); err != nil {
+			return nil, fmt.Errorf("invalid scan schedule: %w", err)
+		}
+		existing.ScanSchedule = &schedule
+	}
+	existing.ScanScheduleEnabled = enabled
+
+	if err := s.repo.Update(existing); err != nil {
+		return nil, fmt.Errorf("failed to update storage path: %w", err)
+	}
+
+	s.logger.Info("Updated storage path scan schedule",
+		zap.Uint("id", id),
+		zap.String("scan_schedule", schedule),
+		zap.Bool("enabled", enabled),
+	)
+
+	return existing, nil
+}
+
+// UpdateExcludePatterns sets the glob patterns of files and directories the
+// scanner skips in this storage path.
+func (s *StoragePathService) UpdateExcludePatterns(id uint, patterns []string) (*data.StoragePath, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage path: %w", err)
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("storage path not found")
+	}
+
+	patterns = cleanExcludePatterns(patterns)
+	if err := ValidateExcludePatterns(patterns); err != nil {
+		return nil, err
+	}
+	existing.ExcludePatterns = patterns
+
+	if err := s.repo.Update(existing); err != nil {
+		return nil, fmt.Errorf("failed to update storage path: %w", err)
+	}
+
+	s.logger.Info("Updated storage path exclude patterns",
+		zap.Uint("id", id),
+		zap.Strings("exclude_patterns", patterns),
+	)
+
+	return existing, nil
+}
+
 // GetDiskUsage returns filesystem usage stats for the given path.
 // Returns nil on error (logged as warning, never fails the request).
 func (s *StoragePathService) GetDiskUsage(path string) *DiskUsage {
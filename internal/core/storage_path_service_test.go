@@ -103,3 +103,35 @@ func TestListWithDiskUsage_InvalidPathReturnsNilUsage(t *testing.T) {
 		t.Fatal("expected nil usage for nonexistent path")
 	}
 }
+
+func TestUpdateScanSchedule(t *testing.T) {
+	svc, repo := newTestStoragePathService(t)
+	repo.EXPECT().GetByID(uint(1)).Return(&data.StoragePath{ID: 1}, nil)
+	repo.EXPECT().Update(gomock.Any()).Return(nil)
+
+	path, err := svc.UpdateScanSchedule(1, " 0 3 * * * ", true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if path.ScanSchedule == nil || *path.ScanSchedule != "0 3 * * *" || !path.ScanScheduleEnabled {
+		t.Fatalf("unexpected schedule %v enabled=%v", path.ScanSchedule, path.ScanScheduleEnabled)
+	}
+}
+
+func TestUpdateScanSchedule_Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		schedule string
+		enabled  bool
+	}{
+		{"every night", true},
+		{"0 3 * * * *", false}, // seconds field is not supported
+		{"", true},
+	} {
+		svc, repo := newTestStoragePathService(t)
+		repo.EXPECT().GetByID(uint(1)).Return(&data.StoragePath{ID: 1}, nil)
+
+		if _, err := svc.UpdateScanSchedule(1, tt.schedule, tt.enabled); err == nil {
+			t.Fatalf("%q: expected an error", tt.schedule)
+		}
+	}
+}
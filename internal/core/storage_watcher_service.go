@@ -0,0 +1,318 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/data"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// storageWatcherRootRefresh is how often the watched roots are re-synced with
+// the configured storage paths.
+const storageWatcherRootRefresh = time.Minute
+
+// StorageWatcherService watches the storage paths for video files being added,
+// removed or renamed and applies the scan logic to just those files, so the
+// library stays current without a full scan. Changes are debounced: a path is
+// only handled once it has not changed for the debounce period, which lets
+// large copies finish first.
+type StorageWatcherService struct {
+	storagePathService *StoragePathService
+	scanService        *ScanService
+	enabled            bool
+	debounce           time.Duration
+	logger             *zap.Logger
+
+	mu      sync.Mutex
+	watcher *fsnotify.Watcher
+	roots   map[string]data.StoragePath // storage path root -> storage path
+	dirs    map[string]struct{}         // watched directories
+	pending map[string]time.Time        // changed path -> last event time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewStorageWatcherService(
+	storagePathService *StoragePathService,
+	scanService *ScanService,
+	enabled bool,
+	debounce time.Duration,
+	logger *zap.Logger,
+) *StorageWatcherService {
+	if debounce <= 0 {
+		debounce = 5 * time.Second
+	}
+	return &StorageWatcherService{
+		storagePathService: storagePathService,
+		scanService:        scanService,
+		enabled:            enabled,
+		debounce:           debounce,
+		logger:             logger,
+		roots:              make(map[string]data.StoragePath),
+		dirs:               make(map[string]struct{}),
+		pending:            make(map[string]time.Time),
+	}
+}
+
+// Start begins watching the storage paths. It is a no-op when disabled.
+func (s *StorageWatcherService) Start() {
+	if !s.enabled || s.storagePathService == nil || s.scanService == nil {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		s.logger.Error("Failed to create storage watcher", zap.Error(err))
+		return
+	}
+	s.watcher = watcher
+	s.refreshRoots()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		tick := time.NewTicker(s.debounce / 2)
+		defer tick.Stop()
+		refresh := time.NewTicker(storageWatcherRootRefresh)
+		defer refresh.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				s.handleEvent(event)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				// Overflows drop events; the next full scan catches up
+				s.logger.Warn("Storage watcher error", zap.Error(err))
+			case <-tick.C:
+				s.processPending(time.Now())
+			case <-refresh.C:
+				s.refreshRoots()
+			}
+		}
+	}()
+
+	s.logger.Info("Storage watcher started", zap.Duration("debounce", s.debounce))
+}
+
+// Stop halts the watcher. Pending changes are dropped.
+func (s *StorageWatcherService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+	if s.watcher != nil {
+		s.watcher.Close()
+	}
+}
+
+// refreshRoots starts watching new storage paths and stops watching removed ones.
+func (s *StorageWatcherService) refreshRoots() {
+	paths, err := s.storagePathService.List()
+	if err != nil {
+		s.logger.Warn("Failed to list storage paths for watcher", zap.Error(err))
+		return
+	}
+
+	current := make(map[string]data.StoragePath, len(paths))
+	for _, sp := range paths {
+		current[filepath.Clean(sp.Path)] = sp
+	}
+
+	s.mu.Lock()
+	var added []string
+	for root, sp := range current {
+		if _, ok := s.roots[root]; !ok {
+			added = append(added, root)
+		}
+		s.roots[root] = sp
+	}
+	for root := range s.roots {
+		if _, ok := current[root]; ok {
+			continue
+		}
+		delete(s.roots, root)
+		for dir := range s.dirs {
+			if isWithin(dir, root) {
+				_ = s.watcher.Remove(dir)
+				delete(s.dirs, dir)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	for _, root := range added {
+		s.watchTree(root, false)
+	}
+}
+
+// watchTree adds a watch for dir and every directory below it. Inotify is not
+// recursive, so directories created later are added as their events arrive.
+// With queueFiles, video files found in the tree are queued too, covering
+// folders moved into a storage path in one go.
+func (s *StorageWatcherService) watchTree(dir string, queueFiles bool) {
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if queueFiles && isVideoExtension(strings.ToLower(filepath.Ext(path))) {
+				s.queue(path, time.Now())
+			}
+			return nil
+		}
+		if err := s.watcher.Add(path); err != nil {
+			s.logger.Warn("Failed to watch directory", zap.String("path", path), zap.Error(err))
+			return nil
+		}
+		s.mu.Lock()
+		s.dirs[path] = struct{}{}
+		s.mu.Unlock()
+		return nil
+	})
+}
+
+// handleEvent records a filesystem event. A rename produces a Rename event for
+// the old path and a Create event for the new one, so moves are handled as a
+// removal plus an addition.
+func (s *StorageWatcherService) handleEvent(event fsnotify.Event) {
+	path := filepath.Clean(event.Name)
+
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			s.watchTree(path, true)
+			return
+		}
+	}
+
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		s.mu.Lock()
+		_, wasDir := s.dirs[path]
+		if wasDir {
+			for dir := range s.dirs {
+				if isWithin(dir, path) {
+					delete(s.dirs, dir)
+				}
+			}
+		}
+		s.mu.Unlock()
+		if wasDir {
+			s.queue(path, time.Now())
+			return
+		}
+	}
+
+	if !isVideoExtension(strings.ToLower(filepath.Ext(path))) {
+		return
+	}
+	if event.Has(fsnotify.Create) || event.Has(fsnotify.Write) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		s.queue(path, time.Now())
+	}
+}
+
+func (s *StorageWatcherService) queue(path string, at time.Time) {
+	s.mu.Lock()
+	s.pending[path] = at
+	s.mu.Unlock()
+}
+
+// duePaths removes and returns the pending paths that have not changed for the
+// debounce period, in path order.
+func (s *StorageWatcherService) duePaths(now time.Time) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []string
+	for path, at := range s.pending {
+		if now.Sub(at) >= s.debounce {
+			due = append(due, path)
+			delete(s.pending, path)
+		}
+	}
+	sort.Strings(due)
+	return due
+}
+
+// processPending handles the settled paths. Paths that exist are imported
+// before missing paths are removed, so a file renamed inside the library is
+// detected as a move rather than soft-deleted first. Nothing is handled while
+// a full scan runs; the paths stay queued until it ends.
+func (s *StorageWatcherService) processPending(now time.Time) {
+	if s.scanService.IsRunning() {
+		return
+	}
+	due := s.duePaths(now)
+	if len(due) == 0 {
+		return
+	}
+
+	var missing []string
+	for _, path := range due {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			missing = append(missing, path)
+			continue
+		}
+		storagePath, ok := s.storagePathFor(path)
+		if !ok {
+			continue
+		}
+		if err := s.scanService.ImportFile(path, &storagePath); err != nil {
+			s.logger.Warn("Failed to import watched file", zap.String("path", path), zap.Error(err))
+		}
+	}
+
+	for _, path := range missing {
+		if _, ok := s.storagePathFor(path); !ok {
+			continue
+		}
+		if _, err := s.scanService.RemoveMissing(path); err != nil {
+			s.logger.Warn("Failed to remove missing watched file", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
+
+// storagePathFor returns the storage path containing path, preferring the
+// deepest root when storage paths are nested.
+func (s *StorageWatcherService) storagePathFor(path string) (data.StoragePath, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var best data.StoragePath
+	bestLen := -1
+	for root, sp := range s.roots {
+		if isWithin(path, root) && len(root) > bestLen {
+			best = sp
+			bestLen = len(root)
+		}
+	}
+	return best, bestLen >= 0
+}
+
+// isWithin reports whether path is dir or below it.
+func isWithin(path, dir string) bool {
+	if path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
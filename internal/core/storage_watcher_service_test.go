@@ -0,0 +1,189 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestStorageWatcher_DuePathsWaitsForDebounce(t *testing.T) {
+	svc := NewStorageWatcherService(nil, nil, true, 5*time.Second, zap.NewNop())
+	now := time.Now()
+	svc.queue("/videos/b.mp4", now.Add(-6*time.Second))
+	svc.queue("/videos/a.mp4", now.Add(-5*time.Second))
+	svc.queue("/videos/c.mp4", now.Add(-time.Second))
+
+	due := svc.duePaths(now)
+	if len(due) != 2 || due[0] != "/videos/a.mp4" || due[1] != "/videos/b.mp4" {
+		t.Fatalf("expected the two settled paths in order, got %v", due)
+	}
+	if _, ok := svc.pending["/videos/c.mp4"]; !ok || len(svc.pending) != 1 {
+		t.Fatalf("expected only the recent path to stay pending, got %v", svc.pending)
+	}
+}
+
+func TestStorageWatcher_StoragePathForPrefersDeepestRoot(t *testing.T) {
+	svc := NewStorageWatcherService(nil, nil, true, time.Second, zap.NewNop())
+	svc.roots["/media"] = data.StoragePath{ID: 1, Path: "/media"}
+	svc.roots["/media/clips"] = data.StoragePath{ID: 2, Path: "/media/clips"}
+
+	tests := []struct {
+		path string
+		id   uint
+		ok   bool
+	}{
+		{"/media/a.mp4", 1, true},
+		{"/media/clips/x/b.mp4", 2, true},
+		{"/media/clipsarchive/c.mp4", 1, true},
+		{"/other/d.mp4", 0, false},
+	}
+	for _, tt := range tests {
+		sp, ok := svc.storagePathFor(tt.path)
+		if ok != tt.ok || sp.ID != tt.id {
+			t.Fatalf("%s: expected storage path %d (%v), got %d (%v)", tt.path, tt.id, tt.ok, sp.ID, ok)
+		}
+	}
+}
+
+func TestStorageWatcher_HandleEvent(t *testing.T) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	root := t.TempDir()
+	svc := NewStorageWatcherService(nil, nil, true, time.Second, zap.NewNop())
+	svc.watcher = watcher
+
+	// Non-video files are ignored
+	svc.handleEvent(fsnotify.Event{Name: filepath.Join(root, "notes.txt"), Op: fsnotify.Create})
+	if len(svc.pending) != 0 {
+		t.Fatalf("expected non-video file to be ignored, got %v", svc.pending)
+	}
+
+	// A folder moved in is watched and its videos are queued
+	dir := filepath.Join(root, "incoming")
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	video := filepath.Join(dir, "sub", "clip.MKV")
+	if err := os.WriteFile(video, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	svc.handleEvent(fsnotify.Event{Name: dir, Op: fsnotify.Create})
+	if _, ok := svc.pending[video]; !ok {
+		t.Fatalf("expected video in new folder to be queued, got %v", svc.pending)
+	}
+	if _, ok := svc.dirs[filepath.Join(dir, "sub")]; !ok {
+		t.Fatalf("expected nested folder to be watched, got %v", svc.dirs)
+	}
+
+	// Removing a watched folder queues the folder and forgets its watches
+	svc.handleEvent(fsnotify.Event{Name: dir, Op: fsnotify.Rename})
+	if _, ok := svc.pending[dir]; !ok {
+		t.Fatalf("expected renamed folder to be queued, got %v", svc.pending)
+	}
+	if len(svc.dirs) != 0 {
+		t.Fatalf("expected folder watches to be forgotten, got %v", svc.dirs)
+	}
+}
+
+func TestScanService_ImportFile(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "new.mp4")
+	if err := os.WriteFile(path, []byte("video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	storagePath := &data.StoragePath{ID: 4, Path: root}
+
+	t.Run("restores a soft-deleted scene as a move", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		sceneRepo := mocks.NewMockSceneRepository(ctrl)
+		svc := NewScanService(nil, sceneRepo, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+		sceneRepo.EXPECT().ExistsByStoredPath(path).Return(false, nil)
+		sceneRepo.EXPECT().GetBySizeAndFilename(int64(5), "new.mp4").Return(&data.Scene{
+			ID:         9,
+			StoredPath: filepath.Join(root, "old", "new.mp4"),
+			DeletedAt:  gorm.DeletedAt{Time: time.Now(), Valid: true},
+		}, nil)
+		sceneRepo.EXPECT().Restore(uint(9)).Return(nil)
+		sceneRepo.EXPECT().UpdateStoredPath(uint(9), path, &storagePath.ID).Return(nil)
+
+		if err := svc.ImportFile(path, storagePath); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("creates a new scene", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		sceneRepo := mocks.NewMockSceneRepository(ctrl)
+		svc := NewScanService(nil, sceneRepo, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+		sceneRepo.EXPECT().ExistsByStoredPath(path).Return(false, nil)
+		sceneRepo.EXPECT().GetBySizeAndFilename(int64(5), "new.mp4").Return(nil, nil)
+		sceneRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(scene *data.Scene) error {
+			if scene.StoredPath != path || scene.Title != "new" || *scene.StoragePathID != 4 {
+				t.Fatalf("unexpected scene %+v", scene)
+			}
+			return nil
+		})
+
+		if err := svc.ImportFile(path, storagePath); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("skips known paths", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		sceneRepo := mocks.NewMockSceneRepository(ctrl)
+		svc := NewScanService(nil, sceneRepo, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+		sceneRepo.EXPECT().ExistsByStoredPath(path).Return(true, nil)
+
+		if err := svc.ImportFile(path, storagePath); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestScanService_RemoveMissing(t *testing.T) {
+	root := t.TempDir()
+	kept := filepath.Join(root, "dir", "kept.mp4")
+	if err := os.MkdirAll(filepath.Dir(kept), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(kept, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	svc := NewScanService(nil, sceneRepo, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	dir := filepath.Join(root, "dir")
+	sceneRepo.EXPECT().GetScenePathsForMissingDetection().Return([]data.ScenePathInfo{
+		{ID: 1, StoredPath: kept},
+		{ID: 2, StoredPath: filepath.Join(dir, "gone.mp4")},
+		{ID: 3, StoredPath: filepath.Join(root, "dirty", "gone.mp4")},
+	}, nil)
+	sceneRepo.EXPECT().MarkAsMissing(uint(2)).Return(nil)
+
+	removed, err := svc.RemoveMissing(dir)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected one scene removed, got %d", removed)
+	}
+}
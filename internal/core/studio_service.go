@@ -2,8 +2,10 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"goonhub/internal/apperrors"
 	"goonhub/internal/data"
+	"slices"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -15,6 +17,7 @@ type StudioService struct {
 	sceneRepo  data.SceneRepository
 	logger     *zap.Logger
 	indexer    SceneIndexer
+	rbac       *RBACService
 }
 
 func NewStudioService(studioRepo data.StudioRepository, sceneRepo data.SceneRepository, logger *zap.Logger) *StudioService {
@@ -25,6 +28,11 @@ func NewStudioService(studioRepo data.StudioRepository, sceneRepo data.SceneRepo
 	}
 }
 
+// SetRBACService limits studio scene lists to the scenes the user's role may see.
+func (s *StudioService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
+}
+
 // SetIndexer sets the scene indexer for search index updates.
 func (s *StudioService) SetIndexer(indexer SceneIndexer) {
 	s.indexer = indexer
@@ -66,6 +74,11 @@ func (s *StudioService) Create(input CreateStudioInput) (*data.Studio, error) {
 		return nil, apperrors.NewValidationErrorWithField("name", "studio name must be 255 characters or less")
 	}
 
+	parentID, networkID, err := s.hierarchy(nil, input.ParentID, input.NetworkID)
+	if err != nil {
+		return nil, err
+	}
+
 	studio := &data.Studio{
 		UUID:        uuid.New(),
 		Name:        input.Name,
@@ -77,8 +90,8 @@ func (s *StudioService) Create(input CreateStudioInput) (*data.Studio, error) {
 		Favicon:     input.Favicon,
 		Poster:      input.Poster,
 		PornDBID:    input.PornDBID,
-		ParentID:    input.ParentID,
-		NetworkID:   input.NetworkID,
+		ParentID:    parentID,
+		NetworkID:   networkID,
 	}
 
 	if err := s.studioRepo.Create(studio); err != nil {
@@ -174,11 +187,17 @@ func (s *StudioService) Update(id uint, input UpdateStudioInput) (*data.Studio,
 	if input.PornDBID != nil {
 		studio.PornDBID = *input.PornDBID
 	}
-	if input.ParentID != nil {
-		studio.ParentID = input.ParentID
-	}
-	if input.NetworkID != nil {
-		studio.NetworkID = input.NetworkID
+	if input.ParentID != nil || input.NetworkID != nil {
+		parentID, networkID := studio.ParentID, studio.NetworkID
+		if input.ParentID != nil {
+			parentID = input.ParentID
+		}
+		if input.NetworkID != nil {
+			networkID = input.NetworkID
+		}
+		if studio.ParentID, studio.NetworkID, err = s.hierarchy(studio, parentID, networkID); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.studioRepo.Update(studio); err != nil {
@@ -268,7 +287,10 @@ func (s *StudioService) SetSceneStudio(sceneID uint, studioID *uint) (*data.Stud
 	return studio, nil
 }
 
-func (s *StudioService) GetStudioScenes(studioID uint, page, limit int) ([]data.Scene, int64, error) {
+// GetStudioScenes returns a studio's scenes. With includeChildren, the scenes
+// of every studio under it are included, so a network lists its whole catalog.
+// Scenes role may not see are left out.
+func (s *StudioService) GetStudioScenes(studioID uint, role string, page, limit int, includeChildren bool) ([]data.Scene, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -283,7 +305,63 @@ func (s *StudioService) GetStudioScenes(studioID uint, page, limit int) ([]data.
 		return nil, 0, apperrors.NewInternalError("failed to find studio", err)
 	}
 
-	return s.studioRepo.GetStudioScenes(studioID, page, limit)
+	restriction := restrictionFor(s.rbac, role)
+	if !includeChildren {
+		return s.studioRepo.GetStudioScenes(studioID, restriction, page, limit)
+	}
+	descendantIDs, err := s.studioRepo.GetDescendantIDs(studioID)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to find child studios", err)
+	}
+	return s.studioRepo.GetScenesByStudioIDs(append([]uint{studioID}, descendantIDs...), restriction, page, limit)
+}
+
+// GetChildren returns the studios directly under a studio.
+func (s *StudioService) GetChildren(studioID uint) ([]data.StudioWithCount, error) {
+	children, err := s.studioRepo.GetChildren(studioID)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get child studios", err)
+	}
+	return children, nil
+}
+
+// hierarchy validates a studio's parent and network, returning them with an
+// ID of 0 cleared. Both must exist and, for an existing studio, be neither the
+// studio itself nor a studio under it.
+func (s *StudioService) hierarchy(studio *data.Studio, parentID, networkID *uint) (*uint, *uint, error) {
+	var descendantIDs []uint
+	if studio != nil {
+		var err error
+		if descendantIDs, err = s.studioRepo.GetDescendantIDs(studio.ID); err != nil {
+			return nil, nil, apperrors.NewInternalError("failed to find child studios", err)
+		}
+	}
+
+	check := func(field string, id *uint) (*uint, error) {
+		if id == nil || *id == 0 {
+			return nil, nil
+		}
+		if studio != nil && (*id == studio.ID || slices.Contains(descendantIDs, *id)) {
+			return nil, apperrors.NewValidationErrorWithField(field, "a studio cannot be under itself")
+		}
+		if _, err := s.studioRepo.GetByID(*id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, apperrors.NewValidationErrorWithField(field, fmt.Sprintf("studio %d not found", *id))
+			}
+			return nil, apperrors.NewInternalError("failed to find studio", err)
+		}
+		return id, nil
+	}
+
+	parentID, err := check("parent_id", parentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	networkID, err = check("network_id", networkID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parentID, networkID, nil
 }
 
 func (s *StudioService) UpdateLogoURL(id uint, logoURL string) (*data.Studio, error) {
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"testing"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestStudioService_Update_Hierarchy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	studioRepo := mocks.NewMockStudioRepository(ctrl)
+	svc := NewStudioService(studioRepo, nil, zap.NewNop())
+
+	network := uint(1)
+	studio := &data.Studio{ID: 2, Name: "Site", NetworkID: &network}
+	studioRepo.EXPECT().GetByID(uint(2)).Return(studio, nil).AnyTimes()
+	studioRepo.EXPECT().GetDescendantIDs(uint(2)).Return([]uint{3}, nil).AnyTimes()
+
+	self := uint(2)
+	if _, err := svc.Update(2, UpdateStudioInput{ParentID: &self}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a studio under itself to be rejected, got %v", err)
+	}
+	child := uint(3)
+	if _, err := svc.Update(2, UpdateStudioInput{NetworkID: &child}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a cycle through a child studio to be rejected, got %v", err)
+	}
+	missing := uint(9)
+	studioRepo.EXPECT().GetByID(uint(9)).Return(nil, gorm.ErrRecordNotFound)
+	if _, err := svc.Update(2, UpdateStudioInput{ParentID: &missing}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected a missing parent to be rejected, got %v", err)
+	}
+
+	parent := uint(4)
+	none := uint(0)
+	studioRepo.EXPECT().GetByID(uint(4)).Return(&data.Studio{ID: 4}, nil)
+	studioRepo.EXPECT().Update(studio).Return(nil)
+	if _, err := svc.Update(2, UpdateStudioInput{ParentID: &parent, NetworkID: &none}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if studio.ParentID == nil || *studio.ParentID != 4 || studio.NetworkID != nil {
+		t.Fatalf("expected the parent set and the network cleared, got parent=%v network=%v", studio.ParentID, studio.NetworkID)
+	}
+}
+
+func TestStudioService_GetStudioScenes_IncludeChildren(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	studioRepo := mocks.NewMockStudioRepository(ctrl)
+	svc := NewStudioService(studioRepo, nil, zap.NewNop())
+
+	studioRepo.EXPECT().GetByID(uint(1)).Return(&data.Studio{ID: 1}, nil).Times(2)
+	studioRepo.EXPECT().GetStudioScenes(uint(1), nil, 1, 20).Return([]data.Scene{{ID: 10}}, int64(1), nil)
+	if _, total, err := svc.GetStudioScenes(1, "user", 1, 20, false); err != nil || total != 1 {
+		t.Fatalf("unexpected result total=%d err=%v", total, err)
+	}
+
+	studioRepo.EXPECT().GetDescendantIDs(uint(1)).Return([]uint{2, 3}, nil)
+	studioRepo.EXPECT().GetScenesByStudioIDs([]uint{1, 2, 3}, nil, 1, 20).Return([]data.Scene{{ID: 10}, {ID: 11}, {ID: 12}}, int64(3), nil)
+	if _, total, err := svc.GetStudioScenes(1, "user", 1, 20, true); err != nil || total != 3 {
+		t.Fatalf("unexpected result total=%d err=%v", total, err)
+	}
+}
+
+func TestStudioService_GetStudioScenes_Restricted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	studioRepo := mocks.NewMockStudioRepository(ctrl)
+	svc := NewStudioService(studioRepo, nil, zap.NewNop())
+	rbac, _, _ := newTestRestrictedRBACService(t, []data.ContentRestriction{
+		{RoleID: 2, RoleName: "guest", SceneTypes: []string{"standard"}},
+	})
+	svc.SetRBACService(rbac)
+
+	studioRepo.EXPECT().GetByID(uint(1)).Return(&data.Studio{ID: 1}, nil)
+	studioRepo.EXPECT().GetStudioScenes(uint(1), rbac.ContentRestriction("guest"), 1, 20).Return([]data.Scene{}, int64(0), nil)
+	if _, _, err := svc.GetStudioScenes(1, "guest", 1, 20, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
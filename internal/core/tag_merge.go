@@ -0,0 +1,72 @@
+package core
+
+import (
+	"errors"
+	"slices"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MergeTags combines duplicate tags into one. Everything tagged with the
+// sources (scenes, markers, marker label defaults, playlists, folder rules and
+// role restrictions) is tagged with the target instead and the sources are
+// deleted. Their names and aliases become the target's aliases, so the old
+// names still resolve to the target. The affected scenes are re-indexed.
+func (s *TagService) MergeTags(targetID uint, sourceIDs []uint) (*data.Tag, error) {
+	var ids []uint
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, apperrors.NewValidationErrorWithField("source_ids", "a tag cannot be merged into itself")
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, apperrors.NewValidationErrorWithField("source_ids", "at least one tag to merge is required")
+	}
+
+	target, err := s.tagRepo.GetByID(targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrTagNotFound(targetID)
+		}
+		return nil, apperrors.NewInternalError("failed to find tag", err)
+	}
+	sources, err := s.tagRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to find tags", err)
+	}
+	aliases := append([]string{}, target.Aliases...)
+	for _, id := range ids {
+		i := slices.IndexFunc(sources, func(t data.Tag) bool { return t.ID == id })
+		if i < 0 {
+			return nil, apperrors.ErrTagNotFound(id)
+		}
+		aliases = append(aliases, sources[i].Name)
+		aliases = append(aliases, sources[i].Aliases...)
+	}
+	target.Aliases = tagAliases(target.Name, aliases)
+
+	// The scenes change tags, so collect them before they move
+	sceneIDs, err := s.tagRepo.GetAllSceneIDs(append([]uint{targetID}, ids...))
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to find tagged scenes", err)
+	}
+	if err := s.tagRepo.Merge(target, ids); err != nil {
+		return nil, apperrors.NewInternalError("failed to merge tags", err)
+	}
+	s.reindexScenes(sceneIDs)
+
+	s.logger.Info("Tags merged",
+		zap.Uint("id", target.ID),
+		zap.String("name", target.Name),
+		zap.Uints("merged_ids", ids),
+		zap.Int("scenes", len(sceneIDs)),
+	)
+	return target, nil
+}
@@ -2,9 +2,14 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"goonhub/internal/apperrors"
 	"goonhub/internal/data"
 	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -65,6 +70,144 @@ func (s *TagService) CreateTag(name, color string) (*data.Tag, error) {
 	return tag, nil
 }
 
+// UpdateTagInput holds the tag fields to change; nil fields are left alone.
+type UpdateTagInput struct {
+	Name    *string
+	Color   *string
+	Aliases *[]string
+}
+
+// UpdateTag renames, recolors or sets the aliases of a tag. A renamed tag keeps
+// its old name as an alias, so sidecars, scrapes and searches using it still
+// find the tag. Names and aliases can't be those of another tag. The tag's
+// scenes are re-indexed when its names change.
+func (s *TagService) UpdateTag(id uint, input UpdateTagInput) (*data.Tag, error) {
+	tag, err := s.tagRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrTagNotFound(id)
+		}
+		return nil, apperrors.NewInternalError("failed to find tag", err)
+	}
+
+	oldName, oldAliases := tag.Name, slices.Clone(tag.Aliases)
+	aliases := tag.Aliases
+	if input.Aliases != nil {
+		aliases = *input.Aliases
+	}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, apperrors.NewValidationErrorWithField("name", "tag name is required")
+		}
+		if len(name) > 100 {
+			return nil, apperrors.NewValidationErrorWithField("name", "tag name must be 100 characters or less")
+		}
+		if name != tag.Name {
+			aliases = append(pq.StringArray{tag.Name}, aliases...)
+			tag.Name = name
+		}
+	}
+	if input.Color != nil {
+		if !colorRegex.MatchString(*input.Color) {
+			return nil, apperrors.NewValidationErrorWithField("color", "invalid color format, must be a hex color like #6B7280")
+		}
+		tag.Color = *input.Color
+	}
+	tag.Aliases = tagAliases(tag.Name, aliases)
+
+	if err := s.checkTagNamesFree(tag); err != nil {
+		return nil, err
+	}
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, apperrors.NewInternalError("failed to update tag", err)
+	}
+
+	if tag.Name != oldName || !slices.Equal(tag.Aliases, oldAliases) {
+		if sceneIDs, err := s.tagRepo.GetAllSceneIDs([]uint{tag.ID}); err != nil {
+			s.logger.Warn("Failed to get scene IDs for tag re-indexing", zap.Uint("tag_id", tag.ID), zap.Error(err))
+		} else {
+			s.reindexScenes(sceneIDs)
+		}
+	}
+
+	s.logger.Info("Tag updated", zap.Uint("id", tag.ID), zap.String("name", tag.Name), zap.String("old_name", oldName))
+	return tag, nil
+}
+
+// checkTagNamesFree rejects a tag whose name or aliases belong to another tag.
+func (s *TagService) checkTagNamesFree(tag *data.Tag) error {
+	names := append([]string{tag.Name}, tag.Aliases...)
+	others, err := s.tagRepo.GetByNames(names)
+	if err != nil {
+		return apperrors.NewInternalError("failed to find tags", err)
+	}
+	for _, other := range others {
+		if other.ID == tag.ID {
+			continue
+		}
+		otherNames := append([]string{other.Name}, other.Aliases...)
+		if slices.Contains(otherNames, tag.Name) {
+			return apperrors.ErrTagAlreadyExists(tag.Name)
+		}
+		for _, alias := range tag.Aliases {
+			if slices.Contains(otherNames, alias) {
+				return apperrors.NewValidationErrorWithField("aliases", fmt.Sprintf("%q is already a name of tag %q", alias, other.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// tagAliases trims and deduplicates aliases, dropping the tag's own name.
+func tagAliases(name string, aliases []string) pq.StringArray {
+	cleaned := pq.StringArray{}
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || alias == name || len(alias) > 100 {
+			continue
+		}
+		if !slices.Contains(cleaned, alias) {
+			cleaned = append(cleaned, alias)
+		}
+	}
+	return cleaned
+}
+
+// DeleteUnusedTags deletes the tags no live scene, marker, playlist, folder
+// rule or role restriction uses. No indexed scene has them, so nothing needs
+// re-indexing.
+func (s *TagService) DeleteUnusedTags() ([]data.Tag, error) {
+	tags, err := s.tagRepo.DeleteUnused()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to delete unused tags", err)
+	}
+	s.logger.Info("Unused tags deleted", zap.Int("count", len(tags)))
+	return tags, nil
+}
+
+// tagReindexBatchSize is how many scenes are re-indexed per request after a
+// tag change.
+const tagReindexBatchSize = 500
+
+// reindexScenes updates the search index of scenes whose tags changed.
+func (s *TagService) reindexScenes(sceneIDs []uint) {
+	if s.indexer == nil {
+		return
+	}
+	for start := 0; start < len(sceneIDs); start += tagReindexBatchSize {
+		batch := sceneIDs[start:min(start+tagReindexBatchSize, len(sceneIDs))]
+		scenes, err := s.sceneRepo.GetByIDs(batch)
+		if err != nil {
+			s.logger.Warn("Failed to fetch scenes for tag re-indexing", zap.Int("count", len(batch)), zap.Error(err))
+			continue
+		}
+		if err := s.indexer.BulkUpdateSceneIndex(scenes); err != nil {
+			s.logger.Warn("Failed to re-index scenes after tag change", zap.Int("count", len(scenes)), zap.Error(err))
+		}
+	}
+}
+
 func (s *TagService) DeleteTag(id uint) error {
 	if _, err := s.tagRepo.GetByID(id); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -289,3 +289,104 @@ func TestCreateTag_ValidColorFormats(t *testing.T) {
 		})
 	}
 }
+
+type recordingSceneIndexer struct {
+	updated []uint
+}
+
+func (r *recordingSceneIndexer) IndexScene(scene *data.Scene) error       { return nil }
+func (r *recordingSceneIndexer) UpdateSceneIndex(scene *data.Scene) error { return nil }
+func (r *recordingSceneIndexer) DeleteSceneIndex(id uint) error           { return nil }
+func (r *recordingSceneIndexer) BulkDeleteSceneIndex(ids []uint) error    { return nil }
+func (r *recordingSceneIndexer) BulkUpdateSceneIndex(scenes []data.Scene) error {
+	for _, scene := range scenes {
+		r.updated = append(r.updated, scene.ID)
+	}
+	return nil
+}
+
+func TestUpdateTag_RenameKeepsOldNameAsAlias(t *testing.T) {
+	svc, tagRepo, sceneRepo := newTestTagService(t)
+	indexer := &recordingSceneIndexer{}
+	svc.SetIndexer(indexer)
+
+	tag := &data.Tag{ID: 1, Name: "Outdoor", Color: "#6B7280", Aliases: []string{"Outside"}}
+	tagRepo.EXPECT().GetByID(uint(1)).Return(tag, nil)
+	tagRepo.EXPECT().GetByNames([]string{"Outdoors", "Outdoor", "Outside"}).Return([]data.Tag{*tag}, nil)
+	tagRepo.EXPECT().Update(tag).Return(nil)
+	tagRepo.EXPECT().GetAllSceneIDs([]uint{1}).Return([]uint{10, 11}, nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{10, 11}).Return([]data.Scene{{ID: 10}, {ID: 11}}, nil)
+
+	name := " Outdoors "
+	updated, err := svc.UpdateTag(1, UpdateTagInput{Name: &name})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if updated.Name != "Outdoors" || strings.Join(updated.Aliases, ",") != "Outdoor,Outside" {
+		t.Fatalf("unexpected tag %+v", updated)
+	}
+	if len(indexer.updated) != 2 {
+		t.Fatalf("expected the tag's scenes to be re-indexed, got %v", indexer.updated)
+	}
+}
+
+func TestUpdateTag_NameOfAnotherTag(t *testing.T) {
+	svc, tagRepo, _ := newTestTagService(t)
+
+	tagRepo.EXPECT().GetByID(uint(1)).DoAndReturn(func(id uint) (*data.Tag, error) {
+		return &data.Tag{ID: 1, Name: "Outdoor"}, nil
+	}).Times(2)
+	tagRepo.EXPECT().GetByNames(gomock.Any()).Return([]data.Tag{{ID: 2, Name: "Outside", Aliases: []string{"Al Fresco"}}}, nil).Times(2)
+
+	name := "Outside"
+	if _, err := svc.UpdateTag(1, UpdateTagInput{Name: &name}); !apperrors.IsConflict(err) {
+		t.Fatalf("expected a conflict, got: %v", err)
+	}
+	aliases := []string{"Al Fresco"}
+	if _, err := svc.UpdateTag(1, UpdateTagInput{Aliases: &aliases}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected another tag's alias to be rejected, got: %v", err)
+	}
+}
+
+func TestMergeTags_Success(t *testing.T) {
+	svc, tagRepo, sceneRepo := newTestTagService(t)
+	indexer := &recordingSceneIndexer{}
+	svc.SetIndexer(indexer)
+
+	tagRepo.EXPECT().GetByID(uint(1)).Return(&data.Tag{ID: 1, Name: "Outdoor"}, nil)
+	tagRepo.EXPECT().GetByIDs([]uint{3, 2}).Return([]data.Tag{
+		{ID: 2, Name: "Outside", Aliases: []string{"Outdoor"}},
+		{ID: 3, Name: "Al Fresco"},
+	}, nil)
+	tagRepo.EXPECT().GetAllSceneIDs([]uint{1, 3, 2}).Return([]uint{10}, nil)
+	tagRepo.EXPECT().Merge(gomock.Any(), []uint{3, 2}).Return(nil)
+	sceneRepo.EXPECT().GetByIDs([]uint{10}).Return([]data.Scene{{ID: 10}}, nil)
+
+	tag, err := svc.MergeTags(1, []uint{3, 2, 3})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if got := strings.Join(tag.Aliases, ","); got != "Al Fresco,Outside" {
+		t.Fatalf("unexpected aliases %q", got)
+	}
+	if len(indexer.updated) != 1 || indexer.updated[0] != 10 {
+		t.Fatalf("expected the affected scenes to be re-indexed, got %v", indexer.updated)
+	}
+}
+
+func TestMergeTags_Validation(t *testing.T) {
+	svc, tagRepo, _ := newTestTagService(t)
+
+	if _, err := svc.MergeTags(1, nil); !apperrors.IsValidation(err) {
+		t.Fatalf("expected no sources to be rejected, got: %v", err)
+	}
+	if _, err := svc.MergeTags(1, []uint{1}); !apperrors.IsValidation(err) {
+		t.Fatalf("expected merging a tag into itself to be rejected, got: %v", err)
+	}
+
+	tagRepo.EXPECT().GetByID(uint(1)).Return(&data.Tag{ID: 1, Name: "Outdoor"}, nil)
+	tagRepo.EXPECT().GetByIDs([]uint{2}).Return([]data.Tag{}, nil)
+	if _, err := svc.MergeTags(1, []uint{2}); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected a missing source to be reported, got: %v", err)
+	}
+}
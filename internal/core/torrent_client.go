@@ -0,0 +1,270 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"goonhub/internal/config"
+)
+
+// TorrentDownload is a completed torrent of the watched category.
+type TorrentDownload struct {
+	Hash  string
+	Name  string
+	Files []string // downloaded files, as paths on the client's side
+}
+
+// torrentClient lists the completed torrents of a category from a torrent
+// client's API.
+type torrentClient interface {
+	CompletedTorrents(ctx context.Context, category string) ([]TorrentDownload, error)
+}
+
+func newTorrentClient(cfg config.TorrentConfig, httpClient *http.Client) torrentClient {
+	if cfg.Client == config.TorrentClientTransmission {
+		return &transmissionClient{
+			endpoint: cfg.URL,
+			username: cfg.Username,
+			password: cfg.Password,
+			client:   httpClient,
+		}
+	}
+	// The web UI session lives in a cookie
+	jar, _ := cookiejar.New(nil)
+	client := *httpClient
+	client.Jar = jar
+	return &qbittorrentClient{
+		baseURL:  strings.TrimSuffix(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		client:   &client,
+	}
+}
+
+// qbittorrentClient talks to the qBittorrent web API (v2).
+type qbittorrentClient struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+}
+
+type qbittorrentTorrent struct {
+	Hash     string  `json:"hash"`
+	Name     string  `json:"name"`
+	SavePath string  `json:"save_path"`
+	Progress float64 `json:"progress"`
+}
+
+type qbittorrentFile struct {
+	Name     string  `json:"name"` // relative to the save path
+	Progress float64 `json:"progress"`
+	Priority int     `json:"priority"` // 0 = not downloaded
+}
+
+func (c *qbittorrentClient) CompletedTorrents(ctx context.Context, category string) ([]TorrentDownload, error) {
+	var torrents []qbittorrentTorrent
+	query := url.Values{"category": {category}, "filter": {"completed"}}
+	if err := c.get(ctx, "/api/v2/torrents/info?"+query.Encode(), &torrents); err != nil {
+		return nil, err
+	}
+
+	downloads := make([]TorrentDownload, 0, len(torrents))
+	for _, t := range torrents {
+		if t.Progress < 1 {
+			continue
+		}
+		var files []qbittorrentFile
+		if err := c.get(ctx, "/api/v2/torrents/files?"+url.Values{"hash": {t.Hash}}.Encode(), &files); err != nil {
+			return nil, err
+		}
+		download := TorrentDownload{Hash: strings.ToLower(t.Hash), Name: t.Name}
+		for _, f := range files {
+			if f.Priority != 0 && f.Progress >= 1 {
+				download.Files = append(download.Files, path.Join(t.SavePath, f.Name))
+			}
+		}
+		downloads = append(downloads, download)
+	}
+	return downloads, nil
+}
+
+// get decodes a GET response, logging in when the session is missing or
+// expired.
+func (c *qbittorrentClient) get(ctx context.Context, endpoint string, out any) error {
+	resp, err := c.do(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		if err := c.login(ctx); err != nil {
+			return err
+		}
+		if resp, err = c.do(ctx, endpoint); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("qBittorrent returned HTTP %d for %s", resp.StatusCode, endpoint)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *qbittorrentClient) do(ctx context.Context, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("qBittorrent request failed: %w", err)
+	}
+	return resp, nil
+}
+
+func (c *qbittorrentClient) login(ctx context.Context) error {
+	form := url.Values{"username": {c.username}, "password": {c.password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v2/auth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("qBittorrent login failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "Ok." {
+		return errors.New("qBittorrent login failed: check the username and password")
+	}
+	return nil
+}
+
+// transmissionSessionHeader carries Transmission's CSRF token, which a 409
+// response hands out.
+const transmissionSessionHeader = "X-Transmission-Session-Id"
+
+// transmissionClient talks to the Transmission RPC API. Categories are
+// Transmission labels.
+type transmissionClient struct {
+	endpoint string
+	username string
+	password string
+	client   *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+type transmissionTorrent struct {
+	HashString  string   `json:"hashString"`
+	Name        string   `json:"name"`
+	DownloadDir string   `json:"downloadDir"`
+	PercentDone float64  `json:"percentDone"`
+	Labels      []string `json:"labels"`
+	Files       []struct {
+		Name           string `json:"name"` // relative to the download dir
+		Length         int64  `json:"length"`
+		BytesCompleted int64  `json:"bytesCompleted"`
+	} `json:"files"`
+}
+
+func (c *transmissionClient) CompletedTorrents(ctx context.Context, category string) ([]TorrentDownload, error) {
+	var result struct {
+		Torrents []transmissionTorrent `json:"torrents"`
+	}
+	fields := []string{"hashString", "name", "downloadDir", "percentDone", "labels", "files"}
+	if err := c.call(ctx, "torrent-get", map[string]any{"fields": fields}, &result); err != nil {
+		return nil, err
+	}
+
+	var downloads []TorrentDownload
+	for _, t := range result.Torrents {
+		if t.PercentDone < 1 || !slices.Contains(t.Labels, category) {
+			continue
+		}
+		download := TorrentDownload{Hash: strings.ToLower(t.HashString), Name: t.Name}
+		for _, f := range t.Files {
+			// Unwanted files are never completed
+			if f.BytesCompleted == f.Length {
+				download.Files = append(download.Files, path.Join(t.DownloadDir, f.Name))
+			}
+		}
+		downloads = append(downloads, download)
+	}
+	return downloads, nil
+}
+
+// call runs an RPC method, fetching a session ID first when needed.
+func (c *transmissionClient) call(ctx context.Context, method string, arguments any, out any) error {
+	body, err := json.Marshal(map[string]any{"method": method, "arguments": arguments})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.post(ctx, body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusConflict {
+		resp.Body.Close()
+		c.mu.Lock()
+		c.sessionID = resp.Header.Get(transmissionSessionHeader)
+		c.mu.Unlock()
+		if resp, err = c.post(ctx, body); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("Transmission rejected the credentials: check the username and password")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Transmission returned HTTP %d", resp.StatusCode)
+	}
+
+	var envelope struct {
+		Result    string          `json:"result"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("invalid Transmission response: %w", err)
+	}
+	if envelope.Result != "success" {
+		return fmt.Errorf("Transmission %s failed: %s", method, envelope.Result)
+	}
+	return json.Unmarshal(envelope.Arguments, out)
+}
+
+func (c *transmissionClient) post(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.mu.Lock()
+	req.Header.Set(transmissionSessionHeader, c.sessionID)
+	c.mu.Unlock()
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Transmission request failed: %w", err)
+	}
+	return resp, nil
+}
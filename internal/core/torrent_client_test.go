@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"goonhub/internal/config"
+)
+
+func TestQBittorrentClient_CompletedTorrents(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			logins++
+			if r.FormValue("username") != "admin" || r.FormValue("password") != "secret" {
+				w.Write([]byte("Fails."))
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session", Path: "/"})
+			w.Write([]byte("Ok."))
+			return
+		}
+		if cookie, err := r.Cookie("SID"); err != nil || cookie.Value != "session" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			if r.URL.Query().Get("category") != "goonhub" || r.URL.Query().Get("filter") != "completed" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode([]qbittorrentTorrent{
+				{Hash: "ABC", Name: "Release", SavePath: "/downloads", Progress: 1},
+			})
+		case "/api/v2/torrents/files":
+			json.NewEncoder(w).Encode([]qbittorrentFile{
+				{Name: "Release/scene.mp4", Progress: 1, Priority: 1},
+				{Name: "Release/extra.mp4", Progress: 0, Priority: 0},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.TorrentConfig{Client: config.TorrentClientQBittorrent, URL: server.URL + "/", Username: "admin", Password: "secret"}
+	client := newTorrentClient(cfg, server.Client())
+
+	for range 2 {
+		torrents, err := client.CompletedTorrents(context.Background(), "goonhub")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []TorrentDownload{{Hash: "abc", Name: "Release", Files: []string{"/downloads/Release/scene.mp4"}}}
+		if !reflect.DeepEqual(torrents, want) {
+			t.Fatalf("expected %+v, got %+v", want, torrents)
+		}
+	}
+	if logins != 1 {
+		t.Fatalf("expected the session to be reused, got %d logins", logins)
+	}
+
+	cfg.Password = "wrong"
+	if _, err := newTorrentClient(cfg, server.Client()).CompletedTorrents(context.Background(), "goonhub"); err == nil {
+		t.Fatal("expected a failed login to be reported")
+	}
+}
+
+func TestTransmissionClient_CompletedTorrents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(transmissionSessionHeader) != "token" {
+			w.Header().Set(transmissionSessionHeader, "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Method != "torrent-get" {
+			t.Errorf("unexpected method %q", req.Method)
+		}
+		w.Write([]byte(`{"result":"success","arguments":{"torrents":[
+			{"hashString":"def","name":"Done","downloadDir":"/downloads","percentDone":1,"labels":["goonhub"],
+			 "files":[{"name":"Done/a.mkv","length":10,"bytesCompleted":10},{"name":"Done/b.mkv","length":10,"bytesCompleted":0}]},
+			{"hashString":"ghi","name":"Other","downloadDir":"/downloads","percentDone":1,"labels":["tv"],"files":[]},
+			{"hashString":"jkl","name":"Partial","downloadDir":"/downloads","percentDone":0.5,"labels":["goonhub"],"files":[]}
+		]}}`))
+	}))
+	defer server.Close()
+
+	cfg := config.TorrentConfig{Client: config.TorrentClientTransmission, URL: server.URL, Username: "admin", Password: "secret"}
+	torrents, err := newTorrentClient(cfg, server.Client()).CompletedTorrents(context.Background(), "goonhub")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []TorrentDownload{{Hash: "def", Name: "Done", Files: []string{"/downloads/Done/a.mkv"}}}
+	if !reflect.DeepEqual(torrents, want) {
+		t.Fatalf("expected %+v, got %+v", want, torrents)
+	}
+}
@@ -0,0 +1,345 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// torrentClientTimeout bounds listing the completed torrents
+const torrentClientTimeout = time.Minute
+
+// torrentSamplePattern matches the names of the sample clips shipped with
+// many releases: "sample", "sample-name" or "name-sample".
+var torrentSamplePattern = regexp.MustCompile(`(?i)^sample$|^sample[-._]|[-._]sample$`)
+
+// TorrentImportResult reports one pass over the torrent client.
+type TorrentImportResult struct {
+	Imported []data.TorrentImport `json:"imported"`
+	Failed   []data.TorrentImport `json:"failed"`
+}
+
+// TorrentImportService polls a qBittorrent or Transmission client for the
+// completed torrents of a category and imports their video files into a
+// storage path through the scan logic, like the dropbox. Every file handled
+// is recorded in torrent_imports, mapping the torrent to its scene, so it is
+// imported once.
+type TorrentImportService struct {
+	cfg                config.TorrentConfig
+	repo               data.TorrentImportRepository
+	storagePathService *StoragePathService
+	scanService        *ScanService
+	sceneRepo          data.SceneRepository
+	storageHealth      *StorageHealthService
+	client             torrentClient
+	logger             *zap.Logger
+
+	mu sync.Mutex // one pass at a time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewTorrentImportService(
+	cfg config.TorrentConfig,
+	repo data.TorrentImportRepository,
+	storagePathService *StoragePathService,
+	scanService *ScanService,
+	sceneRepo data.SceneRepository,
+	logger *zap.Logger,
+) *TorrentImportService {
+	return &TorrentImportService{
+		cfg:                cfg,
+		repo:               repo,
+		storagePathService: storagePathService,
+		scanService:        scanService,
+		sceneRepo:          sceneRepo,
+		client:             newTorrentClient(cfg, &http.Client{Timeout: 30 * time.Second}),
+		logger:             logger.With(zap.String("component", "torrent_import")),
+	}
+}
+
+// SetStorageHealth holds imports back while the destination storage path is offline
+func (s *TorrentImportService) SetStorageHealth(storageHealth *StorageHealthService) {
+	s.storageHealth = storageHealth
+}
+
+// Start polls the torrent client every poll interval. It is a no-op when
+// disabled.
+func (s *TorrentImportService) Start() {
+	if !s.cfg.Enabled {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Run(); err != nil {
+					if apperrors.IsConflict(err) {
+						s.logger.Debug("Torrent import postponed", zap.Error(err))
+					} else {
+						s.logger.Warn("Torrent import failed", zap.Error(err))
+					}
+				}
+			}
+		}
+	}()
+
+	s.logger.Info("Torrent import started",
+		zap.String("client", s.cfg.Client),
+		zap.String("category", s.cfg.Category),
+		zap.String("mode", s.cfg.Mode),
+		zap.Duration("poll_interval", s.cfg.PollInterval),
+	)
+}
+
+// Stop halts polling. A pass in progress finishes first.
+func (s *TorrentImportService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// Run imports the video files of the completed torrents that were not
+// handled yet.
+func (s *TorrentImportService) Run() (*TorrentImportResult, error) {
+	if !s.cfg.Enabled {
+		return nil, apperrors.NewValidationError("torrent import is not enabled")
+	}
+	// Like the storage watcher, leave files alone while a scan runs
+	if s.scanService.IsRunning() {
+		return nil, apperrors.NewConflictError("torrent import", "a scan is running")
+	}
+	if !s.mu.TryLock() {
+		return nil, apperrors.NewConflictError("torrent import", "an import is already running")
+	}
+	defer s.mu.Unlock()
+
+	storagePath, err := s.destination()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), torrentClientTimeout)
+	defer cancel()
+	torrents, err := s.client.CompletedTorrents(ctx, s.cfg.Category)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list completed torrents", err)
+	}
+
+	hashes := make([]string, len(torrents))
+	for i, t := range torrents {
+		hashes[i] = t.Hash
+	}
+	existing, err := s.repo.ListByTorrentHashes(hashes)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to load torrent imports", err)
+	}
+	handled := make(map[[2]string]bool, len(existing))
+	for _, item := range existing {
+		handled[[2]string{item.TorrentHash, item.FilePath}] = true
+	}
+
+	result := &TorrentImportResult{Imported: []data.TorrentImport{}, Failed: []data.TorrentImport{}}
+	for _, torrent := range torrents {
+		for _, file := range torrent.Files {
+			if handled[[2]string{torrent.Hash, file}] || !isTorrentVideoFile(file) {
+				continue
+			}
+			item := s.importFile(torrent, file, storagePath)
+			if err := s.repo.Create(item); err != nil {
+				s.logger.Warn("Failed to record torrent import", zap.String("file", file), zap.Error(err))
+			}
+			if item.Status == data.TorrentImportFailed {
+				result.Failed = append(result.Failed, *item)
+			} else {
+				result.Imported = append(result.Imported, *item)
+			}
+		}
+	}
+	return result, nil
+}
+
+// List returns the recorded imports, newest first, only those of a scene
+// when sceneID is set.
+func (s *TorrentImportService) List(page, limit int, sceneID *uint) ([]data.TorrentImport, int64, error) {
+	items, total, err := s.repo.List(page, limit, sceneID)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list torrent imports", err)
+	}
+	return items, total, nil
+}
+
+// Retry forgets a failed import, so the next pass tries its file again.
+func (s *TorrentImportService) Retry(id uint) error {
+	item, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewNotFoundError("torrent import", id)
+		}
+		return apperrors.NewInternalError("failed to get torrent import", err)
+	}
+	if item.Status != data.TorrentImportFailed {
+		return apperrors.NewValidationError("only failed imports can be retried")
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return apperrors.NewInternalError("failed to delete torrent import", err)
+	}
+	return nil
+}
+
+// destination returns the storage path files are imported into.
+func (s *TorrentImportService) destination() (*data.StoragePath, error) {
+	var storagePath *data.StoragePath
+	var err error
+	if s.cfg.StoragePathID == 0 {
+		storagePath, err = s.storagePathService.GetDefault()
+	} else {
+		storagePath, err = s.storagePathService.GetByID(s.cfg.StoragePathID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if s.cfg.StoragePathID == 0 {
+				return nil, apperrors.NewValidationError("no default storage path to import into")
+			}
+			return nil, apperrors.NewNotFoundError("storage path", s.cfg.StoragePathID)
+		}
+		return nil, apperrors.NewInternalError("failed to get storage path", err)
+	}
+	if s.storageHealth != nil && s.storageHealth.IsOffline(storagePath.ID) {
+		return nil, apperrors.NewConflictError("torrent import", fmt.Sprintf("storage path %s is offline", storagePath.Name))
+	}
+	return storagePath, nil
+}
+
+// importFile places a torrent file in the storage path and imports it. A
+// file placed in the library whose import failed is recorded as imported
+// with the error, as the next scan picks it up.
+func (s *TorrentImportService) importFile(torrent TorrentDownload, file string, storagePath *data.StoragePath) *data.TorrentImport {
+	item := &data.TorrentImport{
+		Client:        s.cfg.Client,
+		TorrentHash:   torrent.Hash,
+		TorrentName:   torrent.Name,
+		FilePath:      file,
+		StoragePathID: &storagePath.ID,
+		Status:        data.TorrentImportImported,
+	}
+
+	dest, err := s.placeFile(s.localPath(file), storagePath)
+	if err != nil {
+		s.logger.Warn("Failed to place torrent file", zap.String("torrent", torrent.Name), zap.String("file", file), zap.Error(err))
+		item.Status, item.Error = data.TorrentImportFailed, err.Error()
+		return item
+	}
+	item.Destination = dest
+
+	if err := s.scanService.ImportFile(dest, storagePath); err != nil {
+		s.logger.Warn("Failed to import torrent file", zap.String("path", dest), zap.Error(err))
+		item.Error = err.Error()
+		return item
+	}
+	if scene, err := s.sceneRepo.GetByStoredPath(dest); err == nil {
+		item.SceneID = &scene.ID
+	}
+	s.logger.Info("Imported torrent file", zap.String("torrent", torrent.Name), zap.String("path", dest))
+	return item
+}
+
+// placeFile links, copies or moves a file into the configured folder of the
+// storage path, adding " (n)" to the name of a taken destination. Copies go
+// through the download folder, so scans never see a partial file.
+func (s *TorrentImportService) placeFile(src string, storagePath *data.StoragePath) (string, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s not found; check remote_path and local_path", src)
+		}
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", src)
+	}
+
+	dest := filepath.Join(storagePath.Path, s.cfg.Folder, filepath.Base(src))
+	if _, err := os.Lstat(dest); err == nil {
+		if dest = availablePath(dest); dest == "" {
+			return "", errDestinationExists
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create destination folder: %w", err)
+	}
+
+	if s.cfg.Mode == config.TorrentModeMove {
+		return dest, moveFile(src, dest)
+	}
+	if err := os.Link(src, dest); err == nil {
+		return dest, nil
+	}
+
+	downloadDir := filepath.Join(storagePath.Path, remoteImportDownloadDir)
+	if err := os.MkdirAll(downloadDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download folder: %w", err)
+	}
+	tmpPath := filepath.Join(downloadDir, fmt.Sprintf("torrent-%d.part", time.Now().UnixNano()))
+	if err := copyFile(src, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to copy file: %w", err)
+	}
+	if err := moveFile(tmpPath, dest); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	return dest, nil
+}
+
+// localPath maps a path on the client's side to this server, through the
+// remote_path and local_path prefixes.
+func (s *TorrentImportService) localPath(remote string) string {
+	if s.cfg.RemotePath == "" {
+		return filepath.FromSlash(remote)
+	}
+	prefix := strings.TrimSuffix(s.cfg.RemotePath, "/")
+	rel, ok := strings.CutPrefix(remote, prefix)
+	if !ok || (rel != "" && !strings.HasPrefix(rel, "/")) {
+		return filepath.FromSlash(remote)
+	}
+	return filepath.Join(s.cfg.LocalPath, filepath.FromSlash(rel))
+}
+
+// isTorrentVideoFile reports whether a torrent file is a video to import,
+// leaving out sample clips.
+func isTorrentVideoFile(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	if !isVideoExtension(ext) {
+		return false
+	}
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	return !torrentSamplePattern.MatchString(name) && !strings.EqualFold(filepath.Base(filepath.Dir(file)), "sample")
+}
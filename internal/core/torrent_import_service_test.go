@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type fakeTorrentClient struct {
+	torrents []TorrentDownload
+}
+
+func (c *fakeTorrentClient) CompletedTorrents(context.Context, string) ([]TorrentDownload, error) {
+	return c.torrents, nil
+}
+
+func TestTorrentImportService_Run(t *testing.T) {
+	downloads := t.TempDir()
+	library := t.TempDir()
+	writeTestFile(t, filepath.Join(downloads, "Release", "scene.mp4"))
+	writeTestFile(t, filepath.Join(downloads, "Release", "release-sample.mp4"))
+	writeTestFile(t, filepath.Join(downloads, "Release", "info.nfo"))
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockTorrentImportRepository(ctrl)
+	storagePathRepo := mocks.NewMockStoragePathRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	storagePathService := NewStoragePathService(storagePathRepo, zap.NewNop())
+	scanService := NewScanService(storagePathService, sceneRepo, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	cfg := config.TorrentConfig{
+		Enabled:      true,
+		Client:       config.TorrentClientQBittorrent,
+		Category:     "goonhub",
+		Folder:       "torrents",
+		Mode:         config.TorrentModeCopy,
+		RemotePath:   "/downloads/",
+		LocalPath:    downloads,
+		PollInterval: time.Minute,
+	}
+	svc := NewTorrentImportService(cfg, repo, storagePathService, scanService, sceneRepo, zap.NewNop())
+	svc.client = &fakeTorrentClient{torrents: []TorrentDownload{{
+		Hash: "abc",
+		Name: "Release",
+		Files: []string{
+			"/downloads/Release/scene.mp4",
+			"/downloads/Release/release-sample.mp4",
+			"/downloads/Release/info.nfo",
+			"/downloads/Release/missing.mp4",
+			"/downloads/Release/done.mp4",
+		},
+	}}}
+
+	dest := filepath.Join(library, "torrents", "scene.mp4")
+	storagePathRepo.EXPECT().GetDefault().Return(&data.StoragePath{ID: 1, Path: library, IsDefault: true}, nil)
+	repo.EXPECT().ListByTorrentHashes([]string{"abc"}).Return([]data.TorrentImport{
+		{TorrentHash: "abc", FilePath: "/downloads/Release/done.mp4", Status: data.TorrentImportImported},
+	}, nil)
+	sceneRepo.EXPECT().ExistsByStoredPath(dest).Return(false, nil)
+	sceneRepo.EXPECT().GetBySizeAndFilename(gomock.Any(), "scene.mp4").Return(nil, nil)
+	sceneRepo.EXPECT().Create(gomock.Any()).Return(nil)
+	sceneRepo.EXPECT().GetByStoredPath(dest).Return(&data.Scene{ID: 42}, nil)
+	var recorded []data.TorrentImport
+	repo.EXPECT().Create(gomock.Any()).DoAndReturn(func(item *data.TorrentImport) error {
+		recorded = append(recorded, *item)
+		return nil
+	}).Times(2)
+
+	result, err := svc.Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Imported) != 1 || result.Imported[0].Destination != dest || *result.Imported[0].SceneID != 42 {
+		t.Fatalf("unexpected imports %+v", result.Imported)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].FilePath != "/downloads/Release/missing.mp4" {
+		t.Fatalf("expected the missing file to fail, got %+v", result.Failed)
+	}
+	if len(recorded) != 2 {
+		t.Fatalf("expected both files recorded, got %d", len(recorded))
+	}
+	// Copy mode leaves the torrent's files in place for seeding
+	if _, err := os.Stat(filepath.Join(downloads, "Release", "scene.mp4")); err != nil {
+		t.Fatalf("expected the source kept: %v", err)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Fatalf("expected the file in the library: %v", err)
+	}
+}
+
+func TestTorrentImportService_Retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockTorrentImportRepository(ctrl)
+	svc := NewTorrentImportService(config.TorrentConfig{}, repo, nil, nil, nil, zap.NewNop())
+
+	repo.EXPECT().GetByID(uint(1)).Return(&data.TorrentImport{ID: 1, Status: data.TorrentImportImported}, nil)
+	if err := svc.Retry(1); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an imported file not to be retried, got %v", err)
+	}
+
+	repo.EXPECT().GetByID(uint(2)).Return(&data.TorrentImport{ID: 2, Status: data.TorrentImportFailed}, nil)
+	repo.EXPECT().Delete(uint(2)).Return(nil)
+	if err := svc.Retry(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestIsTorrentVideoFile(t *testing.T) {
+	tests := map[string]bool{
+		"/d/Release/scene.mp4":           true,
+		"/d/Release/Free Sample Day.mkv": true,
+		"/d/Release/release-sample.mkv":  false,
+		"/d/Release/Sample.mp4":          false,
+		"/d/Release/Sample/scene.mp4":    false,
+		"/d/Release/scene.nfo":           false,
+	}
+	for file, want := range tests {
+		if got := isTorrentVideoFile(file); got != want {
+			t.Fatalf("%s: expected %v, got %v", file, want, got)
+		}
+	}
+}
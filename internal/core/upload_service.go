@@ -0,0 +1,308 @@
+package core
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const defaultUploadSessionTTL = 24 * time.Hour
+
+// UploadDirName is the folder of the scene directory holding uploads in
+// progress. Scans always skip it, see workDirs.
+const UploadDirName = ".uploads"
+
+// UploadService handles chunked uploads, so large files survive dropped
+// connections. A session is created with the file's name and size, then
+// chunks are appended at the offset the server reports until the file is
+// complete, at which point it becomes a scene like a regular upload.
+//
+// The received bytes live in <dir>/<uuid>.part, which is kept next to the
+// scene directory so completing an upload is a rename.
+type UploadService struct {
+	repo         data.UploadSessionRepository
+	sceneService *SceneService
+	dir          string
+	ttl          time.Duration
+	logger       *zap.Logger
+
+	mu    sync.Mutex
+	locks map[uuid.UUID]*sync.Mutex
+}
+
+func NewUploadService(
+	repo data.UploadSessionRepository,
+	sceneService *SceneService,
+	dir string,
+	ttl time.Duration,
+	logger *zap.Logger,
+) *UploadService {
+	if ttl <= 0 {
+		ttl = defaultUploadSessionTTL
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logger.Warn("Failed to create upload directory",
+			zap.String("directory", dir),
+			zap.Error(err),
+		)
+	}
+	return &UploadService{
+		repo:         repo,
+		sceneService: sceneService,
+		dir:          dir,
+		ttl:          ttl,
+		logger:       logger.With(zap.String("component", "upload")),
+		locks:        make(map[uuid.UUID]*sync.Mutex),
+	}
+}
+
+// CreateSession starts a chunked upload of a file of the given size.
+// Expired sessions are discarded first.
+func (s *UploadService) CreateSession(userID uint, filename, title string, size int64) (*data.UploadSession, error) {
+	filename = filepath.Base(filename)
+	if filename == "." || filename == string(filepath.Separator) {
+		return nil, apperrors.NewValidationErrorWithField("filename", "filename is required")
+	}
+	if !s.sceneService.ValidateExtension(filename) {
+		return nil, apperrors.ErrInvalidFileExtension
+	}
+	if size <= 0 {
+		return nil, apperrors.NewValidationErrorWithField("size", "size must be positive")
+	}
+
+	s.pruneExpired()
+
+	now := time.Now()
+	session := &data.UploadSession{
+		UUID:      uuid.New(),
+		UserID:    userID,
+		Filename:  filename,
+		Title:     title,
+		Size:      size,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	f, err := os.OpenFile(s.partPath(session.UUID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to create upload file", err)
+	}
+	f.Close()
+
+	if err := s.repo.Create(session); err != nil {
+		os.Remove(s.partPath(session.UUID))
+		return nil, apperrors.NewInternalError("failed to create upload session", err)
+	}
+
+	s.logger.Info("Upload session created",
+		zap.String("upload_id", session.UUID.String()),
+		zap.String("filename", filename),
+		zap.Int64("size", size),
+	)
+	return session, nil
+}
+
+// GetSession returns a user's upload session with the number of bytes
+// received so far.
+func (s *UploadService) GetSession(userID uint, id uuid.UUID) (*data.UploadSession, error) {
+	session, err := s.getSession(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.readOffset(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// AppendChunk writes a chunk at the given offset, which must match the bytes
+// received so far. On a mismatch the conflict error comes with the session so
+// the client can resume from its offset. Bytes written before a dropped
+// connection are kept. The scene is returned once the last chunk is in.
+func (s *UploadService) AppendChunk(userID uint, id uuid.UUID, offset int64, chunk io.Reader) (*data.UploadSession, *data.Scene, error) {
+	lock := s.lock(id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := s.getSession(userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.readOffset(session); err != nil {
+		return nil, nil, err
+	}
+	if offset != session.Offset {
+		return session, nil, apperrors.NewConflictError("upload", "offset does not match the bytes received")
+	}
+
+	f, err := os.OpenFile(s.partPath(id), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, apperrors.NewInternalError("failed to open upload file", err)
+	}
+	// Read one byte past the remaining size to detect oversized chunks
+	remaining := session.Size - session.Offset
+	written, copyErr := io.Copy(f, io.LimitReader(chunk, remaining+1))
+	overflow := written > remaining
+	if overflow {
+		f.Truncate(session.Size)
+		written = remaining
+	}
+	if err := f.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	session.Offset += written
+
+	session.ExpiresAt = time.Now().Add(s.ttl)
+	if err := s.repo.UpdateExpiry(session.ID, session.ExpiresAt); err != nil {
+		s.logger.Warn("Failed to extend upload session",
+			zap.String("upload_id", id.String()),
+			zap.Error(err),
+		)
+	}
+
+	if overflow {
+		return session, nil, apperrors.NewValidationError("chunk goes past the upload size")
+	}
+	if copyErr != nil {
+		return session, nil, apperrors.NewInternalError("failed to write upload chunk", copyErr)
+	}
+	if session.Offset < session.Size {
+		return session, nil, nil
+	}
+
+	scene, err := s.sceneService.CreateUploadedScene(s.partPath(id), session.Filename, session.Title)
+	if err != nil {
+		return session, nil, apperrors.NewInternalError("failed to create scene from upload", err)
+	}
+	s.finish(session)
+
+	s.logger.Info("Upload completed",
+		zap.String("upload_id", id.String()),
+		zap.Uint("scene_id", scene.ID),
+	)
+	return session, scene, nil
+}
+
+// Cancel discards a user's upload session and the bytes received.
+func (s *UploadService) Cancel(userID uint, id uuid.UUID) error {
+	lock := s.lock(id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := s.getSession(userID, id)
+	if err != nil {
+		return err
+	}
+	os.Remove(s.partPath(id))
+	s.finish(session)
+	return nil
+}
+
+func (s *UploadService) getSession(userID uint, id uuid.UUID) (*data.UploadSession, error) {
+	session, err := s.repo.GetByUUID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("upload", id.String())
+		}
+		return nil, apperrors.NewInternalError("failed to get upload session", err)
+	}
+	// Other users' uploads are not revealed
+	if session.UserID != userID || time.Now().After(session.ExpiresAt) {
+		return nil, apperrors.NewNotFoundError("upload", id.String())
+	}
+	return session, nil
+}
+
+func (s *UploadService) readOffset(session *data.UploadSession) error {
+	info, err := os.Stat(s.partPath(session.UUID))
+	if err != nil {
+		return apperrors.NewInternalError("failed to read upload file", err)
+	}
+	session.Offset = info.Size()
+	return nil
+}
+
+// finish deletes a completed or cancelled session.
+func (s *UploadService) finish(session *data.UploadSession) {
+	if err := s.repo.Delete(session.ID); err != nil {
+		s.logger.Warn("Failed to delete upload session",
+			zap.String("upload_id", session.UUID.String()),
+			zap.Error(err),
+		)
+	}
+	s.mu.Lock()
+	delete(s.locks, session.UUID)
+	s.mu.Unlock()
+}
+
+// pruneExpired discards the sessions that have not received a chunk within
+// the TTL.
+func (s *UploadService) pruneExpired() {
+	expired, err := s.repo.ListExpired(time.Now())
+	if err != nil {
+		s.logger.Warn("Failed to list expired upload sessions", zap.Error(err))
+		return
+	}
+	discarded := 0
+	for i := range expired {
+		if s.discardExpired(expired[i].UUID) {
+			discarded++
+		}
+	}
+	if discarded > 0 {
+		s.logger.Info("Discarded expired upload sessions", zap.Int("count", discarded))
+	}
+}
+
+// discardExpired removes a session listed as expired. It takes the session
+// lock and reloads the session first, since a chunk may have extended it, or
+// completed or cancelled it, after it was listed.
+func (s *UploadService) discardExpired(id uuid.UUID) bool {
+	lock := s.lock(id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := s.repo.GetByUUID(id)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Warn("Failed to get expired upload session",
+				zap.String("upload_id", id.String()),
+				zap.Error(err),
+			)
+		}
+		s.mu.Lock()
+		delete(s.locks, id)
+		s.mu.Unlock()
+		return false
+	}
+	if time.Now().Before(session.ExpiresAt) {
+		return false
+	}
+	os.Remove(s.partPath(id))
+	s.finish(session)
+	return true
+}
+
+func (s *UploadService) lock(id uuid.UUID) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, ok := s.locks[id]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[id] = lock
+	}
+	return lock
+}
+
+func (s *UploadService) partPath(id uuid.UUID) string {
+	return filepath.Join(s.dir, id.String()+".part")
+}
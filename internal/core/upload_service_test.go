@@ -0,0 +1,156 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestUploadService(t *testing.T) (*UploadService, *mocks.MockUploadSessionRepository, *mocks.MockSceneRepository, string) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockUploadSessionRepository(ctrl)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	root := t.TempDir()
+	scenePath := filepath.Join(root, "videos")
+	sceneService := NewSceneService(sceneRepo, scenePath, filepath.Join(root, "metadata"), nil, nil, zap.NewNop(), nil, nil, nil, nil, nil, nil)
+	svc := NewUploadService(repo, sceneService, filepath.Join(scenePath, UploadDirName), time.Hour, zap.NewNop())
+	return svc, repo, sceneRepo, scenePath
+}
+
+func TestUploadService_CreateSession(t *testing.T) {
+	svc, repo, _, _ := newTestUploadService(t)
+
+	if _, err := svc.CreateSession(1, "notes.txt", "", 10); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an unsupported extension to be rejected, got %v", err)
+	}
+	if _, err := svc.CreateSession(1, "scene.mp4", "", 0); !apperrors.IsValidation(err) {
+		t.Fatalf("expected an empty file to be rejected, got %v", err)
+	}
+
+	expired := data.UploadSession{ID: 7, UUID: uuid.New()}
+	writeTestFile(t, svc.partPath(expired.UUID))
+	repo.EXPECT().ListExpired(gomock.Any()).Return([]data.UploadSession{expired}, nil)
+	repo.EXPECT().GetByUUID(expired.UUID).Return(&expired, nil)
+	repo.EXPECT().Delete(uint(7)).Return(nil)
+	repo.EXPECT().Create(gomock.Any()).Return(nil)
+
+	session, err := svc.CreateSession(1, "../dir/scene.mp4", "Title", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Filename != "scene.mp4" || session.Offset != 0 {
+		t.Fatalf("unexpected session %+v", session)
+	}
+	if _, err := os.Stat(svc.partPath(session.UUID)); err != nil {
+		t.Fatalf("expected the upload file created: %v", err)
+	}
+	if _, err := os.Stat(svc.partPath(expired.UUID)); !os.IsNotExist(err) {
+		t.Fatalf("expected the expired upload discarded, got %v", err)
+	}
+}
+
+func TestUploadService_AppendChunk(t *testing.T) {
+	svc, repo, sceneRepo, scenePath := newTestUploadService(t)
+	session := &data.UploadSession{ID: 1, UUID: uuid.New(), UserID: 1, Filename: "scene.mp4", Size: 10, ExpiresAt: time.Now().Add(time.Hour)}
+	writeTestFile(t, svc.partPath(session.UUID))
+	if err := os.Truncate(svc.partPath(session.UUID), 0); err != nil {
+		t.Fatal(err)
+	}
+	repo.EXPECT().GetByUUID(session.UUID).DoAndReturn(func(uuid.UUID) (*data.UploadSession, error) {
+		fresh := *session
+		return &fresh, nil
+	}).AnyTimes()
+	repo.EXPECT().UpdateExpiry(uint(1), gomock.Any()).Return(nil).AnyTimes()
+
+	// Other users' uploads are not found
+	if _, _, err := svc.AppendChunk(2, session.UUID, 0, strings.NewReader("x")); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected another user's upload to be hidden, got %v", err)
+	}
+
+	got, scene, err := svc.AppendChunk(1, session.UUID, 0, strings.NewReader("01234"))
+	if err != nil || scene != nil || got.Offset != 5 {
+		t.Fatalf("expected a partial upload at offset 5, got %+v, %v", got, err)
+	}
+
+	// A retried chunk reports the offset to resume from
+	got, _, err = svc.AppendChunk(1, session.UUID, 0, strings.NewReader("01234"))
+	if !apperrors.IsConflict(err) || got.Offset != 5 {
+		t.Fatalf("expected an offset conflict at 5, got %+v, %v", got, err)
+	}
+
+	sceneRepo.EXPECT().Create(gomock.Any()).Return(nil)
+	repo.EXPECT().Delete(uint(1)).Return(nil)
+	_, scene, err = svc.AppendChunk(1, session.UUID, 5, strings.NewReader("56789"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scene == nil || scene.Size != 10 || scene.Title != "scene.mp4" || filepath.Dir(scene.StoredPath) != scenePath {
+		t.Fatalf("unexpected scene %+v", scene)
+	}
+	if content, _ := os.ReadFile(scene.StoredPath); string(content) != "0123456789" {
+		t.Fatalf("unexpected content %q", content)
+	}
+}
+
+func TestUploadService_PruneExpiredWaitsForChunk(t *testing.T) {
+	svc, repo, _, _ := newTestUploadService(t)
+	session := data.UploadSession{ID: 3, UUID: uuid.New(), UserID: 1, Size: 10, ExpiresAt: time.Now().Add(-time.Minute)}
+	writeTestFile(t, svc.partPath(session.UUID))
+	repo.EXPECT().ListExpired(gomock.Any()).Return([]data.UploadSession{session}, nil)
+	repo.EXPECT().GetByUUID(session.UUID).DoAndReturn(func(uuid.UUID) (*data.UploadSession, error) {
+		fresh := session
+		return &fresh, nil
+	})
+
+	// A chunk is being appended while the session is listed as expired
+	lock := svc.lock(session.UUID)
+	lock.Lock()
+	done := make(chan struct{})
+	go func() {
+		svc.pruneExpired()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("expected pruning to wait for the session lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	// The chunk extends the session before releasing the lock
+	session.ExpiresAt = time.Now().Add(time.Hour)
+	lock.Unlock()
+	<-done
+
+	if _, err := os.Stat(svc.partPath(session.UUID)); err != nil {
+		t.Fatalf("expected the extended upload kept: %v", err)
+	}
+}
+
+func TestUploadService_AppendChunkPastSize(t *testing.T) {
+	svc, repo, _, _ := newTestUploadService(t)
+	session := &data.UploadSession{ID: 1, UUID: uuid.New(), UserID: 1, Filename: "scene.mp4", Size: 4, ExpiresAt: time.Now().Add(time.Hour)}
+	writeTestFile(t, svc.partPath(session.UUID))
+	if err := os.Truncate(svc.partPath(session.UUID), 0); err != nil {
+		t.Fatal(err)
+	}
+	repo.EXPECT().GetByUUID(session.UUID).Return(session, nil)
+	repo.EXPECT().UpdateExpiry(uint(1), gomock.Any()).Return(nil)
+
+	got, scene, err := svc.AppendChunk(1, session.UUID, 0, strings.NewReader("0123456789"))
+	if !apperrors.IsValidation(err) || scene != nil {
+		t.Fatalf("expected an oversized chunk to be rejected, got %v", err)
+	}
+	if info, _ := os.Stat(svc.partPath(session.UUID)); got.Offset != 4 || info.Size() != 4 {
+		t.Fatalf("expected the upload truncated to its size, got offset %d", got.Offset)
+	}
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+)
+
+const (
+	defaultUsageMonths = 12
+	maxUsageMonths     = 60
+	defaultUsageLimit  = 10
+	maxUsageLimit      = 50
+)
+
+// UsageQuery selects the tags or actors to chart and over how many months.
+// Without IDs the most used ones over the window are charted.
+type UsageQuery struct {
+	IDs    []uint
+	Months int // including the current month
+	Limit  int // without IDs, how many to chart
+	UserID uint
+}
+
+// window returns the first month of the query and how many months it spans,
+// with defaults applied.
+func (q UsageQuery) window(now time.Time) (time.Time, int, error) {
+	months := q.Months
+	if months == 0 {
+		months = defaultUsageMonths
+	}
+	if months < 1 || months > maxUsageMonths {
+		return time.Time{}, 0, apperrors.NewValidationErrorWithField("months", "months must be between 1 and 60")
+	}
+	now = now.UTC()
+	since := time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, time.UTC)
+	return since, months, nil
+}
+
+func (q UsageQuery) limit() (int, error) {
+	if q.Limit == 0 {
+		return defaultUsageLimit, nil
+	}
+	if q.Limit < 1 || q.Limit > maxUsageLimit {
+		return 0, apperrors.NewValidationErrorWithField("limit", "limit must be between 1 and 50")
+	}
+	return q.Limit, nil
+}
+
+// usageIDs returns the IDs the query charts, looking up the most used ones
+// when none are given.
+func (q UsageQuery) usageIDs(since time.Time, mostUsed func(userID uint, since time.Time, limit int) ([]uint, error)) ([]uint, error) {
+	if len(q.IDs) > 0 {
+		if len(q.IDs) > maxUsageLimit {
+			return nil, apperrors.NewValidationErrorWithField("ids", "at most 50 IDs can be charted")
+		}
+		return q.IDs, nil
+	}
+	limit, err := q.limit()
+	if err != nil {
+		return nil, err
+	}
+	ids, err := mostUsed(q.UserID, since, limit)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to find the most used", err)
+	}
+	return ids, nil
+}
+
+// buildUsageSeries spreads the counts over every month of the window, so each
+// series has a point per month. series must be in the order to return them.
+func buildUsageSeries(series []data.UsageSeries, counts []data.UsageCount, since time.Time, months int) []data.UsageSeries {
+	index := make(map[uint]int, len(series))
+	for i := range series {
+		index[series[i].ID] = i
+		series[i].Points = make([]data.UsagePoint, months)
+		for m := range months {
+			series[i].Points[m].Month = since.AddDate(0, m, 0)
+		}
+	}
+	for _, count := range counts {
+		i, ok := index[count.ID]
+		if !ok {
+			continue
+		}
+		month := count.Month.UTC()
+		m := (month.Year()-since.Year())*12 + int(month.Month()-since.Month())
+		if m < 0 || m >= months {
+			continue
+		}
+		series[i].Points[m].ScenesAdded += count.ScenesAdded
+		series[i].Points[m].Watches += count.Watches
+	}
+	return series
+}
+
+// GetUsage returns tags' scenes added and the user's watches per month.
+func (s *TagService) GetUsage(q UsageQuery) ([]data.UsageSeries, error) {
+	since, months, err := q.window(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	ids, err := q.usageIDs(since, s.tagRepo.GetMostUsedIDs)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to find tags", err)
+	}
+	byID := make(map[uint]data.Tag, len(tags))
+	for _, tag := range tags {
+		byID[tag.ID] = tag
+	}
+	series := make([]data.UsageSeries, 0, len(ids))
+	for _, id := range ids {
+		tag, ok := byID[id]
+		if !ok {
+			return nil, apperrors.ErrTagNotFound(id)
+		}
+		series = append(series, data.UsageSeries{ID: tag.ID, Name: tag.Name})
+	}
+
+	counts, err := s.tagRepo.GetMonthlyUsage(ids, q.UserID, since)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get tag usage", err)
+	}
+	return buildUsageSeries(series, counts, since, months), nil
+}
+
+// GetUsage returns actors' scenes added and the user's watches per month.
+func (s *ActorService) GetUsage(q UsageQuery) ([]data.UsageSeries, error) {
+	since, months, err := q.window(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	ids, err := q.usageIDs(since, s.actorRepo.GetMostUsedIDs)
+	if err != nil {
+		return nil, err
+	}
+	actors, err := s.actorRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to find actors", err)
+	}
+	byID := make(map[uint]data.Actor, len(actors))
+	for _, actor := range actors {
+		byID[actor.ID] = actor
+	}
+	series := make([]data.UsageSeries, 0, len(ids))
+	for _, id := range ids {
+		actor, ok := byID[id]
+		if !ok {
+			return nil, apperrors.ErrActorNotFound(id)
+		}
+		series = append(series, data.UsageSeries{ID: actor.ID, UUID: actor.UUID.String(), Name: actor.Name})
+	}
+
+	counts, err := s.actorRepo.GetMonthlyUsage(ids, q.UserID, since)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to get actor usage", err)
+	}
+	return buildUsageSeries(series, counts, since, months), nil
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestUsageQuery_window(t *testing.T) {
+	now := time.Date(2026, time.February, 17, 23, 0, 0, 0, time.UTC)
+
+	since, months, err := UsageQuery{}.window(now)
+	if err != nil || months != defaultUsageMonths || !since.Equal(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected default window since=%v months=%d err=%v", since, months, err)
+	}
+	since, _, _ = UsageQuery{Months: 1}.window(now)
+	if !since.Equal(time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected a one month window to start this month, got %v", since)
+	}
+	if _, _, err := (UsageQuery{Months: maxUsageMonths + 1}).window(now); !apperrors.IsValidation(err) {
+		t.Fatalf("expected too many months to be rejected, got %v", err)
+	}
+}
+
+func TestBuildUsageSeries(t *testing.T) {
+	since := time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)
+	series := buildUsageSeries(
+		[]data.UsageSeries{{ID: 2, Name: "b"}, {ID: 1, Name: "a"}},
+		[]data.UsageCount{
+			{ID: 1, Month: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), ScenesAdded: 3, Watches: 1},
+			{ID: 2, Month: time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC), Watches: 4},
+			{ID: 3, Month: since, ScenesAdded: 9},
+		},
+		since, 3,
+	)
+
+	if len(series) != 2 || series[0].ID != 2 || series[1].ID != 1 {
+		t.Fatalf("expected the series order kept, got %+v", series)
+	}
+	for _, s := range series {
+		if len(s.Points) != 3 {
+			t.Fatalf("expected a point per month for %d, got %d", s.ID, len(s.Points))
+		}
+	}
+	if p := series[1].Points[2]; !p.Month.Equal(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)) || p.ScenesAdded != 3 || p.Watches != 1 {
+		t.Fatalf("unexpected January point %+v", p)
+	}
+	if p := series[0].Points[0]; p.Watches != 4 || series[0].Points[1].Watches != 0 {
+		t.Fatalf("unexpected points %+v", series[0].Points)
+	}
+}
+
+func TestTagService_GetUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tagRepo := mocks.NewMockTagRepository(ctrl)
+	svc := NewTagService(tagRepo, nil, zap.NewNop())
+
+	// Without IDs the most used tags are charted, in order of use
+	tagRepo.EXPECT().GetMostUsedIDs(uint(7), gomock.Any(), defaultUsageLimit).Return([]uint{5, 4}, nil)
+	tagRepo.EXPECT().GetByIDs([]uint{5, 4}).Return([]data.Tag{{ID: 4, Name: "four"}, {ID: 5, Name: "five"}}, nil)
+	tagRepo.EXPECT().GetMonthlyUsage([]uint{5, 4}, uint(7), gomock.Any()).Return([]data.UsageCount{}, nil)
+	series, err := svc.GetUsage(UsageQuery{UserID: 7, Months: 6})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(series) != 2 || series[0].Name != "five" || len(series[0].Points) != 6 {
+		t.Fatalf("unexpected series %+v", series)
+	}
+
+	tagRepo.EXPECT().GetByIDs([]uint{9}).Return([]data.Tag{}, nil)
+	if _, err := svc.GetUsage(UsageQuery{IDs: []uint{9}}); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected a missing tag to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,529 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// watchPartyBuffer is the number of messages queued per connection; a client
+// that falls further behind is disconnected and can rejoin for a fresh state.
+const watchPartyBuffer = 16
+
+// Watch party message types sent to clients
+const (
+	WatchPartyMsgState = "state"
+	WatchPartyMsgEnded = "ended"
+)
+
+// Playback actions a host can take
+const (
+	WatchPartyActionPlay  = "play"
+	WatchPartyActionPause = "pause"
+	WatchPartyActionSeek  = "seek"
+)
+
+// WatchPartyMember is a user connected to a watch party.
+type WatchPartyMember struct {
+	UserID   uint      `json:"user_id"`
+	Username string    `json:"username"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// WatchPartyState is a snapshot of a watch party. Position is where playback
+// is at UpdatedAt; while playing, clients add the time elapsed since then.
+type WatchPartyState struct {
+	ID        string             `json:"id"`
+	SceneID   uint               `json:"scene_id"`
+	HostID    uint               `json:"host_id"`
+	Position  float64            `json:"position"`
+	Paused    bool               `json:"paused"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	Members   []WatchPartyMember `json:"members"`
+}
+
+// WatchPartyMessage is pushed to every connection in a watch party.
+type WatchPartyMessage struct {
+	Type  string           `json:"type"`
+	State *WatchPartyState `json:"state,omitempty"`
+}
+
+// WatchPartySubscription is one connection to a watch party. Messages is
+// closed when the connection leaves, is dropped, or the party ends.
+type WatchPartySubscription struct {
+	ID       string
+	PartyID  string
+	UserID   uint
+	Messages <-chan WatchPartyMessage
+
+	username string
+	joinedAt time.Time
+	ch       chan WatchPartyMessage
+}
+
+type watchParty struct {
+	id         string
+	sceneID    uint
+	hostID     uint
+	position   float64
+	paused     bool
+	updatedAt  time.Time
+	createdAt  time.Time
+	subs       map[string]*WatchPartySubscription
+	emptyTimer *time.Timer
+}
+
+// WatchPartyService runs watch-together sessions: users connected to the same
+// party see the host's play, pause and seek actions mirrored on their player.
+// Parties live in memory and end when the host ends them or when nobody has
+// been connected for watch_party.empty_timeout.
+type WatchPartyService struct {
+	sceneRepo data.SceneRepository
+	rbac      *RBACService
+	cfg       config.WatchPartyConfig
+	logger    *zap.Logger
+	now       func() time.Time
+
+	mu      sync.Mutex
+	parties map[string]*watchParty
+}
+
+func NewWatchPartyService(sceneRepo data.SceneRepository, cfg config.WatchPartyConfig, logger *zap.Logger) *WatchPartyService {
+	if cfg.EmptyTimeout <= 0 {
+		cfg.EmptyTimeout = 5 * time.Minute
+	}
+	return &WatchPartyService{
+		sceneRepo: sceneRepo,
+		cfg:       cfg,
+		logger:    logger.With(zap.String("component", "watch_party")),
+		now:       time.Now,
+		parties:   make(map[string]*watchParty),
+	}
+}
+
+// SetRBACService hides parties on scenes the user's role may not see.
+func (s *WatchPartyService) SetRBACService(rbac *RBACService) {
+	s.rbac = rbac
+}
+
+// Create starts a paused watch party for a scene hosted by userID.
+func (s *WatchPartyService) Create(sceneID, userID uint, role string) (*WatchPartyState, error) {
+	if _, err := s.sceneRepo.GetByID(sceneID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.ErrSceneNotFound(sceneID)
+		}
+		return nil, apperrors.NewInternalError("failed to get scene", err)
+	}
+	visibleIDs, err := visibleSceneIDs(s.rbac, role, []uint{sceneID})
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to check scene access", err)
+	}
+	if len(visibleIDs) == 0 {
+		return nil, apperrors.ErrSceneNotFound(sceneID)
+	}
+
+	id, err := newWatchPartyID()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to create watch party", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cfg.MaxParties > 0 && len(s.parties) >= s.cfg.MaxParties {
+		return nil, apperrors.NewConflictError("watch party", "too many active watch parties")
+	}
+
+	now := s.now()
+	p := &watchParty{
+		id:        id,
+		sceneID:   sceneID,
+		hostID:    userID,
+		paused:    true,
+		updatedAt: now,
+		createdAt: now,
+		subs:      make(map[string]*WatchPartySubscription),
+	}
+	s.parties[id] = p
+	// The host has until the empty timeout to connect
+	s.armEmptyTimerLocked(p)
+
+	s.logger.Info("Watch party created",
+		zap.String("party_id", id),
+		zap.Uint("scene_id", sceneID),
+		zap.Uint("host_id", userID),
+	)
+	return s.snapshotLocked(p), nil
+}
+
+// Get returns the current state of a watch party.
+func (s *WatchPartyService) Get(id string, role string) (*WatchPartyState, error) {
+	if err := s.checkPartyAccess(id, role); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.parties[id]
+	if !ok {
+		return nil, apperrors.NewNotFoundError("watch party", id)
+	}
+	return s.snapshotLocked(p), nil
+}
+
+// List returns the active watch parties on scenes role may see, newest first.
+func (s *WatchPartyService) List(role string) ([]WatchPartyState, error) {
+	s.mu.Lock()
+	states := make([]WatchPartyState, 0, len(s.parties))
+	for _, p := range s.parties {
+		states = append(states, *s.snapshotLocked(p))
+	}
+	s.mu.Unlock()
+
+	sceneIDs := make([]uint, len(states))
+	for i := range states {
+		sceneIDs[i] = states[i].SceneID
+	}
+	visibleIDs, err := visibleSceneIDs(s.rbac, role, sceneIDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to filter watch parties", err)
+	}
+	if len(visibleIDs) != len(sceneIDs) {
+		visible := make(map[uint]bool, len(visibleIDs))
+		for _, id := range visibleIDs {
+			visible[id] = true
+		}
+		filtered := states[:0]
+		for _, state := range states {
+			if visible[state.SceneID] {
+				filtered = append(filtered, state)
+			}
+		}
+		states = filtered
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].CreatedAt.After(states[j].CreatedAt)
+	})
+	return states, nil
+}
+
+// checkPartyAccess returns not found when role may not see the party's scene,
+// so parties on restricted scenes look like missing ones.
+func (s *WatchPartyService) checkPartyAccess(id string, role string) error {
+	s.mu.Lock()
+	p, ok := s.parties[id]
+	var sceneID uint
+	if ok {
+		sceneID = p.sceneID
+	}
+	s.mu.Unlock()
+	if !ok {
+		return apperrors.NewNotFoundError("watch party", id)
+	}
+
+	visibleIDs, err := visibleSceneIDs(s.rbac, role, []uint{sceneID})
+	if err != nil {
+		return apperrors.NewInternalError("failed to check scene access", err)
+	}
+	if len(visibleIDs) == 0 {
+		return apperrors.NewNotFoundError("watch party", id)
+	}
+	return nil
+}
+
+// Join connects a user to a watch party. The new connection receives the
+// current state first; everyone else gets the updated member list.
+func (s *WatchPartyService) Join(id string, userID uint, role, username string) (*WatchPartySubscription, error) {
+	if err := s.checkPartyAccess(id, role); err != nil {
+		return nil, err
+	}
+
+	subID, err := newWatchPartyID()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to join watch party", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.parties[id]
+	if !ok {
+		return nil, apperrors.NewNotFoundError("watch party", id)
+	}
+	if s.cfg.MaxMembers > 0 && !p.hasMember(userID) && len(p.members()) >= s.cfg.MaxMembers {
+		return nil, apperrors.NewConflictError("watch party", "watch party is full")
+	}
+
+	ch := make(chan WatchPartyMessage, watchPartyBuffer)
+	sub := &WatchPartySubscription{
+		ID:       subID,
+		PartyID:  id,
+		UserID:   userID,
+		Messages: ch,
+		username: username,
+		joinedAt: s.now(),
+		ch:       ch,
+	}
+	p.subs[subID] = sub
+	if p.emptyTimer != nil {
+		p.emptyTimer.Stop()
+		p.emptyTimer = nil
+	}
+
+	s.broadcastStateLocked(p)
+	return sub, nil
+}
+
+// Leave disconnects a subscription. When the host's last connection leaves,
+// the longest-connected remaining member becomes host.
+func (s *WatchPartyService) Leave(sub *WatchPartySubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.parties[sub.PartyID]
+	if !ok {
+		return
+	}
+	if _, ok := p.subs[sub.ID]; !ok {
+		return
+	}
+	s.dropLocked(p, sub)
+	s.afterLeaveLocked(p)
+}
+
+// Sync re-sends the current state to one connection, e.g. after a client
+// detects drift.
+func (s *WatchPartyService) Sync(sub *WatchPartySubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.parties[sub.PartyID]
+	if !ok {
+		return
+	}
+	if _, ok := p.subs[sub.ID]; ok {
+		s.sendLocked(p, sub, WatchPartyMessage{Type: WatchPartyMsgState, State: s.snapshotLocked(p)})
+	}
+}
+
+// Control applies a host playback action and broadcasts the new state.
+func (s *WatchPartyService) Control(id string, userID uint, action string, position float64) error {
+	if position < 0 {
+		return apperrors.NewValidationErrorWithField("position", "position must not be negative")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.hostPartyLocked(id, userID)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case WatchPartyActionPlay:
+		p.paused = false
+	case WatchPartyActionPause:
+		p.paused = true
+	case WatchPartyActionSeek:
+	default:
+		return apperrors.NewValidationErrorWithField("type", fmt.Sprintf("unknown action %q", action))
+	}
+	p.position = position
+	p.updatedAt = s.now()
+
+	s.broadcastStateLocked(p)
+	return nil
+}
+
+// TransferHost hands host controls to another connected member.
+func (s *WatchPartyService) TransferHost(id string, userID, newHostID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.hostPartyLocked(id, userID)
+	if err != nil {
+		return err
+	}
+	if !p.hasMember(newHostID) {
+		return apperrors.NewValidationErrorWithField("user_id", "new host must be connected to the watch party")
+	}
+
+	p.hostID = newHostID
+	s.broadcastStateLocked(p)
+	return nil
+}
+
+// End closes a watch party and disconnects everyone. Only the host may end it.
+func (s *WatchPartyService) End(id string, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.hostPartyLocked(id, userID)
+	if err != nil {
+		return err
+	}
+	s.endLocked(p, "ended by host")
+	return nil
+}
+
+func (s *WatchPartyService) hostPartyLocked(id string, userID uint) (*watchParty, error) {
+	p, ok := s.parties[id]
+	if !ok {
+		return nil, apperrors.NewNotFoundError("watch party", id)
+	}
+	if p.hostID != userID {
+		return nil, apperrors.NewForbiddenError("only the host can control the watch party")
+	}
+	return p, nil
+}
+
+func (s *WatchPartyService) afterLeaveLocked(p *watchParty) {
+	if len(p.subs) == 0 {
+		s.armEmptyTimerLocked(p)
+		return
+	}
+	if !p.hasMember(p.hostID) {
+		members := p.members()
+		p.hostID = members[0].UserID
+	}
+	s.broadcastStateLocked(p)
+}
+
+func (s *WatchPartyService) armEmptyTimerLocked(p *watchParty) {
+	if p.emptyTimer != nil {
+		p.emptyTimer.Stop()
+	}
+	p.emptyTimer = time.AfterFunc(s.cfg.EmptyTimeout, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if cur, ok := s.parties[p.id]; ok && cur == p && len(p.subs) == 0 {
+			s.endLocked(p, "empty")
+		}
+	})
+}
+
+func (s *WatchPartyService) endLocked(p *watchParty, reason string) {
+	if p.emptyTimer != nil {
+		p.emptyTimer.Stop()
+	}
+	for _, sub := range p.subs {
+		select {
+		case sub.ch <- WatchPartyMessage{Type: WatchPartyMsgEnded}:
+		default:
+		}
+		close(sub.ch)
+	}
+	p.subs = nil
+	delete(s.parties, p.id)
+
+	s.logger.Info("Watch party ended",
+		zap.String("party_id", p.id),
+		zap.String("reason", reason),
+	)
+}
+
+func (s *WatchPartyService) dropLocked(p *watchParty, sub *WatchPartySubscription) {
+	delete(p.subs, sub.ID)
+	close(sub.ch)
+}
+
+func (s *WatchPartyService) broadcastStateLocked(p *watchParty) {
+	msg := WatchPartyMessage{Type: WatchPartyMsgState, State: s.snapshotLocked(p)}
+	var dropped bool
+	for _, sub := range p.subs {
+		if !s.trySendLocked(sub, msg) {
+			s.logger.Warn("Dropping slow watch party connection",
+				zap.String("party_id", p.id),
+				zap.Uint("user_id", sub.UserID),
+			)
+			s.dropLocked(p, sub)
+			dropped = true
+		}
+	}
+	if dropped {
+		s.afterLeaveLocked(p)
+	}
+}
+
+func (s *WatchPartyService) sendLocked(p *watchParty, sub *WatchPartySubscription, msg WatchPartyMessage) {
+	if !s.trySendLocked(sub, msg) {
+		s.dropLocked(p, sub)
+		s.afterLeaveLocked(p)
+	}
+}
+
+func (s *WatchPartyService) trySendLocked(sub *WatchPartySubscription, msg WatchPartyMessage) bool {
+	select {
+	case sub.ch <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *WatchPartyService) snapshotLocked(p *watchParty) *WatchPartyState {
+	return &WatchPartyState{
+		ID:        p.id,
+		SceneID:   p.sceneID,
+		HostID:    p.hostID,
+		Position:  p.position,
+		Paused:    p.paused,
+		UpdatedAt: p.updatedAt,
+		CreatedAt: p.createdAt,
+		Members:   p.members(),
+	}
+}
+
+// members returns one entry per connected user (a user may have several
+// connections), ordered by when they joined.
+func (p *watchParty) members() []WatchPartyMember {
+	byUser := make(map[uint]WatchPartyMember, len(p.subs))
+	for _, sub := range p.subs {
+		if m, ok := byUser[sub.UserID]; !ok || sub.joinedAt.Before(m.JoinedAt) {
+			byUser[sub.UserID] = WatchPartyMember{UserID: sub.UserID, Username: sub.username, JoinedAt: sub.joinedAt}
+		}
+	}
+	members := make([]WatchPartyMember, 0, len(byUser))
+	for _, m := range byUser {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].JoinedAt.Equal(members[j].JoinedAt) {
+			return members[i].UserID < members[j].UserID
+		}
+		return members[i].JoinedAt.Before(members[j].JoinedAt)
+	})
+	return members
+}
+
+func (p *watchParty) hasMember(userID uint) bool {
+	for _, sub := range p.subs {
+		if sub.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func newWatchPartyID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
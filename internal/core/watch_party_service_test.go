@@ -0,0 +1,202 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/config"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestWatchPartyService(t *testing.T, cfg config.WatchPartyConfig) (*WatchPartyService, *mocks.MockSceneRepository) {
+	ctrl := gomock.NewController(t)
+	sceneRepo := mocks.NewMockSceneRepository(ctrl)
+	if cfg.EmptyTimeout == 0 {
+		cfg.EmptyTimeout = time.Minute
+	}
+	return NewWatchPartyService(sceneRepo, cfg, zap.NewNop()), sceneRepo
+}
+
+func createTestParty(t *testing.T, svc *WatchPartyService, sceneRepo *mocks.MockSceneRepository, hostID uint) string {
+	t.Helper()
+	sceneRepo.EXPECT().GetByID(uint(1)).Return(&data.Scene{ID: 1}, nil)
+	state, err := svc.Create(1, hostID, "user")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return state.ID
+}
+
+// lastState drains a subscription and returns the most recent state message.
+func lastState(t *testing.T, sub *WatchPartySubscription) *WatchPartyState {
+	t.Helper()
+	var state *WatchPartyState
+	for {
+		select {
+		case msg, ok := <-sub.Messages:
+			if !ok {
+				return state
+			}
+			if msg.Type == WatchPartyMsgState {
+				state = msg.State
+			}
+		default:
+			if state == nil {
+				t.Fatal("expected a state message")
+			}
+			return state
+		}
+	}
+}
+
+func TestWatchPartyCreate_SceneNotFound(t *testing.T) {
+	svc, sceneRepo := newTestWatchPartyService(t, config.WatchPartyConfig{})
+	sceneRepo.EXPECT().GetByID(uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	if _, err := svc.Create(1, 1, "user"); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestWatchPartyCreate_MaxParties(t *testing.T) {
+	svc, sceneRepo := newTestWatchPartyService(t, config.WatchPartyConfig{MaxParties: 1})
+	createTestParty(t, svc, sceneRepo, 1)
+
+	sceneRepo.EXPECT().GetByID(uint(1)).Return(&data.Scene{ID: 1}, nil)
+	if _, err := svc.Create(1, 2, "user"); !apperrors.IsConflict(err) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestWatchPartyControl_BroadcastsToMembers(t *testing.T) {
+	svc, sceneRepo := newTestWatchPartyService(t, config.WatchPartyConfig{})
+	id := createTestParty(t, svc, sceneRepo, 1)
+
+	host, err := svc.Join(id, 1, "user", "host")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	guest, err := svc.Join(id, 2, "user", "guest")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if state := lastState(t, host); len(state.Members) != 2 {
+		t.Fatalf("expected host to see 2 members, got %d", len(state.Members))
+	}
+
+	if err := svc.Control(id, 1, WatchPartyActionPlay, 42.5); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	state := lastState(t, guest)
+	if state.Paused || state.Position != 42.5 {
+		t.Fatalf("expected playing at 42.5, got paused=%v position=%v", state.Paused, state.Position)
+	}
+
+	// Guests cannot control playback
+	if err := svc.Control(id, 2, WatchPartyActionPause, 10); !apperrors.IsForbidden(err) {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+	if err := svc.Control(id, 1, "rewind", 10); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestWatchPartyJoin_Full(t *testing.T) {
+	svc, sceneRepo := newTestWatchPartyService(t, config.WatchPartyConfig{MaxMembers: 1})
+	id := createTestParty(t, svc, sceneRepo, 1)
+
+	if _, err := svc.Join(id, 1, "user", "host"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// A second connection from the same user does not take another seat
+	if _, err := svc.Join(id, 1, "user", "host"); err != nil {
+		t.Fatalf("expected second connection to be allowed, got %v", err)
+	}
+	if _, err := svc.Join(id, 2, "user", "guest"); !apperrors.IsConflict(err) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestWatchPartyLeave_PromotesNextHost(t *testing.T) {
+	svc, sceneRepo := newTestWatchPartyService(t, config.WatchPartyConfig{})
+	id := createTestParty(t, svc, sceneRepo, 1)
+
+	host, _ := svc.Join(id, 1, "user", "host")
+	guest, _ := svc.Join(id, 2, "user", "guest")
+
+	svc.Leave(host)
+	for range host.Messages {
+		// Drain until closed
+	}
+
+	if state := lastState(t, guest); state.HostID != 2 || len(state.Members) != 1 {
+		t.Fatalf("expected guest to become host, got host=%d members=%d", state.HostID, len(state.Members))
+	}
+}
+
+func TestWatchPartyTransferHost(t *testing.T) {
+	svc, sceneRepo := newTestWatchPartyService(t, config.WatchPartyConfig{})
+	id := createTestParty(t, svc, sceneRepo, 1)
+	svc.Join(id, 1, "user", "host")
+	svc.Join(id, 2, "user", "guest")
+
+	if err := svc.TransferHost(id, 1, 3); !apperrors.IsValidation(err) {
+		t.Fatalf("expected validation error for non-member, got %v", err)
+	}
+	if err := svc.TransferHost(id, 1, 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := svc.Control(id, 1, WatchPartyActionPlay, 0); !apperrors.IsForbidden(err) {
+		t.Fatalf("expected old host to lose controls, got %v", err)
+	}
+}
+
+func TestWatchPartyEnd(t *testing.T) {
+	svc, sceneRepo := newTestWatchPartyService(t, config.WatchPartyConfig{})
+	id := createTestParty(t, svc, sceneRepo, 1)
+	guest, _ := svc.Join(id, 2, "user", "guest")
+
+	if err := svc.End(id, 2); !apperrors.IsForbidden(err) {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+	if err := svc.End(id, 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var ended bool
+	for msg := range guest.Messages {
+		ended = msg.Type == WatchPartyMsgEnded
+	}
+	if !ended {
+		t.Fatal("expected an ended message before the channel closed")
+	}
+	if _, err := svc.Get(id, "user"); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected party to be gone, got %v", err)
+	}
+	// Leaving after the party ended is a no-op
+	svc.Leave(guest)
+}
+
+func TestWatchPartyEmptyTimeout(t *testing.T) {
+	svc, sceneRepo := newTestWatchPartyService(t, config.WatchPartyConfig{EmptyTimeout: 20 * time.Millisecond})
+	id := createTestParty(t, svc, sceneRepo, 1)
+
+	sub, _ := svc.Join(id, 1, "user", "host")
+	time.Sleep(40 * time.Millisecond)
+	if _, err := svc.Get(id, "user"); err != nil {
+		t.Fatalf("expected party with a member to stay, got %v", err)
+	}
+
+	svc.Leave(sub)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := svc.Get(id, "user"); !apperrors.IsNotFound(err) {
+		t.Fatalf("expected empty party to end, got %v", err)
+	}
+}
@@ -0,0 +1,662 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/version"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// WebhookEventPing is sent by the test endpoint and never retried.
+const WebhookEventPing = "ping"
+
+// WebhookEventTypes are the event bus events that webhooks can subscribe to.
+var WebhookEventTypes = []string{
+	"scene:completed",
+	"scene:failed",
+	"scene:dlq_added",
+	"scan:completed",
+	"scan:failed",
+	"duplicate:detected",
+}
+
+const (
+	webhookRequestTimeout    = 10 * time.Second
+	webhookPollInterval      = 15 * time.Second
+	webhookCleanupInterval   = time.Hour
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+	webhookResponseBodyLimit = 2048
+	webhookSecretBytes       = 32
+	webhookBatchSize         = 50
+	webhookConcurrency       = 4
+	maxWebhookNameLength     = 100
+	maxWebhookURLLength      = 2048
+)
+
+// webhookRetryDelays is the wait after each failed attempt. A delivery that
+// still fails after the last delay, about 7.5 hours after the event, is given up.
+var webhookRetryDelays = []time.Duration{
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+}
+
+var webhookMaxAttempts = len(webhookRetryDelays) + 1
+
+// WebhookEvent is the JSON body POSTed to a webhook.
+type WebhookEvent struct {
+	ID        string    `json:"id"` // same for every attempt and redelivery
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	SceneID   uint      `json:"scene_id,omitempty"`
+	Data      any       `json:"data,omitempty"`
+}
+
+// CreatedWebhook is a webhook with its signing secret, which is only
+// available when the webhook is created or the secret is rotated.
+type CreatedWebhook struct {
+	Webhook data.Webhook `json:"webhook"`
+	Secret  string       `json:"secret"`
+}
+
+type CreateWebhookInput struct {
+	Name   string
+	URL    string
+	Events []string
+}
+
+type UpdateWebhookInput struct {
+	Name    *string
+	URL     *string
+	Events  *[]string
+	Enabled *bool
+}
+
+// WebhookService POSTs domain events from the event bus to admin-configured
+// URLs. Each event becomes a delivery row per subscribed webhook; a dispatcher
+// sends due deliveries, signing the body with the webhook's secret, and
+// retries failures with backoff.
+type WebhookService struct {
+	repo     data.WebhookRepository
+	eventBus *EventBus
+	client   *http.Client
+	logger   *zap.Logger
+	now      func() time.Time
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWebhookService(repo data.WebhookRepository, eventBus *EventBus, logger *zap.Logger) *WebhookService {
+	return &WebhookService{
+		repo:     repo,
+		eventBus: eventBus,
+		client: &http.Client{
+			Timeout: webhookRequestTimeout,
+			// A redirect is reported as a failed attempt rather than followed
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+		now:    time.Now,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the webhook secret, as sent in the X-GoonHub-Signature header.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *WebhookService) Create(createdBy uint, input CreateWebhookInput) (*CreatedWebhook, error) {
+	name, err := validateWebhookName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWebhookURL(input.URL); err != nil {
+		return nil, err
+	}
+	events, err := validateWebhookEvents(input.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate webhook secret", err)
+	}
+
+	webhook := data.Webhook{
+		Name:      name,
+		URL:       input.URL,
+		Secret:    secret,
+		Events:    events,
+		Enabled:   true,
+		CreatedBy: &createdBy,
+	}
+	if err := s.repo.Create(&webhook); err != nil {
+		return nil, apperrors.NewInternalError("failed to create webhook", err)
+	}
+
+	s.logger.Info("Webhook created",
+		zap.Uint("webhook_id", webhook.ID),
+		zap.Uint("created_by", createdBy),
+		zap.Strings("events", events),
+	)
+	return &CreatedWebhook{Webhook: webhook, Secret: secret}, nil
+}
+
+func (s *WebhookService) List() ([]data.Webhook, error) {
+	webhooks, err := s.repo.List()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to list webhooks", err)
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) Get(id uint) (*data.Webhook, error) {
+	webhook, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFoundError("webhook", id)
+		}
+		return nil, apperrors.NewInternalError("failed to find webhook", err)
+	}
+	return webhook, nil
+}
+
+func (s *WebhookService) Update(id uint, input UpdateWebhookInput) (*data.Webhook, error) {
+	webhook, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		name, err := validateWebhookName(*input.Name)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Name = name
+	}
+	if input.URL != nil {
+		if err := validateWebhookURL(*input.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *input.URL
+	}
+	if input.Events != nil {
+		events, err := validateWebhookEvents(*input.Events)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = events
+	}
+	if input.Enabled != nil {
+		webhook.Enabled = *input.Enabled
+	}
+
+	if err := s.repo.Update(webhook); err != nil {
+		return nil, apperrors.NewInternalError("failed to update webhook", err)
+	}
+	s.logger.Info("Webhook updated", zap.Uint("webhook_id", id), zap.Bool("enabled", webhook.Enabled))
+	return webhook, nil
+}
+
+// Delete removes the webhook along with its delivery history.
+func (s *WebhookService) Delete(id uint) error {
+	if err := s.repo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewNotFoundError("webhook", id)
+		}
+		return apperrors.NewInternalError("failed to delete webhook", err)
+	}
+	s.logger.Info("Webhook deleted", zap.Uint("webhook_id", id))
+	return nil
+}
+
+// RotateSecret replaces the signing secret. Pending retries are signed with
+// the new one.
+func (s *WebhookService) RotateSecret(id uint) (*CreatedWebhook, error) {
+	webhook, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate webhook secret", err)
+	}
+	webhook.Secret = secret
+	if err := s.repo.Update(webhook); err != nil {
+		return nil, apperrors.NewInternalError("failed to update webhook", err)
+	}
+	s.logger.Info("Webhook secret rotated", zap.Uint("webhook_id", id))
+	return &CreatedWebhook{Webhook: *webhook, Secret: secret}, nil
+}
+
+// ListDeliveries returns a webhook's deliveries, newest first. status filters
+// by delivery status when set.
+func (s *WebhookService) ListDeliveries(webhookID uint, status string, page, limit int) ([]data.WebhookDelivery, int64, error) {
+	if status != "" && status != data.WebhookDeliveryPending && status != data.WebhookDeliverySucceeded && status != data.WebhookDeliveryFailed {
+		return nil, 0, apperrors.NewValidationErrorWithField("status", "status must be pending, succeeded or failed")
+	}
+	if _, err := s.Get(webhookID); err != nil {
+		return nil, 0, err
+	}
+	deliveries, total, err := s.repo.ListDeliveries(webhookID, status, page, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError("failed to list webhook deliveries", err)
+	}
+	return deliveries, total, nil
+}
+
+func (s *WebhookService) GetDelivery(webhookID, deliveryID uint) (*data.WebhookDelivery, error) {
+	delivery, err := s.repo.GetDelivery(deliveryID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.NewInternalError("failed to find webhook delivery", err)
+	}
+	if err != nil || delivery.WebhookID != webhookID {
+		return nil, apperrors.NewNotFoundError("webhook delivery", deliveryID)
+	}
+	return delivery, nil
+}
+
+// Redeliver queues the delivery's payload again as a new delivery with the
+// same event ID, so receivers that dedupe on it can tell.
+func (s *WebhookService) Redeliver(webhookID, deliveryID uint) (*data.WebhookDelivery, error) {
+	original, err := s.GetDelivery(webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	deliveries := []data.WebhookDelivery{{
+		WebhookID:     webhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        data.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	}}
+	if err := s.repo.CreateDeliveries(deliveries); err != nil {
+		return nil, apperrors.NewInternalError("failed to queue webhook delivery", err)
+	}
+	s.wakeDispatcher()
+
+	s.logger.Info("Webhook delivery requeued",
+		zap.Uint("webhook_id", webhookID),
+		zap.Uint("delivery_id", deliveryID),
+		zap.Uint("new_delivery_id", deliveries[0].ID),
+	)
+	return &deliveries[0], nil
+}
+
+// Ping sends a "ping" event right away, even to a disabled webhook, and
+// returns the recorded delivery. Failed pings aren't retried.
+func (s *WebhookService) Ping(ctx context.Context, id uint) (*data.WebhookDelivery, error) {
+	webhook, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.newDeliveries([]data.Webhook{*webhook}, SceneEvent{
+		Type: WebhookEventPing,
+		Data: map[string]any{"webhook_id": webhook.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateDeliveries(deliveries); err != nil {
+		return nil, apperrors.NewInternalError("failed to create webhook delivery", err)
+	}
+
+	s.attempt(ctx, webhook, &deliveries[0])
+	return &deliveries[0], nil
+}
+
+// Start subscribes to the event bus and starts the delivery dispatcher.
+func (s *WebhookService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	subID, events := s.eventBus.Subscribe()
+
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		defer s.eventBus.Unsubscribe(subID)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := s.Enqueue(event); err != nil {
+					s.logger.Error("Failed to queue webhook deliveries",
+						zap.String("event_type", event.Type),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+	}()
+	go func() {
+		defer s.wg.Done()
+		poll := time.NewTicker(webhookPollInterval)
+		defer poll.Stop()
+		cleanup := time.NewTicker(webhookCleanupInterval)
+		defer cleanup.Stop()
+
+		s.DeliverDue(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-poll.C:
+				s.DeliverDue(ctx)
+			case <-s.wake:
+				s.DeliverDue(ctx)
+			case <-cleanup.C:
+				s.cleanup()
+			}
+		}
+	}()
+
+	s.logger.Info("Webhook dispatcher started")
+}
+
+// Stop halts the dispatcher. Deliveries still pending are sent after a restart.
+func (s *WebhookService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+}
+
+// Enqueue creates a pending delivery of the event for every enabled webhook
+// subscribed to it. Events webhooks can't subscribe to are ignored.
+func (s *WebhookService) Enqueue(event SceneEvent) error {
+	if !slices.Contains(WebhookEventTypes, event.Type) {
+		return nil
+	}
+
+	webhooks, err := s.repo.ListEnabled()
+	if err != nil {
+		return err
+	}
+	targets := make([]data.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if webhook.Subscribes(event.Type) {
+			targets = append(targets, webhook)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	deliveries, err := s.newDeliveries(targets, event)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.CreateDeliveries(deliveries); err != nil {
+		return err
+	}
+	s.wakeDispatcher()
+	return nil
+}
+
+func (s *WebhookService) newDeliveries(webhooks []data.Webhook, event SceneEvent) ([]data.WebhookDelivery, error) {
+	now := s.now()
+	eventID := uuid.NewString()
+	payload, err := json.Marshal(WebhookEvent{
+		ID:        eventID,
+		Type:      event.Type,
+		CreatedAt: now.UTC(),
+		SceneID:   event.SceneID,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to encode webhook payload", err)
+	}
+	deliveries := make([]data.WebhookDelivery, len(webhooks))
+	for i, webhook := range webhooks {
+		deliveries[i] = data.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       eventID,
+			EventType:     event.Type,
+			Payload:       data.WebhookPayload(payload),
+			Status:        data.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		}
+	}
+	return deliveries, nil
+}
+
+// DeliverDue sends every pending delivery whose next attempt is due, a few at
+// a time.
+func (s *WebhookService) DeliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		due, err := s.repo.ListDueDeliveries(s.now(), webhookBatchSize)
+		if err != nil {
+			s.logger.Error("Failed to list due webhook deliveries", zap.Error(err))
+			return
+		}
+		if len(due) == 0 {
+			return
+		}
+
+		ids := make([]uint, 0, len(due))
+		for _, d := range due {
+			if !slices.Contains(ids, d.WebhookID) {
+				ids = append(ids, d.WebhookID)
+			}
+		}
+		webhooks, err := s.repo.GetByIDs(ids)
+		if err != nil {
+			s.logger.Error("Failed to load webhooks for delivery", zap.Error(err))
+			return
+		}
+		byID := make(map[uint]*data.Webhook, len(webhooks))
+		for i := range webhooks {
+			byID[webhooks[i].ID] = &webhooks[i]
+		}
+
+		sem := make(chan struct{}, webhookConcurrency)
+		var wg sync.WaitGroup
+		for i := range due {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(delivery *data.WebhookDelivery) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				s.attempt(ctx, byID[delivery.WebhookID], delivery)
+			}(&due[i])
+		}
+		wg.Wait()
+
+		if len(due) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// attempt sends the delivery once and records the outcome: succeeded on a 2xx
+// response, otherwise pending with the next backoff until attempts run out.
+func (s *WebhookService) attempt(ctx context.Context, webhook *data.Webhook, delivery *data.WebhookDelivery) {
+	start := s.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &start
+	delivery.NextAttemptAt = nil
+
+	if webhook == nil || (!webhook.Enabled && delivery.EventType != WebhookEventPing) {
+		delivery.Status = data.WebhookDeliveryFailed
+		delivery.Error = "webhook is disabled"
+	} else {
+		status, body, err := s.send(ctx, webhook, delivery)
+		delivery.DurationMs = int(s.now().Sub(start).Milliseconds())
+		delivery.ResponseStatus = status
+		delivery.ResponseBody = body
+		delivery.Error = ""
+
+		switch {
+		case err == nil && status >= 200 && status < 300:
+			delivery.Status = data.WebhookDeliverySucceeded
+		default:
+			if err != nil {
+				delivery.Error = err.Error()
+			} else {
+				delivery.Error = fmt.Sprintf("endpoint responded with status %d", status)
+			}
+			if delivery.Attempts >= webhookMaxAttempts || delivery.EventType == WebhookEventPing {
+				delivery.Status = data.WebhookDeliveryFailed
+			} else {
+				next := start.Add(webhookRetryDelays[delivery.Attempts-1])
+				delivery.Status = data.WebhookDeliveryPending
+				delivery.NextAttemptAt = &next
+			}
+		}
+	}
+
+	if err := s.repo.UpdateDelivery(delivery); err != nil {
+		s.logger.Error("Failed to record webhook delivery",
+			zap.Uint("delivery_id", delivery.ID),
+			zap.Error(err),
+		)
+	}
+	if delivery.Status == data.WebhookDeliveryFailed {
+		s.logger.Warn("Webhook delivery failed",
+			zap.Uint("webhook_id", delivery.WebhookID),
+			zap.Uint("delivery_id", delivery.ID),
+			zap.String("event_type", delivery.EventType),
+			zap.Int("attempts", delivery.Attempts),
+			zap.String("error", delivery.Error),
+		)
+	}
+}
+
+// send POSTs the payload and returns the response status and the start of the
+// response body.
+func (s *WebhookService) send(ctx context.Context, webhook *data.Webhook, delivery *data.WebhookDelivery) (int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookRequestTimeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoonHub-Webhook/"+version.Version)
+	req.Header.Set("X-GoonHub-Event", delivery.EventType)
+	req.Header.Set("X-GoonHub-Delivery", delivery.EventID)
+	req.Header.Set("X-GoonHub-Timestamp", timestamp)
+	req.Header.Set("X-GoonHub-Signature", "sha256="+SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	// Postgres text columns reject NUL bytes and invalid UTF-8
+	text := strings.ToValidUTF8(strings.ReplaceAll(string(respBody), "\x00", ""), "")
+	return resp.StatusCode, text, nil
+}
+
+func (s *WebhookService) cleanup() {
+	deleted, err := s.repo.DeleteDeliveriesBefore(s.now().Add(-webhookDeliveryRetention))
+	if err != nil {
+		s.logger.Error("Failed to clean up webhook deliveries", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("Cleaned up old webhook deliveries", zap.Int64("deleted", deleted))
+	}
+}
+
+func (s *WebhookService) wakeDispatcher() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func validateWebhookName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", apperrors.NewValidationErrorWithField("name", "name is required")
+	}
+	if len(name) > maxWebhookNameLength {
+		return "", apperrors.NewValidationErrorWithField("name", fmt.Sprintf("name must be at most %d characters", maxWebhookNameLength))
+	}
+	return name, nil
+}
+
+func validateWebhookURL(rawURL string) error {
+	if len(rawURL) > maxWebhookURLLength {
+		return apperrors.NewValidationErrorWithField("url", fmt.Sprintf("url must be at most %d characters", maxWebhookURLLength))
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apperrors.NewValidationErrorWithField("url", "url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// validateWebhookEvents checks the event types and drops duplicates. An empty
+// list subscribes to every event.
+func validateWebhookEvents(events []string) ([]string, error) {
+	valid := make([]string, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(WebhookEventTypes, event) {
+			return nil, apperrors.NewValidationErrorWithField("events", fmt.Sprintf("unknown event type %q", event))
+		}
+		if !slices.Contains(valid, event) {
+			valid = append(valid, event)
+		}
+	}
+	return valid, nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"goonhub/internal/apperrors"
+	"goonhub/internal/data"
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func newTestWebhookService(t *testing.T) (*WebhookService, *mocks.MockWebhookRepository) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockWebhookRepository(ctrl)
+	svc := NewWebhookService(repo, NewEventBus(zap.NewNop()), zap.NewNop())
+	return svc, repo
+}
+
+func TestWebhookService_SendsSignedPayload(t *testing.T) {
+	svc, repo := newTestWebhookService(t)
+
+	var gotBody []byte
+	var gotHeaders http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeaders = r.Header.Clone()
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	webhook := data.Webhook{ID: 1, URL: srv.URL, Secret: "whsec_test", Enabled: true}
+	repo.EXPECT().ListEnabled().Return([]data.Webhook{webhook}, nil)
+	var queued []data.WebhookDelivery
+	repo.EXPECT().CreateDeliveries(gomock.Any()).DoAndReturn(func(deliveries []data.WebhookDelivery) error {
+		queued = deliveries
+		return nil
+	})
+
+	if err := svc.Enqueue(SceneEvent{Type: "scene:completed", SceneID: 7}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queued) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(queued))
+	}
+
+	var recorded data.WebhookDelivery
+	repo.EXPECT().UpdateDelivery(gomock.Any()).DoAndReturn(func(d *data.WebhookDelivery) error {
+		recorded = *d
+		return nil
+	})
+	svc.attempt(context.Background(), &webhook, &queued[0])
+
+	timestamp := gotHeaders.Get("X-GoonHub-Timestamp")
+	want := "sha256=" + SignWebhookPayload("whsec_test", timestamp, gotBody)
+	if gotHeaders.Get("X-GoonHub-Signature") != want {
+		t.Fatalf("expected signature %s, got %s", want, gotHeaders.Get("X-GoonHub-Signature"))
+	}
+	if gotHeaders.Get("X-GoonHub-Event") != "scene:completed" || gotHeaders.Get("X-GoonHub-Delivery") != queued[0].EventID {
+		t.Fatalf("unexpected event headers: %v", gotHeaders)
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(gotBody, &event); err != nil {
+		t.Fatalf("expected a JSON body: %v", err)
+	}
+	if event.ID != queued[0].EventID || event.SceneID != 7 {
+		t.Fatalf("unexpected payload: %+v", event)
+	}
+
+	if recorded.Status != data.WebhookDeliverySucceeded || recorded.ResponseStatus != 200 || recorded.ResponseBody != "ok" {
+		t.Fatalf("expected a succeeded delivery, got %+v", recorded)
+	}
+}
+
+func TestWebhookService_RetriesWithBackoffThenFails(t *testing.T) {
+	svc, repo := newTestWebhookService(t)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	webhook := data.Webhook{ID: 1, URL: srv.URL, Secret: "s", Enabled: true}
+	delivery := data.WebhookDelivery{ID: 3, WebhookID: 1, EventType: "scan:completed", Status: data.WebhookDeliveryPending}
+	repo.EXPECT().UpdateDelivery(gomock.Any()).Return(nil).Times(webhookMaxAttempts)
+
+	svc.attempt(context.Background(), &webhook, &delivery)
+	if delivery.Status != data.WebhookDeliveryPending || delivery.ResponseStatus != 500 {
+		t.Fatalf("expected a pending retry after the first failure, got %+v", delivery)
+	}
+	if !delivery.NextAttemptAt.Equal(now.Add(webhookRetryDelays[0])) {
+		t.Fatalf("expected the first backoff, got %v", delivery.NextAttemptAt)
+	}
+
+	for delivery.Attempts < webhookMaxAttempts {
+		svc.attempt(context.Background(), &webhook, &delivery)
+	}
+	if delivery.Status != data.WebhookDeliveryFailed || delivery.NextAttemptAt != nil {
+		t.Fatalf("expected the delivery to fail once attempts run out, got %+v", delivery)
+	}
+}
+
+func TestWebhookService_EnqueueFiltersByEvent(t *testing.T) {
+	svc, repo := newTestWebhookService(t)
+
+	repo.EXPECT().ListEnabled().Return([]data.Webhook{
+		{ID: 1, Events: []string{"scan:completed"}, Enabled: true},
+		{ID: 2, Events: []string{"scene:completed"}, Enabled: true},
+		{ID: 3, Enabled: true},
+	}, nil)
+	repo.EXPECT().CreateDeliveries(gomock.Any()).DoAndReturn(func(deliveries []data.WebhookDelivery) error {
+		if len(deliveries) != 2 || deliveries[0].WebhookID != 2 || deliveries[1].WebhookID != 3 {
+			t.Fatalf("expected deliveries for the subscribed webhooks only, got %+v", deliveries)
+		}
+		return nil
+	})
+
+	if err := svc.Enqueue(SceneEvent{Type: "scene:completed"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Events webhooks can't subscribe to never reach the repository
+	if err := svc.Enqueue(SceneEvent{Type: "scene:progress"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWebhookService_DisabledWebhookFailsDelivery(t *testing.T) {
+	svc, repo := newTestWebhookService(t)
+
+	delivery := data.WebhookDelivery{ID: 3, WebhookID: 1, EventType: "scan:completed"}
+	repo.EXPECT().UpdateDelivery(gomock.Any()).Return(nil)
+
+	svc.attempt(context.Background(), &data.Webhook{ID: 1, URL: "http://127.0.0.1:1"}, &delivery)
+	if delivery.Status != data.WebhookDeliveryFailed || delivery.Error != "webhook is disabled" {
+		t.Fatalf("expected the delivery to fail without a request, got %+v", delivery)
+	}
+}
+
+func TestWebhookService_CreateValidates(t *testing.T) {
+	svc, repo := newTestWebhookService(t)
+
+	cases := []CreateWebhookInput{
+		{Name: "", URL: "https://example.com/hook"},
+		{Name: "ci", URL: "ftp://example.com/hook"},
+		{Name: "ci", URL: "/relative"},
+		{Name: "ci", URL: "https://example.com/hook", Events: []string{"scene:progress"}},
+	}
+	for _, input := range cases {
+		if _, err := svc.Create(1, input); !apperrors.IsValidation(err) {
+			t.Fatalf("expected a validation error for %+v, got %v", input, err)
+		}
+	}
+
+	repo.EXPECT().Create(gomock.Any()).Return(nil)
+	created, err := svc.Create(1, CreateWebhookInput{
+		Name:   " ci ",
+		URL:    "https://example.com/hook",
+		Events: []string{"scan:completed", "scan:completed"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, "whsec_") || created.Webhook.Secret != created.Secret {
+		t.Fatalf("expected a generated secret, got %q", created.Secret)
+	}
+	if created.Webhook.Name != "ci" || len(created.Webhook.Events) != 1 {
+		t.Fatalf("expected a trimmed name and deduplicated events, got %+v", created.Webhook)
+	}
+}
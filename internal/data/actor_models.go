@@ -37,6 +37,11 @@ type Actor struct {
 	Piercings       string     `gorm:"type:text" json:"piercings"`
 	FakeBoobs       bool       `gorm:"not null;default:false" json:"fake_boobs"`
 	SameSexOnly     bool       `gorm:"not null;default:false" json:"same_sex_only"`
+
+	PornDBID           string            `gorm:"column:porndb_id;size:100;not null;default:''" json:"porndb_id"`
+	PornDBSyncEnabled  bool              `gorm:"column:porndb_sync_enabled;not null;default:true" json:"porndb_sync_enabled"`
+	PornDBSyncedAt     *time.Time        `gorm:"column:porndb_synced_at" json:"porndb_synced_at"`
+	PornDBSyncedValues ActorSyncedValues `gorm:"column:porndb_synced_values;type:jsonb;not null;default:'{}'" json:"-"`
 }
 
 // BeforeCreate generates a UUID if not set
@@ -1,6 +1,9 @@
 package data
 
 import (
+	"errors"
+	"time"
+
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -10,8 +13,12 @@ type ActorRepository interface {
 	GetByID(id uint) (*Actor, error)
 	GetByIDs(ids []uint) ([]Actor, error)
 	GetByUUID(uuid string) (*Actor, error)
+	GetByName(name string) (*Actor, error)
 	Update(actor *Actor) error
 	Delete(id uint) error
+	// Merge saves target and moves everything linked to the source actors to
+	// it, deleting them
+	Merge(target *Actor, sourceIDs []uint) error
 	List(page, limit int, sort string, genders []string) ([]ActorWithCount, int64, error)
 	Search(query string, page, limit int, sort string, genders []string) ([]ActorWithCount, int64, error)
 
@@ -19,9 +26,19 @@ type ActorRepository interface {
 	GetSceneActors(sceneID uint) ([]Actor, error)
 	GetSceneActorsMultiple(sceneIDs []uint) (map[uint][]Actor, error)
 	SetSceneActors(sceneID uint, actorIDs []uint) error
-	GetActorScenes(actorID uint, page, limit int) ([]Scene, int64, error)
+	GetActorScenes(actorID uint, restriction *ContentRestriction, page, limit int) ([]Scene, int64, error)
 	GetActorSceneIDs(actorID uint) ([]uint, error)
 	GetSceneCount(actorID uint) (int64, error)
+	// GetStats aggregates an actor's scenes; ratings are userID's
+	GetStats(actorID, userID uint) (*ActorStats, error)
+
+	// Actors with scenes but no image, in ID order
+	CountWithoutImage() (int64, error)
+	ListWithoutImage(afterID uint, limit int) ([]Actor, error)
+
+	// Usage per month: scenes added and userID's watches
+	GetMonthlyUsage(actorIDs []uint, userID uint, since time.Time) ([]UsageCount, error)
+	GetMostUsedIDs(userID uint, since time.Time, limit int) ([]uint, error)
 
 	// Bulk operations
 	BulkAddActorsToScenes(sceneIDs []uint, actorIDs []uint) error
@@ -29,6 +46,29 @@ type ActorRepository interface {
 	BulkReplaceActorsForScenes(sceneIDs []uint, actorIDs []uint) error
 }
 
+// ActorStats aggregates an actor's scenes for the actor page.
+type ActorStats struct {
+	SceneCount     int64             `json:"scene_count"`
+	TotalDuration  int64             `json:"total_duration"` // seconds
+	AverageRating  *float64          `json:"average_rating"` // of the scenes the user rated
+	RatedScenes    int64             `json:"rated_scenes"`
+	FirstSceneDate *time.Time        `json:"first_scene_date"` // by release date
+	LastSceneDate  *time.Time        `json:"last_scene_date"`
+	TopTags        []ActorStatsEntry `json:"top_tags"`
+	TopStudios     []ActorStatsEntry `json:"top_studios"`
+}
+
+// ActorStatsEntry is a tag or studio with how many of the actor's scenes have it.
+type ActorStatsEntry struct {
+	ID         uint   `json:"id"`
+	UUID       string `json:"uuid,omitempty"`
+	Name       string `json:"name"`
+	SceneCount int64  `json:"scene_count"`
+}
+
+// actorStatsTopLimit is how many tags and studios ActorStats lists.
+const actorStatsTopLimit = 5
+
 type ActorRepositoryImpl struct {
 	DB *gorm.DB
 }
@@ -68,6 +108,23 @@ func (r *ActorRepositoryImpl) GetByUUID(uuid string) (*Actor, error) {
 	return &actor, nil
 }
 
+// GetByName finds an actor by name, or else by alias, ignoring case
+func (r *ActorRepositoryImpl) GetByName(name string) (*Actor, error) {
+	return findActorByName(r.DB, name)
+}
+
+func findActorByName(db *gorm.DB, name string) (*Actor, error) {
+	var actor Actor
+	err := db.Where("LOWER(name) = LOWER(?)", name).First(&actor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = db.Where("EXISTS (SELECT 1 FROM unnest(aliases) alias WHERE LOWER(alias) = LOWER(?))", name).First(&actor).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &actor, nil
+}
+
 func (r *ActorRepositoryImpl) Update(actor *Actor) error {
 	return r.DB.Save(actor).Error
 }
@@ -83,6 +140,64 @@ func (r *ActorRepositoryImpl) Delete(id uint) error {
 	return nil
 }
 
+// Merge moves the source actors' scenes, likes, ratings, folder rules and
+// PornDB syncs to target in one transaction, then deletes them. A user's own
+// like or rating of target wins over theirs of a source; among the sources,
+// the latest rating is kept.
+func (r *ActorRepositoryImpl) Merge(target *Actor, sourceIDs []uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(target).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`INSERT INTO scene_actors (scene_id, actor_id)
+			SELECT DISTINCT scene_id, ? FROM scene_actors WHERE actor_id IN ?
+			ON CONFLICT DO NOTHING`, target.ID, sourceIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("actor_id IN ?", sourceIDs).Delete(&SceneActor{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`INSERT INTO user_actor_likes (user_id, actor_id, created_at)
+			SELECT user_id, ?, MIN(created_at) FROM user_actor_likes WHERE actor_id IN ? GROUP BY user_id
+			ON CONFLICT (user_id, actor_id) DO NOTHING`, target.ID, sourceIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("actor_id IN ?", sourceIDs).Delete(&UserActorLike{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`INSERT INTO user_actor_ratings (user_id, actor_id, rating, created_at, updated_at)
+			SELECT DISTINCT ON (user_id) user_id, ?, rating, created_at, updated_at
+			FROM user_actor_ratings WHERE actor_id IN ? ORDER BY user_id, updated_at DESC
+			ON CONFLICT (user_id, actor_id) DO NOTHING`, target.ID, sourceIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("actor_id IN ?", sourceIDs).Delete(&UserActorRating{}).Error; err != nil {
+			return err
+		}
+
+		// Folder rules adding a source add target instead, once
+		for _, sourceID := range sourceIDs {
+			if err := tx.Exec(`UPDATE folder_rules SET actor_ids = array_replace(actor_ids, ?::bigint, ?::bigint)
+				WHERE ?::bigint = ANY(actor_ids)`, sourceID, target.ID, sourceID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec(`UPDATE folder_rules SET actor_ids = ARRAY(SELECT DISTINCT unnest(actor_ids))
+			WHERE ?::bigint = ANY(actor_ids)`, target.ID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&ActorPornDBSync{}).Where("actor_id IN ?", sourceIDs).Update("actor_id", target.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&Actor{}, sourceIDs).Error
+	})
+}
+
 // actorSortMap maps sort parameter values to SQL ORDER BY clauses.
 // This whitelist approach prevents SQL injection.
 var actorSortMap = map[string]string{
@@ -252,25 +367,25 @@ func (r *ActorRepositoryImpl) SetSceneActors(sceneID uint, actorIDs []uint) erro
 	})
 }
 
-func (r *ActorRepositoryImpl) GetActorScenes(actorID uint, page, limit int) ([]Scene, int64, error) {
+func (r *ActorRepositoryImpl) GetActorScenes(actorID uint, restriction *ContentRestriction, page, limit int) ([]Scene, int64, error) {
 	var scenes []Scene
 	var total int64
 
 	offset := (page - 1) * limit
 
-	countQuery := r.DB.
+	countQuery := restrictScenes(r.DB.
 		Model(&Scene{}).
 		Joins("JOIN scene_actors ON scene_actors.scene_id = scenes.id").
 		Where("scene_actors.actor_id = ?", actorID).
-		Where("scenes.deleted_at IS NULL")
+		Where("scenes.deleted_at IS NULL"), restriction)
 	if err := countQuery.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.DB.
+	err := restrictScenes(r.DB.
 		Joins("JOIN scene_actors ON scene_actors.scene_id = scenes.id").
 		Where("scene_actors.actor_id = ?", actorID).
-		Where("scenes.deleted_at IS NULL").
+		Where("scenes.deleted_at IS NULL"), restriction).
 		Order("scenes.created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -310,6 +425,114 @@ func (r *ActorRepositoryImpl) GetSceneCount(actorID uint) (int64, error) {
 	return count, nil
 }
 
+// GetStats aggregates the actor's live scenes in a few queries: totals and
+// dates, the user's average rating, and the most common tags and studios.
+func (r *ActorRepositoryImpl) GetStats(actorID, userID uint) (*ActorStats, error) {
+	actorScenes := r.DB.
+		Table("scene_actors").
+		Select("scene_actors.scene_id").
+		Joins("JOIN scenes ON scenes.id = scene_actors.scene_id").
+		Where("scene_actors.actor_id = ?", actorID).
+		Where("scenes.deleted_at IS NULL")
+
+	var totals struct {
+		SceneCount     int64
+		TotalDuration  int64
+		FirstSceneDate *time.Time
+		LastSceneDate  *time.Time
+	}
+	err := r.DB.
+		Table("scenes").
+		Select(`COUNT(*) AS scene_count, COALESCE(SUM(duration), 0) AS total_duration,
+			MIN(release_date) AS first_scene_date, MAX(release_date) AS last_scene_date`).
+		Where("id IN (?)", actorScenes).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var ratings struct {
+		AverageRating *float64
+		RatedScenes   int64
+	}
+	err = r.DB.
+		Table("user_scene_ratings").
+		Select("AVG(rating) AS average_rating, COUNT(*) AS rated_scenes").
+		Where("user_id = ? AND scene_id IN (?)", userID, actorScenes).
+		Scan(&ratings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ActorStats{
+		SceneCount:     totals.SceneCount,
+		TotalDuration:  totals.TotalDuration,
+		AverageRating:  ratings.AverageRating,
+		RatedScenes:    ratings.RatedScenes,
+		FirstSceneDate: totals.FirstSceneDate,
+		LastSceneDate:  totals.LastSceneDate,
+		TopTags:        []ActorStatsEntry{},
+		TopStudios:     []ActorStatsEntry{},
+	}
+
+	err = r.DB.
+		Table("scene_tags").
+		Select("tags.id, tags.name, COUNT(*) AS scene_count").
+		Joins("JOIN tags ON tags.id = scene_tags.tag_id").
+		Where("scene_tags.scene_id IN (?)", actorScenes).
+		Group("tags.id, tags.name").
+		Order("scene_count DESC, tags.name ASC").
+		Limit(actorStatsTopLimit).
+		Scan(&stats.TopTags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.DB.
+		Table("scenes").
+		Select("studios.id, studios.uuid, studios.name, COUNT(*) AS scene_count").
+		Joins("JOIN studios ON studios.id = scenes.studio_id AND studios.deleted_at IS NULL").
+		Where("scenes.id IN (?)", actorScenes).
+		Group("studios.id, studios.uuid, studios.name").
+		Order("scene_count DESC, studios.name ASC").
+		Limit(actorStatsTopLimit).
+		Scan(&stats.TopStudios).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// withoutImage selects the actors without an image that are in a live scene.
+func (r *ActorRepositoryImpl) withoutImage() *gorm.DB {
+	return r.DB.Model(&Actor{}).
+		Where("actors.image_url = '' OR actors.image_url IS NULL").
+		Where(`EXISTS (SELECT 1 FROM scene_actors sa JOIN scenes s ON s.id = sa.scene_id
+			WHERE sa.actor_id = actors.id AND s.deleted_at IS NULL)`)
+}
+
+func (r *ActorRepositoryImpl) CountWithoutImage() (int64, error) {
+	var count int64
+	if err := r.withoutImage().Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *ActorRepositoryImpl) ListWithoutImage(afterID uint, limit int) ([]Actor, error) {
+	var actors []Actor
+	err := r.withoutImage().
+		Where("actors.id > ?", afterID).
+		Order("actors.id ASC").
+		Limit(limit).
+		Find(&actors).Error
+	if err != nil {
+		return nil, err
+	}
+	return actors, nil
+}
+
 // BulkAddActorsToScenes adds actors to multiple scenes (skips existing associations)
 func (r *ActorRepositoryImpl) BulkAddActorsToScenes(sceneIDs []uint, actorIDs []uint) error {
 	if len(sceneIDs) == 0 || len(actorIDs) == 0 {
@@ -375,3 +598,11 @@ func (r *ActorRepositoryImpl) BulkReplaceActorsForScenes(sceneIDs []uint, actorI
 		return tx.Create(&sceneActors).Error
 	})
 }
+
+func (r *ActorRepositoryImpl) GetMonthlyUsage(actorIDs []uint, userID uint, since time.Time) ([]UsageCount, error) {
+	return actorUsageSource.monthlyUsage(r.DB, actorIDs, userID, since)
+}
+
+func (r *ActorRepositoryImpl) GetMostUsedIDs(userID uint, since time.Time, limit int) ([]uint, error) {
+	return actorUsageSource.mostUsedIDs(r.DB, userID, since, limit)
+}
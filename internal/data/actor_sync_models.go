@@ -0,0 +1,92 @@
+package data
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Actor PornDB sync statuses
+const (
+	ActorSyncUpdated   = "updated"   // at least one field was overwritten
+	ActorSyncUnchanged = "unchanged" // nothing was overwritten; changes lists locally edited fields left alone
+	ActorSyncFailed    = "failed"    // PornDB could not be read
+)
+
+// ActorSyncedValues maps actor fields to the value the last PornDB sync wrote,
+// in the text form the sync compares them in.
+type ActorSyncedValues map[string]string
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (v ActorSyncedValues) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(v)
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval
+func (v *ActorSyncedValues) Scan(value any) error {
+	if value == nil {
+		*v = ActorSyncedValues{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan ActorSyncedValues: expected []byte")
+	}
+
+	return json.Unmarshal(bytes, v)
+}
+
+// ActorSyncChange is an actor field PornDB had a different value for. It is
+// applied unless the field was edited locally since the last sync.
+type ActorSyncChange struct {
+	Field   string `json:"field"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+	Applied bool   `json:"applied"`
+}
+
+// ActorSyncChanges is a JSONB-backed list of changes
+type ActorSyncChanges []ActorSyncChange
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (c ActorSyncChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval
+func (c *ActorSyncChanges) Scan(value any) error {
+	if value == nil {
+		*c = ActorSyncChanges{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan ActorSyncChanges: expected []byte")
+	}
+
+	return json.Unmarshal(bytes, c)
+}
+
+// ActorPornDBSync records one refresh of an actor from PornDB.
+type ActorPornDBSync struct {
+	ID        uint             `gorm:"primarykey" json:"id"`
+	ActorID   uint             `gorm:"not null" json:"actor_id"`
+	PornDBID  string           `gorm:"column:porndb_id;size:100;not null" json:"porndb_id"`
+	Status    string           `gorm:"size:20;not null" json:"status"`
+	Changes   ActorSyncChanges `gorm:"type:jsonb;not null;default:'[]'" json:"changes"`
+	Error     string           `gorm:"type:text;not null;default:''" json:"error,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+func (ActorPornDBSync) TableName() string {
+	return "actor_porndb_syncs"
+}
@@ -0,0 +1,68 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ActorSyncRepository interface {
+	// ListDue returns actors linked to a PornDB performer with sync enabled
+	// that were last synced before cutoff, or never, after afterID by ID
+	ListDue(cutoff time.Time, afterID uint, limit int) ([]Actor, error)
+	CountDue(cutoff time.Time) (int64, error)
+	Create(sync *ActorPornDBSync) error
+	ListByActor(actorID uint, page, limit int) ([]ActorPornDBSync, int64, error)
+}
+
+type ActorSyncRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewActorSyncRepository(db *gorm.DB) *ActorSyncRepositoryImpl {
+	return &ActorSyncRepositoryImpl{DB: db}
+}
+
+func (r *ActorSyncRepositoryImpl) dueActors(cutoff time.Time) *gorm.DB {
+	return r.DB.Model(&Actor{}).
+		Where("porndb_id <> '' AND porndb_sync_enabled").
+		Where("porndb_synced_at IS NULL OR porndb_synced_at < ?", cutoff)
+}
+
+func (r *ActorSyncRepositoryImpl) ListDue(cutoff time.Time, afterID uint, limit int) ([]Actor, error) {
+	var actors []Actor
+	err := r.dueActors(cutoff).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&actors).Error
+	return actors, err
+}
+
+func (r *ActorSyncRepositoryImpl) CountDue(cutoff time.Time) (int64, error) {
+	var count int64
+	err := r.dueActors(cutoff).Count(&count).Error
+	return count, err
+}
+
+func (r *ActorSyncRepositoryImpl) Create(sync *ActorPornDBSync) error {
+	if sync.Changes == nil {
+		sync.Changes = ActorSyncChanges{}
+	}
+	return r.DB.Create(sync).Error
+}
+
+func (r *ActorSyncRepositoryImpl) ListByActor(actorID uint, page, limit int) ([]ActorPornDBSync, int64, error) {
+	var syncs []ActorPornDBSync
+	var total int64
+
+	query := r.DB.Model(&ActorPornDBSync{}).Where("actor_id = ?", actorID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&syncs).Error; err != nil {
+		return nil, 0, err
+	}
+	return syncs, total, nil
+}
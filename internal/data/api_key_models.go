@@ -0,0 +1,38 @@
+package data
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// APIKeyPrefix starts every API key, so keys can be told apart from session tokens.
+const APIKeyPrefix = "ghk_"
+
+// APIKey is a long-lived credential for scripts and third-party clients. It acts as
+// its owner but only for the permissions it was granted. Only the SHA-256 of the key
+// is stored.
+type APIKey struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	UserID      uint           `gorm:"not null" json:"user_id"`
+	Name        string         `gorm:"size:100;not null" json:"name"`
+	KeyPrefix   string         `gorm:"size:16;not null" json:"key_prefix"`
+	KeyHash     string         `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Permissions pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"permissions"`
+	ExpiresAt   *time.Time     `json:"expires_at"`
+	LastUsedAt  *time.Time     `json:"last_used_at"`
+	RevokedAt   *time.Time     `json:"revoked_at"`
+	CreatedAt   time.Time      `gorm:"not null" json:"created_at"`
+
+	// Read-only: populated by admin listings
+	Username string `gorm:"->;column:username" json:"username,omitempty"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive reports whether the key can still be used at the given time.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
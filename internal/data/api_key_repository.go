@@ -0,0 +1,96 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(key *APIKey) error
+	GetByHash(keyHash string) (*APIKey, error)
+	ListByUser(userID uint) ([]APIKey, error)
+	List(page, limit int) ([]APIKey, int64, error)
+	CountActiveByUser(userID uint, now time.Time) (int64, error)
+	// Revoke revokes a key; userID 0 revokes it whoever owns it
+	Revoke(id, userID uint, at time.Time) error
+	TouchLastUsed(id uint, at time.Time) error
+}
+
+type APIKeyRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepositoryImpl {
+	return &APIKeyRepositoryImpl{DB: db}
+}
+
+func (r *APIKeyRepositoryImpl) Create(key *APIKey) error {
+	return r.DB.Create(key).Error
+}
+
+func (r *APIKeyRepositoryImpl) GetByHash(keyHash string) (*APIKey, error) {
+	var key APIKey
+	if err := r.DB.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepositoryImpl) ListByUser(userID uint) ([]APIKey, error) {
+	var keys []APIKey
+	err := r.DB.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepositoryImpl) List(page, limit int) ([]APIKey, int64, error) {
+	var total int64
+	if err := r.DB.Model(&APIKey{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var keys []APIKey
+	err := r.DB.Table("api_keys").
+		Select("api_keys.*, users.username").
+		Joins("JOIN users ON users.id = api_keys.user_id").
+		Order("api_keys.created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&keys).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return keys, total, nil
+}
+
+func (r *APIKeyRepositoryImpl) CountActiveByUser(userID uint, now time.Time) (int64, error) {
+	var count int64
+	err := r.DB.Model(&APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *APIKeyRepositoryImpl) Revoke(id, userID uint, at time.Time) error {
+	query := r.DB.Model(&APIKey{}).Where("id = ? AND revoked_at IS NULL", id)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	result := query.Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) TouchLastUsed(id uint, at time.Time) error {
+	return r.DB.Model(&APIKey{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
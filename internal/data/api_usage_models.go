@@ -0,0 +1,44 @@
+package data
+
+import "time"
+
+// UserAPIUsage is one user's API usage for a route on a given day.
+type UserAPIUsage struct {
+	UserID       uint      `gorm:"primaryKey" json:"user_id"`
+	UsageDate    time.Time `gorm:"primaryKey;type:date" json:"usage_date"`
+	Method       string    `gorm:"primaryKey" json:"method"`
+	Route        string    `gorm:"primaryKey" json:"route"`
+	RequestCount int64     `gorm:"not null;default:0" json:"request_count"`
+	ErrorCount   int64     `gorm:"not null;default:0" json:"error_count"` // responses with status >= 400
+	BytesSent    int64     `gorm:"not null;default:0" json:"bytes_sent"`
+}
+
+func (UserAPIUsage) TableName() string {
+	return "user_api_usage"
+}
+
+// APIUsageTotals sums a user's API usage over a period.
+type APIUsageTotals struct {
+	UserID       uint   `json:"user_id"`
+	Username     string `json:"username"`
+	RequestCount int64  `json:"request_count"`
+	ErrorCount   int64  `json:"error_count"`
+	BytesSent    int64  `json:"bytes_sent"`
+}
+
+// APIUsageDay sums a user's API usage for one day.
+type APIUsageDay struct {
+	Date         time.Time `json:"date"`
+	RequestCount int64     `json:"request_count"`
+	ErrorCount   int64     `json:"error_count"`
+	BytesSent    int64     `json:"bytes_sent"`
+}
+
+// APIUsageRoute sums a user's API usage for one route over a period.
+type APIUsageRoute struct {
+	Method       string `json:"method"`
+	Route        string `json:"route"`
+	RequestCount int64  `json:"request_count"`
+	ErrorCount   int64  `json:"error_count"`
+	BytesSent    int64  `json:"bytes_sent"`
+}
@@ -0,0 +1,104 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type APIUsageRepository interface {
+	IncrementBatch(rows []UserAPIUsage) error
+	GetTotalsByUser(since time.Time, limit int) ([]APIUsageTotals, error)
+	GetDailyForUser(userID uint, since time.Time) ([]APIUsageDay, error)
+	GetTopRoutesForUser(userID uint, since time.Time, limit int) ([]APIUsageRoute, error)
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type APIUsageRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewAPIUsageRepository(db *gorm.DB) *APIUsageRepositoryImpl {
+	return &APIUsageRepositoryImpl{DB: db}
+}
+
+// IncrementBatch adds the given counts onto the stored daily rows, creating rows as needed.
+func (r *APIUsageRepositoryImpl) IncrementBatch(rows []UserAPIUsage) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "usage_date"}, {Name: "method"}, {Name: "route"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"request_count": gorm.Expr("user_api_usage.request_count + EXCLUDED.request_count"),
+			"error_count":   gorm.Expr("user_api_usage.error_count + EXCLUDED.error_count"),
+			"bytes_sent":    gorm.Expr("user_api_usage.bytes_sent + EXCLUDED.bytes_sent"),
+		}),
+	}).Create(&rows).Error
+}
+
+// GetTotalsByUser returns per-user totals since the given day, busiest users first.
+func (r *APIUsageRepositoryImpl) GetTotalsByUser(since time.Time, limit int) ([]APIUsageTotals, error) {
+	var totals []APIUsageTotals
+	err := r.DB.Raw(`
+		SELECT u.user_id, COALESCE(users.username, '') AS username,
+		       SUM(u.request_count) AS request_count,
+		       SUM(u.error_count) AS error_count,
+		       SUM(u.bytes_sent) AS bytes_sent
+		FROM user_api_usage u
+		LEFT JOIN users ON users.id = u.user_id
+		WHERE u.usage_date >= ?
+		GROUP BY u.user_id, users.username
+		ORDER BY request_count DESC
+		LIMIT ?
+	`, since, limit).Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// GetDailyForUser returns a user's usage per day since the given day, oldest first.
+func (r *APIUsageRepositoryImpl) GetDailyForUser(userID uint, since time.Time) ([]APIUsageDay, error) {
+	var days []APIUsageDay
+	err := r.DB.Raw(`
+		SELECT usage_date AS date,
+		       SUM(request_count) AS request_count,
+		       SUM(error_count) AS error_count,
+		       SUM(bytes_sent) AS bytes_sent
+		FROM user_api_usage
+		WHERE user_id = ? AND usage_date >= ?
+		GROUP BY usage_date
+		ORDER BY usage_date ASC
+	`, userID, since).Scan(&days).Error
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// GetTopRoutesForUser returns a user's most called routes since the given day.
+func (r *APIUsageRepositoryImpl) GetTopRoutesForUser(userID uint, since time.Time, limit int) ([]APIUsageRoute, error) {
+	var routes []APIUsageRoute
+	err := r.DB.Raw(`
+		SELECT method, route,
+		       SUM(request_count) AS request_count,
+		       SUM(error_count) AS error_count,
+		       SUM(bytes_sent) AS bytes_sent
+		FROM user_api_usage
+		WHERE user_id = ? AND usage_date >= ?
+		GROUP BY method, route
+		ORDER BY request_count DESC
+		LIMIT ?
+	`, userID, since, limit).Scan(&routes).Error
+	if err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+func (r *APIUsageRepositoryImpl) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.DB.Where("usage_date < ?", before).Delete(&UserAPIUsage{})
+	return result.RowsAffected, result.Error
+}
@@ -8,10 +8,26 @@ import (
 )
 
 type AppSettingsRecord struct {
-	ID                 int       `gorm:"primaryKey" json:"id"`
-	TrashRetentionDays int       `gorm:"column:trash_retention_days" json:"trash_retention_days"`
-	ServeOGMetadata    bool      `gorm:"column:serve_og_metadata" json:"serve_og_metadata"`
-	UpdatedAt          time.Time `gorm:"column:updated_at" json:"updated_at"`
+	ID                 int  `gorm:"primaryKey" json:"id"`
+	TrashRetentionDays int  `gorm:"column:trash_retention_days" json:"trash_retention_days"`
+	ServeOGMetadata    bool `gorm:"column:serve_og_metadata" json:"serve_og_metadata"`
+
+	// Streaming bandwidth limits in kbps (0 = unlimited)
+	StreamRateLimitKbps       int `gorm:"column:stream_rate_limit_kbps" json:"stream_rate_limit_kbps"`
+	UserStreamRateLimitKbps   int `gorm:"column:user_stream_rate_limit_kbps" json:"user_stream_rate_limit_kbps"`
+	GlobalStreamRateLimitKbps int `gorm:"column:global_stream_rate_limit_kbps" json:"global_stream_rate_limit_kbps"`
+
+	// Casting: issue cast URLs for Chromecast/DLNA renderers
+	CastDiscoveryEnabled bool `gorm:"column:cast_discovery_enabled" json:"cast_discovery_enabled"`
+
+	// Admins must enroll in two-factor authentication before using the app
+	RequireAdminTwoFactor bool `gorm:"column:require_admin_two_factor" json:"require_admin_two_factor"`
+
+	// Anyone can register without an invite, getting OpenRegistrationRole
+	OpenRegistration     bool   `gorm:"column:open_registration" json:"open_registration"`
+	OpenRegistrationRole string `gorm:"column:open_registration_role" json:"open_registration_role"`
+
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
 }
 
 func (AppSettingsRecord) TableName() string {
@@ -38,10 +54,11 @@ func (r *AppSettingsRepositoryImpl) Get() (*AppSettingsRecord, error) {
 		if err == gorm.ErrRecordNotFound {
 			// Return default values if no record exists
 			return &AppSettingsRecord{
-				ID:                 1,
-				TrashRetentionDays: 7,
-				ServeOGMetadata:    true,
-				UpdatedAt:          time.Now(),
+				ID:                   1,
+				TrashRetentionDays:   7,
+				ServeOGMetadata:      true,
+				OpenRegistrationRole: "user",
+				UpdatedAt:            time.Now(),
 			}, nil
 		}
 		return nil, err
@@ -53,7 +70,14 @@ func (r *AppSettingsRepositoryImpl) Upsert(record *AppSettingsRecord) error {
 	record.ID = 1
 	record.UpdatedAt = time.Now()
 	return r.DB.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"trash_retention_days", "serve_og_metadata", "updated_at"}),
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"trash_retention_days", "serve_og_metadata",
+			"stream_rate_limit_kbps", "user_stream_rate_limit_kbps", "global_stream_rate_limit_kbps",
+			"cast_discovery_enabled",
+			"require_admin_two_factor",
+			"open_registration", "open_registration_role",
+			"updated_at",
+		}),
 	}).Create(record).Error
 }
@@ -0,0 +1,35 @@
+package data
+
+import "time"
+
+// SceneArtifactSize records the disk space used by a scene's generated files.
+type SceneArtifactSize struct {
+	SceneID        uint      `gorm:"primaryKey" json:"scene_id"`
+	ThumbnailBytes int64     `gorm:"not null;default:0" json:"thumbnail_bytes"` // small and large thumbnails
+	SpriteBytes    int64     `gorm:"not null;default:0" json:"sprite_bytes"`    // sprite sheets and VTT
+	PreviewBytes   int64     `gorm:"not null;default:0" json:"preview_bytes"`   // scene preview video
+	MarkerBytes    int64     `gorm:"not null;default:0" json:"marker_bytes"`    // marker thumbnails and clips
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (SceneArtifactSize) TableName() string {
+	return "scene_artifact_sizes"
+}
+
+// TotalBytes returns the combined size of all artifacts.
+func (s SceneArtifactSize) TotalBytes() int64 {
+	return s.ThumbnailBytes + s.SpriteBytes + s.PreviewBytes + s.MarkerBytes
+}
+
+// StoragePathArtifactStats aggregates artifact sizes for scenes stored under one storage path.
+// StoragePathID is nil for scenes that are not linked to a storage path.
+type StoragePathArtifactStats struct {
+	StoragePathID   *uint  `json:"storage_path_id"`
+	StoragePathName string `json:"storage_path_name"`
+	SceneCount      int64  `json:"scene_count"`
+	ThumbnailBytes  int64  `json:"thumbnail_bytes"`
+	SpriteBytes     int64  `json:"sprite_bytes"`
+	PreviewBytes    int64  `json:"preview_bytes"`
+	MarkerBytes     int64  `json:"marker_bytes"`
+	TotalBytes      int64  `json:"total_bytes"`
+}
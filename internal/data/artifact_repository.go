@@ -0,0 +1,69 @@
+package data
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SceneArtifactRepository interface {
+	Upsert(size *SceneArtifactSize) error
+	GetBySceneID(sceneID uint) (*SceneArtifactSize, error)
+	GetStatsByStoragePath() ([]StoragePathArtifactStats, error)
+	CountScenesWithoutSizes() (int64, error)
+}
+
+type SceneArtifactRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewSceneArtifactRepository(db *gorm.DB) *SceneArtifactRepositoryImpl {
+	return &SceneArtifactRepositoryImpl{DB: db}
+}
+
+func (r *SceneArtifactRepositoryImpl) Upsert(size *SceneArtifactSize) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "scene_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"thumbnail_bytes", "sprite_bytes", "preview_bytes", "marker_bytes", "updated_at"}),
+	}).Create(size).Error
+}
+
+func (r *SceneArtifactRepositoryImpl) GetBySceneID(sceneID uint) (*SceneArtifactSize, error) {
+	var size SceneArtifactSize
+	if err := r.DB.Where("scene_id = ?", sceneID).First(&size).Error; err != nil {
+		return nil, err
+	}
+	return &size, nil
+}
+
+func (r *SceneArtifactRepositoryImpl) GetStatsByStoragePath() ([]StoragePathArtifactStats, error) {
+	var stats []StoragePathArtifactStats
+	err := r.DB.Table("scene_artifact_sizes a").
+		Select(`sp.id AS storage_path_id,
+			COALESCE(sp.name, '') AS storage_path_name,
+			COUNT(*) AS scene_count,
+			COALESCE(SUM(a.thumbnail_bytes), 0) AS thumbnail_bytes,
+			COALESCE(SUM(a.sprite_bytes), 0) AS sprite_bytes,
+			COALESCE(SUM(a.preview_bytes), 0) AS preview_bytes,
+			COALESCE(SUM(a.marker_bytes), 0) AS marker_bytes,
+			COALESCE(SUM(a.thumbnail_bytes + a.sprite_bytes + a.preview_bytes + a.marker_bytes), 0) AS total_bytes`).
+		Joins("JOIN scenes s ON s.id = a.scene_id").
+		Joins("LEFT JOIN storage_paths sp ON sp.id = s.storage_path_id").
+		Group("sp.id, sp.name").
+		Order("total_bytes DESC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// CountScenesWithoutSizes counts processed, non-trashed scenes that have no
+// recorded artifact sizes, i.e. scenes processed before sizes were tracked.
+func (r *SceneArtifactRepositoryImpl) CountScenesWithoutSizes() (int64, error) {
+	var count int64
+	err := r.DB.Table("scenes s").
+		Joins("LEFT JOIN scene_artifact_sizes a ON a.scene_id = s.id").
+		Where("s.deleted_at IS NULL AND s.trashed_at IS NULL AND s.thumbnail_path != '' AND a.scene_id IS NULL").
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,66 @@
+package data
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// AuditEntry records one destructive or administrative action. Entries are never
+// updated; UserID is nil for actions taken by the server itself.
+type AuditEntry struct {
+	ID           uint         `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time    `gorm:"not null" json:"created_at"`
+	UserID       *uint        `json:"user_id"`
+	Username     string       `gorm:"size:100;not null;default:''" json:"username"`
+	APIKeyID     *uint        `json:"api_key_id,omitempty"`
+	Action       string       `gorm:"size:100;not null" json:"action"`
+	ResourceType string       `gorm:"size:50;not null;default:''" json:"resource_type"`
+	ResourceID   string       `gorm:"size:100;not null;default:''" json:"resource_id"`
+	Method       string       `gorm:"size:10;not null;default:''" json:"method"`
+	Route        string       `gorm:"size:255;not null;default:''" json:"route"`
+	Status       int          `gorm:"not null;default:0" json:"status"`
+	IPAddress    string       `gorm:"size:64;not null;default:''" json:"ip_address"`
+	Details      AuditDetails `gorm:"type:jsonb;not null;default:'{}'" json:"details"`
+}
+
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
+
+// AuditDetails holds action-specific context, such as a failed login's reason.
+type AuditDetails map[string]any
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (d AuditDetails) Value() (driver.Value, error) {
+	if d == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(d)
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval
+func (d *AuditDetails) Scan(value any) error {
+	if value == nil {
+		*d = AuditDetails{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan AuditDetails: expected []byte")
+	}
+
+	return json.Unmarshal(bytes, d)
+}
+
+// AuditFilter narrows an audit log listing. Zero values don't filter.
+type AuditFilter struct {
+	UserID       uint
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Since        *time.Time
+	Until        *time.Time
+}
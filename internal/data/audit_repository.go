@@ -0,0 +1,68 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	Create(entry *AuditEntry) error
+	List(filter AuditFilter, page, limit int) ([]AuditEntry, int64, error)
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type AuditRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepositoryImpl {
+	return &AuditRepositoryImpl{DB: db}
+}
+
+func (r *AuditRepositoryImpl) Create(entry *AuditEntry) error {
+	return r.DB.Create(entry).Error
+}
+
+// List returns matching entries, newest first.
+func (r *AuditRepositoryImpl) List(filter AuditFilter, page, limit int) ([]AuditEntry, int64, error) {
+	query := r.DB.Model(&AuditEntry{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []AuditEntry
+	err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (r *AuditRepositoryImpl) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.DB.Where("created_at < ?", before).Delete(&AuditEntry{})
+	return result.RowsAffected, result.Error
+}
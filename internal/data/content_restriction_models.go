@@ -0,0 +1,30 @@
+package data
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ContentRestriction limits a role to part of the library. Every non-empty list
+// narrows what the role can see: a scene must be in one of the storage paths,
+// carry at least one of the tags and have one of the scene types.
+type ContentRestriction struct {
+	RoleID         uint           `gorm:"primaryKey;autoIncrement:false" json:"role_id"`
+	StoragePathIDs pq.Int64Array  `gorm:"type:bigint[];not null;default:'{}'" json:"storage_path_ids"`
+	TagIDs         pq.Int64Array  `gorm:"type:bigint[];not null;default:'{}'" json:"tag_ids"`
+	SceneTypes     pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"scene_types"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+
+	// Read-only: populated by ListAll
+	RoleName string `gorm:"->;column:role_name" json:"role_name,omitempty"`
+}
+
+func (ContentRestriction) TableName() string {
+	return "role_content_restrictions"
+}
+
+// IsEmpty reports whether the restriction leaves the whole library visible.
+func (r *ContentRestriction) IsEmpty() bool {
+	return len(r.StoragePathIDs) == 0 && len(r.TagIDs) == 0 && len(r.SceneTypes) == 0
+}
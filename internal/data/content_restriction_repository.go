@@ -0,0 +1,104 @@
+package data
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContentRestrictionRepository interface {
+	ListAll() ([]ContentRestriction, error)
+	GetByRoleID(roleID uint) (*ContentRestriction, error)
+	Upsert(restriction *ContentRestriction) error
+	Delete(roleID uint) error
+	// GetVisibleSceneIDs returns the IDs of scenes not in trash that the restriction allows
+	GetVisibleSceneIDs(restriction *ContentRestriction) ([]uint, error)
+	IsSceneVisible(restriction *ContentRestriction, sceneID uint) (bool, error)
+	// FilterVisibleSceneIDs returns the subset of sceneIDs the restriction allows, in no particular order
+	FilterVisibleSceneIDs(restriction *ContentRestriction, sceneIDs []uint) ([]uint, error)
+}
+
+type ContentRestrictionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewContentRestrictionRepository(db *gorm.DB) *ContentRestrictionRepositoryImpl {
+	return &ContentRestrictionRepositoryImpl{DB: db}
+}
+
+func (r *ContentRestrictionRepositoryImpl) ListAll() ([]ContentRestriction, error) {
+	var restrictions []ContentRestriction
+	err := r.DB.Table("role_content_restrictions").
+		Select("role_content_restrictions.*, roles.name AS role_name").
+		Joins("JOIN roles ON roles.id = role_content_restrictions.role_id").
+		Find(&restrictions).Error
+	return restrictions, err
+}
+
+func (r *ContentRestrictionRepositoryImpl) GetByRoleID(roleID uint) (*ContentRestriction, error) {
+	var restriction ContentRestriction
+	if err := r.DB.Where("role_id = ?", roleID).First(&restriction).Error; err != nil {
+		return nil, err
+	}
+	return &restriction, nil
+}
+
+func (r *ContentRestrictionRepositoryImpl) Upsert(restriction *ContentRestriction) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"storage_path_ids", "tag_ids", "scene_types", "updated_at"}),
+	}).Create(restriction).Error
+}
+
+func (r *ContentRestrictionRepositoryImpl) Delete(roleID uint) error {
+	return r.DB.Where("role_id = ?", roleID).Delete(&ContentRestriction{}).Error
+}
+
+func (r *ContentRestrictionRepositoryImpl) GetVisibleSceneIDs(restriction *ContentRestriction) ([]uint, error) {
+	var ids []uint
+	err := r.visibleScenes(restriction).
+		Where("trashed_at IS NULL").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *ContentRestrictionRepositoryImpl) IsSceneVisible(restriction *ContentRestriction, sceneID uint) (bool, error) {
+	var count int64
+	err := r.visibleScenes(restriction).
+		Where("scenes.id = ?", sceneID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *ContentRestrictionRepositoryImpl) FilterVisibleSceneIDs(restriction *ContentRestriction, sceneIDs []uint) ([]uint, error) {
+	if len(sceneIDs) == 0 {
+		return []uint{}, nil
+	}
+	var ids []uint
+	err := r.visibleScenes(restriction).
+		Where("scenes.id IN ?", sceneIDs).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// visibleScenes scopes a scenes query to the scenes a restriction allows.
+func (r *ContentRestrictionRepositoryImpl) visibleScenes(restriction *ContentRestriction) *gorm.DB {
+	return restrictScenes(r.DB.Model(&Scene{}), restriction)
+}
+
+// restrictScenes limits a query on the scenes table to the scenes a
+// restriction allows. A nil restriction leaves the query unchanged.
+func restrictScenes(query *gorm.DB, restriction *ContentRestriction) *gorm.DB {
+	if restriction == nil {
+		return query
+	}
+	if len(restriction.StoragePathIDs) > 0 {
+		query = query.Where("scenes.storage_path_id IN ?", []int64(restriction.StoragePathIDs))
+	}
+	if len(restriction.SceneTypes) > 0 {
+		query = query.Where("scenes.type IN ?", []string(restriction.SceneTypes))
+	}
+	if len(restriction.TagIDs) > 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM scene_tags st WHERE st.scene_id = scenes.id AND st.tag_id IN ?)", []int64(restriction.TagIDs))
+	}
+	return query
+}
@@ -0,0 +1,101 @@
+package data
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidCursor is returned for a cursor that can't be decoded or was
+// issued for a different sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a keyset pagination position: the sort key value and ID of the
+// last row of the previous page. Lists paged by cursor are ordered by the sort
+// key and then by ID in the same direction, so the next page is a range scan
+// however deep it is, and rows added or removed meanwhile don't shift it.
+// Clients get it as an opaque string.
+type Cursor struct {
+	Sort  string `json:"s"` // sort order the cursor was issued for
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+// NewCursor builds a cursor from a time, string or number sort value.
+func NewCursor(sort string, value any, id uint) *Cursor {
+	c := &Cursor{Sort: sort, ID: id}
+	switch v := value.(type) {
+	case time.Time:
+		c.Value = v.UTC().Format(time.RFC3339Nano)
+	case string:
+		c.Value = v
+	case float64:
+		c.Value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		c.Value = fmt.Sprint(v)
+	}
+	return c
+}
+
+// Encode returns the opaque form handed to clients.
+func (c *Cursor) Encode() string {
+	if c == nil {
+		return ""
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor issued for sort. An empty string is the first
+// page and returns nil.
+func DecodeCursor(encoded, sort string) (*Cursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort != sort || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+func (c *Cursor) TimeValue() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+func (c *Cursor) FloatValue() (float64, error) {
+	f, err := strconv.ParseFloat(c.Value, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return f, nil
+}
+
+// keysetAfter returns the condition selecting the rows after a cursor's
+// (value, id) in ORDER BY column, idColumn, both ascending or both descending.
+func keysetAfter(column, idColumn string, desc bool) string {
+	if desc {
+		return fmt.Sprintf("(%s, %s) < (?, ?)", column, idColumn)
+	}
+	return fmt.Sprintf("(%s, %s) > (?, ?)", column, idColumn)
+}
+
+// trimPage cuts a query for limit+1 rows down to limit and reports whether
+// the extra row, meaning another page, was there.
+func trimPage[T any](rows []T, limit int) ([]T, bool) {
+	if len(rows) > limit {
+		return rows[:limit], true
+	}
+	return rows, false
+}
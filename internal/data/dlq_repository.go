@@ -17,6 +17,17 @@ type DLQRepository interface {
 	DeleteBySceneID(sceneID uint) (int64, error)
 	CountByStatus(status string) (int64, error)
 	AutoAbandon(olderThan time.Duration) (int64, error)
+	ListByFilter(filter DLQFilter, limit int) ([]DLQEntry, error)
+	DeleteByFilter(filter DLQFilter) (int64, error)
+	PurgeAbandoned(olderThan time.Duration) (int64, error)
+}
+
+// DLQFilter selects DLQ entries for bulk operations. Empty fields match all.
+type DLQFilter struct {
+	Status        string
+	Phase         string
+	ErrorContains string    // case-insensitive, in the original or last error
+	CreatedBefore time.Time // zero = any age
 }
 
 type DLQRepositoryImpl struct {
@@ -114,3 +125,42 @@ func (r *DLQRepositoryImpl) AutoAbandon(olderThan time.Duration) (int64, error)
 		})
 	return result.RowsAffected, result.Error
 }
+
+func (r *DLQRepositoryImpl) applyFilter(query *gorm.DB, filter DLQFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Phase != "" {
+		query = query.Where("phase = ?", filter.Phase)
+	}
+	if filter.ErrorContains != "" {
+		pattern := "%" + filter.ErrorContains + "%"
+		query = query.Where("(last_error ILIKE ? OR original_error ILIKE ?)", pattern, pattern)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedBefore)
+	}
+	return query
+}
+
+func (r *DLQRepositoryImpl) ListByFilter(filter DLQFilter, limit int) ([]DLQEntry, error) {
+	var entries []DLQEntry
+	err := r.applyFilter(r.DB.Model(&DLQEntry{}), filter).
+		Order("created_at asc").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *DLQRepositoryImpl) DeleteByFilter(filter DLQFilter) (int64, error) {
+	result := r.applyFilter(r.DB, filter).Delete(&DLQEntry{})
+	return result.RowsAffected, result.Error
+}
+
+// PurgeAbandoned deletes abandoned entries that were abandoned before the
+// retention window.
+func (r *DLQRepositoryImpl) PurgeAbandoned(olderThan time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-olderThan)
+	result := r.DB.Where("status = ? AND COALESCE(abandoned_at, updated_at) < ?", "abandoned", cutoff).Delete(&DLQEntry{})
+	return result.RowsAffected, result.Error
+}
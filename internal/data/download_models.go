@@ -0,0 +1,17 @@
+package data
+
+import "time"
+
+// UserSceneDownload counts how often a user downloaded a scene's original file.
+type UserSceneDownload struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	UserID           uint      `gorm:"not null" json:"user_id"`
+	SceneID          uint      `gorm:"not null;column:scene_id" json:"scene_id"`
+	DownloadCount    int       `gorm:"not null;default:0" json:"download_count"`
+	LastDownloadedAt time.Time `gorm:"not null;default:now()" json:"last_downloaded_at"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (UserSceneDownload) TableName() string {
+	return "user_scene_downloads"
+}
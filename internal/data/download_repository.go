@@ -0,0 +1,65 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DownloadRepository interface {
+	// RecordDownloads increments the user's download counter for each scene
+	RecordDownloads(userID uint, sceneIDs []uint) error
+	ListUserDownloads(userID uint, page, limit int) ([]UserSceneDownload, int64, error)
+}
+
+type DownloadRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewDownloadRepository(db *gorm.DB) *DownloadRepositoryImpl {
+	return &DownloadRepositoryImpl{DB: db}
+}
+
+func (r *DownloadRepositoryImpl) RecordDownloads(userID uint, sceneIDs []uint) error {
+	if len(sceneIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	records := make([]UserSceneDownload, len(sceneIDs))
+	for i, sceneID := range sceneIDs {
+		records[i] = UserSceneDownload{
+			UserID:           userID,
+			SceneID:          sceneID,
+			DownloadCount:    1,
+			LastDownloadedAt: now,
+		}
+	}
+
+	return r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "scene_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"download_count":     gorm.Expr("user_scene_downloads.download_count + 1"),
+			"last_downloaded_at": now,
+		}),
+	}).Create(&records).Error
+}
+
+// ListUserDownloads returns the user's downloaded scenes, most recently downloaded first
+func (r *DownloadRepositoryImpl) ListUserDownloads(userID uint, page, limit int) ([]UserSceneDownload, int64, error) {
+	var total int64
+	if err := r.DB.Model(&UserSceneDownload{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var downloads []UserSceneDownload
+	offset := (page - 1) * limit
+	if err := r.DB.Where("user_id = ?", userID).
+		Order("last_downloaded_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&downloads).Error; err != nil {
+		return nil, 0, err
+	}
+	return downloads, total, nil
+}
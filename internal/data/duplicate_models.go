@@ -0,0 +1,74 @@
+package data
+
+import "time"
+
+const (
+	DuplicateGroupStatusPending   = "pending"
+	DuplicateGroupStatusResolved  = "resolved"
+	DuplicateGroupStatusDismissed = "dismissed"
+)
+
+const (
+	// DuplicateReasonPornDBMatch groups scenes matched to the same PornDB scene
+	DuplicateReasonPornDBMatch = "porndb_match"
+	// DuplicateReasonFileHash groups scenes whose files have the same checksum
+	DuplicateReasonFileHash = "file_hash"
+)
+
+// IsValidDuplicateGroupStatus checks if the given duplicate group status is valid.
+func IsValidDuplicateGroupStatus(status string) bool {
+	switch status {
+	case DuplicateGroupStatusPending, DuplicateGroupStatusResolved, DuplicateGroupStatusDismissed:
+		return true
+	}
+	return false
+}
+
+const (
+	// DuplicateActionMerge moves the losers' metadata, tags, markers, watch history and
+	// interactions onto the winner and moves the losers to trash
+	DuplicateActionMerge = "merge"
+)
+
+// DuplicateGroup is a set of scenes flagged as likely copies of the same content.
+// ExternalID holds the shared identifier that caused the flag (e.g. the PornDB scene ID).
+// WinnerSceneID is the scene kept when the group was resolved.
+type DuplicateGroup struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	Status        string    `gorm:"size:20;not null;default:'pending'" json:"status"`
+	Reason        string    `gorm:"size:30;not null" json:"reason"`
+	ExternalID    string    `gorm:"not null;default:''" json:"external_id"`
+	WinnerSceneID *uint     `json:"winner_scene_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	SceneIDs      []uint    `gorm:"-" json:"scene_ids"`
+}
+
+func (DuplicateGroup) TableName() string {
+	return "duplicate_groups"
+}
+
+// DuplicateGroupScene links a scene to a duplicate group.
+type DuplicateGroupScene struct {
+	GroupID   uint      `gorm:"primaryKey" json:"group_id"`
+	SceneID   uint      `gorm:"primaryKey" json:"scene_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (DuplicateGroupScene) TableName() string {
+	return "duplicate_group_scenes"
+}
+
+// DuplicateSuppression is a pair of scenes confirmed as not duplicates of each other.
+// SceneIDA is always the lower ID.
+type DuplicateSuppression struct {
+	SceneIDA    uint      `gorm:"primaryKey;column:scene_id_a" json:"scene_id_a"`
+	SceneIDB    uint      `gorm:"primaryKey;column:scene_id_b" json:"scene_id_b"`
+	CreatedAt   time.Time `json:"created_at"`
+	SceneATitle string    `gorm:"->;column:scene_a_title" json:"scene_a_title"`
+	SceneBTitle string    `gorm:"->;column:scene_b_title" json:"scene_b_title"`
+}
+
+func (DuplicateSuppression) TableName() string {
+	return "duplicate_suppressions"
+}
@@ -0,0 +1,316 @@
+package data
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DuplicateGroupRepository interface {
+	// FindByExternalID returns all groups for the reason and external ID, newest first
+	FindByExternalID(reason, externalID string) ([]DuplicateGroup, error)
+	Create(group *DuplicateGroup, sceneIDs []uint) error
+	AddScenes(groupID uint, sceneIDs []uint) error
+	GetByID(id uint) (*DuplicateGroup, error)
+	List(status string, page, limit int) ([]DuplicateGroup, int64, error)
+	UpdateStatus(id uint, status string) error
+	// Resolve marks the group resolved with the given scene kept
+	Resolve(id, winnerSceneID uint) error
+	// MergeScenes applies fields to the winner and moves the losers' tags, actors,
+	// markers, watch history and interactions onto it in one transaction
+	MergeScenes(winnerSceneID uint, loserSceneIDs []uint, fields map[string]any) error
+	// Dismiss marks the group dismissed and records every pair of its scenes as not duplicates
+	Dismiss(id uint) error
+	// AllPairsSuppressed reports whether every pair of the scenes is recorded as not duplicates
+	AllPairsSuppressed(sceneIDs []uint) (bool, error)
+	ListSuppressions(sceneID uint, page, limit int) ([]DuplicateSuppression, int64, error)
+	DeleteSuppression(sceneIDA, sceneIDB uint) error
+}
+
+type DuplicateGroupRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewDuplicateGroupRepository(db *gorm.DB) *DuplicateGroupRepositoryImpl {
+	return &DuplicateGroupRepositoryImpl{DB: db}
+}
+
+func (r *DuplicateGroupRepositoryImpl) FindByExternalID(reason, externalID string) ([]DuplicateGroup, error) {
+	var groups []DuplicateGroup
+	err := r.DB.Where("reason = ? AND external_id = ?", reason, externalID).
+		Order("created_at DESC").
+		Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadSceneIDs(groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// Create inserts the group and its members in one transaction.
+func (r *DuplicateGroupRepositoryImpl) Create(group *DuplicateGroup, sceneIDs []uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(group).Error; err != nil {
+			return err
+		}
+		group.SceneIDs = sceneIDs
+		return insertDuplicateGroupScenes(tx, group.ID, sceneIDs)
+	})
+}
+
+// AddScenes adds members to a group, ignoring scenes already in it.
+func (r *DuplicateGroupRepositoryImpl) AddScenes(groupID uint, sceneIDs []uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := insertDuplicateGroupScenes(tx, groupID, sceneIDs); err != nil {
+			return err
+		}
+		return tx.Model(&DuplicateGroup{}).Where("id = ?", groupID).
+			Update("updated_at", gorm.Expr("NOW()")).Error
+	})
+}
+
+func (r *DuplicateGroupRepositoryImpl) GetByID(id uint) (*DuplicateGroup, error) {
+	var group DuplicateGroup
+	if err := r.DB.First(&group, id).Error; err != nil {
+		return nil, err
+	}
+	groups := []DuplicateGroup{group}
+	if err := r.loadSceneIDs(groups); err != nil {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+// List returns groups with the given status (all statuses when empty), newest first.
+func (r *DuplicateGroupRepositoryImpl) List(status string, page, limit int) ([]DuplicateGroup, int64, error) {
+	query := r.DB.Model(&DuplicateGroup{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var groups []DuplicateGroup
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&groups).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadSceneIDs(groups); err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+// UpdateStatus changes the group status. Leaving the resolved status clears the winner.
+func (r *DuplicateGroupRepositoryImpl) UpdateStatus(id uint, status string) error {
+	updates := map[string]any{"status": status}
+	if status != DuplicateGroupStatusResolved {
+		updates["winner_scene_id"] = nil
+	}
+	result := r.DB.Model(&DuplicateGroup{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *DuplicateGroupRepositoryImpl) Resolve(id, winnerSceneID uint) error {
+	result := r.DB.Model(&DuplicateGroup{}).Where("id = ?", id).Updates(map[string]any{
+		"status":          DuplicateGroupStatusResolved,
+		"winner_scene_id": winnerSceneID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// mergeSceneStatements move per-scene rows from the losers onto the winner. Rows that
+// are unique per scene keep the winner's value (ratings, likes) or are summed (jizz
+// counts), and the losers' copies are removed so merging twice changes nothing.
+var mergeSceneStatements = []string{
+	`INSERT INTO scene_tags (scene_id, tag_id)
+		SELECT DISTINCT @winner, tag_id FROM scene_tags WHERE scene_id IN @losers
+		ON CONFLICT DO NOTHING`,
+	`INSERT INTO scene_actors (scene_id, actor_id)
+		SELECT DISTINCT @winner, actor_id FROM scene_actors WHERE scene_id IN @losers
+		ON CONFLICT DO NOTHING`,
+	`INSERT INTO user_scene_ratings (user_id, scene_id, rating, created_at, updated_at)
+		SELECT DISTINCT ON (user_id) user_id, @winner, rating, created_at, updated_at
+		FROM user_scene_ratings WHERE scene_id IN @losers
+		ORDER BY user_id, updated_at DESC
+		ON CONFLICT (user_id, scene_id) DO NOTHING`,
+	`INSERT INTO user_scene_likes (user_id, scene_id, created_at)
+		SELECT user_id, @winner, MIN(created_at)
+		FROM user_scene_likes WHERE scene_id IN @losers GROUP BY user_id
+		ON CONFLICT (user_id, scene_id) DO NOTHING`,
+	`INSERT INTO user_scene_jizzed (user_id, scene_id, count, created_at, updated_at)
+		SELECT user_id, @winner, SUM(count), MIN(created_at), MAX(updated_at)
+		FROM user_scene_jizzed WHERE scene_id IN @losers GROUP BY user_id
+		ON CONFLICT (user_id, scene_id) DO UPDATE
+		SET count = user_scene_jizzed.count + EXCLUDED.count, updated_at = NOW()`,
+	`DELETE FROM user_scene_ratings WHERE scene_id IN @losers`,
+	`DELETE FROM user_scene_likes WHERE scene_id IN @losers`,
+	`DELETE FROM user_scene_jizzed WHERE scene_id IN @losers`,
+	`UPDATE user_scene_watches SET scene_id = @winner WHERE scene_id IN @losers`,
+	`UPDATE user_scene_markers SET scene_id = @winner, updated_at = NOW() WHERE scene_id IN @losers`,
+}
+
+func (r *DuplicateGroupRepositoryImpl) MergeScenes(winnerSceneID uint, loserSceneIDs []uint, fields map[string]any) error {
+	if len(loserSceneIDs) == 0 {
+		return nil
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if len(fields) > 0 {
+			if err := tx.Model(&Scene{}).Where("id = ?", winnerSceneID).Updates(fields).Error; err != nil {
+				return err
+			}
+		}
+		args := map[string]any{"winner": winnerSceneID, "losers": loserSceneIDs}
+		for _, stmt := range mergeSceneStatements {
+			if err := tx.Exec(stmt, args).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *DuplicateGroupRepositoryImpl) Dismiss(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&DuplicateGroup{}).Where("id = ?", id).Updates(map[string]any{
+			"status":          DuplicateGroupStatusDismissed,
+			"winner_scene_id": nil,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var sceneIDs []uint
+		if err := tx.Model(&DuplicateGroupScene{}).Where("group_id = ?", id).
+			Order("scene_id ASC").Pluck("scene_id", &sceneIDs).Error; err != nil {
+			return err
+		}
+		var pairs []DuplicateSuppression
+		for i := range sceneIDs {
+			for j := i + 1; j < len(sceneIDs); j++ {
+				pairs = append(pairs, DuplicateSuppression{SceneIDA: sceneIDs[i], SceneIDB: sceneIDs[j]})
+			}
+		}
+		if len(pairs) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&pairs).Error
+	})
+}
+
+func (r *DuplicateGroupRepositoryImpl) AllPairsSuppressed(sceneIDs []uint) (bool, error) {
+	unique := make(map[uint]struct{}, len(sceneIDs))
+	for _, id := range sceneIDs {
+		unique[id] = struct{}{}
+	}
+	n := int64(len(unique))
+	if n < 2 {
+		return false, nil
+	}
+
+	var count int64
+	err := r.DB.Model(&DuplicateSuppression{}).
+		Where("scene_id_a IN ? AND scene_id_b IN ?", sceneIDs, sceneIDs).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count == n*(n-1)/2, nil
+}
+
+// ListSuppressions returns suppressed pairs with their scene titles, newest first,
+// limited to pairs containing sceneID when non-zero.
+func (r *DuplicateGroupRepositoryImpl) ListSuppressions(sceneID uint, page, limit int) ([]DuplicateSuppression, int64, error) {
+	query := r.DB.Model(&DuplicateSuppression{})
+	if sceneID != 0 {
+		query = query.Where("scene_id_a = ? OR scene_id_b = ?", sceneID, sceneID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var suppressions []DuplicateSuppression
+	offset := (page - 1) * limit
+	err := query.
+		Select("duplicate_suppressions.*, a.title AS scene_a_title, b.title AS scene_b_title").
+		Joins("JOIN scenes a ON a.id = duplicate_suppressions.scene_id_a").
+		Joins("JOIN scenes b ON b.id = duplicate_suppressions.scene_id_b").
+		Order("duplicate_suppressions.created_at DESC, scene_id_a ASC, scene_id_b ASC").
+		Offset(offset).Limit(limit).
+		Find(&suppressions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return suppressions, total, nil
+}
+
+// DeleteSuppression removes a suppressed pair; the IDs may be given in either order.
+func (r *DuplicateGroupRepositoryImpl) DeleteSuppression(sceneIDA, sceneIDB uint) error {
+	if sceneIDA > sceneIDB {
+		sceneIDA, sceneIDB = sceneIDB, sceneIDA
+	}
+	result := r.DB.Where("scene_id_a = ? AND scene_id_b = ?", sceneIDA, sceneIDB).Delete(&DuplicateSuppression{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *DuplicateGroupRepositoryImpl) loadSceneIDs(groups []DuplicateGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(groups))
+	index := make(map[uint]int, len(groups))
+	for i := range groups {
+		ids[i] = groups[i].ID
+		index[groups[i].ID] = i
+		groups[i].SceneIDs = []uint{}
+	}
+
+	var members []DuplicateGroupScene
+	if err := r.DB.Where("group_id IN ?", ids).Order("scene_id ASC").Find(&members).Error; err != nil {
+		return err
+	}
+	for _, m := range members {
+		g := &groups[index[m.GroupID]]
+		g.SceneIDs = append(g.SceneIDs, m.SceneID)
+	}
+	return nil
+}
+
+func insertDuplicateGroupScenes(tx *gorm.DB, groupID uint, sceneIDs []uint) error {
+	if len(sceneIDs) == 0 {
+		return nil
+	}
+	rows := make([]DuplicateGroupScene, len(sceneIDs))
+	for i, id := range sceneIDs {
+		rows[i] = DuplicateGroupScene{GroupID: groupID, SceneID: id}
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
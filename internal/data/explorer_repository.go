@@ -24,9 +24,10 @@ func buildFullPath(basePath, relativePath string) string {
 // ExplorerRepository provides folder-based scene access
 type ExplorerRepository interface {
 	GetStoragePathsWithCounts() ([]StoragePathWithCount, error)
-	GetScenesByFolder(storagePathID uint, folderPath string, page, limit int) ([]Scene, int64, error)
+	GetScenesByFolder(storagePathID uint, folderPath string, restriction *ContentRestriction, page, limit int) ([]Scene, int64, error)
 	GetSubfolders(storagePathID uint, parentPath string) ([]FolderInfo, error)
-	GetSceneIDsByFolder(storagePathID uint, folderPath string, recursive bool) ([]uint, error)
+	GetFolderSummary(storagePathID uint, folderPath string) (*FolderInfo, error)
+	GetSceneIDsByFolder(storagePathID uint, folderPath string, recursive bool, restriction *ContentRestriction) ([]uint, error)
 	GetSceneCountByStoragePath(storagePathID uint) (int64, error)
 }
 
@@ -57,7 +58,7 @@ func (r *ExplorerRepositoryImpl) GetStoragePathsWithCounts() ([]StoragePathWithC
 }
 
 // GetScenesByFolder returns scenes in a specific folder (direct children only)
-func (r *ExplorerRepositoryImpl) GetScenesByFolder(storagePathID uint, folderPath string, page, limit int) ([]Scene, int64, error) {
+func (r *ExplorerRepositoryImpl) GetScenesByFolder(storagePathID uint, folderPath string, restriction *ContentRestriction, page, limit int) ([]Scene, int64, error) {
 	var scenes []Scene
 	var total int64
 
@@ -75,11 +76,11 @@ func (r *ExplorerRepositoryImpl) GetScenesByFolder(storagePathID uint, folderPat
 	// Query for scenes directly in this folder (not in subfolders)
 	// Match scenes where stored_path starts with fullPath but has no more path separators after that
 	// Exclude trashed scenes
-	baseQuery := r.DB.Model(&Scene{}).
+	baseQuery := restrictScenes(r.DB.Model(&Scene{}).
 		Where("storage_path_id = ?", storagePathID).
 		Where("stored_path LIKE ?", fullPath+"%").
 		Where("stored_path NOT LIKE ?", fullPath+"%"+string(filepath.Separator)+"%").
-		Where("trashed_at IS NULL")
+		Where("trashed_at IS NULL"), restriction)
 
 	if err := baseQuery.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -96,6 +97,43 @@ func (r *ExplorerRepositoryImpl) GetScenesByFolder(storagePathID uint, folderPat
 	return scenes, total, nil
 }
 
+// folderAggregates are the FolderInfo columns aggregated over a folder's scenes
+const folderAggregates = `COUNT(*) AS scene_count,
+		       COALESCE(SUM(duration), 0) AS total_duration,
+		       COALESCE(SUM(size), 0) AS total_size,
+		       COUNT(*) FILTER (WHERE processing_status <> 'completed') AS unprocessed_count,
+		       COALESCE(ROUND(AVG(width) FILTER (WHERE width > 0 AND height > 0))::int, 0) AS average_width,
+		       COALESCE(ROUND(AVG(height) FILTER (WHERE width > 0 AND height > 0))::int, 0) AS average_height`
+
+// GetFolderSummary aggregates every scene in a folder and its subfolders
+func (r *ExplorerRepositoryImpl) GetFolderSummary(storagePathID uint, folderPath string) (*FolderInfo, error) {
+	var storagePath StoragePath
+	if err := r.DB.First(&storagePath, storagePathID).Error; err != nil {
+		return nil, err
+	}
+	fullPath := buildFullPath(storagePath.Path, folderPath)
+
+	var folder FolderInfo
+	err := r.DB.Raw(`
+		SELECT `+folderAggregates+`
+		FROM scenes
+		WHERE storage_path_id = ?
+		  AND stored_path LIKE ?
+		  AND deleted_at IS NULL
+		  AND trashed_at IS NULL
+	`, storagePathID, fullPath+"%").Scan(&folder).Error
+	if err != nil {
+		return nil, err
+	}
+
+	folder.Path = strings.Trim(folderPath, string(filepath.Separator))
+	folder.Name = filepath.Base(folder.Path)
+	if folder.Path == "" {
+		folder.Name = storagePath.Name
+	}
+	return &folder, nil
+}
+
 // GetSubfolders returns unique subfolders within a folder using SQL aggregation
 // This is more efficient than loading all videos into memory for large folders
 func (r *ExplorerRepositoryImpl) GetSubfolders(storagePathID uint, parentPath string) ([]FolderInfo, error) {
@@ -113,10 +151,8 @@ func (r *ExplorerRepositoryImpl) GetSubfolders(storagePathID uint, parentPath st
 	// SPLIT_PART gets the first component (immediate subfolder)
 	// We filter to only include paths that have a '/' after the parent (i.e., are in subfolders)
 	type folderResult struct {
-		FolderName    string `gorm:"column:folder_name"`
-		SceneCount    int64  `gorm:"column:scene_count"`
-		TotalDuration int64  `gorm:"column:total_duration"`
-		TotalSize     int64  `gorm:"column:total_size"`
+		FolderInfo
+		FolderName string `gorm:"column:folder_name"`
 	}
 
 	var results []folderResult
@@ -127,14 +163,14 @@ func (r *ExplorerRepositoryImpl) GetSubfolders(storagePathID uint, parentPath st
 	// Note: pathLen is embedded directly in SQL since SUBSTRING FROM requires a literal integer
 	// This is safe as pathLen is derived from database values, not user input
 	query := fmt.Sprintf(`
-		SELECT folder_name,
-		       COUNT(*) as scene_count,
-		       COALESCE(SUM(duration), 0) as total_duration,
-		       COALESCE(SUM(size), 0) as total_size
+		SELECT folder_name, %s
 		FROM (
 			SELECT SPLIT_PART(SUBSTRING(stored_path FROM %d), '/', 1) as folder_name,
 			       duration,
-			       size
+			       size,
+			       processing_status,
+			       width,
+			       height
 			FROM scenes
 			WHERE storage_path_id = ?
 			  AND stored_path LIKE ?
@@ -145,7 +181,7 @@ func (r *ExplorerRepositoryImpl) GetSubfolders(storagePathID uint, parentPath st
 		WHERE folder_name != ''
 		GROUP BY folder_name
 		ORDER BY LOWER(folder_name)
-	`, pathLen+1, pathLen+1)
+	`, folderAggregates, pathLen+1, pathLen+1)
 	err := r.DB.Raw(query, storagePathID, fullParentPath+"%").Scan(&results).Error
 
 	if err != nil {
@@ -162,20 +198,17 @@ func (r *ExplorerRepositoryImpl) GetSubfolders(storagePathID uint, parentPath st
 			folderFullPath = filepath.Join(parentPath, r.FolderName)
 		}
 
-		folders = append(folders, FolderInfo{
-			Name:          r.FolderName,
-			Path:          folderFullPath,
-			SceneCount:    r.SceneCount,
-			TotalDuration: r.TotalDuration,
-			TotalSize:     r.TotalSize,
-		})
+		folder := r.FolderInfo
+		folder.Name = r.FolderName
+		folder.Path = folderFullPath
+		folders = append(folders, folder)
 	}
 
 	return folders, nil
 }
 
 // GetSceneIDsByFolder returns scene IDs in a folder, optionally recursive
-func (r *ExplorerRepositoryImpl) GetSceneIDsByFolder(storagePathID uint, folderPath string, recursive bool) ([]uint, error) {
+func (r *ExplorerRepositoryImpl) GetSceneIDsByFolder(storagePathID uint, folderPath string, recursive bool, restriction *ContentRestriction) ([]uint, error) {
 	// Get the storage path
 	var storagePath StoragePath
 	if err := r.DB.First(&storagePath, storagePathID).Error; err != nil {
@@ -186,10 +219,10 @@ func (r *ExplorerRepositoryImpl) GetSceneIDsByFolder(storagePathID uint, folderP
 	fullPath := buildFullPath(storagePath.Path, folderPath)
 
 	var ids []uint
-	query := r.DB.Model(&Scene{}).
+	query := restrictScenes(r.DB.Model(&Scene{}).
 		Where("storage_path_id = ?", storagePathID).
 		Where("stored_path LIKE ?", fullPath+"%").
-		Where("trashed_at IS NULL")
+		Where("trashed_at IS NULL"), restriction)
 
 	if !recursive {
 		// Only direct children
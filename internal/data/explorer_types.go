@@ -7,6 +7,11 @@ type FolderInfo struct {
 	SceneCount    int64  `json:"scene_count"`
 	TotalDuration int64  `json:"total_duration"` // Total duration in seconds
 	TotalSize     int64  `json:"total_size"`     // Total size in bytes
+	// Scenes whose processing has not completed
+	UnprocessedCount int64 `json:"unprocessed_count"`
+	// Average resolution of the scenes with known dimensions, 0 without any
+	AverageWidth  int `json:"average_width"`
+	AverageHeight int `json:"average_height"`
 }
 
 // StoragePathWithCount extends StoragePath with scene count
@@ -0,0 +1,111 @@
+package data
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// FolderRule tags scenes whose folder matches Pattern. Tags and actors are
+// added to what a scene already has; the studio and type are only set on
+// scenes that have none.
+type FolderRule struct {
+	ID            uint          `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	Name          string        `gorm:"size:255;not null" json:"name"`
+	StoragePathID *uint         `json:"storage_path_id"`                  // nil applies the rule to every storage path
+	Pattern       string        `gorm:"size:255;not null" json:"pattern"` // glob matched against folders relative to the storage path
+	TagIDs        pq.Int64Array `gorm:"type:bigint[]" json:"tag_ids"`
+	ActorIDs      pq.Int64Array `gorm:"type:bigint[]" json:"actor_ids"`
+	StudioID      *uint         `json:"studio_id"`
+	SceneType     string        `gorm:"size:50;not null;default:''" json:"scene_type"`
+	Enabled       bool          `gorm:"not null;default:true" json:"enabled"`
+}
+
+func (FolderRule) TableName() string {
+	return "folder_rules"
+}
+
+type FolderRuleRepository interface {
+	List() ([]FolderRule, error)
+	ListEnabled() ([]FolderRule, error)
+	GetByID(id uint) (*FolderRule, error)
+	Create(rule *FolderRule) error
+	Update(rule *FolderRule) error
+	Delete(id uint) error
+	FillStudioAndType(sceneIDs []uint, studioID *uint, sceneType string) error
+}
+
+type FolderRuleRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewFolderRuleRepository(db *gorm.DB) *FolderRuleRepositoryImpl {
+	return &FolderRuleRepositoryImpl{DB: db}
+}
+
+func (r *FolderRuleRepositoryImpl) List() ([]FolderRule, error) {
+	var rules []FolderRule
+	if err := r.DB.Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *FolderRuleRepositoryImpl) ListEnabled() ([]FolderRule, error) {
+	var rules []FolderRule
+	if err := r.DB.Where("enabled = ?", true).Order("id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *FolderRuleRepositoryImpl) GetByID(id uint) (*FolderRule, error) {
+	var rule FolderRule
+	if err := r.DB.First(&rule, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *FolderRuleRepositoryImpl) Create(rule *FolderRule) error {
+	return r.DB.Create(rule).Error
+}
+
+func (r *FolderRuleRepositoryImpl) Update(rule *FolderRule) error {
+	return r.DB.Save(rule).Error
+}
+
+func (r *FolderRuleRepositoryImpl) Delete(id uint) error {
+	return r.DB.Delete(&FolderRule{}, id).Error
+}
+
+// FillStudioAndType sets the studio and type of the scenes that have none.
+// A nil studio or empty type leaves that field alone.
+func (r *FolderRuleRepositoryImpl) FillStudioAndType(sceneIDs []uint, studioID *uint, sceneType string) error {
+	if len(sceneIDs) == 0 {
+		return nil
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if studioID != nil {
+			if err := tx.Model(&Scene{}).
+				Where("id IN ? AND studio_id IS NULL", sceneIDs).
+				Update("studio_id", *studioID).Error; err != nil {
+				return err
+			}
+		}
+		if sceneType != "" {
+			if err := tx.Model(&Scene{}).
+				Where("id IN ? AND COALESCE(type, '') = ''", sceneIDs).
+				Update("type", sceneType).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
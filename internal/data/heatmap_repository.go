@@ -0,0 +1,91 @@
+package data
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// HeatmapSpan is the range a marker covers; End is nil for a single point.
+type HeatmapSpan struct {
+	Start int
+	End   *int
+}
+
+// HeatmapRepository reads the activity scene heatmaps are aggregated from.
+type HeatmapRepository interface {
+	// ListStaleSceneIDs returns scenes whose markers or watches changed since
+	// their heatmap was computed, plus heatmaps computed before refreshBefore
+	// (which catches deleted markers).
+	ListStaleSceneIDs(refreshBefore time.Time, limit int) ([]uint, error)
+	GetMarkerSpans(sceneID uint) ([]HeatmapSpan, error)
+	GetWatchPositions(sceneID uint) ([]int, error)
+	SaveHeatmap(sceneID uint, buckets []int64, computedAt time.Time) error
+}
+
+type HeatmapRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewHeatmapRepository(db *gorm.DB) *HeatmapRepositoryImpl {
+	return &HeatmapRepositoryImpl{DB: db}
+}
+
+func (r *HeatmapRepositoryImpl) ListStaleSceneIDs(refreshBefore time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.DB.Raw(`
+		SELECT s.id FROM scenes s
+		WHERE s.deleted_at IS NULL AND s.trashed_at IS NULL AND s.duration > 0
+		AND (
+			EXISTS (SELECT 1 FROM user_scene_markers m WHERE m.scene_id = s.id
+				AND (s.heatmap_updated_at IS NULL OR m.updated_at > s.heatmap_updated_at))
+			OR EXISTS (SELECT 1 FROM user_scene_watches w WHERE w.scene_id = s.id
+				AND (s.heatmap_updated_at IS NULL OR w.updated_at > s.heatmap_updated_at))
+			OR (s.heatmap IS NOT NULL AND s.heatmap_updated_at < ?)
+		)
+		ORDER BY s.heatmap_updated_at ASC NULLS FIRST, s.id ASC
+		LIMIT ?
+	`, refreshBefore, limit).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *HeatmapRepositoryImpl) GetMarkerSpans(sceneID uint) ([]HeatmapSpan, error) {
+	var spans []HeatmapSpan
+	err := r.DB.Model(&UserSceneMarker{}).
+		Select("timestamp AS start, end_timestamp AS \"end\"").
+		Where("scene_id = ?", sceneID).
+		Scan(&spans).Error
+	if err != nil {
+		return nil, err
+	}
+	return spans, nil
+}
+
+// GetWatchPositions returns where unfinished watch sessions of the scene stopped.
+func (r *HeatmapRepositoryImpl) GetWatchPositions(sceneID uint) ([]int, error) {
+	var positions []int
+	err := r.DB.Model(&UserSceneWatch{}).
+		Where("scene_id = ? AND completed = ? AND last_position > 0", sceneID, false).
+		Pluck("last_position", &positions).Error
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// SaveHeatmap stores a scene's heatmap without touching updated_at. An empty
+// heatmap is stored as NULL.
+func (r *HeatmapRepositoryImpl) SaveHeatmap(sceneID uint, buckets []int64, computedAt time.Time) error {
+	var heatmap any
+	if len(buckets) > 0 {
+		heatmap = pq.Int64Array(buckets)
+	}
+	return r.DB.Model(&Scene{}).Where("id = ?", sceneID).UpdateColumns(map[string]any{
+		"heatmap":            heatmap,
+		"heatmap_updated_at": computedAt,
+	}).Error
+}
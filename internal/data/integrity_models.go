@@ -0,0 +1,93 @@
+package data
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SceneIntegrityReport stores the result of the most recent full decode
+// verification of a scene's file.
+type SceneIntegrityReport struct {
+	ID               uint            `gorm:"primarykey" json:"id"`
+	SceneID          uint            `gorm:"not null;uniqueIndex" json:"scene_id"`
+	JobID            string          `gorm:"size:36;not null;default:''" json:"job_id"`
+	IsCorrupted      bool            `gorm:"not null;default:false" json:"is_corrupted"`
+	DecodeCompleted  bool            `gorm:"not null;default:false" json:"decode_completed"`
+	ErrorCount       int             `gorm:"not null;default:0" json:"error_count"`
+	DecodedSeconds   float64         `gorm:"not null;default:0" json:"decoded_seconds"`
+	Errors           IntegrityErrors `gorm:"type:jsonb;not null;default:'[]'" json:"errors"`
+	UnreadableRanges IntegrityRanges `gorm:"type:jsonb;not null;default:'[]'" json:"unreadable_ranges"`
+	CheckedAt        time.Time       `gorm:"not null" json:"checked_at"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+func (SceneIntegrityReport) TableName() string {
+	return "scene_integrity_reports"
+}
+
+// IntegrityError is a single decoder error at an approximate position in seconds.
+type IntegrityError struct {
+	Timestamp float64 `json:"timestamp"`
+	Message   string  `json:"message"`
+}
+
+// IntegrityRange is a span of the file, in seconds, that failed to decode cleanly.
+type IntegrityRange struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// IntegrityErrors is a JSONB-backed list of decoder errors
+type IntegrityErrors []IntegrityError
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (e IntegrityErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval
+func (e *IntegrityErrors) Scan(value any) error {
+	if value == nil {
+		*e = IntegrityErrors{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan IntegrityErrors: expected []byte")
+	}
+
+	return json.Unmarshal(bytes, e)
+}
+
+// IntegrityRanges is a JSONB-backed list of unreadable time ranges
+type IntegrityRanges []IntegrityRange
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (r IntegrityRanges) Value() (driver.Value, error) {
+	if r == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval
+func (r *IntegrityRanges) Scan(value any) error {
+	if value == nil {
+		*r = IntegrityRanges{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan IntegrityRanges: expected []byte")
+	}
+
+	return json.Unmarshal(bytes, r)
+}
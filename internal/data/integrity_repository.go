@@ -0,0 +1,38 @@
+package data
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SceneIntegrityRepository interface {
+	Upsert(report *SceneIntegrityReport) error
+	GetBySceneID(sceneID uint) (*SceneIntegrityReport, error)
+}
+
+type SceneIntegrityRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewSceneIntegrityRepository(db *gorm.DB) *SceneIntegrityRepositoryImpl {
+	return &SceneIntegrityRepositoryImpl{DB: db}
+}
+
+// Upsert creates or replaces the integrity report for the report's scene.
+func (r *SceneIntegrityRepositoryImpl) Upsert(report *SceneIntegrityReport) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scene_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"job_id", "is_corrupted", "decode_completed", "error_count", "decoded_seconds",
+			"errors", "unreadable_ranges", "checked_at", "updated_at",
+		}),
+	}).Create(report).Error
+}
+
+func (r *SceneIntegrityRepositoryImpl) GetBySceneID(sceneID uint) (*SceneIntegrityReport, error) {
+	var report SceneIntegrityReport
+	if err := r.DB.Where("scene_id = ?", sceneID).First(&report).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
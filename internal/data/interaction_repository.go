@@ -30,6 +30,7 @@ type InteractionRepository interface {
 	GetJizzedSceneIDs(userID uint, minCount, maxCount int) ([]uint, error)
 	GetLikesBySceneIDs(userID uint, sceneIDs []uint) (map[uint]bool, error)
 	GetJizzCountsBySceneIDs(userID uint, sceneIDs []uint) (map[uint]int, error)
+	GetMaxRatingsBySceneIDs(sceneIDs []uint) (map[uint]float64, error)
 }
 
 type InteractionRepositoryImpl struct {
@@ -267,3 +268,30 @@ func (r *InteractionRepositoryImpl) GetJizzCountsBySceneIDs(userID uint, sceneID
 
 // Ensure InteractionRepositoryImpl implements InteractionRepository
 var _ InteractionRepository = (*InteractionRepositoryImpl)(nil)
+
+// GetMaxRatingsBySceneIDs returns the highest rating any user gave each scene.
+// Scenes nobody rated are absent from the map.
+func (r *InteractionRepositoryImpl) GetMaxRatingsBySceneIDs(sceneIDs []uint) (map[uint]float64, error) {
+	if len(sceneIDs) == 0 {
+		return make(map[uint]float64), nil
+	}
+
+	var rows []struct {
+		SceneID   uint
+		MaxRating float64
+	}
+	err := r.DB.Model(&UserSceneRating{}).
+		Select("scene_id, MAX(rating) as max_rating").
+		Where("scene_id IN ?", sceneIDs).
+		Group("scene_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		result[row.SceneID] = row.MaxRating
+	}
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package data
+
+import "time"
+
+// UserInvite is a single-use registration link created by an admin. The account
+// registered with it gets Role. Only the SHA-256 of the invite token is stored.
+type UserInvite struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Role      string     `gorm:"size:50;not null" json:"role"`
+	Note      string     `gorm:"size:255;not null;default:''" json:"note"`
+	CreatedBy *uint      `json:"created_by"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	UsedBy    *uint      `json:"used_by"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `gorm:"not null" json:"created_at"`
+}
+
+func (UserInvite) TableName() string {
+	return "user_invites"
+}
+
+// IsUsable reports whether the invite can still be used to register at the given time.
+func (i *UserInvite) IsUsable(now time.Time) bool {
+	return i.UsedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
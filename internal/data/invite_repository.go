@@ -0,0 +1,91 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InviteRepository interface {
+	Create(invite *UserInvite) error
+	GetByHash(tokenHash string) (*UserInvite, error)
+	List(page, limit int) ([]UserInvite, int64, error)
+	Revoke(id uint, at time.Time) error
+	// Claim marks a usable invite as used, failing with gorm.ErrRecordNotFound if
+	// it was used, revoked or expired in the meantime
+	Claim(id uint, at time.Time) error
+	// Release undoes a Claim whose registration failed
+	Release(id uint) error
+	SetUsedBy(id, userID uint) error
+}
+
+type InviteRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewInviteRepository(db *gorm.DB) *InviteRepositoryImpl {
+	return &InviteRepositoryImpl{DB: db}
+}
+
+func (r *InviteRepositoryImpl) Create(invite *UserInvite) error {
+	return r.DB.Create(invite).Error
+}
+
+func (r *InviteRepositoryImpl) GetByHash(tokenHash string) (*UserInvite, error) {
+	var invite UserInvite
+	if err := r.DB.Where("token_hash = ?", tokenHash).First(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *InviteRepositoryImpl) List(page, limit int) ([]UserInvite, int64, error) {
+	var total int64
+	if err := r.DB.Model(&UserInvite{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var invites []UserInvite
+	err := r.DB.Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&invites).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return invites, total, nil
+}
+
+func (r *InviteRepositoryImpl) Revoke(id uint, at time.Time) error {
+	result := r.DB.Model(&UserInvite{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *InviteRepositoryImpl) Claim(id uint, at time.Time) error {
+	result := r.DB.Model(&UserInvite{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", id, at).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *InviteRepositoryImpl) Release(id uint) error {
+	return r.DB.Model(&UserInvite{}).Where("id = ?", id).Update("used_at", nil).Error
+}
+
+func (r *InviteRepositoryImpl) SetUsedBy(id, userID uint) error {
+	return r.DB.Model(&UserInvite{}).Where("id = ?", id).Update("used_by", userID).Error
+}
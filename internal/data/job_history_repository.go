@@ -5,15 +5,24 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type JobHistoryRepository interface {
 	Create(record *JobHistory) error
 	UpdateStatus(jobID string, status string, errorMessage *string, completedAt *time.Time) error
 	ListAll(page, limit int, status string) ([]JobHistory, int64, error)
+	// ListAfter is ListAll paged by cursor, newest first; the returned cursor is nil on the last page
+	ListAfter(cursor *Cursor, limit int, status string) ([]JobHistory, *Cursor, error)
 	ListRecentFailed(limit int, since time.Duration) ([]JobHistory, error)
 	ListActive() ([]JobHistory, error)
-	DeleteOlderThan(before time.Time) (int64, error)
+	// PruneFinished reclaims up to limit jobs finished before a time, moving
+	// them to job_history_archive first when archive is set. Failed and timed
+	// out jobs are folded into job_failure_summaries either way.
+	PruneFinished(before time.Time, archive bool, limit int) (JobPruneResult, error)
+	// ListFailureSummaries returns the failure summaries of pruned jobs, most
+	// recent first, optionally of one phase
+	ListFailureSummaries(phase string, limit int) ([]JobFailureSummary, error)
 	UpdateProgress(jobID string, progress int) error
 	UpdateRetryInfo(jobID string, retryCount, maxRetries int, nextRetryAt *time.Time) error
 	GetRetryableJobs() ([]JobHistory, error)
@@ -23,29 +32,48 @@ type JobHistoryRepository interface {
 
 	// DB-backed job queue methods
 	CreatePending(record *JobHistory) error
-	CreateBatch(records []*JobHistory) error
+	CreateBatch(records []*JobHistory) (skipped int64, err error)
 	ClaimPendingJobs(phase string, limit int) ([]JobHistory, error)
+	// ClaimPendingJobsExcluding claims like ClaimPendingJobs but leaves the jobs
+	// of scenes in the given storage paths pending
+	ClaimPendingJobsExcluding(phase string, limit int, storagePathIDs []uint) ([]JobHistory, error)
 	CountPendingByPhase() (map[string]int, error)
-	ExistsPendingOrRunning(sceneID uint, phase string) (bool, error)
 	MarkOrphanedRunningAsFailed(olderThan time.Duration) (int64, error)
+	RequeueOrphanedRunningJobs(olderThan time.Duration, maxRequeues int, phases []string) (int64, error)
 
 	// Graceful shutdown methods
 	ResetJobsToPending(jobIDs []string) (int64, error)
 	MarkRunningAsInterrupted() (int64, error)
-	MarkStuckPendingJobsAsFailed(olderThan time.Duration) (int64, error)
 
 	// Scene-specific methods
 	CancelPendingJobsForScene(sceneID uint) (int64, error)
 	CancelPendingJob(jobID string) error
+	// CancelPendingByFilter cancels the pending jobs matching filter in one statement
+	CancelPendingByFilter(filter JobCancelFilter) (int64, error)
+	// ListRunningByFilter returns the claimed jobs matching filter, queued in a
+	// pool or executing
+	ListRunningByFilter(filter JobCancelFilter) ([]JobHistory, error)
 
 	// Monitoring methods
 	CountRecentFailedByPhase(since time.Duration) (map[string]int, error)
 
+	// Analytics methods, over the jobs finished since a time
+	PhaseStats(since time.Time) ([]JobPhaseStats, error)
+	OutcomeTimeline(since time.Time, bucket string) ([]JobTimelineBucket, error)
+	SlowestJobs(since time.Time, phase string, limit int) ([]JobDuration, error)
+
 	// Bulk operations
 	GetFailedJobs() ([]JobHistory, error)
 	DeleteByStatus(status string) (int64, error)
 }
 
+// JobCancelFilter selects jobs for a bulk cancel. Empty fields match all.
+type JobCancelFilter struct {
+	Phase         string
+	SceneIDs      []uint
+	CreatedBefore time.Time // zero = any age
+}
+
 type JobHistoryRepositoryImpl struct {
 	DB *gorm.DB
 }
@@ -102,6 +130,37 @@ func (r *JobHistoryRepositoryImpl) ListAll(page, limit int, status string) ([]Jo
 	return records, total, nil
 }
 
+// JobHistoryCursorSort is the sort order job history cursors are issued for.
+const JobHistoryCursorSort = "started_at"
+
+func (r *JobHistoryRepositoryImpl) ListAfter(cursor *Cursor, limit int, status string) ([]JobHistory, *Cursor, error) {
+	query := r.DB.Model(&JobHistory{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	} else {
+		query = query.Where("status != ?", "running")
+	}
+	if cursor != nil {
+		startedAt, err := cursor.TimeValue()
+		if err != nil {
+			return nil, nil, err
+		}
+		query = query.Where(keysetAfter("started_at", "id", true), startedAt, cursor.ID)
+	}
+
+	var records []JobHistory
+	if err := query.Order("started_at DESC, id DESC").Limit(limit + 1).Find(&records).Error; err != nil {
+		return nil, nil, err
+	}
+
+	records, more := trimPage(records, limit)
+	if !more {
+		return records, nil, nil
+	}
+	last := records[len(records)-1]
+	return records, NewCursor(JobHistoryCursorSort, last.StartedAt, last.ID), nil
+}
+
 func (r *JobHistoryRepositoryImpl) ListRecentFailed(limit int, since time.Duration) ([]JobHistory, error) {
 	var records []JobHistory
 	cutoff := time.Now().Add(-since)
@@ -124,9 +183,94 @@ func (r *JobHistoryRepositoryImpl) ListActive() ([]JobHistory, error) {
 	return records, nil
 }
 
-func (r *JobHistoryRepositoryImpl) DeleteOlderThan(before time.Time) (int64, error) {
-	result := r.DB.Where("started_at < ? AND status != ?", before, "running").Delete(&JobHistory{})
-	return result.RowsAffected, result.Error
+// failureSummaryErrorSize is how much of an error message keys a failure summary
+const failureSummaryErrorSize = 500
+
+func (r *JobHistoryRepositoryImpl) PruneFinished(before time.Time, archive bool, limit int) (JobPruneResult, error) {
+	var result JobPruneResult
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		// Jobs still waiting for a scheduled retry are kept
+		var candidates []JobHistory
+		if err := tx.Model(&JobHistory{}).
+			Select("id, status").
+			Where("status IN ? AND completed_at < ?", jobFinishedStatuses, before).
+			Where("NOT (status = ? AND is_retryable AND next_retry_at IS NOT NULL)", JobStatusFailed).
+			Order("id").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&candidates).Error; err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(candidates))
+		for i, job := range candidates {
+			ids[i] = job.ID
+			if job.Status == JobStatusFailed || job.Status == JobStatusTimedOut {
+				result.Summarized++
+			}
+		}
+
+		if result.Summarized > 0 {
+			if err := tx.Exec(`
+				INSERT INTO job_failure_summaries (phase, error_message, failures, first_failed_at, last_failed_at, updated_at)
+				SELECT phase, LEFT(COALESCE(error_message, ''), ?), COUNT(*), MIN(completed_at), MAX(completed_at), NOW()
+				FROM job_history
+				WHERE id IN ? AND status IN ?
+				GROUP BY 1, 2
+				ON CONFLICT (phase, error_message) DO UPDATE SET
+					failures = job_failure_summaries.failures + EXCLUDED.failures,
+					first_failed_at = LEAST(job_failure_summaries.first_failed_at, EXCLUDED.first_failed_at),
+					last_failed_at = GREATEST(job_failure_summaries.last_failed_at, EXCLUDED.last_failed_at),
+					updated_at = NOW()`,
+				failureSummaryErrorSize, ids, []string{JobStatusFailed, JobStatusTimedOut},
+			).Error; err != nil {
+				return fmt.Errorf("failed to summarize failed jobs: %w", err)
+			}
+		}
+
+		if archive {
+			archived := tx.Exec(`
+				INSERT INTO job_history_archive (job_id, scene_id, scene_title, phase, status, priority, force_target,
+					error_message, retry_count, started_at, completed_at, created_at)
+				SELECT job_id, scene_id, scene_title, phase, status, priority, force_target,
+					error_message, retry_count, started_at, completed_at, created_at
+				FROM job_history
+				WHERE id IN ?
+				ON CONFLICT (job_id) DO NOTHING`,
+				ids,
+			)
+			if archived.Error != nil {
+				return fmt.Errorf("failed to archive jobs: %w", archived.Error)
+			}
+			result.Archived = archived.RowsAffected
+		}
+
+		deleted := tx.Where("id IN ?", ids).Delete(&JobHistory{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		result.Deleted = deleted.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return JobPruneResult{}, err
+	}
+	return result, nil
+}
+
+func (r *JobHistoryRepositoryImpl) ListFailureSummaries(phase string, limit int) ([]JobFailureSummary, error) {
+	query := r.DB.Model(&JobFailureSummary{})
+	if phase != "" {
+		query = query.Where("phase = ?", phase)
+	}
+	var summaries []JobFailureSummary
+	if err := query.Order("last_failed_at DESC").Limit(limit).Find(&summaries).Error; err != nil {
+		return nil, err
+	}
+	return summaries, nil
 }
 
 func (r *JobHistoryRepositoryImpl) UpdateProgress(jobID string, progress int) error {
@@ -181,34 +325,71 @@ func (r *JobHistoryRepositoryImpl) IncrementRetryCount(jobID string) error {
 // CreatePending creates a job with status='pending'
 func (r *JobHistoryRepositoryImpl) CreatePending(record *JobHistory) error {
 	record.Status = JobStatusPending
-	return r.DB.Create(record).Error
+	result := r.DB.Clauses(activeJobConflict).Create(record)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrActiveJobExists
+	}
+	return nil
 }
 
-// CreateBatch inserts multiple pending jobs efficiently
-func (r *JobHistoryRepositoryImpl) CreateBatch(records []*JobHistory) error {
+// CreateBatch inserts multiple pending jobs efficiently, skipping those that
+// already have an active job. It returns how many were skipped.
+func (r *JobHistoryRepositoryImpl) CreateBatch(records []*JobHistory) (int64, error) {
 	if len(records) == 0 {
-		return nil
+		return 0, nil
 	}
 	for _, record := range records {
 		record.Status = JobStatusPending
 	}
-	return r.DB.CreateInBatches(records, 100).Error
+	result := r.DB.Clauses(activeJobConflict).CreateInBatches(records, 100)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int64(len(records)) - result.RowsAffected, nil
+}
+
+// activeJobConflict skips inserts that would give a scene a second active job
+// with the same phase and parameters (idx_job_history_active_key). Unlike a
+// lookup before inserting, it holds across concurrent submitters, restarts
+// and pool resizes.
+var activeJobConflict = clause.OnConflict{
+	Columns: []clause.Column{{Name: "scene_id"}, {Name: "phase"}, {Name: "params_hash"}},
+	TargetWhere: clause.Where{Exprs: []clause.Expression{
+		clause.Expr{SQL: "status IN ('pending', 'running')"},
+	}},
+	DoNothing: true,
 }
 
 // ClaimPendingJobs atomically claims up to 'limit' pending jobs for a phase.
 // Uses FOR UPDATE SKIP LOCKED, sets status='running' and StartedAt.
 func (r *JobHistoryRepositoryImpl) ClaimPendingJobs(phase string, limit int) ([]JobHistory, error) {
+	return r.ClaimPendingJobsExcluding(phase, limit, nil)
+}
+
+func (r *JobHistoryRepositoryImpl) ClaimPendingJobsExcluding(phase string, limit int, storagePathIDs []uint) ([]JobHistory, error) {
 	var jobs []JobHistory
 
 	err := r.DB.Transaction(func(tx *gorm.DB) error {
 		// Select pending jobs with lock, skipping already locked rows
+		exclude := ""
+		args := []any{phase}
+		if len(storagePathIDs) > 0 {
+			exclude = `AND NOT EXISTS (
+				SELECT 1 FROM scenes
+				WHERE scenes.id = job_history.scene_id AND scenes.storage_path_id IN ?
+			)`
+			args = append(args, storagePathIDs)
+		}
 		if err := tx.Raw(`
 			SELECT * FROM job_history
-			WHERE phase = ? AND status = 'pending'
+			WHERE phase = ? AND status = 'pending' `+exclude+`
 			ORDER BY priority DESC, created_at ASC
 			LIMIT ?
 			FOR UPDATE SKIP LOCKED
-		`, phase, limit).Scan(&jobs).Error; err != nil {
+		`, append(args, limit)...).Scan(&jobs).Error; err != nil {
 			return err
 		}
 
@@ -269,17 +450,6 @@ func (r *JobHistoryRepositoryImpl) CountPendingByPhase() (map[string]int, error)
 	return result, nil
 }
 
-// ExistsPendingOrRunning checks if scene+phase already has a pending or running job
-func (r *JobHistoryRepositoryImpl) ExistsPendingOrRunning(sceneID uint, phase string) (bool, error) {
-	var count int64
-	if err := r.DB.Model(&JobHistory{}).
-		Where("scene_id = ? AND phase = ? AND status IN ?", sceneID, phase, []string{JobStatusPending, JobStatusRunning}).
-		Count(&count).Error; err != nil {
-		return false, err
-	}
-	return count > 0, nil
-}
-
 // MarkOrphanedRunningAsFailed marks jobs that have been running for too long as failed.
 // These are likely orphaned jobs from a previous server crash.
 func (r *JobHistoryRepositoryImpl) MarkOrphanedRunningAsFailed(olderThan time.Duration) (int64, error) {
@@ -298,6 +468,29 @@ func (r *JobHistoryRepositoryImpl) MarkOrphanedRunningAsFailed(olderThan time.Du
 	return result.RowsAffected, result.Error
 }
 
+// RequeueOrphanedRunningJobs puts jobs of the given phases left running by a previous
+// crash back in the pending queue. Most of them were only claimed into a channel buffer
+// and never started. created_at and priority are untouched, so they are fed again in
+// their original order. Only phases something claims pending jobs of may be given; the
+// jobs of other phases are left running for MarkOrphanedRunningAsFailed, as are jobs
+// already requeued maxRequeues times, so a job that crashes the server can't loop.
+func (r *JobHistoryRepositoryImpl) RequeueOrphanedRunningJobs(olderThan time.Duration, maxRequeues int, phases []string) (int64, error) {
+	if len(phases) == 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().Add(-olderThan)
+
+	result := r.DB.Model(&JobHistory{}).
+		Where("status = ? AND started_at < ? AND requeue_count < ? AND phase IN ?", JobStatusRunning, cutoff, maxRequeues, phases).
+		Updates(map[string]any{
+			"status":        JobStatusPending,
+			"progress":      0,
+			"requeue_count": gorm.Expr("requeue_count + 1"),
+		})
+
+	return result.RowsAffected, result.Error
+}
+
 // ResetJobsToPending resets jobs by their IDs back to pending status.
 // Used during graceful shutdown to reclaim jobs that were in channel buffers.
 // Note: We keep the original started_at value since the column is NOT NULL.
@@ -332,24 +525,6 @@ func (r *JobHistoryRepositoryImpl) MarkRunningAsInterrupted() (int64, error) {
 	return result.RowsAffected, result.Error
 }
 
-// MarkStuckPendingJobsAsFailed marks pending jobs that have been stuck for too long as failed.
-// This handles edge cases where jobs got stuck in pending state.
-func (r *JobHistoryRepositoryImpl) MarkStuckPendingJobsAsFailed(olderThan time.Duration) (int64, error) {
-	cutoff := time.Now().Add(-olderThan)
-	errMsg := "Stuck pending job recovered after server restart"
-
-	result := r.DB.Model(&JobHistory{}).
-		Where("status = ? AND created_at < ?", JobStatusPending, cutoff).
-		Updates(map[string]any{
-			"status":        JobStatusFailed,
-			"error_message": errMsg,
-			"completed_at":  time.Now(),
-			"is_retryable":  true,
-		})
-
-	return result.RowsAffected, result.Error
-}
-
 // CancelPendingJobsForScene cancels all pending jobs for a scene (marks them as cancelled).
 func (r *JobHistoryRepositoryImpl) CancelPendingJobsForScene(sceneID uint) (int64, error) {
 	result := r.DB.Model(&JobHistory{}).
@@ -383,6 +558,39 @@ func (r *JobHistoryRepositoryImpl) CancelPendingJob(jobID string) error {
 	return nil
 }
 
+func (r *JobHistoryRepositoryImpl) applyCancelFilter(query *gorm.DB, filter JobCancelFilter) *gorm.DB {
+	if filter.Phase != "" {
+		query = query.Where("phase = ?", filter.Phase)
+	}
+	if len(filter.SceneIDs) > 0 {
+		query = query.Where("scene_id IN ?", filter.SceneIDs)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedBefore)
+	}
+	return query
+}
+
+func (r *JobHistoryRepositoryImpl) CancelPendingByFilter(filter JobCancelFilter) (int64, error) {
+	result := r.applyCancelFilter(r.DB.Model(&JobHistory{}), filter).
+		Where("status = ?", JobStatusPending).
+		Updates(map[string]any{
+			"status":       JobStatusCancelled,
+			"completed_at": time.Now(),
+			"is_retryable": false,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *JobHistoryRepositoryImpl) ListRunningByFilter(filter JobCancelFilter) ([]JobHistory, error) {
+	var jobs []JobHistory
+	err := r.applyCancelFilter(r.DB.Model(&JobHistory{}), filter).
+		Where("status = ?", JobStatusRunning).
+		Order("created_at asc").
+		Find(&jobs).Error
+	return jobs, err
+}
+
 // CountRecentFailedByPhase returns the count of failed jobs per phase within a time window.
 func (r *JobHistoryRepositoryImpl) CountRecentFailedByPhase(since time.Duration) (map[string]int, error) {
 	type phaseCount struct {
@@ -424,3 +632,63 @@ func (r *JobHistoryRepositoryImpl) DeleteByStatus(status string) (int64, error)
 	result := r.DB.Where("status = ?", status).Delete(&JobHistory{})
 	return result.RowsAffected, result.Error
 }
+
+// jobDurationSQL is the run time of a finished job in seconds
+const jobDurationSQL = "EXTRACT(EPOCH FROM completed_at - started_at)"
+
+// PhaseStats returns, per phase, the outcomes of the jobs finished since a
+// time and the duration distribution of the completed ones.
+func (r *JobHistoryRepositoryImpl) PhaseStats(since time.Time) ([]JobPhaseStats, error) {
+	var stats []JobPhaseStats
+	err := r.DB.Raw(`
+		SELECT phase,
+			COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE status = 'failed') AS failed,
+			COUNT(*) FILTER (WHERE status = 'timed_out') AS timed_out,
+			COUNT(*) FILTER (WHERE status = 'cancelled') AS cancelled,
+			AVG(`+jobDurationSQL+`) FILTER (WHERE status = 'completed') AS avg_seconds,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY `+jobDurationSQL+`) FILTER (WHERE status = 'completed') AS p50_seconds,
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY `+jobDurationSQL+`) FILTER (WHERE status = 'completed') AS p95_seconds,
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY `+jobDurationSQL+`) FILTER (WHERE status = 'completed') AS p99_seconds,
+			MAX(`+jobDurationSQL+`) FILTER (WHERE status = 'completed') AS max_seconds
+		FROM job_history
+		WHERE completed_at >= ? AND status IN ?
+		GROUP BY phase
+		ORDER BY phase`,
+		since, jobFinishedStatuses,
+	).Scan(&stats).Error
+	return stats, err
+}
+
+// OutcomeTimeline counts the jobs finished since a time per phase and
+// bucket, where bucket is a date_trunc unit ("hour" or "day").
+func (r *JobHistoryRepositoryImpl) OutcomeTimeline(since time.Time, bucket string) ([]JobTimelineBucket, error) {
+	var buckets []JobTimelineBucket
+	err := r.DB.Raw(`
+		SELECT date_trunc(?, completed_at) AS bucket, phase,
+			COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE status IN ('failed', 'timed_out')) AS failed,
+			COUNT(*) FILTER (WHERE status = 'cancelled') AS cancelled
+		FROM job_history
+		WHERE completed_at >= ? AND status IN ?
+		GROUP BY 1, phase
+		ORDER BY 1, phase`,
+		bucket, since, jobFinishedStatuses,
+	).Scan(&buckets).Error
+	return buckets, err
+}
+
+// SlowestJobs returns the longest completed scene jobs finished since a
+// time, optionally of one phase.
+func (r *JobHistoryRepositoryImpl) SlowestJobs(since time.Time, phase string, limit int) ([]JobDuration, error) {
+	query := r.DB.Model(&JobHistory{}).
+		Select("job_id, scene_id, scene_title, phase, started_at, completed_at, "+jobDurationSQL+" AS duration_seconds").
+		Where("status = ? AND completed_at >= ? AND scene_id <> 0", JobStatusCompleted, since)
+	if phase != "" {
+		query = query.Where("phase = ?", phase)
+	}
+
+	var jobs []JobDuration
+	err := query.Order("duration_seconds DESC").Limit(limit).Scan(&jobs).Error
+	return jobs, err
+}
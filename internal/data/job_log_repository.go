@@ -0,0 +1,36 @@
+package data
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobLogRepository interface {
+	// Save stores the log of a job run, replacing the log of an earlier run
+	// of the same job (requeued after a restart)
+	Save(log *JobLog) error
+	GetByJobID(jobID string) (*JobLog, error)
+}
+
+type JobLogRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewJobLogRepository(db *gorm.DB) *JobLogRepositoryImpl {
+	return &JobLogRepositoryImpl{DB: db}
+}
+
+func (r *JobLogRepositoryImpl) Save(log *JobLog) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content", "truncated", "created_at"}),
+	}).Create(log).Error
+}
+
+func (r *JobLogRepositoryImpl) GetByJobID(jobID string) (*JobLog, error) {
+	var log JobLog
+	if err := r.DB.Where("job_id = ?", jobID).First(&log).Error; err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
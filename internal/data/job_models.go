@@ -1,7 +1,13 @@
 package data
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
 )
 
 // Job status constants
@@ -26,18 +32,151 @@ type JobHistory struct {
 	CompletedAt  *time.Time `json:"completed_at,omitempty"`
 	CreatedAt    time.Time  `gorm:"not null;default:now()" json:"created_at"`
 	RetryCount   int        `gorm:"not null;default:0" json:"retry_count"`
+	RequeueCount int        `gorm:"not null;default:0" json:"requeue_count"`
 	MaxRetries   int        `gorm:"not null;default:0" json:"max_retries"`
 	NextRetryAt  *time.Time `json:"next_retry_at,omitempty"`
 	Progress     int        `gorm:"not null;default:0" json:"progress"`
 	IsRetryable  bool       `gorm:"not null;default:true" json:"is_retryable"`
 	Priority     int        `gorm:"not null;default:0" json:"priority"`
 	ForceTarget  string     `gorm:"not null;size:20;default:''" json:"force_target"`
+	ParamsHash   string     `gorm:"not null;size:64;default:''" json:"-"`
 }
 
 func (JobHistory) TableName() string {
 	return "job_history"
 }
 
+// BeforeCreate sets ParamsHash from the job's parameters. A scene can't have
+// two pending or running jobs with the same phase and parameters hash. Jobs
+// without a scene (URL imports, compilations, storage migrations, ...) all
+// have scene_id 0, so they are keyed by their own job ID instead and any
+// number of them can be active at once.
+func (j *JobHistory) BeforeCreate(tx *gorm.DB) error {
+	if j.SceneID == 0 {
+		j.ParamsHash = sceneLessJobKey(j.JobID)
+		return nil
+	}
+	j.ParamsHash = JobParamsHash(j.ForceTarget)
+	return nil
+}
+
+// ErrActiveJobExists is returned when a pending or running job with the same
+// scene, phase and parameters already exists.
+var ErrActiveJobExists = errors.New("job already pending or running")
+
+// JobParamsHash hashes the parameters that change what a job does, which
+// today is only force_target. Migration 000106 computes the same in SQL.
+func JobParamsHash(forceTarget string) string {
+	sum := sha256.Sum256([]byte("force_target=" + forceTarget))
+	return hex.EncodeToString(sum[:])
+}
+
+// sceneLessJobKey is the params_hash of a job that has no scene.
+func sceneLessJobKey(jobID string) string {
+	sum := sha256.Sum256([]byte("job_id=" + jobID))
+	return hex.EncodeToString(sum[:])
+}
+
+// JobLog is the captured log of a job run: the worker pool's lines and the
+// output of the ffmpeg runs behind the job.
+type JobLog struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	JobID     string    `gorm:"uniqueIndex;not null;size:36" json:"job_id"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	Truncated bool      `gorm:"not null" json:"truncated"`
+	CreatedAt time.Time `gorm:"not null;default:now()" json:"created_at"`
+}
+
+func (JobLog) TableName() string {
+	return "job_logs"
+}
+
+// jobFinishedStatuses are the statuses of jobs that ran to an end
+var jobFinishedStatuses = []string{JobStatusCompleted, JobStatusFailed, JobStatusTimedOut, JobStatusCancelled}
+
+// JobPhaseStats aggregates the finished jobs of a phase. The durations, in
+// seconds, cover completed jobs and are nil without any.
+type JobPhaseStats struct {
+	Phase      string   `json:"phase"`
+	Completed  int64    `json:"completed"`
+	Failed     int64    `json:"failed"`
+	TimedOut   int64    `json:"timed_out"`
+	Cancelled  int64    `json:"cancelled"`
+	AvgSeconds *float64 `json:"avg_seconds"`
+	P50Seconds *float64 `json:"p50_seconds"`
+	P95Seconds *float64 `json:"p95_seconds"`
+	P99Seconds *float64 `json:"p99_seconds"`
+	MaxSeconds *float64 `json:"max_seconds"`
+}
+
+// JobTimelineBucket counts the outcomes of a phase's jobs finished in a time
+// bucket. Failed includes timed out jobs.
+type JobTimelineBucket struct {
+	Bucket    time.Time `json:"bucket"`
+	Phase     string    `json:"phase"`
+	Completed int64     `json:"completed"`
+	Failed    int64     `json:"failed"`
+	Cancelled int64     `json:"cancelled"`
+}
+
+// JobDuration is how long a completed job ran.
+type JobDuration struct {
+	JobID           string    `json:"job_id"`
+	SceneID         uint      `json:"scene_id"`
+	SceneTitle      string    `json:"scene_title"`
+	Phase           string    `json:"phase"`
+	StartedAt       time.Time `json:"started_at"`
+	CompletedAt     time.Time `json:"completed_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+}
+
+// JobHistoryArchive is a finished job moved out of job_history by retention.
+type JobHistoryArchive struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	JobID        string     `gorm:"uniqueIndex;not null;size:36" json:"job_id"`
+	SceneID      uint       `gorm:"not null" json:"scene_id"`
+	SceneTitle   string     `gorm:"not null;size:255" json:"scene_title"`
+	Phase        string     `gorm:"not null;size:20" json:"phase"`
+	Status       string     `gorm:"not null;size:20" json:"status"`
+	Priority     int        `gorm:"not null" json:"priority"`
+	ForceTarget  string     `gorm:"not null;size:20" json:"force_target"`
+	ErrorMessage *string    `gorm:"type:text" json:"error_message,omitempty"`
+	RetryCount   int        `gorm:"not null" json:"retry_count"`
+	StartedAt    time.Time  `gorm:"not null" json:"started_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"not null" json:"created_at"`
+	ArchivedAt   time.Time  `gorm:"not null;default:now()" json:"archived_at"`
+}
+
+func (JobHistoryArchive) TableName() string {
+	return "job_history_archive"
+}
+
+// JobFailureSummary counts the failed and timed out jobs of a phase that
+// ended with the same error, once retention pruned them.
+type JobFailureSummary struct {
+	ID            uint      `gorm:"primarykey" json:"-"`
+	Phase         string    `gorm:"not null;size:20" json:"phase"`
+	ErrorMessage  string    `gorm:"not null;size:500" json:"error_message"`
+	Failures      int64     `gorm:"not null" json:"failures"`
+	FirstFailedAt time.Time `gorm:"not null" json:"first_failed_at"`
+	LastFailedAt  time.Time `gorm:"not null" json:"last_failed_at"`
+	UpdatedAt     time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (JobFailureSummary) TableName() string {
+	return "job_failure_summaries"
+}
+
+// JobPruneResult counts the job_history rows a retention run reclaimed.
+// Deleted includes the archived rows; Summarized counts the failed and timed
+// out ones folded into failure summaries.
+type JobPruneResult struct {
+	Deleted    int64 `json:"deleted"`
+	Archived   int64 `json:"archived"`
+	Summarized int64 `json:"summarized"`
+}
+
 type DLQEntry struct {
 	ID            uint       `gorm:"primarykey" json:"id"`
 	JobID         string     `gorm:"uniqueIndex;not null;size:36" json:"job_id"`
@@ -57,14 +196,38 @@ func (DLQEntry) TableName() string {
 	return "dead_letter_queue"
 }
 
+// Backoff strategies of a retry policy
+const (
+	BackoffExponential = "exponential" // initial_delay * backoff_factor^retry
+	BackoffLinear      = "linear"      // initial_delay * (retry + 1)
+	BackoffFixed       = "fixed"       // initial_delay
+)
+
+// Error classes that retry policies match failed jobs' errors against
+const (
+	RetryErrorNotFound   = "not_found"
+	RetryErrorTimeout    = "timeout"
+	RetryErrorPermission = "permission"
+	RetryErrorCorrupt    = "corrupt"
+	RetryErrorResources  = "resources"
+)
+
+// RetryErrorClasses lists the error classes a retry policy can name
+var RetryErrorClasses = []string{RetryErrorNotFound, RetryErrorTimeout, RetryErrorPermission, RetryErrorCorrupt, RetryErrorResources}
+
 type RetryConfigRecord struct {
-	ID                  int       `gorm:"primaryKey" json:"id"`
-	Phase               string    `gorm:"uniqueIndex;not null;size:20" json:"phase"`
-	MaxRetries          int       `gorm:"not null;default:3" json:"max_retries"`
-	InitialDelaySeconds int       `gorm:"not null;default:30" json:"initial_delay_seconds"`
-	MaxDelaySeconds     int       `gorm:"not null;default:3600" json:"max_delay_seconds"`
-	BackoffFactor       float64   `gorm:"type:decimal(3,1);not null;default:2.0" json:"backoff_factor"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	ID                  int            `gorm:"primaryKey" json:"id"`
+	Phase               string         `gorm:"uniqueIndex;not null;size:20" json:"phase"`
+	MaxRetries          int            `gorm:"not null;default:3" json:"max_retries"`
+	InitialDelaySeconds int            `gorm:"not null;default:30" json:"initial_delay_seconds"`
+	MaxDelaySeconds     int            `gorm:"not null;default:3600" json:"max_delay_seconds"`
+	BackoffFactor       float64        `gorm:"type:decimal(3,1);not null;default:2.0" json:"backoff_factor"`
+	BackoffStrategy     string         `gorm:"not null;size:20;default:'exponential'" json:"backoff_strategy"`
+	JitterPercent       int            `gorm:"not null" json:"jitter_percent"`                               // random extra delay, up to this percent
+	NeverRetryErrors    pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"never_retry_errors"`  // error classes sent straight to the DLQ
+	AlwaysRetryErrors   pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"always_retry_errors"` // error classes retried even when retry_unclassified is off
+	RetryUnclassified   bool           `gorm:"not null" json:"retry_unclassified"`                           // retry errors of no listed class
+	UpdatedAt           time.Time      `json:"updated_at"`
 }
 
 func (RetryConfigRecord) TableName() string {
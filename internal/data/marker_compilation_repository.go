@@ -0,0 +1,126 @@
+package data
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// MarkerCompilation is an MP4 joined from the clips of a user's markers. JobID
+// links it to its job_history record; Status uses the job status values.
+type MarkerCompilation struct {
+	ID           uint          `gorm:"primarykey" json:"id"`
+	JobID        string        `gorm:"uniqueIndex;not null;size:36" json:"job_id"`
+	UserID       uint          `gorm:"not null" json:"user_id"`
+	Title        string        `gorm:"size:255;not null;default:''" json:"title"`
+	MarkerIDs    pq.Int64Array `gorm:"type:bigint[]" json:"marker_ids"`
+	ClipSeconds  int           `gorm:"not null" json:"clip_seconds"` // length of the clip taken from markers without an end
+	Status       string        `gorm:"size:20;not null;default:'pending'" json:"status"`
+	Progress     int           `gorm:"not null;default:0" json:"progress"`
+	ErrorMessage *string       `gorm:"type:text" json:"error_message,omitempty"`
+	FileSize     int64         `gorm:"not null;default:0" json:"file_size"`
+	Duration     int           `gorm:"not null;default:0" json:"duration"` // seconds
+	CreatedAt    time.Time     `json:"created_at"`
+	CompletedAt  *time.Time    `json:"completed_at,omitempty"`
+}
+
+func (MarkerCompilation) TableName() string {
+	return "marker_compilations"
+}
+
+type MarkerCompilationRepository interface {
+	Create(compilation *MarkerCompilation) error
+	GetByJobID(jobID string) (*MarkerCompilation, error)
+	ListByUser(userID uint) ([]MarkerCompilation, error)
+	UpdateStatus(jobID, status string, errorMessage *string) error
+	UpdateProgress(jobID string, progress int) error
+	Complete(jobID string, fileSize int64, duration int) error
+	FailInterrupted(message string) ([]string, error)
+	Delete(jobID string) error
+}
+
+type MarkerCompilationRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewMarkerCompilationRepository(db *gorm.DB) *MarkerCompilationRepositoryImpl {
+	return &MarkerCompilationRepositoryImpl{DB: db}
+}
+
+func (r *MarkerCompilationRepositoryImpl) Create(compilation *MarkerCompilation) error {
+	return r.DB.Create(compilation).Error
+}
+
+func (r *MarkerCompilationRepositoryImpl) GetByJobID(jobID string) (*MarkerCompilation, error) {
+	var compilation MarkerCompilation
+	if err := r.DB.Where("job_id = ?", jobID).First(&compilation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &compilation, nil
+}
+
+func (r *MarkerCompilationRepositoryImpl) ListByUser(userID uint) ([]MarkerCompilation, error) {
+	var compilations []MarkerCompilation
+	if err := r.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&compilations).Error; err != nil {
+		return nil, err
+	}
+	return compilations, nil
+}
+
+// UpdateStatus sets the status, and the completion time for final statuses.
+func (r *MarkerCompilationRepositoryImpl) UpdateStatus(jobID, status string, errorMessage *string) error {
+	updates := map[string]any{"status": status, "error_message": errorMessage}
+	if status != JobStatusPending && status != JobStatusRunning {
+		updates["completed_at"] = time.Now()
+	}
+	return r.DB.Model(&MarkerCompilation{}).Where("job_id = ?", jobID).Updates(updates).Error
+}
+
+func (r *MarkerCompilationRepositoryImpl) UpdateProgress(jobID string, progress int) error {
+	return r.DB.Model(&MarkerCompilation{}).Where("job_id = ?", jobID).Update("progress", progress).Error
+}
+
+func (r *MarkerCompilationRepositoryImpl) Complete(jobID string, fileSize int64, duration int) error {
+	return r.DB.Model(&MarkerCompilation{}).Where("job_id = ?", jobID).Updates(map[string]any{
+		"status":       JobStatusCompleted,
+		"progress":     100,
+		"file_size":    fileSize,
+		"duration":     duration,
+		"completed_at": time.Now(),
+	}).Error
+}
+
+// FailInterrupted fails the compilations a previous run left pending or running
+// and returns their job IDs.
+func (r *MarkerCompilationRepositoryImpl) FailInterrupted(message string) ([]string, error) {
+	var jobIDs []string
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&MarkerCompilation{}).
+			Where("status IN ?", []string{JobStatusPending, JobStatusRunning}).
+			Pluck("job_id", &jobIDs).Error; err != nil {
+			return err
+		}
+		if len(jobIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&MarkerCompilation{}).
+			Where("job_id IN ?", jobIDs).
+			Updates(map[string]any{
+				"status":        JobStatusFailed,
+				"error_message": message,
+				"completed_at":  time.Now(),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobIDs, nil
+}
+
+func (r *MarkerCompilationRepositoryImpl) Delete(jobID string) error {
+	return r.DB.Where("job_id = ?", jobID).Delete(&MarkerCompilation{}).Error
+}
@@ -9,6 +9,8 @@ type UserSceneMarker struct {
 	UserID        uint      `gorm:"not null" json:"user_id"`
 	SceneID       uint      `gorm:"not null;column:scene_id" json:"scene_id"`
 	Timestamp     int       `gorm:"not null" json:"timestamp"` // seconds
+	EndTimestamp  *int      `json:"end_timestamp"`              // seconds, set when the marker is a range
+	Visibility    string    `gorm:"size:20;not null;default:'private'" json:"visibility"`
 	Label         string    `gorm:"size:100" json:"label"`
 	Color         string    `gorm:"size:7;default:'#FFFFFF'" json:"color"`
 	ThumbnailPath         string    `gorm:"size:255" json:"thumbnail_path"`
@@ -21,6 +23,18 @@ func (UserSceneMarker) TableName() string {
 	return "user_scene_markers"
 }
 
+// Marker visibilities: shared markers are listed to other users of the scene
+const (
+	MarkerVisibilityPrivate = "private"
+	MarkerVisibilityShared  = "shared"
+)
+
+// SharedMarker is another user's shared marker with its owner's name
+type SharedMarker struct {
+	UserSceneMarker
+	Username string `json:"username"`
+}
+
 type MarkerLabelSuggestion struct {
 	Label string `json:"label"`
 	Count int64  `json:"count"`
@@ -41,6 +55,24 @@ type MarkerWithScene struct {
 	Tags       []MarkerTagInfo `json:"tags,omitempty" gorm:"-"`
 }
 
+// MarkerTagCollection is the virtual collection of all of a user's markers carrying one tag
+type MarkerTagCollection struct {
+	TagID             uint    `json:"tag_id"`
+	TagName           string  `json:"tag_name"`
+	TagColor          string  `json:"tag_color"`
+	MarkerCount       int64   `json:"marker_count"`
+	SceneCount        int64   `json:"scene_count"`
+	Density           float64 `json:"density"` // markers per hour of the scenes they are on
+	ThumbnailMarkerID uint    `json:"thumbnail_marker_id"`
+}
+
+// CollectionMarker is a marker of a tag collection with the collection's density in its scene
+type CollectionMarker struct {
+	MarkerWithScene
+	SceneDuration int     `json:"scene_duration"`
+	Density       float64 `json:"density"` // collection markers per hour of the scene
+}
+
 // MarkerWithTags extends UserSceneMarker with tags
 type MarkerWithTags struct {
 	UserSceneMarker
@@ -60,6 +92,22 @@ func (MarkerLabelTag) TableName() string {
 	return "marker_label_tags"
 }
 
+
+// MarkerLabelSetting holds a user's defaults for markers with a label
+type MarkerLabelSetting struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	UserID       uint      `gorm:"not null" json:"user_id"`
+	Label        string    `gorm:"size:100;not null" json:"label"`
+	Color        string    `gorm:"size:7;not null" json:"color"`  // empty keeps the default color
+	Icon         string    `gorm:"size:50;not null" json:"icon"`  // icon name shown with the label
+	ClipDuration int       `gorm:"not null" json:"clip_duration"` // animated thumbnail seconds, 0 uses the configured length
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (MarkerLabelSetting) TableName() string {
+	return "marker_label_settings"
+}
 // MarkerTag represents a tag on an individual marker
 type MarkerTag struct {
 	ID          uint      `gorm:"primarykey" json:"id"`
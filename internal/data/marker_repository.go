@@ -8,7 +8,10 @@ import (
 type MarkerRepository interface {
 	Create(marker *UserSceneMarker) error
 	GetByID(id uint) (*UserSceneMarker, error)
+	GetByIDs(ids []uint) ([]UserSceneMarker, error)
 	GetByUserAndScene(userID, sceneID uint) ([]UserSceneMarker, error)
+	GetByUserAndScenes(userID uint, sceneIDs []uint) (map[uint][]UserSceneMarker, error)
+	GetSharedByScene(sceneID, excludeUserID uint) ([]SharedMarker, error)
 	CountByUserAndScene(userID, sceneID uint) (int64, error)
 	Update(marker *UserSceneMarker) error
 	Delete(id uint) error
@@ -21,6 +24,12 @@ type MarkerRepository interface {
 	SetLabelTags(userID uint, label string, tagIDs []uint) error
 	GetAllLabelTagsForUser(userID uint) (map[string][]Tag, error)
 
+	// Label setting methods
+	GetLabelSettings(userID uint) ([]MarkerLabelSetting, error)
+	GetLabelSetting(userID uint, label string) (*MarkerLabelSetting, error)
+	UpsertLabelSetting(setting *MarkerLabelSetting) error
+	DeleteLabelSetting(userID uint, label string) error
+
 	// Individual marker tag methods
 	GetMarkerTags(markerID uint) ([]MarkerTagInfo, error)
 	GetMarkerTagsMultiple(markerIDs []uint) (map[uint][]MarkerTagInfo, error)
@@ -30,6 +39,10 @@ type MarkerRepository interface {
 	ApplyLabelTagsToMarker(userID uint, markerID uint, label string) error
 	GetMarkerIDsByLabel(userID uint, label string) ([]uint, error)
 
+	// Tag collection methods
+	GetTagCollectionsForUser(userID uint, offset, limit int, sortBy string) ([]MarkerTagCollection, int64, error)
+	GetCollectionMarkersForUser(userID, tagID uint, offset, limit int, sortBy string) ([]CollectionMarker, int64, error)
+
 	// Thumbnail methods
 	GetRandomThumbnailsForLabels(userID uint, labels []string, perLabel int) (map[string][]uint, error)
 
@@ -37,12 +50,20 @@ type MarkerRepository interface {
 	GetBySceneWithoutThumbnail(sceneID uint) ([]UserSceneMarker, error)
 	GetBySceneWithoutAnimatedThumbnail(sceneID uint) ([]UserSceneMarker, error)
 	GetAllByScene(sceneID uint) ([]UserSceneMarker, error)
+	CountBySceneIDs(sceneIDs []uint) (map[uint]int64, error)
 
 	// All markers (unwrapped view)
 	GetAllMarkersForUser(userID uint, offset, limit int, sortBy string) ([]MarkerWithScene, int64, error)
+	GetAllMarkersForUserAfter(userID uint, cursor *Cursor, limit int, sortBy string) ([]MarkerWithScene, *Cursor, error)
 
 	// Search filter methods
 	GetSceneIDsByLabels(userID uint, labels []string) ([]uint, error)
+	GetMarkedSceneIDs(userID uint) ([]uint, error)
+	GetAllMarkedSceneIDs() ([]uint, error)
+
+	// Segment search methods
+	SearchLabels(userID uint, query string, limit int) ([]UserSceneMarker, error)
+	GetLabeledBySceneIDs(sceneIDs []uint) ([]UserSceneMarker, error)
 }
 
 type MarkerRepositoryImpl struct {
@@ -65,6 +86,18 @@ func (r *MarkerRepositoryImpl) GetByID(id uint) (*UserSceneMarker, error) {
 	return &marker, nil
 }
 
+// GetByIDs returns the markers with the given IDs; missing IDs are left out
+func (r *MarkerRepositoryImpl) GetByIDs(ids []uint) ([]UserSceneMarker, error) {
+	var markers []UserSceneMarker
+	if len(ids) == 0 {
+		return markers, nil
+	}
+	if err := r.DB.Where("id IN ?", ids).Find(&markers).Error; err != nil {
+		return nil, err
+	}
+	return markers, nil
+}
+
 func (r *MarkerRepositoryImpl) GetByUserAndScene(userID, sceneID uint) ([]UserSceneMarker, error) {
 	var markers []UserSceneMarker
 	err := r.DB.Where("user_id = ? AND scene_id = ?", userID, sceneID).
@@ -76,6 +109,42 @@ func (r *MarkerRepositoryImpl) GetByUserAndScene(userID, sceneID uint) ([]UserSc
 	return markers, nil
 }
 
+// GetByUserAndScenes returns the user's markers of several scenes, keyed by scene ID
+func (r *MarkerRepositoryImpl) GetByUserAndScenes(userID uint, sceneIDs []uint) (map[uint][]UserSceneMarker, error) {
+	result := make(map[uint][]UserSceneMarker)
+	if len(sceneIDs) == 0 {
+		return result, nil
+	}
+
+	var markers []UserSceneMarker
+	err := r.DB.Where("user_id = ? AND scene_id IN ?", userID, sceneIDs).
+		Order("timestamp ASC, id ASC").
+		Find(&markers).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range markers {
+		result[m.SceneID] = append(result[m.SceneID], m)
+	}
+	return result, nil
+}
+
+// GetSharedByScene returns the shared markers on a scene, leaving out excludeUserID's own
+func (r *MarkerRepositoryImpl) GetSharedByScene(sceneID, excludeUserID uint) ([]SharedMarker, error) {
+	var markers []SharedMarker
+	err := r.DB.Table("user_scene_markers").
+		Select("user_scene_markers.*, users.username").
+		Joins("JOIN users ON users.id = user_scene_markers.user_id").
+		Where("user_scene_markers.scene_id = ? AND user_scene_markers.visibility = ? AND user_scene_markers.user_id <> ?",
+			sceneID, MarkerVisibilityShared, excludeUserID).
+		Order("user_scene_markers.timestamp ASC, user_scene_markers.id ASC").
+		Scan(&markers).Error
+	if err != nil {
+		return nil, err
+	}
+	return markers, nil
+}
+
 func (r *MarkerRepositoryImpl) CountByUserAndScene(userID, sceneID uint) (int64, error) {
 	var count int64
 	err := r.DB.Model(&UserSceneMarker{}).
@@ -226,6 +295,174 @@ func (r *MarkerRepositoryImpl) GetAllMarkersForUser(userID uint, offset, limit i
 	return markers, totalCount, nil
 }
 
+// allMarkersKeysets are the keyset orderings of the all-markers sorts. Ties
+// are broken by ID in the sort's direction rather than by creation time.
+var allMarkersKeysets = map[string]struct {
+	column string
+	desc   bool
+}{
+	"label_asc":  {"m.label", false},
+	"label_desc": {"m.label", true},
+	"recent":     {"m.created_at", true},
+	"oldest":     {"m.created_at", false},
+}
+
+// GetAllMarkersForUserAfter is GetAllMarkersForUser paged by cursor. The
+// returned cursor is nil on the last page.
+func (r *MarkerRepositoryImpl) GetAllMarkersForUserAfter(userID uint, cursor *Cursor, limit int, sortBy string) ([]MarkerWithScene, *Cursor, error) {
+	keyset, ok := allMarkersKeysets[sortBy]
+	if !ok {
+		sortBy = "label_asc"
+		keyset = allMarkersKeysets[sortBy]
+	}
+	direction := "ASC"
+	if keyset.desc {
+		direction = "DESC"
+	}
+
+	where := "m.user_id = ?"
+	args := []any{userID}
+	if cursor != nil {
+		var value any = cursor.Value
+		if keyset.column == "m.created_at" {
+			createdAt, err := cursor.TimeValue()
+			if err != nil {
+				return nil, nil, err
+			}
+			value = createdAt
+		}
+		where += " AND " + keysetAfter(keyset.column, "m.id", keyset.desc)
+		args = append(args, value, cursor.ID)
+	}
+	args = append(args, limit+1)
+
+	var markers []MarkerWithScene
+	err := r.DB.Raw(`
+		SELECT m.*, s.title as scene_title
+		FROM user_scene_markers m
+		JOIN scenes s ON m.scene_id = s.id
+		WHERE `+where+`
+		ORDER BY `+keyset.column+` `+direction+`, m.id `+direction+`
+		LIMIT ?
+	`, args...).Scan(&markers).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	markers, more := trimPage(markers, limit)
+	if !more {
+		return markers, nil, nil
+	}
+	last := markers[len(markers)-1]
+	var value any = last.Label
+	if keyset.column == "m.created_at" {
+		value = last.CreatedAt
+	}
+	return markers, NewCursor(sortBy, value, last.ID), nil
+}
+
+// tagCollectionSortMap maps sort parameter values to safe SQL ORDER BY clauses for tag collections
+var tagCollectionSortMap = map[string]string{
+	"density": "density DESC, marker_count DESC, tag_name ASC",
+	"count":   "marker_count DESC, tag_name ASC",
+	"name":    "tag_name ASC",
+}
+
+// GetTagCollectionsForUser returns one collection per tag on the user's markers
+func (r *MarkerRepositoryImpl) GetTagCollectionsForUser(userID uint, offset, limit int, sortBy string) ([]MarkerTagCollection, int64, error) {
+	var totalCount int64
+	err := r.DB.Raw(`
+		SELECT COUNT(DISTINCT mt.tag_id)
+		FROM marker_tags mt
+		JOIN user_scene_markers m ON m.id = mt.marker_id
+		JOIN scenes s ON s.id = m.scene_id AND s.deleted_at IS NULL AND s.trashed_at IS NULL
+		WHERE m.user_id = ?
+	`, userID).Scan(&totalCount).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderClause, ok := tagCollectionSortMap[sortBy]
+	if !ok {
+		orderClause = tagCollectionSortMap["density"]
+	}
+
+	var collections []MarkerTagCollection
+	err = r.DB.Raw(`
+		WITH tagged AS (
+			SELECT mt.tag_id, m.id, m.scene_id, s.duration
+			FROM marker_tags mt
+			JOIN user_scene_markers m ON m.id = mt.marker_id
+			JOIN scenes s ON s.id = m.scene_id AND s.deleted_at IS NULL AND s.trashed_at IS NULL
+			WHERE m.user_id = ?
+		), per_scene AS (
+			SELECT tag_id, scene_id, COUNT(*) AS markers, MAX(duration) AS duration
+			FROM tagged
+			GROUP BY tag_id, scene_id
+		)
+		SELECT ps.tag_id, t.name AS tag_name, t.color AS tag_color,
+			SUM(ps.markers) AS marker_count,
+			COUNT(*) AS scene_count,
+			SUM(ps.markers) * 3600.0 / GREATEST(SUM(ps.duration), 1) AS density,
+			(SELECT MAX(tg.id) FROM tagged tg WHERE tg.tag_id = ps.tag_id) AS thumbnail_marker_id
+		FROM per_scene ps
+		JOIN tags t ON t.id = ps.tag_id
+		GROUP BY ps.tag_id, t.name, t.color
+		ORDER BY `+orderClause+`
+		LIMIT ? OFFSET ?
+	`, userID, limit, offset).Scan(&collections).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return collections, totalCount, nil
+}
+
+// collectionMarkersSortMap maps sort parameter values to safe SQL ORDER BY clauses for collection markers
+var collectionMarkersSortMap = map[string]string{
+	"density": "density DESC, m.scene_id ASC, m.timestamp ASC",
+	"scene":   "s.title ASC, m.scene_id ASC, m.timestamp ASC",
+	"recent":  "m.created_at DESC",
+}
+
+// GetCollectionMarkersForUser returns the user's markers carrying a tag with scene info.
+// Density is the number of such markers per hour of the marker's scene.
+func (r *MarkerRepositoryImpl) GetCollectionMarkersForUser(userID, tagID uint, offset, limit int, sortBy string) ([]CollectionMarker, int64, error) {
+	var totalCount int64
+	err := r.DB.Raw(`
+		SELECT COUNT(*)
+		FROM user_scene_markers m
+		JOIN marker_tags mt ON mt.marker_id = m.id AND mt.tag_id = ?
+		JOIN scenes s ON s.id = m.scene_id AND s.deleted_at IS NULL AND s.trashed_at IS NULL
+		WHERE m.user_id = ?
+	`, tagID, userID).Scan(&totalCount).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderClause, ok := collectionMarkersSortMap[sortBy]
+	if !ok {
+		orderClause = collectionMarkersSortMap["density"]
+	}
+
+	var markers []CollectionMarker
+	err = r.DB.Raw(`
+		SELECT m.*, s.title AS scene_title, s.duration AS scene_duration,
+			COUNT(*) OVER (PARTITION BY m.scene_id) * 3600.0 / GREATEST(s.duration, 1) AS density
+		FROM user_scene_markers m
+		JOIN marker_tags mt ON mt.marker_id = m.id AND mt.tag_id = ?
+		JOIN scenes s ON s.id = m.scene_id AND s.deleted_at IS NULL AND s.trashed_at IS NULL
+		WHERE m.user_id = ?
+		ORDER BY `+orderClause+`
+		LIMIT ? OFFSET ?
+	`, tagID, userID, limit, offset).Scan(&markers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return markers, totalCount, nil
+}
+
 // GetLabelTags returns the default tags for a label
 func (r *MarkerRepositoryImpl) GetLabelTags(userID uint, label string) ([]Tag, error) {
 	var tags []Tag
@@ -330,6 +567,44 @@ func (r *MarkerRepositoryImpl) GetAllLabelTagsForUser(userID uint) (map[string][
 	return tagsByLabel, nil
 }
 
+// GetLabelSettings returns all of a user's label settings ordered by label
+func (r *MarkerRepositoryImpl) GetLabelSettings(userID uint) ([]MarkerLabelSetting, error) {
+	var settings []MarkerLabelSetting
+	err := r.DB.Where("user_id = ?", userID).Order("label ASC").Find(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *MarkerRepositoryImpl) GetLabelSetting(userID uint, label string) (*MarkerLabelSetting, error) {
+	var setting MarkerLabelSetting
+	if err := r.DB.Where("user_id = ? AND label = ?", userID, label).First(&setting).Error; err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// UpsertLabelSetting creates the setting or replaces the defaults of an existing one
+func (r *MarkerRepositoryImpl) UpsertLabelSetting(setting *MarkerLabelSetting) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "label"}},
+		DoUpdates: clause.AssignmentColumns([]string{"color", "icon", "clip_duration", "updated_at"}),
+	}).Create(setting).Error
+}
+
+// DeleteLabelSetting removes a label setting, returning gorm.ErrRecordNotFound if there was none
+func (r *MarkerRepositoryImpl) DeleteLabelSetting(userID uint, label string) error {
+	result := r.DB.Where("user_id = ? AND label = ?", userID, label).Delete(&MarkerLabelSetting{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 // GetMarkerTags returns tags for a specific marker
 func (r *MarkerRepositoryImpl) GetMarkerTags(markerID uint) ([]MarkerTagInfo, error) {
 	var tags []MarkerTagInfo
@@ -580,6 +855,33 @@ func (r *MarkerRepositoryImpl) GetAllByScene(sceneID uint) ([]UserSceneMarker, e
 	return markers, nil
 }
 
+// CountBySceneIDs returns the number of markers (across all users) for each scene.
+// Scenes without markers are absent from the map.
+func (r *MarkerRepositoryImpl) CountBySceneIDs(sceneIDs []uint) (map[uint]int64, error) {
+	if len(sceneIDs) == 0 {
+		return make(map[uint]int64), nil
+	}
+
+	var rows []struct {
+		SceneID uint
+		Count   int64
+	}
+	err := r.DB.Model(&UserSceneMarker{}).
+		Select("scene_id, COUNT(*) as count").
+		Where("scene_id IN ?", sceneIDs).
+		Group("scene_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		result[row.SceneID] = row.Count
+	}
+	return result, nil
+}
+
 // GetSceneIDsByLabels returns distinct scene IDs that have markers with any of the given labels for a user
 func (r *MarkerRepositoryImpl) GetSceneIDsByLabels(userID uint, labels []string) ([]uint, error) {
 	if len(labels) == 0 {
@@ -597,5 +899,72 @@ func (r *MarkerRepositoryImpl) GetSceneIDsByLabels(userID uint, labels []string)
 	return sceneIDs, nil
 }
 
+// GetMarkedSceneIDs returns distinct scene IDs the user has markers on
+func (r *MarkerRepositoryImpl) GetMarkedSceneIDs(userID uint) ([]uint, error) {
+	var sceneIDs []uint
+	err := r.DB.Model(&UserSceneMarker{}).
+		Select("DISTINCT scene_id").
+		Where("user_id = ?", userID).
+		Pluck("scene_id", &sceneIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return sceneIDs, nil
+}
+
+// GetAllMarkedSceneIDs returns distinct scene IDs any user has markers on
+func (r *MarkerRepositoryImpl) GetAllMarkedSceneIDs() ([]uint, error) {
+	var sceneIDs []uint
+	err := r.DB.Model(&UserSceneMarker{}).
+		Select("DISTINCT scene_id").
+		Pluck("scene_id", &sceneIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return sceneIDs, nil
+}
+
+// SearchLabels returns a user's markers whose label matches every query word as a
+// prefix, best match first. Markers of trashed scenes are excluded.
+func (r *MarkerRepositoryImpl) SearchLabels(userID uint, query string, limit int) ([]UserSceneMarker, error) {
+	tsQuery := prefixTSQuery(query)
+	if tsQuery == "" {
+		return []UserSceneMarker{}, nil
+	}
+
+	var markers []UserSceneMarker
+	err := r.DB.Model(&UserSceneMarker{}).
+		Joins("JOIN scenes ON scenes.id = user_scene_markers.scene_id AND scenes.deleted_at IS NULL AND scenes.trashed_at IS NULL").
+		Where("user_scene_markers.user_id = ?", userID).
+		Where("to_tsvector('simple', user_scene_markers.label) @@ to_tsquery('simple', ?)", tsQuery).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank(to_tsvector('simple', user_scene_markers.label), to_tsquery('simple', ?)) DESC, user_scene_markers.scene_id DESC, user_scene_markers.timestamp ASC",
+			Vars:               []interface{}{tsQuery},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Find(&markers).Error
+	if err != nil {
+		return nil, err
+	}
+	return markers, nil
+}
+
+// GetLabeledBySceneIDs returns the markers with a label on the given scenes, across all users
+func (r *MarkerRepositoryImpl) GetLabeledBySceneIDs(sceneIDs []uint) ([]UserSceneMarker, error) {
+	if len(sceneIDs) == 0 {
+		return []UserSceneMarker{}, nil
+	}
+
+	var markers []UserSceneMarker
+	err := r.DB.Where("scene_id IN ? AND label <> ''", sceneIDs).
+		Order("id ASC").
+		Find(&markers).Error
+	if err != nil {
+		return nil, err
+	}
+	return markers, nil
+}
+
 // Ensure MarkerRepositoryImpl implements MarkerRepository
 var _ MarkerRepository = (*MarkerRepositoryImpl)(nil)
@@ -0,0 +1,20 @@
+package data
+
+import "time"
+
+// UserOfflineScene is a scene a user keeps available offline. Checksum is the
+// metadata checksum last sent in a sync manifest and ChangedAt when it last
+// changed; RemovedAt marks a tombstone kept for delta manifests.
+type UserOfflineScene struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null" json:"user_id"`
+	SceneID   uint       `gorm:"not null;column:scene_id" json:"scene_id"`
+	Checksum  string     `gorm:"size:64;not null;default:''" json:"checksum"`
+	ChangedAt time.Time  `gorm:"not null;default:now()" json:"changed_at"`
+	RemovedAt *time.Time `json:"removed_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (UserOfflineScene) TableName() string {
+	return "user_offline_scenes"
+}
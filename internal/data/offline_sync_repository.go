@@ -0,0 +1,101 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OfflineSyncRepository interface {
+	// AddScenes selects scenes for offline sync, reviving tombstones
+	AddScenes(userID uint, sceneIDs []uint) error
+	// RemoveScenes turns the user's selected scenes into tombstones
+	RemoveScenes(userID uint, sceneIDs []uint) (int64, error)
+	// ListByUser returns the user's selected scenes and tombstones
+	ListByUser(userID uint) ([]UserOfflineScene, error)
+	CountActive(userID uint) (int64, error)
+	UpdateChecksum(id uint, checksum string, changedAt time.Time) error
+	// DeleteTombstonesBefore drops tombstones removed before the cutoff
+	DeleteTombstonesBefore(userID uint, cutoff time.Time) error
+}
+
+type OfflineSyncRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewOfflineSyncRepository(db *gorm.DB) *OfflineSyncRepositoryImpl {
+	return &OfflineSyncRepositoryImpl{DB: db}
+}
+
+func (r *OfflineSyncRepositoryImpl) AddScenes(userID uint, sceneIDs []uint) error {
+	if len(sceneIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	records := make([]UserOfflineScene, len(sceneIDs))
+	for i, sceneID := range sceneIDs {
+		records[i] = UserOfflineScene{
+			UserID:    userID,
+			SceneID:   sceneID,
+			ChangedAt: now,
+		}
+	}
+
+	// Already selected scenes are left alone; tombstones come back to life
+	return r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "scene_id"}},
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "user_offline_scenes.removed_at IS NOT NULL"},
+		}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"removed_at": nil,
+			"checksum":   "",
+			"changed_at": now,
+		}),
+	}).Create(&records).Error
+}
+
+func (r *OfflineSyncRepositoryImpl) RemoveScenes(userID uint, sceneIDs []uint) (int64, error) {
+	if len(sceneIDs) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now().UTC()
+	result := r.DB.Model(&UserOfflineScene{}).
+		Where("user_id = ? AND scene_id IN ? AND removed_at IS NULL", userID, sceneIDs).
+		Updates(map[string]any{"removed_at": now, "changed_at": now})
+	return result.RowsAffected, result.Error
+}
+
+func (r *OfflineSyncRepositoryImpl) ListByUser(userID uint) ([]UserOfflineScene, error) {
+	var scenes []UserOfflineScene
+	if err := r.DB.Where("user_id = ?", userID).Order("created_at ASC, id ASC").Find(&scenes).Error; err != nil {
+		return nil, err
+	}
+	return scenes, nil
+}
+
+func (r *OfflineSyncRepositoryImpl) CountActive(userID uint) (int64, error) {
+	var count int64
+	err := r.DB.Model(&UserOfflineScene{}).
+		Where("user_id = ? AND removed_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *OfflineSyncRepositoryImpl) UpdateChecksum(id uint, checksum string, changedAt time.Time) error {
+	return r.DB.Model(&UserOfflineScene{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"checksum": checksum, "changed_at": changedAt}).Error
+}
+
+func (r *OfflineSyncRepositoryImpl) DeleteTombstonesBefore(userID uint, cutoff time.Time) error {
+	return r.DB.
+		Where("user_id = ? AND removed_at IS NOT NULL AND removed_at < ?", userID, cutoff).
+		Delete(&UserOfflineScene{}).Error
+}
+
+// Ensure OfflineSyncRepositoryImpl implements OfflineSyncRepository
+var _ OfflineSyncRepository = (*OfflineSyncRepositoryImpl)(nil)
@@ -36,4 +36,5 @@ type PlaylistRepository interface {
 	GetSceneCount(playlistID uint) (int64, error)
 	GetTotalDuration(playlistID uint) (int64, error)
 	GetThumbnailScenes(playlistID uint, limit int) ([]Scene, error)
+	CountPlaylistsBySceneIDs(sceneIDs []uint) (map[uint]int64, error)
 }
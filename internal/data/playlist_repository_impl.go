@@ -403,3 +403,30 @@ func (r *PlaylistRepositoryImpl) GetThumbnailScenes(playlistID uint, limit int)
 	}
 	return scenes, nil
 }
+
+// CountPlaylistsBySceneIDs returns how many playlists contain each scene.
+// Scenes in no playlist are absent from the map.
+func (r *PlaylistRepositoryImpl) CountPlaylistsBySceneIDs(sceneIDs []uint) (map[uint]int64, error) {
+	if len(sceneIDs) == 0 {
+		return make(map[uint]int64), nil
+	}
+
+	var rows []struct {
+		SceneID uint
+		Count   int64
+	}
+	err := r.DB.Model(&PlaylistScene{}).
+		Select("scene_id, COUNT(DISTINCT playlist_id) as count").
+		Where("scene_id IN ?", sceneIDs).
+		Group("scene_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		result[row.SceneID] = row.Count
+	}
+	return result, nil
+}
@@ -13,6 +13,8 @@ type PoolConfigRecord struct {
 	ThumbnailWorkers          int       `gorm:"column:thumbnail_workers" json:"thumbnail_workers"`
 	SpritesWorkers            int       `gorm:"column:sprites_workers" json:"sprites_workers"`
 	AnimatedThumbnailsWorkers int       `gorm:"column:animated_thumbnails_workers" json:"animated_thumbnails_workers"`
+	VerifyWorkers             int       `gorm:"column:verify_workers" json:"verify_workers"`
+	ChecksumWorkers           int       `gorm:"column:checksum_workers" json:"checksum_workers"`
 	UpdatedAt                 time.Time `gorm:"column:updated_at" json:"updated_at"`
 }
 
@@ -50,6 +52,6 @@ func (r *PoolConfigRepositoryImpl) Upsert(record *PoolConfigRecord) error {
 	record.UpdatedAt = time.Now()
 	return r.DB.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"metadata_workers", "thumbnail_workers", "sprites_workers", "animated_thumbnails_workers", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"metadata_workers", "thumbnail_workers", "sprites_workers", "animated_thumbnails_workers", "verify_workers", "checksum_workers", "updated_at"}),
 	}).Create(record).Error
 }
@@ -0,0 +1,33 @@
+package data
+
+import "time"
+
+// PornDB cache entry kinds
+const (
+	PornDBCachePerformer = "performer"
+	PornDBCacheScene     = "scene"
+	PornDBCacheSite      = "site"
+)
+
+// PornDBCacheEntry is a cached PornDB API result. Body is the result as
+// returned by PornDBService, so a hit needs no further conversion.
+type PornDBCacheEntry struct {
+	Key       string    `gorm:"primaryKey;size:512" json:"key"` // kind, endpoint and normalized query or ID
+	Kind      string    `gorm:"size:20;not null" json:"kind"`
+	Body      []byte    `gorm:"type:jsonb;not null" json:"-"`
+	HitCount  int       `gorm:"not null;default:0" json:"hit_count"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (PornDBCacheEntry) TableName() string {
+	return "porndb_cache"
+}
+
+// PornDBCacheKindStats counts the cached entries of one kind.
+type PornDBCacheKindStats struct {
+	Kind    string `json:"kind"`
+	Entries int64  `json:"entries"`
+	Expired int64  `json:"expired"`
+	Hits    int64  `json:"hits"` // total hits of the entries still cached
+}
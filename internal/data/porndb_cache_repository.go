@@ -0,0 +1,71 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PornDBCacheRepository interface {
+	// Get returns the unexpired entry for key, or nil when there is none
+	Get(key string, now time.Time) (*PornDBCacheEntry, error)
+	// Set creates or replaces the entry for entry.Key
+	Set(entry *PornDBCacheEntry) error
+	RecordHit(key string) error
+	Stats(now time.Time) ([]PornDBCacheKindStats, error)
+	// Purge deletes the entries of kind, or of every kind when kind is empty.
+	// With expiredOnly, only entries expired at now are deleted.
+	Purge(kind string, expiredOnly bool, now time.Time) (int64, error)
+}
+
+type PornDBCacheRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewPornDBCacheRepository(db *gorm.DB) *PornDBCacheRepositoryImpl {
+	return &PornDBCacheRepositoryImpl{DB: db}
+}
+
+func (r *PornDBCacheRepositoryImpl) Get(key string, now time.Time) (*PornDBCacheEntry, error) {
+	var entries []PornDBCacheEntry
+	err := r.DB.Where("key = ? AND expires_at > ?", key, now).Limit(1).Find(&entries).Error
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+func (r *PornDBCacheRepositoryImpl) Set(entry *PornDBCacheEntry) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]any{"kind": entry.Kind, "body": entry.Body, "hit_count": 0, "expires_at": entry.ExpiresAt, "created_at": gorm.Expr("NOW()")}),
+	}).Create(entry).Error
+}
+
+func (r *PornDBCacheRepositoryImpl) RecordHit(key string) error {
+	return r.DB.Model(&PornDBCacheEntry{}).Where("key = ?", key).
+		UpdateColumn("hit_count", gorm.Expr("hit_count + 1")).Error
+}
+
+func (r *PornDBCacheRepositoryImpl) Stats(now time.Time) ([]PornDBCacheKindStats, error) {
+	var stats []PornDBCacheKindStats
+	err := r.DB.Model(&PornDBCacheEntry{}).
+		Select("kind, COUNT(*) AS entries, COUNT(*) FILTER (WHERE expires_at <= ?) AS expired, COALESCE(SUM(hit_count), 0) AS hits", now).
+		Group("kind").
+		Order("kind").
+		Scan(&stats).Error
+	return stats, err
+}
+
+func (r *PornDBCacheRepositoryImpl) Purge(kind string, expiredOnly bool, now time.Time) (int64, error) {
+	query := r.DB.Where("1 = 1")
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if expiredOnly {
+		query = query.Where("expires_at <= ?", now)
+	}
+	result := query.Delete(&PornDBCacheEntry{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,78 @@
+package data
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// PornDB match review statuses
+const (
+	PornDBMatchPending   = "pending"   // ambiguous, waiting for an admin to pick a candidate
+	PornDBMatchApplied   = "applied"   // a match was applied, automatically or by an admin
+	PornDBMatchDismissed = "dismissed" // an admin rejected every candidate
+	PornDBMatchNoMatch   = "no_match"  // the provider returned nothing for the scene
+)
+
+// PornDBMatchReview records what the auto-match job decided for a scene.
+// Scenes with a review are skipped by later runs. Candidate and applied scene
+// IDs are IDs at Provider, PornDB unless the run chose StashDB.
+type PornDBMatchReview struct {
+	ID            uint                  `gorm:"primarykey" json:"id"`
+	SceneID       uint                  `gorm:"not null;uniqueIndex" json:"scene_id"`
+	JobID         string                `gorm:"size:36;not null;default:''" json:"job_id"`
+	Provider      string                `gorm:"size:20;not null;default:'porndb'" json:"provider"`
+	Status        string                `gorm:"size:20;not null;default:'pending'" json:"status"`
+	Query         string                `gorm:"type:text;not null;default:''" json:"query"` // what PornDB was searched for
+	Candidates    PornDBMatchCandidates `gorm:"type:jsonb;not null;default:'[]'" json:"candidates"`
+	BestScore     float64               `gorm:"not null;default:0" json:"best_score"`
+	PornDBSceneID string                `gorm:"column:porndb_scene_id;size:255;not null;default:''" json:"porndb_scene_id"` // the applied match
+	ReviewedBy    *uint                 `json:"reviewed_by"`
+	ReviewedAt    *time.Time            `json:"reviewed_at"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+func (PornDBMatchReview) TableName() string {
+	return "porndb_match_reviews"
+}
+
+// PornDBMatchCandidate is a PornDB scene the auto-match job considered, with
+// the confidence score it gave it.
+type PornDBMatchCandidate struct {
+	PornDBSceneID string   `json:"porndb_scene_id"`
+	Title         string   `json:"title"`
+	Site          string   `json:"site,omitempty"`
+	Date          string   `json:"date,omitempty"`
+	Duration      int      `json:"duration,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	Performers    []string `json:"performers,omitempty"`
+	Score         float64  `json:"score"`
+}
+
+// PornDBMatchCandidates is a JSONB-backed list of candidates, best first
+type PornDBMatchCandidates []PornDBMatchCandidate
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (c PornDBMatchCandidates) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval
+func (c *PornDBMatchCandidates) Scan(value any) error {
+	if value == nil {
+		*c = PornDBMatchCandidates{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan PornDBMatchCandidates: expected []byte")
+	}
+
+	return json.Unmarshal(bytes, c)
+}
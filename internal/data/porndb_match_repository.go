@@ -0,0 +1,97 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PornDBMatchRepository interface {
+	// ListUnmatchedScenes returns live scenes after afterID, by ID, that have no
+	// PornDB or StashDB scene ID and no review other than no_match
+	ListUnmatchedScenes(afterID uint, limit int) ([]Scene, error)
+	CountUnmatchedScenes() (int64, error)
+	// Upsert creates or replaces the review of review.SceneID
+	Upsert(review *PornDBMatchReview) error
+	GetBySceneID(sceneID uint) (*PornDBMatchReview, error)
+	List(status string, page, limit int) ([]PornDBMatchReview, int64, error)
+	Update(review *PornDBMatchReview) error
+}
+
+type PornDBMatchRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewPornDBMatchRepository(db *gorm.DB) *PornDBMatchRepositoryImpl {
+	return &PornDBMatchRepositoryImpl{DB: db}
+}
+
+func (r *PornDBMatchRepositoryImpl) unmatchedScenes() *gorm.DB {
+	return r.DB.Model(&Scene{}).
+		Where("(porndb_scene_id IS NULL OR porndb_scene_id = '') AND stashdb_scene_id = '' AND trashed_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM porndb_match_reviews r WHERE r.scene_id = scenes.id AND r.status <> ?)", PornDBMatchNoMatch)
+}
+
+func (r *PornDBMatchRepositoryImpl) ListUnmatchedScenes(afterID uint, limit int) ([]Scene, error) {
+	var scenes []Scene
+	err := r.unmatchedScenes().
+		Where("scenes.id > ?", afterID).
+		Order("scenes.id ASC").
+		Limit(limit).
+		Find(&scenes).Error
+	return scenes, err
+}
+
+func (r *PornDBMatchRepositoryImpl) CountUnmatchedScenes() (int64, error) {
+	var count int64
+	err := r.unmatchedScenes().Count(&count).Error
+	return count, err
+}
+
+func (r *PornDBMatchRepositoryImpl) Upsert(review *PornDBMatchReview) error {
+	review.UpdatedAt = time.Now()
+	if review.Candidates == nil {
+		review.Candidates = PornDBMatchCandidates{}
+	}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scene_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"job_id", "provider", "status", "query", "candidates", "best_score", "porndb_scene_id", "reviewed_by", "reviewed_at", "updated_at",
+		}),
+	}).Create(review).Error
+}
+
+func (r *PornDBMatchRepositoryImpl) GetBySceneID(sceneID uint) (*PornDBMatchReview, error) {
+	var review PornDBMatchReview
+	if err := r.DB.Where("scene_id = ?", sceneID).First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *PornDBMatchRepositoryImpl) List(status string, page, limit int) ([]PornDBMatchReview, int64, error) {
+	query := r.DB.Model(&PornDBMatchReview{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []PornDBMatchReview
+	err := query.Order("best_score DESC, id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&reviews).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *PornDBMatchRepositoryImpl) Update(review *PornDBMatchReview) error {
+	return r.DB.Save(review).Error
+}
@@ -0,0 +1,42 @@
+package data
+
+import "time"
+
+// Remote import statuses
+const (
+	RemoteImportQueued      = "queued"
+	RemoteImportDownloading = "downloading"
+	RemoteImportImporting   = "importing"
+	RemoteImportCompleted   = "completed"
+	RemoteImportFailed      = "failed"
+	RemoteImportCancelled   = "cancelled"
+)
+
+// RemoteImport is a file downloaded from a URL into a storage path. Its JobID
+// is also the job_history entry of the download.
+type RemoteImport struct {
+	ID              uint       `gorm:"primarykey" json:"id"`
+	JobID           string     `gorm:"size:36;uniqueIndex;not null" json:"job_id"`
+	URL             string     `gorm:"not null" json:"url"`
+	Title           string     `gorm:"size:255;not null;default:''" json:"title"`
+	StoragePathID   *uint      `json:"storage_path_id"` // nil = the default storage path
+	UserID          *uint      `json:"user_id"`
+	Status          string     `gorm:"size:20;not null;default:'queued'" json:"status"`
+	Filename        string     `gorm:"type:text;not null;default:''" json:"filename"` // path of the file in the storage path once downloaded
+	BytesDownloaded int64      `gorm:"not null;default:0" json:"bytes_downloaded"`
+	TotalBytes      *int64     `json:"total_bytes"` // nil when the server did not send a size
+	SceneID         *uint      `json:"scene_id"`
+	Error           string     `gorm:"not null;default:''" json:"error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at"`
+}
+
+func (RemoteImport) TableName() string {
+	return "remote_imports"
+}
+
+// IsFinished reports whether the import reached a final status.
+func (r *RemoteImport) IsFinished() bool {
+	return r.Status == RemoteImportCompleted || r.Status == RemoteImportFailed || r.Status == RemoteImportCancelled
+}
@@ -0,0 +1,103 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RemoteImportRepository interface {
+	Create(item *RemoteImport) error
+	GetByJobID(jobID string) (*RemoteImport, error)
+	List(page, limit int) ([]RemoteImport, int64, error)
+	// ListUnfinished returns the queued and interrupted imports, oldest first
+	ListUnfinished() ([]RemoteImport, error)
+	UpdateStatus(jobID, status, errMsg string) error
+	MarkStarted(jobID string, startedAt time.Time) error
+	UpdateProgress(jobID string, bytesDownloaded int64, totalBytes *int64) error
+	MarkDownloaded(jobID, filename string) error
+	MarkCompleted(jobID string, sceneID *uint, completedAt time.Time) error
+}
+
+type RemoteImportRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewRemoteImportRepository(db *gorm.DB) *RemoteImportRepositoryImpl {
+	return &RemoteImportRepositoryImpl{DB: db}
+}
+
+func (r *RemoteImportRepositoryImpl) Create(item *RemoteImport) error {
+	return r.DB.Create(item).Error
+}
+
+func (r *RemoteImportRepositoryImpl) GetByJobID(jobID string) (*RemoteImport, error) {
+	var item RemoteImport
+	if err := r.DB.Where("job_id = ?", jobID).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *RemoteImportRepositoryImpl) List(page, limit int) ([]RemoteImport, int64, error) {
+	var items []RemoteImport
+	var total int64
+	if err := r.DB.Model(&RemoteImport{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * limit
+	if err := r.DB.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *RemoteImportRepositoryImpl) ListUnfinished() ([]RemoteImport, error) {
+	var items []RemoteImport
+	err := r.DB.Where("status IN ?", []string{RemoteImportQueued, RemoteImportDownloading, RemoteImportImporting}).
+		Order("created_at ASC, id ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *RemoteImportRepositoryImpl) UpdateStatus(jobID, status, errMsg string) error {
+	updates := map[string]any{"status": status, "error": errMsg}
+	if status == RemoteImportFailed || status == RemoteImportCancelled {
+		updates["completed_at"] = time.Now()
+	}
+	return r.DB.Model(&RemoteImport{}).Where("job_id = ?", jobID).Updates(updates).Error
+}
+
+func (r *RemoteImportRepositoryImpl) MarkStarted(jobID string, startedAt time.Time) error {
+	return r.DB.Model(&RemoteImport{}).Where("job_id = ?", jobID).Updates(map[string]any{
+		"status":     RemoteImportDownloading,
+		"started_at": startedAt,
+	}).Error
+}
+
+func (r *RemoteImportRepositoryImpl) UpdateProgress(jobID string, bytesDownloaded int64, totalBytes *int64) error {
+	return r.DB.Model(&RemoteImport{}).Where("job_id = ?", jobID).Updates(map[string]any{
+		"bytes_downloaded": bytesDownloaded,
+		"total_bytes":      totalBytes,
+	}).Error
+}
+
+func (r *RemoteImportRepositoryImpl) MarkDownloaded(jobID, filename string) error {
+	return r.DB.Model(&RemoteImport{}).Where("job_id = ?", jobID).Updates(map[string]any{
+		"status":   RemoteImportImporting,
+		"filename": filename,
+	}).Error
+}
+
+func (r *RemoteImportRepositoryImpl) MarkCompleted(jobID string, sceneID *uint, completedAt time.Time) error {
+	return r.DB.Model(&RemoteImport{}).Where("job_id = ?", jobID).Updates(map[string]any{
+		"status":       RemoteImportCompleted,
+		"scene_id":     sceneID,
+		"completed_at": completedAt,
+	}).Error
+}
+
+var _ RemoteImportRepository = (*RemoteImportRepositoryImpl)(nil)
@@ -1,6 +1,8 @@
 package data
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/lib/pq"
@@ -18,6 +20,8 @@ type UserRepository interface {
 	List(page, limit int) ([]User, int64, error)
 	UpdateRole(userID uint, role string) error
 	UpdateLastLogin(userID uint) error
+	UpdatePrivacyLock(userID uint, pinHash string, lockMinutes int) error
+	UpdateTwoFactor(userID uint, secret string, enabled bool, recoveryCodeHashes []string) error
 	Delete(userID uint) error
 }
 
@@ -37,6 +41,7 @@ type SceneSearchParams struct {
 	Limit            int
 	Query            string
 	TagIDs           []uint
+	ExcludeTagIDs    []uint // scenes must have none of these tags
 	Actors           []string
 	Studio           string
 	MinDuration      int
@@ -52,13 +57,19 @@ type SceneSearchParams struct {
 	MaxRating        float64
 	MinJizzCount     int
 	MaxJizzCount     int
-	SceneIDs         []uint   // Pre-filter to specific scene IDs (e.g., folder search)
-	MatchingStrategy string   // Meilisearch matching strategy: "last", "all", or "frequency"
-	MarkerLabels     []string // Filter to scenes with markers having these labels (user-specific)
-	Origin           string   // Filter by origin (web, dvd, personal, stash, unknown)
-	Type             string   // Filter by type (standard, jav, hentai, amateur, professional, vr, compilation, pmv)
-	HasPornDBID      *bool    // nil = no filter, true = has, false = missing
-	Seed             int64    // Random shuffle seed (0 = auto-generate)
+	SceneIDs         []uint              // Pre-filter to specific scene IDs (e.g., folder search)
+	MatchingStrategy string              // Meilisearch matching strategy: "last", "all", or "frequency"
+	MarkerLabels     []string            // Filter to scenes with markers having these labels (user-specific)
+	Origin           string              // Filter by origin (web, dvd, personal, stash, unknown)
+	Type             string              // Filter by type (standard, jav, hentai, amateur, professional, vr, compilation, pmv)
+	HasPornDBID      *bool               // nil = no filter, true = has, false = missing
+	IsCorrupted      *bool               // nil = no filter, true = corrupted only, false = healthy only
+	ReviewStates     []string            // stored review_state values to match (nil = no filter)
+	Seed             int64               // Random shuffle seed (0 = auto-generate)
+	Offset           int                 // Random sort only: shuffle position to start at, used instead of Page when > 0
+	Restriction      *ContentRestriction // Limit to the part of the library the user's role may see (nil = no limit)
+	UseCursor        bool                // Page by Cursor instead of Page; only the created_at, duration and view_count sorts support it
+	Cursor           string              // UseCursor only: encoded cursor from the previous page ("" = first page)
 }
 
 // ScanLookupEntry is a lightweight struct for move detection during scans.
@@ -68,6 +79,7 @@ type ScanLookupEntry struct {
 	StoredPath       string
 	Size             int64
 	OriginalFilename string
+	FileHash         string
 	IsDeleted        bool
 }
 
@@ -79,6 +91,30 @@ type ScenePathInfo struct {
 	Title         string
 }
 
+// SceneColumnUpdate is one scene's changes in a BulkUpdateColumns call, keyed
+// by column name.
+type SceneColumnUpdate struct {
+	SceneID uint
+	Columns map[string]any
+}
+
+// SceneMetadataApplication is everything applying matched metadata writes to
+// a scene in one ApplyMetadata call.
+type SceneMetadataApplication struct {
+	SceneID    uint
+	Columns    map[string]any    // scene columns to set, keyed by column name
+	ActorNames []string          // added to the scene's actors, created when missing
+	TagNames   []string          // added to the scene's tags, created when missing
+	Markers    []UserSceneMarker // created unless their owner has a marker at the same timestamp
+}
+
+// SceneMetadataApplicationResult is what an ApplyMetadata call created.
+type SceneMetadataApplicationResult struct {
+	CreatedActors  []string `json:"created_actors"`
+	CreatedTags    []string `json:"created_tags"`
+	CreatedMarkers int      `json:"created_markers"`
+}
+
 type SceneRepository interface {
 	Create(scene *Scene) error
 	CreateInBatches(scenes []*Scene, batchSize int) error
@@ -86,6 +122,9 @@ type SceneRepository interface {
 	GetByID(id uint) (*Scene, error)
 	GetByIDs(ids []uint) ([]Scene, error)
 	GetAll() ([]Scene, error)
+	GetBatchAfterID(afterID uint, limit int) ([]Scene, error)
+	CountActive() (int64, error)
+	GetActiveIDs() ([]uint, error)
 	GetAllWithStoragePath() ([]Scene, error)
 	GetAllStoredPathSet() (map[string]struct{}, error)
 	GetScanLookupEntries() ([]ScanLookupEntry, error)
@@ -95,15 +134,17 @@ type SceneRepository interface {
 	UpdateMetadata(id uint, duration int, width, height int, thumbnailPath string, spriteSheetPath string, vttPath string, spriteSheetCount int, thumbnailWidth int, thumbnailHeight int) error
 	UpdateBasicMetadata(id uint, duration int, width, height int, frameRate float64, bitRate int64, videoCodec, audioCodec string) error
 	UpdateThumbnail(id uint, thumbnailPath string, thumbnailWidth, thumbnailHeight int) error
-	UpdateSprites(id uint, spriteSheetPath, vttPath string, spriteSheetCount int) error
+	UpdateSprites(id uint, spriteSheetPath, vttPath string, spriteSheetCount int, trickplayPath string) error
 	UpdatePreviewVideoPath(id uint, previewVideoPath string) error
 	UpdateProcessingStatus(id uint, status string, errorMsg string) error
 	UpdateIsCorrupted(id uint, isCorrupted bool) error
+	UpdateChecksum(id uint, fileHash string, mismatch bool) error
 	GetPendingProcessing() ([]Scene, error)
 	GetScenesNeedingPhase(phase string) ([]Scene, error)
 	Delete(id uint) error
 	UpdateDetails(id uint, title, description string, releaseDate *time.Time) error
 	UpdateSceneMetadata(id uint, title, description, studio string, releaseDate *time.Time, porndbSceneID string) error
+	ApplyMetadata(app SceneMetadataApplication) (*SceneMetadataApplicationResult, error)
 	ExistsByStoredPath(path string) (bool, error)
 	GetByStoredPath(path string) (*Scene, error)
 	MarkAsMissing(id uint) error
@@ -111,6 +152,7 @@ type SceneRepository interface {
 	UpdateStoredPath(id uint, newPath string, storagePathID *uint) error
 	GetBySizeAndFilename(size int64, filename string) (*Scene, error)
 	BulkUpdateStudio(sceneIDs []uint, studio string) error
+	BulkUpdateColumns(updates []SceneColumnUpdate) error
 	UpdateActors(id uint, actors []string) error
 	UpdateOriginAndType(id uint, origin, sceneType string) error
 
@@ -126,6 +168,18 @@ type SceneRepository interface {
 	// PornDB filtering
 	GetSceneIDsWithPornDBID() ([]uint, error)
 	GetSceneIDsWithoutPornDBID() ([]uint, error)
+	GetSceneIDsByPornDBID(porndbSceneID string) ([]uint, error)
+	GetSceneIDsByFileHash(fileHash string) ([]uint, error)
+	// GetSharedFileHashes returns the checksums shared by more than one scene
+	GetSharedFileHashes() ([]string, error)
+
+	// Corruption filtering
+	GetSceneIDsByCorruption(isCorrupted bool) ([]uint, error)
+
+	// Review workflow
+	UpdateReviewState(sceneIDs []uint, state string) error
+	CountByReviewState() (map[string]int64, error)
+	GetSceneIDsByReviewStates(states []string) ([]uint, error)
 
 	// Popular scenes (ordered by view count)
 	ListPopular(limit int) ([]Scene, error)
@@ -202,6 +256,35 @@ func (r *SceneRepositoryImpl) GetAll() ([]Scene, error) {
 	return scenes, nil
 }
 
+// GetBatchAfterID returns up to limit non-trashed scenes with an ID above afterID,
+// in ID order, for walking the whole library in batches
+func (r *SceneRepositoryImpl) GetBatchAfterID(afterID uint, limit int) ([]Scene, error) {
+	var scenes []Scene
+	if err := r.DB.Where("id > ? AND trashed_at IS NULL", afterID).Order("id ASC").Limit(limit).Find(&scenes).Error; err != nil {
+		return nil, err
+	}
+	return scenes, nil
+}
+
+// CountActive returns the number of scenes that are not in the trash
+func (r *SceneRepositoryImpl) CountActive() (int64, error) {
+	var count int64
+	if err := r.DB.Model(&Scene{}).Where("trashed_at IS NULL").Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetActiveIDs returns the IDs of all scenes that are not in the trash, in ID order
+func (r *SceneRepositoryImpl) GetActiveIDs() ([]uint, error) {
+	var ids []uint
+	err := r.DB.Model(&Scene{}).
+		Where("trashed_at IS NULL").
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
 func (r *SceneRepositoryImpl) UpdateMetadata(id uint, duration int, width, height int, thumbnailPath string, spriteSheetPath string, vttPath string, spriteSheetCount int, thumbnailWidth int, thumbnailHeight int) error {
 	updates := map[string]interface{}{
 		"duration":           duration,
@@ -240,11 +323,12 @@ func (r *SceneRepositoryImpl) UpdateThumbnail(id uint, thumbnailPath string, thu
 	return r.DB.Model(&Scene{}).Where("id = ?", id).Updates(updates).Error
 }
 
-func (r *SceneRepositoryImpl) UpdateSprites(id uint, spriteSheetPath, vttPath string, spriteSheetCount int) error {
+func (r *SceneRepositoryImpl) UpdateSprites(id uint, spriteSheetPath, vttPath string, spriteSheetCount int, trickplayPath string) error {
 	updates := map[string]interface{}{
 		"sprite_sheet_path":  spriteSheetPath,
 		"vtt_path":           vttPath,
 		"sprite_sheet_count": spriteSheetCount,
+		"trickplay_path":     trickplayPath,
 	}
 	return r.DB.Model(&Scene{}).Where("id = ?", id).Updates(updates).Error
 }
@@ -267,6 +351,15 @@ func (r *SceneRepositoryImpl) UpdateIsCorrupted(id uint, isCorrupted bool) error
 	return r.DB.Model(&Scene{}).Where("id = ?", id).Update("is_corrupted", isCorrupted).Error
 }
 
+// UpdateChecksum stores a scene's file hash and records when it was last checked.
+func (r *SceneRepositoryImpl) UpdateChecksum(id uint, fileHash string, mismatch bool) error {
+	return r.DB.Model(&Scene{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"file_hash":         fileHash,
+		"checksum_mismatch": mismatch,
+		"hash_verified_at":  time.Now(),
+	}).Error
+}
+
 func (r *SceneRepositoryImpl) GetPendingProcessing() ([]Scene, error) {
 	var scenes []Scene
 	if err := r.DB.Where("processing_status = ? AND trashed_at IS NULL", "pending").Find(&scenes).Error; err != nil {
@@ -309,6 +402,11 @@ func (r *SceneRepositoryImpl) GetScenesNeedingPhase(phase string) ([]Scene, erro
 			return nil, err
 		}
 		return animScenes, nil
+	case "verify":
+		// Scenes that have never had a full decode verification
+		baseQuery = baseQuery.Where("NOT EXISTS (SELECT 1 FROM scene_integrity_reports sir WHERE sir.scene_id = scenes.id)")
+	case "checksum":
+		baseQuery = baseQuery.Where("file_hash = ''")
 	default:
 		return nil, nil
 	}
@@ -347,6 +445,95 @@ func (r *SceneRepositoryImpl) UpdateSceneMetadata(id uint, title, description, s
 	return r.DB.Model(&Scene{}).Where("id = ?", id).Updates(updates).Error
 }
 
+// ApplyMetadata writes the scene columns, actors, tags and markers in one
+// transaction, so a failure part way leaves the scene and the actor and tag
+// tables as they were. Actors match by name or alias ignoring case, tags exactly.
+func (r *SceneRepositoryImpl) ApplyMetadata(app SceneMetadataApplication) (*SceneMetadataApplicationResult, error) {
+	result := &SceneMetadataApplicationResult{CreatedActors: []string{}, CreatedTags: []string{}}
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if len(app.Columns) > 0 {
+			res := tx.Model(&Scene{}).Where("id = ?", app.SceneID).Updates(app.Columns)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		if len(app.ActorNames) > 0 {
+			for _, name := range app.ActorNames {
+				actor, err := findActorByName(tx, name)
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					actor = &Actor{Name: name}
+					if err = tx.Create(actor).Error; err == nil {
+						result.CreatedActors = append(result.CreatedActors, name)
+					}
+				}
+				if err != nil {
+					return fmt.Errorf("actor %q: %w", name, err)
+				}
+				link := SceneActor{SceneID: app.SceneID, ActorID: actor.ID}
+				if err := tx.Where(&link).FirstOrCreate(&link).Error; err != nil {
+					return err
+				}
+			}
+
+			// Keep the denormalized actors column in sync
+			var names []string
+			if err := tx.Model(&Actor{}).
+				Joins("JOIN scene_actors ON scene_actors.actor_id = actors.id").
+				Where("scene_actors.scene_id = ?", app.SceneID).
+				Order("actors.name ASC").
+				Pluck("actors.name", &names).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&Scene{}).Where("id = ?", app.SceneID).Update("actors", pq.StringArray(names)).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, name := range app.TagNames {
+			tag, err := findTagByName(tx, name)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				tag = &Tag{Name: name, Color: "#6B7280"}
+				if err = tx.Create(tag).Error; err == nil {
+					result.CreatedTags = append(result.CreatedTags, name)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("tag %q: %w", name, err)
+			}
+			link := SceneTag{SceneID: app.SceneID, TagID: tag.ID}
+			if err := tx.Where(&link).FirstOrCreate(&link).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, marker := range app.Markers {
+			marker.SceneID = app.SceneID
+			var existing int64
+			if err := tx.Model(&UserSceneMarker{}).
+				Where("user_id = ? AND scene_id = ? AND timestamp = ?", marker.UserID, marker.SceneID, marker.Timestamp).
+				Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 {
+				continue
+			}
+			if err := tx.Create(&marker).Error; err != nil {
+				return err
+			}
+			result.CreatedMarkers++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (r *SceneRepositoryImpl) GetDistinctStudios() ([]string, error) {
 	var studios []string
 	err := r.DB.Model(&Scene{}).
@@ -422,7 +609,7 @@ func (r *SceneRepositoryImpl) GetAllStoredPathSet() (map[string]struct{}, error)
 func (r *SceneRepositoryImpl) GetScanLookupEntries() ([]ScanLookupEntry, error) {
 	var entries []ScanLookupEntry
 	if err := r.DB.Unscoped().Model(&Scene{}).
-		Select("id, stored_path, size, original_filename, (deleted_at IS NOT NULL) as is_deleted").
+		Select("id, stored_path, size, original_filename, file_hash, (deleted_at IS NOT NULL) as is_deleted").
 		Find(&entries).Error; err != nil {
 		return nil, err
 	}
@@ -480,6 +667,22 @@ func (r *SceneRepositoryImpl) BulkUpdateStudio(sceneIDs []uint, studio string) e
 	return r.DB.Model(&Scene{}).Where("id IN ?", sceneIDs).Update("studio", studio).Error
 }
 
+// BulkUpdateColumns applies every update in one transaction, so either all
+// scenes change or none do.
+func (r *SceneRepositoryImpl) BulkUpdateColumns(updates []SceneColumnUpdate) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for _, u := range updates {
+			if len(u.Columns) == 0 {
+				continue
+			}
+			if err := tx.Model(&Scene{}).Where("id = ?", u.SceneID).Updates(u.Columns).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *SceneRepositoryImpl) UpdateActors(id uint, actors []string) error {
 	return r.DB.Model(&Scene{}).Where("id = ?", id).Update("actors", pq.StringArray(actors)).Error
 }
@@ -583,6 +786,83 @@ func (r *SceneRepositoryImpl) GetSceneIDsWithoutPornDBID() ([]uint, error) {
 	return ids, err
 }
 
+func (r *SceneRepositoryImpl) GetSceneIDsByPornDBID(porndbSceneID string) ([]uint, error) {
+	var ids []uint
+	err := r.DB.Model(&Scene{}).
+		Where("porndb_scene_id = ? AND trashed_at IS NULL", porndbSceneID).
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetSceneIDsByFileHash skips scenes whose file no longer matches its stored checksum.
+func (r *SceneRepositoryImpl) GetSceneIDsByFileHash(fileHash string) ([]uint, error) {
+	var ids []uint
+	err := r.DB.Model(&Scene{}).
+		Where("file_hash = ? AND checksum_mismatch = false AND trashed_at IS NULL", fileHash).
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *SceneRepositoryImpl) GetSharedFileHashes() ([]string, error) {
+	var hashes []string
+	err := r.DB.Model(&Scene{}).
+		Where("file_hash <> '' AND checksum_mismatch = false AND trashed_at IS NULL").
+		Group("file_hash").
+		Having("COUNT(*) > 1").
+		Order("file_hash ASC").
+		Pluck("file_hash", &hashes).Error
+	return hashes, err
+}
+
+func (r *SceneRepositoryImpl) GetSceneIDsByCorruption(isCorrupted bool) ([]uint, error) {
+	var ids []uint
+	err := r.DB.Model(&Scene{}).
+		Where("is_corrupted = ? AND trashed_at IS NULL", isCorrupted).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *SceneRepositoryImpl) UpdateReviewState(sceneIDs []uint, state string) error {
+	if len(sceneIDs) == 0 {
+		return nil
+	}
+	return r.DB.Model(&Scene{}).Where("id IN ?", sceneIDs).Updates(map[string]interface{}{
+		"review_state":            state,
+		"review_state_updated_at": time.Now(),
+	}).Error
+}
+
+// CountByReviewState counts non-trashed scenes per stored review state.
+func (r *SceneRepositoryImpl) CountByReviewState() (map[string]int64, error) {
+	var rows []struct {
+		ReviewState string
+		Count       int64
+	}
+	err := r.DB.Model(&Scene{}).
+		Select("review_state, COUNT(*) AS count").
+		Where("trashed_at IS NULL").
+		Group("review_state").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ReviewState] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *SceneRepositoryImpl) GetSceneIDsByReviewStates(states []string) ([]uint, error) {
+	var ids []uint
+	err := r.DB.Model(&Scene{}).
+		Where("review_state IN ? AND trashed_at IS NULL", states).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
 func (r *SceneRepositoryImpl) ListPopular(limit int) ([]Scene, error) {
 	var scenes []Scene
 	err := r.DB.Where("trashed_at IS NULL").
@@ -672,6 +952,25 @@ func (r *UserRepositoryImpl) UpdateLastLogin(userID uint) error {
 	return r.DB.Model(&User{}).Where("id = ?", userID).Update("last_login_at", time.Now()).Error
 }
 
+func (r *UserRepositoryImpl) UpdatePrivacyLock(userID uint, pinHash string, lockMinutes int) error {
+	return r.DB.Model(&User{}).Where("id = ?", userID).Updates(map[string]any{
+		"privacy_pin_hash":     pinHash,
+		"privacy_lock_minutes": lockMinutes,
+	}).Error
+}
+
+func (r *UserRepositoryImpl) UpdateTwoFactor(userID uint, secret string, enabled bool, recoveryCodeHashes []string) error {
+	// A nil array would be written as NULL
+	if recoveryCodeHashes == nil {
+		recoveryCodeHashes = []string{}
+	}
+	return r.DB.Model(&User{}).Where("id = ?", userID).Updates(map[string]any{
+		"totp_secret":         secret,
+		"totp_enabled":        enabled,
+		"totp_recovery_codes": pq.StringArray(recoveryCodeHashes),
+	}).Error
+}
+
 func (r *UserRepositoryImpl) Delete(userID uint) error {
 	return r.DB.Where("id = ?", userID).Delete(&User{}).Error
 }
@@ -41,6 +41,6 @@ func (r *RetryConfigRepositoryImpl) Upsert(record *RetryConfigRecord) error {
 	record.UpdatedAt = time.Now()
 	return r.DB.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "phase"}},
-		DoUpdates: clause.AssignmentColumns([]string{"max_retries", "initial_delay_seconds", "max_delay_seconds", "backoff_factor", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"max_retries", "initial_delay_seconds", "max_delay_seconds", "backoff_factor", "backoff_strategy", "jitter_percent", "never_retry_errors", "always_retry_errors", "retry_unclassified", "updated_at"}),
 	}).Create(record).Error
 }
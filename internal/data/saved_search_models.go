@@ -11,13 +11,17 @@ import (
 )
 
 type SavedSearch struct {
-	ID        uint      `gorm:"primarykey" json:"id"`
-	UUID      uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"uuid"`
-	UserID    uint      `gorm:"not null" json:"user_id"`
-	Name      string    `gorm:"size:255;not null" json:"name"`
-	Filters   Filters   `gorm:"type:jsonb;not null;default:'{}'" json:"filters"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID      uint      `gorm:"primarykey" json:"id"`
+	UUID    uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"uuid"`
+	UserID  uint      `gorm:"not null" json:"user_id"`
+	Name    string    `gorm:"size:255;not null" json:"name"`
+	Filters Filters   `gorm:"type:jsonb;not null;default:'{}'" json:"filters"`
+	// Watched searches are re-run periodically to record newly added matching scenes
+	Watched       bool       `gorm:"not null;default:false" json:"watched"`
+	WatchedAt     *time.Time `json:"watched_at"`
+	LastCheckedAt *time.Time `json:"last_checked_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 // BeforeCreate generates a UUID if not set
@@ -28,6 +32,18 @@ func (s *SavedSearch) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// SavedSearchMatch is a scene a watched saved search found after it started watching.
+type SavedSearchMatch struct {
+	SavedSearchID uint       `gorm:"primaryKey" json:"saved_search_id"`
+	SceneID       uint       `gorm:"primaryKey" json:"scene_id"`
+	MatchedAt     time.Time  `json:"matched_at"`
+	SeenAt        *time.Time `json:"seen_at"`
+}
+
+func (SavedSearchMatch) TableName() string {
+	return "saved_search_matches"
+}
+
 // Filters represents the saved search filter parameters
 type Filters struct {
 	Query          string   `json:"query,omitempty"`
@@ -1,7 +1,10 @@
 package data
 
 import (
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SavedSearchRepository interface {
@@ -11,6 +14,13 @@ type SavedSearchRepository interface {
 	Update(search *SavedSearch) error
 	Delete(id uint) error
 	ListByUserID(userID uint) ([]SavedSearch, error)
+
+	// Watch methods
+	ListWatched() ([]SavedSearch, error)
+	AddMatches(searchID uint, sceneIDs []uint) (int64, error)
+	CountUnseenMatches(searchIDs []uint) (map[uint]int64, error)
+	GetUnseenMatchSceneIDs(searchID uint) ([]uint, error)
+	MarkMatchesSeen(searchID uint) error
 }
 
 type SavedSearchRepositoryImpl struct {
@@ -68,5 +78,78 @@ func (r *SavedSearchRepositoryImpl) ListByUserID(userID uint) ([]SavedSearch, er
 	return searches, nil
 }
 
+func (r *SavedSearchRepositoryImpl) ListWatched() ([]SavedSearch, error) {
+	var searches []SavedSearch
+	if err := r.DB.Where("watched = ?", true).Order("id ASC").Find(&searches).Error; err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// AddMatches records scenes found by a watched search and returns how many were
+// new. Scenes recorded before, seen or not, are skipped.
+func (r *SavedSearchRepositoryImpl) AddMatches(searchID uint, sceneIDs []uint) (int64, error) {
+	if len(sceneIDs) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	matches := make([]SavedSearchMatch, len(sceneIDs))
+	for i, sceneID := range sceneIDs {
+		matches[i] = SavedSearchMatch{SavedSearchID: searchID, SceneID: sceneID, MatchedAt: now}
+	}
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&matches)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CountUnseenMatches returns the number of unseen matches for each search.
+// Searches without unseen matches are absent from the map.
+func (r *SavedSearchRepositoryImpl) CountUnseenMatches(searchIDs []uint) (map[uint]int64, error) {
+	if len(searchIDs) == 0 {
+		return make(map[uint]int64), nil
+	}
+
+	var rows []struct {
+		SavedSearchID uint
+		Count         int64
+	}
+	err := r.DB.Model(&SavedSearchMatch{}).
+		Select("saved_search_id, COUNT(*) as count").
+		Where("saved_search_id IN ? AND seen_at IS NULL", searchIDs).
+		Group("saved_search_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		result[row.SavedSearchID] = row.Count
+	}
+	return result, nil
+}
+
+// GetUnseenMatchSceneIDs returns the unseen matches of a search, newest first.
+func (r *SavedSearchRepositoryImpl) GetUnseenMatchSceneIDs(searchID uint) ([]uint, error) {
+	var sceneIDs []uint
+	err := r.DB.Model(&SavedSearchMatch{}).
+		Where("saved_search_id = ? AND seen_at IS NULL", searchID).
+		Order("matched_at DESC, scene_id DESC").
+		Pluck("scene_id", &sceneIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return sceneIDs, nil
+}
+
+func (r *SavedSearchRepositoryImpl) MarkMatchesSeen(searchID uint) error {
+	return r.DB.Model(&SavedSearchMatch{}).
+		Where("saved_search_id = ? AND seen_at IS NULL", searchID).
+		Update("seen_at", time.Now()).Error
+}
+
 // Ensure SavedSearchRepositoryImpl implements SavedSearchRepository
 var _ SavedSearchRepository = (*SavedSearchRepositoryImpl)(nil)
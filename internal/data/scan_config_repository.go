@@ -0,0 +1,68 @@
+package data
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScanConfigRecord holds the scan exclusion rules applied to every storage path.
+type ScanConfigRecord struct {
+	ID               int            `gorm:"primaryKey" json:"id"`
+	MinFileSizeBytes int64          `gorm:"column:min_file_size_bytes" json:"min_file_size_bytes"` // smaller videos are ignored (0 = no minimum)
+	IgnoreSamples    bool           `gorm:"column:ignore_samples" json:"ignore_samples"`           // skip files named like samples or trailers
+	IgnoreHiddenDirs bool           `gorm:"column:ignore_hidden_dirs" json:"ignore_hidden_dirs"`   // skip directories starting with a dot
+	ExcludePatterns  pq.StringArray `gorm:"column:exclude_patterns;type:text[]" json:"exclude_patterns"`
+	UpdatedAt        time.Time      `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (ScanConfigRecord) TableName() string {
+	return "scan_config"
+}
+
+type ScanConfigRepository interface {
+	Get() (*ScanConfigRecord, error)
+	Upsert(record *ScanConfigRecord) error
+}
+
+type ScanConfigRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewScanConfigRepository(db *gorm.DB) *ScanConfigRepositoryImpl {
+	return &ScanConfigRepositoryImpl{DB: db}
+}
+
+func (r *ScanConfigRepositoryImpl) Get() (*ScanConfigRecord, error) {
+	var record ScanConfigRecord
+	err := r.DB.First(&record).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// Return default values if no record exists
+			return &ScanConfigRecord{
+				ID:               1,
+				IgnoreHiddenDirs: true,
+				ExcludePatterns:  pq.StringArray{},
+				UpdatedAt:        time.Now(),
+			}, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *ScanConfigRepositoryImpl) Upsert(record *ScanConfigRecord) error {
+	record.ID = 1
+	record.UpdatedAt = time.Now()
+	if record.ExcludePatterns == nil {
+		record.ExcludePatterns = pq.StringArray{}
+	}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"min_file_size_bytes", "ignore_samples", "ignore_hidden_dirs", "exclude_patterns", "updated_at",
+		}),
+	}).Create(record).Error
+}
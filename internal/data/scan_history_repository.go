@@ -3,25 +3,28 @@ package data
 import (
 	"time"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
 type ScanHistory struct {
-	ID            uint       `gorm:"primarykey" json:"id"`
-	Status        string     `gorm:"not null;default:'running'" json:"status"`
-	StartedAt     time.Time  `gorm:"not null;default:now()" json:"started_at"`
-	CompletedAt   *time.Time `json:"completed_at"`
-	PathsScanned  int        `gorm:"not null;default:0" json:"paths_scanned"`
-	FilesFound    int        `gorm:"not null;default:0" json:"files_found"`
-	VideosAdded   int        `gorm:"not null;default:0" json:"videos_added"`
-	VideosSkipped int        `gorm:"not null;default:0" json:"videos_skipped"`
-	VideosRemoved int        `gorm:"not null;default:0" json:"videos_removed"`
-	VideosMoved   int        `gorm:"not null;default:0" json:"videos_moved"`
-	Errors        int        `gorm:"not null;default:0" json:"errors"`
-	ErrorMessage  *string    `gorm:"type:text" json:"error_message,omitempty"`
-	CurrentPath   *string    `gorm:"size:500" json:"current_path,omitempty"`
-	CurrentFile   *string    `gorm:"size:500" json:"current_file,omitempty"`
-	CreatedAt     time.Time  `gorm:"not null;default:now()" json:"created_at"`
+	ID             uint          `gorm:"primarykey" json:"id"`
+	Status         string        `gorm:"not null;default:'running'" json:"status"`
+	StartedAt      time.Time     `gorm:"not null;default:now()" json:"started_at"`
+	CompletedAt    *time.Time    `json:"completed_at"`
+	PathsScanned   int           `gorm:"not null;default:0" json:"paths_scanned"`
+	FilesFound     int           `gorm:"not null;default:0" json:"files_found"`
+	VideosAdded    int           `gorm:"not null;default:0" json:"videos_added"`
+	VideosSkipped  int           `gorm:"not null;default:0" json:"videos_skipped"`
+	VideosRemoved  int           `gorm:"not null;default:0" json:"videos_removed"`
+	VideosMoved    int           `gorm:"not null;default:0" json:"videos_moved"`
+	Errors         int           `gorm:"not null;default:0" json:"errors"`
+	ErrorMessage   *string       `gorm:"type:text" json:"error_message,omitempty"`
+	CurrentPath    *string       `gorm:"size:500" json:"current_path,omitempty"`
+	CurrentFile    *string       `gorm:"size:500" json:"current_file,omitempty"`
+	StoragePathIDs pq.Int64Array `gorm:"type:bigint[]" json:"storage_path_ids,omitempty"` // scan scope; empty means all storage paths
+	FolderPath     *string       `gorm:"size:500" json:"folder_path,omitempty"`           // folder of the single scoped storage path
+	CreatedAt      time.Time     `gorm:"not null;default:now()" json:"created_at"`
 }
 
 func (ScanHistory) TableName() string {
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Scan report entry kinds
+const (
+	ScanReportAdded   = "added"
+	ScanReportMoved   = "moved"
+	ScanReportRemoved = "removed"
+	ScanReportError   = "error"
+)
+
+// ScanReportEntry records one path a scan added, moved, removed or failed on.
+type ScanReportEntry struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ScanID    uint      `gorm:"not null;index" json:"scan_id"`
+	Kind      string    `gorm:"size:20;not null" json:"kind"`
+	Path      string    `gorm:"type:text;not null" json:"path"`
+	OldPath   string    `gorm:"type:text;not null;default:''" json:"old_path,omitempty"` // previous location of a moved scene
+	SceneID   *uint     `json:"scene_id,omitempty"`
+	Message   string    `gorm:"type:text;not null;default:''" json:"message,omitempty"`
+	CreatedAt time.Time `gorm:"not null;default:now()" json:"created_at"`
+}
+
+func (ScanReportEntry) TableName() string {
+	return "scan_report_entries"
+}
+
+type ScanReportRepository interface {
+	CreateBatch(entries []ScanReportEntry) error
+	List(scanID uint, kind string, page, limit int) ([]ScanReportEntry, int64, error)
+	CountByKind(scanID uint) (map[string]int64, error)
+	ForEach(scanID uint, batchSize int, fn func([]ScanReportEntry) error) error
+}
+
+type ScanReportRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewScanReportRepository(db *gorm.DB) *ScanReportRepositoryImpl {
+	return &ScanReportRepositoryImpl{DB: db}
+}
+
+func (r *ScanReportRepositoryImpl) CreateBatch(entries []ScanReportEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.DB.CreateInBatches(entries, 500).Error
+}
+
+// List returns a page of a scan's report entries, optionally filtered by kind.
+func (r *ScanReportRepositoryImpl) List(scanID uint, kind string, page, limit int) ([]ScanReportEntry, int64, error) {
+	var entries []ScanReportEntry
+	var total int64
+
+	query := r.DB.Model(&ScanReportEntry{}).Where("scan_id = ?", scanID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+func (r *ScanReportRepositoryImpl) CountByKind(scanID uint) (map[string]int64, error) {
+	var rows []struct {
+		Kind  string
+		Count int64
+	}
+	if err := r.DB.Model(&ScanReportEntry{}).
+		Select("kind, COUNT(*) as count").
+		Where("scan_id = ?", scanID).
+		Group("kind").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Kind] = row.Count
+	}
+	return counts, nil
+}
+
+// ForEach streams all entries of a scan in id order, batchSize at a time.
+func (r *ScanReportRepositoryImpl) ForEach(scanID uint, batchSize int, fn func([]ScanReportEntry) error) error {
+	var lastID uint
+	for {
+		var entries []ScanReportEntry
+		if err := r.DB.Where("scan_id = ? AND id > ?", scanID, lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&entries).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		if err := fn(entries); err != nil {
+			return err
+		}
+		lastID = entries[len(entries)-1].ID
+	}
+}
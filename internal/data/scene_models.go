@@ -90,6 +90,7 @@ type Scene struct {
 	SpriteSheetPath  string         `json:"sprite_sheet_path"`
 	VttPath          string         `json:"vtt_path"`
 	SpriteSheetCount int            `json:"sprite_sheet_count"`
+	TrickplayPath    string         `json:"trickplay_path"`
 	ThumbnailWidth   int            `json:"thumbnail_width"`
 	ThumbnailHeight  int            `json:"thumbnail_height"`
 	ProcessingStatus string         `json:"processing_status" gorm:"default:'pending'"`
@@ -100,7 +101,9 @@ type Scene struct {
 	Tags             pq.StringArray `json:"tags" gorm:"type:text[]"`
 	Actors           pq.StringArray `json:"actors" gorm:"type:text[]"`
 	CoverImagePath   string         `json:"cover_image_path"`
-	FileHash         string         `json:"file_hash"`
+	FileHash         string         `json:"file_hash"` // SHA-256 of the file contents, empty until the checksum phase runs
+	ChecksumMismatch bool           `json:"checksum_mismatch" gorm:"default:false"`
+	HashVerifiedAt   *time.Time     `json:"hash_verified_at,omitempty"`
 	FrameRate        float64        `json:"frame_rate"`
 	BitRate          int64          `json:"bit_rate"`
 	VideoCodec       string         `json:"video_codec"`
@@ -109,11 +112,16 @@ type Scene struct {
 	StudioID         *uint          `json:"studio_id"`
 	ReleaseDate      *time.Time     `json:"release_date" gorm:"type:date"`
 	PornDBSceneID    string         `json:"porndb_scene_id" gorm:"column:porndb_scene_id"`
+	StashDBSceneID   string         `json:"stashdb_scene_id" gorm:"column:stashdb_scene_id"`
 	Origin           string         `json:"origin" gorm:"size:100"`
 	Type             string         `json:"type" gorm:"size:50"`
 	PreviewVideoPath string         `json:"preview_video_path"`
 	IsCorrupted      bool           `json:"is_corrupted" gorm:"default:false"`
+	ReviewState      string         `json:"review_state" gorm:"size:50;not null;default:''"` // empty = the workflow's initial state
+	ReviewUpdatedAt  *time.Time     `json:"review_state_updated_at,omitempty" gorm:"column:review_state_updated_at"`
 	TrashedAt        *time.Time     `json:"trashed_at,omitempty" gorm:"index"`
+	Heatmap          pq.Int64Array  `json:"heatmap,omitempty" gorm:"type:integer[]"` // bucket densities 0-100 across the scene, empty until computed
+	HeatmapUpdatedAt *time.Time     `json:"heatmap_updated_at,omitempty"`
 }
 
 func (Scene) TableName() string {
@@ -125,6 +133,8 @@ type Tag struct {
 	CreatedAt time.Time `json:"created_at"`
 	Name      string    `gorm:"uniqueIndex;not null;size:100" json:"name"`
 	Color     string    `gorm:"not null;size:7;default:'#6B7280'" json:"color"`
+	// Former names, kept when the tag is renamed or merged into another
+	Aliases pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"aliases"`
 }
 
 type SceneTag struct {
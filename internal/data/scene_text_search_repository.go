@@ -0,0 +1,235 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SceneTextQuery is a scene query run against PostgreSQL full-text search.
+type SceneTextQuery struct {
+	Query            string
+	TagIDs           []uint   // scenes must have all of these tags
+	ExcludeTagIDs    []uint   // scenes must have none of these tags
+	Actors           []string // scenes must have at least one of these actors
+	Studio           string
+	SubStudios       []string // studios under Studio, whose scenes match too
+	MinDuration      int      // seconds, 0 = no bound
+	MaxDuration      int
+	MinHeight        int
+	MaxHeight        int
+	CreatedAfter     *time.Time
+	CreatedBefore    *time.Time
+	ProcessingStatus string
+	SceneIDs         []uint // pre-filtered scene IDs
+	Sort             string // created_at, title, duration or view_count; empty sorts by relevance
+	SortDesc         bool
+	Offset           int
+	Limit            int
+	AllIDs           bool          // return every matching ID, ignoring Offset, Limit and Sort
+	After            *SceneSortKey // SearchAfter only: position of the previous page's last scene
+}
+
+// SceneSortKey is a scene's position in keyset order: its sort column value
+// (created_at as Unix seconds with a fraction) and ID.
+type SceneSortKey struct {
+	Value float64
+	ID    uint
+}
+
+type SceneTextSearchRepository interface {
+	// Search returns the matching scene IDs in sort order and the total match count
+	Search(q SceneTextQuery) ([]uint, int64, error)
+	// SearchAfter returns the next Limit matching scene IDs after q.After,
+	// ordered by the numeric sort column and then ID in the same direction, and
+	// the position of the last one (nil when no more scenes follow). Offset and
+	// relevance ordering don't apply and the total isn't counted.
+	SearchAfter(q SceneTextQuery) ([]uint, *SceneSortKey, error)
+}
+
+type SceneTextSearchRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewSceneTextSearchRepository(db *gorm.DB) *SceneTextSearchRepositoryImpl {
+	return &SceneTextSearchRepositoryImpl{DB: db}
+}
+
+var sceneTextSortColumns = map[string]string{
+	"created_at": "scenes.created_at",
+	"title":      "scenes.title",
+	"duration":   "scenes.duration",
+	"view_count": "scenes.view_count",
+}
+
+func (r *SceneTextSearchRepositoryImpl) Search(q SceneTextQuery) ([]uint, int64, error) {
+	query, tsQuery := r.filtered(q)
+
+	var ids []uint
+	if q.AllIDs {
+		if err := query.Order("scenes.id ASC").Pluck("scenes.id", &ids).Error; err != nil {
+			return nil, 0, err
+		}
+		return ids, int64(len(ids)), nil
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []uint{}, 0, nil
+	}
+
+	direction := " ASC"
+	if q.SortDesc {
+		direction = " DESC"
+	}
+	if column, ok := sceneTextSortColumns[q.Sort]; ok {
+		query = query.Order(column + direction).Order("scenes.id DESC")
+	} else if tsQuery != "" {
+		// An expression replaces the whole ORDER BY, so it carries the tiebreaker too
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank(scenes.search_vector, to_tsquery('simple', ?)) DESC, scenes.id DESC",
+			Vars:               []interface{}{tsQuery},
+			WithoutParentheses: true,
+		}})
+	} else {
+		query = query.Order("scenes.created_at DESC").Order("scenes.id DESC")
+	}
+
+	if err := query.Offset(q.Offset).Limit(q.Limit).Pluck("scenes.id", &ids).Error; err != nil {
+		return nil, 0, err
+	}
+	return ids, total, nil
+}
+
+// sceneKeysetColumns are the sort columns SearchAfter can page by, with the
+// column's value as a number
+var sceneKeysetColumns = map[string]struct{ column, value string }{
+	"created_at": {"scenes.created_at", "EXTRACT(EPOCH FROM scenes.created_at)"},
+	"duration":   {"scenes.duration", "scenes.duration"},
+	"view_count": {"scenes.view_count", "scenes.view_count"},
+}
+
+func (r *SceneTextSearchRepositoryImpl) SearchAfter(q SceneTextQuery) ([]uint, *SceneSortKey, error) {
+	keyset, ok := sceneKeysetColumns[q.Sort]
+	if !ok {
+		return nil, nil, fmt.Errorf("scenes can't be paged by cursor when sorted by %q", q.Sort)
+	}
+	query, _ := r.filtered(q)
+
+	if q.After != nil {
+		var value any = q.After.Value
+		if q.Sort == "created_at" {
+			value = time.UnixMicro(int64(math.Round(q.After.Value * 1e6)))
+		}
+		query = query.Where(keysetAfter(keyset.column, "scenes.id", q.SortDesc), value, q.After.ID)
+	}
+
+	direction := " ASC"
+	if q.SortDesc {
+		direction = " DESC"
+	}
+	var rows []struct {
+		ID        uint
+		SortValue float64
+	}
+	err := query.
+		Select("scenes.id, " + keyset.value + " AS sort_value").
+		Order(keyset.column + direction).
+		Order("scenes.id" + direction).
+		Limit(q.Limit + 1).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, more := trimPage(rows, q.Limit)
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	if !more {
+		return ids, nil, nil
+	}
+	last := rows[len(rows)-1]
+	return ids, &SceneSortKey{Value: last.SortValue, ID: last.ID}, nil
+}
+
+// filtered applies the query's text search and filters, returning the tsquery
+// the text was turned into.
+func (r *SceneTextSearchRepositoryImpl) filtered(q SceneTextQuery) (*gorm.DB, string) {
+	query := r.DB.Model(&Scene{}).Where("scenes.trashed_at IS NULL")
+
+	tsQuery := prefixTSQuery(q.Query)
+	if tsQuery != "" {
+		// Title, studio, filename and description are in search_vector; actor
+		// and tag names and aliases are matched through their join tables
+		query = query.Where(`(scenes.search_vector @@ to_tsquery('simple', @q)
+			OR EXISTS (SELECT 1 FROM scene_actors sa JOIN actors a ON a.id = sa.actor_id
+				WHERE sa.scene_id = scenes.id AND a.deleted_at IS NULL
+				AND to_tsvector('simple', a.name || ' ' || array_to_string(a.aliases, ' ')) @@ to_tsquery('simple', @q))
+			OR EXISTS (SELECT 1 FROM scene_tags st JOIN tags t ON t.id = st.tag_id
+				WHERE st.scene_id = scenes.id
+				AND to_tsvector('simple', t.name || ' ' || array_to_string(t.aliases, ' ')) @@ to_tsquery('simple', @q)))`,
+			map[string]interface{}{"q": tsQuery})
+	}
+
+	for _, tagID := range q.TagIDs {
+		query = query.Where("EXISTS (SELECT 1 FROM scene_tags st WHERE st.scene_id = scenes.id AND st.tag_id = ?)", tagID)
+	}
+	if len(q.ExcludeTagIDs) > 0 {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM scene_tags st WHERE st.scene_id = scenes.id AND st.tag_id IN ?)", q.ExcludeTagIDs)
+	}
+	if len(q.Actors) > 0 {
+		query = query.Where(`EXISTS (SELECT 1 FROM scene_actors sa JOIN actors a ON a.id = sa.actor_id
+			WHERE sa.scene_id = scenes.id AND a.deleted_at IS NULL AND a.name IN ?)`, q.Actors)
+	}
+	if q.Studio != "" {
+		query = query.Where("scenes.studio IN ?", append([]string{q.Studio}, q.SubStudios...))
+	}
+	if q.MinDuration > 0 {
+		query = query.Where("scenes.duration >= ?", q.MinDuration)
+	}
+	if q.MaxDuration > 0 {
+		query = query.Where("scenes.duration <= ?", q.MaxDuration)
+	}
+	if q.MinHeight > 0 {
+		query = query.Where("scenes.height >= ?", q.MinHeight)
+	}
+	if q.MaxHeight > 0 {
+		query = query.Where("scenes.height <= ?", q.MaxHeight)
+	}
+	if q.CreatedAfter != nil {
+		query = query.Where("scenes.created_at >= ?", *q.CreatedAfter)
+	}
+	if q.CreatedBefore != nil {
+		query = query.Where("scenes.created_at <= ?", *q.CreatedBefore)
+	}
+	if q.ProcessingStatus != "" {
+		query = query.Where("scenes.processing_status = ?", q.ProcessingStatus)
+	}
+	if len(q.SceneIDs) > 0 {
+		query = query.Where("scenes.id IN ?", q.SceneIDs)
+	}
+	return query, tsQuery
+}
+
+// prefixTSQuery turns free text into a tsquery matching every word as a prefix,
+// e.g. "jane do" becomes "jane:* & do:*", so partial words match while typing.
+// Only letters and digits are kept, so the result is always valid tsquery syntax.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
@@ -0,0 +1,34 @@
+package data
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// SchemaRepository reads the migration state golang-migrate keeps in schema_migrations.
+type SchemaRepository interface {
+	GetVersion() (version uint, dirty bool, err error)
+}
+
+type SchemaRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewSchemaRepository(db *gorm.DB) *SchemaRepositoryImpl {
+	return &SchemaRepositoryImpl{DB: db}
+}
+
+// GetVersion returns the applied schema version and whether the last migration
+// failed halfway. Returns version 0 when no migration has run.
+func (r *SchemaRepositoryImpl) GetVersion() (uint, bool, error) {
+	var row struct {
+		Version uint
+		Dirty   bool
+	}
+	err := r.DB.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&row).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, err
+	}
+	return row.Version, row.Dirty, nil
+}
@@ -0,0 +1,70 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Search reindex checkpoint statuses. A checkpoint left "running" by a stopped
+// process is reported as interrupted.
+const (
+	ReindexStatusRunning   = "running"
+	ReindexStatusCompleted = "completed"
+	ReindexStatusFailed    = "failed"
+	ReindexStatusCancelled = "cancelled"
+)
+
+// SearchReindexCheckpoint tracks the last full search reindex. Scenes are
+// indexed in ID order, so LastSceneID is where a resumed rebuild continues.
+type SearchReindexCheckpoint struct {
+	ID          int        `gorm:"primaryKey" json:"-"`
+	Status      string     `gorm:"size:20;not null" json:"status"`
+	LastSceneID uint       `gorm:"not null;default:0" json:"last_scene_id"`
+	Indexed     int        `gorm:"not null;default:0" json:"indexed"`
+	Total       int        `gorm:"not null;default:0" json:"total"`
+	Error       string     `gorm:"type:text;not null;default:''" json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func (SearchReindexCheckpoint) TableName() string {
+	return "search_reindex_checkpoint"
+}
+
+type SearchReindexRepository interface {
+	// Get returns the checkpoint, or nil if no reindex has run yet
+	Get() (*SearchReindexCheckpoint, error)
+	Save(checkpoint *SearchReindexCheckpoint) error
+}
+
+type SearchReindexRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewSearchReindexRepository(db *gorm.DB) *SearchReindexRepositoryImpl {
+	return &SearchReindexRepositoryImpl{DB: db}
+}
+
+func (r *SearchReindexRepositoryImpl) Get() (*SearchReindexCheckpoint, error) {
+	var checkpoint SearchReindexCheckpoint
+	err := r.DB.First(&checkpoint).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (r *SearchReindexRepositoryImpl) Save(checkpoint *SearchReindexCheckpoint) error {
+	checkpoint.ID = 1
+	checkpoint.UpdatedAt = time.Now()
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		UpdateAll: true,
+	}).Create(checkpoint).Error
+}
@@ -0,0 +1,20 @@
+package data
+
+import "time"
+
+// UserSession is a session token issued at login, with the device it was issued
+// to. Only the SHA-256 of the token is stored.
+type UserSession struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	UserID     uint      `gorm:"not null;index" json:"-"`
+	TokenHash  string    `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	UserAgent  string    `gorm:"size:512;not null;default:''" json:"user_agent"`
+	IPAddress  string    `gorm:"size:64;not null;default:''" json:"ip_address"`
+	CreatedAt  time.Time `gorm:"not null" json:"created_at"`
+	LastSeenAt time.Time `gorm:"not null" json:"last_seen_at"`
+	ExpiresAt  time.Time `gorm:"not null" json:"expires_at"`
+}
+
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
@@ -0,0 +1,63 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserSessionRepository interface {
+	Create(session *UserSession) error
+	GetByID(id uint) (*UserSession, error)
+	ListActiveByUser(userID uint, now time.Time) ([]UserSession, error)
+	TouchLastSeen(tokenHash string, at time.Time) error
+	DeleteByTokenHash(tokenHash string) error
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+type UserSessionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewUserSessionRepository(db *gorm.DB) *UserSessionRepositoryImpl {
+	return &UserSessionRepositoryImpl{DB: db}
+}
+
+func (r *UserSessionRepositoryImpl) Create(session *UserSession) error {
+	return r.DB.Create(session).Error
+}
+
+func (r *UserSessionRepositoryImpl) GetByID(id uint) (*UserSession, error) {
+	var session UserSession
+	if err := r.DB.First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveByUser returns the user's unexpired sessions, most recently used first.
+func (r *UserSessionRepositoryImpl) ListActiveByUser(userID uint, now time.Time) ([]UserSession, error) {
+	var sessions []UserSession
+	err := r.DB.Where("user_id = ? AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC, id DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *UserSessionRepositoryImpl) TouchLastSeen(tokenHash string, at time.Time) error {
+	return r.DB.Model(&UserSession{}).
+		Where("token_hash = ?", tokenHash).
+		Update("last_seen_at", at).Error
+}
+
+func (r *UserSessionRepositoryImpl) DeleteByTokenHash(tokenHash string) error {
+	return r.DB.Where("token_hash = ?", tokenHash).Delete(&UserSession{}).Error
+}
+
+func (r *UserSessionRepositoryImpl) DeleteExpired(before time.Time) (int64, error) {
+	result := r.DB.Where("expires_at <= ?", before).Delete(&UserSession{})
+	return result.RowsAffected, result.Error
+}
@@ -31,6 +31,10 @@ type ShareLink struct {
 	ExpiresAt *time.Time `json:"expires_at"`
 	ViewCount int64      `gorm:"not null;default:0" json:"view_count"`
 	CreatedAt time.Time  `gorm:"not null" json:"created_at"`
+
+	// PasswordHash is the bcrypt hash of the link's password, empty when it has none
+	PasswordHash string `gorm:"size:255;not null;default:''" json:"-"`
+	HasPassword  bool   `gorm:"-" json:"has_password"`
 }
 
 func (ShareLink) TableName() string {
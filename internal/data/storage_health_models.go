@@ -0,0 +1,24 @@
+package data
+
+import "time"
+
+// Storage path health statuses
+const (
+	StorageHealthOnline  = "online"
+	StorageHealthOffline = "offline"
+)
+
+// StoragePathHealthSample is the result of one health check of a storage path.
+type StoragePathHealthSample struct {
+	ID            uint      `gorm:"primarykey" json:"-"`
+	StoragePathID uint      `gorm:"not null" json:"storage_path_id"`
+	CheckedAt     time.Time `gorm:"not null" json:"checked_at"`
+	Status        string    `gorm:"size:20;not null" json:"status"`
+	Error         string    `gorm:"not null;default:''" json:"error,omitempty"` // why the path is offline
+	FreeBytes     *int64    `json:"free_bytes"`                                 // nil when the filesystem could not be queried
+	TotalBytes    *int64    `json:"total_bytes"`
+}
+
+func (StoragePathHealthSample) TableName() string {
+	return "storage_path_health_samples"
+}
@@ -0,0 +1,60 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type StorageHealthRepository interface {
+	Create(sample *StoragePathHealthSample) error
+	// GetLatest returns the most recent sample of every storage path that has one
+	GetLatest() ([]StoragePathHealthSample, error)
+	// ListByStoragePath returns a storage path's samples since the given time, oldest first
+	ListByStoragePath(storagePathID uint, since time.Time) ([]StoragePathHealthSample, error)
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type StorageHealthRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewStorageHealthRepository(db *gorm.DB) *StorageHealthRepositoryImpl {
+	return &StorageHealthRepositoryImpl{DB: db}
+}
+
+func (r *StorageHealthRepositoryImpl) Create(sample *StoragePathHealthSample) error {
+	return r.DB.Create(sample).Error
+}
+
+func (r *StorageHealthRepositoryImpl) GetLatest() ([]StoragePathHealthSample, error) {
+	var samples []StoragePathHealthSample
+	err := r.DB.Raw(`
+		SELECT DISTINCT ON (storage_path_id) *
+		FROM storage_path_health_samples
+		ORDER BY storage_path_id, checked_at DESC
+	`).Scan(&samples).Error
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+func (r *StorageHealthRepositoryImpl) ListByStoragePath(storagePathID uint, since time.Time) ([]StoragePathHealthSample, error) {
+	var samples []StoragePathHealthSample
+	err := r.DB.
+		Where("storage_path_id = ? AND checked_at >= ?", storagePathID, since).
+		Order("checked_at ASC").
+		Find(&samples).Error
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+func (r *StorageHealthRepositoryImpl) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.DB.Where("checked_at < ?", before).Delete(&StoragePathHealthSample{})
+	return result.RowsAffected, result.Error
+}
+
+var _ StorageHealthRepository = (*StorageHealthRepositoryImpl)(nil)
@@ -3,16 +3,20 @@ package data
 import (
 	"time"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
 type StoragePath struct {
-	ID        uint      `gorm:"primarykey" json:"id"`
-	Name      string    `gorm:"not null;size:100" json:"name"`
-	Path      string    `gorm:"not null;uniqueIndex;size:500" json:"path"`
-	IsDefault bool      `gorm:"not null;default:false" json:"is_default"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                  uint           `gorm:"primarykey" json:"id"`
+	Name                string         `gorm:"not null;size:100" json:"name"`
+	Path                string         `gorm:"not null;uniqueIndex;size:500" json:"path"`
+	IsDefault           bool           `gorm:"not null;default:false" json:"is_default"`
+	ScanSchedule        *string        `gorm:"size:100" json:"scan_schedule"`
+	ScanScheduleEnabled bool           `gorm:"not null;default:false" json:"scan_schedule_enabled"`
+	ExcludePatterns     pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"exclude_patterns"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
 }
 
 func (StoragePath) TableName() string {
@@ -7,6 +7,7 @@ import (
 type StudioRepository interface {
 	Create(studio *Studio) error
 	GetByID(id uint) (*Studio, error)
+	GetByIDs(ids []uint) ([]Studio, error)
 	GetByUUID(uuid string) (*Studio, error)
 	GetByName(name string) (*Studio, error)
 	Update(studio *Studio) error
@@ -17,10 +18,15 @@ type StudioRepository interface {
 	// Scene associations (one-to-many: scene has one studio)
 	GetSceneStudio(sceneID uint) (*Studio, error)
 	SetSceneStudio(sceneID uint, studioID *uint) error
-	GetStudioScenes(studioID uint, page, limit int) ([]Scene, int64, error)
+	GetStudioScenes(studioID uint, restriction *ContentRestriction, page, limit int) ([]Scene, int64, error)
+	GetScenesByStudioIDs(studioIDs []uint, restriction *ContentRestriction, page, limit int) ([]Scene, int64, error)
 	GetStudioSceneIDs(studioID uint, limit int) ([]uint, error)
 	GetSceneCount(studioID uint) (int64, error)
 
+	// Hierarchy (parent_id and network_id)
+	GetChildren(studioID uint) ([]StudioWithCount, error)
+	GetDescendantIDs(studioID uint) ([]uint, error)
+
 	// Bulk operations
 	BulkSetStudioForScenes(sceneIDs []uint, studioID *uint) error
 }
@@ -45,6 +51,17 @@ func (r *StudioRepositoryImpl) GetByID(id uint) (*Studio, error) {
 	return &studio, nil
 }
 
+func (r *StudioRepositoryImpl) GetByIDs(ids []uint) ([]Studio, error) {
+	if len(ids) == 0 {
+		return []Studio{}, nil
+	}
+	var studios []Studio
+	if err := r.DB.Where("id IN ?", ids).Find(&studios).Error; err != nil {
+		return nil, err
+	}
+	return studios, nil
+}
+
 func (r *StudioRepositoryImpl) GetByUUID(uuid string) (*Studio, error) {
 	var studio Studio
 	if err := r.DB.Where("uuid = ?", uuid).First(&studio).Error; err != nil {
@@ -176,23 +193,23 @@ func (r *StudioRepositoryImpl) SetSceneStudio(sceneID uint, studioID *uint) erro
 	return r.DB.Model(&Scene{}).Where("id = ?", sceneID).Update("studio_id", studioID).Error
 }
 
-func (r *StudioRepositoryImpl) GetStudioScenes(studioID uint, page, limit int) ([]Scene, int64, error) {
+func (r *StudioRepositoryImpl) GetStudioScenes(studioID uint, restriction *ContentRestriction, page, limit int) ([]Scene, int64, error) {
 	var scenes []Scene
 	var total int64
 
 	offset := (page - 1) * limit
 
-	countQuery := r.DB.
+	countQuery := restrictScenes(r.DB.
 		Model(&Scene{}).
 		Where("studio_id = ?", studioID).
-		Where("deleted_at IS NULL")
+		Where("deleted_at IS NULL"), restriction)
 	if err := countQuery.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.DB.
+	err := restrictScenes(r.DB.
 		Where("studio_id = ?", studioID).
-		Where("deleted_at IS NULL").
+		Where("deleted_at IS NULL"), restriction).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -204,6 +221,73 @@ func (r *StudioRepositoryImpl) GetStudioScenes(studioID uint, page, limit int) (
 	return scenes, total, nil
 }
 
+// GetScenesByStudioIDs returns the scenes of any of the studios, newest first
+func (r *StudioRepositoryImpl) GetScenesByStudioIDs(studioIDs []uint, restriction *ContentRestriction, page, limit int) ([]Scene, int64, error) {
+	var scenes []Scene
+	var total int64
+
+	offset := (page - 1) * limit
+
+	countQuery := restrictScenes(r.DB.
+		Model(&Scene{}).
+		Where("studio_id IN ?", studioIDs).
+		Where("deleted_at IS NULL"), restriction)
+	if err := countQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := restrictScenes(r.DB.
+		Where("studio_id IN ?", studioIDs).
+		Where("deleted_at IS NULL"), restriction).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&scenes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return scenes, total, nil
+}
+
+// GetChildren returns the studios whose parent or network is the studio, with
+// their own scene counts
+func (r *StudioRepositoryImpl) GetChildren(studioID uint) ([]StudioWithCount, error) {
+	var studios []StudioWithCount
+	err := r.DB.
+		Table("studios").
+		Select("studios.*, COALESCE(COUNT(scenes.id), 0) as scene_count").
+		Joins("LEFT JOIN scenes ON scenes.studio_id = studios.id AND scenes.deleted_at IS NULL").
+		Where("studios.deleted_at IS NULL").
+		Where("studios.parent_id = ? OR studios.network_id = ?", studioID, studioID).
+		Group("studios.id").
+		Order("studios.name ASC").
+		Find(&studios).Error
+	if err != nil {
+		return nil, err
+	}
+	return studios, nil
+}
+
+// GetDescendantIDs returns the IDs of every studio under the studio, through
+// parent_id or network_id at any depth
+func (r *StudioRepositoryImpl) GetDescendantIDs(studioID uint) ([]uint, error) {
+	var ids []uint
+	// UNION rather than UNION ALL stops at a studio already seen, so a cycle
+	// in the data cannot loop
+	err := r.DB.Raw(`WITH RECURSIVE tree(id) AS (
+			SELECT id FROM studios WHERE (parent_id = @id OR network_id = @id) AND deleted_at IS NULL
+			UNION
+			SELECT s.id FROM studios s JOIN tree t ON s.parent_id = t.id OR s.network_id = t.id
+			WHERE s.deleted_at IS NULL
+		)
+		SELECT id FROM tree WHERE id <> @id ORDER BY id`, map[string]any{"id": studioID}).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 func (r *StudioRepositoryImpl) GetStudioSceneIDs(studioID uint, limit int) ([]uint, error) {
 	var ids []uint
 	err := r.DB.Model(&Scene{}).
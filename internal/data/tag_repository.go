@@ -1,6 +1,10 @@
 package data
 
 import (
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -24,6 +28,16 @@ type TagRepository interface {
 	SetSceneTags(sceneID uint, tagIDs []uint) error
 	GetSceneIDsByTag(tagID uint, limit int) ([]uint, error)
 
+	// Rename, merge and cleanup
+	Update(tag *Tag) error
+	GetAllSceneIDs(tagIDs []uint) ([]uint, error)
+	Merge(target *Tag, sourceIDs []uint) error
+	DeleteUnused() ([]Tag, error)
+
+	// Usage per month: scenes added and userID's watches
+	GetMonthlyUsage(tagIDs []uint, userID uint, since time.Time) ([]UsageCount, error)
+	GetMostUsedIDs(userID uint, since time.Time, limit int) ([]uint, error)
+
 	// Bulk operations
 	BulkAddTagsToScenes(sceneIDs []uint, tagIDs []uint) error
 	BulkRemoveTagsFromScenes(sceneIDs []uint, tagIDs []uint) error
@@ -81,23 +95,54 @@ func (r *TagRepositoryImpl) GetByIDs(ids []uint) ([]Tag, error) {
 	return tags, nil
 }
 
+// GetByNames returns the tags named, or formerly named, any of names.
 func (r *TagRepositoryImpl) GetByNames(names []string) ([]Tag, error) {
 	var tags []Tag
 	if len(names) == 0 {
 		return tags, nil
 	}
-	if err := r.DB.Where("name IN ?", names).Find(&tags).Error; err != nil {
+	if err := r.DB.Where("name IN ? OR aliases && ?", names, pq.StringArray(names)).Find(&tags).Error; err != nil {
 		return nil, err
 	}
 	return tags, nil
 }
 
+// TagIDsByName maps the names and aliases of tags to their IDs, a tag's name
+// taking precedence over another tag's alias.
+func TagIDsByName(tags []Tag) map[string]uint {
+	byName := make(map[string]uint, len(tags))
+	for _, tag := range tags {
+		for _, alias := range tag.Aliases {
+			if _, ok := byName[alias]; !ok {
+				byName[alias] = tag.ID
+			}
+		}
+	}
+	for _, tag := range tags {
+		byName[tag.Name] = tag.ID
+	}
+	return byName
+}
+
+// findTagByName returns the tag named name, or else the tag formerly named so.
+func findTagByName(db *gorm.DB, name string) (*Tag, error) {
+	var tag Tag
+	err := db.Where("name = ?", name).First(&tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = db.Where("? = ANY(aliases)", name).First(&tag).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
 func (r *TagRepositoryImpl) GetIDsByNames(names []string) ([]uint, error) {
 	if len(names) == 0 {
 		return []uint{}, nil
 	}
 	var ids []uint
-	if err := r.DB.Model(&Tag{}).Where("name IN ?", names).Pluck("id", &ids).Error; err != nil {
+	if err := r.DB.Model(&Tag{}).Where("name IN ? OR aliases && ?", names, pq.StringArray(names)).Pluck("id", &ids).Error; err != nil {
 		return nil, err
 	}
 	return ids, nil
@@ -118,6 +163,99 @@ func (r *TagRepositoryImpl) Delete(id uint) error {
 	return nil
 }
 
+func (r *TagRepositoryImpl) Update(tag *Tag) error {
+	return r.DB.Save(tag).Error
+}
+
+// GetAllSceneIDs returns the live scenes that have any of the tags.
+func (r *TagRepositoryImpl) GetAllSceneIDs(tagIDs []uint) ([]uint, error) {
+	if len(tagIDs) == 0 {
+		return []uint{}, nil
+	}
+	var sceneIDs []uint
+	err := r.DB.
+		Table("scene_tags").
+		Distinct("scene_tags.scene_id").
+		Joins("JOIN scenes ON scenes.id = scene_tags.scene_id").
+		Where("scene_tags.tag_id IN ?", tagIDs).
+		Where("scenes.deleted_at IS NULL").
+		Pluck("scene_tags.scene_id", &sceneIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return sceneIDs, nil
+}
+
+// Merge saves target and moves everything tagged with the sources to it
+// (scenes, markers, marker label defaults, playlists, folder rules and role
+// restrictions), then deletes the sources, in one transaction.
+func (r *TagRepositoryImpl) Merge(target *Tag, sourceIDs []uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(target).Error; err != nil {
+			return err
+		}
+
+		moves := []string{
+			`INSERT INTO scene_tags (scene_id, tag_id)
+				SELECT DISTINCT scene_id, ? FROM scene_tags WHERE tag_id IN ?
+				ON CONFLICT DO NOTHING`,
+			`INSERT INTO marker_tags (marker_id, tag_id, is_from_label, created_at)
+				SELECT DISTINCT ON (marker_id) marker_id, ?, is_from_label, created_at FROM marker_tags
+				WHERE tag_id IN ? ORDER BY marker_id, is_from_label
+				ON CONFLICT DO NOTHING`,
+			`INSERT INTO marker_label_tags (user_id, label, tag_id, created_at)
+				SELECT DISTINCT ON (user_id, label) user_id, label, ?, created_at FROM marker_label_tags
+				WHERE tag_id IN ? ORDER BY user_id, label
+				ON CONFLICT DO NOTHING`,
+			`INSERT INTO playlist_tags (playlist_id, tag_id)
+				SELECT DISTINCT playlist_id, ? FROM playlist_tags WHERE tag_id IN ?
+				ON CONFLICT DO NOTHING`,
+		}
+		for _, move := range moves {
+			if err := tx.Exec(move, target.ID, sourceIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		// Tag lists holding a source hold the target instead, once
+		for _, table := range []string{"folder_rules", "role_content_restrictions"} {
+			for _, sourceID := range sourceIDs {
+				if err := tx.Exec(`UPDATE `+table+` SET tag_ids = array_replace(tag_ids, ?::bigint, ?::bigint)
+					WHERE ?::bigint = ANY(tag_ids)`, sourceID, target.ID, sourceID).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec(`UPDATE `+table+` SET tag_ids = ARRAY(SELECT DISTINCT unnest(tag_ids))
+				WHERE ?::bigint = ANY(tag_ids)`, target.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		// Associations still on the sources cascade
+		return tx.Delete(&Tag{}, sourceIDs).Error
+	})
+}
+
+// DeleteUnused deletes the tags nothing uses: no live scene, marker, marker
+// label default or playlist has them, and no folder rule or role restriction
+// lists them. It returns the deleted tags.
+func (r *TagRepositoryImpl) DeleteUnused() ([]Tag, error) {
+	var tags []Tag
+	err := r.DB.Raw(`DELETE FROM tags t
+		WHERE NOT EXISTS (SELECT 1 FROM scene_tags st JOIN scenes s ON s.id = st.scene_id
+			WHERE st.tag_id = t.id AND s.deleted_at IS NULL)
+		AND NOT EXISTS (SELECT 1 FROM marker_tags mt WHERE mt.tag_id = t.id)
+		AND NOT EXISTS (SELECT 1 FROM marker_label_tags mlt WHERE mlt.tag_id = t.id)
+		AND NOT EXISTS (SELECT 1 FROM playlist_tags pt WHERE pt.tag_id = t.id)
+		AND NOT EXISTS (SELECT 1 FROM folder_rules fr WHERE t.id = ANY(fr.tag_ids))
+		AND NOT EXISTS (SELECT 1 FROM role_content_restrictions rcr WHERE t.id = ANY(rcr.tag_ids))
+		RETURNING t.*`).Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
 func (r *TagRepositoryImpl) GetSceneTags(sceneID uint) ([]Tag, error) {
 	var tags []Tag
 	err := r.DB.
@@ -268,3 +406,11 @@ func (r *TagRepositoryImpl) BulkReplaceTagsForScenes(sceneIDs []uint, tagIDs []u
 		return tx.Create(&sceneTags).Error
 	})
 }
+
+func (r *TagRepositoryImpl) GetMonthlyUsage(tagIDs []uint, userID uint, since time.Time) ([]UsageCount, error) {
+	return tagUsageSource.monthlyUsage(r.DB, tagIDs, userID, since)
+}
+
+func (r *TagRepositoryImpl) GetMostUsedIDs(userID uint, since time.Time, limit int) ([]uint, error) {
+	return tagUsageSource.mostUsedIDs(r.DB, userID, since, limit)
+}
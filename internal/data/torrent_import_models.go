@@ -0,0 +1,29 @@
+package data
+
+import "time"
+
+// Torrent import statuses
+const (
+	TorrentImportImported = "imported"
+	TorrentImportFailed   = "failed"
+)
+
+// TorrentImport is a file of a completed torrent imported from the torrent
+// client, linking the torrent to the scene created from it.
+type TorrentImport struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	Client        string    `gorm:"size:20;not null" json:"client"`
+	TorrentHash   string    `gorm:"size:64;not null" json:"torrent_hash"`
+	TorrentName   string    `gorm:"type:text;not null;default:''" json:"torrent_name"`
+	FilePath      string    `gorm:"type:text;not null" json:"file_path"`              // path of the file on the client's side
+	Destination   string    `gorm:"type:text;not null;default:''" json:"destination"` // path of the file in the storage path
+	StoragePathID *uint     `json:"storage_path_id"`
+	SceneID       *uint     `json:"scene_id"`
+	Status        string    `gorm:"size:20;not null" json:"status"`
+	Error         string    `gorm:"type:text;not null;default:''" json:"error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (TorrentImport) TableName() string {
+	return "torrent_imports"
+}
@@ -0,0 +1,70 @@
+package data
+
+import "gorm.io/gorm"
+
+type TorrentImportRepository interface {
+	Create(item *TorrentImport) error
+	GetByID(id uint) (*TorrentImport, error)
+	// List returns the imports newest first, only those of a scene when
+	// sceneID is set
+	List(page, limit int, sceneID *uint) ([]TorrentImport, int64, error)
+	// ListByTorrentHashes returns the imports of the given torrents, to skip
+	// the files already handled
+	ListByTorrentHashes(hashes []string) ([]TorrentImport, error)
+	Delete(id uint) error
+}
+
+type TorrentImportRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewTorrentImportRepository(db *gorm.DB) *TorrentImportRepositoryImpl {
+	return &TorrentImportRepositoryImpl{DB: db}
+}
+
+func (r *TorrentImportRepositoryImpl) Create(item *TorrentImport) error {
+	return r.DB.Create(item).Error
+}
+
+func (r *TorrentImportRepositoryImpl) GetByID(id uint) (*TorrentImport, error) {
+	var item TorrentImport
+	if err := r.DB.First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *TorrentImportRepositoryImpl) List(page, limit int, sceneID *uint) ([]TorrentImport, int64, error) {
+	query := r.DB.Model(&TorrentImport{})
+	if sceneID != nil {
+		query = query.Where("scene_id = ?", *sceneID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var items []TorrentImport
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *TorrentImportRepositoryImpl) ListByTorrentHashes(hashes []string) ([]TorrentImport, error) {
+	var items []TorrentImport
+	if len(hashes) == 0 {
+		return items, nil
+	}
+	if err := r.DB.Where("torrent_hash IN ?", hashes).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *TorrentImportRepositoryImpl) Delete(id uint) error {
+	return r.DB.Delete(&TorrentImport{}, id).Error
+}
+
+var _ TorrentImportRepository = (*TorrentImportRepositoryImpl)(nil)
@@ -0,0 +1,25 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadSession is a chunked upload in progress. The bytes received so far are
+// in a temporary file, whose size is the offset the next chunk must start at.
+type UploadSession struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	UUID      uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"uuid"`
+	UserID    uint      `gorm:"not null" json:"-"`
+	Filename  string    `gorm:"size:255;not null" json:"filename"`
+	Title     string    `gorm:"size:255;not null;default:''" json:"title"`
+	Size      int64     `gorm:"not null" json:"size"`
+	Offset    int64     `gorm:"-" json:"offset"` // bytes received, read from the temporary file
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"` // pushed back by every chunk
+}
+
+func (UploadSession) TableName() string {
+	return "upload_sessions"
+}
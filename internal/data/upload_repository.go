@@ -0,0 +1,55 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UploadSessionRepository interface {
+	Create(session *UploadSession) error
+	GetByUUID(id uuid.UUID) (*UploadSession, error)
+	UpdateExpiry(id uint, expiresAt time.Time) error
+	Delete(id uint) error
+	// ListExpired returns the sessions that expired before the given time
+	ListExpired(before time.Time) ([]UploadSession, error)
+}
+
+type UploadSessionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewUploadSessionRepository(db *gorm.DB) *UploadSessionRepositoryImpl {
+	return &UploadSessionRepositoryImpl{DB: db}
+}
+
+func (r *UploadSessionRepositoryImpl) Create(session *UploadSession) error {
+	return r.DB.Create(session).Error
+}
+
+func (r *UploadSessionRepositoryImpl) GetByUUID(id uuid.UUID) (*UploadSession, error) {
+	var session UploadSession
+	if err := r.DB.Where("uuid = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *UploadSessionRepositoryImpl) UpdateExpiry(id uint, expiresAt time.Time) error {
+	return r.DB.Model(&UploadSession{}).Where("id = ?", id).Update("expires_at", expiresAt).Error
+}
+
+func (r *UploadSessionRepositoryImpl) Delete(id uint) error {
+	return r.DB.Delete(&UploadSession{}, id).Error
+}
+
+func (r *UploadSessionRepositoryImpl) ListExpired(before time.Time) ([]UploadSession, error) {
+	var sessions []UploadSession
+	if err := r.DB.Where("expires_at < ?", before).Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+var _ UploadSessionRepository = (*UploadSessionRepositoryImpl)(nil)
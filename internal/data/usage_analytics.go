@@ -0,0 +1,113 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UsageSeries is a tag's or actor's activity per calendar month (UTC), for
+// trend charts. Months without activity are included with zero counts.
+type UsageSeries struct {
+	ID     uint         `json:"id"`
+	UUID   string       `json:"uuid,omitempty"`
+	Name   string       `json:"name"`
+	Points []UsagePoint `json:"points"`
+}
+
+// UsagePoint is one month of a UsageSeries.
+type UsagePoint struct {
+	Month       time.Time `json:"month"` // first day of the month
+	ScenesAdded int64     `json:"scenes_added"`
+	Watches     int64     `json:"watches"` // watch sessions of the requesting user
+}
+
+// UsageCount is a tag's or actor's activity in one month, as aggregated by
+// the repositories. Months without activity have no row.
+type UsageCount struct {
+	ID          uint
+	Month       time.Time
+	ScenesAdded int64
+	Watches     int64
+}
+
+// usageSource is the table linking scenes to the tags or actors usage is
+// counted for, and its column holding their IDs.
+type usageSource struct {
+	table  string
+	column string
+}
+
+var (
+	tagUsageSource   = usageSource{table: "scene_tags", column: "tag_id"}
+	actorUsageSource = usageSource{table: "scene_actors", column: "actor_id"}
+)
+
+// events selects one row per (id, month) with the live scenes added to the
+// library and the user's watch sessions of scenes linked to each ID since the
+// given time. Both halves bind filter (empty, or an "AND ..." on the linked
+// column), userID and since in that order, so args must be built by
+// eventArgs.
+func (u usageSource) events(filter string) string {
+	return fmt.Sprintf(`
+		SELECT l.%[2]s AS id, date_trunc('month', s.created_at AT TIME ZONE 'UTC') AS month,
+			COUNT(*) AS scenes_added, 0 AS watches
+		FROM %[1]s l
+		JOIN scenes s ON s.id = l.scene_id
+		WHERE s.deleted_at IS NULL AND s.created_at >= ? %[3]s
+		GROUP BY 1, 2
+		UNION ALL
+		SELECT l.%[2]s, date_trunc('month', w.watched_at AT TIME ZONE 'UTC'),
+			0, COUNT(*)
+		FROM user_scene_watches w
+		JOIN %[1]s l ON l.scene_id = w.scene_id
+		WHERE w.user_id = ? AND w.watched_at >= ? %[3]s
+		GROUP BY 1, 2`, u.table, u.column, filter)
+}
+
+func (u usageSource) eventArgs(ids []uint, userID uint, since time.Time) []any {
+	if len(ids) == 0 {
+		return []any{since, userID, since}
+	}
+	return []any{since, ids, userID, since, ids}
+}
+
+// monthlyUsage returns the usage of the given IDs per month since the given
+// time, ordered by ID and month.
+func (u usageSource) monthlyUsage(db *gorm.DB, ids []uint, userID uint, since time.Time) ([]UsageCount, error) {
+	if len(ids) == 0 {
+		return []UsageCount{}, nil
+	}
+	filter := fmt.Sprintf("AND l.%s IN ?", u.column)
+	var counts []UsageCount
+	err := db.Raw(`
+		SELECT id, month, SUM(scenes_added) AS scenes_added, SUM(watches) AS watches
+		FROM (`+u.events(filter)+`) events
+		GROUP BY id, month
+		ORDER BY id, month`,
+		u.eventArgs(ids, userID, since)...,
+	).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// mostUsedIDs returns the IDs with the most scenes added and watches since
+// the given time.
+func (u usageSource) mostUsedIDs(db *gorm.DB, userID uint, since time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := db.Raw(`
+		SELECT id
+		FROM (`+u.events("")+`) events
+		GROUP BY id
+		ORDER BY SUM(scenes_added) + SUM(watches) DESC, id
+		LIMIT ?`,
+		append(u.eventArgs(nil, userID, since), limit)...,
+	).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type User struct {
@@ -15,6 +17,13 @@ type User struct {
 	Password    string     `gorm:"not null" json:"-"`
 	Role        string     `gorm:"not null;default:'user'" json:"role"`
 	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	// PrivacyPinHash is empty when the privacy lock is disabled
+	PrivacyPinHash     string `gorm:"not null;default:''" json:"-"`
+	PrivacyLockMinutes int    `gorm:"not null;default:15" json:"-"`
+	// TOTPSecret is set during enrollment and kept while two-factor auth is enabled
+	TOTPSecret        string         `gorm:"column:totp_secret;not null;default:''" json:"-"`
+	TOTPEnabled       bool           `gorm:"column:totp_enabled;not null;default:false" json:"two_factor_enabled"`
+	TOTPRecoveryCodes pq.StringArray `gorm:"column:totp_recovery_codes;type:text[]" json:"-"` // SHA-256 hashes of unused codes
 }
 
 type Role struct {
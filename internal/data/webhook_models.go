@@ -0,0 +1,99 @@
+package data
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Webhook is an admin-configured URL that domain events are POSTed to. Secret
+// signs every request; it is only shown when the webhook is created or its
+// secret is rotated.
+type Webhook struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Name      string         `gorm:"size:100;not null" json:"name"`
+	URL       string         `gorm:"size:2048;not null" json:"url"`
+	Secret    string         `gorm:"size:128;not null" json:"-"`
+	Events    pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"events"` // empty subscribes to every event
+	Enabled   bool           `gorm:"not null;default:true" json:"enabled"`
+	CreatedBy *uint          `json:"created_by"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Subscribes reports whether the webhook wants events of the given type.
+func (w *Webhook) Subscribes(eventType string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, eventType)
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent, or waiting to be sent, to a webhook. The
+// response fields describe the latest attempt.
+type WebhookDelivery struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	WebhookID      uint           `gorm:"not null" json:"webhook_id"`
+	EventID        string         `gorm:"size:36;not null" json:"event_id"`
+	EventType      string         `gorm:"size:100;not null" json:"event_type"`
+	Payload        WebhookPayload `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string         `gorm:"size:20;not null;default:'pending'" json:"status"`
+	Attempts       int            `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int            `gorm:"not null;default:0" json:"response_status"` // 0 when no response was received
+	ResponseBody   string         `gorm:"type:text;not null;default:''" json:"response_body"`
+	Error          string         `gorm:"type:text;not null;default:''" json:"error"`
+	DurationMs     int            `gorm:"not null;default:0" json:"duration_ms"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time     `json:"last_attempt_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookPayload is the JSON body of a delivery, kept as sent.
+type WebhookPayload json.RawMessage
+
+// MarshalJSON embeds the payload as is
+func (p WebhookPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("null"), nil
+	}
+	return p, nil
+}
+
+// Value implements the driver.Valuer interface for JSONB storage
+func (p WebhookPayload) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return []byte("{}"), nil
+	}
+	return []byte(p), nil
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval
+func (p *WebhookPayload) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		*p = append(WebhookPayload(nil), v...)
+	case string:
+		*p = WebhookPayload(v)
+	default:
+		return errors.New("failed to scan WebhookPayload: expected []byte")
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package data
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	Create(webhook *Webhook) error
+	GetByID(id uint) (*Webhook, error)
+	GetByIDs(ids []uint) ([]Webhook, error)
+	List() ([]Webhook, error)
+	ListEnabled() ([]Webhook, error)
+	Update(webhook *Webhook) error
+	Delete(id uint) error
+
+	CreateDeliveries(deliveries []WebhookDelivery) error
+	GetDelivery(id uint) (*WebhookDelivery, error)
+	ListDeliveries(webhookID uint, status string, page, limit int) ([]WebhookDelivery, int64, error)
+	// ListDueDeliveries returns pending deliveries whose next attempt is at or before now, oldest first
+	ListDueDeliveries(now time.Time, limit int) ([]WebhookDelivery, error)
+	UpdateDelivery(delivery *WebhookDelivery) error
+	DeleteDeliveriesBefore(before time.Time) (int64, error)
+}
+
+type WebhookRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepositoryImpl {
+	return &WebhookRepositoryImpl{DB: db}
+}
+
+func (r *WebhookRepositoryImpl) Create(webhook *Webhook) error {
+	return r.DB.Create(webhook).Error
+}
+
+func (r *WebhookRepositoryImpl) GetByID(id uint) (*Webhook, error) {
+	var webhook Webhook
+	if err := r.DB.First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *WebhookRepositoryImpl) GetByIDs(ids []uint) ([]Webhook, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var webhooks []Webhook
+	err := r.DB.Where("id IN ?", ids).Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *WebhookRepositoryImpl) List() ([]Webhook, error) {
+	var webhooks []Webhook
+	err := r.DB.Order("created_at ASC, id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *WebhookRepositoryImpl) ListEnabled() ([]Webhook, error) {
+	var webhooks []Webhook
+	err := r.DB.Where("enabled = ?", true).Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *WebhookRepositoryImpl) Update(webhook *Webhook) error {
+	return r.DB.Save(webhook).Error
+}
+
+func (r *WebhookRepositoryImpl) Delete(id uint) error {
+	result := r.DB.Delete(&Webhook{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepositoryImpl) CreateDeliveries(deliveries []WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.DB.Create(&deliveries).Error
+}
+
+func (r *WebhookRepositoryImpl) GetDelivery(id uint) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	if err := r.DB.First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *WebhookRepositoryImpl) ListDeliveries(webhookID uint, status string, page, limit int) ([]WebhookDelivery, int64, error) {
+	query := r.DB.Model(&WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []WebhookDelivery
+	err := query.Order("created_at DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *WebhookRepositoryImpl) ListDueDeliveries(now time.Time, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := r.DB.Where("status = ? AND next_attempt_at <= ?", WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *WebhookRepositoryImpl) UpdateDelivery(delivery *WebhookDelivery) error {
+	return r.DB.Save(delivery).Error
+}
+
+func (r *WebhookRepositoryImpl) DeleteDeliveriesBefore(before time.Time) (int64, error) {
+	result := r.DB.Where("created_at < ? AND status <> ?", before, WebhookDeliveryPending).Delete(&WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
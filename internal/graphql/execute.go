@@ -0,0 +1,689 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response holds the data of an executed request and any errors. Data is
+// omitted when the request couldn't be executed at all.
+type Response struct {
+	Data   any      `json:"data,omitempty"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Error is a request or field error. Path lists the response keys leading to
+// the failed field; list indices are left out because list items are resolved
+// together.
+type Error struct {
+	Message string   `json:"message"`
+	Path    []string `json:"path,omitempty"`
+}
+
+func requestError(format string, args ...any) *Response {
+	return &Response{Errors: []*Error{{Message: fmt.Sprintf(format, args...)}}}
+}
+
+// Execute runs a query against the schema.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return requestError("%s", err.Error())
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return requestError("%s", err.Error())
+	}
+	if op.Type != "query" {
+		return requestError("only queries are supported, not %ss", op.Type)
+	}
+
+	e := &executor{ctx: ctx, schema: s, doc: doc}
+	if err := e.setVariables(op, req.Variables); err != nil {
+		return requestError("%s", err.Error())
+	}
+	if err := e.validate(s.Query, op.Selections, 1, map[string]bool{}); err != nil {
+		return requestError("%s", err.Error())
+	}
+
+	data := e.executeObjects(s.Query, []any{nil}, op.Selections, nil)[0]
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document has several operations")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+type executor struct {
+	ctx       context.Context
+	schema    *Schema
+	doc       *Document
+	variables map[string]any
+
+	mu     sync.Mutex
+	errors []*Error
+}
+
+func (e *executor) addError(path []string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: append([]string(nil), path...)})
+}
+
+// setVariables checks the provided variables against their definitions. Values
+// are kept as given and coerced with the arguments they are used in.
+func (e *executor) setVariables(op *Operation, provided map[string]any) error {
+	e.variables = map[string]any{}
+	for _, def := range op.Variables {
+		t, err := e.inputType(def.Type)
+		if err != nil {
+			return err
+		}
+		value, ok := provided[def.Name]
+		if !ok {
+			value = def.Default
+		}
+		if _, err := coerceInput(value, t); err != nil {
+			return fmt.Errorf("variable $%s: %w", def.Name, err)
+		}
+		if ok || def.Default != nil {
+			e.variables[def.Name] = value
+		}
+	}
+	return nil
+}
+
+func (e *executor) inputType(ref *TypeRef) (Type, error) {
+	var t Type
+	if ref.Elem != nil {
+		elem, err := e.inputType(ref.Elem)
+		if err != nil {
+			return nil, err
+		}
+		t = ListOf(elem)
+	} else {
+		named, ok := e.schema.types[ref.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %q", ref.Name)
+		}
+		if _, isObject := named.(*Object); isObject {
+			return nil, fmt.Errorf("type %q can't be used as an input", ref.Name)
+		}
+		t = named
+	}
+	if ref.NonNull {
+		t = NonNullOf(t)
+	}
+	return t, nil
+}
+
+// validate checks selections against the schema before anything is resolved:
+// fields and arguments exist, leaf fields have no selections and objects do,
+// fragments exist, apply to the type and don't spread themselves, and nesting
+// stays within MaxDepth.
+func (e *executor) validate(obj *Object, selections []Selection, depth int, spreading map[string]bool) error {
+	for _, selection := range selections {
+		switch node := selection.(type) {
+		case *FieldNode:
+			if node.Name == "__typename" {
+				if len(node.Selections) > 0 {
+					return fmt.Errorf("field \"__typename\" can't have a selection")
+				}
+				continue
+			}
+			field := obj.Field(node.Name)
+			if field == nil {
+				return fmt.Errorf("cannot query field %q on type %q", node.Name, obj.Name)
+			}
+			if _, err := e.arguments(field, node); err != nil {
+				return err
+			}
+			child, isObject := namedType(field.Type).(*Object)
+			switch {
+			case isObject && len(node.Selections) == 0:
+				return fmt.Errorf("field %q of type %q must have a selection of subfields", node.Name, field.Type)
+			case !isObject && len(node.Selections) > 0:
+				return fmt.Errorf("field %q can't have a selection since type %q has no subfields", node.Name, field.Type)
+			case isObject:
+				if e.schema.MaxDepth > 0 && depth+1 > e.schema.MaxDepth {
+					return fmt.Errorf("query is nested deeper than the limit of %d", e.schema.MaxDepth)
+				}
+				if err := e.validate(child, node.Selections, depth+1, spreading); err != nil {
+					return err
+				}
+			}
+		case *FragmentSpread:
+			fragment, ok := e.doc.Fragments[node.Name]
+			if !ok {
+				return fmt.Errorf("unknown fragment %q", node.Name)
+			}
+			if spreading[node.Name] {
+				return fmt.Errorf("fragment %q spreads itself", node.Name)
+			}
+			if fragment.TypeCondition != obj.Name {
+				return fmt.Errorf("fragment %q on %q can't be spread on %q", node.Name, fragment.TypeCondition, obj.Name)
+			}
+			spreading[node.Name] = true
+			err := e.validate(obj, fragment.Selections, depth, spreading)
+			delete(spreading, node.Name)
+			if err != nil {
+				return err
+			}
+		case *InlineFragment:
+			if node.TypeCondition != "" && node.TypeCondition != obj.Name {
+				return fmt.Errorf("fragment on %q can't be spread on %q", node.TypeCondition, obj.Name)
+			}
+			if err := e.validate(obj, node.Selections, depth, spreading); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Field) argument(name string) *Argument {
+	for _, arg := range f.Args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+	return nil
+}
+
+// collectedField is a response key with the field nodes merged into it.
+type collectedField struct {
+	key   string
+	nodes []*FieldNode
+}
+
+func (e *executor) collectFields(selections []Selection, fields []*collectedField) []*collectedField {
+	for _, selection := range selections {
+		switch node := selection.(type) {
+		case *FieldNode:
+			if !e.included(node.Directives) {
+				continue
+			}
+			key := node.ResponseKey()
+			merged := false
+			for _, field := range fields {
+				if field.key == key {
+					field.nodes = append(field.nodes, node)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				fields = append(fields, &collectedField{key: key, nodes: []*FieldNode{node}})
+			}
+		case *FragmentSpread:
+			if e.included(node.Directives) {
+				fields = e.collectFields(e.doc.Fragments[node.Name].Selections, fields)
+			}
+		case *InlineFragment:
+			if e.included(node.Directives) {
+				fields = e.collectFields(node.Selections, fields)
+			}
+		}
+	}
+	return fields
+}
+
+// included applies @skip(if:) and @include(if:).
+func (e *executor) included(directives []*Directive) bool {
+	for _, directive := range directives {
+		var condition bool
+		for _, arg := range directive.Arguments {
+			if arg.Name == "if" {
+				condition, _ = e.literal(arg.Value).(bool)
+			}
+		}
+		if (directive.Name == "skip" && condition) || (directive.Name == "include" && !condition) {
+			return false
+		}
+	}
+	return true
+}
+
+// literal replaces variables in a literal with their values.
+func (e *executor) literal(value any) any {
+	switch v := value.(type) {
+	case Variable:
+		return e.variables[string(v)]
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = e.literal(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = e.literal(item)
+		}
+		return out
+	}
+	return value
+}
+
+func (e *executor) arguments(field *Field, node *FieldNode) (map[string]any, error) {
+	given := make(map[string]any, len(node.Arguments))
+	for _, arg := range node.Arguments {
+		if field.argument(arg.Name) == nil {
+			return nil, fmt.Errorf("unknown argument %q on field %q", arg.Name, field.Name)
+		}
+		given[arg.Name] = arg.Value
+	}
+
+	args := make(map[string]any, len(field.Args))
+	for _, def := range field.Args {
+		raw, ok := given[def.Name]
+		if variable, isVariable := raw.(Variable); ok && isVariable {
+			raw, ok = e.variables[string(variable)]
+		}
+		if !ok {
+			if def.Default != nil {
+				args[def.Name] = def.Default
+				continue
+			}
+			if _, required := def.Type.(*NonNull); required {
+				return nil, fmt.Errorf("argument %q of field %q is required", def.Name, field.Name)
+			}
+			continue
+		}
+		value, err := coerceInput(e.literal(raw), def.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q of field %q: %w", def.Name, field.Name, err)
+		}
+		args[def.Name] = value
+	}
+	return args, nil
+}
+
+// coerceInput converts a literal or variable value to the Go value of an input
+// type. Lists are []any.
+func coerceInput(value any, t Type) (any, error) {
+	if nonNull, ok := t.(*NonNull); ok {
+		if value == nil {
+			return nil, fmt.Errorf("expected a non-null %s", nonNull.Of)
+		}
+		return coerceInput(value, nonNull.Of)
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		items, ok := value.([]any)
+		if !ok {
+			item, err := coerceInput(value, t.Of)
+			return []any{item}, err
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			coerced, err := coerceInput(item, t.Of)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = coerced
+		}
+		return out, nil
+	case *Scalar:
+		parsed, ok := t.ParseValue(value)
+		if !ok {
+			return nil, fmt.Errorf("expected %s, got %s", t.Name, describeValue(value))
+		}
+		return parsed, nil
+	case *Enum:
+		var name string
+		switch v := value.(type) {
+		case EnumValue:
+			name = string(v)
+		case string:
+			name = v
+		}
+		if !t.has(name) {
+			return nil, fmt.Errorf("expected one of %s, got %s", strings.Join(t.Values, ", "), describeValue(value))
+		}
+		return name, nil
+	}
+	return nil, fmt.Errorf("%s is not an input type", t)
+}
+
+func describeValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case EnumValue:
+		return string(v)
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
+
+// executeObjects resolves a selection set for every object of one type at once.
+func (e *executor) executeObjects(obj *Object, sources []any, selections []Selection, path []string) []*orderedMap {
+	results := make([]*orderedMap, len(sources))
+	for i := range results {
+		results[i] = &orderedMap{}
+	}
+
+	for _, field := range e.collectFields(selections, nil) {
+		fieldPath := append(path[:len(path):len(path)], field.key)
+		values := e.resolveField(obj, field, sources, fieldPath)
+		for i, result := range results {
+			result.set(field.key, values[i])
+		}
+	}
+	return results
+}
+
+// failed stands in for a value whose resolver returned an error, so it
+// completes to null without also being reported as a non-null violation.
+type failed struct{}
+
+func (e *executor) resolveField(obj *Object, collected *collectedField, sources []any, path []string) []any {
+	node := collected.nodes[0]
+	values := make([]any, len(sources))
+	if node.Name == "__typename" {
+		for i := range values {
+			values[i] = obj.Name
+		}
+		return values
+	}
+
+	fail := func(err error) []any {
+		e.addError(path, err)
+		for i := range values {
+			values[i] = failed{}
+		}
+		return e.complete(obj.Field(node.Name).Type, values, nil, path)
+	}
+
+	field := obj.Field(node.Name)
+	args, err := e.arguments(field, node)
+	if err != nil {
+		return fail(err)
+	}
+
+	switch {
+	case field.Batch != nil:
+		batch, err := field.Batch(BatchParams{Context: e.ctx, Sources: sources, Args: args})
+		if err == nil && len(batch) != len(sources) {
+			err = fmt.Errorf("batch resolver for %s.%s returned %d values for %d objects", obj.Name, field.Name, len(batch), len(sources))
+		}
+		if err != nil {
+			return fail(err)
+		}
+		values = batch
+	default:
+		for i, source := range sources {
+			var value any
+			var err error
+			if field.Resolve != nil {
+				value, err = field.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+			} else {
+				value, err = defaultResolve(source, field.Name)
+			}
+			if err != nil {
+				e.addError(path, err)
+				value = failed{}
+			}
+			values[i] = value
+		}
+	}
+
+	var selections []Selection
+	for _, n := range collected.nodes {
+		selections = append(selections, n.Selections...)
+	}
+	return e.complete(field.Type, values, selections, path)
+}
+
+// complete turns resolved values into response values of type t.
+func (e *executor) complete(t Type, values []any, selections []Selection, path []string) []any {
+	switch t := t.(type) {
+	case *NonNull:
+		completed := e.complete(t.Of, values, selections, path)
+		for i, value := range completed {
+			if value != nil {
+				continue
+			}
+			if _, alreadyFailed := values[i].(failed); !alreadyFailed && isNull(values[i]) {
+				e.addError(path, fmt.Errorf("non-null field returned null"))
+			}
+		}
+		return completed
+
+	case *List:
+		// Complete the items of every list together, then split them up again
+		var items []any
+		lengths := make([]int, len(values))
+		for i, value := range values {
+			lengths[i] = -1
+			if isNull(value) {
+				continue
+			}
+			rv := reflect.ValueOf(value)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				e.addError(path, fmt.Errorf("expected a list, got %T", value))
+				continue
+			}
+			lengths[i] = rv.Len()
+			for j := 0; j < rv.Len(); j++ {
+				items = append(items, rv.Index(j).Interface())
+			}
+		}
+		completed := e.complete(t.Of, items, selections, path)
+		out := make([]any, len(values))
+		offset := 0
+		for i, n := range lengths {
+			if n < 0 {
+				continue
+			}
+			out[i] = completed[offset : offset+n : offset+n]
+			offset += n
+		}
+		return out
+
+	case *Object:
+		var sources []any
+		var indexes []int
+		for i, value := range values {
+			if !isNull(value) {
+				sources = append(sources, value)
+				indexes = append(indexes, i)
+			}
+		}
+		out := make([]any, len(values))
+		if len(sources) == 0 {
+			return out
+		}
+		for i, result := range e.executeObjects(t, sources, selections, path) {
+			out[indexes[i]] = result
+		}
+		return out
+
+	case *Scalar:
+		out := make([]any, len(values))
+		for i, value := range values {
+			if isNull(value) {
+				continue
+			}
+			serialized, ok := t.Serialize(deref(value))
+			if !ok {
+				e.addError(path, fmt.Errorf("can't represent %T as %s", value, t.Name))
+				continue
+			}
+			out[i] = serialized
+		}
+		return out
+
+	case *Enum:
+		out := make([]any, len(values))
+		for i, value := range values {
+			if isNull(value) {
+				continue
+			}
+			name, _ := String.Serialize(deref(value))
+			if s, ok := name.(string); !ok || !t.has(s) {
+				e.addError(path, fmt.Errorf("%v is not a value of %s", value, t.Name))
+				continue
+			}
+			out[i] = name
+		}
+		return out
+	}
+	return make([]any, len(values))
+}
+
+// isNull reports whether v is nil, failed, or a nil pointer, map or interface.
+// Nil slices are empty lists, not null.
+func isNull(v any) bool {
+	if _, ok := v.(failed); ok || v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func deref(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	return rv.Interface()
+}
+
+// defaultResolve reads a map key or a struct field matched by its json tag.
+func defaultResolve(source any, name string) (any, error) {
+	if source == nil {
+		return nil, nil
+	}
+	if m, ok := source.(map[string]any); ok {
+		return m[name], nil
+	}
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't read field %q of %T", name, source)
+	}
+	index, ok := structField(rv.Type(), name)
+	if !ok {
+		return nil, nil
+	}
+	return rv.FieldByIndex(index).Interface(), nil
+}
+
+type fieldKey struct {
+	t    reflect.Type
+	name string
+}
+
+var fieldIndexes sync.Map // fieldKey -> []int, nil when the struct has no such field
+
+func structField(t reflect.Type, name string) ([]int, bool) {
+	key := fieldKey{t, name}
+	if cached, ok := fieldIndexes.Load(key); ok {
+		index := cached.([]int)
+		return index, index != nil
+	}
+
+	var index []int
+	snake := snakeCase(name)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if tag == snake || (tag == "" && strings.EqualFold(f.Name, name)) {
+			index = f.Index
+			break
+		}
+	}
+	fieldIndexes.Store(key, index)
+	return index, index != nil
+}
+
+// snakeCase turns "createdAt" into "created_at".
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// orderedMap is a response object, which keeps fields in query order.
+type orderedMap struct {
+	keys   []string
+	values []any
+}
+
+func (m *orderedMap) set(key string, value any) {
+	m.keys = append(m.keys, key)
+	m.values = append(m.values, value)
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
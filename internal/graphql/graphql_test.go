@@ -0,0 +1,249 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testAuthor struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type testBook struct {
+	ID       uint   `json:"id"`
+	Title    string `json:"title"`
+	AuthorID uint   `json:"author_id"`
+}
+
+func testSchema(t *testing.T, batches *int) *Schema {
+	t.Helper()
+	authors := map[uint]*testAuthor{1: {ID: 1, Name: "Ann"}, 2: {ID: 2, Name: "Bob"}}
+	books := []testBook{{ID: 1, Title: "First", AuthorID: 1}, {ID: 2, Title: "Second", AuthorID: 2}, {ID: 3, Title: "Third"}}
+
+	author := &Object{Name: "Author", Fields: []*Field{
+		{Name: "id", Type: NonNullOf(ID)},
+		{Name: "name", Type: NonNullOf(String)},
+	}}
+	book := &Object{Name: "Book", Fields: []*Field{
+		{Name: "id", Type: NonNullOf(Int)},
+		{Name: "title", Type: NonNullOf(String)},
+		{Name: "author", Type: author, Batch: func(p BatchParams) ([]any, error) {
+			*batches++
+			out := make([]any, len(p.Sources))
+			for i, source := range p.Sources {
+				if a, ok := authors[source.(testBook).AuthorID]; ok {
+					out[i] = a
+				}
+			}
+			return out, nil
+		}},
+	}}
+	query := &Object{Name: "Query", Fields: []*Field{
+		{
+			Name: "books",
+			Type: NonNullOf(ListOf(NonNullOf(book))),
+			Args: []*Argument{
+				{Name: "limit", Type: Int, Default: 10},
+				{Name: "order", Type: &Enum{Name: "Order", Values: []string{"ASC", "DESC"}}, Default: "ASC"},
+			},
+			Resolve: func(p ResolveParams) (any, error) {
+				out := append([]testBook(nil), books...)
+				if p.Args["order"] == "DESC" {
+					for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+						out[i], out[j] = out[j], out[i]
+					}
+				}
+				if limit := p.Args["limit"].(int); limit < len(out) {
+					out = out[:limit]
+				}
+				return out, nil
+			},
+		},
+		{
+			Name: "book",
+			Type: book,
+			Args: []*Argument{{Name: "id", Type: NonNullOf(Int)}},
+			Resolve: func(p ResolveParams) (any, error) {
+				for _, b := range books {
+					if int(b.ID) == p.Args["id"].(int) {
+						return b, nil
+					}
+				}
+				return nil, nil
+			},
+		},
+	}}
+
+	schema, err := NewSchema(query, 3)
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	return schema
+}
+
+func execute(t *testing.T, schema *Schema, req Request) (string, []*Error) {
+	t.Helper()
+	resp := schema.Execute(context.Background(), req)
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data), resp.Errors
+}
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		query Books($limit: Int = 2, $ids: [ID!]!) @cached {
+			list: books(limit: $limit, order: DESC) { ...BookFields author { name } }
+		}
+		fragment BookFields on Book { id title @include(if: true) }
+	`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	op := doc.Operations[0]
+	if op.Name != "Books" || len(op.Variables) != 2 || op.Variables[1].Type.String() != "[ID!]!" {
+		t.Fatalf("unexpected operation: %+v", op)
+	}
+	if op.Variables[0].Default != int64(2) {
+		t.Fatalf("expected an int64 default, got %#v", op.Variables[0].Default)
+	}
+	field := op.Selections[0].(*FieldNode)
+	if field.ResponseKey() != "list" || field.Arguments[1].Value != EnumValue("DESC") || field.Arguments[0].Value != Variable("limit") {
+		t.Fatalf("unexpected field: %+v", field)
+	}
+	if _, ok := doc.Fragments["BookFields"]; !ok {
+		t.Fatal("expected the fragment to be parsed")
+	}
+
+	_, err = Parse("{ books(limit: ) }")
+	syntaxErr, ok := err.(*SyntaxError)
+	if !ok || syntaxErr.Line != 1 || syntaxErr.Column != 16 {
+		t.Fatalf("expected a positioned syntax error, got %v", err)
+	}
+}
+
+func TestExecute_BatchesNestedFields(t *testing.T) {
+	batches := 0
+	schema := testSchema(t, &batches)
+
+	data, errs := execute(t, schema, Request{Query: `{ books { id title author { id name } } }`})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs[0].Message)
+	}
+	want := `{"books":[{"id":1,"title":"First","author":{"id":"1","name":"Ann"}},{"id":2,"title":"Second","author":{"id":"2","name":"Bob"}},{"id":3,"title":"Third","author":null}]}`
+	if data != want {
+		t.Fatalf("got %s\nwant %s", data, want)
+	}
+	if batches != 1 {
+		t.Fatalf("expected one batch call for all books, got %d", batches)
+	}
+}
+
+func TestExecute_VariablesFragmentsAndAliases(t *testing.T) {
+	batches := 0
+	schema := testSchema(t, &batches)
+
+	data, errs := execute(t, schema, Request{
+		Query: `
+			query Q($n: Int!, $order: Order, $withAuthor: Boolean!) {
+				latest: books(limit: $n, order: $order) { ...F }
+				one: book(id: 2) { __typename title author @include(if: $withAuthor) { name } }
+			}
+			fragment F on Book { title }
+		`,
+		Variables: map[string]any{"n": float64(1), "order": "DESC", "withAuthor": false},
+	})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs[0].Message)
+	}
+	want := `{"latest":[{"title":"Third"}],"one":{"__typename":"Book","title":"Second"}}`
+	if data != want {
+		t.Fatalf("got %s\nwant %s", data, want)
+	}
+}
+
+func TestExecute_RejectsInvalidRequests(t *testing.T) {
+	batches := 0
+	schema := testSchema(t, &batches)
+
+	tests := []struct {
+		name      string
+		req       Request
+		errSubstr string
+	}{
+		{"syntax", Request{Query: `{ books {`}, "syntax error"},
+		{"unknown field", Request{Query: `{ books { isbn } }`}, `cannot query field "isbn" on type "Book"`},
+		{"unknown argument", Request{Query: `{ books(page: 2) { id } }`}, `unknown argument "page"`},
+		{"missing selection", Request{Query: `{ books }`}, "must have a selection"},
+		{"scalar selection", Request{Query: `{ books { id { x } } }`}, "has no subfields"},
+		{"mutation", Request{Query: `mutation { books { id } }`}, "only queries are supported"},
+		{"variable type", Request{Query: `query($n: Int) { books(limit: $n) { id } }`, Variables: map[string]any{"n": "two"}}, "variable $n"},
+		{"missing variable", Request{Query: `query($id: Int!) { book(id: $id) { id } }`}, "expected a non-null Int"},
+		{"bad enum", Request{Query: `{ books(order: UP) { id } }`}, "expected one of ASC, DESC"},
+		{"fragment cycle", Request{Query: `{ books { ...A } } fragment A on Book { ...B } fragment B on Book { ...A }`}, "spreads itself"},
+		{"depth", Request{Query: `{ books { author { name } } book(id: 1) { author { id } } }`}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := schema.Execute(context.Background(), tt.req)
+			if tt.errSubstr == "" {
+				if len(resp.Errors) > 0 {
+					t.Fatalf("expected a query within the depth limit to run, got %v", resp.Errors[0].Message)
+				}
+				return
+			}
+			if resp.Data != nil || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, tt.errSubstr) {
+				t.Fatalf("expected a request error containing %q, got %+v", tt.errSubstr, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestExecute_DepthLimit(t *testing.T) {
+	batches := 0
+	schema := testSchema(t, &batches)
+	schema.MaxDepth = 2
+
+	resp := schema.Execute(context.Background(), Request{Query: `{ books { author { name } } }`})
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "limit of 2") {
+		t.Fatalf("expected a depth error, got %+v", resp.Errors)
+	}
+}
+
+func TestExecute_FieldErrorsKeepPartialData(t *testing.T) {
+	failing := &Object{Name: "Query", Fields: []*Field{
+		{Name: "ok", Type: String, Resolve: func(ResolveParams) (any, error) { return "yes", nil }},
+		{Name: "broken", Type: NonNullOf(String), Resolve: func(ResolveParams) (any, error) { return nil, context.Canceled }},
+	}}
+	schema, err := NewSchema(failing, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, errs := execute(t, schema, Request{Query: `{ ok broken }`})
+	if data != `{"ok":"yes","broken":null}` {
+		t.Fatalf("unexpected data: %s", data)
+	}
+	if len(errs) != 1 || errs[0].Message != context.Canceled.Error() || errs[0].Path[0] != "broken" {
+		t.Fatalf("expected one field error with a path, got %+v", errs)
+	}
+}
+
+func TestSDL(t *testing.T) {
+	batches := 0
+	sdl := testSchema(t, &batches).SDL()
+	for _, want := range []string{
+		"schema {\n  query: Query\n}",
+		"books(limit: Int = 10, order: Order = ASC): [Book!]!",
+		"enum Order {\n  ASC\n  DESC\n}",
+		"author: Author",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Fatalf("expected SDL to contain %q, got:\n%s", want, sdl)
+		}
+	}
+}
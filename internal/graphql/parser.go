@@ -0,0 +1,563 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is a parsed GraphQL request document.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+type Operation struct {
+	// Type is "query", "mutation" or "subscription"
+	Type       string
+	Name       string
+	Variables  []*VariableDefinition
+	Selections []Selection
+}
+
+type VariableDefinition struct {
+	Name    string
+	Type    *TypeRef
+	Default any
+}
+
+// TypeRef is a type as written in a variable definition, such as [String!]!.
+type TypeRef struct {
+	Name    string
+	Elem    *TypeRef // set for list types
+	NonNull bool
+}
+
+func (t *TypeRef) String() string {
+	s := t.Name
+	if t.Elem != nil {
+		s = "[" + t.Elem.String() + "]"
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// Selection is a *FieldNode, *FragmentSpread or *InlineFragment.
+type Selection interface{}
+
+type FieldNode struct {
+	Alias      string
+	Name       string
+	Arguments  []*ArgumentNode
+	Directives []*Directive
+	Selections []Selection
+}
+
+// ResponseKey is the key of the field in the response.
+func (f *FieldNode) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	Selections    []Selection
+}
+
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Selections    []Selection
+}
+
+type Directive struct {
+	Name      string
+	Arguments []*ArgumentNode
+}
+
+// ArgumentNode is an argument with its literal value: nil, bool, int64, float64,
+// string, EnumValue, Variable, []any or map[string]any.
+type ArgumentNode struct {
+	Name  string
+	Value any
+}
+
+// Variable is a $variable reference in a literal.
+type Variable string
+
+// EnumValue is an unquoted enum literal.
+type EnumValue string
+
+// SyntaxError is a malformed document.
+type SyntaxError struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Parse parses a GraphQL executable document.
+func Parse(source string) (doc *Document, err error) {
+	p := &parser{lexer: lexer{src: source, line: 1, col: 1}}
+	defer func() {
+		if r := recover(); r != nil {
+			syntaxErr, ok := r.(*SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, syntaxErr
+		}
+	}()
+	p.next()
+	return p.parseDocument(), nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind   tokenKind
+	value  string
+	line   int
+	column int
+}
+
+type lexer struct {
+	src       string
+	pos       int
+	line, col int
+}
+
+func (l *lexer) fail(format string, args ...any) {
+	panic(&SyntaxError{Message: fmt.Sprintf(format, args...), Line: l.line, Column: l.col})
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n; i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.advance(1)
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\ufeff"):
+			l.pos += len("\ufeff")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() token {
+	l.skipIgnored()
+	tok := token{line: l.line, column: l.col}
+	if l.pos >= len(l.src) {
+		tok.kind = tokenEOF
+		return tok
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.advance(3)
+		tok.kind, tok.value = tokenPunct, "..."
+	case strings.IndexByte("!$&()=:@[]{}|", c) >= 0:
+		l.advance(1)
+		tok.kind, tok.value = tokenPunct, string(c)
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.advance(1)
+		}
+		tok.kind, tok.value = tokenName, l.src[start:l.pos]
+	case c == '-' || isDigit(c):
+		tok.kind, tok.value = l.number()
+	case strings.HasPrefix(l.src[l.pos:], `"""`):
+		tok.kind, tok.value = tokenString, l.blockString()
+	case c == '"':
+		tok.kind, tok.value = tokenString, l.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		l.fail("unexpected character %q", r)
+	}
+	return tok
+}
+
+func (l *lexer) number() (tokenKind, string) {
+	start := l.pos
+	kind := tokenInt
+	if l.src[l.pos] == '-' {
+		l.advance(1)
+	}
+	digits := func() {
+		if l.pos >= len(l.src) || !isDigit(l.src[l.pos]) {
+			l.fail("invalid number")
+		}
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.advance(1)
+		}
+	}
+	digits()
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.advance(1)
+		digits()
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.advance(1)
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.advance(1)
+		}
+		digits()
+	}
+	return kind, l.src[start:l.pos]
+}
+
+func (l *lexer) string() string {
+	l.advance(1)
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' {
+			l.fail("unterminated string")
+		}
+		c := l.src[l.pos]
+		if c == '"' {
+			l.advance(1)
+			return b.String()
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			l.advance(1)
+			continue
+		}
+		if l.pos+1 >= len(l.src) {
+			l.fail("unterminated string")
+		}
+		escape := l.src[l.pos+1]
+		l.advance(2)
+		switch escape {
+		case '"', '\\', '/':
+			b.WriteByte(escape)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if l.pos+4 > len(l.src) {
+				l.fail("invalid unicode escape")
+			}
+			code, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+			if err != nil {
+				l.fail("invalid unicode escape")
+			}
+			b.WriteRune(rune(code))
+			l.advance(4)
+		default:
+			l.fail("invalid escape \\%c", escape)
+		}
+	}
+}
+
+// blockString reads a """block string""". Indentation is kept as written.
+func (l *lexer) blockString() string {
+	l.advance(3)
+	end := 0
+	for {
+		i := strings.Index(l.src[l.pos+end:], `"""`)
+		if i < 0 {
+			l.fail("unterminated block string")
+		}
+		end += i
+		if end == 0 || l.src[l.pos+end-1] != '\\' {
+			break
+		}
+		end += 3
+	}
+	value := l.src[l.pos : l.pos+end]
+	l.advance(end + 3)
+	return strings.ReplaceAll(strings.TrimSpace(value), `\"""`, `"""`)
+}
+
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+type parser struct {
+	lexer lexer
+	tok   token
+}
+
+func (p *parser) next() { p.tok = p.lexer.next() }
+
+func (p *parser) fail(format string, args ...any) {
+	panic(&SyntaxError{Message: fmt.Sprintf(format, args...), Line: p.tok.line, Column: p.tok.column})
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == punct
+}
+
+func (p *parser) skip(punct string) bool {
+	if p.peek(punct) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) {
+	if !p.skip(punct) {
+		p.fail("expected %q, found %s", punct, p.describe())
+	}
+}
+
+func (p *parser) name() string {
+	if p.tok.kind != tokenName {
+		p.fail("expected a name, found %s", p.describe())
+	}
+	name := p.tok.value
+	p.next()
+	return name
+}
+
+func (p *parser) describe() string {
+	if p.tok.kind == tokenEOF {
+		return "end of document"
+	}
+	return strconv.Quote(p.tok.value)
+}
+
+func (p *parser) parseDocument() *Document {
+	doc := &Document{Fragments: map[string]*Fragment{}}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek("{"):
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: p.selectionSet()})
+		case p.tok.kind == tokenName && p.tok.value == "fragment":
+			fragment := p.fragment()
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				p.fail("fragment %q is defined more than once", fragment.Name)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		case p.tok.kind == tokenName && (p.tok.value == "query" || p.tok.value == "mutation" || p.tok.value == "subscription"):
+			doc.Operations = append(doc.Operations, p.operation())
+		default:
+			p.fail("expected an operation or fragment, found %s", p.describe())
+		}
+	}
+	if len(doc.Operations) == 0 {
+		p.fail("document has no operations")
+	}
+	return doc
+}
+
+func (p *parser) operation() *Operation {
+	op := &Operation{Type: p.name()}
+	if p.tok.kind == tokenName {
+		op.Name = p.name()
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			p.expect("$")
+			def := &VariableDefinition{Name: p.name()}
+			p.expect(":")
+			def.Type = p.typeRef()
+			if p.skip("=") {
+				def.Default = p.value(true)
+			}
+			op.Variables = append(op.Variables, def)
+		}
+	}
+	p.directives()
+	op.Selections = p.selectionSet()
+	return op
+}
+
+func (p *parser) fragment() *Fragment {
+	p.next() // "fragment"
+	fragment := &Fragment{Name: p.name()}
+	if fragment.Name == "on" {
+		p.fail("fragments can't be named \"on\"")
+	}
+	if p.tok.kind != tokenName || p.tok.value != "on" {
+		p.fail("expected \"on\", found %s", p.describe())
+	}
+	p.next()
+	fragment.TypeCondition = p.name()
+	p.directives()
+	fragment.Selections = p.selectionSet()
+	return fragment
+}
+
+func (p *parser) typeRef() *TypeRef {
+	var t *TypeRef
+	if p.skip("[") {
+		t = &TypeRef{Elem: p.typeRef()}
+		p.expect("]")
+	} else {
+		t = &TypeRef{Name: p.name()}
+	}
+	t.NonNull = p.skip("!")
+	return t
+}
+
+func (p *parser) selectionSet() []Selection {
+	p.expect("{")
+	var selections []Selection
+	for !p.skip("}") {
+		selections = append(selections, p.selection())
+	}
+	if len(selections) == 0 {
+		p.fail("selection set is empty")
+	}
+	return selections
+}
+
+func (p *parser) selection() Selection {
+	if p.skip("...") {
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			return &FragmentSpread{Name: p.name(), Directives: p.directives()}
+		}
+		inline := &InlineFragment{}
+		if p.tok.kind == tokenName {
+			p.next() // "on"
+			inline.TypeCondition = p.name()
+		}
+		inline.Directives = p.directives()
+		inline.Selections = p.selectionSet()
+		return inline
+	}
+
+	field := &FieldNode{Name: p.name()}
+	if p.skip(":") {
+		field.Alias, field.Name = field.Name, p.name()
+	}
+	field.Arguments = p.arguments(false)
+	field.Directives = p.directives()
+	if p.peek("{") {
+		field.Selections = p.selectionSet()
+	}
+	return field
+}
+
+func (p *parser) arguments(constant bool) []*ArgumentNode {
+	if !p.skip("(") {
+		return nil
+	}
+	var args []*ArgumentNode
+	for !p.skip(")") {
+		arg := &ArgumentNode{Name: p.name()}
+		p.expect(":")
+		arg.Value = p.value(constant)
+		args = append(args, arg)
+	}
+	return args
+}
+
+func (p *parser) directives() []*Directive {
+	var directives []*Directive
+	for p.skip("@") {
+		directives = append(directives, &Directive{Name: p.name(), Arguments: p.arguments(false)})
+	}
+	return directives
+}
+
+// value parses a literal. Constant values (variable defaults) can't use variables.
+func (p *parser) value(constant bool) any {
+	tok := p.tok
+	switch tok.kind {
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				p.fail("variables are not allowed here")
+			}
+			p.next()
+			return Variable(p.name())
+		case "[":
+			p.next()
+			list := []any{}
+			for !p.skip("]") {
+				list = append(list, p.value(constant))
+			}
+			return list
+		case "{":
+			p.next()
+			object := map[string]any{}
+			for !p.skip("}") {
+				name := p.name()
+				p.expect(":")
+				object[name] = p.value(constant)
+			}
+			return object
+		}
+	case tokenInt:
+		p.next()
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			p.fail("integer %s is out of range", tok.value)
+		}
+		return n
+	case tokenFloat:
+		p.next()
+		f, _ := strconv.ParseFloat(tok.value, 64)
+		return f
+	case tokenString:
+		p.next()
+		return tok.value
+	case tokenName:
+		p.next()
+		switch tok.value {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return EnumValue(tok.value)
+	}
+	p.fail("expected a value, found %s", p.describe())
+	return nil
+}
@@ -0,0 +1,310 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Type is a *Scalar, *Enum, *Object, *List or *NonNull.
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type. Serialize turns a resolved value into its JSON form
+// and ParseValue coerces an input (literal or variable) into the Go value given
+// to resolvers; both report false for values of the wrong type.
+type Scalar struct {
+	Name        string
+	Description string
+	Serialize   func(value any) (any, bool)
+	ParseValue  func(value any) (any, bool)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// Enum is a leaf type with a fixed set of string values.
+type Enum struct {
+	Name        string
+	Description string
+	Values      []string
+}
+
+func (e *Enum) String() string { return e.Name }
+
+func (e *Enum) has(value string) bool {
+	for _, v := range e.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Object is an output type with fields, listed in declaration order.
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+func (o *Object) String() string { return o.Name }
+
+// Field returns the named field, or nil.
+func (o *Object) Field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+type List struct{ Of Type }
+
+func (l *List) String() string { return "[" + l.Of.String() + "]" }
+
+type NonNull struct{ Of Type }
+
+func (n *NonNull) String() string { return n.Of.String() + "!" }
+
+// ListOf and NonNullOf wrap a type.
+func ListOf(t Type) *List       { return &List{Of: t} }
+func NonNullOf(t Type) *NonNull { return &NonNull{Of: t} }
+
+// Field is a field of an object type. A field without Resolve or Batch reads
+// the parent's map key or struct field of the same name, matching struct fields
+// by their json tag in snake_case ("createdAt" reads `json:"created_at"`).
+type Field struct {
+	Name        string
+	Description string
+	Type        Type
+	Args        []*Argument
+	// Resolve returns the field's value for one parent object
+	Resolve func(p ResolveParams) (any, error)
+	// Batch returns the field's values for every parent object in the response
+	// at once, index-aligned with p.Sources, so nested fields cost one query per
+	// level instead of one per parent
+	Batch func(p BatchParams) ([]any, error)
+}
+
+type Argument struct {
+	Name        string
+	Description string
+	Type        Type
+	// Default is used when the argument is omitted
+	Default any
+}
+
+type ResolveParams struct {
+	Context context.Context
+	Source  any
+	Args    map[string]any
+}
+
+type BatchParams struct {
+	Context context.Context
+	Sources []any
+	Args    map[string]any
+}
+
+// Schema is a queryable type system. Only queries are supported.
+type Schema struct {
+	Query *Object
+	// MaxDepth limits how deeply selections can nest (0 means unlimited)
+	MaxDepth int
+
+	types map[string]Type
+	order []string
+}
+
+// NewSchema collects the types reachable from query and checks that their
+// names are unique.
+func NewSchema(query *Object, maxDepth int) (*Schema, error) {
+	s := &Schema{Query: query, MaxDepth: maxDepth, types: map[string]Type{}}
+	for _, scalar := range []*Scalar{Int, Float, String, Boolean, ID} {
+		s.types[scalar.Name] = scalar
+	}
+	if err := s.collect(query); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Schema) collect(t Type) error {
+	switch t := t.(type) {
+	case *List:
+		return s.collect(t.Of)
+	case *NonNull:
+		return s.collect(t.Of)
+	}
+
+	name := t.String()
+	if existing, ok := s.types[name]; ok {
+		if existing != t {
+			return fmt.Errorf("graphql: two different types are named %s", name)
+		}
+		return nil
+	}
+	s.types[name] = t
+	s.order = append(s.order, name)
+
+	if obj, ok := t.(*Object); ok {
+		for _, field := range obj.Fields {
+			if err := s.collect(field.Type); err != nil {
+				return err
+			}
+			for _, arg := range field.Args {
+				if _, isObject := namedType(arg.Type).(*Object); isObject {
+					return fmt.Errorf("graphql: argument %s.%s(%s) must be a scalar or enum", obj.Name, field.Name, arg.Name)
+				}
+				if err := s.collect(arg.Type); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// namedType strips list and non-null wrappers.
+func namedType(t Type) Type {
+	for {
+		switch wrapped := t.(type) {
+		case *List:
+			t = wrapped.Of
+		case *NonNull:
+			t = wrapped.Of
+		default:
+			return t
+		}
+	}
+}
+
+// Built-in scalars. Int accepts any Go integer, so 64-bit values such as file
+// sizes are returned as-is.
+var (
+	Int = &Scalar{
+		Name: "Int",
+		Serialize: func(v any) (any, bool) {
+			switch rv := reflect.ValueOf(v); rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return rv.Int(), true
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return rv.Uint(), true
+			}
+			return nil, false
+		},
+		ParseValue: func(v any) (any, bool) {
+			switch n := v.(type) {
+			case int64:
+				if n < math.MinInt32 || n > math.MaxInt32 {
+					return nil, false
+				}
+				return int(n), true
+			case float64:
+				if n != math.Trunc(n) || n < math.MinInt32 || n > math.MaxInt32 {
+					return nil, false
+				}
+				return int(n), true
+			}
+			return nil, false
+		},
+	}
+	Float = &Scalar{
+		Name: "Float",
+		Serialize: func(v any) (any, bool) {
+			switch rv := reflect.ValueOf(v); rv.Kind() {
+			case reflect.Float32, reflect.Float64:
+				return rv.Float(), true
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return float64(rv.Int()), true
+			}
+			return nil, false
+		},
+		ParseValue: func(v any) (any, bool) {
+			switch n := v.(type) {
+			case int64:
+				return float64(n), true
+			case float64:
+				return n, true
+			}
+			return nil, false
+		},
+	}
+	String = &Scalar{
+		Name: "String",
+		Serialize: func(v any) (any, bool) {
+			switch s := v.(type) {
+			case string:
+				return s, true
+			case fmt.Stringer:
+				return s.String(), true
+			}
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+				return rv.String(), true
+			}
+			return nil, false
+		},
+		ParseValue: func(v any) (any, bool) {
+			s, ok := v.(string)
+			return s, ok
+		},
+	}
+	Boolean = &Scalar{
+		Name: "Boolean",
+		Serialize: func(v any) (any, bool) {
+			b, ok := v.(bool)
+			return b, ok
+		},
+		ParseValue: func(v any) (any, bool) {
+			b, ok := v.(bool)
+			return b, ok
+		},
+	}
+	ID = &Scalar{
+		Name: "ID",
+		Serialize: func(v any) (any, bool) {
+			if n, ok := Int.Serialize(v); ok {
+				return fmt.Sprint(n), true
+			}
+			return String.Serialize(v)
+		},
+		ParseValue: func(v any) (any, bool) {
+			switch id := v.(type) {
+			case string:
+				return id, true
+			case int64:
+				return strconv.FormatInt(id, 10), true
+			case float64:
+				if id == math.Trunc(id) {
+					return strconv.FormatFloat(id, 'f', 0, 64), true
+				}
+			}
+			return nil, false
+		},
+	}
+	// DateTime is an RFC 3339 timestamp
+	DateTime = &Scalar{
+		Name:        "DateTime",
+		Description: "An RFC 3339 timestamp",
+		Serialize: func(v any) (any, bool) {
+			t, ok := v.(time.Time)
+			if !ok {
+				return nil, false
+			}
+			return t.Format(time.RFC3339Nano), true
+		},
+		ParseValue: func(v any) (any, bool) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, false
+			}
+			t, err := time.Parse(time.RFC3339Nano, s)
+			return t, err == nil
+		},
+	}
+)
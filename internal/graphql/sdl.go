@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SDL prints the schema in the GraphQL schema definition language, for clients
+// and code generators since introspection isn't supported.
+func (s *Schema) SDL() string {
+	var b strings.Builder
+	b.WriteString("schema {\n  query: " + s.Query.Name + "\n}\n")
+
+	for _, name := range s.order {
+		b.WriteByte('\n')
+		switch t := s.types[name].(type) {
+		case *Scalar:
+			writeDescription(&b, "", t.Description)
+			fmt.Fprintf(&b, "scalar %s\n", t.Name)
+		case *Enum:
+			writeDescription(&b, "", t.Description)
+			fmt.Fprintf(&b, "enum %s {\n", t.Name)
+			for _, value := range t.Values {
+				fmt.Fprintf(&b, "  %s\n", value)
+			}
+			b.WriteString("}\n")
+		case *Object:
+			writeDescription(&b, "", t.Description)
+			fmt.Fprintf(&b, "type %s {\n", t.Name)
+			for _, field := range t.Fields {
+				writeDescription(&b, "  ", field.Description)
+				b.WriteString("  " + field.Name)
+				if len(field.Args) > 0 {
+					args := make([]string, len(field.Args))
+					for i, arg := range field.Args {
+						args[i] = arg.Name + ": " + arg.Type.String()
+						if arg.Default != nil {
+							args[i] += " = " + formatDefault(arg.Default, arg.Type)
+						}
+					}
+					b.WriteString("(" + strings.Join(args, ", ") + ")")
+				}
+				b.WriteString(": " + field.Type.String() + "\n")
+			}
+			b.WriteString("}\n")
+		}
+	}
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s%q\n", indent, description)
+}
+
+func formatDefault(value any, t Type) string {
+	if _, isEnum := namedType(t).(*Enum); isEnum {
+		return fmt.Sprint(value)
+	}
+	return describeValue(value)
+}
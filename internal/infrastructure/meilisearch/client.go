@@ -2,7 +2,12 @@ package meilisearch
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -70,7 +75,9 @@ func (c *Client) EnsureIndex() error {
 		"path",
 		"description",
 		"actors",
+		"actor_aliases",
 		"tag_names",
+		"tag_aliases",
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update searchable attributes: %w", err)
@@ -103,6 +110,7 @@ func (c *Client) EnsureIndex() error {
 		"title",
 		"duration",
 		"view_count",
+		"id",
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update sortable attributes: %w", err)
@@ -122,10 +130,49 @@ func (c *Client) EnsureIndex() error {
 		return fmt.Errorf("failed to wait for pagination settings task: %w", err)
 	}
 
+	if err := c.ensureSegmentsIndex(ctx); err != nil {
+		return err
+	}
+
 	c.logger.Info("meilisearch index configured", zap.String("index", c.indexName), zap.Int64("max_total_hits", c.maxTotalHits))
 	return nil
 }
 
+// segmentsIndexName is the index holding marker and subtitle segments.
+func (c *Client) segmentsIndexName() string {
+	return c.indexName + "_segments"
+}
+
+// ensureSegmentsIndex creates the segments index if it doesn't exist and configures settings.
+func (c *Client) ensureSegmentsIndex(ctx context.Context) error {
+	_, err := c.client.CreateIndex(&meili.IndexConfig{
+		Uid:        c.segmentsIndexName(),
+		PrimaryKey: "id",
+	})
+	if err != nil && !strings.Contains(err.Error(), "index_already_exists") {
+		return fmt.Errorf("failed to create segments index: %w", err)
+	}
+
+	index := c.client.Index(c.segmentsIndexName())
+
+	searchableTask, err := index.UpdateSearchableAttributes(&[]string{"text"})
+	if err != nil {
+		return fmt.Errorf("failed to update segment searchable attributes: %w", err)
+	}
+	if _, err := c.client.WaitForTask(searchableTask.TaskUID, meili.WaitParams{Context: ctx, Interval: 100 * time.Millisecond}); err != nil {
+		return fmt.Errorf("failed to wait for segment searchable attributes task: %w", err)
+	}
+
+	filterableTask, err := index.UpdateFilterableAttributes(&[]string{"scene_id", "user_id", "kind"})
+	if err != nil {
+		return fmt.Errorf("failed to update segment filterable attributes: %w", err)
+	}
+	if _, err := c.client.WaitForTask(filterableTask.TaskUID, meili.WaitParams{Context: ctx, Interval: 100 * time.Millisecond}); err != nil {
+		return fmt.Errorf("failed to wait for segment filterable attributes task: %w", err)
+	}
+	return nil
+}
+
 // UpdateMaxTotalHits updates the pagination maxTotalHits setting on the Meilisearch index.
 func (c *Client) UpdateMaxTotalHits(maxTotalHits int64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -239,6 +286,10 @@ func (c *Client) Search(params SearchParams) (*SearchResult, error) {
 		searchReq.Limit = c.maxTotalHits
 		searchReq.Offset = 0
 		sort = nil
+	} else if params.Keyset {
+		// One extra hit tells whether another page follows
+		searchReq.Limit = int64(params.Limit) + 1
+		searchReq.AttributesToRetrieve = []string{"id", keysetField(params)}
 	} else {
 		searchReq.Limit = int64(params.Limit)
 		searchReq.Offset = int64(params.Offset)
@@ -263,10 +314,18 @@ func (c *Client) Search(params SearchParams) (*SearchResult, error) {
 
 	// Extract IDs from hits
 	ids := make([]uint, 0, len(result.Hits))
-	for _, hit := range result.Hits {
+	var next *SortKey
+	for i, hit := range result.Hits {
+		if params.Keyset && i == params.Limit {
+			break
+		}
 		if m, ok := hit.(map[string]interface{}); ok {
 			if id, ok := m["id"].(float64); ok {
 				ids = append(ids, uint(id))
+				if params.Keyset && i == params.Limit-1 && len(result.Hits) > params.Limit {
+					value, _ := m[keysetField(params)].(float64)
+					next = &SortKey{Value: value, ID: uint(id)}
+				}
 			}
 		}
 	}
@@ -274,9 +333,18 @@ func (c *Client) Search(params SearchParams) (*SearchResult, error) {
 	return &SearchResult{
 		IDs:        ids,
 		TotalCount: result.EstimatedTotalHits,
+		Next:       next,
 	}, nil
 }
 
+// keysetField is the document field keyset pages are ordered by.
+func keysetField(params SearchParams) string {
+	if field := sortFieldFor(params.Sort); field != "" {
+		return field
+	}
+	return "created_at"
+}
+
 // buildFilters constructs the filter string for Meilisearch.
 func (c *Client) buildFilters(params SearchParams) []string {
 	var filters []string
@@ -285,6 +353,9 @@ func (c *Client) buildFilters(params SearchParams) []string {
 	for _, tagID := range params.TagIDs {
 		filters = append(filters, fmt.Sprintf("tag_ids = %d", tagID))
 	}
+	for _, tagID := range params.ExcludeTagIDs {
+		filters = append(filters, fmt.Sprintf("NOT tag_ids = %d", tagID))
+	}
 
 	// Actor filter (OR logic - must have at least one specified actor)
 	if len(params.Actors) > 0 {
@@ -295,9 +366,17 @@ func (c *Client) buildFilters(params SearchParams) []string {
 		filters = append(filters, "("+strings.Join(actorFilters, " OR ")+")")
 	}
 
-	// Studio filter
+	// Studio filter, including the studios under it
 	if params.Studio != "" {
-		filters = append(filters, fmt.Sprintf("studio = \"%s\"", escapeFilterValue(params.Studio)))
+		studioFilter := fmt.Sprintf("studio = \"%s\"", escapeFilterValue(params.Studio))
+		if len(params.SubStudios) > 0 {
+			studioFilters := []string{studioFilter}
+			for _, studio := range params.SubStudios {
+				studioFilters = append(studioFilters, fmt.Sprintf("studio = \"%s\"", escapeFilterValue(studio)))
+			}
+			studioFilter = "(" + strings.Join(studioFilters, " OR ") + ")"
+		}
+		filters = append(filters, studioFilter)
 	}
 
 	// Duration range
@@ -338,27 +417,33 @@ func (c *Client) buildFilters(params SearchParams) []string {
 		filters = append(filters, "("+strings.Join(idStrs, " OR ")+")")
 	}
 
+	// Hits after the previous keyset page. created_at is indexed in whole
+	// seconds, so the cursor's value is truncated to match.
+	if params.Keyset && params.After != nil {
+		field := keysetField(params)
+		value := params.After.Value
+		if field == "created_at" {
+			value = math.Floor(value)
+		}
+		op := "<"
+		if params.SortDir == "asc" {
+			op = ">"
+		}
+		filters = append(filters, fmt.Sprintf("(%s %s %s OR (%s = %s AND id %s %d))",
+			field, op, formatFilterNumber(value), field, formatFilterNumber(value), op, params.After.ID))
+	}
+
 	return filters
 }
 
+func formatFilterNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
 // buildSort constructs the sort array for Meilisearch.
 func (c *Client) buildSort(params SearchParams) []string {
-	if params.Sort == "" {
-		return nil
-	}
-
-	// Map frontend sort fields to Meilisearch fields
-	sortField := params.Sort
-	switch sortField {
-	case "date", "created_at":
-		sortField = "created_at"
-	case "title", "name":
-		sortField = "title"
-	case "duration", "length":
-		sortField = "duration"
-	case "view_count", "views":
-		sortField = "view_count"
-	default:
+	sortField := sortFieldFor(params.Sort)
+	if sortField == "" {
 		// For relevance or unknown, don't specify sort (use default ranking)
 		return nil
 	}
@@ -368,9 +453,28 @@ func (c *Client) buildSort(params SearchParams) []string {
 		direction = "asc"
 	}
 
+	if params.Keyset {
+		return []string{fmt.Sprintf("%s:%s", sortField, direction), fmt.Sprintf("id:%s", direction)}
+	}
 	return []string{fmt.Sprintf("%s:%s", sortField, direction)}
 }
 
+// sortFieldFor maps frontend sort fields to Meilisearch fields, returning ""
+// for relevance and unknown sorts.
+func sortFieldFor(sort string) string {
+	switch sort {
+	case "date", "created_at":
+		return "created_at"
+	case "title", "name":
+		return "title"
+	case "duration", "length":
+		return "duration"
+	case "view_count", "views":
+		return "view_count"
+	}
+	return ""
+}
+
 // escapeFilterValue escapes special characters in filter values.
 func escapeFilterValue(s string) string {
 	s = strings.ReplaceAll(s, "\\", "\\\\")
@@ -397,6 +501,184 @@ func (c *Client) ClearIndex() error {
 	return nil
 }
 
+// IndexSegments adds or updates segment documents.
+// Fire-and-forget: Meilisearch processes the task asynchronously.
+func (c *Client) IndexSegments(docs []SegmentDocument) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	index := c.client.Index(c.segmentsIndexName())
+	if _, err := index.AddDocuments(docs, "id"); err != nil {
+		return fmt.Errorf("failed to index segments: %w", err)
+	}
+
+	c.logger.Debug("indexed segments", zap.Int("count", len(docs)))
+	return nil
+}
+
+// DeleteSegment removes a segment document.
+// Fire-and-forget: Meilisearch processes the task asynchronously.
+func (c *Client) DeleteSegment(id string) error {
+	index := c.client.Index(c.segmentsIndexName())
+	if _, err := index.DeleteDocument(id); err != nil {
+		return fmt.Errorf("failed to delete segment: %w", err)
+	}
+
+	c.logger.Debug("deleted segment from index", zap.String("id", id))
+	return nil
+}
+
+// SearchSegments returns the segments matching the query, best match first.
+func (c *Client) SearchSegments(params SegmentSearchParams) ([]SegmentDocument, error) {
+	index := c.client.Index(c.segmentsIndexName())
+
+	result, err := index.Search(params.Query, &meili.SearchRequest{
+		Filter: buildSegmentFilter(params),
+		Limit:  int64(params.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("segment search failed: %w", err)
+	}
+
+	docs := make([]SegmentDocument, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		m, ok := hit.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Hits decode as generic maps; round-trip through JSON to get the document
+		raw, err := json.Marshal(m)
+		if err != nil {
+			continue
+		}
+		var doc SegmentDocument
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// buildSegmentFilter limits segment search to the user's own and shared segments.
+func buildSegmentFilter(params SegmentSearchParams) string {
+	return fmt.Sprintf("(user_id = %d OR user_id = 0)", params.UserID)
+}
+
+// ClearSegments removes all documents from the segments index.
+func (c *Client) ClearSegments() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	index := c.client.Index(c.segmentsIndexName())
+	task, err := index.DeleteAllDocuments()
+	if err != nil {
+		return fmt.Errorf("failed to clear segments index: %w", err)
+	}
+
+	if _, err := c.client.WaitForTask(task.TaskUID, meili.WaitParams{Context: ctx, Interval: 100 * time.Millisecond}); err != nil {
+		return fmt.Errorf("failed to wait for segments clear task: %w", err)
+	}
+
+	c.logger.Info("cleared meilisearch segments index", zap.String("index", c.segmentsIndexName()))
+	return nil
+}
+
+// Stats returns the document count, pending task count and the outcome of the
+// latest document writes for the scenes index.
+func (c *Client) Stats() (*IndexStats, error) {
+	index := c.client.Index(c.indexName)
+	indexStats, err := index.GetStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index stats: %w", err)
+	}
+
+	stats := &IndexStats{
+		DocumentCount: indexStats.NumberOfDocuments,
+		IsIndexing:    indexStats.IsIndexing,
+	}
+
+	pending, err := c.client.GetTasks(&meili.TasksQuery{
+		IndexUIDS: []string{c.indexName},
+		Statuses:  []meili.TaskStatus{meili.TaskStatusEnqueued, meili.TaskStatusProcessing},
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending tasks: %w", err)
+	}
+	stats.PendingTasks = pending.Total
+
+	succeeded, err := c.client.GetTasks(&meili.TasksQuery{
+		IndexUIDS: []string{c.indexName},
+		Statuses:  []meili.TaskStatus{meili.TaskStatusSucceeded},
+		Types:     []meili.TaskType{meili.TaskTypeDocumentAdditionOrUpdate, meili.TaskTypeDocumentDeletion},
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get succeeded tasks: %w", err)
+	}
+	if len(succeeded.Results) > 0 {
+		finishedAt := succeeded.Results[0].FinishedAt
+		stats.LastWriteAt = &finishedAt
+	}
+
+	failed, err := c.client.GetTasks(&meili.TasksQuery{
+		IndexUIDS: []string{c.indexName},
+		Statuses:  []meili.TaskStatus{meili.TaskStatusFailed},
+		Limit:     1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed tasks: %w", err)
+	}
+	if len(failed.Results) > 0 {
+		finishedAt := failed.Results[0].FinishedAt
+		stats.LastFailedAt = &finishedAt
+		stats.LastFailedError = failed.Results[0].Error.Message
+	}
+
+	return stats, nil
+}
+
+// HasDocument reports whether a scene document is present in the index.
+func (c *Client) HasDocument(id uint) (bool, error) {
+	index := c.client.Index(c.indexName)
+	var doc SceneDocument
+	err := index.GetDocument(fmt.Sprintf("%d", id), &meili.DocumentQuery{Fields: []string{"id"}}, &doc)
+	if err == nil {
+		return true, nil
+	}
+
+	var meiliErr *meili.Error
+	if errors.As(err, &meiliErr) && meiliErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to get scene document: %w", err)
+}
+
+// DocumentIDs returns the IDs of every scene document in the index.
+func (c *Client) DocumentIDs() ([]uint, error) {
+	const pageSize = 1000
+	index := c.client.Index(c.indexName)
+
+	var ids []uint
+	for offset := int64(0); ; offset += pageSize {
+		var page meili.DocumentsResult
+		if err := index.GetDocuments(&meili.DocumentsQuery{Offset: offset, Limit: pageSize, Fields: []string{"id"}}, &page); err != nil {
+			return nil, fmt.Errorf("failed to list scene documents: %w", err)
+		}
+		for _, doc := range page.Results {
+			// Document IDs decode from JSON as float64
+			if id, ok := doc["id"].(float64); ok {
+				ids = append(ids, uint(id))
+			}
+		}
+		if len(page.Results) < pageSize {
+			return ids, nil
+		}
+	}
+}
+
 // Health checks if Meilisearch is healthy.
 func (c *Client) Health() error {
 	health, err := c.client.Health()
@@ -28,6 +28,15 @@ func TestClient_buildFilters(t *testing.T) {
 			expectedLen:    3,
 			expectContains: []string{"tag_ids = 1", "tag_ids = 2", "tag_ids = 3"},
 		},
+		{
+			name: "excluded tag IDs filter",
+			params: SearchParams{
+				TagIDs:        []uint{1},
+				ExcludeTagIDs: []uint{4, 5},
+			},
+			expectedLen:    3,
+			expectContains: []string{"tag_ids = 1", "NOT tag_ids = 4", "NOT tag_ids = 5"},
+		},
 		{
 			name: "studio filter",
 			params: SearchParams{
@@ -36,6 +45,15 @@ func TestClient_buildFilters(t *testing.T) {
 			expectedLen:    1,
 			expectContains: []string{`studio = "Test Studio"`},
 		},
+		{
+			name: "studio filter with sub-studios",
+			params: SearchParams{
+				Studio:     "Network",
+				SubStudios: []string{"Site A", "Site B"},
+			},
+			expectedLen:    1,
+			expectContains: []string{`(studio = "Network" OR studio = "Site A" OR studio = "Site B")`},
+		},
 		{
 			name: "duration range",
 			params: SearchParams{
@@ -62,6 +80,28 @@ func TestClient_buildFilters(t *testing.T) {
 			expectedLen:    1,
 			expectContains: []string{"(id = 1 OR id = 2 OR id = 3)"},
 		},
+		{
+			name: "keyset after created_at",
+			params: SearchParams{
+				Sort:    "created_at",
+				SortDir: "desc",
+				Keyset:  true,
+				After:   &SortKey{Value: 1700000000.75, ID: 42},
+			},
+			expectedLen:    1,
+			expectContains: []string{"(created_at < 1700000000 OR (created_at = 1700000000 AND id < 42))"},
+		},
+		{
+			name: "keyset after duration asc",
+			params: SearchParams{
+				Sort:    "duration",
+				SortDir: "asc",
+				Keyset:  true,
+				After:   &SortKey{Value: 90.5, ID: 7},
+			},
+			expectedLen:    1,
+			expectContains: []string{"(duration > 90.5 OR (duration = 90.5 AND id > 7))"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +166,11 @@ func TestClient_buildSort(t *testing.T) {
 			params:   SearchParams{Sort: "duration", SortDir: "desc"},
 			expected: []string{"duration:desc"},
 		},
+		{
+			name:     "keyset sort breaks ties on id",
+			params:   SearchParams{Sort: "created_at", SortDir: "desc", Keyset: true},
+			expected: []string{"created_at:desc", "id:desc"},
+		},
 		{
 			name:     "unknown sort field",
 			params:   SearchParams{Sort: "unknown"},
@@ -179,3 +224,10 @@ func floatPtr(f float64) *float64 {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestBuildSegmentFilter(t *testing.T) {
+	filter := buildSegmentFilter(SegmentSearchParams{Query: "kitchen", UserID: 7})
+	if filter != "(user_id = 7 OR user_id = 0)" {
+		t.Errorf("unexpected filter %q", filter)
+	}
+}
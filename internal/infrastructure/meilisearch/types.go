@@ -1,5 +1,7 @@
 package meilisearch
 
+import "time"
+
 // SceneDocument represents a scene document in Meilisearch.
 type SceneDocument struct {
 	ID               uint     `json:"id"`
@@ -9,8 +11,10 @@ type SceneDocument struct {
 	Description      string   `json:"description"`
 	Studio           string   `json:"studio"`
 	Actors           []string `json:"actors"`
+	ActorAliases     []string `json:"actor_aliases"`
 	TagIDs           []uint   `json:"tag_ids"`
 	TagNames         []string `json:"tag_names"`
+	TagAliases       []string `json:"tag_aliases"`
 	Duration         float64  `json:"duration"`
 	Height           int      `json:"height"`
 	CreatedAt        int64    `json:"created_at"`
@@ -22,8 +26,10 @@ type SceneDocument struct {
 type SearchParams struct {
 	Query            string
 	TagIDs           []uint
+	ExcludeTagIDs    []uint
 	Actors           []string
 	Studio           string
+	SubStudios       []string // studios under Studio, whose scenes match too
 	MinDuration      *float64
 	MaxDuration      *float64
 	MinHeight        *int
@@ -38,10 +44,58 @@ type SearchParams struct {
 	Limit            int
 	MatchingStrategy string // Meilisearch matching strategy: "last", "all", or "frequency"
 	FetchAllIDs      bool   // When true, fetch all matching IDs (ignore Offset/Limit, skip sort)
+	// Keyset pages by sort value and ID instead of Offset: hits are ordered by
+	// Sort and then ID in the same direction and start after After (nil for the
+	// first page). Only numeric sorts can be paged this way.
+	Keyset bool
+	After  *SortKey
+}
+
+// SortKey is the position of a hit in keyset order.
+type SortKey struct {
+	Value float64
+	ID    uint
 }
 
 // SearchResult contains the result of a search query.
 type SearchResult struct {
 	IDs        []uint
 	TotalCount int64
+	// Next is the position of the last hit in keyset mode, nil when no more hits follow
+	Next *SortKey
+}
+
+// IndexStats describes the state of the scenes index.
+type IndexStats struct {
+	DocumentCount   int64      `json:"document_count"`
+	IsIndexing      bool       `json:"is_indexing"`
+	PendingTasks    int64      `json:"pending_tasks"`               // enqueued or processing tasks for the index
+	LastWriteAt     *time.Time `json:"last_write_at"`               // finish time of the last successful document write
+	LastFailedAt    *time.Time `json:"last_failed_at"`              // finish time of the last failed task
+	LastFailedError string     `json:"last_failed_error,omitempty"` // error message of the last failed task
+}
+
+// Segment kinds. Subtitle cues are not indexed yet.
+const (
+	SegmentKindMarker   = "marker"
+	SegmentKindSubtitle = "subtitle"
+)
+
+// SegmentDocument is a searchable point in a scene, such as a marker label or a
+// subtitle cue. Segments live in their own index next to the scenes index.
+type SegmentDocument struct {
+	ID        string `json:"id"` // kind and source ID, e.g. "marker-12"
+	SceneID   uint   `json:"scene_id"`
+	UserID    uint   `json:"user_id"` // owner, 0 for segments every user can see
+	Kind      string `json:"kind"`
+	SourceID  uint   `json:"source_id"` // marker ID for markers
+	Text      string `json:"text"`
+	Timestamp int    `json:"timestamp"` // seconds
+}
+
+// SegmentSearchParams contains parameters for searching segments.
+type SegmentSearchParams struct {
+	Query  string
+	UserID uint // segments owned by this user and shared segments are searched
+	Limit  int
 }
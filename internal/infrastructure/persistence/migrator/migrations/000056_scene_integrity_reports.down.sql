@@ -0,0 +1,24 @@
+-- Remove default configs for verify
+DELETE FROM trigger_config WHERE phase = 'verify';
+DELETE FROM retry_config WHERE phase = 'verify';
+DELETE FROM job_history WHERE phase = 'verify';
+UPDATE trigger_config SET trigger_type = 'manual', after_phase = NULL
+  WHERE after_phase IN ('animated_thumbnails', 'verify');
+
+-- Restore CHECK constraints without verify
+ALTER TABLE trigger_config DROP CONSTRAINT IF EXISTS valid_phase;
+ALTER TABLE trigger_config ADD CONSTRAINT valid_phase
+  CHECK (phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'scan'));
+
+ALTER TABLE trigger_config DROP CONSTRAINT IF EXISTS valid_after_phase;
+ALTER TABLE trigger_config ADD CONSTRAINT valid_after_phase
+  CHECK (after_phase IS NULL OR after_phase IN ('metadata', 'thumbnail', 'sprites'));
+
+ALTER TABLE retry_config DROP CONSTRAINT IF EXISTS valid_retry_phase;
+ALTER TABLE retry_config ADD CONSTRAINT valid_retry_phase
+  CHECK (phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'scan'));
+
+-- Remove pool_config column
+ALTER TABLE pool_config DROP COLUMN IF EXISTS verify_workers;
+
+DROP TABLE IF EXISTS scene_integrity_reports;
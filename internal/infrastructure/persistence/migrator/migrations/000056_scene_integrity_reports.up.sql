@@ -0,0 +1,42 @@
+CREATE TABLE scene_integrity_reports (
+    id                BIGSERIAL PRIMARY KEY,
+    scene_id          BIGINT NOT NULL REFERENCES scenes(id) ON DELETE CASCADE,
+    job_id            VARCHAR(36) NOT NULL DEFAULT '',
+    is_corrupted      BOOLEAN NOT NULL DEFAULT FALSE,
+    decode_completed  BOOLEAN NOT NULL DEFAULT FALSE,
+    error_count       INTEGER NOT NULL DEFAULT 0,
+    decoded_seconds   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    errors            JSONB NOT NULL DEFAULT '[]',
+    unreadable_ranges JSONB NOT NULL DEFAULT '[]',
+    checked_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX idx_scene_integrity_reports_scene_id ON scene_integrity_reports(scene_id);
+
+-- pool_config: add verify workers
+ALTER TABLE pool_config ADD COLUMN verify_workers INTEGER NOT NULL DEFAULT 1;
+
+-- trigger_config: update CHECK constraint to include verify
+ALTER TABLE trigger_config DROP CONSTRAINT IF EXISTS valid_phase;
+ALTER TABLE trigger_config ADD CONSTRAINT valid_phase
+  CHECK (phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify', 'scan'));
+
+-- trigger_config: allow chaining after animated_thumbnails and verify
+ALTER TABLE trigger_config DROP CONSTRAINT IF EXISTS valid_after_phase;
+ALTER TABLE trigger_config ADD CONSTRAINT valid_after_phase
+  CHECK (after_phase IS NULL OR after_phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify'));
+
+-- retry_config: update CHECK constraint
+ALTER TABLE retry_config DROP CONSTRAINT IF EXISTS valid_retry_phase;
+ALTER TABLE retry_config ADD CONSTRAINT valid_retry_phase
+  CHECK (phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify', 'scan'));
+
+-- Default trigger config for verify (manual by default, full decodes are expensive)
+INSERT INTO trigger_config (phase, trigger_type) VALUES ('verify', 'manual')
+  ON CONFLICT DO NOTHING;
+
+-- Default retry config for verify
+INSERT INTO retry_config (phase, max_retries, initial_delay_seconds, max_delay_seconds, backoff_factor)
+  VALUES ('verify', 2, 60, 600, 2.0)
+  ON CONFLICT DO NOTHING;
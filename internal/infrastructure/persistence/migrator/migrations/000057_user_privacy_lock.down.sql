@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS privacy_lock_minutes;
+ALTER TABLE users DROP COLUMN IF EXISTS privacy_pin_hash;
@@ -0,0 +1,3 @@
+-- Per-user privacy lock: a short PIN required to resume browsing after idle time
+ALTER TABLE users ADD COLUMN privacy_pin_hash VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN privacy_lock_minutes INTEGER NOT NULL DEFAULT 15;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_scenes_porndb_scene_id;
+DROP TABLE IF EXISTS duplicate_group_scenes;
+DROP TABLE IF EXISTS duplicate_groups;
@@ -0,0 +1,25 @@
+-- Duplicate groups: sets of scenes flagged as likely copies of the same content
+CREATE TABLE duplicate_groups (
+    id          BIGSERIAL PRIMARY KEY,
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reason      VARCHAR(30) NOT NULL,
+    external_id TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT valid_duplicate_group_status CHECK (status IN ('pending', 'resolved', 'dismissed')),
+    CONSTRAINT valid_duplicate_group_reason CHECK (reason IN ('porndb_match'))
+);
+CREATE INDEX idx_duplicate_groups_status ON duplicate_groups(status, created_at DESC);
+CREATE UNIQUE INDEX idx_duplicate_groups_pending_external ON duplicate_groups(reason, external_id)
+    WHERE status = 'pending' AND external_id != '';
+
+CREATE TABLE duplicate_group_scenes (
+    group_id   BIGINT NOT NULL REFERENCES duplicate_groups(id) ON DELETE CASCADE,
+    scene_id   BIGINT NOT NULL REFERENCES scenes(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, scene_id)
+);
+CREATE INDEX idx_duplicate_group_scenes_scene_id ON duplicate_group_scenes(scene_id);
+
+-- Looks up scenes matched to the same PornDB scene
+CREATE INDEX idx_scenes_porndb_scene_id ON scenes(porndb_scene_id) WHERE porndb_scene_id != '';
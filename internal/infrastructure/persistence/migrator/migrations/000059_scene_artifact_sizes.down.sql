@@ -0,0 +1 @@
+DROP TABLE IF EXISTS scene_artifact_sizes;
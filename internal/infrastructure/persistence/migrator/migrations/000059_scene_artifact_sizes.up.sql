@@ -0,0 +1,9 @@
+-- Disk usage of generated artifacts (thumbnails, sprites, previews, marker clips) per scene
+CREATE TABLE scene_artifact_sizes (
+    scene_id        BIGINT PRIMARY KEY REFERENCES scenes(id) ON DELETE CASCADE,
+    thumbnail_bytes BIGINT NOT NULL DEFAULT 0,
+    sprite_bytes    BIGINT NOT NULL DEFAULT 0,
+    preview_bytes   BIGINT NOT NULL DEFAULT 0,
+    marker_bytes    BIGINT NOT NULL DEFAULT 0,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
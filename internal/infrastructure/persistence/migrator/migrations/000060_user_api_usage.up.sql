@@ -0,0 +1,13 @@
+-- Daily per-user API call counts and response bytes, aggregated by route
+CREATE TABLE user_api_usage (
+    user_id       BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    usage_date    DATE NOT NULL,
+    method        VARCHAR(10) NOT NULL,
+    route         VARCHAR(255) NOT NULL,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    error_count   BIGINT NOT NULL DEFAULT 0,
+    bytes_sent    BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, usage_date, method, route)
+);
+
+CREATE INDEX idx_user_api_usage_date ON user_api_usage (usage_date);
@@ -0,0 +1,3 @@
+ALTER TABLE app_settings DROP COLUMN global_stream_rate_limit_kbps;
+ALTER TABLE app_settings DROP COLUMN user_stream_rate_limit_kbps;
+ALTER TABLE app_settings DROP COLUMN stream_rate_limit_kbps;
@@ -0,0 +1,4 @@
+-- Streaming bandwidth limits in kilobits per second (0 = unlimited)
+ALTER TABLE app_settings ADD COLUMN stream_rate_limit_kbps INT NOT NULL DEFAULT 0;
+ALTER TABLE app_settings ADD COLUMN user_stream_rate_limit_kbps INT NOT NULL DEFAULT 0;
+ALTER TABLE app_settings ADD COLUMN global_stream_rate_limit_kbps INT NOT NULL DEFAULT 0;
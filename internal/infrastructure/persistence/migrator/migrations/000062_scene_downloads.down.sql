@@ -0,0 +1,9 @@
+-- Remove scenes:download permission from roles
+DELETE FROM role_permissions WHERE permission_id IN (
+    SELECT id FROM permissions WHERE name = 'scenes:download'
+);
+
+-- Remove scenes:download permission
+DELETE FROM permissions WHERE name = 'scenes:download';
+
+DROP TABLE IF EXISTS user_scene_downloads;
@@ -0,0 +1,25 @@
+-- Per-user download counters
+CREATE TABLE IF NOT EXISTS user_scene_downloads (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scene_id BIGINT NOT NULL REFERENCES scenes(id) ON DELETE CASCADE,
+    download_count INT NOT NULL DEFAULT 0,
+    last_downloaded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_user_scene_downloads UNIQUE (user_id, scene_id)
+);
+CREATE INDEX idx_user_scene_downloads_user_last ON user_scene_downloads(user_id, last_downloaded_at DESC);
+CREATE INDEX idx_user_scene_downloads_scene_id ON user_scene_downloads(scene_id);
+
+-- Add scenes:download permission
+INSERT INTO permissions (name, description, created_at)
+VALUES ('scenes:download', 'Download original scene files', NOW())
+ON CONFLICT (name) DO NOTHING;
+
+-- Grant scenes:download to every role that can already view (and so stream) scenes
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT rp.role_id, p.id FROM role_permissions rp
+JOIN permissions v ON v.id = rp.permission_id AND v.name = 'scenes:view'
+CROSS JOIN permissions p
+WHERE p.name = 'scenes:download'
+ON CONFLICT DO NOTHING;
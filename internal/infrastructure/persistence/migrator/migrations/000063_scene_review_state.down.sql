@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_scenes_review_state;
+
+ALTER TABLE scenes DROP COLUMN IF EXISTS review_state_updated_at;
+ALTER TABLE scenes DROP COLUMN IF EXISTS review_state;
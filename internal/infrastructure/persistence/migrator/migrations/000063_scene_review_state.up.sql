@@ -0,0 +1,6 @@
+-- Review workflow state per scene. Empty means the workflow's initial state, so
+-- renaming the first configured state does not require rewriting every scene.
+ALTER TABLE scenes ADD COLUMN review_state VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE scenes ADD COLUMN review_state_updated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_scenes_review_state ON scenes(review_state) WHERE trashed_at IS NULL;
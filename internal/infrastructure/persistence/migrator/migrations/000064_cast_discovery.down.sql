@@ -0,0 +1 @@
+ALTER TABLE app_settings DROP COLUMN IF EXISTS cast_discovery_enabled;
@@ -0,0 +1,2 @@
+-- Casting toggle: when enabled, scenes expose tokenized cast URLs for Chromecast/DLNA renderers
+ALTER TABLE app_settings ADD COLUMN cast_discovery_enabled BOOLEAN NOT NULL DEFAULT FALSE;
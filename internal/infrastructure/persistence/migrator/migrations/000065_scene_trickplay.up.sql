@@ -0,0 +1,2 @@
+-- BIF trickplay file generated alongside sprite sheets for Roku/Plex-style scrubbing
+ALTER TABLE scenes ADD COLUMN trickplay_path VARCHAR(512) NOT NULL DEFAULT '';
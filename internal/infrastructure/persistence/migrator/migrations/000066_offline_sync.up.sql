@@ -0,0 +1,14 @@
+-- Scenes a user keeps available offline in a mobile client. Removed scenes stay
+-- as tombstones (removed_at) so delta manifests can report them. scene_id has no
+-- foreign key on purpose: rows of deleted scenes must survive to become tombstones.
+CREATE TABLE IF NOT EXISTS user_offline_scenes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scene_id BIGINT NOT NULL,
+    checksum VARCHAR(64) NOT NULL DEFAULT '',
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    removed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_user_offline_scenes UNIQUE (user_id, scene_id)
+);
+CREATE INDEX idx_user_offline_scenes_user_changed ON user_offline_scenes(user_id, changed_at);
@@ -0,0 +1,13 @@
+-- Checkpoint of the last full search reindex, so an interrupted rebuild can resume
+CREATE TABLE IF NOT EXISTS search_reindex_checkpoint (
+    id INTEGER PRIMARY KEY DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    last_scene_id BIGINT NOT NULL DEFAULT 0,
+    indexed INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT search_reindex_checkpoint_singleton CHECK (id = 1)
+);
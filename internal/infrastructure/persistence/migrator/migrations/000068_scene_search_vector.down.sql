@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scenes_search_vector;
+ALTER TABLE scenes DROP COLUMN IF EXISTS search_vector;
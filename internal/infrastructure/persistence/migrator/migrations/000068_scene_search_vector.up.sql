@@ -0,0 +1,10 @@
+-- Full-text vector for the PostgreSQL search backend, used when Meilisearch is
+-- disabled or unreachable. Actor and tag names live in join tables and are
+-- matched separately at query time.
+ALTER TABLE scenes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple'::regconfig, coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple'::regconfig, coalesce(studio, '')), 'B') ||
+    setweight(to_tsvector('simple'::regconfig, coalesce(original_filename, '')), 'C') ||
+    setweight(to_tsvector('simple'::regconfig, coalesce(description, '')), 'D')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_scenes_search_vector ON scenes USING GIN (search_vector);
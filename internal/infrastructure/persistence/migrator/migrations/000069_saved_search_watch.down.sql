@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS saved_search_matches;
+DROP INDEX IF EXISTS idx_saved_searches_watched;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS last_checked_at;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS watched_at;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS watched;
@@ -0,0 +1,17 @@
+-- Watched saved searches are re-run periodically to find newly added matching scenes
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS watched BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS watched_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS last_checked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_watched ON saved_searches(watched) WHERE watched;
+
+-- Scenes a watched search found since it started watching; unseen until the user opens the search
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    saved_search_id BIGINT NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    scene_id BIGINT NOT NULL REFERENCES scenes(id) ON DELETE CASCADE,
+    matched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    seen_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (saved_search_id, scene_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_unseen ON saved_search_matches(saved_search_id) WHERE seen_at IS NULL;
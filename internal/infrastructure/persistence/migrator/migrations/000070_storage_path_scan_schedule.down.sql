@@ -0,0 +1,3 @@
+ALTER TABLE scan_history DROP COLUMN IF EXISTS storage_path_ids;
+ALTER TABLE storage_paths DROP COLUMN IF EXISTS scan_schedule_enabled;
+ALTER TABLE storage_paths DROP COLUMN IF EXISTS scan_schedule;
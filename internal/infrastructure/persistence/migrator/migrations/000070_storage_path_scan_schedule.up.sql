@@ -0,0 +1,6 @@
+-- Per storage path scan schedules (5-field cron expression)
+ALTER TABLE storage_paths ADD COLUMN IF NOT EXISTS scan_schedule VARCHAR(100);
+ALTER TABLE storage_paths ADD COLUMN IF NOT EXISTS scan_schedule_enabled BOOLEAN NOT NULL DEFAULT false;
+
+-- Storage paths covered by a scan; NULL means all of them
+ALTER TABLE scan_history ADD COLUMN IF NOT EXISTS storage_path_ids BIGINT[];
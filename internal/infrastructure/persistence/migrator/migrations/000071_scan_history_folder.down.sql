@@ -0,0 +1 @@
+ALTER TABLE scan_history DROP COLUMN IF EXISTS folder_path;
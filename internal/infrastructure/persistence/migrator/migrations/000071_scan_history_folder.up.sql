@@ -0,0 +1,2 @@
+-- Folder of the single scoped storage path a scan covered; NULL means the whole path
+ALTER TABLE scan_history ADD COLUMN IF NOT EXISTS folder_path VARCHAR(500);
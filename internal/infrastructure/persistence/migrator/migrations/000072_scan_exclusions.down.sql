@@ -0,0 +1,2 @@
+ALTER TABLE storage_paths DROP COLUMN IF EXISTS exclude_patterns;
+DROP TABLE IF EXISTS scan_config;
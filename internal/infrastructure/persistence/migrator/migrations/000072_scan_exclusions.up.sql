@@ -0,0 +1,14 @@
+-- Global scan exclusion rules (singleton)
+CREATE TABLE IF NOT EXISTS scan_config (
+    id INTEGER PRIMARY KEY DEFAULT 1,
+    min_file_size_bytes BIGINT NOT NULL DEFAULT 0,
+    ignore_samples BOOLEAN NOT NULL DEFAULT false,
+    ignore_hidden_dirs BOOLEAN NOT NULL DEFAULT true,
+    exclude_patterns TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT scan_config_singleton CHECK (id = 1)
+);
+INSERT INTO scan_config (id) VALUES (1) ON CONFLICT DO NOTHING;
+
+-- Glob patterns excluded from scans of a single storage path
+ALTER TABLE storage_paths ADD COLUMN IF NOT EXISTS exclude_patterns TEXT[] NOT NULL DEFAULT '{}';
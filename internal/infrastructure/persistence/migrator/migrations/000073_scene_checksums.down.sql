@@ -0,0 +1,27 @@
+-- Remove default configs for checksum
+DELETE FROM trigger_config WHERE phase = 'checksum';
+DELETE FROM retry_config WHERE phase = 'checksum';
+DELETE FROM job_history WHERE phase = 'checksum';
+UPDATE trigger_config SET trigger_type = 'manual', after_phase = NULL
+  WHERE after_phase = 'checksum';
+
+-- Restore CHECK constraints without checksum
+ALTER TABLE trigger_config DROP CONSTRAINT IF EXISTS valid_phase;
+ALTER TABLE trigger_config ADD CONSTRAINT valid_phase
+  CHECK (phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify', 'scan'));
+
+ALTER TABLE trigger_config DROP CONSTRAINT IF EXISTS valid_after_phase;
+ALTER TABLE trigger_config ADD CONSTRAINT valid_after_phase
+  CHECK (after_phase IS NULL OR after_phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify'));
+
+ALTER TABLE retry_config DROP CONSTRAINT IF EXISTS valid_retry_phase;
+ALTER TABLE retry_config ADD CONSTRAINT valid_retry_phase
+  CHECK (phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify', 'scan'));
+
+-- Remove pool_config column
+ALTER TABLE pool_config DROP COLUMN IF EXISTS checksum_workers;
+
+DROP INDEX IF EXISTS idx_scenes_checksum_mismatch;
+DROP INDEX IF EXISTS idx_scenes_file_hash;
+ALTER TABLE scenes DROP COLUMN IF EXISTS hash_verified_at;
+ALTER TABLE scenes DROP COLUMN IF EXISTS checksum_mismatch;
@@ -0,0 +1,31 @@
+-- scenes: checksum verification state (file_hash already exists)
+ALTER TABLE scenes ADD COLUMN checksum_mismatch BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE scenes ADD COLUMN hash_verified_at TIMESTAMPTZ;
+CREATE INDEX idx_scenes_file_hash ON scenes(file_hash) WHERE file_hash <> '';
+CREATE INDEX idx_scenes_checksum_mismatch ON scenes(checksum_mismatch) WHERE checksum_mismatch;
+
+-- pool_config: add checksum workers
+ALTER TABLE pool_config ADD COLUMN checksum_workers INTEGER NOT NULL DEFAULT 1;
+
+-- trigger_config: update CHECK constraint to include checksum
+ALTER TABLE trigger_config DROP CONSTRAINT IF EXISTS valid_phase;
+ALTER TABLE trigger_config ADD CONSTRAINT valid_phase
+  CHECK (phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify', 'checksum', 'scan'));
+
+ALTER TABLE trigger_config DROP CONSTRAINT IF EXISTS valid_after_phase;
+ALTER TABLE trigger_config ADD CONSTRAINT valid_after_phase
+  CHECK (after_phase IS NULL OR after_phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify', 'checksum'));
+
+-- retry_config: update CHECK constraint
+ALTER TABLE retry_config DROP CONSTRAINT IF EXISTS valid_retry_phase;
+ALTER TABLE retry_config ADD CONSTRAINT valid_retry_phase
+  CHECK (phase IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify', 'checksum', 'scan'));
+
+-- Default trigger config for checksum (manual by default, hashing reads every byte)
+INSERT INTO trigger_config (phase, trigger_type) VALUES ('checksum', 'manual')
+  ON CONFLICT DO NOTHING;
+
+-- Default retry config for checksum
+INSERT INTO retry_config (phase, max_retries, initial_delay_seconds, max_delay_seconds, backoff_factor)
+  VALUES ('checksum', 2, 60, 600, 2.0)
+  ON CONFLICT DO NOTHING;
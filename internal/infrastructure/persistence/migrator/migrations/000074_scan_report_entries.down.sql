@@ -0,0 +1 @@
+DROP TABLE IF EXISTS scan_report_entries;
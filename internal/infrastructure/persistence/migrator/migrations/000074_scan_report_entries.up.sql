@@ -0,0 +1,12 @@
+CREATE TABLE scan_report_entries (
+    id         BIGSERIAL PRIMARY KEY,
+    scan_id    INTEGER NOT NULL REFERENCES scan_history(id) ON DELETE CASCADE,
+    kind       VARCHAR(20) NOT NULL,
+    path       TEXT NOT NULL,
+    old_path   TEXT NOT NULL DEFAULT '',
+    scene_id   BIGINT,
+    message    TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT valid_scan_report_kind CHECK (kind IN ('added', 'moved', 'removed', 'error'))
+);
+CREATE INDEX idx_scan_report_entries_scan_kind ON scan_report_entries(scan_id, kind, id);
@@ -0,0 +1,13 @@
+CREATE TABLE folder_rules (
+    id              SERIAL PRIMARY KEY,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    name            VARCHAR(255) NOT NULL,
+    storage_path_id INTEGER REFERENCES storage_paths(id) ON DELETE CASCADE,
+    pattern         VARCHAR(255) NOT NULL,
+    tag_ids         BIGINT[] NOT NULL DEFAULT '{}',
+    actor_ids       BIGINT[] NOT NULL DEFAULT '{}',
+    studio_id       BIGINT REFERENCES studios(id) ON DELETE SET NULL,
+    scene_type      VARCHAR(50) NOT NULL DEFAULT '',
+    enabled         BOOLEAN NOT NULL DEFAULT TRUE
+);
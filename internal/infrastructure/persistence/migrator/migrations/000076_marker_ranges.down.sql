@@ -0,0 +1,2 @@
+ALTER TABLE user_scene_markers DROP CONSTRAINT IF EXISTS chk_user_scene_markers_range;
+ALTER TABLE user_scene_markers DROP COLUMN IF EXISTS end_timestamp;
@@ -0,0 +1,4 @@
+-- user_scene_markers: optional end of the range a marker describes
+ALTER TABLE user_scene_markers ADD COLUMN end_timestamp INTEGER;
+ALTER TABLE user_scene_markers ADD CONSTRAINT chk_user_scene_markers_range
+  CHECK (end_timestamp IS NULL OR end_timestamp > timestamp);
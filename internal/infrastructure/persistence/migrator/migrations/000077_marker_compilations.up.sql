@@ -0,0 +1,18 @@
+CREATE TABLE marker_compilations (
+    id BIGSERIAL PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    marker_ids BIGINT[] NOT NULL DEFAULT '{}',
+    clip_seconds INTEGER NOT NULL DEFAULT 10,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    progress INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    duration INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_marker_compilations_job_id ON marker_compilations (job_id);
+CREATE INDEX idx_marker_compilations_user_id ON marker_compilations (user_id, created_at DESC);
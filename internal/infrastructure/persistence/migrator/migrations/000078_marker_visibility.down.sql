@@ -0,0 +1,11 @@
+-- Remove shared marker permissions from roles
+DELETE FROM role_permissions WHERE permission_id IN (
+    SELECT id FROM permissions WHERE name IN ('markers:view_shared', 'markers:copy_shared')
+);
+
+-- Remove shared marker permissions
+DELETE FROM permissions WHERE name IN ('markers:view_shared', 'markers:copy_shared');
+
+DROP INDEX IF EXISTS idx_user_scene_markers_shared;
+ALTER TABLE user_scene_markers DROP CONSTRAINT IF EXISTS chk_user_scene_markers_visibility;
+ALTER TABLE user_scene_markers DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,20 @@
+-- user_scene_markers: markers can be shared with the other users of a scene
+ALTER TABLE user_scene_markers ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'private';
+ALTER TABLE user_scene_markers ADD CONSTRAINT chk_user_scene_markers_visibility
+  CHECK (visibility IN ('private', 'shared'));
+CREATE INDEX idx_user_scene_markers_shared ON user_scene_markers(scene_id, timestamp) WHERE visibility = 'shared';
+
+-- Add shared marker permissions
+INSERT INTO permissions (name, description, created_at)
+VALUES
+    ('markers:view_shared', 'View markers other users shared', NOW()),
+    ('markers:copy_shared', 'Copy markers other users shared into own markers', NOW())
+ON CONFLICT (name) DO NOTHING;
+
+-- Grant them to every role that can view scenes
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT rp.role_id, p.id FROM role_permissions rp
+JOIN permissions v ON v.id = rp.permission_id AND v.name = 'scenes:view'
+CROSS JOIN permissions p
+WHERE p.name IN ('markers:view_shared', 'markers:copy_shared')
+ON CONFLICT DO NOTHING;
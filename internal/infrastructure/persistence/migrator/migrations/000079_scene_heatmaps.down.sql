@@ -0,0 +1,2 @@
+ALTER TABLE scenes DROP COLUMN IF EXISTS heatmap_updated_at;
+ALTER TABLE scenes DROP COLUMN IF EXISTS heatmap;
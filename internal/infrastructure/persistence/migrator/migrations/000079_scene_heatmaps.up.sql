@@ -0,0 +1,3 @@
+-- scenes: popularity heatmap aggregated from markers and watch positions
+ALTER TABLE scenes ADD COLUMN heatmap INTEGER[];
+ALTER TABLE scenes ADD COLUMN heatmap_updated_at TIMESTAMPTZ;
@@ -0,0 +1 @@
+ALTER TABLE duplicate_groups DROP COLUMN IF EXISTS winner_scene_id;
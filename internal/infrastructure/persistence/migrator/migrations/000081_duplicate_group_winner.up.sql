@@ -0,0 +1,2 @@
+-- The scene kept when a duplicate group is resolved
+ALTER TABLE duplicate_groups ADD COLUMN IF NOT EXISTS winner_scene_id BIGINT REFERENCES scenes(id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+DELETE FROM duplicate_groups WHERE reason = 'file_hash';
+ALTER TABLE duplicate_groups DROP CONSTRAINT IF EXISTS valid_duplicate_group_reason;
+ALTER TABLE duplicate_groups ADD CONSTRAINT valid_duplicate_group_reason CHECK (reason IN ('porndb_match'));
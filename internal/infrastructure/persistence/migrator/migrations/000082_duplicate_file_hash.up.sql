@@ -0,0 +1,3 @@
+-- Byte-identical files (same checksum) are grouped without further matching
+ALTER TABLE duplicate_groups DROP CONSTRAINT IF EXISTS valid_duplicate_group_reason;
+ALTER TABLE duplicate_groups ADD CONSTRAINT valid_duplicate_group_reason CHECK (reason IN ('porndb_match', 'file_hash'));
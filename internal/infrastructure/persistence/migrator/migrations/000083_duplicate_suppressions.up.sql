@@ -0,0 +1,9 @@
+-- Scene pairs confirmed as distinct by dismissing a duplicate group; the lower ID comes first
+CREATE TABLE IF NOT EXISTS duplicate_suppressions (
+    scene_id_a BIGINT NOT NULL REFERENCES scenes(id) ON DELETE CASCADE,
+    scene_id_b BIGINT NOT NULL REFERENCES scenes(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scene_id_a, scene_id_b),
+    CONSTRAINT ordered_duplicate_suppression CHECK (scene_id_a < scene_id_b)
+);
+CREATE INDEX idx_duplicate_suppressions_scene_id_b ON duplicate_suppressions(scene_id_b);
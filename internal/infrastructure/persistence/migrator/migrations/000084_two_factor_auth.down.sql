@@ -0,0 +1,5 @@
+ALTER TABLE app_settings DROP COLUMN IF EXISTS require_admin_two_factor;
+
+ALTER TABLE users DROP COLUMN IF EXISTS totp_recovery_codes;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
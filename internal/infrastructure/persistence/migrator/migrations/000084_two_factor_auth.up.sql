@@ -0,0 +1,7 @@
+-- TOTP two-factor authentication. totp_secret holds the base32 secret (also while
+-- enrollment is pending); totp_recovery_codes holds SHA-256 hashes of unused codes.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_recovery_codes TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE app_settings ADD COLUMN IF NOT EXISTS require_admin_two_factor BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,10 @@
+-- Limits a role to part of the library. Each non-empty list narrows what the
+-- role's users can see: scenes must be in one of the storage paths, carry one
+-- of the tags and have one of the types. Roles without a row see everything.
+CREATE TABLE IF NOT EXISTS role_content_restrictions (
+    role_id BIGINT PRIMARY KEY REFERENCES roles(id) ON DELETE CASCADE,
+    storage_path_ids BIGINT[] NOT NULL DEFAULT '{}',
+    tag_ids BIGINT[] NOT NULL DEFAULT '{}',
+    scene_types TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+ALTER TABLE app_settings DROP COLUMN IF EXISTS open_registration_role;
+ALTER TABLE app_settings DROP COLUMN IF EXISTS open_registration;
+
+DROP TABLE IF EXISTS user_invites;
@@ -0,0 +1,21 @@
+-- Invites let admins hand out single-use registration links with a preset role.
+-- Only the SHA-256 of an invite token is stored.
+CREATE TABLE IF NOT EXISTS user_invites (
+    id BIGSERIAL PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    used_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_invites_token_hash ON user_invites(token_hash);
+CREATE INDEX IF NOT EXISTS idx_user_invites_created_at ON user_invites(created_at);
+
+-- Open registration lets anyone create an account without an invite
+ALTER TABLE app_settings ADD COLUMN IF NOT EXISTS open_registration BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE app_settings ADD COLUMN IF NOT EXISTS open_registration_role VARCHAR(50) NOT NULL DEFAULT 'user';
@@ -0,0 +1 @@
+ALTER TABLE share_links DROP COLUMN IF EXISTS password_hash;
@@ -0,0 +1,3 @@
+-- Share links can be protected with a password. Only the bcrypt hash is stored;
+-- an empty hash means the link has no password.
+ALTER TABLE share_links ADD COLUMN IF NOT EXISTS password_hash VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_scene_markers_user_label_id;
+DROP INDEX IF EXISTS idx_user_scene_markers_user_created_at;
+DROP INDEX IF EXISTS idx_job_history_started_at_id;
+DROP INDEX IF EXISTS idx_scenes_created_at_id;
@@ -0,0 +1,6 @@
+-- Composite indexes matching the (sort key, id) order of cursor-paged lists, so
+-- each page is a range scan instead of an offset walk.
+CREATE INDEX IF NOT EXISTS idx_scenes_created_at_id ON scenes(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_job_history_started_at_id ON job_history(started_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_user_scene_markers_user_created_at ON user_scene_markers(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_user_scene_markers_user_label_id ON user_scene_markers(user_id, label, id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS porndb_match_reviews;
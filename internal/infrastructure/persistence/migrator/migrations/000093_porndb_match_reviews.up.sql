@@ -0,0 +1,21 @@
+-- Outcome of the PornDB auto-match job for each scene it looked at. Confident
+-- matches are applied to the scene and recorded as applied; ambiguous ones
+-- wait as pending with their best candidates until an admin accepts or
+-- dismisses them. Scenes with a row are skipped by later runs.
+CREATE TABLE IF NOT EXISTS porndb_match_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    scene_id BIGINT NOT NULL REFERENCES scenes(id) ON DELETE CASCADE,
+    job_id VARCHAR(36) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    query TEXT NOT NULL DEFAULT '',
+    candidates JSONB NOT NULL DEFAULT '[]',
+    best_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    porndb_scene_id VARCHAR(255) NOT NULL DEFAULT '',
+    reviewed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_porndb_match_reviews_scene_id ON porndb_match_reviews(scene_id);
+CREATE INDEX IF NOT EXISTS idx_porndb_match_reviews_status ON porndb_match_reviews(status, best_score DESC);
@@ -0,0 +1,14 @@
+-- Cached PornDB API responses, keyed by lookup, so repeated searches and
+-- detail fetches for the same performer, scene or site don't hit the API
+-- again until they expire.
+CREATE TABLE IF NOT EXISTS porndb_cache (
+    key VARCHAR(512) PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    body JSONB NOT NULL,
+    hit_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_porndb_cache_kind ON porndb_cache(kind);
+CREATE INDEX IF NOT EXISTS idx_porndb_cache_expires_at ON porndb_cache(expires_at);
@@ -0,0 +1,2 @@
+ALTER TABLE porndb_match_reviews DROP COLUMN IF EXISTS provider;
+ALTER TABLE scenes DROP COLUMN IF EXISTS stashdb_scene_id;
@@ -0,0 +1,5 @@
+-- StashDB is a second metadata provider. Scenes record the StashDB scene they
+-- were matched to next to the PornDB one, and auto-match reviews record which
+-- provider their candidates came from.
+ALTER TABLE scenes ADD COLUMN IF NOT EXISTS stashdb_scene_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE porndb_match_reviews ADD COLUMN IF NOT EXISTS provider VARCHAR(20) NOT NULL DEFAULT 'porndb';
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS actor_porndb_syncs;
+DROP INDEX IF EXISTS idx_actors_porndb_id;
+ALTER TABLE actors DROP COLUMN IF EXISTS porndb_synced_values;
+ALTER TABLE actors DROP COLUMN IF EXISTS porndb_synced_at;
+ALTER TABLE actors DROP COLUMN IF EXISTS porndb_sync_enabled;
+ALTER TABLE actors DROP COLUMN IF EXISTS porndb_id;
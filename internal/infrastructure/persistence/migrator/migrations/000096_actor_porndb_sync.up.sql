@@ -0,0 +1,22 @@
+-- Actors linked to a PornDB performer are refreshed from PornDB on a schedule.
+-- porndb_synced_values holds the values the last sync wrote, so a field whose
+-- value no longer matches was edited locally and is left alone.
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS porndb_id VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS porndb_sync_enabled BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS porndb_synced_at TIMESTAMPTZ;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS porndb_synced_values JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_actors_porndb_id ON actors(porndb_id) WHERE porndb_id <> '';
+
+-- One row per actor sync, with what PornDB had that differed from the actor
+CREATE TABLE IF NOT EXISTS actor_porndb_syncs (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT NOT NULL REFERENCES actors(id) ON DELETE CASCADE,
+    porndb_id VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_actor_porndb_syncs_actor ON actor_porndb_syncs(actor_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_studios_network_id;
+DROP INDEX IF EXISTS idx_studios_parent_id;
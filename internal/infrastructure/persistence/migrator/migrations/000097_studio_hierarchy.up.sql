@@ -0,0 +1,8 @@
+-- Studios form a hierarchy through parent_id and network_id; a network's
+-- scenes include those of every studio under it, found by walking down.
+CREATE INDEX IF NOT EXISTS idx_studios_parent_id ON studios(parent_id) WHERE parent_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_studios_network_id ON studios(network_id) WHERE network_id IS NOT NULL;
+
+-- A studio cannot be its own parent or network
+UPDATE studios SET parent_id = NULL WHERE parent_id = id;
+UPDATE studios SET network_id = NULL WHERE network_id = id;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tags_aliases;
+ALTER TABLE tags DROP COLUMN IF EXISTS aliases;
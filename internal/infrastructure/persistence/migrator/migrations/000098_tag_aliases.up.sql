@@ -0,0 +1,3 @@
+-- Former names of renamed and merged tags, which still resolve to the tag
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS aliases TEXT[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS idx_tags_aliases ON tags USING GIN (aliases);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS storage_path_health_samples;
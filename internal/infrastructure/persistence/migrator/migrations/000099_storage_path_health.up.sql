@@ -0,0 +1,16 @@
+-- One row per storage path health check: whether the path was usable and its
+-- free space. The latest row is the path's current status.
+CREATE TABLE IF NOT EXISTS storage_path_health_samples (
+    id BIGSERIAL PRIMARY KEY,
+    storage_path_id INTEGER NOT NULL REFERENCES storage_paths(id) ON DELETE CASCADE,
+    checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    status VARCHAR(20) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    free_bytes BIGINT,
+    total_bytes BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_storage_path_health_samples_path_checked
+    ON storage_path_health_samples (storage_path_id, checked_at DESC);
+CREATE INDEX IF NOT EXISTS idx_storage_path_health_samples_checked
+    ON storage_path_health_samples (checked_at);
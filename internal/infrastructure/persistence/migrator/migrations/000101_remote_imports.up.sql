@@ -0,0 +1,22 @@
+-- URL imports: files downloaded into a storage path and imported like scanned
+-- files. Queued and interrupted downloads are picked up again on startup.
+CREATE TABLE IF NOT EXISTS remote_imports (
+    id SERIAL PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL UNIQUE,
+    url TEXT NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    storage_path_id INTEGER REFERENCES storage_paths(id) ON DELETE SET NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    filename TEXT NOT NULL DEFAULT '',
+    bytes_downloaded BIGINT NOT NULL DEFAULT 0,
+    total_bytes BIGINT,
+    scene_id INTEGER REFERENCES scenes(id) ON DELETE SET NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_remote_imports_status ON remote_imports (status);
+CREATE INDEX IF NOT EXISTS idx_remote_imports_created_at ON remote_imports (created_at DESC);
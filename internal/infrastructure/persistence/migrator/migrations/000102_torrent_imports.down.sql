@@ -0,0 +1 @@
+DROP TABLE IF EXISTS torrent_imports;
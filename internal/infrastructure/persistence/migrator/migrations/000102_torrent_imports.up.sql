@@ -0,0 +1,20 @@
+-- Torrent imports: the files of completed torrents imported from a torrent
+-- client, mapping each torrent to the scenes created from it. A file with a
+-- row is not imported again; failed rows are deleted to retry them.
+CREATE TABLE IF NOT EXISTS torrent_imports (
+    id SERIAL PRIMARY KEY,
+    client VARCHAR(20) NOT NULL,
+    torrent_hash VARCHAR(64) NOT NULL,
+    torrent_name TEXT NOT NULL DEFAULT '',
+    file_path TEXT NOT NULL,
+    destination TEXT NOT NULL DEFAULT '',
+    storage_path_id INTEGER REFERENCES storage_paths(id) ON DELETE SET NULL,
+    scene_id INTEGER REFERENCES scenes(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (torrent_hash, file_path)
+);
+
+CREATE INDEX IF NOT EXISTS idx_torrent_imports_scene_id ON torrent_imports (scene_id);
+CREATE INDEX IF NOT EXISTS idx_torrent_imports_created_at ON torrent_imports (created_at DESC);
@@ -0,0 +1,7 @@
+ALTER TABLE retry_config DROP CONSTRAINT IF EXISTS valid_jitter_percent;
+ALTER TABLE retry_config DROP CONSTRAINT IF EXISTS valid_backoff_strategy;
+ALTER TABLE retry_config DROP COLUMN IF EXISTS retry_unclassified;
+ALTER TABLE retry_config DROP COLUMN IF EXISTS always_retry_errors;
+ALTER TABLE retry_config DROP COLUMN IF EXISTS never_retry_errors;
+ALTER TABLE retry_config DROP COLUMN IF EXISTS jitter_percent;
+ALTER TABLE retry_config DROP COLUMN IF EXISTS backoff_strategy;
@@ -0,0 +1,14 @@
+-- Per-phase retry policies: backoff curve, jitter and error class rules
+ALTER TABLE retry_config ADD COLUMN backoff_strategy VARCHAR(20) NOT NULL DEFAULT 'exponential';
+ALTER TABLE retry_config ADD COLUMN jitter_percent INTEGER NOT NULL DEFAULT 20;
+ALTER TABLE retry_config ADD COLUMN never_retry_errors TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE retry_config ADD COLUMN always_retry_errors TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE retry_config ADD COLUMN retry_unclassified BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE retry_config ADD CONSTRAINT valid_backoff_strategy
+  CHECK (backoff_strategy IN ('exponential', 'linear', 'fixed'));
+ALTER TABLE retry_config ADD CONSTRAINT valid_jitter_percent
+  CHECK (jitter_percent BETWEEN 0 AND 100);
+
+-- A missing file won't reappear on its own; a timeout usually passes
+UPDATE retry_config SET never_retry_errors = '{not_found}', always_retry_errors = '{timeout}';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_job_history_completed_at;
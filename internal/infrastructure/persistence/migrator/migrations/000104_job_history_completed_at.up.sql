@@ -0,0 +1,2 @@
+-- Job analytics aggregate the jobs finished within a window
+CREATE INDEX IF NOT EXISTS idx_job_history_completed_at ON job_history (completed_at) WHERE completed_at IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Captured job-level log lines and ffmpeg output, one row per job run.
+-- Rows go with their job when job history retention deletes it.
+CREATE TABLE IF NOT EXISTS job_logs (
+    id BIGSERIAL PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL REFERENCES job_history(job_id) ON DELETE CASCADE,
+    content TEXT NOT NULL DEFAULT '',
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_logs_job_id ON job_logs (job_id);
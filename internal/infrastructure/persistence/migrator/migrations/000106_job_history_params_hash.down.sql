@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_job_history_active_key;
+
+-- Keep one active job per scene and phase so the old index can be rebuilt
+UPDATE job_history SET status = 'cancelled', completed_at = NOW()
+WHERE status IN ('pending', 'running')
+  AND id NOT IN (
+    SELECT MIN(id) FROM job_history
+    WHERE status IN ('pending', 'running')
+    GROUP BY scene_id, phase
+  );
+
+CREATE UNIQUE INDEX idx_job_history_scene_phase_active
+    ON job_history (scene_id, phase)
+    WHERE status IN ('pending', 'running');
+
+ALTER TABLE job_history DROP COLUMN params_hash;
@@ -0,0 +1,11 @@
+-- Jobs of the same scene and phase with different parameters are different
+-- work; the active-job key now includes a hash of them (data.JobParamsHash)
+ALTER TABLE job_history ADD COLUMN params_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+UPDATE job_history
+SET params_hash = encode(sha256(convert_to('force_target=' || force_target, 'UTF8')), 'hex');
+
+DROP INDEX IF EXISTS idx_job_history_scene_phase_active;
+CREATE UNIQUE INDEX idx_job_history_active_key
+    ON job_history (scene_id, phase, params_hash)
+    WHERE status IN ('pending', 'running');
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS job_failure_summaries;
+DROP TABLE IF EXISTS job_history_archive;
@@ -0,0 +1,37 @@
+-- Job history retention: finished jobs past processing.job_history_retention
+-- are deleted, or moved here first when processing.job_history_archive is on.
+-- Job logs are not archived.
+CREATE TABLE IF NOT EXISTS job_history_archive (
+    id BIGSERIAL PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL UNIQUE,
+    scene_id BIGINT NOT NULL,
+    scene_title VARCHAR(255) NOT NULL DEFAULT '',
+    phase VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    force_target VARCHAR(20) NOT NULL DEFAULT '',
+    error_message TEXT,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_history_archive_completed_at ON job_history_archive (completed_at);
+CREATE INDEX IF NOT EXISTS idx_job_history_archive_scene_id ON job_history_archive (scene_id);
+
+-- Failed and timed out jobs pruned by retention, counted per phase and error
+-- so the failure history outlives the rows.
+CREATE TABLE IF NOT EXISTS job_failure_summaries (
+    id BIGSERIAL PRIMARY KEY,
+    phase VARCHAR(20) NOT NULL,
+    error_message VARCHAR(500) NOT NULL,
+    failures BIGINT NOT NULL DEFAULT 0,
+    first_failed_at TIMESTAMPTZ NOT NULL,
+    last_failed_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (phase, error_message)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_failure_summaries_last_failed_at ON job_failure_summaries (last_failed_at DESC);
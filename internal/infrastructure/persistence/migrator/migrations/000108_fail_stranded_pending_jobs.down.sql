@@ -0,0 +1 @@
+-- The failed jobs are not put back in the pending queue, where nothing claims them
@@ -0,0 +1,11 @@
+-- Crash recovery used to requeue the running jobs of every phase, but only the
+-- processing phases are claimed from the pending queue. Jobs of the other
+-- phases stayed pending forever and held their active-job key; fail them as
+-- orphans, like recovery now does.
+UPDATE job_history
+SET status = 'failed',
+    error_message = 'Orphaned job recovered after server restart',
+    completed_at = NOW(),
+    is_retryable = true
+WHERE status = 'pending'
+  AND phase NOT IN ('metadata', 'thumbnail', 'sprites', 'animated_thumbnails', 'verify', 'checksum');
@@ -0,0 +1,7 @@
+-- Remove the marker processing permission from roles
+DELETE FROM role_permissions WHERE permission_id IN (
+    SELECT id FROM permissions WHERE name = 'markers:process'
+);
+
+-- Remove the marker processing permission
+DELETE FROM permissions WHERE name = 'markers:process';
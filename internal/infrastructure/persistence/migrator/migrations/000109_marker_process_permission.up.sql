@@ -0,0 +1,12 @@
+-- Add the permission to queue marker compilations and thumbnail regeneration
+INSERT INTO permissions (name, description, created_at)
+VALUES ('markers:process', 'Queue marker compilations and marker thumbnail regeneration', NOW())
+ON CONFLICT (name) DO NOTHING;
+
+-- Grant it to every role that can view scenes, which could queue them before
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT rp.role_id, p.id FROM role_permissions rp
+JOIN permissions v ON v.id = rp.permission_id AND v.name = 'scenes:view'
+CROSS JOIN permissions p
+WHERE p.name = 'markers:process'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE job_history DROP COLUMN IF EXISTS requeue_count;
@@ -0,0 +1,3 @@
+-- Crash requeues are counted apart from retries, so a job orphaned by a
+-- restart keeps every retry its retry policy allows
+ALTER TABLE job_history ADD COLUMN requeue_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,24 @@
+package migrator
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestLatestVersionMatchesNewestMigration(t *testing.T) {
+	files, err := fs.Glob(migrations, "migrations/*.up.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("expected embedded migrations, got %v (err %v)", files, err)
+	}
+
+	version, err := LatestVersion()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	newest := files[len(files)-1]
+	if want := fmt.Sprintf("migrations/%06d_", version); newest[:len(want)] != want {
+		t.Fatalf("expected latest version to match %s, got %d", newest, version)
+	}
+}
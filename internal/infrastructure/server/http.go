@@ -8,6 +8,7 @@ import (
 	"goonhub/internal/core"
 	"goonhub/internal/data"
 	"goonhub/internal/infrastructure/logging"
+	"goonhub/internal/version"
 	"net/http"
 	"os"
 	"os/signal"
@@ -37,6 +38,28 @@ type Server struct {
 	actorService      *core.ActorService
 	studioService     *core.StudioService
 	shareServer       *ShareServer
+	agentService      *core.AgentService
+	artifactService   *core.ArtifactService
+	apiUsageService   *core.APIUsageService
+	searchConsistency *core.SearchConsistencyService
+	markerService     *core.MarkerService
+	savedSearches     *core.SavedSearchService
+	storageWatcher    *core.StorageWatcherService
+	scanScheduler     *core.ScanScheduler
+	folderRules       *core.FolderRuleService
+	compilations      *core.MarkerCompilationService
+	heatmaps          *core.SceneHeatmapService
+	duplicates        *core.DuplicateService
+	audit             *core.AuditService
+	webhooks          *core.WebhookService
+	actorSync         *core.ActorSyncService
+	storageHealth     *core.StorageHealthService
+	dropbox           *core.DropboxImportService
+	remoteImports     *core.RemoteImportService
+	torrentImports    *core.TorrentImportService
+	migrations        *core.StorageMigrationService
+	jobRetention      *core.JobHistoryRetentionWorker
+	poolAutoscaler    *core.PoolAutoscaler
 	srv               *http.Server
 }
 
@@ -60,6 +83,28 @@ func NewHTTPServer(
 	actorService *core.ActorService,
 	studioService *core.StudioService,
 	shareServer *ShareServer,
+	agentService *core.AgentService,
+	artifactService *core.ArtifactService,
+	apiUsageService *core.APIUsageService,
+	searchConsistency *core.SearchConsistencyService,
+	markerService *core.MarkerService,
+	savedSearches *core.SavedSearchService,
+	storageWatcher *core.StorageWatcherService,
+	scanScheduler *core.ScanScheduler,
+	folderRules *core.FolderRuleService,
+	compilations *core.MarkerCompilationService,
+	heatmaps *core.SceneHeatmapService,
+	duplicates *core.DuplicateService,
+	audit *core.AuditService,
+	webhooks *core.WebhookService,
+	actorSync *core.ActorSyncService,
+	storageHealth *core.StorageHealthService,
+	dropbox *core.DropboxImportService,
+	remoteImports *core.RemoteImportService,
+	torrentImports *core.TorrentImportService,
+	migrations *core.StorageMigrationService,
+	jobRetention *core.JobHistoryRetentionWorker,
+	poolAutoscaler *core.PoolAutoscaler,
 ) *Server {
 	return &Server{
 		router:            router,
@@ -81,6 +126,28 @@ func NewHTTPServer(
 		actorService:      actorService,
 		studioService:     studioService,
 		shareServer:       shareServer,
+		agentService:      agentService,
+		artifactService:   artifactService,
+		apiUsageService:   apiUsageService,
+		searchConsistency: searchConsistency,
+		markerService:     markerService,
+		savedSearches:     savedSearches,
+		storageWatcher:    storageWatcher,
+		scanScheduler:     scanScheduler,
+		folderRules:       folderRules,
+		compilations:      compilations,
+		heatmaps:          heatmaps,
+		duplicates:        duplicates,
+		audit:             audit,
+		webhooks:          webhooks,
+		actorSync:         actorSync,
+		storageHealth:     storageHealth,
+		dropbox:           dropbox,
+		remoteImports:     remoteImports,
+		torrentImports:    torrentImports,
+		migrations:        migrations,
+		jobRetention:      jobRetention,
+		poolAutoscaler:    poolAutoscaler,
 	}
 }
 
@@ -103,6 +170,9 @@ func (s *Server) Start() error {
 		if s.scanService != nil {
 			s.scanService.SetIndexer(s.searchService)
 		}
+		if s.folderRules != nil {
+			s.folderRules.SetIndexer(s.searchService)
+		}
 		if s.explorerService != nil {
 			s.explorerService.SetIndexer(s.searchService)
 			s.explorerService.SetSearchService(s.searchService)
@@ -113,6 +183,12 @@ func (s *Server) Start() error {
 		if s.studioService != nil {
 			s.studioService.SetIndexer(s.searchService)
 		}
+		if s.markerService != nil {
+			s.markerService.SetIndexer(s.searchService)
+		}
+		if s.duplicates != nil {
+			s.duplicates.SetIndexer(s.searchService)
+		}
 		s.logger.Info("Search indexer wired to services")
 	}
 
@@ -129,7 +205,81 @@ func (s *Server) Start() error {
 	// Configure job queue feeder with shutdown config timeouts
 	if s.jobQueueFeeder != nil {
 		s.jobQueueFeeder.SetOrphanTimeout(s.cfg.Shutdown.OrphanTimeout)
-		s.jobQueueFeeder.SetStuckPendingTime(s.cfg.Shutdown.StuckPendingTime)
+		s.jobQueueFeeder.SetMaxRequeues(s.cfg.Shutdown.MaxRequeues)
+	}
+
+	// Track generated artifact sizes and hold back sprite/preview jobs over the metadata quota
+	if s.artifactService != nil {
+		if s.processingService != nil {
+			s.processingService.SetArtifactRecorder(s.artifactService)
+		}
+		if s.jobQueueFeeder != nil {
+			s.jobQueueFeeder.SetArtifactService(s.artifactService)
+		}
+	}
+
+	// Job runs leave their log in job history
+	if s.jobHistoryService != nil && s.processingService != nil {
+		s.processingService.SetJobLogRecorder(s.jobHistoryService)
+	}
+
+	// Bulk marker thumbnail generation runs through the processing pools
+	if s.markerService != nil && s.processingService != nil {
+		s.markerService.SetThumbnailQueue(s.processingService)
+	}
+
+	// Merging a duplicate group trashes the losers through the scene service
+	if s.duplicates != nil && s.sceneService != nil {
+		s.duplicates.SetSceneTrasher(s.sceneService)
+	}
+
+	// Scenes with the same checksum are flagged as duplicates once it is computed
+	if s.duplicates != nil && s.processingService != nil {
+		s.processingService.SetDuplicateFlagger(s.duplicates)
+	}
+
+	// Scans and the job feeder leave offline storage paths alone; checking
+	// starts before both so a path unmounted while down is caught first
+	if s.storageHealth != nil {
+		if s.scanService != nil {
+			s.scanService.SetStorageHealth(s.storageHealth)
+		}
+		if s.jobQueueFeeder != nil {
+			s.jobQueueFeeder.SetStorageHealth(s.storageHealth)
+		}
+		if s.dropbox != nil {
+			s.dropbox.SetStorageHealth(s.storageHealth)
+		}
+		if s.remoteImports != nil {
+			s.remoteImports.SetStorageHealth(s.storageHealth)
+		}
+		if s.torrentImports != nil {
+			s.torrentImports.SetStorageHealth(s.storageHealth)
+		}
+		if s.migrations != nil {
+			s.migrations.SetStorageHealth(s.storageHealth)
+		}
+		s.storageHealth.Start()
+	}
+
+	if s.apiUsageService != nil {
+		s.apiUsageService.Start()
+	}
+
+	if s.audit != nil {
+		s.audit.Start()
+	}
+
+	if s.webhooks != nil {
+		s.webhooks.Start()
+	}
+
+	// Let worker pools dispatch remote-capable jobs to registered agents
+	if s.agentService != nil && s.agentService.Enabled() {
+		s.agentService.Start()
+		if s.processingService != nil {
+			s.processingService.SetRemoteExecutor(s.agentService)
+		}
 	}
 
 	if s.processingService != nil {
@@ -142,14 +292,58 @@ func (s *Server) Start() error {
 		s.jobQueueFeeder.Start()
 	}
 
-	if s.jobHistoryService != nil {
-		s.jobHistoryService.StartCleanupTicker()
+	if s.poolAutoscaler != nil {
+		s.poolAutoscaler.Start()
+	}
+
+	if s.jobRetention != nil {
+		s.jobRetention.Start()
 	}
 
 	if s.triggerScheduler != nil {
 		s.triggerScheduler.Start()
 	}
 
+	if s.searchConsistency != nil {
+		s.searchConsistency.Start()
+	}
+
+	if s.savedSearches != nil {
+		s.savedSearches.Start()
+	}
+
+	if s.storageWatcher != nil {
+		s.storageWatcher.Start()
+	}
+
+	if s.dropbox != nil {
+		s.dropbox.Start()
+	}
+
+	if s.remoteImports != nil {
+		s.remoteImports.Start()
+	}
+
+	if s.torrentImports != nil {
+		s.torrentImports.Start()
+	}
+
+	if s.scanScheduler != nil {
+		s.scanScheduler.Start()
+	}
+
+	if s.compilations != nil {
+		s.compilations.Start()
+	}
+
+	if s.heatmaps != nil {
+		s.heatmaps.Start()
+	}
+
+	if s.actorSync != nil {
+		s.actorSync.Start()
+	}
+
 	// Wire up retry scheduler and DLQ service to processing service
 	if s.retryScheduler != nil {
 		s.retryScheduler.SetProcessingService(s.processingService)
@@ -191,12 +385,13 @@ func (s *Server) Start() error {
 			s.logger.Info("Starting HTTPS server",
 				zap.String("port", s.cfg.Server.Port),
 				zap.String("cert", s.cfg.Server.TLSCertFile),
+				zap.String("version", version.Version),
 			)
 			if err := s.srv.ListenAndServeTLS(s.cfg.Server.TLSCertFile, s.cfg.Server.TLSKeyFile); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				s.logger.Fatal("HTTPS server start failed", zap.Error(err))
 			}
 		} else {
-			s.logger.Info("Starting HTTP server", zap.String("port", s.cfg.Server.Port))
+			s.logger.Info("Starting HTTP server", zap.String("port", s.cfg.Server.Port), zap.String("version", version.Version))
 			if s.cfg.Environment == "production" {
 				s.logger.Warn("Running HTTP without TLS in production - configure tls_cert_file and tls_key_file for HTTPS")
 			}
@@ -232,11 +427,46 @@ func (s *Server) Start() error {
 		s.logger.Info("Job queue feeder stopped")
 	}
 
+	// Pools are not resized while they drain
+	if s.poolAutoscaler != nil {
+		s.poolAutoscaler.Stop()
+	}
+
 	if s.triggerScheduler != nil {
 		s.triggerScheduler.Stop()
 		s.logger.Info("Trigger scheduler stopped")
 	}
 
+	if s.storageWatcher != nil {
+		s.storageWatcher.Stop()
+		s.logger.Info("Storage watcher stopped")
+	}
+
+	if s.dropbox != nil {
+		s.dropbox.Stop()
+	}
+
+	if s.remoteImports != nil {
+		s.remoteImports.Stop()
+	}
+
+	if s.torrentImports != nil {
+		s.torrentImports.Stop()
+	}
+
+	if s.migrations != nil {
+		s.migrations.Stop()
+	}
+
+	if s.scanScheduler != nil {
+		s.scanScheduler.Stop()
+	}
+
+	if s.compilations != nil {
+		s.compilations.Stop()
+		s.logger.Info("Marker compilation worker stopped")
+	}
+
 	if s.retryScheduler != nil {
 		s.retryScheduler.Stop()
 		s.logger.Info("Retry scheduler stopped")
@@ -303,8 +533,32 @@ func (s *Server) Start() error {
 	// ---------------------------------------------------------------------------
 	s.logger.Info("PHASE 4: Final cleanup...")
 
-	if s.jobHistoryService != nil {
-		s.jobHistoryService.StopCleanupTicker()
+	if s.jobRetention != nil {
+		s.jobRetention.Stop()
+	}
+
+	if s.agentService != nil {
+		s.agentService.Stop()
+	}
+
+	if s.searchConsistency != nil {
+		s.searchConsistency.Stop()
+	}
+
+	if s.savedSearches != nil {
+		s.savedSearches.Stop()
+	}
+
+	if s.heatmaps != nil {
+		s.heatmaps.Stop()
+	}
+
+	if s.actorSync != nil {
+		s.actorSync.Stop()
+	}
+
+	if s.storageHealth != nil {
+		s.storageHealth.Stop()
 	}
 
 	// Shutdown HTTP servers with remaining graceful timeout
@@ -319,6 +573,19 @@ func (s *Server) Start() error {
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
 
+	// Flush usage counted for the requests drained above
+	if s.apiUsageService != nil {
+		s.apiUsageService.Stop()
+	}
+
+	if s.audit != nil {
+		s.audit.Stop()
+	}
+
+	if s.webhooks != nil {
+		s.webhooks.Stop()
+	}
+
 	s.logger.Info("Server shutdown complete")
 	return nil
 }
@@ -6,15 +6,18 @@ import (
 	"sync/atomic"
 	"time"
 
+	"goonhub/internal/data"
+
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // AnimatedThumbnailGenerator generates animated preview clips for scene markers
 // and scene preview videos. Defined here to avoid circular imports between jobs and core packages.
+// The optional onProgress callbacks receive the progress of each step as a percentage.
 type AnimatedThumbnailGenerator interface {
-	GenerateMissingAnimatedForScene(ctx context.Context, sceneID uint, forceTarget string) (int, error)
-	GenerateScenePreview(ctx context.Context, sceneID uint, forceTarget string) error
+	GenerateMissingAnimatedForScene(ctx context.Context, sceneID uint, forceTarget string, onProgress func(percent int)) (int, error)
+	GenerateScenePreview(ctx context.Context, sceneID uint, forceTarget string, onProgress func(percent int)) error
 }
 
 type AnimatedThumbnailJob struct {
@@ -28,6 +31,7 @@ type AnimatedThumbnailJob struct {
 	cancelled   atomic.Bool
 	ctx         context.Context
 	cancelFn    context.CancelFunc
+	progressState
 }
 
 func NewAnimatedThumbnailJob(
@@ -69,6 +73,9 @@ func (j *AnimatedThumbnailJob) GetPhase() string      { return "animated_thumbna
 func (j *AnimatedThumbnailJob) GetStatus() JobStatus   { return j.status }
 func (j *AnimatedThumbnailJob) GetError() error       { return j.error }
 
+// GetParamsHash returns the hash of the job's force target.
+func (j *AnimatedThumbnailJob) GetParamsHash() string { return data.JobParamsHash(j.forceTarget) }
+
 func (j *AnimatedThumbnailJob) Cancel() {
 	j.cancelled.Store(true)
 	if j.cancelFn != nil {
@@ -97,7 +104,10 @@ func (j *AnimatedThumbnailJob) ExecuteWithContext(ctx context.Context) error {
 		return fmt.Errorf("job cancelled")
 	}
 
-	generated, err := j.generator.GenerateMissingAnimatedForScene(j.ctx, j.sceneID, j.forceTarget)
+	// Marker clips make up the first half of the progress, the scene preview the second
+	generated, err := j.generator.GenerateMissingAnimatedForScene(j.ctx, j.sceneID, j.forceTarget, func(percent int) {
+		j.reportProgress(j.id, percent/2)
+	})
 	if err != nil {
 		if j.ctx.Err() == context.DeadlineExceeded {
 			j.status = JobStatusTimedOut
@@ -114,7 +124,11 @@ func (j *AnimatedThumbnailJob) ExecuteWithContext(ctx context.Context) error {
 	}
 
 	// Generate scene preview video (best-effort, does not fail the job)
-	if previewErr := j.generator.GenerateScenePreview(j.ctx, j.sceneID, j.forceTarget); previewErr != nil {
+	j.reportProgress(j.id, 50)
+	previewErr := j.generator.GenerateScenePreview(j.ctx, j.sceneID, j.forceTarget, func(percent int) {
+		j.reportProgress(j.id, 50+percent/2)
+	})
+	if previewErr != nil {
 		if j.ctx.Err() != nil {
 			// Propagate cancellation/timeout
 			if j.ctx.Err() == context.DeadlineExceeded {
@@ -0,0 +1,213 @@
+package jobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"goonhub/internal/data"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// checksumBufferSize is the read size used when hashing scene files
+const checksumBufferSize = 4 << 20
+
+type ChecksumResult struct {
+	FileHash string
+	Mismatch bool
+}
+
+// ChecksumJob hashes a scene file with SHA-256. The first run stores the hash;
+// later runs compare against it and flag the scene when the contents changed.
+type ChecksumJob struct {
+	id           string
+	sceneID      uint
+	scenePath    string
+	expectedHash string
+	repo         data.SceneRepository
+	logger       *zap.Logger
+	status       JobStatus
+	error        error
+	cancelled    atomic.Bool
+	result       *ChecksumResult
+	ctx          context.Context
+	cancelFn     context.CancelFunc
+	progressState
+}
+
+func NewChecksumJob(
+	sceneID uint,
+	scenePath string,
+	expectedHash string,
+	repo data.SceneRepository,
+	logger *zap.Logger,
+) *ChecksumJob {
+	return NewChecksumJobWithID(uuid.New().String(), sceneID, scenePath, expectedHash, repo, logger)
+}
+
+// NewChecksumJobWithID creates a ChecksumJob with a pre-assigned job ID.
+// Used by JobQueueFeeder when creating jobs from pending DB records.
+func NewChecksumJobWithID(
+	jobID string,
+	sceneID uint,
+	scenePath string,
+	expectedHash string,
+	repo data.SceneRepository,
+	logger *zap.Logger,
+) *ChecksumJob {
+	return &ChecksumJob{
+		id:           jobID,
+		sceneID:      sceneID,
+		scenePath:    scenePath,
+		expectedHash: expectedHash,
+		repo:         repo,
+		logger:       logger,
+		status:       JobStatusPending,
+	}
+}
+
+func (j *ChecksumJob) GetID() string              { return j.id }
+func (j *ChecksumJob) GetSceneID() uint           { return j.sceneID }
+func (j *ChecksumJob) GetPhase() string           { return "checksum" }
+func (j *ChecksumJob) GetStatus() JobStatus       { return j.status }
+func (j *ChecksumJob) GetError() error            { return j.error }
+func (j *ChecksumJob) GetResult() *ChecksumResult { return j.result }
+
+func (j *ChecksumJob) Cancel() {
+	j.cancelled.Store(true)
+	if j.cancelFn != nil {
+		j.cancelFn()
+	}
+}
+
+func (j *ChecksumJob) Execute() error {
+	return j.ExecuteWithContext(context.Background())
+}
+
+func (j *ChecksumJob) ExecuteWithContext(ctx context.Context) error {
+	j.ctx, j.cancelFn = context.WithCancel(ctx)
+	defer j.cancelFn()
+
+	startTime := time.Now()
+	j.status = JobStatusRunning
+
+	j.logger.Info("Starting checksum job",
+		zap.String("job_id", j.id),
+		zap.Uint("scene_id", j.sceneID),
+		zap.String("scene_path", j.scenePath),
+	)
+
+	if j.cancelled.Load() || j.ctx.Err() != nil {
+		j.status = JobStatusCancelled
+		return fmt.Errorf("job cancelled")
+	}
+
+	hash, err := ComputeFileChecksum(j.ctx, j.scenePath, func(read, total int64) {
+		if total > 0 {
+			j.reportProgress(j.id, int(read*100/total))
+		}
+	})
+	if err != nil {
+		if j.ctx.Err() == context.DeadlineExceeded {
+			j.status = JobStatusTimedOut
+			j.error = fmt.Errorf("checksum computation timed out")
+			return j.error
+		}
+		if j.ctx.Err() == context.Canceled || j.cancelled.Load() {
+			j.status = JobStatusCancelled
+			return fmt.Errorf("job cancelled")
+		}
+		j.error = fmt.Errorf("checksum computation failed: %w", err)
+		j.status = JobStatusFailed
+		return j.error
+	}
+
+	// The first stored hash is the reference; a later mismatch keeps it so the
+	// change stays visible until the file is restored or re-imported
+	storedHash := hash
+	mismatch := false
+	if j.expectedHash != "" {
+		storedHash = j.expectedHash
+		mismatch = hash != j.expectedHash
+	}
+
+	if err := j.repo.UpdateChecksum(j.sceneID, storedHash, mismatch); err != nil {
+		j.logger.Error("Failed to save checksum",
+			zap.Uint("scene_id", j.sceneID),
+			zap.Error(err),
+		)
+		j.error = fmt.Errorf("failed to save checksum: %w", err)
+		j.status = JobStatusFailed
+		return j.error
+	}
+
+	if mismatch {
+		j.logger.Warn("Scene file checksum does not match the stored hash",
+			zap.Uint("scene_id", j.sceneID),
+			zap.String("scene_path", j.scenePath),
+			zap.String("expected", j.expectedHash),
+			zap.String("actual", hash),
+		)
+	}
+
+	j.result = &ChecksumResult{
+		FileHash: storedHash,
+		Mismatch: mismatch,
+	}
+
+	j.status = JobStatusCompleted
+	j.logger.Info("Checksum job completed",
+		zap.String("job_id", j.id),
+		zap.Uint("scene_id", j.sceneID),
+		zap.Bool("mismatch", mismatch),
+		zap.Duration("elapsed", time.Since(startTime)),
+	)
+
+	return nil
+}
+
+// ComputeFileChecksum returns the hex SHA-256 of a file. onProgress, when set,
+// receives the bytes read so far and the file size after every chunk.
+func ComputeFileChecksum(ctx context.Context, path string, onProgress func(read, total int64)) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	buf := make([]byte, checksumBufferSize)
+	var read int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		n, err := f.Read(buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			read += int64(n)
+			if onProgress != nil {
+				onProgress(read, info.Size())
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
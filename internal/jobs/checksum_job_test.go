@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goonhub/internal/mocks"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// sha256 of "hello world"
+const helloWorldSHA256 = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+func writeChecksumFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "clip.mp4")
+	if err := os.WriteFile(path, []byte("hello world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestComputeFileChecksum(t *testing.T) {
+	path := writeChecksumFile(t)
+
+	var lastRead, lastTotal int64
+	hash, err := ComputeFileChecksum(context.Background(), path, func(read, total int64) {
+		lastRead, lastTotal = read, total
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if hash != helloWorldSHA256 {
+		t.Fatalf("unexpected hash %s", hash)
+	}
+	if lastRead != 11 || lastTotal != 11 {
+		t.Fatalf("expected final progress 11/11, got %d/%d", lastRead, lastTotal)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ComputeFileChecksum(ctx, path, nil); err == nil {
+		t.Fatal("expected error for cancelled context")
+	}
+}
+
+func TestChecksumJob_StoresFirstHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSceneRepository(ctrl)
+	repo.EXPECT().UpdateChecksum(uint(4), helloWorldSHA256, false).Return(nil)
+
+	job := NewChecksumJob(4, writeChecksumFile(t), "", repo, zap.NewNop())
+	if err := job.Execute(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.GetStatus() != JobStatusCompleted || job.GetResult().Mismatch {
+		t.Fatalf("unexpected status %s or result %+v", job.GetStatus(), job.GetResult())
+	}
+	if job.GetProgress() != 100 {
+		t.Fatalf("expected progress 100, got %d", job.GetProgress())
+	}
+}
+
+func TestChecksumJob_FlagsMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSceneRepository(ctrl)
+	// The reference hash is kept so the mismatch stays visible
+	repo.EXPECT().UpdateChecksum(uint(4), "deadbeef", true).Return(nil)
+
+	job := NewChecksumJob(4, writeChecksumFile(t), "deadbeef", repo, zap.NewNop())
+	if err := job.Execute(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !job.GetResult().Mismatch {
+		t.Fatal("expected mismatch")
+	}
+}
+
+func TestChecksumJob_MissingFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockSceneRepository(ctrl)
+
+	job := NewChecksumJob(4, filepath.Join(t.TempDir(), "gone.mp4"), "", repo, zap.NewNop())
+	if err := job.Execute(); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if job.GetStatus() != JobStatusFailed {
+		t.Fatalf("expected failed status, got %s", job.GetStatus())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"goonhub/internal/agent"
 )
 
 type JobStatus string
@@ -45,13 +46,22 @@ type Job interface {
 	GetError() error
 }
 
+// ParameterizedJob is implemented by jobs whose parameters change the work
+// they do. Jobs of one scene and phase only duplicate each other when their
+// parameters hash (data.JobParamsHash) matches too.
+type ParameterizedJob interface {
+	GetParamsHash() string
+}
+
 type JobResult struct {
-	JobID   string
-	SceneID uint
-	Phase   string
-	Status  JobStatus
-	Error   error
-	Data    any
+	JobID    string
+	SceneID  uint
+	Phase    string
+	Status   JobStatus
+	Error    error
+	Data     any
+	Progress int     // Last progress reported by the job (0-100), 0 if it doesn't report progress
+	Log      *JobLog // Pool lines and ffmpeg output of the run
 }
 
 // ProgressCallback is a function type for reporting job progress.
@@ -61,3 +71,23 @@ type ProgressCallback func(jobID string, progress int)
 type ProgressReporter interface {
 	SetProgressCallback(callback ProgressCallback)
 }
+
+// ProgressTracker is an interface for jobs that expose their last reported progress.
+type ProgressTracker interface {
+	GetProgress() int
+}
+
+// RemoteExecutor runs the ffmpeg-heavy part of a job on a remote agent.
+// Defined here to avoid circular imports between jobs and core packages.
+type RemoteExecutor interface {
+	// CanExecute reports whether a healthy agent currently accepts the phase.
+	CanExecute(phase string) bool
+	// Execute hands the task to an agent and blocks until it completes, fails or ctx is done.
+	// Uploaded artifacts are written to artifactDir.
+	Execute(ctx context.Context, task agent.Task, artifactDir string, progress func(int)) (*agent.TaskResult, error)
+}
+
+// RemoteCapable is an interface for jobs that can delegate their work to a RemoteExecutor.
+type RemoteCapable interface {
+	SetRemoteExecutor(executor RemoteExecutor)
+}
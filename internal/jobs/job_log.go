@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultJobLogLimit is the size a job's log is kept under, in bytes.
+const DefaultJobLogLimit = 64 * 1024
+
+// JobLog collects the log of a single job run: the worker pool's own lines
+// and the output of the ffmpeg runs behind the job. Past its size limit the
+// oldest lines are dropped, since a failure is explained at the end.
+// It is safe for concurrent use.
+type JobLog struct {
+	mu      sync.Mutex
+	lines   []string
+	size    int
+	limit   int
+	dropped int
+}
+
+// NewJobLog creates a log kept under limit bytes.
+func NewJobLog(limit int) *JobLog {
+	return &JobLog{limit: limit}
+}
+
+// Printf appends a timestamped line.
+func (l *JobLog) Printf(format string, args ...any) {
+	l.append(time.Now().UTC().Format(time.RFC3339) + " " + fmt.Sprintf(format, args...))
+}
+
+// Write appends each line of p as is, so the log can collect command output.
+// Every write is taken to end on a line boundary.
+func (l *JobLog) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		l.append(strings.TrimRight(line, "\r"))
+	}
+	return len(p), nil
+}
+
+func (l *JobLog) append(line string) {
+	// Leave room for the line's newline
+	if len(line) >= l.limit {
+		line = line[:l.limit-1]
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, line)
+	l.size += len(line) + 1
+	for l.size > l.limit {
+		l.size -= len(l.lines[0]) + 1
+		l.lines = l.lines[1:]
+		l.dropped++
+	}
+}
+
+// String returns the log, noting how many lines were dropped from its start.
+func (l *JobLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var b strings.Builder
+	if l.dropped > 0 {
+		fmt.Fprintf(&b, "[... %d earlier lines dropped ...]\n", l.dropped)
+	}
+	for _, line := range l.lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Truncated reports whether lines were dropped to stay under the limit.
+func (l *JobLog) Truncated() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.dropped > 0
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJobLog_KeepsOutputLines(t *testing.T) {
+	log := NewJobLog(1024)
+	log.Printf("Started %s job", "metadata")
+	log.Write([]byte("$ ffprobe scene.mp4\r\nmoov atom not found\n"))
+
+	lines := strings.Split(strings.TrimSuffix(log.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", lines)
+	}
+	if !strings.HasSuffix(lines[0], " Started metadata job") {
+		t.Fatalf("expected a timestamped line, got %q", lines[0])
+	}
+	if lines[1] != "$ ffprobe scene.mp4" || lines[2] != "moov atom not found" {
+		t.Fatalf("expected the output kept as is, got %q", lines[1:])
+	}
+	if log.Truncated() {
+		t.Fatal("expected the log not truncated")
+	}
+}
+
+func TestJobLog_DropsOldestLines(t *testing.T) {
+	log := NewJobLog(20)
+	for _, line := range []string{"first line", "second line", "last line"} {
+		log.Write([]byte(line + "\n"))
+	}
+
+	if !log.Truncated() {
+		t.Fatal("expected the log truncated")
+	}
+	if got := log.String(); got != "[... 2 earlier lines dropped ...]\nlast line\n" {
+		t.Fatalf("expected only the newest line kept, got %q", got)
+	}
+
+	// A line longer than the limit is cut rather than emptying the log
+	log.Write([]byte(strings.Repeat("x", 50) + "\n"))
+	if got := log.String(); !strings.Contains(got, strings.Repeat("x", 19)) {
+		t.Fatalf("expected the long line cut to fit, got %q", got)
+	}
+}
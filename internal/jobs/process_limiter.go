@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ProcessLimiter is a counting semaphore shared by worker pools to cap the number
+// of jobs (and thus ffmpeg processes) running at once across all of them.
+type ProcessLimiter struct {
+	slots   chan struct{}
+	waiting atomic.Int32
+}
+
+// ProcessLimiterStats is a snapshot of limiter usage.
+type ProcessLimiterStats struct {
+	Limit   int
+	InUse   int
+	Waiting int
+}
+
+// NewProcessLimiter creates a limiter allowing up to max concurrent holders.
+// Returns nil when max <= 0, which pools treat as unlimited.
+func NewProcessLimiter(max int) *ProcessLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &ProcessLimiter{slots: make(chan struct{}, max)}
+}
+
+// Acquire blocks until a slot is free or ctx is done.
+func (l *ProcessLimiter) Acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	l.waiting.Add(1)
+	defer l.waiting.Add(-1)
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire.
+func (l *ProcessLimiter) Release() {
+	<-l.slots
+}
+
+// Stats returns current limiter usage. A nil limiter reports zero values.
+func (l *ProcessLimiter) Stats() ProcessLimiterStats {
+	if l == nil {
+		return ProcessLimiterStats{}
+	}
+	return ProcessLimiterStats{
+		Limit:   cap(l.slots),
+		InUse:   len(l.slots),
+		Waiting: int(l.waiting.Load()),
+	}
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import "sync"
+
+// progressState tracks the last progress a job reported and forwards changes to its
+// ProgressCallback. Jobs embed it to implement ProgressReporter and ProgressTracker.
+type progressState struct {
+	progressMu       sync.Mutex
+	progressCallback ProgressCallback
+	progress         int
+}
+
+// SetProgressCallback sets the progress callback for this job.
+func (p *progressState) SetProgressCallback(callback ProgressCallback) {
+	p.progressMu.Lock()
+	defer p.progressMu.Unlock()
+	p.progressCallback = callback
+}
+
+// GetProgress returns the last reported progress (0-100).
+func (p *progressState) GetProgress() int {
+	p.progressMu.Lock()
+	defer p.progressMu.Unlock()
+	return p.progress
+}
+
+// reportProgress clamps progress to 0-100 and notifies the callback when it changed.
+func (p *progressState) reportProgress(jobID string, progress int) {
+	progress = max(0, min(progress, 100))
+
+	p.progressMu.Lock()
+	if progress == p.progress {
+		p.progressMu.Unlock()
+		return
+	}
+	p.progress = progress
+	callback := p.progressCallback
+	p.progressMu.Unlock()
+
+	if callback != nil {
+		callback(jobID, progress)
+	}
+}
@@ -8,8 +8,9 @@ import (
 // JobRegistry provides thread-safe tracking of jobs for deduplication and cancellation.
 type JobRegistry struct {
 	mu           sync.RWMutex
-	byID         map[string]Job    // job_id -> Job
-	byScenePhase map[string]string // "sceneID:phase" -> job_id
+	byID         map[string]Job      // job_id -> Job
+	byScenePhase map[string]string   // "sceneID:phase:paramsHash" -> job_id
+	executing    map[string]struct{} // "sceneID:phase" of the jobs running now
 }
 
 // NewJobRegistry creates a new JobRegistry.
@@ -17,17 +18,18 @@ func NewJobRegistry() *JobRegistry {
 	return &JobRegistry{
 		byID:         make(map[string]Job),
 		byScenePhase: make(map[string]string),
+		executing:    make(map[string]struct{}),
 	}
 }
 
 // Register adds a job to the registry. Returns the existing job ID if a job
-// for the same scene+phase is already registered (duplicate), otherwise returns
-// an empty string.
+// for the same scene+phase (and parameters, see ParameterizedJob) is already
+// registered (duplicate), otherwise returns an empty string.
 func (r *JobRegistry) Register(job Job) string {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	key := scenePhaseKey(job.GetSceneID(), job.GetPhase())
+	key := jobKey(job)
 
 	// Check for existing job with same scene+phase
 	if existingJobID, exists := r.byScenePhase[key]; exists {
@@ -50,11 +52,31 @@ func (r *JobRegistry) Unregister(jobID string) {
 		return
 	}
 
-	key := scenePhaseKey(job.GetSceneID(), job.GetPhase())
+	key := jobKey(job)
 	delete(r.byID, jobID)
 	delete(r.byScenePhase, key)
 }
 
+// TryAcquireExecution marks job as executing until release is called, unless
+// another job of the same scene and phase is executing, whatever its
+// parameters. Jobs that differ only in parameters (an animated thumbnails job
+// forcing markers and one forcing previews) write the same files, so they may
+// be queued together but must not run at once.
+func (r *JobRegistry) TryAcquireExecution(job Job) (release func(), ok bool) {
+	key := scenePhaseKey(job.GetSceneID(), job.GetPhase(), "")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, busy := r.executing[key]; busy {
+		return nil, false
+	}
+	r.executing[key] = struct{}{}
+	return func() {
+		r.mu.Lock()
+		delete(r.executing, key)
+		r.mu.Unlock()
+	}, true
+}
+
 // Get retrieves a job by its ID.
 func (r *JobRegistry) Get(jobID string) (Job, bool) {
 	r.mu.RLock()
@@ -64,12 +86,13 @@ func (r *JobRegistry) Get(jobID string) (Job, bool) {
 	return job, exists
 }
 
-// GetByScenePhase retrieves a job by scene ID and phase.
+// GetByScenePhase retrieves a job by scene ID and phase. Jobs with
+// parameters aren't found this way.
 func (r *JobRegistry) GetByScenePhase(sceneID uint, phase string) (Job, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	key := scenePhaseKey(sceneID, phase)
+	key := scenePhaseKey(sceneID, phase, "")
 	jobID, exists := r.byScenePhase[key]
 	if !exists {
 		return nil, false
@@ -86,7 +109,16 @@ func (r *JobRegistry) Count() int {
 	return len(r.byID)
 }
 
-// scenePhaseKey generates a unique key for scene+phase combination.
-func scenePhaseKey(sceneID uint, phase string) string {
-	return fmt.Sprintf("%d:%s", sceneID, phase)
+// jobKey is the key under which a job counts as a duplicate of another.
+func jobKey(job Job) string {
+	paramsHash := ""
+	if parameterized, ok := job.(ParameterizedJob); ok {
+		paramsHash = parameterized.GetParamsHash()
+	}
+	return scenePhaseKey(job.GetSceneID(), job.GetPhase(), paramsHash)
+}
+
+// scenePhaseKey generates a unique key for scene+phase+parameters combination.
+func scenePhaseKey(sceneID uint, phase string, paramsHash string) string {
+	return fmt.Sprintf("%d:%s:%s", sceneID, phase, paramsHash)
 }
@@ -249,3 +249,35 @@ func TestRegistry_UnregisterNonExistent(t *testing.T) {
 		t.Fatalf("expected count 0, got %d", registry.Count())
 	}
 }
+
+// paramsTestJob is a registryTestJob with parameters
+type paramsTestJob struct {
+	*registryTestJob
+	paramsHash string
+}
+
+func (j *paramsTestJob) GetParamsHash() string { return j.paramsHash }
+
+func TestRegistry_ParameterizedJobs(t *testing.T) {
+	registry := NewJobRegistry()
+
+	markers := &paramsTestJob{newRegistryTestJob("job-1", 100, "animated_thumbnails"), "markers"}
+	previews := &paramsTestJob{newRegistryTestJob("job-2", 100, "animated_thumbnails"), "previews"}
+	markersAgain := &paramsTestJob{newRegistryTestJob("job-3", 100, "animated_thumbnails"), "markers"}
+
+	if existing := registry.Register(markers); existing != "" {
+		t.Fatalf("expected the first job registered, got duplicate of %s", existing)
+	}
+	// Other parameters are other work
+	if existing := registry.Register(previews); existing != "" {
+		t.Fatalf("expected different parameters not to be a duplicate, got %s", existing)
+	}
+	if existing := registry.Register(markersAgain); existing != "job-1" {
+		t.Fatalf("expected the same parameters to duplicate job-1, got %q", existing)
+	}
+
+	registry.Unregister("job-1")
+	if existing := registry.Register(markersAgain); existing != "" {
+		t.Fatalf("expected the key released on unregister, got %s", existing)
+	}
+}
@@ -3,11 +3,11 @@ package jobs
 import (
 	"context"
 	"fmt"
+	"goonhub/internal/agent"
 	"goonhub/internal/data"
 	"goonhub/pkg/ffmpeg"
 	"os"
 	"path/filepath"
-	"sync"
 	"sync/atomic"
 	"time"
 
@@ -19,6 +19,7 @@ type SpritesResult struct {
 	SpriteSheetPath  string
 	VttPath          string
 	SpriteSheetCount int
+	TrickplayPath    string
 }
 
 type SpritesJob struct {
@@ -35,6 +36,7 @@ type SpritesJob struct {
 	gridCols         int
 	gridRows         int
 	concurrency      int
+	trickplay        bool
 	repo             data.SceneRepository
 	logger           *zap.Logger
 	status           JobStatus
@@ -43,8 +45,8 @@ type SpritesJob struct {
 	result           *SpritesResult
 	ctx              context.Context
 	cancelFn         context.CancelFunc
-	progressCallback ProgressCallback
-	progressMu       sync.Mutex
+	progressState
+	remote           RemoteExecutor
 }
 
 func NewSpritesJob(
@@ -136,22 +138,16 @@ func (j *SpritesJob) Cancel() {
 	}
 }
 
-// SetProgressCallback sets the progress callback for this job.
-func (j *SpritesJob) SetProgressCallback(callback ProgressCallback) {
-	j.progressMu.Lock()
-	defer j.progressMu.Unlock()
-	j.progressCallback = callback
+// SetTrickplayEnabled makes the job build a BIF trickplay file from the sprite
+// sheets once they are generated.
+func (j *SpritesJob) SetTrickplayEnabled(enabled bool) {
+	j.trickplay = enabled
 }
 
-// reportProgress reports progress to the callback if set.
-func (j *SpritesJob) reportProgress(progress int) {
-	j.progressMu.Lock()
-	callback := j.progressCallback
-	j.progressMu.Unlock()
-
-	if callback != nil {
-		callback(j.id, progress)
-	}
+// SetRemoteExecutor lets the job extract sprite sheets on a remote agent when one is available.
+// VTT generation and the DB update always happen locally.
+func (j *SpritesJob) SetRemoteExecutor(executor RemoteExecutor) {
+	j.remote = executor
 }
 
 func (j *SpritesJob) Execute() error {
@@ -193,23 +189,29 @@ func (j *SpritesJob) ExecuteWithContext(ctx context.Context) error {
 
 	// Create a progress callback wrapper
 	progressCallback := func(progress int) {
-		j.reportProgress(progress)
+		j.reportProgress(j.id, progress)
 	}
 
-	spriteSheets, err := ffmpeg.ExtractSpriteSheetsWithProgress(
-		j.ctx,
-		j.scenePath,
-		j.spriteDir,
-		int(j.sceneID),
-		j.tileWidth,
-		j.tileHeight,
-		j.gridCols,
-		j.gridRows,
-		j.frameInterval,
-		j.frameQuality,
-		j.concurrency,
-		progressCallback,
-	)
+	var spriteSheets []string
+	var err error
+	if j.remote != nil && j.remote.CanExecute(agent.PhaseSprites) {
+		spriteSheets, err = j.extractRemote(progressCallback)
+	} else {
+		spriteSheets, err = ffmpeg.ExtractSpriteSheetsWithProgress(
+			j.ctx,
+			j.scenePath,
+			j.spriteDir,
+			int(j.sceneID),
+			j.tileWidth,
+			j.tileHeight,
+			j.gridCols,
+			j.gridRows,
+			j.frameInterval,
+			j.frameQuality,
+			j.concurrency,
+			progressCallback,
+		)
+	}
 	if err != nil {
 		if j.ctx.Err() == context.DeadlineExceeded {
 			j.status = JobStatusTimedOut
@@ -267,7 +269,23 @@ func (j *SpritesJob) ExecuteWithContext(ctx context.Context) error {
 		spriteSheetPath = filepath.Join(j.spriteDir, spriteSheets[0])
 	}
 
-	if err := j.repo.UpdateSprites(j.sceneID, spriteSheetPath, vttPath, len(spriteSheets)); err != nil {
+	trickplayPath := ""
+	if j.trickplay && len(spriteSheets) > 0 {
+		trickplayPath, err = j.generateTrickplay(vttPath)
+		if err != nil {
+			if j.ctx.Err() != nil || j.cancelled.Load() {
+				j.status = JobStatusCancelled
+				return fmt.Errorf("job cancelled")
+			}
+			// Trickplay is optional for third-party players; keep the sprites
+			j.logger.Warn("Failed to generate trickplay file",
+				zap.Uint("scene_id", j.sceneID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if err := j.repo.UpdateSprites(j.sceneID, spriteSheetPath, vttPath, len(spriteSheets), trickplayPath); err != nil {
 		j.logger.Error("Failed to update sprites in database",
 			zap.Uint("scene_id", j.sceneID),
 			zap.Error(err),
@@ -280,6 +298,7 @@ func (j *SpritesJob) ExecuteWithContext(ctx context.Context) error {
 		SpriteSheetPath:  spriteSheetPath,
 		VttPath:          vttPath,
 		SpriteSheetCount: len(spriteSheets),
+		TrickplayPath:    trickplayPath,
 	}
 
 	j.status = JobStatusCompleted
@@ -294,6 +313,58 @@ func (j *SpritesJob) ExecuteWithContext(ctx context.Context) error {
 	return nil
 }
 
+// generateTrickplay builds <sceneID>_trickplay.bif in the sprite directory from
+// the sheets listed in the VTT file.
+func (j *SpritesJob) generateTrickplay(vttPath string) (string, error) {
+	cues, err := ffmpeg.ParseVttFile(vttPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read VTT file: %w", err)
+	}
+
+	bifPath := filepath.Join(j.spriteDir, fmt.Sprintf("%d_trickplay.bif", j.sceneID))
+	if err := ffmpeg.GenerateBifFile(j.ctx, bifPath, j.spriteDir, cues, j.gridCols, j.gridRows, j.frameInterval, j.frameQuality); err != nil {
+		return "", err
+	}
+	return bifPath, nil
+}
+
+// extractRemote dispatches sprite sheet extraction to a remote agent, which uploads
+// the sheets straight into spriteDir.
+func (j *SpritesJob) extractRemote(progressCallback func(int)) ([]string, error) {
+	var sourceSize int64
+	if info, err := os.Stat(j.scenePath); err == nil {
+		sourceSize = info.Size()
+	}
+
+	task := agent.Task{
+		ID:         j.id,
+		SceneID:    j.sceneID,
+		Phase:      agent.PhaseSprites,
+		SourcePath: j.scenePath,
+		SourceSize: sourceSize,
+		Sprites: &agent.SpritesParams{
+			TileWidth:     j.tileWidth,
+			TileHeight:    j.tileHeight,
+			GridCols:      j.gridCols,
+			GridRows:      j.gridRows,
+			FrameInterval: j.frameInterval,
+			FrameQuality:  j.frameQuality,
+			Concurrency:   j.concurrency,
+		},
+	}
+
+	j.logger.Info("Dispatching sprite sheet generation to remote agent",
+		zap.String("job_id", j.id),
+		zap.Uint("scene_id", j.sceneID),
+	)
+
+	result, err := j.remote.Execute(j.ctx, task, j.spriteDir, progressCallback)
+	if err != nil {
+		return nil, err
+	}
+	return result.SpriteSheets, nil
+}
+
 func (j *SpritesJob) handleError(err error) {
 	j.error = err
 	j.status = JobStatusFailed
@@ -0,0 +1,220 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"goonhub/internal/data"
+	"goonhub/pkg/ffmpeg"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type VerifyResult struct {
+	IsCorrupted bool
+	ErrorCount  int
+}
+
+// VerifyJob runs a full decode pass over a scene file, updates the scene's
+// corruption flag and stores a detailed integrity report.
+type VerifyJob struct {
+	id            string
+	sceneID       uint
+	scenePath     string
+	duration      int
+	repo          data.SceneRepository
+	integrityRepo data.SceneIntegrityRepository
+	logger        *zap.Logger
+	status        JobStatus
+	error         error
+	cancelled     atomic.Bool
+	result        *VerifyResult
+	ctx           context.Context
+	cancelFn      context.CancelFunc
+	progressState
+}
+
+func NewVerifyJob(
+	sceneID uint,
+	scenePath string,
+	duration int,
+	repo data.SceneRepository,
+	integrityRepo data.SceneIntegrityRepository,
+	logger *zap.Logger,
+) *VerifyJob {
+	return &VerifyJob{
+		id:            uuid.New().String(),
+		sceneID:       sceneID,
+		scenePath:     scenePath,
+		duration:      duration,
+		repo:          repo,
+		integrityRepo: integrityRepo,
+		logger:        logger,
+		status:        JobStatusPending,
+	}
+}
+
+// NewVerifyJobWithID creates a VerifyJob with a pre-assigned job ID.
+// Used by JobQueueFeeder when creating jobs from pending DB records.
+func NewVerifyJobWithID(
+	jobID string,
+	sceneID uint,
+	scenePath string,
+	duration int,
+	repo data.SceneRepository,
+	integrityRepo data.SceneIntegrityRepository,
+	logger *zap.Logger,
+) *VerifyJob {
+	return &VerifyJob{
+		id:            jobID,
+		sceneID:       sceneID,
+		scenePath:     scenePath,
+		duration:      duration,
+		repo:          repo,
+		integrityRepo: integrityRepo,
+		logger:        logger,
+		status:        JobStatusPending,
+	}
+}
+
+func (j *VerifyJob) GetID() string            { return j.id }
+func (j *VerifyJob) GetSceneID() uint         { return j.sceneID }
+func (j *VerifyJob) GetPhase() string         { return "verify" }
+func (j *VerifyJob) GetStatus() JobStatus     { return j.status }
+func (j *VerifyJob) GetError() error          { return j.error }
+func (j *VerifyJob) GetResult() *VerifyResult { return j.result }
+
+func (j *VerifyJob) Cancel() {
+	j.cancelled.Store(true)
+	if j.cancelFn != nil {
+		j.cancelFn()
+	}
+}
+
+func (j *VerifyJob) Execute() error {
+	return j.ExecuteWithContext(context.Background())
+}
+
+func (j *VerifyJob) ExecuteWithContext(ctx context.Context) error {
+	j.ctx, j.cancelFn = context.WithCancel(ctx)
+	defer j.cancelFn()
+
+	startTime := time.Now()
+	j.status = JobStatusRunning
+
+	j.logger.Info("Starting verify job",
+		zap.String("job_id", j.id),
+		zap.Uint("scene_id", j.sceneID),
+		zap.String("scene_path", j.scenePath),
+	)
+
+	if j.cancelled.Load() || j.ctx.Err() != nil {
+		j.status = JobStatusCancelled
+		return fmt.Errorf("job cancelled")
+	}
+
+	// Progress is only meaningful when the duration is known
+	progressCallback := func(seconds float64) {
+		if j.duration <= 0 {
+			return
+		}
+		j.reportProgress(j.id, int(seconds*100/float64(j.duration)))
+	}
+
+	report, err := ffmpeg.VerifyVideoDecodeWithContext(j.ctx, j.scenePath, progressCallback)
+	if err != nil {
+		if j.ctx.Err() == context.DeadlineExceeded {
+			j.status = JobStatusTimedOut
+			j.error = fmt.Errorf("decode verification timed out")
+			return j.error
+		}
+		if j.ctx.Err() == context.Canceled || j.cancelled.Load() {
+			j.status = JobStatusCancelled
+			return fmt.Errorf("job cancelled")
+		}
+		j.logger.Error("Decode verification failed with system error",
+			zap.Uint("scene_id", j.sceneID),
+			zap.Error(err),
+		)
+		j.error = fmt.Errorf("decode verification failed: %w", err)
+		j.status = JobStatusFailed
+		return j.error
+	}
+
+	record := j.buildReport(report)
+	if err := j.integrityRepo.Upsert(record); err != nil {
+		j.logger.Error("Failed to save integrity report",
+			zap.Uint("scene_id", j.sceneID),
+			zap.Error(err),
+		)
+		j.error = fmt.Errorf("failed to save integrity report: %w", err)
+		j.status = JobStatusFailed
+		return j.error
+	}
+
+	if err := j.repo.UpdateIsCorrupted(j.sceneID, record.IsCorrupted); err != nil {
+		j.logger.Error("Failed to update corruption flag",
+			zap.Uint("scene_id", j.sceneID),
+			zap.Error(err),
+		)
+		j.error = fmt.Errorf("failed to update corruption flag: %w", err)
+		j.status = JobStatusFailed
+		return j.error
+	}
+
+	if record.IsCorrupted {
+		j.logger.Warn("Video file failed decode verification",
+			zap.Uint("scene_id", j.sceneID),
+			zap.String("scene_path", j.scenePath),
+			zap.Int("error_count", record.ErrorCount),
+			zap.Bool("decode_completed", record.DecodeCompleted),
+		)
+	}
+
+	j.result = &VerifyResult{
+		IsCorrupted: record.IsCorrupted,
+		ErrorCount:  record.ErrorCount,
+	}
+
+	j.status = JobStatusCompleted
+	j.logger.Info("Verify job completed",
+		zap.String("job_id", j.id),
+		zap.Uint("scene_id", j.sceneID),
+		zap.Bool("is_corrupted", record.IsCorrupted),
+		zap.Duration("elapsed", time.Since(startTime)),
+	)
+
+	return nil
+}
+
+// buildReport converts a decode report into its persisted form. When the
+// decoder stopped early, everything after the last decoded position is
+// recorded as unreadable.
+func (j *VerifyJob) buildReport(report *ffmpeg.DecodeReport) *data.SceneIntegrityReport {
+	errs := make(data.IntegrityErrors, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		errs = append(errs, data.IntegrityError{Timestamp: e.Timestamp, Message: e.Message})
+	}
+
+	ranges := make(data.IntegrityRanges, 0, len(report.UnreadableRanges)+1)
+	for _, r := range report.UnreadableRanges {
+		ranges = append(ranges, data.IntegrityRange{Start: r.Start, End: r.End})
+	}
+	if !report.Completed && j.duration > 0 && report.DecodedSeconds < float64(j.duration) {
+		ranges = append(ranges, data.IntegrityRange{Start: report.DecodedSeconds, End: float64(j.duration)})
+	}
+
+	return &data.SceneIntegrityReport{
+		SceneID:          j.sceneID,
+		JobID:            j.id,
+		IsCorrupted:      report.IsCorrupted(),
+		DecodeCompleted:  report.Completed,
+		ErrorCount:       report.ErrorCount,
+		DecodedSeconds:   report.DecodedSeconds,
+		Errors:           errs,
+		UnreadableRanges: ranges,
+		CheckedAt:        time.Now(),
+	}
+}
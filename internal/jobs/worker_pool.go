@@ -3,15 +3,21 @@ package jobs
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"goonhub/pkg/ffmpeg"
+
 	"go.uber.org/zap"
 )
 
 type WorkerPool struct {
 	workerCount int
+	workersMu   sync.Mutex
+	quits       []chan struct{} // one per started worker, closed to retire it
+	nextID      int
 	jobQueue    chan Job
 	resultChan  chan JobResult
 	wg          sync.WaitGroup
@@ -22,6 +28,19 @@ type WorkerPool struct {
 	logger      *zap.Logger
 	registry    *JobRegistry
 	timeout     time.Duration
+	limiter     *ProcessLimiter // shared across pools; nil = unlimited
+	logLimit    int             // size each job's log is kept under, in bytes
+
+	deferredMu sync.Mutex
+	deferred   []*deferredJob // dequeued jobs waiting for their scene and phase
+	wake       chan struct{}  // signalled when a deferred job may be ready
+}
+
+// deferredJob is a job taken from the queue while another job of its scene and
+// phase was running. It is set aside so its worker can take other jobs.
+type deferredJob struct {
+	job       Job
+	cancelled bool
 }
 
 func NewWorkerPool(workerCount int, queueSize int) *WorkerPool {
@@ -35,6 +54,8 @@ func NewWorkerPool(workerCount int, queueSize int) *WorkerPool {
 		logger:      zap.NewNop(),
 		registry:    NewJobRegistry(),
 		timeout:     0, // no timeout by default
+		logLimit:    DefaultJobLogLimit,
+		wake:        make(chan struct{}, 1),
 	}
 }
 
@@ -48,64 +69,247 @@ func (p *WorkerPool) Start() {
 	}
 
 	p.logger.Info("Starting worker pool",
-		zap.Int("worker_count", p.workerCount),
+		zap.Int("worker_count", p.ActiveWorkers()),
 		zap.Int("queue_size", cap(p.jobQueue)),
 	)
 
-	for i := 0; i < p.workerCount; i++ {
-		p.wg.Add(1)
-		go p.worker(i)
+	p.workersMu.Lock()
+	for len(p.quits) < p.workerCount {
+		p.startWorker()
 	}
+	p.workersMu.Unlock()
 
 	p.logger.Info("All workers started and ready")
 }
 
-func (p *WorkerPool) worker(id int) {
+// startWorker starts one more worker. The caller holds workersMu.
+func (p *WorkerPool) startWorker() {
+	quit := make(chan struct{})
+	p.quits = append(p.quits, quit)
+	p.wg.Add(1)
+	go p.worker(p.nextID, quit)
+	p.nextID++
+}
+
+// Resize changes the number of workers in place. Added workers start right
+// away; retired workers finish their current job first, so no job is
+// interrupted and the queue and registry are kept.
+func (p *WorkerPool) Resize(workerCount int) error {
+	if workerCount < 1 {
+		return fmt.Errorf("worker count must be at least 1")
+	}
+
+	p.workersMu.Lock()
+	defer p.workersMu.Unlock()
+	previous := p.workerCount
+	p.workerCount = workerCount
+	if !p.running.Load() {
+		return nil
+	}
+
+	for len(p.quits) < workerCount {
+		p.startWorker()
+	}
+	for len(p.quits) > workerCount {
+		last := len(p.quits) - 1
+		close(p.quits[last])
+		p.quits = p.quits[:last]
+	}
+
+	if previous != workerCount {
+		p.logger.Info("Resized worker pool",
+			zap.Int("from", previous),
+			zap.Int("to", workerCount),
+		)
+	}
+	return nil
+}
+
+func (p *WorkerPool) worker(id int, quit <-chan struct{}) {
 	defer p.wg.Done()
 	p.logger.Debug("Worker started", zap.Int("worker_id", id))
 
 	for {
+		// A retired worker leaves before taking another job
+		select {
+		case <-quit:
+			p.logger.Debug("Worker retired", zap.Int("worker_id", id))
+			return
+		default:
+		}
+
 		select {
 		case <-p.ctx.Done():
 			p.logger.Debug("Worker shutting down", zap.Int("worker_id", id))
 			return
+		case <-quit:
+			p.logger.Debug("Worker retired", zap.Int("worker_id", id))
+			return
+		case <-p.wake:
+			job, releaseScene := p.nextDeferred()
+			if job == nil {
+				continue
+			}
+			if releaseScene == nil {
+				// Cancelled while deferred, so it never started
+				select {
+				case p.resultChan <- p.cancelledResult(job):
+				case <-p.ctx.Done():
+					return
+				}
+				continue
+			}
+			if !p.run(id, job, releaseScene) {
+				return
+			}
 		case job := <-p.jobQueue:
 			if job == nil {
 				return
 			}
 
-			p.activeCount.Add(1)
+			// Only one job of a scene and phase runs at a time. Others are set
+			// aside rather than waited for, so they hold no worker.
+			releaseScene, ok := p.registry.TryAcquireExecution(job)
+			if !ok {
+				p.deferJob(job)
+				continue
+			}
+			if !p.run(id, job, releaseScene) {
+				return
+			}
+		}
+	}
+}
 
-			p.logger.Info("Worker accepted job",
-				zap.Int("worker_id", id),
-				zap.String("job_id", job.GetID()),
-				zap.String("job_status", string(job.GetStatus())),
-				zap.Int("queue_depth", p.QueueSize()),
-			)
+// run executes a job that holds its scene and phase and sends its result.
+// It returns false when the pool stopped before the result was taken.
+func (p *WorkerPool) run(id int, job Job, releaseScene func()) bool {
+	// Wait for a shared process slot. If the pool stops meanwhile, the job
+	// runs with the already-cancelled pool context and reports cancellation.
+	release := p.acquireProcessSlot()
 
-			result := p.executeJob(id, job)
+	p.activeCount.Add(1)
 
-			select {
-			case p.resultChan <- result:
-			case <-p.ctx.Done():
-				return
+	p.logger.Info("Worker accepted job",
+		zap.Int("worker_id", id),
+		zap.String("job_id", job.GetID()),
+		zap.String("job_status", string(job.GetStatus())),
+		zap.Int("queue_depth", p.QueueSize()),
+	)
+
+	result := p.executeJob(id, job)
+	release()
+	releaseScene()
+	if p.deferredCount() > 0 {
+		p.wakeDeferred()
+	}
+
+	select {
+	case p.resultChan <- result:
+		return true
+	case <-p.ctx.Done():
+		return false
+	}
+}
+
+// deferJob sets aside a job whose scene and phase is running. A worker is
+// woken in case that job ended before it was added.
+func (p *WorkerPool) deferJob(job Job) {
+	p.deferredMu.Lock()
+	p.deferred = append(p.deferred, &deferredJob{job: job})
+	p.deferredMu.Unlock()
+
+	p.logger.Debug("Job deferred until its scene and phase is free",
+		zap.String("job_id", job.GetID()),
+		zap.Uint("scene_id", job.GetSceneID()),
+		zap.String("phase", job.GetPhase()),
+	)
+	p.wakeDeferred()
+}
+
+// nextDeferred takes the first deferred job that was cancelled, returned with
+// a nil release, or whose scene and phase is free, returned holding it.
+func (p *WorkerPool) nextDeferred() (Job, func()) {
+	p.deferredMu.Lock()
+	defer p.deferredMu.Unlock()
+
+	for i, d := range p.deferred {
+		var release func()
+		if !d.cancelled {
+			var ok bool
+			if release, ok = p.registry.TryAcquireExecution(d.job); !ok {
+				continue
 			}
 		}
+		p.deferred = slices.Delete(p.deferred, i, i+1)
+		// Others may be ready too
+		if len(p.deferred) > 0 {
+			p.wakeDeferred()
+		}
+		return d.job, release
+	}
+	return nil, nil
+}
+
+// cancelDeferred marks a deferred job cancelled so a worker reports it without
+// waiting for its scene and phase. It returns false if the job is not deferred.
+func (p *WorkerPool) cancelDeferred(jobID string) bool {
+	p.deferredMu.Lock()
+	defer p.deferredMu.Unlock()
+
+	for _, d := range p.deferred {
+		if d.job.GetID() == jobID {
+			d.cancelled = true
+			return true
+		}
+	}
+	return false
+}
+
+func (p *WorkerPool) deferredCount() int {
+	p.deferredMu.Lock()
+	defer p.deferredMu.Unlock()
+	return len(p.deferred)
+}
+
+// wakeDeferred lets a worker look at the deferred jobs again.
+func (p *WorkerPool) wakeDeferred() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// cancelledResult reports a job cancelled before it started.
+func (p *WorkerPool) cancelledResult(job Job) JobResult {
+	p.registry.Unregister(job.GetID())
+	jobLog := NewJobLog(p.logLimit)
+	jobLog.Printf("Job cancelled before it started")
+	return JobResult{
+		JobID:   job.GetID(),
+		SceneID: job.GetSceneID(),
+		Phase:   job.GetPhase(),
+		Status:  JobStatusCancelled,
+		Error:   fmt.Errorf("job cancelled"),
+		Log:     jobLog,
 	}
 }
 
 // executeJob runs a single job with panic recovery, ensuring activeCount is always decremented.
 func (p *WorkerPool) executeJob(workerID int, job Job) (result JobResult) {
+	jobLog := NewJobLog(p.logLimit)
 	defer func() {
 		p.activeCount.Add(-1)
 		if r := recover(); r != nil {
 			p.registry.Unregister(job.GetID())
+			jobLog.Printf("Job panicked: %v", r)
 			result = JobResult{
 				JobID:   job.GetID(),
 				SceneID: job.GetSceneID(),
 				Phase:   job.GetPhase(),
 				Status:  JobStatusFailed,
 				Error:   fmt.Errorf("job panicked: %v", r),
+				Log:     jobLog,
 			}
 			p.logger.Error("Worker job panicked",
 				zap.Int("worker_id", workerID),
@@ -121,7 +325,9 @@ func (p *WorkerPool) executeJob(workerID int, job Job) (result JobResult) {
 		JobID:   job.GetID(),
 		SceneID: job.GetSceneID(),
 		Phase:   job.GetPhase(),
+		Log:     jobLog,
 	}
+	jobLog.Printf("Started %s job for scene %d on worker %d", job.GetPhase(), job.GetSceneID(), workerID)
 
 	// Create execution context with optional timeout
 	var execCtx context.Context
@@ -131,6 +337,7 @@ func (p *WorkerPool) executeJob(workerID int, job Job) (result JobResult) {
 	} else {
 		execCtx, execCancel = context.WithCancel(p.ctx)
 	}
+	execCtx = ffmpeg.WithOutputLog(execCtx, jobLog)
 
 	err := job.ExecuteWithContext(execCtx)
 	execCancel()
@@ -138,12 +345,17 @@ func (p *WorkerPool) executeJob(workerID int, job Job) (result JobResult) {
 	// Unregister the job from the registry after execution
 	p.registry.Unregister(job.GetID())
 
+	if tracker, ok := job.(ProgressTracker); ok {
+		result.Progress = tracker.GetProgress()
+	}
+
 	if err != nil {
 		// Check for timeout vs cancellation vs other failures
 		jobStatus := job.GetStatus()
 		if jobStatus == JobStatusTimedOut {
 			result.Status = JobStatusTimedOut
 			result.Error = err
+			jobLog.Printf("Job timed out after %s: %v", p.timeout, err)
 			p.logger.Warn("Worker job timed out",
 				zap.Int("worker_id", workerID),
 				zap.String("job_id", job.GetID()),
@@ -154,6 +366,7 @@ func (p *WorkerPool) executeJob(workerID int, job Job) (result JobResult) {
 		} else if jobStatus == JobStatusCancelled {
 			result.Status = JobStatusCancelled
 			result.Error = err
+			jobLog.Printf("Job cancelled")
 			p.logger.Warn("Worker job cancelled",
 				zap.Int("worker_id", workerID),
 				zap.String("job_id", job.GetID()),
@@ -163,6 +376,7 @@ func (p *WorkerPool) executeJob(workerID int, job Job) (result JobResult) {
 		} else {
 			result.Status = JobStatusFailed
 			result.Error = err
+			jobLog.Printf("Job failed: %v", err)
 			p.logger.Error("Worker job failed",
 				zap.Int("worker_id", workerID),
 				zap.String("job_id", job.GetID()),
@@ -174,6 +388,7 @@ func (p *WorkerPool) executeJob(workerID int, job Job) (result JobResult) {
 	} else {
 		result.Status = JobStatusCompleted
 		result.Data = job
+		jobLog.Printf("Job completed")
 		p.logger.Debug("Worker job completed",
 			zap.Int("worker_id", workerID),
 			zap.String("job_id", job.GetID()),
@@ -224,7 +439,7 @@ func (p *WorkerPool) Stop() {
 
 	p.logger.Info("Stopping worker pool gracefully",
 		zap.Int("pending_jobs", p.QueueSize()),
-		zap.Int("active_workers", p.workerCount),
+		zap.Int("active_workers", p.ActiveWorkers()),
 	)
 
 	p.cancel()
@@ -239,8 +454,9 @@ func (p *WorkerPool) Running() bool {
 	return p.running.Load()
 }
 
+// QueueSize returns the number of jobs waiting, deferred ones included.
 func (p *WorkerPool) QueueSize() int {
-	return len(p.jobQueue)
+	return len(p.jobQueue) + p.deferredCount()
 }
 
 // ActiveJobCount returns the number of jobs currently being executed by workers.
@@ -249,18 +465,35 @@ func (p *WorkerPool) ActiveJobCount() int {
 }
 
 func (p *WorkerPool) ActiveWorkers() int {
+	p.workersMu.Lock()
+	defer p.workersMu.Unlock()
 	return p.workerCount
 }
 
 func (p *WorkerPool) LogStatus() {
 	p.logger.Info("Worker pool status",
 		zap.Int("queue_size", p.QueueSize()),
-		zap.Int("active_workers", p.workerCount),
+		zap.Int("active_workers", p.ActiveWorkers()),
 		zap.Int("queue_capacity", cap(p.jobQueue)),
 		zap.Bool("running", p.running.Load()),
 	)
 }
 
+// SetProcessLimiter sets the limiter shared with other pools. Must be called before Start.
+func (p *WorkerPool) SetProcessLimiter(limiter *ProcessLimiter) {
+	p.limiter = limiter
+}
+
+func (p *WorkerPool) acquireProcessSlot() func() {
+	if p.limiter == nil {
+		return func() {}
+	}
+	if err := p.limiter.Acquire(p.ctx); err != nil {
+		return func() {}
+	}
+	return p.limiter.Release
+}
+
 // SetTimeout sets the job execution timeout. A timeout of 0 means no timeout.
 func (p *WorkerPool) SetTimeout(timeout time.Duration) {
 	p.timeout = timeout
@@ -283,6 +516,9 @@ func (p *WorkerPool) CancelJob(jobID string) error {
 		return fmt.Errorf("job not found: %s", jobID)
 	}
 	job.Cancel()
+	if p.cancelDeferred(jobID) {
+		p.wakeDeferred()
+	}
 	p.logger.Info("Job cancelled",
 		zap.String("job_id", jobID),
 		zap.Uint("scene_id", job.GetSceneID()),
@@ -308,7 +544,7 @@ func (p *WorkerPool) GracefulStop(timeout time.Duration) []string {
 
 	p.logger.Info("Starting graceful shutdown of worker pool",
 		zap.Int("pending_jobs", p.QueueSize()),
-		zap.Int("active_workers", p.workerCount),
+		zap.Int("active_workers", p.ActiveWorkers()),
 		zap.Duration("timeout", timeout),
 	)
 
@@ -344,11 +580,21 @@ func (p *WorkerPool) GracefulStop(timeout time.Duration) []string {
 	return bufferedJobIDs
 }
 
-// drainBuffer extracts all jobs from the channel buffer without executing them.
-// Returns the job IDs of all buffered jobs.
+// drainBuffer extracts all jobs from the channel buffer and the deferred jobs
+// without executing them. Returns the job IDs of those not cancelled.
 func (p *WorkerPool) drainBuffer() []string {
 	var jobIDs []string
 
+	p.deferredMu.Lock()
+	for _, d := range p.deferred {
+		if !d.cancelled {
+			jobIDs = append(jobIDs, d.job.GetID())
+		}
+		p.registry.Unregister(d.job.GetID())
+	}
+	p.deferred = nil
+	p.deferredMu.Unlock()
+
 	// Non-blocking drain of the channel
 	for {
 		select {
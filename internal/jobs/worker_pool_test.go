@@ -118,6 +118,9 @@ func TestWorkerPool_PropagatesErrors(t *testing.T) {
 		if result.Error.Error() != "something went wrong" {
 			t.Fatalf("expected 'something went wrong', got: %v", result.Error)
 		}
+		if log := result.Log.String(); !strings.Contains(log, "Job failed: something went wrong") {
+			t.Fatalf("expected the failure in the job log, got: %q", log)
+		}
 	case <-time.After(5 * time.Second):
 		t.Fatal("timed out waiting for job result")
 	}
@@ -415,6 +418,119 @@ func TestWorkerPool_DuplicateJobRejection(t *testing.T) {
 	pool.Stop()
 }
 
+// paramsSceneJob is a testJobWithSceneID with parameters
+type paramsSceneJob struct {
+	*testJobWithSceneID
+	paramsHash string
+}
+
+func (j *paramsSceneJob) GetParamsHash() string { return j.paramsHash }
+
+func TestWorkerPool_SerializesScenePhaseAcrossParameters(t *testing.T) {
+	pool := NewWorkerPool(2, 10)
+	pool.Start()
+	defer pool.Stop()
+
+	var running, overlapped atomic.Int32
+	run := func() error {
+		if running.Add(1) > 1 {
+			overlapped.Store(1)
+		}
+		time.Sleep(50 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+
+	// Different parameters, so both are accepted, but they may not run at once
+	markers := &paramsSceneJob{newTestJobWithSceneID("job-1", 100, "animated_thumbnails", run), "markers"}
+	previews := &paramsSceneJob{newTestJobWithSceneID("job-2", 100, "animated_thumbnails", run), "previews"}
+
+	for _, job := range []Job{markers, previews} {
+		if err := pool.Submit(job); err != nil {
+			t.Fatalf("failed to submit %s: %v", job.GetID(), err)
+		}
+	}
+	for range 2 {
+		select {
+		case <-pool.Results():
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for job result")
+		}
+	}
+
+	if overlapped.Load() != 0 {
+		t.Fatal("expected jobs of one scene and phase to run one at a time")
+	}
+}
+
+func TestWorkerPool_DefersBlockedVariantsWithoutHoldingWorkers(t *testing.T) {
+	// As many blocked variants as workers
+	pool := NewWorkerPool(2, 10)
+	pool.Start()
+	defer pool.Stop()
+
+	started := make(chan struct{})
+	hold := make(chan struct{})
+	running := &paramsSceneJob{newTestJobWithSceneID("job-1", 100, "animated_thumbnails", func() error {
+		close(started)
+		<-hold
+		return nil
+	}), "all"}
+	if err := pool.Submit(running); err != nil {
+		t.Fatalf("failed to submit: %v", err)
+	}
+	<-started
+
+	ok := func() error { return nil }
+	markers := &paramsSceneJob{newTestJobWithSceneID("job-2", 100, "animated_thumbnails", ok), "markers"}
+	previews := &paramsSceneJob{newTestJobWithSceneID("job-3", 100, "animated_thumbnails", ok), "previews"}
+	other := newTestJobWithSceneID("job-4", 101, "animated_thumbnails", ok)
+	for _, job := range []Job{markers, previews, other} {
+		if err := pool.Submit(job); err != nil {
+			t.Fatalf("failed to submit %s: %v", job.GetID(), err)
+		}
+	}
+
+	next := func() JobResult {
+		select {
+		case result := <-pool.Results():
+			return result
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for job result")
+			return JobResult{}
+		}
+	}
+
+	// The variants wait without a worker, so another scene still runs
+	if result := next(); result.JobID != "job-4" || result.Status != JobStatusCompleted {
+		t.Fatalf("expected job-4 completed while the variants wait, got %s %s", result.JobID, result.Status)
+	}
+
+	// A waiting variant is reported as soon as it is cancelled
+	if err := pool.CancelJob("job-3"); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+	if result := next(); result.JobID != "job-3" || result.Status != JobStatusCancelled {
+		t.Fatalf("expected job-3 cancelled, got %s %s", result.JobID, result.Status)
+	}
+	if _, exists := pool.GetJob("job-3"); exists {
+		t.Fatal("expected the cancelled job unregistered")
+	}
+
+	close(hold)
+	completed := map[string]bool{}
+	for range 2 {
+		result := next()
+		completed[result.JobID] = result.Status == JobStatusCompleted
+	}
+	if !completed["job-1"] || !completed["job-2"] {
+		t.Fatalf("expected job-1 and job-2 completed, got %v", completed)
+	}
+	if pool.QueueSize() != 0 {
+		t.Fatalf("expected no job left waiting, got %d", pool.QueueSize())
+	}
+}
+
 func TestWorkerPool_CancelJobActive(t *testing.T) {
 	pool := NewWorkerPool(1, 10)
 	pool.Start()
@@ -810,3 +926,222 @@ func TestWorkerPool_PanicRecovery(t *testing.T) {
 
 	pool.Stop()
 }
+
+// progressJob is a testJob that reports progress while executing
+type progressJob struct {
+	*testJob
+	progressState
+}
+
+func TestWorkerPool_ResultIncludesProgress(t *testing.T) {
+	pool := NewWorkerPool(1, 10)
+	pool.Start()
+	defer pool.Stop()
+
+	var reported []int
+	job := &progressJob{}
+	job.testJob = newTestJob("progress-job", func() error {
+		job.reportProgress(job.id, 40)
+		job.reportProgress(job.id, 40)
+		job.reportProgress(job.id, 75)
+		return fmt.Errorf("decoder error")
+	})
+	job.SetProgressCallback(func(jobID string, progress int) {
+		reported = append(reported, progress)
+	})
+
+	if err := pool.Submit(job); err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+
+	select {
+	case result := <-pool.Results():
+		if result.Status != JobStatusFailed {
+			t.Fatalf("expected failed status, got %s", result.Status)
+		}
+		if result.Progress != 75 {
+			t.Fatalf("expected result progress 75, got %d", result.Progress)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for job result")
+	}
+
+	if len(reported) != 2 || reported[0] != 40 || reported[1] != 75 {
+		t.Fatalf("expected progress callbacks [40 75], got %v", reported)
+	}
+}
+
+func TestWorkerPool_SharedProcessLimiter(t *testing.T) {
+	limiter := NewProcessLimiter(2)
+	poolA := NewWorkerPool(3, 10)
+	poolB := NewWorkerPool(3, 10)
+	poolA.SetProcessLimiter(limiter)
+	poolB.SetProcessLimiter(limiter)
+	poolA.Start()
+	poolB.Start()
+	defer poolA.Stop()
+	defer poolB.Stop()
+
+	var running, peak atomic.Int32
+	release := make(chan struct{})
+	work := func() error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := poolA.Submit(newTestJob(fmt.Sprintf("a-%d", i), work)); err != nil {
+			t.Fatalf("failed to submit job: %v", err)
+		}
+		if err := poolB.Submit(newTestJob(fmt.Sprintf("b-%d", i), work)); err != nil {
+			t.Fatalf("failed to submit job: %v", err)
+		}
+	}
+
+	deadline := time.After(5 * time.Second)
+	for limiter.Stats().InUse < 2 || limiter.Stats().Waiting < 4 {
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for limiter saturation, stats %+v", limiter.Stats())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	if active := poolA.ActiveJobCount() + poolB.ActiveJobCount(); active != 2 {
+		t.Fatalf("expected 2 active jobs across pools, got %d", active)
+	}
+
+	close(release)
+	for i := 0; i < 6; i++ {
+		var results <-chan JobResult = poolA.Results()
+		if i%2 == 1 {
+			results = poolB.Results()
+		}
+		select {
+		case result := <-results:
+			if result.Status != JobStatusCompleted {
+				t.Fatalf("expected completed status, got %s", result.Status)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for job result")
+		}
+	}
+
+	if peak.Load() != 2 {
+		t.Fatalf("expected at most 2 concurrent jobs, peak was %d", peak.Load())
+	}
+	if stats := limiter.Stats(); stats.InUse != 0 || stats.Limit != 2 {
+		t.Fatalf("expected limiter drained, got %+v", stats)
+	}
+}
+
+func TestProcessLimiter_AcquireRespectsContext(t *testing.T) {
+	if NewProcessLimiter(0) != nil {
+		t.Fatal("expected nil limiter for non-positive max")
+	}
+
+	limiter := NewProcessLimiter(1)
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Acquire(ctx); err == nil {
+		t.Fatal("expected acquire to fail once context is done")
+	}
+
+	limiter.Release()
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("expected slot after release, got %v", err)
+	}
+}
+
+func TestWorkerPool_Resize(t *testing.T) {
+	pool := NewWorkerPool(1, 10)
+	pool.Start()
+	defer pool.Stop()
+
+	release := make(chan struct{})
+	started := make(chan string, 10)
+	blocking := func(id string, sceneID uint) Job {
+		return newTestJobWithSceneIDContext(id, sceneID, "sprites", func(ctx context.Context) error {
+			started <- id
+			select {
+			case <-release:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+	waitStarted := func(want string) {
+		t.Helper()
+		select {
+		case id := <-started:
+			if id != want {
+				t.Fatalf("expected %s to start, got %s", want, id)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s did not start", want)
+		}
+	}
+
+	pool.Submit(blocking("a", 1000))
+	waitStarted("a")
+
+	// Growing starts a worker for the queued job while the first still runs
+	if err := pool.Resize(2); err != nil {
+		t.Fatal(err)
+	}
+	pool.Submit(blocking("b", 1001))
+	waitStarted("b")
+	if pool.ActiveWorkers() != 2 {
+		t.Fatalf("expected 2 workers, got %d", pool.ActiveWorkers())
+	}
+
+	// Shrinking lets the running jobs finish
+	if err := pool.Resize(1); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	for i := 0; i < 2; i++ {
+		select {
+		case result := <-pool.Results():
+			if result.Status != JobStatusCompleted {
+				t.Fatalf("expected %s to complete, got %s (%v)", result.JobID, result.Status, result.Error)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for results")
+		}
+	}
+
+	// One worker is left
+	hold := make(chan struct{})
+	for i, id := range []string{"c", "d"} {
+		pool.Submit(newTestJobWithSceneIDContext(id, uint(1002+i), "sprites", func(ctx context.Context) error {
+			started <- id
+			<-hold
+			return nil
+		}))
+	}
+	waitStarted("c")
+	select {
+	case id := <-started:
+		t.Fatalf("%s started alongside c after shrinking to one worker", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(hold)
+	waitStarted("d")
+
+	if err := pool.Resize(0); err == nil {
+		t.Fatal("expected an error resizing to 0 workers")
+	}
+}
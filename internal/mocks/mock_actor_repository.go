@@ -12,6 +12,7 @@ package mocks
 import (
 	data "goonhub/internal/data"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -82,6 +83,21 @@ func (mr *MockActorRepositoryMockRecorder) BulkReplaceActorsForScenes(sceneIDs,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkReplaceActorsForScenes", reflect.TypeOf((*MockActorRepository)(nil).BulkReplaceActorsForScenes), sceneIDs, actorIDs)
 }
 
+// CountWithoutImage mocks base method.
+func (m *MockActorRepository) CountWithoutImage() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithoutImage")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithoutImage indicates an expected call of CountWithoutImage.
+func (mr *MockActorRepositoryMockRecorder) CountWithoutImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithoutImage", reflect.TypeOf((*MockActorRepository)(nil).CountWithoutImage))
+}
+
 // Create mocks base method.
 func (m *MockActorRepository) Create(actor *data.Actor) error {
 	m.ctrl.T.Helper()
@@ -126,9 +142,9 @@ func (mr *MockActorRepositoryMockRecorder) GetActorSceneIDs(actorID any) *gomock
 }
 
 // GetActorScenes mocks base method.
-func (m *MockActorRepository) GetActorScenes(actorID uint, page, limit int) ([]data.Scene, int64, error) {
+func (m *MockActorRepository) GetActorScenes(actorID uint, restriction *data.ContentRestriction, page, limit int) ([]data.Scene, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorScenes", actorID, page, limit)
+	ret := m.ctrl.Call(m, "GetActorScenes", actorID, restriction, page, limit)
 	ret0, _ := ret[0].([]data.Scene)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -136,9 +152,9 @@ func (m *MockActorRepository) GetActorScenes(actorID uint, page, limit int) ([]d
 }
 
 // GetActorScenes indicates an expected call of GetActorScenes.
-func (mr *MockActorRepositoryMockRecorder) GetActorScenes(actorID, page, limit any) *gomock.Call {
+func (mr *MockActorRepositoryMockRecorder) GetActorScenes(actorID, restriction, page, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorScenes", reflect.TypeOf((*MockActorRepository)(nil).GetActorScenes), actorID, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorScenes", reflect.TypeOf((*MockActorRepository)(nil).GetActorScenes), actorID, restriction, page, limit)
 }
 
 // GetByID mocks base method.
@@ -171,6 +187,21 @@ func (mr *MockActorRepositoryMockRecorder) GetByIDs(ids any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockActorRepository)(nil).GetByIDs), ids)
 }
 
+// GetByName mocks base method.
+func (m *MockActorRepository) GetByName(name string) (*data.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByName", name)
+	ret0, _ := ret[0].(*data.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByName indicates an expected call of GetByName.
+func (mr *MockActorRepositoryMockRecorder) GetByName(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockActorRepository)(nil).GetByName), name)
+}
+
 // GetByUUID mocks base method.
 func (m *MockActorRepository) GetByUUID(uuid string) (*data.Actor, error) {
 	m.ctrl.T.Helper()
@@ -186,6 +217,36 @@ func (mr *MockActorRepositoryMockRecorder) GetByUUID(uuid any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUUID", reflect.TypeOf((*MockActorRepository)(nil).GetByUUID), uuid)
 }
 
+// GetMonthlyUsage mocks base method.
+func (m *MockActorRepository) GetMonthlyUsage(actorIDs []uint, userID uint, since time.Time) ([]data.UsageCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlyUsage", actorIDs, userID, since)
+	ret0, _ := ret[0].([]data.UsageCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlyUsage indicates an expected call of GetMonthlyUsage.
+func (mr *MockActorRepositoryMockRecorder) GetMonthlyUsage(actorIDs, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyUsage", reflect.TypeOf((*MockActorRepository)(nil).GetMonthlyUsage), actorIDs, userID, since)
+}
+
+// GetMostUsedIDs mocks base method.
+func (m *MockActorRepository) GetMostUsedIDs(userID uint, since time.Time, limit int) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMostUsedIDs", userID, since, limit)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMostUsedIDs indicates an expected call of GetMostUsedIDs.
+func (mr *MockActorRepositoryMockRecorder) GetMostUsedIDs(userID, since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMostUsedIDs", reflect.TypeOf((*MockActorRepository)(nil).GetMostUsedIDs), userID, since, limit)
+}
+
 // GetSceneActors mocks base method.
 func (m *MockActorRepository) GetSceneActors(sceneID uint) ([]data.Actor, error) {
 	m.ctrl.T.Helper()
@@ -231,6 +292,21 @@ func (mr *MockActorRepositoryMockRecorder) GetSceneCount(actorID any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSceneCount", reflect.TypeOf((*MockActorRepository)(nil).GetSceneCount), actorID)
 }
 
+// GetStats mocks base method.
+func (m *MockActorRepository) GetStats(actorID, userID uint) (*data.ActorStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", actorID, userID)
+	ret0, _ := ret[0].(*data.ActorStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockActorRepositoryMockRecorder) GetStats(actorID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockActorRepository)(nil).GetStats), actorID, userID)
+}
+
 // List mocks base method.
 func (m *MockActorRepository) List(page, limit int, sort string, genders []string) ([]data.ActorWithCount, int64, error) {
 	m.ctrl.T.Helper()
@@ -247,6 +323,35 @@ func (mr *MockActorRepositoryMockRecorder) List(page, limit, sort, genders any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockActorRepository)(nil).List), page, limit, sort, genders)
 }
 
+// ListWithoutImage mocks base method.
+func (m *MockActorRepository) ListWithoutImage(afterID uint, limit int) ([]data.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWithoutImage", afterID, limit)
+	ret0, _ := ret[0].([]data.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWithoutImage indicates an expected call of ListWithoutImage.
+func (mr *MockActorRepositoryMockRecorder) ListWithoutImage(afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithoutImage", reflect.TypeOf((*MockActorRepository)(nil).ListWithoutImage), afterID, limit)
+}
+
+// Merge mocks base method.
+func (m *MockActorRepository) Merge(target *data.Actor, sourceIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", target, sourceIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockActorRepositoryMockRecorder) Merge(target, sourceIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockActorRepository)(nil).Merge), target, sourceIDs)
+}
+
 // Search mocks base method.
 func (m *MockActorRepository) Search(query string, page, limit int, sort string, genders []string) ([]data.ActorWithCount, int64, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: ActorSyncRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_actor_sync_repository.go -package=mocks goonhub/internal/data ActorSyncRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockActorSyncRepository is a mock of ActorSyncRepository interface.
+type MockActorSyncRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockActorSyncRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockActorSyncRepositoryMockRecorder is the mock recorder for MockActorSyncRepository.
+type MockActorSyncRepositoryMockRecorder struct {
+	mock *MockActorSyncRepository
+}
+
+// NewMockActorSyncRepository creates a new mock instance.
+func NewMockActorSyncRepository(ctrl *gomock.Controller) *MockActorSyncRepository {
+	mock := &MockActorSyncRepository{ctrl: ctrl}
+	mock.recorder = &MockActorSyncRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockActorSyncRepository) EXPECT() *MockActorSyncRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountDue mocks base method.
+func (m *MockActorSyncRepository) CountDue(cutoff time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDue", cutoff)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDue indicates an expected call of CountDue.
+func (mr *MockActorSyncRepositoryMockRecorder) CountDue(cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDue", reflect.TypeOf((*MockActorSyncRepository)(nil).CountDue), cutoff)
+}
+
+// Create mocks base method.
+func (m *MockActorSyncRepository) Create(sync *data.ActorPornDBSync) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", sync)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockActorSyncRepositoryMockRecorder) Create(sync any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockActorSyncRepository)(nil).Create), sync)
+}
+
+// ListByActor mocks base method.
+func (m *MockActorSyncRepository) ListByActor(actorID uint, page, limit int) ([]data.ActorPornDBSync, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByActor", actorID, page, limit)
+	ret0, _ := ret[0].([]data.ActorPornDBSync)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByActor indicates an expected call of ListByActor.
+func (mr *MockActorSyncRepositoryMockRecorder) ListByActor(actorID, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByActor", reflect.TypeOf((*MockActorSyncRepository)(nil).ListByActor), actorID, page, limit)
+}
+
+// ListDue mocks base method.
+func (m *MockActorSyncRepository) ListDue(cutoff time.Time, afterID uint, limit int) ([]data.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", cutoff, afterID, limit)
+	ret0, _ := ret[0].([]data.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockActorSyncRepositoryMockRecorder) ListDue(cutoff, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockActorSyncRepository)(nil).ListDue), cutoff, afterID, limit)
+}
@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: APIUsageRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_api_usage_repository.go -package=mocks goonhub/internal/data APIUsageRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAPIUsageRepository is a mock of APIUsageRepository interface.
+type MockAPIUsageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIUsageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIUsageRepositoryMockRecorder is the mock recorder for MockAPIUsageRepository.
+type MockAPIUsageRepositoryMockRecorder struct {
+	mock *MockAPIUsageRepository
+}
+
+// NewMockAPIUsageRepository creates a new mock instance.
+func NewMockAPIUsageRepository(ctrl *gomock.Controller) *MockAPIUsageRepository {
+	mock := &MockAPIUsageRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIUsageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIUsageRepository) EXPECT() *MockAPIUsageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteOlderThan mocks base method.
+func (m *MockAPIUsageRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOlderThan", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOlderThan indicates an expected call of DeleteOlderThan.
+func (mr *MockAPIUsageRepositoryMockRecorder) DeleteOlderThan(before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOlderThan", reflect.TypeOf((*MockAPIUsageRepository)(nil).DeleteOlderThan), before)
+}
+
+// GetDailyForUser mocks base method.
+func (m *MockAPIUsageRepository) GetDailyForUser(userID uint, since time.Time) ([]data.APIUsageDay, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyForUser", userID, since)
+	ret0, _ := ret[0].([]data.APIUsageDay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyForUser indicates an expected call of GetDailyForUser.
+func (mr *MockAPIUsageRepositoryMockRecorder) GetDailyForUser(userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyForUser", reflect.TypeOf((*MockAPIUsageRepository)(nil).GetDailyForUser), userID, since)
+}
+
+// GetTopRoutesForUser mocks base method.
+func (m *MockAPIUsageRepository) GetTopRoutesForUser(userID uint, since time.Time, limit int) ([]data.APIUsageRoute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopRoutesForUser", userID, since, limit)
+	ret0, _ := ret[0].([]data.APIUsageRoute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopRoutesForUser indicates an expected call of GetTopRoutesForUser.
+func (mr *MockAPIUsageRepositoryMockRecorder) GetTopRoutesForUser(userID, since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopRoutesForUser", reflect.TypeOf((*MockAPIUsageRepository)(nil).GetTopRoutesForUser), userID, since, limit)
+}
+
+// GetTotalsByUser mocks base method.
+func (m *MockAPIUsageRepository) GetTotalsByUser(since time.Time, limit int) ([]data.APIUsageTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTotalsByUser", since, limit)
+	ret0, _ := ret[0].([]data.APIUsageTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTotalsByUser indicates an expected call of GetTotalsByUser.
+func (mr *MockAPIUsageRepositoryMockRecorder) GetTotalsByUser(since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalsByUser", reflect.TypeOf((*MockAPIUsageRepository)(nil).GetTotalsByUser), since, limit)
+}
+
+// IncrementBatch mocks base method.
+func (m *MockAPIUsageRepository) IncrementBatch(rows []data.UserAPIUsage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementBatch", rows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementBatch indicates an expected call of IncrementBatch.
+func (mr *MockAPIUsageRepositoryMockRecorder) IncrementBatch(rows any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementBatch", reflect.TypeOf((*MockAPIUsageRepository)(nil).IncrementBatch), rows)
+}
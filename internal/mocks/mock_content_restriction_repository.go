@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: ContentRestrictionRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_content_restriction_repository.go -package=mocks goonhub/internal/data ContentRestrictionRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockContentRestrictionRepository is a mock of ContentRestrictionRepository interface.
+type MockContentRestrictionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockContentRestrictionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockContentRestrictionRepositoryMockRecorder is the mock recorder for MockContentRestrictionRepository.
+type MockContentRestrictionRepositoryMockRecorder struct {
+	mock *MockContentRestrictionRepository
+}
+
+// NewMockContentRestrictionRepository creates a new mock instance.
+func NewMockContentRestrictionRepository(ctrl *gomock.Controller) *MockContentRestrictionRepository {
+	mock := &MockContentRestrictionRepository{ctrl: ctrl}
+	mock.recorder = &MockContentRestrictionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContentRestrictionRepository) EXPECT() *MockContentRestrictionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockContentRestrictionRepository) Delete(roleID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockContentRestrictionRepositoryMockRecorder) Delete(roleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockContentRestrictionRepository)(nil).Delete), roleID)
+}
+
+// FilterVisibleSceneIDs mocks base method.
+func (m *MockContentRestrictionRepository) FilterVisibleSceneIDs(restriction *data.ContentRestriction, sceneIDs []uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterVisibleSceneIDs", restriction, sceneIDs)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterVisibleSceneIDs indicates an expected call of FilterVisibleSceneIDs.
+func (mr *MockContentRestrictionRepositoryMockRecorder) FilterVisibleSceneIDs(restriction, sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterVisibleSceneIDs", reflect.TypeOf((*MockContentRestrictionRepository)(nil).FilterVisibleSceneIDs), restriction, sceneIDs)
+}
+
+// GetByRoleID mocks base method.
+func (m *MockContentRestrictionRepository) GetByRoleID(roleID uint) (*data.ContentRestriction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByRoleID", roleID)
+	ret0, _ := ret[0].(*data.ContentRestriction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByRoleID indicates an expected call of GetByRoleID.
+func (mr *MockContentRestrictionRepositoryMockRecorder) GetByRoleID(roleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByRoleID", reflect.TypeOf((*MockContentRestrictionRepository)(nil).GetByRoleID), roleID)
+}
+
+// GetVisibleSceneIDs mocks base method.
+func (m *MockContentRestrictionRepository) GetVisibleSceneIDs(restriction *data.ContentRestriction) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVisibleSceneIDs", restriction)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVisibleSceneIDs indicates an expected call of GetVisibleSceneIDs.
+func (mr *MockContentRestrictionRepositoryMockRecorder) GetVisibleSceneIDs(restriction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVisibleSceneIDs", reflect.TypeOf((*MockContentRestrictionRepository)(nil).GetVisibleSceneIDs), restriction)
+}
+
+// IsSceneVisible mocks base method.
+func (m *MockContentRestrictionRepository) IsSceneVisible(restriction *data.ContentRestriction, sceneID uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSceneVisible", restriction, sceneID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSceneVisible indicates an expected call of IsSceneVisible.
+func (mr *MockContentRestrictionRepositoryMockRecorder) IsSceneVisible(restriction, sceneID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSceneVisible", reflect.TypeOf((*MockContentRestrictionRepository)(nil).IsSceneVisible), restriction, sceneID)
+}
+
+// ListAll mocks base method.
+func (m *MockContentRestrictionRepository) ListAll() ([]data.ContentRestriction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll")
+	ret0, _ := ret[0].([]data.ContentRestriction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockContentRestrictionRepositoryMockRecorder) ListAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockContentRestrictionRepository)(nil).ListAll))
+}
+
+// Upsert mocks base method.
+func (m *MockContentRestrictionRepository) Upsert(restriction *data.ContentRestriction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", restriction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockContentRestrictionRepositoryMockRecorder) Upsert(restriction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockContentRestrictionRepository)(nil).Upsert), restriction)
+}
@@ -99,6 +99,21 @@ func (mr *MockDLQRepositoryMockRecorder) Delete(jobID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDLQRepository)(nil).Delete), jobID)
 }
 
+// DeleteByFilter mocks base method.
+func (m *MockDLQRepository) DeleteByFilter(filter data.DLQFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByFilter", filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByFilter indicates an expected call of DeleteByFilter.
+func (mr *MockDLQRepositoryMockRecorder) DeleteByFilter(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByFilter", reflect.TypeOf((*MockDLQRepository)(nil).DeleteByFilter), filter)
+}
+
 // DeleteBySceneID mocks base method.
 func (m *MockDLQRepository) DeleteBySceneID(sceneID uint) (int64, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +144,21 @@ func (mr *MockDLQRepositoryMockRecorder) GetByJobID(jobID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByJobID", reflect.TypeOf((*MockDLQRepository)(nil).GetByJobID), jobID)
 }
 
+// ListByFilter mocks base method.
+func (m *MockDLQRepository) ListByFilter(filter data.DLQFilter, limit int) ([]data.DLQEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByFilter", filter, limit)
+	ret0, _ := ret[0].([]data.DLQEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByFilter indicates an expected call of ListByFilter.
+func (mr *MockDLQRepositoryMockRecorder) ListByFilter(filter, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFilter", reflect.TypeOf((*MockDLQRepository)(nil).ListByFilter), filter, limit)
+}
+
 // ListByStatus mocks base method.
 func (m *MockDLQRepository) ListByStatus(status string, page, limit int) ([]data.DLQEntry, int64, error) {
 	m.ctrl.T.Helper()
@@ -175,6 +205,21 @@ func (mr *MockDLQRepositoryMockRecorder) MarkAbandoned(jobID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAbandoned", reflect.TypeOf((*MockDLQRepository)(nil).MarkAbandoned), jobID)
 }
 
+// PurgeAbandoned mocks base method.
+func (m *MockDLQRepository) PurgeAbandoned(olderThan time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeAbandoned", olderThan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeAbandoned indicates an expected call of PurgeAbandoned.
+func (mr *MockDLQRepositoryMockRecorder) PurgeAbandoned(olderThan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeAbandoned", reflect.TypeOf((*MockDLQRepository)(nil).PurgeAbandoned), olderThan)
+}
+
 // UpdateStatus mocks base method.
 func (m *MockDLQRepository) UpdateStatus(jobID, status string) error {
 	m.ctrl.T.Helper()
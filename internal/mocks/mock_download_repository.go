@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: DownloadRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_download_repository.go -package=mocks goonhub/internal/data DownloadRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDownloadRepository is a mock of DownloadRepository interface.
+type MockDownloadRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDownloadRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDownloadRepositoryMockRecorder is the mock recorder for MockDownloadRepository.
+type MockDownloadRepositoryMockRecorder struct {
+	mock *MockDownloadRepository
+}
+
+// NewMockDownloadRepository creates a new mock instance.
+func NewMockDownloadRepository(ctrl *gomock.Controller) *MockDownloadRepository {
+	mock := &MockDownloadRepository{ctrl: ctrl}
+	mock.recorder = &MockDownloadRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDownloadRepository) EXPECT() *MockDownloadRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListUserDownloads mocks base method.
+func (m *MockDownloadRepository) ListUserDownloads(userID uint, page, limit int) ([]data.UserSceneDownload, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserDownloads", userID, page, limit)
+	ret0, _ := ret[0].([]data.UserSceneDownload)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListUserDownloads indicates an expected call of ListUserDownloads.
+func (mr *MockDownloadRepositoryMockRecorder) ListUserDownloads(userID, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserDownloads", reflect.TypeOf((*MockDownloadRepository)(nil).ListUserDownloads), userID, page, limit)
+}
+
+// RecordDownloads mocks base method.
+func (m *MockDownloadRepository) RecordDownloads(userID uint, sceneIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordDownloads", userID, sceneIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordDownloads indicates an expected call of RecordDownloads.
+func (mr *MockDownloadRepositoryMockRecorder) RecordDownloads(userID, sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordDownloads", reflect.TypeOf((*MockDownloadRepository)(nil).RecordDownloads), userID, sceneIDs)
+}
@@ -0,0 +1,216 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: DuplicateGroupRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_duplicate_group_repository.go -package=mocks goonhub/internal/data DuplicateGroupRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDuplicateGroupRepository is a mock of DuplicateGroupRepository interface.
+type MockDuplicateGroupRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDuplicateGroupRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDuplicateGroupRepositoryMockRecorder is the mock recorder for MockDuplicateGroupRepository.
+type MockDuplicateGroupRepositoryMockRecorder struct {
+	mock *MockDuplicateGroupRepository
+}
+
+// NewMockDuplicateGroupRepository creates a new mock instance.
+func NewMockDuplicateGroupRepository(ctrl *gomock.Controller) *MockDuplicateGroupRepository {
+	mock := &MockDuplicateGroupRepository{ctrl: ctrl}
+	mock.recorder = &MockDuplicateGroupRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDuplicateGroupRepository) EXPECT() *MockDuplicateGroupRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddScenes mocks base method.
+func (m *MockDuplicateGroupRepository) AddScenes(groupID uint, sceneIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddScenes", groupID, sceneIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddScenes indicates an expected call of AddScenes.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) AddScenes(groupID, sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddScenes", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).AddScenes), groupID, sceneIDs)
+}
+
+// AllPairsSuppressed mocks base method.
+func (m *MockDuplicateGroupRepository) AllPairsSuppressed(sceneIDs []uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllPairsSuppressed", sceneIDs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllPairsSuppressed indicates an expected call of AllPairsSuppressed.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) AllPairsSuppressed(sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllPairsSuppressed", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).AllPairsSuppressed), sceneIDs)
+}
+
+// Create mocks base method.
+func (m *MockDuplicateGroupRepository) Create(group *data.DuplicateGroup, sceneIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", group, sceneIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) Create(group, sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).Create), group, sceneIDs)
+}
+
+// DeleteSuppression mocks base method.
+func (m *MockDuplicateGroupRepository) DeleteSuppression(sceneIDA, sceneIDB uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSuppression", sceneIDA, sceneIDB)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSuppression indicates an expected call of DeleteSuppression.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) DeleteSuppression(sceneIDA, sceneIDB any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSuppression", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).DeleteSuppression), sceneIDA, sceneIDB)
+}
+
+// Dismiss mocks base method.
+func (m *MockDuplicateGroupRepository) Dismiss(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dismiss", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Dismiss indicates an expected call of Dismiss.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) Dismiss(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dismiss", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).Dismiss), id)
+}
+
+// FindByExternalID mocks base method.
+func (m *MockDuplicateGroupRepository) FindByExternalID(reason, externalID string) ([]data.DuplicateGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByExternalID", reason, externalID)
+	ret0, _ := ret[0].([]data.DuplicateGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByExternalID indicates an expected call of FindByExternalID.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) FindByExternalID(reason, externalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByExternalID", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).FindByExternalID), reason, externalID)
+}
+
+// GetByID mocks base method.
+func (m *MockDuplicateGroupRepository) GetByID(id uint) (*data.DuplicateGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*data.DuplicateGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).GetByID), id)
+}
+
+// List mocks base method.
+func (m *MockDuplicateGroupRepository) List(status string, page, limit int) ([]data.DuplicateGroup, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", status, page, limit)
+	ret0, _ := ret[0].([]data.DuplicateGroup)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) List(status, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).List), status, page, limit)
+}
+
+// ListSuppressions mocks base method.
+func (m *MockDuplicateGroupRepository) ListSuppressions(sceneID uint, page, limit int) ([]data.DuplicateSuppression, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSuppressions", sceneID, page, limit)
+	ret0, _ := ret[0].([]data.DuplicateSuppression)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListSuppressions indicates an expected call of ListSuppressions.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) ListSuppressions(sceneID, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSuppressions", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).ListSuppressions), sceneID, page, limit)
+}
+
+// MergeScenes mocks base method.
+func (m *MockDuplicateGroupRepository) MergeScenes(winnerSceneID uint, loserSceneIDs []uint, fields map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeScenes", winnerSceneID, loserSceneIDs, fields)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeScenes indicates an expected call of MergeScenes.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) MergeScenes(winnerSceneID, loserSceneIDs, fields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeScenes", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).MergeScenes), winnerSceneID, loserSceneIDs, fields)
+}
+
+// Resolve mocks base method.
+func (m *MockDuplicateGroupRepository) Resolve(id, winnerSceneID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", id, winnerSceneID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) Resolve(id, winnerSceneID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).Resolve), id, winnerSceneID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockDuplicateGroupRepository) UpdateStatus(id uint, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockDuplicateGroupRepositoryMockRecorder) UpdateStatus(id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockDuplicateGroupRepository)(nil).UpdateStatus), id, status)
+}
@@ -40,6 +40,21 @@ func (m *MockExplorerRepository) EXPECT() *MockExplorerRepositoryMockRecorder {
 	return m.recorder
 }
 
+// GetFolderSummary mocks base method.
+func (m *MockExplorerRepository) GetFolderSummary(storagePathID uint, folderPath string) (*data.FolderInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFolderSummary", storagePathID, folderPath)
+	ret0, _ := ret[0].(*data.FolderInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFolderSummary indicates an expected call of GetFolderSummary.
+func (mr *MockExplorerRepositoryMockRecorder) GetFolderSummary(storagePathID, folderPath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFolderSummary", reflect.TypeOf((*MockExplorerRepository)(nil).GetFolderSummary), storagePathID, folderPath)
+}
+
 // GetSceneCountByStoragePath mocks base method.
 func (m *MockExplorerRepository) GetSceneCountByStoragePath(storagePathID uint) (int64, error) {
 	m.ctrl.T.Helper()
@@ -56,24 +71,24 @@ func (mr *MockExplorerRepositoryMockRecorder) GetSceneCountByStoragePath(storage
 }
 
 // GetSceneIDsByFolder mocks base method.
-func (m *MockExplorerRepository) GetSceneIDsByFolder(storagePathID uint, folderPath string, recursive bool) ([]uint, error) {
+func (m *MockExplorerRepository) GetSceneIDsByFolder(storagePathID uint, folderPath string, recursive bool, restriction *data.ContentRestriction) ([]uint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSceneIDsByFolder", storagePathID, folderPath, recursive)
+	ret := m.ctrl.Call(m, "GetSceneIDsByFolder", storagePathID, folderPath, recursive, restriction)
 	ret0, _ := ret[0].([]uint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSceneIDsByFolder indicates an expected call of GetSceneIDsByFolder.
-func (mr *MockExplorerRepositoryMockRecorder) GetSceneIDsByFolder(storagePathID, folderPath, recursive any) *gomock.Call {
+func (mr *MockExplorerRepositoryMockRecorder) GetSceneIDsByFolder(storagePathID, folderPath, recursive, restriction any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSceneIDsByFolder", reflect.TypeOf((*MockExplorerRepository)(nil).GetSceneIDsByFolder), storagePathID, folderPath, recursive)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSceneIDsByFolder", reflect.TypeOf((*MockExplorerRepository)(nil).GetSceneIDsByFolder), storagePathID, folderPath, recursive, restriction)
 }
 
 // GetScenesByFolder mocks base method.
-func (m *MockExplorerRepository) GetScenesByFolder(storagePathID uint, folderPath string, page, limit int) ([]data.Scene, int64, error) {
+func (m *MockExplorerRepository) GetScenesByFolder(storagePathID uint, folderPath string, restriction *data.ContentRestriction, page, limit int) ([]data.Scene, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetScenesByFolder", storagePathID, folderPath, page, limit)
+	ret := m.ctrl.Call(m, "GetScenesByFolder", storagePathID, folderPath, restriction, page, limit)
 	ret0, _ := ret[0].([]data.Scene)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -81,9 +96,9 @@ func (m *MockExplorerRepository) GetScenesByFolder(storagePathID uint, folderPat
 }
 
 // GetScenesByFolder indicates an expected call of GetScenesByFolder.
-func (mr *MockExplorerRepositoryMockRecorder) GetScenesByFolder(storagePathID, folderPath, page, limit any) *gomock.Call {
+func (mr *MockExplorerRepositoryMockRecorder) GetScenesByFolder(storagePathID, folderPath, restriction, page, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScenesByFolder", reflect.TypeOf((*MockExplorerRepository)(nil).GetScenesByFolder), storagePathID, folderPath, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScenesByFolder", reflect.TypeOf((*MockExplorerRepository)(nil).GetScenesByFolder), storagePathID, folderPath, restriction, page, limit)
 }
 
 // GetStoragePathsWithCounts mocks base method.
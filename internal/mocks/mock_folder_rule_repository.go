@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: FolderRuleRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_folder_rule_repository.go -package=mocks goonhub/internal/data FolderRuleRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFolderRuleRepository is a mock of FolderRuleRepository interface.
+type MockFolderRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFolderRuleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFolderRuleRepositoryMockRecorder is the mock recorder for MockFolderRuleRepository.
+type MockFolderRuleRepositoryMockRecorder struct {
+	mock *MockFolderRuleRepository
+}
+
+// NewMockFolderRuleRepository creates a new mock instance.
+func NewMockFolderRuleRepository(ctrl *gomock.Controller) *MockFolderRuleRepository {
+	mock := &MockFolderRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockFolderRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFolderRuleRepository) EXPECT() *MockFolderRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFolderRuleRepository) Create(rule *data.FolderRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFolderRuleRepositoryMockRecorder) Create(rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFolderRuleRepository)(nil).Create), rule)
+}
+
+// Delete mocks base method.
+func (m *MockFolderRuleRepository) Delete(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFolderRuleRepositoryMockRecorder) Delete(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFolderRuleRepository)(nil).Delete), id)
+}
+
+// FillStudioAndType mocks base method.
+func (m *MockFolderRuleRepository) FillStudioAndType(sceneIDs []uint, studioID *uint, sceneType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FillStudioAndType", sceneIDs, studioID, sceneType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FillStudioAndType indicates an expected call of FillStudioAndType.
+func (mr *MockFolderRuleRepositoryMockRecorder) FillStudioAndType(sceneIDs, studioID, sceneType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FillStudioAndType", reflect.TypeOf((*MockFolderRuleRepository)(nil).FillStudioAndType), sceneIDs, studioID, sceneType)
+}
+
+// GetByID mocks base method.
+func (m *MockFolderRuleRepository) GetByID(id uint) (*data.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*data.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockFolderRuleRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockFolderRuleRepository)(nil).GetByID), id)
+}
+
+// List mocks base method.
+func (m *MockFolderRuleRepository) List() ([]data.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]data.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockFolderRuleRepositoryMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFolderRuleRepository)(nil).List))
+}
+
+// ListEnabled mocks base method.
+func (m *MockFolderRuleRepository) ListEnabled() ([]data.FolderRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEnabled")
+	ret0, _ := ret[0].([]data.FolderRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEnabled indicates an expected call of ListEnabled.
+func (mr *MockFolderRuleRepositoryMockRecorder) ListEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnabled", reflect.TypeOf((*MockFolderRuleRepository)(nil).ListEnabled))
+}
+
+// Update mocks base method.
+func (m *MockFolderRuleRepository) Update(rule *data.FolderRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFolderRuleRepositoryMockRecorder) Update(rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFolderRuleRepository)(nil).Update), rule)
+}
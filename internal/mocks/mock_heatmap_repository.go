@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: HeatmapRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_heatmap_repository.go -package=mocks goonhub/internal/data HeatmapRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHeatmapRepository is a mock of HeatmapRepository interface.
+type MockHeatmapRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHeatmapRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockHeatmapRepositoryMockRecorder is the mock recorder for MockHeatmapRepository.
+type MockHeatmapRepositoryMockRecorder struct {
+	mock *MockHeatmapRepository
+}
+
+// NewMockHeatmapRepository creates a new mock instance.
+func NewMockHeatmapRepository(ctrl *gomock.Controller) *MockHeatmapRepository {
+	mock := &MockHeatmapRepository{ctrl: ctrl}
+	mock.recorder = &MockHeatmapRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHeatmapRepository) EXPECT() *MockHeatmapRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetMarkerSpans mocks base method.
+func (m *MockHeatmapRepository) GetMarkerSpans(sceneID uint) ([]data.HeatmapSpan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMarkerSpans", sceneID)
+	ret0, _ := ret[0].([]data.HeatmapSpan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMarkerSpans indicates an expected call of GetMarkerSpans.
+func (mr *MockHeatmapRepositoryMockRecorder) GetMarkerSpans(sceneID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarkerSpans", reflect.TypeOf((*MockHeatmapRepository)(nil).GetMarkerSpans), sceneID)
+}
+
+// GetWatchPositions mocks base method.
+func (m *MockHeatmapRepository) GetWatchPositions(sceneID uint) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatchPositions", sceneID)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWatchPositions indicates an expected call of GetWatchPositions.
+func (mr *MockHeatmapRepositoryMockRecorder) GetWatchPositions(sceneID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatchPositions", reflect.TypeOf((*MockHeatmapRepository)(nil).GetWatchPositions), sceneID)
+}
+
+// ListStaleSceneIDs mocks base method.
+func (m *MockHeatmapRepository) ListStaleSceneIDs(refreshBefore time.Time, limit int) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStaleSceneIDs", refreshBefore, limit)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStaleSceneIDs indicates an expected call of ListStaleSceneIDs.
+func (mr *MockHeatmapRepositoryMockRecorder) ListStaleSceneIDs(refreshBefore, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStaleSceneIDs", reflect.TypeOf((*MockHeatmapRepository)(nil).ListStaleSceneIDs), refreshBefore, limit)
+}
+
+// SaveHeatmap mocks base method.
+func (m *MockHeatmapRepository) SaveHeatmap(sceneID uint, buckets []int64, computedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveHeatmap", sceneID, buckets, computedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveHeatmap indicates an expected call of SaveHeatmap.
+func (mr *MockHeatmapRepositoryMockRecorder) SaveHeatmap(sceneID, buckets, computedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveHeatmap", reflect.TypeOf((*MockHeatmapRepository)(nil).SaveHeatmap), sceneID, buckets, computedAt)
+}
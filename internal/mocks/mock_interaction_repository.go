@@ -158,6 +158,21 @@ func (mr *MockInteractionRepositoryMockRecorder) GetLikesBySceneIDs(userID, scen
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLikesBySceneIDs", reflect.TypeOf((*MockInteractionRepository)(nil).GetLikesBySceneIDs), userID, sceneIDs)
 }
 
+// GetMaxRatingsBySceneIDs mocks base method.
+func (m *MockInteractionRepository) GetMaxRatingsBySceneIDs(sceneIDs []uint) (map[uint]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxRatingsBySceneIDs", sceneIDs)
+	ret0, _ := ret[0].(map[uint]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaxRatingsBySceneIDs indicates an expected call of GetMaxRatingsBySceneIDs.
+func (mr *MockInteractionRepositoryMockRecorder) GetMaxRatingsBySceneIDs(sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxRatingsBySceneIDs", reflect.TypeOf((*MockInteractionRepository)(nil).GetMaxRatingsBySceneIDs), sceneIDs)
+}
+
 // GetRatedSceneIDs mocks base method.
 func (m *MockInteractionRepository) GetRatedSceneIDs(userID uint, minRating, maxRating float64) ([]uint, error) {
 	m.ctrl.T.Helper()
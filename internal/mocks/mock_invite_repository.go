@@ -0,0 +1,143 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: InviteRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_invite_repository.go -package=mocks goonhub/internal/data InviteRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockInviteRepository is a mock of InviteRepository interface.
+type MockInviteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInviteRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockInviteRepositoryMockRecorder is the mock recorder for MockInviteRepository.
+type MockInviteRepositoryMockRecorder struct {
+	mock *MockInviteRepository
+}
+
+// NewMockInviteRepository creates a new mock instance.
+func NewMockInviteRepository(ctrl *gomock.Controller) *MockInviteRepository {
+	mock := &MockInviteRepository{ctrl: ctrl}
+	mock.recorder = &MockInviteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInviteRepository) EXPECT() *MockInviteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockInviteRepository) Claim(id uint, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockInviteRepositoryMockRecorder) Claim(id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockInviteRepository)(nil).Claim), id, at)
+}
+
+// Create mocks base method.
+func (m *MockInviteRepository) Create(invite *data.UserInvite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", invite)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInviteRepositoryMockRecorder) Create(invite any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInviteRepository)(nil).Create), invite)
+}
+
+// GetByHash mocks base method.
+func (m *MockInviteRepository) GetByHash(tokenHash string) (*data.UserInvite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", tokenHash)
+	ret0, _ := ret[0].(*data.UserInvite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockInviteRepositoryMockRecorder) GetByHash(tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockInviteRepository)(nil).GetByHash), tokenHash)
+}
+
+// List mocks base method.
+func (m *MockInviteRepository) List(page, limit int) ([]data.UserInvite, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", page, limit)
+	ret0, _ := ret[0].([]data.UserInvite)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockInviteRepositoryMockRecorder) List(page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInviteRepository)(nil).List), page, limit)
+}
+
+// Release mocks base method.
+func (m *MockInviteRepository) Release(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockInviteRepositoryMockRecorder) Release(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockInviteRepository)(nil).Release), id)
+}
+
+// Revoke mocks base method.
+func (m *MockInviteRepository) Revoke(id uint, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockInviteRepositoryMockRecorder) Revoke(id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockInviteRepository)(nil).Revoke), id, at)
+}
+
+// SetUsedBy mocks base method.
+func (m *MockInviteRepository) SetUsedBy(id, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUsedBy", id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUsedBy indicates an expected call of SetUsedBy.
+func (mr *MockInviteRepositoryMockRecorder) SetUsedBy(id, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUsedBy", reflect.TypeOf((*MockInviteRepository)(nil).SetUsedBy), id, userID)
+}
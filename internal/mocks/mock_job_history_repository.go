@@ -41,6 +41,21 @@ func (m *MockJobHistoryRepository) EXPECT() *MockJobHistoryRepositoryMockRecorde
 	return m.recorder
 }
 
+// CancelPendingByFilter mocks base method.
+func (m *MockJobHistoryRepository) CancelPendingByFilter(filter data.JobCancelFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPendingByFilter", filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelPendingByFilter indicates an expected call of CancelPendingByFilter.
+func (mr *MockJobHistoryRepositoryMockRecorder) CancelPendingByFilter(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPendingByFilter", reflect.TypeOf((*MockJobHistoryRepository)(nil).CancelPendingByFilter), filter)
+}
+
 // CancelPendingJob mocks base method.
 func (m *MockJobHistoryRepository) CancelPendingJob(jobID string) error {
 	m.ctrl.T.Helper()
@@ -85,6 +100,21 @@ func (mr *MockJobHistoryRepositoryMockRecorder) ClaimPendingJobs(phase, limit an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingJobs", reflect.TypeOf((*MockJobHistoryRepository)(nil).ClaimPendingJobs), phase, limit)
 }
 
+// ClaimPendingJobsExcluding mocks base method.
+func (m *MockJobHistoryRepository) ClaimPendingJobsExcluding(phase string, limit int, storagePathIDs []uint) ([]data.JobHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPendingJobsExcluding", phase, limit, storagePathIDs)
+	ret0, _ := ret[0].([]data.JobHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPendingJobsExcluding indicates an expected call of ClaimPendingJobsExcluding.
+func (mr *MockJobHistoryRepositoryMockRecorder) ClaimPendingJobsExcluding(phase, limit, storagePathIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingJobsExcluding", reflect.TypeOf((*MockJobHistoryRepository)(nil).ClaimPendingJobsExcluding), phase, limit, storagePathIDs)
+}
+
 // CountPendingByPhase mocks base method.
 func (m *MockJobHistoryRepository) CountPendingByPhase() (map[string]int, error) {
 	m.ctrl.T.Helper()
@@ -130,11 +160,12 @@ func (mr *MockJobHistoryRepositoryMockRecorder) Create(record any) *gomock.Call
 }
 
 // CreateBatch mocks base method.
-func (m *MockJobHistoryRepository) CreateBatch(records []*data.JobHistory) error {
+func (m *MockJobHistoryRepository) CreateBatch(records []*data.JobHistory) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateBatch", records)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateBatch indicates an expected call of CreateBatch.
@@ -172,36 +203,6 @@ func (mr *MockJobHistoryRepositoryMockRecorder) DeleteByStatus(status any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByStatus", reflect.TypeOf((*MockJobHistoryRepository)(nil).DeleteByStatus), status)
 }
 
-// DeleteOlderThan mocks base method.
-func (m *MockJobHistoryRepository) DeleteOlderThan(before time.Time) (int64, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOlderThan", before)
-	ret0, _ := ret[0].(int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// DeleteOlderThan indicates an expected call of DeleteOlderThan.
-func (mr *MockJobHistoryRepositoryMockRecorder) DeleteOlderThan(before any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOlderThan", reflect.TypeOf((*MockJobHistoryRepository)(nil).DeleteOlderThan), before)
-}
-
-// ExistsPendingOrRunning mocks base method.
-func (m *MockJobHistoryRepository) ExistsPendingOrRunning(sceneID uint, phase string) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExistsPendingOrRunning", sceneID, phase)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// ExistsPendingOrRunning indicates an expected call of ExistsPendingOrRunning.
-func (mr *MockJobHistoryRepositoryMockRecorder) ExistsPendingOrRunning(sceneID, phase any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsPendingOrRunning", reflect.TypeOf((*MockJobHistoryRepository)(nil).ExistsPendingOrRunning), sceneID, phase)
-}
-
 // GetByJobID mocks base method.
 func (m *MockJobHistoryRepository) GetByJobID(jobID string) (*data.JobHistory, error) {
 	m.ctrl.T.Helper()
@@ -276,6 +277,22 @@ func (mr *MockJobHistoryRepositoryMockRecorder) ListActive() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockJobHistoryRepository)(nil).ListActive))
 }
 
+// ListAfter mocks base method.
+func (m *MockJobHistoryRepository) ListAfter(cursor *data.Cursor, limit int, status string) ([]data.JobHistory, *data.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", cursor, limit, status)
+	ret0, _ := ret[0].([]data.JobHistory)
+	ret1, _ := ret[1].(*data.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockJobHistoryRepositoryMockRecorder) ListAfter(cursor, limit, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockJobHistoryRepository)(nil).ListAfter), cursor, limit, status)
+}
+
 // ListAll mocks base method.
 func (m *MockJobHistoryRepository) ListAll(page, limit int, status string) ([]data.JobHistory, int64, error) {
 	m.ctrl.T.Helper()
@@ -292,6 +309,21 @@ func (mr *MockJobHistoryRepositoryMockRecorder) ListAll(page, limit, status any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockJobHistoryRepository)(nil).ListAll), page, limit, status)
 }
 
+// ListFailureSummaries mocks base method.
+func (m *MockJobHistoryRepository) ListFailureSummaries(phase string, limit int) ([]data.JobFailureSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailureSummaries", phase, limit)
+	ret0, _ := ret[0].([]data.JobFailureSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailureSummaries indicates an expected call of ListFailureSummaries.
+func (mr *MockJobHistoryRepositoryMockRecorder) ListFailureSummaries(phase, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailureSummaries", reflect.TypeOf((*MockJobHistoryRepository)(nil).ListFailureSummaries), phase, limit)
+}
+
 // ListRecentFailed mocks base method.
 func (m *MockJobHistoryRepository) ListRecentFailed(limit int, since time.Duration) ([]data.JobHistory, error) {
 	m.ctrl.T.Helper()
@@ -307,6 +339,21 @@ func (mr *MockJobHistoryRepositoryMockRecorder) ListRecentFailed(limit, since an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecentFailed", reflect.TypeOf((*MockJobHistoryRepository)(nil).ListRecentFailed), limit, since)
 }
 
+// ListRunningByFilter mocks base method.
+func (m *MockJobHistoryRepository) ListRunningByFilter(filter data.JobCancelFilter) ([]data.JobHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRunningByFilter", filter)
+	ret0, _ := ret[0].([]data.JobHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRunningByFilter indicates an expected call of ListRunningByFilter.
+func (mr *MockJobHistoryRepositoryMockRecorder) ListRunningByFilter(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRunningByFilter", reflect.TypeOf((*MockJobHistoryRepository)(nil).ListRunningByFilter), filter)
+}
+
 // MarkNotRetryable mocks base method.
 func (m *MockJobHistoryRepository) MarkNotRetryable(jobID string) error {
 	m.ctrl.T.Helper()
@@ -351,19 +398,64 @@ func (mr *MockJobHistoryRepositoryMockRecorder) MarkRunningAsInterrupted() *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRunningAsInterrupted", reflect.TypeOf((*MockJobHistoryRepository)(nil).MarkRunningAsInterrupted))
 }
 
-// MarkStuckPendingJobsAsFailed mocks base method.
-func (m *MockJobHistoryRepository) MarkStuckPendingJobsAsFailed(olderThan time.Duration) (int64, error) {
+// OutcomeTimeline mocks base method.
+func (m *MockJobHistoryRepository) OutcomeTimeline(since time.Time, bucket string) ([]data.JobTimelineBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutcomeTimeline", since, bucket)
+	ret0, _ := ret[0].([]data.JobTimelineBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OutcomeTimeline indicates an expected call of OutcomeTimeline.
+func (mr *MockJobHistoryRepositoryMockRecorder) OutcomeTimeline(since, bucket any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutcomeTimeline", reflect.TypeOf((*MockJobHistoryRepository)(nil).OutcomeTimeline), since, bucket)
+}
+
+// PhaseStats mocks base method.
+func (m *MockJobHistoryRepository) PhaseStats(since time.Time) ([]data.JobPhaseStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkStuckPendingJobsAsFailed", olderThan)
+	ret := m.ctrl.Call(m, "PhaseStats", since)
+	ret0, _ := ret[0].([]data.JobPhaseStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PhaseStats indicates an expected call of PhaseStats.
+func (mr *MockJobHistoryRepositoryMockRecorder) PhaseStats(since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PhaseStats", reflect.TypeOf((*MockJobHistoryRepository)(nil).PhaseStats), since)
+}
+
+// PruneFinished mocks base method.
+func (m *MockJobHistoryRepository) PruneFinished(before time.Time, archive bool, limit int) (data.JobPruneResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneFinished", before, archive, limit)
+	ret0, _ := ret[0].(data.JobPruneResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneFinished indicates an expected call of PruneFinished.
+func (mr *MockJobHistoryRepositoryMockRecorder) PruneFinished(before, archive, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneFinished", reflect.TypeOf((*MockJobHistoryRepository)(nil).PruneFinished), before, archive, limit)
+}
+
+// RequeueOrphanedRunningJobs mocks base method.
+func (m *MockJobHistoryRepository) RequeueOrphanedRunningJobs(olderThan time.Duration, maxRequeues int, phases []string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueOrphanedRunningJobs", olderThan, maxRequeues, phases)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// MarkStuckPendingJobsAsFailed indicates an expected call of MarkStuckPendingJobsAsFailed.
-func (mr *MockJobHistoryRepositoryMockRecorder) MarkStuckPendingJobsAsFailed(olderThan any) *gomock.Call {
+// RequeueOrphanedRunningJobs indicates an expected call of RequeueOrphanedRunningJobs.
+func (mr *MockJobHistoryRepositoryMockRecorder) RequeueOrphanedRunningJobs(olderThan, maxRequeues, phases any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkStuckPendingJobsAsFailed", reflect.TypeOf((*MockJobHistoryRepository)(nil).MarkStuckPendingJobsAsFailed), olderThan)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueOrphanedRunningJobs", reflect.TypeOf((*MockJobHistoryRepository)(nil).RequeueOrphanedRunningJobs), olderThan, maxRequeues, phases)
 }
 
 // ResetJobsToPending mocks base method.
@@ -381,6 +473,21 @@ func (mr *MockJobHistoryRepositoryMockRecorder) ResetJobsToPending(jobIDs any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetJobsToPending", reflect.TypeOf((*MockJobHistoryRepository)(nil).ResetJobsToPending), jobIDs)
 }
 
+// SlowestJobs mocks base method.
+func (m *MockJobHistoryRepository) SlowestJobs(since time.Time, phase string, limit int) ([]data.JobDuration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SlowestJobs", since, phase, limit)
+	ret0, _ := ret[0].([]data.JobDuration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SlowestJobs indicates an expected call of SlowestJobs.
+func (mr *MockJobHistoryRepositoryMockRecorder) SlowestJobs(since, phase, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SlowestJobs", reflect.TypeOf((*MockJobHistoryRepository)(nil).SlowestJobs), since, phase, limit)
+}
+
 // UpdateProgress mocks base method.
 func (m *MockJobHistoryRepository) UpdateProgress(jobID string, progress int) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: JobLogRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_job_log_repository.go -package=mocks goonhub/internal/data JobLogRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobLogRepository is a mock of JobLogRepository interface.
+type MockJobLogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobLogRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockJobLogRepositoryMockRecorder is the mock recorder for MockJobLogRepository.
+type MockJobLogRepositoryMockRecorder struct {
+	mock *MockJobLogRepository
+}
+
+// NewMockJobLogRepository creates a new mock instance.
+func NewMockJobLogRepository(ctrl *gomock.Controller) *MockJobLogRepository {
+	mock := &MockJobLogRepository{ctrl: ctrl}
+	mock.recorder = &MockJobLogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobLogRepository) EXPECT() *MockJobLogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByJobID mocks base method.
+func (m *MockJobLogRepository) GetByJobID(jobID string) (*data.JobLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByJobID", jobID)
+	ret0, _ := ret[0].(*data.JobLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByJobID indicates an expected call of GetByJobID.
+func (mr *MockJobLogRepositoryMockRecorder) GetByJobID(jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByJobID", reflect.TypeOf((*MockJobLogRepository)(nil).GetByJobID), jobID)
+}
+
+// Save mocks base method.
+func (m *MockJobLogRepository) Save(log *data.JobLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockJobLogRepositoryMockRecorder) Save(log any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockJobLogRepository)(nil).Save), log)
+}
@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: MarkerCompilationRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_marker_compilation_repository.go -package=mocks goonhub/internal/data MarkerCompilationRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMarkerCompilationRepository is a mock of MarkerCompilationRepository interface.
+type MockMarkerCompilationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMarkerCompilationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMarkerCompilationRepositoryMockRecorder is the mock recorder for MockMarkerCompilationRepository.
+type MockMarkerCompilationRepositoryMockRecorder struct {
+	mock *MockMarkerCompilationRepository
+}
+
+// NewMockMarkerCompilationRepository creates a new mock instance.
+func NewMockMarkerCompilationRepository(ctrl *gomock.Controller) *MockMarkerCompilationRepository {
+	mock := &MockMarkerCompilationRepository{ctrl: ctrl}
+	mock.recorder = &MockMarkerCompilationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMarkerCompilationRepository) EXPECT() *MockMarkerCompilationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockMarkerCompilationRepository) Complete(jobID string, fileSize int64, duration int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", jobID, fileSize, duration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockMarkerCompilationRepositoryMockRecorder) Complete(jobID, fileSize, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockMarkerCompilationRepository)(nil).Complete), jobID, fileSize, duration)
+}
+
+// Create mocks base method.
+func (m *MockMarkerCompilationRepository) Create(compilation *data.MarkerCompilation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", compilation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMarkerCompilationRepositoryMockRecorder) Create(compilation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMarkerCompilationRepository)(nil).Create), compilation)
+}
+
+// Delete mocks base method.
+func (m *MockMarkerCompilationRepository) Delete(jobID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", jobID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMarkerCompilationRepositoryMockRecorder) Delete(jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMarkerCompilationRepository)(nil).Delete), jobID)
+}
+
+// FailInterrupted mocks base method.
+func (m *MockMarkerCompilationRepository) FailInterrupted(message string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailInterrupted", message)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailInterrupted indicates an expected call of FailInterrupted.
+func (mr *MockMarkerCompilationRepositoryMockRecorder) FailInterrupted(message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailInterrupted", reflect.TypeOf((*MockMarkerCompilationRepository)(nil).FailInterrupted), message)
+}
+
+// GetByJobID mocks base method.
+func (m *MockMarkerCompilationRepository) GetByJobID(jobID string) (*data.MarkerCompilation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByJobID", jobID)
+	ret0, _ := ret[0].(*data.MarkerCompilation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByJobID indicates an expected call of GetByJobID.
+func (mr *MockMarkerCompilationRepositoryMockRecorder) GetByJobID(jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByJobID", reflect.TypeOf((*MockMarkerCompilationRepository)(nil).GetByJobID), jobID)
+}
+
+// ListByUser mocks base method.
+func (m *MockMarkerCompilationRepository) ListByUser(userID uint) ([]data.MarkerCompilation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID)
+	ret0, _ := ret[0].([]data.MarkerCompilation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockMarkerCompilationRepositoryMockRecorder) ListByUser(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockMarkerCompilationRepository)(nil).ListByUser), userID)
+}
+
+// UpdateProgress mocks base method.
+func (m *MockMarkerCompilationRepository) UpdateProgress(jobID string, progress int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProgress", jobID, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProgress indicates an expected call of UpdateProgress.
+func (mr *MockMarkerCompilationRepositoryMockRecorder) UpdateProgress(jobID, progress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProgress", reflect.TypeOf((*MockMarkerCompilationRepository)(nil).UpdateProgress), jobID, progress)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockMarkerCompilationRepository) UpdateStatus(jobID, status string, errorMessage *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", jobID, status, errorMessage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockMarkerCompilationRepositoryMockRecorder) UpdateStatus(jobID, status, errorMessage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockMarkerCompilationRepository)(nil).UpdateStatus), jobID, status, errorMessage)
+}
@@ -68,6 +68,21 @@ func (mr *MockMarkerRepositoryMockRecorder) ApplyLabelTagsToMarker(userID, marke
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyLabelTagsToMarker", reflect.TypeOf((*MockMarkerRepository)(nil).ApplyLabelTagsToMarker), userID, markerID, label)
 }
 
+// CountBySceneIDs mocks base method.
+func (m *MockMarkerRepository) CountBySceneIDs(sceneIDs []uint) (map[uint]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBySceneIDs", sceneIDs)
+	ret0, _ := ret[0].(map[uint]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBySceneIDs indicates an expected call of CountBySceneIDs.
+func (mr *MockMarkerRepositoryMockRecorder) CountBySceneIDs(sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBySceneIDs", reflect.TypeOf((*MockMarkerRepository)(nil).CountBySceneIDs), sceneIDs)
+}
+
 // CountByUserAndScene mocks base method.
 func (m *MockMarkerRepository) CountByUserAndScene(userID, sceneID uint) (int64, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +126,20 @@ func (mr *MockMarkerRepositoryMockRecorder) Delete(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMarkerRepository)(nil).Delete), id)
 }
 
+// DeleteLabelSetting mocks base method.
+func (m *MockMarkerRepository) DeleteLabelSetting(userID uint, label string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabelSetting", userID, label)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLabelSetting indicates an expected call of DeleteLabelSetting.
+func (mr *MockMarkerRepositoryMockRecorder) DeleteLabelSetting(userID, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabelSetting", reflect.TypeOf((*MockMarkerRepository)(nil).DeleteLabelSetting), userID, label)
+}
+
 // GetAllByScene mocks base method.
 func (m *MockMarkerRepository) GetAllByScene(sceneID uint) ([]data.UserSceneMarker, error) {
 	m.ctrl.T.Helper()
@@ -141,6 +170,21 @@ func (mr *MockMarkerRepositoryMockRecorder) GetAllLabelTagsForUser(userID any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllLabelTagsForUser", reflect.TypeOf((*MockMarkerRepository)(nil).GetAllLabelTagsForUser), userID)
 }
 
+// GetAllMarkedSceneIDs mocks base method.
+func (m *MockMarkerRepository) GetAllMarkedSceneIDs() ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMarkedSceneIDs")
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllMarkedSceneIDs indicates an expected call of GetAllMarkedSceneIDs.
+func (mr *MockMarkerRepositoryMockRecorder) GetAllMarkedSceneIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMarkedSceneIDs", reflect.TypeOf((*MockMarkerRepository)(nil).GetAllMarkedSceneIDs))
+}
+
 // GetAllMarkersForUser mocks base method.
 func (m *MockMarkerRepository) GetAllMarkersForUser(userID uint, offset, limit int, sortBy string) ([]data.MarkerWithScene, int64, error) {
 	m.ctrl.T.Helper()
@@ -157,6 +201,22 @@ func (mr *MockMarkerRepositoryMockRecorder) GetAllMarkersForUser(userID, offset,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMarkersForUser", reflect.TypeOf((*MockMarkerRepository)(nil).GetAllMarkersForUser), userID, offset, limit, sortBy)
 }
 
+// GetAllMarkersForUserAfter mocks base method.
+func (m *MockMarkerRepository) GetAllMarkersForUserAfter(userID uint, cursor *data.Cursor, limit int, sortBy string) ([]data.MarkerWithScene, *data.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllMarkersForUserAfter", userID, cursor, limit, sortBy)
+	ret0, _ := ret[0].([]data.MarkerWithScene)
+	ret1, _ := ret[1].(*data.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllMarkersForUserAfter indicates an expected call of GetAllMarkersForUserAfter.
+func (mr *MockMarkerRepositoryMockRecorder) GetAllMarkersForUserAfter(userID, cursor, limit, sortBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllMarkersForUserAfter", reflect.TypeOf((*MockMarkerRepository)(nil).GetAllMarkersForUserAfter), userID, cursor, limit, sortBy)
+}
+
 // GetByID mocks base method.
 func (m *MockMarkerRepository) GetByID(id uint) (*data.UserSceneMarker, error) {
 	m.ctrl.T.Helper()
@@ -172,6 +232,21 @@ func (mr *MockMarkerRepositoryMockRecorder) GetByID(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockMarkerRepository)(nil).GetByID), id)
 }
 
+// GetByIDs mocks base method.
+func (m *MockMarkerRepository) GetByIDs(ids []uint) ([]data.UserSceneMarker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ids)
+	ret0, _ := ret[0].([]data.UserSceneMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockMarkerRepositoryMockRecorder) GetByIDs(ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockMarkerRepository)(nil).GetByIDs), ids)
+}
+
 // GetBySceneWithoutAnimatedThumbnail mocks base method.
 func (m *MockMarkerRepository) GetBySceneWithoutAnimatedThumbnail(sceneID uint) ([]data.UserSceneMarker, error) {
 	m.ctrl.T.Helper()
@@ -217,6 +292,37 @@ func (mr *MockMarkerRepositoryMockRecorder) GetByUserAndScene(userID, sceneID an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserAndScene", reflect.TypeOf((*MockMarkerRepository)(nil).GetByUserAndScene), userID, sceneID)
 }
 
+// GetByUserAndScenes mocks base method.
+func (m *MockMarkerRepository) GetByUserAndScenes(userID uint, sceneIDs []uint) (map[uint][]data.UserSceneMarker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserAndScenes", userID, sceneIDs)
+	ret0, _ := ret[0].(map[uint][]data.UserSceneMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserAndScenes indicates an expected call of GetByUserAndScenes.
+func (mr *MockMarkerRepositoryMockRecorder) GetByUserAndScenes(userID, sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserAndScenes", reflect.TypeOf((*MockMarkerRepository)(nil).GetByUserAndScenes), userID, sceneIDs)
+}
+
+// GetCollectionMarkersForUser mocks base method.
+func (m *MockMarkerRepository) GetCollectionMarkersForUser(userID, tagID uint, offset, limit int, sortBy string) ([]data.CollectionMarker, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionMarkersForUser", userID, tagID, offset, limit, sortBy)
+	ret0, _ := ret[0].([]data.CollectionMarker)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCollectionMarkersForUser indicates an expected call of GetCollectionMarkersForUser.
+func (mr *MockMarkerRepositoryMockRecorder) GetCollectionMarkersForUser(userID, tagID, offset, limit, sortBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionMarkersForUser", reflect.TypeOf((*MockMarkerRepository)(nil).GetCollectionMarkersForUser), userID, tagID, offset, limit, sortBy)
+}
+
 // GetLabelGroupsForUser mocks base method.
 func (m *MockMarkerRepository) GetLabelGroupsForUser(userID uint, offset, limit int, sortBy string) ([]data.MarkerLabelGroup, int64, error) {
 	m.ctrl.T.Helper()
@@ -233,6 +339,36 @@ func (mr *MockMarkerRepositoryMockRecorder) GetLabelGroupsForUser(userID, offset
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabelGroupsForUser", reflect.TypeOf((*MockMarkerRepository)(nil).GetLabelGroupsForUser), userID, offset, limit, sortBy)
 }
 
+// GetLabelSetting mocks base method.
+func (m *MockMarkerRepository) GetLabelSetting(userID uint, label string) (*data.MarkerLabelSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabelSetting", userID, label)
+	ret0, _ := ret[0].(*data.MarkerLabelSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabelSetting indicates an expected call of GetLabelSetting.
+func (mr *MockMarkerRepositoryMockRecorder) GetLabelSetting(userID, label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabelSetting", reflect.TypeOf((*MockMarkerRepository)(nil).GetLabelSetting), userID, label)
+}
+
+// GetLabelSettings mocks base method.
+func (m *MockMarkerRepository) GetLabelSettings(userID uint) ([]data.MarkerLabelSetting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabelSettings", userID)
+	ret0, _ := ret[0].([]data.MarkerLabelSetting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabelSettings indicates an expected call of GetLabelSettings.
+func (mr *MockMarkerRepositoryMockRecorder) GetLabelSettings(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabelSettings", reflect.TypeOf((*MockMarkerRepository)(nil).GetLabelSettings), userID)
+}
+
 // GetLabelSuggestionsForUser mocks base method.
 func (m *MockMarkerRepository) GetLabelSuggestionsForUser(userID uint, limit int) ([]data.MarkerLabelSuggestion, error) {
 	m.ctrl.T.Helper()
@@ -263,6 +399,36 @@ func (mr *MockMarkerRepositoryMockRecorder) GetLabelTags(userID, label any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabelTags", reflect.TypeOf((*MockMarkerRepository)(nil).GetLabelTags), userID, label)
 }
 
+// GetLabeledBySceneIDs mocks base method.
+func (m *MockMarkerRepository) GetLabeledBySceneIDs(sceneIDs []uint) ([]data.UserSceneMarker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabeledBySceneIDs", sceneIDs)
+	ret0, _ := ret[0].([]data.UserSceneMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabeledBySceneIDs indicates an expected call of GetLabeledBySceneIDs.
+func (mr *MockMarkerRepositoryMockRecorder) GetLabeledBySceneIDs(sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabeledBySceneIDs", reflect.TypeOf((*MockMarkerRepository)(nil).GetLabeledBySceneIDs), sceneIDs)
+}
+
+// GetMarkedSceneIDs mocks base method.
+func (m *MockMarkerRepository) GetMarkedSceneIDs(userID uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMarkedSceneIDs", userID)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMarkedSceneIDs indicates an expected call of GetMarkedSceneIDs.
+func (mr *MockMarkerRepositoryMockRecorder) GetMarkedSceneIDs(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarkedSceneIDs", reflect.TypeOf((*MockMarkerRepository)(nil).GetMarkedSceneIDs), userID)
+}
+
 // GetMarkerIDsByLabel mocks base method.
 func (m *MockMarkerRepository) GetMarkerIDsByLabel(userID uint, label string) ([]uint, error) {
 	m.ctrl.T.Helper()
@@ -354,6 +520,52 @@ func (mr *MockMarkerRepositoryMockRecorder) GetSceneIDsByLabels(userID, labels a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSceneIDsByLabels", reflect.TypeOf((*MockMarkerRepository)(nil).GetSceneIDsByLabels), userID, labels)
 }
 
+// GetSharedByScene mocks base method.
+func (m *MockMarkerRepository) GetSharedByScene(sceneID, excludeUserID uint) ([]data.SharedMarker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedByScene", sceneID, excludeUserID)
+	ret0, _ := ret[0].([]data.SharedMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedByScene indicates an expected call of GetSharedByScene.
+func (mr *MockMarkerRepositoryMockRecorder) GetSharedByScene(sceneID, excludeUserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedByScene", reflect.TypeOf((*MockMarkerRepository)(nil).GetSharedByScene), sceneID, excludeUserID)
+}
+
+// GetTagCollectionsForUser mocks base method.
+func (m *MockMarkerRepository) GetTagCollectionsForUser(userID uint, offset, limit int, sortBy string) ([]data.MarkerTagCollection, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagCollectionsForUser", userID, offset, limit, sortBy)
+	ret0, _ := ret[0].([]data.MarkerTagCollection)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTagCollectionsForUser indicates an expected call of GetTagCollectionsForUser.
+func (mr *MockMarkerRepositoryMockRecorder) GetTagCollectionsForUser(userID, offset, limit, sortBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagCollectionsForUser", reflect.TypeOf((*MockMarkerRepository)(nil).GetTagCollectionsForUser), userID, offset, limit, sortBy)
+}
+
+// SearchLabels mocks base method.
+func (m *MockMarkerRepository) SearchLabels(userID uint, query string, limit int) ([]data.UserSceneMarker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchLabels", userID, query, limit)
+	ret0, _ := ret[0].([]data.UserSceneMarker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchLabels indicates an expected call of SearchLabels.
+func (mr *MockMarkerRepositoryMockRecorder) SearchLabels(userID, query, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLabels", reflect.TypeOf((*MockMarkerRepository)(nil).SearchLabels), userID, query, limit)
+}
+
 // SetLabelTags mocks base method.
 func (m *MockMarkerRepository) SetLabelTags(userID uint, label string, tagIDs []uint) error {
 	m.ctrl.T.Helper()
@@ -409,3 +621,17 @@ func (mr *MockMarkerRepositoryMockRecorder) Update(marker any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockMarkerRepository)(nil).Update), marker)
 }
+
+// UpsertLabelSetting mocks base method.
+func (m *MockMarkerRepository) UpsertLabelSetting(setting *data.MarkerLabelSetting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLabelSetting", setting)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertLabelSetting indicates an expected call of UpsertLabelSetting.
+func (mr *MockMarkerRepositoryMockRecorder) UpsertLabelSetting(setting any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLabelSetting", reflect.TypeOf((*MockMarkerRepository)(nil).UpsertLabelSetting), setting)
+}
@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: OfflineSyncRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_offline_sync_repository.go -package=mocks goonhub/internal/data OfflineSyncRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOfflineSyncRepository is a mock of OfflineSyncRepository interface.
+type MockOfflineSyncRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOfflineSyncRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOfflineSyncRepositoryMockRecorder is the mock recorder for MockOfflineSyncRepository.
+type MockOfflineSyncRepositoryMockRecorder struct {
+	mock *MockOfflineSyncRepository
+}
+
+// NewMockOfflineSyncRepository creates a new mock instance.
+func NewMockOfflineSyncRepository(ctrl *gomock.Controller) *MockOfflineSyncRepository {
+	mock := &MockOfflineSyncRepository{ctrl: ctrl}
+	mock.recorder = &MockOfflineSyncRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOfflineSyncRepository) EXPECT() *MockOfflineSyncRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddScenes mocks base method.
+func (m *MockOfflineSyncRepository) AddScenes(userID uint, sceneIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddScenes", userID, sceneIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddScenes indicates an expected call of AddScenes.
+func (mr *MockOfflineSyncRepositoryMockRecorder) AddScenes(userID, sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddScenes", reflect.TypeOf((*MockOfflineSyncRepository)(nil).AddScenes), userID, sceneIDs)
+}
+
+// CountActive mocks base method.
+func (m *MockOfflineSyncRepository) CountActive(userID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActive", userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActive indicates an expected call of CountActive.
+func (mr *MockOfflineSyncRepositoryMockRecorder) CountActive(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActive", reflect.TypeOf((*MockOfflineSyncRepository)(nil).CountActive), userID)
+}
+
+// DeleteTombstonesBefore mocks base method.
+func (m *MockOfflineSyncRepository) DeleteTombstonesBefore(userID uint, cutoff time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTombstonesBefore", userID, cutoff)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTombstonesBefore indicates an expected call of DeleteTombstonesBefore.
+func (mr *MockOfflineSyncRepositoryMockRecorder) DeleteTombstonesBefore(userID, cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTombstonesBefore", reflect.TypeOf((*MockOfflineSyncRepository)(nil).DeleteTombstonesBefore), userID, cutoff)
+}
+
+// ListByUser mocks base method.
+func (m *MockOfflineSyncRepository) ListByUser(userID uint) ([]data.UserOfflineScene, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", userID)
+	ret0, _ := ret[0].([]data.UserOfflineScene)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockOfflineSyncRepositoryMockRecorder) ListByUser(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockOfflineSyncRepository)(nil).ListByUser), userID)
+}
+
+// RemoveScenes mocks base method.
+func (m *MockOfflineSyncRepository) RemoveScenes(userID uint, sceneIDs []uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveScenes", userID, sceneIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveScenes indicates an expected call of RemoveScenes.
+func (mr *MockOfflineSyncRepositoryMockRecorder) RemoveScenes(userID, sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveScenes", reflect.TypeOf((*MockOfflineSyncRepository)(nil).RemoveScenes), userID, sceneIDs)
+}
+
+// UpdateChecksum mocks base method.
+func (m *MockOfflineSyncRepository) UpdateChecksum(id uint, checksum string, changedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChecksum", id, checksum, changedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChecksum indicates an expected call of UpdateChecksum.
+func (mr *MockOfflineSyncRepositoryMockRecorder) UpdateChecksum(id, checksum, changedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChecksum", reflect.TypeOf((*MockOfflineSyncRepository)(nil).UpdateChecksum), id, checksum, changedAt)
+}
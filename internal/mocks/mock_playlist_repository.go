@@ -54,6 +54,21 @@ func (mr *MockPlaylistRepositoryMockRecorder) AddScenes(playlistID, sceneIDs any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddScenes", reflect.TypeOf((*MockPlaylistRepository)(nil).AddScenes), playlistID, sceneIDs)
 }
 
+// CountPlaylistsBySceneIDs mocks base method.
+func (m *MockPlaylistRepository) CountPlaylistsBySceneIDs(sceneIDs []uint) (map[uint]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPlaylistsBySceneIDs", sceneIDs)
+	ret0, _ := ret[0].(map[uint]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPlaylistsBySceneIDs indicates an expected call of CountPlaylistsBySceneIDs.
+func (mr *MockPlaylistRepositoryMockRecorder) CountPlaylistsBySceneIDs(sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPlaylistsBySceneIDs", reflect.TypeOf((*MockPlaylistRepository)(nil).CountPlaylistsBySceneIDs), sceneIDs)
+}
+
 // Create mocks base method.
 func (m *MockPlaylistRepository) Create(playlist *data.Playlist) error {
 	m.ctrl.T.Helper()
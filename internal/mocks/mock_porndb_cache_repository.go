@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: PornDBCacheRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_porndb_cache_repository.go -package=mocks goonhub/internal/data PornDBCacheRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPornDBCacheRepository is a mock of PornDBCacheRepository interface.
+type MockPornDBCacheRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPornDBCacheRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPornDBCacheRepositoryMockRecorder is the mock recorder for MockPornDBCacheRepository.
+type MockPornDBCacheRepositoryMockRecorder struct {
+	mock *MockPornDBCacheRepository
+}
+
+// NewMockPornDBCacheRepository creates a new mock instance.
+func NewMockPornDBCacheRepository(ctrl *gomock.Controller) *MockPornDBCacheRepository {
+	mock := &MockPornDBCacheRepository{ctrl: ctrl}
+	mock.recorder = &MockPornDBCacheRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPornDBCacheRepository) EXPECT() *MockPornDBCacheRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockPornDBCacheRepository) Get(key string, now time.Time) (*data.PornDBCacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key, now)
+	ret0, _ := ret[0].(*data.PornDBCacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPornDBCacheRepositoryMockRecorder) Get(key, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPornDBCacheRepository)(nil).Get), key, now)
+}
+
+// Purge mocks base method.
+func (m *MockPornDBCacheRepository) Purge(kind string, expiredOnly bool, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", kind, expiredOnly, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockPornDBCacheRepositoryMockRecorder) Purge(kind, expiredOnly, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockPornDBCacheRepository)(nil).Purge), kind, expiredOnly, now)
+}
+
+// RecordHit mocks base method.
+func (m *MockPornDBCacheRepository) RecordHit(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordHit", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordHit indicates an expected call of RecordHit.
+func (mr *MockPornDBCacheRepositoryMockRecorder) RecordHit(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordHit", reflect.TypeOf((*MockPornDBCacheRepository)(nil).RecordHit), key)
+}
+
+// Set mocks base method.
+func (m *MockPornDBCacheRepository) Set(entry *data.PornDBCacheEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockPornDBCacheRepositoryMockRecorder) Set(entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockPornDBCacheRepository)(nil).Set), entry)
+}
+
+// Stats mocks base method.
+func (m *MockPornDBCacheRepository) Stats(now time.Time) ([]data.PornDBCacheKindStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", now)
+	ret0, _ := ret[0].([]data.PornDBCacheKindStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockPornDBCacheRepositoryMockRecorder) Stats(now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockPornDBCacheRepository)(nil).Stats), now)
+}
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: PornDBMatchRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_porndb_match_repository.go -package=mocks goonhub/internal/data PornDBMatchRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPornDBMatchRepository is a mock of PornDBMatchRepository interface.
+type MockPornDBMatchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPornDBMatchRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPornDBMatchRepositoryMockRecorder is the mock recorder for MockPornDBMatchRepository.
+type MockPornDBMatchRepositoryMockRecorder struct {
+	mock *MockPornDBMatchRepository
+}
+
+// NewMockPornDBMatchRepository creates a new mock instance.
+func NewMockPornDBMatchRepository(ctrl *gomock.Controller) *MockPornDBMatchRepository {
+	mock := &MockPornDBMatchRepository{ctrl: ctrl}
+	mock.recorder = &MockPornDBMatchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPornDBMatchRepository) EXPECT() *MockPornDBMatchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountUnmatchedScenes mocks base method.
+func (m *MockPornDBMatchRepository) CountUnmatchedScenes() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnmatchedScenes")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnmatchedScenes indicates an expected call of CountUnmatchedScenes.
+func (mr *MockPornDBMatchRepositoryMockRecorder) CountUnmatchedScenes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnmatchedScenes", reflect.TypeOf((*MockPornDBMatchRepository)(nil).CountUnmatchedScenes))
+}
+
+// GetBySceneID mocks base method.
+func (m *MockPornDBMatchRepository) GetBySceneID(sceneID uint) (*data.PornDBMatchReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySceneID", sceneID)
+	ret0, _ := ret[0].(*data.PornDBMatchReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySceneID indicates an expected call of GetBySceneID.
+func (mr *MockPornDBMatchRepositoryMockRecorder) GetBySceneID(sceneID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySceneID", reflect.TypeOf((*MockPornDBMatchRepository)(nil).GetBySceneID), sceneID)
+}
+
+// List mocks base method.
+func (m *MockPornDBMatchRepository) List(status string, page, limit int) ([]data.PornDBMatchReview, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", status, page, limit)
+	ret0, _ := ret[0].([]data.PornDBMatchReview)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockPornDBMatchRepositoryMockRecorder) List(status, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPornDBMatchRepository)(nil).List), status, page, limit)
+}
+
+// ListUnmatchedScenes mocks base method.
+func (m *MockPornDBMatchRepository) ListUnmatchedScenes(afterID uint, limit int) ([]data.Scene, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnmatchedScenes", afterID, limit)
+	ret0, _ := ret[0].([]data.Scene)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnmatchedScenes indicates an expected call of ListUnmatchedScenes.
+func (mr *MockPornDBMatchRepositoryMockRecorder) ListUnmatchedScenes(afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnmatchedScenes", reflect.TypeOf((*MockPornDBMatchRepository)(nil).ListUnmatchedScenes), afterID, limit)
+}
+
+// Update mocks base method.
+func (m *MockPornDBMatchRepository) Update(review *data.PornDBMatchReview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPornDBMatchRepositoryMockRecorder) Update(review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPornDBMatchRepository)(nil).Update), review)
+}
+
+// Upsert mocks base method.
+func (m *MockPornDBMatchRepository) Upsert(review *data.PornDBMatchReview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockPornDBMatchRepositoryMockRecorder) Upsert(review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockPornDBMatchRepository)(nil).Upsert), review)
+}
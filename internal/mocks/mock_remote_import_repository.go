@@ -0,0 +1,172 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: RemoteImportRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_remote_import_repository.go -package=mocks goonhub/internal/data RemoteImportRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRemoteImportRepository is a mock of RemoteImportRepository interface.
+type MockRemoteImportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRemoteImportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRemoteImportRepositoryMockRecorder is the mock recorder for MockRemoteImportRepository.
+type MockRemoteImportRepositoryMockRecorder struct {
+	mock *MockRemoteImportRepository
+}
+
+// NewMockRemoteImportRepository creates a new mock instance.
+func NewMockRemoteImportRepository(ctrl *gomock.Controller) *MockRemoteImportRepository {
+	mock := &MockRemoteImportRepository{ctrl: ctrl}
+	mock.recorder = &MockRemoteImportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRemoteImportRepository) EXPECT() *MockRemoteImportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRemoteImportRepository) Create(item *data.RemoteImport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRemoteImportRepositoryMockRecorder) Create(item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRemoteImportRepository)(nil).Create), item)
+}
+
+// GetByJobID mocks base method.
+func (m *MockRemoteImportRepository) GetByJobID(jobID string) (*data.RemoteImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByJobID", jobID)
+	ret0, _ := ret[0].(*data.RemoteImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByJobID indicates an expected call of GetByJobID.
+func (mr *MockRemoteImportRepositoryMockRecorder) GetByJobID(jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByJobID", reflect.TypeOf((*MockRemoteImportRepository)(nil).GetByJobID), jobID)
+}
+
+// List mocks base method.
+func (m *MockRemoteImportRepository) List(page, limit int) ([]data.RemoteImport, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", page, limit)
+	ret0, _ := ret[0].([]data.RemoteImport)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockRemoteImportRepositoryMockRecorder) List(page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRemoteImportRepository)(nil).List), page, limit)
+}
+
+// ListUnfinished mocks base method.
+func (m *MockRemoteImportRepository) ListUnfinished() ([]data.RemoteImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnfinished")
+	ret0, _ := ret[0].([]data.RemoteImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnfinished indicates an expected call of ListUnfinished.
+func (mr *MockRemoteImportRepositoryMockRecorder) ListUnfinished() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnfinished", reflect.TypeOf((*MockRemoteImportRepository)(nil).ListUnfinished))
+}
+
+// MarkCompleted mocks base method.
+func (m *MockRemoteImportRepository) MarkCompleted(jobID string, sceneID *uint, completedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkCompleted", jobID, sceneID, completedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkCompleted indicates an expected call of MarkCompleted.
+func (mr *MockRemoteImportRepositoryMockRecorder) MarkCompleted(jobID, sceneID, completedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkCompleted", reflect.TypeOf((*MockRemoteImportRepository)(nil).MarkCompleted), jobID, sceneID, completedAt)
+}
+
+// MarkDownloaded mocks base method.
+func (m *MockRemoteImportRepository) MarkDownloaded(jobID, filename string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDownloaded", jobID, filename)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDownloaded indicates an expected call of MarkDownloaded.
+func (mr *MockRemoteImportRepositoryMockRecorder) MarkDownloaded(jobID, filename any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDownloaded", reflect.TypeOf((*MockRemoteImportRepository)(nil).MarkDownloaded), jobID, filename)
+}
+
+// MarkStarted mocks base method.
+func (m *MockRemoteImportRepository) MarkStarted(jobID string, startedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkStarted", jobID, startedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkStarted indicates an expected call of MarkStarted.
+func (mr *MockRemoteImportRepositoryMockRecorder) MarkStarted(jobID, startedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkStarted", reflect.TypeOf((*MockRemoteImportRepository)(nil).MarkStarted), jobID, startedAt)
+}
+
+// UpdateProgress mocks base method.
+func (m *MockRemoteImportRepository) UpdateProgress(jobID string, bytesDownloaded int64, totalBytes *int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProgress", jobID, bytesDownloaded, totalBytes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProgress indicates an expected call of UpdateProgress.
+func (mr *MockRemoteImportRepositoryMockRecorder) UpdateProgress(jobID, bytesDownloaded, totalBytes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProgress", reflect.TypeOf((*MockRemoteImportRepository)(nil).UpdateProgress), jobID, bytesDownloaded, totalBytes)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockRemoteImportRepository) UpdateStatus(jobID, status, errMsg string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", jobID, status, errMsg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockRemoteImportRepositoryMockRecorder) UpdateStatus(jobID, status, errMsg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockRemoteImportRepository)(nil).UpdateStatus), jobID, status, errMsg)
+}
@@ -40,6 +40,36 @@ func (m *MockSavedSearchRepository) EXPECT() *MockSavedSearchRepositoryMockRecor
 	return m.recorder
 }
 
+// AddMatches mocks base method.
+func (m *MockSavedSearchRepository) AddMatches(searchID uint, sceneIDs []uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMatches", searchID, sceneIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMatches indicates an expected call of AddMatches.
+func (mr *MockSavedSearchRepositoryMockRecorder) AddMatches(searchID, sceneIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMatches", reflect.TypeOf((*MockSavedSearchRepository)(nil).AddMatches), searchID, sceneIDs)
+}
+
+// CountUnseenMatches mocks base method.
+func (m *MockSavedSearchRepository) CountUnseenMatches(searchIDs []uint) (map[uint]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnseenMatches", searchIDs)
+	ret0, _ := ret[0].(map[uint]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnseenMatches indicates an expected call of CountUnseenMatches.
+func (mr *MockSavedSearchRepositoryMockRecorder) CountUnseenMatches(searchIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnseenMatches", reflect.TypeOf((*MockSavedSearchRepository)(nil).CountUnseenMatches), searchIDs)
+}
+
 // Create mocks base method.
 func (m *MockSavedSearchRepository) Create(search *data.SavedSearch) error {
 	m.ctrl.T.Helper()
@@ -98,6 +128,21 @@ func (mr *MockSavedSearchRepositoryMockRecorder) GetByUUID(uuid any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUUID", reflect.TypeOf((*MockSavedSearchRepository)(nil).GetByUUID), uuid)
 }
 
+// GetUnseenMatchSceneIDs mocks base method.
+func (m *MockSavedSearchRepository) GetUnseenMatchSceneIDs(searchID uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnseenMatchSceneIDs", searchID)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnseenMatchSceneIDs indicates an expected call of GetUnseenMatchSceneIDs.
+func (mr *MockSavedSearchRepositoryMockRecorder) GetUnseenMatchSceneIDs(searchID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnseenMatchSceneIDs", reflect.TypeOf((*MockSavedSearchRepository)(nil).GetUnseenMatchSceneIDs), searchID)
+}
+
 // ListByUserID mocks base method.
 func (m *MockSavedSearchRepository) ListByUserID(userID uint) ([]data.SavedSearch, error) {
 	m.ctrl.T.Helper()
@@ -113,6 +158,35 @@ func (mr *MockSavedSearchRepositoryMockRecorder) ListByUserID(userID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUserID", reflect.TypeOf((*MockSavedSearchRepository)(nil).ListByUserID), userID)
 }
 
+// ListWatched mocks base method.
+func (m *MockSavedSearchRepository) ListWatched() ([]data.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWatched")
+	ret0, _ := ret[0].([]data.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWatched indicates an expected call of ListWatched.
+func (mr *MockSavedSearchRepositoryMockRecorder) ListWatched() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWatched", reflect.TypeOf((*MockSavedSearchRepository)(nil).ListWatched))
+}
+
+// MarkMatchesSeen mocks base method.
+func (m *MockSavedSearchRepository) MarkMatchesSeen(searchID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMatchesSeen", searchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkMatchesSeen indicates an expected call of MarkMatchesSeen.
+func (mr *MockSavedSearchRepositoryMockRecorder) MarkMatchesSeen(searchID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMatchesSeen", reflect.TypeOf((*MockSavedSearchRepository)(nil).MarkMatchesSeen), searchID)
+}
+
 // Update mocks base method.
 func (m *MockSavedSearchRepository) Update(search *data.SavedSearch) error {
 	m.ctrl.T.Helper()
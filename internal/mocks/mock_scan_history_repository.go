@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: ScanHistoryRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_scan_history_repository.go -package=mocks goonhub/internal/data ScanHistoryRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScanHistoryRepository is a mock of ScanHistoryRepository interface.
+type MockScanHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScanHistoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockScanHistoryRepositoryMockRecorder is the mock recorder for MockScanHistoryRepository.
+type MockScanHistoryRepositoryMockRecorder struct {
+	mock *MockScanHistoryRepository
+}
+
+// NewMockScanHistoryRepository creates a new mock instance.
+func NewMockScanHistoryRepository(ctrl *gomock.Controller) *MockScanHistoryRepository {
+	mock := &MockScanHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockScanHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScanHistoryRepository) EXPECT() *MockScanHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockScanHistoryRepository) Create(scan *data.ScanHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", scan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScanHistoryRepositoryMockRecorder) Create(scan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScanHistoryRepository)(nil).Create), scan)
+}
+
+// GetByID mocks base method.
+func (m *MockScanHistoryRepository) GetByID(id uint) (*data.ScanHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*data.ScanHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockScanHistoryRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockScanHistoryRepository)(nil).GetByID), id)
+}
+
+// GetLatest mocks base method.
+func (m *MockScanHistoryRepository) GetLatest() (*data.ScanHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatest")
+	ret0, _ := ret[0].(*data.ScanHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatest indicates an expected call of GetLatest.
+func (mr *MockScanHistoryRepositoryMockRecorder) GetLatest() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatest", reflect.TypeOf((*MockScanHistoryRepository)(nil).GetLatest))
+}
+
+// GetRunning mocks base method.
+func (m *MockScanHistoryRepository) GetRunning() (*data.ScanHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunning")
+	ret0, _ := ret[0].(*data.ScanHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRunning indicates an expected call of GetRunning.
+func (mr *MockScanHistoryRepositoryMockRecorder) GetRunning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunning", reflect.TypeOf((*MockScanHistoryRepository)(nil).GetRunning))
+}
+
+// List mocks base method.
+func (m *MockScanHistoryRepository) List(page, limit int) ([]data.ScanHistory, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", page, limit)
+	ret0, _ := ret[0].([]data.ScanHistory)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockScanHistoryRepositoryMockRecorder) List(page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockScanHistoryRepository)(nil).List), page, limit)
+}
+
+// MarkInterruptedAsFailedOnStartup mocks base method.
+func (m *MockScanHistoryRepository) MarkInterruptedAsFailedOnStartup() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkInterruptedAsFailedOnStartup")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkInterruptedAsFailedOnStartup indicates an expected call of MarkInterruptedAsFailedOnStartup.
+func (mr *MockScanHistoryRepositoryMockRecorder) MarkInterruptedAsFailedOnStartup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInterruptedAsFailedOnStartup", reflect.TypeOf((*MockScanHistoryRepository)(nil).MarkInterruptedAsFailedOnStartup))
+}
+
+// Update mocks base method.
+func (m *MockScanHistoryRepository) Update(scan *data.ScanHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", scan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScanHistoryRepositoryMockRecorder) Update(scan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScanHistoryRepository)(nil).Update), scan)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: ScanReportRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_scan_report_repository.go -package=mocks goonhub/internal/data ScanReportRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScanReportRepository is a mock of ScanReportRepository interface.
+type MockScanReportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScanReportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockScanReportRepositoryMockRecorder is the mock recorder for MockScanReportRepository.
+type MockScanReportRepositoryMockRecorder struct {
+	mock *MockScanReportRepository
+}
+
+// NewMockScanReportRepository creates a new mock instance.
+func NewMockScanReportRepository(ctrl *gomock.Controller) *MockScanReportRepository {
+	mock := &MockScanReportRepository{ctrl: ctrl}
+	mock.recorder = &MockScanReportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScanReportRepository) EXPECT() *MockScanReportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByKind mocks base method.
+func (m *MockScanReportRepository) CountByKind(scanID uint) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByKind", scanID)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByKind indicates an expected call of CountByKind.
+func (mr *MockScanReportRepositoryMockRecorder) CountByKind(scanID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByKind", reflect.TypeOf((*MockScanReportRepository)(nil).CountByKind), scanID)
+}
+
+// CreateBatch mocks base method.
+func (m *MockScanReportRepository) CreateBatch(entries []data.ScanReportEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockScanReportRepositoryMockRecorder) CreateBatch(entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockScanReportRepository)(nil).CreateBatch), entries)
+}
+
+// ForEach mocks base method.
+func (m *MockScanReportRepository) ForEach(scanID uint, batchSize int, fn func([]data.ScanReportEntry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", scanID, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach.
+func (mr *MockScanReportRepositoryMockRecorder) ForEach(scanID, batchSize, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockScanReportRepository)(nil).ForEach), scanID, batchSize, fn)
+}
+
+// List mocks base method.
+func (m *MockScanReportRepository) List(scanID uint, kind string, page, limit int) ([]data.ScanReportEntry, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", scanID, kind, page, limit)
+	ret0, _ := ret[0].([]data.ScanReportEntry)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockScanReportRepositoryMockRecorder) List(scanID, kind, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockScanReportRepository)(nil).List), scanID, kind, page, limit)
+}
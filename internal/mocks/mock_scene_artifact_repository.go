@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: SceneArtifactRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_scene_artifact_repository.go -package=mocks goonhub/internal/data SceneArtifactRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSceneArtifactRepository is a mock of SceneArtifactRepository interface.
+type MockSceneArtifactRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSceneArtifactRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSceneArtifactRepositoryMockRecorder is the mock recorder for MockSceneArtifactRepository.
+type MockSceneArtifactRepositoryMockRecorder struct {
+	mock *MockSceneArtifactRepository
+}
+
+// NewMockSceneArtifactRepository creates a new mock instance.
+func NewMockSceneArtifactRepository(ctrl *gomock.Controller) *MockSceneArtifactRepository {
+	mock := &MockSceneArtifactRepository{ctrl: ctrl}
+	mock.recorder = &MockSceneArtifactRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSceneArtifactRepository) EXPECT() *MockSceneArtifactRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountScenesWithoutSizes mocks base method.
+func (m *MockSceneArtifactRepository) CountScenesWithoutSizes() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountScenesWithoutSizes")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountScenesWithoutSizes indicates an expected call of CountScenesWithoutSizes.
+func (mr *MockSceneArtifactRepositoryMockRecorder) CountScenesWithoutSizes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountScenesWithoutSizes", reflect.TypeOf((*MockSceneArtifactRepository)(nil).CountScenesWithoutSizes))
+}
+
+// GetBySceneID mocks base method.
+func (m *MockSceneArtifactRepository) GetBySceneID(sceneID uint) (*data.SceneArtifactSize, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySceneID", sceneID)
+	ret0, _ := ret[0].(*data.SceneArtifactSize)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySceneID indicates an expected call of GetBySceneID.
+func (mr *MockSceneArtifactRepositoryMockRecorder) GetBySceneID(sceneID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySceneID", reflect.TypeOf((*MockSceneArtifactRepository)(nil).GetBySceneID), sceneID)
+}
+
+// GetStatsByStoragePath mocks base method.
+func (m *MockSceneArtifactRepository) GetStatsByStoragePath() ([]data.StoragePathArtifactStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatsByStoragePath")
+	ret0, _ := ret[0].([]data.StoragePathArtifactStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatsByStoragePath indicates an expected call of GetStatsByStoragePath.
+func (mr *MockSceneArtifactRepositoryMockRecorder) GetStatsByStoragePath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatsByStoragePath", reflect.TypeOf((*MockSceneArtifactRepository)(nil).GetStatsByStoragePath))
+}
+
+// Upsert mocks base method.
+func (m *MockSceneArtifactRepository) Upsert(size *data.SceneArtifactSize) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", size)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockSceneArtifactRepositoryMockRecorder) Upsert(size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockSceneArtifactRepository)(nil).Upsert), size)
+}
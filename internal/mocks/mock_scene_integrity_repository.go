@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: SceneIntegrityRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_scene_integrity_repository.go -package=mocks goonhub/internal/data SceneIntegrityRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSceneIntegrityRepository is a mock of SceneIntegrityRepository interface.
+type MockSceneIntegrityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSceneIntegrityRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSceneIntegrityRepositoryMockRecorder is the mock recorder for MockSceneIntegrityRepository.
+type MockSceneIntegrityRepositoryMockRecorder struct {
+	mock *MockSceneIntegrityRepository
+}
+
+// NewMockSceneIntegrityRepository creates a new mock instance.
+func NewMockSceneIntegrityRepository(ctrl *gomock.Controller) *MockSceneIntegrityRepository {
+	mock := &MockSceneIntegrityRepository{ctrl: ctrl}
+	mock.recorder = &MockSceneIntegrityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSceneIntegrityRepository) EXPECT() *MockSceneIntegrityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetBySceneID mocks base method.
+func (m *MockSceneIntegrityRepository) GetBySceneID(sceneID uint) (*data.SceneIntegrityReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySceneID", sceneID)
+	ret0, _ := ret[0].(*data.SceneIntegrityReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySceneID indicates an expected call of GetBySceneID.
+func (mr *MockSceneIntegrityRepositoryMockRecorder) GetBySceneID(sceneID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySceneID", reflect.TypeOf((*MockSceneIntegrityRepository)(nil).GetBySceneID), sceneID)
+}
+
+// Upsert mocks base method.
+func (m *MockSceneIntegrityRepository) Upsert(report *data.SceneIntegrityReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockSceneIntegrityRepositoryMockRecorder) Upsert(report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockSceneIntegrityRepository)(nil).Upsert), report)
+}
@@ -41,6 +41,35 @@ func (m *MockSceneRepository) EXPECT() *MockSceneRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ApplyMetadata mocks base method.
+func (m *MockSceneRepository) ApplyMetadata(app data.SceneMetadataApplication) (*data.SceneMetadataApplicationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyMetadata", app)
+	ret0, _ := ret[0].(*data.SceneMetadataApplicationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyMetadata indicates an expected call of ApplyMetadata.
+func (mr *MockSceneRepositoryMockRecorder) ApplyMetadata(app any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyMetadata", reflect.TypeOf((*MockSceneRepository)(nil).ApplyMetadata), app)
+}
+
+// BulkUpdateColumns mocks base method.
+func (m *MockSceneRepository) BulkUpdateColumns(updates []data.SceneColumnUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateColumns", updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkUpdateColumns indicates an expected call of BulkUpdateColumns.
+func (mr *MockSceneRepositoryMockRecorder) BulkUpdateColumns(updates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateColumns", reflect.TypeOf((*MockSceneRepository)(nil).BulkUpdateColumns), updates)
+}
+
 // BulkUpdateStudio mocks base method.
 func (m *MockSceneRepository) BulkUpdateStudio(sceneIDs []uint, studio string) error {
 	m.ctrl.T.Helper()
@@ -55,6 +84,36 @@ func (mr *MockSceneRepositoryMockRecorder) BulkUpdateStudio(sceneIDs, studio any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateStudio", reflect.TypeOf((*MockSceneRepository)(nil).BulkUpdateStudio), sceneIDs, studio)
 }
 
+// CountActive mocks base method.
+func (m *MockSceneRepository) CountActive() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActive")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActive indicates an expected call of CountActive.
+func (mr *MockSceneRepositoryMockRecorder) CountActive() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActive", reflect.TypeOf((*MockSceneRepository)(nil).CountActive))
+}
+
+// CountByReviewState mocks base method.
+func (m *MockSceneRepository) CountByReviewState() (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByReviewState")
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByReviewState indicates an expected call of CountByReviewState.
+func (mr *MockSceneRepositoryMockRecorder) CountByReviewState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByReviewState", reflect.TypeOf((*MockSceneRepository)(nil).CountByReviewState))
+}
+
 // CountTrashed mocks base method.
 func (m *MockSceneRepository) CountTrashed() (int64, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +186,21 @@ func (mr *MockSceneRepositoryMockRecorder) ExistsByStoredPath(path any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByStoredPath", reflect.TypeOf((*MockSceneRepository)(nil).ExistsByStoredPath), path)
 }
 
+// GetActiveIDs mocks base method.
+func (m *MockSceneRepository) GetActiveIDs() ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveIDs")
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveIDs indicates an expected call of GetActiveIDs.
+func (mr *MockSceneRepositoryMockRecorder) GetActiveIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveIDs", reflect.TypeOf((*MockSceneRepository)(nil).GetActiveIDs))
+}
+
 // GetAll mocks base method.
 func (m *MockSceneRepository) GetAll() ([]data.Scene, error) {
 	m.ctrl.T.Helper()
@@ -172,6 +246,21 @@ func (mr *MockSceneRepositoryMockRecorder) GetAllWithStoragePath() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWithStoragePath", reflect.TypeOf((*MockSceneRepository)(nil).GetAllWithStoragePath))
 }
 
+// GetBatchAfterID mocks base method.
+func (m *MockSceneRepository) GetBatchAfterID(afterID uint, limit int) ([]data.Scene, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatchAfterID", afterID, limit)
+	ret0, _ := ret[0].([]data.Scene)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatchAfterID indicates an expected call of GetBatchAfterID.
+func (mr *MockSceneRepositoryMockRecorder) GetBatchAfterID(afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatchAfterID", reflect.TypeOf((*MockSceneRepository)(nil).GetBatchAfterID), afterID, limit)
+}
+
 // GetByID mocks base method.
 func (m *MockSceneRepository) GetByID(id uint) (*data.Scene, error) {
 	m.ctrl.T.Helper()
@@ -322,6 +411,66 @@ func (mr *MockSceneRepositoryMockRecorder) GetScanLookupEntries() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScanLookupEntries", reflect.TypeOf((*MockSceneRepository)(nil).GetScanLookupEntries))
 }
 
+// GetSceneIDsByCorruption mocks base method.
+func (m *MockSceneRepository) GetSceneIDsByCorruption(isCorrupted bool) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSceneIDsByCorruption", isCorrupted)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSceneIDsByCorruption indicates an expected call of GetSceneIDsByCorruption.
+func (mr *MockSceneRepositoryMockRecorder) GetSceneIDsByCorruption(isCorrupted any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSceneIDsByCorruption", reflect.TypeOf((*MockSceneRepository)(nil).GetSceneIDsByCorruption), isCorrupted)
+}
+
+// GetSceneIDsByFileHash mocks base method.
+func (m *MockSceneRepository) GetSceneIDsByFileHash(fileHash string) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSceneIDsByFileHash", fileHash)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSceneIDsByFileHash indicates an expected call of GetSceneIDsByFileHash.
+func (mr *MockSceneRepositoryMockRecorder) GetSceneIDsByFileHash(fileHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSceneIDsByFileHash", reflect.TypeOf((*MockSceneRepository)(nil).GetSceneIDsByFileHash), fileHash)
+}
+
+// GetSceneIDsByPornDBID mocks base method.
+func (m *MockSceneRepository) GetSceneIDsByPornDBID(porndbSceneID string) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSceneIDsByPornDBID", porndbSceneID)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSceneIDsByPornDBID indicates an expected call of GetSceneIDsByPornDBID.
+func (mr *MockSceneRepositoryMockRecorder) GetSceneIDsByPornDBID(porndbSceneID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSceneIDsByPornDBID", reflect.TypeOf((*MockSceneRepository)(nil).GetSceneIDsByPornDBID), porndbSceneID)
+}
+
+// GetSceneIDsByReviewStates mocks base method.
+func (m *MockSceneRepository) GetSceneIDsByReviewStates(states []string) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSceneIDsByReviewStates", states)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSceneIDsByReviewStates indicates an expected call of GetSceneIDsByReviewStates.
+func (mr *MockSceneRepositoryMockRecorder) GetSceneIDsByReviewStates(states any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSceneIDsByReviewStates", reflect.TypeOf((*MockSceneRepository)(nil).GetSceneIDsByReviewStates), states)
+}
+
 // GetSceneIDsWithPornDBID mocks base method.
 func (m *MockSceneRepository) GetSceneIDsWithPornDBID() ([]uint, error) {
 	m.ctrl.T.Helper()
@@ -382,6 +531,21 @@ func (mr *MockSceneRepositoryMockRecorder) GetScenesNeedingPhase(phase any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScenesNeedingPhase", reflect.TypeOf((*MockSceneRepository)(nil).GetScenesNeedingPhase), phase)
 }
 
+// GetSharedFileHashes mocks base method.
+func (m *MockSceneRepository) GetSharedFileHashes() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedFileHashes")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedFileHashes indicates an expected call of GetSharedFileHashes.
+func (mr *MockSceneRepositoryMockRecorder) GetSharedFileHashes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedFileHashes", reflect.TypeOf((*MockSceneRepository)(nil).GetSharedFileHashes))
+}
+
 // HardDelete mocks base method.
 func (m *MockSceneRepository) HardDelete(id uint) (*data.Scene, error) {
 	m.ctrl.T.Helper()
@@ -529,6 +693,20 @@ func (mr *MockSceneRepositoryMockRecorder) UpdateBasicMetadata(id, duration, wid
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBasicMetadata", reflect.TypeOf((*MockSceneRepository)(nil).UpdateBasicMetadata), id, duration, width, height, frameRate, bitRate, videoCodec, audioCodec)
 }
 
+// UpdateChecksum mocks base method.
+func (m *MockSceneRepository) UpdateChecksum(id uint, fileHash string, mismatch bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChecksum", id, fileHash, mismatch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChecksum indicates an expected call of UpdateChecksum.
+func (mr *MockSceneRepositoryMockRecorder) UpdateChecksum(id, fileHash, mismatch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChecksum", reflect.TypeOf((*MockSceneRepository)(nil).UpdateChecksum), id, fileHash, mismatch)
+}
+
 // UpdateDetails mocks base method.
 func (m *MockSceneRepository) UpdateDetails(id uint, title, description string, releaseDate *time.Time) error {
 	m.ctrl.T.Helper()
@@ -613,6 +791,20 @@ func (mr *MockSceneRepositoryMockRecorder) UpdateProcessingStatus(id, status, er
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProcessingStatus", reflect.TypeOf((*MockSceneRepository)(nil).UpdateProcessingStatus), id, status, errorMsg)
 }
 
+// UpdateReviewState mocks base method.
+func (m *MockSceneRepository) UpdateReviewState(sceneIDs []uint, state string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReviewState", sceneIDs, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReviewState indicates an expected call of UpdateReviewState.
+func (mr *MockSceneRepositoryMockRecorder) UpdateReviewState(sceneIDs, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReviewState", reflect.TypeOf((*MockSceneRepository)(nil).UpdateReviewState), sceneIDs, state)
+}
+
 // UpdateSceneMetadata mocks base method.
 func (m *MockSceneRepository) UpdateSceneMetadata(id uint, title, description, studio string, releaseDate *time.Time, porndbSceneID string) error {
 	m.ctrl.T.Helper()
@@ -628,17 +820,17 @@ func (mr *MockSceneRepositoryMockRecorder) UpdateSceneMetadata(id, title, descri
 }
 
 // UpdateSprites mocks base method.
-func (m *MockSceneRepository) UpdateSprites(id uint, spriteSheetPath, vttPath string, spriteSheetCount int) error {
+func (m *MockSceneRepository) UpdateSprites(id uint, spriteSheetPath, vttPath string, spriteSheetCount int, trickplayPath string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSprites", id, spriteSheetPath, vttPath, spriteSheetCount)
+	ret := m.ctrl.Call(m, "UpdateSprites", id, spriteSheetPath, vttPath, spriteSheetCount, trickplayPath)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateSprites indicates an expected call of UpdateSprites.
-func (mr *MockSceneRepositoryMockRecorder) UpdateSprites(id, spriteSheetPath, vttPath, spriteSheetCount any) *gomock.Call {
+func (mr *MockSceneRepositoryMockRecorder) UpdateSprites(id, spriteSheetPath, vttPath, spriteSheetCount, trickplayPath any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSprites", reflect.TypeOf((*MockSceneRepository)(nil).UpdateSprites), id, spriteSheetPath, vttPath, spriteSheetCount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSprites", reflect.TypeOf((*MockSceneRepository)(nil).UpdateSprites), id, spriteSheetPath, vttPath, spriteSheetCount, trickplayPath)
 }
 
 // UpdateStoredPath mocks base method.
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: SceneTextSearchRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_scene_text_search_repository.go -package=mocks goonhub/internal/data SceneTextSearchRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSceneTextSearchRepository is a mock of SceneTextSearchRepository interface.
+type MockSceneTextSearchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSceneTextSearchRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSceneTextSearchRepositoryMockRecorder is the mock recorder for MockSceneTextSearchRepository.
+type MockSceneTextSearchRepositoryMockRecorder struct {
+	mock *MockSceneTextSearchRepository
+}
+
+// NewMockSceneTextSearchRepository creates a new mock instance.
+func NewMockSceneTextSearchRepository(ctrl *gomock.Controller) *MockSceneTextSearchRepository {
+	mock := &MockSceneTextSearchRepository{ctrl: ctrl}
+	mock.recorder = &MockSceneTextSearchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSceneTextSearchRepository) EXPECT() *MockSceneTextSearchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSceneTextSearchRepository) Search(q data.SceneTextQuery) ([]uint, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", q)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSceneTextSearchRepositoryMockRecorder) Search(q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSceneTextSearchRepository)(nil).Search), q)
+}
+
+// SearchAfter mocks base method.
+func (m *MockSceneTextSearchRepository) SearchAfter(q data.SceneTextQuery) ([]uint, *data.SceneSortKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAfter", q)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(*data.SceneSortKey)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchAfter indicates an expected call of SearchAfter.
+func (mr *MockSceneTextSearchRepositoryMockRecorder) SearchAfter(q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAfter", reflect.TypeOf((*MockSceneTextSearchRepository)(nil).SearchAfter), q)
+}
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: SearchReindexRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_search_reindex_repository.go -package=mocks goonhub/internal/data SearchReindexRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSearchReindexRepository is a mock of SearchReindexRepository interface.
+type MockSearchReindexRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchReindexRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSearchReindexRepositoryMockRecorder is the mock recorder for MockSearchReindexRepository.
+type MockSearchReindexRepositoryMockRecorder struct {
+	mock *MockSearchReindexRepository
+}
+
+// NewMockSearchReindexRepository creates a new mock instance.
+func NewMockSearchReindexRepository(ctrl *gomock.Controller) *MockSearchReindexRepository {
+	mock := &MockSearchReindexRepository{ctrl: ctrl}
+	mock.recorder = &MockSearchReindexRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchReindexRepository) EXPECT() *MockSearchReindexRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockSearchReindexRepository) Get() (*data.SearchReindexCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get")
+	ret0, _ := ret[0].(*data.SearchReindexCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSearchReindexRepositoryMockRecorder) Get() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSearchReindexRepository)(nil).Get))
+}
+
+// Save mocks base method.
+func (m *MockSearchReindexRepository) Save(checkpoint *data.SearchReindexCheckpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", checkpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockSearchReindexRepositoryMockRecorder) Save(checkpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockSearchReindexRepository)(nil).Save), checkpoint)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: StorageHealthRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_storage_health_repository.go -package=mocks goonhub/internal/data StorageHealthRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStorageHealthRepository is a mock of StorageHealthRepository interface.
+type MockStorageHealthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageHealthRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStorageHealthRepositoryMockRecorder is the mock recorder for MockStorageHealthRepository.
+type MockStorageHealthRepositoryMockRecorder struct {
+	mock *MockStorageHealthRepository
+}
+
+// NewMockStorageHealthRepository creates a new mock instance.
+func NewMockStorageHealthRepository(ctrl *gomock.Controller) *MockStorageHealthRepository {
+	mock := &MockStorageHealthRepository{ctrl: ctrl}
+	mock.recorder = &MockStorageHealthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageHealthRepository) EXPECT() *MockStorageHealthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockStorageHealthRepository) Create(sample *data.StoragePathHealthSample) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", sample)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockStorageHealthRepositoryMockRecorder) Create(sample any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStorageHealthRepository)(nil).Create), sample)
+}
+
+// DeleteOlderThan mocks base method.
+func (m *MockStorageHealthRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOlderThan", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOlderThan indicates an expected call of DeleteOlderThan.
+func (mr *MockStorageHealthRepositoryMockRecorder) DeleteOlderThan(before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOlderThan", reflect.TypeOf((*MockStorageHealthRepository)(nil).DeleteOlderThan), before)
+}
+
+// GetLatest mocks base method.
+func (m *MockStorageHealthRepository) GetLatest() ([]data.StoragePathHealthSample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatest")
+	ret0, _ := ret[0].([]data.StoragePathHealthSample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatest indicates an expected call of GetLatest.
+func (mr *MockStorageHealthRepositoryMockRecorder) GetLatest() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatest", reflect.TypeOf((*MockStorageHealthRepository)(nil).GetLatest))
+}
+
+// ListByStoragePath mocks base method.
+func (m *MockStorageHealthRepository) ListByStoragePath(storagePathID uint, since time.Time) ([]data.StoragePathHealthSample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStoragePath", storagePathID, since)
+	ret0, _ := ret[0].([]data.StoragePathHealthSample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStoragePath indicates an expected call of ListByStoragePath.
+func (mr *MockStorageHealthRepositoryMockRecorder) ListByStoragePath(storagePathID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStoragePath", reflect.TypeOf((*MockStorageHealthRepository)(nil).ListByStoragePath), storagePathID, since)
+}
@@ -97,6 +97,21 @@ func (mr *MockStudioRepositoryMockRecorder) GetByID(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockStudioRepository)(nil).GetByID), id)
 }
 
+// GetByIDs mocks base method.
+func (m *MockStudioRepository) GetByIDs(ids []uint) ([]data.Studio, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ids)
+	ret0, _ := ret[0].([]data.Studio)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockStudioRepositoryMockRecorder) GetByIDs(ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockStudioRepository)(nil).GetByIDs), ids)
+}
+
 // GetByName mocks base method.
 func (m *MockStudioRepository) GetByName(name string) (*data.Studio, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +142,36 @@ func (mr *MockStudioRepositoryMockRecorder) GetByUUID(uuid any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUUID", reflect.TypeOf((*MockStudioRepository)(nil).GetByUUID), uuid)
 }
 
+// GetChildren mocks base method.
+func (m *MockStudioRepository) GetChildren(studioID uint) ([]data.StudioWithCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildren", studioID)
+	ret0, _ := ret[0].([]data.StudioWithCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildren indicates an expected call of GetChildren.
+func (mr *MockStudioRepositoryMockRecorder) GetChildren(studioID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildren", reflect.TypeOf((*MockStudioRepository)(nil).GetChildren), studioID)
+}
+
+// GetDescendantIDs mocks base method.
+func (m *MockStudioRepository) GetDescendantIDs(studioID uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDescendantIDs", studioID)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDescendantIDs indicates an expected call of GetDescendantIDs.
+func (mr *MockStudioRepositoryMockRecorder) GetDescendantIDs(studioID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescendantIDs", reflect.TypeOf((*MockStudioRepository)(nil).GetDescendantIDs), studioID)
+}
+
 // GetSceneCount mocks base method.
 func (m *MockStudioRepository) GetSceneCount(studioID uint) (int64, error) {
 	m.ctrl.T.Helper()
@@ -157,6 +202,22 @@ func (mr *MockStudioRepositoryMockRecorder) GetSceneStudio(sceneID any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSceneStudio", reflect.TypeOf((*MockStudioRepository)(nil).GetSceneStudio), sceneID)
 }
 
+// GetScenesByStudioIDs mocks base method.
+func (m *MockStudioRepository) GetScenesByStudioIDs(studioIDs []uint, restriction *data.ContentRestriction, page, limit int) ([]data.Scene, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScenesByStudioIDs", studioIDs, restriction, page, limit)
+	ret0, _ := ret[0].([]data.Scene)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetScenesByStudioIDs indicates an expected call of GetScenesByStudioIDs.
+func (mr *MockStudioRepositoryMockRecorder) GetScenesByStudioIDs(studioIDs, restriction, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScenesByStudioIDs", reflect.TypeOf((*MockStudioRepository)(nil).GetScenesByStudioIDs), studioIDs, restriction, page, limit)
+}
+
 // GetStudioSceneIDs mocks base method.
 func (m *MockStudioRepository) GetStudioSceneIDs(studioID uint, limit int) ([]uint, error) {
 	m.ctrl.T.Helper()
@@ -173,9 +234,9 @@ func (mr *MockStudioRepositoryMockRecorder) GetStudioSceneIDs(studioID, limit an
 }
 
 // GetStudioScenes mocks base method.
-func (m *MockStudioRepository) GetStudioScenes(studioID uint, page, limit int) ([]data.Scene, int64, error) {
+func (m *MockStudioRepository) GetStudioScenes(studioID uint, restriction *data.ContentRestriction, page, limit int) ([]data.Scene, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStudioScenes", studioID, page, limit)
+	ret := m.ctrl.Call(m, "GetStudioScenes", studioID, restriction, page, limit)
 	ret0, _ := ret[0].([]data.Scene)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -183,9 +244,9 @@ func (m *MockStudioRepository) GetStudioScenes(studioID uint, page, limit int) (
 }
 
 // GetStudioScenes indicates an expected call of GetStudioScenes.
-func (mr *MockStudioRepositoryMockRecorder) GetStudioScenes(studioID, page, limit any) *gomock.Call {
+func (mr *MockStudioRepositoryMockRecorder) GetStudioScenes(studioID, restriction, page, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudioScenes", reflect.TypeOf((*MockStudioRepository)(nil).GetStudioScenes), studioID, page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudioScenes", reflect.TypeOf((*MockStudioRepository)(nil).GetStudioScenes), studioID, restriction, page, limit)
 }
 
 // List mocks base method.
@@ -12,6 +12,7 @@ package mocks
 import (
 	data "goonhub/internal/data"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -110,6 +111,36 @@ func (mr *MockTagRepositoryMockRecorder) Delete(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagRepository)(nil).Delete), id)
 }
 
+// DeleteUnused mocks base method.
+func (m *MockTagRepository) DeleteUnused() ([]data.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnused")
+	ret0, _ := ret[0].([]data.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUnused indicates an expected call of DeleteUnused.
+func (mr *MockTagRepositoryMockRecorder) DeleteUnused() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnused", reflect.TypeOf((*MockTagRepository)(nil).DeleteUnused))
+}
+
+// GetAllSceneIDs mocks base method.
+func (m *MockTagRepository) GetAllSceneIDs(tagIDs []uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllSceneIDs", tagIDs)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllSceneIDs indicates an expected call of GetAllSceneIDs.
+func (mr *MockTagRepositoryMockRecorder) GetAllSceneIDs(tagIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSceneIDs", reflect.TypeOf((*MockTagRepository)(nil).GetAllSceneIDs), tagIDs)
+}
+
 // GetByID mocks base method.
 func (m *MockTagRepository) GetByID(id uint) (*data.Tag, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +201,36 @@ func (mr *MockTagRepositoryMockRecorder) GetIDsByNames(names any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIDsByNames", reflect.TypeOf((*MockTagRepository)(nil).GetIDsByNames), names)
 }
 
+// GetMonthlyUsage mocks base method.
+func (m *MockTagRepository) GetMonthlyUsage(tagIDs []uint, userID uint, since time.Time) ([]data.UsageCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlyUsage", tagIDs, userID, since)
+	ret0, _ := ret[0].([]data.UsageCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlyUsage indicates an expected call of GetMonthlyUsage.
+func (mr *MockTagRepositoryMockRecorder) GetMonthlyUsage(tagIDs, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyUsage", reflect.TypeOf((*MockTagRepository)(nil).GetMonthlyUsage), tagIDs, userID, since)
+}
+
+// GetMostUsedIDs mocks base method.
+func (m *MockTagRepository) GetMostUsedIDs(userID uint, since time.Time, limit int) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMostUsedIDs", userID, since, limit)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMostUsedIDs indicates an expected call of GetMostUsedIDs.
+func (mr *MockTagRepositoryMockRecorder) GetMostUsedIDs(userID, since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMostUsedIDs", reflect.TypeOf((*MockTagRepository)(nil).GetMostUsedIDs), userID, since, limit)
+}
+
 // GetSceneIDsByTag mocks base method.
 func (m *MockTagRepository) GetSceneIDsByTag(tagID uint, limit int) ([]uint, error) {
 	m.ctrl.T.Helper()
@@ -245,6 +306,20 @@ func (mr *MockTagRepositoryMockRecorder) ListWithCounts() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWithCounts", reflect.TypeOf((*MockTagRepository)(nil).ListWithCounts))
 }
 
+// Merge mocks base method.
+func (m *MockTagRepository) Merge(target *data.Tag, sourceIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", target, sourceIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockTagRepositoryMockRecorder) Merge(target, sourceIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockTagRepository)(nil).Merge), target, sourceIDs)
+}
+
 // SetSceneTags mocks base method.
 func (m *MockTagRepository) SetSceneTags(sceneID uint, tagIDs []uint) error {
 	m.ctrl.T.Helper()
@@ -258,3 +333,17 @@ func (mr *MockTagRepositoryMockRecorder) SetSceneTags(sceneID, tagIDs any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSceneTags", reflect.TypeOf((*MockTagRepository)(nil).SetSceneTags), sceneID, tagIDs)
 }
+
+// Update mocks base method.
+func (m *MockTagRepository) Update(tag *data.Tag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagRepositoryMockRecorder) Update(tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTagRepository)(nil).Update), tag)
+}
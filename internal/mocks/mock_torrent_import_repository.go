@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: TorrentImportRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_torrent_import_repository.go -package=mocks goonhub/internal/data TorrentImportRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTorrentImportRepository is a mock of TorrentImportRepository interface.
+type MockTorrentImportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTorrentImportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTorrentImportRepositoryMockRecorder is the mock recorder for MockTorrentImportRepository.
+type MockTorrentImportRepositoryMockRecorder struct {
+	mock *MockTorrentImportRepository
+}
+
+// NewMockTorrentImportRepository creates a new mock instance.
+func NewMockTorrentImportRepository(ctrl *gomock.Controller) *MockTorrentImportRepository {
+	mock := &MockTorrentImportRepository{ctrl: ctrl}
+	mock.recorder = &MockTorrentImportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTorrentImportRepository) EXPECT() *MockTorrentImportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTorrentImportRepository) Create(item *data.TorrentImport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTorrentImportRepositoryMockRecorder) Create(item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTorrentImportRepository)(nil).Create), item)
+}
+
+// Delete mocks base method.
+func (m *MockTorrentImportRepository) Delete(id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTorrentImportRepositoryMockRecorder) Delete(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTorrentImportRepository)(nil).Delete), id)
+}
+
+// GetByID mocks base method.
+func (m *MockTorrentImportRepository) GetByID(id uint) (*data.TorrentImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*data.TorrentImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTorrentImportRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTorrentImportRepository)(nil).GetByID), id)
+}
+
+// List mocks base method.
+func (m *MockTorrentImportRepository) List(page, limit int, sceneID *uint) ([]data.TorrentImport, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", page, limit, sceneID)
+	ret0, _ := ret[0].([]data.TorrentImport)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockTorrentImportRepositoryMockRecorder) List(page, limit, sceneID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTorrentImportRepository)(nil).List), page, limit, sceneID)
+}
+
+// ListByTorrentHashes mocks base method.
+func (m *MockTorrentImportRepository) ListByTorrentHashes(hashes []string) ([]data.TorrentImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTorrentHashes", hashes)
+	ret0, _ := ret[0].([]data.TorrentImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTorrentHashes indicates an expected call of ListByTorrentHashes.
+func (mr *MockTorrentImportRepositoryMockRecorder) ListByTorrentHashes(hashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTorrentHashes", reflect.TypeOf((*MockTorrentImportRepository)(nil).ListByTorrentHashes), hashes)
+}
@@ -172,6 +172,20 @@ func (mr *MockUserRepositoryMockRecorder) UpdatePassword(userID, hashedPassword
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), userID, hashedPassword)
 }
 
+// UpdatePrivacyLock mocks base method.
+func (m *MockUserRepository) UpdatePrivacyLock(userID uint, pinHash string, lockMinutes int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePrivacyLock", userID, pinHash, lockMinutes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePrivacyLock indicates an expected call of UpdatePrivacyLock.
+func (mr *MockUserRepositoryMockRecorder) UpdatePrivacyLock(userID, pinHash, lockMinutes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePrivacyLock", reflect.TypeOf((*MockUserRepository)(nil).UpdatePrivacyLock), userID, pinHash, lockMinutes)
+}
+
 // UpdateRole mocks base method.
 func (m *MockUserRepository) UpdateRole(userID uint, role string) error {
 	m.ctrl.T.Helper()
@@ -186,6 +200,20 @@ func (mr *MockUserRepositoryMockRecorder) UpdateRole(userID, role any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUserRepository)(nil).UpdateRole), userID, role)
 }
 
+// UpdateTwoFactor mocks base method.
+func (m *MockUserRepository) UpdateTwoFactor(userID uint, secret string, enabled bool, recoveryCodeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTwoFactor", userID, secret, enabled, recoveryCodeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTwoFactor indicates an expected call of UpdateTwoFactor.
+func (mr *MockUserRepositoryMockRecorder) UpdateTwoFactor(userID, secret, enabled, recoveryCodeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTwoFactor", reflect.TypeOf((*MockUserRepository)(nil).UpdateTwoFactor), userID, secret, enabled, recoveryCodeHashes)
+}
+
 // UpdateUsername mocks base method.
 func (m *MockUserRepository) UpdateUsername(userID uint, newUsername string) error {
 	m.ctrl.T.Helper()
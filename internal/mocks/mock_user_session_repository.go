@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: goonhub/internal/data (interfaces: UserSessionRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=internal/mocks/mock_user_session_repository.go -package=mocks goonhub/internal/data UserSessionRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	data "goonhub/internal/data"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserSessionRepository is a mock of UserSessionRepository interface.
+type MockUserSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserSessionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUserSessionRepositoryMockRecorder is the mock recorder for MockUserSessionRepository.
+type MockUserSessionRepositoryMockRecorder struct {
+	mock *MockUserSessionRepository
+}
+
+// NewMockUserSessionRepository creates a new mock instance.
+func NewMockUserSessionRepository(ctrl *gomock.Controller) *MockUserSessionRepository {
+	mock := &MockUserSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockUserSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserSessionRepository) EXPECT() *MockUserSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserSessionRepository) Create(session *data.UserSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserSessionRepositoryMockRecorder) Create(session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserSessionRepository)(nil).Create), session)
+}
+
+// DeleteByTokenHash mocks base method.
+func (m *MockUserSessionRepository) DeleteByTokenHash(tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByTokenHash", tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByTokenHash indicates an expected call of DeleteByTokenHash.
+func (mr *MockUserSessionRepositoryMockRecorder) DeleteByTokenHash(tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByTokenHash", reflect.TypeOf((*MockUserSessionRepository)(nil).DeleteByTokenHash), tokenHash)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockUserSessionRepository) DeleteExpired(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockUserSessionRepositoryMockRecorder) DeleteExpired(before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockUserSessionRepository)(nil).DeleteExpired), before)
+}
+
+// GetByID mocks base method.
+func (m *MockUserSessionRepository) GetByID(id uint) (*data.UserSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*data.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserSessionRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserSessionRepository)(nil).GetByID), id)
+}
+
+// ListActiveByUser mocks base method.
+func (m *MockUserSessionRepository) ListActiveByUser(userID uint, now time.Time) ([]data.UserSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveByUser", userID, now)
+	ret0, _ := ret[0].([]data.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveByUser indicates an expected call of ListActiveByUser.
+func (mr *MockUserSessionRepositoryMockRecorder) ListActiveByUser(userID, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveByUser", reflect.TypeOf((*MockUserSessionRepository)(nil).ListActiveByUser), userID, now)
+}
+
+// TouchLastSeen mocks base method.
+func (m *MockUserSessionRepository) TouchLastSeen(tokenHash string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastSeen", tokenHash, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastSeen indicates an expected call of TouchLastSeen.
+func (mr *MockUserSessionRepositoryMockRecorder) TouchLastSeen(tokenHash, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastSeen", reflect.TypeOf((*MockUserSessionRepository)(nil).TouchLastSeen), tokenHash, at)
+}
@@ -0,0 +1,288 @@
+package streaming
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"goonhub/internal/data"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// throttleChunkSize is the largest write that waits on the token buckets at once
+	throttleChunkSize = 32 * 1024
+	// throughputWindow is how many one-second buckets current throughput is averaged over
+	throughputWindow = 5
+)
+
+// BandwidthLimits caps streaming throughput in bytes per second (0 = unlimited).
+type BandwidthLimits struct {
+	PerStream int64 `json:"per_stream"`
+	PerUser   int64 `json:"per_user"`
+	Global    int64 `json:"global"`
+}
+
+// BandwidthLimitsFromSettings converts the kbps limits stored in app settings.
+func BandwidthLimitsFromSettings(settings *data.AppSettingsRecord) BandwidthLimits {
+	return BandwidthLimits{
+		PerStream: kbpsToBytes(settings.StreamRateLimitKbps),
+		PerUser:   kbpsToBytes(settings.UserStreamRateLimitKbps),
+		Global:    kbpsToBytes(settings.GlobalStreamRateLimitKbps),
+	}
+}
+
+func kbpsToBytes(kbps int) int64 {
+	if kbps <= 0 {
+		return 0
+	}
+	return int64(kbps) * 1000 / 8
+}
+
+// ViewerThroughput is the current streaming rate of one viewer.
+type ViewerThroughput struct {
+	Viewer      string `json:"viewer"`
+	Streams     int    `json:"streams"`
+	BytesPerSec int64  `json:"bytes_per_sec"`
+}
+
+// BandwidthStats reports the configured limits and current throughput.
+type BandwidthStats struct {
+	Limits      BandwidthLimits    `json:"limits"`
+	BytesPerSec int64              `json:"bytes_per_sec"`
+	Viewers     []ViewerThroughput `json:"viewers"`
+}
+
+// BandwidthThrottle rate limits streamed bytes with token buckets per stream,
+// per viewer (user, or IP for anonymous requests) and globally. Limits can be
+// changed while streams are running.
+type BandwidthThrottle struct {
+	mu      sync.Mutex
+	limits  BandwidthLimits
+	global  *rate.Limiter
+	meter   *throughputMeter
+	viewers map[string]*viewerBandwidth
+}
+
+type viewerBandwidth struct {
+	limiter *rate.Limiter
+	meter   *throughputMeter
+	streams map[*ThrottledStream]struct{}
+}
+
+// NewBandwidthThrottle creates a throttle with no limits.
+func NewBandwidthThrottle() *BandwidthThrottle {
+	return &BandwidthThrottle{
+		global:  newBandwidthLimiter(0),
+		meter:   &throughputMeter{},
+		viewers: make(map[string]*viewerBandwidth),
+	}
+}
+
+// SetLimits applies new limits to the throttle and all running streams.
+func (t *BandwidthThrottle) SetLimits(limits BandwidthLimits) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.limits = limits
+	setBandwidthLimit(t.global, limits.Global)
+	for _, v := range t.viewers {
+		setBandwidthLimit(v.limiter, limits.PerUser)
+		for s := range v.streams {
+			setBandwidthLimit(s.limiter, limits.PerStream)
+		}
+	}
+}
+
+// Limits returns the current limits.
+func (t *BandwidthThrottle) Limits() BandwidthLimits {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limits
+}
+
+// Open starts a throttled stream for viewer. The stream must be closed when the
+// response is done.
+func (t *BandwidthThrottle) Open(ctx context.Context, viewer string) *ThrottledStream {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.viewers[viewer]
+	if !ok {
+		v = &viewerBandwidth{
+			limiter: newBandwidthLimiter(t.limits.PerUser),
+			meter:   &throughputMeter{},
+			streams: make(map[*ThrottledStream]struct{}),
+		}
+		t.viewers[viewer] = v
+	}
+
+	s := &ThrottledStream{
+		ctx:      ctx,
+		throttle: t,
+		viewer:   viewer,
+		user:     v,
+		limiter:  newBandwidthLimiter(t.limits.PerStream),
+	}
+	v.streams[s] = struct{}{}
+	return s
+}
+
+func (t *BandwidthThrottle) close(s *ThrottledStream) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	v, ok := t.viewers[s.viewer]
+	if !ok {
+		return
+	}
+	delete(v.streams, s)
+	if len(v.streams) == 0 {
+		delete(t.viewers, s.viewer)
+	}
+}
+
+// Stats returns the limits, the global throughput and the throughput of every
+// viewer with an open stream, fastest first.
+func (t *BandwidthThrottle) Stats() BandwidthStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	stats := BandwidthStats{
+		Limits:      t.limits,
+		BytesPerSec: t.meter.Rate(now),
+		Viewers:     make([]ViewerThroughput, 0, len(t.viewers)),
+	}
+	for viewer, v := range t.viewers {
+		stats.Viewers = append(stats.Viewers, ViewerThroughput{
+			Viewer:      viewer,
+			Streams:     len(v.streams),
+			BytesPerSec: v.meter.Rate(now),
+		})
+	}
+	sort.Slice(stats.Viewers, func(i, j int) bool {
+		return stats.Viewers[i].BytesPerSec > stats.Viewers[j].BytesPerSec
+	})
+	return stats
+}
+
+// ThrottledStream is one response being rate limited.
+type ThrottledStream struct {
+	ctx      context.Context
+	throttle *BandwidthThrottle
+	viewer   string
+	user     *viewerBandwidth
+	limiter  *rate.Limiter
+}
+
+// Wrap returns a ResponseWriter whose body writes are throttled by this stream.
+func (s *ThrottledStream) Wrap(w http.ResponseWriter) http.ResponseWriter {
+	return &throttledResponseWriter{ResponseWriter: w, stream: s}
+}
+
+// Close releases the stream's viewer slot.
+func (s *ThrottledStream) Close() {
+	s.throttle.close(s)
+}
+
+// wait blocks until n bytes may be sent under the stream, viewer and global limits.
+func (s *ThrottledStream) wait(n int) error {
+	if err := s.limiter.WaitN(s.ctx, n); err != nil {
+		return err
+	}
+	if err := s.user.limiter.WaitN(s.ctx, n); err != nil {
+		return err
+	}
+	return s.throttle.global.WaitN(s.ctx, n)
+}
+
+func (s *ThrottledStream) record(n int) {
+	now := time.Now()
+	s.user.meter.Add(now, n)
+	s.throttle.meter.Add(now, n)
+}
+
+type throttledResponseWriter struct {
+	http.ResponseWriter
+	stream *ThrottledStream
+}
+
+func (w *throttledResponseWriter) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		chunk := p[written:min(written+throttleChunkSize, len(p))]
+		if err := w.stream.wait(len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := w.ResponseWriter.Write(chunk)
+		written += n
+		w.stream.record(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Flush passes through so streamed transcodes still reach the client promptly.
+func (w *throttledResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// newBandwidthLimiter creates a token bucket for bytesPerSec (0 = unlimited).
+func newBandwidthLimiter(bytesPerSec int64) *rate.Limiter {
+	l := rate.NewLimiter(rate.Inf, throttleChunkSize)
+	setBandwidthLimit(l, bytesPerSec)
+	return l
+}
+
+func setBandwidthLimit(l *rate.Limiter, bytesPerSec int64) {
+	if bytesPerSec <= 0 {
+		l.SetLimit(rate.Inf)
+		return
+	}
+	// A quarter second of burst smooths playback; it must fit at least one chunk
+	l.SetBurst(int(max(bytesPerSec/4, throttleChunkSize)))
+	l.SetLimit(rate.Limit(bytesPerSec))
+}
+
+// throughputMeter averages bytes sent over the last throughputWindow whole seconds.
+type throughputMeter struct {
+	mu      sync.Mutex
+	buckets [throughputWindow + 1]int64
+	seconds [throughputWindow + 1]int64
+}
+
+func (m *throughputMeter) Add(now time.Time, n int) {
+	sec := now.Unix()
+	i := sec % int64(len(m.buckets))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.seconds[i] != sec {
+		m.seconds[i] = sec
+		m.buckets[i] = 0
+	}
+	m.buckets[i] += int64(n)
+}
+
+// Rate returns bytes per second over the last throughputWindow complete seconds.
+func (m *throughputMeter) Rate(now time.Time) int64 {
+	current := now.Unix()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var total int64
+	for i, sec := range m.seconds {
+		if sec < current && sec >= current-throughputWindow {
+			total += m.buckets[i]
+		}
+	}
+	return total / throughputWindow
+}
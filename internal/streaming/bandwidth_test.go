@@ -0,0 +1,138 @@
+package streaming
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"goonhub/internal/data"
+)
+
+func TestBandwidthLimitsFromSettings(t *testing.T) {
+	limits := BandwidthLimitsFromSettings(&data.AppSettingsRecord{
+		StreamRateLimitKbps:       8000,
+		UserStreamRateLimitKbps:   0,
+		GlobalStreamRateLimitKbps: -5,
+	})
+
+	if limits.PerStream != 1_000_000 {
+		t.Fatalf("expected 8000 kbps to be 1000000 bytes/s, got %d", limits.PerStream)
+	}
+	if limits.PerUser != 0 || limits.Global != 0 {
+		t.Fatalf("expected zero and negative limits to mean unlimited, got %+v", limits)
+	}
+}
+
+func TestThrottledStream_Unlimited(t *testing.T) {
+	throttle := NewBandwidthThrottle()
+	stream := throttle.Open(context.Background(), "user:1")
+	defer stream.Close()
+
+	rec := httptest.NewRecorder()
+	payload := make([]byte, 4*1024*1024)
+
+	start := time.Now()
+	n, err := stream.Wrap(rec).Write(payload)
+	if err != nil || n != len(payload) {
+		t.Fatalf("expected full write, got n=%d err=%v", n, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected unlimited write to be fast, took %v", elapsed)
+	}
+	if rec.Body.Len() != len(payload) {
+		t.Fatalf("expected %d bytes written, got %d", len(payload), rec.Body.Len())
+	}
+}
+
+func TestThrottledStream_PerStreamLimit(t *testing.T) {
+	throttle := NewBandwidthThrottle()
+	throttle.SetLimits(BandwidthLimits{PerStream: 1024 * 1024})
+	stream := throttle.Open(context.Background(), "user:1")
+	defer stream.Close()
+
+	// The first 256KB is burst; the remaining 256KB takes ~250ms at 1MB/s
+	start := time.Now()
+	if _, err := stream.Wrap(httptest.NewRecorder()).Write(make([]byte, 512*1024)); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected write to be throttled, took %v", elapsed)
+	}
+}
+
+func TestThrottledStream_CancelledContext(t *testing.T) {
+	throttle := NewBandwidthThrottle()
+	throttle.SetLimits(BandwidthLimits{Global: throttleChunkSize})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := throttle.Open(ctx, "ip:10.0.0.1")
+	defer stream.Close()
+	cancel()
+
+	n, err := stream.Wrap(httptest.NewRecorder()).Write(make([]byte, 4*throttleChunkSize))
+	if err == nil {
+		t.Fatal("expected error once the client has gone away")
+	}
+	if n >= 4*throttleChunkSize {
+		t.Fatalf("expected a partial write, got %d bytes", n)
+	}
+}
+
+func TestBandwidthThrottle_SetLimitsUpdatesRunningStreams(t *testing.T) {
+	throttle := NewBandwidthThrottle()
+	stream := throttle.Open(context.Background(), "user:1")
+	defer stream.Close()
+
+	throttle.SetLimits(BandwidthLimits{PerStream: 2 * 1024 * 1024, PerUser: 4 * 1024 * 1024})
+
+	if got := int64(stream.limiter.Limit()); got != 2*1024*1024 {
+		t.Fatalf("expected running stream limit to be updated, got %d", got)
+	}
+	if got := int64(stream.user.limiter.Limit()); got != 4*1024*1024 {
+		t.Fatalf("expected running viewer limit to be updated, got %d", got)
+	}
+}
+
+func TestBandwidthThrottle_StatsTracksViewers(t *testing.T) {
+	throttle := NewBandwidthThrottle()
+	a := throttle.Open(context.Background(), "user:1")
+	b := throttle.Open(context.Background(), "user:1")
+	c := throttle.Open(context.Background(), "ip:10.0.0.1")
+
+	stats := throttle.Stats()
+	if len(stats.Viewers) != 2 {
+		t.Fatalf("expected 2 viewers, got %+v", stats.Viewers)
+	}
+
+	a.Close()
+	b.Close()
+	stats = throttle.Stats()
+	if len(stats.Viewers) != 1 || stats.Viewers[0].Viewer != "ip:10.0.0.1" {
+		t.Fatalf("expected only the IP viewer to remain, got %+v", stats.Viewers)
+	}
+
+	c.Close()
+	if stats = throttle.Stats(); len(stats.Viewers) != 0 {
+		t.Fatalf("expected no viewers after all streams closed, got %+v", stats.Viewers)
+	}
+}
+
+func TestThroughputMeter_Rate(t *testing.T) {
+	m := &throughputMeter{}
+	base := time.Unix(1_000_000, 0)
+
+	for i := range throughputWindow {
+		m.Add(base.Add(time.Duration(i)*time.Second), 1000)
+	}
+	// Bytes in the current (incomplete) second are not counted yet
+	now := base.Add(throughputWindow * time.Second)
+	m.Add(now, 50_000)
+
+	if got := m.Rate(now); got != 1000 {
+		t.Fatalf("expected 1000 bytes/s, got %d", got)
+	}
+	if got := m.Rate(now.Add(time.Minute)); got != 0 {
+		t.Fatalf("expected idle meter to report 0, got %d", got)
+	}
+}
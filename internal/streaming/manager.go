@@ -1,6 +1,7 @@
 package streaming
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -10,24 +11,40 @@ import (
 	"go.uber.org/zap"
 )
 
-// Manager coordinates all streaming components (limiter, buffer pool, path cache).
+// Manager coordinates all streaming components (limiter, bandwidth throttle,
+// buffer pool, path cache).
 // It provides a unified interface for the streaming handler.
 type Manager struct {
 	limiter    *StreamLimiter
+	bandwidth  *BandwidthThrottle
 	bufferPool *BufferPool
 	pathCache  *PathCache
 	sceneRepo  data.SceneRepository
 	logger     *zap.Logger
+
+	transcodeEnabled bool
+	transcodePreset  string
+	transcodeSlots   chan struct{}
+	embedChapters    bool
 }
 
 // NewManager creates a new streaming manager with all components initialized.
 func NewManager(cfg *config.StreamingConfig, sceneRepo data.SceneRepository, logger *zap.Logger) *Manager {
+	maxTranscodes := cfg.MaxTranscodes
+	if maxTranscodes <= 0 {
+		maxTranscodes = 1
+	}
 	return &Manager{
-		limiter:    NewStreamLimiter(cfg.MaxGlobalStreams, cfg.MaxStreamsPerIP),
-		bufferPool: NewBufferPool(cfg.BufferSize),
-		pathCache:  NewPathCache(cfg.PathCacheTTL, cfg.PathCacheMaxSize),
-		sceneRepo:  sceneRepo,
-		logger:     logger,
+		limiter:          NewStreamLimiter(cfg.MaxGlobalStreams, cfg.MaxStreamsPerIP),
+		bandwidth:        NewBandwidthThrottle(),
+		bufferPool:       NewBufferPool(cfg.BufferSize),
+		pathCache:        NewPathCache(cfg.PathCacheTTL, cfg.PathCacheMaxSize),
+		sceneRepo:        sceneRepo,
+		logger:           logger,
+		transcodeEnabled: cfg.TranscodeEnabled,
+		transcodePreset:  cfg.TranscodePreset,
+		transcodeSlots:   make(chan struct{}, maxTranscodes),
+		embedChapters:    cfg.EmbedChapters,
 	}
 }
 
@@ -36,6 +53,32 @@ func (m *Manager) Limiter() *StreamLimiter {
 	return m.limiter
 }
 
+// Bandwidth returns the throttle that rate limits streamed bytes.
+func (m *Manager) Bandwidth() *BandwidthThrottle {
+	return m.bandwidth
+}
+
+// SetBandwidthLimits applies new streaming bandwidth limits, including to
+// streams that are already running.
+func (m *Manager) SetBandwidthLimits(limits BandwidthLimits) {
+	m.bandwidth.SetLimits(limits)
+	m.logger.Info("Streaming bandwidth limits updated",
+		zap.Int64("per_stream_bytes_per_sec", limits.PerStream),
+		zap.Int64("per_user_bytes_per_sec", limits.PerUser),
+		zap.Int64("global_bytes_per_sec", limits.Global),
+	)
+}
+
+// OpenStream starts throttling a response for a viewer. userID identifies the
+// viewer when authenticated (0 = anonymous, in which case the client IP is used).
+func (m *Manager) OpenStream(ctx context.Context, userID uint, clientIP string) *ThrottledStream {
+	viewer := "ip:" + clientIP
+	if userID != 0 {
+		viewer = fmt.Sprintf("user:%d", userID)
+	}
+	return m.bandwidth.Open(ctx, viewer)
+}
+
 // BufferPool returns the buffer pool for efficient streaming.
 func (m *Manager) BufferPool() *BufferPool {
 	return m.bufferPool
@@ -79,6 +122,7 @@ func (m *Manager) InvalidateScenePath(sceneID uint) {
 func (m *Manager) Stats() ManagerStats {
 	return ManagerStats{
 		Stream:    m.limiter.Stats(),
+		Bandwidth: m.bandwidth.Stats(),
 		CacheSize: m.pathCache.Size(),
 	}
 }
@@ -95,8 +139,9 @@ func (m *Manager) Stop() {
 
 // ManagerStats combines statistics from all streaming components.
 type ManagerStats struct {
-	Stream    StreamStats `json:"stream"`
-	CacheSize int         `json:"cache_size"`
+	Stream    StreamStats    `json:"stream"`
+	Bandwidth BandwidthStats `json:"bandwidth"`
+	CacheSize int            `json:"cache_size"`
 }
 
 // DefaultConfig returns a default streaming configuration.
@@ -107,5 +152,8 @@ func DefaultConfig() *config.StreamingConfig {
 		BufferSize:       262144, // 256KB
 		PathCacheTTL:     5 * time.Minute,
 		PathCacheMaxSize: 10000,
+		TranscodeEnabled: true,
+		TranscodePreset:  "veryfast",
+		MaxTranscodes:    2,
 	}
 }
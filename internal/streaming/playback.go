@@ -0,0 +1,254 @@
+package streaming
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"goonhub/internal/data"
+	"goonhub/pkg/ffmpeg"
+
+	"go.uber.org/zap"
+)
+
+// Playback modes returned by DecidePlayback.
+const (
+	PlaybackDirect    = "direct"
+	PlaybackTranscode = "transcode"
+)
+
+// Transcode output format. Every browser can play H.264/AAC in MP4.
+const (
+	transcodeVideoCodec = "h264"
+	transcodeAudioCodec = "aac"
+)
+
+// ClientCapabilities describes what the player can decode natively. Values use
+// ffprobe names ("h264", "hevc", "aac") or common browser aliases ("avc1", "mp4a").
+// An empty list falls back to the formats every browser supports.
+type ClientCapabilities struct {
+	Containers  []string `json:"containers"`
+	VideoCodecs []string `json:"video_codecs"`
+	AudioCodecs []string `json:"audio_codecs"`
+}
+
+// PlaybackDecision tells the client which URL to play a scene from.
+type PlaybackDecision struct {
+	Mode       string   `json:"mode"`
+	StreamURL  string   `json:"stream_url"`
+	Container  string   `json:"container"`
+	VideoCodec string   `json:"video_codec"`
+	AudioCodec string   `json:"audio_codec"`
+	Reasons    []string `json:"reasons"` // why direct play was rejected (empty for direct)
+
+	// Which streams the transcode re-encodes; copied streams cost almost no CPU
+	TranscodeVideo bool `json:"transcode_video"`
+	TranscodeAudio bool `json:"transcode_audio"`
+}
+
+var (
+	defaultContainers  = []string{"mp4"}
+	defaultVideoCodecs = []string{"h264"}
+	defaultAudioCodecs = []string{"aac", "mp3"}
+)
+
+// codecAliases maps browser/MIME codec names to ffprobe codec names
+var codecAliases = map[string]string{
+	"avc":   "h264",
+	"avc1":  "h264",
+	"h.264": "h264",
+	"h265":  "hevc",
+	"h.265": "hevc",
+	"hvc1":  "hevc",
+	"hev1":  "hevc",
+	"vp09":  "vp9",
+	"av01":  "av1",
+	"mp4a":  "aac",
+}
+
+// containerAliases maps file extensions and MIME subtypes to container names
+var containerAliases = map[string]string{
+	"m4v":       "mp4",
+	"matroska":  "mkv",
+	"quicktime": "mov",
+	"mpegts":    "ts",
+	"m2ts":      "ts",
+}
+
+// DecidePlayback picks direct play when the client supports the scene's container
+// and codecs, otherwise on-the-fly transcode to H.264/AAC MP4.
+func (m *Manager) DecidePlayback(sceneID uint, caps ClientCapabilities) (*PlaybackDecision, error) {
+	scene, err := m.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scene %d: %w", sceneID, err)
+	}
+	if scene == nil {
+		return nil, fmt.Errorf("scene %d not found", sceneID)
+	}
+
+	decision := decidePlayback(scene, caps)
+	if decision.Mode == PlaybackTranscode && !m.transcodeEnabled {
+		// Nothing better to offer; let the client try the original file
+		decision.Mode = PlaybackDirect
+		decision.StreamURL = directStreamURL(sceneID)
+		decision.TranscodeVideo = false
+		decision.TranscodeAudio = false
+		decision.Reasons = append(decision.Reasons, "transcoding disabled")
+	}
+	return decision, nil
+}
+
+// TranscodeOptions returns the stored path of a scene and the ffmpeg options for
+// transcoding it from startSeconds, copying streams that are already H.264/AAC.
+func (m *Manager) TranscodeOptions(sceneID uint, startSeconds float64) (string, ffmpeg.TranscodeOptions, error) {
+	if !m.transcodeEnabled {
+		return "", ffmpeg.TranscodeOptions{}, fmt.Errorf("transcoding is disabled")
+	}
+
+	scene, err := m.sceneRepo.GetByID(sceneID)
+	if err != nil {
+		return "", ffmpeg.TranscodeOptions{}, fmt.Errorf("failed to get scene %d: %w", sceneID, err)
+	}
+	if scene == nil {
+		return "", ffmpeg.TranscodeOptions{}, fmt.Errorf("scene %d not found", sceneID)
+	}
+
+	return scene.StoredPath, ffmpeg.TranscodeOptions{
+		StartSeconds: startSeconds,
+		CopyVideo:    normalizeCodec(scene.VideoCodec) == transcodeVideoCodec,
+		CopyAudio:    normalizeCodec(scene.AudioCodec) == transcodeAudioCodec,
+		Preset:       m.transcodePreset,
+	}, nil
+}
+
+// Transcode streams the scene to w with ffmpeg until it finishes or ctx is
+// cancelled. Failures are logged; the response has already started by then.
+func (m *Manager) Transcode(ctx context.Context, sceneID uint, filePath string, opts ffmpeg.TranscodeOptions, w io.Writer) {
+	if err := ffmpeg.TranscodeToWriter(ctx, filePath, opts, w); err != nil && ctx.Err() == nil {
+		m.logger.Warn("On-the-fly transcode failed",
+			zap.Uint("scene_id", sceneID),
+			zap.Float64("start", opts.StartSeconds),
+			zap.Bool("copy_video", opts.CopyVideo),
+			zap.Bool("copy_audio", opts.CopyAudio),
+			zap.Error(err),
+		)
+	}
+}
+
+// EmbedChapters reports whether transcodes carry the viewer's markers as chapters.
+func (m *Manager) EmbedChapters() bool {
+	return m.embedChapters
+}
+
+// AcquireTranscode reserves one of the max_transcodes slots. Returns false when
+// all slots are in use.
+func (m *Manager) AcquireTranscode() bool {
+	select {
+	case m.transcodeSlots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReleaseTranscode frees a slot reserved by AcquireTranscode.
+func (m *Manager) ReleaseTranscode() {
+	<-m.transcodeSlots
+}
+
+// decidePlayback compares the scene's metadata with the client capabilities.
+func decidePlayback(scene *data.Scene, caps ClientCapabilities) *PlaybackDecision {
+	container := sceneContainer(scene.StoredPath)
+	videoCodec := normalizeCodec(scene.VideoCodec)
+	audioCodec := normalizeCodec(scene.AudioCodec)
+
+	decision := &PlaybackDecision{
+		Container:  container,
+		VideoCodec: videoCodec,
+		AudioCodec: audioCodec,
+		Reasons:    []string{},
+	}
+
+	if videoCodec == "" {
+		// Metadata not extracted yet, so there is nothing to negotiate on
+		decision.Mode = PlaybackDirect
+		decision.StreamURL = directStreamURL(scene.ID)
+		decision.Reasons = append(decision.Reasons, "codec metadata not available")
+		return decision
+	}
+
+	containers := normalizedSet(caps.Containers, defaultContainers, normalizeContainer)
+	videoCodecs := normalizedSet(caps.VideoCodecs, defaultVideoCodecs, normalizeCodec)
+	audioCodecs := normalizedSet(caps.AudioCodecs, defaultAudioCodecs, normalizeCodec)
+
+	if !containers[container] {
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("container %q not supported", container))
+	}
+	if !videoCodecs[videoCodec] {
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("video codec %q not supported", videoCodec))
+	}
+	if audioCodec != "" && !audioCodecs[audioCodec] {
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("audio codec %q not supported", audioCodec))
+	}
+
+	if len(decision.Reasons) == 0 {
+		decision.Mode = PlaybackDirect
+		decision.StreamURL = directStreamURL(scene.ID)
+		return decision
+	}
+
+	decision.Mode = PlaybackTranscode
+	decision.StreamURL = transcodeStreamURL(scene.ID)
+	decision.TranscodeVideo = videoCodec != transcodeVideoCodec
+	decision.TranscodeAudio = audioCodec != "" && audioCodec != transcodeAudioCodec
+	return decision
+}
+
+func directStreamURL(sceneID uint) string {
+	return fmt.Sprintf("/api/v1/scenes/%d/stream", sceneID)
+}
+
+func transcodeStreamURL(sceneID uint) string {
+	return fmt.Sprintf("/api/v1/scenes/%d/stream/transcode", sceneID)
+}
+
+// sceneContainer derives the container from the stored file extension.
+func sceneContainer(storedPath string) string {
+	return normalizeContainer(strings.TrimPrefix(filepath.Ext(storedPath), "."))
+}
+
+func normalizeCodec(codec string) string {
+	codec = strings.ToLower(strings.TrimSpace(codec))
+	// Strip RFC 6381 profile suffixes such as "avc1.64001f" or "mp4a.40.2"
+	if i := strings.IndexByte(codec, '.'); i > 0 && codec != "h.264" && codec != "h.265" {
+		codec = codec[:i]
+	}
+	if alias, ok := codecAliases[codec]; ok {
+		return alias
+	}
+	return codec
+}
+
+func normalizeContainer(container string) string {
+	container = strings.ToLower(strings.TrimSpace(container))
+	container = strings.TrimPrefix(container, "video/")
+	container = strings.TrimPrefix(container, "x-")
+	if alias, ok := containerAliases[container]; ok {
+		return alias
+	}
+	return container
+}
+
+// normalizedSet builds a lookup set from the client's list, or the defaults when empty.
+func normalizedSet(values, defaults []string, normalize func(string) string) map[string]bool {
+	if len(values) == 0 {
+		values = defaults
+	}
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[normalize(v)] = true
+	}
+	return set
+}
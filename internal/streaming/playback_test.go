@@ -0,0 +1,125 @@
+package streaming
+
+import (
+	"testing"
+
+	"goonhub/internal/data"
+)
+
+func TestDecidePlayback(t *testing.T) {
+	tests := []struct {
+		name           string
+		scene          data.Scene
+		caps           ClientCapabilities
+		expectedMode   string
+		transcodeVideo bool
+		transcodeAudio bool
+	}{
+		{
+			name:         "h264 mp4 with default capabilities",
+			scene:        data.Scene{ID: 1, StoredPath: "/v/a.mp4", VideoCodec: "h264", AudioCodec: "aac"},
+			expectedMode: PlaybackDirect,
+		},
+		{
+			name:         "hevc mp4 with hevc-capable client",
+			scene:        data.Scene{ID: 1, StoredPath: "/v/a.mp4", VideoCodec: "hevc", AudioCodec: "aac"},
+			caps:         ClientCapabilities{Containers: []string{"video/mp4"}, VideoCodecs: []string{"hvc1.1.6.L93.B0", "avc1"}, AudioCodecs: []string{"mp4a.40.2"}},
+			expectedMode: PlaybackDirect,
+		},
+		{
+			name:           "hevc mp4 with h264-only client",
+			scene:          data.Scene{ID: 1, StoredPath: "/v/a.mp4", VideoCodec: "hevc", AudioCodec: "aac"},
+			expectedMode:   PlaybackTranscode,
+			transcodeVideo: true,
+		},
+		{
+			name:         "h264 mkv remuxes without re-encoding",
+			scene:        data.Scene{ID: 1, StoredPath: "/v/a.MKV", VideoCodec: "h264", AudioCodec: "aac"},
+			expectedMode: PlaybackTranscode,
+		},
+		{
+			name:           "mkv with ac3 audio on matroska-capable client",
+			scene:          data.Scene{ID: 1, StoredPath: "/v/a.mkv", VideoCodec: "h264", AudioCodec: "ac3"},
+			caps:           ClientCapabilities{Containers: []string{"video/x-matroska", "mp4"}, VideoCodecs: []string{"h264"}, AudioCodecs: []string{"aac"}},
+			expectedMode:   PlaybackTranscode,
+			transcodeAudio: true,
+		},
+		{
+			name:         "silent video ignores audio support",
+			scene:        data.Scene{ID: 1, StoredPath: "/v/a.webm", VideoCodec: "vp9"},
+			caps:         ClientCapabilities{Containers: []string{"webm"}, VideoCodecs: []string{"vp09"}},
+			expectedMode: PlaybackDirect,
+		},
+		{
+			name:         "missing metadata falls back to direct",
+			scene:        data.Scene{ID: 1, StoredPath: "/v/a.avi"},
+			expectedMode: PlaybackDirect,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := decidePlayback(&tt.scene, tt.caps)
+
+			if decision.Mode != tt.expectedMode {
+				t.Fatalf("expected mode %s, got %s (reasons: %v)", tt.expectedMode, decision.Mode, decision.Reasons)
+			}
+			if decision.TranscodeVideo != tt.transcodeVideo || decision.TranscodeAudio != tt.transcodeAudio {
+				t.Fatalf("expected transcode video=%v audio=%v, got video=%v audio=%v",
+					tt.transcodeVideo, tt.transcodeAudio, decision.TranscodeVideo, decision.TranscodeAudio)
+			}
+
+			expectedURL := directStreamURL(tt.scene.ID)
+			if tt.expectedMode == PlaybackTranscode {
+				expectedURL = transcodeStreamURL(tt.scene.ID)
+			}
+			if decision.StreamURL != expectedURL {
+				t.Fatalf("expected stream URL %s, got %s", expectedURL, decision.StreamURL)
+			}
+		})
+	}
+}
+
+func TestNormalizeCodecAndContainer(t *testing.T) {
+	codecs := map[string]string{
+		"avc1.64001f": "h264",
+		"H264":        "h264",
+		"H.265":       "hevc",
+		"mp4a.40.2":   "aac",
+		"opus":        "opus",
+	}
+	for in, want := range codecs {
+		if got := normalizeCodec(in); got != want {
+			t.Fatalf("normalizeCodec(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	containers := map[string]string{
+		"video/mp4":        "mp4",
+		"video/x-matroska": "mkv",
+		"m4v":              "mp4",
+		"video/quicktime":  "mov",
+	}
+	for in, want := range containers {
+		if got := normalizeContainer(in); got != want {
+			t.Fatalf("normalizeContainer(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTranscodeSlots(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxTranscodes = 1
+	m := &Manager{transcodeSlots: make(chan struct{}, cfg.MaxTranscodes)}
+
+	if !m.AcquireTranscode() {
+		t.Fatal("expected first transcode slot to be acquired")
+	}
+	if m.AcquireTranscode() {
+		t.Fatal("expected second transcode to be rejected")
+	}
+	m.ReleaseTranscode()
+	if !m.AcquireTranscode() {
+		t.Fatal("expected slot to be reusable after release")
+	}
+}
@@ -0,0 +1,35 @@
+// Package version exposes build information and the embedded release changelog.
+package version
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// Version and Commit are set at build time with
+// -ldflags "-X goonhub/internal/version.Version=... -X goonhub/internal/version.Commit=..."
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+//go:embed changelog.json
+var changelogJSON []byte
+
+// ChangelogEntry lists user-facing changes and deprecations for one release.
+type ChangelogEntry struct {
+	Version      string   `json:"version"`
+	Date         string   `json:"date,omitempty"` // YYYY-MM-DD, empty while unreleased
+	Changes      []string `json:"changes"`
+	Deprecations []string `json:"deprecations,omitempty"`
+}
+
+// Changelog returns the embedded changelog, newest release first.
+func Changelog() ([]ChangelogEntry, error) {
+	var entries []ChangelogEntry
+	if err := json.Unmarshal(changelogJSON, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded changelog: %w", err)
+	}
+	return entries, nil
+}
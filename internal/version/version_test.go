@@ -0,0 +1,18 @@
+package version
+
+import "testing"
+
+func TestChangelogParses(t *testing.T) {
+	entries, err := Changelog()
+	if err != nil {
+		t.Fatalf("expected embedded changelog to parse, got %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("expected at least one changelog entry")
+	}
+	for _, e := range entries {
+		if e.Version == "" || len(e.Changes) == 0 {
+			t.Fatalf("changelog entry missing version or changes: %+v", e)
+		}
+	}
+}
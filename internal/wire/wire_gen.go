@@ -13,15 +13,19 @@ import (
 	"goonhub/internal/api"
 	"goonhub/internal/api/middleware"
 	"goonhub/internal/api/v1/handler"
+	"goonhub/internal/cli"
 	"goonhub/internal/config"
 	"goonhub/internal/core"
+	"goonhub/internal/core/scraper"
 	"goonhub/internal/data"
 	"goonhub/internal/infrastructure/logging"
 	"goonhub/internal/infrastructure/meilisearch"
+	"goonhub/internal/infrastructure/persistence/migrator"
 	"goonhub/internal/infrastructure/persistence/postgres"
 	"goonhub/internal/infrastructure/server"
 	"goonhub/internal/streaming"
 	"gorm.io/gorm"
+	"path/filepath"
 	"time"
 )
 
@@ -44,65 +48,85 @@ func InitializeServer(cfgPath string) (*server.Server, error) {
 	sceneRepository := provideSceneRepository(db)
 	markerRepository := provideMarkerRepository(db)
 	tagRepository := provideTagRepository(db)
-	markerService := provideMarkerService(markerRepository, sceneRepository, tagRepository, configConfig, logger)
 	eventBus := provideEventBus(logger)
+	roleRepository := provideRoleRepository(db)
+	permissionRepository := providePermissionRepository(db)
+	contentRestrictionRepository := provideContentRestrictionRepository(db)
+	rbacService := provideRBACService(roleRepository, permissionRepository, contentRestrictionRepository, logger)
+	markerService := provideMarkerService(markerRepository, sceneRepository, tagRepository, eventBus, configConfig, rbacService, logger)
 	jobHistoryRepository := provideJobHistoryRepository(db)
-	jobHistoryService := provideJobHistoryService(jobHistoryRepository, configConfig, logger)
+	jobLogRepository := provideJobLogRepository(db)
+	jobHistoryService := provideJobHistoryService(jobHistoryRepository, jobLogRepository, configConfig, logger)
 	poolConfigRepository := providePoolConfigRepository(db)
 	processingConfigRepository := provideProcessingConfigRepository(db)
 	triggerConfigRepository := provideTriggerConfigRepository(db)
 	sceneProcessingService := provideSceneProcessingService(sceneRepository, markerService, configConfig, logger, eventBus, jobHistoryService, poolConfigRepository, processingConfigRepository, triggerConfigRepository)
 	dlqRepository := provideDLQRepository(db)
 	appSettingsRepository := provideAppSettingsRepository(db)
-	sceneService := provideSceneService(sceneRepository, configConfig, sceneProcessingService, eventBus, logger, jobHistoryRepository, dlqRepository, appSettingsRepository)
+	sceneIntegrityRepository := provideSceneIntegrityRepository(db)
+	duplicateGroupRepository := provideDuplicateGroupRepository(db)
+	duplicateService := provideDuplicateService(duplicateGroupRepository, sceneRepository, eventBus, logger)
+	interactionRepository := provideInteractionRepository(db)
+	playlistRepository := providePlaylistRepository(db)
+	deletionGuard := provideDeletionGuard(interactionRepository, markerRepository, playlistRepository, configConfig, logger)
+	auditRepository := provideAuditRepository(db)
+	auditService := provideAuditService(auditRepository, configConfig, logger)
+	sceneService := provideSceneService(sceneRepository, configConfig, sceneProcessingService, eventBus, logger, jobHistoryRepository, dlqRepository, appSettingsRepository, sceneIntegrityRepository, duplicateService, deletionGuard, rbacService, auditService)
 	tagService := provideTagService(tagRepository, sceneRepository, logger)
 	searchConfigRepository := provideSearchConfigRepository(db)
 	client, err := provideMeilisearchClient(configConfig, searchConfigRepository, logger)
 	if err != nil {
 		return nil, err
 	}
-	interactionRepository := provideInteractionRepository(db)
+	sceneTextSearchRepository := provideSceneTextSearchRepository(db)
 	actorRepository := provideActorRepository(db)
-	searchService := provideSearchService(client, sceneRepository, interactionRepository, tagRepository, actorRepository, markerRepository, logger)
 	studioRepository := provideStudioRepository(db)
+	searchService := provideSearchService(client, sceneTextSearchRepository, configConfig, sceneRepository, interactionRepository, tagRepository, actorRepository, markerRepository, contentRestrictionRepository, studioRepository, logger)
 	actorInteractionRepository := provideActorInteractionRepository(db)
 	studioInteractionRepository := provideStudioInteractionRepository(db)
 	watchHistoryRepository := provideWatchHistoryRepository(db)
-	relatedScenesService := provideRelatedScenesService(sceneRepository, tagRepository, actorRepository, studioRepository, actorInteractionRepository, studioInteractionRepository, watchHistoryRepository, logger)
-	manager := provideStreamManager(configConfig, sceneRepository, logger)
-	sceneHandler := provideSceneHandler(sceneService, sceneProcessingService, tagService, searchService, relatedScenesService, markerService, manager, interactionRepository, tagRepository, actorRepository, configConfig)
+	relatedScenesService := provideRelatedScenesService(sceneRepository, tagRepository, actorRepository, studioRepository, actorInteractionRepository, studioInteractionRepository, watchHistoryRepository, rbacService, logger)
+	manager := provideStreamManager(configConfig, sceneRepository, appSettingsRepository, logger)
+	reviewWorkflowService := provideReviewWorkflowService(sceneRepository, configConfig, eventBus, logger)
+	mediaSigner := provideMediaSigner(configConfig)
+	castService := provideCastService(sceneRepository, appSettingsRepository, mediaSigner, configConfig, logger)
+	spriteFrameService := provideSpriteFrameService(sceneRepository, configConfig, logger)
+	sceneHandler := provideSceneHandler(sceneService, sceneProcessingService, tagService, searchService, relatedScenesService, markerService, manager, interactionRepository, tagRepository, actorRepository, reviewWorkflowService, castService, spriteFrameService, mediaSigner, configConfig)
 	userRepository := provideUserRepository(db)
 	revokedTokenRepository := provideRevokedTokenRepository(db)
-	authService, err := provideAuthService(userRepository, revokedTokenRepository, configConfig, logger)
+	apiKeyRepository := provideAPIKeyRepository(db)
+	apiKeyService := provideAPIKeyService(apiKeyRepository, userRepository, rbacService, logger)
+	authService, err := provideAuthService(userRepository, revokedTokenRepository, appSettingsRepository, apiKeyService, configConfig, logger)
 	if err != nil {
 		return nil, err
 	}
 	userService := provideUserService(userRepository, logger)
-	authHandler := provideAuthHandler(authService, userService, configConfig)
+	privacyLockService := providePrivacyLockService(userRepository, authService, logger)
+	userSessionRepository := provideUserSessionRepository(db)
+	sessionService := provideSessionService(userSessionRepository, authService, logger)
+	authHandler := provideAuthHandler(authService, userService, privacyLockService, auditService, sessionService, configConfig)
 	userSettingsRepository := provideUserSettingsRepository(db)
 	settingsService := provideSettingsService(userSettingsRepository, userRepository, logger)
 	settingsHandler := provideSettingsHandler(settingsService, configConfig)
-	roleRepository := provideRoleRepository(db)
-	permissionRepository := providePermissionRepository(db)
-	rbacService := provideRBACService(roleRepository, permissionRepository, logger)
 	adminService := provideAdminService(userRepository, roleRepository, rbacService, logger)
-	adminHandler := provideAdminHandler(adminService, rbacService, sceneService, appSettingsRepository)
-	jobHandler := provideJobHandler(jobHistoryService, sceneProcessingService)
-	poolConfigHandler := providePoolConfigHandler(sceneProcessingService, poolConfigRepository)
+	adminHandler := provideAdminHandler(adminService, rbacService, sceneService, appSettingsRepository, manager)
+	poolAutoscaler := providePoolAutoscaler(configConfig, sceneProcessingService, jobHistoryRepository, logger)
+	poolConfigHandler := providePoolConfigHandler(sceneProcessingService, poolConfigRepository, poolAutoscaler)
 	processingConfigHandler := provideProcessingConfigHandler(sceneProcessingService, processingConfigRepository, markerService)
 	triggerScheduler := provideTriggerScheduler(triggerConfigRepository, sceneRepository, sceneProcessingService, logger)
 	triggerConfigHandler := provideTriggerConfigHandler(triggerConfigRepository, sceneProcessingService, triggerScheduler)
 	dlqService := provideDLQService(dlqRepository, jobHistoryRepository, sceneRepository, eventBus, logger)
 	dlqHandler := provideDLQHandler(dlqService)
 	retryConfigRepository := provideRetryConfigRepository(db)
-	retryScheduler := provideRetryScheduler(jobHistoryRepository, dlqRepository, retryConfigRepository, sceneRepository, eventBus, logger)
+	retryScheduler := provideRetryScheduler(jobHistoryRepository, dlqRepository, retryConfigRepository, sceneRepository, eventBus, configConfig, logger)
 	retryConfigHandler := provideRetryConfigHandler(retryConfigRepository, retryScheduler)
 	jobStatusService := provideJobStatusService(jobHistoryService, sceneProcessingService, logger)
 	sseHandler := provideSSEHandler(eventBus, authService, jobStatusService, logger)
 	tagHandler := provideTagHandler(tagService)
-	actorService := provideActorService(actorRepository, sceneRepository, logger)
-	actorHandler := provideActorHandler(actorService, configConfig)
-	studioService := provideStudioService(studioRepository, sceneRepository, logger)
+	actorService := provideActorService(actorRepository, sceneRepository, rbacService, logger)
+	actorImageService := provideActorImageService(actorRepository, sceneRepository, markerRepository, jobHistoryService, configConfig, logger)
+	actorHandler := provideActorHandler(actorService, actorImageService, configConfig)
+	studioService := provideStudioService(studioRepository, sceneRepository, rbacService, logger)
 	studioHandler := provideStudioHandler(studioService, configConfig)
 	interactionService := provideInteractionService(interactionRepository, logger)
 	interactionHandler := provideInteractionHandler(interactionService)
@@ -110,43 +134,170 @@ func InitializeServer(cfgPath string) (*server.Server, error) {
 	actorInteractionHandler := provideActorInteractionHandler(actorInteractionService, actorRepository)
 	studioInteractionService := provideStudioInteractionService(studioInteractionRepository, logger)
 	studioInteractionHandler := provideStudioInteractionHandler(studioInteractionService, studioRepository)
-	searchHandler := provideSearchHandler(searchService, searchConfigRepository)
+	searchReindexRepository := provideSearchReindexRepository(db)
+	searchReindexService := provideSearchReindexService(searchService, sceneRepository, tagRepository, actorRepository, searchReindexRepository, eventBus, logger)
+	searchConsistencyService := provideSearchConsistencyService(searchService, searchReindexService, sceneRepository, configConfig, logger)
+	searchHandler := provideSearchHandler(searchService, searchReindexService, searchConsistencyService, searchConfigRepository)
 	watchHistoryService := provideWatchHistoryService(watchHistoryRepository, sceneRepository, searchService, logger)
 	watchHistoryHandler := provideWatchHistoryHandler(watchHistoryService)
 	storagePathRepository := provideStoragePathRepository(db)
 	storagePathService := provideStoragePathService(storagePathRepository, logger)
-	storagePathHandler := provideStoragePathHandler(storagePathService)
 	scanHistoryRepository := provideScanHistoryRepository(db)
-	scanService := provideScanService(storagePathService, sceneRepository, scanHistoryRepository, sceneProcessingService, eventBus, logger)
-	scanHandler := provideScanHandler(scanService)
+	scanReportRepository := provideScanReportRepository(db)
+	scanConfigRepository := provideScanConfigRepository(db)
+	sidecarMetadataService := provideSidecarMetadataService(configConfig, sceneRepository, studioRepository, tagRepository, actorRepository, logger)
+	folderRuleRepository := provideFolderRuleRepository(db)
 	explorerRepository := provideExplorerRepository(db)
-	explorerService := provideExplorerService(explorerRepository, storagePathRepository, sceneRepository, tagRepository, actorRepository, jobHistoryRepository, eventBus, logger, configConfig)
-	explorerHandler := provideExplorerHandler(explorerService)
-	pornDBService := providePornDBService(configConfig, logger)
-	pornDBHandler := providePornDBHandler(pornDBService)
+	folderRuleService := provideFolderRuleService(folderRuleRepository, storagePathRepository, explorerRepository, sceneRepository, tagRepository, actorRepository, studioRepository, eventBus, logger)
+	scanService := provideScanService(storagePathService, sceneRepository, scanHistoryRepository, scanReportRepository, scanConfigRepository, sidecarMetadataService, folderRuleService, sceneProcessingService, eventBus, logger)
+	scanScheduler := provideScanScheduler(storagePathRepository, scanService, logger)
+	storageHealthRepository := provideStorageHealthRepository(db)
+	storageHealthService := provideStorageHealthService(storagePathRepository, explorerRepository, storageHealthRepository, eventBus, configConfig, logger)
+	storagePathHandler := provideStoragePathHandler(storagePathService, scanScheduler, storageHealthService)
+	dropboxImportService := provideDropboxImportService(storagePathService, scanService, sidecarMetadataService, configConfig, logger)
+	scanHandler := provideScanHandler(scanService, folderRuleService, dropboxImportService)
+	remoteImportRepository := provideRemoteImportRepository(db)
+	remoteImportService := provideRemoteImportService(remoteImportRepository, storagePathService, scanService, sceneRepository, jobHistoryService, eventBus, configConfig, logger)
+	remoteImportHandler := provideRemoteImportHandler(remoteImportService)
+	torrentImportRepository := provideTorrentImportRepository(db)
+	torrentImportService := provideTorrentImportService(torrentImportRepository, storagePathService, scanService, sceneRepository, configConfig, logger)
+	torrentImportHandler := provideTorrentImportHandler(torrentImportService)
+	explorerService := provideExplorerService(explorerRepository, storagePathRepository, sceneRepository, tagRepository, actorRepository, studioRepository, jobHistoryRepository, eventBus, logger, configConfig, deletionGuard, rbacService)
+	storageMigrationService := provideStorageMigrationService(sceneRepository, storagePathService, explorerService, jobHistoryService, eventBus, logger)
+	jobQueueFeeder := provideJobQueueFeeder(jobHistoryRepository, sceneRepository, markerService, sceneIntegrityRepository, sceneProcessingService, eventBus, logger)
+	jobHistoryRetentionWorker := provideJobHistoryRetentionWorker(jobHistoryRepository, configConfig, logger)
+	jobHandler := provideJobHandler(jobHistoryService, sceneProcessingService, remoteImportService, storageMigrationService, jobQueueFeeder, jobHistoryRetentionWorker)
+	explorerHandler := provideExplorerHandler(explorerService, storageMigrationService)
+	pornDBCacheRepository := providePornDBCacheRepository(db)
+	pornDBService := providePornDBService(configConfig, pornDBCacheRepository, logger)
+	pornDBMatchRepository := providePornDBMatchRepository(db)
+	stashDBService := provideStashDBService(configConfig, logger)
+	metadataProviders := provideMetadataProviders(pornDBService, stashDBService)
+	pornDBMatchService := providePornDBMatchService(pornDBMatchRepository, sceneRepository, metadataProviders, sceneService, jobHistoryService, logger)
+	actorSyncRepository := provideActorSyncRepository(db)
+	actorSyncService := provideActorSyncService(actorRepository, actorSyncRepository, pornDBService, configConfig, logger)
+	pornDBHandler := providePornDBHandler(pornDBService, metadataProviders, pornDBMatchService, actorSyncService)
 	savedSearchRepository := provideSavedSearchRepository(db)
-	savedSearchService := provideSavedSearchService(savedSearchRepository, logger)
+	savedSearchService := provideSavedSearchService(savedSearchRepository, tagRepository, sceneRepository, searchService, eventBus, configConfig, rbacService, logger)
 	savedSearchHandler := provideSavedSearchHandler(savedSearchService)
-	playlistRepository := providePlaylistRepository(db)
 	playlistService := providePlaylistService(playlistRepository, sceneRepository, tagRepository, logger)
-	homepageService := provideHomepageService(settingsService, searchService, savedSearchService, playlistService, watchHistoryRepository, interactionRepository, sceneRepository, tagRepository, actorRepository, studioRepository, logger)
+	homepageService := provideHomepageService(settingsService, searchService, savedSearchService, playlistService, watchHistoryRepository, interactionRepository, sceneRepository, tagRepository, actorRepository, studioRepository, reviewWorkflowService, rbacService, logger)
 	homepageHandler := provideHomepageHandler(homepageService)
-	markerHandler := provideMarkerHandler(markerService, configConfig)
+	markerCompilationRepository := provideMarkerCompilationRepository(db)
+	markerCompilationService := provideMarkerCompilationService(markerCompilationRepository, markerRepository, sceneRepository, jobHistoryService, eventBus, configConfig, logger)
+	markerHandler := provideMarkerHandler(markerService, markerCompilationService, configConfig)
 	importHandler := provideImportHandler(sceneRepository, markerRepository, logger)
 	streamStatsHandler := provideStreamStatsHandler(manager)
+	requestStatsService := provideRequestStatsService(configConfig)
+	requestStatsHandler := provideRequestStatsHandler(requestStatsService)
+	duplicateHandler := provideDuplicateHandler(duplicateService)
+	sceneArtifactRepository := provideSceneArtifactRepository(db)
+	artifactService := provideArtifactService(sceneArtifactRepository, sceneRepository, markerRepository, configConfig, logger)
+	artifactHandler := provideArtifactHandler(artifactService)
+	schemaRepository := provideSchemaRepository(db)
+	releaseService := provideReleaseService(schemaRepository, sceneArtifactRepository, logger)
+	releaseHandler := provideReleaseHandler(releaseService)
+	apiUsageRepository := provideAPIUsageRepository(db)
+	apiUsageService := provideAPIUsageService(apiUsageRepository, configConfig, logger)
+	apiUsageHandler := provideAPIUsageHandler(apiUsageService)
+	downloadRepository := provideDownloadRepository(db)
+	downloadService := provideDownloadService(sceneRepository, downloadRepository, logger)
+	downloadHandler := provideDownloadHandler(downloadService, sceneService, manager)
+	reviewWorkflowHandler := provideReviewWorkflowHandler(reviewWorkflowService)
 	playlistHandler := providePlaylistHandler(playlistService, configConfig)
 	shareLinkRepository := provideShareLinkRepository(db)
-	shareService := provideShareService(shareLinkRepository, sceneRepository, logger)
+	shareService := provideShareService(shareLinkRepository, sceneRepository, configConfig, logger)
 	shareHandler := provideShareHandler(shareService, authService, manager, configConfig)
+	agentService := provideAgentService(configConfig, logger)
+	agentHandler := provideAgentHandler(agentService)
+	watchPartyService := provideWatchPartyService(sceneRepository, configConfig, rbacService, logger)
+	watchPartyHandler := provideWatchPartyHandler(watchPartyService, logger)
+	offlineSyncRepository := provideOfflineSyncRepository(db)
+	offlineSyncService := provideOfflineSyncService(offlineSyncRepository, sceneRepository, markerRepository, mediaSigner, rbacService, logger)
+	offlineSyncHandler := provideOfflineSyncHandler(offlineSyncService)
+	apiKeyHandler := provideAPIKeyHandler(apiKeyService, rbacService)
+	auditHandler := provideAuditHandler(auditService)
+	inviteRepository := provideInviteRepository(db)
+	registrationService := provideRegistrationService(inviteRepository, userRepository, roleRepository, appSettingsRepository, adminService, logger)
+	registrationHandler := provideRegistrationHandler(registrationService, auditService)
+	graphQLService := provideGraphQLService(sceneService, searchService, tagRepository, actorRepository, studioRepository, markerRepository, logger)
+	graphQLHandler := provideGraphQLHandler(graphQLService)
+	webhookRepository := provideWebhookRepository(db)
+	webhookService := provideWebhookService(webhookRepository, eventBus, logger)
+	webhookHandler := provideWebhookHandler(webhookService)
+	registry := provideScraperRegistry(configConfig, logger)
+	sceneScrapeService := provideSceneScrapeService(registry, sceneService, tagService, actorService, actorRepository, logger)
+	scraperHandler := provideScraperHandler(sceneScrapeService)
+	uploadSessionRepository := provideUploadSessionRepository(db)
+	uploadService := provideUploadService(uploadSessionRepository, sceneService, configConfig, logger)
+	uploadHandler := provideUploadHandler(uploadService, sceneService)
 	ipRateLimiter := provideRateLimiter(configConfig)
-	ogMiddleware := provideOGMiddleware(sceneRepository, actorRepository, studioRepository, playlistRepository, shareLinkRepository, appSettingsRepository, logger)
-	engine := provideRouter(logger, configConfig, sceneHandler, authHandler, settingsHandler, adminHandler, jobHandler, poolConfigHandler, processingConfigHandler, triggerConfigHandler, dlqHandler, retryConfigHandler, sseHandler, tagHandler, actorHandler, studioHandler, interactionHandler, actorInteractionHandler, studioInteractionHandler, searchHandler, watchHistoryHandler, storagePathHandler, scanHandler, explorerHandler, pornDBHandler, savedSearchHandler, homepageHandler, markerHandler, importHandler, streamStatsHandler, playlistHandler, shareHandler, authService, rbacService, ipRateLimiter, ogMiddleware)
-	jobQueueFeeder := provideJobQueueFeeder(jobHistoryRepository, sceneRepository, markerService, sceneProcessingService, logger)
-	shareServer := provideShareServer(configConfig, shareHandler, ogMiddleware, logger)
-	serverServer := provideServer(engine, logger, configConfig, sceneProcessingService, userService, jobHistoryService, jobHistoryRepository, jobQueueFeeder, triggerScheduler, sceneService, tagService, searchService, scanService, explorerService, retryScheduler, dlqService, actorService, studioService, shareServer)
+	ogMiddleware := provideOGMiddleware(sceneRepository, actorRepository, studioRepository, playlistRepository, shareLinkRepository, appSettingsRepository, mediaSigner, logger)
+	engine := provideRouter(logger, configConfig, sceneHandler, authHandler, settingsHandler, adminHandler, jobHandler, poolConfigHandler, processingConfigHandler, triggerConfigHandler, dlqHandler, retryConfigHandler, sseHandler, tagHandler, actorHandler, studioHandler, interactionHandler, actorInteractionHandler, studioInteractionHandler, searchHandler, watchHistoryHandler, storagePathHandler, scanHandler, explorerHandler, pornDBHandler, savedSearchHandler, homepageHandler, markerHandler, importHandler, streamStatsHandler, requestStatsHandler, duplicateHandler, artifactHandler, releaseHandler, apiUsageHandler, downloadHandler, reviewWorkflowHandler, playlistHandler, shareHandler, agentHandler, watchPartyHandler, offlineSyncHandler, apiKeyHandler, auditHandler, registrationHandler, graphQLHandler, webhookHandler, scraperHandler, uploadHandler, remoteImportHandler, torrentImportHandler, authService, rbacService, privacyLockService, requestStatsService, apiUsageService, auditService, shareService, ipRateLimiter, ogMiddleware, mediaSigner)
+	shareServer := provideShareServer(configConfig, shareHandler, ogMiddleware, requestStatsService, shareService, authService, mediaSigner, ipRateLimiter, logger)
+	storageWatcherService := provideStorageWatcherService(storagePathService, scanService, configConfig, logger)
+	heatmapRepository := provideHeatmapRepository(db)
+	sceneHeatmapService := provideSceneHeatmapService(heatmapRepository, sceneRepository, configConfig, logger)
+	serverServer := provideServer(engine, logger, configConfig, sceneProcessingService, userService, jobHistoryService, jobHistoryRepository, jobQueueFeeder, triggerScheduler, sceneService, tagService, searchService, scanService, explorerService, retryScheduler, dlqService, actorService, studioService, shareServer, agentService, artifactService, apiUsageService, searchConsistencyService, markerService, savedSearchService, storageWatcherService, scanScheduler, folderRuleService, markerCompilationService, sceneHeatmapService, duplicateService, auditService, webhookService, actorSyncService, storageHealthService, dropboxImportService, remoteImportService, torrentImportService, storageMigrationService, jobHistoryRetentionWorker, poolAutoscaler)
 	return serverServer, nil
 }
 
+// InitializeCLI creates the goonhubctl admin application. It only wires the
+// services the CLI commands need and never starts worker pools or the HTTP server.
+func InitializeCLI(cfgPath string) (*cli.App, error) {
+	configConfig, err := config.Load(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+	logger, err := logging.New(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	db, err := postgres.NewDB(configConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	userRepository := provideUserRepository(db)
+	scanHistoryRepository := provideScanHistoryRepository(db)
+	roleRepository := provideRoleRepository(db)
+	permissionRepository := providePermissionRepository(db)
+	contentRestrictionRepository := provideContentRestrictionRepository(db)
+	rbacService := provideRBACService(roleRepository, permissionRepository, contentRestrictionRepository, logger)
+	adminService := provideAdminService(userRepository, roleRepository, rbacService, logger)
+	storagePathRepository := provideStoragePathRepository(db)
+	storagePathService := provideStoragePathService(storagePathRepository, logger)
+	sceneRepository := provideSceneRepository(db)
+	scanReportRepository := provideScanReportRepository(db)
+	scanConfigRepository := provideScanConfigRepository(db)
+	studioRepository := provideStudioRepository(db)
+	tagRepository := provideTagRepository(db)
+	actorRepository := provideActorRepository(db)
+	sidecarMetadataService := provideSidecarMetadataService(configConfig, sceneRepository, studioRepository, tagRepository, actorRepository, logger)
+	folderRuleRepository := provideFolderRuleRepository(db)
+	explorerRepository := provideExplorerRepository(db)
+	eventBus := provideEventBus(logger)
+	folderRuleService := provideFolderRuleService(folderRuleRepository, storagePathRepository, explorerRepository, sceneRepository, tagRepository, actorRepository, studioRepository, eventBus, logger)
+	markerRepository := provideMarkerRepository(db)
+	markerService := provideMarkerService(markerRepository, sceneRepository, tagRepository, eventBus, configConfig, rbacService, logger)
+	jobHistoryRepository := provideJobHistoryRepository(db)
+	jobLogRepository := provideJobLogRepository(db)
+	jobHistoryService := provideJobHistoryService(jobHistoryRepository, jobLogRepository, configConfig, logger)
+	poolConfigRepository := providePoolConfigRepository(db)
+	processingConfigRepository := provideProcessingConfigRepository(db)
+	triggerConfigRepository := provideTriggerConfigRepository(db)
+	sceneProcessingService := provideSceneProcessingService(sceneRepository, markerService, configConfig, logger, eventBus, jobHistoryService, poolConfigRepository, processingConfigRepository, triggerConfigRepository)
+	scanService := provideScanService(storagePathService, sceneRepository, scanHistoryRepository, scanReportRepository, scanConfigRepository, sidecarMetadataService, folderRuleService, sceneProcessingService, eventBus, logger)
+	searchConfigRepository := provideSearchConfigRepository(db)
+	client := provideCLIMeilisearchClient(configConfig, searchConfigRepository, logger)
+	sceneTextSearchRepository := provideSceneTextSearchRepository(db)
+	interactionRepository := provideInteractionRepository(db)
+	searchService := provideSearchService(client, sceneTextSearchRepository, configConfig, sceneRepository, interactionRepository, tagRepository, actorRepository, markerRepository, contentRestrictionRepository, studioRepository, logger)
+	searchReindexRepository := provideSearchReindexRepository(db)
+	searchReindexService := provideSearchReindexService(searchService, sceneRepository, tagRepository, actorRepository, searchReindexRepository, eventBus, logger)
+	app := cli.NewApp(configConfig, logger, userRepository, scanHistoryRepository, adminService, scanService, searchService, searchReindexService)
+	return app, nil
+}
+
 // wire.go:
 
 func provideUserRepository(db *gorm.DB) data.UserRepository {
@@ -205,6 +356,10 @@ func provideJobHistoryRepository(db *gorm.DB) data.JobHistoryRepository {
 	return data.NewJobHistoryRepository(db)
 }
 
+func provideJobLogRepository(db *gorm.DB) data.JobLogRepository {
+	return data.NewJobLogRepository(db)
+}
+
 func providePoolConfigRepository(db *gorm.DB) data.PoolConfigRepository {
 	return data.NewPoolConfigRepository(db)
 }
@@ -233,6 +388,30 @@ func provideScanHistoryRepository(db *gorm.DB) data.ScanHistoryRepository {
 	return data.NewScanHistoryRepository(db)
 }
 
+func provideScanReportRepository(db *gorm.DB) data.ScanReportRepository {
+	return data.NewScanReportRepository(db)
+}
+
+func provideFolderRuleRepository(db *gorm.DB) data.FolderRuleRepository {
+	return data.NewFolderRuleRepository(db)
+}
+
+func provideStorageHealthRepository(db *gorm.DB) data.StorageHealthRepository {
+	return data.NewStorageHealthRepository(db)
+}
+
+func provideUploadSessionRepository(db *gorm.DB) data.UploadSessionRepository {
+	return data.NewUploadSessionRepository(db)
+}
+
+func provideRemoteImportRepository(db *gorm.DB) data.RemoteImportRepository {
+	return data.NewRemoteImportRepository(db)
+}
+
+func provideTorrentImportRepository(db *gorm.DB) data.TorrentImportRepository {
+	return data.NewTorrentImportRepository(db)
+}
+
 func provideExplorerRepository(db *gorm.DB) data.ExplorerRepository {
 	return data.NewExplorerRepository(db)
 }
@@ -241,6 +420,18 @@ func provideSearchConfigRepository(db *gorm.DB) data.SearchConfigRepository {
 	return data.NewSearchConfigRepository(db)
 }
 
+func provideScanConfigRepository(db *gorm.DB) data.ScanConfigRepository {
+	return data.NewScanConfigRepository(db)
+}
+
+func provideSearchReindexRepository(db *gorm.DB) data.SearchReindexRepository {
+	return data.NewSearchReindexRepository(db)
+}
+
+func provideSceneTextSearchRepository(db *gorm.DB) data.SceneTextSearchRepository {
+	return data.NewSceneTextSearchRepository(db)
+}
+
 func provideAppSettingsRepository(db *gorm.DB) data.AppSettingsRepository {
 	return data.NewAppSettingsRepository(db)
 }
@@ -253,6 +444,14 @@ func provideMarkerRepository(db *gorm.DB) data.MarkerRepository {
 	return data.NewMarkerRepository(db)
 }
 
+func provideMarkerCompilationRepository(db *gorm.DB) data.MarkerCompilationRepository {
+	return data.NewMarkerCompilationRepository(db)
+}
+
+func provideHeatmapRepository(db *gorm.DB) data.HeatmapRepository {
+	return data.NewHeatmapRepository(db)
+}
+
 func providePlaylistRepository(db *gorm.DB) data.PlaylistRepository {
 	return data.NewPlaylistRepository(db)
 }
@@ -261,7 +460,77 @@ func provideShareLinkRepository(db *gorm.DB) data.ShareLinkRepository {
 	return data.NewShareLinkRepository(db)
 }
 
+func provideDuplicateGroupRepository(db *gorm.DB) data.DuplicateGroupRepository {
+	return data.NewDuplicateGroupRepository(db)
+}
+
+func provideSceneArtifactRepository(db *gorm.DB) data.SceneArtifactRepository {
+	return data.NewSceneArtifactRepository(db)
+}
+
+func provideSchemaRepository(db *gorm.DB) data.SchemaRepository {
+	return data.NewSchemaRepository(db)
+}
+
+func provideAPIUsageRepository(db *gorm.DB) data.APIUsageRepository {
+	return data.NewAPIUsageRepository(db)
+}
+
+func provideDownloadRepository(db *gorm.DB) data.DownloadRepository {
+	return data.NewDownloadRepository(db)
+}
+
+func provideOfflineSyncRepository(db *gorm.DB) data.OfflineSyncRepository {
+	return data.NewOfflineSyncRepository(db)
+}
+
+func provideAPIKeyRepository(db *gorm.DB) data.APIKeyRepository {
+	return data.NewAPIKeyRepository(db)
+}
+
+func provideContentRestrictionRepository(db *gorm.DB) data.ContentRestrictionRepository {
+	return data.NewContentRestrictionRepository(db)
+}
+
+func provideAuditRepository(db *gorm.DB) data.AuditRepository {
+	return data.NewAuditRepository(db)
+}
+
+func provideUserSessionRepository(db *gorm.DB) data.UserSessionRepository {
+	return data.NewUserSessionRepository(db)
+}
+
+func provideInviteRepository(db *gorm.DB) data.InviteRepository {
+	return data.NewInviteRepository(db)
+}
+
+func provideWebhookRepository(db *gorm.DB) data.WebhookRepository {
+	return data.NewWebhookRepository(db)
+}
+
+func providePornDBMatchRepository(db *gorm.DB) data.PornDBMatchRepository {
+	return data.NewPornDBMatchRepository(db)
+}
+
+func providePornDBCacheRepository(db *gorm.DB) data.PornDBCacheRepository {
+	return data.NewPornDBCacheRepository(db)
+}
+
+func provideActorSyncRepository(db *gorm.DB) data.ActorSyncRepository {
+	return data.NewActorSyncRepository(db)
+}
+
+func provideSceneIntegrityRepository(db *gorm.DB) data.SceneIntegrityRepository {
+	return data.NewSceneIntegrityRepository(db)
+}
+
 func provideMeilisearchClient(cfg *config.Config, searchConfigRepo data.SearchConfigRepository, logger *logging.Logger) (*meilisearch.Client, error) {
+
+	if cfg.Search.Backend == core.SearchBackendPostgres {
+		logger.Info("search backend is postgres, not connecting to meilisearch")
+		return nil, nil
+	}
+
 	var maxTotalHits int64 = 100000
 	record, err := searchConfigRepo.Get()
 	if err != nil {
@@ -278,22 +547,64 @@ func provideMeilisearchClient(cfg *config.Config, searchConfigRepo data.SearchCo
 		logger.Logger,
 	)
 	if err != nil {
+		if cfg.Search.Backend == core.SearchBackendAuto {
+
+			logger.Warn(fmt.Sprintf("meilisearch unavailable, searching with postgres: %v", err))
+			return nil, nil
+		}
 		return nil, fmt.Errorf("failed to connect to meilisearch: %w", err)
 	}
 	return client, nil
 }
 
+// provideCLIMeilisearchClient connects to Meilisearch like provideMeilisearchClient,
+// but degrades to a nil client so DB-only commands keep working when search is down.
+func provideCLIMeilisearchClient(cfg *config.Config, searchConfigRepo data.SearchConfigRepository, logger *logging.Logger) *meilisearch.Client {
+	client, err := provideMeilisearchClient(cfg, searchConfigRepo, logger)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("meilisearch unavailable, search index will not be updated: %v", err))
+		return nil
+	}
+	return client
+}
+
 func provideEventBus(logger *logging.Logger) *core.EventBus {
 	return core.NewEventBus(logger.Logger)
 }
 
-func provideAuthService(userRepo data.UserRepository, revokedRepo data.RevokedTokenRepository, cfg *config.Config, logger *logging.Logger) (*core.AuthService, error) {
-	return core.NewAuthService(
+func provideAuthService(userRepo data.UserRepository, revokedRepo data.RevokedTokenRepository, appSettingsRepo data.AppSettingsRepository, apiKeyService *core.APIKeyService, cfg *config.Config, logger *logging.Logger) (*core.AuthService, error) {
+	authService, err := core.NewAuthService(
 		userRepo, revokedRepo,
 		cfg.Auth.PasetoSecret, cfg.Auth.TokenDuration,
 		cfg.Auth.LockoutThreshold, cfg.Auth.LockoutDuration,
 		logger.Logger,
 	)
+	if err != nil {
+		return nil, err
+	}
+	authService.SetAppSettingsRepository(appSettingsRepo)
+	authService.SetAPIKeyAuthenticator(apiKeyService)
+	return authService, nil
+}
+
+func provideRequestStatsService(cfg *config.Config) *core.RequestStatsService {
+	return core.NewRequestStatsService(cfg.Server.SlowRequestThreshold)
+}
+
+func provideAPIUsageService(apiUsageRepo data.APIUsageRepository, cfg *config.Config, logger *logging.Logger) *core.APIUsageService {
+	return core.NewAPIUsageService(apiUsageRepo, cfg.Server.APIUsageRetentionDays, logger.Logger)
+}
+
+func provideAuditService(auditRepo data.AuditRepository, cfg *config.Config, logger *logging.Logger) *core.AuditService {
+	return core.NewAuditService(auditRepo, cfg.Server.AuditRetentionDays, logger.Logger)
+}
+
+func provideSessionService(sessionRepo data.UserSessionRepository, authService *core.AuthService, logger *logging.Logger) *core.SessionService {
+	return core.NewSessionService(sessionRepo, authService, logger.Logger)
+}
+
+func providePrivacyLockService(userRepo data.UserRepository, authService *core.AuthService, logger *logging.Logger) *core.PrivacyLockService {
+	return core.NewPrivacyLockService(userRepo, authService, logger.Logger)
 }
 
 func provideUserService(userRepo data.UserRepository, logger *logging.Logger) *core.UserService {
@@ -304,32 +615,50 @@ func provideSettingsService(settingsRepo data.UserSettingsRepository, userRepo d
 	return core.NewSettingsService(settingsRepo, userRepo, logger.Logger)
 }
 
-func provideRBACService(roleRepo data.RoleRepository, permRepo data.PermissionRepository, logger *logging.Logger) *core.RBACService {
+func provideRBACService(roleRepo data.RoleRepository, permRepo data.PermissionRepository, restrictionRepo data.ContentRestrictionRepository, logger *logging.Logger) *core.RBACService {
 	svc, err := core.NewRBACService(roleRepo, permRepo, logger.Logger)
 	if err != nil {
 		panic(err)
 	}
+	if err := svc.SetContentRestrictionRepository(restrictionRepo); err != nil {
+		panic(err)
+	}
 	return svc
 }
 
+func provideRegistrationService(inviteRepo data.InviteRepository, userRepo data.UserRepository, roleRepo data.RoleRepository, appSettingsRepo data.AppSettingsRepository, adminService *core.AdminService, logger *logging.Logger) *core.RegistrationService {
+	return core.NewRegistrationService(inviteRepo, userRepo, roleRepo, appSettingsRepo, adminService, logger.Logger)
+}
+
 func provideAdminService(userRepo data.UserRepository, roleRepo data.RoleRepository, rbac *core.RBACService, logger *logging.Logger) *core.AdminService {
 	return core.NewAdminService(userRepo, roleRepo, rbac, logger.Logger)
 }
 
-func provideSceneService(repo data.SceneRepository, cfg *config.Config, processingService *core.SceneProcessingService, eventBus *core.EventBus, logger *logging.Logger, jobHistoryRepo data.JobHistoryRepository, dlqRepo data.DLQRepository, appSettingsRepo data.AppSettingsRepository) *core.SceneService {
-	return core.NewSceneService(repo, cfg.Processing.VideoDir, cfg.Processing.MetadataDir, processingService, eventBus, logger.Logger, jobHistoryRepo, dlqRepo, appSettingsRepo)
+func provideSceneService(repo data.SceneRepository, cfg *config.Config, processingService *core.SceneProcessingService, eventBus *core.EventBus, logger *logging.Logger, jobHistoryRepo data.JobHistoryRepository, dlqRepo data.DLQRepository, appSettingsRepo data.AppSettingsRepository, integrityRepo data.SceneIntegrityRepository, duplicateService *core.DuplicateService, deletionGuard *core.DeletionGuard, rbac *core.RBACService, auditService *core.AuditService) *core.SceneService {
+	svc := core.NewSceneService(repo, cfg.Processing.VideoDir, cfg.Processing.MetadataDir, processingService, eventBus, logger.Logger, jobHistoryRepo, dlqRepo, appSettingsRepo, integrityRepo, duplicateService, deletionGuard)
+	svc.SetRBACService(rbac)
+	svc.SetAuditService(auditService)
+	return svc
 }
 
 func provideTagService(tagRepo data.TagRepository, sceneRepo data.SceneRepository, logger *logging.Logger) *core.TagService {
 	return core.NewTagService(tagRepo, sceneRepo, logger.Logger)
 }
 
-func provideActorService(actorRepo data.ActorRepository, sceneRepo data.SceneRepository, logger *logging.Logger) *core.ActorService {
-	return core.NewActorService(actorRepo, sceneRepo, logger.Logger)
+func provideActorService(actorRepo data.ActorRepository, sceneRepo data.SceneRepository, rbac *core.RBACService, logger *logging.Logger) *core.ActorService {
+	svc := core.NewActorService(actorRepo, sceneRepo, logger.Logger)
+	svc.SetRBACService(rbac)
+	return svc
+}
+
+func provideActorImageService(actorRepo data.ActorRepository, sceneRepo data.SceneRepository, markerRepo data.MarkerRepository, jobHistoryService *core.JobHistoryService, cfg *config.Config, logger *logging.Logger) *core.ActorImageService {
+	return core.NewActorImageService(actorRepo, sceneRepo, markerRepo, jobHistoryService, cfg.Processing.ActorImageDir, logger.Logger)
 }
 
-func provideStudioService(studioRepo data.StudioRepository, sceneRepo data.SceneRepository, logger *logging.Logger) *core.StudioService {
-	return core.NewStudioService(studioRepo, sceneRepo, logger.Logger)
+func provideStudioService(studioRepo data.StudioRepository, sceneRepo data.SceneRepository, rbac *core.RBACService, logger *logging.Logger) *core.StudioService {
+	svc := core.NewStudioService(studioRepo, sceneRepo, logger.Logger)
+	svc.SetRBACService(rbac)
+	return svc
 }
 
 func provideInteractionService(repo data.InteractionRepository, logger *logging.Logger) *core.InteractionService {
@@ -344,8 +673,23 @@ func provideStudioInteractionService(repo data.StudioInteractionRepository, logg
 	return core.NewStudioInteractionService(repo, logger.Logger)
 }
 
-func provideSearchService(meiliClient *meilisearch.Client, sceneRepo data.SceneRepository, interactionRepo data.InteractionRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, markerRepo data.MarkerRepository, logger *logging.Logger) *core.SearchService {
-	return core.NewSearchService(meiliClient, sceneRepo, interactionRepo, tagRepo, actorRepo, markerRepo, logger.Logger)
+func provideSearchService(meiliClient *meilisearch.Client, textSearchRepo data.SceneTextSearchRepository, cfg *config.Config, sceneRepo data.SceneRepository, interactionRepo data.InteractionRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, markerRepo data.MarkerRepository, restrictionRepo data.ContentRestrictionRepository, studioRepo data.StudioRepository, logger *logging.Logger) *core.SearchService {
+	svc := core.NewSearchService(meiliClient, textSearchRepo, cfg.Search.Backend, sceneRepo, interactionRepo, tagRepo, actorRepo, markerRepo, logger.Logger)
+	svc.SetContentRestrictionRepository(restrictionRepo)
+	svc.SetStudioRepository(studioRepo)
+	return svc
+}
+
+func provideSearchReindexService(searchService *core.SearchService, sceneRepo data.SceneRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, checkpointRepo data.SearchReindexRepository, eventBus *core.EventBus, logger *logging.Logger) *core.SearchReindexService {
+	return core.NewSearchReindexService(searchService, sceneRepo, tagRepo, actorRepo, checkpointRepo, eventBus, logger.Logger)
+}
+
+func provideStorageHealthService(storagePathRepo data.StoragePathRepository, explorerRepo data.ExplorerRepository, healthRepo data.StorageHealthRepository, eventBus *core.EventBus, cfg *config.Config, logger *logging.Logger) *core.StorageHealthService {
+	return core.NewStorageHealthService(storagePathRepo, explorerRepo, healthRepo, eventBus, cfg.Scan.HealthCheckInterval, cfg.Scan.HealthHistoryRetention, logger.Logger)
+}
+
+func provideSearchConsistencyService(searchService *core.SearchService, reindexService *core.SearchReindexService, sceneRepo data.SceneRepository, cfg *config.Config, logger *logging.Logger) *core.SearchConsistencyService {
+	return core.NewSearchConsistencyService(searchService, reindexService, sceneRepo, cfg.Meilisearch.ConsistencyCheckInterval, cfg.Meilisearch.ConsistencyAutoHeal, logger.Logger)
 }
 
 func provideWatchHistoryService(repo data.WatchHistoryRepository, sceneRepo data.SceneRepository, searchService *core.SearchService, logger *logging.Logger) *core.WatchHistoryService {
@@ -360,33 +704,53 @@ func provideRelatedScenesService(
 	actorInteractionRepo data.ActorInteractionRepository,
 	studioInteractionRepo data.StudioInteractionRepository,
 	watchHistoryRepo data.WatchHistoryRepository,
+	rbac *core.RBACService,
 	logger *logging.Logger,
 ) *core.RelatedScenesService {
-	return core.NewRelatedScenesService(sceneRepo, tagRepo, actorRepo, studioRepo, actorInteractionRepo, studioInteractionRepo, watchHistoryRepo, logger.Logger)
+	svc := core.NewRelatedScenesService(sceneRepo, tagRepo, actorRepo, studioRepo, actorInteractionRepo, studioInteractionRepo, watchHistoryRepo, logger.Logger)
+	svc.SetRBACService(rbac)
+	return svc
 }
 
 func provideSceneProcessingService(repo data.SceneRepository, markerService *core.MarkerService, cfg *config.Config, logger *logging.Logger, eventBus *core.EventBus, jobHistory *core.JobHistoryService, poolConfigRepo data.PoolConfigRepository, processingConfigRepo data.ProcessingConfigRepository, triggerConfigRepo data.TriggerConfigRepository) *core.SceneProcessingService {
 	return core.NewSceneProcessingService(repo, markerService, cfg.Processing, logger.Logger, eventBus, jobHistory, poolConfigRepo, processingConfigRepo, triggerConfigRepo)
 }
 
-func provideJobHistoryService(repo data.JobHistoryRepository, cfg *config.Config, logger *logging.Logger) *core.JobHistoryService {
-	return core.NewJobHistoryService(repo, cfg.Processing, logger.Logger)
+func provideJobHistoryService(repo data.JobHistoryRepository, logRepo data.JobLogRepository, cfg *config.Config, logger *logging.Logger) *core.JobHistoryService {
+	service := core.NewJobHistoryService(repo, cfg.Processing, logger.Logger)
+	service.SetLogRepository(logRepo)
+	return service
+}
+
+func provideJobHistoryRetentionWorker(repo data.JobHistoryRepository, cfg *config.Config, logger *logging.Logger) *core.JobHistoryRetentionWorker {
+	return core.NewJobHistoryRetentionWorker(repo, cfg.Processing, logger.Logger)
+}
+
+func providePoolAutoscaler(cfg *config.Config, processingService *core.SceneProcessingService, jobHistoryRepo data.JobHistoryRepository, logger *logging.Logger) *core.PoolAutoscaler {
+	return core.NewPoolAutoscaler(cfg.Processing.Autoscale, processingService, jobHistoryRepo, logger.Logger)
 }
 
 func provideJobStatusService(jobHistoryService *core.JobHistoryService, processingService *core.SceneProcessingService, logger *logging.Logger) *core.JobStatusService {
 	return core.NewJobStatusService(jobHistoryService, processingService, logger.Logger)
 }
 
-func provideJobQueueFeeder(jobHistoryRepo data.JobHistoryRepository, sceneRepo data.SceneRepository, markerService *core.MarkerService, processingService *core.SceneProcessingService, logger *logging.Logger) *core.JobQueueFeeder {
-	return core.NewJobQueueFeeder(jobHistoryRepo, sceneRepo, markerService, markerService, processingService.GetPoolManager(), logger.Logger)
+func provideJobQueueFeeder(jobHistoryRepo data.JobHistoryRepository, sceneRepo data.SceneRepository, markerService *core.MarkerService, integrityRepo data.SceneIntegrityRepository, processingService *core.SceneProcessingService, eventBus *core.EventBus, logger *logging.Logger) *core.JobQueueFeeder {
+	return core.NewJobQueueFeeder(jobHistoryRepo, sceneRepo, markerService, markerService, integrityRepo, processingService.GetPoolManager(), eventBus, logger.Logger)
 }
 
 func provideTriggerScheduler(triggerConfigRepo data.TriggerConfigRepository, sceneRepo data.SceneRepository, processingService *core.SceneProcessingService, logger *logging.Logger) *core.TriggerScheduler {
 	return core.NewTriggerScheduler(triggerConfigRepo, sceneRepo, processingService, logger.Logger)
 }
 
-func provideRetryScheduler(jobHistoryRepo data.JobHistoryRepository, dlqRepo data.DLQRepository, retryConfigRepo data.RetryConfigRepository, sceneRepo data.SceneRepository, eventBus *core.EventBus, logger *logging.Logger) *core.RetryScheduler {
-	return core.NewRetryScheduler(jobHistoryRepo, dlqRepo, retryConfigRepo, sceneRepo, eventBus, logger.Logger)
+func provideRetryScheduler(jobHistoryRepo data.JobHistoryRepository, dlqRepo data.DLQRepository, retryConfigRepo data.RetryConfigRepository, sceneRepo data.SceneRepository, eventBus *core.EventBus, cfg *config.Config, logger *logging.Logger) *core.RetryScheduler {
+	scheduler := core.NewRetryScheduler(jobHistoryRepo, dlqRepo, retryConfigRepo, sceneRepo, eventBus, logger.Logger)
+	retention, err := config.ParseRetentionDuration(cfg.Processing.DLQRetention)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("failed to parse dlq_retention %q, keeping abandoned DLQ entries: %v", cfg.Processing.DLQRetention, err))
+		retention = 0
+	}
+	scheduler.SetDLQRetention(retention)
+	return scheduler
 }
 
 func provideDLQService(dlqRepo data.DLQRepository, jobHistoryRepo data.JobHistoryRepository, sceneRepo data.SceneRepository, eventBus *core.EventBus, logger *logging.Logger) *core.DLQService {
@@ -397,20 +761,96 @@ func provideStoragePathService(repo data.StoragePathRepository, logger *logging.
 	return core.NewStoragePathService(repo, logger.Logger)
 }
 
-func provideScanService(storagePathService *core.StoragePathService, sceneRepo data.SceneRepository, scanHistoryRepo data.ScanHistoryRepository, processingService *core.SceneProcessingService, eventBus *core.EventBus, logger *logging.Logger) *core.ScanService {
-	return core.NewScanService(storagePathService, sceneRepo, scanHistoryRepo, processingService, eventBus, logger.Logger)
+func provideSidecarMetadataService(cfg *config.Config, sceneRepo data.SceneRepository, studioRepo data.StudioRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, logger *logging.Logger) *core.SidecarMetadataService {
+	return core.NewSidecarMetadataService(cfg.Scan.Sidecar, sceneRepo, studioRepo, tagRepo, actorRepo, logger.Logger)
+}
+
+func provideFolderRuleService(folderRuleRepo data.FolderRuleRepository, storagePathRepo data.StoragePathRepository, explorerRepo data.ExplorerRepository, sceneRepo data.SceneRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, studioRepo data.StudioRepository, eventBus *core.EventBus, logger *logging.Logger) *core.FolderRuleService {
+	return core.NewFolderRuleService(folderRuleRepo, storagePathRepo, explorerRepo, sceneRepo, tagRepo, actorRepo, studioRepo, eventBus, logger.Logger)
+}
+
+func provideScanService(storagePathService *core.StoragePathService, sceneRepo data.SceneRepository, scanHistoryRepo data.ScanHistoryRepository, scanReportRepo data.ScanReportRepository, scanConfigRepo data.ScanConfigRepository, sidecarService *core.SidecarMetadataService, folderRuleService *core.FolderRuleService, processingService *core.SceneProcessingService, eventBus *core.EventBus, logger *logging.Logger) *core.ScanService {
+	return core.NewScanService(storagePathService, sceneRepo, scanHistoryRepo, scanReportRepo, scanConfigRepo, sidecarService, folderRuleService, processingService, eventBus, logger.Logger)
+}
+
+func provideExplorerService(explorerRepo data.ExplorerRepository, storagePathRepo data.StoragePathRepository, sceneRepo data.SceneRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, studioRepo data.StudioRepository, jobHistoryRepo data.JobHistoryRepository, eventBus *core.EventBus, logger *logging.Logger, cfg *config.Config, deletionGuard *core.DeletionGuard, rbac *core.RBACService) *core.ExplorerService {
+	svc := core.NewExplorerService(explorerRepo, storagePathRepo, sceneRepo, tagRepo, actorRepo, studioRepo, jobHistoryRepo, eventBus, logger.Logger, cfg.Processing.MetadataDir, deletionGuard)
+	svc.SetRBACService(rbac)
+	return svc
+}
+
+func providePornDBService(cfg *config.Config, cacheRepo data.PornDBCacheRepository, logger *logging.Logger) *core.PornDBService {
+	return core.NewPornDBService(cfg.PornDB, cacheRepo, logger.Logger)
+}
+
+func provideStashDBService(cfg *config.Config, logger *logging.Logger) *core.StashDBService {
+	return core.NewStashDBService(cfg.StashDB, logger.Logger)
+}
+
+func provideMetadataProviders(pornDBService *core.PornDBService, stashDBService *core.StashDBService) *core.MetadataProviders {
+	return core.NewMetadataProviders(pornDBService, stashDBService)
+}
+
+func provideActorSyncService(actorRepo data.ActorRepository, syncRepo data.ActorSyncRepository, pornDBService *core.PornDBService, cfg *config.Config, logger *logging.Logger) *core.ActorSyncService {
+	return core.NewActorSyncService(actorRepo, syncRepo, pornDBService, cfg.PornDB.PerformerSyncInterval, logger.Logger)
+}
+
+func providePornDBMatchService(matchRepo data.PornDBMatchRepository, sceneRepo data.SceneRepository, providers *core.MetadataProviders, sceneService *core.SceneService, jobHistoryService *core.JobHistoryService, logger *logging.Logger) *core.PornDBMatchService {
+	return core.NewPornDBMatchService(matchRepo, sceneRepo, providers, sceneService, jobHistoryService, logger.Logger)
+}
+
+func provideScanScheduler(storagePathRepo data.StoragePathRepository, scanService *core.ScanService, logger *logging.Logger) *core.ScanScheduler {
+	return core.NewScanScheduler(storagePathRepo, scanService, logger.Logger)
+}
+
+func provideUploadService(repo data.UploadSessionRepository, sceneService *core.SceneService, cfg *config.Config, logger *logging.Logger) *core.UploadService {
+	return core.NewUploadService(repo, sceneService, filepath.Join(cfg.Processing.VideoDir, core.UploadDirName), cfg.Processing.UploadSessionTTL, logger.Logger)
 }
 
-func provideExplorerService(explorerRepo data.ExplorerRepository, storagePathRepo data.StoragePathRepository, sceneRepo data.SceneRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, jobHistoryRepo data.JobHistoryRepository, eventBus *core.EventBus, logger *logging.Logger, cfg *config.Config) *core.ExplorerService {
-	return core.NewExplorerService(explorerRepo, storagePathRepo, sceneRepo, tagRepo, actorRepo, jobHistoryRepo, eventBus, logger.Logger, cfg.Processing.MetadataDir)
+func provideRemoteImportService(repo data.RemoteImportRepository, storagePathService *core.StoragePathService, scanService *core.ScanService, sceneRepo data.SceneRepository, jobHistoryService *core.JobHistoryService, eventBus *core.EventBus, cfg *config.Config, logger *logging.Logger) *core.RemoteImportService {
+	return core.NewRemoteImportService(cfg.Scan.RemoteImport, repo, storagePathService, scanService, sceneRepo, jobHistoryService, eventBus, logger.Logger)
 }
 
-func providePornDBService(cfg *config.Config, logger *logging.Logger) *core.PornDBService {
-	return core.NewPornDBService(cfg.PornDB.APIKey, logger.Logger)
+func provideTorrentImportService(repo data.TorrentImportRepository, storagePathService *core.StoragePathService, scanService *core.ScanService, sceneRepo data.SceneRepository, cfg *config.Config, logger *logging.Logger) *core.TorrentImportService {
+	return core.NewTorrentImportService(cfg.Scan.Torrent, repo, storagePathService, scanService, sceneRepo, logger.Logger)
 }
 
-func provideSavedSearchService(repo data.SavedSearchRepository, logger *logging.Logger) *core.SavedSearchService {
-	return core.NewSavedSearchService(repo, logger.Logger)
+func provideStorageMigrationService(sceneRepo data.SceneRepository, storagePathService *core.StoragePathService, explorerService *core.ExplorerService, jobHistoryService *core.JobHistoryService, eventBus *core.EventBus, logger *logging.Logger) *core.StorageMigrationService {
+	return core.NewStorageMigrationService(sceneRepo, storagePathService, explorerService, jobHistoryService, eventBus, logger.Logger)
+}
+
+func provideDropboxImportService(storagePathService *core.StoragePathService, scanService *core.ScanService, sidecars *core.SidecarMetadataService, cfg *config.Config, logger *logging.Logger) *core.DropboxImportService {
+	return core.NewDropboxImportService(cfg.Scan.Dropbox, storagePathService, scanService, sidecars, logger.Logger)
+}
+
+func provideStorageWatcherService(storagePathService *core.StoragePathService, scanService *core.ScanService, cfg *config.Config, logger *logging.Logger) *core.StorageWatcherService {
+	return core.NewStorageWatcherService(storagePathService, scanService, cfg.Scan.WatchEnabled, cfg.Scan.WatchDebounce, logger.Logger)
+}
+
+func provideSavedSearchService(repo data.SavedSearchRepository, tagRepo data.TagRepository, sceneRepo data.SceneRepository, searchService *core.SearchService, eventBus *core.EventBus, cfg *config.Config, rbac *core.RBACService, logger *logging.Logger) *core.SavedSearchService {
+	svc := core.NewSavedSearchService(repo, tagRepo, sceneRepo, searchService, eventBus, cfg.Search.SavedSearchWatchInterval, logger.Logger)
+	svc.SetRBACService(rbac)
+	return svc
+}
+
+func provideWebhookService(repo data.WebhookRepository, eventBus *core.EventBus, logger *logging.Logger) *core.WebhookService {
+	return core.NewWebhookService(repo, eventBus, logger.Logger)
+}
+
+func provideScraperRegistry(cfg *config.Config, logger *logging.Logger) *scraper.Registry {
+	return scraper.NewRegistry(scraper.Options{
+		Dir:       cfg.Scrapers.Dir,
+		Timeout:   cfg.Scrapers.Timeout,
+		UserAgent: cfg.Scrapers.UserAgent,
+	}, logger.Logger)
+}
+
+func provideSceneScrapeService(registry *scraper.Registry, sceneService *core.SceneService, tagService *core.TagService, actorService *core.ActorService, actorRepo data.ActorRepository, logger *logging.Logger) *core.SceneScrapeService {
+	return core.NewSceneScrapeService(registry, sceneService, tagService, actorService, actorRepo, logger.Logger)
+}
+
+func provideGraphQLService(sceneService *core.SceneService, searchService *core.SearchService, tagRepo data.TagRepository, actorRepo data.ActorRepository, studioRepo data.StudioRepository, markerRepo data.MarkerRepository, logger *logging.Logger) *core.GraphQLService {
+	return core.NewGraphQLService(sceneService, searchService, tagRepo, actorRepo, studioRepo, markerRepo, logger.Logger)
 }
 
 func provideHomepageService(
@@ -424,9 +864,11 @@ func provideHomepageService(
 	tagRepo data.TagRepository,
 	actorRepo data.ActorRepository,
 	studioRepo data.StudioRepository,
+	reviewWorkflowService *core.ReviewWorkflowService,
+	rbac *core.RBACService,
 	logger *logging.Logger,
 ) *core.HomepageService {
-	return core.NewHomepageService(
+	svc := core.NewHomepageService(
 		settingsService,
 		searchService,
 		savedSearchService,
@@ -439,22 +881,103 @@ func provideHomepageService(
 		studioRepo,
 		logger.Logger,
 	)
+	svc.SetReviewWorkflowService(reviewWorkflowService)
+	svc.SetRBACService(rbac)
+	return svc
 }
 
-func provideMarkerService(markerRepo data.MarkerRepository, sceneRepo data.SceneRepository, tagRepo data.TagRepository, cfg *config.Config, logger *logging.Logger) *core.MarkerService {
-	return core.NewMarkerService(markerRepo, sceneRepo, tagRepo, cfg, logger.Logger)
+func provideMarkerService(markerRepo data.MarkerRepository, sceneRepo data.SceneRepository, tagRepo data.TagRepository, eventBus *core.EventBus, cfg *config.Config, rbac *core.RBACService, logger *logging.Logger) *core.MarkerService {
+	svc := core.NewMarkerService(markerRepo, sceneRepo, tagRepo, cfg, logger.Logger)
+	svc.SetEventBus(eventBus)
+	svc.SetRBACService(rbac)
+	return svc
+}
+
+func provideMarkerCompilationService(compilationRepo data.MarkerCompilationRepository, markerRepo data.MarkerRepository, sceneRepo data.SceneRepository, jobHistoryService *core.JobHistoryService, eventBus *core.EventBus, cfg *config.Config, logger *logging.Logger) *core.MarkerCompilationService {
+	return core.NewMarkerCompilationService(compilationRepo, markerRepo, sceneRepo, jobHistoryService, eventBus, cfg, logger.Logger)
+}
+
+func provideSceneHeatmapService(heatmapRepo data.HeatmapRepository, sceneRepo data.SceneRepository, cfg *config.Config, logger *logging.Logger) *core.SceneHeatmapService {
+	return core.NewSceneHeatmapService(heatmapRepo, sceneRepo, cfg.Processing.HeatmapInterval, logger.Logger)
 }
 
 func providePlaylistService(repo data.PlaylistRepository, sceneRepo data.SceneRepository, tagRepo data.TagRepository, logger *logging.Logger) *core.PlaylistService {
 	return core.NewPlaylistService(repo, sceneRepo, tagRepo, logger.Logger)
 }
 
-func provideShareService(shareLinkRepo data.ShareLinkRepository, sceneRepo data.SceneRepository, logger *logging.Logger) *core.ShareService {
-	return core.NewShareService(shareLinkRepo, sceneRepo, logger.Logger)
+func provideShareService(shareLinkRepo data.ShareLinkRepository, sceneRepo data.SceneRepository, cfg *config.Config, logger *logging.Logger) *core.ShareService {
+	return core.NewShareService(shareLinkRepo, sceneRepo, cfg.Auth.PasetoSecret, logger.Logger)
+}
+
+func provideDuplicateService(duplicateRepo data.DuplicateGroupRepository, sceneRepo data.SceneRepository, eventBus *core.EventBus, logger *logging.Logger) *core.DuplicateService {
+	return core.NewDuplicateService(duplicateRepo, sceneRepo, eventBus, logger.Logger)
+}
+
+func provideArtifactService(artifactRepo data.SceneArtifactRepository, sceneRepo data.SceneRepository, markerRepo data.MarkerRepository, cfg *config.Config, logger *logging.Logger) *core.ArtifactService {
+	return core.NewArtifactService(artifactRepo, sceneRepo, markerRepo, cfg.Processing, logger.Logger)
+}
+
+func provideDeletionGuard(interactionRepo data.InteractionRepository, markerRepo data.MarkerRepository, playlistRepo data.PlaylistRepository, cfg *config.Config, logger *logging.Logger) *core.DeletionGuard {
+	return core.NewDeletionGuard(interactionRepo, markerRepo, playlistRepo, cfg.DeletionProtection, logger.Logger)
+}
+
+func provideReleaseService(schemaRepo data.SchemaRepository, artifactRepo data.SceneArtifactRepository, logger *logging.Logger) *core.ReleaseService {
+	latest, err := migrator.LatestVersion()
+	if err != nil {
+		logger.Warn(fmt.Sprintf("failed to read embedded migration versions: %v", err))
+	}
+	return core.NewReleaseService(schemaRepo, artifactRepo, latest, logger.Logger)
+}
+
+func provideReviewWorkflowService(sceneRepo data.SceneRepository, cfg *config.Config, eventBus *core.EventBus, logger *logging.Logger) *core.ReviewWorkflowService {
+	return core.NewReviewWorkflowService(sceneRepo, cfg.ReviewWorkflow, eventBus, logger.Logger)
 }
 
-func provideStreamManager(cfg *config.Config, sceneRepo data.SceneRepository, logger *logging.Logger) *streaming.Manager {
-	return streaming.NewManager(&cfg.Streaming, sceneRepo, logger.Logger)
+func provideCastService(sceneRepo data.SceneRepository, appSettingsRepo data.AppSettingsRepository, mediaSigner *core.MediaSigner, cfg *config.Config, logger *logging.Logger) *core.CastService {
+	return core.NewCastService(sceneRepo, appSettingsRepo, mediaSigner, cfg.Auth.PasetoSecret, cfg.Casting, logger.Logger)
+}
+
+func provideMediaSigner(cfg *config.Config) *core.MediaSigner {
+	return core.NewMediaSigner(cfg.Auth.PasetoSecret, cfg.MediaSigning)
+}
+
+func provideSpriteFrameService(sceneRepo data.SceneRepository, cfg *config.Config, logger *logging.Logger) *core.SpriteFrameService {
+	return core.NewSpriteFrameService(sceneRepo, cfg.Processing.SpriteDir, cfg.Processing.FrameQualitySprites, logger.Logger)
+}
+
+func provideWatchPartyService(sceneRepo data.SceneRepository, cfg *config.Config, rbac *core.RBACService, logger *logging.Logger) *core.WatchPartyService {
+	svc := core.NewWatchPartyService(sceneRepo, cfg.WatchParty, logger.Logger)
+	svc.SetRBACService(rbac)
+	return svc
+}
+
+func provideAPIKeyService(apiKeyRepo data.APIKeyRepository, userRepo data.UserRepository, rbacService *core.RBACService, logger *logging.Logger) *core.APIKeyService {
+	return core.NewAPIKeyService(apiKeyRepo, userRepo, rbacService, logger.Logger)
+}
+
+func provideOfflineSyncService(syncRepo data.OfflineSyncRepository, sceneRepo data.SceneRepository, markerRepo data.MarkerRepository, mediaSigner *core.MediaSigner, rbac *core.RBACService, logger *logging.Logger) *core.OfflineSyncService {
+	svc := core.NewOfflineSyncService(syncRepo, sceneRepo, markerRepo, mediaSigner, logger.Logger)
+	svc.SetRBACService(rbac)
+	return svc
+}
+
+func provideDownloadService(sceneRepo data.SceneRepository, downloadRepo data.DownloadRepository, logger *logging.Logger) *core.DownloadService {
+	return core.NewDownloadService(sceneRepo, downloadRepo, logger.Logger)
+}
+
+func provideAgentService(cfg *config.Config, logger *logging.Logger) *core.AgentService {
+	return core.NewAgentService(cfg.Agents, logger.Logger)
+}
+
+func provideStreamManager(cfg *config.Config, sceneRepo data.SceneRepository, appSettingsRepo data.AppSettingsRepository, logger *logging.Logger) *streaming.Manager {
+	manager := streaming.NewManager(&cfg.Streaming, sceneRepo, logger.Logger)
+
+	if settings, err := appSettingsRepo.Get(); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to load streaming bandwidth limits, streams are unthrottled: %v", err))
+	} else {
+		manager.SetBandwidthLimits(streaming.BandwidthLimitsFromSettings(settings))
+	}
+	return manager
 }
 
 func provideRateLimiter(cfg *config.Config) *middleware.IPRateLimiter {
@@ -462,36 +985,39 @@ func provideRateLimiter(cfg *config.Config) *middleware.IPRateLimiter {
 	return middleware.NewIPRateLimiter(rl, cfg.Auth.LoginRateBurst)
 }
 
-func provideOGMiddleware(sceneRepo data.SceneRepository, actorRepo data.ActorRepository, studioRepo data.StudioRepository, playlistRepo data.PlaylistRepository, shareLinkRepo data.ShareLinkRepository, appSettingsRepo data.AppSettingsRepository, logger *logging.Logger) *middleware.OGMiddleware {
-	return middleware.NewOGMiddleware(sceneRepo, actorRepo, studioRepo, playlistRepo, shareLinkRepo, appSettingsRepo, logger)
+func provideOGMiddleware(sceneRepo data.SceneRepository, actorRepo data.ActorRepository, studioRepo data.StudioRepository, playlistRepo data.PlaylistRepository, shareLinkRepo data.ShareLinkRepository, appSettingsRepo data.AppSettingsRepository, mediaSigner *core.MediaSigner, logger *logging.Logger) *middleware.OGMiddleware {
+	return middleware.NewOGMiddleware(sceneRepo, actorRepo, studioRepo, playlistRepo, shareLinkRepo, appSettingsRepo, mediaSigner, logger)
 }
 
-func provideAuthHandler(authService *core.AuthService, userService *core.UserService, cfg *config.Config) *handler.AuthHandler {
+func provideAuthHandler(authService *core.AuthService, userService *core.UserService, privacyLockService *core.PrivacyLockService, auditService *core.AuditService, sessionService *core.SessionService, cfg *config.Config) *handler.AuthHandler {
 	secureCookies := cfg.Environment == "production"
 	if cfg.Server.SecureCookies != nil {
 		secureCookies = *cfg.Server.SecureCookies
 	}
-	return handler.NewAuthHandlerWithConfig(authService, userService, cfg.Auth.TokenDuration, secureCookies)
+	authHandler := handler.NewAuthHandlerWithConfig(authService, userService, privacyLockService, cfg.Auth.TokenDuration, secureCookies)
+	authHandler.AuditService = auditService
+	authHandler.SessionService = sessionService
+	return authHandler
 }
 
-func provideAdminHandler(adminService *core.AdminService, rbacService *core.RBACService, sceneService *core.SceneService, appSettingsRepo data.AppSettingsRepository) *handler.AdminHandler {
-	return handler.NewAdminHandler(adminService, rbacService, sceneService, appSettingsRepo)
+func provideAdminHandler(adminService *core.AdminService, rbacService *core.RBACService, sceneService *core.SceneService, appSettingsRepo data.AppSettingsRepository, streamManager *streaming.Manager) *handler.AdminHandler {
+	return handler.NewAdminHandler(adminService, rbacService, sceneService, appSettingsRepo, streamManager)
 }
 
 func provideSettingsHandler(settingsService *core.SettingsService, cfg *config.Config) *handler.SettingsHandler {
 	return handler.NewSettingsHandler(settingsService, cfg.Pagination.MaxItemsPerPage)
 }
 
-func provideSceneHandler(service *core.SceneService, processingService *core.SceneProcessingService, tagService *core.TagService, searchService *core.SearchService, relatedScenesService *core.RelatedScenesService, markerService *core.MarkerService, streamManager *streaming.Manager, interactionRepo data.InteractionRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, cfg *config.Config) *handler.SceneHandler {
-	return handler.NewSceneHandler(service, processingService, tagService, searchService, relatedScenesService, markerService, streamManager, interactionRepo, tagRepo, actorRepo, cfg.Pagination.MaxItemsPerPage)
+func provideSceneHandler(service *core.SceneService, processingService *core.SceneProcessingService, tagService *core.TagService, searchService *core.SearchService, relatedScenesService *core.RelatedScenesService, markerService *core.MarkerService, streamManager *streaming.Manager, interactionRepo data.InteractionRepository, tagRepo data.TagRepository, actorRepo data.ActorRepository, reviewWorkflowService *core.ReviewWorkflowService, castService *core.CastService, spriteFrameService *core.SpriteFrameService, mediaSigner *core.MediaSigner, cfg *config.Config) *handler.SceneHandler {
+	return handler.NewSceneHandler(service, processingService, tagService, searchService, relatedScenesService, markerService, streamManager, interactionRepo, tagRepo, actorRepo, reviewWorkflowService, castService, spriteFrameService, mediaSigner, cfg.Pagination.MaxItemsPerPage)
 }
 
 func provideTagHandler(tagService *core.TagService) *handler.TagHandler {
 	return handler.NewTagHandler(tagService)
 }
 
-func provideActorHandler(actorService *core.ActorService, cfg *config.Config) *handler.ActorHandler {
-	return handler.NewActorHandler(actorService, cfg.Processing.ActorImageDir, cfg.Pagination.MaxItemsPerPage)
+func provideActorHandler(actorService *core.ActorService, actorImageService *core.ActorImageService, cfg *config.Config) *handler.ActorHandler {
+	return handler.NewActorHandler(actorService, actorImageService, cfg.Processing.ActorImageDir, cfg.Pagination.MaxItemsPerPage)
 }
 
 func provideStudioHandler(studioService *core.StudioService, cfg *config.Config) *handler.StudioHandler {
@@ -510,20 +1036,20 @@ func provideStudioInteractionHandler(service *core.StudioInteractionService, stu
 	return handler.NewStudioInteractionHandler(service, studioRepo)
 }
 
-func provideSearchHandler(searchService *core.SearchService, searchConfigRepo data.SearchConfigRepository) *handler.SearchHandler {
-	return handler.NewSearchHandler(searchService, searchConfigRepo)
+func provideSearchHandler(searchService *core.SearchService, searchReindexService *core.SearchReindexService, searchConsistencyService *core.SearchConsistencyService, searchConfigRepo data.SearchConfigRepository) *handler.SearchHandler {
+	return handler.NewSearchHandler(searchService, searchReindexService, searchConsistencyService, searchConfigRepo)
 }
 
 func provideWatchHistoryHandler(service *core.WatchHistoryService) *handler.WatchHistoryHandler {
 	return handler.NewWatchHistoryHandler(service)
 }
 
-func provideJobHandler(jobHistoryService *core.JobHistoryService, processingService *core.SceneProcessingService) *handler.JobHandler {
-	return handler.NewJobHandler(jobHistoryService, processingService)
+func provideJobHandler(jobHistoryService *core.JobHistoryService, processingService *core.SceneProcessingService, remoteImportService *core.RemoteImportService, storageMigrationService *core.StorageMigrationService, jobQueueFeeder *core.JobQueueFeeder, retentionWorker *core.JobHistoryRetentionWorker) *handler.JobHandler {
+	return handler.NewJobHandler(jobHistoryService, processingService, remoteImportService, storageMigrationService, jobQueueFeeder, retentionWorker)
 }
 
-func providePoolConfigHandler(processingService *core.SceneProcessingService, poolConfigRepo data.PoolConfigRepository) *handler.PoolConfigHandler {
-	return handler.NewPoolConfigHandler(processingService, poolConfigRepo)
+func providePoolConfigHandler(processingService *core.SceneProcessingService, poolConfigRepo data.PoolConfigRepository, autoscaler *core.PoolAutoscaler) *handler.PoolConfigHandler {
+	return handler.NewPoolConfigHandler(processingService, poolConfigRepo, autoscaler)
 }
 
 func provideProcessingConfigHandler(processingService *core.SceneProcessingService, processingConfigRepo data.ProcessingConfigRepository, markerService *core.MarkerService) *handler.ProcessingConfigHandler {
@@ -546,20 +1072,20 @@ func provideSSEHandler(eventBus *core.EventBus, authService *core.AuthService, j
 	return handler.NewSSEHandler(eventBus, authService, jobStatusService, logger.Logger)
 }
 
-func provideStoragePathHandler(service *core.StoragePathService) *handler.StoragePathHandler {
-	return handler.NewStoragePathHandler(service)
+func provideStoragePathHandler(service *core.StoragePathService, scanScheduler *core.ScanScheduler, healthService *core.StorageHealthService) *handler.StoragePathHandler {
+	return handler.NewStoragePathHandler(service, scanScheduler, healthService)
 }
 
-func provideScanHandler(scanService *core.ScanService) *handler.ScanHandler {
-	return handler.NewScanHandler(scanService)
+func provideScanHandler(scanService *core.ScanService, folderRuleService *core.FolderRuleService, dropboxService *core.DropboxImportService) *handler.ScanHandler {
+	return handler.NewScanHandler(scanService, folderRuleService, dropboxService)
 }
 
-func provideExplorerHandler(explorerService *core.ExplorerService) *handler.ExplorerHandler {
-	return handler.NewExplorerHandler(explorerService)
+func provideExplorerHandler(explorerService *core.ExplorerService, storageMigrationService *core.StorageMigrationService) *handler.ExplorerHandler {
+	return handler.NewExplorerHandler(explorerService, storageMigrationService)
 }
 
-func providePornDBHandler(pornDBService *core.PornDBService) *handler.PornDBHandler {
-	return handler.NewPornDBHandler(pornDBService)
+func providePornDBHandler(pornDBService *core.PornDBService, providers *core.MetadataProviders, matchService *core.PornDBMatchService, actorSyncService *core.ActorSyncService) *handler.PornDBHandler {
+	return handler.NewPornDBHandler(pornDBService, providers, matchService, actorSyncService)
 }
 
 func provideSavedSearchHandler(service *core.SavedSearchService) *handler.SavedSearchHandler {
@@ -570,8 +1096,8 @@ func provideHomepageHandler(homepageService *core.HomepageService) *handler.Home
 	return handler.NewHomepageHandler(homepageService)
 }
 
-func provideMarkerHandler(markerService *core.MarkerService, cfg *config.Config) *handler.MarkerHandler {
-	return handler.NewMarkerHandler(markerService, cfg.Pagination.MaxItemsPerPage)
+func provideMarkerHandler(markerService *core.MarkerService, compilationService *core.MarkerCompilationService, cfg *config.Config) *handler.MarkerHandler {
+	return handler.NewMarkerHandler(markerService, compilationService, cfg.Pagination.MaxItemsPerPage)
 }
 
 func providePlaylistHandler(service *core.PlaylistService, cfg *config.Config) *handler.PlaylistHandler {
@@ -586,8 +1112,88 @@ func provideStreamStatsHandler(streamManager *streaming.Manager) *handler.Stream
 	return handler.NewStreamStatsHandler(streamManager)
 }
 
+func provideRequestStatsHandler(requestStatsService *core.RequestStatsService) *handler.RequestStatsHandler {
+	return handler.NewRequestStatsHandler(requestStatsService)
+}
+
+func provideArtifactHandler(artifactService *core.ArtifactService) *handler.ArtifactHandler {
+	return handler.NewArtifactHandler(artifactService)
+}
+
+func provideReleaseHandler(releaseService *core.ReleaseService) *handler.ReleaseHandler {
+	return handler.NewReleaseHandler(releaseService)
+}
+
+func provideAPIUsageHandler(apiUsageService *core.APIUsageService) *handler.APIUsageHandler {
+	return handler.NewAPIUsageHandler(apiUsageService)
+}
+
+func provideDownloadHandler(downloadService *core.DownloadService, sceneService *core.SceneService, streamManager *streaming.Manager) *handler.DownloadHandler {
+	return handler.NewDownloadHandler(downloadService, sceneService, streamManager)
+}
+
+func provideReviewWorkflowHandler(reviewWorkflowService *core.ReviewWorkflowService) *handler.ReviewWorkflowHandler {
+	return handler.NewReviewWorkflowHandler(reviewWorkflowService)
+}
+
+func provideDuplicateHandler(duplicateService *core.DuplicateService) *handler.DuplicateHandler {
+	return handler.NewDuplicateHandler(duplicateService)
+}
+
 func provideShareHandler(shareService *core.ShareService, authService *core.AuthService, streamManager *streaming.Manager, cfg *config.Config) *handler.ShareHandler {
-	return handler.NewShareHandler(shareService, authService, streamManager, cfg.Sharing.BaseURL)
+	secureCookies := cfg.Environment == "production"
+	if cfg.Server.SecureCookies != nil {
+		secureCookies = *cfg.Server.SecureCookies
+	}
+	return handler.NewShareHandler(shareService, authService, streamManager, cfg.Sharing.BaseURL, cfg.Processing.ThumbnailDir, secureCookies)
+}
+
+func provideAgentHandler(agentService *core.AgentService) *handler.AgentHandler {
+	return handler.NewAgentHandler(agentService)
+}
+
+func provideWatchPartyHandler(watchPartyService *core.WatchPartyService, logger *logging.Logger) *handler.WatchPartyHandler {
+	return handler.NewWatchPartyHandler(watchPartyService, logger.Logger)
+}
+
+func provideOfflineSyncHandler(offlineSyncService *core.OfflineSyncService) *handler.OfflineSyncHandler {
+	return handler.NewOfflineSyncHandler(offlineSyncService)
+}
+
+func provideAPIKeyHandler(apiKeyService *core.APIKeyService, rbacService *core.RBACService) *handler.APIKeyHandler {
+	return handler.NewAPIKeyHandler(apiKeyService, rbacService)
+}
+
+func provideAuditHandler(auditService *core.AuditService) *handler.AuditHandler {
+	return handler.NewAuditHandler(auditService)
+}
+
+func provideRegistrationHandler(registrationService *core.RegistrationService, auditService *core.AuditService) *handler.RegistrationHandler {
+	return handler.NewRegistrationHandler(registrationService, auditService)
+}
+
+func provideWebhookHandler(webhookService *core.WebhookService) *handler.WebhookHandler {
+	return handler.NewWebhookHandler(webhookService)
+}
+
+func provideRemoteImportHandler(remoteImportService *core.RemoteImportService) *handler.RemoteImportHandler {
+	return handler.NewRemoteImportHandler(remoteImportService)
+}
+
+func provideTorrentImportHandler(torrentImportService *core.TorrentImportService) *handler.TorrentImportHandler {
+	return handler.NewTorrentImportHandler(torrentImportService)
+}
+
+func provideUploadHandler(uploadService *core.UploadService, sceneService *core.SceneService) *handler.UploadHandler {
+	return handler.NewUploadHandler(uploadService, sceneService)
+}
+
+func provideScraperHandler(scrapeService *core.SceneScrapeService) *handler.ScraperHandler {
+	return handler.NewScraperHandler(scrapeService)
+}
+
+func provideGraphQLHandler(graphQLService *core.GraphQLService) *handler.GraphQLHandler {
+	return handler.NewGraphQLHandler(graphQLService)
 }
 
 func provideRouter(
@@ -621,12 +1227,37 @@ func provideRouter(
 	markerHandler *handler.MarkerHandler,
 	importHandler *handler.ImportHandler,
 	streamStatsHandler *handler.StreamStatsHandler,
+	requestStatsHandler *handler.RequestStatsHandler,
+	duplicateHandler *handler.DuplicateHandler,
+	artifactHandler *handler.ArtifactHandler,
+	releaseHandler *handler.ReleaseHandler,
+	apiUsageHandler *handler.APIUsageHandler,
+	downloadHandler *handler.DownloadHandler,
+	reviewWorkflowHandler *handler.ReviewWorkflowHandler,
 	playlistHandler *handler.PlaylistHandler,
 	shareHandler *handler.ShareHandler,
+	agentHandler *handler.AgentHandler,
+	watchPartyHandler *handler.WatchPartyHandler,
+	offlineSyncHandler *handler.OfflineSyncHandler,
+	apiKeyHandler *handler.APIKeyHandler,
+	auditHandler *handler.AuditHandler,
+	registrationHandler *handler.RegistrationHandler,
+	graphQLHandler *handler.GraphQLHandler,
+	webhookHandler *handler.WebhookHandler,
+	scraperHandler *handler.ScraperHandler,
+	uploadHandler *handler.UploadHandler,
+	remoteImportHandler *handler.RemoteImportHandler,
+	torrentImportHandler *handler.TorrentImportHandler,
 	authService *core.AuthService,
 	rbacService *core.RBACService,
+	privacyLockService *core.PrivacyLockService,
+	requestStatsService *core.RequestStatsService,
+	apiUsageService *core.APIUsageService,
+	auditService *core.AuditService,
+	shareService *core.ShareService,
 	rateLimiter *middleware.IPRateLimiter,
 	ogMiddleware *middleware.OGMiddleware,
+	mediaSigner *core.MediaSigner,
 ) *gin.Engine {
 	return api.NewRouter(
 		logger, cfg,
@@ -635,7 +1266,8 @@ func provideRouter(
 		dlqHandler, retryConfigHandler, sseHandler, tagHandler, actorHandler, studioHandler, interactionHandler,
 		actorInteractionHandler, studioInteractionHandler, searchHandler, watchHistoryHandler, storagePathHandler, scanHandler,
 		explorerHandler, pornDBHandler, savedSearchHandler, homepageHandler, markerHandler, importHandler, streamStatsHandler,
-		playlistHandler, shareHandler, authService, rbacService, rateLimiter, ogMiddleware,
+		requestStatsHandler, duplicateHandler, artifactHandler, releaseHandler, apiUsageHandler, downloadHandler, reviewWorkflowHandler, playlistHandler, shareHandler, agentHandler, watchPartyHandler, offlineSyncHandler, apiKeyHandler, auditHandler, registrationHandler, graphQLHandler, webhookHandler, scraperHandler, uploadHandler, remoteImportHandler, torrentImportHandler, authService, rbacService, privacyLockService, requestStatsService, apiUsageService, auditService, shareService,
+		rateLimiter, ogMiddleware, mediaSigner,
 	)
 }
 
@@ -643,12 +1275,17 @@ func provideShareServer(
 	cfg *config.Config,
 	shareHandler *handler.ShareHandler,
 	ogMiddleware *middleware.OGMiddleware,
+	requestStatsService *core.RequestStatsService,
+	shareService *core.ShareService,
+	authService *core.AuthService,
+	mediaSigner *core.MediaSigner,
+	rateLimiter *middleware.IPRateLimiter,
 	logger *logging.Logger,
 ) *server.ShareServer {
 	if cfg.Sharing.Port == "" {
 		return nil
 	}
-	router := api.NewShareRouter(cfg, shareHandler, ogMiddleware, logger)
+	router := api.NewShareRouter(cfg, shareHandler, ogMiddleware, requestStatsService, shareService, authService, mediaSigner, rateLimiter, logger)
 	return server.NewShareServer(router, cfg.Sharing.Port, cfg, logger)
 }
 
@@ -672,11 +1309,37 @@ func provideServer(
 	actorService *core.ActorService,
 	studioService *core.StudioService,
 	shareServer *server.ShareServer,
+	agentService *core.AgentService,
+	artifactService *core.ArtifactService,
+	apiUsageService *core.APIUsageService,
+	searchConsistencyService *core.SearchConsistencyService,
+	markerService *core.MarkerService,
+	savedSearchService *core.SavedSearchService,
+	storageWatcher *core.StorageWatcherService,
+	scanScheduler *core.ScanScheduler,
+	folderRuleService *core.FolderRuleService,
+	compilationService *core.MarkerCompilationService,
+	heatmapService *core.SceneHeatmapService,
+	duplicateService *core.DuplicateService,
+	auditService *core.AuditService,
+	webhookService *core.WebhookService,
+	actorSyncService *core.ActorSyncService,
+	storageHealthService *core.StorageHealthService,
+	dropboxService *core.DropboxImportService,
+	remoteImportService *core.RemoteImportService,
+	torrentImportService *core.TorrentImportService,
+	storageMigrationService *core.StorageMigrationService,
+	jobRetention *core.JobHistoryRetentionWorker,
+	poolAutoscaler *core.PoolAutoscaler,
 ) *server.Server {
 	return server.NewHTTPServer(
 		router, logger, cfg,
 		processingService, userService, jobHistoryService, jobHistoryRepo, jobQueueFeeder, triggerScheduler,
 		sceneService, tagService, searchService, scanService, explorerService, retryScheduler, dlqService,
-		actorService, studioService, shareServer,
+		actorService, studioService, shareServer, agentService, artifactService, apiUsageService,
+		searchConsistencyService, markerService, savedSearchService, storageWatcher, scanScheduler, folderRuleService,
+		compilationService, heatmapService, duplicateService, auditService, webhookService, actorSyncService,
+		storageHealthService, dropboxService, remoteImportService, torrentImportService,
+		storageMigrationService, jobRetention, poolAutoscaler,
 	)
 }
@@ -0,0 +1,158 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// bifMagic opens every BIF (Roku/Plex trickplay) file.
+var bifMagic = []byte{0x89, 0x42, 0x49, 0x46, 0x0d, 0x0a, 0x1a, 0x0a}
+
+const bifHeaderSize = 64
+
+// WriteBif writes JPEG frames as a BIF trickplay file. Frame i is shown from
+// i*intervalMs milliseconds into the video.
+func WriteBif(w io.Writer, intervalMs int, frames [][]byte) error {
+	header := make([]byte, bifHeaderSize)
+	copy(header, bifMagic)
+	binary.LittleEndian.PutUint32(header[8:], 0) // version
+	binary.LittleEndian.PutUint32(header[12:], uint32(len(frames)))
+	binary.LittleEndian.PutUint32(header[16:], uint32(intervalMs))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	// Index: one (timestamp, offset) pair per frame plus a terminating entry
+	// whose offset marks the end of the last frame
+	index := make([]byte, (len(frames)+1)*8)
+	offset := uint32(bifHeaderSize + len(index))
+	for i, frame := range frames {
+		binary.LittleEndian.PutUint32(index[i*8:], uint32(i))
+		binary.LittleEndian.PutUint32(index[i*8+4:], offset)
+		offset += uint32(len(frame))
+	}
+	binary.LittleEndian.PutUint32(index[len(frames)*8:], 0xffffffff)
+	binary.LittleEndian.PutUint32(index[len(frames)*8+4:], offset)
+	if _, err := w.Write(index); err != nil {
+		return err
+	}
+
+	for _, frame := range frames {
+		if _, err := w.Write(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBifIndex returns the frame count and interval of a BIF file.
+func ReadBifIndex(r io.Reader) (frames int, intervalMs int, err error) {
+	header := make([]byte, bifHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, 0, fmt.Errorf("failed to read BIF header: %w", err)
+	}
+	if !bytes.Equal(header[:8], bifMagic) {
+		return 0, 0, fmt.Errorf("not a BIF file")
+	}
+	return int(binary.LittleEndian.Uint32(header[12:])), int(binary.LittleEndian.Uint32(header[16:])), nil
+}
+
+// GenerateBifFile builds a BIF trickplay file from generated sprite sheets.
+// Each sheet is split back into JPEG tiles with ffmpeg, and the tiles are
+// ordered by the VTT cues so BIF frames line up with the sprite thumbnails.
+func GenerateBifFile(ctx context.Context, bifPath, spriteDir string, cues []SpriteCue, gridCols, gridRows, intervalSeconds, quality int) error {
+	if len(cues) == 0 {
+		return fmt.Errorf("no sprite cues to build trickplay from")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "bif-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tilesBySheet := make(map[string][]string)
+	frames := make([][]byte, 0, len(cues))
+	for _, cue := range cues {
+		sheet := filepath.Base(cue.Sheet)
+		tiles, ok := tilesBySheet[sheet]
+		if !ok {
+			sheetDir := filepath.Join(tmpDir, strconv.Itoa(len(tilesBySheet)))
+			tiles, err = untileToJpeg(ctx, filepath.Join(spriteDir, sheet), sheetDir, gridCols, gridRows, quality)
+			if err != nil {
+				return err
+			}
+			tilesBySheet[sheet] = tiles
+		}
+
+		if cue.Width == 0 || cue.Height == 0 {
+			return fmt.Errorf("invalid sprite cue size for %s", sheet)
+		}
+		tile := (cue.Y/cue.Height)*gridCols + cue.X/cue.Width
+		if tile >= len(tiles) {
+			return fmt.Errorf("sprite cue outside of sheet %s", sheet)
+		}
+		data, err := os.ReadFile(tiles[tile])
+		if err != nil {
+			return fmt.Errorf("failed to read tile: %w", err)
+		}
+		frames = append(frames, data)
+	}
+
+	// Write to a temp file first so players never see a half-written BIF
+	tmpPath := bifPath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create BIF file: %w", err)
+	}
+	if err := WriteBif(f, intervalSeconds*1000, frames); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write BIF file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write BIF file: %w", err)
+	}
+	return os.Rename(tmpPath, bifPath)
+}
+
+// untileToJpeg splits a sprite sheet into its tiles, in row-major order.
+func untileToJpeg(ctx context.Context, sheetPath, outputDir string, gridCols, gridRows, quality int) ([]string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tile dir: %w", err)
+	}
+
+	args := GetDefaultArgs()
+	args = append(args,
+		"-v", "error",
+		"-i", sheetPath,
+		"-vf", fmt.Sprintf("untile=%dx%d", gridCols, gridRows),
+		"-c:v", "mjpeg",
+		"-q:v", strconv.Itoa(mjpegQScale(quality)),
+		filepath.Join(outputDir, "%05d.jpg"),
+	)
+
+	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("ffmpeg failed: %w, output: %s", err, string(output))
+	}
+
+	tiles, err := filepath.Glob(filepath.Join(outputDir, "*.jpg"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(tiles)
+	return tiles, nil
+}
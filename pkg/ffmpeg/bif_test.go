@@ -0,0 +1,49 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestWriteBif(t *testing.T) {
+	frames := [][]byte{[]byte("aaa"), []byte("bbbbb")}
+
+	var buf bytes.Buffer
+	if err := WriteBif(&buf, 10000, frames); err != nil {
+		t.Fatalf("WriteBif failed: %v", err)
+	}
+	out := buf.Bytes()
+
+	count, interval, err := ReadBifIndex(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("ReadBifIndex failed: %v", err)
+	}
+	if count != 2 || interval != 10000 {
+		t.Fatalf("expected 2 frames at 10000ms, got %d at %dms", count, interval)
+	}
+
+	// Header (64) + index (3 entries * 8) puts the first frame at 88
+	index := out[bifHeaderSize:]
+	wantOffsets := []uint32{88, 91, 96}
+	wantTimestamps := []uint32{0, 1, 0xffffffff}
+	for i := range wantOffsets {
+		ts := binary.LittleEndian.Uint32(index[i*8:])
+		off := binary.LittleEndian.Uint32(index[i*8+4:])
+		if ts != wantTimestamps[i] || off != wantOffsets[i] {
+			t.Errorf("entry %d = (%d, %d), want (%d, %d)", i, ts, off, wantTimestamps[i], wantOffsets[i])
+		}
+	}
+	if len(out) != 96 {
+		t.Fatalf("expected 96 bytes, got %d", len(out))
+	}
+	if string(out[88:91]) != "aaa" || string(out[91:96]) != "bbbbb" {
+		t.Fatal("frame data not written at the indexed offsets")
+	}
+}
+
+func TestReadBifIndex_NotBif(t *testing.T) {
+	if _, _, err := ReadBifIndex(bytes.NewReader(make([]byte, bifHeaderSize))); err == nil {
+		t.Fatal("expected error for non-BIF data")
+	}
+}
@@ -0,0 +1,119 @@
+package ffmpeg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Chapter is a titled time range of a video.
+type Chapter struct {
+	Start float64 // seconds
+	End   float64 // seconds
+	Title string
+}
+
+// shiftChapters moves chapters back by offset seconds, for output that starts
+// offset seconds into the source. Chapters that ended before it are dropped and
+// the one in progress starts at zero.
+func shiftChapters(chapters []Chapter, offset float64) []Chapter {
+	if offset <= 0 {
+		return chapters
+	}
+	shifted := make([]Chapter, 0, len(chapters))
+	for _, c := range chapters {
+		if c.End <= offset {
+			continue
+		}
+		shifted = append(shifted, Chapter{
+			Start: max(c.Start-offset, 0),
+			End:   c.End - offset,
+			Title: c.Title,
+		})
+	}
+	return shifted
+}
+
+// FormatFFMetadata renders chapters as an ffmetadata file, the format ffmpeg
+// reads with -f ffmetadata to add chapters to a container.
+func FormatFFMetadata(chapters []Chapter) string {
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for _, c := range chapters {
+		fmt.Fprintf(&b, "\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			millis(c.Start), millis(c.End), escapeFFMetadata(c.Title))
+	}
+	return b.String()
+}
+
+// FormatWebVTTChapters renders chapters as a WebVTT chapters track.
+func FormatWebVTTChapters(chapters []Chapter) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for i, c := range chapters {
+		// A cue text cannot contain a blank line or "-->"
+		title := strings.ReplaceAll(strings.Join(strings.Fields(c.Title), " "), "-->", "->")
+		fmt.Fprintf(&b, "\n%d\n%s --> %s\n%s\n", i+1, formatVTTTimestamp(c.Start), formatVTTTimestamp(c.End), title)
+	}
+	return b.String()
+}
+
+type matroskaChapters struct {
+	XMLName xml.Name `xml:"Chapters"`
+	Edition struct {
+		Atoms []matroskaChapterAtom `xml:"ChapterAtom"`
+	} `xml:"EditionEntry"`
+}
+
+type matroskaChapterAtom struct {
+	TimeStart string `xml:"ChapterTimeStart"`
+	TimeEnd   string `xml:"ChapterTimeEnd"`
+	Display   struct {
+		String   string `xml:"ChapterString"`
+		Language string `xml:"ChapterLanguage"`
+	} `xml:"ChapterDisplay"`
+}
+
+// FormatMatroskaChapters renders chapters as a Matroska chapters XML file, as
+// read by mkvmerge --chapters and mkvpropedit.
+func FormatMatroskaChapters(chapters []Chapter) ([]byte, error) {
+	var doc matroskaChapters
+	for _, c := range chapters {
+		atom := matroskaChapterAtom{
+			TimeStart: formatMatroskaTimestamp(c.Start),
+			TimeEnd:   formatMatroskaTimestamp(c.End),
+		}
+		atom.Display.String = c.Title
+		atom.Display.Language = "und"
+		doc.Edition.Atoms = append(doc.Edition.Atoms, atom)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	header := xml.Header + "<!DOCTYPE Chapters SYSTEM \"matroskachapters.dtd\">\n"
+	return append([]byte(header), append(out, '\n')...), nil
+}
+
+func millis(seconds float64) int64 {
+	return int64(seconds*1000 + 0.5)
+}
+
+// formatVTTTimestamp formats seconds as HH:MM:SS.mmm
+func formatVTTTimestamp(seconds float64) string {
+	ms := millis(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// formatMatroskaTimestamp formats seconds as HH:MM:SS.nnnnnnnnn
+func formatMatroskaTimestamp(seconds float64) string {
+	ms := millis(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d.%09d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000*1000000)
+}
+
+// escapeFFMetadata escapes the characters with a meaning in ffmetadata values.
+func escapeFFMetadata(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", `\`+"\n")
+	return r.Replace(s)
+}
@@ -0,0 +1,74 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+)
+
+var testChapters = []Chapter{
+	{Start: 0, End: 65.5, Title: "Intro"},
+	{Start: 65.5, End: 3725, Title: "Part=1; #a"},
+}
+
+func TestFormatFFMetadata(t *testing.T) {
+	want := ";FFMETADATA1\n" +
+		"\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=65500\ntitle=Intro\n" +
+		"\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=65500\nEND=3725000\ntitle=Part\\=1\\; \\#a\n"
+	if got := FormatFFMetadata(testChapters); got != want {
+		t.Fatalf("unexpected ffmetadata:\n%s", got)
+	}
+}
+
+func TestFormatWebVTTChapters(t *testing.T) {
+	got := FormatWebVTTChapters([]Chapter{
+		testChapters[0],
+		{Start: 65.5, End: 3725, Title: "a --> b\n\nc"},
+	})
+	want := "WEBVTT\n" +
+		"\n1\n00:00:00.000 --> 00:01:05.500\nIntro\n" +
+		"\n2\n00:01:05.500 --> 01:02:05.000\na -> b c\n"
+	if got != want {
+		t.Fatalf("unexpected vtt:\n%s", got)
+	}
+}
+
+func TestFormatMatroskaChapters(t *testing.T) {
+	out, err := FormatMatroskaChapters(testChapters)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got := string(out)
+	for _, want := range []string{
+		"<!DOCTYPE Chapters SYSTEM \"matroskachapters.dtd\">",
+		"<ChapterTimeStart>00:01:05.500000000</ChapterTimeStart>",
+		"<ChapterTimeEnd>01:02:05.000000000</ChapterTimeEnd>",
+		"<ChapterString>Part=1; #a</ChapterString>",
+		"<ChapterLanguage>und</ChapterLanguage>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected xml to contain %q, got:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "<ChapterAtom>"); n != 2 {
+		t.Fatalf("expected 2 chapter atoms, got %d", n)
+	}
+}
+
+func TestShiftChapters(t *testing.T) {
+	shifted := shiftChapters(testChapters, 100)
+	if len(shifted) != 1 {
+		t.Fatalf("expected ended chapters to be dropped, got %+v", shifted)
+	}
+	if shifted[0].Start != 0 || shifted[0].End != 3625 || shifted[0].Title != "Part=1; #a" {
+		t.Fatalf("unexpected chapter %+v", shifted[0])
+	}
+
+	shifted = shiftChapters(testChapters, 30)
+	if len(shifted) != 2 || shifted[0].Start != 0 || shifted[0].End != 35.5 || shifted[1].Start != 35.5 {
+		t.Fatalf("unexpected chapters %+v", shifted)
+	}
+
+	if got := shiftChapters(testChapters, 0); len(got) != 2 || got[1].Start != 65.5 {
+		t.Fatalf("expected chapters unchanged without offset, got %+v", got)
+	}
+}
@@ -0,0 +1,111 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Compilation segments are normalized to one format so they can be joined without
+// re-encoding.
+const (
+	compilationWidth  = 1280
+	compilationHeight = 720
+	compilationFPS    = 30
+)
+
+// CompilationSegment is a range of a source video to include in a compilation.
+type CompilationSegment struct {
+	VideoPath string
+	Start     int  // seconds
+	Duration  int  // seconds
+	HasAudio  bool // silence is added for sources without audio so every segment has a track
+}
+
+// compilationSegmentArgs builds the ffmpeg arguments that cut a segment into an
+// MPEG-TS file letterboxed to the compilation size.
+func compilationSegmentArgs(seg CompilationSegment, outputPath string) []string {
+	args := GetDefaultArgs()
+	args = append(args, "-ss", strconv.Itoa(seg.Start), "-i", seg.VideoPath)
+	audio := "0:a:0"
+	if !seg.HasAudio {
+		args = append(args, "-f", "lavfi", "-i", "anullsrc=channel_layout=stereo:sample_rate=48000")
+		audio = "1:a:0"
+	}
+	return append(args,
+		"-t", strconv.Itoa(seg.Duration),
+		"-map", "0:v:0",
+		"-map", audio,
+		"-vf", fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=%d",
+			compilationWidth, compilationHeight, compilationWidth, compilationHeight, compilationFPS),
+		"-c:v", "libx264",
+		"-preset", "veryfast",
+		"-crf", "23",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "aac",
+		"-b:a", "128k",
+		"-ar", "48000",
+		"-ac", "2",
+		"-map_metadata", "-1",
+		"-f", "mpegts",
+		"-y",
+		outputPath,
+	)
+}
+
+// ExtractCompilationSegmentWithContext cuts a segment for ConcatSegmentsWithContext.
+func ExtractCompilationSegmentWithContext(ctx context.Context, seg CompilationSegment, outputPath string) error {
+	cmd := exec.CommandContext(ctx, FFMpegPath(), compilationSegmentArgs(seg, outputPath)...)
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("ffmpeg segment failed: %w, output: %s", err, string(output))
+	}
+	return nil
+}
+
+// concatList renders segment paths as an ffmpeg concat demuxer list.
+func concatList(segmentPaths []string) string {
+	var b strings.Builder
+	for _, p := range segmentPaths {
+		// Single quotes are closed, escaped and reopened
+		fmt.Fprintf(&b, "file '%s'\n", strings.ReplaceAll(p, "'", `'\''`))
+	}
+	return b.String()
+}
+
+// ConcatSegmentsWithContext joins segments cut by ExtractCompilationSegmentWithContext
+// into a single MP4 without re-encoding.
+func ConcatSegmentsWithContext(ctx context.Context, segmentPaths []string, outputPath string) error {
+	listPath := outputPath + ".txt"
+	if err := os.WriteFile(listPath, []byte(concatList(segmentPaths)), 0644); err != nil {
+		return fmt.Errorf("failed to write concat list: %w", err)
+	}
+	defer os.Remove(listPath)
+
+	args := GetDefaultArgs()
+	args = append(args,
+		"-f", "concat",
+		"-safe", "0",
+		"-i", listPath,
+		"-c", "copy",
+		"-bsf:a", "aac_adtstoasc",
+		"-movflags", "+faststart",
+		"-f", "mp4",
+		"-y",
+		outputPath,
+	)
+
+	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("ffmpeg concat failed: %w, output: %s", err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompilationSegmentArgs(t *testing.T) {
+	args := strings.Join(compilationSegmentArgs(CompilationSegment{VideoPath: "/v/a.mkv", Start: 90, Duration: 15, HasAudio: true}, "/tmp/0.ts"), " ")
+	for _, want := range []string{"-ss 90 -i /v/a.mkv -t 15", "-map 0:v:0 -map 0:a:0", "pad=1280:720", "-f mpegts -y /tmp/0.ts"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected args to contain %q, got %s", want, args)
+		}
+	}
+
+	// Sources without audio get a silent track
+	args = strings.Join(compilationSegmentArgs(CompilationSegment{VideoPath: "/v/b.mp4", Duration: 5}, "/tmp/1.ts"), " ")
+	for _, want := range []string{"-f lavfi -i anullsrc", "-map 0:v:0 -map 1:a:0"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected args to contain %q, got %s", want, args)
+		}
+	}
+}
+
+func TestConcatList(t *testing.T) {
+	got := concatList([]string{"/tmp/a.ts", "/tmp/it's.ts"})
+	want := "file '/tmp/a.ts'\nfile '/tmp/it'\\''s.ts'\n"
+	if got != want {
+		t.Fatalf("unexpected concat list %q", got)
+	}
+}
@@ -1,8 +1,10 @@
 package ffmpeg
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -31,7 +33,34 @@ func ExtractThumbnailWithContext(ctx context.Context, videoPath, outputPath, see
 	}...)
 
 	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("ffmpeg failed: %w, output: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// ExtractPortraitFrameWithContext extracts one frame at the seek position,
+// center-cropped to a 2:3 portrait (frames narrower than that keep their width)
+// and scaled to the given height, as WebP.
+func ExtractPortraitFrameWithContext(ctx context.Context, videoPath, outputPath, seekPosition string, height, quality int) error {
+	args := GetDefaultArgs()
+	args = append(args, []string{
+		"-ss", seekPosition,
+		"-i", videoPath,
+		"-vframes", "1",
+		"-c:v", "libwebp",
+		"-vf", fmt.Sprintf("crop='min(iw,ih*2/3)':ih,scale=-2:%d", height),
+		"-q:v", strconv.Itoa(quality),
+		"-y",
+		outputPath,
+	}...)
+
+	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
+	if output, err := combinedOutput(ctx, cmd); err != nil {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
@@ -64,7 +93,7 @@ func ExtractAnimatedThumbnailWithContext(ctx context.Context, videoPath, outputP
 	)
 
 	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := combinedOutput(ctx, cmd); err != nil {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
@@ -74,11 +103,53 @@ func ExtractAnimatedThumbnailWithContext(ctx context.Context, videoPath, outputP
 	return nil
 }
 
+// ExtractClipWithContext cuts duration seconds starting at start out of a video into a
+// playable MP4 with audio. The clip is re-encoded so it starts exactly at start rather
+// than on the preceding keyframe.
+func ExtractClipWithContext(ctx context.Context, videoPath, outputPath string, start, duration int) error {
+	args := GetDefaultArgs()
+	args = append(args,
+		"-ss", strconv.Itoa(start),
+		"-i", videoPath,
+		"-t", strconv.Itoa(duration),
+		"-map", "0:v:0",
+		"-map", "0:a:0?",
+		"-c:v", "libx264",
+		"-preset", "veryfast",
+		"-crf", "23",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "aac",
+		"-b:a", "128k",
+		"-movflags", "+faststart",
+		"-map_metadata", "-1",
+		"-f", "mp4",
+		"-y",
+		outputPath,
+	)
+
+	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("ffmpeg clip failed: %w, output: %s", err, string(output))
+	}
+
+	return nil
+}
+
 // ExtractScenePreviewWithContext generates a scene preview video by sampling multiple segments
 // throughout the video and concatenating them into a single clip. For short videos where the
 // total content is less than segments * segmentDuration, it encodes the entire video at reduced resolution.
 func ExtractScenePreviewWithContext(ctx context.Context, videoPath, outputPath string,
 	duration int, segments int, segmentDuration float64, width, crf int) error {
+	return ExtractScenePreviewWithProgress(ctx, videoPath, outputPath, duration, segments, segmentDuration, width, crf, nil)
+}
+
+// ExtractScenePreviewWithProgress is ExtractScenePreviewWithContext with optional progress reporting
+// based on ffmpeg's -progress output.
+func ExtractScenePreviewWithProgress(ctx context.Context, videoPath, outputPath string,
+	duration int, segments int, segmentDuration float64, width, crf int, onProgress ProgressFunc) error {
 
 	totalNeeded := float64(segments) * segmentDuration
 
@@ -100,8 +171,7 @@ func ExtractScenePreviewWithContext(ctx context.Context, videoPath, outputPath s
 			outputPath,
 		)
 
-		cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := runWithProgress(ctx, args, float64(duration), onProgress); err != nil {
 			if ctx.Err() != nil {
 				return ctx.Err()
 			}
@@ -150,8 +220,7 @@ func ExtractScenePreviewWithContext(ctx context.Context, videoPath, outputPath s
 		outputPath,
 	)
 
-	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := runWithProgress(ctx, args, totalNeeded, onProgress); err != nil {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
@@ -291,6 +360,42 @@ func ResizeImageToWebp(inputPath, outputPath string, width, height, quality int)
 	return nil
 }
 
+// CropImageToWriter cuts a width x height region at (x, y) out of an image and
+// writes it to w as "webp" or "jpeg".
+func CropImageToWriter(ctx context.Context, inputPath string, x, y, width, height int, format string, quality int, w io.Writer) error {
+	args := GetDefaultArgs()
+	args = append(args,
+		"-v", "error",
+		"-i", inputPath,
+		"-vf", fmt.Sprintf("crop=%d:%d:%d:%d", width, height, x, y),
+		"-frames:v", "1",
+	)
+	switch format {
+	case "jpeg":
+		args = append(args, "-c:v", "mjpeg", "-q:v", strconv.Itoa(mjpegQScale(quality)), "-f", "image2pipe")
+	default:
+		args = append(args, "-c:v", "libwebp", "-quality", strconv.Itoa(quality), "-f", "webp")
+	}
+	args = append(args, "pipe:1")
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("ffmpeg crop failed: %w, output: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// mjpegQScale maps a 1-100 quality onto the mjpeg -q:v scale, which runs from 2 (best) to 31.
+func mjpegQScale(quality int) int {
+	return 2 + (100-quality)*29/100
+}
+
 func ExtractSpriteSheets(videoPath, outputDir string, videoID int, width, height, gridCols, gridRows, interval, quality, concurrency int) ([]string, error) {
 	return ExtractSpriteSheetsWithContext(context.Background(), videoPath, outputDir, videoID, width, height, gridCols, gridRows, interval, quality, concurrency)
 }
@@ -384,7 +489,7 @@ func ExtractSpriteSheetsWithProgress(ctx context.Context, videoPath, outputDir s
 			)
 
 			cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
-			if output, err := cmd.CombinedOutput(); err != nil {
+			if output, err := combinedOutput(ctx, cmd); err != nil {
 				if ctx.Err() != nil {
 					errChan <- ctx.Err()
 					return
@@ -458,7 +563,7 @@ func ExtractSpriteSheetsWithProgress(ctx context.Context, videoPath, outputDir s
 		)
 
 		cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
-		output, cmdErr := cmd.CombinedOutput()
+		output, cmdErr := combinedOutput(ctx, cmd)
 		os.RemoveAll(sheetDir)
 		if cmdErr != nil {
 			if ctx.Err() != nil {
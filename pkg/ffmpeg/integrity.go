@@ -1,8 +1,14 @@
 package ffmpeg
 
 import (
+	"bufio"
 	"context"
+	"fmt"
 	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 // CheckVideoIntegrityWithContext verifies video file integrity by demuxing
@@ -23,7 +29,7 @@ func CheckVideoIntegrityWithContext(ctx context.Context, videoPath string) (bool
 	)
 
 	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
-	if err := cmd.Run(); err != nil {
+	if _, err := combinedOutput(ctx, cmd); err != nil {
 		if ctx.Err() != nil {
 			return false, ctx.Err()
 		}
@@ -32,3 +38,179 @@ func CheckVideoIntegrityWithContext(ctx context.Context, videoPath string) (bool
 
 	return true, nil
 }
+
+const (
+	// maxReportedDecodeErrors caps the number of individual error lines kept in
+	// a DecodeReport so that badly damaged files don't produce huge reports.
+	maxReportedDecodeErrors = 200
+	// unreadableRangeGap is the maximum distance in seconds between two decode
+	// errors for them to be merged into the same unreadable range.
+	unreadableRangeGap = 2.0
+)
+
+// DecodeError is a single error emitted by the decoder, tagged with the
+// approximate playback position at which it occurred.
+type DecodeError struct {
+	Timestamp float64 `json:"timestamp"`
+	Message   string  `json:"message"`
+}
+
+// TimeRange is a span of the video in seconds.
+type TimeRange struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// DecodeReport is the outcome of a full decode pass over a video file.
+type DecodeReport struct {
+	ErrorCount       int
+	Errors           []DecodeError
+	UnreadableRanges []TimeRange
+	DecodedSeconds   float64
+	Completed        bool
+}
+
+// IsCorrupted reports whether the decode pass found any problem.
+func (r *DecodeReport) IsCorrupted() bool {
+	return r.ErrorCount > 0 || !r.Completed
+}
+
+// VerifyVideoDecodeWithContext fully decodes every stream of the file and
+// discards the output. Unlike CheckVideoIntegrityWithContext this exercises
+// the decoders, so it catches damaged frames inside otherwise well-formed
+// packets. It is CPU-bound and takes roughly as long as a transcode.
+// The optional progressCallback receives the decoded position in seconds.
+// A non-nil error is only returned for system errors; decode problems are
+// reported through the returned DecodeReport.
+func VerifyVideoDecodeWithContext(ctx context.Context, videoPath string, progressCallback func(seconds float64)) (*DecodeReport, error) {
+	args := GetDefaultArgs()
+	args = append(args,
+		"-v", "error",
+		"-progress", "pipe:1",
+		"-nostats",
+		"-i", videoPath,
+		"-f", "null",
+		"-",
+	)
+
+	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	report := &DecodeReport{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if seconds, ok := parseProgressTime(line); ok {
+				mu.Lock()
+				if seconds > report.DecodedSeconds {
+					report.DecodedSeconds = seconds
+				}
+				mu.Unlock()
+				if progressCallback != nil {
+					progressCallback(seconds)
+				}
+			} else if line == "progress=end" {
+				mu.Lock()
+				report.Completed = true
+				mu.Unlock()
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			mu.Lock()
+			report.ErrorCount++
+			if len(report.Errors) < maxReportedDecodeErrors {
+				report.Errors = append(report.Errors, DecodeError{
+					Timestamp: report.DecodedSeconds,
+					Message:   line,
+				})
+			}
+			mu.Unlock()
+		}
+	}()
+
+	wg.Wait()
+	waitErr := cmd.Wait()
+
+	var output strings.Builder
+	for _, e := range report.Errors {
+		output.WriteString(e.Message + "\n")
+	}
+	logRun(ctx, cmd, []byte(output.String()), waitErr)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if waitErr != nil {
+		// ffmpeg exits non-zero when it cannot read the file at all; that is a
+		// corruption finding, not a system error.
+		report.Completed = false
+	}
+
+	report.UnreadableRanges = mergeErrorRanges(report.Errors, unreadableRangeGap)
+	return report, nil
+}
+
+// parseProgressTime extracts the decoded position in seconds from an
+// ffmpeg -progress line ("out_time_us=..." or "out_time_ms=...", both of
+// which are in microseconds).
+func parseProgressTime(line string) (float64, bool) {
+	key, value, ok := strings.Cut(line, "=")
+	if !ok || (key != "out_time_us" && key != "out_time_ms") {
+		return 0, false
+	}
+	us, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || us < 0 {
+		return 0, false
+	}
+	return float64(us) / 1e6, true
+}
+
+// mergeErrorRanges groups decode errors whose timestamps are within gap
+// seconds of each other into contiguous time ranges.
+func mergeErrorRanges(errs []DecodeError, gap float64) []TimeRange {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	timestamps := make([]float64, len(errs))
+	for i, e := range errs {
+		timestamps[i] = e.Timestamp
+	}
+	sort.Float64s(timestamps)
+
+	ranges := []TimeRange{{Start: timestamps[0], End: timestamps[0]}}
+	for _, ts := range timestamps[1:] {
+		last := &ranges[len(ranges)-1]
+		if ts-last.End <= gap {
+			last.End = ts
+			continue
+		}
+		ranges = append(ranges, TimeRange{Start: ts, End: ts})
+	}
+	return ranges
+}
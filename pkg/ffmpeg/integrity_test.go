@@ -0,0 +1,102 @@
+package ffmpeg
+
+import (
+	"testing"
+)
+
+func TestParseProgressTime(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected float64
+		ok       bool
+	}{
+		{"out_time_us=1500000", 1.5, true},
+		{"out_time_ms=2000000", 2.0, true},
+		{"out_time_us=0", 0, true},
+		{"out_time_us=N/A", 0, false},
+		{"out_time_us=-5", 0, false},
+		{"out_time=00:00:01.500000", 0, false},
+		{"progress=continue", 0, false},
+		{"garbage", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			result, ok := parseProgressTime(tt.line)
+			if ok != tt.ok {
+				t.Fatalf("parseProgressTime(%q) ok = %v, want %v", tt.line, ok, tt.ok)
+			}
+			if result != tt.expected {
+				t.Fatalf("parseProgressTime(%q) = %v, want %v", tt.line, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMergeErrorRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		errs     []DecodeError
+		expected []TimeRange
+	}{
+		{
+			name:     "no errors",
+			errs:     nil,
+			expected: nil,
+		},
+		{
+			name:     "single error",
+			errs:     []DecodeError{{Timestamp: 10}},
+			expected: []TimeRange{{Start: 10, End: 10}},
+		},
+		{
+			name:     "adjacent errors merge",
+			errs:     []DecodeError{{Timestamp: 10}, {Timestamp: 11}, {Timestamp: 12.5}},
+			expected: []TimeRange{{Start: 10, End: 12.5}},
+		},
+		{
+			name:     "distant errors split",
+			errs:     []DecodeError{{Timestamp: 10}, {Timestamp: 30}},
+			expected: []TimeRange{{Start: 10, End: 10}, {Start: 30, End: 30}},
+		},
+		{
+			name:     "unsorted input",
+			errs:     []DecodeError{{Timestamp: 31}, {Timestamp: 10}, {Timestamp: 30}, {Timestamp: 11}},
+			expected: []TimeRange{{Start: 10, End: 11}, {Start: 30, End: 31}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := mergeErrorRanges(tt.errs, 2.0)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("mergeErrorRanges() returned %d ranges, want %d: %+v", len(result), len(tt.expected), result)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Fatalf("range[%d] = %+v, want %+v", i, result[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeReport_IsCorrupted(t *testing.T) {
+	tests := []struct {
+		name     string
+		report   DecodeReport
+		expected bool
+	}{
+		{"clean", DecodeReport{Completed: true}, false},
+		{"errors", DecodeReport{Completed: true, ErrorCount: 3}, true},
+		{"incomplete", DecodeReport{Completed: false}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.IsCorrupted(); got != tt.expected {
+				t.Fatalf("IsCorrupted() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// maxLoggedOutput caps the output of a single run written to an output log;
+// the end is kept since that is where ffmpeg reports what went wrong.
+const maxLoggedOutput = 16 * 1024
+
+type outputLogKey struct{}
+
+// WithOutputLog returns a context under which ffmpeg and ffprobe runs write
+// their command line, diagnostic output and exit status to w once they finish.
+// w must be safe for concurrent use, as frame extraction runs in parallel.
+func WithOutputLog(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, outputLogKey{}, w)
+}
+
+// logRun writes a finished run to the context's output log, if it has one.
+func logRun(ctx context.Context, cmd *exec.Cmd, output []byte, err error) {
+	w, ok := ctx.Value(outputLogKey{}).(io.Writer)
+	if !ok {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ %s\n", strings.Join(cmd.Args, " "))
+	if len(output) > maxLoggedOutput {
+		fmt.Fprintf(&b, "[... %d bytes of output omitted ...]\n", len(output)-maxLoggedOutput)
+		output = output[len(output)-maxLoggedOutput:]
+	}
+	b.Write(output)
+	if len(output) > 0 && output[len(output)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	if err != nil {
+		fmt.Fprintf(&b, "exit: %v\n", err)
+	}
+	io.WriteString(w, b.String())
+}
+
+// combinedOutput runs cmd like CombinedOutput and writes the run to the
+// context's output log.
+func combinedOutput(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	output, err := cmd.CombinedOutput()
+	logRun(ctx, cmd, output, err)
+	return output, err
+}
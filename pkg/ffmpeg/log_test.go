@@ -0,0 +1,31 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestLogRun(t *testing.T) {
+	cmd := exec.Command("ffmpeg", "-i", "scene.mp4")
+
+	// Without an output log nothing is written anywhere
+	logRun(context.Background(), cmd, []byte("ignored"), nil)
+
+	var log bytes.Buffer
+	ctx := WithOutputLog(context.Background(), &log)
+	logRun(ctx, cmd, []byte("moov atom not found"), errors.New("exit status 1"))
+	want := "$ ffmpeg -i scene.mp4\nmoov atom not found\nexit: exit status 1\n"
+	if log.String() != want {
+		t.Fatalf("expected %q, got %q", want, log.String())
+	}
+
+	log.Reset()
+	logRun(ctx, cmd, []byte(strings.Repeat("x", maxLoggedOutput+10)), nil)
+	if !strings.Contains(log.String(), "[... 10 bytes of output omitted ...]") {
+		t.Fatalf("expected the start of long output omitted, got %q", log.String()[:80])
+	}
+}
@@ -49,6 +49,7 @@ func GetMetadataWithContext(ctx context.Context, videoPath string) (*VideoMetada
 
 	cmd := exec.CommandContext(ctx, FFprobePath(), args...)
 	output, err := cmd.Output()
+	logRun(ctx, cmd, nil, err)
 	if err != nil {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
@@ -0,0 +1,62 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// ProgressFunc receives the progress of an ffmpeg run as a percentage (0-100).
+type ProgressFunc func(percent int)
+
+// runWithProgress runs ffmpeg and reports the -progress output position as a
+// percentage of totalSeconds. Like CombinedOutput it returns ffmpeg's diagnostic
+// output, which callers include in their error messages.
+func runWithProgress(ctx context.Context, args []string, totalSeconds float64, onProgress ProgressFunc) ([]byte, error) {
+	if onProgress == nil || totalSeconds <= 0 {
+		return combinedOutput(ctx, exec.CommandContext(ctx, FFMpegPath(), args...))
+	}
+
+	// -progress is a global option, so it can go in front of the caller's arguments
+	args = append([]string{"-progress", "pipe:1", "-nostats"}, args...)
+	cmd := exec.CommandContext(ctx, FFMpegPath(), args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	scanProgress(stdout, totalSeconds, onProgress)
+
+	err = cmd.Wait()
+	logRun(ctx, cmd, stderr.Bytes(), err)
+	return stderr.Bytes(), err
+}
+
+// scanProgress reads ffmpeg -progress lines and calls onProgress whenever the
+// percentage changes.
+func scanProgress(r io.Reader, totalSeconds float64, onProgress ProgressFunc) {
+	last := -1
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		seconds, ok := parseProgressTime(scanner.Text())
+		if !ok {
+			continue
+		}
+		percent := min(int(seconds*100/totalSeconds), 100)
+		if percent != last {
+			last = percent
+			onProgress(percent)
+		}
+	}
+	// Drain the pipe so ffmpeg never blocks on a full stdout buffer
+	io.Copy(io.Discard, r)
+}
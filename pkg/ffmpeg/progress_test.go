@@ -0,0 +1,41 @@
+package ffmpeg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanProgress(t *testing.T) {
+	input := strings.Join([]string{
+		"frame=10",
+		"out_time_us=1000000",
+		"progress=continue",
+		"out_time_us=1500000",
+		"out_time_ms=2500000",
+		"out_time_us=N/A",
+		"out_time_us=12000000",
+		"progress=end",
+	}, "\n")
+
+	var got []int
+	scanProgress(strings.NewReader(input), 10, func(percent int) {
+		got = append(got, percent)
+	})
+
+	want := []int{10, 15, 25, 100}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("scanProgress reported %v, want %v", got, want)
+	}
+}
+
+func TestScanProgress_DeduplicatesPercent(t *testing.T) {
+	input := "out_time_us=1000000\nout_time_us=1001000\nout_time_us=1002000\n"
+
+	calls := 0
+	scanProgress(strings.NewReader(input), 100, func(int) { calls++ })
+
+	if calls != 1 {
+		t.Fatalf("expected 1 progress call, got %d", calls)
+	}
+}
@@ -0,0 +1,86 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// TranscodeOptions controls an on-the-fly transcode to fragmented MP4.
+type TranscodeOptions struct {
+	StartSeconds float64 // seek position in the source (0 = from the beginning)
+	CopyVideo    bool    // source video is already H.264 and is passed through
+	CopyAudio    bool    // source audio is already AAC and is passed through
+	Preset       string  // x264 preset used when re-encoding video
+	// Chapters, in source time, are embedded in the output. They are piped to
+	// ffmpeg as ffmetadata and shifted to match StartSeconds.
+	Chapters []Chapter
+}
+
+// transcodeArgs builds the ffmpeg arguments for streaming a fragmented MP4
+// (H.264/AAC) to stdout.
+func transcodeArgs(videoPath string, opts TranscodeOptions) []string {
+	args := GetDefaultArgs()
+	args = append(args, "-v", "error")
+	if opts.StartSeconds > 0 {
+		// Input seeking is fast and lands on the preceding keyframe
+		args = append(args, "-ss", strconv.FormatFloat(opts.StartSeconds, 'f', 3, 64))
+	}
+	args = append(args, "-i", videoPath)
+	if len(opts.Chapters) > 0 {
+		args = append(args, "-f", "ffmetadata", "-i", "pipe:0", "-map_chapters", "1")
+	}
+	args = append(args, "-map", "0:v:0", "-map", "0:a:0?", "-sn", "-dn")
+
+	if opts.CopyVideo {
+		args = append(args, "-c:v", "copy")
+	} else {
+		preset := opts.Preset
+		if preset == "" {
+			preset = "veryfast"
+		}
+		args = append(args,
+			"-c:v", "libx264",
+			"-preset", preset,
+			"-crf", "23",
+			"-pix_fmt", "yuv420p",
+		)
+	}
+
+	if opts.CopyAudio {
+		args = append(args, "-c:a", "copy")
+	} else {
+		args = append(args, "-c:a", "aac", "-b:a", "160k", "-ac", "2")
+	}
+
+	return append(args,
+		"-movflags", "frag_keyframe+empty_moov+default_base_moof",
+		"-f", "mp4",
+		"pipe:1",
+	)
+}
+
+// TranscodeToWriter transcodes videoPath to fragmented MP4 and streams it to w
+// until the source ends or ctx is cancelled (e.g. the client disconnects).
+func TranscodeToWriter(ctx context.Context, videoPath string, opts TranscodeOptions, w io.Writer) error {
+	cmd := exec.CommandContext(ctx, FFMpegPath(), transcodeArgs(videoPath, opts)...)
+
+	var stderr bytes.Buffer
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if len(opts.Chapters) > 0 {
+		cmd.Stdin = strings.NewReader(FormatFFMetadata(shiftChapters(opts.Chapters, opts.StartSeconds)))
+	}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("ffmpeg transcode failed: %w, output: %s", err, stderr.String())
+	}
+	return nil
+}
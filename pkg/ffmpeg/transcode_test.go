@@ -0,0 +1,35 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranscodeArgs(t *testing.T) {
+	args := strings.Join(transcodeArgs("/v/a.mkv", TranscodeOptions{StartSeconds: 90.5, CopyVideo: true}), " ")
+
+	for _, want := range []string{"-ss 90.500 -i /v/a.mkv", "-c:v copy", "-c:a aac", "frag_keyframe+empty_moov", "pipe:1"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected args to contain %q, got %s", want, args)
+		}
+	}
+
+	args = strings.Join(transcodeArgs("/v/a.mkv", TranscodeOptions{CopyAudio: true}), " ")
+	if strings.Contains(args, "-ss") {
+		t.Fatalf("expected no seek without a start position, got %s", args)
+	}
+	for _, want := range []string{"-c:v libx264 -preset veryfast", "-c:a copy"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected args to contain %q, got %s", want, args)
+		}
+	}
+
+	if strings.Contains(args, "-map_chapters") {
+		t.Fatalf("expected no chapters input without chapters, got %s", args)
+	}
+
+	args = strings.Join(transcodeArgs("/v/a.mkv", TranscodeOptions{Chapters: []Chapter{{Start: 0, End: 10, Title: "Intro"}}}), " ")
+	if !strings.Contains(args, "-i /v/a.mkv -f ffmetadata -i pipe:0 -map_chapters 1 -map 0:v:0") {
+		t.Fatalf("expected chapters to be read from stdin, got %s", args)
+	}
+}
@@ -1,11 +1,27 @@
 package ffmpeg
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
+// SpriteCue is one thumbnail VTT cue: the time range and the tile of the
+// sprite sheet that previews it.
+type SpriteCue struct {
+	Start  float64 // seconds
+	End    float64 // seconds
+	Sheet  string  // sprite sheet URL as written in the VTT (e.g. /sprites/1_sheet_000.webp)
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
 func GenerateVttFile(vttPath string, spriteSheets []string, videoDuration, interval, gridCols, gridRows, width, height int) error {
 	if err := os.MkdirAll(filepath.Dir(vttPath), 0755); err != nil {
 		return fmt.Errorf("failed to create VTT directory: %w", err)
@@ -57,3 +73,97 @@ func formatTime(seconds int) string {
 	millis := 0
 	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, secs, millis)
 }
+
+// ParseVttFile reads the sprite cues of a thumbnail VTT file.
+func ParseVttFile(vttPath string) ([]SpriteCue, error) {
+	f, err := os.Open(vttPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseVtt(f)
+}
+
+// ParseVtt reads sprite cues in the format written by GenerateVttFile. Cues
+// without a #xywh= fragment are skipped.
+func ParseVtt(r io.Reader) ([]SpriteCue, error) {
+	var cues []SpriteCue
+	var start, end float64
+	haveTiming := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			haveTiming = false
+			continue
+		}
+
+		if from, to, ok := strings.Cut(line, "-->"); ok {
+			s, err1 := parseVttTime(strings.TrimSpace(from))
+			e, err2 := parseVttTime(strings.TrimSpace(to))
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid cue timing %q", line)
+			}
+			start, end, haveTiming = s, e, true
+			continue
+		}
+
+		if !haveTiming {
+			continue
+		}
+		sheet, fragment, ok := strings.Cut(line, "#xywh=")
+		if !ok {
+			continue
+		}
+		var x, y, w, h int
+		if _, err := fmt.Sscanf(fragment, "%d,%d,%d,%d", &x, &y, &w, &h); err != nil {
+			return nil, fmt.Errorf("invalid sprite fragment %q", line)
+		}
+		cues = append(cues, SpriteCue{Start: start, End: end, Sheet: sheet, X: x, Y: y, Width: w, Height: h})
+		haveTiming = false
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cues, nil
+}
+
+// FindSpriteCue returns the cue covering second t. Times past the last cue map
+// to the last cue so the end of the video still has a frame.
+func FindSpriteCue(cues []SpriteCue, t float64) (SpriteCue, bool) {
+	if len(cues) == 0 || t < 0 {
+		return SpriteCue{}, false
+	}
+	for _, cue := range cues {
+		if t >= cue.Start && t < cue.End {
+			return cue, true
+		}
+	}
+	last := cues[len(cues)-1]
+	if t >= last.End {
+		return last, true
+	}
+	return SpriteCue{}, false
+}
+
+// parseVttTime parses "HH:MM:SS.mmm" or "MM:SS.mmm" into seconds.
+func parseVttTime(v string) (float64, error) {
+	parts := strings.Split(v, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid VTT time %q", v)
+	}
+	var seconds float64
+	for _, part := range parts[:len(parts)-1] {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, fmt.Errorf("invalid VTT time %q", v)
+		}
+		seconds = seconds*60 + float64(n)
+	}
+	secs, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid VTT time %q", v)
+	}
+	return seconds*60 + secs, nil
+}
@@ -196,3 +196,63 @@ func TestGenerateVtt_FirstFrameAt0_0(t *testing.T) {
 		t.Fatal("first frame cue should start at 00:00:00.000 with position 0,0")
 	}
 }
+
+func TestParseVtt_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	vttPath := filepath.Join(dir, "test.vtt")
+
+	// 50s video, 10s interval, 2x2 grid over two sheets
+	if err := GenerateVttFile(vttPath, []string{"1_a.webp", "1_b.webp"}, 50, 10, 2, 2, 160, 90); err != nil {
+		t.Fatalf("GenerateVttFile failed: %v", err)
+	}
+
+	cues, err := ParseVttFile(vttPath)
+	if err != nil {
+		t.Fatalf("ParseVttFile failed: %v", err)
+	}
+	if len(cues) != 5 {
+		t.Fatalf("expected 5 cues, got %d", len(cues))
+	}
+
+	want := SpriteCue{Start: 30, End: 40, Sheet: "/sprites/1_a.webp", X: 160, Y: 90, Width: 160, Height: 90}
+	if cues[3] != want {
+		t.Fatalf("cue 3 = %+v, want %+v", cues[3], want)
+	}
+	if cues[4].Sheet != "/sprites/1_b.webp" || cues[4].X != 0 || cues[4].Y != 0 {
+		t.Fatalf("expected cue 4 at the start of the second sheet, got %+v", cues[4])
+	}
+}
+
+func TestParseVtt_Invalid(t *testing.T) {
+	if _, err := ParseVtt(strings.NewReader("WEBVTT\n\nnope --> 00:00:05.000\n/sprites/a.webp#xywh=0,0,1,1\n")); err == nil {
+		t.Fatal("expected error for invalid timing")
+	}
+}
+
+func TestFindSpriteCue(t *testing.T) {
+	cues := []SpriteCue{
+		{Start: 0, End: 5, X: 0},
+		{Start: 5, End: 10, X: 160},
+	}
+
+	tests := []struct {
+		t     float64
+		x     int
+		found bool
+	}{
+		{0, 0, true},
+		{4.9, 0, true},
+		{5, 160, true},
+		{42, 160, true}, // past the end maps to the last frame
+		{-1, 0, false},
+	}
+	for _, tt := range tests {
+		cue, ok := FindSpriteCue(cues, tt.t)
+		if ok != tt.found || (ok && cue.X != tt.x) {
+			t.Errorf("FindSpriteCue(%v) = %+v, %v; want x=%d, %v", tt.t, cue, ok, tt.x, tt.found)
+		}
+	}
+	if _, ok := FindSpriteCue(nil, 1); ok {
+		t.Error("expected no cue for empty list")
+	}
+}
@@ -1,3 +1,16 @@
+import type { APIKey } from '~/types/api_key';
+import type { CreateInviteRequest, CreatedInvite, UserInvite } from '~/types/invite';
+import type {
+    APIUsageOverview,
+    APIUsageReport,
+    AuditLogFilter,
+    AuditLogPage,
+    ContentRestriction,
+    ReleaseInfo,
+    SearchConsistencyReport,
+    SearchReindexStatus,
+} from '~/types/admin';
+
 /**
  * Admin API operations: users, roles, permissions management, trash.
  */
@@ -78,6 +91,26 @@ export const useApiAdmin = () => {
         return handleResponse(response);
     };
 
+    const fetchContentRestriction = async (roleId: number): Promise<ContentRestriction> => {
+        const response = await fetch(`/api/v1/admin/roles/${roleId}/content-restrictions`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const updateContentRestriction = async (
+        roleId: number,
+        restriction: { storage_path_ids: number[]; tag_ids: number[]; scene_types: string[] },
+    ): Promise<ContentRestriction> => {
+        const response = await fetch(`/api/v1/admin/roles/${roleId}/content-restrictions`, {
+            method: 'PUT',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(restriction),
+        });
+        return handleResponse(response);
+    };
+
     const getSearchStatus = async () => {
         const response = await fetch('/api/v1/admin/search/status', {
             headers: getAuthHeaders(),
@@ -86,8 +119,43 @@ export const useApiAdmin = () => {
         return handleResponse(response);
     };
 
-    const triggerReindex = async () => {
+    const triggerReindex = async (
+        resume = false,
+    ): Promise<{ message: string; status: SearchReindexStatus }> => {
+        const url = resume
+            ? '/api/v1/admin/search/reindex?resume=true'
+            : '/api/v1/admin/search/reindex';
+        const response = await fetch(url, {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const getReindexStatus = async (): Promise<{ status: SearchReindexStatus | null }> => {
         const response = await fetch('/api/v1/admin/search/reindex', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const getSearchConsistency = async (): Promise<{ report: SearchConsistencyReport | null }> => {
+        const response = await fetch('/api/v1/admin/search/consistency', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const checkSearchConsistency = async (
+        heal = false,
+    ): Promise<{ report: SearchConsistencyReport }> => {
+        const url = heal
+            ? '/api/v1/admin/search/consistency?heal=true'
+            : '/api/v1/admin/search/consistency';
+        const response = await fetch(url, {
             method: 'POST',
             headers: getAuthHeaders(),
             ...fetchOptions(),
@@ -152,6 +220,27 @@ export const useApiAdmin = () => {
         return handleResponse(response);
     };
 
+    // API keys of all users
+    const fetchAllAPIKeys = async (
+        page = 1,
+        limit = 20,
+    ): Promise<{ data: APIKey[]; total: number; page: number; limit: number }> => {
+        const params = new URLSearchParams({ page: page.toString(), limit: limit.toString() });
+        const response = await fetch(`/api/v1/admin/api-keys?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const revokeUserAPIKey = async (id: number) => {
+        const response = await fetch(`/api/v1/admin/api-keys/${id}`, {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+        });
+        return handleResponse(response);
+    };
+
     // App settings
     const getAppSettings = async () => {
         const response = await fetch('/api/v1/admin/app-settings', {
@@ -164,6 +253,13 @@ export const useApiAdmin = () => {
     const updateAppSettings = async (settings: {
         serve_og_metadata: boolean;
         trash_retention_days: number;
+        stream_rate_limit_kbps: number;
+        user_stream_rate_limit_kbps: number;
+        global_stream_rate_limit_kbps: number;
+        cast_discovery_enabled: boolean;
+        require_admin_two_factor: boolean;
+        open_registration: boolean;
+        open_registration_role: string;
     }) => {
         const response = await fetch('/api/v1/admin/app-settings', {
             method: 'PUT',
@@ -173,6 +269,80 @@ export const useApiAdmin = () => {
         return handleResponse(response);
     };
 
+    const getReleaseInfo = async (limit = 5): Promise<ReleaseInfo> => {
+        const params = new URLSearchParams({ limit: limit.toString() });
+        const response = await fetch(`/api/v1/admin/release-info?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const getAPIUsageOverview = async (days = 30, limit = 50): Promise<APIUsageOverview> => {
+        const params = new URLSearchParams({ days: days.toString(), limit: limit.toString() });
+        const response = await fetch(`/api/v1/admin/usage?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const getUserAPIUsage = async (userId: number, days = 30): Promise<APIUsageReport> => {
+        const params = new URLSearchParams({ days: days.toString() });
+        const response = await fetch(`/api/v1/admin/usage/users/${userId}?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const fetchInvites = async (
+        page: number,
+        limit: number,
+    ): Promise<{ data: UserInvite[]; total: number; page: number; limit: number }> => {
+        const params = new URLSearchParams({ page: page.toString(), limit: limit.toString() });
+        const response = await fetch(`/api/v1/admin/invites?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const createInvite = async (req: CreateInviteRequest): Promise<CreatedInvite> => {
+        const response = await fetch('/api/v1/admin/invites', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(req),
+        });
+        return handleResponse(response);
+    };
+
+    const revokeInvite = async (id: number) => {
+        const response = await fetch(`/api/v1/admin/invites/${id}`, {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+        });
+        return handleResponse(response);
+    };
+
+    const fetchAuditLog = async (
+        page: number,
+        limit: number,
+        filter: AuditLogFilter = {},
+    ): Promise<AuditLogPage> => {
+        const params = new URLSearchParams({ page: page.toString(), limit: limit.toString() });
+        for (const [key, value] of Object.entries(filter)) {
+            if (value !== undefined && value !== '') {
+                params.set(key, String(value));
+            }
+        }
+        const response = await fetch(`/api/v1/admin/audit-log?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
     return {
         fetchAdminUsers,
         createUser,
@@ -182,8 +352,13 @@ export const useApiAdmin = () => {
         fetchRoles,
         fetchPermissions,
         syncRolePermissions,
+        fetchContentRestriction,
+        updateContentRestriction,
         getSearchStatus,
         triggerReindex,
+        getReindexStatus,
+        getSearchConsistency,
+        checkSearchConsistency,
         getSearchConfig,
         updateSearchConfig,
         listTrash,
@@ -192,5 +367,14 @@ export const useApiAdmin = () => {
         emptyTrash,
         getAppSettings,
         updateAppSettings,
+        getReleaseInfo,
+        getAPIUsageOverview,
+        getUserAPIUsage,
+        fetchAllAPIKeys,
+        revokeUserAPIKey,
+        fetchAuditLog,
+        fetchInvites,
+        createInvite,
+        revokeInvite,
     };
 };
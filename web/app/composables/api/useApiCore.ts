@@ -27,6 +27,12 @@ export const useApiCore = () => {
             throw new Error('Unauthorized');
         }
 
+        if (response.status === 423) {
+            // Session idle-locked by the privacy lock, show the PIN screen
+            authStore.setPrivacyLocked(true);
+            throw new Error('Locked');
+        }
+
         if (!response.ok) {
             const error = await response.json();
             throw new Error(error.error || 'Request failed');
@@ -43,6 +49,10 @@ export const useApiCore = () => {
             authStore.logout();
             throw new Error('Unauthorized');
         }
+        if (response.status === 423) {
+            authStore.setPrivacyLocked(true);
+            throw new Error('Locked');
+        }
         if (!response.ok && response.status !== 204) {
             const error = await response.json();
             throw new Error(error.error || 'Request failed');
@@ -1,3 +1,5 @@
+import type { BulkPhasePreview } from '~/types/jobs';
+
 /**
  * Job-related API operations: history, pool config, processing config, triggers.
  */
@@ -30,6 +32,8 @@ export const useApiJobs = () => {
         thumbnail_workers: number;
         sprites_workers: number;
         animated_thumbnails_workers: number;
+        verify_workers?: number;
+        checksum_workers?: number;
     }) => {
         const response = await fetch('/api/v1/admin/pool-config', {
             method: 'PUT',
@@ -119,6 +123,30 @@ export const useApiJobs = () => {
         return handleResponse(response);
     };
 
+    const verifyChecksums = async (mode: 'missing' | 'all' = 'all') => {
+        const response = await fetch('/api/v1/admin/jobs/verify-checksums', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ mode }),
+        });
+        return handleResponse(response);
+    };
+
+    const previewBulkPhase = async (
+        phase: string,
+        mode: string,
+        sceneIds?: number[],
+    ): Promise<BulkPhasePreview> => {
+        const body: Record<string, unknown> = { phase, mode, dry_run: true };
+        if (sceneIds?.length) body.scene_ids = sceneIds;
+        const response = await fetch('/api/v1/admin/jobs/bulk', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(body),
+        });
+        return handleResponse(response);
+    };
+
     const fetchRetryConfig = async () => {
         const response = await fetch('/api/v1/admin/retry-config', {
             headers: getAuthHeaders(),
@@ -206,6 +234,8 @@ export const useApiJobs = () => {
         updateTriggerConfig,
         triggerScenePhase,
         triggerBulkPhase,
+        verifyChecksums,
+        previewBulkPhase,
         fetchRetryConfig,
         updateRetryConfig,
         cancelJob,
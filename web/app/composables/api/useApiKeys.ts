@@ -0,0 +1,55 @@
+import type { APIKey, CreateAPIKeyRequest, CreatedAPIKey } from '~/types/api_key';
+
+/**
+ * API key operations: the current user's long-lived keys for scripts and clients.
+ */
+export const useApiKeys = () => {
+    const { fetchOptions, getAuthHeaders, handleResponse } = useApiCore();
+
+    const fetchAPIKeys = async (): Promise<{ data: APIKey[] }> => {
+        const response = await fetch('/api/v1/api-keys', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+
+        return handleResponse(response);
+    };
+
+    // Permissions the current user's role allows granting to a key
+    const fetchAPIKeyPermissions = async (): Promise<{ data: string[] }> => {
+        const response = await fetch('/api/v1/api-keys/permissions', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+
+        return handleResponse(response);
+    };
+
+    const createAPIKey = async (payload: CreateAPIKeyRequest): Promise<CreatedAPIKey> => {
+        const response = await fetch('/api/v1/api-keys', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(payload),
+            ...fetchOptions(),
+        });
+
+        return handleResponse(response);
+    };
+
+    const revokeAPIKey = async (id: number) => {
+        const response = await fetch(`/api/v1/api-keys/${id}`, {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+
+        return handleResponse(response);
+    };
+
+    return {
+        fetchAPIKeys,
+        fetchAPIKeyPermissions,
+        createAPIKey,
+        revokeAPIKey,
+    };
+};
@@ -13,9 +13,25 @@ import type {
     MarkerWithScene,
     MarkerTagInfo,
     MarkersResponse,
+    SharedMarkersResponse,
     LabelSuggestionsResponse,
     LabelTagsResponse,
+    MarkerLabelSetting,
+    SetLabelSettingRequest,
+    LabelSettingsResponse,
     MarkerTagsResponse,
+    MarkerChapterFormat,
+    ImportMarkersRequest,
+    ImportMarkersResult,
+    MarkerCompilation,
+    MarkerCompilationsResponse,
+    CreateMarkerCompilationRequest,
+    MarkerThumbnailRegenerationResult,
+    MarkerTagCollection,
+    MarkerTagCollectionSort,
+    CollectionMarker,
+    CollectionMarkerSort,
+    CollectionQueue,
     PaginatedResponse,
 } from '~/types/marker';
 import type { Tag } from '~/types/tag';
@@ -107,6 +123,52 @@ export const useApiMarkers = () => {
         return handleResponse(response);
     };
 
+    const fetchTagCollections = async (
+        page: number = 1,
+        limit: number = 20,
+        sort: MarkerTagCollectionSort = 'density',
+    ): Promise<PaginatedResponse<MarkerTagCollection>> => {
+        const params = new URLSearchParams({
+            page: String(page),
+            limit: String(limit),
+            sort,
+        });
+        const response = await fetch(`/api/v1/markers/collections?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const fetchCollectionMarkers = async (
+        tagId: number,
+        page: number = 1,
+        limit: number = 20,
+        sort: CollectionMarkerSort = 'density',
+    ): Promise<PaginatedResponse<CollectionMarker>> => {
+        const params = new URLSearchParams({
+            page: String(page),
+            limit: String(limit),
+            sort,
+        });
+        const response = await fetch(`/api/v1/markers/collections/${tagId}?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    // Pass the seed of a previous queue to get the same order back
+    const fetchCollectionQueue = async (tagId: number, seed?: number): Promise<CollectionQueue> => {
+        const params = new URLSearchParams();
+        if (seed) params.set('seed', String(seed));
+        const response = await fetch(`/api/v1/markers/collections/${tagId}/queue?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
     const fetchMarkersByLabel = async (
         label: string,
         page: number = 1,
@@ -147,6 +209,35 @@ export const useApiMarkers = () => {
         return data.tags || [];
     };
 
+    const fetchLabelSettings = async (): Promise<MarkerLabelSetting[]> => {
+        const response = await fetch('/api/v1/markers/label-settings', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        const data: LabelSettingsResponse = await handleResponse(response);
+        return data.settings || [];
+    };
+
+    const setLabelSetting = async (data: SetLabelSettingRequest): Promise<MarkerLabelSetting> => {
+        const response = await fetch('/api/v1/markers/label-settings', {
+            method: 'PUT',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(data),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const deleteLabelSetting = async (label: string): Promise<void> => {
+        const params = new URLSearchParams({ label });
+        const response = await fetch(`/api/v1/markers/label-settings?${params}`, {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponseWithNoContent(response);
+    };
+
     // Individual marker tag methods
     const fetchMarkerTags = async (markerId: number): Promise<MarkerTagInfo[]> => {
         const response = await fetch(`/api/v1/markers/${markerId}/tags`, {
@@ -179,6 +270,128 @@ export const useApiMarkers = () => {
         return data.tags || [];
     };
 
+    const fetchSharedMarkers = async (sceneId: number): Promise<SharedMarkersResponse> => {
+        const response = await fetch(`/api/v1/scenes/${sceneId}/markers/shared`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const copySharedMarker = async (sceneId: number, markerId: number): Promise<Marker> => {
+        const response = await fetch(`/api/v1/scenes/${sceneId}/markers/${markerId}/copy`, {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    // Scene scope when sceneId is set, otherwise every scene the user has markers on
+    const regenerateMarkerThumbnails = async (
+        sceneId?: number,
+    ): Promise<MarkerThumbnailRegenerationResult> => {
+        const url = sceneId
+            ? `/api/v1/scenes/${sceneId}/markers/thumbnails/regenerate`
+            : '/api/v1/markers/thumbnails/regenerate';
+        const response = await fetch(url, {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    // Admin only: every scene with markers
+    const regenerateAllMarkerThumbnails = async (): Promise<MarkerThumbnailRegenerationResult> => {
+        const response = await fetch('/api/v1/admin/markers/thumbnails/regenerate', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    // URL of the MP4 cut from a range marker; the first request generates it
+    const getMarkerClipUrl = (sceneId: number, markerId: number): string =>
+        `/api/v1/scenes/${sceneId}/markers/${markerId}/clip`;
+
+    const importMarkers = async (
+        sceneId: number,
+        data: ImportMarkersRequest,
+    ): Promise<ImportMarkersResult> => {
+        const response = await fetch(`/api/v1/scenes/${sceneId}/markers/import`, {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(data),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const chapterFileExtensions: Record<MarkerChapterFormat, string> = {
+        vtt: 'vtt',
+        ffmetadata: 'ffmetadata',
+        mkv: 'xml',
+    };
+
+    const exportMarkers = async (sceneId: number, format: MarkerChapterFormat = 'vtt') => {
+        const params = new URLSearchParams({ format });
+        const response = await fetch(`/api/v1/scenes/${sceneId}/markers/export?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        if (!response.ok) {
+            return handleResponse(response);
+        }
+        const url = URL.createObjectURL(await response.blob());
+        const link = document.createElement('a');
+        link.href = url;
+        link.download = `scene-${sceneId}-chapters.${chapterFileExtensions[format]}`;
+        link.click();
+        URL.revokeObjectURL(url);
+    };
+
+    const createMarkerCompilation = async (
+        data: CreateMarkerCompilationRequest,
+    ): Promise<MarkerCompilation> => {
+        const response = await fetch('/api/v1/markers/compilations', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(data),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const fetchMarkerCompilations = async (): Promise<MarkerCompilationsResponse> => {
+        const response = await fetch('/api/v1/markers/compilations', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const fetchMarkerCompilation = async (jobId: string): Promise<MarkerCompilation> => {
+        const response = await fetch(`/api/v1/markers/compilations/${jobId}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const getMarkerCompilationDownloadUrl = (jobId: string): string =>
+        `/api/v1/markers/compilations/${jobId}/download`;
+
+    const deleteMarkerCompilation = async (jobId: string): Promise<void> => {
+        const response = await fetch(`/api/v1/markers/compilations/${jobId}`, {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponseWithNoContent(response);
+    };
+
     return {
         fetchMarkers,
         createMarker,
@@ -187,13 +400,33 @@ export const useApiMarkers = () => {
         fetchLabelSuggestions,
         fetchLabelGroups,
         fetchAllMarkers,
+        fetchTagCollections,
+        fetchCollectionMarkers,
+        fetchCollectionQueue,
         fetchMarkersByLabel,
         // Label tag methods
         fetchLabelTags,
         setLabelTags,
+        fetchLabelSettings,
+        setLabelSetting,
+        deleteLabelSetting,
         // Marker tag methods
         fetchMarkerTags,
         setMarkerTags,
         addMarkerTags,
+        // Shared marker methods
+        fetchSharedMarkers,
+        copySharedMarker,
+        regenerateMarkerThumbnails,
+        regenerateAllMarkerThumbnails,
+        getMarkerClipUrl,
+        importMarkers,
+        exportMarkers,
+        // Compilation methods
+        createMarkerCompilation,
+        fetchMarkerCompilations,
+        fetchMarkerCompilation,
+        getMarkerCompilationDownloadUrl,
+        deleteMarkerCompilation,
     };
 };
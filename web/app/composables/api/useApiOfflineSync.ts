@@ -0,0 +1,47 @@
+import type { SyncManifest } from '~/types/offline_sync';
+
+/**
+ * Offline sync API operations: the per-user sync manifest and its scene selection.
+ */
+export const useApiOfflineSync = () => {
+    const { fetchOptions, getAuthHeaders, handleResponse, handleResponseWithNoContent } =
+        useApiCore();
+
+    // Pass the previous manifest's generated_at as since to get only changes
+    const fetchSyncManifest = async (since?: string): Promise<SyncManifest> => {
+        const query = since ? `?${new URLSearchParams({ since })}` : '';
+        const response = await fetch(`/api/v1/sync/manifest${query}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+
+        return handleResponse(response);
+    };
+
+    const addOfflineScenes = async (sceneIds: number[]) => {
+        const response = await fetch('/api/v1/sync/scenes', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ scene_ids: sceneIds }),
+            ...fetchOptions(),
+        });
+
+        await handleResponseWithNoContent(response);
+    };
+
+    const removeOfflineScene = async (sceneId: number) => {
+        const response = await fetch(`/api/v1/sync/scenes/${sceneId}`, {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+
+        await handleResponseWithNoContent(response);
+    };
+
+    return {
+        fetchSyncManifest,
+        addOfflineScenes,
+        removeOfflineScene,
+    };
+};
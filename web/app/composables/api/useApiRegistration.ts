@@ -0,0 +1,40 @@
+import type { User } from '~/types/auth';
+import type { InvitePreview, RegisterRequest, RegistrationStatus } from '~/types/invite';
+
+/**
+ * Registration operations: sign-up with an invite or under open registration.
+ * These endpoints don't need a session.
+ */
+export const useApiRegistration = () => {
+    const { fetchOptions, handleResponse } = useApiCore();
+
+    const fetchRegistrationStatus = async (): Promise<RegistrationStatus> => {
+        const response = await fetch('/api/v1/auth/registration', {
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const previewInvite = async (token: string): Promise<InvitePreview> => {
+        const response = await fetch(`/api/v1/auth/invites/${encodeURIComponent(token)}`, {
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const register = async (req: RegisterRequest): Promise<User> => {
+        const response = await fetch('/api/v1/auth/register', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(req),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    return {
+        fetchRegistrationStatus,
+        previewInvite,
+        register,
+    };
+};
@@ -1,3 +1,4 @@
+import type { SceneListItem } from '~/types/scene';
 import type {
     SavedSearch,
     SavedSearchListResponse,
@@ -6,7 +7,8 @@ import type {
 } from '~/types/saved_search';
 
 /**
- * Saved search API operations: CRUD for user's saved search templates.
+ * Saved search API operations: CRUD for user's saved search templates and the
+ * new matches of watched searches.
  */
 export const useApiSavedSearches = () => {
     const { fetchOptions, getAuthHeaders, handleResponse, handleResponseWithNoContent } =
@@ -58,11 +60,32 @@ export const useApiSavedSearches = () => {
         await handleResponseWithNoContent(response);
     };
 
+    const fetchSavedSearchNewMatches = async (
+        uuid: string,
+    ): Promise<{ data: SceneListItem[] }> => {
+        const response = await fetch(`/api/v1/saved-searches/${uuid}/new-matches`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const markSavedSearchSeen = async (uuid: string): Promise<void> => {
+        const response = await fetch(`/api/v1/saved-searches/${uuid}/seen`, {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        await handleResponseWithNoContent(response);
+    };
+
     return {
         fetchSavedSearches,
         fetchSavedSearch,
         createSavedSearch,
         updateSavedSearch,
         deleteSavedSearch,
+        fetchSavedSearchNewMatches,
+        markSavedSearchSeen,
     };
 };
@@ -1,3 +1,13 @@
+import type {
+    PlaybackCapabilities,
+    PlaybackDecision,
+    SceneSegmentResult,
+    SimilarSceneResult,
+    RandomSceneResponse,
+    ShuffleQueueResponse,
+    SearchQueryValidation,
+} from '~/types/scene';
+
 /**
  * Scene-related API operations: CRUD, search, streaming, filters, interactions.
  */
@@ -21,6 +31,17 @@ export const useApiScenes = () => {
         return handleResponse(response);
     };
 
+    // Builds the scene list query string, leaving out unset and zero values
+    const toSearchQuery = (searchParams: Record<string, string | number | undefined>) => {
+        const params = new URLSearchParams();
+        for (const [key, value] of Object.entries(searchParams)) {
+            if (value !== undefined && value !== '' && value !== 0) {
+                params.set(key, String(value));
+            }
+        }
+        return params;
+    };
+
     const fetchScenes = async (page: number, limit: number) => {
         const params = new URLSearchParams({
             page: page.toString(),
@@ -41,12 +62,7 @@ export const useApiScenes = () => {
     };
 
     const searchScenes = async (searchParams: Record<string, string | number | undefined>) => {
-        const params = new URLSearchParams();
-        for (const [key, value] of Object.entries(searchParams)) {
-            if (value !== undefined && value !== '' && value !== 0) {
-                params.set(key, String(value));
-            }
-        }
+        const params = toSearchQuery(searchParams);
 
         const settingsStore = useSettingsStore();
         if (settingsStore.cardFieldsParam) {
@@ -100,6 +116,53 @@ export const useApiScenes = () => {
         return handleResponse(response);
     };
 
+    const fetchRandomScene = async (
+        searchParams: Record<string, string | number | undefined>,
+    ): Promise<RandomSceneResponse> => {
+        const response = await fetch(`/api/v1/scenes/random?${toSearchQuery(searchParams)}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const fetchShuffleQueue = async (
+        searchParams: Record<string, string | number | undefined>,
+        offset = 0,
+        count = 20,
+    ): Promise<ShuffleQueueResponse> => {
+        const params = toSearchQuery({ ...searchParams, offset, count });
+        const response = await fetch(`/api/v1/scenes/shuffle?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const validateSearchQuery = async (query: string): Promise<SearchQueryValidation> => {
+        const params = new URLSearchParams({ q: query });
+        const response = await fetch(`/api/v1/search/validate?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const searchSceneSegments = async (
+        query: string,
+        limit?: number,
+    ): Promise<{ results: SceneSegmentResult[] }> => {
+        const params = new URLSearchParams({ q: query });
+        if (limit) {
+            params.set('limit', String(limit));
+        }
+        const response = await fetch(`/api/v1/scenes/segments?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
     const fetchScene = async (id: number) => {
         const response = await fetch(`/api/v1/scenes/${id}`, {
             headers: getAuthHeaders(),
@@ -306,11 +369,98 @@ export const useApiScenes = () => {
         return handleResponse(response);
     };
 
-    const deleteScene = async (sceneId: number, permanent = false) => {
+    const fetchSimilarScenes = async (
+        sceneId: number,
+        limit = 12,
+    ): Promise<{ results: SimilarSceneResult[] }> => {
+        const params = new URLSearchParams({ limit: limit.toString() });
+        const response = await fetch(`/api/v1/scenes/${sceneId}/similar?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const negotiatePlayback = async (
+        sceneId: number,
+        capabilities: PlaybackCapabilities = {},
+    ): Promise<PlaybackDecision> => {
+        const response = await fetch(`/api/v1/scenes/${sceneId}/playback`, {
+            method: 'POST',
+            headers: { ...getAuthHeaders(), 'Content-Type': 'application/json' },
+            body: JSON.stringify(capabilities),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    // Download URLs are used as plain links; the auth cookie authenticates them
+    const getSceneDownloadUrl = (sceneId: number) => `/api/v1/scenes/${sceneId}/download`;
+
+    const getBundleDownloadUrl = (sceneIds: number[]) =>
+        `/api/v1/downloads/bundle?${new URLSearchParams({ ids: sceneIds.join(',') })}`;
+
+    const fetchDownloads = async (page = 1, limit = 20) => {
+        const params = new URLSearchParams({ page: page.toString(), limit: limit.toString() });
+        const response = await fetch(`/api/v1/downloads?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const getCastInfo = async (sceneId: number) => {
+        const response = await fetch(`/api/v1/scenes/${sceneId}/cast`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const getSceneMediaUrls = async (sceneId: number) => {
+        const response = await fetch(`/api/v1/scenes/${sceneId}/media`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const getSceneFrameUrl = (sceneId: number, t: number, format: 'webp' | 'jpg' = 'webp') =>
+        `/api/v1/scenes/${sceneId}/frame?${new URLSearchParams({ t: t.toString(), format })}`;
+
+    const fetchReviewWorkflow = async () => {
+        const response = await fetch('/api/v1/review-workflow', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const setReviewState = async (sceneId: number, state: string) => {
+        const response = await fetch(`/api/v1/scenes/${sceneId}/review-state`, {
+            method: 'PUT',
+            headers: { ...getAuthHeaders(), 'Content-Type': 'application/json' },
+            body: JSON.stringify({ state }),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const bulkSetReviewState = async (sceneIds: number[], state: string) => {
+        const response = await fetch('/api/v1/review-workflow/bulk', {
+            method: 'POST',
+            headers: { ...getAuthHeaders(), 'Content-Type': 'application/json' },
+            body: JSON.stringify({ scene_ids: sceneIds, state }),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const deleteScene = async (sceneId: number, permanent = false, force = false) => {
         const response = await fetch(`/api/v1/scenes/${sceneId}`, {
             method: 'DELETE',
             headers: { ...getAuthHeaders(), 'Content-Type': 'application/json' },
-            body: JSON.stringify({ permanent }),
+            body: JSON.stringify({ permanent, force }),
             ...fetchOptions(),
         });
 
@@ -326,6 +476,7 @@ export const useApiScenes = () => {
         searchScenes,
         fetchAllSearchSceneIDs,
         fetchFilterOptions,
+        searchSceneSegments,
         fetchScene,
         updateSceneDetails,
         extractThumbnail,
@@ -346,6 +497,20 @@ export const useApiScenes = () => {
         getUserWatchHistoryByTimeRange,
         getDailyActivity,
         fetchRelatedScenes,
+        fetchSimilarScenes,
+        fetchRandomScene,
+        fetchShuffleQueue,
+        validateSearchQuery,
+        negotiatePlayback,
+        getSceneDownloadUrl,
+        getBundleDownloadUrl,
+        fetchDownloads,
+        getCastInfo,
+        getSceneMediaUrls,
+        getSceneFrameUrl,
+        fetchReviewWorkflow,
+        setReviewState,
+        bulkSetReviewState,
         deleteScene,
     };
 };
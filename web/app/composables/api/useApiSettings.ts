@@ -1,5 +1,12 @@
 import type { ParsingRulesSettings } from '~/types/parsing-rules';
 import type { UserSettings } from '~/types/settings';
+import type {
+    PrivacyLockStatus,
+    RecoveryCodesResponse,
+    TwoFactorSetup,
+    TwoFactorStatus,
+    UserSession,
+} from '~/types/auth';
 
 /**
  * User settings API operations: unified settings, account, parsing rules.
@@ -42,6 +49,90 @@ export const useApiSettings = () => {
         return handleResponse(response);
     };
 
+    const updatePrivacyLock = async (payload: {
+        current_password: string;
+        enabled: boolean;
+        pin?: string;
+        idle_timeout_minutes?: number;
+    }): Promise<PrivacyLockStatus> => {
+        const response = await fetch('/api/v1/settings/privacy-lock', {
+            method: 'PUT',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(payload),
+        });
+        return handleResponse(response);
+    };
+
+    const getTwoFactorStatus = async (): Promise<TwoFactorStatus> => {
+        const response = await fetch('/api/v1/auth/2fa', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const setupTwoFactor = async (currentPassword: string): Promise<TwoFactorSetup> => {
+        const response = await fetch('/api/v1/auth/2fa/setup', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ current_password: currentPassword }),
+        });
+        return handleResponse(response);
+    };
+
+    const enableTwoFactor = async (code: string): Promise<RecoveryCodesResponse> => {
+        const response = await fetch('/api/v1/auth/2fa/enable', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ code }),
+        });
+        return handleResponse(response);
+    };
+
+    const disableTwoFactor = async (currentPassword: string, code: string) => {
+        const response = await fetch('/api/v1/auth/2fa/disable', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ current_password: currentPassword, code }),
+        });
+        return handleResponse(response);
+    };
+
+    const regenerateRecoveryCodes = async (
+        currentPassword: string,
+    ): Promise<RecoveryCodesResponse> => {
+        const response = await fetch('/api/v1/auth/2fa/recovery-codes', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ current_password: currentPassword }),
+        });
+        return handleResponse(response);
+    };
+
+    const fetchSessions = async (): Promise<{ data: UserSession[] }> => {
+        const response = await fetch('/api/v1/auth/sessions', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const revokeSession = async (id: number) => {
+        const response = await fetch(`/api/v1/auth/sessions/${id}`, {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+        });
+        return handleResponse(response);
+    };
+
+    const revokeOtherSessions = async (): Promise<{ revoked: number }> => {
+        const response = await fetch('/api/v1/auth/sessions', {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+        });
+        return handleResponse(response);
+    };
+
     const getParsingRules = async (): Promise<ParsingRulesSettings> => {
         const response = await fetch('/api/v1/settings/parsing-rules', {
             headers: getAuthHeaders(),
@@ -66,6 +157,15 @@ export const useApiSettings = () => {
         updateAllSettings,
         changePassword,
         changeUsername,
+        updatePrivacyLock,
+        getTwoFactorStatus,
+        setupTwoFactor,
+        enableTwoFactor,
+        disableTwoFactor,
+        regenerateRecoveryCodes,
+        fetchSessions,
+        revokeSession,
+        revokeOtherSessions,
         getParsingRules,
         updateParsingRules,
     };
@@ -1,17 +1,22 @@
-import type { ShareLinksResponse } from '~/types/share';
+import type { ShareAccess, ShareLinksResponse } from '~/types/share';
 
 /**
- * Share link API operations: create, list, delete, resolve.
+ * Share link API operations: create, list, delete, resolve, unlock.
  */
 export const useApiShares = () => {
     const { fetchOptions, getAuthHeaders, handleResponse, handleResponseWithNoContent } =
         useApiCore();
 
-    const createShareLink = async (sceneId: number, shareType: string, expiresIn: string) => {
+    const createShareLink = async (
+        sceneId: number,
+        shareType: string,
+        expiresIn: string,
+        password = '',
+    ) => {
         const response = await fetch(`/api/v1/scenes/${sceneId}/shares`, {
             method: 'POST',
             headers: getAuthHeaders(),
-            body: JSON.stringify({ share_type: shareType, expires_in: expiresIn }),
+            body: JSON.stringify({ share_type: shareType, expires_in: expiresIn, password }),
             ...fetchOptions(),
         });
 
@@ -37,12 +42,8 @@ export const useApiShares = () => {
         await handleResponseWithNoContent(response);
     };
 
-    const resolveShareLink = async (token: string) => {
-        const response = await fetch(`/api/v1/shares/${token}`, {
-            ...fetchOptions(),
-        });
-
-        // Don't use handleResponse because it auto-logs out on 401
+    // Don't use handleResponse for public share routes because it auto-logs out on 401
+    const handleShareResponse = async (response: Response) => {
         if (!response.ok) {
             const errorBody = await response.json();
             const err = new Error(errorBody.error || 'Request failed') as Error & {
@@ -57,10 +58,31 @@ export const useApiShares = () => {
         return response.json();
     };
 
+    const resolveShareLink = async (token: string) => {
+        const response = await fetch(`/api/v1/shares/${token}`, {
+            ...fetchOptions(),
+        });
+
+        return handleShareResponse(response);
+    };
+
+    // Sets the share cookie that the stream and thumbnail requests of the link send
+    const unlockShareLink = async (token: string, password: string): Promise<ShareAccess> => {
+        const response = await fetch(`/api/v1/shares/${token}/unlock`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ password }),
+            ...fetchOptions(),
+        });
+
+        return handleShareResponse(response);
+    };
+
     return {
         createShareLink,
         listShareLinks,
         deleteShareLink,
         resolveShareLink,
+        unlockShareLink,
     };
 };
@@ -1,3 +1,5 @@
+import type { FolderRuleInput, ScanExclusions, ScanReportKind } from '~/types/scan';
+
 /**
  * Storage and scan API operations: paths, validation, scanning.
  */
@@ -53,10 +55,35 @@ export const useApiStorage = () => {
         return handleResponse(response);
     };
 
-    const startScan = async () => {
+    const updateStoragePathScanSchedule = async (
+        id: number,
+        scanSchedule: string,
+        enabled: boolean,
+    ) => {
+        const response = await fetch(`/api/v1/admin/storage-paths/${id}/scan-schedule`, {
+            method: 'PUT',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ scan_schedule: scanSchedule, enabled }),
+        });
+        return handleResponse(response);
+    };
+
+    const updateStoragePathExcludePatterns = async (id: number, excludePatterns: string[]) => {
+        const response = await fetch(`/api/v1/admin/storage-paths/${id}/exclude-patterns`, {
+            method: 'PUT',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ exclude_patterns: excludePatterns }),
+        });
+        return handleResponse(response);
+    };
+
+    const startScan = async (storagePathId?: number, folderPath?: string) => {
         const response = await fetch('/api/v1/admin/scan', {
             method: 'POST',
             headers: getAuthHeaders(),
+            body: storagePathId
+                ? JSON.stringify({ storage_path_id: storagePathId, folder_path: folderPath || '' })
+                : undefined,
             ...fetchOptions(),
         });
         return handleResponse(response);
@@ -91,15 +118,141 @@ export const useApiStorage = () => {
         return handleResponse(response);
     };
 
+    const getScanReport = async (scanId: number, kind?: ScanReportKind, page = 1, limit = 50) => {
+        const params = new URLSearchParams({
+            page: page.toString(),
+            limit: limit.toString(),
+        });
+        if (kind) params.set('kind', kind);
+        const response = await fetch(`/api/v1/admin/scan/history/${scanId}/report?${params}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const downloadScanReport = async (scanId: number, format: 'csv' | 'json' = 'csv') => {
+        const params = new URLSearchParams({ format });
+        const response = await fetch(
+            `/api/v1/admin/scan/history/${scanId}/report/download?${params}`,
+            {
+                headers: getAuthHeaders(),
+                ...fetchOptions(),
+            },
+        );
+        if (!response.ok) {
+            return handleResponse(response);
+        }
+        const url = URL.createObjectURL(await response.blob());
+        const link = document.createElement('a');
+        link.href = url;
+        link.download = `scan-${scanId}-report.${format}`;
+        link.click();
+        URL.revokeObjectURL(url);
+    };
+
+    const getScanExclusions = async () => {
+        const response = await fetch('/api/v1/admin/scan/exclusions', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const updateScanExclusions = async (exclusions: ScanExclusions) => {
+        const response = await fetch('/api/v1/admin/scan/exclusions', {
+            method: 'PUT',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(exclusions),
+        });
+        return handleResponse(response);
+    };
+
+    const testScanExclusions = async (storagePathId: number, excludePatterns?: string[]) => {
+        const response = await fetch('/api/v1/admin/scan/exclusions/test', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({
+                storage_path_id: storagePathId,
+                exclude_patterns: excludePatterns,
+            }),
+        });
+        return handleResponse(response);
+    };
+
+    const getFolderRules = async () => {
+        const response = await fetch('/api/v1/admin/scan/folder-rules', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
+    const createFolderRule = async (rule: FolderRuleInput) => {
+        const response = await fetch('/api/v1/admin/scan/folder-rules', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(rule),
+        });
+        return handleResponse(response);
+    };
+
+    const updateFolderRule = async (id: number, rule: FolderRuleInput) => {
+        const response = await fetch(`/api/v1/admin/scan/folder-rules/${id}`, {
+            method: 'PUT',
+            headers: getAuthHeaders(),
+            body: JSON.stringify(rule),
+        });
+        return handleResponse(response);
+    };
+
+    const deleteFolderRule = async (id: number) => {
+        const response = await fetch(`/api/v1/admin/scan/folder-rules/${id}`, {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+        });
+        return handleResponse(response);
+    };
+
+    const applyFolderRules = async (storagePathId: number, folderPath = '') => {
+        const response = await fetch('/api/v1/admin/scan/folder-rules/apply', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ storage_path_id: storagePathId, folder_path: folderPath }),
+        });
+        return handleResponse(response);
+    };
+
+    const getFolderRulesApplyStatus = async () => {
+        const response = await fetch('/api/v1/admin/scan/folder-rules/apply', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+        return handleResponse(response);
+    };
+
     return {
         fetchStoragePaths,
         createStoragePath,
         updateStoragePath,
         deleteStoragePath,
         validateStoragePath,
+        updateStoragePathScanSchedule,
+        updateStoragePathExcludePatterns,
         startScan,
         cancelScan,
         getScanStatus,
         getScanHistory,
+        getScanReport,
+        downloadScanReport,
+        getScanExclusions,
+        updateScanExclusions,
+        testScanExclusions,
+        getFolderRules,
+        createFolderRule,
+        updateFolderRule,
+        deleteFolderRule,
+        applyFolderRules,
+        getFolderRulesApplyStatus,
     };
 };
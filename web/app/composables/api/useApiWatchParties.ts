@@ -0,0 +1,62 @@
+import type { WatchPartyState } from '~/types/watch_party';
+
+/**
+ * Watch party API operations: create, list, end, and the realtime WebSocket URL.
+ */
+export const useApiWatchParties = () => {
+    const { fetchOptions, getAuthHeaders, handleResponse, handleResponseWithNoContent } =
+        useApiCore();
+
+    const createWatchParty = async (sceneId: number): Promise<WatchPartyState> => {
+        const response = await fetch('/api/v1/watch-parties', {
+            method: 'POST',
+            headers: getAuthHeaders(),
+            body: JSON.stringify({ scene_id: sceneId }),
+            ...fetchOptions(),
+        });
+
+        return handleResponse(response);
+    };
+
+    const fetchWatchParties = async (): Promise<{ data: WatchPartyState[] }> => {
+        const response = await fetch('/api/v1/watch-parties', {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+
+        return handleResponse(response);
+    };
+
+    const fetchWatchParty = async (partyId: string): Promise<WatchPartyState> => {
+        const response = await fetch(`/api/v1/watch-parties/${partyId}`, {
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+
+        return handleResponse(response);
+    };
+
+    const endWatchParty = async (partyId: string) => {
+        const response = await fetch(`/api/v1/watch-parties/${partyId}`, {
+            method: 'DELETE',
+            headers: getAuthHeaders(),
+            ...fetchOptions(),
+        });
+
+        await handleResponseWithNoContent(response);
+    };
+
+    // The WebSocket authenticates with the session cookie, like the SSE stream
+    const getWatchPartySocketUrl = (partyId: string) => {
+        const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+        return `${protocol}//${window.location.host}/api/v1/watch-parties/${partyId}/ws`;
+    };
+
+    return {
+        createWatchParty,
+        fetchWatchParties,
+        fetchWatchParty,
+        endWatchParty,
+        getWatchPartySocketUrl,
+    };
+};
@@ -18,6 +18,10 @@
  * - useApiMarkers() for scene marker operations
  * - useApiPlaylists() for playlist operations
  * - useApiShares() for share link operations
+ * - useApiWatchParties() for watch-together sessions
+ * - useApiOfflineSync() for the mobile offline sync manifest
+ * - useApiKeys() for API keys
+ * - useApiRegistration() for sign-up with an invite or open registration
  */
 export const useApi = () => {
     const scenes = useApiScenes();
@@ -43,6 +47,11 @@ export const useApi = () => {
         searchScenes: scenes.searchScenes,
         fetchAllSearchSceneIDs: scenes.fetchAllSearchSceneIDs,
         fetchFilterOptions: scenes.fetchFilterOptions,
+        searchSceneSegments: scenes.searchSceneSegments,
+        fetchSimilarScenes: scenes.fetchSimilarScenes,
+        fetchRandomScene: scenes.fetchRandomScene,
+        fetchShuffleQueue: scenes.fetchShuffleQueue,
+        validateSearchQuery: scenes.validateSearchQuery,
         fetchScene: scenes.fetchScene,
         updateSceneDetails: scenes.updateSceneDetails,
         extractThumbnail: scenes.extractThumbnail,
@@ -68,6 +77,7 @@ export const useApi = () => {
         updateAllSettings: settings.updateAllSettings,
         changePassword: settings.changePassword,
         changeUsername: settings.changeUsername,
+        updatePrivacyLock: settings.updatePrivacyLock,
         getParsingRules: settings.getParsingRules,
         updateParsingRules: settings.updateParsingRules,
 
@@ -82,6 +92,9 @@ export const useApi = () => {
         syncRolePermissions: admin.syncRolePermissions,
         getSearchStatus: admin.getSearchStatus,
         triggerReindex: admin.triggerReindex,
+        getReindexStatus: admin.getReindexStatus,
+        getSearchConsistency: admin.getSearchConsistency,
+        checkSearchConsistency: admin.checkSearchConsistency,
 
         // Job operations
         fetchJobs: jobs.fetchJobs,
@@ -93,6 +106,8 @@ export const useApi = () => {
         updateTriggerConfig: jobs.updateTriggerConfig,
         triggerScenePhase: jobs.triggerScenePhase,
         triggerBulkPhase: jobs.triggerBulkPhase,
+        verifyChecksums: jobs.verifyChecksums,
+        previewBulkPhase: jobs.previewBulkPhase,
         fetchRetryConfig: jobs.fetchRetryConfig,
         updateRetryConfig: jobs.updateRetryConfig,
         cancelJob: jobs.cancelJob,
@@ -157,10 +172,23 @@ export const useApi = () => {
         updateStoragePath: storage.updateStoragePath,
         deleteStoragePath: storage.deleteStoragePath,
         validateStoragePath: storage.validateStoragePath,
+        updateStoragePathScanSchedule: storage.updateStoragePathScanSchedule,
+        updateStoragePathExcludePatterns: storage.updateStoragePathExcludePatterns,
         startScan: storage.startScan,
         cancelScan: storage.cancelScan,
         getScanStatus: storage.getScanStatus,
         getScanHistory: storage.getScanHistory,
+        getScanReport: storage.getScanReport,
+        downloadScanReport: storage.downloadScanReport,
+        getScanExclusions: storage.getScanExclusions,
+        updateScanExclusions: storage.updateScanExclusions,
+        testScanExclusions: storage.testScanExclusions,
+        getFolderRules: storage.getFolderRules,
+        createFolderRule: storage.createFolderRule,
+        updateFolderRule: storage.updateFolderRule,
+        deleteFolderRule: storage.deleteFolderRule,
+        applyFolderRules: storage.applyFolderRules,
+        getFolderRulesApplyStatus: storage.getFolderRulesApplyStatus,
 
         // DLQ operations
         fetchDLQ: dlq.fetchDLQ,
@@ -181,6 +209,8 @@ export const useApi = () => {
         createSavedSearch: savedSearches.createSavedSearch,
         updateSavedSearch: savedSearches.updateSavedSearch,
         deleteSavedSearch: savedSearches.deleteSavedSearch,
+        fetchSavedSearchNewMatches: savedSearches.fetchSavedSearchNewMatches,
+        markSavedSearchSeen: savedSearches.markSavedSearchSeen,
 
         // Marker operations
         fetchMarkers: markers.fetchMarkers,
@@ -190,6 +220,21 @@ export const useApi = () => {
         fetchLabelSuggestions: markers.fetchLabelSuggestions,
         fetchLabelGroups: markers.fetchLabelGroups,
         fetchMarkersByLabel: markers.fetchMarkersByLabel,
+        fetchTagCollections: markers.fetchTagCollections,
+        fetchCollectionMarkers: markers.fetchCollectionMarkers,
+        fetchCollectionQueue: markers.fetchCollectionQueue,
+        fetchSharedMarkers: markers.fetchSharedMarkers,
+        copySharedMarker: markers.copySharedMarker,
+        regenerateMarkerThumbnails: markers.regenerateMarkerThumbnails,
+        regenerateAllMarkerThumbnails: markers.regenerateAllMarkerThumbnails,
+        getMarkerClipUrl: markers.getMarkerClipUrl,
+        importMarkers: markers.importMarkers,
+        exportMarkers: markers.exportMarkers,
+        createMarkerCompilation: markers.createMarkerCompilation,
+        fetchMarkerCompilations: markers.fetchMarkerCompilations,
+        fetchMarkerCompilation: markers.fetchMarkerCompilation,
+        getMarkerCompilationDownloadUrl: markers.getMarkerCompilationDownloadUrl,
+        deleteMarkerCompilation: markers.deleteMarkerCompilation,
 
         // Playlist operations
         fetchPlaylists: playlists.fetchPlaylists,
@@ -0,0 +1,49 @@
+/**
+ * Composable that keeps the privacy lock state in sync with the server.
+ * The server decides when a session is idle-locked; this polls the (activity-neutral)
+ * status endpoint so the PIN screen appears even when the page makes no requests.
+ */
+
+const STATUS_POLL_INTERVAL_MS = 30 * 1000;
+
+export const usePrivacyLock = () => {
+    const authStore = useAuthStore();
+
+    let pollTimer: ReturnType<typeof setInterval> | null = null;
+
+    const checkStatus = async () => {
+        if (!authStore.user || authStore.isPrivacyLocked) return;
+        try {
+            await authStore.fetchPrivacyLockStatus();
+        } catch {
+            // Network errors are handled by regular requests
+        }
+    };
+
+    const handleVisibilityChange = () => {
+        if (!document.hidden) {
+            checkStatus();
+        }
+    };
+
+    const startPrivacyLockWatch = () => {
+        if (!import.meta.client || pollTimer) return;
+
+        pollTimer = setInterval(checkStatus, STATUS_POLL_INTERVAL_MS);
+        document.addEventListener('visibilitychange', handleVisibilityChange);
+        checkStatus();
+    };
+
+    const stopPrivacyLockWatch = () => {
+        if (!import.meta.client || !pollTimer) return;
+
+        clearInterval(pollTimer);
+        pollTimer = null;
+        document.removeEventListener('visibilitychange', handleVisibilityChange);
+    };
+
+    return {
+        startPrivacyLockWatch,
+        stopPrivacyLockWatch,
+    };
+};
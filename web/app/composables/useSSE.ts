@@ -1,4 +1,7 @@
-import type { JobStatusData } from '~/types/jobs';
+import type { JobStatusData, JobProgressEvent } from '~/types/jobs';
+import type { SearchReindexStatus } from '~/types/admin';
+import type { SavedSearchNewMatchesEvent } from '~/types/saved_search';
+import type { MarkerThumbnailReadyEvent } from '~/types/marker';
 
 interface SceneEventData {
     type: string;
@@ -49,6 +52,24 @@ const SCAN_EVENTS = [
     'scan:video_moved',
 ];
 
+// Live per-job progress, routed to the job status store
+const JOB_PROGRESS_EVENT = 'job:progress';
+
+// Full search reindex progress, routed to the search reindex store
+const SEARCH_REINDEX_EVENTS = [
+    'search:reindex_started',
+    'search:reindex_progress',
+    'search:reindex_completed',
+    'search:reindex_failed',
+    'search:reindex_cancelled',
+];
+
+// New matches of a watched saved search, routed to the search store
+const SAVED_SEARCH_MATCHES_EVENT = 'saved_search:new_matches';
+
+// Marker thumbnails generated by the processing pool, routed to the scene store
+const MARKER_THUMBNAIL_READY_EVENT = 'marker:thumbnail_ready';
+
 // Events that remove scenes from the store
 const SCENE_REMOVE_EVENTS = ['scene:trashed', 'scene:deleted'];
 
@@ -77,6 +98,28 @@ function handleSSEEvent(
         return;
     }
 
+    if (eventType === JOB_PROGRESS_EVENT) {
+        useJobStatusStore().updateJobProgress(event.data as unknown as JobProgressEvent);
+        return;
+    }
+
+    if (SEARCH_REINDEX_EVENTS.includes(eventType)) {
+        useSearchReindexStore().setStatus(event.data as unknown as SearchReindexStatus);
+        return;
+    }
+
+    if (eventType === SAVED_SEARCH_MATCHES_EVENT) {
+        useSearchStore().setSavedSearchMatches(
+            event.data as unknown as SavedSearchNewMatchesEvent,
+        );
+        return;
+    }
+
+    if (eventType === MARKER_THUMBNAIL_READY_EVENT) {
+        sceneStore.markerThumbnailReady = event.data as unknown as MarkerThumbnailReadyEvent;
+        return;
+    }
+
     // Handle scan events
     if (SCAN_EVENTS.includes(eventType)) {
         const scanStore = useScanStore();
@@ -250,7 +293,13 @@ function useSSELeaderElection() {
             });
         }
 
-        for (const eventType of SCAN_EVENTS) {
+        for (const eventType of [
+            ...SCAN_EVENTS,
+            JOB_PROGRESS_EVENT,
+            ...SEARCH_REINDEX_EVENTS,
+            SAVED_SEARCH_MATCHES_EVENT,
+            MARKER_THUMBNAIL_READY_EVENT,
+        ]) {
             eventSource.addEventListener(eventType, (e: MessageEvent) => {
                 dispatchEvent(eventType, e.data);
                 channel?.postMessage({ type: 'sse-event', eventType, data: e.data });
@@ -498,8 +547,14 @@ function useSSEFallback() {
             });
         }
 
-        // Scan event handlers
-        for (const eventType of SCAN_EVENTS) {
+        // Scan, job progress, search reindex and saved search event handlers
+        for (const eventType of [
+            ...SCAN_EVENTS,
+            JOB_PROGRESS_EVENT,
+            ...SEARCH_REINDEX_EVENTS,
+            SAVED_SEARCH_MATCHES_EVENT,
+            MARKER_THUMBNAIL_READY_EVENT,
+        ]) {
             eventSource.addEventListener(eventType, (e: MessageEvent) => {
                 handleSSEEvent(eventType, e.data, sceneStore);
             });
@@ -71,6 +71,7 @@ export const WATCH_PAGE_DATA_KEY = 'watchPageData';
 
 export function useWatchPageData(sceneId: Ref<number>): WatchPageData {
     const authStore = useAuthStore();
+    const sceneStore = useSceneStore();
     const { fetchScene, fetchSceneInteractions, getResumePosition, fetchRelatedScenes } =
         useApiScenes();
     const { fetchMarkers } = useApiMarkers();
@@ -317,6 +318,21 @@ export function useWatchPageData(sceneId: Ref<number>): WatchPageData {
         tags.value = newTags;
     }
 
+    // Thumbnails are generated after a marker is saved; patch them in as they arrive
+    watch(
+        () => sceneStore.markerThumbnailReady,
+        (ready) => {
+            if (!ready) return;
+            const idx = markers.value.findIndex((m) => m.id === ready.marker_id);
+            if (idx === -1) return;
+            markers.value[idx] = {
+                ...markers.value[idx],
+                thumbnail_path: ready.thumbnail_path,
+                animated_thumbnail_path: ready.animated_thumbnail_path,
+            } as Marker;
+        },
+    );
+
     function setActors(newActors: Actor[]): void {
         actors.value = newActors;
     }
@@ -1,5 +1,11 @@
 import { defineStore } from 'pinia';
-import type { User, AuthResponse, ErrorResponse } from '~/types/auth';
+import type {
+    User,
+    AuthResponse,
+    ErrorResponse,
+    PrivacyLockStatus,
+    TwoFactorChallengeResponse,
+} from '~/types/auth';
 
 // Configuration constants
 const VALIDATION_CACHE_WINDOW_MS = 5 * 60 * 1000; // 5 minutes
@@ -15,6 +21,9 @@ export const useAuthStore = defineStore(
         const lastValidatedAt = ref<number>(0);
         const isValidating = ref(false);
 
+        // Privacy lock (idle PIN gate, enforced server-side)
+        const isPrivacyLocked = ref(false);
+
         // Cross-tab logout sync
         let logoutChannel: BroadcastChannel | null = null;
 
@@ -27,7 +36,11 @@ export const useAuthStore = defineStore(
             return Date.now() - lastValidatedAt.value < VALIDATION_CACHE_WINDOW_MS;
         });
 
-        const login = async (username: string, password: string): Promise<AuthResponse> => {
+        // Resolves with a challenge instead of logging in when a two-factor code is needed
+        const login = async (
+            username: string,
+            password: string,
+        ): Promise<AuthResponse | TwoFactorChallengeResponse> => {
             isLoading.value = true;
             error.value = null;
 
@@ -44,16 +57,52 @@ export const useAuthStore = defineStore(
                     throw new Error(err.error || 'Login failed');
                 }
 
-                const data: AuthResponse = await response.json();
+                const data: AuthResponse | TwoFactorChallengeResponse = await response.json();
+                if ('two_factor_required' in data) {
+                    return data;
+                }
                 // Token is set in HTTP-only cookie by server (not in response body)
-                user.value = data.user;
-                lastValidatedAt.value = Date.now();
+                startSession(data);
                 return data;
             } finally {
                 isLoading.value = false;
             }
         };
 
+        const loginTwoFactor = async (
+            challengeToken: string,
+            code: string,
+        ): Promise<AuthResponse> => {
+            isLoading.value = true;
+            error.value = null;
+
+            try {
+                const response = await fetch('/api/v1/auth/login/2fa', {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    credentials: 'include',
+                    body: JSON.stringify({ challenge_token: challengeToken, code }),
+                });
+
+                if (!response.ok) {
+                    const err: ErrorResponse = await response.json();
+                    throw new Error(err.error || 'Login failed');
+                }
+
+                const data: AuthResponse = await response.json();
+                startSession(data);
+                return data;
+            } finally {
+                isLoading.value = false;
+            }
+        };
+
+        const startSession = (data: AuthResponse) => {
+            user.value = data.user;
+            lastValidatedAt.value = Date.now();
+            isPrivacyLocked.value = false;
+        };
+
         const logout = async (broadcast = true) => {
             try {
                 // Server will clear the HTTP-only cookie
@@ -68,6 +117,7 @@ export const useAuthStore = defineStore(
             user.value = null;
             error.value = null;
             lastValidatedAt.value = 0;
+            isPrivacyLocked.value = false;
 
             // Notify other tabs about logout
             if (broadcast) {
@@ -153,6 +203,53 @@ export const useAuthStore = defineStore(
             }
         };
 
+        // Marks the session as privacy-locked (called when the server answers 423)
+        const setPrivacyLocked = (locked: boolean) => {
+            isPrivacyLocked.value = locked;
+        };
+
+        const fetchPrivacyLockStatus = async (): Promise<PrivacyLockStatus | null> => {
+            const response = await fetch('/api/v1/auth/privacy-lock', {
+                credentials: 'include',
+            });
+            if (!response.ok) return null;
+
+            const status: PrivacyLockStatus = await response.json();
+            isPrivacyLocked.value = status.locked;
+            return status;
+        };
+
+        const unlockPrivacy = async (pin: string) => {
+            const response = await fetch('/api/v1/auth/privacy-lock/unlock', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                credentials: 'include',
+                body: JSON.stringify({ pin }),
+            });
+
+            if (response.status === 401) {
+                // Too many wrong PINs: the server revoked the session
+                const err: ErrorResponse = await response.json();
+                await logout();
+                throw new Error(err.error || 'Session expired');
+            }
+            if (!response.ok) {
+                const err: ErrorResponse = await response.json();
+                throw new Error(err.error || 'Unlock failed');
+            }
+            isPrivacyLocked.value = false;
+        };
+
+        const lockPrivacy = async () => {
+            const response = await fetch('/api/v1/auth/privacy-lock/lock', {
+                method: 'POST',
+                credentials: 'include',
+            });
+            if (response.ok) {
+                isPrivacyLocked.value = true;
+            }
+        };
+
         // Reset validation timestamp (called on 401 to force revalidation)
         const invalidateValidation = () => {
             lastValidatedAt.value = 0;
@@ -171,12 +268,18 @@ export const useAuthStore = defineStore(
             isValidationFresh,
             isValidating,
             lastValidatedAt,
+            isPrivacyLocked,
             login,
+            loginTwoFactor,
             logout,
             fetchCurrentUser,
             validateSession,
             invalidateValidation,
             checkSession,
+            setPrivacyLocked,
+            fetchPrivacyLockStatus,
+            unlockPrivacy,
+            lockPrivacy,
             initLogoutChannel,
             destroyLogoutChannel,
         };
@@ -1,9 +1,11 @@
-import type { JobStatusData, ActiveJobInfo, JobStatusPhase } from '~/types/jobs';
+import type { JobStatusData, ActiveJobInfo, JobStatusPhase, JobProgressEvent } from '~/types/jobs';
 
 export const useJobStatusStore = defineStore('jobStatus', () => {
     const status = ref<JobStatusData | null>(null);
     const isConnected = ref(true);
     const lastReconnectedAt = ref<number>(0);
+    // Live progress per job ID from job:progress events, newer than the 3s status snapshots
+    const jobProgress = ref<Record<string, number>>({});
 
     const totalRunning = computed(() => status.value?.total_running ?? 0);
     const totalQueued = computed(() => status.value?.total_queued ?? 0);
@@ -19,6 +21,18 @@ export const useJobStatusStore = defineStore('jobStatus', () => {
 
     function updateStatus(newStatus: JobStatusData) {
         status.value = newStatus;
+        if (newStatus.total_running === 0) {
+            jobProgress.value = {};
+        }
+    }
+
+    function updateJobProgress(event: JobProgressEvent) {
+        jobProgress.value[event.job_id] = event.progress;
+    }
+
+    // Progress only moves forward, so the higher of the snapshot and live value is the newest
+    function progressFor(jobId: string, fallback = 0): number {
+        return Math.max(fallback, jobProgress.value[jobId] ?? 0);
     }
 
     function setConnected(connected: boolean) {
@@ -44,6 +58,8 @@ export const useJobStatusStore = defineStore('jobStatus', () => {
         byPhase,
         moreCount,
         updateStatus,
+        updateJobProgress,
+        progressFor,
         setConnected,
         markReconnected,
     };
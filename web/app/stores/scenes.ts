@@ -1,5 +1,6 @@
 import { defineStore } from 'pinia';
 import type { SceneListItem, SceneListResponse } from '~/types/scene';
+import type { MarkerThumbnailReadyEvent } from '~/types/marker';
 
 export const useSceneStore = defineStore('scenes', () => {
     const scenes = ref<SceneListItem[]>([]);
@@ -13,6 +14,9 @@ export const useSceneStore = defineStore('scenes', () => {
     const likes = ref<Record<string, boolean>>({});
     const jizzCounts = ref<Record<string, number>>({});
 
+    // Latest marker thumbnail generated in the background, picked up by the watch page
+    const markerThumbnailReady = ref<MarkerThumbnailReadyEvent | null>(null);
+
     const settingsStore = useSettingsStore();
     const { fetchScenes: apiFetchScenes, uploadScene: apiUploadScene } = useApi();
 
@@ -94,6 +98,7 @@ export const useSceneStore = defineStore('scenes', () => {
         ratings,
         likes,
         jizzCounts,
+        markerThumbnailReady,
         loadScenes,
         uploadScene,
         updateSceneFields,
@@ -1,5 +1,5 @@
 import type { SceneListItem, SceneFilterOptions } from '~/types/scene';
-import type { SavedSearchFilters } from '~/types/saved_search';
+import type { SavedSearchFilters, SavedSearchNewMatchesEvent } from '~/types/saved_search';
 
 export const useSearchStore = defineStore('search', () => {
     const api = useApi();
@@ -42,6 +42,9 @@ export const useSearchStore = defineStore('search', () => {
     const likes = ref<Record<string, boolean>>({});
     const jizzCounts = ref<Record<string, number>>({});
 
+    // Latest new matches event of a watched saved search, from SSE
+    const savedSearchMatches = ref<SavedSearchNewMatchesEvent | null>(null);
+
     // Filter options
     const filterOptions = ref<SceneFilterOptions>({
         studios: [],
@@ -243,6 +246,10 @@ export const useSearchStore = defineStore('search', () => {
         return params;
     };
 
+    const setSavedSearchMatches = (event: SavedSearchNewMatchesEvent) => {
+        savedSearchMatches.value = event;
+    };
+
     return {
         query,
         selectedTags,
@@ -272,6 +279,8 @@ export const useSearchStore = defineStore('search', () => {
         likes,
         jizzCounts,
         filterOptions,
+        savedSearchMatches,
+        setSavedSearchMatches,
         hasActiveFilters,
         getSearchParams,
         search,
@@ -0,0 +1,22 @@
+import type { SearchReindexStatus } from '~/types/admin';
+
+export const useSearchReindexStore = defineStore('searchReindex', () => {
+    const status = ref<SearchReindexStatus | null>(null);
+
+    const isRunning = computed(() => status.value?.status === 'running');
+    const percent = computed(() => {
+        if (!status.value || status.value.total === 0) return 0;
+        return Math.min(100, Math.round((status.value.indexed / status.value.total) * 100));
+    });
+
+    function setStatus(newStatus: SearchReindexStatus | null) {
+        status.value = newStatus;
+    }
+
+    return {
+        status,
+        isRunning,
+        percent,
+        setStatus,
+    };
+});
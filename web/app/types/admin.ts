@@ -20,8 +20,136 @@ export interface RoleResponse {
     permissions: PermissionResponse[];
 }
 
+// Part of the library a role is limited to; empty lists don't restrict
+export interface ContentRestriction {
+    role_id: number;
+    role_name?: string;
+    storage_path_ids: number[];
+    tag_ids: number[];
+    scene_types: string[];
+    updated_at: string;
+}
+
 export interface PermissionResponse {
     id: number;
     name: string;
     description: string;
 }
+
+export interface ChangelogEntry {
+    version: string;
+    date?: string;
+    changes: string[];
+    deprecations?: string[];
+}
+
+export interface ReleaseNotice {
+    id: string;
+    severity: 'info' | 'warning' | 'error';
+    message: string;
+    action?: string;
+}
+
+export interface ReleaseInfo {
+    version: string;
+    commit?: string;
+    schema: {
+        current_version: number;
+        latest_version: number;
+        dirty: boolean;
+    };
+    changelog: ChangelogEntry[];
+    notices: ReleaseNotice[];
+}
+
+export interface APIUsageTotals {
+    request_count: number;
+    error_count: number;
+    bytes_sent: number;
+}
+
+export interface APIUsageDay extends APIUsageTotals {
+    date: string;
+}
+
+export interface APIUsageRoute extends APIUsageTotals {
+    method: string;
+    route: string;
+}
+
+export interface APIUsageReport {
+    user_id: number;
+    days: number;
+    totals: APIUsageTotals;
+    daily: APIUsageDay[];
+    top_routes: APIUsageRoute[];
+}
+
+export interface APIUsageUserTotals extends APIUsageTotals {
+    user_id: number;
+    username: string;
+}
+
+export interface APIUsageOverview {
+    days: number;
+    users: APIUsageUserTotals[];
+}
+
+// One recorded destructive or administrative action
+export interface AuditEntry {
+    id: number;
+    created_at: string;
+    user_id: number | null;
+    username: string;
+    api_key_id?: number;
+    action: string;
+    resource_type: string;
+    resource_id: string;
+    method: string;
+    route: string;
+    status: number;
+    ip_address: string;
+    details: Record<string, unknown>;
+}
+
+export interface AuditLogFilter {
+    user_id?: number;
+    action?: string;
+    resource_type?: string;
+    resource_id?: string;
+    since?: string;
+    until?: string;
+}
+
+export interface AuditLogPage {
+    data: AuditEntry[];
+    total: number;
+    page: number;
+    limit: number;
+}
+
+export type SearchReindexState = 'running' | 'completed' | 'failed' | 'cancelled' | 'interrupted';
+
+export interface SearchReindexStatus {
+    status: SearchReindexState;
+    last_scene_id: number;
+    indexed: number;
+    total: number;
+    error?: string;
+    started_at: string;
+    updated_at: string;
+    completed_at?: string;
+    resumable: boolean;
+}
+
+export interface SearchConsistencyReport {
+    checked_at: string;
+    db_scene_count: number;
+    indexed_count: number;
+    missing_count: number;
+    missing_scene_ids: number[];
+    orphan_count: number;
+    orphan_scene_ids: number[];
+    healed: boolean;
+    heal_error?: string;
+}
@@ -0,0 +1,26 @@
+export interface APIKey {
+    id: number;
+    user_id: number;
+    name: string;
+    key_prefix: string;
+    permissions: string[];
+    expires_at: string | null;
+    last_used_at: string | null;
+    revoked_at: string | null;
+    created_at: string;
+    // Only set in admin listings
+    username?: string;
+}
+
+// The key itself is only returned once, when it is created
+export interface CreatedAPIKey {
+    api_key: APIKey;
+    key: string;
+}
+
+export interface CreateAPIKeyRequest {
+    name: string;
+    permissions: string[];
+    // 0 for a key that never expires
+    expires_in_days: number;
+}
@@ -2,6 +2,7 @@ export interface User {
     id: number;
     username: string;
     role: 'admin' | 'user';
+    two_factor_setup_required: boolean;
 }
 
 // SECURITY: Token is transmitted only via HTTP-only cookie, never in response body
@@ -9,6 +10,12 @@ export interface AuthResponse {
     user: User;
 }
 
+// Returned by login instead of AuthResponse when a two-factor code is still needed
+export interface TwoFactorChallengeResponse {
+    two_factor_required: true;
+    challenge_token: string;
+}
+
 export interface LoginRequest {
     username: string;
     password: string;
@@ -17,3 +24,35 @@ export interface LoginRequest {
 export interface ErrorResponse {
     error: string;
 }
+
+export interface PrivacyLockStatus {
+    enabled: boolean;
+    locked: boolean;
+    idle_timeout_minutes: number;
+}
+
+export interface TwoFactorStatus {
+    enabled: boolean;
+    recovery_codes_remaining: number;
+    required: boolean;
+}
+
+export interface TwoFactorSetup {
+    secret: string;
+    provisioning_uri: string;
+}
+
+export interface RecoveryCodesResponse {
+    recovery_codes: string[];
+}
+
+// A signed-in device; current is set for the session making the request
+export interface UserSession {
+    id: number;
+    user_agent: string;
+    ip_address: string;
+    created_at: string;
+    last_seen_at: string;
+    expires_at: string;
+    current: boolean;
+}
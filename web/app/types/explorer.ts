@@ -67,6 +67,7 @@ export interface FolderSceneIDsResponse {
 export interface BulkDeleteRequest {
     scene_ids: number[];
     permanent?: boolean;
+    force?: boolean;
 }
 
 export interface BulkDeleteResponse {
@@ -1,4 +1,4 @@
-import type { SceneListItem } from './scene';
+import type { ReviewCounts, SceneListItem } from './scene';
 import type { PlaylistListItem } from './playlist';
 
 export type SectionType =
@@ -46,6 +46,7 @@ export interface HomepageSectionData {
 export interface HomepageResponse {
     config: HomepageConfig;
     sections: HomepageSectionData[];
+    review_counts?: ReviewCounts;
 }
 
 export const SECTION_TYPE_LABELS: Record<SectionType, string> = {
@@ -0,0 +1,39 @@
+// Single-use registration link created by an admin for a role
+export interface UserInvite {
+    id: number;
+    role: string;
+    note: string;
+    created_by: number | null;
+    expires_at: string;
+    used_at: string | null;
+    used_by: number | null;
+    revoked_at: string | null;
+    created_at: string;
+}
+
+// Returned once on creation; token is never shown again
+export interface CreatedInvite {
+    invite: UserInvite;
+    token: string;
+}
+
+export interface CreateInviteRequest {
+    role: string;
+    note?: string;
+    expires_in_hours?: number;
+}
+
+export interface InvitePreview {
+    role: string;
+    expires_at: string;
+}
+
+export interface RegistrationStatus {
+    open_registration: boolean;
+}
+
+export interface RegisterRequest {
+    username: string;
+    password: string;
+    invite_token?: string;
+}
@@ -3,7 +3,7 @@ export interface JobHistory {
     job_id: string;
     scene_id: number;
     scene_title: string;
-    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails';
+    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails' | 'verify' | 'checksum';
     status: 'pending' | 'running' | 'completed' | 'failed' | 'cancelled' | 'timed_out';
     error_message?: string;
     started_at: string;
@@ -22,6 +22,8 @@ export interface PoolConfig {
     thumbnail_workers: number;
     sprites_workers: number;
     animated_thumbnails_workers: number;
+    verify_workers: number;
+    checksum_workers: number;
 }
 
 export interface ProcessingConfig {
@@ -40,19 +42,38 @@ export interface ProcessingConfig {
     scene_preview_crf: number;
 }
 
+export interface BulkPhasePreview {
+    message: string;
+    dry_run: boolean;
+    scene_ids: number[];
+    scene_count: number;
+    skipped: number;
+    total_duration: number;
+    estimated_bytes: number;
+}
+
 export interface QueueStatus {
     metadata_queued: number;
     thumbnail_queued: number;
     sprites_queued: number;
     animated_thumbnails_queued: number;
+    verify_queued: number;
+    checksum_queued: number;
     metadata_running: number;
     thumbnail_running: number;
     sprites_running: number;
     animated_thumbnails_running: number;
+    verify_running: number;
+    checksum_running: number;
     metadata_pending: number;
     thumbnail_pending: number;
     sprites_pending: number;
     animated_thumbnails_pending: number;
+    verify_pending: number;
+    checksum_pending: number;
+    ffmpeg_running: number;
+    ffmpeg_waiting: number;
+    ffmpeg_limit: number;
 }
 
 export interface JobListResponse {
@@ -69,7 +90,7 @@ export interface JobListResponse {
 
 export interface TriggerConfig {
     id: number;
-    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails' | 'scan';
+    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails' | 'verify' | 'checksum' | 'scan';
     trigger_type: 'on_import' | 'after_job' | 'manual' | 'scheduled';
     after_phase: string | null;
     cron_expression: string | null;
@@ -77,7 +98,7 @@ export interface TriggerConfig {
 }
 
 export interface BulkJobRequest {
-    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails';
+    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails' | 'verify' | 'checksum';
     mode: 'missing' | 'all';
     force_target?: 'markers' | 'previews' | 'both';
 }
@@ -94,7 +115,7 @@ export interface DLQEntry {
     job_id: string;
     scene_id: number;
     scene_title: string;
-    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails';
+    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails' | 'verify' | 'checksum';
     original_error: string;
     failure_count: number;
     last_error: string;
@@ -119,7 +140,7 @@ export interface DLQListResponse {
 
 export interface RetryConfig {
     id: number;
-    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails' | 'scan';
+    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails' | 'verify' | 'checksum' | 'scan';
     max_retries: number;
     initial_delay_seconds: number;
     max_delay_seconds: number;
@@ -139,8 +160,15 @@ export interface ActiveJobInfo {
     job_id: string;
     scene_id: number;
     scene_title: string;
-    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails';
+    phase: 'metadata' | 'thumbnail' | 'sprites' | 'animated_thumbnails' | 'verify' | 'checksum';
     started_at: string;
+    progress: number;
+}
+
+export interface JobProgressEvent {
+    job_id: string;
+    phase: string;
+    progress: number;
 }
 
 export interface JobStatusData {
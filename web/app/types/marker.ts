@@ -1,10 +1,15 @@
+// Shared markers are visible to the other users of the scene
+export type MarkerVisibility = 'private' | 'shared';
+
 export interface Marker {
     id: number;
     user_id: number;
     scene_id: number;
     timestamp: number;
+    end_timestamp?: number | null;
     label: string;
     color: string;
+    visibility: MarkerVisibility;
     thumbnail_path: string;
     animated_thumbnail_path: string;
     created_at: string;
@@ -14,16 +19,87 @@ export interface Marker {
 
 export interface CreateMarkerRequest {
     timestamp: number;
+    end_timestamp?: number;
     label?: string;
     color?: string;
+    visibility?: MarkerVisibility;
 }
 
 export interface UpdateMarkerRequest {
     timestamp?: number;
+    // 0 turns a range back into a single point
+    end_timestamp?: number;
     label?: string;
     color?: string;
+    visibility?: MarkerVisibility;
+}
+
+// Another user's shared marker
+export interface SharedMarker extends Marker {
+    username: string;
+}
+
+// File formats markers can be imported from; omit to detect from the content
+export type MarkerImportFormat = 'csv' | 'json' | 'funscript' | 'vtt';
+
+export interface MarkerImportItem {
+    timestamp: number;
+    label: string;
+    color?: string;
+}
+
+export interface ImportMarkersRequest {
+    format?: MarkerImportFormat;
+    content?: string;
+    markers?: MarkerImportItem[];
+    color?: string;
+    dedupe_seconds?: number;
+    dry_run?: boolean;
+}
+
+export interface MarkerImportSkip extends MarkerImportItem {
+    reason: string;
+}
+
+export interface ImportMarkersResult {
+    dry_run: boolean;
+    markers: MarkerImportItem[];
+    created?: Marker[];
+    skipped: MarkerImportSkip[];
 }
 
+export type MarkerCompilationStatus = 'pending' | 'running' | 'completed' | 'failed';
+
+// An MP4 joined from the clips of a set of markers, built by a background job
+export interface MarkerCompilation {
+    id: number;
+    job_id: string;
+    user_id: number;
+    title: string;
+    marker_ids: number[];
+    clip_seconds: number;
+    status: MarkerCompilationStatus;
+    progress: number;
+    error_message?: string;
+    file_size: number;
+    duration: number;
+    created_at: string;
+    completed_at?: string;
+}
+
+export interface CreateMarkerCompilationRequest {
+    marker_ids: number[];
+    clip_seconds?: number;
+    title?: string;
+}
+
+export interface MarkerCompilationsResponse {
+    compilations: MarkerCompilation[];
+}
+
+// Chapter file formats markers can be exported to
+export type MarkerChapterFormat = 'vtt' | 'ffmetadata' | 'mkv';
+
 export interface MarkerLabelSuggestion {
     label: string;
     count: number;
@@ -41,6 +117,41 @@ export interface MarkerWithScene extends Marker {
     tags?: MarkerTagInfo[];
 }
 
+// Virtual collection of all of the user's markers carrying one tag
+export interface MarkerTagCollection {
+    tag_id: number;
+    tag_name: string;
+    tag_color: string;
+    marker_count: number;
+    scene_count: number;
+    density: number; // markers per hour of the scenes they are on
+    thumbnail_marker_id: number;
+}
+
+export type MarkerTagCollectionSort = 'density' | 'count' | 'name';
+
+export interface CollectionMarker extends MarkerWithScene {
+    scene_duration: number;
+    density: number; // collection markers per hour of the scene
+}
+
+export type CollectionMarkerSort = 'density' | 'scene' | 'recent';
+
+export interface CollectionClip {
+    marker_id: number;
+    scene_id: number;
+    scene_title: string;
+    label: string;
+    start: number;
+    end: number;
+}
+
+export interface CollectionQueue {
+    clips: CollectionClip[];
+    total: number;
+    seed: number;
+}
+
 // Tag info with metadata about source
 export interface MarkerTagInfo {
     id: number;
@@ -54,6 +165,24 @@ export interface MarkersResponse {
     markers: Marker[];
 }
 
+export interface SharedMarkersResponse {
+    markers: SharedMarker[];
+}
+
+// Payload of the marker:thumbnail_ready SSE event
+export interface MarkerThumbnailReadyEvent {
+    marker_id: number;
+    thumbnail_path: string;
+    animated_thumbnail_path: string;
+}
+
+// Scenes queued for marker thumbnail regeneration
+export interface MarkerThumbnailRegenerationResult {
+    submitted: number;
+    skipped: number;
+    errors: number;
+}
+
 export interface LabelSuggestionsResponse {
     labels: MarkerLabelSuggestion[];
 }
@@ -82,6 +211,28 @@ export interface LabelTagsResponse {
     tags: import('~/types/tag').Tag[];
 }
 
+// Per-user defaults for a label, applied when a marker with the label is created
+export interface MarkerLabelSetting {
+    id: number;
+    label: string;
+    color: string; // empty keeps the default color
+    icon: string;
+    clip_duration: number; // animated thumbnail seconds, 0 uses the configured length
+    created_at: string;
+    updated_at: string;
+}
+
+export interface SetLabelSettingRequest {
+    label: string;
+    color?: string;
+    icon?: string;
+    clip_duration?: number;
+}
+
+export interface LabelSettingsResponse {
+    settings: MarkerLabelSetting[];
+}
+
 export interface MarkerTagsResponse {
     tags: MarkerTagInfo[];
 }
@@ -0,0 +1,35 @@
+export interface SyncMarker {
+    id: number;
+    timestamp: number;
+    label: string;
+    color: string;
+}
+
+export interface SyncSceneEntry {
+    id: number;
+    title: string;
+    description: string;
+    duration: number;
+    size: number;
+    width: number;
+    height: number;
+    studio: string;
+    tags: string[];
+    actors: string[];
+    release_date: string | null;
+    file_hash: string;
+    has_vtt: boolean;
+    markers: SyncMarker[];
+    checksum: string;
+    changed_at: string;
+    thumbnail_url: string;
+    vtt_url?: string;
+    download_url: string;
+}
+
+export interface SyncManifest {
+    generated_at: string;
+    full: boolean;
+    scenes: SyncSceneEntry[];
+    removed_scene_ids: number[];
+}
@@ -22,6 +22,9 @@ export interface SavedSearch {
     uuid: string;
     name: string;
     filters: SavedSearchFilters;
+    watched: boolean;
+    last_checked_at: string | null;
+    new_match_count: number;
     created_at: string;
     updated_at: string;
 }
@@ -33,9 +36,19 @@ export interface SavedSearchListResponse {
 export interface CreateSavedSearchInput {
     name: string;
     filters: SavedSearchFilters;
+    watched?: boolean;
 }
 
 export interface UpdateSavedSearchInput {
     name?: string;
     filters?: SavedSearchFilters;
+    watched?: boolean;
+}
+
+// Payload of the saved_search:new_matches SSE event
+export interface SavedSearchNewMatchesEvent {
+    uuid: string;
+    name: string;
+    new_matches: number;
+    unseen_count: number;
 }
@@ -13,6 +13,8 @@ export interface ScanHistory {
     error_message?: string;
     current_path?: string;
     current_file?: string;
+    storage_path_ids?: number[];
+    folder_path?: string;
     created_at: string;
 }
 
@@ -28,6 +30,28 @@ export interface ScanHistoryResponse {
     limit: number;
 }
 
+export type ScanReportKind = 'added' | 'moved' | 'removed' | 'error';
+
+export interface ScanReportEntry {
+    id: number;
+    scan_id: number;
+    kind: ScanReportKind;
+    path: string;
+    old_path?: string;
+    scene_id?: number;
+    message?: string;
+    created_at: string;
+}
+
+export interface ScanReport {
+    scan: ScanHistory;
+    counts: Partial<Record<ScanReportKind, number>>;
+    data: ScanReportEntry[];
+    total: number;
+    page: number;
+    limit: number;
+}
+
 export interface ScanProgressEvent {
     files_found: number;
     videos_added: number;
@@ -57,3 +81,53 @@ export interface ScanVideoMovedEvent {
     new_path: string;
     title: string;
 }
+
+export interface ScanExclusions {
+    min_file_size_bytes: number;
+    ignore_samples: boolean;
+    ignore_hidden_dirs: boolean;
+    exclude_patterns: string[];
+    updated_at?: string;
+}
+
+export interface ExcludedEntry {
+    path: string;
+    is_dir: boolean;
+    reason: 'pattern' | 'hidden_dir' | 'min_size' | 'sample' | 'work_dir';
+}
+
+export interface ExclusionTestResult {
+    excluded: ExcludedEntry[];
+    files_checked: number;
+    files_excluded: number;
+    dirs_excluded: number;
+    truncated: boolean;
+}
+
+export interface FolderRule {
+    id: number;
+    name: string;
+    storage_path_id: number | null;
+    pattern: string;
+    tag_ids: number[];
+    actor_ids: number[];
+    studio_id: number | null;
+    scene_type: string;
+    enabled: boolean;
+    created_at: string;
+    updated_at: string;
+}
+
+export type FolderRuleInput = Omit<FolderRule, 'id' | 'created_at' | 'updated_at'>;
+
+export interface FolderRuleApplyStatus {
+    running: boolean;
+    storage_path_id: number;
+    folder_path: string;
+    total: number;
+    processed: number;
+    matched: number;
+    error?: string;
+    started_at: string;
+    completed_at?: string;
+}
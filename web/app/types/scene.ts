@@ -9,6 +9,7 @@ export interface SceneListItem {
     preview_video_path: string;
     processing_status: string;
     is_corrupted: boolean;
+    review_state: string; // empty = the workflow's initial state
     created_at: string;
     updated_at: string;
     // Optional fields included via card_fields
@@ -34,6 +35,7 @@ export interface Scene extends SceneListItem {
     sprite_sheet_path?: string;
     vtt_path?: string;
     sprite_sheet_count?: number;
+    trickplay_path?: string;
     thumbnail_width?: number;
     thumbnail_height?: number;
     processing_error?: string;
@@ -43,7 +45,9 @@ export interface Scene extends SceneListItem {
     tags?: string[];
     actors?: string[];
     cover_image_path?: string;
-    file_hash?: string;
+    file_hash?: string; // SHA-256, set by the checksum phase
+    checksum_mismatch?: boolean;
+    hash_verified_at?: string;
     frame_rate?: number;
     bit_rate?: number;
     video_codec?: string;
@@ -52,6 +56,9 @@ export interface Scene extends SceneListItem {
     porndb_scene_id?: string;
     origin?: string;
     type?: string;
+    // Popularity across the scene in equal buckets (0-100), from markers and watch positions
+    heatmap?: number[];
+    heatmap_updated_at?: string;
 }
 
 export interface SceneListResponse {
@@ -107,3 +114,107 @@ export interface SceneFilterOptions {
     origins: string[];
     types: string[];
 }
+
+export interface PlaybackCapabilities {
+    containers?: string[];
+    video_codecs?: string[];
+    audio_codecs?: string[];
+}
+
+export interface PlaybackDecision {
+    mode: 'direct' | 'transcode';
+    stream_url: string;
+    container: string;
+    video_codec: string;
+    audio_codec: string;
+    reasons: string[];
+    transcode_video: boolean;
+    transcode_audio: boolean;
+}
+
+export interface ReviewWorkflow {
+    states: string[];
+    initial_state: string;
+    transitions?: Record<string, string[]>; // absent when any transition is allowed
+}
+
+export interface ReviewCounts {
+    states: { state: string; count: number }[];
+    unknown: number;
+    total: number;
+}
+
+export interface ReviewWorkflowResponse {
+    workflow: ReviewWorkflow;
+    counts: ReviewCounts;
+}
+
+export interface CastInfo {
+    scene_id: number;
+    title: string;
+    duration: number;
+    mime_type: string;
+    stream_url: string;
+    thumbnail_url: string;
+    expires_at: string;
+}
+
+export interface SceneMediaUrls {
+    thumbnail_url: string;
+    thumbnail_lg_url: string;
+    vtt_url: string;
+    trickplay_url: string;
+    preview_url: string;
+    expires_at: string | null;
+}
+
+// SegmentMatch is a point in a scene whose marker label (or, later, subtitle
+// text) matched a segment search. watch_url starts playback at the match.
+export interface SegmentMatch {
+    kind: 'marker' | 'subtitle';
+    marker_id?: number;
+    text: string;
+    timestamp: number; // seconds
+    watch_url: string;
+}
+
+export interface SceneSegmentResult {
+    scene: SceneListItem;
+    matches: SegmentMatch[];
+}
+
+export interface RandomSceneResponse {
+    scene: SceneListItem;
+    seed: number;
+}
+
+// One batch of a seeded shuffle; pass seed and next_offset back for the next batch
+export interface ShuffleQueueResponse {
+    data: SceneListItem[];
+    total: number;
+    seed: number;
+    next_offset: number;
+    done: boolean;
+}
+
+// A "more like this" scene; score is 0-1
+export interface SimilarSceneResult {
+    scene: SceneListItem;
+    score: number;
+    shared_actors: string[];
+    shared_tags: string[];
+    same_studio: boolean;
+}
+
+export interface SearchQueryParseError {
+    message: string;
+    position: number;
+}
+
+// Result of /search/validate for the advanced query syntax
+export interface SearchQueryValidation {
+    valid: boolean;
+    error?: SearchQueryParseError;
+    query?: Record<string, unknown>;
+    warnings?: string[];
+}
@@ -7,6 +7,7 @@ export interface ShareLink {
     expires_at: string | null;
     view_count: number;
     created_at: string;
+    has_password: boolean;
 }
 
 export interface ShareSceneData {
@@ -24,9 +25,15 @@ export interface ShareSceneData {
 export interface ResolvedShareLink {
     share_link: ShareLink;
     scene: ShareSceneData;
+    thumbnail_url: string;
 }
 
 export interface ShareLinksResponse {
     share_links: ShareLink[];
     share_base_url: string;
 }
+
+export interface ShareAccess {
+    access_token: string;
+    expires_at: string;
+}
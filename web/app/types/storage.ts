@@ -13,6 +13,10 @@ export interface StoragePath {
     created_at: string;
     updated_at: string;
     disk_usage: DiskUsage | null;
+    scan_schedule: string | null;
+    scan_schedule_enabled: boolean;
+    next_scan_at: string | null;
+    exclude_patterns: string[];
 }
 
 export interface StoragePathListResponse {
@@ -0,0 +1,30 @@
+export interface WatchPartyMember {
+    user_id: number;
+    username: string;
+    joined_at: string;
+}
+
+/**
+ * Watch party snapshot. `position` is the playback position at `updated_at`;
+ * while playing, add the time elapsed since then.
+ */
+export interface WatchPartyState {
+    id: string;
+    scene_id: number;
+    host_id: number;
+    position: number;
+    paused: boolean;
+    updated_at: string;
+    created_at: string;
+    members: WatchPartyMember[];
+}
+
+export type WatchPartyServerMessage =
+    | { type: 'state'; state: WatchPartyState }
+    | { type: 'ended' }
+    | { type: 'error'; error: string; code: string };
+
+export type WatchPartyClientMessage =
+    | { type: 'play' | 'pause' | 'seek'; position: number }
+    | { type: 'transfer_host'; user_id: number }
+    | { type: 'end' | 'sync' | 'ping' };
@@ -19,6 +19,14 @@ var SSE_EVENT_TYPES = [
     'scene:cancelled',
     'scene:timed_out',
     'jobs:status',
+    'job:progress',
+    'search:reindex_started',
+    'search:reindex_progress',
+    'search:reindex_completed',
+    'search:reindex_failed',
+    'search:reindex_cancelled',
+    'saved_search:new_matches',
+    'marker:thumbnail_ready',
 ];
 
 function broadcast(type, payload) {